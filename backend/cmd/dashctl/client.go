@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// apiClient 是 dashboard API 的轻量 HTTP 客户端
+type apiClient struct {
+	baseURL    string
+	token      string
+	cluster    string
+	httpClient *http.Client
+}
+
+func newAPIClient(baseURL, token, cluster string) *apiClient {
+	return &apiClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		cluster: cluster,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// do 发送请求并返回原始响应体，非 2xx 时解析 {"error": ...} 作为错误返回
+func (c *apiClient) do(method, path string, query url.Values, body interface{}) ([]byte, error) {
+	endpoint := c.baseURL + "/api/v2" + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequest(method, endpoint, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.cluster != "" {
+		req.Header.Set("X-Cluster", c.cluster)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error != "" {
+			return nil, fmt.Errorf("%s %s: %d %s", method, path, resp.StatusCode, apiErr.Error)
+		}
+		return nil, fmt.Errorf("%s %s: %d %s", method, path, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
+
+func (c *apiClient) get(path string, query url.Values) ([]byte, error) {
+	return c.do(http.MethodGet, path, query, nil)
+}
+
+func (c *apiClient) post(path string, body interface{}) ([]byte, error) {
+	return c.do(http.MethodPost, path, nil, body)
+}
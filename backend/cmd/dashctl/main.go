@@ -0,0 +1,306 @@
+// dashctl 是 k8s-dashboard API 的命令行客户端。
+//
+// 认证使用 API Token（Authorization: Bearer），可通过 -token 参数或
+// DASHCTL_TOKEN 环境变量提供；服务地址通过 -server 或 DASHCTL_SERVER 指定。
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+const usage = `用法: dashctl [全局参数] <命令> [参数]
+
+全局参数:
+  -server   服务地址（默认 $DASHCTL_SERVER 或 http://localhost:8080）
+  -token    API Token（默认 $DASHCTL_TOKEN）
+  -cluster  目标集群（默认 $DASHCTL_CLUSTER，空为默认集群）
+
+命令:
+  anomalies [summary|pods|nodes|excess] [-namespace ns]   查看异常
+  approvals list [-status pending]                         列出审批
+  approvals approve|reject <id> [-comment text]            处理审批
+  restart deployment|statefulset|daemonset <ns> <name>     重启工作负载
+  audit [-user u] [-namespace ns] [-resource r] [-page n] [-page-size n]
+                                                           查询审计日志
+  report [-duration 24h] [-o file]                         导出观测与审计报告
+`
+
+// stdout 命令输出，测试中替换为缓冲区
+var stdout io.Writer = os.Stdout
+
+func main() {
+	global := flag.NewFlagSet("dashctl", flag.ExitOnError)
+	global.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	server := global.String("server", envOrDefault("DASHCTL_SERVER", "http://localhost:8080"), "")
+	token := global.String("token", os.Getenv("DASHCTL_TOKEN"), "")
+	cluster := global.String("cluster", os.Getenv("DASHCTL_CLUSTER"), "")
+	_ = global.Parse(os.Args[1:])
+
+	args := global.Args()
+	if len(args) == 0 {
+		global.Usage()
+		os.Exit(2)
+	}
+	if *token == "" {
+		fatal(errors.New("未提供 API Token，请使用 -token 或设置 DASHCTL_TOKEN"))
+	}
+
+	client := newAPIClient(*server, *token, *cluster)
+
+	var err error
+	switch args[0] {
+	case "anomalies":
+		err = runAnomalies(client, args[1:])
+	case "approvals":
+		err = runApprovals(client, args[1:])
+	case "restart":
+		err = runRestart(client, args[1:])
+	case "audit":
+		err = runAudit(client, args[1:])
+	case "report":
+		err = runReport(client, args[1:])
+	case "help", "-h", "--help":
+		global.Usage()
+		return
+	default:
+		err = fmt.Errorf("未知命令: %s", args[0])
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fatal(err)
+	}
+}
+
+func runAnomalies(client *apiClient, args []string) error {
+	kind := "summary"
+	if len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		kind, args = args[0], args[1:]
+	}
+
+	fs := flag.NewFlagSet("anomalies", flag.ContinueOnError)
+	namespace := fs.String("namespace", "", "按命名空间过滤")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	if *namespace != "" {
+		query.Set("namespace", *namespace)
+	}
+
+	paths := map[string]string{
+		"summary": "/observation/summary",
+		"pods":    "/observation/pods/anomaly",
+		"nodes":   "/observation/nodes/anomaly",
+		"excess":  "/observation/resources/excess",
+	}
+	path, ok := paths[kind]
+	if !ok {
+		return fmt.Errorf("未知异常类型: %s", kind)
+	}
+
+	data, err := client.get(path, query)
+	if err != nil {
+		return err
+	}
+	return printJSON(data)
+}
+
+func runApprovals(client *apiClient, args []string) error {
+	if len(args) == 0 {
+		return errors.New("用法: dashctl approvals list|approve|reject ...")
+	}
+
+	switch args[0] {
+	case "list":
+		fs := flag.NewFlagSet("approvals list", flag.ContinueOnError)
+		status := fs.String("status", "pending", "审批状态")
+		page := fs.Int("page", 1, "页码")
+		pageSize := fs.Int("page-size", 20, "每页数量")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		query := url.Values{}
+		if *status != "" {
+			query.Set("status", *status)
+		}
+		query.Set("page", strconv.Itoa(*page))
+		query.Set("pageSize", strconv.Itoa(*pageSize))
+
+		data, err := client.get("/approvals", query)
+		if err != nil {
+			return err
+		}
+		return printJSON(data)
+	case "approve", "reject":
+		if len(args) < 2 {
+			return fmt.Errorf("用法: dashctl approvals %s <id> [-comment text]", args[0])
+		}
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("无效的审批ID: %s", args[1])
+		}
+		fs := flag.NewFlagSet("approvals "+args[0], flag.ContinueOnError)
+		comment := fs.String("comment", "", "审批意见")
+		if err := fs.Parse(args[2:]); err != nil {
+			return err
+		}
+
+		data, err := client.post(fmt.Sprintf("/approvals/%d/%s", id, args[0]), map[string]string{
+			"comment": *comment,
+		})
+		if err != nil {
+			return err
+		}
+		return printJSON(data)
+	default:
+		return fmt.Errorf("未知子命令: approvals %s", args[0])
+	}
+}
+
+func runRestart(client *apiClient, args []string) error {
+	if len(args) != 3 {
+		return errors.New("用法: dashctl restart deployment|statefulset|daemonset <ns> <name>")
+	}
+
+	resources := map[string]string{
+		"deployment":  "deployments",
+		"statefulset": "statefulsets",
+		"daemonset":   "daemonsets",
+	}
+	resource, ok := resources[args[0]]
+	if !ok {
+		return fmt.Errorf("不支持重启的资源类型: %s", args[0])
+	}
+
+	path := fmt.Sprintf("/namespaces/%s/%s/%s/restart", url.PathEscape(args[1]), resource, url.PathEscape(args[2]))
+	data, err := client.post(path, nil)
+	if err != nil {
+		return err
+	}
+	return printJSON(data)
+}
+
+func runAudit(client *apiClient, args []string) error {
+	fs := flag.NewFlagSet("audit", flag.ContinueOnError)
+	user := fs.String("user", "", "按用户过滤")
+	namespace := fs.String("namespace", "", "按命名空间过滤")
+	resource := fs.String("resource", "", "按资源类型过滤")
+	action := fs.String("action", "", "按操作过滤")
+	since := fs.Duration("since", 0, "仅查询最近一段时间，例如 1h")
+	page := fs.Int("page", 1, "页码")
+	pageSize := fs.Int("page-size", 20, "每页数量")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	query.Set("page", strconv.Itoa(*page))
+	query.Set("pageSize", strconv.Itoa(*pageSize))
+	setIfNotEmpty(query, "user", *user)
+	setIfNotEmpty(query, "namespace", *namespace)
+	setIfNotEmpty(query, "resource", *resource)
+	setIfNotEmpty(query, "action", *action)
+	if *since > 0 {
+		query.Set("startTime", time.Now().Add(-*since).UTC().Format(time.RFC3339))
+	}
+
+	data, err := client.get("/audit", query)
+	if err != nil {
+		return err
+	}
+	return printJSON(data)
+}
+
+// runReport 汇总观测摘要、异常明细和审计统计，导出为一份 JSON 报告
+func runReport(client *apiClient, args []string) error {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	duration := fs.String("duration", "24h", "审计统计时间窗口")
+	output := fs.String("o", "", "输出文件（默认标准输出）")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	sections := []struct {
+		key   string
+		path  string
+		query url.Values
+	}{
+		{"summary", "/observation/summary", nil},
+		{"podAnomalies", "/observation/pods/anomaly", nil},
+		{"nodeAnomalies", "/observation/nodes/anomaly", nil},
+		{"resourceExcess", "/observation/resources/excess", nil},
+		{"auditStats", "/audit/stats", url.Values{"duration": {*duration}}},
+	}
+
+	report := map[string]interface{}{
+		"generatedAt": time.Now().UTC().Format(time.RFC3339),
+		"cluster":     client.cluster,
+	}
+	errs := map[string]string{}
+	for _, s := range sections {
+		data, err := client.get(s.path, s.query)
+		if err != nil {
+			// 单个分区失败不影响整体导出
+			errs[s.key] = err.Error()
+			continue
+		}
+		report[s.key] = json.RawMessage(data)
+	}
+	if len(errs) > 0 {
+		report["errors"] = errs
+	}
+
+	payload, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		fmt.Fprintln(stdout, string(payload))
+		return nil
+	}
+	return os.WriteFile(*output, append(payload, '\n'), 0o644)
+}
+
+func printJSON(data []byte) error {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		fmt.Fprintln(stdout, string(data))
+		return nil
+	}
+	fmt.Fprintln(stdout, buf.String())
+	return nil
+}
+
+func setIfNotEmpty(query url.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
+
+func envOrDefault(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "dashctl:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recordedRequest API 服务端收到的一次请求
+type recordedRequest struct {
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	body   string
+}
+
+// newTestServer 启动模拟 API 服务，按路径返回 responses 中的内容（缺省为 {}），并记录收到的请求
+func newTestServer(t *testing.T, responses map[string]string) (*apiClient, *[]recordedRequest) {
+	t.Helper()
+	var requests []recordedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, recordedRequest{
+			method: r.Method,
+			path:   r.URL.EscapedPath(),
+			query:  r.URL.Query(),
+			header: r.Header.Clone(),
+			body:   string(body),
+		})
+		if resp, ok := responses[r.URL.Path]; ok {
+			if strings.HasPrefix(resp, "!") {
+				w.WriteHeader(http.StatusForbidden)
+				resp = resp[1:]
+			}
+			w.Write([]byte(resp))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+	return newAPIClient(server.URL+"/", "secret-token", "prod"), &requests
+}
+
+// captureStdout 将命令输出重定向到缓冲区
+func captureStdout(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	prev := stdout
+	stdout = &buf
+	t.Cleanup(func() { stdout = prev })
+	return &buf
+}
+
+func TestClientSendsAuthAndClusterHeaders(t *testing.T) {
+	client, requests := newTestServer(t, nil)
+	if _, err := client.post("/approvals/1/approve", map[string]string{"comment": "ok"}); err != nil {
+		t.Fatalf("post failed: %v", err)
+	}
+
+	req := (*requests)[0]
+	if req.method != http.MethodPost || req.path != "/api/v2/approvals/1/approve" {
+		t.Fatalf("request = %s %s", req.method, req.path)
+	}
+	if got := req.header.Get("Authorization"); got != "Bearer secret-token" {
+		t.Fatalf("Authorization = %q", got)
+	}
+	if got := req.header.Get("X-Cluster"); got != "prod" {
+		t.Fatalf("X-Cluster = %q", got)
+	}
+	if got := req.header.Get("Content-Type"); got != "application/json" {
+		t.Fatalf("Content-Type = %q", got)
+	}
+}
+
+func TestClientOmitsEmptyClusterAndBody(t *testing.T) {
+	client, requests := newTestServer(t, nil)
+	client.cluster = ""
+	if _, err := client.get("/audit", nil); err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+
+	req := (*requests)[0]
+	if _, ok := req.header["X-Cluster"]; ok {
+		t.Fatalf("unexpected X-Cluster header %q", req.header.Get("X-Cluster"))
+	}
+	if req.header.Get("Content-Type") != "" || req.body != "" {
+		t.Fatalf("GET should not send a body, got %q", req.body)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	client, _ := newTestServer(t, map[string]string{
+		"/api/v2/json-error":  `!{"error":"权限不足"}`,
+		"/api/v2/plain-error": "!forbidden\n",
+	})
+
+	_, err := client.get("/json-error", nil)
+	if err == nil || err.Error() != "GET /json-error: 403 权限不足" {
+		t.Fatalf("json error = %v", err)
+	}
+	_, err = client.get("/plain-error", nil)
+	if err == nil || err.Error() != "GET /plain-error: 403 forbidden" {
+		t.Fatalf("plain error = %v", err)
+	}
+}
+
+func TestCommandRequests(t *testing.T) {
+	tests := []struct {
+		name      string
+		run       func(*apiClient, []string) error
+		args      []string
+		wantPath  string
+		wantQuery url.Values
+		wantBody  string
+	}{
+		{
+			name:      "anomalies default summary",
+			run:       runAnomalies,
+			wantPath:  "/api/v2/observation/summary",
+			wantQuery: url.Values{},
+		},
+		{
+			name:      "anomalies pods with namespace",
+			run:       runAnomalies,
+			args:      []string{"pods", "-namespace", "shop"},
+			wantPath:  "/api/v2/observation/pods/anomaly",
+			wantQuery: url.Values{"namespace": {"shop"}},
+		},
+		{
+			name:      "anomalies flags without kind",
+			run:       runAnomalies,
+			args:      []string{"-namespace", "shop"},
+			wantPath:  "/api/v2/observation/summary",
+			wantQuery: url.Values{"namespace": {"shop"}},
+		},
+		{
+			name:      "approvals list defaults",
+			run:       runApprovals,
+			args:      []string{"list"},
+			wantPath:  "/api/v2/approvals",
+			wantQuery: url.Values{"status": {"pending"}, "page": {"1"}, "pageSize": {"20"}},
+		},
+		{
+			name:      "approvals list all statuses",
+			run:       runApprovals,
+			args:      []string{"list", "-status", "", "-page", "3", "-page-size", "50"},
+			wantPath:  "/api/v2/approvals",
+			wantQuery: url.Values{"page": {"3"}, "pageSize": {"50"}},
+		},
+		{
+			name:      "approvals reject with comment",
+			run:       runApprovals,
+			args:      []string{"reject", "42", "-comment", "不在变更窗口"},
+			wantPath:  "/api/v2/approvals/42/reject",
+			wantQuery: url.Values{},
+			wantBody:  `{"comment":"不在变更窗口"}`,
+		},
+		{
+			name:      "restart escapes path segments",
+			run:       runRestart,
+			args:      []string{"statefulset", "data", "redis/primary"},
+			wantPath:  "/api/v2/namespaces/data/statefulsets/redis%2Fprimary/restart",
+			wantQuery: url.Values{},
+		},
+		{
+			name:      "audit filters",
+			run:       runAudit,
+			args:      []string{"-user", "alice", "-resource", "deployments", "-page-size", "5"},
+			wantPath:  "/api/v2/audit",
+			wantQuery: url.Values{"user": {"alice"}, "resource": {"deployments"}, "page": {"1"}, "pageSize": {"5"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			captureStdout(t)
+			client, requests := newTestServer(t, nil)
+			if err := tt.run(client, tt.args); err != nil {
+				t.Fatalf("run failed: %v", err)
+			}
+			if len(*requests) != 1 {
+				t.Fatalf("expected 1 request, got %d", len(*requests))
+			}
+			req := (*requests)[0]
+			if req.path != tt.wantPath {
+				t.Fatalf("path = %s, want %s", req.path, tt.wantPath)
+			}
+			if req.query.Encode() != tt.wantQuery.Encode() {
+				t.Fatalf("query = %s, want %s", req.query.Encode(), tt.wantQuery.Encode())
+			}
+			if req.body != tt.wantBody {
+				t.Fatalf("body = %s, want %s", req.body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestAuditSinceSetsStartTime(t *testing.T) {
+	captureStdout(t)
+	client, requests := newTestServer(t, nil)
+	before := time.Now().Add(-time.Hour).Add(-time.Second)
+	if err := runAudit(client, []string{"-since", "1h"}); err != nil {
+		t.Fatalf("runAudit failed: %v", err)
+	}
+	start, err := time.Parse(time.RFC3339, (*requests)[0].query.Get("startTime"))
+	if err != nil {
+		t.Fatalf("invalid startTime: %v", err)
+	}
+	if start.Before(before) || start.After(time.Now().Add(-time.Hour)) {
+		t.Fatalf("startTime %s not about one hour ago", start)
+	}
+}
+
+func TestCommandArgumentErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(*apiClient, []string) error
+		args []string
+		want string
+	}{
+		{"unknown anomaly kind", runAnomalies, []string{"disks"}, "未知异常类型"},
+		{"unknown anomalies flag", runAnomalies, []string{"-bogus"}, "flag provided but not defined"},
+		{"approvals without subcommand", runApprovals, nil, "用法"},
+		{"approvals unknown subcommand", runApprovals, []string{"delete"}, "未知子命令"},
+		{"approve without id", runApprovals, []string{"approve"}, "用法"},
+		{"approve invalid id", runApprovals, []string{"approve", "abc"}, "无效的审批ID"},
+		{"approvals list invalid page", runApprovals, []string{"list", "-page", "x"}, "invalid value"},
+		{"restart wrong arity", runRestart, []string{"deployment", "default"}, "用法"},
+		{"restart unsupported kind", runRestart, []string{"job", "default", "x"}, "不支持重启"},
+		{"audit invalid since", runAudit, []string{"-since", "yesterday"}, "invalid value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			captureStdout(t)
+			client, requests := newTestServer(t, nil)
+			err := tt.run(client, tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error = %v, want containing %q", err, tt.want)
+			}
+			if len(*requests) != 0 {
+				t.Fatalf("expected no request on argument error, got %d", len(*requests))
+			}
+		})
+	}
+}
+
+func TestReportCollectsSectionsAndErrors(t *testing.T) {
+	client, requests := newTestServer(t, map[string]string{
+		"/api/v2/observation/summary": `{"pods":3}`,
+		"/api/v2/audit/stats":         `!{"error":"需要管理员权限"}`,
+	})
+	output := filepath.Join(t.TempDir(), "report.json")
+	if err := runReport(client, []string{"-duration", "7d", "-o", output}); err != nil {
+		t.Fatalf("runReport failed: %v", err)
+	}
+
+	var statsQuery url.Values
+	for _, req := range *requests {
+		if req.path == "/api/v2/audit/stats" {
+			statsQuery = req.query
+		}
+	}
+	if statsQuery.Get("duration") != "7d" {
+		t.Fatalf("audit stats duration = %q", statsQuery.Get("duration"))
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	var report struct {
+		Cluster string            `json:"cluster"`
+		Summary map[string]int    `json:"summary"`
+		Errors  map[string]string `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	if report.Cluster != "prod" || report.Summary["pods"] != 3 {
+		t.Fatalf("unexpected report %s", data)
+	}
+	if !strings.Contains(report.Errors["auditStats"], "需要管理员权限") || len(report.Errors) != 1 {
+		t.Fatalf("unexpected errors %v", report.Errors)
+	}
+}
+
+func TestPrintJSON(t *testing.T) {
+	out := captureStdout(t)
+	if err := printJSON([]byte(`{"a":1}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := printJSON([]byte("not json")); err != nil {
+		t.Fatal(err)
+	}
+	if err := printJSON([]byte("  ")); err != nil {
+		t.Fatal(err)
+	}
+	if want := "{\n  \"a\": 1\n}\nnot json\n"; out.String() != want {
+		t.Fatalf("output = %q, want %q", out.String(), want)
+	}
+}
@@ -13,45 +13,86 @@ import (
 	"github.com/k8s-dashboard/backend/internal/alertmanager"
 	"github.com/k8s-dashboard/backend/internal/alerts"
 	"github.com/k8s-dashboard/backend/internal/api"
+	"github.com/k8s-dashboard/backend/internal/api/handlers"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
 	"github.com/k8s-dashboard/backend/internal/audit"
 	"github.com/k8s-dashboard/backend/internal/auth"
 	"github.com/k8s-dashboard/backend/internal/clusters"
+	"github.com/k8s-dashboard/backend/internal/config"
+	"github.com/k8s-dashboard/backend/internal/cost"
 	"github.com/k8s-dashboard/backend/internal/db"
+	"github.com/k8s-dashboard/backend/internal/eventstore"
 	"github.com/k8s-dashboard/backend/internal/k8s"
 	"github.com/k8s-dashboard/backend/internal/metrics"
+	"github.com/k8s-dashboard/backend/internal/notifications"
+	"github.com/k8s-dashboard/backend/internal/notify"
+	"github.com/k8s-dashboard/backend/internal/observation"
+	"github.com/k8s-dashboard/backend/internal/panels"
+	"github.com/k8s-dashboard/backend/internal/promql"
+	"github.com/k8s-dashboard/backend/internal/ratelimit"
+	"github.com/k8s-dashboard/backend/internal/recommendations"
+	"github.com/k8s-dashboard/backend/internal/runbooks"
+	"github.com/k8s-dashboard/backend/internal/tracing"
+	"github.com/k8s-dashboard/backend/internal/webhook"
 )
 
 func main() {
+	// 加载配置（默认值 < 配置文件 < 环境变量），校验失败直接退出
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	log.Printf("Environment: %s", cfg.Environment)
+
+	// 初始化链路追踪（需在创建各客户端之前，未配置 OTLP 地址时为 noop）
+	shutdownTracing, err := tracing.Init(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to init tracing: %v", err)
+	}
+	if tracing.Enabled() {
+		log.Println("OpenTelemetry tracing enabled")
+	}
+
 	// 初始化 Kubernetes 客户端
 	k8sClient, err := k8s.NewClient()
 	if err != nil {
 		log.Fatalf("Failed to create Kubernetes client: %v", err)
 	}
 
+	// 按用户身份访问 Kubernetes（K8S_USER_AUTH=token|impersonate），使集群 RBAC 对每个用户生效
+	userClients, err := k8s.NewUserClients(cfg.K8sUserAuth)
+	if err != nil {
+		log.Fatalf("Failed to init user Kubernetes clients: %v", err)
+	}
+	log.Printf("Kubernetes user auth mode: %s", userClients.Mode())
+
 	// 初始化 VictoriaMetrics 客户端
-	vmURL := os.Getenv("VICTORIA_METRICS_URL")
-	if vmURL == "" {
-		vmURL = "http://192.168.1.90:31007"
+	metricsClient := metrics.NewClientWithConfig(cfg.VictoriaMetricsURL, cfg.Metrics)
+	metricsClient.SetQueryPath(cfg.VictoriaMetricsQueryPath)
+	log.Printf("VictoriaMetrics URL: %s (query path: %s)", cfg.VictoriaMetricsURL, cfg.VictoriaMetricsQueryPath)
+
+	// 内置查询的指标与标签映射（METRICS_MAPPING），适配只有 cAdvisor、kube-state-metrics v2 等非标准命名
+	var queryRewriter metrics.QueryRewriter
+	if !cfg.MetricsMapping.Empty() {
+		rewriter, err := promql.NewRewriter(cfg.MetricsMapping)
+		if err != nil {
+			log.Fatalf("Failed to parse metrics mapping: %v", err)
+		}
+		queryRewriter = rewriter
+		metricsClient.SetQueryRewriter(rewriter)
+		log.Printf("Metrics mapping enabled: %d metrics, %d labels", len(cfg.MetricsMapping.Metrics), len(cfg.MetricsMapping.Labels))
 	}
-	metricsClient := metrics.NewClient(vmURL)
-	log.Printf("VictoriaMetrics URL: %s", vmURL)
 
 	// 初始化 Alertmanager 客户端
-	amURL := os.Getenv("ALERTMANAGER_URL")
-	if amURL == "" {
-		amURL = "http://192.168.1.90:32607"
-	}
-	alertClient := alertmanager.NewClient(amURL)
-	log.Printf("Alertmanager URL: %s", amURL)
+	alertClient := alertmanager.NewClient(cfg.AlertmanagerURL)
+	log.Printf("Alertmanager URL: %s", cfg.AlertmanagerURL)
 
-	// JWT 密钥
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		jwtSecret = "k8s-dashboard-secret-key-change-in-production"
-	}
+	// 告警严重级别映射（兼容 P1/P2、sev1 等自定义级别），未配置时使用默认映射
+	severityMapping := cfg.AlertSeverityMapping
+	alertClient.SetSeverityMapping(severityMapping)
 
 	// 初始化数据库连接（PostgreSQL 优先，失败可按配置回落 SQLite）
-	database, dialect, err := db.OpenFromEnv()
+	database, dialect, err := db.Open(cfg.Database)
 	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
@@ -61,21 +102,117 @@ func main() {
 
 	// 初始化依赖数据库的模块
 	var auditClient *audit.Client
+	var auditForwarder *audit.Forwarder
 	var authClient *auth.Client
 	var alertService *alerts.Service
 	var clusterManager *clusters.Manager
+	var panelService *panels.Service
+	var runbookService *runbooks.Service
+	var eventRepo *eventstore.Repository
 
 	// 初始化审计日志客户端
 	auditClient, err = audit.NewClient(database, dialect)
 	if err != nil {
 		log.Printf("Warning: 审计日志初始化失败: %v", err)
+	} else {
+		// 终端会话录制
+		if err := auditClient.SetTerminalRecording(cfg.TerminalRecording); err != nil {
+			log.Fatalf("Failed to configure terminal recording: %v", err)
+		}
+		log.Printf("Terminal session recording enabled: %v", cfg.TerminalRecording.Enabled)
+		if err := auditClient.SetArchiveDir(cfg.AuditArchiveDir); err != nil {
+			log.Fatalf("Failed to configure audit archive: %v", err)
+		}
+		if cfg.AuditArchiveDir != "" && cfg.AuditRetentionDays == 0 {
+			log.Printf("Warning: 已设置 AUDIT_ARCHIVE_DIR，但 AUDIT_RETENTION_DAYS=0，审计日志不会被归档")
+		}
+		auditForwarder = newAuditForwarder(cfg.AuditForward)
+		auditClient.SetForwarder(auditForwarder)
 	}
 
 	// 初始化认证客户端
-	authClient, err = auth.NewClient(database, dialect, jwtSecret)
+	if cfg.JWTSecret == config.DefaultJWTSecret {
+		log.Printf("Warning: 正在使用默认 JWT_SECRET，仅限开发环境")
+	}
+	authClient, err = auth.NewClient(database, dialect, cfg.JWTSecret)
 	if err != nil {
 		log.Fatalf("Failed to initialize auth module: %v", err)
 	}
+	authClient.SetPasswordPolicy(cfg.PasswordPolicy)
+	authClient.SetTokenLifetimes(cfg.Session.AccessTokenTTL(), cfg.Session.RefreshTokenTTL())
+
+	// 用户生命周期事件 Webhook（供身份治理系统对账）
+	if hookURL := cfg.UserWebhook.URL; hookURL != "" {
+		userHook := webhook.NewClient(hookURL, cfg.UserWebhook.Secret)
+		authClient.SetEventHandler(func(event auth.UserEvent) {
+			userHook.Dispatch(event.Type, event)
+		})
+		log.Printf("User lifecycle webhook: %s", hookURL)
+	}
+
+	// 实时通知：审批变更即时推送给在线用户
+	notifyHub := notify.NewHub(authClient.GetPendingApprovalCount)
+	approvalHandler := notifyHub.HandleApproval
+
+	// 审批通知：推送到 Webhook、Slack 与邮件，审批人无需在线轮询
+	var approvalSinks []notify.Sink
+	notifyCfg := cfg.ApprovalNotify
+	if notifyCfg.WebhookURL != "" {
+		approvalSinks = append(approvalSinks, notify.NewWebhookSink(notifyCfg.WebhookURL, notifyCfg.WebhookSecret))
+	}
+	if notifyCfg.SlackWebhookURL != "" {
+		approvalSinks = append(approvalSinks, notify.NewSlackSink(notifyCfg.SlackWebhookURL))
+	}
+	if smtpCfg := notifyCfg.SMTP; smtpCfg.Host != "" {
+		approvalSinks = append(approvalSinks, notify.NewEmailSink(smtpCfg.Host, smtpCfg.Port, smtpCfg.Username, smtpCfg.Password, smtpCfg.From, smtpCfg.To))
+	}
+
+	// 通知渠道：管理员在界面中配置的 Slack、钉钉、企业微信、邮件与 Webhook 渠道，按订阅的事件接收通知
+	var channelService *notifications.Service
+	var channelSinks []notify.Sink
+	channelRepo, err := notifications.NewRepository(database, dialect)
+	if err != nil {
+		log.Printf("Warning: 通知渠道数据仓库初始化失败: %v", err)
+	} else {
+		channelService = notifications.NewService(channelRepo)
+		channelSinks = append(channelSinks, channelService)
+	}
+
+	if sinks := append(append([]notify.Sink{}, approvalSinks...), channelSinks...); len(sinks) > 0 {
+		notifier, err := notify.NewApprovalNotifier(notifyCfg.Template, notifyCfg.DashboardURL, authClient.ApprovalNotificationEmails, sinks...)
+		if err != nil {
+			log.Fatalf("Failed to initialize approval notifications: %v", err)
+		}
+		approvalHandler = func(eventType string, approval *auth.ApprovalRequest) {
+			notifyHub.HandleApproval(eventType, approval)
+			notifier.HandleApproval(eventType, approval)
+		}
+		log.Printf("Approval notifications enabled: %d sink(s)", len(sinks))
+	}
+	authClient.SetApprovalHandler(approvalHandler)
+	go notifyHub.Run(context.Background())
+
+	// 审计异常检测：告警推送给在线 admin 与订阅的通知渠道，可选同时推送到审批通知渠道
+	if auditClient != nil && cfg.AuditAnomaly.Enabled {
+		detector, err := audit.NewAnomalyDetector(cfg.AuditAnomaly)
+		if err != nil {
+			log.Fatalf("Failed to initialize audit anomaly detection: %v", err)
+		}
+		var anomalyHandler audit.AnomalyHandler = notifyHub.HandleAnomaly
+		anomalySinks := channelSinks
+		if cfg.AuditAnomaly.Notify {
+			anomalySinks = append(append([]notify.Sink{}, approvalSinks...), channelSinks...)
+		}
+		if len(anomalySinks) > 0 {
+			anomalyNotifier := notify.NewAnomalyNotifier(notifyCfg.DashboardURL, anomalySinks...)
+			anomalyHandler = func(anomaly *audit.AuditAnomaly) {
+				notifyHub.HandleAnomaly(anomaly)
+				anomalyNotifier.HandleAnomaly(anomaly)
+			}
+		}
+		auditClient.SetAnomalyDetector(detector, anomalyHandler)
+		log.Printf("Audit anomaly detection enabled: %d rule(s)", len(cfg.AuditAnomaly.Rules))
+	}
 
 	// 初始化告警服务
 	alertRepo, err := alerts.NewRepository(database, dialect)
@@ -83,28 +220,135 @@ func main() {
 		log.Printf("Warning: 告警数据仓库初始化失败: %v", err)
 	} else {
 		alertService = alerts.NewService(alertRepo, alertClient)
+		// Alertmanager Webhook 推送（或定期拉取）的告警实时推送给在线用户与订阅的通知渠道，
+		// 可选同时推送到审批通知渠道；并按告警路由推送到各团队配置的渠道
+		historyHandlers := []alerts.HistoryHandler{notifyHub.HandleAlerts}
+		alertSinks := channelSinks
+		if cfg.AlertWebhook.Notify {
+			alertSinks = append(append([]notify.Sink{}, approvalSinks...), channelSinks...)
+		}
+		if len(alertSinks) > 0 {
+			historyHandlers = append(historyHandlers, notify.NewAlertNotifier(notifyCfg.DashboardURL, alertSinks...).HandleAlerts)
+		}
+		if channelService != nil {
+			historyHandlers = append(historyHandlers, notifications.NewAlertRouter(channelService, notifyCfg.DashboardURL).HandleAlerts)
+		}
+		alertService.SetHistoryHandler(func(entries []*alerts.HistoryEntry) {
+			for _, handle := range historyHandlers {
+				handle(entries)
+			}
+		})
+		if cfg.AlertWebhook.Token != "" {
+			log.Printf("Alertmanager webhook receiver enabled")
+		}
 		log.Printf("告警服务初始化成功")
 	}
 
+	// 初始化自定义监控面板服务
+	panelRepo, err := panels.NewRepository(database, dialect)
+	if err != nil {
+		log.Printf("Warning: 面板数据仓库初始化失败: %v", err)
+	} else {
+		panelService = panels.NewService(panelRepo, metricsClient)
+	}
+
+	// 初始化异常检测阈值（按集群/命名空间覆盖默认阈值）
+	thresholdRepo, err := observation.NewThresholdRepository(database, dialect)
+	if err != nil {
+		log.Printf("Warning: 异常阈值数据仓库初始化失败: %v", err)
+	}
+
+	// 初始化运行手册服务
+	runbookRepo, err := runbooks.NewRepository(database, dialect)
+	if err != nil {
+		log.Printf("Warning: 运行手册数据仓库初始化失败: %v", err)
+	} else {
+		runbookService = runbooks.NewService(runbookRepo)
+	}
+
 	// 初始化多集群管理（可选）
-	if parseBoolEnv("MULTI_CLUSTER_ENABLED", true) {
-		clusterManager, err = clusters.NewManager(database, dialect, jwtSecret, k8sClient)
+	if cfg.MultiClusterEnabled {
+		clusterManager, err = clusters.NewManager(database, dialect, cfg.ClusterEncryptionKey, cfg.JWTSecret, k8sClient)
 		if err != nil {
 			log.Fatalf("Failed to initialize cluster manager: %v", err)
 		}
+		clusterManager.SetAlertSeverityMapping(severityMapping)
+		clusterManager.SetMetricsQueryRewriter(queryRewriter)
+		clusterManager.SetMetricsConfig(cfg.Metrics)
 		log.Printf("多集群管理初始化成功")
 	} else {
 		log.Printf("多集群管理已禁用 (MULTI_CLUSTER_ENABLED=false)")
 	}
 
+	// 事件历史：持续采集集群 Event 并持久化，弥补 Kubernetes 事件约 1 小时即过期的问题
+	if cfg.EventHistory.Enabled {
+		eventRepo, err = eventstore.NewRepository(database, dialect)
+		if err != nil {
+			log.Printf("Warning: 事件历史数据仓库初始化失败: %v", err)
+		} else {
+			resolve := func(name string) (*k8s.Client, error) {
+				if clusterManager == nil {
+					return k8sClient, nil
+				}
+				return clusterManager.GetClient(name)
+			}
+			clusterNames := cfg.EventHistory.Clusters
+			eventstore.NewCollector(eventRepo, resolve, clusterNames).Run(context.Background())
+			log.Printf("事件历史采集已启动，集群: %s", strings.Join(clusterNames, ","))
+		}
+	}
+
+	// 审计/告警/事件数据维护（预建分区、按保留期清理）
+	go runDataMaintenance(auditClient, alertService, eventRepo,
+		time.Duration(cfg.AuditRetentionDays)*24*time.Hour,
+		time.Duration(cfg.AlertRetentionDays)*24*time.Hour,
+		time.Duration(cfg.EventHistory.RetentionDays)*24*time.Hour,
+	)
+
+	// 未配置 Alertmanager Webhook 时定期拉取默认集群的告警，同样写入告警历史并触发通知与告警路由
+	if alertService != nil && cfg.AlertWebhook.PollSeconds > 0 {
+		go runAlertPolling(alertService, clusterManager, alertClient, time.Duration(cfg.AlertWebhook.PollSeconds)*time.Second)
+		log.Printf("Alertmanager polling enabled: every %ds", cfg.AlertWebhook.PollSeconds)
+	}
+
+	// 容器资源建议与费用估算，参数已在加载配置时校验
+	recommendationService, err := recommendations.NewService(cfg.Recommendations)
+	if err != nil {
+		log.Fatalf("Failed to initialize recommendation service: %v", err)
+	}
+	costService, err := cost.NewService(cfg.Cost)
+	if err != nil {
+		log.Fatalf("Failed to initialize cost service: %v", err)
+	}
+	queryPolicy, err := promql.NewPolicy(cfg.MetricsQuery)
+	if err != nil {
+		log.Fatalf("Failed to initialize metrics query policy: %v", err)
+	}
+
+	// WebSocket 会话限制，时长已在加载配置时校验
+	wsIdle, wsMaxDuration, wsWarning, _ := cfg.WebSocket.SessionLimits()
+
 	// 创建路由
-	router := api.NewRouter(k8sClient, clusterManager, metricsClient, alertClient, alertService, auditClient, authClient)
+	router := api.NewRouter(k8sClient, clusterManager, metricsClient, alertClient, alertService, auditClient, authClient, panelService, runbookService, eventRepo, notifyHub, userClients, recommendationService, costService, channelService, thresholdRepo, ratelimit.NewLimiter(cfg.RateLimit), queryPolicy, api.Options{
+		AlertWebhookToken: cfg.AlertWebhook.Token,
+		WSAuth: middleware.WSAuthConfig{
+			AllowedOrigins:  cfg.WebSocket.AllowedOrigins,
+			AllowQueryToken: cfg.WebSocket.AllowQueryToken,
+		},
+		Handler: handlers.Options{
+			WSAllowedOrigins:     cfg.WebSocket.AllowedOrigins,
+			WSIdleTimeout:        wsIdle,
+			WSMaxSessionDuration: wsMaxDuration,
+			WSTimeoutWarning:     wsWarning,
+			PacketCaptureImage:   cfg.PacketCaptureImage,
+			NodePoolLabel:        cfg.NodePoolLabel,
+		},
+		V1Sunset:       cfg.APIV1SunsetDate(),
+		TrustedProxies: cfg.TrustedProxies,
+	})
 
 	// 配置 HTTP 服务器
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
+	port := cfg.Port
 
 	srv := &http.Server{
 		Addr:         ":" + port,
@@ -135,22 +379,102 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
+	if auditForwarder != nil {
+		auditForwarder.Close(ctx)
+	}
+	if err := shutdownTracing(ctx); err != nil {
+		log.Printf("Failed to flush traces: %v", err)
+	}
 
 	log.Println("Server exited")
 }
 
-func parseBoolEnv(key string, def bool) bool {
-	v := strings.TrimSpace(strings.ToLower(os.Getenv(key)))
-	if v == "" {
-		return def
+// newAuditForwarder 按配置创建审计日志外部转发器，未配置任何渠道时返回 nil
+func newAuditForwarder(cfg config.AuditForwardConfig) *audit.Forwarder {
+	var sinks []audit.Sink
+	var names []string
+	if cfg.WebhookURL != "" {
+		sinks = append(sinks, audit.NewWebhookSink(cfg.WebhookURL, cfg.WebhookSecret))
+	}
+	if cfg.SyslogAddr != "" {
+		sink, err := audit.NewSyslogSink(cfg.SyslogAddr)
+		if err != nil {
+			log.Fatalf("Invalid AUDIT_SYSLOG_ADDR: %v", err)
+		}
+		sinks = append(sinks, sink)
+	}
+	if cfg.KafkaRESTURL != "" {
+		sinks = append(sinks, audit.NewKafkaRESTSink(cfg.KafkaRESTURL, cfg.KafkaTopic))
+	}
+	if len(sinks) == 0 {
+		return nil
+	}
+	for _, sink := range sinks {
+		names = append(names, sink.Name())
+	}
+	log.Printf("审计日志外部转发已启用: %s", strings.Join(names, ","))
+	return audit.NewForwarder(cfg.BufferSize, sinks...)
+}
+
+// runAlertPolling 按间隔拉取默认集群 Alertmanager 的告警写入告警历史
+func runAlertPolling(alertService *alerts.Service, clusterManager *clusters.Manager, alertClient *alertmanager.Client, interval time.Duration) {
+	run := func() {
+		var cluster string
+		client := alertClient
+		if clusterManager != nil {
+			name, err := clusterManager.ResolveClusterName("")
+			if err != nil {
+				log.Printf("Warning: 拉取告警失败: %v", err)
+				return
+			}
+			cluster = name
+			// 默认集群单独配置了 Alertmanager 时使用集群专属地址
+			if _, clusterAlerts, err := clusterManager.GetEndpointClients(name); err == nil && clusterAlerts != nil {
+				client = clusterAlerts
+			}
+		}
+		if _, err := alertService.WithAlertmanager(cluster, client).SyncActiveAlerts(); err != nil {
+			log.Printf("Warning: 拉取告警失败: %v", err)
+		}
+	}
+
+	run()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		run()
+	}
+}
+
+// runDataMaintenance 启动时及每天执行一次数据维护
+func runDataMaintenance(auditClient *audit.Client, alertService *alerts.Service, eventRepo *eventstore.Repository, auditRetention, alertRetention, eventRetention time.Duration) {
+	run := func() {
+		if auditClient != nil {
+			if err := auditClient.MaintainPartitions(auditRetention); err != nil {
+				log.Printf("Warning: 审计数据维护失败: %v", err)
+			}
+		}
+		if alertService != nil {
+			if n, err := alertService.PurgeExpired(alertRetention); err != nil {
+				log.Printf("Warning: 告警数据清理失败: %v", err)
+			} else if n > 0 {
+				log.Printf("已清理 %d 条过期告警确认/静默记录", n)
+			}
+		}
+		// EVENT_RETENTION_DAYS=0 表示永久保留
+		if eventRepo != nil && eventRetention > 0 {
+			if n, err := eventRepo.PurgeBefore(time.Now().Add(-eventRetention)); err != nil {
+				log.Printf("Warning: 事件历史清理失败: %v", err)
+			} else if n > 0 {
+				log.Printf("已清理 %d 条过期事件", n)
+			}
+		}
 	}
 
-	switch v {
-	case "1", "true", "yes", "on":
-		return true
-	case "0", "false", "no", "off":
-		return false
-	default:
-		return def
+	run()
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+	for range ticker.C {
+		run()
 	}
 }
@@ -2,10 +2,14 @@ package alertmanager
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -13,6 +17,8 @@ import (
 type Client struct {
 	baseURL    string
 	httpClient *http.Client
+	severity   *SeverityMapping
+	ctx        context.Context
 }
 
 // NewClient 创建 Alertmanager 客户端
@@ -22,6 +28,52 @@ func NewClient(baseURL string) *Client {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		severity: DefaultSeverityMapping(),
+	}
+}
+
+// SetSeverityMapping 设置严重级别映射，nil 时恢复默认映射
+func (c *Client) SetSeverityMapping(mapping *SeverityMapping) {
+	if mapping == nil {
+		mapping = DefaultSeverityMapping()
+	}
+	c.severity = mapping
+}
+
+// WithContext 返回绑定请求上下文的客户端副本，请求随上下文取消或超时而中止
+func (c *Client) WithContext(ctx context.Context) *Client {
+	if c == nil {
+		return nil
+	}
+	clone := *c
+	clone.ctx = ctx
+	return &clone
+}
+
+// do 使用绑定的上下文发送请求
+func (c *Client) do(method, rawURL, contentType string, body io.Reader) (*http.Response, error) {
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return c.httpClient.Do(req)
+}
+
+func (c *Client) get(rawURL string) (*http.Response, error) {
+	return c.do(http.MethodGet, rawURL, "", nil)
+}
+
+// classifyAlerts 为告警填充统一后的严重级别
+func (c *Client) classifyAlerts(alerts []Alert) {
+	for i := range alerts {
+		alerts[i].Severity = c.severity.Classify(alerts[i].Labels)
 	}
 }
 
@@ -36,6 +88,8 @@ type Alert struct {
 	Status       AlertStatus       `json:"status"`
 	Receivers    []Receiver        `json:"receivers"`
 	UpdatedAt    time.Time         `json:"updatedAt"`
+	// Severity 经映射/重分类后的统一严重级别（critical, warning, info）
+	Severity string `json:"severity"`
 }
 
 // AlertStatus 告警状态
@@ -68,7 +122,7 @@ type AlertGroup struct {
 
 // GetAlerts 获取所有告警
 func (c *Client) GetAlerts() ([]Alert, error) {
-	resp, err := c.httpClient.Get(fmt.Sprintf("%s/api/v2/alerts", c.baseURL))
+	resp, err := c.get(fmt.Sprintf("%s/api/v2/alerts", c.baseURL))
 	if err != nil {
 		return nil, fmt.Errorf("获取告警失败: %w", err)
 	}
@@ -84,12 +138,13 @@ func (c *Client) GetAlerts() ([]Alert, error) {
 		return nil, fmt.Errorf("解析告警失败: %w", err)
 	}
 
+	c.classifyAlerts(alerts)
 	return alerts, nil
 }
 
 // GetAlertGroups 获取告警分组
 func (c *Client) GetAlertGroups() ([]AlertGroup, error) {
-	resp, err := c.httpClient.Get(fmt.Sprintf("%s/api/v2/alerts/groups", c.baseURL))
+	resp, err := c.get(fmt.Sprintf("%s/api/v2/alerts/groups", c.baseURL))
 	if err != nil {
 		return nil, fmt.Errorf("获取告警分组失败: %w", err)
 	}
@@ -105,6 +160,9 @@ func (c *Client) GetAlertGroups() ([]AlertGroup, error) {
 		return nil, fmt.Errorf("解析告警分组失败: %w", err)
 	}
 
+	for i := range groups {
+		c.classifyAlerts(groups[i].Alerts)
+	}
 	return groups, nil
 }
 
@@ -125,16 +183,13 @@ func (c *Client) GetAlertSummary() (*AlertSummary, error) {
 			continue
 		}
 
-		severity := alert.Labels["severity"]
-		switch severity {
-		case "critical":
+		// 严重级别已在 GetAlerts 中按映射规则归一化
+		switch alert.Severity {
+		case SeverityCritical:
 			summary.Critical++
-		case "warning":
-			summary.Warning++
-		case "info":
+		case SeverityInfo:
 			summary.Info++
 		default:
-			// 没有指定严重级别的告警，默认为 warning
 			summary.Warning++
 		}
 	}
@@ -148,6 +203,26 @@ type AlertFilter struct {
 	Namespace string
 	AlertName string
 	State     string // active, suppressed, unprocessed
+	// Search 不区分大小写匹配告警名称、标签值与 summary/description 注解
+	Search string
+	// Sort 排序方式：severity（默认，级别高的在前，同级别按触发时间倒序）、startsAt（触发时间倒序）、alertname
+	Sort string
+}
+
+// 告警排序方式
+const (
+	SortBySeverity  = "severity"
+	SortByStartsAt  = "startsAt"
+	SortByAlertName = "alertname"
+)
+
+// ValidAlertSort 判断排序方式是否支持，空值表示默认排序
+func ValidAlertSort(sortBy string) bool {
+	switch sortBy {
+	case "", SortBySeverity, SortByStartsAt, SortByAlertName:
+		return true
+	}
+	return false
 }
 
 // GetActiveAlerts 获取活跃告警（按严重级别排序）
@@ -162,6 +237,10 @@ func (c *Client) GetFilteredAlerts(filter AlertFilter) ([]Alert, error) {
 		return nil, err
 	}
 
+	// 过滤条件同样支持自定义级别（如 P1），先换算为统一级别
+	severity := c.severity.FilterValue(filter.Severity)
+	search := strings.ToLower(strings.TrimSpace(filter.Search))
+
 	// 过滤告警
 	var filteredAlerts []Alert
 	for _, alert := range alerts {
@@ -171,7 +250,7 @@ func (c *Client) GetFilteredAlerts(filter AlertFilter) ([]Alert, error) {
 		}
 
 		// 严重级别过滤
-		if filter.Severity != "" && alert.Labels["severity"] != filter.Severity {
+		if severity != "" && alert.Severity != severity {
 			continue
 		}
 
@@ -185,27 +264,55 @@ func (c *Client) GetFilteredAlerts(filter AlertFilter) ([]Alert, error) {
 			continue
 		}
 
+		// 关键字搜索
+		if search != "" && !alertMatchesSearch(alert, search) {
+			continue
+		}
+
 		filteredAlerts = append(filteredAlerts, alert)
 	}
 
-	// 按严重级别排序: critical > warning > info
-	severityOrder := map[string]int{
-		"critical": 0,
-		"warning":  1,
-		"info":     2,
-	}
+	sortAlerts(filteredAlerts, filter.Sort)
 
-	for i := 0; i < len(filteredAlerts)-1; i++ {
-		for j := i + 1; j < len(filteredAlerts); j++ {
-			si := severityOrder[filteredAlerts[i].Labels["severity"]]
-			sj := severityOrder[filteredAlerts[j].Labels["severity"]]
-			if si > sj {
-				filteredAlerts[i], filteredAlerts[j] = filteredAlerts[j], filteredAlerts[i]
-			}
+	return filteredAlerts, nil
+}
+
+// alertMatchesSearch 告警名称、任一标签值或 summary/description 注解包含关键字（keyword 已转为小写）
+func alertMatchesSearch(alert Alert, keyword string) bool {
+	for _, v := range alert.Labels {
+		if strings.Contains(strings.ToLower(v), keyword) {
+			return true
 		}
 	}
+	for _, key := range []string{"summary", "description", "message"} {
+		if strings.Contains(strings.ToLower(alert.Annotations[key]), keyword) {
+			return true
+		}
+	}
+	return false
+}
 
-	return filteredAlerts, nil
+// sortAlerts 按排序方式排序，相同时按 fingerprint 保证分页顺序稳定
+func sortAlerts(alerts []Alert, sortBy string) {
+	sort.Slice(alerts, func(i, j int) bool {
+		a, b := alerts[i], alerts[j]
+		switch sortBy {
+		case SortByAlertName:
+			if a.Labels["alertname"] != b.Labels["alertname"] {
+				return a.Labels["alertname"] < b.Labels["alertname"]
+			}
+		case SortByStartsAt:
+		default:
+			// 按严重级别排序: critical > warning > info
+			if ra, rb := severityRank(a.Severity), severityRank(b.Severity); ra != rb {
+				return ra < rb
+			}
+		}
+		if !a.StartsAt.Equal(b.StartsAt) {
+			return a.StartsAt.After(b.StartsAt)
+		}
+		return a.Fingerprint < b.Fingerprint
+	})
 }
 
 // GetAlertByFingerprint 根据 fingerprint 获取单个告警
@@ -248,6 +355,9 @@ func (c *Client) GetAlertNames() ([]string, error) {
 
 // ========== 静默管理 ==========
 
+// ErrSilenceNotFound Alertmanager 中不存在该静默规则（已被删除或过期后被回收）
+var ErrSilenceNotFound = errors.New("静默规则不存在")
+
 // Silence 静默规则结构
 type Silence struct {
 	ID        string                   `json:"id"`
@@ -274,7 +384,7 @@ type SilenceStatus struct {
 
 // GetSilences 获取所有静默规则
 func (c *Client) GetSilences() ([]Silence, error) {
-	resp, err := c.httpClient.Get(fmt.Sprintf("%s/api/v2/silences", c.baseURL))
+	resp, err := c.get(fmt.Sprintf("%s/api/v2/silences", c.baseURL))
 	if err != nil {
 		return nil, fmt.Errorf("获取静默规则失败: %w", err)
 	}
@@ -295,14 +405,14 @@ func (c *Client) GetSilences() ([]Silence, error) {
 
 // GetSilence 获取单个静默规则
 func (c *Client) GetSilence(id string) (*Silence, error) {
-	resp, err := c.httpClient.Get(fmt.Sprintf("%s/api/v2/silence/%s", c.baseURL, id))
+	resp, err := c.get(fmt.Sprintf("%s/api/v2/silence/%s", c.baseURL, id))
 	if err != nil {
 		return nil, fmt.Errorf("获取静默规则失败: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == 404 {
-		return nil, fmt.Errorf("静默规则不存在: %s", id)
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrSilenceNotFound, id)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -325,7 +435,8 @@ func (c *Client) CreateSilence(silence *Silence) (string, error) {
 		return "", fmt.Errorf("序列化静默规则失败: %w", err)
 	}
 
-	resp, err := c.httpClient.Post(
+	resp, err := c.do(
+		http.MethodPost,
 		fmt.Sprintf("%s/api/v2/silences", c.baseURL),
 		"application/json",
 		bytes.NewBuffer(data),
@@ -358,21 +469,15 @@ func (c *Client) CreateSilence(silence *Silence) (string, error) {
 
 // DeleteSilence 删除静默规则
 func (c *Client) DeleteSilence(id string) error {
-	req, err := http.NewRequest(
-		"DELETE",
-		fmt.Sprintf("%s/api/v2/silence/%s", c.baseURL, id),
-		nil,
-	)
-	if err != nil {
-		return fmt.Errorf("创建请求失败: %w", err)
-	}
-
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(http.MethodDelete, fmt.Sprintf("%s/api/v2/silence/%s", c.baseURL, id), "", nil)
 	if err != nil {
 		return fmt.Errorf("删除静默规则失败: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", ErrSilenceNotFound, id)
+	}
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("删除静默规则失败: %s", string(body))
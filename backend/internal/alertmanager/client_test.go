@@ -0,0 +1,127 @@
+package alertmanager
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAlertMatchesSearch(t *testing.T) {
+	alert := Alert{
+		Labels: map[string]string{
+			"alertname": "KubePodCrashLooping",
+			"namespace": "payments",
+			"pod":       "api-7f9c",
+		},
+		Annotations: map[string]string{
+			"summary":     "Pod is crash looping",
+			"description": "Container API restarted 5 times",
+			"message":     "legacy OOMKilled message",
+			"runbook_url": "https://runbooks.example.com/crashloop",
+		},
+	}
+
+	tests := []struct {
+		keyword string
+		want    bool
+	}{
+		{"kubepodcrash", true},  // 告警名称
+		{"payments", true},      // 标签值
+		{"api-7f", true},        // 标签值子串
+		{"crash looping", true}, // summary
+		{"restarted 5", true},   // description
+		{"oomkilled", true},     // message
+		{"runbooks.example", false},
+		{"namespace", false}, // 标签名不参与匹配
+		{"staging", false},
+	}
+	for _, tt := range tests {
+		if got := alertMatchesSearch(alert, tt.keyword); got != tt.want {
+			t.Errorf("alertMatchesSearch(%q) = %v, want %v", tt.keyword, got, tt.want)
+		}
+	}
+}
+
+func TestSortAlerts(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	alert := func(fp, name, severity string, minutes int) Alert {
+		return Alert{
+			Fingerprint: fp,
+			Labels:      map[string]string{"alertname": name},
+			Severity:    severity,
+			StartsAt:    base.Add(time.Duration(minutes) * time.Minute),
+		}
+	}
+	alerts := []Alert{
+		alert("f", "Disk", SeverityInfo, 5),
+		alert("d", "CPU", SeverityWarning, 1),
+		alert("b", "Memory", SeverityCritical, 1),
+		alert("e", "CPU", SeverityWarning, 1),
+		alert("a", "Memory", SeverityCritical, 1),
+		alert("c", "CPU", SeverityCritical, 3),
+	}
+
+	tests := []struct {
+		sortBy string
+		want   []string
+	}{
+		// 级别高的在前，同级别按触发时间倒序，再按 fingerprint
+		{"", []string{"c", "a", "b", "d", "e", "f"}},
+		{SortBySeverity, []string{"c", "a", "b", "d", "e", "f"}},
+		// 触发时间倒序，相同时间按 fingerprint
+		{SortByStartsAt, []string{"f", "c", "a", "b", "d", "e"}},
+		// 名称升序，同名按触发时间倒序，再按 fingerprint
+		{SortByAlertName, []string{"c", "d", "e", "f", "a", "b"}},
+	}
+	for _, tt := range tests {
+		sorted := append([]Alert(nil), alerts...)
+		sortAlerts(sorted, tt.sortBy)
+		got := make([]string, len(sorted))
+		for i, a := range sorted {
+			got[i] = a.Fingerprint
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sortAlerts(%q) = %v, want %v", tt.sortBy, got, tt.want)
+		}
+
+		// 输入顺序不影响结果
+		reversed := make([]Alert, len(alerts))
+		for i, a := range alerts {
+			reversed[len(alerts)-1-i] = a
+		}
+		sortAlerts(reversed, tt.sortBy)
+		for i, a := range reversed {
+			if a.Fingerprint != tt.want[i] {
+				t.Errorf("sortAlerts(%q) on reversed input differs at %d: %s", tt.sortBy, i, a.Fingerprint)
+				break
+			}
+		}
+	}
+}
+
+func TestGetFilteredAlertsSearchesAndSorts(t *testing.T) {
+	alerts := []Alert{
+		{Fingerprint: "1", Labels: map[string]string{"alertname": "HighLatency", "severity": "warning", "namespace": "shop"}, Annotations: map[string]string{"summary": "Checkout is slow"}, Status: AlertStatus{State: "active"}},
+		{Fingerprint: "2", Labels: map[string]string{"alertname": "PodCrash", "severity": "critical", "namespace": "shop"}, Annotations: map[string]string{"description": "checkout pod restarting"}, Status: AlertStatus{State: "active"}},
+		{Fingerprint: "3", Labels: map[string]string{"alertname": "DiskFull", "severity": "critical", "namespace": "ops"}, Status: AlertStatus{State: "active"}},
+		{Fingerprint: "4", Labels: map[string]string{"alertname": "CheckoutDown", "severity": "critical"}, Status: AlertStatus{State: "suppressed"}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(alerts)
+	}))
+	defer server.Close()
+
+	got, err := NewClient(server.URL).GetFilteredAlerts(AlertFilter{State: "active", Search: "  CHECKOUT "})
+	if err != nil {
+		t.Fatalf("GetFilteredAlerts failed: %v", err)
+	}
+	if len(got) != 2 || got[0].Fingerprint != "2" || got[1].Fingerprint != "1" {
+		t.Fatalf("unexpected alerts %+v", got)
+	}
+	if got[0].Severity != SeverityCritical {
+		t.Fatalf("severity not classified: %q", got[0].Severity)
+	}
+}
@@ -0,0 +1,152 @@
+package alertmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// 统一后的严重级别
+const (
+	SeverityCritical = "critical"
+	SeverityWarning  = "warning"
+	SeverityInfo     = "info"
+)
+
+// SeverityRule 按告警名称（可选命名空间）强制重新归类严重级别
+type SeverityRule struct {
+	AlertName string `json:"alertName"`
+	Namespace string `json:"namespace,omitempty"`
+	Severity  string `json:"severity"`
+}
+
+// SeverityMapping 严重级别映射配置，用于兼容 P1/P2、sev1 等自定义级别体系
+//
+// 示例：
+//
+//	{
+//	  "label": "priority",
+//	  "map": {"P1": "critical", "P2": "warning", "P3": "info"},
+//	  "rules": [{"alertName": "Watchdog", "severity": "info"}],
+//	  "default": "warning"
+//	}
+type SeverityMapping struct {
+	// Label 读取原始级别的标签名，默认 severity
+	Label string `json:"label"`
+	// Map 原始级别 -> 统一级别，键不区分大小写
+	Map map[string]string `json:"map"`
+	// Rules 按告警名称重新归类，优先于 Map
+	Rules []SeverityRule `json:"rules"`
+	// Default 无法识别时使用的级别，默认 warning
+	Default string `json:"default"`
+}
+
+// DefaultSeverityMapping 返回与历史行为一致的默认映射
+func DefaultSeverityMapping() *SeverityMapping {
+	return &SeverityMapping{
+		Label:   "severity",
+		Map:     map[string]string{},
+		Default: SeverityWarning,
+	}
+}
+
+// ParseSeverityMapping 解析 JSON 格式的严重级别映射（ALERT_SEVERITY_MAPPING_FILE 的文件内容）
+func ParseSeverityMapping(data []byte) (*SeverityMapping, error) {
+	mapping := DefaultSeverityMapping()
+	if err := json.Unmarshal(data, mapping); err != nil {
+		return nil, fmt.Errorf("解析严重级别映射失败: %w", err)
+	}
+	if err := mapping.Normalize(); err != nil {
+		return nil, err
+	}
+	return mapping, nil
+}
+
+func isCanonicalSeverity(s string) bool {
+	return s == SeverityCritical || s == SeverityWarning || s == SeverityInfo
+}
+
+// Normalize 校验映射目标必须是统一级别，补全默认值并将键转为小写
+func (m *SeverityMapping) Normalize() error {
+	if m.Label == "" {
+		m.Label = "severity"
+	}
+	m.Default = strings.ToLower(strings.TrimSpace(m.Default))
+	if m.Default == "" {
+		m.Default = SeverityWarning
+	}
+	if !isCanonicalSeverity(m.Default) {
+		return fmt.Errorf("无效的默认严重级别: %s", m.Default)
+	}
+
+	normalized := make(map[string]string, len(m.Map))
+	for from, to := range m.Map {
+		to = strings.ToLower(strings.TrimSpace(to))
+		if !isCanonicalSeverity(to) {
+			return fmt.Errorf("严重级别 %q 的映射目标无效: %s", from, to)
+		}
+		normalized[strings.ToLower(strings.TrimSpace(from))] = to
+	}
+	m.Map = normalized
+
+	for i := range m.Rules {
+		m.Rules[i].Severity = strings.ToLower(strings.TrimSpace(m.Rules[i].Severity))
+		if m.Rules[i].AlertName == "" || !isCanonicalSeverity(m.Rules[i].Severity) {
+			return fmt.Errorf("第 %d 条重分类规则无效", i+1)
+		}
+	}
+	return nil
+}
+
+// Classify 根据告警标签计算统一严重级别
+func (m *SeverityMapping) Classify(labels map[string]string) string {
+	if m == nil {
+		m = DefaultSeverityMapping()
+	}
+
+	alertName := labels["alertname"]
+	for _, rule := range m.Rules {
+		if rule.AlertName != alertName {
+			continue
+		}
+		if rule.Namespace != "" && rule.Namespace != labels["namespace"] {
+			continue
+		}
+		return rule.Severity
+	}
+
+	raw := strings.ToLower(strings.TrimSpace(labels[m.Label]))
+	if mapped, ok := m.Map[raw]; ok {
+		return mapped
+	}
+	if isCanonicalSeverity(raw) {
+		return raw
+	}
+	return m.Default
+}
+
+// FilterValue 将过滤参数换算为统一级别，无法识别时原样返回
+func (m *SeverityMapping) FilterValue(value string) string {
+	v := strings.ToLower(strings.TrimSpace(value))
+	if v == "" || isCanonicalSeverity(v) {
+		return v
+	}
+	if m != nil {
+		if mapped, ok := m.Map[v]; ok {
+			return mapped
+		}
+	}
+	return value
+}
+
+// severityRank 排序用：critical > warning > info
+func severityRank(severity string) int {
+	switch severity {
+	case SeverityCritical:
+		return 0
+	case SeverityWarning:
+		return 1
+	default:
+		return 2
+	}
+}
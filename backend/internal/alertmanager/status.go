@@ -0,0 +1,187 @@
+package alertmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Status Alertmanager 运行状态（/api/v2/status）
+type Status struct {
+	Cluster     ClusterStatus     `json:"cluster"`
+	VersionInfo map[string]string `json:"versionInfo"`
+	Config      struct {
+		Original string `json:"original"`
+	} `json:"config"`
+	Uptime time.Time `json:"uptime"`
+}
+
+// ClusterStatus Alertmanager 集群（gossip）状态
+type ClusterStatus struct {
+	Name   string     `json:"name"`
+	Status string     `json:"status"` // ready, settling, disabled
+	Peers  []PeerInfo `json:"peers"`
+}
+
+// PeerInfo 集群节点
+type PeerInfo struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+}
+
+// Route 路由树节点（来自 Alertmanager 配置）
+type Route struct {
+	Receiver       string            `json:"receiver,omitempty"`
+	GroupBy        []string          `json:"group_by,omitempty"`
+	Matchers       []string          `json:"matchers,omitempty"`
+	Match          map[string]string `json:"match,omitempty"`
+	MatchRE        map[string]string `json:"match_re,omitempty"`
+	Continue       bool              `json:"continue,omitempty"`
+	GroupWait      string            `json:"group_wait,omitempty"`
+	GroupInterval  string            `json:"group_interval,omitempty"`
+	RepeatInterval string            `json:"repeat_interval,omitempty"`
+	Routes         []*Route          `json:"routes,omitempty"`
+}
+
+// ReceiverInfo 接收器配置概要
+type ReceiverInfo struct {
+	Name         string   `json:"name"`
+	Integrations []string `json:"integrations"` // webhook, email, slack ...
+	Used         bool     `json:"used"`         // 是否被路由树引用
+	Issues       []string `json:"issues"`
+}
+
+// ConfigOverview 解析后的配置概要
+type ConfigOverview struct {
+	Route     *Route         `json:"route"`
+	Receivers []ReceiverInfo `json:"receivers"`
+	Issues    []string       `json:"issues"`
+}
+
+// GetStatus 获取 Alertmanager 状态
+func (c *Client) GetStatus() (*Status, error) {
+	resp, err := c.get(fmt.Sprintf("%s/api/v2/status", c.baseURL))
+	if err != nil {
+		return nil, fmt.Errorf("获取 Alertmanager 状态失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("获取 Alertmanager 状态失败: %s", string(body))
+	}
+
+	var status Status
+	if err := json.Unmarshal(body, &status); err != nil {
+		return nil, fmt.Errorf("解析 Alertmanager 状态失败: %w", err)
+	}
+
+	return &status, nil
+}
+
+// GetConfigOverview 获取路由树与接收器，并检查常见的配置问题
+func (c *Client) GetConfigOverview() (*ConfigOverview, error) {
+	status, err := c.GetStatus()
+	if err != nil {
+		return nil, err
+	}
+	return ParseConfig(status.Config.Original)
+}
+
+// ParseConfig 解析 Alertmanager 原始 YAML 配置
+func ParseConfig(original string) (*ConfigOverview, error) {
+	var raw struct {
+		Route     *Route                   `json:"route"`
+		Receivers []map[string]interface{} `json:"receivers"`
+	}
+	if err := yaml.Unmarshal([]byte(original), &raw); err != nil {
+		return nil, fmt.Errorf("解析 Alertmanager 配置失败: %w", err)
+	}
+
+	overview := &ConfigOverview{
+		Route:     raw.Route,
+		Receivers: make([]ReceiverInfo, 0, len(raw.Receivers)),
+		Issues:    []string{},
+	}
+
+	// 收集路由树引用的接收器（子路由未指定时继承父路由）
+	referenced := make(map[string]bool)
+	var walk func(route *Route, parent string)
+	walk = func(route *Route, parent string) {
+		if route == nil {
+			return
+		}
+		receiver := route.Receiver
+		if receiver == "" {
+			receiver = parent
+		}
+		if receiver != "" {
+			referenced[receiver] = true
+		}
+		for _, child := range route.Routes {
+			walk(child, receiver)
+		}
+	}
+	walk(raw.Route, "")
+
+	if raw.Route == nil || raw.Route.Receiver == "" {
+		overview.Issues = append(overview.Issues, "根路由未配置默认接收器")
+	}
+
+	defined := make(map[string]bool)
+	for _, r := range raw.Receivers {
+		name, _ := r["name"].(string)
+		info := ReceiverInfo{
+			Name:         name,
+			Integrations: []string{},
+			Used:         referenced[name],
+			Issues:       []string{},
+		}
+		for key, value := range r {
+			if !strings.HasSuffix(key, "_configs") {
+				continue
+			}
+			if items, ok := value.([]interface{}); ok && len(items) > 0 {
+				info.Integrations = append(info.Integrations, strings.TrimSuffix(key, "_configs"))
+			}
+		}
+		sort.Strings(info.Integrations)
+
+		if name == "" {
+			info.Issues = append(info.Issues, "接收器缺少名称")
+		}
+		if defined[name] {
+			info.Issues = append(info.Issues, "接收器名称重复")
+		}
+		if len(info.Integrations) == 0 {
+			info.Issues = append(info.Issues, "未配置任何通知渠道，告警将被丢弃")
+		}
+		if !info.Used {
+			info.Issues = append(info.Issues, "未被任何路由引用")
+		}
+		defined[name] = true
+		overview.Receivers = append(overview.Receivers, info)
+	}
+
+	missing := make([]string, 0)
+	for name := range referenced {
+		if !defined[name] {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	for _, name := range missing {
+		overview.Issues = append(overview.Issues, fmt.Sprintf("路由引用了不存在的接收器: %s", name))
+	}
+
+	return overview, nil
+}
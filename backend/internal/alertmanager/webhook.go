@@ -0,0 +1,47 @@
+package alertmanager
+
+import "time"
+
+// 告警在 Webhook 通知中的状态
+const (
+	WebhookStatusFiring   = "firing"
+	WebhookStatusResolved = "resolved"
+)
+
+// WebhookMessage Alertmanager webhook_configs 推送的通知（version 4）
+type WebhookMessage struct {
+	Version           string            `json:"version"`
+	GroupKey          string            `json:"groupKey"`
+	TruncatedAlerts   int               `json:"truncatedAlerts"`
+	Status            string            `json:"status"`
+	Receiver          string            `json:"receiver"`
+	GroupLabels       map[string]string `json:"groupLabels"`
+	CommonLabels      map[string]string `json:"commonLabels"`
+	CommonAnnotations map[string]string `json:"commonAnnotations"`
+	ExternalURL       string            `json:"externalURL"`
+	Alerts            []WebhookAlert    `json:"alerts"`
+}
+
+// WebhookAlert Webhook 通知中的单条告警
+type WebhookAlert struct {
+	Status       string            `json:"status"` // firing, resolved
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"` // 未恢复时为零值（0001-01-01）
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+	// Severity 经映射/重分类后的统一严重级别，由 ClassifyWebhook 填充
+	Severity string `json:"severity,omitempty"`
+}
+
+// ClassifyWebhook 按客户端的严重级别映射为 Webhook 通知中的告警填充统一严重级别
+func (c *Client) ClassifyWebhook(msg *WebhookMessage) {
+	var mapping *SeverityMapping
+	if c != nil {
+		mapping = c.severity
+	}
+	for i := range msg.Alerts {
+		msg.Alerts[i].Severity = mapping.Classify(msg.Alerts[i].Labels)
+	}
+}
@@ -0,0 +1,285 @@
+package alerts
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/k8s-dashboard/backend/internal/alertmanager"
+	dbutil "github.com/k8s-dashboard/backend/internal/db"
+)
+
+// HistoryEntry 告警历史记录，同一告警的一次触发（cluster + fingerprint + startsAt）对应一条，恢复时更新状态
+type HistoryEntry struct {
+	ID           int64             `json:"id"`
+	Cluster      string            `json:"cluster,omitempty"`
+	Fingerprint  string            `json:"fingerprint"`
+	AlertName    string            `json:"alertname"`
+	Namespace    string            `json:"namespace,omitempty"`
+	Severity     string            `json:"severity"`
+	Status       string            `json:"status"` // firing, resolved
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       *time.Time        `json:"endsAt,omitempty"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+	Receiver     string            `json:"receiver,omitempty"`
+	UpdatedAt    time.Time         `json:"updatedAt"`
+}
+
+// HistoryQuery 告警历史查询条件
+type HistoryQuery struct {
+	Cluster     string
+	Status      string
+	Severity    string
+	AlertName   string
+	Namespace   string
+	Fingerprint string
+	Since       time.Time
+	Until       time.Time
+	Page        int
+	PageSize    int
+}
+
+// initHistorySchema 初始化告警历史表
+func (r *Repository) initHistorySchema() error {
+	var schema string
+	if r.dialect == dbutil.DialectSQLite {
+		schema = `
+		CREATE TABLE IF NOT EXISTS alert_history (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			cluster TEXT NOT NULL DEFAULT '',
+			fingerprint TEXT NOT NULL,
+			alertname TEXT NOT NULL DEFAULT '',
+			namespace TEXT NOT NULL DEFAULT '',
+			severity TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL,
+			labels TEXT NOT NULL,
+			annotations TEXT NOT NULL,
+			starts_at DATETIME NOT NULL,
+			ends_at DATETIME,
+			generator_url TEXT,
+			receiver TEXT,
+			updated_at DATETIME NOT NULL,
+			UNIQUE (cluster, fingerprint, starts_at)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_alert_history_starts ON alert_history(starts_at DESC);
+		CREATE INDEX IF NOT EXISTS idx_alert_history_status ON alert_history(cluster, status);
+		`
+	} else {
+		schema = `
+		CREATE TABLE IF NOT EXISTS alert_history (
+			id BIGSERIAL PRIMARY KEY,
+			cluster VARCHAR(100) NOT NULL DEFAULT '',
+			fingerprint VARCHAR(64) NOT NULL,
+			alertname VARCHAR(255) NOT NULL DEFAULT '',
+			namespace VARCHAR(255) NOT NULL DEFAULT '',
+			severity VARCHAR(20) NOT NULL DEFAULT '',
+			status VARCHAR(20) NOT NULL,
+			labels JSONB NOT NULL,
+			annotations JSONB NOT NULL,
+			starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			ends_at TIMESTAMP WITH TIME ZONE,
+			generator_url TEXT,
+			receiver VARCHAR(255),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			UNIQUE (cluster, fingerprint, starts_at)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_alert_history_starts ON alert_history(starts_at DESC);
+		CREATE INDEX IF NOT EXISTS idx_alert_history_status ON alert_history(cluster, status);
+		`
+	}
+
+	_, err := r.db.Exec(schema)
+	return err
+}
+
+// RecordHistory 写入告警历史，已存在时更新状态与注解；返回状态是否发生变化（新触发或由触发转为恢复），
+// Alertmanager 重复发送的相同通知返回 false
+func (r *Repository) RecordHistory(entry *HistoryEntry) (bool, error) {
+	labelsJSON, err := json.Marshal(entry.Labels)
+	if err != nil {
+		return false, fmt.Errorf("序列化 labels 失败: %w", err)
+	}
+	annotationsJSON, err := json.Marshal(entry.Annotations)
+	if err != nil {
+		return false, fmt.Errorf("序列化 annotations 失败: %w", err)
+	}
+
+	var previous string
+	err = r.db.QueryRow(
+		`SELECT status FROM alert_history WHERE cluster = $1 AND fingerprint = $2 AND starts_at = $3`,
+		entry.Cluster, entry.Fingerprint, entry.StartsAt,
+	).Scan(&previous)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, err
+	}
+
+	err = r.db.QueryRow(`
+		INSERT INTO alert_history (
+			cluster, fingerprint, alertname, namespace, severity, status, labels, annotations,
+			starts_at, ends_at, generator_url, receiver, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (cluster, fingerprint, starts_at) DO UPDATE SET
+			severity = excluded.severity,
+			status = excluded.status,
+			annotations = excluded.annotations,
+			ends_at = excluded.ends_at,
+			receiver = excluded.receiver,
+			updated_at = excluded.updated_at
+		RETURNING id
+	`, entry.Cluster, entry.Fingerprint, entry.AlertName, entry.Namespace, entry.Severity, entry.Status,
+		string(labelsJSON), string(annotationsJSON), entry.StartsAt, entry.EndsAt,
+		entry.GeneratorURL, entry.Receiver, entry.UpdatedAt).Scan(&entry.ID)
+	if err != nil {
+		return false, err
+	}
+	return previous != entry.Status, nil
+}
+
+// ListHistory 分页查询告警历史，按触发时间倒序
+func (r *Repository) ListHistory(params HistoryQuery) ([]*HistoryEntry, int64, error) {
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.PageSize < 1 {
+		params.PageSize = 50
+	}
+	if params.PageSize > 500 {
+		params.PageSize = 500
+	}
+
+	where := "WHERE cluster = $1"
+	args := []interface{}{params.Cluster}
+	argIndex := 2
+	addCond := func(cond string, value interface{}) {
+		where += fmt.Sprintf(" AND "+cond, argIndex)
+		args = append(args, value)
+		argIndex++
+	}
+
+	if params.Status != "" {
+		addCond("status = $%d", params.Status)
+	}
+	if params.Severity != "" {
+		addCond("severity = $%d", params.Severity)
+	}
+	if params.AlertName != "" {
+		addCond("alertname = $%d", params.AlertName)
+	}
+	if params.Namespace != "" {
+		addCond("namespace = $%d", params.Namespace)
+	}
+	if params.Fingerprint != "" {
+		addCond("fingerprint = $%d", params.Fingerprint)
+	}
+	if !params.Since.IsZero() {
+		addCond("starts_at >= $%d", params.Since)
+	}
+	if !params.Until.IsZero() {
+		addCond("starts_at <= $%d", params.Until)
+	}
+
+	var total int64
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM alert_history "+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, cluster, fingerprint, alertname, namespace, severity, status, labels, annotations,
+		       starts_at, ends_at, COALESCE(generator_url, ''), COALESCE(receiver, ''), updated_at
+		FROM alert_history %s
+		ORDER BY starts_at DESC, id DESC
+		LIMIT $%d OFFSET $%d
+	`, where, argIndex, argIndex+1)
+	args = append(args, params.PageSize, (params.Page-1)*params.PageSize)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := []*HistoryEntry{}
+	for rows.Next() {
+		entry, err := scanHistoryEntry(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, total, rows.Err()
+}
+
+func scanHistoryEntry(row rowScanner) (*HistoryEntry, error) {
+	entry := &HistoryEntry{}
+	var labelsJSON, annotationsJSON []byte
+	var endsAt sql.NullTime
+	err := row.Scan(
+		&entry.ID,
+		&entry.Cluster,
+		&entry.Fingerprint,
+		&entry.AlertName,
+		&entry.Namespace,
+		&entry.Severity,
+		&entry.Status,
+		&labelsJSON,
+		&annotationsJSON,
+		&entry.StartsAt,
+		&endsAt,
+		&entry.GeneratorURL,
+		&entry.Receiver,
+		&entry.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if endsAt.Valid {
+		entry.EndsAt = &endsAt.Time
+	}
+	if err := json.Unmarshal(labelsJSON, &entry.Labels); err != nil {
+		return nil, fmt.Errorf("反序列化 labels 失败: %w", err)
+	}
+	if err := json.Unmarshal(annotationsJSON, &entry.Annotations); err != nil {
+		return nil, fmt.Errorf("反序列化 annotations 失败: %w", err)
+	}
+	return entry, nil
+}
+
+// ListFiringHistory 列出集群中仍处于触发状态的告警历史
+func (r *Repository) ListFiringHistory(cluster string) ([]*HistoryEntry, error) {
+	rows, err := r.db.Query(`
+		SELECT id, cluster, fingerprint, alertname, namespace, severity, status, labels, annotations,
+		       starts_at, ends_at, COALESCE(generator_url, ''), COALESCE(receiver, ''), updated_at
+		FROM alert_history
+		WHERE cluster = $1 AND status = $2
+		ORDER BY starts_at DESC, id DESC
+	`, cluster, alertmanager.WebhookStatusFiring)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*HistoryEntry{}
+	for rows.Next() {
+		entry, err := scanHistoryEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// ResolveHistory 将告警历史标记为已恢复
+func (r *Repository) ResolveHistory(id int64, endsAt time.Time) error {
+	_, err := r.db.Exec(
+		`UPDATE alert_history SET status = $1, ends_at = $2, updated_at = $3 WHERE id = $4`,
+		alertmanager.WebhookStatusResolved, endsAt, time.Now(), id,
+	)
+	return err
+}
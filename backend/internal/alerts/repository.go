@@ -13,6 +13,7 @@ import (
 type Acknowledgement struct {
 	ID               int64      `json:"id"`
 	AlertFingerprint string     `json:"alertFingerprint"`
+	Cluster          string     `json:"cluster,omitempty"`
 	AcknowledgedBy   string     `json:"acknowledgedBy"`
 	AcknowledgedAt   time.Time  `json:"acknowledgedAt"`
 	Comment          string     `json:"comment"`
@@ -22,7 +23,8 @@ type Acknowledgement struct {
 // Silence 静默规则
 type Silence struct {
 	ID        int64                    `json:"id"`
-	SilenceID string                   `json:"silenceId"` // Alertmanager ID
+	SilenceID string                   `json:"silenceId"`         // Alertmanager ID
+	Cluster   string                   `json:"cluster,omitempty"` // 静默所在 Alertmanager 对应的集群
 	Matchers  []map[string]interface{} `json:"matchers"`
 	StartsAt  time.Time                `json:"startsAt"`
 	EndsAt    time.Time                `json:"endsAt"`
@@ -120,8 +122,16 @@ func (r *Repository) initSchema() error {
 		`
 	}
 
-	_, err := r.db.Exec(schema)
-	return err
+	if _, err := r.db.Exec(schema); err != nil {
+		return err
+	}
+	// 旧版本的确认与静默表没有 cluster 列，升级时补充（空字符串表示默认集群）
+	for _, table := range []string{"alert_acknowledgements", "alert_silences"} {
+		if err := dbutil.EnsureColumn(r.db, r.dialect, table, "cluster", "TEXT NOT NULL DEFAULT ''"); err != nil {
+			return err
+		}
+	}
+	return r.initHistorySchema()
 }
 
 // ========== 确认告警 ==========
@@ -130,12 +140,13 @@ func (r *Repository) initSchema() error {
 func (r *Repository) AcknowledgeAlert(ack *Acknowledgement) error {
 	query := `
 		INSERT INTO alert_acknowledgements (
-			alert_fingerprint, acknowledged_by, acknowledged_at, comment, expires_at
-		) VALUES ($1, $2, $3, $4, $5)
+			alert_fingerprint, cluster, acknowledged_by, acknowledged_at, comment, expires_at
+		) VALUES ($1, $2, $3, $4, $5, $6)
 	`
 
 	_, err := r.db.Exec(query,
 		ack.AlertFingerprint,
+		ack.Cluster,
 		ack.AcknowledgedBy,
 		ack.AcknowledgedAt,
 		ack.Comment,
@@ -146,44 +157,89 @@ func (r *Repository) AcknowledgeAlert(ack *Acknowledgement) error {
 }
 
 // UnacknowledgeAlert 取消确认告警
-func (r *Repository) UnacknowledgeAlert(fingerprint string) error {
-	query := `DELETE FROM alert_acknowledgements WHERE alert_fingerprint = $1`
-	_, err := r.db.Exec(query, fingerprint)
+func (r *Repository) UnacknowledgeAlert(cluster, fingerprint string) error {
+	query := `DELETE FROM alert_acknowledgements WHERE cluster = $1 AND alert_fingerprint = $2`
+	_, err := r.db.Exec(query, cluster, fingerprint)
 	return err
 }
 
-// GetAcknowledgement 获取告警确认记录
-func (r *Repository) GetAcknowledgement(fingerprint string) (*Acknowledgement, error) {
-	nowExpr := "NOW()"
+// nowExpr 当前时间的 SQL 表达式
+func (r *Repository) nowExpr() string {
 	if r.dialect == dbutil.DialectSQLite {
-		nowExpr = "CURRENT_TIMESTAMP"
+		return "CURRENT_TIMESTAMP"
 	}
+	return "NOW()"
+}
+
+// GetAcknowledgement 获取告警确认记录
+func (r *Repository) GetAcknowledgement(cluster, fingerprint string) (*Acknowledgement, error) {
 	query := fmt.Sprintf(`
-		SELECT id, alert_fingerprint, acknowledged_by, acknowledged_at, comment, expires_at
+		SELECT id, alert_fingerprint, cluster, acknowledged_by, acknowledged_at, comment, expires_at
 		FROM alert_acknowledgements
-		WHERE alert_fingerprint = $1
+		WHERE cluster = $1 AND alert_fingerprint = $2
 		AND (expires_at IS NULL OR expires_at > %s)
 		ORDER BY acknowledged_at DESC
 		LIMIT 1
-	`, nowExpr)
+	`, r.nowExpr())
+
+	ack, err := scanAcknowledgement(r.db.QueryRow(query, cluster, fingerprint))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return ack, nil
+}
 
+// ListActiveAcknowledgements 列出集群内未过期的告警确认，按 fingerprint 索引，同一告警只保留最新一条
+func (r *Repository) ListActiveAcknowledgements(cluster string) (map[string]*Acknowledgement, error) {
+	query := fmt.Sprintf(`
+		SELECT id, alert_fingerprint, cluster, acknowledged_by, acknowledged_at, comment, expires_at
+		FROM alert_acknowledgements
+		WHERE cluster = $1
+		AND (expires_at IS NULL OR expires_at > %s)
+		ORDER BY acknowledged_at DESC
+	`, r.nowExpr())
+
+	rows, err := r.db.Query(query, cluster)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	acks := make(map[string]*Acknowledgement)
+	for rows.Next() {
+		ack, err := scanAcknowledgement(rows)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := acks[ack.AlertFingerprint]; !exists {
+			acks[ack.AlertFingerprint] = ack
+		}
+	}
+	return acks, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAcknowledgement(row rowScanner) (*Acknowledgement, error) {
 	ack := &Acknowledgement{}
-	err := r.db.QueryRow(query, fingerprint).Scan(
+	err := row.Scan(
 		&ack.ID,
 		&ack.AlertFingerprint,
+		&ack.Cluster,
 		&ack.AcknowledgedBy,
 		&ack.AcknowledgedAt,
 		&ack.Comment,
 		&ack.ExpiresAt,
 	)
-
-	if err == sql.ErrNoRows {
-		return nil, nil
-	}
 	if err != nil {
 		return nil, err
 	}
-
 	return ack, nil
 }
 
@@ -199,8 +255,8 @@ func (r *Repository) CreateSilence(silence *Silence) error {
 	if r.dialect == dbutil.DialectSQLite {
 		query := `
 			INSERT INTO alert_silences (
-				silence_id, matchers, starts_at, ends_at, created_by, comment, state
-			) VALUES ($1, $2, $3, $4, $5, $6, $7)
+				silence_id, matchers, starts_at, ends_at, created_by, comment, state, cluster
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		`
 		result, err := r.db.Exec(query,
 			silence.SilenceID,
@@ -210,6 +266,7 @@ func (r *Repository) CreateSilence(silence *Silence) error {
 			silence.CreatedBy,
 			silence.Comment,
 			silence.State,
+			silence.Cluster,
 		)
 		if err != nil {
 			return err
@@ -228,8 +285,8 @@ func (r *Repository) CreateSilence(silence *Silence) error {
 
 	query := `
 		INSERT INTO alert_silences (
-			silence_id, matchers, starts_at, ends_at, created_by, comment, state
-		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			silence_id, matchers, starts_at, ends_at, created_by, comment, state, cluster
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING id, created_at
 	`
 
@@ -241,10 +298,11 @@ func (r *Repository) CreateSilence(silence *Silence) error {
 		silence.CreatedBy,
 		silence.Comment,
 		silence.State,
+		silence.Cluster,
 	).Scan(&silence.ID, &silence.CreatedAt)
 }
 
-// UpdateSilence 更新静默规则
+// UpdateSilence 更新静默规则，Alertmanager 更新静默时可能换发新 ID，silence_id 一并更新
 func (r *Repository) UpdateSilence(silence *Silence) error {
 	matchersJSON, err := json.Marshal(silence.Matchers)
 	if err != nil {
@@ -253,11 +311,12 @@ func (r *Repository) UpdateSilence(silence *Silence) error {
 
 	query := `
 		UPDATE alert_silences
-		SET matchers = $1, starts_at = $2, ends_at = $3, comment = $4, state = $5
-		WHERE id = $6
+		SET silence_id = $1, matchers = $2, starts_at = $3, ends_at = $4, comment = $5, state = $6
+		WHERE id = $7
 	`
 
 	_, err = r.db.Exec(query,
+		silence.SilenceID,
 		matchersJSON,
 		silence.StartsAt,
 		silence.EndsAt,
@@ -279,7 +338,7 @@ func (r *Repository) DeleteSilence(id int64) error {
 // GetSilence 获取单个静默规则
 func (r *Repository) GetSilence(id int64) (*Silence, error) {
 	query := `
-		SELECT id, silence_id, matchers, starts_at, ends_at, created_by, comment, state, created_at
+		SELECT id, silence_id, cluster, matchers, starts_at, ends_at, created_by, comment, state, created_at
 		FROM alert_silences
 		WHERE id = $1
 	`
@@ -290,6 +349,7 @@ func (r *Repository) GetSilence(id int64) (*Silence, error) {
 	err := r.db.QueryRow(query, id).Scan(
 		&silence.ID,
 		&silence.SilenceID,
+		&silence.Cluster,
 		&matchersJSON,
 		&silence.StartsAt,
 		&silence.EndsAt,
@@ -314,16 +374,17 @@ func (r *Repository) GetSilence(id int64) (*Silence, error) {
 	return silence, nil
 }
 
-// ListSilences 列出静默规则
-func (r *Repository) ListSilences(state string) ([]*Silence, error) {
+// ListSilences 列出指定集群的静默规则，state 为空时不按状态过滤
+func (r *Repository) ListSilences(cluster, state string) ([]*Silence, error) {
 	query := `
-		SELECT id, silence_id, matchers, starts_at, ends_at, created_by, comment, state, created_at
+		SELECT id, silence_id, cluster, matchers, starts_at, ends_at, created_by, comment, state, created_at
 		FROM alert_silences
+		WHERE cluster = $1
 	`
 
-	args := []interface{}{}
+	args := []interface{}{cluster}
 	if state != "" {
-		query += ` WHERE state = $1`
+		query += ` AND state = $2`
 		args = append(args, state)
 	}
 
@@ -343,6 +404,7 @@ func (r *Repository) ListSilences(state string) ([]*Silence, error) {
 		err := rows.Scan(
 			&silence.ID,
 			&silence.SilenceID,
+			&silence.Cluster,
 			&matchersJSON,
 			&silence.StartsAt,
 			&silence.EndsAt,
@@ -373,6 +435,36 @@ func (r *Repository) UpdateSilenceState(id int64, state string) error {
 	return err
 }
 
+// PurgeBefore 清理 cutoff 之前已过期的确认记录、已结束的静默规则和不再更新的告警历史
+func (r *Repository) PurgeBefore(cutoff time.Time) (int64, error) {
+	var total int64
+
+	result, err := r.db.Exec(`DELETE FROM alert_acknowledgements WHERE expires_at IS NOT NULL AND expires_at < $1`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	if n, err := result.RowsAffected(); err == nil {
+		total += n
+	}
+
+	result, err = r.db.Exec(`DELETE FROM alert_silences WHERE ends_at < $1`, cutoff)
+	if err != nil {
+		return total, err
+	}
+	if n, err := result.RowsAffected(); err == nil {
+		total += n
+	}
+
+	result, err = r.db.Exec(`DELETE FROM alert_history WHERE updated_at < $1`, cutoff)
+	if err != nil {
+		return total, err
+	}
+	if n, err := result.RowsAffected(); err == nil {
+		total += n
+	}
+	return total, nil
+}
+
 // Close 关闭数据库连接
 func (r *Repository) Close() error {
 	// 连接由上层统一管理，仓库不主动关闭。
@@ -1,7 +1,11 @@
 package alerts
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/k8s-dashboard/backend/internal/alertmanager"
@@ -11,6 +15,8 @@ import (
 type Service struct {
 	repo            *Repository
 	alertmanager    *alertmanager.Client
+	cluster         string
+	historyHandler  HistoryHandler
 }
 
 // NewService 创建告警服务
@@ -23,51 +29,205 @@ func NewService(repo *Repository, alertmanagerClient *alertmanager.Client) *Serv
 
 // ========== 确认告警 ==========
 
-// AcknowledgeAlert 确认告警
-func (s *Service) AcknowledgeAlert(fingerprint, user, comment string, expiresAt *time.Time) error {
+// AcknowledgeAlert 确认告警（表示有人正在处理），已有确认时由当前用户接手；expiresAt 为空表示不过期
+func (s *Service) AcknowledgeAlert(fingerprint, user, comment string, expiresAt *time.Time) (*Acknowledgement, error) {
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, &ValidationError{Field: "expiresAt", Message: "过期时间必须晚于当前时间"}
+	}
+
 	ack := &Acknowledgement{
 		AlertFingerprint: fingerprint,
+		Cluster:          s.cluster,
 		AcknowledgedBy:   user,
 		AcknowledgedAt:   time.Now(),
-		Comment:          comment,
+		Comment:          strings.TrimSpace(comment),
 		ExpiresAt:        expiresAt,
 	}
 
-	return s.repo.AcknowledgeAlert(ack)
+	if err := s.repo.UnacknowledgeAlert(s.cluster, fingerprint); err != nil {
+		return nil, err
+	}
+	if err := s.repo.AcknowledgeAlert(ack); err != nil {
+		return nil, err
+	}
+	return ack, nil
 }
 
 // UnacknowledgeAlert 取消确认告警
 func (s *Service) UnacknowledgeAlert(fingerprint string) error {
-	return s.repo.UnacknowledgeAlert(fingerprint)
+	return s.repo.UnacknowledgeAlert(s.cluster, fingerprint)
 }
 
 // GetAcknowledgement 获取告警确认记录
 func (s *Service) GetAcknowledgement(fingerprint string) (*Acknowledgement, error) {
-	return s.repo.GetAcknowledgement(fingerprint)
+	return s.repo.GetAcknowledgement(s.cluster, fingerprint)
+}
+
+// ActiveAcknowledgements 返回当前集群未过期的告警确认，按 fingerprint 索引，用于在告警列表上标注处理人
+func (s *Service) ActiveAcknowledgements() (map[string]*Acknowledgement, error) {
+	return s.repo.ListActiveAcknowledgements(s.cluster)
 }
 
 // ========== 静默规则 ==========
 
-// CreateSilence 创建静默规则
-func (s *Service) CreateSilence(matchers []map[string]interface{}, startsAt, endsAt time.Time, createdBy, comment string) (*Silence, error) {
-	// 转换 matchers 格式为 Alertmanager 格式
+// 静默规则状态，与 Alertmanager 一致
+const (
+	SilenceStateActive  = "active"
+	SilenceStatePending = "pending"
+	SilenceStateExpired = "expired"
+)
+
+// ErrSilenceNotFound 静默规则不存在
+var ErrSilenceNotFound = errors.New("静默规则不存在")
+
+// ErrAlertmanagerUnavailable 当前集群未配置 Alertmanager
+var ErrAlertmanagerUnavailable = errors.New("Alertmanager 未配置")
+
+// ValidationError 告警确认或静默规则参数校验失败
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// SilenceInput 创建或更新静默规则的参数
+type SilenceInput struct {
+	Matchers []map[string]interface{} `json:"matchers"`
+	StartsAt time.Time                `json:"startsAt"`
+	EndsAt   time.Time                `json:"endsAt"`
+	Comment  string                   `json:"comment"`
+}
+
+// WithAlertmanager 返回绑定到指定集群 Alertmanager 的服务副本，告警确认与静默规则按集群分别存储
+func (s *Service) WithAlertmanager(cluster string, client *alertmanager.Client) *Service {
+	clone := *s
+	clone.cluster = cluster
+	clone.alertmanager = client
+	return &clone
+}
+
+// parseMatchers 校验匹配器并转换为 Alertmanager 格式，isEqual 缺省为 true、isRegex 缺省为 false；
+// 返回补全缺省值后的匹配器用于本地存储
+func parseMatchers(matchers []map[string]interface{}) ([]alertmanager.Matcher, []map[string]interface{}, error) {
+	if len(matchers) == 0 {
+		return nil, nil, &ValidationError{Field: "matchers", Message: "至少需要一个匹配器"}
+	}
+
 	amMatchers := make([]alertmanager.Matcher, 0, len(matchers))
-	for _, m := range matchers {
-		amMatchers = append(amMatchers, alertmanager.Matcher{
-			Name:    m["name"].(string),
-			Value:   m["value"].(string),
-			IsRegex: m["isRegex"].(bool),
-			IsEqual: m["isEqual"].(bool),
+	normalized := make([]map[string]interface{}, 0, len(matchers))
+	selective := false
+	for i, m := range matchers {
+		field := fmt.Sprintf("matchers[%d]", i)
+		name, ok := m["name"].(string)
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, nil, &ValidationError{Field: field + ".name", Message: "标签名不能为空"}
+		}
+		value, ok := m["value"].(string)
+		if !ok {
+			return nil, nil, &ValidationError{Field: field + ".value", Message: "标签值必须是字符串"}
+		}
+		isRegex, isEqual := false, true
+		if raw, exists := m["isRegex"]; exists && raw != nil {
+			if isRegex, ok = raw.(bool); !ok {
+				return nil, nil, &ValidationError{Field: field + ".isRegex", Message: "必须是布尔值"}
+			}
+		}
+		if raw, exists := m["isEqual"]; exists && raw != nil {
+			if isEqual, ok = raw.(bool); !ok {
+				return nil, nil, &ValidationError{Field: field + ".isEqual", Message: "必须是布尔值"}
+			}
+		}
+		// 与 Alertmanager 一致：至少一个匹配器不能匹配空字符串，否则会静默所有告警
+		matchesEmpty := (value == "") == isEqual
+		if isRegex {
+			re, err := regexp.Compile("^(?:" + value + ")$")
+			if err != nil {
+				return nil, nil, &ValidationError{Field: field + ".value", Message: "正则表达式无效: " + err.Error()}
+			}
+			matchesEmpty = re.MatchString("") == isEqual
+		}
+		if !matchesEmpty {
+			selective = true
+		}
+
+		name = strings.TrimSpace(name)
+		amMatchers = append(amMatchers, alertmanager.Matcher{Name: name, Value: value, IsRegex: isRegex, IsEqual: isEqual})
+		normalized = append(normalized, map[string]interface{}{
+			"name":    name,
+			"value":   value,
+			"isRegex": isRegex,
+			"isEqual": isEqual,
 		})
 	}
 
+	if !selective {
+		return nil, nil, &ValidationError{Field: "matchers", Message: "至少需要一个不匹配空值的匹配器"}
+	}
+	return amMatchers, normalized, nil
+}
+
+// validate 校验静默规则参数，startsAt 缺省为当前时间
+func (in *SilenceInput) validate() ([]alertmanager.Matcher, error) {
+	amMatchers, normalized, err := parseMatchers(in.Matchers)
+	if err != nil {
+		return nil, err
+	}
+	in.Matchers = normalized
+
+	in.Comment = strings.TrimSpace(in.Comment)
+	if in.Comment == "" {
+		return nil, &ValidationError{Field: "comment", Message: "备注不能为空"}
+	}
+
+	now := time.Now()
+	if in.StartsAt.IsZero() {
+		in.StartsAt = now
+	}
+	if in.EndsAt.IsZero() {
+		return nil, &ValidationError{Field: "endsAt", Message: "结束时间不能为空"}
+	}
+	if !in.EndsAt.After(in.StartsAt) {
+		return nil, &ValidationError{Field: "endsAt", Message: "结束时间必须晚于开始时间"}
+	}
+	if !in.EndsAt.After(now) {
+		return nil, &ValidationError{Field: "endsAt", Message: "结束时间必须晚于当前时间"}
+	}
+	return amMatchers, nil
+}
+
+// silenceState 按起止时间计算静默状态
+func silenceState(startsAt, endsAt time.Time) string {
+	now := time.Now()
+	switch {
+	case startsAt.After(now):
+		return SilenceStatePending
+	case endsAt.Before(now):
+		return SilenceStateExpired
+	default:
+		return SilenceStateActive
+	}
+}
+
+// CreateSilence 在 Alertmanager 中创建静默规则，并在本地记录创建者
+func (s *Service) CreateSilence(in SilenceInput, createdBy string) (*Silence, error) {
+	if s.alertmanager == nil {
+		return nil, ErrAlertmanagerUnavailable
+	}
+	amMatchers, err := in.validate()
+	if err != nil {
+		return nil, err
+	}
+
 	// 在 Alertmanager 中创建静默
 	amSilence := &alertmanager.Silence{
 		Matchers:  amMatchers,
-		StartsAt:  startsAt,
-		EndsAt:    endsAt,
+		StartsAt:  in.StartsAt,
+		EndsAt:    in.EndsAt,
 		CreatedBy: createdBy,
-		Comment:   comment,
+		Comment:   in.Comment,
 	}
 
 	silenceID, err := s.alertmanager.CreateSilence(amSilence)
@@ -75,24 +235,16 @@ func (s *Service) CreateSilence(matchers []map[string]interface{}, startsAt, end
 		return nil, fmt.Errorf("在 Alertmanager 创建静默失败: %w", err)
 	}
 
-	// 计算状态
-	now := time.Now()
-	state := "active"
-	if startsAt.After(now) {
-		state = "pending"
-	} else if endsAt.Before(now) {
-		state = "expired"
-	}
-
 	// 保存到数据库
 	silence := &Silence{
 		SilenceID: silenceID,
-		Matchers:  matchers,
-		StartsAt:  startsAt,
-		EndsAt:    endsAt,
+		Cluster:   s.cluster,
+		Matchers:  in.Matchers,
+		StartsAt:  in.StartsAt,
+		EndsAt:    in.EndsAt,
 		CreatedBy: createdBy,
-		Comment:   comment,
-		State:     state,
+		Comment:   in.Comment,
+		State:     silenceState(in.StartsAt, in.EndsAt),
 	}
 
 	if err := s.repo.CreateSilence(silence); err != nil {
@@ -104,16 +256,59 @@ func (s *Service) CreateSilence(matchers []map[string]interface{}, startsAt, end
 	return silence, nil
 }
 
-// ListSilences 列出静默规则
+// UpdateSilence 更新静默规则。Alertmanager 无法原地修改时会使旧静默过期并换发新 ID，本地记录随之更新
+func (s *Service) UpdateSilence(id int64, in SilenceInput) (*Silence, error) {
+	if s.alertmanager == nil {
+		return nil, ErrAlertmanagerUnavailable
+	}
+	silence, err := s.getLocalSilence(id)
+	if err != nil {
+		return nil, err
+	}
+	amMatchers, err := in.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	silenceID, err := s.alertmanager.CreateSilence(&alertmanager.Silence{
+		ID:        silence.SilenceID,
+		Matchers:  amMatchers,
+		StartsAt:  in.StartsAt,
+		EndsAt:    in.EndsAt,
+		CreatedBy: silence.CreatedBy,
+		Comment:   in.Comment,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("在 Alertmanager 更新静默失败: %w", err)
+	}
+
+	silence.SilenceID = silenceID
+	silence.Matchers = in.Matchers
+	silence.StartsAt = in.StartsAt
+	silence.EndsAt = in.EndsAt
+	silence.Comment = in.Comment
+	silence.State = silenceState(in.StartsAt, in.EndsAt)
+	if err := s.repo.UpdateSilence(silence); err != nil {
+		return nil, fmt.Errorf("更新数据库中的静默失败: %w", err)
+	}
+	return silence, nil
+}
+
+// ListSilences 列出静默规则，以 Alertmanager 为准同步本地记录的状态与起止时间；
+// 本地有而 Alertmanager 中已不存在的（过期后被回收）标记为 expired
 func (s *Service) ListSilences(state string) ([]*Silence, error) {
+	if s.alertmanager == nil {
+		return nil, ErrAlertmanagerUnavailable
+	}
+
 	// 从 Alertmanager 获取静默规则
 	amSilences, err := s.alertmanager.GetSilences()
 	if err != nil {
 		return nil, fmt.Errorf("从 Alertmanager 获取静默规则失败: %w", err)
 	}
 
-	// 从数据库获取静默规则
-	dbSilences, err := s.repo.ListSilences(state)
+	// 从数据库获取静默规则，同步状态需要全部记录
+	dbSilences, err := s.repo.ListSilences(s.cluster, "")
 	if err != nil {
 		return nil, fmt.Errorf("从数据库获取静默规则失败: %w", err)
 	}
@@ -128,34 +323,16 @@ func (s *Service) ListSilences(state string) ([]*Silence, error) {
 	for _, ams := range amSilences {
 		// 优先使用数据库中的记录（包含创建者等信息）
 		if dbs, ok := silenceMap[ams.ID]; ok {
-			// 更新状态（以 Alertmanager 为准）
-			dbs.State = ams.Status.State
+			delete(silenceMap, ams.ID)
+			s.syncSilence(dbs, &ams)
 			if dbs.State != state && state != "" {
 				continue
 			}
 			result = append(result, dbs)
 		} else {
 			// 如果数据库中没有，直接使用 Alertmanager 的数据
-			matchers := make([]map[string]interface{}, 0, len(ams.Matchers))
-			for _, m := range ams.Matchers {
-				matchers = append(matchers, map[string]interface{}{
-					"name":    m.Name,
-					"value":   m.Value,
-					"isRegex": m.IsRegex,
-					"isEqual": m.IsEqual,
-				})
-			}
-
-			silence := &Silence{
-				SilenceID: ams.ID,
-				Matchers:  matchers,
-				StartsAt:  ams.StartsAt,
-				EndsAt:    ams.EndsAt,
-				CreatedBy: ams.CreatedBy,
-				Comment:   ams.Comment,
-				State:     ams.Status.State,
-			}
-
+			silence := fromAlertmanager(&ams)
+			silence.Cluster = s.cluster
 			if silence.State != state && state != "" {
 				continue
 			}
@@ -163,28 +340,59 @@ func (s *Service) ListSilences(state string) ([]*Silence, error) {
 		}
 	}
 
+	// Alertmanager 中已不存在的本地记录
+	for _, dbs := range dbSilences {
+		if _, missing := silenceMap[dbs.SilenceID]; !missing {
+			continue
+		}
+		s.syncSilence(dbs, nil)
+		if dbs.State != state && state != "" {
+			continue
+		}
+		result = append(result, dbs)
+	}
+
 	return result, nil
 }
 
-// GetSilence 获取单个静默规则
+// GetSilence 获取单个静默规则，Alertmanager 可用时同步其最新状态
 func (s *Service) GetSilence(id int64) (*Silence, error) {
-	return s.repo.GetSilence(id)
+	silence, err := s.getLocalSilence(id)
+	if err != nil {
+		return nil, err
+	}
+	if s.alertmanager == nil {
+		return silence, nil
+	}
+
+	ams, err := s.alertmanager.GetSilence(silence.SilenceID)
+	switch {
+	case err == nil:
+		s.syncSilence(silence, ams)
+	case errors.Is(err, alertmanager.ErrSilenceNotFound):
+		s.syncSilence(silence, nil)
+	default:
+		// Alertmanager 不可达时返回本地记录
+		log.Printf("同步静默规则 %s 状态失败: %v", silence.SilenceID, err)
+	}
+	return silence, nil
 }
 
-// DeleteSilence 删除静默规则
+// DeleteSilence 使 Alertmanager 中的静默立即过期并删除本地记录；已过期或已被回收的静默只删除本地记录
 func (s *Service) DeleteSilence(id int64) error {
-	// 从数据库获取静默规则
-	silence, err := s.repo.GetSilence(id)
-	if err != nil {
-		return fmt.Errorf("获取静默规则失败: %w", err)
+	if s.alertmanager == nil {
+		return ErrAlertmanagerUnavailable
 	}
-	if silence == nil {
-		return fmt.Errorf("静默规则不存在")
+	silence, err := s.GetSilence(id)
+	if err != nil {
+		return err
 	}
 
 	// 从 Alertmanager 删除
-	if err := s.alertmanager.DeleteSilence(silence.SilenceID); err != nil {
-		return fmt.Errorf("从 Alertmanager 删除静默失败: %w", err)
+	if silence.State != SilenceStateExpired {
+		if err := s.alertmanager.DeleteSilence(silence.SilenceID); err != nil && !errors.Is(err, alertmanager.ErrSilenceNotFound) {
+			return fmt.Errorf("从 Alertmanager 删除静默失败: %w", err)
+		}
 	}
 
 	// 从数据库删除
@@ -195,7 +403,200 @@ func (s *Service) DeleteSilence(id int64) error {
 	return nil
 }
 
+// getLocalSilence 读取当前集群的本地静默记录
+func (s *Service) getLocalSilence(id int64) (*Silence, error) {
+	silence, err := s.repo.GetSilence(id)
+	if err != nil {
+		return nil, fmt.Errorf("获取静默规则失败: %w", err)
+	}
+	if silence == nil || silence.Cluster != s.cluster {
+		return nil, ErrSilenceNotFound
+	}
+	return silence, nil
+}
+
+// syncSilence 用 Alertmanager 中的静默更新本地记录，ams 为 nil 表示已不存在；有变化时写回数据库
+func (s *Service) syncSilence(silence *Silence, ams *alertmanager.Silence) {
+	state, startsAt, endsAt := SilenceStateExpired, silence.StartsAt, silence.EndsAt
+	if ams != nil {
+		// Alertmanager 中手动过期的静默结束时间会被改为过期时刻
+		state, startsAt, endsAt = ams.Status.State, ams.StartsAt, ams.EndsAt
+	}
+	if state == silence.State && startsAt.Equal(silence.StartsAt) && endsAt.Equal(silence.EndsAt) {
+		return
+	}
+	silence.State, silence.StartsAt, silence.EndsAt = state, startsAt, endsAt
+	if err := s.repo.UpdateSilence(silence); err != nil {
+		log.Printf("写回静默规则 %s 状态失败: %v", silence.SilenceID, err)
+	}
+}
+
+// fromAlertmanager 将 Alertmanager 中的静默（未经本服务创建）转换为本地结构
+func fromAlertmanager(ams *alertmanager.Silence) *Silence {
+	matchers := make([]map[string]interface{}, 0, len(ams.Matchers))
+	for _, m := range ams.Matchers {
+		matchers = append(matchers, map[string]interface{}{
+			"name":    m.Name,
+			"value":   m.Value,
+			"isRegex": m.IsRegex,
+			"isEqual": m.IsEqual,
+		})
+	}
+
+	return &Silence{
+		SilenceID: ams.ID,
+		Matchers:  matchers,
+		StartsAt:  ams.StartsAt,
+		EndsAt:    ams.EndsAt,
+		CreatedBy: ams.CreatedBy,
+		Comment:   ams.Comment,
+		State:     ams.Status.State,
+	}
+}
+
 // UpdateSilenceState 更新静默规则状态（定时任务调用）
 func (s *Service) UpdateSilenceState(id int64, state string) error {
 	return s.repo.UpdateSilenceState(id, state)
 }
+
+// ========== 告警历史 ==========
+
+// HistoryHandler 告警状态变化（新触发或恢复）时的回调，用于推送实时通知
+type HistoryHandler func(entries []*HistoryEntry)
+
+// SetHistoryHandler 设置告警状态变化回调
+func (s *Service) SetHistoryHandler(handler HistoryHandler) {
+	s.historyHandler = handler
+}
+
+// RecordWebhook 将 Alertmanager Webhook 通知写入当前集群的告警历史，
+// 状态发生变化的告警交给 HistoryHandler 推送，返回写入的条数
+func (s *Service) RecordWebhook(msg *alertmanager.WebhookMessage) (int, error) {
+	now := time.Now()
+	var changed []*HistoryEntry
+	for i, alert := range msg.Alerts {
+		if alert.Fingerprint == "" || alert.StartsAt.IsZero() {
+			return i, &ValidationError{Field: fmt.Sprintf("alerts[%d]", i), Message: "缺少 fingerprint 或 startsAt"}
+		}
+		status := alert.Status
+		if status != alertmanager.WebhookStatusResolved {
+			status = alertmanager.WebhookStatusFiring
+		}
+		entry := &HistoryEntry{
+			Cluster:      s.cluster,
+			Fingerprint:  alert.Fingerprint,
+			AlertName:    alert.Labels["alertname"],
+			Namespace:    alert.Labels["namespace"],
+			Severity:     alert.Severity,
+			Status:       status,
+			Labels:       alert.Labels,
+			Annotations:  alert.Annotations,
+			StartsAt:     alert.StartsAt,
+			GeneratorURL: alert.GeneratorURL,
+			Receiver:     msg.Receiver,
+			UpdatedAt:    now,
+		}
+		if status == alertmanager.WebhookStatusResolved && !alert.EndsAt.IsZero() {
+			endsAt := alert.EndsAt
+			entry.EndsAt = &endsAt
+		}
+		isChanged, err := s.repo.RecordHistory(entry)
+		if err != nil {
+			return i, err
+		}
+		if isChanged {
+			changed = append(changed, entry)
+		}
+	}
+	if len(changed) > 0 && s.historyHandler != nil {
+		s.historyHandler(changed)
+	}
+	return len(msg.Alerts), nil
+}
+
+// SyncActiveAlerts 拉取 Alertmanager 当前的告警写入告警历史，用于未配置 Webhook 的场景：
+// 新出现的活跃告警记为触发，历史中仍在触发但已不在 Alertmanager 中的告警记为恢复；
+// 被静默或抑制的告警保持原状态。状态变化的告警同样交给 HistoryHandler 推送，返回变化的条数
+func (s *Service) SyncActiveAlerts() (int, error) {
+	if s.alertmanager == nil {
+		return 0, errors.New("Alertmanager 未配置")
+	}
+	current, err := s.alertmanager.GetAlerts()
+	if err != nil {
+		return 0, err
+	}
+	firing, err := s.repo.ListFiringHistory(s.cluster)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	present := make(map[string]bool, len(current))
+	var changed []*HistoryEntry
+	for _, alert := range current {
+		if alert.Fingerprint == "" || alert.StartsAt.IsZero() {
+			continue
+		}
+		present[alert.Fingerprint] = true
+		if alert.Status.State != "active" {
+			continue
+		}
+		var receiver string
+		if len(alert.Receivers) > 0 {
+			receiver = alert.Receivers[0].Name
+		}
+		entry := &HistoryEntry{
+			Cluster:      s.cluster,
+			Fingerprint:  alert.Fingerprint,
+			AlertName:    alert.Labels["alertname"],
+			Namespace:    alert.Labels["namespace"],
+			Severity:     alert.Severity,
+			Status:       alertmanager.WebhookStatusFiring,
+			Labels:       alert.Labels,
+			Annotations:  alert.Annotations,
+			StartsAt:     alert.StartsAt,
+			GeneratorURL: alert.GeneratorURL,
+			Receiver:     receiver,
+			UpdatedAt:    now,
+		}
+		isChanged, err := s.repo.RecordHistory(entry)
+		if err != nil {
+			return len(changed), err
+		}
+		if isChanged {
+			changed = append(changed, entry)
+		}
+	}
+
+	for _, entry := range firing {
+		if present[entry.Fingerprint] {
+			continue
+		}
+		if err := s.repo.ResolveHistory(entry.ID, now); err != nil {
+			return len(changed), err
+		}
+		entry.Status = alertmanager.WebhookStatusResolved
+		entry.EndsAt = &now
+		entry.UpdatedAt = now
+		changed = append(changed, entry)
+	}
+
+	if len(changed) > 0 && s.historyHandler != nil {
+		s.historyHandler(changed)
+	}
+	return len(changed), nil
+}
+
+// ListHistory 分页查询当前集群的告警历史
+func (s *Service) ListHistory(query HistoryQuery) ([]*HistoryEntry, int64, error) {
+	query.Cluster = s.cluster
+	return s.repo.ListHistory(query)
+}
+
+// PurgeExpired 按保留期清理过期的告警确认、静默记录与告警历史，retention 为 0 时不清理
+func (s *Service) PurgeExpired(retention time.Duration) (int64, error) {
+	if retention <= 0 {
+		return 0, nil
+	}
+	return s.repo.PurgeBefore(time.Now().Add(-retention))
+}
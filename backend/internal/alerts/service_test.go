@@ -0,0 +1,338 @@
+package alerts
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/k8s-dashboard/backend/internal/alertmanager"
+	dbutil "github.com/k8s-dashboard/backend/internal/db"
+)
+
+// fakeAlertmanager 内存中的 Alertmanager 告警与静默接口
+type fakeAlertmanager struct {
+	mu       sync.Mutex
+	alerts   []alertmanager.Alert
+	silences map[string]*alertmanager.Silence
+	nextID   int
+}
+
+func (f *fakeAlertmanager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v2/alerts":
+		_ = json.NewEncoder(w).Encode(f.alerts)
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v2/silences":
+		items := make([]*alertmanager.Silence, 0, len(f.silences))
+		for _, s := range f.silences {
+			items = append(items, s)
+		}
+		_ = json.NewEncoder(w).Encode(items)
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v2/silences":
+		var s alertmanager.Silence
+		if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if s.ID == "" {
+			f.nextID++
+			s.ID = "sil-" + strconv.Itoa(f.nextID)
+		} else if _, ok := f.silences[s.ID]; !ok {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		s.Status.State = "active"
+		f.silences[s.ID] = &s
+		_ = json.NewEncoder(w).Encode(map[string]string{"silenceID": s.ID})
+	case strings.HasPrefix(r.URL.Path, "/api/v2/silence/"):
+		id := strings.TrimPrefix(r.URL.Path, "/api/v2/silence/")
+		s, ok := f.silences[id]
+		if !ok {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			s.Status.State = "expired"
+			s.EndsAt = time.Now()
+			return
+		}
+		_ = json.NewEncoder(w).Encode(s)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newTestService(t *testing.T) (*Service, *fakeAlertmanager) {
+	t.Helper()
+	conn, dialect, err := dbutil.Open(dbutil.Config{
+		SQLitePath:          filepath.Join(t.TempDir(), "alerts.db"),
+		AllowSQLiteFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	repo, err := NewRepository(conn, dialect)
+	if err != nil {
+		t.Fatalf("NewRepository failed: %v", err)
+	}
+
+	am := &fakeAlertmanager{silences: map[string]*alertmanager.Silence{}}
+	server := httptest.NewServer(am)
+	t.Cleanup(server.Close)
+	return NewService(repo, alertmanager.NewClient(server.URL)), am
+}
+
+func TestParseMatchers(t *testing.T) {
+	tests := []struct {
+		name     string
+		matchers []map[string]interface{}
+		field    string
+	}{
+		{"empty", nil, "matchers"},
+		{"missing name", []map[string]interface{}{{"value": "x"}}, "matchers[0].name"},
+		{"non-string value", []map[string]interface{}{{"name": "a", "value": 1}}, "matchers[0].value"},
+		{"non-bool isRegex", []map[string]interface{}{{"name": "a", "value": "x", "isRegex": "yes"}}, "matchers[0].isRegex"},
+		{"invalid regex", []map[string]interface{}{{"name": "a", "value": "(", "isRegex": true}}, "matchers[0].value"},
+		{"matches everything", []map[string]interface{}{{"name": "a", "value": ".*", "isRegex": true}}, "matchers"},
+		{"defaults", []map[string]interface{}{{"name": "alertname", "value": "Down"}}, ""},
+		{"not equal empty", []map[string]interface{}{{"name": "a", "value": "", "isEqual": false}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amMatchers, normalized, err := parseMatchers(tt.matchers)
+			if tt.field == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if len(amMatchers) != 1 || normalized[0]["isEqual"] != amMatchers[0].IsEqual {
+					t.Fatalf("matchers = %+v, normalized = %+v", amMatchers, normalized)
+				}
+				return
+			}
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tt.field {
+				t.Fatalf("expected validation error on %s, got %v", tt.field, err)
+			}
+		})
+	}
+}
+
+func TestSilenceLifecycle(t *testing.T) {
+	service, am := newTestService(t)
+	prod := service.WithAlertmanager("prod", service.alertmanager)
+
+	created, err := prod.CreateSilence(SilenceInput{
+		Matchers: []map[string]interface{}{{"name": "alertname", "value": "HighCPUUsage"}},
+		EndsAt:   time.Now().Add(time.Hour),
+		Comment:  "maintenance",
+	}, "alice")
+	if err != nil {
+		t.Fatalf("CreateSilence failed: %v", err)
+	}
+	if created.CreatedBy != "alice" || created.Cluster != "prod" || created.State != SilenceStateActive {
+		t.Fatalf("created = %+v", created)
+	}
+
+	// 其它集群看不到该记录
+	if _, err := service.WithAlertmanager("staging", service.alertmanager).GetSilence(created.ID); !errors.Is(err, ErrSilenceNotFound) {
+		t.Fatalf("expected not found from other cluster, got %v", err)
+	}
+
+	updated, err := prod.UpdateSilence(created.ID, SilenceInput{
+		Matchers: []map[string]interface{}{{"name": "alertname", "value": "HighCPUUsage"}},
+		EndsAt:   time.Now().Add(2 * time.Hour),
+		Comment:  "extended",
+	})
+	if err != nil {
+		t.Fatalf("UpdateSilence failed: %v", err)
+	}
+	if updated.Comment != "extended" || updated.CreatedBy != "alice" {
+		t.Fatalf("updated = %+v", updated)
+	}
+
+	// 在 Alertmanager 中直接过期后，列表以 Alertmanager 为准同步状态
+	am.mu.Lock()
+	am.silences[updated.SilenceID].Status.State = SilenceStateExpired
+	am.mu.Unlock()
+	list, err := prod.ListSilences(SilenceStateExpired)
+	if err != nil {
+		t.Fatalf("ListSilences failed: %v", err)
+	}
+	if len(list) != 1 || list[0].CreatedBy != "alice" {
+		t.Fatalf("list = %+v", list)
+	}
+	stored, _ := prod.repo.GetSilence(created.ID)
+	if stored.State != SilenceStateExpired {
+		t.Fatalf("expected synced state to be persisted, got %s", stored.State)
+	}
+
+	// Alertmanager 回收后仍可删除本地记录
+	am.mu.Lock()
+	delete(am.silences, updated.SilenceID)
+	am.mu.Unlock()
+	if err := prod.DeleteSilence(created.ID); err != nil {
+		t.Fatalf("DeleteSilence failed: %v", err)
+	}
+	if _, err := prod.GetSilence(created.ID); !errors.Is(err, ErrSilenceNotFound) {
+		t.Fatalf("expected silence to be deleted, got %v", err)
+	}
+}
+
+func TestAcknowledgementOverlay(t *testing.T) {
+	service, _ := newTestService(t)
+	prod := service.WithAlertmanager("prod", service.alertmanager)
+
+	if _, err := prod.AcknowledgeAlert("fp-1", "alice", "looking", nil); err != nil {
+		t.Fatalf("AcknowledgeAlert failed: %v", err)
+	}
+	// 其他人接手后只保留最新的确认
+	if _, err := prod.AcknowledgeAlert("fp-1", "bob", "taking over", nil); err != nil {
+		t.Fatalf("AcknowledgeAlert failed: %v", err)
+	}
+	past := time.Now().Add(-time.Minute)
+	var validationErr *ValidationError
+	if _, err := prod.AcknowledgeAlert("fp-2", "alice", "", &past); !errors.As(err, &validationErr) {
+		t.Fatalf("expected validation error for past expiresAt, got %v", err)
+	}
+
+	acks, err := prod.ActiveAcknowledgements()
+	if err != nil {
+		t.Fatalf("ActiveAcknowledgements failed: %v", err)
+	}
+	if len(acks) != 1 || acks["fp-1"].AcknowledgedBy != "bob" || acks["fp-1"].Comment != "taking over" {
+		t.Fatalf("acks = %+v", acks)
+	}
+
+	// 确认按集群隔离
+	staging, err := service.WithAlertmanager("staging", service.alertmanager).ActiveAcknowledgements()
+	if err != nil || len(staging) != 0 {
+		t.Fatalf("staging acks = %+v, err = %v", staging, err)
+	}
+
+	if err := prod.UnacknowledgeAlert("fp-1"); err != nil {
+		t.Fatalf("UnacknowledgeAlert failed: %v", err)
+	}
+	if ack, err := prod.GetAcknowledgement("fp-1"); err != nil || ack != nil {
+		t.Fatalf("expected acknowledgement removed, got %+v, err = %v", ack, err)
+	}
+}
+
+func TestRecordWebhook(t *testing.T) {
+	service, _ := newTestService(t)
+	prod := service.WithAlertmanager("prod", nil)
+
+	var notified [][]*HistoryEntry
+	prod.SetHistoryHandler(func(entries []*HistoryEntry) {
+		notified = append(notified, entries)
+	})
+
+	startsAt := time.Now().Add(-10 * time.Minute).UTC()
+	firing := &alertmanager.WebhookMessage{
+		Receiver: "dashboard",
+		Alerts: []alertmanager.WebhookAlert{{
+			Status:      alertmanager.WebhookStatusFiring,
+			Labels:      map[string]string{"alertname": "NodeDown", "namespace": "kube-system"},
+			Annotations: map[string]string{"summary": "node unreachable"},
+			StartsAt:    startsAt,
+			Fingerprint: "fp-1",
+			Severity:    "critical",
+		}},
+	}
+	if n, err := prod.RecordWebhook(firing); err != nil || n != 1 {
+		t.Fatalf("RecordWebhook = %d, %v", n, err)
+	}
+	// Alertmanager 按 repeat_interval 重复发送时不重复通知
+	if _, err := prod.RecordWebhook(firing); err != nil {
+		t.Fatalf("RecordWebhook failed: %v", err)
+	}
+	if len(notified) != 1 || notified[0][0].AlertName != "NodeDown" {
+		t.Fatalf("notified = %+v", notified)
+	}
+
+	resolved := *firing
+	resolved.Alerts = []alertmanager.WebhookAlert{firing.Alerts[0]}
+	resolved.Alerts[0].Status = alertmanager.WebhookStatusResolved
+	resolved.Alerts[0].EndsAt = time.Now().UTC()
+	if _, err := prod.RecordWebhook(&resolved); err != nil {
+		t.Fatalf("RecordWebhook failed: %v", err)
+	}
+	if len(notified) != 2 || notified[1][0].Status != alertmanager.WebhookStatusResolved {
+		t.Fatalf("expected resolved notification, got %+v", notified)
+	}
+
+	items, total, err := prod.ListHistory(HistoryQuery{})
+	if err != nil {
+		t.Fatalf("ListHistory failed: %v", err)
+	}
+	if total != 1 || items[0].Status != alertmanager.WebhookStatusResolved || items[0].EndsAt == nil || items[0].Labels["namespace"] != "kube-system" {
+		t.Fatalf("history = %+v (total %d)", items, total)
+	}
+	if _, total, _ := prod.ListHistory(HistoryQuery{Status: alertmanager.WebhookStatusFiring}); total != 0 {
+		t.Fatalf("expected no firing history, got %d", total)
+	}
+	if _, total, _ := service.WithAlertmanager("staging", nil).ListHistory(HistoryQuery{}); total != 0 {
+		t.Fatalf("expected history scoped to cluster, got %d", total)
+	}
+
+	var validationErr *ValidationError
+	if _, err := prod.RecordWebhook(&alertmanager.WebhookMessage{Alerts: []alertmanager.WebhookAlert{{Status: "firing"}}}); !errors.As(err, &validationErr) {
+		t.Fatalf("expected validation error for alert without fingerprint, got %v", err)
+	}
+}
+
+func TestSyncActiveAlerts(t *testing.T) {
+	service, am := newTestService(t)
+	var notified [][]*HistoryEntry
+	service.SetHistoryHandler(func(entries []*HistoryEntry) {
+		notified = append(notified, entries)
+	})
+
+	startsAt := time.Now().Add(-time.Hour).UTC()
+	newAlert := func(fingerprint, state string) alertmanager.Alert {
+		return alertmanager.Alert{
+			Labels:      map[string]string{"alertname": "PodCrashLooping", "namespace": "payments", "severity": "critical"},
+			StartsAt:    startsAt,
+			Fingerprint: fingerprint,
+			Status:      alertmanager.AlertStatus{State: state},
+			Receivers:   []alertmanager.Receiver{{Name: "team-payments"}},
+		}
+	}
+	am.alerts = []alertmanager.Alert{newAlert("fp-1", "active"), newAlert("fp-2", "suppressed")}
+
+	if n, err := service.SyncActiveAlerts(); err != nil || n != 1 {
+		t.Fatalf("SyncActiveAlerts = %d, %v", n, err)
+	}
+	if len(notified) != 1 || notified[0][0].Fingerprint != "fp-1" || notified[0][0].Receiver != "team-payments" || notified[0][0].Severity != "critical" {
+		t.Fatalf("notified = %+v", notified)
+	}
+	// 重复拉取不重复通知；被静默的告警不会记为恢复
+	if n, err := service.SyncActiveAlerts(); err != nil || n != 0 {
+		t.Fatalf("SyncActiveAlerts = %d, %v", n, err)
+	}
+
+	am.alerts = []alertmanager.Alert{newAlert("fp-2", "suppressed")}
+	if n, err := service.SyncActiveAlerts(); err != nil || n != 1 {
+		t.Fatalf("SyncActiveAlerts = %d, %v", n, err)
+	}
+	if len(notified) != 2 || notified[1][0].Status != alertmanager.WebhookStatusResolved || notified[1][0].EndsAt == nil {
+		t.Fatalf("expected resolved notification, got %+v", notified)
+	}
+	if _, total, _ := service.ListHistory(HistoryQuery{Status: alertmanager.WebhookStatusFiring}); total != 0 {
+		t.Fatalf("expected no firing history, got %d", total)
+	}
+
+	if _, err := service.WithAlertmanager("prod", nil).SyncActiveAlerts(); err == nil {
+		t.Fatal("expected error without Alertmanager")
+	}
+}
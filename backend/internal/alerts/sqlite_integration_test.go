@@ -33,7 +33,7 @@ func TestSQLiteAcknowledgementAndSilenceLifecycle(t *testing.T) {
 		t.Fatalf("AcknowledgeAlert failed: %v", err)
 	}
 
-	gotAck, err := repo.GetAcknowledgement("fp-1")
+	gotAck, err := repo.GetAcknowledgement("", "fp-1")
 	if err != nil {
 		t.Fatalf("GetAcknowledgement failed: %v", err)
 	}
@@ -41,10 +41,10 @@ func TestSQLiteAcknowledgementAndSilenceLifecycle(t *testing.T) {
 		t.Fatalf("expected acknowledgement to exist")
 	}
 
-	if err := repo.UnacknowledgeAlert("fp-1"); err != nil {
+	if err := repo.UnacknowledgeAlert("", "fp-1"); err != nil {
 		t.Fatalf("UnacknowledgeAlert failed: %v", err)
 	}
-	gotAck, err = repo.GetAcknowledgement("fp-1")
+	gotAck, err = repo.GetAcknowledgement("", "fp-1")
 	if err != nil {
 		t.Fatalf("GetAcknowledgement after delete failed: %v", err)
 	}
@@ -87,7 +87,7 @@ func TestSQLiteAcknowledgementAndSilenceLifecycle(t *testing.T) {
 		t.Fatalf("expected silence matchers not empty")
 	}
 
-	list, err := repo.ListSilences("active")
+	list, err := repo.ListSilences("", "active")
 	if err != nil {
 		t.Fatalf("ListSilences failed: %v", err)
 	}
@@ -99,3 +99,49 @@ func TestSQLiteAcknowledgementAndSilenceLifecycle(t *testing.T) {
 		t.Fatalf("DeleteSilence failed: %v", err)
 	}
 }
+
+func TestSQLitePurgeBefore(t *testing.T) {
+	conn, dialect, err := dbutil.Open(dbutil.Config{
+		SQLitePath:          filepath.Join(t.TempDir(), "alerts-purge.db"),
+		AllowSQLiteFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer conn.Close()
+
+	repo, err := NewRepository(conn, dialect)
+	if err != nil {
+		t.Fatalf("NewRepository failed: %v", err)
+	}
+
+	now := time.Now()
+	expired := now.Add(-48 * time.Hour)
+	if err := repo.AcknowledgeAlert(&Acknowledgement{
+		AlertFingerprint: "fp-old",
+		AcknowledgedBy:   "tester",
+		AcknowledgedAt:   expired.Add(-time.Hour),
+		ExpiresAt:        &expired,
+	}); err != nil {
+		t.Fatalf("AcknowledgeAlert failed: %v", err)
+	}
+	if err := repo.AcknowledgeAlert(&Acknowledgement{
+		AlertFingerprint: "fp-open",
+		AcknowledgedBy:   "tester",
+		AcknowledgedAt:   expired,
+	}); err != nil {
+		t.Fatalf("AcknowledgeAlert failed: %v", err)
+	}
+
+	purged, err := repo.PurgeBefore(now.Add(-24 * time.Hour))
+	if err != nil {
+		t.Fatalf("PurgeBefore failed: %v", err)
+	}
+	if purged != 1 {
+		t.Fatalf("expected 1 purged row, got %d", purged)
+	}
+
+	if ack, _ := repo.GetAcknowledgement("", "fp-open"); ack == nil {
+		t.Fatalf("expected acknowledgement without expiry to be kept")
+	}
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
+	"github.com/k8s-dashboard/backend/internal/auth"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxAccessChecks 单次预检的最大条数
+const maxAccessChecks = 50
+
+// AccessCheck 单条权限预检
+type AccessCheck struct {
+	Verb        string `json:"verb"`
+	Group       string `json:"group"`
+	Resource    string `json:"resource"`
+	Subresource string `json:"subresource"`
+	Namespace   string `json:"namespace"`
+	Name        string `json:"name"`
+}
+
+// AccessCheckResult 预检结果：dashboardAllowed 为平台角色与命名空间授权，
+// clusterAllowed 为 Kubernetes RBAC（SelfSubjectAccessReview），二者都通过时 allowed 为 true
+type AccessCheckResult struct {
+	AccessCheck
+	Allowed          bool   `json:"allowed"`
+	DashboardAllowed bool   `json:"dashboardAllowed"`
+	ClusterAllowed   bool   `json:"clusterAllowed"`
+	Reason           string `json:"reason,omitempty"`
+}
+
+// verbMethods 将 Kubernetes 动词映射为平台路由的 HTTP 方法，用于复用按路由的角色规则
+var verbMethods = map[string]string{
+	"get":    http.MethodGet,
+	"list":   http.MethodGet,
+	"watch":  http.MethodGet,
+	"create": http.MethodPost,
+	"update": http.MethodPut,
+	"patch":  http.MethodPut,
+	"delete": http.MethodDelete,
+}
+
+// CanI 批量预检当前用户在目标集群上能否执行操作，供前端禁用无权限的按钮。
+// 请求体 {"checks": [{"verb": "delete", "resource": "pods", "namespace": "default"}]}
+func (h *Handler) CanI(c *gin.Context) {
+	var req struct {
+		Checks []AccessCheck `json:"checks"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求体"})
+		return
+	}
+	if len(req.Checks) == 0 || len(req.Checks) > maxAccessChecks {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("checks 数量必须在 1-%d 之间", maxAccessChecks)})
+		return
+	}
+
+	user := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未认证"})
+		return
+	}
+	scope, err := h.getNamespaceAccessScope(c)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx := requestContext(c)
+	reviews := h.getK8s(c).Clientset.AuthorizationV1().SelfSubjectAccessReviews()
+	results := make([]AccessCheckResult, 0, len(req.Checks))
+	for _, check := range req.Checks {
+		check.Verb = strings.ToLower(strings.TrimSpace(check.Verb))
+		check.Resource = strings.ToLower(strings.TrimSpace(check.Resource))
+		result := AccessCheckResult{AccessCheck: check}
+		if check.Verb == "" || check.Resource == "" {
+			result.Reason = "verb 与 resource 不能为空"
+			results = append(results, result)
+			continue
+		}
+
+		result.DashboardAllowed, result.Reason = dashboardAllows(c, user.Role, scope, check)
+
+		review, err := reviews.Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace:   check.Namespace,
+					Verb:        check.Verb,
+					Group:       check.Group,
+					Resource:    check.Resource,
+					Subresource: check.Subresource,
+					Name:        check.Name,
+				},
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			writeError(c, http.StatusInternalServerError, err)
+			return
+		}
+		result.ClusterAllowed = review.Status.Allowed && !review.Status.Denied
+		if !result.ClusterAllowed && result.Reason == "" {
+			result.Reason = review.Status.Reason
+			if result.Reason == "" {
+				result.Reason = "集群 RBAC 不允许该操作"
+			}
+		}
+
+		result.Allowed = result.DashboardAllowed && result.ClusterAllowed
+		results = append(results, result)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
+
+// dashboardAllows 按平台路由角色规则与命名空间授权判断，返回是否允许及拒绝原因
+func dashboardAllows(c *gin.Context, role string, scope namespaceAccessScope, check AccessCheck) (bool, string) {
+	if check.Namespace != "" && !namespaceAllowed(scope, check.Namespace) {
+		return false, "无权访问该命名空间"
+	}
+
+	method, ok := verbMethods[check.Verb]
+	if !ok {
+		// exec、escalate 等动词没有对应的通用路由，至少需要 operator 与命名空间 write 授权
+		if !middleware.RoleAtLeast(role, "operator") {
+			return false, "平台角色权限不足，需要 operator"
+		}
+		if check.Namespace != "" && !middleware.NamespacePermissionAllowed(c, check.Namespace, auth.NamespacePermWrite) {
+			return false, "命名空间授权不足，需要 " + auth.NamespacePermWrite
+		}
+		return true, ""
+	}
+
+	path := "/api/v1/" + check.Resource
+	if check.Namespace != "" {
+		path = "/api/v1/namespaces/" + check.Namespace + "/" + check.Resource
+	}
+	if check.Name != "" {
+		path += "/" + check.Name
+	}
+	if check.Subresource != "" {
+		path += "/" + check.Subresource
+	}
+	return routeAllows(c, role, method, path, check.Namespace)
+}
+
+// routeAllows 按请求 method+path 对应的路由角色规则与命名空间授权级别判断，返回是否允许及拒绝原因
+func routeAllows(c *gin.Context, role, method, path, namespace string) (bool, string) {
+	if required := middleware.RequiredRole(method, path); !middleware.RoleAtLeast(role, required) {
+		return false, "平台角色权限不足，需要 " + required
+	}
+	if namespace != "" {
+		required := middleware.RequiredNamespacePermission(method, path)
+		if !middleware.NamespacePermissionAllowed(c, namespace, required) {
+			return false, "命名空间授权不足，需要 " + required
+		}
+	}
+	return true, ""
+}
+
+// BodyNamespaceRoute 返回命名空间来自请求体（而非 :ns 路径参数）的写接口，
+// 对目标命名空间做授权检查时使用的等效路由；ok 为 false 表示该路由不属于此类接口
+func BodyNamespaceRoute(method, route, namespace, name string) (string, string, bool) {
+	if method != http.MethodPost {
+		return "", "", false
+	}
+	switch middleware.CanonicalPath(route) {
+	case "/api/v1/nodes/:name/rebalance":
+		return http.MethodPost, "/api/v1/namespaces/" + namespace + "/deployments/" + name + "/restart", true
+	case "/api/v1/runbooks/:name/run":
+		return http.MethodPost, "/api/v1/namespaces/" + namespace + "/jobs", true
+	}
+	return "", "", false
+}
+
+// bodyNamespaceAllows 按 BodyNamespaceRoute 的等效路由检查当前用户对请求体中命名空间的角色与授权级别
+func bodyNamespaceAllows(c *gin.Context, role, route, namespace, name string) (bool, string) {
+	method, path, ok := BodyNamespaceRoute(http.MethodPost, route, namespace, name)
+	if !ok {
+		return false, "未知的授权路由 " + route
+	}
+	return routeAllows(c, role, method, path, namespace)
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
+	"github.com/k8s-dashboard/backend/internal/auth"
+	"github.com/k8s-dashboard/backend/internal/notifications"
+)
+
+// AlertRouteHandler 告警路由规则处理器：operator 及以上可将自己有权限的命名空间的告警路由到通知渠道
+type AlertRouteHandler struct {
+	h       *Handler
+	service *notifications.Service
+}
+
+// NewAlertRouteHandler 创建告警路由处理器
+func NewAlertRouteHandler(h *Handler, service *notifications.Service) *AlertRouteHandler {
+	return &AlertRouteHandler{h: h, service: service}
+}
+
+// writeAlertRouteError 将告警路由服务错误映射为 HTTP 状态码
+func writeAlertRouteError(c *gin.Context, err error) {
+	var validationErr *notifications.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Message, "field": validationErr.Field})
+	case errors.Is(err, notifications.ErrRouteNotFound):
+		writeError(c, http.StatusNotFound, err)
+	default:
+		writeError(c, http.StatusInternalServerError, err)
+	}
+}
+
+func (rh *AlertRouteHandler) available(c *gin.Context) bool {
+	if rh.service == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "通知渠道服务未启用"})
+		return false
+	}
+	return true
+}
+
+// editor 校验当前用户可以修改告警路由，返回当前用户
+func (rh *AlertRouteHandler) editor(c *gin.Context) (*auth.User, bool) {
+	user := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未认证"})
+		return nil, false
+	}
+	if !middleware.RoleAtLeast(user.Role, "operator") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "需要 operator 权限"})
+		return nil, false
+	}
+	return user, true
+}
+
+// checkNamespaces 非管理员只能路由自己有权限的命名空间的告警，且必须指定命名空间
+func (rh *AlertRouteHandler) checkNamespaces(c *gin.Context, match notifications.RouteMatch) bool {
+	scope, err := rh.h.getNamespaceAccessScope(c)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return false
+	}
+	if scope.unrestricted {
+		return true
+	}
+	if len(match.Namespaces) == 0 {
+		c.JSON(http.StatusForbidden, gin.H{"error": "需要指定有权限的命名空间", "field": "match.namespaces"})
+		return false
+	}
+	for _, ns := range match.Namespaces {
+		if !namespaceAllowed(scope, ns) {
+			c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("无权访问命名空间 %s", ns), "field": "match.namespaces"})
+			return false
+		}
+	}
+	return true
+}
+
+// ownedRoute 获取路由并校验当前用户是创建者或管理员
+func (rh *AlertRouteHandler) ownedRoute(c *gin.Context, user *auth.User) (*notifications.Route, bool) {
+	id, ok := routeID(c)
+	if !ok {
+		return nil, false
+	}
+	rt, err := rh.service.GetRoute(id)
+	if err != nil {
+		writeAlertRouteError(c, err)
+		return nil, false
+	}
+	if user.Role != "admin" && rt.CreatedBy != user.Username {
+		c.JSON(http.StatusForbidden, gin.H{"error": "只能修改自己创建的告警路由"})
+		return nil, false
+	}
+	return rt, true
+}
+
+// routeID 解析路径中的路由 ID
+func routeID(c *gin.Context) (int64, bool) {
+	var id int64
+	if _, err := parsePathInt64(c, "id", &id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid route id"})
+		return 0, false
+	}
+	return id, true
+}
+
+// ListRoutes 列出告警路由
+func (rh *AlertRouteHandler) ListRoutes(c *gin.Context) {
+	if !rh.available(c) {
+		return
+	}
+	items, err := rh.service.ListRoutes()
+	if err != nil {
+		writeAlertRouteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "total": len(items)})
+}
+
+// ListRouteChannels 列出可选的通知渠道（仅名称与类型）
+func (rh *AlertRouteHandler) ListRouteChannels(c *gin.Context) {
+	if !rh.available(c) {
+		return
+	}
+	items, err := rh.service.ChannelOptions()
+	if err != nil {
+		writeAlertRouteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "total": len(items)})
+}
+
+// GetRoute 获取告警路由
+func (rh *AlertRouteHandler) GetRoute(c *gin.Context) {
+	if !rh.available(c) {
+		return
+	}
+	id, ok := routeID(c)
+	if !ok {
+		return
+	}
+	rt, err := rh.service.GetRoute(id)
+	if err != nil {
+		writeAlertRouteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rt)
+}
+
+// CreateRoute 创建告警路由
+func (rh *AlertRouteHandler) CreateRoute(c *gin.Context) {
+	if !rh.available(c) {
+		return
+	}
+	user, ok := rh.editor(c)
+	if !ok {
+		return
+	}
+	var req notifications.RouteInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+	if !rh.checkNamespaces(c, req.Match) {
+		return
+	}
+
+	rt, err := rh.service.CreateRoute(req, user.Username)
+	if err != nil {
+		writeAlertRouteError(c, err)
+		return
+	}
+	middleware.SetAuditDetail(c, fmt.Sprintf("route=%s channel=%s", rt.Name, rt.ChannelName))
+	c.JSON(http.StatusCreated, rt)
+}
+
+// UpdateRoute 更新告警路由（创建者或管理员）
+func (rh *AlertRouteHandler) UpdateRoute(c *gin.Context) {
+	if !rh.available(c) {
+		return
+	}
+	user, ok := rh.editor(c)
+	if !ok {
+		return
+	}
+	existing, ok := rh.ownedRoute(c, user)
+	if !ok {
+		return
+	}
+	var req notifications.RouteInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+	if !rh.checkNamespaces(c, req.Match) {
+		return
+	}
+
+	rt, err := rh.service.UpdateRoute(existing.ID, req)
+	if err != nil {
+		writeAlertRouteError(c, err)
+		return
+	}
+	middleware.SetAuditDetail(c, fmt.Sprintf("route=%s channel=%s", rt.Name, rt.ChannelName))
+	c.JSON(http.StatusOK, rt)
+}
+
+// DeleteRoute 删除告警路由（创建者或管理员）
+func (rh *AlertRouteHandler) DeleteRoute(c *gin.Context) {
+	if !rh.available(c) {
+		return
+	}
+	user, ok := rh.editor(c)
+	if !ok {
+		return
+	}
+	existing, ok := rh.ownedRoute(c, user)
+	if !ok {
+		return
+	}
+	if err := rh.service.DeleteRoute(existing.ID); err != nil {
+		writeAlertRouteError(c, err)
+		return
+	}
+	middleware.SetAuditDetail(c, fmt.Sprintf("route=%s", existing.Name))
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
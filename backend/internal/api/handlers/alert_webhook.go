@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/alertmanager"
+	"github.com/k8s-dashboard/backend/internal/alerts"
+)
+
+// AlertWebhookHandler 接收 Alertmanager webhook_configs 推送，Alertmanager API 不可达时告警仍能实时进入 Dashboard
+type AlertWebhookHandler struct {
+	h     *Handler
+	token string
+}
+
+// NewAlertWebhookHandler 创建 Alertmanager Webhook 处理器，token 为空时接口不启用
+func NewAlertWebhookHandler(h *Handler, token string) *AlertWebhookHandler {
+	return &AlertWebhookHandler{h: h, token: token}
+}
+
+// webhookResponse Webhook 接收结果
+type webhookResponse struct {
+	Cluster  string `json:"cluster"`
+	Received int    `json:"received"`
+}
+
+// ReceiveAlertmanagerWebhook 写入告警历史并推送通知。
+// Alertmanager 通过 http_config.authorization 携带 Bearer 令牌，?cluster= 指定告警所属集群（为空时为默认集群）
+func (w *AlertWebhookHandler) ReceiveAlertmanagerWebhook(c *gin.Context) {
+	if w.token == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Alertmanager webhook not enabled"})
+		return
+	}
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(w.token)) != 1 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid webhook token"})
+		return
+	}
+	if w.h.alertService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Alert service not configured"})
+		return
+	}
+
+	cluster := strings.TrimSpace(c.Query("cluster"))
+	if w.h.clusters != nil {
+		name, err := w.h.clusters.ResolveClusterName(cluster)
+		if err != nil {
+			writeError(c, http.StatusBadRequest, err)
+			return
+		}
+		cluster = name
+	}
+
+	var msg alertmanager.WebhookMessage
+	if err := c.ShouldBindJSON(&msg); err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	// 各集群共用同一份严重级别映射，按全局客户端的映射分类即可
+	w.h.alerts.ClassifyWebhook(&msg)
+	received, err := w.h.alertService.WithAlertmanager(cluster, nil).RecordWebhook(&msg)
+	if err != nil {
+		writeAlertServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhookResponse{Cluster: cluster, Received: received})
+}
+
+// GetAlertHistory 查询当前集群的告警历史（来自 Alertmanager Webhook），
+// 支持 status、severity、alertname、namespace、fingerprint 过滤，startTime/endTime（RFC3339）限定触发时间
+func (h *Handler) GetAlertHistory(c *gin.Context) {
+	service := h.alertServiceFor(c)
+	if service == nil {
+		return
+	}
+
+	query := alerts.HistoryQuery{
+		Status:      c.Query("status"),
+		Severity:    strings.ToLower(c.Query("severity")),
+		AlertName:   c.Query("alertname"),
+		Namespace:   c.Query("namespace"),
+		Fingerprint: c.Query("fingerprint"),
+	}
+	query.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	query.PageSize, _ = strconv.Atoi(c.DefaultQuery("pageSize", "50"))
+	for param, target := range map[string]*time.Time{"startTime": &query.Since, "endTime": &query.Until} {
+		v := c.Query(param)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": param + " 必须是 RFC3339 格式"})
+			return
+		}
+		*target = t
+	}
+
+	items, total, err := service.ListHistory(query)
+	if err != nil {
+		writeAlertServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"items": items,
+		"total": total,
+	})
+}
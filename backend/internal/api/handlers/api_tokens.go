@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
+	"github.com/k8s-dashboard/backend/internal/auth"
+)
+
+// currentTokenOwner 返回可管理 API 令牌的当前用户；通过 API 令牌认证的请求不能再签发或管理令牌
+func (h *AuthHandler) currentTokenOwner(c *gin.Context) (*auth.User, bool) {
+	if h.auth == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "认证服务未启用"})
+		return nil, false
+	}
+	user := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未认证"})
+		return nil, false
+	}
+	if middleware.GetAPIToken(c) != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "API 令牌不能管理令牌，请使用登录会话"})
+		return nil, false
+	}
+	return user, true
+}
+
+// CreateAPIToken 签发个人 API 令牌，明文仅在响应中返回一次
+func (h *AuthHandler) CreateAPIToken(c *gin.Context) {
+	user, ok := h.currentTokenOwner(c)
+	if !ok {
+		return
+	}
+
+	var req auth.CreateAPITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求体"})
+		return
+	}
+
+	token, plaintext, err := h.auth.CreateAPIToken(user, &req)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"token": plaintext,
+		"item":  token,
+	})
+}
+
+// ListAPITokens 列出当前用户的 API 令牌（不含明文）
+func (h *AuthHandler) ListAPITokens(c *gin.Context) {
+	user, ok := h.currentTokenOwner(c)
+	if !ok {
+		return
+	}
+
+	tokens, err := h.auth.ListAPITokens(user.ID)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": tokens})
+}
+
+// RevokeAPIToken 撤销当前用户的 API 令牌
+func (h *AuthHandler) RevokeAPIToken(c *gin.Context) {
+	user, ok := h.currentTokenOwner(c)
+	if !ok {
+		return
+	}
+
+	var tokenID int64
+	if _, err := parsePathInt64(c, "id", &tokenID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的令牌ID"})
+		return
+	}
+
+	if err := h.auth.RevokeAPIToken(user.ID, tokenID); err != nil {
+		if errors.Is(err, auth.ErrAPITokenNotFound) {
+			writeError(c, http.StatusNotFound, err)
+			return
+		}
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "令牌已撤销"})
+}
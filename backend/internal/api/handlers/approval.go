@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
+	"github.com/k8s-dashboard/backend/internal/auth"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// approvalActionData 审批请求中 requestData 的约定格式
+type approvalActionData struct {
+	Replicas *int32            `json:"replicas,omitempty"` // scale
+	Images   map[string]string `json:"images,omitempty"`   // update：容器名 -> 新镜像
+	Kinds    []string          `json:"kinds,omitempty"`    // cleanup：清理的资源类别
+}
+
+// approvalTarget 预览所需的工作负载信息
+type approvalTarget struct {
+	kind       string
+	replicas   *int32
+	selector   *metav1.LabelSelector
+	containers []corev1.Container
+}
+
+// CreateApproval 提交审批请求，并根据集群当前状态计算变更预览一并保存
+func (h *Handler) CreateApproval(c *gin.Context) {
+	if h.auth == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "认证服务未启用"})
+		return
+	}
+	user := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未认证"})
+		return
+	}
+
+	var req auth.CreateApprovalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+	if req.Action == "" || req.Resource == "" || req.ResourceName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "action、resource、resourceName 不能为空"})
+		return
+	}
+
+	if !approvalExecutable(req.Action, req.Resource) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("不支持对 %s 提交 %s 审批", req.Resource, req.Action)})
+		return
+	}
+	switch {
+	case req.Resource == "namespaces" && req.Namespace == "":
+		req.Namespace = req.ResourceName
+	case req.Resource == "namespaces" && req.Namespace != req.ResourceName:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "命名空间操作的 namespace 与 resourceName 必须一致"})
+		return
+	case req.Resource != "persistentvolumes" && req.Namespace == "":
+		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace 不能为空"})
+		return
+	}
+	// 按等效的直接操作路由检查角色与命名空间授权，避免借审批绕过权限或读取无权命名空间中的资源
+	method, path := approvalRoute(req.Action, req.Resource, req.Namespace, req.ResourceName)
+	if ok, reason := routeAllows(c, user.Role, method, path, req.Namespace); !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": reason})
+		return
+	}
+
+	var data approvalActionData
+	if req.RequestData != nil {
+		raw, err := json.Marshal(req.RequestData)
+		if err == nil {
+			err = json.Unmarshal(raw, &data)
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "requestData 格式不正确: " + err.Error()})
+			return
+		}
+	}
+	if req.Action == "scale" && (data.Replicas == nil || *data.Replicas < 0) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "scale 操作需要提供非负的 requestData.replicas"})
+		return
+	}
+	if req.Action == "cleanup" {
+		if err := validateCleanupKinds(data.Kinds); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "requestData." + err.Error()})
+			return
+		}
+	}
+
+	approval, status, err := h.submitApproval(c, user, &req, &data)
+	if err != nil {
+		writeError(c, status, err)
+		return
+	}
+	c.JSON(http.StatusCreated, approval)
+}
+
+// submitApproval 计算变更预览并保存审批请求，失败时返回对应的 HTTP 状态码
+func (h *Handler) submitApproval(c *gin.Context, user *auth.User, req *auth.CreateApprovalRequest, data *approvalActionData) (*auth.ApprovalRequest, int, error) {
+	ctx := requestContext(c)
+	target, err := h.getApprovalTarget(ctx, c, req.Resource, req.Namespace, req.ResourceName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, http.StatusNotFound, err
+		}
+		return nil, http.StatusInternalServerError, err
+	}
+	if target != nil {
+		preview, err := h.buildApprovalPreview(ctx, c, req.Action, req.Namespace, target, data)
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		req.Preview = preview
+	}
+	if req.Action == "cleanup" {
+		preview, err := cleanupApprovalPreview(ctx, h.getK8s(c).Clientset, req.Namespace, data.Kinds)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		req.Preview = preview
+	}
+	req.Cluster = middleware.GetClusterName(c)
+
+	approval, err := h.auth.CreateApproval(user.ID, req)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	return approval, http.StatusCreated, nil
+}
+
+// approvalRoute 返回审批动作对应的直接操作路由（method 与 path），用于按路由规则做授权检查
+func approvalRoute(action, resource, namespace, name string) (method, path string) {
+	switch {
+	case resource == "namespaces" && action == "cleanup":
+		return http.MethodPost, "/api/v1/namespaces/" + namespace + "/cleanup"
+	case resource == "namespaces":
+		return http.MethodDelete, "/api/v1/namespaces/" + namespace
+	case namespace == "":
+		path = "/api/v1/" + resource + "/" + name
+	default:
+		path = "/api/v1/namespaces/" + namespace + "/" + resource + "/" + name
+	}
+	if action == "delete" {
+		return http.MethodDelete, path
+	}
+	return http.MethodPost, path + "/" + action
+}
+
+// decodeApprovalData 解析审批记录中保存的 requestData
+func decodeApprovalData(raw string, data *approvalActionData) error {
+	if raw == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(raw), data); err != nil {
+		return fmt.Errorf("requestData 格式不正确: %w", err)
+	}
+	return nil
+}
+
+// getApprovalTarget 读取审批目标资源；不支持预览的资源类型返回 nil
+func (h *Handler) getApprovalTarget(ctx context.Context, c *gin.Context, resource, namespace, name string) (*approvalTarget, error) {
+	clientset := h.getK8s(c).Clientset
+	switch resource {
+	case "deployments":
+		dep, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &approvalTarget{
+			kind:       "Deployment",
+			replicas:   dep.Spec.Replicas,
+			selector:   dep.Spec.Selector,
+			containers: dep.Spec.Template.Spec.Containers,
+		}, nil
+	case "statefulsets":
+		sts, err := clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &approvalTarget{
+			kind:       "StatefulSet",
+			replicas:   sts.Spec.Replicas,
+			selector:   sts.Spec.Selector,
+			containers: sts.Spec.Template.Spec.Containers,
+		}, nil
+	case "daemonsets":
+		ds, err := clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &approvalTarget{
+			kind:       "DaemonSet",
+			selector:   ds.Spec.Selector,
+			containers: ds.Spec.Template.Spec.Containers,
+		}, nil
+	case "pods":
+		pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &approvalTarget{kind: "Pod", containers: pod.Spec.Containers}, nil
+	}
+	return nil, nil
+}
+
+// buildApprovalPreview 计算副本数变化、镜像差异与受影响的 Pod 数
+func (h *Handler) buildApprovalPreview(ctx context.Context, c *gin.Context, action, namespace string, target *approvalTarget, data *approvalActionData) (*auth.ApprovalPreview, error) {
+	preview := &auth.ApprovalPreview{
+		Kind:            target.kind,
+		CurrentReplicas: target.replicas,
+		ComputedAt:      time.Now(),
+	}
+
+	// 当前由该工作负载管理的 Pod 数
+	podCount := 1
+	if target.selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(target.selector)
+		if err != nil {
+			return nil, err
+		}
+		pods, err := h.getK8s(c).Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return nil, err
+		}
+		podCount = 0
+		for _, pod := range pods.Items {
+			if pod.DeletionTimestamp == nil {
+				podCount++
+			}
+		}
+	}
+
+	switch action {
+	case "scale":
+		if target.kind != "Deployment" && target.kind != "StatefulSet" {
+			return nil, fmt.Errorf("%s 不支持扩缩容", target.kind)
+		}
+		current := int32(1)
+		if target.replicas != nil {
+			current = *target.replicas
+		}
+		requested := *data.Replicas
+		preview.CurrentReplicas = &current
+		preview.RequestedReplicas = &requested
+		diff := int(requested - current)
+		switch {
+		case diff > 0:
+			preview.AffectedPods = diff
+			preview.Summary = fmt.Sprintf("副本数 %d → %d，新增 %d 个 Pod", current, requested, diff)
+		case diff < 0:
+			preview.AffectedPods = -diff
+			preview.Summary = fmt.Sprintf("副本数 %d → %d，删除 %d 个 Pod", current, requested, -diff)
+		default:
+			preview.Summary = fmt.Sprintf("副本数保持 %d", current)
+			preview.Warnings = append(preview.Warnings, "请求的副本数与当前一致，批准后不会产生变化")
+		}
+		if requested == 0 && current > 0 {
+			preview.Warnings = append(preview.Warnings, "缩容到 0 将停止全部 Pod，服务不可用")
+		}
+	case "restart":
+		if target.kind == "Pod" {
+			return nil, fmt.Errorf("Pod 不支持重启，请使用 delete")
+		}
+		preview.AffectedPods = podCount
+		preview.Summary = fmt.Sprintf("滚动重启 %d 个 Pod", podCount)
+	case "update":
+		current := make(map[string]string, len(target.containers))
+		for _, container := range target.containers {
+			current[container.Name] = container.Image
+		}
+		for _, container := range target.containers {
+			image, ok := data.Images[container.Name]
+			if !ok {
+				continue
+			}
+			if image == container.Image {
+				preview.Warnings = append(preview.Warnings, fmt.Sprintf("容器 %s 镜像未变化", container.Name))
+				continue
+			}
+			preview.ImageChanges = append(preview.ImageChanges, auth.ImageChange{
+				Container: container.Name,
+				Current:   container.Image,
+				Requested: image,
+			})
+		}
+		for name := range data.Images {
+			if _, ok := current[name]; !ok {
+				return nil, fmt.Errorf("%s 中不存在容器 %s", target.kind, name)
+			}
+		}
+		if len(preview.ImageChanges) > 0 {
+			preview.AffectedPods = podCount
+		}
+		preview.Summary = fmt.Sprintf("更新 %d 个容器镜像，影响 %d 个 Pod", len(preview.ImageChanges), preview.AffectedPods)
+	case "delete":
+		preview.AffectedPods = podCount
+		preview.Summary = fmt.Sprintf("删除 %s，影响 %d 个 Pod", target.kind, podCount)
+		preview.Warnings = append(preview.Warnings, "删除操作不可恢复")
+	default:
+		preview.AffectedPods = podCount
+		preview.Summary = fmt.Sprintf("%s %s", action, target.kind)
+	}
+	return preview, nil
+}
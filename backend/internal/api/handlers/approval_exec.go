@@ -0,0 +1,421 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
+	"github.com/k8s-dashboard/backend/internal/auth"
+	"github.com/k8s-dashboard/backend/internal/k8s"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ApprovalOperation 写操作路由对应的审批动作
+type ApprovalOperation struct {
+	Action   string // delete, scale, restart, cleanup
+	Resource string // deployments, pods, namespaces, ...
+}
+
+// approvalDeletable 批准后可自动执行删除的资源
+var approvalDeletable = map[string]bool{
+	"pods":                   true,
+	"deployments":            true,
+	"statefulsets":           true,
+	"daemonsets":             true,
+	"jobs":                   true,
+	"cronjobs":               true,
+	"services":               true,
+	"ingresses":              true,
+	"configmaps":             true,
+	"secrets":                true,
+	"persistentvolumeclaims": true,
+	"persistentvolumes":      true,
+	"namespaces":             true,
+}
+
+// approvalScalable 批准后可自动执行扩缩容的资源
+var approvalScalable = map[string]bool{"deployments": true, "statefulsets": true}
+
+// approvalRestartable 批准后可自动执行滚动重启的资源
+var approvalRestartable = map[string]bool{"deployments": true, "statefulsets": true, "daemonsets": true}
+
+// ApprovalOperationForRoute 根据路由模板识别需要经过审批规则检查的写操作，
+// 返回 false 表示该路由不受审批约束
+func ApprovalOperationForRoute(method, route string) (ApprovalOperation, bool) {
+	segments := strings.Split(strings.TrimPrefix(middleware.CanonicalPath(route), "/api/v1/"), "/")
+	switch {
+	case method == http.MethodDelete && len(segments) == 2 && (segments[0] == "namespaces" || segments[0] == "namespace") && segments[1] == ":ns":
+		return ApprovalOperation{Action: "delete", Resource: "namespaces"}, true
+	case method == http.MethodDelete && len(segments) == 2 && segments[1] == ":name" && approvalDeletable[segments[0]]:
+		return ApprovalOperation{Action: "delete", Resource: segments[0]}, true
+	case method == http.MethodPost && len(segments) == 3 && (segments[0] == "namespaces" || segments[0] == "namespace") && segments[1] == ":ns" && segments[2] == "cleanup":
+		return ApprovalOperation{Action: "cleanup", Resource: "namespaces"}, true
+	case len(segments) < 4 || segments[0] != "namespaces" || segments[1] != ":ns" || segments[3] != ":name":
+		return ApprovalOperation{}, false
+	case method == http.MethodDelete && len(segments) == 4 && approvalDeletable[segments[2]]:
+		return ApprovalOperation{Action: "delete", Resource: segments[2]}, true
+	case method == http.MethodPost && len(segments) == 5 && segments[4] == "scale" && approvalScalable[segments[2]]:
+		return ApprovalOperation{Action: "scale", Resource: segments[2]}, true
+	case method == http.MethodPost && len(segments) == 5 && segments[4] == "restart" && approvalRestartable[segments[2]]:
+		return ApprovalOperation{Action: "restart", Resource: segments[2]}, true
+	}
+	return ApprovalOperation{}, false
+}
+
+// approvalExecutable 判断审批请求批准后能否由服务端自动执行
+func approvalExecutable(action, resource string) bool {
+	switch action {
+	case "delete":
+		return approvalDeletable[resource]
+	case "scale":
+		return approvalScalable[resource]
+	case "restart":
+		return approvalRestartable[resource]
+	case "cleanup":
+		return resource == "namespaces"
+	}
+	return false
+}
+
+// ApprovalGate 拦截命中审批规则的写操作：不直接执行，而是保存为待审批请求并返回 202，
+// 审批通过后由审批人的批准操作执行。需放在认证、授权与 RequestScope 之后
+func (h *Handler) ApprovalGate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if h.auth == nil {
+			c.Next()
+			return
+		}
+		op, ok := ApprovalOperationForRoute(c.Request.Method, c.FullPath())
+		user := middleware.GetCurrentUser(c)
+		if !ok || user == nil {
+			c.Next()
+			return
+		}
+
+		namespace, name := c.Param("ns"), c.Param("name")
+		if op.Resource == "namespaces" {
+			name = namespace
+		}
+		needs, err := h.auth.NeedsApproval(user.Role, op.Action, op.Resource, namespace)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "检查审批规则失败: " + err.Error()})
+			c.Abort()
+			return
+		}
+		if !needs {
+			c.Next()
+			return
+		}
+
+		var data approvalActionData
+		if op.Action == "scale" {
+			var body struct {
+				Replicas *int32 `json:"replicas"`
+			}
+			if err := c.ShouldBindJSON(&body); err != nil || body.Replicas == nil || *body.Replicas < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "scale 操作需要提供非负的 replicas"})
+				c.Abort()
+				return
+			}
+			data.Replicas = body.Replicas
+		}
+		if op.Action == "cleanup" {
+			// dry-run 只读预览，不需要审批；读取后恢复请求体供后续 handler 绑定
+			body, err := io.ReadAll(c.Request.Body)
+			if err != nil {
+				writeError(c, http.StatusBadRequest, err)
+				c.Abort()
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewBuffer(body))
+			var req CleanupRequest
+			if err := json.Unmarshal(body, &req); err != nil {
+				writeError(c, http.StatusBadRequest, err)
+				c.Abort()
+				return
+			}
+			if req.DryRun {
+				c.Next()
+				return
+			}
+			if err := validateCleanupKinds(req.Kinds); err != nil {
+				writeError(c, http.StatusBadRequest, err)
+				c.Abort()
+				return
+			}
+			data.Kinds = req.Kinds
+		}
+
+		req := &auth.CreateApprovalRequest{
+			Action:       op.Action,
+			Resource:     op.Resource,
+			ResourceName: name,
+			Namespace:    namespace,
+			Reason:       c.GetHeader("X-Approval-Reason"),
+			RequestData:  data,
+		}
+		approval, status, err := h.submitApproval(c, user, req, &data)
+		if err != nil {
+			writeError(c, status, err)
+			c.Abort()
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{
+			"message":          "该操作需要审批，已提交审批请求",
+			"approvalRequired": true,
+			"approval":         approval,
+		})
+		c.Abort()
+	}
+}
+
+// ApproveRequest 批准审批请求，并在请求记录的集群上执行保存的操作，执行结果写回审批记录
+func (h *Handler) ApproveRequest(c *gin.Context) {
+	if h.auth == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "认证服务未启用"})
+		return
+	}
+
+	user := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未认证"})
+		return
+	}
+
+	var approvalID int64
+	if _, err := parsePathInt64(c, "id", &approvalID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的审批ID"})
+		return
+	}
+
+	var req ApprovalActionRequest
+	c.ShouldBindJSON(&req)
+
+	approved, err := h.auth.ApproveRequest(approvalID, user.ID, req.Comment)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	approval, err := h.auth.GetApprovalByID(approvalID)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if !approved {
+		c.JSON(http.StatusOK, gin.H{
+			"message":  fmt.Sprintf("已记录批准（%d/%d），等待其他审批人批准", len(approval.Votes), approval.RequiredApprovals),
+			"approval": approval,
+		})
+		return
+	}
+	if !approvalExecutable(approval.Action, approval.Resource) {
+		c.JSON(http.StatusOK, gin.H{"message": "已批准，该操作需由申请人手动执行", "approval": approval})
+		return
+	}
+
+	status := auth.ApprovalExecutionSucceeded
+	ctx, cancel := operationContext(c)
+	defer cancel()
+	result, execErr := h.executeApproval(ctx, approval)
+	if execErr != nil {
+		status, result = auth.ApprovalExecutionFailed, execErr.Error()
+	}
+	if err := h.auth.RecordApprovalExecution(approvalID, status, result); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "记录执行结果失败: " + err.Error()})
+		return
+	}
+
+	approval, _ = h.auth.GetApprovalByID(approvalID)
+	message := "已批准并执行"
+	if status == auth.ApprovalExecutionFailed {
+		message = "已批准，但执行失败"
+	}
+	c.JSON(http.StatusOK, gin.H{"message": message, "approval": approval})
+}
+
+// approvalClient 返回审批请求目标集群的客户端，使用 Dashboard 自身的集群凭据执行；
+// 执行前需经 requesterAllows 确认申请人仍有权执行该操作
+func (h *Handler) approvalClient(cluster string) (*k8s.Client, error) {
+	if cluster == "" || h.clusters == nil {
+		if h.k8s == nil {
+			return nil, fmt.Errorf("Kubernetes 客户端未初始化")
+		}
+		return h.k8s, nil
+	}
+	return h.clusters.GetClient(cluster)
+}
+
+// executeApproval 执行已批准的操作，返回执行结果摘要
+func (h *Handler) executeApproval(ctx context.Context, approval *auth.ApprovalRequest) (string, error) {
+	ok, reason, err := h.requesterAllows(approval)
+	if err != nil {
+		return "", fmt.Errorf("检查申请人权限失败: %w", err)
+	}
+	if !ok {
+		return "", fmt.Errorf("申请人已无权执行该操作: %s", reason)
+	}
+	client, err := h.approvalClient(approval.Cluster)
+	if err != nil {
+		return "", err
+	}
+	var data approvalActionData
+	if err := decodeApprovalData(approval.RequestData, &data); err != nil {
+		return "", err
+	}
+	return executeOperation(ctx, client.Clientset, approval.Action, approval.Resource, approval.Namespace, approval.ResourceName, data)
+}
+
+// requesterAllows 以申请人（而非审批人）当前的角色与命名空间授权重新检查审批操作，
+// 申请人在提交后被停用、降级或收回命名空间授权时不再执行
+func (h *Handler) requesterAllows(approval *auth.ApprovalRequest) (bool, string, error) {
+	requester, err := h.auth.GetUserByID(approval.UserID)
+	if errors.Is(err, auth.ErrUserNotFound) {
+		return false, "申请人不存在", nil
+	}
+	if err != nil {
+		return false, "", err
+	}
+	if !requester.Enabled {
+		return false, "申请人已被禁用", nil
+	}
+
+	method, path := approvalRoute(approval.Action, approval.Resource, approval.Namespace, approval.ResourceName)
+	if required := middleware.RequiredRole(method, path); !middleware.RoleAtLeast(requester.Role, required) {
+		return false, "平台角色权限不足，需要 " + required, nil
+	}
+	if approval.Namespace == "" || requester.Role == "admin" || requester.AllNamespaces {
+		return true, "", nil
+	}
+	grants, err := h.auth.NamespaceGrants(requester)
+	if err != nil {
+		return false, "", err
+	}
+	required := middleware.RequiredNamespacePermission(method, path)
+	for _, grant := range grants {
+		if grant.Namespace == approval.Namespace && auth.NamespacePermissionAtLeast(grant.Permissions, required) {
+			return true, "", nil
+		}
+	}
+	return false, "命名空间授权不足，需要 " + required, nil
+}
+
+// executeOperation 执行删除、扩缩容、滚动重启或命名空间清理，返回执行结果摘要；供审批执行与批量操作共用
+func executeOperation(ctx context.Context, clientset kubernetes.Interface, action, resource, ns, name string, data approvalActionData) (string, error) {
+	var err error
+	switch action {
+	case "delete":
+		opts := metav1.DeleteOptions{}
+		switch resource {
+		case "pods":
+			err = clientset.CoreV1().Pods(ns).Delete(ctx, name, opts)
+		case "deployments":
+			err = clientset.AppsV1().Deployments(ns).Delete(ctx, name, opts)
+		case "statefulsets":
+			err = clientset.AppsV1().StatefulSets(ns).Delete(ctx, name, opts)
+		case "daemonsets":
+			err = clientset.AppsV1().DaemonSets(ns).Delete(ctx, name, opts)
+		case "jobs":
+			propagation := metav1.DeletePropagationBackground
+			err = clientset.BatchV1().Jobs(ns).Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+		case "cronjobs":
+			err = clientset.BatchV1().CronJobs(ns).Delete(ctx, name, opts)
+		case "services":
+			err = clientset.CoreV1().Services(ns).Delete(ctx, name, opts)
+		case "ingresses":
+			err = clientset.NetworkingV1().Ingresses(ns).Delete(ctx, name, opts)
+		case "configmaps":
+			err = clientset.CoreV1().ConfigMaps(ns).Delete(ctx, name, opts)
+		case "secrets":
+			err = clientset.CoreV1().Secrets(ns).Delete(ctx, name, opts)
+		case "persistentvolumeclaims":
+			err = clientset.CoreV1().PersistentVolumeClaims(ns).Delete(ctx, name, opts)
+		case "persistentvolumes":
+			err = clientset.CoreV1().PersistentVolumes().Delete(ctx, name, opts)
+		case "namespaces":
+			err = clientset.CoreV1().Namespaces().Delete(ctx, name, opts)
+		default:
+			return "", fmt.Errorf("不支持自动删除 %s", resource)
+		}
+		if apierrors.IsNotFound(err) {
+			return "", fmt.Errorf("%s %s 已不存在", resource, name)
+		}
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("已删除 %s %s", resource, name), nil
+
+	case "scale":
+		if data.Replicas == nil || *data.Replicas < 0 {
+			return "", fmt.Errorf("缺少有效的副本数")
+		}
+		switch resource {
+		case "deployments":
+			scale, err := clientset.AppsV1().Deployments(ns).GetScale(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return "", err
+			}
+			scale.Spec.Replicas = *data.Replicas
+			_, err = clientset.AppsV1().Deployments(ns).UpdateScale(ctx, name, scale, metav1.UpdateOptions{})
+			if err != nil {
+				return "", err
+			}
+		case "statefulsets":
+			scale, err := clientset.AppsV1().StatefulSets(ns).GetScale(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return "", err
+			}
+			scale.Spec.Replicas = *data.Replicas
+			_, err = clientset.AppsV1().StatefulSets(ns).UpdateScale(ctx, name, scale, metav1.UpdateOptions{})
+			if err != nil {
+				return "", err
+			}
+		default:
+			return "", fmt.Errorf("不支持自动扩缩容 %s", resource)
+		}
+		return fmt.Sprintf("已将 %s %s 副本数调整为 %d", resource, name, *data.Replicas), nil
+
+	case "restart":
+		patch := []byte(fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{"kubectl.kubernetes.io/restartedAt":%q}}}}}`, time.Now().Format(time.RFC3339)))
+		switch resource {
+		case "deployments":
+			_, err = clientset.AppsV1().Deployments(ns).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+		case "statefulsets":
+			_, err = clientset.AppsV1().StatefulSets(ns).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+		case "daemonsets":
+			_, err = clientset.AppsV1().DaemonSets(ns).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+		default:
+			return "", fmt.Errorf("不支持自动重启 %s", resource)
+		}
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("已触发 %s %s 滚动重启", resource, name), nil
+
+	case "cleanup":
+		if err := validateCleanupKinds(data.Kinds); err != nil {
+			return "", err
+		}
+		// 清理范围在执行时重新计算，审批期间新结束的资源也会被清理
+		result, err := cleanupNamespace(ctx, clientset, ns, data.Kinds, false)
+		if err != nil {
+			return "", err
+		}
+		if result.Failed > 0 {
+			return "", fmt.Errorf("已清理 %s 中 %d 个资源，%d 个删除失败", ns, result.Deleted, result.Failed)
+		}
+		return fmt.Sprintf("已清理 %s 中 %d 个资源", ns, result.Deleted), nil
+	}
+	return "", fmt.Errorf("不支持自动执行操作 %s", action)
+}
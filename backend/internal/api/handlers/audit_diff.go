@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
+	"github.com/k8s-dashboard/backend/internal/audit"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// recordAuditDiff 记录更新前后对象的差异到审计日志。before 为 nil（更新前获取失败）时不记录
+func recordAuditDiff(c *gin.Context, before, after interface{}) {
+	diff, err := audit.Diff(before, after)
+	if err != nil {
+		log.Printf("Warning: 计算审计差异失败: %v", err)
+		return
+	}
+	if diff != "" {
+		middleware.SetAuditDiff(c, diff)
+	}
+}
+
+// auditBefore 更新前获取的对象，获取失败时返回 nil（client-go 出错时仍会返回空对象）
+func auditBefore[T any](obj *T, err error) *T {
+	if err != nil {
+		return nil
+	}
+	return obj
+}
+
+// secretAuditView 审计差异中使用的 Secret 视图：值替换为摘要，只能看出哪些键发生了变化
+type secretAuditView struct {
+	*corev1.Secret
+	Data map[string]string `json:"data,omitempty"`
+}
+
+func newSecretAuditView(secret *corev1.Secret) interface{} {
+	if secret == nil {
+		return nil
+	}
+	view := secretAuditView{Secret: secret.DeepCopy(), Data: make(map[string]string, len(secret.Data))}
+	view.Secret.StringData = nil
+	for key, value := range secret.Data {
+		sum := sha256.Sum256(value)
+		view.Data[key] = "sha256:" + hex.EncodeToString(sum[:8])
+	}
+	return view
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
+	"github.com/k8s-dashboard/backend/internal/audit"
+)
+
+// auditFilterParams 解析审计日志过滤条件（不含分页），时间范围格式无效时返回错误，已解析的条件仍然有效
+func auditFilterParams(c *gin.Context) (audit.ListParams, error) {
+	params := audit.ListParams{
+		User:      c.Query("user"),
+		Action:    c.Query("action"),
+		Resource:  c.Query("resource"),
+		Namespace: c.Query("namespace"),
+		Cluster:   c.Query("cluster"),
+	}
+
+	var err error
+	if startTime := c.Query("startTime"); startTime != "" {
+		t, parseErr := time.Parse(time.RFC3339, startTime)
+		if parseErr != nil {
+			err = fmt.Errorf("startTime 需为 RFC3339 格式")
+		}
+		params.StartTime = t
+	}
+	if endTime := c.Query("endTime"); endTime != "" {
+		t, parseErr := time.Parse(time.RFC3339, endTime)
+		if parseErr != nil {
+			err = fmt.Errorf("endTime 需为 RFC3339 格式")
+		}
+		params.EndTime = t
+	}
+	return params, err
+}
+
+// auditExportContentType 导出格式对应的 Content-Type
+func auditExportContentType(format string) string {
+	if format == audit.ExportFormatNDJSON {
+		return "application/x-ndjson"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// ExportAuditLogs 按与列表相同的过滤条件导出审计日志（CSV / NDJSON）。
+// 数据量不超过 audit.StreamExportRows 时直接流式返回，否则（或 async=true）创建后台导出任务并返回 202
+func (h *Handler) ExportAuditLogs(c *gin.Context) {
+	if h.audit == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "审计日志功能未启用"})
+		return
+	}
+
+	params, err := auditFilterParams(c)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+	format := c.DefaultQuery("format", audit.ExportFormatCSV)
+	if !audit.ValidExportFormat(format) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format 仅支持 csv 或 ndjson"})
+		return
+	}
+	limit := audit.MaxExportRows
+	if raw := c.Query("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > audit.MaxExportRows {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit 需在 1-%d 之间", audit.MaxExportRows)})
+			return
+		}
+	}
+
+	total, err := h.audit.Count(params)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	rows := total
+	if rows > int64(limit) {
+		rows = int64(limit)
+	}
+
+	if c.Query("async") == "true" || rows > audit.StreamExportRows {
+		user := "anonymous"
+		if u := middleware.GetCurrentUser(c); u != nil {
+			user = u.Username
+		}
+		export, err := h.audit.StartExport(user, params, format, limit)
+		if err != nil {
+			writeError(c, http.StatusInternalServerError, err)
+			return
+		}
+		middleware.SetAuditDetail(c, fmt.Sprintf("export #%d %s rows=%d", export.ID, format, rows))
+		c.JSON(http.StatusAccepted, gin.H{
+			"message": fmt.Sprintf("共 %d 条记录，已转为后台导出，完成后通过 /api/v1/audit/exports/%d/download 下载", rows, export.ID),
+			"export":  export,
+		})
+		return
+	}
+
+	middleware.SetAuditDetail(c, fmt.Sprintf("export %s rows=%d", format, rows))
+	filename := fmt.Sprintf("audit-%s.%s", time.Now().Format("20060102-150405"), format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Header("Content-Type", auditExportContentType(format))
+	c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+	if total > int64(limit) {
+		c.Header("X-Export-Truncated", "true")
+	}
+	c.Status(http.StatusOK)
+
+	// 响应头已发送，中途失败只能截断输出
+	if _, err := h.audit.Export(c.Request.Context(), params, format, limit, c.Writer); err != nil {
+		log.Printf("导出审计日志失败: %v", err)
+	}
+}
+
+// ListAuditExports 列出最近的后台导出任务
+func (h *Handler) ListAuditExports(c *gin.Context) {
+	if h.audit == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "审计日志功能未启用"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	items, err := h.audit.ListExports(limit)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// GetAuditExport 查询后台导出任务状态
+func (h *Handler) GetAuditExport(c *gin.Context) {
+	if h.audit == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "审计日志功能未启用"})
+		return
+	}
+
+	var id int64
+	if _, err := parsePathInt64(c, "id", &id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的导出任务ID"})
+		return
+	}
+
+	export, err := h.audit.GetExport(id)
+	if err != nil {
+		if errors.Is(err, audit.ErrExportNotFound) {
+			writeError(c, http.StatusNotFound, err)
+			return
+		}
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, export)
+}
+
+// DownloadAuditExport 下载已完成的后台导出文件（gzip 压缩）
+func (h *Handler) DownloadAuditExport(c *gin.Context) {
+	if h.audit == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "审计日志功能未启用"})
+		return
+	}
+
+	var id int64
+	if _, err := parsePathInt64(c, "id", &id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的导出任务ID"})
+		return
+	}
+
+	export, data, err := h.audit.GetExportData(id)
+	if err != nil {
+		if errors.Is(err, audit.ErrExportNotFound) {
+			writeError(c, http.StatusNotFound, err)
+			return
+		}
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if export.Status != audit.ExportStatusCompleted {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("导出状态为 %s，暂无可下载的文件", export.Status)})
+		return
+	}
+	middleware.SetAuditDetail(c, fmt.Sprintf("download export #%d rows=%d", export.ID, export.Rows))
+
+	filename := fmt.Sprintf("audit-export-%d.%s.gz", export.ID, export.Format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "application/gzip", data)
+}
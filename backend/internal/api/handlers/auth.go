@@ -1,8 +1,15 @@
 package handlers
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/k8s-dashboard/backend/internal/api/middleware"
@@ -31,6 +38,11 @@ type LoginResponse struct {
 	User  *auth.User `json:"user"`
 }
 
+// RefreshRequest 刷新令牌请求，登出时也可携带以撤销对应会话
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
 // Login 用户登录
 func (h *AuthHandler) Login(c *gin.Context) {
 	if h.auth == nil {
@@ -47,7 +59,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	ip := c.ClientIP()
 	userAgent := c.Request.UserAgent()
 
-	user, token, err := h.auth.Login(req.Username, req.Password, ip, userAgent)
+	user, tokens, err := h.auth.Login(req.Username, req.Password, ip, userAgent)
 	if err != nil {
 		status := http.StatusUnauthorized
 		message := "登录失败"
@@ -60,21 +72,76 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		case auth.ErrUserDisabled:
 			message = "用户已被禁用"
 			status = http.StatusForbidden
+		case auth.ErrUserLocked:
+			message = err.Error()
+			status = http.StatusTooManyRequests
 		default:
 			message = err.Error()
 		}
 
-		c.JSON(status, gin.H{"error": message})
+		// 登录失败时请求未认证，审计日志中记录尝试的用户名
+		middleware.SetAuditDetail(c, "username="+req.Username)
+		writeError(c, status, errors.New(message))
 		return
 	}
+	// 登录接口不经过认证中间件，由此处写入当前用户供审计记录
+	c.Set(middleware.ContextUserKey, user)
 
 	// 获取用户的命名空间列表
 	namespaces, _ := h.auth.GetUserNamespaces(user.ID)
 
 	c.JSON(http.StatusOK, gin.H{
-		"token":      token,
-		"user":       user,
-		"namespaces": namespaces,
+		"token":            tokens.AccessToken,
+		"refreshToken":     tokens.RefreshToken,
+		"expiresAt":        tokens.ExpiresAt,
+		"refreshExpiresAt": tokens.RefreshExpiresAt,
+		"user":             user,
+		"namespaces":       namespaces,
+	})
+}
+
+// Refresh 使用刷新令牌换取新的访问令牌，刷新令牌同时轮换
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	if h.auth == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "认证服务未启用"})
+		return
+	}
+
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.RefreshToken == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请提供刷新令牌"})
+		return
+	}
+
+	user, tokens, err := h.auth.RefreshSession(req.RefreshToken, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		status := http.StatusUnauthorized
+		message := "刷新令牌无效，请重新登录"
+
+		switch err {
+		case auth.ErrTokenExpired:
+			message = "会话已过期，请重新登录"
+		case auth.ErrRefreshTokenReused:
+			message = err.Error()
+		case auth.ErrUserDisabled:
+			message = "用户已被禁用"
+			status = http.StatusForbidden
+		case auth.ErrInvalidToken:
+		default:
+			status = http.StatusInternalServerError
+			message = err.Error()
+		}
+
+		writeError(c, status, errors.New(message))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":            tokens.AccessToken,
+		"refreshToken":     tokens.RefreshToken,
+		"expiresAt":        tokens.ExpiresAt,
+		"refreshExpiresAt": tokens.RefreshExpiresAt,
+		"user":             user,
 	})
 }
 
@@ -90,6 +157,11 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 		token := authHeader[7:] // 移除 "Bearer "
 		h.auth.Logout(token)
 	}
+	// 访问令牌已过期时，通过刷新令牌撤销会话
+	var req RefreshRequest
+	if c.ShouldBindJSON(&req) == nil && req.RefreshToken != "" {
+		h.auth.RevokeRefreshToken(req.RefreshToken)
+	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "已登出"})
 }
@@ -141,8 +213,8 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 		return
 	}
 
-	if len(req.NewPassword) < 6 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "新密码长度至少6位"})
+	if err := h.auth.PasswordPolicy().Validate(req.NewPassword); err != nil {
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -152,13 +224,26 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "旧密码错误"})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if err == auth.ErrPasswordReused {
+			writeError(c, http.StatusBadRequest, err)
+			return
+		}
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "密码修改成功"})
 }
 
+// GetPasswordPolicy 返回密码策略，供修改密码页面提示要求
+func (h *AuthHandler) GetPasswordPolicy(c *gin.Context) {
+	if h.auth == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "认证服务未启用"})
+		return
+	}
+	c.JSON(http.StatusOK, h.auth.PasswordPolicy())
+}
+
 // GetUserSessions 获取用户会话列表
 func (h *AuthHandler) GetUserSessions(c *gin.Context) {
 	user := middleware.GetCurrentUser(c)
@@ -169,29 +254,110 @@ func (h *AuthHandler) GetUserSessions(c *gin.Context) {
 
 	sessions, err := h.auth.GetUserSessions(user.ID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"items": sessions})
 }
 
-// RevokeSession 撤销会话
+// RevokeSession 撤销当前用户自己的会话
 func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	user := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未认证"})
+		return
+	}
+
 	sessionID := c.Param("id")
 	if sessionID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "会话ID不能为空"})
 		return
 	}
 
-	if err := h.auth.RevokeSession(sessionID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.auth.RevokeUserSession(user.ID, sessionID); err != nil {
+		if err == auth.ErrSessionNotFound {
+			writeError(c, http.StatusNotFound, err)
+			return
+		}
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "会话已撤销"})
+}
+
+// ListAllSessions 列出所有用户的活跃会话（管理员），支持 ?userId= 过滤
+func (h *AuthHandler) ListAllSessions(c *gin.Context) {
+	if h.auth == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "认证服务未启用"})
+		return
+	}
+
+	var userID int64
+	if raw := c.Query("userId"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的用户ID"})
+			return
+		}
+		userID = id
+	}
+
+	sessions, err := h.auth.ListActiveSessions(userID)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": sessions, "total": len(sessions)})
+}
+
+// AdminRevokeSession 撤销任意用户的会话（管理员）
+func (h *AuthHandler) AdminRevokeSession(c *gin.Context) {
+	if h.auth == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "认证服务未启用"})
+		return
+	}
+
+	if err := h.auth.RevokeSession(c.Param("id")); err != nil {
+		if err == auth.ErrSessionNotFound {
+			writeError(c, http.StatusNotFound, err)
+			return
+		}
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "会话已撤销"})
 }
 
+// ForceLogoutUser 强制用户下线，撤销其全部会话（管理员）
+func (h *AuthHandler) ForceLogoutUser(c *gin.Context) {
+	if h.auth == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "认证服务未启用"})
+		return
+	}
+
+	var userID int64
+	if _, err := parsePathInt64(c, "id", &userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的用户ID"})
+		return
+	}
+
+	revoked, err := h.auth.ForceLogout(userID, c.ClientIP())
+	if err != nil {
+		if err == auth.ErrUserNotFound {
+			writeError(c, http.StatusNotFound, err)
+			return
+		}
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "用户已强制下线", "revoked": revoked})
+}
+
 // ========== 用户管理 ==========
 
 // ListUsers 获取用户列表
@@ -203,13 +369,13 @@ func (h *AuthHandler) ListUsers(c *gin.Context) {
 
 	var params auth.ListUsersParams
 	if err := c.ShouldBindQuery(&params); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	result, err := h.auth.ListUsers(params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -252,18 +418,18 @@ func (h *AuthHandler) CreateUser(c *gin.Context) {
 
 	var req auth.CreateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
-	if len(req.Password) < 6 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "密码长度至少6位"})
+	if err := h.auth.PasswordPolicy().Validate(req.Password); err != nil {
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	user, err := h.auth.CreateUser(&req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -285,13 +451,17 @@ func (h *AuthHandler) UpdateUser(c *gin.Context) {
 
 	var req auth.UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	user, err := h.auth.UpdateUser(userID, &req)
+	if err == auth.ErrUserNotFound {
+		writeError(c, http.StatusNotFound, err)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -322,13 +492,13 @@ func (h *AuthHandler) ResetPassword(c *gin.Context) {
 		return
 	}
 
-	if len(req.NewPassword) < 6 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "密码长度至少6位"})
+	if err := h.auth.PasswordPolicy().Validate(req.NewPassword); err != nil {
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	if err := h.auth.ResetPassword(userID, req.NewPassword); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -349,13 +519,136 @@ func (h *AuthHandler) DeleteUser(c *gin.Context) {
 	}
 
 	if err := h.auth.DeleteUser(userID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "用户已删除"})
 }
 
+// ExportUsers 导出全部用户（format=json|csv，默认 json）
+func (h *AuthHandler) ExportUsers(c *gin.Context) {
+	if h.auth == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "认证服务未启用"})
+		return
+	}
+
+	records, err := h.auth.ExportUsers()
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	filename := fmt.Sprintf("users-%s", time.Now().Format("20060102-150405"))
+	switch strings.ToLower(c.DefaultQuery("format", "json")) {
+	case "csv":
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.csv", filename))
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		if err := auth.WriteUsersCSV(c.Writer, records); err != nil {
+			writeError(c, http.StatusInternalServerError, err)
+		}
+	case "json":
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.json", filename))
+		c.JSON(http.StatusOK, gin.H{"items": records, "total": len(records)})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format 仅支持 json 或 csv"})
+	}
+}
+
+// ImportUsers 批量导入用户。支持 JSON 数组、{"items": [...]}、text/csv 请求体
+// 或 multipart 文件（字段名 file）。dryRun=true 时只返回校验报告。
+func (h *AuthHandler) ImportUsers(c *gin.Context) {
+	if h.auth == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "认证服务未启用"})
+		return
+	}
+
+	records, err := readUserImport(c)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+	if len(records) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "导入内容为空"})
+		return
+	}
+	if len(records) > maxUserImportRecords {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("单次最多导入 %d 个用户", maxUserImportRecords)})
+		return
+	}
+
+	dryRun := c.Query("dryRun") == "true"
+	result, err := h.auth.ImportUsers(records, dryRun)
+	if err != nil {
+		// 导入中途失败时一并返回已处理的逐行结果
+		status, resp := errorResponse(http.StatusInternalServerError, err)
+		c.JSON(status, struct {
+			ErrorResponse
+			Result *auth.ImportResult `json:"result"`
+		}{resp, result})
+		return
+	}
+	middleware.SetAuditDetail(c, fmt.Sprintf("dryRun=%v created=%d updated=%d failed=%d", dryRun, result.Created, result.Updated, result.Failed))
+
+	if result.Failed > 0 && !dryRun {
+		c.JSON(http.StatusUnprocessableEntity, result)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// 单次导入上限
+const maxUserImportRecords = 5000
+
+// readUserImport 根据 Content-Type 解析导入内容
+func readUserImport(c *gin.Context) ([]auth.UserRecord, error) {
+	contentType := c.ContentType()
+
+	if strings.HasPrefix(contentType, "multipart/") {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return nil, fmt.Errorf("缺少上传文件 file")
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		if strings.HasSuffix(strings.ToLower(fileHeader.Filename), ".csv") {
+			return auth.ParseUsersCSV(file)
+		}
+		return decodeUserRecordsJSON(file)
+	}
+
+	if strings.Contains(contentType, "csv") {
+		return auth.ParseUsersCSV(c.Request.Body)
+	}
+	return decodeUserRecordsJSON(c.Request.Body)
+}
+
+func decodeUserRecordsJSON(r io.Reader) ([]auth.UserRecord, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	trimmed := bytes.TrimSpace(data)
+
+	var records []auth.UserRecord
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		err = json.Unmarshal(trimmed, &records)
+	} else {
+		var wrapper struct {
+			Items []auth.UserRecord `json:"items"`
+		}
+		err = json.Unmarshal(trimmed, &wrapper)
+		records = wrapper.Items
+	}
+	if err != nil {
+		return nil, fmt.Errorf("解析 JSON 失败: %w", err)
+	}
+	return records, nil
+}
+
 // ========== 审批管理 ==========
 
 // ListApprovals 获取审批列表
@@ -367,13 +660,13 @@ func (h *AuthHandler) ListApprovals(c *gin.Context) {
 
 	var params auth.ListApprovalParams
 	if err := c.ShouldBindQuery(&params); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	result, err := h.auth.ListApprovals(params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -395,7 +688,7 @@ func (h *AuthHandler) GetApproval(c *gin.Context) {
 
 	approval, err := h.auth.GetApprovalByID(approvalID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -407,36 +700,6 @@ type ApprovalActionRequest struct {
 	Comment string `json:"comment"`
 }
 
-// ApproveRequest 批准审批
-func (h *AuthHandler) ApproveRequest(c *gin.Context) {
-	if h.auth == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "认证服务未启用"})
-		return
-	}
-
-	user := middleware.GetCurrentUser(c)
-	if user == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "未认证"})
-		return
-	}
-
-	var approvalID int64
-	if _, err := parsePathInt64(c, "id", &approvalID); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的审批ID"})
-		return
-	}
-
-	var req ApprovalActionRequest
-	c.ShouldBindJSON(&req)
-
-	if err := h.auth.ApproveRequest(approvalID, user.ID, req.Comment); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "已批准"})
-}
-
 // RejectRequest 拒绝审批
 func (h *AuthHandler) RejectRequest(c *gin.Context) {
 	if h.auth == nil {
@@ -460,7 +723,7 @@ func (h *AuthHandler) RejectRequest(c *gin.Context) {
 	c.ShouldBindJSON(&req)
 
 	if err := h.auth.RejectRequest(approvalID, user.ID, req.Comment); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -476,7 +739,7 @@ func (h *AuthHandler) GetPendingCount(c *gin.Context) {
 
 	count, err := h.auth.GetPendingApprovalCount()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -494,7 +757,7 @@ func (h *AuthHandler) ListApprovalRules(c *gin.Context) {
 
 	rules, err := h.auth.ListApprovalRules()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -505,8 +768,13 @@ func (h *AuthHandler) ListApprovalRules(c *gin.Context) {
 type UpdateApprovalRuleRequest struct {
 	MinRole string `json:"minRole"`
 	Enabled bool   `json:"enabled"`
+	// RequiredApprovals 需要的批准人数（1-5），不传时保持原值
+	RequiredApprovals *int `json:"requiredApprovals"`
 }
 
+// maxRequiredApprovals 单条规则最多要求的批准人数
+const maxRequiredApprovals = 5
+
 // UpdateApprovalRule 更新审批规则
 func (h *AuthHandler) UpdateApprovalRule(c *gin.Context) {
 	if h.auth == nil {
@@ -522,12 +790,21 @@ func (h *AuthHandler) UpdateApprovalRule(c *gin.Context) {
 
 	var req UpdateApprovalRuleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := h.auth.UpdateApprovalRule(ruleID, req.MinRole, req.Enabled); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	requiredApprovals := 0
+	if req.RequiredApprovals != nil {
+		requiredApprovals = *req.RequiredApprovals
+		if requiredApprovals < 1 || requiredApprovals > maxRequiredApprovals {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("requiredApprovals 需在 1-%d 之间", maxRequiredApprovals)})
+			return
+		}
+	}
+
+	if err := h.auth.UpdateApprovalRule(ruleID, req.MinRole, req.Enabled, requiredApprovals); err != nil {
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
+	"github.com/k8s-dashboard/backend/internal/auth"
+)
+
+const (
+	// maxBatchOperations 单次批量请求的最大操作数
+	maxBatchOperations = 100
+	// batchWorkers 批量操作同时执行的数量，避免瞬间向 API Server 发出大量写请求
+	batchWorkers = 5
+)
+
+// 批量操作单项的处理结果
+const (
+	BatchSucceeded       = "succeeded"
+	BatchFailed          = "failed"
+	BatchDenied          = "denied"
+	BatchPendingApproval = "pendingApproval"
+)
+
+// batchKinds 批量操作支持的资源，kind 可使用 Pod、Deployment 等类型名或 pods、deployments 等资源名
+var batchKinds = map[string]string{
+	"pod":          "pods",
+	"pods":         "pods",
+	"deployment":   "deployments",
+	"deployments":  "deployments",
+	"statefulset":  "statefulsets",
+	"statefulsets": "statefulsets",
+	"daemonset":    "daemonsets",
+	"daemonsets":   "daemonsets",
+	"job":          "jobs",
+	"jobs":         "jobs",
+	"cronjob":      "cronjobs",
+	"cronjobs":     "cronjobs",
+}
+
+// batchActions 各批量动作支持的资源
+var batchActions = map[string]map[string]bool{
+	"delete":  {"pods": true, "deployments": true, "statefulsets": true, "daemonsets": true, "jobs": true, "cronjobs": true},
+	"restart": approvalRestartable,
+	"scale":   approvalScalable,
+}
+
+// BatchOperation 批量操作中的一项
+type BatchOperation struct {
+	Action    string `json:"action"` // delete, restart, scale
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Replicas scale 时必填
+	Replicas *int32 `json:"replicas,omitempty"`
+}
+
+// BatchRequest 批量操作请求
+type BatchRequest struct {
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchResult 单项结果，status 为 succeeded、failed、denied（无权限）或 pendingApproval（命中审批规则，已提交审批）
+type BatchResult struct {
+	BatchOperation
+	Status     string `json:"status"`
+	Message    string `json:"message,omitempty"`
+	ApprovalID int64  `json:"approvalId,omitempty"`
+}
+
+// BatchResponse 批量操作结果，results 与请求中的 operations 一一对应
+type BatchResponse struct {
+	Results         []BatchResult `json:"results"`
+	Succeeded       int           `json:"succeeded"`
+	Failed          int           `json:"failed"`
+	Denied          int           `json:"denied"`
+	PendingApproval int           `json:"pendingApproval"`
+}
+
+// BatchOperations 批量删除 Pod/工作负载、滚动重启与扩缩容，供前端多选操作使用。
+// 每一项按对应单项接口的角色规则、命名空间授权与审批规则分别检查，命中审批规则的项转为审批请求；
+// 其余项以有限并发执行，单项失败不影响其它项
+func (h *Handler) BatchOperations(c *gin.Context) {
+	var req BatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+	if len(req.Operations) == 0 || len(req.Operations) > maxBatchOperations {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("operations 数量必须在 1-%d 之间", maxBatchOperations)})
+		return
+	}
+	resources := make([]string, len(req.Operations))
+	for i := range req.Operations {
+		resource, err := validateBatchOperation(&req.Operations[i])
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("operations[%d]: %v", i, err)})
+			return
+		}
+		resources[i] = resource
+	}
+
+	user := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未认证"})
+		return
+	}
+	scope, err := h.getNamespaceAccessScope(c)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	// 授权与审批检查依赖请求上下文，逐项顺序进行；通过的项再并发执行
+	results := make([]BatchResult, len(req.Operations))
+	runnable := make([]int, 0, len(req.Operations))
+	for i, op := range req.Operations {
+		results[i] = BatchResult{BatchOperation: op}
+		if !namespaceAllowed(scope, op.Namespace) {
+			results[i].Status, results[i].Message = BatchDenied, "无权访问该命名空间"
+			continue
+		}
+		method, path := batchRoute(op, resources[i])
+		if ok, reason := routeAllows(c, user.Role, method, path, op.Namespace); !ok {
+			results[i].Status, results[i].Message = BatchDenied, reason
+			continue
+		}
+		if h.auth != nil {
+			needs, err := h.auth.NeedsApproval(user.Role, op.Action, resources[i], op.Namespace)
+			if err != nil {
+				results[i].Status, results[i].Message = BatchFailed, "检查审批规则失败: "+err.Error()
+				continue
+			}
+			if needs {
+				data := approvalActionData{Replicas: op.Replicas}
+				approval, _, err := h.submitApproval(c, user, &auth.CreateApprovalRequest{
+					Action:       op.Action,
+					Resource:     resources[i],
+					ResourceName: op.Name,
+					Namespace:    op.Namespace,
+					Reason:       c.GetHeader("X-Approval-Reason"),
+					RequestData:  data,
+				}, &data)
+				if err != nil {
+					results[i].Status, results[i].Message = BatchFailed, "提交审批失败: "+err.Error()
+					continue
+				}
+				results[i].Status, results[i].Message, results[i].ApprovalID = BatchPendingApproval, "该操作需要审批，已提交审批请求", approval.ID
+				continue
+			}
+		}
+		runnable = append(runnable, i)
+	}
+
+	ctx, cancel := operationContext(c)
+	defer cancel()
+	clientset := h.getK8s(c).Clientset
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, batchWorkers)
+	for _, i := range runnable {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			op := req.Operations[i]
+			message, err := executeOperation(ctx, clientset, op.Action, resources[i], op.Namespace, op.Name, approvalActionData{Replicas: op.Replicas})
+			if err != nil {
+				results[i].Status, results[i].Message = BatchFailed, err.Error()
+				return
+			}
+			results[i].Status, results[i].Message = BatchSucceeded, message
+		}(i)
+	}
+	wg.Wait()
+
+	resp := BatchResponse{Results: results}
+	for _, result := range results {
+		switch result.Status {
+		case BatchSucceeded:
+			resp.Succeeded++
+		case BatchFailed:
+			resp.Failed++
+		case BatchDenied:
+			resp.Denied++
+		case BatchPendingApproval:
+			resp.PendingApproval++
+		}
+	}
+	middleware.SetAuditDetail(c, fmt.Sprintf("operations=%d succeeded=%d failed=%d denied=%d pendingApproval=%d",
+		len(results), resp.Succeeded, resp.Failed, resp.Denied, resp.PendingApproval))
+	c.JSON(http.StatusOK, resp)
+}
+
+// validateBatchOperation 规范化动作与资源类型并校验必填字段，返回资源名（如 deployments）
+func validateBatchOperation(op *BatchOperation) (string, error) {
+	op.Action = strings.ToLower(strings.TrimSpace(op.Action))
+	supported, ok := batchActions[op.Action]
+	if !ok {
+		return "", fmt.Errorf("不支持的操作 %q，可选 delete、restart、scale", op.Action)
+	}
+	resource, ok := batchKinds[strings.ToLower(strings.TrimSpace(op.Kind))]
+	if !ok || !supported[resource] {
+		return "", fmt.Errorf("%s 不支持资源类型 %q", op.Action, op.Kind)
+	}
+	if op.Namespace == "" || op.Name == "" {
+		return "", fmt.Errorf("namespace 与 name 不能为空")
+	}
+	if strings.Contains(op.Namespace, "/") || strings.Contains(op.Name, "/") {
+		return "", fmt.Errorf("namespace 与 name 不能包含 /")
+	}
+	if op.Action == "scale" && (op.Replicas == nil || *op.Replicas < 0) {
+		return "", fmt.Errorf("scale 操作需要提供非负的 replicas")
+	}
+	return resource, nil
+}
+
+// batchRoute 返回批量操作项对应的单项接口，用于复用按路由的角色与命名空间授权规则
+func batchRoute(op BatchOperation, resource string) (method, path string) {
+	path = "/api/v1/namespaces/" + op.Namespace + "/" + resource + "/" + op.Name
+	if op.Action == "delete" {
+		return http.MethodDelete, path
+	}
+	return http.MethodPost, path + "/" + op.Action
+}
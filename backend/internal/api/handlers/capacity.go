@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/capacity"
+	"github.com/k8s-dashboard/backend/internal/metrics"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	capacityDefaultLookback = "14d"
+	// capacityForecastPoints 预测使用的采样点数，步长按回看窗口等分
+	capacityForecastPoints = 336
+)
+
+// CapacityResponse 容量规划结果；未配置 VictoriaMetrics 或查询失败时 used 为 0、forecast 为空，原因见 warnings
+type CapacityResponse struct {
+	Pools    []capacity.Pool     `json:"pools"`
+	Total    capacity.Pool       `json:"total"`
+	Forecast []capacity.Forecast `json:"forecast"`
+	Lookback string              `json:"lookback"`
+	Warnings []string            `json:"warnings,omitempty"`
+}
+
+// GetCapacity 按节点池汇总可分配、已申请与实际用量，并根据 lookback（默认 14d）内的集群用量趋势
+// 线性预测 CPU、内存、Pod 容量的耗尽时间
+func (h *Handler) GetCapacity(c *gin.Context) {
+	lookback := c.DefaultQuery("lookback", capacityDefaultLookback)
+	lookbackDuration, err := metrics.ParseWindow(lookback)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := requestContext(c)
+	clientset := h.getK8s(c).Clientset
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	pods, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := CapacityResponse{Forecast: []capacity.Forecast{}, Lookback: lookback}
+	var podUsage []metrics.PodMetrics
+	var trends map[string][]metrics.TimeSeriesData
+	if metricsClient := h.getMetrics(c); metricsClient == nil {
+		resp.Warnings = append(resp.Warnings, "未配置 VictoriaMetrics，无法统计实际用量与预测")
+	} else {
+		metricsClient = metricsClient.WithContext(ctx)
+		if podUsage, err = metricsClient.GetAllPodMetrics(nil); err != nil {
+			resp.Warnings = append(resp.Warnings, "查询实际用量失败: "+err.Error())
+		}
+		end := time.Now()
+		if trends, err = metricsClient.GetCapacityTrends(end.Add(-lookbackDuration), end, lookbackDuration/capacityForecastPoints); err != nil {
+			resp.Warnings = append(resp.Warnings, "查询用量趋势失败: "+err.Error())
+		}
+	}
+
+	resp.Pools = capacity.BuildPools(nodes.Items, pods.Items, podUsage, h.nodePoolLabel(c))
+	resp.Total = capacity.Total(resp.Pools)
+	if trends != nil {
+		now := time.Now()
+		resp.Forecast = append(resp.Forecast,
+			capacity.LinearForecast(capacity.ResourceCPU, trends[capacity.ResourceCPU], resp.Total.CPU.Allocatable, now),
+			capacity.LinearForecast(capacity.ResourceMemory, trends[capacity.ResourceMemory], resp.Total.Memory.Allocatable, now),
+			capacity.LinearForecast(capacity.ResourcePods, trends[capacity.ResourcePods], resp.Total.Pods.Allocatable, now),
+		)
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,405 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
+	"github.com/k8s-dashboard/backend/internal/audit"
+	"github.com/k8s-dashboard/backend/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+)
+
+// 抓包限制
+const (
+	defaultCaptureImage    = "nicolaka/netshoot:latest"
+	defaultCaptureDuration = 30
+	maxCaptureDuration     = 300
+	maxCaptureBytes        = 50 << 20 // 50MB
+	maxCaptureFilterLength = 512
+	captureStartTimeout    = 2 * time.Minute
+	capturePollInterval    = 2 * time.Second
+	captureFile            = "/tmp/capture.pcap"
+)
+
+var (
+	captureInterfacePattern = regexp.MustCompile(`^[a-zA-Z0-9._@-]{1,15}$`)
+	// BPF 过滤表达式允许的字符，参数以 argv 传给 tcpdump，不经过 shell 解析
+	captureFilterPattern = regexp.MustCompile(`^[a-zA-Z0-9 .:/()!<>=&|\[\]+*-]*$`)
+)
+
+// captureScript 在临时容器中执行：限时抓包，完成后等待后端取走 pcap 再退出。
+// $0 为时长、$1 为网卡，其余参数为过滤表达式
+const captureScript = `d=$0; i=$1; shift 2
+timeout "$d" tcpdump -i "$i" -U -w ` + captureFile + ` "$@" 2>/tmp/capture.err
+touch /tmp/capture.done
+for n in $(seq 600); do [ -f /tmp/capture.collected ] && exit 0; sleep 1; done`
+
+type packetCaptureRequest struct {
+	Interface       string `json:"interface"`
+	Filter          string `json:"filter"`
+	DurationSeconds int    `json:"durationSeconds"`
+}
+
+// captureImage 抓包镜像，需包含 tcpdump，可通过 PACKET_CAPTURE_IMAGE 指定内网镜像，未配置时使用 netshoot
+func (h *Handler) captureImage() string {
+	if h.opts.PacketCaptureImage != "" {
+		return h.opts.PacketCaptureImage
+	}
+	return defaultCaptureImage
+}
+
+func (r *packetCaptureRequest) normalize() error {
+	r.Interface = strings.TrimSpace(r.Interface)
+	if r.Interface == "" {
+		r.Interface = "any"
+	}
+	if !captureInterfacePattern.MatchString(r.Interface) {
+		return fmt.Errorf("invalid interface name")
+	}
+
+	r.Filter = strings.TrimSpace(r.Filter)
+	if len(r.Filter) > maxCaptureFilterLength {
+		return fmt.Errorf("filter exceeds %d characters", maxCaptureFilterLength)
+	}
+	// 以 - 开头会被 tcpdump 当作选项
+	if !captureFilterPattern.MatchString(r.Filter) || strings.HasPrefix(r.Filter, "-") {
+		return fmt.Errorf("filter contains unsupported characters")
+	}
+
+	if r.DurationSeconds == 0 {
+		r.DurationSeconds = defaultCaptureDuration
+	}
+	if r.DurationSeconds < 1 || r.DurationSeconds > maxCaptureDuration {
+		return fmt.Errorf("durationSeconds must be between 1 and %d", maxCaptureDuration)
+	}
+	return nil
+}
+
+// CreatePacketCapture 向 Pod 注入 tcpdump 临时容器，在 Pod 网络命名空间内限时抓包，
+// 结果异步保存为 pcap，可通过 /packet-captures/:id/download 下载
+func (h *Handler) CreatePacketCapture(c *gin.Context) {
+	if h.audit == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "审计日志功能未启用"})
+		return
+	}
+	user := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未认证"})
+		return
+	}
+
+	namespace := c.Param("ns")
+	name := c.Param("name")
+	scope, err := h.getNamespaceAccessScope(c)
+	if err != nil {
+		writeError(c, http.StatusUnauthorized, err)
+		return
+	}
+	if !namespaceAllowed(scope, namespace) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "无权访问该命名空间"})
+		return
+	}
+
+	var req packetCaptureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求体"})
+		return
+	}
+	if err := req.normalize(); err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	cluster := middleware.GetClusterName(c)
+	if cluster == "" {
+		cluster = "default"
+	}
+	running, err := h.audit.HasRunningPacketCapture(cluster, namespace, name)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if running {
+		c.JSON(http.StatusConflict, gin.H{"error": "该 Pod 已有进行中的抓包"})
+		return
+	}
+
+	client := h.getK8s(c)
+	ctx := requestContext(c)
+	pod, err := client.Clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "只能对运行中的 Pod 抓包"})
+		return
+	}
+	// hostNetwork Pod 与节点共享网络命名空间，抓包会拿到整个节点的流量
+	if pod.Spec.HostNetwork && user.Role != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "hostNetwork Pod 抓包需要 admin 权限"})
+		return
+	}
+
+	containerName := "capture-" + utilrand.String(5)
+	args := []string{strconv.Itoa(req.DurationSeconds), req.Interface}
+	if req.Filter != "" {
+		args = append(args, req.Filter)
+	}
+	pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, corev1.EphemeralContainer{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:    containerName,
+			Image:   h.captureImage(),
+			Command: append([]string{"sh", "-c", captureScript}, args...),
+			SecurityContext: &corev1.SecurityContext{
+				Capabilities: &corev1.Capabilities{
+					Add: []corev1.Capability{"NET_RAW", "NET_ADMIN"},
+				},
+			},
+		},
+	})
+	if _, err := client.Clientset.CoreV1().Pods(namespace).UpdateEphemeralContainers(ctx, name, pod, metav1.UpdateOptions{}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("注入抓包容器失败: %v", err)})
+		return
+	}
+
+	capture := &audit.PacketCapture{
+		User:            user.Username,
+		Cluster:         cluster,
+		Namespace:       namespace,
+		Pod:             name,
+		Container:       containerName,
+		Interface:       req.Interface,
+		Filter:          req.Filter,
+		DurationSeconds: req.DurationSeconds,
+	}
+	if err := h.audit.StartPacketCapture(capture); err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	middleware.SetAuditDetail(c, fmt.Sprintf("capture #%d interface=%s filter=%q duration=%ds",
+		capture.ID, req.Interface, req.Filter, req.DurationSeconds))
+
+	go h.runPacketCapture(client, capture)
+
+	c.JSON(http.StatusAccepted, capture)
+}
+
+// runPacketCapture 等待抓包结束后取回 pcap 并写入记录
+func (h *Handler) runPacketCapture(client *k8s.Client, capture *audit.PacketCapture) {
+	timeout := captureStartTimeout + time.Duration(capture.DurationSeconds)*time.Second + time.Minute
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	data, truncated, err := collectPacketCapture(ctx, client, capture)
+	status, message := audit.CaptureStatusCompleted, ""
+	if err != nil {
+		status, message = audit.CaptureStatusFailed, err.Error()
+	}
+	if err := h.audit.FinishPacketCapture(capture.ID, status, message, data, truncated); err != nil {
+		log.Printf("保存抓包结果失败: %v", err)
+	}
+}
+
+func collectPacketCapture(ctx context.Context, client *k8s.Client, capture *audit.PacketCapture) ([]byte, bool, error) {
+	if err := waitCaptureContainerRunning(ctx, client, capture); err != nil {
+		return nil, false, err
+	}
+
+	exec := func(command ...string) ([]byte, error) {
+		var out bytes.Buffer
+		err := podExec(ctx, client, capture.Namespace, capture.Pod, capture.Container, command, nil, &out)
+		return out.Bytes(), err
+	}
+	// 无论成功与否都通知抓包容器退出
+	defer exec("touch", "/tmp/capture.collected")
+
+	select {
+	case <-ctx.Done():
+		return nil, false, ctx.Err()
+	case <-time.After(time.Duration(capture.DurationSeconds) * time.Second):
+	}
+
+	ticker := time.NewTicker(capturePollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := exec("test", "-f", "/tmp/capture.done"); err == nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, false, fmt.Errorf("等待抓包结束超时")
+		case <-ticker.C:
+		}
+	}
+
+	data, err := exec("head", "-c", strconv.Itoa(maxCaptureBytes+1), captureFile)
+	if err != nil || len(data) == 0 {
+		if msg, _ := exec("cat", "/tmp/capture.err"); len(bytes.TrimSpace(msg)) > 0 {
+			return nil, false, fmt.Errorf("tcpdump: %s", strings.TrimSpace(string(msg)))
+		}
+		if err == nil {
+			err = errors.New("未生成抓包文件")
+		}
+		return nil, false, err
+	}
+	// 超过上限时截断，截断后的 pcap 仍可被 Wireshark 读取（最后一个包不完整）
+	if len(data) > maxCaptureBytes {
+		return data[:maxCaptureBytes], true, nil
+	}
+	return data, false, nil
+}
+
+// waitCaptureContainerRunning 等待临时容器启动，镜像拉取失败等情况直接返回错误
+func waitCaptureContainerRunning(ctx context.Context, client *k8s.Client, capture *audit.PacketCapture) error {
+	ctx, cancel := context.WithTimeout(ctx, captureStartTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(capturePollInterval)
+	defer ticker.Stop()
+	for {
+		pod, err := client.Clientset.CoreV1().Pods(capture.Namespace).Get(ctx, capture.Pod, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		for _, status := range pod.Status.EphemeralContainerStatuses {
+			if status.Name != capture.Container {
+				continue
+			}
+			switch {
+			case status.State.Running != nil:
+				return nil
+			case status.State.Terminated != nil:
+				return fmt.Errorf("抓包容器已退出: %s", status.State.Terminated.Reason)
+			case status.State.Waiting != nil && (status.State.Waiting.Reason == "ErrImagePull" ||
+				status.State.Waiting.Reason == "ImagePullBackOff" || status.State.Waiting.Reason == "InvalidImageName"):
+				return fmt.Errorf("抓包容器启动失败: %s %s", status.State.Waiting.Reason, status.State.Waiting.Message)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("等待抓包容器启动超时")
+		case <-ticker.C:
+		}
+	}
+}
+
+// canAccessPacketCapture 抓包内容可能包含敏感数据，仅发起人和 admin 可见
+func canAccessPacketCapture(c *gin.Context, capture *audit.PacketCapture) bool {
+	user := middleware.GetCurrentUser(c)
+	return user != nil && (user.Role == "admin" || user.Username == capture.User)
+}
+
+// ListPacketCaptures 查询抓包记录，非 admin 只能看到自己发起的抓包
+func (h *Handler) ListPacketCaptures(c *gin.Context) {
+	if h.audit == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "审计日志功能未启用"})
+		return
+	}
+	user := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未认证"})
+		return
+	}
+
+	params := audit.PacketCaptureListParams{
+		User:      c.Query("user"),
+		Namespace: c.Query("namespace"),
+		Pod:       c.Query("pod"),
+	}
+	if user.Role != "admin" {
+		params.User = user.Username
+	}
+	params.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	params.PageSize, _ = strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+
+	items, total, err := h.audit.ListPacketCaptures(params)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items": items,
+		"total": total,
+	})
+}
+
+// GetPacketCapture 获取抓包记录状态
+func (h *Handler) GetPacketCapture(c *gin.Context) {
+	if h.audit == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "审计日志功能未启用"})
+		return
+	}
+
+	var id int64
+	if _, err := parsePathInt64(c, "id", &id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的抓包ID"})
+		return
+	}
+
+	capture, err := h.audit.GetPacketCapture(id)
+	if err != nil {
+		if errors.Is(err, audit.ErrPacketCaptureNotFound) {
+			writeError(c, http.StatusNotFound, err)
+			return
+		}
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if !canAccessPacketCapture(c, capture) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "权限不足"})
+		return
+	}
+
+	c.JSON(http.StatusOK, capture)
+}
+
+// DownloadPacketCapture 下载 pcap 文件
+func (h *Handler) DownloadPacketCapture(c *gin.Context) {
+	if h.audit == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "审计日志功能未启用"})
+		return
+	}
+
+	var id int64
+	if _, err := parsePathInt64(c, "id", &id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的抓包ID"})
+		return
+	}
+
+	capture, data, err := h.audit.GetPacketCaptureData(id)
+	if err != nil {
+		if errors.Is(err, audit.ErrPacketCaptureNotFound) {
+			writeError(c, http.StatusNotFound, err)
+			return
+		}
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if !canAccessPacketCapture(c, capture) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "权限不足"})
+		return
+	}
+	if capture.Status != audit.CaptureStatusCompleted {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("抓包状态为 %s，暂无可下载的文件", capture.Status)})
+		return
+	}
+	middleware.SetAuditDetail(c, fmt.Sprintf("download capture #%d %s/%s", capture.ID, capture.Namespace, capture.Pod))
+
+	filename := fmt.Sprintf("%s-%d.pcap", capture.Pod, capture.ID)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "application/vnd.tcpdump.pcap", data)
+}
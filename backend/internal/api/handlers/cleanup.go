@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
+	"github.com/k8s-dashboard/backend/internal/auth"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// 可清理的资源类别
+const (
+	CleanupCompletedJobs       = "completed-jobs"       // 已完成或已失败的 Job
+	CleanupFinishedPods        = "finished-pods"        // Succeeded/Failed 状态的 Pod
+	CleanupOrphanedReplicaSets = "orphaned-replicasets" // 没有上层控制器且副本数为 0 的 ReplicaSet
+)
+
+var cleanupKinds = map[string]bool{
+	CleanupCompletedJobs:       true,
+	CleanupFinishedPods:        true,
+	CleanupOrphanedReplicaSets: true,
+}
+
+// CleanupRequest 命名空间清理请求，dryRun 为 true 时只返回将被删除的资源
+type CleanupRequest struct {
+	Kinds  []string `json:"kinds"`
+	DryRun bool     `json:"dryRun"`
+}
+
+// CleanupItem 一个待清理（或已清理）的资源
+type CleanupItem struct {
+	Kind   string `json:"kind"` // Job, Pod, ReplicaSet
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+	Error  string `json:"error,omitempty"`
+}
+
+// CleanupResult 清理结果
+type CleanupResult struct {
+	Namespace string        `json:"namespace"`
+	DryRun    bool          `json:"dryRun"`
+	Items     []CleanupItem `json:"items"`
+	Deleted   int           `json:"deleted"`
+	Failed    int           `json:"failed"`
+}
+
+// CleanupNamespace 批量清理命名空间中的已结束资源。非 dry-run 请求受审批规则（cleanup namespaces）约束，
+// 命中规则时由 ApprovalGate 转为审批请求，批准后在执行时重新计算清理范围
+func (h *Handler) CleanupNamespace(c *gin.Context) {
+	namespace := c.Param("ns")
+	var req CleanupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+	if err := validateCleanupKinds(req.Kinds); err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx, cancel := operationContext(c)
+	defer cancel()
+	result, err := cleanupNamespace(ctx, h.getK8s(c).Clientset, namespace, req.Kinds, req.DryRun)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if !req.DryRun {
+		middleware.SetAuditDetail(c, fmt.Sprintf("kinds=%s deleted=%d failed=%d", strings.Join(req.Kinds, ","), result.Deleted, result.Failed))
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func validateCleanupKinds(kinds []string) error {
+	if len(kinds) == 0 {
+		return fmt.Errorf("kinds 不能为空，可选 %s、%s、%s", CleanupCompletedJobs, CleanupFinishedPods, CleanupOrphanedReplicaSets)
+	}
+	for _, kind := range kinds {
+		if !cleanupKinds[kind] {
+			return fmt.Errorf("不支持清理 %s", kind)
+		}
+	}
+	return nil
+}
+
+// cleanupNamespace 计算清理范围并在非 dry-run 时逐个删除，单个资源删除失败不影响其余资源
+func cleanupNamespace(ctx context.Context, clientset kubernetes.Interface, namespace string, kinds []string, dryRun bool) (*CleanupResult, error) {
+	items, err := cleanupCandidates(ctx, clientset, namespace, kinds)
+	if err != nil {
+		return nil, err
+	}
+	result := &CleanupResult{Namespace: namespace, DryRun: dryRun, Items: items}
+	if dryRun {
+		return result, nil
+	}
+
+	background := metav1.DeletePropagationBackground
+	opts := metav1.DeleteOptions{PropagationPolicy: &background}
+	for i := range result.Items {
+		item := &result.Items[i]
+		switch item.Kind {
+		case "Job":
+			err = clientset.BatchV1().Jobs(namespace).Delete(ctx, item.Name, opts)
+		case "Pod":
+			err = clientset.CoreV1().Pods(namespace).Delete(ctx, item.Name, opts)
+		case "ReplicaSet":
+			err = clientset.AppsV1().ReplicaSets(namespace).Delete(ctx, item.Name, opts)
+		}
+		if err != nil && !apierrors.IsNotFound(err) {
+			item.Error = err.Error()
+			result.Failed++
+			continue
+		}
+		result.Deleted++
+	}
+	return result, nil
+}
+
+// cleanupApprovalPreview 按提交审批时的集群状态统计清理范围，供审批人参考
+func cleanupApprovalPreview(ctx context.Context, clientset kubernetes.Interface, namespace string, kinds []string) (*auth.ApprovalPreview, error) {
+	items, err := cleanupCandidates(ctx, clientset, namespace, kinds)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, item := range items {
+		counts[item.Kind]++
+	}
+	preview := &auth.ApprovalPreview{
+		Kind:         "Namespace",
+		Summary:      fmt.Sprintf("清理 %s：Job %d 个，Pod %d 个，ReplicaSet %d 个", namespace, counts["Job"], counts["Pod"], counts["ReplicaSet"]),
+		AffectedPods: counts["Pod"],
+		ComputedAt:   time.Now(),
+	}
+	if len(items) == 0 {
+		preview.Warnings = append(preview.Warnings, "当前没有符合条件的资源，执行时将重新计算清理范围")
+	}
+	return preview, nil
+}
+
+// cleanupCandidates 列出命名空间中符合清理条件的资源。由 Job 创建的 Pod 随 Job 一并删除，不单独列出
+func cleanupCandidates(ctx context.Context, clientset kubernetes.Interface, namespace string, kinds []string) ([]CleanupItem, error) {
+	selected := make(map[string]bool, len(kinds))
+	for _, kind := range kinds {
+		selected[kind] = true
+	}
+	items := make([]CleanupItem, 0)
+	deletedJobs := make(map[string]bool)
+
+	if selected[CleanupCompletedJobs] {
+		jobs, err := clientset.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for i := range jobs.Items {
+			if reason := finishedJobReason(&jobs.Items[i]); reason != "" {
+				items = append(items, CleanupItem{Kind: "Job", Name: jobs.Items[i].Name, Reason: reason})
+				deletedJobs[jobs.Items[i].Name] = true
+			}
+		}
+	}
+
+	if selected[CleanupFinishedPods] {
+		pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+				continue
+			}
+			if owner := metav1.GetControllerOf(pod); owner != nil && owner.Kind == "Job" && deletedJobs[owner.Name] {
+				continue
+			}
+			items = append(items, CleanupItem{Kind: "Pod", Name: pod.Name, Reason: "Pod 状态为 " + string(pod.Status.Phase)})
+		}
+	}
+
+	if selected[CleanupOrphanedReplicaSets] {
+		replicaSets, err := clientset.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for i := range replicaSets.Items {
+			rs := &replicaSets.Items[i]
+			if metav1.GetControllerOf(rs) != nil || rs.Status.Replicas > 0 || (rs.Spec.Replicas != nil && *rs.Spec.Replicas > 0) {
+				continue
+			}
+			items = append(items, CleanupItem{Kind: "ReplicaSet", Name: rs.Name, Reason: "没有上层控制器且副本数为 0"})
+		}
+	}
+	return items, nil
+}
+
+// finishedJobReason 返回已结束 Job 的说明，仍在运行的 Job 返回空
+func finishedJobReason(job *batchv1.Job) string {
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			return "Job 已完成"
+		case batchv1.JobFailed:
+			return "Job 已失败"
+		}
+	}
+	return ""
+}
@@ -7,15 +7,15 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/clusters"
 )
 
-type clusterTestRequest struct {
-	Kubeconfig string `json:"kubeconfig" binding:"required"`
-}
-
+// clusterAddRequest 凭据二选一：kubeconfig，或 server + caData + bearerToken（ServiceAccount Token）
 type clusterAddRequest struct {
-	Name       string `json:"name" binding:"required"`
-	Kubeconfig string `json:"kubeconfig" binding:"required"`
+	clusters.Credentials
+	Name               string `json:"name" binding:"required"`
+	VictoriaMetricsURL string `json:"victoriaMetricsUrl"`
+	AlertmanagerURL    string `json:"alertmanagerUrl"`
 }
 
 func (h *Handler) ListClusters(c *gin.Context) {
@@ -24,9 +24,9 @@ func (h *Handler) ListClusters(c *gin.Context) {
 		return
 	}
 
-	items, err := h.clusters.List(context.Background())
+	items, err := h.clusters.List(requestContext(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusOK, items)
@@ -44,13 +44,13 @@ func (h *Handler) GetCluster(c *gin.Context) {
 		return
 	}
 
-	info, err := h.clusters.Get(context.Background(), name)
+	info, err := h.clusters.Get(requestContext(c), name)
 	if err != nil {
 		status := http.StatusInternalServerError
 		if strings.Contains(err.Error(), "not found") {
 			status = http.StatusNotFound
 		}
-		c.JSON(status, gin.H{"error": err.Error()})
+		writeError(c, status, err)
 		return
 	}
 	c.JSON(http.StatusOK, info)
@@ -62,13 +62,13 @@ func (h *Handler) TestCluster(c *gin.Context) {
 		return
 	}
 
-	var req clusterTestRequest
+	var req clusters.Credentials
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
-	info, err := h.clusters.TestKubeconfig(context.Background(), req.Kubeconfig)
+	info, err := h.clusters.TestCredentials(requestContext(c), req)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
@@ -84,6 +84,24 @@ func (h *Handler) TestCluster(c *gin.Context) {
 	})
 }
 
+// ListKubeconfigContexts 解析提交的 kubeconfig 并返回其中的 context，供导入时选择（不会连接集群）
+func (h *Handler) ListKubeconfigContexts(c *gin.Context) {
+	var req struct {
+		Kubeconfig string `json:"kubeconfig" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	items, err := clusters.ListKubeconfigContexts(req.Kubeconfig)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+	c.JSON(http.StatusOK, ListResponse{Items: items, Total: len(items)})
+}
+
 func (h *Handler) AddCluster(c *gin.Context) {
 	if h.clusters == nil {
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "multi-cluster is not enabled"})
@@ -92,22 +110,90 @@ func (h *Handler) AddCluster(c *gin.Context) {
 
 	var req clusterAddRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
-	info, err := h.clusters.Add(context.Background(), req.Name, req.Kubeconfig)
+	info, err := h.clusters.Add(requestContext(c), req.Name, req.Credentials, clusters.Endpoints{
+		VictoriaMetricsURL: req.VictoriaMetricsURL,
+		AlertmanagerURL:    req.AlertmanagerURL,
+	})
 	if err != nil {
 		status := http.StatusBadRequest
 		if strings.Contains(err.Error(), "already exists") {
 			status = http.StatusConflict
 		}
-		c.JSON(status, gin.H{"error": err.Error()})
+		writeError(c, status, err)
 		return
 	}
 	c.JSON(http.StatusCreated, info)
 }
 
+// clusterUpdateRequest 提供 kubeconfig 或 server/bearerToken 时轮换凭据
+type clusterUpdateRequest struct {
+	clusters.Credentials
+	DisplayName *string `json:"displayName"`
+	Enabled     *bool   `json:"enabled"`
+}
+
+// UpdateCluster 修改集群展示名称、轮换凭据或启用/禁用集群，未提供的字段保持不变
+func (h *Handler) UpdateCluster(c *gin.Context) {
+	if h.clusters == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "multi-cluster is not enabled"})
+		return
+	}
+
+	var req clusterUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+	update := clusters.Update{DisplayName: req.DisplayName, Enabled: req.Enabled}
+	if req.Credentials != (clusters.Credentials{}) {
+		update.Credentials = &req.Credentials
+	}
+	if update.DisplayName == nil && update.Credentials == nil && update.Enabled == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "nothing to update"})
+		return
+	}
+
+	info, err := h.clusters.Update(requestContext(c), c.Param("name"), update)
+	if err != nil {
+		status := http.StatusBadRequest
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		writeError(c, status, err)
+		return
+	}
+	c.JSON(http.StatusOK, info)
+}
+
+// UpdateClusterEndpoints 设置集群专属的 VictoriaMetrics 与 Alertmanager 地址，空值表示使用全局配置
+func (h *Handler) UpdateClusterEndpoints(c *gin.Context) {
+	if h.clusters == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "multi-cluster is not enabled"})
+		return
+	}
+
+	var req clusters.Endpoints
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	info, err := h.clusters.UpdateEndpoints(requestContext(c), c.Param("name"), req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		writeError(c, status, err)
+		return
+	}
+	c.JSON(http.StatusOK, info)
+}
+
 func (h *Handler) DeleteCluster(c *gin.Context) {
 	if h.clusters == nil {
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "multi-cluster is not enabled"})
@@ -128,7 +214,7 @@ func (h *Handler) DeleteCluster(c *gin.Context) {
 		if strings.Contains(err.Error(), "not found") {
 			status = http.StatusNotFound
 		}
-		c.JSON(status, gin.H{"error": err.Error()})
+		writeError(c, status, err)
 		return
 	}
 
@@ -147,13 +233,13 @@ func (h *Handler) SwitchCluster(c *gin.Context) {
 		return
 	}
 
-	info, err := h.clusters.Switch(context.Background(), name)
+	info, err := h.clusters.Switch(requestContext(c), name)
 	if err != nil {
 		status := http.StatusBadRequest
 		if strings.Contains(err.Error(), "not found") {
 			status = http.StatusNotFound
 		}
-		c.JSON(status, gin.H{"error": err.Error()})
+		writeError(c, status, err)
 		return
 	}
 
@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ConfigReference 引用 ConfigMap/Secret 的工作负载及引用方式
+type ConfigReference struct {
+	Kind       string   `json:"kind"`
+	Name       string   `json:"name"`
+	References []string `json:"references"` // 如 "envFrom(app)"、"env(app): DB_PASSWORD"、"volume: config"
+}
+
+// ConfigUsageResponse ConfigMap/Secret 的引用情况
+type ConfigUsageResponse struct {
+	Kind      string            `json:"kind"`
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	InUse     bool              `json:"inUse"`
+	Workloads []ConfigReference `json:"workloads"`
+}
+
+// GetConfigMapUsage 列出命名空间内引用该 ConfigMap 的工作负载
+func (h *Handler) GetConfigMapUsage(c *gin.Context) {
+	h.getConfigUsage(c, "ConfigMap")
+}
+
+// GetSecretUsage 列出命名空间内引用该 Secret 的工作负载
+func (h *Handler) GetSecretUsage(c *gin.Context) {
+	h.getConfigUsage(c, "Secret")
+}
+
+func (h *Handler) getConfigUsage(c *gin.Context, kind string) {
+	namespace := c.Param("ns")
+	name := c.Param("name")
+	workloads, err := findConfigReferences(requestContext(c), h.getK8s(c).Clientset, namespace, kind, name)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, ConfigUsageResponse{
+		Kind:      kind,
+		Name:      name,
+		Namespace: namespace,
+		InUse:     len(workloads) > 0,
+		Workloads: workloads,
+	})
+}
+
+// checkConfigInUse 删除前检查引用，仍被引用且未指定 force=true 时返回 409 并列出受影响的工作负载。
+// 返回 false 表示已写入响应，调用方应直接返回
+func checkConfigInUse(c *gin.Context, clientset kubernetes.Interface, kind string) bool {
+	if c.Query("force") == "true" {
+		return true
+	}
+	namespace, name := c.Param("ns"), c.Param("name")
+	workloads, err := findConfigReferences(requestContext(c), clientset, namespace, kind, name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "检查引用失败: " + err.Error()})
+		return false
+	}
+	if len(workloads) == 0 {
+		return true
+	}
+	c.JSON(http.StatusConflict, gin.H{
+		"error":     fmt.Sprintf("%s %s 仍被 %d 个工作负载引用，删除后这些工作负载将无法启动，如需删除请使用 force=true", kind, name, len(workloads)),
+		"workloads": workloads,
+	})
+	return false
+}
+
+// ConfigRolloutResult 更新 ConfigMap 后自动滚动重启 Deployment 的结果
+type ConfigRolloutResult struct {
+	Restarted []string `json:"restarted"`
+	Failed    []string `json:"failed"`
+}
+
+// respondConfigMapUpdate 返回更新后的 ConfigMap；指定 rollout=true 时滚动重启引用它的 Deployment，
+// 使以环境变量、subPath 方式使用配置的 Pod 也能拿到新值。重启失败不影响更新结果，逐个返回在 failed 中
+func respondConfigMapUpdate(c *gin.Context, clientset kubernetes.Interface, cm *corev1.ConfigMap) {
+	if c.Query("rollout") != "true" {
+		c.JSON(http.StatusOK, cm)
+		return
+	}
+	rollout, err := rolloutConfigMapDeployments(requestContext(c), clientset, cm.Namespace, cm.Name)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{"configMap": cm, "rolloutError": "查找引用的 Deployment 失败: " + err.Error()})
+		return
+	}
+	middleware.SetAuditDetail(c, "rollout="+strings.Join(rollout.Restarted, ","))
+	c.JSON(http.StatusOK, gin.H{"configMap": cm, "rollout": rollout})
+}
+
+// rolloutConfigMapDeployments 通过 restartedAt 注解滚动重启引用指定 ConfigMap 的 Deployment
+func rolloutConfigMapDeployments(ctx context.Context, clientset kubernetes.Interface, namespace, name string) (*ConfigRolloutResult, error) {
+	deployments, err := clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	result := &ConfigRolloutResult{Restarted: []string{}, Failed: []string{}}
+	patch := []byte(fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{"kubectl.kubernetes.io/restartedAt":%q}}}}}`, time.Now().Format(time.RFC3339)))
+	for i := range deployments.Items {
+		dep := &deployments.Items[i]
+		if len(podSpecConfigReferences(&dep.Spec.Template.Spec, "ConfigMap", name)) == 0 {
+			continue
+		}
+		if _, err := clientset.AppsV1().Deployments(namespace).Patch(ctx, dep.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			result.Failed = append(result.Failed, dep.Name+": "+err.Error())
+			continue
+		}
+		result.Restarted = append(result.Restarted, dep.Name)
+	}
+	return result, nil
+}
+
+// findConfigReferences 扫描命名空间内工作负载与独立 Pod 的 Pod 模板，找出引用指定 ConfigMap/Secret 的对象。
+// 由控制器管理的 Pod 与 ReplicaSet/Job 归入其上层工作负载，不重复列出
+func findConfigReferences(ctx context.Context, clientset kubernetes.Interface, namespace, kind, name string) ([]ConfigReference, error) {
+	var workloads []ConfigReference
+	add := func(workloadKind, workloadName string, spec *corev1.PodSpec) {
+		if refs := podSpecConfigReferences(spec, kind, name); len(refs) > 0 {
+			workloads = append(workloads, ConfigReference{Kind: workloadKind, Name: workloadName, References: refs})
+		}
+	}
+	opts := metav1.ListOptions{}
+
+	deployments, err := clientset.AppsV1().Deployments(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range deployments.Items {
+		add("Deployment", deployments.Items[i].Name, &deployments.Items[i].Spec.Template.Spec)
+	}
+	statefulSets, err := clientset.AppsV1().StatefulSets(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range statefulSets.Items {
+		add("StatefulSet", statefulSets.Items[i].Name, &statefulSets.Items[i].Spec.Template.Spec)
+	}
+	daemonSets, err := clientset.AppsV1().DaemonSets(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range daemonSets.Items {
+		add("DaemonSet", daemonSets.Items[i].Name, &daemonSets.Items[i].Spec.Template.Spec)
+	}
+	replicaSets, err := clientset.AppsV1().ReplicaSets(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range replicaSets.Items {
+		if len(replicaSets.Items[i].OwnerReferences) == 0 {
+			add("ReplicaSet", replicaSets.Items[i].Name, &replicaSets.Items[i].Spec.Template.Spec)
+		}
+	}
+	cronJobs, err := clientset.BatchV1().CronJobs(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range cronJobs.Items {
+		add("CronJob", cronJobs.Items[i].Name, &cronJobs.Items[i].Spec.JobTemplate.Spec.Template.Spec)
+	}
+	jobs, err := clientset.BatchV1().Jobs(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range jobs.Items {
+		if len(jobs.Items[i].OwnerReferences) == 0 {
+			add("Job", jobs.Items[i].Name, &jobs.Items[i].Spec.Template.Spec)
+		}
+	}
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range pods.Items {
+		if len(pods.Items[i].OwnerReferences) == 0 {
+			add("Pod", pods.Items[i].Name, &pods.Items[i].Spec)
+		}
+	}
+
+	sort.SliceStable(workloads, func(i, j int) bool {
+		if workloads[i].Kind != workloads[j].Kind {
+			return workloads[i].Kind < workloads[j].Kind
+		}
+		return workloads[i].Name < workloads[j].Name
+	})
+	return workloads, nil
+}
+
+// podSpecConfigReferences 返回 Pod 模板中对指定 ConfigMap/Secret 的全部引用：
+// envFrom、env valueFrom、卷（含 projected 卷），Secret 额外检查 imagePullSecrets
+func podSpecConfigReferences(spec *corev1.PodSpec, kind, name string) []string {
+	var refs []string
+	containers := make([]corev1.Container, 0, len(spec.InitContainers)+len(spec.Containers))
+	containers = append(containers, spec.InitContainers...)
+	containers = append(containers, spec.Containers...)
+
+	for _, container := range containers {
+		for _, from := range container.EnvFrom {
+			if (kind == "ConfigMap" && from.ConfigMapRef != nil && from.ConfigMapRef.Name == name) ||
+				(kind == "Secret" && from.SecretRef != nil && from.SecretRef.Name == name) {
+				refs = append(refs, fmt.Sprintf("envFrom(%s)", container.Name))
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if (kind == "ConfigMap" && env.ValueFrom.ConfigMapKeyRef != nil && env.ValueFrom.ConfigMapKeyRef.Name == name) ||
+				(kind == "Secret" && env.ValueFrom.SecretKeyRef != nil && env.ValueFrom.SecretKeyRef.Name == name) {
+				refs = append(refs, fmt.Sprintf("env(%s): %s", container.Name, env.Name))
+			}
+		}
+	}
+
+	for _, volume := range spec.Volumes {
+		switch {
+		case kind == "ConfigMap" && volume.ConfigMap != nil && volume.ConfigMap.Name == name,
+			kind == "Secret" && volume.Secret != nil && volume.Secret.SecretName == name:
+			refs = append(refs, "volume: "+volume.Name)
+		case volume.Projected != nil:
+			for _, source := range volume.Projected.Sources {
+				if (kind == "ConfigMap" && source.ConfigMap != nil && source.ConfigMap.Name == name) ||
+					(kind == "Secret" && source.Secret != nil && source.Secret.Name == name) {
+					refs = append(refs, "projected volume: "+volume.Name)
+					break
+				}
+			}
+		}
+	}
+
+	if kind == "Secret" {
+		for _, ref := range spec.ImagePullSecrets {
+			if ref.Name == name {
+				refs = append(refs, "imagePullSecrets")
+			}
+		}
+	}
+	return refs
+}
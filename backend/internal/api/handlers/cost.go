@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/cost"
+	"github.com/k8s-dashboard/backend/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CostHandler 费用估算处理器
+type CostHandler struct {
+	h       *Handler
+	service *cost.Service
+}
+
+// NewCostHandler 创建费用估算处理器
+func NewCostHandler(h *Handler, service *cost.Service) *CostHandler {
+	return &CostHandler{h: h, service: service}
+}
+
+// GetNamespaceCosts 按命名空间汇总窗口内的估算费用
+func (ch *CostHandler) GetNamespaceCosts(c *gin.Context) {
+	ch.estimate(c, cost.GroupByNamespace)
+}
+
+// GetWorkloadCosts 按工作负载汇总窗口内的估算费用，namespace 参数可限定命名空间
+func (ch *CostHandler) GetWorkloadCosts(c *gin.Context) {
+	ch.estimate(c, cost.GroupByWorkload)
+}
+
+// estimate 参数 namespace 限定命名空间，window 覆盖默认统计窗口（如 24h、7d、30d）
+func (ch *CostHandler) estimate(c *gin.Context, groupBy string) {
+	if ch.service == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "费用估算服务未初始化"})
+		return
+	}
+	metricsClient := ch.h.getMetrics(c)
+	if metricsClient == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "metrics client not configured"})
+		return
+	}
+	cfg := ch.service.Config()
+	window := c.Query("window")
+	if window != "" {
+		if err := metrics.ValidateWindow(window); err != nil {
+			writeError(c, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	namespaces, ok := ch.h.metricsNamespaces(c)
+	if !ok {
+		return
+	}
+	if ns := c.Query("namespace"); ns != "" {
+		if namespaces != nil && !slices.Contains(namespaces, ns) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "无权访问该命名空间"})
+			return
+		}
+		namespaces = []string{ns}
+	}
+	if namespaces != nil && len(namespaces) == 0 {
+		if window == "" {
+			window = cfg.Window
+		}
+		c.JSON(http.StatusOK, cost.Report{
+			Window:            window,
+			Currency:          cfg.Currency,
+			CPUHourPrice:      cfg.CPUHourPrice,
+			MemoryGBHourPrice: cfg.MemoryGBHourPrice,
+			Items:             []cost.Item{},
+		})
+		return
+	}
+
+	ctx := requestContext(c)
+	clientset := ch.h.getK8s(c).Clientset
+	pods, err := listPodsInNamespaces(ctx, clientset, namespaces)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	// 节点单价只在配置了实例价格时需要；无权读取节点时退回默认单价
+	var nodes []corev1.Node
+	if len(cfg.NodePrices) > 0 {
+		if list, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{}); err == nil {
+			nodes = list.Items
+		}
+	}
+
+	report, err := ch.service.Estimate(metricsClient.WithContext(ctx), pods, nodes, namespaces, window, groupBy)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
+	"github.com/k8s-dashboard/backend/internal/k8s"
+	batchv1 "k8s.io/api/batch/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+)
+
+// 手动触发的 Job 标记
+const (
+	cronJobTriggerLabel     = "k8s-dashboard/trigger"
+	cronJobTriggerManual    = "manual"
+	cronJobTriggeredByKey   = "k8s-dashboard/triggered-by"
+	cronJobTriggeredAtKey   = "k8s-dashboard/triggered-at"
+	cronJobInstantiateKey   = "cronjob.kubernetes.io/instantiate" // 与 kubectl create job --from 保持一致
+	cronJobTriggerScheduled = "scheduled"
+	cronJobRunStatusPending = "pending"
+)
+
+// 执行历史返回数量
+const (
+	cronJobRunsDefaultLimit = 50
+	cronJobRunsMaximumLimit = 200
+)
+
+// 手动 Job 名称：前缀截断长度与随机后缀长度，与 API Server 处理 generateName 的规则一致
+const (
+	manualJobNamePrefixLimit  = 58
+	manualJobNameSuffixLength = 5
+)
+
+// CronJobRun CronJob 的一次执行记录
+type CronJobRun struct {
+	Name            string       `json:"name"`
+	CreatedAt       metav1.Time  `json:"createdAt"`
+	Trigger         string       `json:"trigger"` // scheduled, manual
+	TriggeredBy     string       `json:"triggeredBy,omitempty"`
+	Status          string       `json:"status"` // pending, running, succeeded, failed
+	Message         string       `json:"message,omitempty"`
+	StartTime       *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime  *time.Time   `json:"completionTime,omitempty"`
+	DurationSeconds int64        `json:"durationSeconds"`
+	Active          int32        `json:"active"`
+	Succeeded       int32        `json:"succeeded"`
+	Failed          int32        `json:"failed"`
+}
+
+// TriggerCronJob 按 CronJob 模板手动创建 Job。已有未结束的手动 Job 时返回 409，force=true 时跳过检查；
+// 创建的 Job 带有触发标记与操作人注解，便于在执行历史中区分
+func (h *Handler) TriggerCronJob(c *gin.Context) {
+	ctx := requestContext(c)
+	namespace := c.Param("ns")
+	name := c.Param("name")
+	force := c.Query("force") == "true"
+
+	jobs := h.getK8s(c).Clientset.BatchV1().Jobs(namespace)
+	cj, err := h.getK8s(c).Clientset.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		writeError(c, http.StatusNotFound, err)
+		return
+	}
+
+	if !force {
+		list, err := jobs.List(ctx, metav1.ListOptions{LabelSelector: cronJobTriggerLabel + "=" + cronJobTriggerManual})
+		if err != nil {
+			writeError(c, http.StatusInternalServerError, err)
+			return
+		}
+		if running := runningManualJob(list.Items, cj, nil); running != nil {
+			writeManualJobConflict(c, running)
+			return
+		}
+	}
+
+	actor := "unknown"
+	if user := middleware.GetCurrentUser(c); user != nil {
+		actor = user.Username
+	}
+
+	labels := make(map[string]string, len(cj.Spec.JobTemplate.Labels)+1)
+	for k, v := range cj.Spec.JobTemplate.Labels {
+		labels[k] = v
+	}
+	labels[cronJobTriggerLabel] = cronJobTriggerManual
+
+	annotations := make(map[string]string, len(cj.Spec.JobTemplate.Annotations)+3)
+	for k, v := range cj.Spec.JobTemplate.Annotations {
+		annotations[k] = v
+	}
+	annotations[cronJobInstantiateKey] = cronJobTriggerManual
+	annotations[cronJobTriggeredByKey] = actor
+	annotations[cronJobTriggeredAtKey] = time.Now().UTC().Format(time.RFC3339)
+
+	// 创建一个新的 Job
+	isController := true
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        manualJobName(cj.Name),
+			Namespace:   namespace,
+			Labels:      labels,
+			Annotations: annotations,
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: "batch/v1",
+					Kind:       "CronJob",
+					Name:       cj.Name,
+					UID:        cj.UID,
+					Controller: &isController,
+				},
+			},
+		},
+		Spec: cj.Spec.JobTemplate.Spec,
+	}
+
+	// 名称冲突（AlreadyExists）由 writeError 映射为 409
+	result, err := jobs.Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	// 并发触发时多个请求都可能通过上面的检查：创建后再列一次，较晚创建的一方删除自己的 Job 并返回 409，
+	// 保证最终只保留最早创建的那个
+	if !force {
+		list, err := jobs.List(ctx, metav1.ListOptions{LabelSelector: cronJobTriggerLabel + "=" + cronJobTriggerManual})
+		if err != nil {
+			writeError(c, http.StatusInternalServerError, err)
+			return
+		}
+		if running := runningManualJob(list.Items, cj, result); running != nil {
+			propagation := metav1.DeletePropagationBackground
+			if err := jobs.Delete(ctx, result.Name, metav1.DeleteOptions{PropagationPolicy: &propagation}); err != nil && !apierrors.IsNotFound(err) {
+				writeError(c, http.StatusInternalServerError, err)
+				return
+			}
+			writeManualJobConflict(c, running)
+			return
+		}
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// manualJobName 生成手动触发的 Job 名称：随机后缀避免同一秒内的重复触发冲突
+func manualJobName(cronJob string) string {
+	return jobNamePrefix(cronJob+"-manual-") + utilrand.String(manualJobNameSuffixLength)
+}
+
+// jobNamePrefix 按 API Server 处理 generateName 的方式截断前缀，加上随机后缀后总长度不超过 63
+func jobNamePrefix(prefix string) string {
+	if len(prefix) > manualJobNamePrefixLimit {
+		return prefix[:manualJobNamePrefixLimit]
+	}
+	return prefix
+}
+
+// runningManualJob 返回属于该 CronJob、仍在运行的手动 Job。created 非空时只考虑比它更早创建的 Job
+// （创建时间相同按名称比较），用于创建后的二次检查；多个符合时返回最早的一个
+func runningManualJob(jobs []batchv1.Job, cj *batchv1.CronJob, created *batchv1.Job) *batchv1.Job {
+	var earliest *batchv1.Job
+	for i := range jobs {
+		job := &jobs[i]
+		if !ownedByCronJob(job, cj) || job.Labels[cronJobTriggerLabel] != cronJobTriggerManual {
+			continue
+		}
+		if created != nil && (job.UID == created.UID || !createdBefore(job, created)) {
+			continue
+		}
+		if status, _, _ := k8s.JobStatus(job); status != k8s.JobStatusRunning {
+			continue
+		}
+		if earliest == nil || createdBefore(job, earliest) {
+			earliest = job
+		}
+	}
+	return earliest
+}
+
+func createdBefore(a, b *batchv1.Job) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
+
+func writeManualJobConflict(c *gin.Context, running *batchv1.Job) {
+	c.JSON(http.StatusConflict, gin.H{
+		"error": fmt.Sprintf("手动触发的 Job %s 仍在运行，如需重复执行请使用 force=true", running.Name),
+		"job":   running.Name,
+	})
+}
+
+// ListCronJobRuns 列出 CronJob 的执行历史（定时与手动触发），按创建时间倒序。
+// 历史受 CronJob 的 successfulJobsHistoryLimit/failedJobsHistoryLimit 限制，limit 默认 50
+func (h *Handler) ListCronJobRuns(c *gin.Context) {
+	ctx := requestContext(c)
+	namespace := c.Param("ns")
+	name := c.Param("name")
+
+	limit := cronJobRunsDefaultLimit
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		limit = min(n, cronJobRunsMaximumLimit)
+	}
+
+	cj, err := h.getK8s(c).Clientset.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		writeError(c, http.StatusNotFound, err)
+		return
+	}
+	list, err := h.getK8s(c).Clientset.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	runs := []CronJobRun{}
+	for i := range list.Items {
+		job := &list.Items[i]
+		if ownedByCronJob(job, cj) {
+			runs = append(runs, cronJobRunFromJob(job))
+		}
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].CreatedAt.After(runs[j].CreatedAt.Time)
+	})
+
+	total := len(runs)
+	if len(runs) > limit {
+		runs = runs[:limit]
+	}
+	c.JSON(http.StatusOK, ListResponse{Items: runs, Total: total})
+}
+
+func ownedByCronJob(job *batchv1.Job, cj *batchv1.CronJob) bool {
+	for _, ref := range job.OwnerReferences {
+		if ref.Kind == "CronJob" && ref.UID == cj.UID {
+			return true
+		}
+	}
+	return false
+}
+
+func cronJobRunFromJob(job *batchv1.Job) CronJobRun {
+	run := CronJobRun{
+		Name:      job.Name,
+		CreatedAt: job.CreationTimestamp,
+		Trigger:   cronJobTriggerScheduled,
+		StartTime: job.Status.StartTime,
+		Active:    job.Status.Active,
+		Succeeded: job.Status.Succeeded,
+		Failed:    job.Status.Failed,
+	}
+	if job.Labels[cronJobTriggerLabel] == cronJobTriggerManual || job.Annotations[cronJobInstantiateKey] == cronJobTriggerManual {
+		run.Trigger = cronJobTriggerManual
+		run.TriggeredBy = job.Annotations[cronJobTriggeredByKey]
+	}
+
+	status, message, finishedAt := k8s.JobStatus(job)
+	run.Status, run.Message = status, message
+	if job.Status.CompletionTime != nil {
+		t := job.Status.CompletionTime.Time
+		finishedAt = &t
+	}
+	run.CompletionTime = finishedAt
+
+	if run.StartTime != nil {
+		end := time.Now()
+		if finishedAt != nil {
+			end = *finishedAt
+		}
+		run.DurationSeconds = int64(end.Sub(run.StartTime.Time).Seconds())
+	} else if status == k8s.JobStatusRunning {
+		// 已创建但 Pod 尚未启动
+		run.Status = cronJobRunStatusPending
+	}
+	return run
+}
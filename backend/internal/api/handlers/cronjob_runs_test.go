@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func TestManualJobName(t *testing.T) {
+	name := manualJobName("backup")
+	if !strings.HasPrefix(name, "backup-manual-") || len(name) != len("backup-manual-")+manualJobNameSuffixLength {
+		t.Fatalf("unexpected name %q", name)
+	}
+	if other := manualJobName("backup"); other == name {
+		t.Fatalf("names should differ between triggers, both %q", name)
+	}
+
+	long := manualJobName(strings.Repeat("a", 52))
+	if len(long) > validation.DNS1123LabelMaxLength {
+		t.Fatalf("name %q exceeds %d characters", long, validation.DNS1123LabelMaxLength)
+	}
+	if errs := validation.IsDNS1123Label(long); len(errs) != 0 {
+		t.Fatalf("name %q is not a valid label: %v", long, errs)
+	}
+}
+
+func TestRunningManualJob(t *testing.T) {
+	cj := &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "backup", UID: "cj-uid"}}
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	job := func(name string, offset time.Duration, manual bool, owner types.UID, done bool) batchv1.Job {
+		j := batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			UID:               types.UID(name + "-uid"),
+			CreationTimestamp: metav1.NewTime(base.Add(offset)),
+			OwnerReferences:   []metav1.OwnerReference{{Kind: "CronJob", Name: "backup", UID: owner}},
+		}}
+		if manual {
+			j.Labels = map[string]string{cronJobTriggerLabel: cronJobTriggerManual}
+		}
+		if done {
+			j.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+		}
+		return j
+	}
+
+	tests := []struct {
+		name    string
+		jobs    []batchv1.Job
+		created string
+		want    string
+	}{
+		{name: "no jobs"},
+		{
+			name: "finished and scheduled jobs ignored",
+			jobs: []batchv1.Job{job("done", 0, true, "cj-uid", true), job("scheduled", 0, false, "cj-uid", false)},
+		},
+		{
+			name: "other cronjob ignored",
+			jobs: []batchv1.Job{job("other", 0, true, "other-uid", false)},
+		},
+		{
+			name: "earliest running job returned",
+			jobs: []batchv1.Job{job("b", time.Second, true, "cj-uid", false), job("a", 2*time.Second, true, "cj-uid", false)},
+			want: "b",
+		},
+		{
+			name:    "created job does not conflict with itself",
+			jobs:    []batchv1.Job{job("mine", 0, true, "cj-uid", false)},
+			created: "mine",
+		},
+		{
+			name:    "later concurrent job yields to earlier one",
+			jobs:    []batchv1.Job{job("mine", time.Second, true, "cj-uid", false), job("theirs", 0, true, "cj-uid", false)},
+			created: "mine",
+			want:    "theirs",
+		},
+		{
+			name:    "earlier job keeps running when a later one appears",
+			jobs:    []batchv1.Job{job("mine", 0, true, "cj-uid", false), job("theirs", time.Second, true, "cj-uid", false)},
+			created: "mine",
+		},
+		{
+			name:    "same creation second breaks ties by name",
+			jobs:    []batchv1.Job{job("backup-manual-bbbbb", 0, true, "cj-uid", false), job("backup-manual-aaaaa", 0, true, "cj-uid", false)},
+			created: "backup-manual-bbbbb",
+			want:    "backup-manual-aaaaa",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *batchv1.Job
+			for i := range tt.jobs {
+				if tt.jobs[i].Name == tt.created {
+					created = &tt.jobs[i]
+				}
+			}
+			got := runningManualJob(tt.jobs, cj, created)
+			if tt.want == "" {
+				if got != nil {
+					t.Fatalf("got %s, want none", got.Name)
+				}
+				return
+			}
+			if got == nil || got.Name != tt.want {
+				t.Fatalf("got %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCronJobRunFromJob(t *testing.T) {
+	start := metav1.NewTime(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	completion := metav1.NewTime(start.Add(90 * time.Second))
+
+	pending := cronJobRunFromJob(&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "backup-28500000"}})
+	if pending.Status != cronJobRunStatusPending || pending.Trigger != cronJobTriggerScheduled {
+		t.Fatalf("unexpected pending run %+v", pending)
+	}
+
+	manual := cronJobRunFromJob(&batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "backup-manual-x7k2p",
+			Labels:      map[string]string{cronJobTriggerLabel: cronJobTriggerManual},
+			Annotations: map[string]string{cronJobTriggeredByKey: "alice"},
+		},
+		Status: batchv1.JobStatus{
+			StartTime:      &start,
+			CompletionTime: &completion,
+			Succeeded:      1,
+			Conditions:     []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue, LastTransitionTime: completion}},
+		},
+	})
+	if manual.Trigger != cronJobTriggerManual || manual.TriggeredBy != "alice" {
+		t.Fatalf("unexpected trigger %q by %q", manual.Trigger, manual.TriggeredBy)
+	}
+	if manual.Status != "succeeded" || manual.DurationSeconds != 90 {
+		t.Fatalf("unexpected status %q duration %d", manual.Status, manual.DurationSeconds)
+	}
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/k8s"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ErrorResponse 统一的错误响应。Code 为大写下划线形式的错误类别（与 CLUSTER_UNAVAILABLE 等一致），
+// Reason 为 Kubernetes 返回的 StatusReason；Error 与 Message 相同，保留给只读取 error 字段的调用方
+type ErrorResponse struct {
+	Error   string        `json:"error"`
+	Code    string        `json:"code"`
+	Reason  string        `json:"reason,omitempty"`
+	Message string        `json:"message"`
+	Details *ErrorDetails `json:"details,omitempty"`
+}
+
+// ErrorDetails Kubernetes 错误涉及的对象与字段级原因
+type ErrorDetails struct {
+	Group             string       `json:"group,omitempty"`
+	Kind              string       `json:"kind,omitempty"`
+	Name              string       `json:"name,omitempty"`
+	Causes            []ErrorCause `json:"causes,omitempty"`
+	RetryAfterSeconds int32        `json:"retryAfterSeconds,omitempty"`
+}
+
+// ErrorCause 单个字段的校验失败原因
+type ErrorCause struct {
+	Type    string `json:"type,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// writeError 写出错误响应。Kubernetes API 错误按 API Server 返回的状态码与原因映射（NotFound→404、
+// Forbidden→403、Conflict/AlreadyExists→409、Invalid→422 等），超时映射为 504，其它错误使用 fallback
+func writeError(c *gin.Context, fallback int, err error) {
+	status, resp := errorResponse(fallback, err)
+	c.JSON(status, resp)
+}
+
+// writeValidationErrors 以 422 返回请求体的字段级校验错误，结构与 API Server 的 Invalid 错误一致
+func writeValidationErrors(c *gin.Context, errs field.ErrorList) {
+	resp := ErrorResponse{
+		Code:    "INVALID",
+		Reason:  string(metav1.StatusReasonInvalid),
+		Message: errs.ToAggregate().Error(),
+		Details: &ErrorDetails{},
+	}
+	resp.Error = resp.Message
+	for _, err := range errs {
+		resp.Details.Causes = append(resp.Details.Causes, ErrorCause{Type: string(err.Type), Field: err.Field, Message: err.ErrorBody()})
+	}
+	c.JSON(http.StatusUnprocessableEntity, resp)
+}
+
+// validateObject 提交前校验请求体对象（见 k8s.ValidateObject），失败时写出 422 并返回 false
+func validateObject(c *gin.Context, obj runtime.Object, namespace, name string) bool {
+	if errs := k8s.ValidateObject(obj, namespace, name); len(errs) > 0 {
+		writeValidationErrors(c, errs)
+		return false
+	}
+	return true
+}
+
+// decodeManifest 严格解析 YAML 请求体（见 k8s.DecodeManifest），失败时写出 422 并返回 false
+func decodeManifest(c *gin.Context, data []byte, into runtime.Object) bool {
+	if errs := k8s.DecodeManifest(data, into); len(errs) > 0 {
+		writeValidationErrors(c, errs)
+		return false
+	}
+	return true
+}
+
+func errorResponse(fallback int, err error) (int, ErrorResponse) {
+	resp := ErrorResponse{Error: err.Error(), Message: err.Error()}
+	status := fallback
+
+	var apiStatus apierrors.APIStatus
+	switch {
+	case errors.As(err, &apiStatus):
+		s := apiStatus.Status()
+		if s.Code > 0 {
+			status = int(s.Code)
+		}
+		resp.Reason = string(s.Reason)
+		if s.Details != nil {
+			details := &ErrorDetails{
+				Group:             s.Details.Group,
+				Kind:              s.Details.Kind,
+				Name:              s.Details.Name,
+				RetryAfterSeconds: s.Details.RetryAfterSeconds,
+			}
+			for _, cause := range s.Details.Causes {
+				details.Causes = append(details.Causes, ErrorCause{Type: string(cause.Type), Field: cause.Field, Message: cause.Message})
+			}
+			resp.Details = details
+		}
+		// 集群凭据失效不是当前用户未登录，不能返回 401，否则前端会直接登出
+		if status == http.StatusUnauthorized {
+			status = http.StatusBadGateway
+			resp.Code = "UPSTREAM_UNAUTHORIZED"
+		}
+	case errors.Is(err, context.DeadlineExceeded):
+		status = http.StatusGatewayTimeout
+		resp.Reason = "Timeout"
+	}
+
+	if resp.Code == "" {
+		if resp.Reason != "" {
+			resp.Code = upperSnake(resp.Reason)
+		} else {
+			resp.Code = upperSnake(http.StatusText(status))
+		}
+	}
+	return status, resp
+}
+
+// upperSnake 将 "NotFound"、"Not Found" 转换为 "NOT_FOUND"
+func upperSnake(s string) string {
+	var b strings.Builder
+	prevLower := false
+	for _, r := range s {
+		switch {
+		case r == ' ' || r == '-':
+			b.WriteByte('_')
+			prevLower = false
+			continue
+		case unicode.IsUpper(r) && prevLower:
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+		prevLower = unicode.IsLower(r) || unicode.IsDigit(r)
+	}
+	if b.Len() == 0 {
+		return "ERROR"
+	}
+	return b.String()
+}
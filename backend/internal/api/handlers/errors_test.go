@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestErrorResponse(t *testing.T) {
+	deployments := schema.GroupResource{Group: "apps", Resource: "deployments"}
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantReason string
+	}{
+		{
+			name:       "not found",
+			err:        apierrors.NewNotFound(deployments, "web"),
+			wantStatus: http.StatusNotFound, wantCode: "NOT_FOUND", wantReason: "NotFound",
+		},
+		{
+			name:       "forbidden",
+			err:        apierrors.NewForbidden(deployments, "web", errors.New("rbac denied")),
+			wantStatus: http.StatusForbidden, wantCode: "FORBIDDEN", wantReason: "Forbidden",
+		},
+		{
+			name:       "conflict",
+			err:        apierrors.NewConflict(deployments, "web", errors.New("object has been modified")),
+			wantStatus: http.StatusConflict, wantCode: "CONFLICT", wantReason: "Conflict",
+		},
+		{
+			name:       "already exists",
+			err:        apierrors.NewAlreadyExists(deployments, "web"),
+			wantStatus: http.StatusConflict, wantCode: "ALREADY_EXISTS", wantReason: "AlreadyExists",
+		},
+		{
+			name: "invalid",
+			err: apierrors.NewInvalid(schema.GroupKind{Group: "apps", Kind: "Deployment"}, "web", field.ErrorList{
+				field.Required(field.NewPath("spec", "selector"), ""),
+			}),
+			wantStatus: http.StatusUnprocessableEntity, wantCode: "INVALID", wantReason: "Invalid",
+		},
+		{
+			name:       "api server timeout",
+			err:        apierrors.NewTimeoutError("request did not complete", 2),
+			wantStatus: http.StatusGatewayTimeout, wantCode: "TIMEOUT", wantReason: "Timeout",
+		},
+		{
+			name:       "context deadline",
+			err:        fmt.Errorf("list pods: %w", context.DeadlineExceeded),
+			wantStatus: http.StatusGatewayTimeout, wantCode: "TIMEOUT", wantReason: "Timeout",
+		},
+		{
+			name:       "upstream unauthorized is not a login failure",
+			err:        apierrors.NewUnauthorized("token expired"),
+			wantStatus: http.StatusBadGateway, wantCode: "UPSTREAM_UNAUTHORIZED", wantReason: "Unauthorized",
+		},
+		{
+			name:       "plain error uses fallback",
+			err:        errors.New("boom"),
+			wantStatus: http.StatusInternalServerError, wantCode: "INTERNAL_SERVER_ERROR",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := errorResponse(http.StatusInternalServerError, tt.err)
+			if status != tt.wantStatus || resp.Code != tt.wantCode || resp.Reason != tt.wantReason {
+				t.Fatalf("errorResponse = (%d, code %q, reason %q), want (%d, %q, %q)",
+					status, resp.Code, resp.Reason, tt.wantStatus, tt.wantCode, tt.wantReason)
+			}
+			if resp.Error != tt.err.Error() || resp.Message != resp.Error {
+				t.Fatalf("error = %q, message = %q, want %q", resp.Error, resp.Message, tt.err.Error())
+			}
+		})
+	}
+}
+
+func TestErrorResponseDetails(t *testing.T) {
+	err := apierrors.NewInvalid(schema.GroupKind{Group: "apps", Kind: "Deployment"}, "web", field.ErrorList{
+		field.Invalid(field.NewPath("spec", "replicas"), -1, "must be greater than or equal to 0"),
+	})
+	_, resp := errorResponse(http.StatusInternalServerError, err)
+	if resp.Details == nil || resp.Details.Kind != "Deployment" || resp.Details.Name != "web" || len(resp.Details.Causes) != 1 {
+		t.Fatalf("details = %+v", resp.Details)
+	}
+	if cause := resp.Details.Causes[0]; cause.Field != "spec.replicas" || cause.Type != string(field.ErrorTypeInvalid) {
+		t.Fatalf("cause = %+v", cause)
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// writeList 写出列表响应（支持 ?view=summary 精简视图，见 listView），并按列表中各对象的 UID 与 resourceVersion 生成弱 ETag；
+// 请求的 If-None-Match 命中时返回 304，轮询时列表未变化不再重复传输。
+// 列表的 resourceVersion 随集群内任意写入变化，不适合作为缓存标识，因此使用对象自身的版本
+func writeList(c *gin.Context, resp ListResponse) {
+	if etag := listETag(c, resp); etag != "" {
+		c.Header("ETag", etag)
+		// 允许浏览器缓存，但每次使用前必须重新验证
+		c.Header("Cache-Control", "private, no-cache")
+		if etagMatches(c.GetHeader("If-None-Match"), etag) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+	c.JSON(http.StatusOK, listView(c, resp))
+}
+
+// listETag 计算列表的 ETag，除对象版本外还包含集群、用户与请求参数（同一路径在不同集群或不同脱敏视图下内容不同）；
+// 列表元素不是 Kubernetes 对象时返回空，不启用条件请求
+func listETag(c *gin.Context, resp ListResponse) string {
+	items := reflect.ValueOf(resp.Items)
+	if items.Kind() != reflect.Slice {
+		return ""
+	}
+
+	hash := sha256.New()
+	write := func(parts ...string) {
+		for _, part := range parts {
+			hash.Write([]byte(part))
+			hash.Write([]byte{0})
+		}
+	}
+	user := ""
+	if current := middleware.GetCurrentUser(c); current != nil {
+		user = strconv.FormatInt(current.ID, 10) + "/" + current.Role
+	}
+	write(middleware.GetClusterName(c), user, c.Request.URL.RequestURI(), strconv.Itoa(resp.Total), resp.Continue)
+
+	for i := 0; i < items.Len(); i++ {
+		item := items.Index(i)
+		if item.CanAddr() {
+			item = item.Addr()
+		}
+		obj, err := meta.Accessor(item.Interface())
+		if err != nil || obj.GetResourceVersion() == "" {
+			return ""
+		}
+		write(string(obj.GetUID()), obj.GetResourceVersion())
+	}
+	return `W/"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+}
+
+// etagMatches 按弱比较判断 If-None-Match 是否命中，支持逗号分隔的多个值与 *
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	want := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
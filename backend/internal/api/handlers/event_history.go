@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
+	"github.com/k8s-dashboard/backend/internal/eventstore"
+)
+
+// EventHistoryHandler 事件历史处理器
+type EventHistoryHandler struct {
+	h    *Handler
+	repo *eventstore.Repository
+}
+
+// NewEventHistoryHandler 创建事件历史处理器
+func NewEventHistoryHandler(h *Handler, repo *eventstore.Repository) *EventHistoryHandler {
+	return &EventHistoryHandler{h: h, repo: repo}
+}
+
+// GetEventHistory 查询持久化的历史事件（不受 Kubernetes 事件 1 小时过期限制），
+// 支持 namespace、type、reason、kind、name 精确过滤，q 模糊搜索，startTime/endTime（RFC3339）时间范围
+func (e *EventHistoryHandler) GetEventHistory(c *gin.Context) {
+	if e.repo == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "事件历史功能未启用"})
+		return
+	}
+
+	scope, err := e.h.getNamespaceAccessScope(c)
+	if err != nil {
+		writeError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	params := eventstore.QueryParams{
+		Cluster:   middleware.GetClusterName(c),
+		Namespace: c.Query("namespace"),
+		Type:      c.Query("type"),
+		Reason:    c.Query("reason"),
+		Kind:      c.Query("kind"),
+		Name:      c.Query("name"),
+		Search:    c.Query("q"),
+	}
+	if params.Cluster == "" {
+		params.Cluster = "default"
+	}
+	params.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	params.PageSize, _ = strconv.Atoi(c.DefaultQuery("pageSize", "50"))
+
+	if v := c.Query("startTime"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "startTime 必须是 RFC3339 格式"})
+			return
+		}
+		params.Since = t
+	}
+	if v := c.Query("endTime"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "endTime 必须是 RFC3339 格式"})
+			return
+		}
+		params.Until = t
+	}
+
+	if !scope.unrestricted {
+		if params.Namespace != "" && !namespaceAllowed(scope, params.Namespace) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "无权访问该命名空间"})
+			return
+		}
+		if len(scope.allowed) == 0 {
+			c.JSON(http.StatusOK, gin.H{"items": []eventstore.Record{}, "total": 0})
+			return
+		}
+		params.Namespaces = scope.allowed
+	}
+
+	items, total, err := e.repo.Query(params)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items": items,
+		"total": total,
+	})
+}
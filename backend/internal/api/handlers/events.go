@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
+	"github.com/k8s-dashboard/backend/internal/auth"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const (
+	eventStreamPingInterval = 30 * time.Second
+	eventStreamWriteTimeout = 10 * time.Second
+)
+
+// eventStreamFilter 事件流过滤条件，每项为逗号分隔的多个值，空表示不过滤
+type eventStreamFilter struct {
+	types   map[string]bool
+	reasons map[string]bool
+	kinds   map[string]bool
+}
+
+// eventStreamMessage 推送给客户端的消息，type 为 event 或 error
+type eventStreamMessage struct {
+	Type    string        `json:"type"`
+	Action  string        `json:"action,omitempty"`
+	Event   *corev1.Event `json:"event,omitempty"`
+	Message string        `json:"message,omitempty"`
+}
+
+func parseEventStreamFilter(c *gin.Context) eventStreamFilter {
+	return eventStreamFilter{
+		types:   splitFilterValues(c.Query("type")),
+		reasons: splitFilterValues(c.Query("reason")),
+		kinds:   splitFilterValues(c.Query("kind")),
+	}
+}
+
+func splitFilterValues(raw string) map[string]bool {
+	values := make(map[string]bool)
+	for _, v := range strings.Split(raw, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values[v] = true
+		}
+	}
+	return values
+}
+
+// fieldSelector 单值条件下推给 API Server 过滤，多值条件在本地匹配
+func (f eventStreamFilter) fieldSelector() string {
+	set := fields.Set{}
+	for field, values := range map[string]map[string]bool{
+		"type":                f.types,
+		"reason":              f.reasons,
+		"involvedObject.kind": f.kinds,
+	} {
+		if len(values) == 1 {
+			for v := range values {
+				set[field] = v
+			}
+		}
+	}
+	if len(set) == 0 {
+		return ""
+	}
+	return fields.SelectorFromSet(set).String()
+}
+
+func (f eventStreamFilter) matches(event *corev1.Event) bool {
+	if len(f.types) > 0 && !f.types[event.Type] {
+		return false
+	}
+	if len(f.reasons) > 0 && !f.reasons[event.Reason] {
+		return false
+	}
+	if len(f.kinds) > 0 && !f.kinds[event.InvolvedObject.Kind] {
+		return false
+	}
+	return true
+}
+
+// eventStreamUser 票据链路中上下文没有当前用户，需要按票据中的用户 ID 查询，
+// 并恢复签发票据时 API 令牌收窄后的角色与命名空间范围
+func (h *Handler) eventStreamUser(c *gin.Context) (*auth.User, error) {
+	if user := middleware.GetCurrentUser(c); user != nil {
+		return user, nil
+	}
+	ticket := middleware.GetWSTicket(c)
+	if ticket == nil || h.auth == nil {
+		return nil, fmt.Errorf("unauthenticated")
+	}
+	user, err := h.auth.GetUserByID(ticket.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if ticket.Role != "" && !middleware.RoleAtLeast(ticket.Role, user.Role) {
+		user.Role = ticket.Role
+	}
+	if ticket.ScopedNamespaces != nil {
+		user.AllNamespaces = false
+		user.ScopedNamespaces = ticket.ScopedNamespaces
+	}
+	return user, nil
+}
+
+// eventStreamScope 返回可见命名空间集合，nil 表示不限制
+func (h *Handler) eventStreamScope(user *auth.User) (map[string]bool, error) {
+	if user.Role == "admin" || user.AllNamespaces {
+		return nil, nil
+	}
+	if h.auth == nil {
+		return nil, fmt.Errorf("auth service not available")
+	}
+	namespaces, err := h.auth.NamespaceGrants(user)
+	if err != nil {
+		return nil, err
+	}
+	allowed := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		if ns.Namespace != "" {
+			allowed[ns.Namespace] = true
+		}
+	}
+	return allowed, nil
+}
+
+// StreamEvents 通过 WebSocket 实时推送新产生的集群事件，
+// 支持按 namespace、type（Warning/Normal）、reason、kind（涉及对象类型）过滤
+func (h *Handler) StreamEvents(c *gin.Context) {
+	namespace := c.Query("namespace")
+	if ticket := middleware.GetWSTicket(c); ticket != nil {
+		namespace = ticket.Namespace
+	}
+	filter := parseEventStreamFilter(c)
+
+	user, err := h.eventStreamUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未认证"})
+		return
+	}
+	allowed, err := h.eventStreamScope(user)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if allowed != nil && namespace != "" && !allowed[namespace] {
+		c.JSON(http.StatusForbidden, gin.H{"error": "无权访问该命名空间"})
+		return
+	}
+
+	events := h.getK8s(c).Clientset.CoreV1().Events(namespace)
+	selector := filter.fieldSelector()
+	// 先取当前 resourceVersion，只推送连接建立之后的事件
+	initial, err := events.List(c.Request.Context(), metav1.ListOptions{FieldSelector: selector, Limit: 1})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool {
+			return h.isAllowedWSOrigin(r)
+		},
+	}
+	ws, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer ws.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if maxDuration := h.wsSessionLimits().MaxDuration; maxDuration > 0 {
+		ctx, cancel = context.WithTimeout(ctx, maxDuration)
+		defer cancel()
+	}
+
+	// 只读连接，读循环仅用于感知客户端断开
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	send := func(msg eventStreamMessage) error {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		ws.SetWriteDeadline(time.Now().Add(eventStreamWriteTimeout))
+		return ws.WriteMessage(websocket.TextMessage, data)
+	}
+
+	err = streamEvents(ctx, events, selector, initial.ResourceVersion, func(action watch.EventType, event *corev1.Event) error {
+		if allowed != nil && !allowed[event.Namespace] {
+			return nil
+		}
+		if !filter.matches(event) {
+			return nil
+		}
+		return send(eventStreamMessage{Type: "event", Action: string(action), Event: event})
+	}, func() error {
+		ws.SetWriteDeadline(time.Now().Add(eventStreamWriteTimeout))
+		return ws.WriteMessage(websocket.PingMessage, nil)
+	})
+	if err != nil && ctx.Err() == nil {
+		send(eventStreamMessage{Type: "error", Message: err.Error()})
+	}
+	ws.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+		time.Now().Add(time.Second))
+}
+
+// streamEvents 持续 watch 事件，watch 中断后从最后的 resourceVersion 续接，
+// resourceVersion 过期时重新获取最新版本（期间的事件会丢失）
+func streamEvents(ctx context.Context, events typedcorev1.EventInterface, selector, resourceVersion string,
+	handle func(watch.EventType, *corev1.Event) error, ping func() error) error {
+	ticker := time.NewTicker(eventStreamPingInterval)
+	defer ticker.Stop()
+
+	for {
+		w, err := events.Watch(ctx, metav1.ListOptions{
+			FieldSelector:       selector,
+			ResourceVersion:     resourceVersion,
+			AllowWatchBookmarks: true,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+	loop:
+		for {
+			select {
+			case <-ctx.Done():
+				w.Stop()
+				return nil
+			case <-ticker.C:
+				if err := ping(); err != nil {
+					w.Stop()
+					return nil
+				}
+			case ev, ok := <-w.ResultChan():
+				if !ok {
+					break loop
+				}
+				switch ev.Type {
+				case watch.Error:
+					w.Stop()
+					status := apierrors.FromObject(ev.Object)
+					if !apierrors.IsResourceExpired(status) && !apierrors.IsGone(status) {
+						return status
+					}
+					list, err := events.List(ctx, metav1.ListOptions{FieldSelector: selector, Limit: 1})
+					if err != nil {
+						if ctx.Err() != nil {
+							return nil
+						}
+						return err
+					}
+					resourceVersion = list.ResourceVersion
+					break loop
+				case watch.Bookmark:
+					if event, ok := ev.Object.(*corev1.Event); ok {
+						resourceVersion = event.ResourceVersion
+					}
+				case watch.Added, watch.Modified:
+					event, ok := ev.Object.(*corev1.Event)
+					if !ok {
+						continue
+					}
+					resourceVersion = event.ResourceVersion
+					if err := handle(ev.Type, event); err != nil {
+						w.Stop()
+						return nil
+					}
+				}
+			}
+		}
+
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
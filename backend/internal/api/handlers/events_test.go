@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
+	"github.com/k8s-dashboard/backend/internal/auth"
+	dbutil "github.com/k8s-dashboard/backend/internal/db"
+)
+
+// newScopedTokenTicket 为全命名空间 operator 创建仅限 shop 命名空间的 viewer 令牌，并用其签发事件流票据
+func newScopedTokenTicket(t *testing.T, namespace string) (*auth.Client, *middleware.WSTicket) {
+	t.Helper()
+	conn, dialect, err := dbutil.Open(dbutil.Config{
+		SQLitePath:          filepath.Join(t.TempDir(), "auth.db"),
+		AllowSQLiteFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	client, err := auth.NewClient(conn, dialect, "test-secret")
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	owner, err := client.CreateUser(&auth.CreateUserRequest{
+		Username:      "carol",
+		Password:      "Passw0rd!",
+		Role:          "operator",
+		AllNamespaces: true,
+	})
+	if err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	_, plaintext, err := client.CreateAPIToken(owner, &auth.CreateAPITokenRequest{
+		Name:       "ci",
+		Role:       "viewer",
+		Namespaces: []string{"shop"},
+	})
+	if err != nil {
+		t.Fatalf("CreateAPIToken failed: %v", err)
+	}
+	tokenUser, _, err := client.ValidateAPIToken(plaintext)
+	if err != nil {
+		t.Fatalf("ValidateAPIToken failed: %v", err)
+	}
+	ticket, err := middleware.IssueWSTicket(tokenUser, middleware.WSTicketRequest{Action: "events", Namespace: namespace})
+	if err != nil {
+		t.Fatalf("IssueWSTicket failed: %v", err)
+	}
+	return client, ticket
+}
+
+func TestEventStreamTicketKeepsTokenScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	client, ticket := newScopedTokenTicket(t, "")
+	h := NewHandler(nil, nil, nil, nil, nil, nil, client, Options{})
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Set(middleware.ContextWSTicketKey, ticket)
+	user, err := h.eventStreamUser(c)
+	if err != nil {
+		t.Fatalf("eventStreamUser failed: %v", err)
+	}
+	if user.Role != "viewer" || user.AllNamespaces {
+		t.Fatalf("user role %q allNamespaces %v, want token scope", user.Role, user.AllNamespaces)
+	}
+	allowed, err := h.eventStreamScope(user)
+	if err != nil {
+		t.Fatalf("eventStreamScope failed: %v", err)
+	}
+	if want := map[string]bool{"shop": true}; !reflect.DeepEqual(allowed, want) {
+		t.Fatalf("allowed = %v, want %v", allowed, want)
+	}
+}
+
+func TestStreamEventsRejectsNamespaceOutsideTokenScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	client, ticket := newScopedTokenTicket(t, "ops")
+	h := NewHandler(nil, nil, nil, nil, nil, nil, client, Options{})
+	router := gin.New()
+	router.GET("/ws/events", func(c *gin.Context) {
+		c.Set(middleware.ContextWSTicketKey, ticket)
+		c.Next()
+	}, h.StreamEvents)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ws/events", nil))
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// exportedObject 待导出的资源
+type exportedObject struct {
+	dir  string
+	name string
+	gvk  schema.GroupVersionKind
+	obj  interface{}
+}
+
+// 导出时移除的集群生成字段
+var exportStrippedMetadata = []string{
+	"managedFields", "uid", "resourceVersion", "generation", "creationTimestamp",
+	"selfLink", "ownerReferences", "deletionTimestamp", "deletionGracePeriodSeconds",
+}
+
+// 导出时移除的注解
+var exportStrippedAnnotations = []string{
+	"kubectl.kubernetes.io/last-applied-configuration",
+	"kubectl.kubernetes.io/restartedAt",
+	"deployment.kubernetes.io/revision",
+}
+
+// ExportNamespace 将命名空间下的工作负载、Service、ConfigMap 与 Ingress 清理后打包为 zip，
+// 可直接作为 GitOps 仓库的初始清单；format=json 时输出 JSON，默认 YAML
+func (h *Handler) ExportNamespace(c *gin.Context) {
+	ctx := requestContext(c)
+	namespace := c.Param("ns")
+	format := c.DefaultQuery("format", "yaml")
+	if format != "yaml" && format != "json" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be yaml or json"})
+		return
+	}
+
+	clientset := h.getK8s(c).Clientset
+	if _, err := clientset.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{}); err != nil {
+		writeError(c, http.StatusNotFound, err)
+		return
+	}
+
+	// 先全部列出再开始写响应，任一类资源失败时仍可返回 JSON 错误
+	var objects []exportedObject
+	deployments, err := clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	for i := range deployments.Items {
+		item := &deployments.Items[i]
+		objects = append(objects, exportedObject{"deployments", item.Name, schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, item})
+	}
+
+	statefulSets, err := clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	for i := range statefulSets.Items {
+		item := &statefulSets.Items[i]
+		objects = append(objects, exportedObject{"statefulsets", item.Name, schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"}, item})
+	}
+
+	daemonSets, err := clientset.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	for i := range daemonSets.Items {
+		item := &daemonSets.Items[i]
+		objects = append(objects, exportedObject{"daemonsets", item.Name, schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "DaemonSet"}, item})
+	}
+
+	cronJobs, err := clientset.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	for i := range cronJobs.Items {
+		item := &cronJobs.Items[i]
+		objects = append(objects, exportedObject{"cronjobs", item.Name, schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "CronJob"}, item})
+	}
+
+	services, err := clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	for i := range services.Items {
+		item := &services.Items[i]
+		objects = append(objects, exportedObject{"services", item.Name, schema.GroupVersionKind{Version: "v1", Kind: "Service"}, item})
+	}
+
+	configMaps, err := clientset.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	for i := range configMaps.Items {
+		item := &configMaps.Items[i]
+		// 集群自动注入的 CA 证书不属于应用清单
+		if item.Name == "kube-root-ca.crt" {
+			continue
+		}
+		objects = append(objects, exportedObject{"configmaps", item.Name, schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, item})
+	}
+
+	ingresses, err := clientset.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	for i := range ingresses.Items {
+		item := &ingresses.Items[i]
+		objects = append(objects, exportedObject{"ingresses", item.Name, schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}, item})
+	}
+
+	stamp := time.Now().Format("20060102-150405")
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s.zip"`, namespace, stamp))
+	c.Status(http.StatusOK)
+
+	zw := zip.NewWriter(c.Writer)
+	defer zw.Close()
+
+	for _, item := range objects {
+		data, err := cleanExportManifest(item, format)
+		if err != nil {
+			log.Printf("导出 %s/%s/%s 失败: %v", namespace, item.dir, item.name, err)
+			continue
+		}
+		entry, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     fmt.Sprintf("%s/%s/%s.%s", namespace, item.dir, item.name, format),
+			Method:   zip.Deflate,
+			Modified: time.Now(),
+		})
+		if err != nil {
+			log.Printf("打包命名空间 %s 清单中断: %v", namespace, err)
+			return
+		}
+		if _, err := entry.Write(data); err != nil {
+			log.Printf("打包命名空间 %s 清单中断: %v", namespace, err)
+			return
+		}
+	}
+}
+
+// cleanExportManifest 去掉 status 与集群生成的元数据，补全 apiVersion/kind 后序列化
+func cleanExportManifest(item exportedObject, format string) ([]byte, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(item.obj)
+	if err != nil {
+		return nil, err
+	}
+
+	obj["apiVersion"] = item.gvk.GroupVersion().String()
+	obj["kind"] = item.gvk.Kind
+	delete(obj, "status")
+
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		for _, key := range exportStrippedMetadata {
+			delete(metadata, key)
+		}
+		if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+			for _, key := range exportStrippedAnnotations {
+				delete(annotations, key)
+			}
+			if len(annotations) == 0 {
+				delete(metadata, "annotations")
+			}
+		}
+	}
+
+	// 清单中的 Pod 模板带有控制器注入的重启注解时一并清理
+	if spec, ok := obj["spec"].(map[string]interface{}); ok {
+		if template, ok := spec["template"].(map[string]interface{}); ok {
+			if metadata, ok := template["metadata"].(map[string]interface{}); ok {
+				delete(metadata, "creationTimestamp")
+				if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+					delete(annotations, "kubectl.kubernetes.io/restartedAt")
+					if len(annotations) == 0 {
+						delete(metadata, "annotations")
+					}
+				}
+			}
+		}
+		// Service 的 ClusterIP 由集群分配，迁移到新集群时必须留空（Headless 的 None 保留）
+		if item.gvk.Kind == "Service" {
+			if ip, _ := spec["clusterIP"].(string); !strings.EqualFold(ip, "None") {
+				delete(spec, "clusterIP")
+				delete(spec, "clusterIPs")
+			}
+		}
+	}
+
+	if format == "json" {
+		return json.MarshalIndent(obj, "", "  ")
+	}
+	return yaml.Marshal(obj)
+}
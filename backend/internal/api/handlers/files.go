@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
+	"github.com/k8s-dashboard/backend/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// 容器文件传输大小限制
+const (
+	maxFileDownloadBytes = 100 << 20 // 100MB
+	maxFileUploadBytes   = 50 << 20  // 50MB
+	fileTransferTimeout  = 5 * time.Minute
+)
+
+var errFileTooLarge = errors.New("file exceeds size limit")
+
+// cleanContainerPath 校验并规范化容器内路径，只接受绝对路径
+func cleanContainerPath(p string) (string, error) {
+	p = strings.TrimSpace(p)
+	if p == "" || !strings.HasPrefix(p, "/") {
+		return "", fmt.Errorf("path must be an absolute path")
+	}
+	cleaned := path.Clean(p)
+	if cleaned == "/" {
+		return "", fmt.Errorf("path must not be the root directory")
+	}
+	return cleaned, nil
+}
+
+// streamPodExec 在容器中执行命令并连接 stdin/stdout，stderr 收集后随错误返回
+func (h *Handler) streamPodExec(ctx context.Context, c *gin.Context, namespace, pod, container string, command []string, stdin io.Reader, stdout io.Writer) error {
+	return podExec(ctx, h.getK8s(c), namespace, pod, container, command, stdin, stdout)
+}
+
+// podExec 同 streamPodExec，供脱离请求上下文的后台任务使用
+func podExec(ctx context.Context, client *k8s.Client, namespace, pod, container string, command []string, stdin io.Reader, stdout io.Writer) error {
+	req := client.Clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdin:     stdin != nil,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	exec, err := remotecommand.NewSPDYExecutor(client.Config, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+
+	var stderr bytes.Buffer
+	err = exec.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: &stderr,
+	})
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%v: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// extendTransferDeadlines 将连接的读写截止时间延长到 fileTransferTimeout，
+// 否则服务器 15s 的 ReadTimeout/WriteTimeout 会中断大文件的上传与下载
+func extendTransferDeadlines(c *gin.Context) {
+	rc := http.NewResponseController(c.Writer)
+	deadline := time.Now().Add(fileTransferTimeout)
+	// 不支持设置截止时间的 ResponseWriter（如测试中的 Recorder）沿用服务器超时
+	_ = rc.SetReadDeadline(deadline)
+	_ = rc.SetWriteDeadline(deadline)
+}
+
+// limitedWriter 写入超过上限时返回 errFileTooLarge，已写入的部分保留在 w 中
+type limitedWriter struct {
+	w       io.Writer
+	written int64
+	limit   int64
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if w.written+int64(len(p)) > w.limit {
+		return 0, errFileTooLarge
+	}
+	n, err := w.w.Write(p)
+	w.written += int64(n)
+	return n, err
+}
+
+// DownloadPodFile 以 tar 格式从容器中下载文件或目录（等价于 kubectl cp）。
+// 归档先写入临时文件，完整读取且未超过上限后才发送响应，超限返回 413，不会返回被截断的 200
+func (h *Handler) DownloadPodFile(c *gin.Context) {
+	namespace := c.Param("ns")
+	name := c.Param("name")
+	container := c.Query("container")
+
+	srcPath, err := cleanContainerPath(c.Query("path"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+	middleware.SetAuditDetail(c, "download "+srcPath)
+	extendTransferDeadlines(c)
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), fileTransferTimeout)
+	defer cancel()
+
+	spool, err := os.CreateTemp("", "pod-download-*.tar")
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	dir, base := path.Split(srcPath)
+	writer := &limitedWriter{w: spool, limit: maxFileDownloadBytes}
+	err = h.streamPodExec(ctx, c, namespace, name, container, []string{"tar", "cf", "-", "-C", dir, base}, nil, writer)
+	if errors.Is(err, errFileTooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("file exceeds download limit of %d bytes", maxFileDownloadBytes)})
+		return
+	}
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.DataFromReader(http.StatusOK, writer.written, "application/x-tar", spool, map[string]string{
+		"Content-Disposition": fmt.Sprintf("attachment; filename=%q", base+".tar"),
+	})
+}
+
+// UploadPodFile 将上传的文件通过 tar 写入容器指定目录
+func (h *Handler) UploadPodFile(c *gin.Context) {
+	namespace := c.Param("ns")
+	name := c.Param("name")
+	container := c.Query("container")
+
+	destDir, err := cleanContainerPath(c.DefaultPostForm("path", c.Query("path")))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	extendTransferDeadlines(c)
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxFileUploadBytes+1<<20)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("file exceeds upload limit of %d bytes", maxFileUploadBytes)})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if fileHeader.Size > maxFileUploadBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("file exceeds upload limit of %d bytes", maxFileUploadBytes)})
+		return
+	}
+
+	filename := path.Base(strings.ReplaceAll(fileHeader.Filename, "\\", "/"))
+	if filename == "." || filename == "/" || filename == ".." {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid file name"})
+		return
+	}
+	middleware.SetAuditDetail(c, "upload "+path.Join(destDir, filename))
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	defer file.Close()
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), fileTransferTimeout)
+	defer cancel()
+
+	pr, pw := io.Pipe()
+	go func() {
+		tw := tar.NewWriter(pw)
+		err := tw.WriteHeader(&tar.Header{
+			Name:    filename,
+			Mode:    0644,
+			Size:    fileHeader.Size,
+			ModTime: time.Now(),
+		})
+		if err == nil {
+			_, err = io.Copy(tw, file)
+		}
+		if err == nil {
+			err = tw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	err = h.streamPodExec(ctx, c, namespace, name, container, []string{"tar", "xf", "-", "-C", destDir}, pr, io.Discard)
+	pr.Close()
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "uploaded",
+		"path":    path.Join(destDir, filename),
+		"size":    fileHeader.Size,
+	})
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
+)
+
+func TestLimitedWriterRejectsWritesPastLimit(t *testing.T) {
+	var buf bytes.Buffer
+	w := &limitedWriter{w: &buf, limit: 8}
+
+	if n, err := w.Write([]byte("12345")); n != 5 || err != nil {
+		t.Fatalf("first write = (%d, %v)", n, err)
+	}
+	if n, err := w.Write([]byte("678")); n != 3 || err != nil {
+		t.Fatalf("write up to the limit = (%d, %v)", n, err)
+	}
+	if n, err := w.Write([]byte("9")); n != 0 || !errors.Is(err, errFileTooLarge) {
+		t.Fatalf("write past the limit = (%d, %v), want errFileTooLarge", n, err)
+	}
+	if buf.String() != "12345678" || w.written != 8 {
+		t.Fatalf("buffer = %q, written = %d", buf.String(), w.written)
+	}
+}
+
+func TestCleanContainerPath(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "/var/log/app.log", want: "/var/log/app.log"},
+		{in: " /tmp/../etc/hosts ", want: "/etc/hosts"},
+		{in: "/data/", want: "/data"},
+		{in: "relative/path", wantErr: true},
+		{in: "", wantErr: true},
+		{in: "/", wantErr: true},
+		{in: "/tmp/..", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := cleanContainerPath(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("cleanContainerPath(%q) = (%q, %v), wantErr %v want %q", tt.in, got, err, tt.wantErr, tt.want)
+		}
+	}
+}
+
+func TestExtendTransferDeadlinesOutlivesServerTimeouts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const serverTimeout = 100 * time.Millisecond
+	r := gin.New()
+	r.Use(middleware.Gzip())
+	r.POST("/api/v2/transfer", func(c *gin.Context) {
+		extendTransferDeadlines(c)
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		time.Sleep(2 * serverTimeout)
+		c.String(http.StatusOK, string(body))
+	})
+	server := httptest.NewUnstartedServer(r)
+	server.Config.ReadTimeout = serverTimeout
+	server.Config.WriteTimeout = serverTimeout
+	server.Start()
+	t.Cleanup(server.Close)
+
+	// 请求体分两段发送，总耗时超过 ReadTimeout；处理耗时也超过 WriteTimeout
+	pr, pw := io.Pipe()
+	go func() {
+		pw.Write([]byte("part1,"))
+		time.Sleep(2 * serverTimeout)
+		pw.Write([]byte("part2"))
+		pw.Close()
+	}()
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/api/v2/transfer", pr)
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || string(body) != "part1,part2" {
+		t.Fatalf("status = %d, body %q", resp.StatusCode, body)
+	}
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/metrics"
+)
+
+// GetGPUMetrics 获取 GPU 利用率、显存、温度与功耗（来自 dcgm-exporter），namespace 参数只保留被该命名空间 Pod 占用的 GPU。
+// 受限用户只能看到被可见命名空间占用的 GPU；available 为 false 表示未采集到 DCGM 指标
+func (h *Handler) GetGPUMetrics(c *gin.Context) {
+	if h.getMetrics(c) == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "metrics client not configured"})
+		return
+	}
+
+	namespaces, ok := h.metricsNamespaces(c)
+	if !ok {
+		return
+	}
+	if ns := c.Query("namespace"); ns != "" {
+		if namespaces != nil && !slices.Contains(namespaces, ns) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "无权访问该命名空间"})
+			return
+		}
+		namespaces = []string{ns}
+	}
+
+	// DCGM 的命名空间标签可能被采集端改名为 exported_namespace，无法在查询中注入匹配条件，改为查询后过滤
+	gpus, err := h.getMetrics(c).WithContext(requestContext(c)).GetGPUMetrics()
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	available := len(gpus) > 0
+	if namespaces != nil {
+		items := make([]metrics.GPUMetrics, 0, len(gpus))
+		for _, gpu := range gpus {
+			if gpu.Namespace != "" && slices.Contains(namespaces, gpu.Namespace) {
+				items = append(items, gpu)
+			}
+		}
+		gpus = items
+	}
+	c.JSON(http.StatusOK, gin.H{"items": gpus, "total": len(gpus), "available": available})
+}
@@ -2,13 +2,15 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"net/url"
-	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -30,8 +32,10 @@ import (
 	storagev1 "k8s.io/api/storage/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/remotecommand"
 	"sigs.k8s.io/yaml"
@@ -46,10 +50,27 @@ type Handler struct {
 	alertService *alerts.Service
 	audit        *audit.Client
 	auth         *auth.Client
+	overview     *overviewCache
+	nodeCapacity *nodeCapacityCache
+	opts         Options
+}
+
+// Options 处理器的启动配置，由 config.Config 传入
+type Options struct {
+	// WSAllowedOrigins 允许的 WebSocket Origin，为空时只允许与请求同源
+	WSAllowedOrigins []string
+	// 终端、日志与事件流会话的空闲超时、最长时长与到期前提醒，0 表示不限制
+	WSIdleTimeout        time.Duration
+	WSMaxSessionDuration time.Duration
+	WSTimeoutWarning     time.Duration
+	// PacketCaptureImage 抓包临时容器镜像，需包含 tcpdump
+	PacketCaptureImage string
+	// NodePoolLabel 节点池分组标签，为空时自动识别常见云厂商的节点池标签
+	NodePoolLabel string
 }
 
 // NewHandler 创建处理器
-func NewHandler(k8sClient *k8s.Client, clusterManager *clusters.Manager, metricsClient *metrics.Client, alertClient *alertmanager.Client, alertService *alerts.Service, auditClient *audit.Client, authClient *auth.Client) *Handler {
+func NewHandler(k8sClient *k8s.Client, clusterManager *clusters.Manager, metricsClient *metrics.Client, alertClient *alertmanager.Client, alertService *alerts.Service, auditClient *audit.Client, authClient *auth.Client, opts Options) *Handler {
 	return &Handler{
 		k8s:          k8sClient,
 		clusters:     clusterManager,
@@ -58,14 +79,52 @@ func NewHandler(k8sClient *k8s.Client, clusterManager *clusters.Manager, metrics
 		alertService: alertService,
 		audit:        auditClient,
 		auth:         authClient,
+		overview:     newOverviewCache(),
+		nodeCapacity: newNodeCapacityCache(),
+		opts:         opts,
 	}
 }
 
-func (h *Handler) getK8s(c *gin.Context) *k8s.Client {
-	if client := middleware.GetClusterClient(c); client != nil {
-		return client
+// requestTimeout 单个请求内 K8s、指标与告警调用的总超时，小于服务器 WriteTimeout（15s），超时后仍能写回错误
+const requestTimeout = 10 * time.Second
+
+// operationTimeout 多步写操作（清理、重平衡、审批执行）的超时
+const operationTimeout = 2 * time.Minute
+
+// requestContext 返回携带请求 trace 的上下文，客户端断开时取消并受 requestTimeout 限制，
+// 出站调用的 span 挂在请求 span 下
+func requestContext(c *gin.Context) context.Context {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	// net/http 在请求结束时取消父上下文，届时释放计时器，调用方无需持有 cancel
+	context.AfterFunc(c.Request.Context(), cancel)
+	return ctx
+}
+
+// operationContext 用于多步写操作：客户端断开后继续执行，避免操作只完成一半，超时为 operationTimeout
+func operationContext(c *gin.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(c.Request.Context()), operationTimeout)
+}
+
+// scope 返回中间件解析好的请求作用域，未经过 RequestScope 的请求使用全局客户端
+func (h *Handler) scope(c *gin.Context) *middleware.Scope {
+	if scope := middleware.GetScope(c); scope != nil {
+		return scope
 	}
-	return h.k8s
+	return middleware.ScopeDefaults{K8s: h.k8s, Metrics: h.metrics, Alerts: h.alerts}.Scope()
+}
+
+func (h *Handler) getK8s(c *gin.Context) *k8s.Client {
+	return h.scope(c).K8s
+}
+
+// getMetrics 返回当前请求集群的 VictoriaMetrics 客户端，集群未单独配置时为全局客户端
+func (h *Handler) getMetrics(c *gin.Context) *metrics.Client {
+	return h.scope(c).Metrics
+}
+
+// getAlerts 返回当前请求集群的 Alertmanager 客户端（已绑定请求上下文），集群未单独配置时为全局客户端
+func (h *Handler) getAlerts(c *gin.Context) *alertmanager.Client {
+	return h.scope(c).Alerts.WithContext(requestContext(c))
 }
 
 // ListResponse 列表响应
@@ -80,17 +139,39 @@ type namespaceAccessScope struct {
 	allowed      []string
 }
 
-func parseListOptions(c *gin.Context) metav1.ListOptions {
+// parseListOptions 解析列表接口的 limit、continue、labelSelector 与 fieldSelector 参数，
+// 选择器语法错误时直接返回 400 并返回 false
+func parseListOptions(c *gin.Context) (metav1.ListOptions, bool) {
 	var limit int64
 	if raw := c.Query("limit"); raw != "" {
 		if v, err := strconv.ParseInt(raw, 10, 64); err == nil && v > 0 {
 			limit = v
 		}
 	}
-	return metav1.ListOptions{
-		Limit:    limit,
-		Continue: c.Query("continue"),
+	opts := metav1.ListOptions{
+		Limit:         limit,
+		Continue:      c.Query("continue"),
+		LabelSelector: c.Query("labelSelector"),
+		FieldSelector: c.Query("fieldSelector"),
 	}
+	if opts.LabelSelector != "" {
+		if _, err := labels.Parse(opts.LabelSelector); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "labelSelector 无效: " + err.Error()})
+			return opts, false
+		}
+	}
+	if opts.FieldSelector != "" {
+		if _, err := fields.ParseSelector(opts.FieldSelector); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "fieldSelector 无效: " + err.Error()})
+			return opts, false
+		}
+	}
+	return opts, true
+}
+
+// selectorListOptions 只保留选择器，用于受限用户逐个命名空间查询后再统一分页的场景
+func selectorListOptions(opts metav1.ListOptions) metav1.ListOptions {
+	return metav1.ListOptions{LabelSelector: opts.LabelSelector, FieldSelector: opts.FieldSelector}
 }
 
 func paginateSlice[T any](items []T, limit int64, continueToken string) ([]T, string, error) {
@@ -143,7 +224,7 @@ func (h *Handler) getNamespaceAccessScope(c *gin.Context) (namespaceAccessScope,
 		return namespaceAccessScope{}, fmt.Errorf("auth service not available")
 	}
 
-	namespaces, err := h.auth.GetUserNamespaces(user.ID)
+	namespaces, err := h.auth.NamespaceGrants(user)
 	if err != nil {
 		return namespaceAccessScope{}, err
 	}
@@ -190,7 +271,7 @@ func maskSecret(secret corev1.Secret, view string) corev1.Secret {
 		if masked.Data != nil {
 			redacted := make(map[string][]byte, len(masked.Data))
 			for key := range masked.Data {
-				redacted[key] = []byte("REDACTED")
+				redacted[key] = []byte(secretRedactedValue)
 			}
 			masked.Data = redacted
 		}
@@ -216,6 +297,8 @@ type OverviewResponse struct {
 	Namespaces  int           `json:"namespaces"`
 	Events      EventSummary  `json:"events"`
 	Resources   ResourceUsage `json:"resources"`
+	// Warnings 获取失败的数据块说明，对应字段为零值；全部成功时为空
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 type ResourceCount struct {
@@ -235,30 +318,149 @@ type ResourceUsage struct {
 	Memory     UsageMetric `json:"memory"`     // 容器内存（K8s 视角）
 	NodeMemory UsageMetric `json:"nodeMemory"` // 节点内存（OS 视角）
 	Pods       UsageMetric `json:"pods"`
+	// Extended 扩展资源（如 nvidia.com/gpu）与大页内存，used 为未结束 Pod 的 requests 之和
+	Extended map[string]UsageMetric `json:"extended,omitempty"`
 }
 
 type UsageMetric struct {
 	Used  float64 `json:"used"`
 	Total float64 `json:"total"`
 	Unit  string  `json:"unit"`
+	// UsedStatus 用量数据状态（ok、no_data、unavailable，见 metrics.Status*），为空表示直接来自 Kubernetes API
+	UsedStatus string `json:"usedStatus,omitempty"`
 }
 
 // ========== 集群概览 ==========
 
+// GetOverview 集群概览。结果按客户端缓存 overviewCacheTTL，同一客户端的并发请求只计算一次
 func (h *Handler) GetOverview(c *gin.Context) {
-	ctx := context.Background()
-
-	// 获取节点信息
-	nodes, err := h.getK8s(c).Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	client := h.getK8s(c)
+	metricsClient := h.getMetrics(c)
+	overview, err := h.overview.get(client, func() (OverviewResponse, error) {
+		// 结果由并发请求共享并缓存，不随发起者断开而取消
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), requestTimeout)
+		defer cancel()
+		return buildOverview(ctx, client, metricsClient)
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, overview)
+}
+
+// buildOverview 并发获取概览所需的资源。列表请求带 resourceVersion=0，由 API Server 的 watch 缓存返回，
+// 不穿透到 etcd；Service 与 Namespace 只需要数量，搜索索引已同步时直接从 informer 缓存读取。
+// 单个数据块失败时该块留空并记入 Warnings，全部失败才返回错误
+func buildOverview(ctx context.Context, client *k8s.Client, metricsClient *metrics.Client) (OverviewResponse, error) {
+	cached := metav1.ListOptions{ResourceVersion: "0"}
+	index := client.CachedSearchIndex()
+
+	nodes := overviewSection{name: "节点"}
+	pods := overviewSection{name: "Pod"}
+	deployments := overviewSection{name: "Deployment"}
+	services := overviewSection{name: "Service"}
+	namespaces := overviewSection{name: "命名空间"}
+	events := overviewSection{name: "事件"}
+
+	var (
+		nodeItems                    []corev1.Node
+		podItems                     []corev1.Pod
+		deploymentItems              []appsv1.Deployment
+		eventItems                   []corev1.Event
+		serviceCount, namespaceCount int
+		clusterMetrics               *metrics.ClusterMetrics
+		// Metrics Server 的节点用量合计，serverUsage 为 false 表示未取到
+		serverCPU, serverMemory float64
+		serverUsage             bool
+		usageErr                error
+	)
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		var list *corev1.NodeList
+		if list, nodes.err = client.Clientset.CoreV1().Nodes().List(ctx, cached); nodes.err == nil {
+			nodeItems = list.Items
+		}
+	})
+	wg.Go(func() {
+		var list *corev1.PodList
+		if list, pods.err = client.Clientset.CoreV1().Pods("").List(ctx, cached); pods.err == nil {
+			podItems = list.Items
+		}
+	})
+	wg.Go(func() {
+		var list *appsv1.DeploymentList
+		if list, deployments.err = client.Clientset.AppsV1().Deployments("").List(ctx, cached); deployments.err == nil {
+			deploymentItems = list.Items
+		}
+	})
+	wg.Go(func() {
+		var list *corev1.EventList
+		if list, events.err = client.Clientset.CoreV1().Events("").List(ctx, cached); events.err == nil {
+			eventItems = list.Items
+		}
+	})
+	wg.Go(func() {
+		serviceCount, services.err = countObjects(index, "Service", func() (int, error) {
+			list, err := client.Clientset.CoreV1().Services("").List(ctx, cached)
+			if err != nil {
+				return 0, err
+			}
+			return len(list.Items), nil
+		})
+	})
+	wg.Go(func() {
+		namespaceCount, namespaces.err = countObjects(index, "Namespace", func() (int, error) {
+			list, err := client.Clientset.CoreV1().Namespaces().List(ctx, cached)
+			if err != nil {
+				return 0, err
+			}
+			return len(list.Items), nil
+		})
+	})
+	// 用量数据优先 VictoriaMetrics，不可用时回退到 Kubernetes Metrics Server；两者都未配置时不算失败
+	wg.Go(func() {
+		if metricsClient != nil {
+			if clusterMetrics, usageErr = metricsClient.WithContext(ctx).GetClusterMetrics(); usageErr == nil {
+				return
+			}
+		}
+		if client.MetricsClient != nil {
+			nodeMetrics, err := client.MetricsClient.MetricsV1beta1().NodeMetricses().List(ctx, metav1.ListOptions{})
+			if err != nil {
+				usageErr = err
+				return
+			}
+			for _, nm := range nodeMetrics.Items {
+				serverCPU += float64(nm.Usage.Cpu().MilliValue()) / 1000
+				serverMemory += float64(nm.Usage.Memory().Value()) / (1024 * 1024 * 1024)
+			}
+			serverUsage = true
+			usageErr = nil
+		}
+	})
+	wg.Wait()
+
+	sections := []overviewSection{nodes, pods, deployments, services, namespaces, events}
+	var warnings []string
+	for _, section := range sections {
+		if section.err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s数据不可用: %v", section.name, section.err))
+		}
+	}
+	if len(warnings) == len(sections) {
+		return OverviewResponse{}, nodes.err
+	}
+	if usageErr != nil {
+		warnings = append(warnings, fmt.Sprintf("资源用量数据不可用: %v", usageErr))
 	}
 
-	nodeCount := ResourceCount{Total: len(nodes.Items)}
+	nodeCount := ResourceCount{Total: len(nodeItems)}
 	var totalCPU, usedCPU, totalMemory, usedMemory, totalNodeMemory, usedNodeMemory, totalPods, usedPods float64
+	var extendedAllocatable, extendedRequested map[string]float64
 
-	for _, node := range nodes.Items {
+	for _, node := range nodeItems {
 		ready := false
 		for _, cond := range node.Status.Conditions {
 			if cond.Type == corev1.NodeReady && cond.Status == corev1.ConditionTrue {
@@ -282,34 +484,24 @@ func (h *Handler) GetOverview(c *gin.Context) {
 		if pods := node.Status.Allocatable.Pods(); pods != nil {
 			totalPods += float64(pods.Value())
 		}
+		extendedAllocatable = k8s.AddResources(extendedAllocatable, k8s.ExtendedResources(node.Status.Allocatable))
 	}
 
-	// 获取所有 Pod
-	pods, err := h.getK8s(c).Clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	podCount := ResourceCount{Total: len(pods.Items)}
-	for _, pod := range pods.Items {
+	podCount := ResourceCount{Total: len(podItems)}
+	for i, pod := range podItems {
 		if pod.Status.Phase == corev1.PodRunning {
 			podCount.Ready++
 		} else {
 			podCount.NotReady++
 		}
+		if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+			extendedRequested = k8s.AddResources(extendedRequested, k8s.PodExtendedRequests(&podItems[i]))
+		}
 	}
-	usedPods = float64(len(pods.Items))
-
-	// 获取 Deployments
-	deployments, err := h.getK8s(c).Clientset.AppsV1().Deployments("").List(ctx, metav1.ListOptions{})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
+	usedPods = float64(len(podItems))
 
-	deploymentCount := ResourceCount{Total: len(deployments.Items)}
-	for _, dep := range deployments.Items {
+	deploymentCount := ResourceCount{Total: len(deploymentItems)}
+	for _, dep := range deploymentItems {
 		if dep.Status.ReadyReplicas == dep.Status.Replicas {
 			deploymentCount.Ready++
 		} else {
@@ -317,31 +509,8 @@ func (h *Handler) GetOverview(c *gin.Context) {
 		}
 	}
 
-	// 获取 Services
-	services, err := h.getK8s(c).Clientset.CoreV1().Services("").List(ctx, metav1.ListOptions{})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	serviceCount := ResourceCount{Total: len(services.Items), Ready: len(services.Items)}
-
-	// 获取 Namespaces
-	namespaces, err := h.getK8s(c).Clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	// 获取事件
-	events, err := h.getK8s(c).Clientset.CoreV1().Events("").List(ctx, metav1.ListOptions{})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	eventSummary := EventSummary{Total: len(events.Items)}
-	for _, event := range events.Items {
+	eventSummary := EventSummary{Total: len(eventItems)}
+	for _, event := range eventItems {
 		if event.Type == "Warning" {
 			eventSummary.Warning++
 		} else {
@@ -349,84 +518,98 @@ func (h *Handler) GetOverview(c *gin.Context) {
 		}
 	}
 
-	// 优先从 VictoriaMetrics 获取资源使用数据
-	vmDataUsed := false
-	if h.metrics != nil {
-		clusterMetrics, err := h.metrics.GetClusterMetrics()
-		if err == nil {
-			usedCPU = clusterMetrics.CPU.Used
-			usedMemory = clusterMetrics.Memory.Used
-			usedNodeMemory = clusterMetrics.NodeMemory.Used
-			// 如果 VM 返回了总量数据，也使用它
-			if clusterMetrics.CPU.Total > 0 {
-				totalCPU = clusterMetrics.CPU.Total
-			}
-			if clusterMetrics.Memory.Total > 0 {
-				totalMemory = clusterMetrics.Memory.Total
-			}
-			if clusterMetrics.NodeMemory.Total > 0 {
-				totalNodeMemory = clusterMetrics.NodeMemory.Total
-			}
-			if clusterMetrics.Pods.Total > 0 {
-				totalPods = clusterMetrics.Pods.Total
-			}
-			if clusterMetrics.Pods.Used > 0 {
-				usedPods = clusterMetrics.Pods.Used
-			}
-			vmDataUsed = true
+	// 用量未取到时如实标记，前端显示“指标不可用”而不是 0
+	cpuStatus, memoryStatus, nodeMemoryStatus := metrics.StatusUnavailable, metrics.StatusUnavailable, metrics.StatusUnavailable
+	if clusterMetrics != nil {
+		usedCPU = clusterMetrics.CPU.Used
+		usedMemory = clusterMetrics.Memory.Used
+		usedNodeMemory = clusterMetrics.NodeMemory.Used
+		cpuStatus, memoryStatus, nodeMemoryStatus = clusterMetrics.CPU.UsedStatus, clusterMetrics.Memory.UsedStatus, clusterMetrics.NodeMemory.UsedStatus
+		// 如果 VM 返回了总量数据，也使用它
+		if clusterMetrics.CPU.Total > 0 {
+			totalCPU = clusterMetrics.CPU.Total
 		}
-	}
-
-	// 如果 VM 不可用，回退到 Kubernetes Metrics Server
-	if !vmDataUsed && h.getK8s(c).MetricsClient != nil {
-		nodeMetrics, err := h.getK8s(c).MetricsClient.MetricsV1beta1().NodeMetricses().List(ctx, metav1.ListOptions{})
-		if err == nil {
-			usedCPU = 0
-			usedMemory = 0
-			for _, nm := range nodeMetrics.Items {
-				if cpu := nm.Usage.Cpu(); cpu != nil {
-					usedCPU += float64(cpu.MilliValue()) / 1000
-				}
-				if mem := nm.Usage.Memory(); mem != nil {
-					usedMemory += float64(mem.Value()) / (1024 * 1024 * 1024)
-				}
-			}
+		if clusterMetrics.Memory.Total > 0 {
+			totalMemory = clusterMetrics.Memory.Total
+		}
+		if clusterMetrics.NodeMemory.Total > 0 {
+			totalNodeMemory = clusterMetrics.NodeMemory.Total
 		}
+		if clusterMetrics.Pods.Total > 0 {
+			totalPods = clusterMetrics.Pods.Total
+		}
+		if clusterMetrics.Pods.Used > 0 {
+			usedPods = clusterMetrics.Pods.Used
+		}
+	} else if serverUsage {
+		usedCPU = serverCPU
+		usedMemory = serverMemory
+		cpuStatus, memoryStatus = metrics.StatusOK, metrics.StatusOK
 	}
 
-	c.JSON(http.StatusOK, OverviewResponse{
+	return OverviewResponse{
 		Nodes:       nodeCount,
 		Pods:        podCount,
 		Deployments: deploymentCount,
-		Services:    serviceCount,
-		Namespaces:  len(namespaces.Items),
+		Services:    ResourceCount{Total: serviceCount, Ready: serviceCount},
+		Namespaces:  namespaceCount,
 		Events:      eventSummary,
 		Resources: ResourceUsage{
-			CPU:        UsageMetric{Used: usedCPU, Total: totalCPU, Unit: "cores"},
-			Memory:     UsageMetric{Used: usedMemory, Total: totalMemory, Unit: "GB"},
-			NodeMemory: UsageMetric{Used: usedNodeMemory, Total: totalNodeMemory, Unit: "GB"},
+			CPU:        UsageMetric{Used: usedCPU, Total: totalCPU, Unit: "cores", UsedStatus: cpuStatus},
+			Memory:     UsageMetric{Used: usedMemory, Total: totalMemory, Unit: "GB", UsedStatus: memoryStatus},
+			NodeMemory: UsageMetric{Used: usedNodeMemory, Total: totalNodeMemory, Unit: "GB", UsedStatus: nodeMemoryStatus},
 			Pods:       UsageMetric{Used: usedPods, Total: totalPods, Unit: "pods"},
+			Extended:   extendedUsage(extendedAllocatable, extendedRequested),
 		},
-	})
+		Warnings: warnings,
+	}, nil
+}
+
+// overviewSection 概览中一个数据块的名称与获取错误
+type overviewSection struct {
+	name string
+	err  error
+}
+
+// extendedUsage 合并扩展资源的可分配量与申请量，大页内存单位为字节
+func extendedUsage(allocatable, requested map[string]float64) map[string]UsageMetric {
+	if len(allocatable) == 0 && len(requested) == 0 {
+		return nil
+	}
+	result := make(map[string]UsageMetric)
+	for _, names := range []map[string]float64{allocatable, requested} {
+		for name := range names {
+			unit := "count"
+			if strings.HasPrefix(name, corev1.ResourceHugePagesPrefix) {
+				unit = "bytes"
+			}
+			result[name] = UsageMetric{Used: requested[name], Total: allocatable[name], Unit: unit}
+		}
+	}
+	return result
 }
 
 // ========== Namespaces ==========
 
 func (h *Handler) ListNamespaces(c *gin.Context) {
-	ctx := context.Background()
-	list, err := h.getK8s(c).Clientset.CoreV1().Namespaces().List(ctx, parseListOptions(c))
+	ctx := requestContext(c)
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+	list, err := h.getK8s(c).Clientset.CoreV1().Namespaces().List(ctx, listOpts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
 	scope, scopeErr := h.getNamespaceAccessScope(c)
 	if scopeErr != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": scopeErr.Error()})
+		writeError(c, http.StatusUnauthorized, scopeErr)
 		return
 	}
 	if scope.unrestricted {
-		c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
+		writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
 		return
 	}
 
@@ -436,41 +619,44 @@ func (h *Handler) ListNamespaces(c *gin.Context) {
 			items = append(items, item)
 		}
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: items, Total: len(items)})
+	writeList(c, ListResponse{Items: items, Total: len(items)})
 }
 
 func (h *Handler) GetNamespace(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	name := c.Param("ns")
 	ns, err := h.getK8s(c).Clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 	c.JSON(http.StatusOK, ns)
 }
 
 func (h *Handler) CreateNamespace(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	var ns corev1.Namespace
 	if err := c.ShouldBindJSON(&ns); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+	if !validateObject(c, &ns, "", "") {
 		return
 	}
 	result, err := h.getK8s(c).Clientset.CoreV1().Namespaces().Create(ctx, &ns, metav1.CreateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusCreated, result)
 }
 
 func (h *Handler) DeleteNamespace(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	name := c.Param("ns")
 	err := h.getK8s(c).Clientset.CoreV1().Namespaces().Delete(ctx, name, metav1.DeleteOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
@@ -479,29 +665,32 @@ func (h *Handler) DeleteNamespace(c *gin.Context) {
 // ========== Pods ==========
 
 func (h *Handler) ListAllPods(c *gin.Context) {
-	ctx := context.Background()
-	listOpts := parseListOptions(c)
+	ctx := requestContext(c)
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
 	scope, err := h.getNamespaceAccessScope(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		writeError(c, http.StatusUnauthorized, err)
 		return
 	}
 
 	if scope.unrestricted {
 		list, err := h.getK8s(c).Clientset.CoreV1().Pods("").List(ctx, listOpts)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			writeError(c, http.StatusInternalServerError, err)
 			return
 		}
-		c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
+		writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
 		return
 	}
 
 	items := make([]corev1.Pod, 0)
 	for _, ns := range scope.allowed {
-		list, err := h.getK8s(c).Clientset.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{})
+		list, err := h.getK8s(c).Clientset.CoreV1().Pods(ns).List(ctx, selectorListOptions(listOpts))
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			writeError(c, http.StatusInternalServerError, err)
 			return
 		}
 		items = append(items, list.Items...)
@@ -509,19 +698,19 @@ func (h *Handler) ListAllPods(c *gin.Context) {
 
 	paged, nextToken, err := paginateSlice(items, listOpts.Limit, listOpts.Continue)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, ListResponse{Items: paged, Total: len(items), Continue: nextToken})
+	writeList(c, ListResponse{Items: paged, Total: len(items), Continue: nextToken})
 }
 
 func (h *Handler) ListPods(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	scope, err := h.getNamespaceAccessScope(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		writeError(c, http.StatusUnauthorized, err)
 		return
 	}
 	if !namespaceAllowed(scope, namespace) {
@@ -529,90 +718,110 @@ func (h *Handler) ListPods(c *gin.Context) {
 		return
 	}
 
-	list, err := h.getK8s(c).Clientset.CoreV1().Pods(namespace).List(ctx, parseListOptions(c))
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+	list, err := h.getK8s(c).Clientset.CoreV1().Pods(namespace).List(ctx, listOpts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
+	writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
 }
 
 func (h *Handler) GetPod(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
-	pod, err := h.getK8s(c).Clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	clientset := h.getK8s(c).Clientset
+	pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
-	c.JSON(http.StatusOK, pod)
+	c.JSON(http.StatusOK, podDetail{
+		Pod:               pod,
+		ResourceRelations: resolveRelations(ctx, clientset, pod, "Pod", nil),
+		ExtendedRequests:  k8s.PodExtendedRequests(pod),
+	})
 }
 
 func (h *Handler) DeletePod(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 	err := h.getK8s(c).Clientset.CoreV1().Pods(namespace).Delete(ctx, name, metav1.DeleteOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
 }
 
 func (h *Handler) GetPodYAML(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 	pod, err := h.getK8s(c).Clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 	// 清理不需要的字段
 	pod.ManagedFields = nil
 	yamlBytes, err := yaml.Marshal(pod)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.String(http.StatusOK, string(yamlBytes))
 }
 
+// GetPodLogs 获取 Pod 日志，支持 sinceTime/untilTime/previous 以及 grep（正则）/invert 服务端过滤，
+// 过滤时通过 X-Log-Match-Count、X-Log-Scanned-Lines 返回命中行数与扫描行数
 func (h *Handler) GetPodLogs(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
-	container := c.Query("container")
-	tailLines := c.DefaultQuery("tailLines", "100")
 
-	opts := &corev1.PodLogOptions{}
-	if container != "" {
-		opts.Container = container
-	}
-	if lines, err := strconv.ParseInt(tailLines, 10, 64); err == nil {
-		opts.TailLines = &lines
+	filter, err := parseLogFilter(c, "100")
+	if err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
 	}
 
-	req := h.getK8s(c).Clientset.CoreV1().Pods(namespace).GetLogs(name, opts)
+	req := h.getK8s(c).Clientset.CoreV1().Pods(namespace).GetLogs(name, filter.podLogOptions())
 	logs, err := req.Stream(ctx)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	defer logs.Close()
 
-	logBytes, err := io.ReadAll(logs)
+	if !filter.filtering() {
+		logBytes, err := io.ReadAll(logs)
+		if err != nil {
+			writeError(c, http.StatusInternalServerError, err)
+			return
+		}
+		c.String(http.StatusOK, string(logBytes))
+		return
+	}
+
+	var buf strings.Builder
+	matched, scanned, err := filterLogStream(logs, &buf, filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.String(http.StatusOK, string(logBytes))
+	c.Header("X-Log-Match-Count", strconv.Itoa(matched))
+	c.Header("X-Log-Scanned-Lines", strconv.Itoa(scanned))
+	c.String(http.StatusOK, buf.String())
 }
 
 func (h *Handler) GetPodEvents(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 
@@ -621,38 +830,41 @@ func (h *Handler) GetPodEvents(c *gin.Context) {
 		FieldSelector: fieldSelector,
 	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: events.Items, Total: len(events.Items)})
+	writeList(c, ListResponse{Items: events.Items, Total: len(events.Items)})
 }
 
 // ========== Deployments ==========
 
 func (h *Handler) ListAllDeployments(c *gin.Context) {
-	ctx := context.Background()
-	listOpts := parseListOptions(c)
+	ctx := requestContext(c)
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
 	scope, err := h.getNamespaceAccessScope(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		writeError(c, http.StatusUnauthorized, err)
 		return
 	}
 
 	if scope.unrestricted {
 		list, err := h.getK8s(c).Clientset.AppsV1().Deployments("").List(ctx, listOpts)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			writeError(c, http.StatusInternalServerError, err)
 			return
 		}
-		c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
+		writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
 		return
 	}
 
 	items := make([]appsv1.Deployment, 0)
 	for _, ns := range scope.allowed {
-		list, err := h.getK8s(c).Clientset.AppsV1().Deployments(ns).List(ctx, metav1.ListOptions{})
+		list, err := h.getK8s(c).Clientset.AppsV1().Deployments(ns).List(ctx, selectorListOptions(listOpts))
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			writeError(c, http.StatusInternalServerError, err)
 			return
 		}
 		items = append(items, list.Items...)
@@ -660,19 +872,19 @@ func (h *Handler) ListAllDeployments(c *gin.Context) {
 
 	paged, nextToken, err := paginateSlice(items, listOpts.Limit, listOpts.Continue)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, ListResponse{Items: paged, Total: len(items), Continue: nextToken})
+	writeList(c, ListResponse{Items: paged, Total: len(items), Continue: nextToken})
 }
 
 func (h *Handler) ListDeployments(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	scope, err := h.getNamespaceAccessScope(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		writeError(c, http.StatusUnauthorized, err)
 		return
 	}
 	if !namespaceAllowed(scope, namespace) {
@@ -680,116 +892,130 @@ func (h *Handler) ListDeployments(c *gin.Context) {
 		return
 	}
 
-	list, err := h.getK8s(c).Clientset.AppsV1().Deployments(namespace).List(ctx, parseListOptions(c))
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+	list, err := h.getK8s(c).Clientset.AppsV1().Deployments(namespace).List(ctx, listOpts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
+	writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
 }
 
 func (h *Handler) GetDeployment(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
-	dep, err := h.getK8s(c).Clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	clientset := h.getK8s(c).Clientset
+	dep, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
-	c.JSON(http.StatusOK, dep)
+	c.JSON(http.StatusOK, deploymentDetail{Deployment: dep, ResourceRelations: resolveRelations(ctx, clientset, dep, "Deployment", dep.Spec.Selector)})
 }
 
 func (h *Handler) CreateDeployment(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	var dep appsv1.Deployment
 	if err := c.ShouldBindJSON(&dep); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+	if !validateObject(c, &dep, namespace, "") {
 		return
 	}
 	result, err := h.getK8s(c).Clientset.AppsV1().Deployments(namespace).Create(ctx, &dep, metav1.CreateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusCreated, result)
 }
 
 func (h *Handler) UpdateDeployment(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	var dep appsv1.Deployment
 	if err := c.ShouldBindJSON(&dep); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+	if !validateObject(c, &dep, namespace, c.Param("name")) {
 		return
 	}
+	before := auditBefore(h.getK8s(c).Clientset.AppsV1().Deployments(namespace).Get(ctx, c.Param("name"), metav1.GetOptions{}))
 	result, err := h.getK8s(c).Clientset.AppsV1().Deployments(namespace).Update(ctx, &dep, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
+	recordAuditDiff(c, before, result)
 	c.JSON(http.StatusOK, result)
 }
 
 func (h *Handler) DeleteDeployment(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 	err := h.getK8s(c).Clientset.AppsV1().Deployments(namespace).Delete(ctx, name, metav1.DeleteOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
 }
 
 func (h *Handler) GetDeploymentYAML(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 	dep, err := h.getK8s(c).Clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 	dep.ManagedFields = nil
 	yamlBytes, err := yaml.Marshal(dep)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.String(http.StatusOK, string(yamlBytes))
 }
 
 func (h *Handler) UpdateDeploymentYAML(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 
 	var req struct {
 		YAML string `json:"yaml"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	var dep appsv1.Deployment
-	if err := yaml.Unmarshal([]byte(req.YAML), &dep); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !decodeManifest(c, []byte(req.YAML), &dep) || !validateObject(c, &dep, namespace, c.Param("name")) {
 		return
 	}
 
+	before := auditBefore(h.getK8s(c).Clientset.AppsV1().Deployments(namespace).Get(ctx, c.Param("name"), metav1.GetOptions{}))
 	result, err := h.getK8s(c).Clientset.AppsV1().Deployments(namespace).Update(ctx, &dep, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
+	recordAuditDiff(c, before, result)
 	c.JSON(http.StatusOK, result)
 }
 
 func (h *Handler) ScaleDeployment(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 
@@ -797,33 +1023,33 @@ func (h *Handler) ScaleDeployment(c *gin.Context) {
 		Replicas int32 `json:"replicas"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	scale, err := h.getK8s(c).Clientset.AppsV1().Deployments(namespace).GetScale(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 
 	scale.Spec.Replicas = req.Replicas
 	_, err = h.getK8s(c).Clientset.AppsV1().Deployments(namespace).UpdateScale(ctx, name, scale, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "scaled", "replicas": req.Replicas})
 }
 
 func (h *Handler) RestartDeployment(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 
 	dep, err := h.getK8s(c).Clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -835,14 +1061,14 @@ func (h *Handler) RestartDeployment(c *gin.Context) {
 
 	_, err = h.getK8s(c).Clientset.AppsV1().Deployments(namespace).Update(ctx, dep, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "restarted"})
 }
 
 func (h *Handler) RollbackDeployment(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 
@@ -854,13 +1080,13 @@ func (h *Handler) RollbackDeployment(c *gin.Context) {
 	// 获取 ReplicaSets
 	dep, err := h.getK8s(c).Clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 
 	selector, err := metav1.LabelSelectorAsSelector(dep.Spec.Selector)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -868,7 +1094,7 @@ func (h *Handler) RollbackDeployment(c *gin.Context) {
 		LabelSelector: selector.String(),
 	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -901,20 +1127,20 @@ func (h *Handler) RollbackDeployment(c *gin.Context) {
 	dep.Spec.Template = targetRS.Spec.Template
 	_, err = h.getK8s(c).Clientset.AppsV1().Deployments(namespace).Update(ctx, dep, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "rolled back"})
 }
 
 func (h *Handler) GetDeploymentPods(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 
 	dep, err := h.getK8s(c).Clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -923,79 +1149,88 @@ func (h *Handler) GetDeploymentPods(c *gin.Context) {
 		LabelSelector: selector.String(),
 	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: pods.Items, Total: len(pods.Items)})
+	writeList(c, ListResponse{Items: pods.Items, Total: len(pods.Items)})
 }
 
 // ========== StatefulSets ==========
 
 func (h *Handler) ListAllStatefulSets(c *gin.Context) {
-	ctx := context.Background()
-	list, err := h.getK8s(c).Clientset.AppsV1().StatefulSets("").List(ctx, metav1.ListOptions{})
+	ctx := requestContext(c)
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+	list, err := h.getK8s(c).Clientset.AppsV1().StatefulSets("").List(ctx, listOpts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items)})
+	writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
 }
 
 func (h *Handler) ListStatefulSets(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
-	list, err := h.getK8s(c).Clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+	list, err := h.getK8s(c).Clientset.AppsV1().StatefulSets(namespace).List(ctx, listOpts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items)})
+	writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
 }
 
 func (h *Handler) GetStatefulSet(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
-	sts, err := h.getK8s(c).Clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	clientset := h.getK8s(c).Clientset
+	sts, err := clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
-	c.JSON(http.StatusOK, sts)
+	c.JSON(http.StatusOK, statefulSetDetail{StatefulSet: sts, ResourceRelations: resolveRelations(ctx, clientset, sts, "StatefulSet", sts.Spec.Selector)})
 }
 
 func (h *Handler) DeleteStatefulSet(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 	err := h.getK8s(c).Clientset.AppsV1().StatefulSets(namespace).Delete(ctx, name, metav1.DeleteOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
 }
 
 func (h *Handler) GetStatefulSetYAML(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 	sts, err := h.getK8s(c).Clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 	sts.ManagedFields = nil
 	yamlBytes, err := yaml.Marshal(sts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.String(http.StatusOK, string(yamlBytes))
 }
 
 func (h *Handler) ScaleStatefulSet(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 
@@ -1003,20 +1238,20 @@ func (h *Handler) ScaleStatefulSet(c *gin.Context) {
 		Replicas int32 `json:"replicas"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	scale, err := h.getK8s(c).Clientset.AppsV1().StatefulSets(namespace).GetScale(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 
 	scale.Spec.Replicas = req.Replicas
 	_, err = h.getK8s(c).Clientset.AppsV1().StatefulSets(namespace).UpdateScale(ctx, name, scale, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "scaled", "replicas": req.Replicas})
@@ -1025,63 +1260,72 @@ func (h *Handler) ScaleStatefulSet(c *gin.Context) {
 // ========== DaemonSets ==========
 
 func (h *Handler) ListAllDaemonSets(c *gin.Context) {
-	ctx := context.Background()
-	list, err := h.getK8s(c).Clientset.AppsV1().DaemonSets("").List(ctx, metav1.ListOptions{})
+	ctx := requestContext(c)
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+	list, err := h.getK8s(c).Clientset.AppsV1().DaemonSets("").List(ctx, listOpts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items)})
+	writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
 }
 
 func (h *Handler) ListDaemonSets(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
-	list, err := h.getK8s(c).Clientset.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+	list, err := h.getK8s(c).Clientset.AppsV1().DaemonSets(namespace).List(ctx, listOpts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items)})
+	writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
 }
 
 func (h *Handler) GetDaemonSet(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
-	ds, err := h.getK8s(c).Clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	clientset := h.getK8s(c).Clientset
+	ds, err := clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
-	c.JSON(http.StatusOK, ds)
+	c.JSON(http.StatusOK, daemonSetDetail{DaemonSet: ds, ResourceRelations: resolveRelations(ctx, clientset, ds, "DaemonSet", ds.Spec.Selector)})
 }
 
 func (h *Handler) DeleteDaemonSet(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 	err := h.getK8s(c).Clientset.AppsV1().DaemonSets(namespace).Delete(ctx, name, metav1.DeleteOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
 }
 
 func (h *Handler) GetDaemonSetYAML(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 	ds, err := h.getK8s(c).Clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 	ds.ManagedFields = nil
 	yamlBytes, err := yaml.Marshal(ds)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.String(http.StatusOK, string(yamlBytes))
@@ -1090,40 +1334,49 @@ func (h *Handler) GetDaemonSetYAML(c *gin.Context) {
 // ========== Jobs ==========
 
 func (h *Handler) ListAllJobs(c *gin.Context) {
-	ctx := context.Background()
-	list, err := h.getK8s(c).Clientset.BatchV1().Jobs("").List(ctx, metav1.ListOptions{})
+	ctx := requestContext(c)
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+	list, err := h.getK8s(c).Clientset.BatchV1().Jobs("").List(ctx, listOpts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items)})
+	writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
 }
 
 func (h *Handler) ListJobs(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
-	list, err := h.getK8s(c).Clientset.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+	list, err := h.getK8s(c).Clientset.BatchV1().Jobs(namespace).List(ctx, listOpts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items)})
+	writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
 }
 
 func (h *Handler) GetJob(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
-	job, err := h.getK8s(c).Clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	clientset := h.getK8s(c).Clientset
+	job, err := clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
-	c.JSON(http.StatusOK, job)
+	c.JSON(http.StatusOK, jobDetail{Job: job, ResourceRelations: resolveRelations(ctx, clientset, job, "Job", job.Spec.Selector)})
 }
 
 func (h *Handler) DeleteJob(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 	propagation := metav1.DeletePropagationBackground
@@ -1131,122 +1384,231 @@ func (h *Handler) DeleteJob(c *gin.Context) {
 		PropagationPolicy: &propagation,
 	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
 }
 
+// jobControllerLabels 是 Job 控制器自动注入的标签，重新运行时必须剔除，
+// 否则新 Job 的 selector 会与原 Job 的 Pod 冲突。
+var jobControllerLabels = []string{
+	"controller-uid",
+	"job-name",
+	batchv1.ControllerUidLabel,
+	batchv1.JobNameLabel,
+}
+
+func stripJobControllerLabels(in map[string]string) map[string]string {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(in))
+	for k, v := range in {
+		out[k] = v
+	}
+	for _, key := range jobControllerLabels {
+		delete(out, key)
+	}
+	return out
+}
+
+// RerunJob 基于已有 Job 的 spec 克隆出一个新 Job 重新运行
+func (h *Handler) RerunJob(c *gin.Context) {
+	ctx := requestContext(c)
+	namespace := c.Param("ns")
+	name := c.Param("name")
+
+	src, err := h.getK8s(c).Clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	spec := *src.Spec.DeepCopy()
+	// selector 与 controller-uid 由控制器重新生成
+	spec.Selector = nil
+	spec.ManualSelector = nil
+	spec.Template.Labels = stripJobControllerLabels(spec.Template.Labels)
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			// 由 API Server 追加随机后缀，避免同一秒内重复重跑冲突及超长名称
+			GenerateName: jobNamePrefix(name + "-rerun-"),
+			Namespace:    namespace,
+			Labels:       stripJobControllerLabels(src.Labels),
+			Annotations:  map[string]string{"k8s-dashboard/rerun-of": src.Name},
+		},
+		Spec: spec,
+	}
+
+	result, err := h.getK8s(c).Clientset.BatchV1().Jobs(namespace).Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
 // ========== CronJobs ==========
 
 func (h *Handler) ListAllCronJobs(c *gin.Context) {
-	ctx := context.Background()
-	list, err := h.getK8s(c).Clientset.BatchV1().CronJobs("").List(ctx, metav1.ListOptions{})
+	ctx := requestContext(c)
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+	list, err := h.getK8s(c).Clientset.BatchV1().CronJobs("").List(ctx, listOpts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items)})
+	writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
 }
 
 func (h *Handler) ListCronJobs(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
-	list, err := h.getK8s(c).Clientset.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+	list, err := h.getK8s(c).Clientset.BatchV1().CronJobs(namespace).List(ctx, listOpts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items)})
+	writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
+}
+
+// cronJobDetail 在 CronJob 对象基础上附带最近调度时间和活跃 Job 详情
+type cronJobDetail struct {
+	*batchv1.CronJob
+	LastScheduleTime *metav1.Time  `json:"lastScheduleTime,omitempty"`
+	ActiveJobs       []batchv1.Job `json:"activeJobs"`
+	ResourceRelations
 }
 
 func (h *Handler) GetCronJob(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 	cj, err := h.getK8s(c).Clientset.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
-	c.JSON(http.StatusOK, cj)
+
+	detail := cronJobDetail{
+		CronJob:          cj,
+		LastScheduleTime: cj.Status.LastScheduleTime,
+		ActiveJobs:       []batchv1.Job{},
+	}
+	for _, ref := range cj.Status.Active {
+		job, err := h.getK8s(c).Clientset.BatchV1().Jobs(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			// 活跃 Job 可能刚被清理，忽略即可
+			continue
+		}
+		detail.ActiveJobs = append(detail.ActiveJobs, *job)
+	}
+	detail.ResourceRelations = resolveRelations(ctx, h.getK8s(c).Clientset, cj, "CronJob", nil)
+	c.JSON(http.StatusOK, detail)
 }
 
 func (h *Handler) DeleteCronJob(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 	err := h.getK8s(c).Clientset.BatchV1().CronJobs(namespace).Delete(ctx, name, metav1.DeleteOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
 }
 
-func (h *Handler) TriggerCronJob(c *gin.Context) {
-	ctx := context.Background()
+// SuspendCronJob 暂停 CronJob 调度，已暂停时同样返回 200（changed=false）。
+// 兼容请求体 {"suspend": false}，此时等价于 ResumeCronJob
+func (h *Handler) SuspendCronJob(c *gin.Context) {
+	var req struct {
+		Suspend *bool `json:"suspend"`
+	}
+	_ = c.ShouldBindJSON(&req)
+
+	suspend := true
+	if req.Suspend != nil {
+		suspend = *req.Suspend
+	}
+	h.setCronJobSuspend(c, suspend)
+}
+
+// ResumeCronJob 恢复 CronJob 调度
+func (h *Handler) ResumeCronJob(c *gin.Context) {
+	h.setCronJobSuspend(c, false)
+}
+
+func (h *Handler) setCronJobSuspend(c *gin.Context, suspend bool) {
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 
 	cj, err := h.getK8s(c).Clientset.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 
-	// 创建一个新的 Job
-	job := &batchv1.Job{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("%s-manual-%d", name, time.Now().Unix()),
-			Namespace: namespace,
-			Labels:    cj.Spec.JobTemplate.Labels,
-			OwnerReferences: []metav1.OwnerReference{
-				{
-					APIVersion: "batch/v1",
-					Kind:       "CronJob",
-					Name:       cj.Name,
-					UID:        cj.UID,
-				},
-			},
-		},
-		Spec: cj.Spec.JobTemplate.Spec,
+	message := "resumed"
+	if suspend {
+		message = "suspended"
 	}
 
-	result, err := h.getK8s(c).Clientset.BatchV1().Jobs(namespace).Create(ctx, job, metav1.CreateOptions{})
+	// 已处于目标状态时直接返回成功，重复请求不报错
+	current := cj.Spec.Suspend != nil && *cj.Spec.Suspend
+	if current == suspend {
+		c.JSON(http.StatusOK, gin.H{"message": message, "changed": false, "cronjob": cj})
+		return
+	}
+
+	cj.Spec.Suspend = &suspend
+	result, err := h.getK8s(c).Clientset.BatchV1().CronJobs(namespace).Update(ctx, cj, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, result)
+	c.JSON(http.StatusOK, gin.H{"message": message, "changed": true, "cronjob": result})
 }
 
 // ========== Services ==========
 
 func (h *Handler) ListAllServices(c *gin.Context) {
-	ctx := context.Background()
-	listOpts := parseListOptions(c)
+	ctx := requestContext(c)
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
 	scope, err := h.getNamespaceAccessScope(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		writeError(c, http.StatusUnauthorized, err)
 		return
 	}
 
 	if scope.unrestricted {
 		list, err := h.getK8s(c).Clientset.CoreV1().Services("").List(ctx, listOpts)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			writeError(c, http.StatusInternalServerError, err)
 			return
 		}
-		c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
+		writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
 		return
 	}
 
 	items := make([]corev1.Service, 0)
 	for _, ns := range scope.allowed {
-		list, err := h.getK8s(c).Clientset.CoreV1().Services(ns).List(ctx, metav1.ListOptions{})
+		list, err := h.getK8s(c).Clientset.CoreV1().Services(ns).List(ctx, selectorListOptions(listOpts))
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			writeError(c, http.StatusInternalServerError, err)
 			return
 		}
 		items = append(items, list.Items...)
@@ -1254,19 +1616,19 @@ func (h *Handler) ListAllServices(c *gin.Context) {
 
 	paged, nextToken, err := paginateSlice(items, listOpts.Limit, listOpts.Continue)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, ListResponse{Items: paged, Total: len(items), Continue: nextToken})
+	writeList(c, ListResponse{Items: paged, Total: len(items), Continue: nextToken})
 }
 
 func (h *Handler) ListServices(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	scope, err := h.getNamespaceAccessScope(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		writeError(c, http.StatusUnauthorized, err)
 		return
 	}
 	if !namespaceAllowed(scope, namespace) {
@@ -1274,275 +1636,299 @@ func (h *Handler) ListServices(c *gin.Context) {
 		return
 	}
 
-	list, err := h.getK8s(c).Clientset.CoreV1().Services(namespace).List(ctx, parseListOptions(c))
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+	list, err := h.getK8s(c).Clientset.CoreV1().Services(namespace).List(ctx, listOpts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
+	writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
 }
 
 func (h *Handler) GetService(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 	svc, err := h.getK8s(c).Clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 	c.JSON(http.StatusOK, svc)
 }
 
 func (h *Handler) DeleteService(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 	err := h.getK8s(c).Clientset.CoreV1().Services(namespace).Delete(ctx, name, metav1.DeleteOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
 }
 
 func (h *Handler) GetServiceYAML(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 	svc, err := h.getK8s(c).Clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 	svc.ManagedFields = nil
 	yamlBytes, err := yaml.Marshal(svc)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.String(http.StatusOK, string(yamlBytes))
 }
 
 func (h *Handler) CreateService(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	var svc corev1.Service
 	if err := c.ShouldBindJSON(&svc); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+	if !validateObject(c, &svc, namespace, "") {
 		return
 	}
-	svc.Namespace = namespace
 	created, err := h.getK8s(c).Clientset.CoreV1().Services(namespace).Create(ctx, &svc, metav1.CreateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusCreated, created)
 }
 
 func (h *Handler) UpdateService(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 	var svc corev1.Service
 	if err := c.ShouldBindJSON(&svc); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
-	svc.Namespace = namespace
-	svc.Name = name
+	if !validateObject(c, &svc, namespace, name) {
+		return
+	}
+	before := auditBefore(h.getK8s(c).Clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{}))
 	updated, err := h.getK8s(c).Clientset.CoreV1().Services(namespace).Update(ctx, &svc, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
+	recordAuditDiff(c, before, updated)
 	c.JSON(http.StatusOK, updated)
 }
 
 func (h *Handler) UpdateServiceYAML(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 
 	body, err := io.ReadAll(c.Request.Body)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	var svc corev1.Service
-	if err := yaml.Unmarshal(body, &svc); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !decodeManifest(c, body, &svc) || !validateObject(c, &svc, namespace, name) {
 		return
 	}
 
-	svc.Namespace = namespace
-	svc.Name = name
-
+	before := auditBefore(h.getK8s(c).Clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{}))
 	updated, err := h.getK8s(c).Clientset.CoreV1().Services(namespace).Update(ctx, &svc, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
+	recordAuditDiff(c, before, updated)
 	c.JSON(http.StatusOK, updated)
 }
 
 // ========== Ingresses ==========
 
 func (h *Handler) ListAllIngresses(c *gin.Context) {
-	ctx := context.Background()
-	list, err := h.getK8s(c).Clientset.NetworkingV1().Ingresses("").List(ctx, metav1.ListOptions{})
+	ctx := requestContext(c)
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+	list, err := h.getK8s(c).Clientset.NetworkingV1().Ingresses("").List(ctx, listOpts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items)})
+	writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
 }
 
 func (h *Handler) ListIngresses(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
-	list, err := h.getK8s(c).Clientset.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+	list, err := h.getK8s(c).Clientset.NetworkingV1().Ingresses(namespace).List(ctx, listOpts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items)})
+	writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
 }
 
 func (h *Handler) GetIngress(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 	ing, err := h.getK8s(c).Clientset.NetworkingV1().Ingresses(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
-	c.JSON(http.StatusOK, ing)
+	c.JSON(http.StatusOK, IngressDetail{
+		Ingress:         ing,
+		TLSCertificates: ingressTLSCertificates(ctx, h.getK8s(c).Clientset, ing),
+	})
 }
 
 func (h *Handler) DeleteIngress(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 	err := h.getK8s(c).Clientset.NetworkingV1().Ingresses(namespace).Delete(ctx, name, metav1.DeleteOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
 }
 
 func (h *Handler) CreateIngress(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	var ing networkingv1.Ingress
 	if err := c.ShouldBindJSON(&ing); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+	if !validateObject(c, &ing, namespace, "") {
 		return
 	}
-	ing.Namespace = namespace
 	created, err := h.getK8s(c).Clientset.NetworkingV1().Ingresses(namespace).Create(ctx, &ing, metav1.CreateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusCreated, created)
 }
 
 func (h *Handler) UpdateIngress(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 	var ing networkingv1.Ingress
 	if err := c.ShouldBindJSON(&ing); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+	if !validateObject(c, &ing, namespace, name) {
 		return
 	}
-	ing.Namespace = namespace
-	ing.Name = name
+	before := auditBefore(h.getK8s(c).Clientset.NetworkingV1().Ingresses(namespace).Get(ctx, name, metav1.GetOptions{}))
 	updated, err := h.getK8s(c).Clientset.NetworkingV1().Ingresses(namespace).Update(ctx, &ing, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
+	recordAuditDiff(c, before, updated)
 	c.JSON(http.StatusOK, updated)
 }
 
 func (h *Handler) GetIngressYAML(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 	ing, err := h.getK8s(c).Clientset.NetworkingV1().Ingresses(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 	ing.ManagedFields = nil
 	yamlBytes, err := yaml.Marshal(ing)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.String(http.StatusOK, string(yamlBytes))
 }
 
 func (h *Handler) UpdateIngressYAML(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 
 	body, err := io.ReadAll(c.Request.Body)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	var ing networkingv1.Ingress
-	if err := yaml.Unmarshal(body, &ing); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !decodeManifest(c, body, &ing) || !validateObject(c, &ing, namespace, name) {
 		return
 	}
 
-	ing.Namespace = namespace
-	ing.Name = name
-
+	before := auditBefore(h.getK8s(c).Clientset.NetworkingV1().Ingresses(namespace).Get(ctx, name, metav1.GetOptions{}))
 	updated, err := h.getK8s(c).Clientset.NetworkingV1().Ingresses(namespace).Update(ctx, &ing, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
+	recordAuditDiff(c, before, updated)
 	c.JSON(http.StatusOK, updated)
 }
 
 // ========== ConfigMaps ==========
 
 func (h *Handler) ListAllConfigMaps(c *gin.Context) {
-	ctx := context.Background()
-	listOpts := parseListOptions(c)
+	ctx := requestContext(c)
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
 	scope, err := h.getNamespaceAccessScope(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		writeError(c, http.StatusUnauthorized, err)
 		return
 	}
 
 	if scope.unrestricted {
 		list, err := h.getK8s(c).Clientset.CoreV1().ConfigMaps("").List(ctx, listOpts)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			writeError(c, http.StatusInternalServerError, err)
 			return
 		}
-		c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
+		writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
 		return
 	}
 
 	items := make([]corev1.ConfigMap, 0)
 	for _, ns := range scope.allowed {
-		list, err := h.getK8s(c).Clientset.CoreV1().ConfigMaps(ns).List(ctx, metav1.ListOptions{})
+		list, err := h.getK8s(c).Clientset.CoreV1().ConfigMaps(ns).List(ctx, selectorListOptions(listOpts))
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			writeError(c, http.StatusInternalServerError, err)
 			return
 		}
 		items = append(items, list.Items...)
@@ -1550,19 +1936,19 @@ func (h *Handler) ListAllConfigMaps(c *gin.Context) {
 
 	paged, nextToken, err := paginateSlice(items, listOpts.Limit, listOpts.Continue)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, ListResponse{Items: paged, Total: len(items), Continue: nextToken})
+	writeList(c, ListResponse{Items: paged, Total: len(items), Continue: nextToken})
 }
 
 func (h *Handler) ListConfigMaps(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	scope, err := h.getNamespaceAccessScope(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		writeError(c, http.StatusUnauthorized, err)
 		return
 	}
 	if !namespaceAllowed(scope, namespace) {
@@ -1570,86 +1956,102 @@ func (h *Handler) ListConfigMaps(c *gin.Context) {
 		return
 	}
 
-	list, err := h.getK8s(c).Clientset.CoreV1().ConfigMaps(namespace).List(ctx, parseListOptions(c))
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+	list, err := h.getK8s(c).Clientset.CoreV1().ConfigMaps(namespace).List(ctx, listOpts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
+	writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
 }
 
 func (h *Handler) GetConfigMap(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 	cm, err := h.getK8s(c).Clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 	c.JSON(http.StatusOK, cm)
 }
 
 func (h *Handler) CreateConfigMap(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	var cm corev1.ConfigMap
 	if err := c.ShouldBindJSON(&cm); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+	if !validateObject(c, &cm, namespace, "") {
 		return
 	}
 	result, err := h.getK8s(c).Clientset.CoreV1().ConfigMaps(namespace).Create(ctx, &cm, metav1.CreateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusCreated, result)
 }
 
 func (h *Handler) UpdateConfigMap(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	var cm corev1.ConfigMap
 	if err := c.ShouldBindJSON(&cm); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+	if !validateObject(c, &cm, namespace, c.Param("name")) {
 		return
 	}
+	before := auditBefore(h.getK8s(c).Clientset.CoreV1().ConfigMaps(namespace).Get(ctx, c.Param("name"), metav1.GetOptions{}))
 	result, err := h.getK8s(c).Clientset.CoreV1().ConfigMaps(namespace).Update(ctx, &cm, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, result)
+	recordAuditDiff(c, before, result)
+	respondConfigMapUpdate(c, h.getK8s(c).Clientset, result)
 }
 
+// DeleteConfigMap 删除 ConfigMap；仍被工作负载引用时返回 409，force=true 跳过检查
 func (h *Handler) DeleteConfigMap(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
+	if !checkConfigInUse(c, h.getK8s(c).Clientset, "ConfigMap") {
+		return
+	}
 	err := h.getK8s(c).Clientset.CoreV1().ConfigMaps(namespace).Delete(ctx, name, metav1.DeleteOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
 }
 
 func (h *Handler) GetConfigMapYAML(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 
 	// 获取 ConfigMap
 	cm, err := h.getK8s(c).Clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 
 	// 转换为 YAML
 	yamlBytes, err := yaml.Marshal(cm)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -1657,61 +2059,65 @@ func (h *Handler) GetConfigMapYAML(c *gin.Context) {
 }
 
 func (h *Handler) UpdateConfigMapYAML(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 
 	// 读取 YAML 内容
 	body, err := io.ReadAll(c.Request.Body)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	// 解析 YAML 为 ConfigMap 对象
 	var cm corev1.ConfigMap
-	if err := yaml.Unmarshal(body, &cm); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid YAML: " + err.Error()})
+	if !decodeManifest(c, body, &cm) || !validateObject(c, &cm, namespace, c.Param("name")) {
 		return
 	}
 
+	before := auditBefore(h.getK8s(c).Clientset.CoreV1().ConfigMaps(namespace).Get(ctx, c.Param("name"), metav1.GetOptions{}))
 	// 更新 ConfigMap
 	result, err := h.getK8s(c).Clientset.CoreV1().ConfigMaps(namespace).Update(ctx, &cm, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	recordAuditDiff(c, before, result)
+	respondConfigMapUpdate(c, h.getK8s(c).Clientset, result)
 }
 
 // ========== Secrets ==========
 
 func (h *Handler) ListAllSecrets(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	view := parseSecretView(c)
-	listOpts := parseListOptions(c)
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
 	scope, err := h.getNamespaceAccessScope(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		writeError(c, http.StatusUnauthorized, err)
 		return
 	}
 
 	if scope.unrestricted {
 		list, err := h.getK8s(c).Clientset.CoreV1().Secrets("").List(ctx, listOpts)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			writeError(c, http.StatusInternalServerError, err)
 			return
 		}
 		masked := maskSecrets(list.Items, view)
-		c.JSON(http.StatusOK, ListResponse{Items: masked, Total: len(masked), Continue: list.Continue})
+		writeList(c, ListResponse{Items: masked, Total: len(masked), Continue: list.Continue})
 		return
 	}
 
 	items := make([]corev1.Secret, 0)
 	for _, ns := range scope.allowed {
-		list, err := h.getK8s(c).Clientset.CoreV1().Secrets(ns).List(ctx, metav1.ListOptions{})
+		list, err := h.getK8s(c).Clientset.CoreV1().Secrets(ns).List(ctx, selectorListOptions(listOpts))
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			writeError(c, http.StatusInternalServerError, err)
 			return
 		}
 		items = append(items, list.Items...)
@@ -1719,21 +2125,21 @@ func (h *Handler) ListAllSecrets(c *gin.Context) {
 
 	paged, nextToken, err := paginateSlice(items, listOpts.Limit, listOpts.Continue)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	masked := maskSecrets(paged, view)
-	c.JSON(http.StatusOK, ListResponse{Items: masked, Total: len(items), Continue: nextToken})
+	writeList(c, ListResponse{Items: masked, Total: len(items), Continue: nextToken})
 }
 
 func (h *Handler) ListSecrets(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	view := parseSecretView(c)
 	scope, err := h.getNamespaceAccessScope(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		writeError(c, http.StatusUnauthorized, err)
 		return
 	}
 	if !namespaceAllowed(scope, namespace) {
@@ -1741,24 +2147,28 @@ func (h *Handler) ListSecrets(c *gin.Context) {
 		return
 	}
 
-	list, err := h.getK8s(c).Clientset.CoreV1().Secrets(namespace).List(ctx, parseListOptions(c))
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+	list, err := h.getK8s(c).Clientset.CoreV1().Secrets(namespace).List(ctx, listOpts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	masked := maskSecrets(list.Items, view)
-	c.JSON(http.StatusOK, ListResponse{Items: masked, Total: len(masked), Continue: list.Continue})
+	writeList(c, ListResponse{Items: masked, Total: len(masked), Continue: list.Continue})
 }
 
 func (h *Handler) GetSecret(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 	view := parseSecretView(c)
 
 	scope, err := h.getNamespaceAccessScope(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		writeError(c, http.StatusUnauthorized, err)
 		return
 	}
 	if !namespaceAllowed(scope, namespace) {
@@ -1768,65 +2178,78 @@ func (h *Handler) GetSecret(c *gin.Context) {
 
 	secret, err := h.getK8s(c).Clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 	c.JSON(http.StatusOK, maskSecret(*secret, view))
 }
 
 func (h *Handler) CreateSecret(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	var secret corev1.Secret
 	if err := c.ShouldBindJSON(&secret); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+	if !validateObject(c, &secret, namespace, "") {
 		return
 	}
 	result, err := h.getK8s(c).Clientset.CoreV1().Secrets(namespace).Create(ctx, &secret, metav1.CreateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusCreated, result)
+	c.JSON(http.StatusCreated, maskSecret(*result, "masked"))
 }
 
 func (h *Handler) UpdateSecret(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	var secret corev1.Secret
 	if err := c.ShouldBindJSON(&secret); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
+	if !validateObject(c, &secret, namespace, c.Param("name")) {
+		return
+	}
+	before := auditBefore(h.getK8s(c).Clientset.CoreV1().Secrets(namespace).Get(ctx, c.Param("name"), metav1.GetOptions{}))
+	restoreRedactedSecretData(&secret, before)
 	result, err := h.getK8s(c).Clientset.CoreV1().Secrets(namespace).Update(ctx, &secret, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, result)
+	recordAuditDiff(c, newSecretAuditView(before), newSecretAuditView(result))
+	c.JSON(http.StatusOK, maskSecret(*result, "masked"))
 }
 
+// DeleteSecret 删除 Secret；仍被工作负载引用时返回 409，force=true 跳过检查
 func (h *Handler) DeleteSecret(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
+	if !checkConfigInUse(c, h.getK8s(c).Clientset, "Secret") {
+		return
+	}
 	err := h.getK8s(c).Clientset.CoreV1().Secrets(namespace).Delete(ctx, name, metav1.DeleteOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
 }
 
 func (h *Handler) GetSecretYAML(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 	view := parseSecretView(c)
 
 	scope, err := h.getNamespaceAccessScope(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		writeError(c, http.StatusUnauthorized, err)
 		return
 	}
 	if !namespaceAllowed(scope, namespace) {
@@ -1837,7 +2260,7 @@ func (h *Handler) GetSecretYAML(c *gin.Context) {
 	// 获取 Secret
 	secret, err := h.getK8s(c).Clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -1846,7 +2269,7 @@ func (h *Handler) GetSecretYAML(c *gin.Context) {
 	// 转换为 YAML
 	yamlBytes, err := yaml.Marshal(masked)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -1854,62 +2277,69 @@ func (h *Handler) GetSecretYAML(c *gin.Context) {
 }
 
 func (h *Handler) UpdateSecretYAML(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 
 	// 读取 YAML 内容
 	body, err := io.ReadAll(c.Request.Body)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	// 解析 YAML 为 Secret 对象
 	var secret corev1.Secret
-	if err := yaml.Unmarshal(body, &secret); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid YAML: " + err.Error()})
+	if !decodeManifest(c, body, &secret) || !validateObject(c, &secret, namespace, c.Param("name")) {
 		return
 	}
 
+	before := auditBefore(h.getK8s(c).Clientset.CoreV1().Secrets(namespace).Get(ctx, c.Param("name"), metav1.GetOptions{}))
+	// GET /yaml 返回的是脱敏内容，未修改的键保留原值
+	restoreRedactedSecretData(&secret, before)
 	// 更新 Secret
 	result, err := h.getK8s(c).Clientset.CoreV1().Secrets(namespace).Update(ctx, &secret, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	recordAuditDiff(c, newSecretAuditView(before), newSecretAuditView(result))
+	c.JSON(http.StatusOK, maskSecret(*result, "masked"))
 }
 
 // ========== PersistentVolumes ==========
 
 func (h *Handler) ListPersistentVolumes(c *gin.Context) {
-	ctx := context.Background()
-	list, err := h.getK8s(c).Clientset.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	ctx := requestContext(c)
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+	list, err := h.getK8s(c).Clientset.CoreV1().PersistentVolumes().List(ctx, listOpts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items)})
+	writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
 }
 
 func (h *Handler) GetPersistentVolume(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	name := c.Param("name")
 	pv, err := h.getK8s(c).Clientset.CoreV1().PersistentVolumes().Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 	c.JSON(http.StatusOK, pv)
 }
 
 func (h *Handler) DeletePersistentVolume(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	name := c.Param("name")
 	err := h.getK8s(c).Clientset.CoreV1().PersistentVolumes().Delete(ctx, name, metav1.DeleteOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
@@ -1918,29 +2348,32 @@ func (h *Handler) DeletePersistentVolume(c *gin.Context) {
 // ========== PersistentVolumeClaims ==========
 
 func (h *Handler) ListAllPersistentVolumeClaims(c *gin.Context) {
-	ctx := context.Background()
-	listOpts := parseListOptions(c)
+	ctx := requestContext(c)
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
 	scope, err := h.getNamespaceAccessScope(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		writeError(c, http.StatusUnauthorized, err)
 		return
 	}
 
 	if scope.unrestricted {
 		list, err := h.getK8s(c).Clientset.CoreV1().PersistentVolumeClaims("").List(ctx, listOpts)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			writeError(c, http.StatusInternalServerError, err)
 			return
 		}
-		c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
+		c.JSON(http.StatusOK, listView(c, ListResponse{Items: h.pvcListItems(c, list.Items), Total: len(list.Items), Continue: list.Continue}))
 		return
 	}
 
 	items := make([]corev1.PersistentVolumeClaim, 0)
 	for _, ns := range scope.allowed {
-		list, err := h.getK8s(c).Clientset.CoreV1().PersistentVolumeClaims(ns).List(ctx, metav1.ListOptions{})
+		list, err := h.getK8s(c).Clientset.CoreV1().PersistentVolumeClaims(ns).List(ctx, selectorListOptions(listOpts))
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			writeError(c, http.StatusInternalServerError, err)
 			return
 		}
 		items = append(items, list.Items...)
@@ -1948,19 +2381,19 @@ func (h *Handler) ListAllPersistentVolumeClaims(c *gin.Context) {
 
 	paged, nextToken, err := paginateSlice(items, listOpts.Limit, listOpts.Continue)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, ListResponse{Items: paged, Total: len(items), Continue: nextToken})
+	c.JSON(http.StatusOK, listView(c, ListResponse{Items: h.pvcListItems(c, paged), Total: len(items), Continue: nextToken}))
 }
 
 func (h *Handler) ListPersistentVolumeClaims(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	scope, err := h.getNamespaceAccessScope(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		writeError(c, http.StatusUnauthorized, err)
 		return
 	}
 	if !namespaceAllowed(scope, namespace) {
@@ -1968,33 +2401,25 @@ func (h *Handler) ListPersistentVolumeClaims(c *gin.Context) {
 		return
 	}
 
-	list, err := h.getK8s(c).Clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, parseListOptions(c))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	listOpts, ok := parseListOptions(c)
+	if !ok {
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
-}
-
-func (h *Handler) GetPersistentVolumeClaim(c *gin.Context) {
-	ctx := context.Background()
-	namespace := c.Param("ns")
-	name := c.Param("name")
-	pvc, err := h.getK8s(c).Clientset.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+	list, err := h.getK8s(c).Clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, listOpts)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, pvc)
+	c.JSON(http.StatusOK, listView(c, ListResponse{Items: h.pvcListItems(c, list.Items), Total: len(list.Items), Continue: list.Continue}))
 }
 
 func (h *Handler) DeletePersistentVolumeClaim(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 	err := h.getK8s(c).Clientset.CoreV1().PersistentVolumeClaims(namespace).Delete(ctx, name, metav1.DeleteOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
@@ -2003,21 +2428,25 @@ func (h *Handler) DeletePersistentVolumeClaim(c *gin.Context) {
 // ========== StorageClasses ==========
 
 func (h *Handler) ListStorageClasses(c *gin.Context) {
-	ctx := context.Background()
-	list, err := h.getK8s(c).Clientset.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ctx := requestContext(c)
+	listOpts, ok := parseListOptions(c)
+	if !ok {
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items)})
+	list, err := h.getK8s(c).Clientset.StorageV1().StorageClasses().List(ctx, listOpts)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
 }
 
 func (h *Handler) GetStorageClass(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	name := c.Param("name")
 	sc, err := h.getK8s(c).Clientset.StorageV1().StorageClasses().Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 	c.JSON(http.StatusOK, sc)
@@ -2026,50 +2455,54 @@ func (h *Handler) GetStorageClass(c *gin.Context) {
 // ========== Nodes ==========
 
 func (h *Handler) ListNodes(c *gin.Context) {
-	ctx := context.Background()
-	list, err := h.getK8s(c).Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	ctx := requestContext(c)
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+	list, err := h.getK8s(c).Clientset.CoreV1().Nodes().List(ctx, listOpts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items)})
+	writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
 }
 
 func (h *Handler) GetNode(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	name := c.Param("name")
 	node, err := h.getK8s(c).Clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 	c.JSON(http.StatusOK, node)
 }
 
 func (h *Handler) GetNodeYAML(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	name := c.Param("name")
 	node, err := h.getK8s(c).Clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 	node.ManagedFields = nil
 	yamlBytes, err := yaml.Marshal(node)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.String(http.StatusOK, string(yamlBytes))
 }
 
 func (h *Handler) GetNodeMetrics(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	name := c.Param("name")
 
 	node, err := h.getK8s(c).Clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -2113,59 +2546,59 @@ func (h *Handler) GetNodeMetrics(c *gin.Context) {
 }
 
 func (h *Handler) GetNodePods(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	name := c.Param("name")
 
 	pods, err := h.getK8s(c).Clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{
 		FieldSelector: fmt.Sprintf("spec.nodeName=%s", name),
 	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: pods.Items, Total: len(pods.Items)})
+	writeList(c, ListResponse{Items: pods.Items, Total: len(pods.Items)})
 }
 
 func (h *Handler) CordonNode(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	name := c.Param("name")
 
 	node, err := h.getK8s(c).Clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 
 	node.Spec.Unschedulable = true
 	_, err = h.getK8s(c).Clientset.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "cordoned"})
 }
 
 func (h *Handler) UncordonNode(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	name := c.Param("name")
 
 	node, err := h.getK8s(c).Clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 
 	node.Spec.Unschedulable = false
 	_, err = h.getK8s(c).Clientset.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "uncordoned"})
 }
 
 func (h *Handler) DrainNode(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	name := c.Param("name")
 
 	var req struct {
@@ -2194,13 +2627,13 @@ func (h *Handler) DrainNode(c *gin.Context) {
 	// 先 cordon
 	node, err := h.getK8s(c).Clientset.CoreV1().Nodes().Get(drainCtx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 	node.Spec.Unschedulable = true
 	_, err = h.getK8s(c).Clientset.CoreV1().Nodes().Update(drainCtx, node, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -2208,7 +2641,7 @@ func (h *Handler) DrainNode(c *gin.Context) {
 		FieldSelector: fmt.Sprintf("spec.nodeName=%s", name),
 	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -2319,29 +2752,32 @@ func (h *Handler) DrainNode(c *gin.Context) {
 // ========== Events ==========
 
 func (h *Handler) ListAllEvents(c *gin.Context) {
-	ctx := context.Background()
-	listOpts := parseListOptions(c)
+	ctx := requestContext(c)
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
 	scope, err := h.getNamespaceAccessScope(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		writeError(c, http.StatusUnauthorized, err)
 		return
 	}
 
 	if scope.unrestricted {
 		list, err := h.getK8s(c).Clientset.CoreV1().Events("").List(ctx, listOpts)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			writeError(c, http.StatusInternalServerError, err)
 			return
 		}
-		c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
+		writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
 		return
 	}
 
 	items := make([]corev1.Event, 0)
 	for _, ns := range scope.allowed {
-		list, err := h.getK8s(c).Clientset.CoreV1().Events(ns).List(ctx, metav1.ListOptions{})
+		list, err := h.getK8s(c).Clientset.CoreV1().Events(ns).List(ctx, selectorListOptions(listOpts))
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			writeError(c, http.StatusInternalServerError, err)
 			return
 		}
 		items = append(items, list.Items...)
@@ -2349,19 +2785,19 @@ func (h *Handler) ListAllEvents(c *gin.Context) {
 
 	paged, nextToken, err := paginateSlice(items, listOpts.Limit, listOpts.Continue)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, ListResponse{Items: paged, Total: len(items), Continue: nextToken})
+	writeList(c, ListResponse{Items: paged, Total: len(items), Continue: nextToken})
 }
 
 func (h *Handler) ListEvents(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	scope, err := h.getNamespaceAccessScope(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		writeError(c, http.StatusUnauthorized, err)
 		return
 	}
 	if !namespaceAllowed(scope, namespace) {
@@ -2369,82 +2805,105 @@ func (h *Handler) ListEvents(c *gin.Context) {
 		return
 	}
 
-	list, err := h.getK8s(c).Clientset.CoreV1().Events(namespace).List(ctx, parseListOptions(c))
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+	list, err := h.getK8s(c).Clientset.CoreV1().Events(namespace).List(ctx, listOpts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
+	writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
 }
 
 // ========== RBAC ==========
 
 func (h *Handler) ListRoles(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
-	list, err := h.getK8s(c).Clientset.RbacV1().Roles(namespace).List(ctx, metav1.ListOptions{})
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+	list, err := h.getK8s(c).Clientset.RbacV1().Roles(namespace).List(ctx, listOpts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items)})
+	writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
 }
 
 func (h *Handler) ListClusterRoles(c *gin.Context) {
-	ctx := context.Background()
-	list, err := h.getK8s(c).Clientset.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{})
+	ctx := requestContext(c)
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+	list, err := h.getK8s(c).Clientset.RbacV1().ClusterRoles().List(ctx, listOpts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items)})
+	writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
 }
 
 func (h *Handler) ListRoleBindings(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
-	list, err := h.getK8s(c).Clientset.RbacV1().RoleBindings(namespace).List(ctx, metav1.ListOptions{})
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+	list, err := h.getK8s(c).Clientset.RbacV1().RoleBindings(namespace).List(ctx, listOpts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items)})
+	writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
 }
 
 func (h *Handler) ListClusterRoleBindings(c *gin.Context) {
-	ctx := context.Background()
-	list, err := h.getK8s(c).Clientset.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+	ctx := requestContext(c)
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+	list, err := h.getK8s(c).Clientset.RbacV1().ClusterRoleBindings().List(ctx, listOpts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items)})
+	writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
 }
 
 func (h *Handler) ListAllServiceAccounts(c *gin.Context) {
-	ctx := context.Background()
-	listOpts := parseListOptions(c)
+	ctx := requestContext(c)
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
 	scope, err := h.getNamespaceAccessScope(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		writeError(c, http.StatusUnauthorized, err)
 		return
 	}
 
 	if scope.unrestricted {
 		list, err := h.getK8s(c).Clientset.CoreV1().ServiceAccounts("").List(ctx, listOpts)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			writeError(c, http.StatusInternalServerError, err)
 			return
 		}
-		c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
+		writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
 		return
 	}
 
 	items := make([]corev1.ServiceAccount, 0)
 	for _, ns := range scope.allowed {
-		list, err := h.getK8s(c).Clientset.CoreV1().ServiceAccounts(ns).List(ctx, metav1.ListOptions{})
+		list, err := h.getK8s(c).Clientset.CoreV1().ServiceAccounts(ns).List(ctx, selectorListOptions(listOpts))
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			writeError(c, http.StatusInternalServerError, err)
 			return
 		}
 		items = append(items, list.Items...)
@@ -2452,19 +2911,19 @@ func (h *Handler) ListAllServiceAccounts(c *gin.Context) {
 
 	paged, nextToken, err := paginateSlice(items, listOpts.Limit, listOpts.Continue)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, ListResponse{Items: paged, Total: len(items), Continue: nextToken})
+	writeList(c, ListResponse{Items: paged, Total: len(items), Continue: nextToken})
 }
 
 func (h *Handler) ListServiceAccounts(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	scope, err := h.getNamespaceAccessScope(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		writeError(c, http.StatusUnauthorized, err)
 		return
 	}
 	if !namespaceAllowed(scope, namespace) {
@@ -2472,19 +2931,92 @@ func (h *Handler) ListServiceAccounts(c *gin.Context) {
 		return
 	}
 
-	list, err := h.getK8s(c).Clientset.CoreV1().ServiceAccounts(namespace).List(ctx, parseListOptions(c))
+	listOpts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+	list, err := h.getK8s(c).Clientset.CoreV1().ServiceAccounts(namespace).List(ctx, listOpts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
+	writeList(c, ListResponse{Items: list.Items, Total: len(list.Items), Continue: list.Continue})
 }
 
 // ========== WebSocket 占位 ==========
 
+// StreamPodLogs 通过 WebSocket 持续推送 Pod 日志（follow），与终端共用空闲超时与最长会话时长限制。
+// 支持 container、tailLines、sinceTime、timestamps 参数；客户端发送任意消息即视为活动
 func (h *Handler) StreamPodLogs(c *gin.Context) {
-	// TODO: 实现 WebSocket 日志流
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "not implemented"})
+	filter, err := parseLogFilter(c, "100")
+	if err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+	namespace := c.Query("namespace")
+	name := c.Query("name")
+	if ticket := middleware.GetWSTicket(c); ticket != nil {
+		namespace = ticket.Namespace
+		name = ticket.Name
+		if ticket.Container != "" {
+			filter.Container = ticket.Container
+		}
+	}
+	if namespace == "" || name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace and name are required"})
+		return
+	}
+	if filter.filtering() || filter.Previous {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "日志流不支持 grep、untilTime 与 previous"})
+		return
+	}
+	opts := filter.podLogOptions()
+	opts.Follow = true
+
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool {
+			return h.isAllowedWSOrigin(r)
+		},
+	}
+	ws, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer ws.Close()
+
+	session, sessionCtx := newWSSession(ws, h.wsSessionLimits())
+	defer session.Close()
+
+	// 客户端断开时结束日志流
+	go func() {
+		defer session.Close()
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				return
+			}
+			session.Touch()
+		}
+	}()
+
+	logs, err := h.getK8s(c).Clientset.CoreV1().Pods(namespace).GetLogs(name, opts).Stream(sessionCtx)
+	if err != nil {
+		session.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf("Error streaming logs: %v\r\n", err)))
+		return
+	}
+	defer logs.Close()
+
+	buf := make([]byte, 4096)
+	for {
+		n, err := logs.Read(buf)
+		if n > 0 {
+			if werr := session.WriteMessage(websocket.TextMessage, buf[:n]); werr != nil {
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
 }
 
 func (h *Handler) ExecPod(c *gin.Context) {
@@ -2513,7 +3045,7 @@ func (h *Handler) ExecPod(c *gin.Context) {
 	// 升级为 WebSocket 连接
 	upgrader := websocket.Upgrader{
 		CheckOrigin: func(r *http.Request) bool {
-			return isAllowedExecOrigin(r)
+			return h.isAllowedWSOrigin(r)
 		},
 	}
 
@@ -2524,6 +3056,10 @@ func (h *Handler) ExecPod(c *gin.Context) {
 	}
 	defer ws.Close()
 
+	// 空闲超时与最长会话时长限制
+	session, sessionCtx := newWSSession(ws, h.wsSessionLimits())
+	defer session.Close()
+
 	// 创建 exec 请求
 	req := h.getK8s(c).Clientset.CoreV1().RESTClient().Post().
 		Resource("pods").
@@ -2543,10 +3079,13 @@ func (h *Handler) ExecPod(c *gin.Context) {
 	config := h.getK8s(c).Config
 	exec, err := remotecommand.NewSPDYExecutor(config, "POST", req.URL())
 	if err != nil {
-		ws.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf("Error creating executor: %v\r\n", err)))
+		session.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf("Error creating executor: %v\r\n", err)))
 		return
 	}
 
+	// 会话录制（合规审查用）
+	recorder, sessionID := h.startTerminalRecording(c, namespace, name, container, command)
+
 	// 创建双向通道
 	stdinReader, stdinWriter := io.Pipe()
 	stdoutReader, stdoutWriter := io.Pipe()
@@ -2559,12 +3098,18 @@ func (h *Handler) ExecPod(c *gin.Context) {
 			if err != nil {
 				return
 			}
+			session.Touch()
+			if recorder != nil {
+				recorder.Input(message)
+			}
 			stdinWriter.Write(message)
 		}
 	}()
 
 	// 处理容器 stdout -> WebSocket 输出
+	outputDone := make(chan struct{})
 	go func() {
+		defer close(outputDone)
 		buf := make([]byte, 4096)
 		for {
 			n, err := stdoutReader.Read(buf)
@@ -2572,33 +3117,41 @@ func (h *Handler) ExecPod(c *gin.Context) {
 				return
 			}
 			if n > 0 {
-				ws.WriteMessage(websocket.BinaryMessage, buf[:n])
+				if recorder != nil {
+					recorder.Output(buf[:n])
+				}
+				session.WriteMessage(websocket.BinaryMessage, buf[:n])
 			}
 		}
 	}()
 
 	// 执行命令
-	err = exec.StreamWithContext(context.Background(), remotecommand.StreamOptions{
+	err = exec.StreamWithContext(sessionCtx, remotecommand.StreamOptions{
 		Stdin:  stdinReader,
 		Stdout: stdoutWriter,
 		Stderr: stdoutWriter,
 		Tty:    true,
 	})
 
-	if err != nil {
-		ws.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf("\r\nSession ended: %v\r\n", err)))
+	// 等待输出全部转发完成后再落盘录制内容
+	stdoutWriter.Close()
+	<-outputDone
+	h.finishTerminalRecording(recorder, sessionID)
+
+	if err != nil && sessionCtx.Err() == nil {
+		session.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf("\r\nSession ended: %v\r\n", err)))
 	}
 }
 
-func isAllowedExecOrigin(r *http.Request) bool {
+// isAllowedWSOrigin 校验 WebSocket 握手的 Origin：配置了 WSAllowedOrigins 时按列表匹配，否则要求同源
+func (h *Handler) isAllowedWSOrigin(r *http.Request) bool {
 	origin := strings.TrimSpace(r.Header.Get("Origin"))
 	if origin == "" {
 		return false
 	}
 
-	allowedEnv := strings.TrimSpace(os.Getenv("WS_ALLOWED_ORIGINS"))
-	if allowedEnv != "" {
-		for _, item := range strings.Split(allowedEnv, ",") {
+	if len(h.opts.WSAllowedOrigins) > 0 {
+		for _, item := range h.opts.WSAllowedOrigins {
 			if strings.EqualFold(strings.TrimSpace(item), origin) {
 				return true
 			}
@@ -2625,33 +3178,86 @@ func (h *Handler) WatchResources(c *gin.Context) {
 
 // GetClusterMetrics 获取集群指标
 func (h *Handler) GetClusterMetrics(c *gin.Context) {
-	if h.metrics == nil {
+	if h.getMetrics(c) == nil {
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "metrics client not configured"})
 		return
 	}
 
-	metrics, err := h.metrics.GetClusterMetrics()
+	metrics, err := h.getMetrics(c).WithContext(requestContext(c)).GetClusterMetrics()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
+	// 未部署 kube-state-metrics 时容量序列为空，回落到节点 allocatable（按 nodeCapacityCacheTTL 缓存）
+	if metrics.NeedsCapacityFallback() {
+		if capacity, err := h.nodeCapacity.get(requestContext(c), h.getK8s(c)); err == nil {
+			metrics.ApplyNodeCapacity(capacity.nodes, capacity.runningPods)
+		}
+	}
+
 	c.JSON(http.StatusOK, metrics)
 }
 
+// metricsNamespaces 返回指标查询需要限定的命名空间：nil 表示不限制，空切片表示无可见命名空间。
+// 返回 false 时已写入错误响应
+func (h *Handler) metricsNamespaces(c *gin.Context) ([]string, bool) {
+	scope, err := h.getNamespaceAccessScope(c)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return nil, false
+	}
+	if scope.unrestricted {
+		return nil, true
+	}
+	if scope.allowed == nil {
+		return []string{}, true
+	}
+	return scope.allowed, true
+}
+
+// listPodsInNamespaces 列出指定命名空间的 Pod，namespaces 为 nil 时列出全部命名空间
+func listPodsInNamespaces(ctx context.Context, clientset kubernetes.Interface, namespaces []string) ([]corev1.Pod, error) {
+	if namespaces == nil {
+		list, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	}
+	var pods []corev1.Pod
+	for _, ns := range namespaces {
+		list, err := clientset.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		pods = append(pods, list.Items...)
+	}
+	return pods, nil
+}
+
 // GetCPUHistory 获取 CPU 历史数据
 func (h *Handler) GetCPUHistory(c *gin.Context) {
-	if h.metrics == nil {
+	if h.getMetrics(c) == nil {
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "metrics client not configured"})
 		return
 	}
 
+	namespaces, ok := h.metricsNamespaces(c)
+	if !ok {
+		return
+	}
+	if namespaces != nil && len(namespaces) == 0 {
+		c.JSON(http.StatusOK, gin.H{"data": []metrics.TimeSeriesData{}})
+		return
+	}
+
 	duration := c.DefaultQuery("duration", "1h")
 	step := c.DefaultQuery("step", "1m")
 
-	data, err := h.metrics.GetCPUHistory(duration, step)
+	data, err := h.getMetrics(c).WithContext(requestContext(c)).GetCPUHistory(duration, step, namespaces)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -2660,17 +3266,26 @@ func (h *Handler) GetCPUHistory(c *gin.Context) {
 
 // GetMemoryHistory 获取内存历史数据
 func (h *Handler) GetMemoryHistory(c *gin.Context) {
-	if h.metrics == nil {
+	if h.getMetrics(c) == nil {
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "metrics client not configured"})
 		return
 	}
 
+	namespaces, ok := h.metricsNamespaces(c)
+	if !ok {
+		return
+	}
+	if namespaces != nil && len(namespaces) == 0 {
+		c.JSON(http.StatusOK, gin.H{"data": []metrics.TimeSeriesData{}})
+		return
+	}
+
 	duration := c.DefaultQuery("duration", "1h")
 	step := c.DefaultQuery("step", "1m")
 
-	data, err := h.metrics.GetMemoryHistory(duration, step)
+	data, err := h.getMetrics(c).WithContext(requestContext(c)).GetMemoryHistory(duration, step, namespaces)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -2679,24 +3294,44 @@ func (h *Handler) GetMemoryHistory(c *gin.Context) {
 
 // GetNodeMetricsVM 从 VictoriaMetrics 获取节点指标
 func (h *Handler) GetNodeMetricsVM(c *gin.Context) {
-	if h.metrics == nil {
+	if h.getMetrics(c) == nil {
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "metrics client not configured"})
 		return
 	}
 
 	nodeName := c.Param("name")
-	metrics, err := h.metrics.GetNodeMetrics(nodeName)
+	metrics, err := h.getMetrics(c).WithContext(requestContext(c)).GetNodeMetrics(nodeName)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, metrics)
 }
 
+// GetNodeHistory 获取节点 CPU、内存、磁盘、网络与负载的历史数据，供节点详情页判断资源饱和度
+func (h *Handler) GetNodeHistory(c *gin.Context) {
+	if h.getMetrics(c) == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "metrics client not configured"})
+		return
+	}
+
+	nodeName := c.Param("name")
+	duration := c.DefaultQuery("duration", "1h")
+	step := c.DefaultQuery("step", "1m")
+
+	data, err := h.getMetrics(c).WithContext(requestContext(c)).GetNodeHistory(nodeName, duration, step)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
 // GetPodMetricsVM 从 VictoriaMetrics 获取 Pod 指标
 func (h *Handler) GetPodMetricsVM(c *gin.Context) {
-	if h.metrics == nil {
+	if h.getMetrics(c) == nil {
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "metrics client not configured"})
 		return
 	}
@@ -2704,25 +3339,115 @@ func (h *Handler) GetPodMetricsVM(c *gin.Context) {
 	ns := c.Param("ns")
 	name := c.Param("name")
 
-	metrics, err := h.metrics.GetPodMetrics(ns, name)
+	scope, err := h.getNamespaceAccessScope(c)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if !namespaceAllowed(scope, ns) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "no permission for namespace " + ns})
+		return
+	}
+
+	metrics, err := h.getMetrics(c).WithContext(requestContext(c)).GetPodMetrics(ns, name)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, metrics)
 }
 
+// GetPodContainerMetrics 获取 Pod 内各容器的用量、requests/limits 与 CPU 限流，用于定位 Pod 中的资源大户。
+// Pod 已被删除时只返回指标中的容器用量
+func (h *Handler) GetPodContainerMetrics(c *gin.Context) {
+	if h.getMetrics(c) == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "metrics client not configured"})
+		return
+	}
+
+	ns := c.Param("ns")
+	name := c.Param("name")
+
+	scope, err := h.getNamespaceAccessScope(c)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if !namespaceAllowed(scope, ns) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "no permission for namespace " + ns})
+		return
+	}
+
+	ctx := requestContext(c)
+	result, err := h.getMetrics(c).WithContext(ctx).GetContainerMetrics(ns, name)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	pod, err := h.getK8s(c).Clientset.CoreV1().Pods(ns).Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		result.ApplyPodSpec(pod)
+	case !apierrors.IsNotFound(err):
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetPodHistory 获取单个 Pod 的 CPU 与内存历史数据，供 Pod 详情页绘制用量曲线
+func (h *Handler) GetPodHistory(c *gin.Context) {
+	if h.getMetrics(c) == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "metrics client not configured"})
+		return
+	}
+
+	ns := c.Param("ns")
+	name := c.Param("name")
+
+	scope, err := h.getNamespaceAccessScope(c)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if !namespaceAllowed(scope, ns) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "no permission for namespace " + ns})
+		return
+	}
+
+	duration := c.DefaultQuery("duration", "1h")
+	step := c.DefaultQuery("step", "1m")
+
+	data, err := h.getMetrics(c).WithContext(requestContext(c)).GetPodHistory(ns, name, duration, step)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
 // ListAllPodMetricsVM 批量获取所有 Pod 的指标
 func (h *Handler) ListAllPodMetricsVM(c *gin.Context) {
-	if h.metrics == nil {
+	if h.getMetrics(c) == nil {
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "metrics client not configured"})
 		return
 	}
 
-	podMetrics, err := h.metrics.GetAllPodMetrics()
+	namespaces, ok := h.metricsNamespaces(c)
+	if !ok {
+		return
+	}
+	if namespaces != nil && len(namespaces) == 0 {
+		c.JSON(http.StatusOK, gin.H{"items": []metrics.PodMetrics{}, "total": 0})
+		return
+	}
+
+	podMetrics, err := h.getMetrics(c).WithContext(requestContext(c)).GetAllPodMetrics(namespaces)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -2734,9 +3459,10 @@ func (h *Handler) ListAllPodMetricsVM(c *gin.Context) {
 
 // ========== Alerts (Alertmanager) ==========
 
-// ListAlerts 获取告警列表（支持过滤）
+// ListAlerts 获取告警列表，支持 severity、namespace、alertname、state 过滤与 search 关键字搜索，
+// sort=severity|startsAt|alertname 排序；指定 page 或 pageSize 时分页返回，total 为过滤后的总数
 func (h *Handler) ListAlerts(c *gin.Context) {
-	if h.alerts == nil {
+	if h.getAlerts(c) == nil {
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Alertmanager not configured"})
 		return
 	}
@@ -2747,23 +3473,94 @@ func (h *Handler) ListAlerts(c *gin.Context) {
 		Namespace: c.Query("namespace"),
 		AlertName: c.Query("alertname"),
 		State:     c.DefaultQuery("state", "active"), // 默认只显示活跃告警
+		Search:    c.Query("search"),
+		Sort:      c.Query("sort"),
+	}
+	if !alertmanager.ValidAlertSort(filter.Sort) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort 仅支持 severity、startsAt 或 alertname"})
+		return
 	}
 
-	alerts, err := h.alerts.GetFilteredAlerts(filter)
+	alerts, err := h.getAlerts(c).GetFilteredAlerts(filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
+	total := len(alerts)
+	_, hasPage := c.GetQuery("page")
+	_, hasPageSize := c.GetQuery("pageSize")
+	if !hasPage && !hasPageSize {
+		items := h.withAcknowledgements(c, alerts)
+		c.JSON(http.StatusOK, gin.H{
+			"items": items,
+			"total": total,
+		})
+		return
+	}
+
+	page, pageSize, start, end := alertPage(c.Query("page"), c.Query("pageSize"), total)
+	items := h.withAcknowledgements(c, alerts[start:end])
 	c.JSON(http.StatusOK, gin.H{
-		"items": alerts,
-		"total": len(alerts),
+		"items":    items,
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
 	})
 }
 
+// alertPage 解析告警列表的 page/pageSize，返回规范化后的值与当前页在结果中的范围 [start, end)。
+// page 缺省或小于 1 时为 1；pageSize 缺省或无效时为 50，最大 500；超出最后一页时范围为空
+func alertPage(pageParam, pageSizeParam string, total int) (page, pageSize, start, end int) {
+	page, _ = strconv.Atoi(pageParam)
+	pageSize, _ = strconv.Atoi(pageSizeParam)
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 50
+	}
+	if pageSize > 500 {
+		pageSize = 500
+	}
+	// 先比较页数，避免超大 page 相乘溢出
+	start = total
+	if page-1 < (total+pageSize-1)/pageSize {
+		start = (page - 1) * pageSize
+	}
+	end = min(start+pageSize, total)
+	return page, pageSize, start, end
+}
+
+// AlertItem 告警及其确认状态，acknowledgement 为当前处理人、时间与备注
+type AlertItem struct {
+	alertmanager.Alert
+	Acknowledged    bool                    `json:"acknowledged"`
+	Acknowledgement *alerts.Acknowledgement `json:"acknowledgement,omitempty"`
+}
+
+// withAcknowledgements 为告警标注确认状态；告警服务未启用或查询失败时只返回告警本身
+func (h *Handler) withAcknowledgements(c *gin.Context, list []alertmanager.Alert) []AlertItem {
+	var acks map[string]*alerts.Acknowledgement
+	if h.alertService != nil {
+		var err error
+		acks, err = h.alertService.WithAlertmanager(middleware.GetClusterName(c), nil).ActiveAcknowledgements()
+		if err != nil {
+			log.Printf("查询告警确认失败: %v", err)
+		}
+	}
+
+	items := make([]AlertItem, 0, len(list))
+	for _, alert := range list {
+		ack := acks[alert.Fingerprint]
+		items = append(items, AlertItem{Alert: alert, Acknowledged: ack != nil, Acknowledgement: ack})
+	}
+	return items
+}
+
 // GetAlertDetail 获取告警详情
 func (h *Handler) GetAlertDetail(c *gin.Context) {
-	if h.alerts == nil {
+	if h.getAlerts(c) == nil {
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Alertmanager not configured"})
 		return
 	}
@@ -2774,25 +3571,25 @@ func (h *Handler) GetAlertDetail(c *gin.Context) {
 		return
 	}
 
-	alert, err := h.alerts.GetAlertByFingerprint(fingerprint)
+	alert, err := h.getAlerts(c).GetAlertByFingerprint(fingerprint)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, alert)
+	c.JSON(http.StatusOK, h.withAcknowledgements(c, []alertmanager.Alert{*alert})[0])
 }
 
 // GetAlertNames 获取告警名称列表（用于过滤器）
 func (h *Handler) GetAlertNames(c *gin.Context) {
-	if h.alerts == nil {
+	if h.getAlerts(c) == nil {
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Alertmanager not configured"})
 		return
 	}
 
-	names, err := h.alerts.GetAlertNames()
+	names, err := h.getAlerts(c).GetAlertNames()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -2803,24 +3600,108 @@ func (h *Handler) GetAlertNames(c *gin.Context) {
 
 // GetAlertSummary 获取告警摘要
 func (h *Handler) GetAlertSummary(c *gin.Context) {
-	if h.alerts == nil {
+	if h.getAlerts(c) == nil {
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Alertmanager not configured"})
 		return
 	}
 
-	summary, err := h.alerts.GetAlertSummary()
+	summary, err := h.getAlerts(c).GetAlertSummary()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, summary)
 }
 
-// AcknowledgeAlert 确认告警
-func (h *Handler) AcknowledgeAlert(c *gin.Context) {
+// GetAlertmanagerStatus 获取 Alertmanager 运行状态（版本、集群、原始配置）
+func (h *Handler) GetAlertmanagerStatus(c *gin.Context) {
+	if h.getAlerts(c) == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Alertmanager not configured"})
+		return
+	}
+
+	status, err := h.getAlerts(c).GetStatus()
+	if err != nil {
+		writeError(c, http.StatusBadGateway, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// ListAlertmanagerReceivers 获取已配置的接收器及配置问题
+func (h *Handler) ListAlertmanagerReceivers(c *gin.Context) {
+	if h.getAlerts(c) == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Alertmanager not configured"})
+		return
+	}
+
+	overview, err := h.getAlerts(c).GetConfigOverview()
+	if err != nil {
+		writeError(c, http.StatusBadGateway, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":  overview.Receivers,
+		"total":  len(overview.Receivers),
+		"issues": overview.Issues,
+	})
+}
+
+// GetAlertmanagerRoutes 获取告警路由树
+func (h *Handler) GetAlertmanagerRoutes(c *gin.Context) {
+	if h.getAlerts(c) == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Alertmanager not configured"})
+		return
+	}
+
+	overview, err := h.getAlerts(c).GetConfigOverview()
+	if err != nil {
+		writeError(c, http.StatusBadGateway, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"route":  overview.Route,
+		"issues": overview.Issues,
+	})
+}
+
+// alertServiceFor 返回绑定到当前请求集群的告警服务（确认与静默按集群存储），告警服务未启用时返回 503
+func (h *Handler) alertServiceFor(c *gin.Context) *alerts.Service {
 	if h.alertService == nil {
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Alert service not configured"})
+		return nil
+	}
+	return h.alertService.WithAlertmanager(middleware.GetClusterName(c), h.getAlerts(c))
+}
+
+func writeAlertServiceError(c *gin.Context, err error) {
+	var validationErr *alerts.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Message, "field": validationErr.Field})
+	case errors.Is(err, alerts.ErrSilenceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "silence not found"})
+	case errors.Is(err, alerts.ErrAlertmanagerUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Alertmanager not configured"})
+	default:
+		writeError(c, http.StatusInternalServerError, err)
+	}
+}
+
+// ackRequest 确认告警的请求体，expiresAt 为空表示不过期
+type ackRequest struct {
+	Comment   string     `json:"comment"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// AcknowledgeAlert 确认告警，记录当前用户为处理人；已被他人确认时由当前用户接手
+func (h *Handler) AcknowledgeAlert(c *gin.Context) {
+	service := h.alertServiceFor(c)
+	if service == nil {
 		return
 	}
 
@@ -2830,35 +3711,33 @@ func (h *Handler) AcknowledgeAlert(c *gin.Context) {
 		return
 	}
 
-	var req struct {
-		Comment   string     `json:"comment"`
-		ExpiresAt *time.Time `json:"expiresAt"`
-	}
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
-		return
+	var req ackRequest
+	// 请求体可省略
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
 	}
 
-	// 从上下文获取用户（如果有认证）
 	user := "anonymous"
-	if val, exists := c.Get("user"); exists {
-		if u, ok := val.(string); ok {
-			user = u
-		}
+	if current := middleware.GetCurrentUser(c); current != nil {
+		user = current.Username
 	}
 
-	if err := h.alertService.AcknowledgeAlert(fingerprint, user, req.Comment, req.ExpiresAt); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ack, err := service.AcknowledgeAlert(fingerprint, user, req.Comment, req.ExpiresAt)
+	if err != nil {
+		writeAlertServiceError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Alert acknowledged"})
+	c.JSON(http.StatusOK, ack)
 }
 
 // UnacknowledgeAlert 取消确认告警
 func (h *Handler) UnacknowledgeAlert(c *gin.Context) {
-	if h.alertService == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Alert service not configured"})
+	service := h.alertServiceFor(c)
+	if service == nil {
 		return
 	}
 
@@ -2868,8 +3747,8 @@ func (h *Handler) UnacknowledgeAlert(c *gin.Context) {
 		return
 	}
 
-	if err := h.alertService.UnacknowledgeAlert(fingerprint); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := service.UnacknowledgeAlert(fingerprint); err != nil {
+		writeAlertServiceError(c, err)
 		return
 	}
 
@@ -2878,8 +3757,8 @@ func (h *Handler) UnacknowledgeAlert(c *gin.Context) {
 
 // GetAlertAcknowledgement 获取告警确认记录
 func (h *Handler) GetAlertAcknowledgement(c *gin.Context) {
-	if h.alertService == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Alert service not configured"})
+	service := h.alertServiceFor(c)
+	if service == nil {
 		return
 	}
 
@@ -2889,9 +3768,9 @@ func (h *Handler) GetAlertAcknowledgement(c *gin.Context) {
 		return
 	}
 
-	ack, err := h.alertService.GetAcknowledgement(fingerprint)
+	ack, err := service.GetAcknowledgement(fingerprint)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeAlertServiceError(c, err)
 		return
 	}
 
@@ -2907,16 +3786,14 @@ func (h *Handler) GetAlertAcknowledgement(c *gin.Context) {
 
 // ListSilences 列出静默规则
 func (h *Handler) ListSilences(c *gin.Context) {
-	if h.alertService == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Alert service not configured"})
+	service := h.alertServiceFor(c)
+	if service == nil {
 		return
 	}
 
-	state := c.Query("state")
-
-	silences, err := h.alertService.ListSilences(state)
+	silences, err := service.ListSilences(c.Query("state"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeAlertServiceError(c, err)
 		return
 	}
 
@@ -2928,8 +3805,8 @@ func (h *Handler) ListSilences(c *gin.Context) {
 
 // GetSilence 获取单个静默规则
 func (h *Handler) GetSilence(c *gin.Context) {
-	if h.alertService == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Alert service not configured"})
+	service := h.alertServiceFor(c)
+	if service == nil {
 		return
 	}
 
@@ -2939,74 +3816,76 @@ func (h *Handler) GetSilence(c *gin.Context) {
 		return
 	}
 
-	silence, err := h.alertService.GetSilence(id)
+	silence, err := service.GetSilence(id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	if silence == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "silence not found"})
+		writeAlertServiceError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, silence)
 }
 
-// CreateSilence 创建静默规则
+// CreateSilence 创建静默规则，创建者记录为当前用户
 func (h *Handler) CreateSilence(c *gin.Context) {
-	if h.alertService == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Alert service not configured"})
+	service := h.alertServiceFor(c)
+	if service == nil {
 		return
 	}
 
-	var req struct {
-		Matchers []map[string]interface{} `json:"matchers"`
-		StartsAt time.Time                `json:"startsAt"`
-		EndsAt   time.Time                `json:"endsAt"`
-		Comment  string                   `json:"comment"`
-	}
-
-	if err := c.BindJSON(&req); err != nil {
+	var req alerts.SilenceInput
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
 		return
 	}
 
-	// 验证参数
-	if len(req.Matchers) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "matchers is required"})
+	createdBy := "anonymous"
+	if user := middleware.GetCurrentUser(c); user != nil {
+		createdBy = user.Username
+	}
+
+	silence, err := service.CreateSilence(req, createdBy)
+	if err != nil {
+		writeAlertServiceError(c, err)
 		return
 	}
-	if req.EndsAt.Before(req.StartsAt) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "endsAt must be after startsAt"})
+
+	middleware.SetAuditDetail(c, fmt.Sprintf("silence=%s", silence.SilenceID))
+	c.JSON(http.StatusCreated, silence)
+}
+
+// UpdateSilence 修改静默规则的匹配器、起止时间或备注
+func (h *Handler) UpdateSilence(c *gin.Context) {
+	service := h.alertServiceFor(c)
+	if service == nil {
 		return
 	}
-	if req.Comment == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "comment is required"})
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid silence id"})
 		return
 	}
 
-	// 从上下文获取用户（如果有认证）
-	user := "anonymous"
-	if val, exists := c.Get("user"); exists {
-		if u, ok := val.(string); ok {
-			user = u
-		}
+	var req alerts.SilenceInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
 	}
 
-	silence, err := h.alertService.CreateSilence(req.Matchers, req.StartsAt, req.EndsAt, user, req.Comment)
+	silence, err := service.UpdateSilence(id, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeAlertServiceError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, silence)
+	middleware.SetAuditDetail(c, fmt.Sprintf("silence=%s", silence.SilenceID))
+	c.JSON(http.StatusOK, silence)
 }
 
-// DeleteSilence 删除静默规则
+// DeleteSilence 删除静默规则（使其在 Alertmanager 中立即过期）
 func (h *Handler) DeleteSilence(c *gin.Context) {
-	if h.alertService == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Alert service not configured"})
+	service := h.alertServiceFor(c)
+	if service == nil {
 		return
 	}
 
@@ -3016,8 +3895,8 @@ func (h *Handler) DeleteSilence(c *gin.Context) {
 		return
 	}
 
-	if err := h.alertService.DeleteSilence(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := service.DeleteSilence(id); err != nil {
+		writeAlertServiceError(c, err)
 		return
 	}
 
@@ -3043,35 +3922,59 @@ func (h *Handler) ListAuditLogs(c *gin.Context) {
 		return
 	}
 
-	// 解析查询参数
-	var params audit.ListParams
+	// 解析查询参数，无效的时间范围忽略
+	params, _ := auditFilterParams(c)
 	params.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
 	params.PageSize, _ = strconv.Atoi(c.DefaultQuery("pageSize", "20"))
-	params.User = c.Query("user")
-	params.Action = c.Query("action")
-	params.Resource = c.Query("resource")
-	params.Namespace = c.Query("namespace")
-	params.Cluster = c.Query("cluster")
 
-	// 解析时间范围
-	if startTime := c.Query("startTime"); startTime != "" {
-		if t, err := time.Parse(time.RFC3339, startTime); err == nil {
-			params.StartTime = t
-		}
+	result, err := h.audit.List(params)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
 	}
-	if endTime := c.Query("endTime"); endTime != "" {
-		if t, err := time.Parse(time.RFC3339, endTime); err == nil {
-			params.EndTime = t
-		}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetAuditStorage 获取审计表占用空间、保留期与归档情况
+func (h *Handler) GetAuditStorage(c *gin.Context) {
+	if h.audit == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "审计日志功能未启用"})
+		return
 	}
 
-	result, err := h.audit.List(params)
+	stats, err := h.audit.GetStorageStats()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
+	c.JSON(http.StatusOK, stats)
+}
 
-	c.JSON(http.StatusOK, result)
+// ListAuditAnomalies 列出审计异常告警，支持 since（RFC3339）与 limit
+func (h *Handler) ListAuditAnomalies(c *gin.Context) {
+	if h.audit == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "审计日志功能未启用"})
+		return
+	}
+
+	var since time.Time
+	if raw := c.Query("since"); raw != "" {
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since 需为 RFC3339 格式"})
+			return
+		}
+		since = t
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+
+	anomalies, err := h.audit.ListAnomalies(since, limit)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": anomalies, "total": len(anomalies)})
 }
 
 // GetAuditStats 获取审计日志统计
@@ -3090,7 +3993,7 @@ func (h *Handler) GetAuditStats(c *gin.Context) {
 
 	stats, err := h.audit.GetStats(d)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -3101,13 +4004,13 @@ func (h *Handler) GetAuditStats(c *gin.Context) {
 
 // RestartStatefulSet 重启 StatefulSet
 func (h *Handler) RestartStatefulSet(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 
 	sts, err := h.getK8s(c).Clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -3119,7 +4022,7 @@ func (h *Handler) RestartStatefulSet(c *gin.Context) {
 
 	_, err = h.getK8s(c).Clientset.AppsV1().StatefulSets(namespace).Update(ctx, sts, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "restarted"})
@@ -3127,40 +4030,41 @@ func (h *Handler) RestartStatefulSet(c *gin.Context) {
 
 // UpdateStatefulSetYAML 通过 YAML 更新 StatefulSet
 func (h *Handler) UpdateStatefulSetYAML(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 
 	var req struct {
 		YAML string `json:"yaml"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	var sts appsv1.StatefulSet
-	if err := yaml.Unmarshal([]byte(req.YAML), &sts); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !decodeManifest(c, []byte(req.YAML), &sts) || !validateObject(c, &sts, namespace, c.Param("name")) {
 		return
 	}
 
+	before := auditBefore(h.getK8s(c).Clientset.AppsV1().StatefulSets(namespace).Get(ctx, c.Param("name"), metav1.GetOptions{}))
 	result, err := h.getK8s(c).Clientset.AppsV1().StatefulSets(namespace).Update(ctx, &sts, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
+	recordAuditDiff(c, before, result)
 	c.JSON(http.StatusOK, result)
 }
 
 // GetStatefulSetPods 获取 StatefulSet 关联的 Pods
 func (h *Handler) GetStatefulSetPods(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 
 	sts, err := h.getK8s(c).Clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -3169,15 +4073,15 @@ func (h *Handler) GetStatefulSetPods(c *gin.Context) {
 		LabelSelector: selector.String(),
 	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: pods.Items, Total: len(pods.Items)})
+	writeList(c, ListResponse{Items: pods.Items, Total: len(pods.Items)})
 }
 
 // GetStatefulSetEvents 获取 StatefulSet 相关事件
 func (h *Handler) GetStatefulSetEvents(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 
@@ -3186,23 +4090,23 @@ func (h *Handler) GetStatefulSetEvents(c *gin.Context) {
 		FieldSelector: fieldSelector,
 	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: events.Items, Total: len(events.Items)})
+	writeList(c, ListResponse{Items: events.Items, Total: len(events.Items)})
 }
 
 // ========== DaemonSet 高级功能 ==========
 
 // RestartDaemonSet 重启 DaemonSet
 func (h *Handler) RestartDaemonSet(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 
 	ds, err := h.getK8s(c).Clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -3214,7 +4118,7 @@ func (h *Handler) RestartDaemonSet(c *gin.Context) {
 
 	_, err = h.getK8s(c).Clientset.AppsV1().DaemonSets(namespace).Update(ctx, ds, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "restarted"})
@@ -3222,40 +4126,41 @@ func (h *Handler) RestartDaemonSet(c *gin.Context) {
 
 // UpdateDaemonSetYAML 通过 YAML 更新 DaemonSet
 func (h *Handler) UpdateDaemonSetYAML(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 
 	var req struct {
 		YAML string `json:"yaml"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	var ds appsv1.DaemonSet
-	if err := yaml.Unmarshal([]byte(req.YAML), &ds); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !decodeManifest(c, []byte(req.YAML), &ds) || !validateObject(c, &ds, namespace, c.Param("name")) {
 		return
 	}
 
+	before := auditBefore(h.getK8s(c).Clientset.AppsV1().DaemonSets(namespace).Get(ctx, c.Param("name"), metav1.GetOptions{}))
 	result, err := h.getK8s(c).Clientset.AppsV1().DaemonSets(namespace).Update(ctx, &ds, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
+	recordAuditDiff(c, before, result)
 	c.JSON(http.StatusOK, result)
 }
 
 // GetDaemonSetPods 获取 DaemonSet 关联的 Pods
 func (h *Handler) GetDaemonSetPods(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 
 	ds, err := h.getK8s(c).Clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -3264,15 +4169,15 @@ func (h *Handler) GetDaemonSetPods(c *gin.Context) {
 		LabelSelector: selector.String(),
 	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: pods.Items, Total: len(pods.Items)})
+	writeList(c, ListResponse{Items: pods.Items, Total: len(pods.Items)})
 }
 
 // GetDaemonSetEvents 获取 DaemonSet 相关事件
 func (h *Handler) GetDaemonSetEvents(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 
@@ -3281,17 +4186,17 @@ func (h *Handler) GetDaemonSetEvents(c *gin.Context) {
 		FieldSelector: fieldSelector,
 	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: events.Items, Total: len(events.Items)})
+	writeList(c, ListResponse{Items: events.Items, Total: len(events.Items)})
 }
 
 // ========== Deployment 事件 ==========
 
 // GetDeploymentEvents 获取 Deployment 相关事件
 func (h *Handler) GetDeploymentEvents(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 
@@ -3300,17 +4205,17 @@ func (h *Handler) GetDeploymentEvents(c *gin.Context) {
 		FieldSelector: fieldSelector,
 	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, ListResponse{Items: events.Items, Total: len(events.Items)})
+	writeList(c, ListResponse{Items: events.Items, Total: len(events.Items)})
 }
 
 // ========== 阶段 2: 运维增强功能 ==========
 
 // UpdateDeploymentStrategy 更新 Deployment 滚动更新策略
 func (h *Handler) UpdateDeploymentStrategy(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 
@@ -3320,15 +4225,16 @@ func (h *Handler) UpdateDeploymentStrategy(c *gin.Context) {
 		MaxSurge       string `json:"maxSurge"`       // 可以是数字或百分比
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	dep, err := h.getK8s(c).Clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
+	before := dep.DeepCopy()
 
 	// 更新策略类型
 	if req.Type != "" {
@@ -3352,15 +4258,16 @@ func (h *Handler) UpdateDeploymentStrategy(c *gin.Context) {
 
 	result, err := h.getK8s(c).Clientset.AppsV1().Deployments(namespace).Update(ctx, dep, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
+	recordAuditDiff(c, before, result)
 	c.JSON(http.StatusOK, result)
 }
 
 // UpdateStatefulSetStrategy 更新 StatefulSet 滚动更新策略
 func (h *Handler) UpdateStatefulSetStrategy(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 
@@ -3369,15 +4276,16 @@ func (h *Handler) UpdateStatefulSetStrategy(c *gin.Context) {
 		Partition int32  `json:"partition"` // 分区值
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	sts, err := h.getK8s(c).Clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
+	before := sts.DeepCopy()
 
 	// 更新策略类型
 	if req.Type != "" {
@@ -3394,15 +4302,16 @@ func (h *Handler) UpdateStatefulSetStrategy(c *gin.Context) {
 
 	result, err := h.getK8s(c).Clientset.AppsV1().StatefulSets(namespace).Update(ctx, sts, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
+	recordAuditDiff(c, before, result)
 	c.JSON(http.StatusOK, result)
 }
 
 // UpdateDaemonSetStrategy 更新 DaemonSet 滚动更新策略
 func (h *Handler) UpdateDaemonSetStrategy(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 
@@ -3412,15 +4321,16 @@ func (h *Handler) UpdateDaemonSetStrategy(c *gin.Context) {
 		MaxSurge       string `json:"maxSurge"`       // 可以是数字或百分比
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	ds, err := h.getK8s(c).Clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
+	before := ds.DeepCopy()
 
 	// 更新策略类型
 	if req.Type != "" {
@@ -3444,21 +4354,22 @@ func (h *Handler) UpdateDaemonSetStrategy(c *gin.Context) {
 
 	result, err := h.getK8s(c).Clientset.AppsV1().DaemonSets(namespace).Update(ctx, ds, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
+	recordAuditDiff(c, before, result)
 	c.JSON(http.StatusOK, result)
 }
 
 // GetStatefulSetRevisions 获取 StatefulSet 修订版本历史
 func (h *Handler) GetStatefulSetRevisions(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 
 	sts, err := h.getK8s(c).Clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -3468,7 +4379,7 @@ func (h *Handler) GetStatefulSetRevisions(c *gin.Context) {
 		LabelSelector: selector.String(),
 	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -3499,7 +4410,7 @@ func (h *Handler) GetStatefulSetRevisions(c *gin.Context) {
 
 // RollbackStatefulSet 回滚 StatefulSet 到指定版本
 func (h *Handler) RollbackStatefulSet(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 
@@ -3507,13 +4418,13 @@ func (h *Handler) RollbackStatefulSet(c *gin.Context) {
 		Revision int64 `json:"revision"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	sts, err := h.getK8s(c).Clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -3523,7 +4434,7 @@ func (h *Handler) RollbackStatefulSet(c *gin.Context) {
 		LabelSelector: selector.String(),
 	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -3556,7 +4467,7 @@ func (h *Handler) RollbackStatefulSet(c *gin.Context) {
 	sts.Spec.Template = patchedSts.Spec.Template
 	result, err := h.getK8s(c).Clientset.AppsV1().StatefulSets(namespace).Update(ctx, sts, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "rolled back", "statefulset": result})
@@ -3564,13 +4475,13 @@ func (h *Handler) RollbackStatefulSet(c *gin.Context) {
 
 // PauseDeployment 暂停 Deployment 更新
 func (h *Handler) PauseDeployment(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 
 	dep, err := h.getK8s(c).Clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -3582,7 +4493,7 @@ func (h *Handler) PauseDeployment(c *gin.Context) {
 	dep.Spec.Paused = true
 	result, err := h.getK8s(c).Clientset.AppsV1().Deployments(namespace).Update(ctx, dep, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "paused", "deployment": result})
@@ -3590,13 +4501,13 @@ func (h *Handler) PauseDeployment(c *gin.Context) {
 
 // ResumeDeployment 恢复 Deployment 更新
 func (h *Handler) ResumeDeployment(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 
 	dep, err := h.getK8s(c).Clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -3608,7 +4519,7 @@ func (h *Handler) ResumeDeployment(c *gin.Context) {
 	dep.Spec.Paused = false
 	result, err := h.getK8s(c).Clientset.AppsV1().Deployments(namespace).Update(ctx, dep, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "resumed", "deployment": result})
@@ -3616,19 +4527,19 @@ func (h *Handler) ResumeDeployment(c *gin.Context) {
 
 // GetDeploymentRevisions 获取 Deployment 修订版本历史
 func (h *Handler) GetDeploymentRevisions(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 
 	dep, err := h.getK8s(c).Clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
 
 	selector, err := metav1.LabelSelectorAsSelector(dep.Spec.Selector)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -3636,7 +4547,7 @@ func (h *Handler) GetDeploymentRevisions(c *gin.Context) {
 		LabelSelector: selector.String(),
 	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -3673,7 +4584,7 @@ func (h *Handler) GetDeploymentRevisions(c *gin.Context) {
 
 // UpdateDeploymentImage 更新 Deployment 容器镜像
 func (h *Handler) UpdateDeploymentImage(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 
@@ -3684,15 +4595,16 @@ func (h *Handler) UpdateDeploymentImage(c *gin.Context) {
 		} `json:"containers"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	dep, err := h.getK8s(c).Clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
+	before := dep.DeepCopy()
 
 	// 更新容器镜像
 	for _, container := range req.Containers {
@@ -3704,18 +4616,19 @@ func (h *Handler) UpdateDeploymentImage(c *gin.Context) {
 		}
 	}
 
-	_, err = h.getK8s(c).Clientset.AppsV1().Deployments(namespace).Update(ctx, dep, metav1.UpdateOptions{})
+	result, err := h.getK8s(c).Clientset.AppsV1().Deployments(namespace).Update(ctx, dep, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
+	recordAuditDiff(c, before, result)
 	c.JSON(http.StatusOK, gin.H{"message": "镜像更新成功"})
 }
 
 // UpdateDeploymentScheduling 更新 Deployment 调度配置
 func (h *Handler) UpdateDeploymentScheduling(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Param("ns")
 	name := c.Param("name")
 
@@ -3724,25 +4637,27 @@ func (h *Handler) UpdateDeploymentScheduling(c *gin.Context) {
 		Tolerations  []corev1.Toleration `json:"tolerations"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	dep, err := h.getK8s(c).Clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeError(c, http.StatusNotFound, err)
 		return
 	}
+	before := dep.DeepCopy()
 
 	// 更新调度配置
 	dep.Spec.Template.Spec.NodeSelector = req.NodeSelector
 	dep.Spec.Template.Spec.Tolerations = req.Tolerations
 
-	_, err = h.getK8s(c).Clientset.AppsV1().Deployments(namespace).Update(ctx, dep, metav1.UpdateOptions{})
+	result, err := h.getK8s(c).Clientset.AppsV1().Deployments(namespace).Update(ctx, dep, metav1.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
+	recordAuditDiff(c, before, result)
 	c.JSON(http.StatusOK, gin.H{"message": "调度配置更新成功"})
 }
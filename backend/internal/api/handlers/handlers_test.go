@@ -0,0 +1,329 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/alertmanager"
+	"github.com/k8s-dashboard/backend/internal/k8s"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// newCronJobAPIServer 模拟 API Server 上的单个 CronJob，记录收到的更新次数
+func newCronJobAPIServer(t *testing.T, suspended bool) (*k8s.Client, *int) {
+	t.Helper()
+	cj := batchv1.CronJob{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "batch/v1", Kind: "CronJob"},
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "ops", ResourceVersion: "1"},
+		Spec:       batchv1.CronJobSpec{Schedule: "0 * * * *", Suspend: &suspended},
+	}
+	updates := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/apis/batch/v1/namespaces/ops/cronjobs/backup" {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			if err := json.Unmarshal(body, &cj); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			updates++
+		default:
+			http.Error(w, "unexpected method", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(cj)
+	}))
+	t.Cleanup(server.Close)
+
+	config := &rest.Config{Host: server.URL, ContentConfig: rest.ContentConfig{ContentType: "application/json"}}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		t.Fatalf("NewForConfig failed: %v", err)
+	}
+	return &k8s.Client{Clientset: clientset, Config: config}, &updates
+}
+
+func TestSetCronJobSuspendIsIdempotent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name        string
+		suspended   bool
+		path        string
+		wantMessage string
+		wantChanged bool
+	}{
+		{name: "suspend active", suspended: false, path: "suspend", wantMessage: "suspended", wantChanged: true},
+		{name: "suspend already suspended", suspended: true, path: "suspend", wantMessage: "suspended"},
+		{name: "resume suspended", suspended: true, path: "resume", wantMessage: "resumed", wantChanged: true},
+		{name: "resume already active", suspended: false, path: "resume", wantMessage: "resumed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, updates := newCronJobAPIServer(t, tt.suspended)
+			h := NewHandler(client, nil, nil, nil, nil, nil, nil, Options{})
+			router := gin.New()
+			router.POST("/namespaces/:ns/cronjobs/:name/suspend", h.SuspendCronJob)
+			router.POST("/namespaces/:ns/cronjobs/:name/resume", h.ResumeCronJob)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/namespaces/ops/cronjobs/backup/"+tt.path, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+			}
+			var resp struct {
+				Message string          `json:"message"`
+				Changed bool            `json:"changed"`
+				CronJob batchv1.CronJob `json:"cronjob"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if resp.Message != tt.wantMessage || resp.Changed != tt.wantChanged {
+				t.Fatalf("response = %+v, want message %q changed %v", resp, tt.wantMessage, tt.wantChanged)
+			}
+			wantSuspended := tt.path == "suspend"
+			if resp.CronJob.Spec.Suspend == nil || *resp.CronJob.Spec.Suspend != wantSuspended {
+				t.Fatalf("cronjob suspend = %v, want %v", resp.CronJob.Spec.Suspend, wantSuspended)
+			}
+			if wantUpdates := map[bool]int{true: 1, false: 0}[tt.wantChanged]; *updates != wantUpdates {
+				t.Fatalf("updates = %d, want %d", *updates, wantUpdates)
+			}
+		})
+	}
+}
+
+// newJobAPIServer 模拟 API Server 上的单个 Job，创建请求按 generateName 补全名称后原样返回
+func newJobAPIServer(t *testing.T, src batchv1.Job) (*k8s.Client, *[]batchv1.Job) {
+	t.Helper()
+	var created []batchv1.Job
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/apis/batch/v1/namespaces/ops/jobs/"+src.Name:
+			json.NewEncoder(w).Encode(src)
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/apis/batch/v1/namespaces/ops/jobs/"):
+			status := apierrors.NewNotFound(batchv1.Resource("jobs"), strings.TrimPrefix(r.URL.Path, "/apis/batch/v1/namespaces/ops/jobs/")).ErrStatus
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(status)
+		case r.Method == http.MethodPost && r.URL.Path == "/apis/batch/v1/namespaces/ops/jobs":
+			var job batchv1.Job
+			if err := json.NewDecoder(r.Body).Decode(&job); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if job.Name == "" {
+				job.Name = job.GenerateName + strconv.Itoa(len(created)+10000)
+			}
+			created = append(created, job)
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(job)
+		default:
+			http.Error(w, "unexpected request", http.StatusMethodNotAllowed)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	config := &rest.Config{Host: server.URL, ContentConfig: rest.ContentConfig{ContentType: "application/json"}}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		t.Fatalf("NewForConfig failed: %v", err)
+	}
+	return &k8s.Client{Clientset: clientset, Config: config}, &created
+}
+
+func TestRerunJob(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	longName := strings.Repeat("a", 60)
+	src := batchv1.Job{
+		TypeMeta: metav1.TypeMeta{APIVersion: "batch/v1", Kind: "Job"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      longName,
+			Namespace: "ops",
+			Labels:    map[string]string{"app": "backup", "controller-uid": "uid-1", "job-name": longName},
+		},
+		Spec: batchv1.JobSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"controller-uid": "uid-1"}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "backup", "controller-uid": "uid-1", "job-name": longName}},
+			},
+		},
+	}
+	client, created := newJobAPIServer(t, src)
+	h := NewHandler(client, nil, nil, nil, nil, nil, nil, Options{})
+	router := gin.New()
+	router.POST("/namespaces/:ns/jobs/:name/rerun", h.RerunJob)
+
+	// 同一秒内连续重跑两次，都应成功且名称不同
+	var names []string
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/namespaces/ops/jobs/"+longName+"/rerun", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+		}
+		var job batchv1.Job
+		if err := json.Unmarshal(w.Body.Bytes(), &job); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		names = append(names, job.Name)
+	}
+	if len(*created) != 2 || names[0] == names[1] {
+		t.Fatalf("created %d jobs named %v", len(*created), names)
+	}
+
+	job := (*created)[0]
+	if job.Name != names[0] {
+		t.Fatalf("response name %q, created %q", names[0], job.Name)
+	}
+	if len(job.GenerateName) > manualJobNamePrefixLimit || !strings.HasPrefix(job.GenerateName, "aaaa") {
+		t.Fatalf("generateName %q (%d characters), want at most %d", job.GenerateName, len(job.GenerateName), manualJobNamePrefixLimit)
+	}
+	if job.Annotations["k8s-dashboard/rerun-of"] != longName {
+		t.Fatalf("annotations = %v", job.Annotations)
+	}
+	if job.Spec.Selector != nil {
+		t.Fatalf("selector should be regenerated, got %v", job.Spec.Selector)
+	}
+	for _, labels := range []map[string]string{job.Labels, job.Spec.Template.Labels} {
+		if _, ok := labels["controller-uid"]; ok || labels["app"] != "backup" {
+			t.Fatalf("labels = %v", labels)
+		}
+	}
+}
+
+func TestRerunJobNotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	client, created := newJobAPIServer(t, batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "ops"}})
+	h := NewHandler(client, nil, nil, nil, nil, nil, nil, Options{})
+	router := gin.New()
+	router.POST("/namespaces/:ns/jobs/:name/rerun", h.RerunJob)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/namespaces/ops/jobs/missing/rerun", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+	}
+	if len(*created) != 0 {
+		t.Fatalf("created %d jobs for a missing source", len(*created))
+	}
+}
+
+func TestAlertPage(t *testing.T) {
+	tests := []struct {
+		name               string
+		page, pageSize     string
+		total              int
+		wantPage, wantSize int
+		wantStart, wantEnd int
+	}{
+		{name: "defaults", total: 120, wantPage: 1, wantSize: 50, wantStart: 0, wantEnd: 50},
+		{name: "middle page", page: "2", pageSize: "50", total: 120, wantPage: 2, wantSize: 50, wantStart: 50, wantEnd: 100},
+		{name: "partial last page", page: "3", pageSize: "50", total: 120, wantPage: 3, wantSize: 50, wantStart: 100, wantEnd: 120},
+		{name: "past last page", page: "4", pageSize: "50", total: 120, wantPage: 4, wantSize: 50, wantStart: 120, wantEnd: 120},
+		{name: "huge page does not overflow", page: "9223372036854775807", pageSize: "500", total: 10, wantPage: 9223372036854775807, wantSize: 500, wantStart: 10, wantEnd: 10},
+		{name: "zero and negative page", page: "-3", pageSize: "10", total: 25, wantPage: 1, wantSize: 10, wantStart: 0, wantEnd: 10},
+		{name: "invalid numbers fall back", page: "abc", pageSize: "x", total: 5, wantPage: 1, wantSize: 50, wantStart: 0, wantEnd: 5},
+		{name: "oversized page size capped", page: "1", pageSize: "100000", total: 1200, wantPage: 1, wantSize: 500, wantStart: 0, wantEnd: 500},
+		{name: "zero page size uses default", page: "1", pageSize: "0", total: 60, wantPage: 1, wantSize: 50, wantStart: 0, wantEnd: 50},
+		{name: "empty result", page: "1", pageSize: "20", total: 0, wantPage: 1, wantSize: 20, wantStart: 0, wantEnd: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, size, start, end := alertPage(tt.page, tt.pageSize, tt.total)
+			if page != tt.wantPage || size != tt.wantSize || start != tt.wantStart || end != tt.wantEnd {
+				t.Fatalf("alertPage = (%d, %d, %d, %d), want (%d, %d, %d, %d)",
+					page, size, start, end, tt.wantPage, tt.wantSize, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestListAlertsSearchSortAndPagination(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	startsAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	var list []alertmanager.Alert
+	for i, fp := range []string{"e", "b", "d", "a", "c"} {
+		list = append(list, alertmanager.Alert{
+			Fingerprint: fp,
+			Labels:      map[string]string{"alertname": "Checkout" + strconv.Itoa(i%2), "severity": "warning"},
+			StartsAt:    startsAt,
+			Status:      alertmanager.AlertStatus{State: "active"},
+		})
+	}
+	list = append(list, alertmanager.Alert{
+		Fingerprint: "z",
+		Labels:      map[string]string{"alertname": "DiskFull", "severity": "critical"},
+		StartsAt:    startsAt,
+		Status:      alertmanager.AlertStatus{State: "active"},
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(list)
+	}))
+	t.Cleanup(server.Close)
+
+	h := NewHandler(nil, nil, nil, alertmanager.NewClient(server.URL), nil, nil, nil, Options{})
+	router := gin.New()
+	router.GET("/alerts", h.ListAlerts)
+
+	tests := []struct {
+		query     string
+		wantCode  int
+		wantTotal int
+		wantItems []string
+	}{
+		// 同级别、同触发时间按 fingerprint 排序
+		{query: "search=checkout", wantCode: http.StatusOK, wantTotal: 5, wantItems: []string{"a", "b", "c", "d", "e"}},
+		{query: "search=checkout&page=2&pageSize=2", wantCode: http.StatusOK, wantTotal: 5, wantItems: []string{"c", "d"}},
+		{query: "search=checkout&page=3&pageSize=2", wantCode: http.StatusOK, wantTotal: 5, wantItems: []string{"e"}},
+		{query: "search=checkout&page=4&pageSize=2", wantCode: http.StatusOK, wantTotal: 5, wantItems: []string{}},
+		{query: "sort=alertname&pageSize=3", wantCode: http.StatusOK, wantTotal: 6, wantItems: []string{"c", "d", "e"}},
+		{query: "sort=severity&pageSize=2", wantCode: http.StatusOK, wantTotal: 6, wantItems: []string{"z", "a"}},
+		{query: "page=1&pageSize=100000", wantCode: http.StatusOK, wantTotal: 6, wantItems: []string{"z", "a", "b", "c", "d", "e"}},
+		{query: "sort=newest", wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/alerts?"+tt.query, nil))
+			if w.Code != tt.wantCode {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var resp struct {
+				Items []AlertItem `json:"items"`
+				Total int         `json:"total"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			got := make([]string, 0, len(resp.Items))
+			for _, item := range resp.Items {
+				got = append(got, item.Fingerprint)
+			}
+			if resp.Total != tt.wantTotal || !reflect.DeepEqual(got, tt.wantItems) {
+				t.Fatalf("total %d items %v, want total %d items %v", resp.Total, got, tt.wantTotal, tt.wantItems)
+			}
+		})
+	}
+}
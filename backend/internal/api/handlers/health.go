@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/alertmanager"
+	"github.com/k8s-dashboard/backend/internal/auth"
+	"github.com/k8s-dashboard/backend/internal/k8s"
+	"github.com/k8s-dashboard/backend/internal/metrics"
+)
+
+// 依赖检查状态
+const (
+	dependencyUp       = "up"
+	dependencyDown     = "down"
+	dependencyDisabled = "disabled"
+)
+
+// readinessTimeout 单次就绪检查的总超时，需小于探针的 timeoutSeconds
+const readinessTimeout = 2 * time.Second
+
+// DependencyStatus 单个依赖的检查结果
+type DependencyStatus struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`   // up, down, disabled
+	Critical  bool   `json:"critical"` // 关键依赖不可用时 readyz 返回 503
+	LatencyMs int64  `json:"latencyMs"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ReadinessResponse 就绪检查结果；status 为 ok、degraded（仅非关键依赖异常）或 unavailable
+type ReadinessResponse struct {
+	Status       string             `json:"status"`
+	Dependencies []DependencyStatus `json:"dependencies"`
+	CheckedAt    time.Time          `json:"checkedAt"`
+}
+
+type dependencyCheck struct {
+	name     string
+	critical bool
+	check    func(ctx context.Context) error // nil 表示未配置
+}
+
+// HealthHandler 存活与就绪探针处理器
+type HealthHandler struct {
+	checks []dependencyCheck
+}
+
+// NewHealthHandler 创建探针处理器。K8s API 与数据库为关键依赖，
+// VictoriaMetrics 与 Alertmanager 不可用时仅降级（相关页面报错，其余功能可用）
+func NewHealthHandler(k8sClient *k8s.Client, metricsClient *metrics.Client, alertClient *alertmanager.Client, authClient *auth.Client) *HealthHandler {
+	h := &HealthHandler{}
+
+	k8sCheck := dependencyCheck{name: "kubernetes", critical: true}
+	if k8sClient != nil {
+		k8sCheck.check = func(ctx context.Context) error {
+			return k8sClient.Clientset.Discovery().RESTClient().Get().AbsPath("/version").Do(ctx).Error()
+		}
+	}
+
+	dbCheck := dependencyCheck{name: "database", critical: true}
+	if authClient != nil {
+		dbCheck.name = "database(" + string(authClient.Dialect()) + ")"
+		dbCheck.check = authClient.Ping
+	}
+
+	vmCheck := dependencyCheck{name: "victoriametrics"}
+	if metricsClient != nil {
+		vmCheck.check = func(ctx context.Context) error {
+			_, err := metricsClient.WithContext(ctx).Query("vector(1)")
+			return err
+		}
+	}
+
+	amCheck := dependencyCheck{name: "alertmanager"}
+	if alertClient != nil {
+		amCheck.check = func(ctx context.Context) error {
+			_, err := alertClient.WithContext(ctx).GetStatus()
+			return err
+		}
+	}
+
+	h.checks = []dependencyCheck{k8sCheck, dbCheck, vmCheck, amCheck}
+	return h
+}
+
+// Healthz 存活探针：进程能响应即视为存活，不检查外部依赖，避免依赖故障导致 Pod 被反复重启
+func (h *HealthHandler) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz 就绪探针：并发检查各依赖并返回延迟，关键依赖不可用时返回 503
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	results := make([]DependencyStatus, len(h.checks))
+	var wg sync.WaitGroup
+	for i, dep := range h.checks {
+		results[i] = DependencyStatus{Name: dep.name, Critical: dep.critical, Status: dependencyDisabled}
+		if dep.check == nil {
+			if dep.critical {
+				results[i].Status = dependencyDown
+				results[i].Error = "not configured"
+			}
+			continue
+		}
+		wg.Add(1)
+		go func(i int, dep dependencyCheck) {
+			defer wg.Done()
+			results[i] = runDependencyCheck(ctx, dep)
+		}(i, dep)
+	}
+	wg.Wait()
+
+	resp := ReadinessResponse{Status: "ok", Dependencies: results, CheckedAt: time.Now()}
+	code := http.StatusOK
+	for _, r := range results {
+		if r.Status != dependencyDown {
+			continue
+		}
+		if r.Critical {
+			resp.Status = "unavailable"
+			code = http.StatusServiceUnavailable
+			break
+		}
+		resp.Status = "degraded"
+	}
+
+	c.JSON(code, resp)
+}
+
+// runDependencyCheck 执行单个检查；部分客户端不支持 context，超时后直接判定为 down
+func runDependencyCheck(ctx context.Context, dep dependencyCheck) DependencyStatus {
+	result := DependencyStatus{Name: dep.name, Critical: dep.critical}
+	start := time.Now()
+
+	done := make(chan error, 1)
+	go func() { done <- dep.check(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Status = dependencyDown
+		result.Error = err.Error()
+	} else {
+		result.Status = dependencyUp
+	}
+	return result
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// certificateExpiringDays 证书剩余有效期不足该天数时标记为即将过期
+const certificateExpiringDays = 30
+
+// IngressDetail Ingress 详情，附带 TLS Secret 中证书的解析结果
+type IngressDetail struct {
+	*networkingv1.Ingress
+	TLSCertificates []IngressTLSCertificate `json:"tlsCertificates,omitempty"`
+}
+
+// IngressTLSCertificate spec.tls 中一项引用的证书信息，取 tls.crt 中的第一张（叶子）证书
+type IngressTLSCertificate struct {
+	SecretName     string     `json:"secretName"`
+	Hosts          []string   `json:"hosts,omitempty"`
+	Subject        string     `json:"subject,omitempty"`
+	Issuer         string     `json:"issuer,omitempty"`
+	DNSNames       []string   `json:"dnsNames,omitempty"`
+	NotBefore      *time.Time `json:"notBefore,omitempty"`
+	NotAfter       *time.Time `json:"notAfter,omitempty"`
+	DaysRemaining  int        `json:"daysRemaining"`
+	Status         string     `json:"status"`                   // valid, expiring, expired, default（使用 Controller 默认证书）, error
+	UnmatchedHosts []string   `json:"unmatchedHosts,omitempty"` // spec.tls 中证书未覆盖的主机名
+	Error          string     `json:"error,omitempty"`
+}
+
+// ingressTLSCertificates 读取 Ingress 引用的 TLS Secret 并解析证书，单个 Secret 读取或解析失败时记录在该项的 error 中
+func ingressTLSCertificates(ctx context.Context, clientset kubernetes.Interface, ing *networkingv1.Ingress) []IngressTLSCertificate {
+	certs := make([]IngressTLSCertificate, 0, len(ing.Spec.TLS))
+	for _, tls := range ing.Spec.TLS {
+		cert := IngressTLSCertificate{SecretName: tls.SecretName, Hosts: tls.Hosts}
+		if tls.SecretName == "" {
+			// 未指定 Secret 时由 Ingress Controller 使用默认证书
+			cert.Status = "default"
+			certs = append(certs, cert)
+			continue
+		}
+		secret, err := clientset.CoreV1().Secrets(ing.Namespace).Get(ctx, tls.SecretName, metav1.GetOptions{})
+		if err != nil {
+			cert.Status, cert.Error = "error", err.Error()
+			if apierrors.IsNotFound(err) {
+				cert.Error = fmt.Sprintf("Secret %s 不存在", tls.SecretName)
+			}
+			certs = append(certs, cert)
+			continue
+		}
+		inspectTLSCertificate(&cert, secret.Data[corev1.TLSCertKey], time.Now())
+		certs = append(certs, cert)
+	}
+	return certs
+}
+
+// inspectTLSCertificate 解析 PEM 编码的证书链并填充证书信息与状态
+func inspectTLSCertificate(cert *IngressTLSCertificate, data []byte, now time.Time) {
+	var block *pem.Block
+	for rest := data; ; {
+		block, rest = pem.Decode(rest)
+		if block == nil || block.Type == "CERTIFICATE" {
+			break
+		}
+	}
+	if block == nil {
+		cert.Status, cert.Error = "error", "Secret 中没有 PEM 格式的 tls.crt"
+		return
+	}
+	parsed, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		cert.Status, cert.Error = "error", "解析证书失败: "+err.Error()
+		return
+	}
+
+	notBefore, notAfter := parsed.NotBefore, parsed.NotAfter
+	cert.Subject = parsed.Subject.String()
+	cert.Issuer = parsed.Issuer.String()
+	cert.DNSNames = parsed.DNSNames
+	cert.NotBefore = &notBefore
+	cert.NotAfter = &notAfter
+	cert.DaysRemaining = int(notAfter.Sub(now).Hours() / 24)
+	switch {
+	case now.After(notAfter):
+		cert.Status = "expired"
+	case cert.DaysRemaining < certificateExpiringDays:
+		cert.Status = "expiring"
+	default:
+		cert.Status = "valid"
+	}
+	for _, host := range cert.Hosts {
+		if parsed.VerifyHostname(host) != nil {
+			cert.UnmatchedHosts = append(cert.UnmatchedHosts, host)
+		}
+	}
+}
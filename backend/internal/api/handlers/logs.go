@@ -0,0 +1,420 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// 未指定 tailLines 且带过滤条件时，单次读取的日志上限
+const maxFilteredLogBytes int64 = 64 * 1024 * 1024
+
+// logFilter 日志查询与过滤条件
+type logFilter struct {
+	Container  string
+	TailLines  *int64
+	SinceTime  *time.Time
+	UntilTime  *time.Time
+	Previous   bool
+	Timestamps bool // 调用方是否要求输出时间戳
+	Grep       *regexp.Regexp
+	Invert     bool
+}
+
+// parseLogFilter 解析日志查询参数：container, tailLines, sinceTime, untilTime,
+// previous, timestamps, grep（正则）, invert。defaultTail 为未指定任何范围时的默认行数，空表示不限制
+func parseLogFilter(c *gin.Context, defaultTail string) (*logFilter, error) {
+	f := &logFilter{
+		Container:  c.Query("container"),
+		Previous:   c.Query("previous") == "true",
+		Timestamps: c.Query("timestamps") == "true",
+		Invert:     c.Query("invert") == "true",
+	}
+
+	if v := c.Query("sinceTime"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, fmt.Errorf("sinceTime 必须是 RFC3339 格式")
+		}
+		f.SinceTime = &t
+	}
+	if v := c.Query("untilTime"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, fmt.Errorf("untilTime 必须是 RFC3339 格式")
+		}
+		f.UntilTime = &t
+	}
+	if f.SinceTime != nil && f.UntilTime != nil && !f.UntilTime.After(*f.SinceTime) {
+		return nil, fmt.Errorf("untilTime 必须晚于 sinceTime")
+	}
+	if v := c.Query("grep"); v != "" {
+		re, err := regexp.Compile(v)
+		if err != nil {
+			return nil, fmt.Errorf("grep 不是合法的正则表达式: %v", err)
+		}
+		f.Grep = re
+	}
+
+	// 只有未设置任何过滤条件时才使用默认行数，避免过滤只作用在最后几行上
+	tailLines := c.Query("tailLines")
+	if tailLines == "" && !f.filtering() && f.SinceTime == nil {
+		tailLines = defaultTail
+	}
+	if tailLines != "" {
+		lines, err := strconv.ParseInt(tailLines, 10, 64)
+		if err != nil || lines < 0 {
+			return nil, fmt.Errorf("tailLines 必须是非负整数")
+		}
+		f.TailLines = &lines
+	}
+	return f, nil
+}
+
+// filtering 是否需要逐行过滤
+func (f *logFilter) filtering() bool {
+	return f.Grep != nil || f.UntilTime != nil
+}
+
+// podLogOptions 转换为 Kubernetes 日志选项
+func (f *logFilter) podLogOptions() *corev1.PodLogOptions {
+	opts := &corev1.PodLogOptions{
+		Container:  f.Container,
+		Previous:   f.Previous,
+		TailLines:  f.TailLines,
+		Timestamps: f.Timestamps || f.UntilTime != nil,
+	}
+	if f.SinceTime != nil {
+		since := metav1.NewTime(*f.SinceTime)
+		opts.SinceTime = &since
+	}
+	if f.filtering() && f.TailLines == nil {
+		limit := maxFilteredLogBytes
+		opts.LimitBytes = &limit
+	}
+	return opts
+}
+
+// filterLogStream 逐行过滤日志并写出，返回命中行数与扫描行数。
+// 日志带时间戳时，grep 只匹配时间戳之后的内容
+func filterLogStream(r io.Reader, w io.Writer, f *logFilter) (matched, scanned int, err error) {
+	reader := bufio.NewReaderSize(r, 64*1024)
+	opts := f.podLogOptions()
+	// 为了支持 untilTime 向 Kubernetes 请求了时间戳，但调用方未要求时需去掉
+	stripTimestamp := opts.Timestamps && !f.Timestamps
+
+	for {
+		line, readErr := reader.ReadString('\n')
+		if line != "" {
+			scanned++
+
+			content, message := line, line
+			if opts.Timestamps {
+				if ts, rest, ok := strings.Cut(line, " "); ok {
+					if t, parseErr := time.Parse(time.RFC3339Nano, ts); parseErr == nil {
+						// 日志按时间顺序输出，超过 untilTime 即可停止读取
+						if f.UntilTime != nil && t.After(*f.UntilTime) {
+							return matched, scanned, nil
+						}
+						message = rest
+						if stripTimestamp {
+							content = rest
+						}
+					}
+				}
+			}
+
+			if f.Grep == nil || f.Grep.MatchString(strings.TrimRight(message, "\r\n")) != f.Invert {
+				matched++
+				if _, err := io.WriteString(w, content); err != nil {
+					return matched, scanned, err
+				}
+			}
+		}
+		if readErr == io.EOF {
+			return matched, scanned, nil
+		}
+		if readErr != nil {
+			return matched, scanned, readErr
+		}
+	}
+}
+
+// copyPodLogs 将日志写出，设置了过滤条件时逐行过滤
+func copyPodLogs(r io.Reader, w io.Writer, f *logFilter) error {
+	if f.filtering() {
+		_, _, err := filterLogStream(r, w, f)
+		return err
+	}
+	_, err := io.Copy(w, r)
+	return err
+}
+
+// DownloadPodLogs 以附件形式下载 Pod 日志。默认输出单个容器的 gzip 文件，
+// format=zip 时将全部容器（含 init 容器）的日志打包为 zip；支持与 GetPodLogs 相同的过滤参数
+func (h *Handler) DownloadPodLogs(c *gin.Context) {
+	// 下载可能超过 requestTimeout，只随客户端断开而取消
+	ctx := c.Request.Context()
+	namespace := c.Param("ns")
+	name := c.Param("name")
+
+	filter, err := parseLogFilter(c, "")
+	if err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+	stamp := time.Now().Format("20060102-150405")
+
+	if c.Query("format") == "zip" {
+		h.downloadPodLogsZip(ctx, c, namespace, name, filter, stamp)
+		return
+	}
+
+	logs, err := h.getK8s(c).Clientset.CoreV1().Pods(namespace).GetLogs(name, filter.podLogOptions()).Stream(ctx)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	defer logs.Close()
+
+	filename := name
+	if filter.Container != "" {
+		filename += "-" + filter.Container
+	}
+	filename = fmt.Sprintf("%s-%s.log", filename, stamp)
+	c.Header("Content-Type", "application/gzip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.gz"`, filename))
+	c.Status(http.StatusOK)
+
+	gz := gzip.NewWriter(c.Writer)
+	gz.Name = filename
+	if err := copyPodLogs(logs, gz, filter); err != nil {
+		log.Printf("下载 Pod %s/%s 日志中断: %v", namespace, name, err)
+	}
+	if err := gz.Close(); err != nil {
+		log.Printf("下载 Pod %s/%s 日志中断: %v", namespace, name, err)
+	}
+}
+
+func (h *Handler) downloadPodLogsZip(ctx context.Context, c *gin.Context, namespace, name string, filter *logFilter, stamp string) {
+	clientset := h.getK8s(c).Clientset
+	pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		writeError(c, http.StatusNotFound, err)
+		return
+	}
+
+	var containers []string
+	for _, container := range pod.Spec.InitContainers {
+		containers = append(containers, container.Name)
+	}
+	for _, container := range pod.Spec.Containers {
+		containers = append(containers, container.Name)
+	}
+	if filter.Container != "" {
+		containers = []string{filter.Container}
+	}
+
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s.zip"`, name, stamp))
+	c.Status(http.StatusOK)
+
+	zw := zip.NewWriter(c.Writer)
+	defer zw.Close()
+
+	for _, container := range containers {
+		containerFilter := *filter
+		containerFilter.Container = container
+
+		logs, err := clientset.CoreV1().Pods(namespace).GetLogs(name, containerFilter.podLogOptions()).Stream(ctx)
+		if err != nil {
+			// 单个容器失败（如尚未启动）不影响其余容器，错误信息写入归档
+			entry, createErr := zw.Create(container + ".error.txt")
+			if createErr != nil {
+				return
+			}
+			io.WriteString(entry, err.Error()+"\n")
+			continue
+		}
+
+		entry, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     container + ".log",
+			Method:   zip.Deflate,
+			Modified: time.Now(),
+		})
+		if err == nil {
+			err = copyPodLogs(logs, entry, &containerFilter)
+		}
+		logs.Close()
+		if err != nil {
+			log.Printf("打包 Pod %s/%s 容器 %s 日志中断: %v", namespace, name, container, err)
+			return
+		}
+	}
+}
+
+// 聚合日志的 Pod 数量与并发上限
+const (
+	maxAggregatedLogPods = 50
+	aggregatedLogWorkers = 8
+)
+
+// aggregatedLogLine 带来源 Pod 的日志行
+type aggregatedLogLine struct {
+	time    time.Time
+	pod     string
+	content string
+}
+
+// GetDeploymentLogs 聚合 Deployment 下所有 Pod 的日志，按时间戳合并排序，
+// 每行以 [pod] 标注来源；tailLines 作用于单个 Pod，其余参数与 GetPodLogs 相同
+func (h *Handler) GetDeploymentLogs(c *gin.Context) {
+	ctx := requestContext(c)
+	namespace := c.Param("ns")
+	name := c.Param("name")
+
+	filter, err := parseLogFilter(c, "100")
+	if err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	clientset := h.getK8s(c).Clientset
+	dep, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		writeError(c, http.StatusNotFound, err)
+		return
+	}
+	selector, err := metav1.LabelSelectorAsSelector(dep.Spec.Selector)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	podNames := make([]string, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		// 尚未调度的 Pod 没有日志
+		if pod.Spec.NodeName != "" {
+			podNames = append(podNames, pod.Name)
+		}
+	}
+	sort.Strings(podNames)
+	if len(podNames) > maxAggregatedLogPods {
+		c.Header("X-Log-Truncated-Pods", strconv.Itoa(len(podNames)-maxAggregatedLogPods))
+		podNames = podNames[:maxAggregatedLogPods]
+	}
+
+	// 未指定容器时使用 Pod 模板中的第一个容器
+	if filter.Container == "" && len(dep.Spec.Template.Spec.Containers) > 0 {
+		filter.Container = dep.Spec.Template.Spec.Containers[0].Name
+	}
+	// 合并排序需要时间戳，输出时按调用方要求决定是否保留
+	podFilter := *filter
+	podFilter.Timestamps = true
+
+	var (
+		mu      sync.Mutex
+		lines   []aggregatedLogLine
+		matched int
+		scanned int
+		failed  []string
+		wg      sync.WaitGroup
+	)
+	sem := make(chan struct{}, aggregatedLogWorkers)
+	for _, podName := range podNames {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(podName string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			podLines, podMatched, podScanned, err := h.collectPodLogLines(ctx, c, namespace, podName, &podFilter)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", podName, err))
+				return
+			}
+			lines = append(lines, podLines...)
+			matched += podMatched
+			scanned += podScanned
+		}(podName)
+	}
+	wg.Wait()
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].time.Before(lines[j].time)
+	})
+
+	var buf strings.Builder
+	sort.Strings(failed)
+	for _, msg := range failed {
+		fmt.Fprintf(&buf, "[dashboard] 获取日志失败 %s\n", msg)
+	}
+	for _, line := range lines {
+		buf.WriteString("[" + line.pod + "] ")
+		if filter.Timestamps && !line.time.IsZero() {
+			buf.WriteString(line.time.Format(time.RFC3339Nano) + " ")
+		}
+		buf.WriteString(line.content)
+		buf.WriteByte('\n')
+	}
+
+	c.Header("X-Log-Pod-Count", strconv.Itoa(len(podNames)))
+	if filter.filtering() {
+		c.Header("X-Log-Match-Count", strconv.Itoa(matched))
+		c.Header("X-Log-Scanned-Lines", strconv.Itoa(scanned))
+	}
+	c.String(http.StatusOK, buf.String())
+}
+
+// collectPodLogLines 读取单个 Pod 的日志（需带时间戳）并解析为日志行
+func (h *Handler) collectPodLogLines(ctx context.Context, c *gin.Context, namespace, pod string, f *logFilter) ([]aggregatedLogLine, int, int, error) {
+	logs, err := h.getK8s(c).Clientset.CoreV1().Pods(namespace).GetLogs(pod, f.podLogOptions()).Stream(ctx)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	defer logs.Close()
+
+	var buf strings.Builder
+	matched, scanned, err := filterLogStream(logs, &buf, f)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	var lines []aggregatedLogLine
+	for _, raw := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+		if raw == "" {
+			continue
+		}
+		line := aggregatedLogLine{pod: pod, content: strings.TrimRight(raw, "\r")}
+		if ts, rest, ok := strings.Cut(line.content, " "); ok {
+			if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+				line.time = t
+				line.content = rest
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines, matched, scanned, nil
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/metrics"
+	"github.com/k8s-dashboard/backend/internal/promql"
+)
+
+// MetricsQueryHandler 自定义 PromQL 查询代理
+type MetricsQueryHandler struct {
+	h      *Handler
+	policy *promql.Policy
+}
+
+// NewMetricsQueryHandler 创建自定义查询处理器
+func NewMetricsQueryHandler(h *Handler, policy *promql.Policy) *MetricsQueryHandler {
+	return &MetricsQueryHandler{h: h, policy: policy}
+}
+
+// MetricsQueryRequest 自定义查询请求，type 为 instant（默认）或 range，时间均为 Unix 秒
+type MetricsQueryRequest struct {
+	Query string `json:"query"`
+	Type  string `json:"type"`
+	// Time 即时查询的时间点，默认当前时间
+	Time int64 `json:"time,omitempty"`
+	// Start、End、Step 范围查询参数，默认最近 1 小时、步长 1m
+	Start int64  `json:"start,omitempty"`
+	End   int64  `json:"end,omitempty"`
+	Step  string `json:"step,omitempty"`
+}
+
+// MetricsQueryResponse 查询结果，结构与 Prometheus HTTP API 的 data 字段一致
+type MetricsQueryResponse struct {
+	ResultType string                `json:"resultType"`
+	Result     []metrics.QueryResult `json:"result"`
+	Time       int64                 `json:"time,omitempty"`
+	Start      int64                 `json:"start,omitempty"`
+	End        int64                 `json:"end,omitempty"`
+	Step       string                `json:"step,omitempty"`
+}
+
+// Query 将用户提交的 PromQL 转发给 VictoriaMetrics，供自定义面板使用而不直接暴露 VM。
+// 查询先经过指标允许/拒绝列表与时间范围检查；命名空间受限的用户只能查到可见命名空间的序列，
+// 不带 namespace 标签的指标（如节点指标）对其不可见
+func (qh *MetricsQueryHandler) Query(c *gin.Context) {
+	if qh.h.getMetrics(c) == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "metrics client not configured"})
+		return
+	}
+	if qh.policy == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "自定义查询未启用"})
+		return
+	}
+
+	var req MetricsQueryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+	req.Query = strings.TrimSpace(req.Query)
+	now := time.Now()
+	if err := qh.policy.CheckQuery(req.Query, now); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, promql.ErrMetricDenied) {
+			status = http.StatusForbidden
+		}
+		writeError(c, status, err)
+		return
+	}
+
+	resp := MetricsQueryResponse{}
+	var start, end, ts time.Time
+	var step time.Duration
+	switch req.Type {
+	case "", "instant":
+		resp.ResultType = "vector"
+		ts = now
+		if req.Time != 0 {
+			ts = time.Unix(req.Time, 0)
+		}
+		if err := qh.policy.CheckInstant(ts, now); err != nil {
+			writeError(c, http.StatusBadRequest, fmt.Errorf("time %w", err))
+			return
+		}
+		resp.Time = ts.Unix()
+	case "range":
+		resp.ResultType = "matrix"
+		end = now
+		if req.End != 0 {
+			end = time.Unix(req.End, 0)
+		}
+		start = end.Add(-time.Hour)
+		if req.Start != 0 {
+			start = time.Unix(req.Start, 0)
+		}
+		if req.Step == "" {
+			req.Step = "1m"
+		}
+		var err error
+		if step, err = time.ParseDuration(req.Step); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "step 格式无效: " + req.Step})
+			return
+		}
+		if err := qh.policy.CheckRange(start, end, step, now); err != nil {
+			writeError(c, http.StatusBadRequest, err)
+			return
+		}
+		resp.Start, resp.End, resp.Step = start.Unix(), end.Unix(), req.Step
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type 必须为 instant 或 range"})
+		return
+	}
+
+	namespaces, ok := qh.h.metricsNamespaces(c)
+	if !ok {
+		return
+	}
+	query := req.Query
+	if namespaces != nil {
+		if len(namespaces) == 0 {
+			resp.Result = []metrics.QueryResult{}
+			c.JSON(http.StatusOK, resp)
+			return
+		}
+		scoped, err := promql.Scope(query, metrics.NamespaceMatcher(namespaces))
+		if err != nil {
+			writeError(c, http.StatusBadRequest, err)
+			return
+		}
+		query = scoped
+	}
+
+	client := qh.h.getMetrics(c).Raw().WithContext(requestContext(c))
+	var result *metrics.QueryResponse
+	var err error
+	if req.Type == "range" {
+		result, err = client.QueryRange(query, start, end, strconv.FormatFloat(step.Seconds(), 'f', -1, 64))
+	} else {
+		result, err = client.QueryAt(query, ts)
+	}
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp.ResultType = result.Data.ResultType
+	resp.Result = result.Data.Result
+	if resp.Result == nil {
+		resp.Result = []metrics.QueryResult{}
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/metrics"
+)
+
+// networkMetricsNamespaces 解析网络流量接口的命名空间范围：受限用户只能查询可见命名空间，namespace 参数进一步收窄。
+// 返回 nil 表示不限制；ok 为 false 时已写出错误响应
+func (h *Handler) networkMetricsNamespaces(c *gin.Context) ([]string, bool) {
+	namespaces, ok := h.metricsNamespaces(c)
+	if !ok {
+		return nil, false
+	}
+	if ns := c.Query("namespace"); ns != "" {
+		if namespaces != nil && !slices.Contains(namespaces, ns) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "无权访问该命名空间"})
+			return nil, false
+		}
+		namespaces = []string{ns}
+	}
+	return namespaces, true
+}
+
+// GetPodNetworkMetrics 获取各 Pod 的网络收发速率（bytes/s），按收发合计降序排列，用于找出占用带宽最多的工作负载。
+// limit 参数只返回前 N 个，total 为截断前的数量
+func (h *Handler) GetPodNetworkMetrics(c *gin.Context) {
+	if h.getMetrics(c) == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "metrics client not configured"})
+		return
+	}
+
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		limit = n
+	}
+	namespaces, ok := h.networkMetricsNamespaces(c)
+	if !ok {
+		return
+	}
+	if namespaces != nil && len(namespaces) == 0 {
+		c.JSON(http.StatusOK, gin.H{"items": []metrics.PodNetworkMetrics{}, "total": 0})
+		return
+	}
+
+	pods, err := h.getMetrics(c).WithContext(requestContext(c)).GetPodNetworkMetrics(namespaces)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	total := len(pods)
+	if limit > 0 && len(pods) > limit {
+		pods = pods[:limit]
+	}
+	c.JSON(http.StatusOK, gin.H{"items": pods, "total": total})
+}
+
+// GetNamespaceNetworkMetrics 按命名空间汇总 Pod 网络收发速率（bytes/s），按收发合计降序排列
+func (h *Handler) GetNamespaceNetworkMetrics(c *gin.Context) {
+	if h.getMetrics(c) == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "metrics client not configured"})
+		return
+	}
+
+	namespaces, ok := h.networkMetricsNamespaces(c)
+	if !ok {
+		return
+	}
+	if namespaces != nil && len(namespaces) == 0 {
+		c.JSON(http.StatusOK, gin.H{"items": []metrics.NamespaceNetworkMetrics{}, "total": 0})
+		return
+	}
+
+	pods, err := h.getMetrics(c).WithContext(requestContext(c)).GetPodNetworkMetrics(namespaces)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	items := metrics.AggregateNamespaceNetwork(pods)
+	c.JSON(http.StatusOK, gin.H{"items": items, "total": len(items)})
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/capacity"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// nodePoolLabel 节点池分组标签：请求参数 label 优先，其次为配置的 NODE_POOL_LABEL，
+// 均为空时自动识别常见云厂商的节点池标签
+func (h *Handler) nodePoolLabel(c *gin.Context) string {
+	if label := strings.TrimSpace(c.Query("label")); label != "" {
+		return label
+	}
+	return h.opts.NodePoolLabel
+}
+
+// ListNodePools 按节点池汇总节点数量、Ready/NotReady/已封锁状态、容量与 metrics-server 用量
+func (h *Handler) ListNodePools(c *gin.Context) {
+	ctx := requestContext(c)
+	client := h.getK8s(c)
+	nodes, err := client.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	pods, err := client.Clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{FieldSelector: "status.phase=Running"})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	var nodeUsage map[string]corev1.ResourceList
+	if client.MetricsClient != nil {
+		if list, err := client.MetricsClient.MetricsV1beta1().NodeMetricses().List(ctx, metav1.ListOptions{}); err == nil {
+			nodeUsage = make(map[string]corev1.ResourceList, len(list.Items))
+			for _, m := range list.Items {
+				nodeUsage[m.Name] = m.Usage
+			}
+		}
+	}
+
+	label := h.nodePoolLabel(c)
+	pools := capacity.SummarizeNodePools(nodes.Items, pods.Items, nodeUsage, label)
+	c.JSON(http.StatusOK, gin.H{"items": pools, "total": len(pools), "label": label})
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
+	"github.com/k8s-dashboard/backend/internal/notifications"
+)
+
+// NotificationChannelHandler 通知渠道（Slack、钉钉、企业微信、邮件、Webhook）管理处理器
+type NotificationChannelHandler struct {
+	service *notifications.Service
+}
+
+// NewNotificationChannelHandler 创建通知渠道处理器
+func NewNotificationChannelHandler(service *notifications.Service) *NotificationChannelHandler {
+	return &NotificationChannelHandler{service: service}
+}
+
+// writeNotificationChannelError 将通知渠道服务错误映射为 HTTP 状态码
+func writeNotificationChannelError(c *gin.Context, err error) {
+	var validationErr *notifications.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Message, "field": validationErr.Field})
+	case errors.Is(err, notifications.ErrChannelNotFound):
+		writeError(c, http.StatusNotFound, err)
+	case errors.Is(err, notifications.ErrDeliveryFailed):
+		writeError(c, http.StatusBadGateway, err)
+	default:
+		writeError(c, http.StatusInternalServerError, err)
+	}
+}
+
+func (nh *NotificationChannelHandler) available(c *gin.Context) bool {
+	if nh.service == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "通知渠道服务未启用"})
+		return false
+	}
+	return true
+}
+
+// channelID 解析路径中的渠道 ID
+func channelID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid channel id"})
+		return 0, false
+	}
+	return id, true
+}
+
+// ListChannels 列出通知渠道（admin），密钥以占位值返回
+func (nh *NotificationChannelHandler) ListChannels(c *gin.Context) {
+	if !nh.available(c) {
+		return
+	}
+	items, err := nh.service.List()
+	if err != nil {
+		writeNotificationChannelError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "total": len(items)})
+}
+
+// GetChannel 获取通知渠道（admin）
+func (nh *NotificationChannelHandler) GetChannel(c *gin.Context) {
+	if !nh.available(c) {
+		return
+	}
+	id, ok := channelID(c)
+	if !ok {
+		return
+	}
+	ch, err := nh.service.Get(id)
+	if err != nil {
+		writeNotificationChannelError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ch)
+}
+
+// CreateChannel 创建通知渠道（admin）
+func (nh *NotificationChannelHandler) CreateChannel(c *gin.Context) {
+	if !nh.available(c) {
+		return
+	}
+	var req notifications.ChannelInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	var createdBy string
+	if user := middleware.GetCurrentUser(c); user != nil {
+		createdBy = user.Username
+	}
+	ch, err := nh.service.Create(req, createdBy)
+	if err != nil {
+		writeNotificationChannelError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, ch)
+}
+
+// UpdateChannel 更新通知渠道（admin），密钥提交占位值时保留原值
+func (nh *NotificationChannelHandler) UpdateChannel(c *gin.Context) {
+	if !nh.available(c) {
+		return
+	}
+	id, ok := channelID(c)
+	if !ok {
+		return
+	}
+	var req notifications.ChannelInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+	ch, err := nh.service.Update(id, req)
+	if err != nil {
+		writeNotificationChannelError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ch)
+}
+
+// DeleteChannel 删除通知渠道（admin）
+func (nh *NotificationChannelHandler) DeleteChannel(c *gin.Context) {
+	if !nh.available(c) {
+		return
+	}
+	id, ok := channelID(c)
+	if !ok {
+		return
+	}
+	if err := nh.service.Delete(id); err != nil {
+		writeNotificationChannelError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// TestChannel 向渠道发送测试通知（admin），推送失败返回 502 及渠道返回的错误
+func (nh *NotificationChannelHandler) TestChannel(c *gin.Context) {
+	if !nh.available(c) {
+		return
+	}
+	id, ok := channelID(c)
+	if !ok {
+		return
+	}
+	if err := nh.service.Test(c.Request.Context(), id); err != nil {
+		writeNotificationChannelError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "sent"})
+}
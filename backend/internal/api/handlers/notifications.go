@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
+	"github.com/k8s-dashboard/backend/internal/auth"
+	"github.com/k8s-dashboard/backend/internal/notify"
+)
+
+const (
+	// sessionCheckInterval 会话过期检查间隔
+	sessionCheckInterval = 30 * time.Second
+	// sessionExpiringWindow 距离过期不足该时长时提醒用户重新登录
+	sessionExpiringWindow = 5 * time.Minute
+)
+
+// NotificationHandler 实时通知推送处理器
+type NotificationHandler struct {
+	h   *Handler
+	hub *notify.Hub
+}
+
+// NewNotificationHandler 创建实时通知处理器
+func NewNotificationHandler(h *Handler, hub *notify.Hub) *NotificationHandler {
+	return &NotificationHandler{h: h, hub: hub}
+}
+
+// StreamNotifications 通过 WebSocket 推送待审批数量变化（仅 admin）、审批通知与会话即将过期提醒，
+// 替代前端轮询 /approvals/pending/count
+func (n *NotificationHandler) StreamNotifications(c *gin.Context) {
+	if n.hub == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "通知服务未启用"})
+		return
+	}
+
+	user, err := n.h.eventStreamUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未认证"})
+		return
+	}
+	sessionID := ""
+	if ticket := middleware.GetWSTicket(c); ticket != nil {
+		sessionID = ticket.SessionID
+	}
+
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool {
+			return n.h.isAllowedWSOrigin(r)
+		},
+	}
+	ws, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer ws.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 只读连接，读循环仅用于感知客户端断开
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	send := func(msg notify.Message) error {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		ws.SetWriteDeadline(time.Now().Add(eventStreamWriteTimeout))
+		return ws.WriteMessage(websocket.TextMessage, data)
+	}
+
+	messages, unsubscribe := n.hub.Subscribe(user.ID, user.Role == "admin")
+	defer unsubscribe()
+
+	pingTicker := time.NewTicker(eventStreamPingInterval)
+	defer pingTicker.Stop()
+	sessionTicker := time.NewTicker(sessionCheckInterval)
+	defer sessionTicker.Stop()
+
+	// checkSession 返回 false 表示会话已失效，需要关闭连接
+	warned := false
+	checkSession := func() bool {
+		if sessionID == "" || n.h.auth == nil {
+			return true
+		}
+		expiresAt, err := n.h.auth.SessionExpiry(sessionID)
+		if errors.Is(err, auth.ErrInvalidToken) || (err == nil && !time.Now().Before(expiresAt)) {
+			send(notify.Message{Type: notify.MessageSessionExpired})
+			return false
+		}
+		if err == nil && !warned && time.Until(expiresAt) <= sessionExpiringWindow {
+			warned = true
+			send(notify.Message{Type: notify.MessageSessionExpiring, ExpiresAt: &expiresAt})
+		}
+		return true
+	}
+
+	if !checkSession() {
+		return
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-messages:
+			if err := send(msg); err != nil {
+				return
+			}
+		case <-pingTicker.C:
+			ws.SetWriteDeadline(time.Now().Add(eventStreamWriteTimeout))
+			if err := ws.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-sessionTicker.C:
+			if !checkSession() {
+				ws.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "session expired"),
+					time.Now().Add(time.Second))
+				return
+			}
+		}
+	}
+}
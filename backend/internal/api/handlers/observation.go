@@ -1,7 +1,8 @@
 package handlers
 
 import (
-	"context"
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -22,30 +23,90 @@ func NewObservationHandler(service *observation.Service) *ObservationHandler {
 }
 
 func (h *ObservationHandler) serviceForRequest(c *gin.Context) *observation.Service {
-	return h.service.WithK8sClient(middleware.GetClusterClient(c))
+	scope := middleware.GetScope(c)
+	if scope == nil {
+		return h.service.WithCluster(middleware.GetClusterName(c))
+	}
+	return h.service.WithK8sClient(scope.K8s).WithEndpointClients(scope.Metrics, scope.Alerts).WithCluster(middleware.GetClusterName(c))
+}
+
+// observationNamespaces 返回查询需要限定的命名空间：namespace 参数非空时只查该命名空间，
+// 受限用户限定为可见命名空间（nil 表示不限制）。无权访问或没有任何可见命名空间时返回 false，
+// 后者由 empty 写出空结果
+func observationNamespaces(c *gin.Context, empty func()) ([]string, bool) {
+	namespace := c.Query("namespace")
+
+	user := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthenticated"})
+		return nil, false
+	}
+	if user.Role == "admin" || user.AllNamespaces {
+		if namespace != "" {
+			return []string{namespace}, true
+		}
+		return nil, true
+	}
+
+	scope := namespaceAccessScope{allowed: middleware.GetAllowedNamespaces(c)}
+	if namespace != "" && !namespaceAllowed(scope, namespace) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "no permission for namespace " + namespace})
+		return nil, false
+	}
+	if namespace != "" {
+		return []string{namespace}, true
+	}
+	if len(scope.allowed) == 0 {
+		empty()
+		return nil, false
+	}
+	return scope.allowed, true
+}
+
+// observationTimeRange 解析 range 参数（realtime、1h、24h、7d、30d），不支持的值返回 400
+func observationTimeRange(c *gin.Context, defaultRange string) (observation.TimeRange, bool) {
+	value := c.DefaultQuery("range", defaultRange)
+	timeRange := observation.ParseTimeRange(value)
+	if string(timeRange) != value {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "range 仅支持 realtime、1h、24h、7d、30d"})
+		return "", false
+	}
+	return timeRange, true
 }
 
-// GetObservationSummary 获取异常状态汇总
+// GetObservationSummary 获取异常状态汇总，namespace 参数或受限用户的可见命名空间限定 Pod 异常与资源超限的统计范围
 func (h *ObservationHandler) GetObservationSummary(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
+	namespaces, ok := observationNamespaces(c, func() {
+		c.JSON(http.StatusOK, observation.ObservationSummary{})
+	})
+	if !ok {
+		return
+	}
 
-	summary, err := h.serviceForRequest(c).GetSummary(ctx)
+	summary, err := h.serviceForRequest(c).GetSummary(ctx, namespaces)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, summary)
 }
 
-// GetPodAnomalies 获取异常 Pod 列表
+// GetPodAnomalies 获取异常 Pod 列表，仅包含用户有权访问的命名空间
 func (h *ObservationHandler) GetPodAnomalies(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Query("namespace")
+	namespaces, ok := observationNamespaces(c, func() {
+		c.JSON(http.StatusOK, gin.H{"items": []observation.PodAnomaly{}, "total": 0})
+	})
+	if !ok {
+		return
+	}
 
-	anomalies, err := h.serviceForRequest(c).GetPodAnomalies(ctx, namespace)
+	anomalies, err := h.serviceForRequest(c).GetPodAnomalies(ctx, namespace, namespaces)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -57,11 +118,11 @@ func (h *ObservationHandler) GetPodAnomalies(c *gin.Context) {
 
 // GetNodeAnomalies 获取异常节点列表
 func (h *ObservationHandler) GetNodeAnomalies(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 
 	anomalies, err := h.serviceForRequest(c).GetNodeAnomalies(ctx)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -71,14 +132,20 @@ func (h *ObservationHandler) GetNodeAnomalies(c *gin.Context) {
 	})
 }
 
-// GetResourceExcess 获取资源超限列表
+// GetResourceExcess 获取资源超限列表，仅包含用户有权访问的命名空间
 func (h *ObservationHandler) GetResourceExcess(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	namespace := c.Query("namespace")
+	namespaces, ok := observationNamespaces(c, func() {
+		c.JSON(http.StatusOK, gin.H{"items": []observation.ResourceExcess{}, "total": 0})
+	})
+	if !ok {
+		return
+	}
 
-	excess, err := h.serviceForRequest(c).GetResourceExcess(ctx, namespace)
+	excess, err := h.serviceForRequest(c).GetResourceExcess(ctx, namespace, namespaces)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -88,45 +155,159 @@ func (h *ObservationHandler) GetResourceExcess(c *gin.Context) {
 	})
 }
 
-// GetResourceTrend 获取资源使用趋势
+// GetResourceTrend 获取集群资源使用率趋势（type=cpu|memory），range 默认 24h
 func (h *ObservationHandler) GetResourceTrend(c *gin.Context) {
-	ctx := context.Background()
+	ctx := requestContext(c)
 	resourceType := observation.ResourceType(c.DefaultQuery("type", "cpu"))
-	timeRange := observation.ParseTimeRange(c.DefaultQuery("range", "24h"))
+	if resourceType != observation.ResourceTypeCPU && resourceType != observation.ResourceTypeMemory {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type 仅支持 cpu 或 memory"})
+		return
+	}
+	timeRange, ok := observationTimeRange(c, "24h")
+	if !ok {
+		return
+	}
 
 	trend, err := h.serviceForRequest(c).GetResourceTrend(ctx, resourceType, timeRange)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, trend)
 }
 
-// GetAlertTrend 获取告警趋势
+// GetAlertTrend 获取告警趋势，range 默认 7d
 func (h *ObservationHandler) GetAlertTrend(c *gin.Context) {
-	ctx := context.Background()
-	timeRange := observation.ParseTimeRange(c.DefaultQuery("range", "7d"))
+	ctx := requestContext(c)
+	timeRange, ok := observationTimeRange(c, "7d")
+	if !ok {
+		return
+	}
 
 	trend, err := h.serviceForRequest(c).GetAlertTrend(ctx, timeRange)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, trend)
 }
 
-// GetRestartTrend 获取 Pod 重启趋势
+// GetRestartTrend 获取 Pod 重启趋势，range 默认 24h，namespace 参数或受限用户的可见命名空间限定统计范围
 func (h *ObservationHandler) GetRestartTrend(c *gin.Context) {
-	ctx := context.Background()
-	timeRange := observation.ParseTimeRange(c.DefaultQuery("range", "24h"))
+	ctx := requestContext(c)
+	timeRange, ok := observationTimeRange(c, "24h")
+	if !ok {
+		return
+	}
+	namespaces, ok := observationNamespaces(c, func() {
+		c.JSON(http.StatusOK, observation.RestartTrend{Current: []observation.RestartTrendPoint{}})
+	})
+	if !ok {
+		return
+	}
 
-	trend, err := h.serviceForRequest(c).GetRestartTrend(ctx, timeRange)
+	trend, err := h.serviceForRequest(c).GetRestartTrend(ctx, timeRange, namespaces)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, trend)
 }
+
+// thresholdRuleRequest 保存异常阈值规则的请求体，cluster、namespace 为空表示全部
+type thresholdRuleRequest struct {
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+	observation.AnomalyThresholds
+}
+
+// thresholdRulesResponse 阈值规则列表及默认阈值
+type thresholdRulesResponse struct {
+	Items    observation.ThresholdSet      `json:"items"`
+	Total    int                           `json:"total"`
+	Defaults observation.AnomalyThresholds `json:"defaults"`
+}
+
+// writeThresholdError 将阈值规则错误映射为 HTTP 状态码
+func writeThresholdError(c *gin.Context, err error) {
+	var validationErr *observation.ThresholdValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Message, "field": validationErr.Field})
+	case errors.Is(err, observation.ErrThresholdRuleNotFound):
+		writeError(c, http.StatusNotFound, err)
+	default:
+		writeError(c, http.StatusInternalServerError, err)
+	}
+}
+
+func (h *ObservationHandler) thresholdRepository(c *gin.Context) *observation.ThresholdRepository {
+	repo := h.service.Thresholds()
+	if repo == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "异常阈值配置未启用"})
+	}
+	return repo
+}
+
+// ListThresholdRules 列出异常阈值规则（admin）
+func (h *ObservationHandler) ListThresholdRules(c *gin.Context) {
+	repo := h.thresholdRepository(c)
+	if repo == nil {
+		return
+	}
+	items, err := repo.List()
+	if err != nil {
+		writeThresholdError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, thresholdRulesResponse{Items: items, Total: len(items), Defaults: observation.DefaultAnomalyThresholds()})
+}
+
+// SaveThresholdRule 保存集群/命名空间的异常阈值规则，同一范围已有规则时覆盖（admin）
+func (h *ObservationHandler) SaveThresholdRule(c *gin.Context) {
+	repo := h.thresholdRepository(c)
+	if repo == nil {
+		return
+	}
+	var req thresholdRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	rule := &observation.ThresholdRule{Cluster: req.Cluster, Namespace: req.Namespace, AnomalyThresholds: req.AnomalyThresholds}
+	if user := middleware.GetCurrentUser(c); user != nil {
+		rule.UpdatedBy = user.Username
+	}
+	saved, err := repo.Save(rule)
+	if err != nil {
+		writeThresholdError(c, err)
+		return
+	}
+	middleware.SetAuditDetail(c, fmt.Sprintf("cluster=%q namespace=%q pending=%dm restarts=%d oom=%dm",
+		saved.Cluster, saved.Namespace, saved.PendingMinutes, saved.RestartCount, saved.OOMWindowMinutes))
+	c.JSON(http.StatusOK, saved)
+}
+
+// DeleteThresholdRule 删除异常阈值规则（admin）
+func (h *ObservationHandler) DeleteThresholdRule(c *gin.Context) {
+	repo := h.thresholdRepository(c)
+	if repo == nil {
+		return
+	}
+	var id int64
+	if _, err := parsePathInt64(c, "id", &id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid threshold rule id"})
+		return
+	}
+	rule, err := repo.Delete(id)
+	if err != nil {
+		writeThresholdError(c, err)
+		return
+	}
+	middleware.SetAuditDetail(c, fmt.Sprintf("cluster=%q namespace=%q", rule.Cluster, rule.Namespace))
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/k8s-dashboard/backend/internal/alertmanager"
+	"github.com/k8s-dashboard/backend/internal/alerts"
+	"github.com/k8s-dashboard/backend/internal/audit"
+	"github.com/k8s-dashboard/backend/internal/auth"
+	"github.com/k8s-dashboard/backend/internal/clusters"
+	"github.com/k8s-dashboard/backend/internal/cost"
+	"github.com/k8s-dashboard/backend/internal/k8s"
+	"github.com/k8s-dashboard/backend/internal/metrics"
+	"github.com/k8s-dashboard/backend/internal/notifications"
+	"github.com/k8s-dashboard/backend/internal/observation"
+	"github.com/k8s-dashboard/backend/internal/openapi"
+	"github.com/k8s-dashboard/backend/internal/panels"
+	"github.com/k8s-dashboard/backend/internal/recommendations"
+	"github.com/k8s-dashboard/backend/internal/runbooks"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	storagev1 "k8s.io/api/storage/v1"
+)
+
+// apiPrefix OpenAPIRoutes 中路由的公共前缀
+const apiPrefix = "/api/v1"
+
+// namespacedResource 命名空间级资源的通用增删改查接口
+type namespacedResource struct {
+	path   string
+	object interface{}
+	// detail 详情接口的响应，含关联资源等附加字段
+	detail interface{}
+	// writable 是否提供 POST 创建与 PUT 更新
+	writable bool
+}
+
+var namespacedResources = []namespacedResource{
+	{"pods", corev1.Pod{}, podDetail{}, false},
+	{"deployments", appsv1.Deployment{}, deploymentDetail{}, true},
+	{"statefulsets", appsv1.StatefulSet{}, statefulSetDetail{}, false},
+	{"daemonsets", appsv1.DaemonSet{}, daemonSetDetail{}, false},
+	{"jobs", batchv1.Job{}, jobDetail{}, false},
+	{"cronjobs", batchv1.CronJob{}, cronJobDetail{}, false},
+	{"services", corev1.Service{}, corev1.Service{}, true},
+	{"ingresses", networkingv1.Ingress{}, IngressDetail{}, true},
+	{"configmaps", corev1.ConfigMap{}, corev1.ConfigMap{}, true},
+	{"secrets", corev1.Secret{}, corev1.Secret{}, true},
+	{"persistentvolumeclaims", corev1.PersistentVolumeClaim{}, PersistentVolumeClaimDetail{}, false},
+}
+
+// deletedResponse 删除类接口的响应
+type deletedResponse struct {
+	Message string `json:"message"`
+}
+
+// tokenResponse 登录与刷新令牌的响应
+type tokenResponse struct {
+	auth.TokenPair
+	User       *auth.User           `json:"user"`
+	Namespaces []auth.UserNamespace `json:"namespaces"`
+}
+
+// currentUserResponse 当前用户及其命名空间授权
+type currentUserResponse struct {
+	User       *auth.User           `json:"user"`
+	Namespaces []auth.UserNamespace `json:"namespaces"`
+}
+
+// historyResponse 用量历史曲线
+type historyResponse struct {
+	Data []metrics.TimeSeriesData `json:"data"`
+}
+
+// topConsumersResponse 资源占用排行
+type topConsumersResponse struct {
+	Resource string                `json:"resource"`
+	Scope    string                `json:"scope"`
+	Items    []metrics.TopConsumer `json:"items"`
+}
+
+// OpenAPIRoutes 以 "METHOD /path" 为键的接口说明，未登记的路由只生成路径参数与错误响应
+func OpenAPIRoutes() map[string]openapi.Route {
+	docs := map[string]openapi.Route{
+		"GET /api/v1/openapi.json": {Summary: "OpenAPI 文档", Public: true},
+
+		// 认证
+		"POST /api/v1/auth/login":    {Summary: "登录", Public: true, Request: LoginRequest{}, Response: tokenResponse{}},
+		"POST /api/v1/auth/refresh":  {Summary: "刷新访问令牌", Public: true, Request: RefreshRequest{}, Response: tokenResponse{}},
+		"POST /api/v1/auth/logout":   {Summary: "登出并撤销会话", Public: true, Request: RefreshRequest{}},
+		"GET /api/v1/auth/me":        {Summary: "当前用户", Response: currentUserResponse{}},
+		"POST /api/v1/auth/password": {Summary: "修改密码", Request: ChangePasswordRequest{}},
+		"GET /api/v1/auth/sessions": {Summary: "当前用户的会话", Response: struct {
+			Items []auth.Session `json:"items"`
+		}{}},
+		"POST /api/v1/auth/tokens": {Summary: "创建 API 令牌", Request: auth.CreateAPITokenRequest{}, Status: http.StatusCreated},
+		"POST /api/v1/batch":       {Summary: "批量删除、重启与扩缩容", Request: BatchRequest{}, Response: BatchResponse{}},
+
+		// 集群
+		"GET /api/v1/clusters":       {Summary: "集群列表", Response: []clusters.Info{}},
+		"GET /api/v1/clusters/:name": {Summary: "集群详情", Response: clusters.Info{}},
+
+		// 概览与搜索
+		"GET /api/v1/overview":        {Summary: "集群概览", Response: OverviewResponse{}},
+		"GET /api/v1/overview/issues": {Summary: "概览问题列表", Response: OverviewIssuesResponse{}},
+		"GET /api/v1/search":          {Summary: "全局搜索", Query: []string{"q", "kinds", "namespace", "limit"}, Response: k8s.SearchResult{}},
+
+		// 告警
+		"GET /api/v1/alerts/summary":                                      {Summary: "告警统计", Response: alertmanager.AlertSummary{}},
+		"GET /api/v1/alerts":                                              {Summary: "告警列表", Query: []string{"severity", "namespace", "alertname", "state", "search", "sort", "page", "pageSize"}, Response: openapi.List(AlertItem{})},
+		"GET /api/v1/alerts/:fingerprint":                                 {Summary: "告警详情（含确认状态）", Response: AlertItem{}},
+		"GET /api/v1/alerts/history":                                      {Summary: "告警历史（Alertmanager Webhook 推送）", Query: []string{"status", "severity", "alertname", "namespace", "fingerprint", "startTime", "endTime", "page", "pageSize"}, Response: openapi.List(alerts.HistoryEntry{})},
+		"POST /api/v1/integrations/alertmanager/webhook":                  {Summary: "接收 Alertmanager Webhook 推送（Bearer 令牌认证）", Public: true, Query: []string{"cluster"}, Request: alertmanager.WebhookMessage{}, Response: webhookResponse{}},
+		"POST /api/v1/alerts/:fingerprint/ack":                            {Summary: "确认告警（标记当前用户正在处理）", Request: ackRequest{}, Response: alerts.Acknowledgement{}},
+		"DELETE /api/v1/alerts/:fingerprint/ack":                          {Summary: "取消确认告警"},
+		"GET /api/v1/alerts/:fingerprint/ack":                             {Summary: "告警确认记录", Response: alerts.Acknowledgement{}},
+		"GET /api/v1/alertmanager/status":                                 {Summary: "Alertmanager 状态", Response: alertmanager.Status{}},
+		"GET /api/v1/alertmanager/routes":                                 {Summary: "Alertmanager 路由树", Response: alertmanager.Route{}},
+		"GET /api/v1/alerts/silences":                                     {Summary: "静默规则列表", Query: []string{"state"}, Response: openapi.List(alerts.Silence{})},
+		"POST /api/v1/alerts/silences":                                    {Summary: "创建静默规则", Request: alerts.SilenceInput{}, Response: alerts.Silence{}, Status: http.StatusCreated},
+		"GET /api/v1/alerts/silences/:id":                                 {Summary: "静默规则详情", Response: alerts.Silence{}},
+		"PUT /api/v1/alerts/silences/:id":                                 {Summary: "更新静默规则", Request: alerts.SilenceInput{}, Response: alerts.Silence{}},
+		"DELETE /api/v1/alerts/silences/:id":                              {Summary: "删除静默规则", Response: deletedResponse{}},
+		"GET /api/v1/alerts/routes":                                       {Summary: "告警路由列表", Response: openapi.List(notifications.Route{})},
+		"POST /api/v1/alerts/routes":                                      {Summary: "创建告警路由（operator，非管理员只能匹配有权限的命名空间）", Request: notifications.RouteInput{}, Response: notifications.Route{}, Status: http.StatusCreated},
+		"GET /api/v1/alerts/routes/channels":                              {Summary: "可选的通知渠道", Response: openapi.List(notifications.ChannelOption{})},
+		"GET /api/v1/alerts/routes/:id":                                   {Summary: "告警路由详情", Response: notifications.Route{}},
+		"PUT /api/v1/alerts/routes/:id":                                   {Summary: "更新告警路由（创建者或管理员）", Request: notifications.RouteInput{}, Response: notifications.Route{}},
+		"DELETE /api/v1/alerts/routes/:id":                                {Summary: "删除告警路由（创建者或管理员）", Response: deletedResponse{}},
+		"GET /api/v1/silences":                                            {Summary: "静默规则列表（已废弃）", Query: []string{"state"}, Response: openapi.List(alerts.Silence{}), Deprecated: true},
+		"POST /api/v1/silences":                                           {Summary: "创建静默规则（已废弃）", Request: alerts.SilenceInput{}, Response: alerts.Silence{}, Status: http.StatusCreated, Deprecated: true},
+		"GET /api/v1/silences/:id":                                        {Summary: "静默规则详情（已废弃）", Response: alerts.Silence{}, Deprecated: true},
+		"DELETE /api/v1/silences/:id":                                     {Summary: "删除静默规则（已废弃）", Response: deletedResponse{}, Deprecated: true},
+		"GET /api/v1/namespaces":                                          {Summary: "命名空间列表", Response: openapi.List(corev1.Namespace{})},
+		"POST /api/v1/namespaces":                                         {Summary: "创建命名空间", Request: corev1.Namespace{}, Response: corev1.Namespace{}, Status: http.StatusCreated},
+		"GET /api/v1/namespaces/:ns":                                      {Summary: "命名空间详情", Response: corev1.Namespace{}},
+		"DELETE /api/v1/namespaces/:ns":                                   {Summary: "删除命名空间", Response: deletedResponse{}},
+		"POST /api/v1/namespaces/:ns/cleanup":                             {Summary: "清理命名空间中的残留资源", Request: CleanupRequest{}},
+		"GET /api/v1/namespace/:ns":                                       {Summary: "命名空间详情（已废弃）", Response: corev1.Namespace{}, Deprecated: true},
+		"DELETE /api/v1/namespace/:ns":                                    {Summary: "删除命名空间（已废弃）", Response: deletedResponse{}, Deprecated: true},
+		"POST /api/v1/namespace/:ns/cleanup":                              {Summary: "清理命名空间（已废弃）", Request: CleanupRequest{}, Deprecated: true},
+		"GET /api/v1/namespaces/:ns/events":                               {Summary: "命名空间事件", Response: openapi.List(corev1.Event{})},
+		"GET /api/v1/events":                                              {Summary: "全部事件", Response: openapi.List(corev1.Event{})},
+		"POST /api/v1/namespaces/:ns/services/:name/ports":                {Summary: "更新 Service 端口", Request: ServicePortsRequest{}, Response: corev1.Service{}},
+		"POST /api/v1/namespaces/:ns/persistentvolumeclaims":              {Summary: "创建 PVC", Request: corev1.PersistentVolumeClaim{}, Response: corev1.PersistentVolumeClaim{}, Status: http.StatusCreated},
+		"POST /api/v1/namespaces/:ns/persistentvolumeclaims/:name/expand": {Summary: "扩容 PVC", Request: ExpandPVCRequest{}, Response: corev1.PersistentVolumeClaim{}},
+		"POST /api/v1/namespaces/:ns/deployments/:name/scale": {Summary: "调整副本数", Request: struct {
+			Replicas int32 `json:"replicas"`
+		}{}},
+		"POST /api/v1/namespaces/:ns/statefulsets/:name/scale": {Summary: "调整副本数", Request: struct {
+			Replicas int32 `json:"replicas"`
+		}{}},
+		"GET /api/v1/namespaces/:ns/configmaps/:name/usage": {Summary: "引用该 ConfigMap 的工作负载", Response: ConfigUsageResponse{}},
+		"GET /api/v1/namespaces/:ns/secrets/:name/usage":    {Summary: "引用该 Secret 的工作负载", Response: ConfigUsageResponse{}},
+
+		// 集群级资源
+		"GET /api/v1/nodes":                          {Summary: "节点列表", Response: openapi.List(corev1.Node{})},
+		"GET /api/v1/nodes/:name":                    {Summary: "节点详情", Response: corev1.Node{}},
+		"GET /api/v1/nodes/:name/pods":               {Summary: "节点上的 Pod", Response: openapi.List(corev1.Pod{})},
+		"GET /api/v1/persistentvolumes":              {Summary: "PV 列表", Response: openapi.List(corev1.PersistentVolume{})},
+		"GET /api/v1/persistentvolumes/:name":        {Summary: "PV 详情", Response: corev1.PersistentVolume{}},
+		"GET /api/v1/storageclasses":                 {Summary: "StorageClass 列表", Response: openapi.List(storagev1.StorageClass{})},
+		"GET /api/v1/storageclasses/:name":           {Summary: "StorageClass 详情", Response: storagev1.StorageClass{}},
+		"POST /api/v1/storageclasses":                {Summary: "创建 StorageClass", Request: storagev1.StorageClass{}, Response: storagev1.StorageClass{}, Status: http.StatusCreated},
+		"GET /api/v1/clusterroles":                   {Summary: "ClusterRole 列表", Response: openapi.List(rbacv1.ClusterRole{})},
+		"GET /api/v1/clusterrolebindings":            {Summary: "ClusterRoleBinding 列表", Response: openapi.List(rbacv1.ClusterRoleBinding{})},
+		"GET /api/v1/namespaces/:ns/roles":           {Summary: "Role 列表", Response: openapi.List(rbacv1.Role{})},
+		"GET /api/v1/namespaces/:ns/rolebindings":    {Summary: "RoleBinding 列表", Response: openapi.List(rbacv1.RoleBinding{})},
+		"GET /api/v1/serviceaccounts":                {Summary: "ServiceAccount 列表", Response: openapi.List(corev1.ServiceAccount{})},
+		"GET /api/v1/namespaces/:ns/serviceaccounts": {Summary: "ServiceAccount 列表", Response: openapi.List(corev1.ServiceAccount{})},
+
+		// 监控
+		"GET /api/v1/metrics/cluster":                   {Summary: "集群资源用量", Response: metrics.ClusterMetrics{}},
+		"GET /api/v1/metrics/history/cpu":               {Summary: "CPU 用量历史", Query: []string{"duration", "step"}, Response: historyResponse{}},
+		"GET /api/v1/metrics/history/memory":            {Summary: "内存用量历史", Query: []string{"duration", "step"}, Response: historyResponse{}},
+		"GET /api/v1/metrics/nodes/:name/history":       {Summary: "节点 CPU/内存/磁盘/网络/负载历史", Query: []string{"duration", "step"}, Response: metrics.NodeHistory{}},
+		"GET /api/v1/metrics/nodes/:name":               {Summary: "节点用量（含磁盘空间与 inode）", Response: metrics.NodeMetrics{}},
+		"GET /api/v1/metrics/pods/:ns/:name":            {Summary: "Pod 用量", Response: metrics.PodMetrics{}},
+		"GET /api/v1/metrics/pods/:ns/:name/containers": {Summary: "Pod 内各容器的用量、requests/limits 与 CPU 限流", Response: metrics.PodContainerMetrics{}},
+		"GET /api/v1/metrics/pods/:ns/:name/history":    {Summary: "Pod CPU/内存用量历史", Query: []string{"duration", "step"}, Response: metrics.PodHistory{}},
+		"GET /api/v1/metrics/pvc":                       {Summary: "PVC 容量用量", Response: openapi.List(metrics.VolumeMetrics{})},
+		"GET /api/v1/metrics/network/pods":              {Summary: "Pod 网络收发速率", Query: []string{"namespace", "limit"}, Response: openapi.List(metrics.PodNetworkMetrics{})},
+		"GET /api/v1/metrics/network/namespaces":        {Summary: "命名空间网络收发速率", Query: []string{"namespace"}, Response: openapi.List(metrics.NamespaceNetworkMetrics{})},
+		"POST /api/v1/metrics/query":                    {Summary: "自定义 PromQL 查询（受指标允许/拒绝列表与时间范围限制）", Request: MetricsQueryRequest{}, Response: MetricsQueryResponse{}},
+		"GET /api/v1/metrics/top":                       {Summary: "CPU/内存占用最多的 Pod、命名空间或节点", Query: []string{"resource", "scope", "k"}, Response: topConsumersResponse{}},
+		"GET /api/v1/metrics/gpu":                       {Summary: "GPU 用量", Response: openapi.List(metrics.GPUMetrics{})},
+		"GET /api/v1/capacity":                          {Summary: "容量规划", Query: []string{"lookback"}, Response: CapacityResponse{}},
+		"GET /api/v1/recommendations/resources":         {Summary: "容器资源建议", Query: []string{"namespace", "window"}, Response: recommendations.Report{}},
+		"GET /api/v1/cost/namespaces":                   {Summary: "按命名空间的费用估算", Query: []string{"window"}, Response: cost.Report{}},
+		"GET /api/v1/cost/workloads":                    {Summary: "按工作负载的费用估算", Query: []string{"namespace", "window"}, Response: cost.Report{}},
+
+		// 告警/记录规则
+		"GET /api/v1/rules":                               {Summary: "告警/记录规则组及评估状态", Query: []string{"type", "health"}, Response: ruleGroupsResponse{}},
+		"GET /api/v1/rules/resources":                     {Summary: "PrometheusRule / VMRule 列表", Query: []string{"namespace"}, Response: openapi.List(k8s.RuleResource{})},
+		"GET /api/v1/rules/resources/:resource/:ns/:name": {Summary: "规则 CR 详情", Response: k8s.RuleResource{}},
+		"PUT /api/v1/rules/resources/:resource/:ns/:name": {Summary: "编辑规则 CR 的规则组（admin，提交前校验表达式）", Request: updateRuleResourceRequest{}, Response: k8s.RuleResource{}},
+
+		// 集群观测
+		"GET /api/v1/observation/summary":          {Summary: "观测汇总（受限用户只统计可见命名空间）", Query: []string{"namespace"}, Response: observation.ObservationSummary{}},
+		"GET /api/v1/observation/pods/anomaly":     {Summary: "异常 Pod（CrashLoopBackOff、OOMKilled、长时间 Pending 等）", Query: []string{"namespace"}, Response: openapi.List(observation.PodAnomaly{})},
+		"GET /api/v1/observation/nodes/anomaly":    {Summary: "异常节点（NotReady 与资源压力）", Response: openapi.List(observation.NodeAnomaly{})},
+		"GET /api/v1/observation/resources/excess": {Summary: "用量超过 limits 80% 的 Pod", Query: []string{"namespace"}, Response: openapi.List(observation.ResourceExcess{})},
+		"GET /api/v1/observation/trends/resource":  {Summary: "集群资源使用率趋势及周环比", Query: []string{"type", "range"}, Response: observation.ResourceTrend{}},
+		"GET /api/v1/observation/trends/alerts":    {Summary: "告警趋势", Query: []string{"range"}, Response: observation.AlertTrend{}},
+		"GET /api/v1/observation/trends/restarts":  {Summary: "Pod 重启趋势及周环比", Query: []string{"namespace", "range"}, Response: observation.RestartTrend{}},
+
+		// 审计
+		"GET /api/v1/audit":               {Summary: "审计日志", Response: audit.ListResponse{}},
+		"GET /api/v1/audit/storage":       {Summary: "审计日志存储统计", Response: audit.StorageStats{}},
+		"GET /api/v1/audit/exports/:id":   {Summary: "审计导出任务", Response: audit.AuditExport{}},
+		"GET /api/v1/packet-captures/:id": {Summary: "抓包记录", Response: audit.PacketCapture{}},
+
+		// 自定义监控面板
+		"GET /api/v1/panels":          {Summary: "面板列表", Response: openapi.List(panels.Panel{})},
+		"GET /api/v1/panels/:id":      {Summary: "面板详情", Response: panels.Panel{}},
+		"POST /api/v1/panels":         {Summary: "创建面板", Request: panels.Panel{}, Response: panels.Panel{}, Status: http.StatusCreated},
+		"PUT /api/v1/panels/:id":      {Summary: "更新面板", Request: panels.Panel{}, Response: panels.Panel{}},
+		"DELETE /api/v1/panels/:id":   {Summary: "删除面板", Response: deletedResponse{}},
+		"GET /api/v1/panels/:id/data": {Summary: "面板数据", Response: panels.PanelData{}},
+
+		// 运行手册
+		"GET /api/v1/runbooks":          {Summary: "运行手册列表", Response: openapi.List(runbooks.Runbook{})},
+		"GET /api/v1/runbooks/:name":    {Summary: "运行手册详情", Response: runbooks.Runbook{}},
+		"GET /api/v1/runbooks/runs":     {Summary: "执行记录", Response: openapi.List(runbooks.Run{})},
+		"GET /api/v1/runbooks/runs/:id": {Summary: "执行记录详情", Response: runbooks.Run{}},
+
+		// 审批
+		"GET /api/v1/approvals":              {Summary: "审批列表", Response: auth.ListApprovalResponse{}},
+		"POST /api/v1/approvals":             {Summary: "提交审批", Request: auth.CreateApprovalRequest{}, Response: auth.ApprovalRequest{}, Status: http.StatusCreated},
+		"GET /api/v1/approvals/:id":          {Summary: "审批详情", Response: auth.ApprovalRequest{}},
+		"POST /api/v1/approvals/:id/approve": {Summary: "批准并执行", Request: ApprovalActionRequest{}},
+		"POST /api/v1/approvals/:id/reject":  {Summary: "拒绝", Request: ApprovalActionRequest{}},
+
+		// 管理员
+		"GET /api/v1/admin/users":                     {Summary: "用户列表", Response: auth.ListUsersResponse{}},
+		"POST /api/v1/admin/users":                    {Summary: "创建用户", Request: auth.CreateUserRequest{}, Response: auth.User{}, Status: http.StatusCreated},
+		"GET /api/v1/admin/users/:id":                 {Summary: "用户详情", Response: auth.User{}},
+		"PUT /api/v1/admin/users/:id":                 {Summary: "更新用户", Request: auth.UpdateUserRequest{}, Response: auth.User{}},
+		"POST /api/v1/admin/users/:id/reset-password": {Summary: "重置密码", Request: ResetPasswordRequest{}},
+		"POST /api/v1/admin/users/import":             {Summary: "批量导入用户", Response: auth.ImportResult{}},
+		"PUT /api/v1/admin/approval-rules/:id":        {Summary: "更新审批规则", Request: UpdateApprovalRuleRequest{}},
+		"GET /api/v1/admin/approval-rules": {Summary: "审批规则", Response: struct {
+			Items []auth.ApprovalRule `json:"items"`
+		}{}},
+		"POST /api/v1/admin/runbooks":                       {Summary: "创建运行手册", Request: runbooks.Runbook{}, Response: runbooks.Runbook{}, Status: http.StatusCreated},
+		"PUT /api/v1/admin/runbooks/:name":                  {Summary: "更新运行手册", Request: runbooks.Runbook{}, Response: runbooks.Runbook{}},
+		"GET /api/v1/admin/notification-channels":           {Summary: "通知渠道列表（密钥以占位值返回）", Response: openapi.List(notifications.Channel{})},
+		"POST /api/v1/admin/notification-channels":          {Summary: "创建通知渠道", Request: notifications.ChannelInput{}, Response: notifications.Channel{}, Status: http.StatusCreated},
+		"GET /api/v1/admin/notification-channels/:id":       {Summary: "通知渠道详情", Response: notifications.Channel{}},
+		"PUT /api/v1/admin/notification-channels/:id":       {Summary: "更新通知渠道（密钥提交占位值时保留原值）", Request: notifications.ChannelInput{}, Response: notifications.Channel{}},
+		"DELETE /api/v1/admin/notification-channels/:id":    {Summary: "删除通知渠道（被告警路由引用时拒绝）", Response: deletedResponse{}},
+		"POST /api/v1/admin/notification-channels/:id/test": {Summary: "发送测试通知"},
+
+		// 异常检测阈值
+		"GET /api/v1/admin/observation/thresholds":        {Summary: "异常检测阈值规则及默认阈值", Response: thresholdRulesResponse{}},
+		"PUT /api/v1/admin/observation/thresholds":        {Summary: "保存集群/命名空间的异常检测阈值（同一范围覆盖）", Request: thresholdRuleRequest{}, Response: observation.ThresholdRule{}},
+		"DELETE /api/v1/admin/observation/thresholds/:id": {Summary: "删除异常检测阈值规则", Response: deletedResponse{}},
+	}
+
+	for _, res := range namespacedResources {
+		list := openapi.List(res.object)
+		docs["GET "+apiPrefix+"/"+res.path] = openapi.Route{Summary: "全部命名空间的 " + res.path, Query: []string{"labelSelector", "limit", "continue", "view"}, Response: list}
+		docs["GET "+apiPrefix+"/namespaces/:ns/"+res.path] = openapi.Route{Summary: res.path + " 列表", Query: []string{"labelSelector", "limit", "continue", "view"}, Response: list}
+		docs["GET "+apiPrefix+"/namespaces/:ns/"+res.path+"/:name"] = openapi.Route{Summary: res.path + " 详情", Response: res.detail}
+		docs["DELETE "+apiPrefix+"/namespaces/:ns/"+res.path+"/:name"] = openapi.Route{Summary: "删除 " + res.path, Response: deletedResponse{}}
+		if res.writable {
+			docs["POST "+apiPrefix+"/namespaces/:ns/"+res.path] = openapi.Route{Summary: "创建 " + res.path, Request: res.object, Response: res.object, Status: http.StatusCreated}
+			docs["PUT "+apiPrefix+"/namespaces/:ns/"+res.path+"/:name"] = openapi.Route{Summary: "更新 " + res.path, Request: res.object, Response: res.object}
+		}
+	}
+	return docs
+}
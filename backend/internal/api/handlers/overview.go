@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/k8s-dashboard/backend/internal/k8s"
+	"golang.org/x/sync/singleflight"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// overviewCacheTTL 概览结果的缓存时间，多人同时打开仪表盘时避免反复全量 LIST
+const overviewCacheTTL = 10 * time.Second
+
+// overviewCache 按 K8s 客户端缓存概览。启用用户身份客户端时每个用户的客户端不同，
+// 缓存随之按用户隔离，不会把一个用户可见的数据返回给另一个用户
+type overviewCache struct {
+	mu      sync.Mutex
+	entries map[*k8s.Client]overviewCacheEntry
+	group   singleflight.Group
+}
+
+type overviewCacheEntry struct {
+	overview  OverviewResponse
+	expiresAt time.Time
+}
+
+func newOverviewCache() *overviewCache {
+	return &overviewCache{entries: make(map[*k8s.Client]overviewCacheEntry)}
+}
+
+// get 返回未过期的缓存，否则调用 build 计算；同一客户端的并发请求共享一次计算，出错时不缓存
+func (oc *overviewCache) get(client *k8s.Client, build func() (OverviewResponse, error)) (OverviewResponse, error) {
+	now := time.Now()
+	oc.mu.Lock()
+	entry, ok := oc.entries[client]
+	oc.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.overview, nil
+	}
+
+	result, err, _ := oc.group.Do(fmt.Sprintf("%p", client), func() (interface{}, error) {
+		overview, err := build()
+		if err != nil {
+			return nil, err
+		}
+		oc.set(client, overview)
+		return overview, nil
+	})
+	if err != nil {
+		return OverviewResponse{}, err
+	}
+	return result.(OverviewResponse), nil
+}
+
+// set 写入缓存并清理过期条目，避免已淘汰的客户端长期占用内存
+func (oc *overviewCache) set(client *k8s.Client, overview OverviewResponse) {
+	now := time.Now()
+	oc.mu.Lock()
+	defer oc.mu.Unlock()
+	for key, entry := range oc.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(oc.entries, key)
+		}
+	}
+	oc.entries[client] = overviewCacheEntry{overview: overview, expiresAt: now.Add(overviewCacheTTL)}
+}
+
+// countObjects 统计对象数量，搜索索引已同步时直接读取 informer 缓存，否则调用 list
+func countObjects(index *k8s.SearchIndex, kind string, list func() (int, error)) (int, error) {
+	if index != nil {
+		if count, ok := index.Count(kind); ok {
+			return count, nil
+		}
+	}
+	return list()
+}
+
+// nodeCapacityCacheTTL 节点 allocatable 回落数据的缓存时间，节点容量变化缓慢，
+// 避免每次刷新仪表盘都全量 LIST 节点与 Pod
+const nodeCapacityCacheTTL = time.Minute
+
+// nodeCapacity 集群指标回落所需的节点可分配资源与运行中 Pod 数，runningPods 为 -1 表示未知
+type nodeCapacity struct {
+	nodes       []corev1.Node // 仅保留 status.allocatable
+	runningPods int
+	expiresAt   time.Time
+}
+
+// nodeCapacityCache 按 K8s 客户端缓存 nodeCapacity，隔离方式与 overviewCache 相同
+type nodeCapacityCache struct {
+	mu      sync.Mutex
+	entries map[*k8s.Client]nodeCapacity
+	group   singleflight.Group
+}
+
+func newNodeCapacityCache() *nodeCapacityCache {
+	return &nodeCapacityCache{entries: make(map[*k8s.Client]nodeCapacity)}
+}
+
+// get 返回未过期的缓存，否则 LIST 节点与运行中的 Pod；同一客户端的并发请求共享一次计算，出错时不缓存
+func (nc *nodeCapacityCache) get(ctx context.Context, client *k8s.Client) (nodeCapacity, error) {
+	now := time.Now()
+	nc.mu.Lock()
+	entry, ok := nc.entries[client]
+	nc.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry, nil
+	}
+
+	result, err, _ := nc.group.Do(fmt.Sprintf("%p", client), func() (interface{}, error) {
+		nodes, err := client.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		capacity := nodeCapacity{nodes: make([]corev1.Node, len(nodes.Items)), runningPods: -1}
+		for i, node := range nodes.Items {
+			capacity.nodes[i].Status.Allocatable = node.Status.Allocatable
+		}
+		pods, err := client.Clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+			FieldSelector:   "status.phase=Running",
+			ResourceVersion: "0",
+		})
+		if err == nil {
+			capacity.runningPods = len(pods.Items)
+		}
+
+		now := time.Now()
+		capacity.expiresAt = now.Add(nodeCapacityCacheTTL)
+		nc.mu.Lock()
+		for key, entry := range nc.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(nc.entries, key)
+			}
+		}
+		nc.entries[client] = capacity
+		nc.mu.Unlock()
+		return capacity, nil
+	})
+	if err != nil {
+		return nodeCapacity{}, err
+	}
+	return result.(nodeCapacity), nil
+}
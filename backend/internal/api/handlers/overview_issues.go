@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/alertmanager"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// 概览问题类型
+const (
+	issueCrashLoop     = "crashLoopBackOff"
+	issueImagePull     = "imagePull"
+	issuePending       = "pending"
+	issueNodeNotReady  = "nodeNotReady"
+	issueCriticalAlert = "criticalAlert"
+)
+
+const (
+	// pendingIssueThreshold Pending 超过该时长才计入问题，避免正常调度中的 Pod 误报
+	pendingIssueThreshold = 5 * time.Minute
+	// maxIssueSamples 每类问题返回的示例数量
+	maxIssueSamples = 5
+)
+
+// OverviewIssueItem 问题示例，link 为前端详情页路径
+type OverviewIssueItem struct {
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Message   string `json:"message,omitempty"`
+	Link      string `json:"link"`
+}
+
+// OverviewIssue 一类问题的汇总
+type OverviewIssue struct {
+	Type     string              `json:"type"`
+	Title    string              `json:"title"`
+	Severity string              `json:"severity"` // critical, warning
+	Count    int                 `json:"count"`
+	Link     string              `json:"link"` // 前端列表页路径
+	Items    []OverviewIssueItem `json:"items"`
+}
+
+// OverviewIssuesResponse 概览问题列表，healthy 为 true 表示未发现问题
+type OverviewIssuesResponse struct {
+	Healthy     bool            `json:"healthy"`
+	Issues      []OverviewIssue `json:"issues"`
+	Errors      []string        `json:"errors,omitempty"` // 部分数据源不可用时的说明
+	GeneratedAt time.Time       `json:"generatedAt"`
+}
+
+func (i *OverviewIssue) add(item OverviewIssueItem) {
+	i.Count++
+	if len(i.Items) < maxIssueSamples {
+		i.Items = append(i.Items, item)
+	}
+}
+
+// GetOverviewIssues 汇总集群当前问题：CrashLoopBackOff、镜像拉取失败、Pending 超过 5 分钟、
+// NotReady 节点与触发中的 critical 告警，仅返回数量非零的类别，Pod 与告警按命名空间权限过滤
+func (h *Handler) GetOverviewIssues(c *gin.Context) {
+	ctx := requestContext(c)
+	client := h.getK8s(c)
+
+	scope, err := h.getNamespaceAccessScope(c)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	crashLoop := &OverviewIssue{Type: issueCrashLoop, Title: "CrashLoopBackOff", Severity: "critical", Link: "/workloads/pods?status=CrashLoopBackOff"}
+	imagePull := &OverviewIssue{Type: issueImagePull, Title: "镜像拉取失败", Severity: "critical", Link: "/workloads/pods?status=ImagePullBackOff"}
+	pending := &OverviewIssue{Type: issuePending, Title: "Pending 超过 5 分钟", Severity: "warning", Link: "/workloads/pods?status=Pending"}
+	nodeNotReady := &OverviewIssue{Type: issueNodeNotReady, Title: "NotReady 节点", Severity: "critical", Link: "/nodes"}
+	criticalAlert := &OverviewIssue{Type: issueCriticalAlert, Title: "Critical 告警", Severity: "critical", Link: "/alerts?severity=critical"}
+
+	resp := OverviewIssuesResponse{GeneratedAt: time.Now()}
+
+	pods, err := client.Clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	now := time.Now()
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !namespaceAllowed(scope, pod.Namespace) {
+			continue
+		}
+		item := OverviewIssueItem{
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+			Link:      fmt.Sprintf("/workloads/pods/%s/%s", url.PathEscape(pod.Namespace), url.PathEscape(pod.Name)),
+		}
+		if reason, message := podWaitingIssue(pod); reason != "" {
+			item.Message = message
+			if reason == "CrashLoopBackOff" {
+				crashLoop.add(item)
+			} else {
+				imagePull.add(item)
+			}
+			continue
+		}
+		if pod.Status.Phase == corev1.PodPending && now.Sub(pod.CreationTimestamp.Time) > pendingIssueThreshold {
+			item.Message = pendingReason(pod)
+			pending.add(item)
+		}
+	}
+
+	nodes, err := client.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		resp.Errors = append(resp.Errors, "节点: "+err.Error())
+	} else {
+		for i := range nodes.Items {
+			node := &nodes.Items[i]
+			if isNodeReady(node) {
+				continue
+			}
+			nodeNotReady.add(OverviewIssueItem{
+				Name:    node.Name,
+				Message: nodeReadyMessage(node),
+				Link:    "/nodes/" + url.PathEscape(node.Name),
+			})
+		}
+	}
+
+	if alertClient := h.getAlerts(c); alertClient != nil {
+		alerts, err := alertClient.GetAlerts()
+		if err != nil {
+			resp.Errors = append(resp.Errors, "告警: "+err.Error())
+		} else {
+			for _, alert := range alerts {
+				if alert.Status.State != "active" || alert.Severity != alertmanager.SeverityCritical {
+					continue
+				}
+				ns := alert.Labels["namespace"]
+				// 受限用户只能看到带命名空间标签且有权限的告警
+				if !scope.unrestricted && (ns == "" || !namespaceAllowed(scope, ns)) {
+					continue
+				}
+				message := alert.Annotations["summary"]
+				if message == "" {
+					message = alert.Annotations["description"]
+				}
+				criticalAlert.add(OverviewIssueItem{
+					Namespace: ns,
+					Name:      alert.Labels["alertname"],
+					Message:   message,
+					Link:      "/alerts?fingerprint=" + url.QueryEscape(alert.Fingerprint),
+				})
+			}
+		}
+	}
+
+	for _, issue := range []*OverviewIssue{crashLoop, imagePull, pending, nodeNotReady, criticalAlert} {
+		if issue.Count > 0 {
+			resp.Issues = append(resp.Issues, *issue)
+		}
+	}
+	if resp.Issues == nil {
+		resp.Issues = []OverviewIssue{}
+	}
+	resp.Healthy = len(resp.Issues) == 0 && len(resp.Errors) == 0
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// podWaitingIssue 返回容器处于 CrashLoopBackOff 或镜像拉取失败时的原因
+func podWaitingIssue(pod *corev1.Pod) (string, string) {
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, cs := range statuses {
+		if cs.State.Waiting == nil {
+			continue
+		}
+		switch cs.State.Waiting.Reason {
+		case "CrashLoopBackOff", "ImagePullBackOff", "ErrImagePull", "InvalidImageName":
+			return cs.State.Waiting.Reason, fmt.Sprintf("%s: %s", cs.Name, cs.State.Waiting.Reason)
+		}
+	}
+	return "", ""
+}
+
+// pendingReason 优先使用 PodScheduled 条件中的调度失败原因
+func pendingReason(pod *corev1.Pod) string {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse && cond.Message != "" {
+			return cond.Message
+		}
+	}
+	return "Pending"
+}
+
+func nodeReadyMessage(node *corev1.Node) string {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			if cond.Message != "" {
+				return cond.Message
+			}
+			return string(cond.Status)
+		}
+	}
+	return "Ready condition missing"
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
+	"github.com/k8s-dashboard/backend/internal/panels"
+)
+
+// PanelHandler 自定义监控面板处理器
+type PanelHandler struct {
+	h       *Handler
+	service *panels.Service
+}
+
+// NewPanelHandler 创建面板处理器
+func NewPanelHandler(h *Handler, service *panels.Service) *PanelHandler {
+	return &PanelHandler{h: h, service: service}
+}
+
+// panelRequest 创建/更新面板请求
+type panelRequest struct {
+	Name       string             `json:"name" binding:"required"`
+	Query      string             `json:"query" binding:"required"`
+	Range      string             `json:"range"`
+	Step       string             `json:"step"`
+	Unit       string             `json:"unit"`
+	Thresholds []panels.Threshold `json:"thresholds"`
+}
+
+func (r panelRequest) toPanel() *panels.Panel {
+	return &panels.Panel{
+		Name:       r.Name,
+		Query:      r.Query,
+		Range:      r.Range,
+		Step:       r.Step,
+		Unit:       r.Unit,
+		Thresholds: r.Thresholds,
+	}
+}
+
+// writePanelError 将面板服务错误映射为 HTTP 状态码
+func writePanelError(c *gin.Context, err error) {
+	var validationErr *panels.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Message, "field": validationErr.Field})
+	case errors.Is(err, panels.ErrPanelNotFound):
+		writeError(c, http.StatusNotFound, err)
+	case errors.Is(err, panels.ErrMetricsUnavailable):
+		writeError(c, http.StatusServiceUnavailable, err)
+	default:
+		writeError(c, http.StatusInternalServerError, err)
+	}
+}
+
+// panelContext 校验服务与当前用户，返回用户 ID
+func (h *PanelHandler) panelContext(c *gin.Context) (int64, bool) {
+	if h.service == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "面板服务未启用"})
+		return 0, false
+	}
+	user := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未认证"})
+		return 0, false
+	}
+	return user.ID, true
+}
+
+// ListPanels 获取当前用户的面板列表
+func (h *PanelHandler) ListPanels(c *gin.Context) {
+	userID, ok := h.panelContext(c)
+	if !ok {
+		return
+	}
+
+	items, err := h.service.List(userID)
+	if err != nil {
+		writePanelError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "total": len(items)})
+}
+
+// GetPanel 获取面板定义
+func (h *PanelHandler) GetPanel(c *gin.Context) {
+	userID, ok := h.panelContext(c)
+	if !ok {
+		return
+	}
+
+	var id int64
+	if _, err := parsePathInt64(c, "id", &id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的面板ID"})
+		return
+	}
+
+	panel, err := h.service.Get(userID, id)
+	if err != nil {
+		writePanelError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, panel)
+}
+
+// CreatePanel 创建面板
+func (h *PanelHandler) CreatePanel(c *gin.Context) {
+	userID, ok := h.panelContext(c)
+	if !ok {
+		return
+	}
+
+	var req panelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	panel, err := h.service.Create(userID, req.toPanel())
+	if err != nil {
+		writePanelError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, panel)
+}
+
+// UpdatePanel 更新面板
+func (h *PanelHandler) UpdatePanel(c *gin.Context) {
+	userID, ok := h.panelContext(c)
+	if !ok {
+		return
+	}
+
+	var id int64
+	if _, err := parsePathInt64(c, "id", &id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的面板ID"})
+		return
+	}
+
+	var req panelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	panel, err := h.service.Update(userID, id, req.toPanel())
+	if err != nil {
+		writePanelError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, panel)
+}
+
+// DeletePanel 删除面板
+func (h *PanelHandler) DeletePanel(c *gin.Context) {
+	userID, ok := h.panelContext(c)
+	if !ok {
+		return
+	}
+
+	var id int64
+	if _, err := parsePathInt64(c, "id", &id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的面板ID"})
+		return
+	}
+
+	if err := h.service.Delete(userID, id); err != nil {
+		writePanelError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// GetPanelData 执行面板 PromQL 查询并返回时序数据。非管理员的查询限定在其可见命名空间内，
+// 与 /metrics/query 的限制方式一致
+func (h *PanelHandler) GetPanelData(c *gin.Context) {
+	userID, ok := h.panelContext(c)
+	if !ok {
+		return
+	}
+
+	var id int64
+	if _, err := parsePathInt64(c, "id", &id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的面板ID"})
+		return
+	}
+
+	namespaces, ok := h.h.metricsNamespaces(c)
+	if !ok {
+		return
+	}
+
+	data, err := h.service.Query(userID, id, namespaces)
+	if err != nil {
+		writePanelError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// PersistentVolumeClaimDetail PVC 详情，附带绑定的 PV 与扩容相关信息
+type PersistentVolumeClaimDetail struct {
+	*corev1.PersistentVolumeClaim
+	Capacity      string                              `json:"capacity,omitempty"` // 实际容量（status.capacity）
+	Requested     string                              `json:"requested,omitempty"`
+	AccessModes   []corev1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+	BoundVolume   *BoundVolumeInfo                    `json:"boundVolume,omitempty"`
+	Expandable    bool                                `json:"expandable"`    // StorageClass 允许扩容
+	ResizePending bool                                `json:"resizePending"` // 已提交扩容但尚未完成
+	Usage         *metrics.VolumeMetrics              `json:"usage,omitempty"`
+}
+
+// PersistentVolumeClaimItem PVC 列表项，附带卷用量；VictoriaMetrics 未配置、查询失败或卷未挂载时为空
+type PersistentVolumeClaimItem struct {
+	corev1.PersistentVolumeClaim
+	Usage *metrics.VolumeMetrics `json:"usage,omitempty"`
+}
+
+// BoundVolumeInfo PVC 绑定的 PV 摘要
+type BoundVolumeInfo struct {
+	Name          string                               `json:"name"`
+	Capacity      string                               `json:"capacity,omitempty"`
+	AccessModes   []corev1.PersistentVolumeAccessMode  `json:"accessModes,omitempty"`
+	ReclaimPolicy corev1.PersistentVolumeReclaimPolicy `json:"reclaimPolicy,omitempty"`
+	VolumeMode    string                               `json:"volumeMode,omitempty"`
+	Phase         corev1.PersistentVolumePhase         `json:"phase,omitempty"`
+}
+
+// ExpandPVCRequest PVC 扩容请求
+type ExpandPVCRequest struct {
+	Storage string `json:"storage" binding:"required"` // 目标容量，如 20Gi
+}
+
+func (h *Handler) GetPersistentVolumeClaim(c *gin.Context) {
+	ctx := requestContext(c)
+	namespace := c.Param("ns")
+	name := c.Param("name")
+	clientset := h.getK8s(c).Clientset
+	pvc, err := clientset.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		writeError(c, http.StatusNotFound, err)
+		return
+	}
+
+	detail := PersistentVolumeClaimDetail{PersistentVolumeClaim: pvc, AccessModes: pvc.Status.AccessModes}
+	if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+		detail.Capacity = capacity.String()
+		if requested, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok && requested.Cmp(capacity) > 0 {
+			detail.ResizePending = true
+		}
+	}
+	if requested, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		detail.Requested = requested.String()
+	}
+	for _, cond := range pvc.Status.Conditions {
+		if (cond.Type == corev1.PersistentVolumeClaimResizing || cond.Type == corev1.PersistentVolumeClaimFileSystemResizePending) && cond.Status == corev1.ConditionTrue {
+			detail.ResizePending = true
+		}
+	}
+	detail.Expandable, _ = pvcExpandable(ctx, clientset, pvc)
+	detail.Usage = h.pvcVolumeUsage(c, []string{namespace})[namespace+"/"+name]
+
+	if pvc.Spec.VolumeName != "" {
+		if pv, err := clientset.CoreV1().PersistentVolumes().Get(ctx, pvc.Spec.VolumeName, metav1.GetOptions{}); err == nil {
+			info := &BoundVolumeInfo{
+				Name:          pv.Name,
+				AccessModes:   pv.Spec.AccessModes,
+				ReclaimPolicy: pv.Spec.PersistentVolumeReclaimPolicy,
+				Phase:         pv.Status.Phase,
+			}
+			if capacity, ok := pv.Spec.Capacity[corev1.ResourceStorage]; ok {
+				info.Capacity = capacity.String()
+			}
+			if pv.Spec.VolumeMode != nil {
+				info.VolumeMode = string(*pv.Spec.VolumeMode)
+			}
+			detail.BoundVolume = info
+		}
+	}
+	c.JSON(http.StatusOK, detail)
+}
+
+func (h *Handler) CreatePersistentVolumeClaim(c *gin.Context) {
+	ctx := requestContext(c)
+	namespace := c.Param("ns")
+	var pvc corev1.PersistentVolumeClaim
+	if err := c.ShouldBindJSON(&pvc); err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+	if !validateObject(c, &pvc, namespace, "") {
+		return
+	}
+	created, err := h.getK8s(c).Clientset.CoreV1().PersistentVolumeClaims(namespace).Create(ctx, &pvc, metav1.CreateOptions{})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// ExpandPersistentVolumeClaim 扩容 PVC：只允许增大容量，且 StorageClass 需开启 allowVolumeExpansion
+func (h *Handler) ExpandPersistentVolumeClaim(c *gin.Context) {
+	ctx := requestContext(c)
+	namespace := c.Param("ns")
+	name := c.Param("name")
+
+	var req ExpandPVCRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+	target, err := resource.ParseQuantity(req.Storage)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的容量: " + req.Storage})
+		return
+	}
+
+	clientset := h.getK8s(c).Clientset
+	pvcs := clientset.CoreV1().PersistentVolumeClaims(namespace)
+	pvc, err := pvcs.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		writeError(c, http.StatusNotFound, err)
+		return
+	}
+	if pvc.Status.Phase != corev1.ClaimBound {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "只能扩容已绑定的 PVC"})
+		return
+	}
+	if current, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok && target.Cmp(current) <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("目标容量 %s 必须大于当前容量 %s，PVC 不支持缩容", target.String(), current.String())})
+		return
+	}
+	if expandable, err := pvcExpandable(ctx, clientset, pvc); err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	} else if !expandable {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("StorageClass %s 未开启 allowVolumeExpansion", pvcStorageClassName(pvc))})
+		return
+	}
+
+	before := pvc.DeepCopy()
+	patch := []byte(fmt.Sprintf(`{"spec":{"resources":{"requests":{"storage":%q}}}}`, target.String()))
+	updated, err := pvcs.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	recordAuditDiff(c, before, updated)
+	c.JSON(http.StatusOK, updated)
+}
+
+// pvcExpandable 判断 PVC 所用 StorageClass 是否允许扩容；未使用 StorageClass 时返回错误
+func pvcExpandable(ctx context.Context, clientset kubernetes.Interface, pvc *corev1.PersistentVolumeClaim) (bool, error) {
+	className := pvcStorageClassName(pvc)
+	if className == "" {
+		return false, fmt.Errorf("PVC 未使用 StorageClass，无法扩容")
+	}
+	sc, err := clientset.StorageV1().StorageClasses().Get(ctx, className, metav1.GetOptions{})
+	if err != nil {
+		return false, fmt.Errorf("获取 StorageClass %s 失败: %w", className, err)
+	}
+	return sc.AllowVolumeExpansion != nil && *sc.AllowVolumeExpansion, nil
+}
+
+func pvcStorageClassName(pvc *corev1.PersistentVolumeClaim) string {
+	if pvc.Spec.StorageClassName != nil {
+		return *pvc.Spec.StorageClassName
+	}
+	return ""
+}
+
+// GetPVCMetrics 获取可见命名空间内所有 PVC 的卷用量，namespace 参数可进一步限定
+func (h *Handler) GetPVCMetrics(c *gin.Context) {
+	if h.getMetrics(c) == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "metrics client not configured"})
+		return
+	}
+
+	namespaces, ok := h.metricsNamespaces(c)
+	if !ok {
+		return
+	}
+	if ns := c.Query("namespace"); ns != "" {
+		if namespaces != nil && !slices.Contains(namespaces, ns) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "无权访问该命名空间"})
+			return
+		}
+		namespaces = []string{ns}
+	}
+	if namespaces != nil && len(namespaces) == 0 {
+		c.JSON(http.StatusOK, gin.H{"items": []metrics.VolumeMetrics{}, "total": 0})
+		return
+	}
+
+	volumes, err := h.getMetrics(c).WithContext(requestContext(c)).GetVolumeMetrics(namespaces)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": volumes, "total": len(volumes)})
+}
+
+// pvcListItems 为 PVC 列表附加卷用量，只查询列表中出现的命名空间
+func (h *Handler) pvcListItems(c *gin.Context, pvcs []corev1.PersistentVolumeClaim) []PersistentVolumeClaimItem {
+	seen := make(map[string]bool)
+	namespaces := make([]string, 0)
+	for i := range pvcs {
+		if !seen[pvcs[i].Namespace] {
+			seen[pvcs[i].Namespace] = true
+			namespaces = append(namespaces, pvcs[i].Namespace)
+		}
+	}
+	usage := h.pvcVolumeUsage(c, namespaces)
+
+	items := make([]PersistentVolumeClaimItem, 0, len(pvcs))
+	for i := range pvcs {
+		items = append(items, PersistentVolumeClaimItem{
+			PersistentVolumeClaim: pvcs[i],
+			Usage:                 usage[pvcs[i].Namespace+"/"+pvcs[i].Name],
+		})
+	}
+	return items
+}
+
+// pvcVolumeUsage 查询指定命名空间的卷用量，按 namespace/name 索引；指标不可用时返回空表，不影响 PVC 本身的展示
+func (h *Handler) pvcVolumeUsage(c *gin.Context, namespaces []string) map[string]*metrics.VolumeMetrics {
+	usage := make(map[string]*metrics.VolumeMetrics)
+	if h.getMetrics(c) == nil || len(namespaces) == 0 {
+		return usage
+	}
+	volumes, err := h.getMetrics(c).WithContext(requestContext(c)).GetVolumeMetrics(namespaces)
+	if err != nil {
+		log.Printf("Warning: 查询 PVC 卷用量失败: %v", err)
+		return usage
+	}
+	for i := range volumes {
+		usage[volumes[i].Namespace+"/"+volumes[i].PVC] = &volumes[i]
+	}
+	return usage
+}
@@ -0,0 +1,364 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// 单次重新均衡最多重启的工作负载数
+const maxRebalanceWorkloads = 20
+
+// rebalanceSuggestion 可通过滚动重启分散到目标节点的 Deployment
+type rebalanceSuggestion struct {
+	Namespace    string         `json:"namespace"`
+	Name         string         `json:"name"`
+	Replicas     int32          `json:"replicas"`
+	Distribution map[string]int `json:"distribution"` // 节点 -> 运行中的 Pod 数
+	Skew         int            `json:"skew"`         // 单节点最多 Pod 数与目标节点（0）之差
+	CPURequest   string         `json:"cpuRequest,omitempty"`
+	MemRequest   string         `json:"memoryRequest,omitempty"`
+	Fits         bool           `json:"fits"` // 目标节点剩余可分配资源能否容纳一个 Pod
+	Notes        []string       `json:"notes,omitempty"`
+}
+
+type rebalanceTarget struct {
+	Namespace string `json:"namespace" binding:"required"`
+	Name      string `json:"name" binding:"required"`
+}
+
+type rebalanceRequest struct {
+	Deployments []rebalanceTarget `json:"deployments" binding:"required"`
+}
+
+type rebalanceResult struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Status    string `json:"status"` // restarted, skipped, failed
+	Message   string `json:"message,omitempty"`
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// nodeTaintsTolerated 判断 Pod 模板能否容忍节点上影响调度的污点
+func nodeTaintsTolerated(node *corev1.Node, tolerations []corev1.Toleration) bool {
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		tolerated := false
+		for j := range tolerations {
+			if tolerations[j].ToleratesTaint(taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false
+		}
+	}
+	return true
+}
+
+func nodeSelectorMatches(node *corev1.Node, selector map[string]string) bool {
+	for k, v := range selector {
+		if node.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func podTemplateRequests(spec *corev1.PodSpec) (cpu, mem resource.Quantity) {
+	for _, ctr := range spec.Containers {
+		if q, ok := ctr.Resources.Requests[corev1.ResourceCPU]; ok {
+			cpu.Add(q)
+		}
+		if q, ok := ctr.Resources.Requests[corev1.ResourceMemory]; ok {
+			mem.Add(q)
+		}
+	}
+	return cpu, mem
+}
+
+// GetNodeRebalanceSuggestions 节点 uncordon 后 Pod 不会自动迁移，
+// 按 Deployment 的 Pod 分布倾斜程度给出可通过滚动重启分散到该节点的建议
+func (h *Handler) GetNodeRebalanceSuggestions(c *gin.Context) {
+	ctx := requestContext(c)
+	name := c.Param("name")
+	client := h.getK8s(c).Clientset
+
+	scope, err := h.getNamespaceAccessScope(c)
+	if err != nil {
+		writeError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	target, err := client.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		writeError(c, http.StatusNotFound, err)
+		return
+	}
+
+	hints := make([]string, 0)
+	if target.Spec.Unschedulable {
+		hints = append(hints, "节点仍处于 cordon 状态，需先 uncordon 才能接收新 Pod")
+	}
+	if !isNodeReady(target) {
+		hints = append(hints, "节点未就绪")
+	}
+	for _, taint := range target.Spec.Taints {
+		if taint.Effect != corev1.TaintEffectPreferNoSchedule {
+			hints = append(hints, fmt.Sprintf("节点存在污点 %s=%s:%s，仅容忍该污点的工作负载可调度", taint.Key, taint.Value, taint.Effect))
+		}
+	}
+
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	schedulable := make(map[string]bool)
+	for i := range nodes.Items {
+		if !nodes.Items[i].Spec.Unschedulable && isNodeReady(&nodes.Items[i]) {
+			schedulable[nodes.Items[i].Name] = true
+		}
+	}
+	schedulable[target.Name] = true
+
+	namespaces := []string{metav1.NamespaceAll}
+	if !scope.unrestricted {
+		namespaces = scope.allowed
+	}
+
+	// ReplicaSet UID -> Deployment，用于把 Pod 归属到 Deployment
+	rsOwner := make(map[types.UID]*appsv1.Deployment)
+	deployments := make(map[types.UID]*appsv1.Deployment)
+	var pods []corev1.Pod
+	for _, ns := range namespaces {
+		depList, err := client.AppsV1().Deployments(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			writeError(c, http.StatusInternalServerError, err)
+			return
+		}
+		for i := range depList.Items {
+			deployments[depList.Items[i].UID] = &depList.Items[i]
+		}
+		rsList, err := client.AppsV1().ReplicaSets(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			writeError(c, http.StatusInternalServerError, err)
+			return
+		}
+		for _, rs := range rsList.Items {
+			if ref := metav1.GetControllerOf(&rs); ref != nil && ref.Kind == "Deployment" {
+				if dep, ok := deployments[ref.UID]; ok {
+					rsOwner[rs.UID] = dep
+				}
+			}
+		}
+		podList, err := client.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{
+			FieldSelector: "status.phase=Running",
+		})
+		if err != nil {
+			writeError(c, http.StatusInternalServerError, err)
+			return
+		}
+		pods = append(pods, podList.Items...)
+	}
+
+	// 目标节点剩余可分配资源
+	freeCPU := target.Status.Allocatable.Cpu().DeepCopy()
+	freeMem := target.Status.Allocatable.Memory().DeepCopy()
+	distribution := make(map[types.UID]map[string]int)
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Spec.NodeName == target.Name {
+			cpu, mem := podTemplateRequests(&pod.Spec)
+			freeCPU.Sub(cpu)
+			freeMem.Sub(mem)
+		}
+		ref := metav1.GetControllerOf(pod)
+		if ref == nil || ref.Kind != "ReplicaSet" {
+			continue
+		}
+		dep, ok := rsOwner[ref.UID]
+		if !ok || pod.Spec.NodeName == "" {
+			continue
+		}
+		if distribution[dep.UID] == nil {
+			distribution[dep.UID] = make(map[string]int)
+		}
+		distribution[dep.UID][pod.Spec.NodeName]++
+	}
+
+	suggestions := make([]rebalanceSuggestion, 0)
+	for uid, dist := range distribution {
+		dep := deployments[uid]
+		if dep.Spec.Replicas == nil || *dep.Spec.Replicas < 2 || dist[target.Name] > 0 {
+			continue
+		}
+		spec := &dep.Spec.Template.Spec
+		if !nodeSelectorMatches(target, spec.NodeSelector) || !nodeTaintsTolerated(target, spec.Tolerations) {
+			continue
+		}
+
+		maxPerNode := 0
+		for node, count := range dist {
+			if schedulable[node] && count > maxPerNode {
+				maxPerNode = count
+			}
+		}
+		// 目标节点上为 0，倾斜至少为 2 才值得迁移
+		if maxPerNode < 2 {
+			continue
+		}
+
+		cpu, mem := podTemplateRequests(spec)
+		s := rebalanceSuggestion{
+			Namespace:    dep.Namespace,
+			Name:         dep.Name,
+			Replicas:     *dep.Spec.Replicas,
+			Distribution: dist,
+			Skew:         maxPerNode,
+			Fits:         cpu.Cmp(freeCPU) <= 0 && mem.Cmp(freeMem) <= 0,
+		}
+		if !cpu.IsZero() {
+			s.CPURequest = cpu.String()
+		}
+		if !mem.IsZero() {
+			s.MemRequest = mem.String()
+		}
+		if !s.Fits {
+			s.Notes = append(s.Notes, "目标节点剩余可分配资源不足以容纳一个 Pod")
+		}
+		if spec.Affinity != nil && (spec.Affinity.NodeAffinity != nil || spec.Affinity.PodAntiAffinity != nil) {
+			s.Notes = append(s.Notes, "配置了亲和性规则，调度结果以调度器为准")
+		}
+		if dep.Status.AvailableReplicas < *dep.Spec.Replicas {
+			s.Notes = append(s.Notes, "当前并非全部副本可用，重启会被跳过")
+		}
+		suggestions = append(suggestions, s)
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Skew != suggestions[j].Skew {
+			return suggestions[i].Skew > suggestions[j].Skew
+		}
+		if suggestions[i].Namespace != suggestions[j].Namespace {
+			return suggestions[i].Namespace < suggestions[j].Namespace
+		}
+		return suggestions[i].Name < suggestions[j].Name
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"node":        target.Name,
+		"schedulable": !target.Spec.Unschedulable && isNodeReady(target),
+		"hints":       hints,
+		"freeCpu":     freeCPU.String(),
+		"freeMemory":  freeMem.String(),
+		"suggestions": suggestions,
+	})
+}
+
+// RebalanceNode 对选中的 Deployment 执行滚动重启，使新 Pod 有机会调度到刚恢复的节点。
+// 仅在节点可调度时执行，未完全可用的 Deployment 会被跳过以免加剧故障
+func (h *Handler) RebalanceNode(c *gin.Context) {
+	ctx, cancel := operationContext(c)
+	defer cancel()
+	name := c.Param("name")
+	client := h.getK8s(c).Clientset
+
+	var req rebalanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求体"})
+		return
+	}
+	if len(req.Deployments) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "deployments is required"})
+		return
+	}
+	if len(req.Deployments) > maxRebalanceWorkloads {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("单次最多重启 %d 个工作负载", maxRebalanceWorkloads)})
+		return
+	}
+
+	user := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未认证"})
+		return
+	}
+	scope, err := h.getNamespaceAccessScope(c)
+	if err != nil {
+		writeError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	node, err := client.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		writeError(c, http.StatusNotFound, err)
+		return
+	}
+	if node.Spec.Unschedulable || !isNodeReady(node) {
+		c.JSON(http.StatusConflict, gin.H{"error": "节点不可调度，重启不会把 Pod 迁移到该节点"})
+		return
+	}
+
+	results := make([]rebalanceResult, 0, len(req.Deployments))
+	restarted := 0
+	for _, t := range req.Deployments {
+		result := rebalanceResult{Namespace: t.Namespace, Name: t.Name}
+		if !namespaceAllowed(scope, t.Namespace) {
+			result.Status, result.Message = "failed", "无权访问该命名空间"
+			results = append(results, result)
+			continue
+		}
+		// 重启需要目标命名空间的 write 授权，与直接调用 restart 接口一致
+		if ok, reason := bodyNamespaceAllows(c, user.Role, "/api/v1/nodes/:name/rebalance", t.Namespace, t.Name); !ok {
+			result.Status, result.Message = "failed", reason
+			results = append(results, result)
+			continue
+		}
+
+		dep, err := client.AppsV1().Deployments(t.Namespace).Get(ctx, t.Name, metav1.GetOptions{})
+		switch {
+		case err != nil:
+			result.Status, result.Message = "failed", err.Error()
+		case dep.Spec.Replicas != nil && dep.Status.AvailableReplicas < *dep.Spec.Replicas:
+			result.Status, result.Message = "skipped", fmt.Sprintf("仅 %d/%d 个副本可用", dep.Status.AvailableReplicas, *dep.Spec.Replicas)
+		default:
+			patch := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{"kubectl.kubernetes.io/restartedAt":%q}}}}}`,
+				time.Now().Format(time.RFC3339))
+			if _, err := client.AppsV1().Deployments(t.Namespace).Patch(ctx, t.Name, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{}); err != nil {
+				result.Status, result.Message = "failed", err.Error()
+			} else {
+				result.Status = "restarted"
+				restarted++
+			}
+		}
+		results = append(results, result)
+	}
+	middleware.SetAuditDetail(c, fmt.Sprintf("rebalance onto %s: %d/%d restarted", name, restarted, len(req.Deployments)))
+
+	c.JSON(http.StatusOK, gin.H{
+		"node":      name,
+		"restarted": restarted,
+		"results":   results,
+	})
+}
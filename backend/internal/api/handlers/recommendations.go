@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/metrics"
+	"github.com/k8s-dashboard/backend/internal/recommendations"
+)
+
+// RecommendationHandler 容器资源建议处理器
+type RecommendationHandler struct {
+	h       *Handler
+	service *recommendations.Service
+}
+
+// NewRecommendationHandler 创建资源建议处理器
+func NewRecommendationHandler(h *Handler, service *recommendations.Service) *RecommendationHandler {
+	return &RecommendationHandler{h: h, service: service}
+}
+
+// GetResourceRecommendations 对比容器 requests/limits 与窗口内的用量分位数，按工作负载、容器给出建议值。
+// 参数 namespace 限定命名空间，window 覆盖默认统计窗口（如 24h、7d、2w）
+func (rh *RecommendationHandler) GetResourceRecommendations(c *gin.Context) {
+	if rh.service == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "资源建议服务未初始化"})
+		return
+	}
+	metricsClient := rh.h.getMetrics(c)
+	if metricsClient == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "metrics client not configured"})
+		return
+	}
+	window := c.Query("window")
+	if window != "" {
+		if err := metrics.ValidateWindow(window); err != nil {
+			writeError(c, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	namespaces, ok := rh.h.metricsNamespaces(c)
+	if !ok {
+		return
+	}
+	if ns := c.Query("namespace"); ns != "" {
+		if namespaces != nil && !slices.Contains(namespaces, ns) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "无权访问该命名空间"})
+			return
+		}
+		namespaces = []string{ns}
+	}
+	if namespaces != nil && len(namespaces) == 0 {
+		cfg := rh.service.Config()
+		if window == "" {
+			window = cfg.Window
+		}
+		c.JSON(http.StatusOK, recommendations.Report{Window: window, Percentile: cfg.Percentile, Items: []recommendations.ContainerRecommendation{}})
+		return
+	}
+
+	ctx := requestContext(c)
+	pods, err := listPodsInNamespaces(ctx, rh.h.getK8s(c).Clientset, namespaces)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	report, err := rh.service.Recommend(metricsClient.WithContext(ctx), pods, namespaces, window)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// maxOwnerChainDepth 向上解析 ownerReference 的最大层数，防止异常的循环引用
+const maxOwnerChainDepth = 5
+
+// ResourceRef 关联对象引用，Link 为前端详情页路径（没有详情页的类型为空）
+type ResourceRef struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Link      string `json:"link,omitempty"`
+}
+
+// ResourceRelations 详情响应中的上下游关系：controlledBy 为控制器链（由近及远，如 ReplicaSet、Deployment），
+// controls 为直接受其控制的子对象
+type ResourceRelations struct {
+	ControlledBy []ResourceRef `json:"controlledBy"`
+	Controls     []ResourceRef `json:"controls"`
+}
+
+type podDetail struct {
+	*corev1.Pod
+	ResourceRelations
+	// ExtendedRequests 申请的扩展资源（如 nvidia.com/gpu）与大页内存
+	ExtendedRequests map[string]float64 `json:"extendedRequests,omitempty"`
+}
+
+type deploymentDetail struct {
+	*appsv1.Deployment
+	ResourceRelations
+}
+
+type statefulSetDetail struct {
+	*appsv1.StatefulSet
+	ResourceRelations
+}
+
+type daemonSetDetail struct {
+	*appsv1.DaemonSet
+	ResourceRelations
+}
+
+type jobDetail struct {
+	*batchv1.Job
+	ResourceRelations
+}
+
+func newResourceRef(kind, namespace, name string) ResourceRef {
+	return ResourceRef{Kind: kind, Name: name, Namespace: namespace, Link: resourceLink(kind, namespace, name)}
+}
+
+// resolveRelations 解析 obj 的控制器链与受控子对象；查询失败的部分返回已解析到的结果，不影响详情本身
+func resolveRelations(ctx context.Context, clientset kubernetes.Interface, obj metav1.Object, kind string, selector *metav1.LabelSelector) ResourceRelations {
+	return ResourceRelations{
+		ControlledBy: resolveOwnerChain(ctx, clientset, obj.GetNamespace(), obj.GetOwnerReferences()),
+		Controls:     resolveControlledChildren(ctx, clientset, obj, kind, selector),
+	}
+}
+
+// resolveOwnerChain 沿 controller ownerReference 逐级向上解析，遇到不认识的类型或对象不存在时停止
+func resolveOwnerChain(ctx context.Context, clientset kubernetes.Interface, namespace string, owners []metav1.OwnerReference) []ResourceRef {
+	chain := make([]ResourceRef, 0)
+	for depth := 0; depth < maxOwnerChainDepth; depth++ {
+		owner := controllerReference(owners)
+		if owner == nil {
+			break
+		}
+		chain = append(chain, newResourceRef(owner.Kind, namespace, owner.Name))
+
+		var meta metav1.Object
+		var err error
+		switch owner.Kind {
+		case "ReplicaSet":
+			meta, err = clientset.AppsV1().ReplicaSets(namespace).Get(ctx, owner.Name, metav1.GetOptions{})
+		case "Deployment":
+			meta, err = clientset.AppsV1().Deployments(namespace).Get(ctx, owner.Name, metav1.GetOptions{})
+		case "StatefulSet":
+			meta, err = clientset.AppsV1().StatefulSets(namespace).Get(ctx, owner.Name, metav1.GetOptions{})
+		case "DaemonSet":
+			meta, err = clientset.AppsV1().DaemonSets(namespace).Get(ctx, owner.Name, metav1.GetOptions{})
+		case "Job":
+			meta, err = clientset.BatchV1().Jobs(namespace).Get(ctx, owner.Name, metav1.GetOptions{})
+		case "CronJob":
+			meta, err = clientset.BatchV1().CronJobs(namespace).Get(ctx, owner.Name, metav1.GetOptions{})
+		default:
+			return chain
+		}
+		if err != nil {
+			return chain
+		}
+		owners = meta.GetOwnerReferences()
+	}
+	return chain
+}
+
+// controllerReference 优先返回 controller=true 的 ownerReference，没有时取第一个
+func controllerReference(owners []metav1.OwnerReference) *metav1.OwnerReference {
+	for i := range owners {
+		if owners[i].Controller != nil && *owners[i].Controller {
+			return &owners[i]
+		}
+	}
+	if len(owners) > 0 {
+		return &owners[0]
+	}
+	return nil
+}
+
+// resolveControlledChildren 列出直接受 owner 控制的子对象：Deployment 的 ReplicaSet、CronJob 的 Job，
+// 其余控制器为 Pod。先按选择器缩小范围，再用 ownerReference 的 UID 精确匹配
+func resolveControlledChildren(ctx context.Context, clientset kubernetes.Interface, owner metav1.Object, kind string, selector *metav1.LabelSelector) []ResourceRef {
+	children := make([]ResourceRef, 0)
+	namespace := owner.GetNamespace()
+
+	opts := metav1.ListOptions{}
+	if selector != nil {
+		sel, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil {
+			return children
+		}
+		opts.LabelSelector = sel.String()
+	}
+
+	switch kind {
+	case "Deployment":
+		list, err := clientset.AppsV1().ReplicaSets(namespace).List(ctx, opts)
+		if err != nil {
+			return children
+		}
+		for i := range list.Items {
+			if metav1.IsControlledBy(&list.Items[i], owner) {
+				children = append(children, newResourceRef("ReplicaSet", namespace, list.Items[i].Name))
+			}
+		}
+	case "CronJob":
+		list, err := clientset.BatchV1().Jobs(namespace).List(ctx, opts)
+		if err != nil {
+			return children
+		}
+		for i := range list.Items {
+			if metav1.IsControlledBy(&list.Items[i], owner) {
+				children = append(children, newResourceRef("Job", namespace, list.Items[i].Name))
+			}
+		}
+	case "ReplicaSet", "StatefulSet", "DaemonSet", "Job":
+		list, err := clientset.CoreV1().Pods(namespace).List(ctx, opts)
+		if err != nil {
+			return children
+		}
+		for i := range list.Items {
+			if metav1.IsControlledBy(&list.Items[i], owner) {
+				children = append(children, newResourceRef("Pod", namespace, list.Items[i].Name))
+			}
+		}
+	}
+	return children
+}
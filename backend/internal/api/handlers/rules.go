@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
+	"github.com/k8s-dashboard/backend/internal/k8s"
+	"github.com/k8s-dashboard/backend/internal/metrics"
+)
+
+// ruleGroupsResponse 规则组列表及健康状况汇总
+type ruleGroupsResponse struct {
+	Groups  []metrics.RuleGroup `json:"groups"`
+	Total   int                 `json:"total"`
+	Summary ruleHealthSummary   `json:"summary"`
+}
+
+// ruleHealthSummary 按健康状态与告警状态统计的规则数量
+type ruleHealthSummary struct {
+	Alerting  int `json:"alerting"`
+	Recording int `json:"recording"`
+	Unhealthy int `json:"unhealthy"` // health=err
+	Firing    int `json:"firing"`
+	Pending   int `json:"pending"`
+}
+
+// updateRuleResourceRequest 编辑规则 CR 的请求体
+type updateRuleResourceRequest struct {
+	Groups []interface{} `json:"groups" binding:"required"`
+	// ResourceVersion 读取时的版本，CR 已被他人修改时返回 409
+	ResourceVersion string `json:"resourceVersion"`
+}
+
+// GetRuleGroups 获取 Prometheus / vmalert 当前加载的告警与记录规则，含健康状态与最近一次评估信息。
+// type=alert|record 过滤规则类型，health=ok|err|unknown 只保留对应健康状态的规则；
+// 受限用户只能看到可见命名空间内的告警实例
+func (h *Handler) GetRuleGroups(c *gin.Context) {
+	metricsClient := h.getMetrics(c)
+	if metricsClient == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "metrics client not configured"})
+		return
+	}
+	ruleType := c.Query("type")
+	if ruleType != "" && ruleType != metrics.RuleTypeAlert && ruleType != metrics.RuleTypeRecord {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type 仅支持 alert 或 record"})
+		return
+	}
+	health := c.Query("health")
+	scope, err := h.getNamespaceAccessScope(c)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	groups, err := metricsClient.WithContext(requestContext(c)).GetRules(ruleType)
+	if err != nil {
+		writeError(c, http.StatusBadGateway, err)
+		return
+	}
+
+	resp := ruleGroupsResponse{Groups: make([]metrics.RuleGroup, 0, len(groups))}
+	for _, group := range groups {
+		rules := make([]metrics.Rule, 0, len(group.Rules))
+		for _, rule := range group.Rules {
+			if health != "" && rule.Health != health {
+				continue
+			}
+			if !scope.unrestricted {
+				rule.Alerts = visibleRuleAlerts(scope, rule.Alerts)
+			}
+			rules = append(rules, rule)
+
+			if rule.Type == "recording" {
+				resp.Summary.Recording++
+			} else {
+				resp.Summary.Alerting++
+			}
+			if rule.Health == "err" {
+				resp.Summary.Unhealthy++
+			}
+			switch rule.State {
+			case "firing":
+				resp.Summary.Firing++
+			case "pending":
+				resp.Summary.Pending++
+			}
+		}
+		if len(rules) == 0 && health != "" {
+			continue
+		}
+		group.Rules = rules
+		resp.Groups = append(resp.Groups, group)
+	}
+	resp.Total = len(resp.Groups)
+	c.JSON(http.StatusOK, resp)
+}
+
+// visibleRuleAlerts 只保留命名空间可见的告警实例，不带 namespace 标签的集群级告警保留
+func visibleRuleAlerts(scope namespaceAccessScope, alerts []metrics.RuleAlert) []metrics.RuleAlert {
+	visible := make([]metrics.RuleAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		if ns := alert.Labels["namespace"]; ns == "" || namespaceAllowed(scope, ns) {
+			visible = append(visible, alert)
+		}
+	}
+	return visible
+}
+
+// writeRuleResourceError 将规则 CR 操作错误映射为 HTTP 状态码
+func writeRuleResourceError(c *gin.Context, err error) {
+	var validationErr *k8s.RuleValidationError
+	if errors.As(err, &validationErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Message, "field": validationErr.Field})
+		return
+	}
+	writeError(c, http.StatusInternalServerError, err)
+}
+
+// ruleResourceKind 校验路径中的规则资源类型
+func ruleResourceKind(c *gin.Context) (string, bool) {
+	resource := c.Param("resource")
+	if _, ok := k8s.RuleKinds[resource]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("不支持的规则资源: %s，仅支持 prometheusrules、vmrules", resource)})
+		return "", false
+	}
+	return resource, true
+}
+
+// ListRuleResources 列出 PrometheusRule 与 VMRule，namespace 参数只列出该命名空间；
+// 集群未安装对应 CRD 时跳过，受限用户只能看到可见命名空间中的规则
+func (h *Handler) ListRuleResources(c *gin.Context) {
+	scope, err := h.getNamespaceAccessScope(c)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	namespace := c.Query("namespace")
+	if namespace != "" && !namespaceAllowed(scope, namespace) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "无权访问该命名空间"})
+		return
+	}
+
+	items, err := k8s.ListRuleResources(requestContext(c), h.getK8s(c).DynamicClient, namespace)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if !scope.unrestricted {
+		visible := items[:0]
+		for _, item := range items {
+			if namespaceAllowed(scope, item.Namespace) {
+				visible = append(visible, item)
+			}
+		}
+		items = visible
+	}
+	c.JSON(http.StatusOK, ListResponse{Items: items, Total: len(items)})
+}
+
+// GetRuleResource 获取规则 CR 的规则组定义
+func (h *Handler) GetRuleResource(c *gin.Context) {
+	resource, ok := ruleResourceKind(c)
+	if !ok {
+		return
+	}
+	scope, err := h.getNamespaceAccessScope(c)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if !namespaceAllowed(scope, c.Param("ns")) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "无权访问该命名空间"})
+		return
+	}
+
+	item, err := k8s.GetRuleResource(requestContext(c), h.getK8s(c).DynamicClient, resource, c.Param("ns"), c.Param("name"))
+	if err != nil {
+		writeRuleResourceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+// UpdateRuleResource 替换规则 CR 的 spec.groups（admin），提交前校验表达式语法、for 与 interval，
+// 由 Prometheus / VictoriaMetrics Operator 负责重新加载
+func (h *Handler) UpdateRuleResource(c *gin.Context) {
+	resource, ok := ruleResourceKind(c)
+	if !ok {
+		return
+	}
+	var req updateRuleResourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	namespace, name := c.Param("ns"), c.Param("name")
+	item, err := k8s.UpdateRuleGroups(requestContext(c), h.getK8s(c).DynamicClient, resource, namespace, name, req.ResourceVersion, req.Groups)
+	if err != nil {
+		writeRuleResourceError(c, err)
+		return
+	}
+	middleware.SetAuditDetail(c, fmt.Sprintf("%s %s/%s groups=%d", item.Kind, namespace, name, len(item.Groups)))
+	c.JSON(http.StatusOK, item)
+}
@@ -0,0 +1,420 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
+	"github.com/k8s-dashboard/backend/internal/k8s"
+	"github.com/k8s-dashboard/backend/internal/runbooks"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RunbookHandler 运行手册（参数化 Job 模板）处理器
+type RunbookHandler struct {
+	h       *Handler
+	service *runbooks.Service
+}
+
+// NewRunbookHandler 创建运行手册处理器
+func NewRunbookHandler(h *Handler, service *runbooks.Service) *RunbookHandler {
+	return &RunbookHandler{h: h, service: service}
+}
+
+// runbookRequest 创建/更新运行手册请求
+type runbookRequest struct {
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	Manifest    string               `json:"manifest" binding:"required"`
+	Parameters  []runbooks.Parameter `json:"parameters"`
+	MinRole     string               `json:"minRole"`
+	Namespaces  []string             `json:"namespaces"`
+}
+
+// runRunbookRequest 执行运行手册请求
+type runRunbookRequest struct {
+	Namespace  string            `json:"namespace" binding:"required"`
+	Parameters map[string]string `json:"parameters"`
+}
+
+// writeRunbookError 将运行手册服务错误映射为 HTTP 状态码
+func writeRunbookError(c *gin.Context, err error) {
+	var validationErr *runbooks.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Message, "field": validationErr.Field})
+	case errors.Is(err, runbooks.ErrRunbookNotFound), errors.Is(err, runbooks.ErrRunNotFound):
+		writeError(c, http.StatusNotFound, err)
+	default:
+		writeError(c, http.StatusInternalServerError, err)
+	}
+}
+
+func (rh *RunbookHandler) available(c *gin.Context) bool {
+	if rh.service == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "运行手册服务未启用"})
+		return false
+	}
+	return true
+}
+
+// ListRunbooks 列出运行手册，canRun 表示当前用户的角色是否满足执行要求
+func (rh *RunbookHandler) ListRunbooks(c *gin.Context) {
+	if !rh.available(c) {
+		return
+	}
+	user := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未认证"})
+		return
+	}
+
+	items, err := rh.service.List()
+	if err != nil {
+		writeRunbookError(c, err)
+		return
+	}
+	type runbookItem struct {
+		runbooks.Runbook
+		CanRun bool `json:"canRun"`
+	}
+	result := make([]runbookItem, 0, len(items))
+	for _, rb := range items {
+		result = append(result, runbookItem{Runbook: rb, CanRun: middleware.RoleAtLeast(user.Role, rb.MinRole)})
+	}
+	c.JSON(http.StatusOK, gin.H{"items": result, "total": len(result)})
+}
+
+// GetRunbook 获取运行手册定义
+func (rh *RunbookHandler) GetRunbook(c *gin.Context) {
+	if !rh.available(c) {
+		return
+	}
+	rb, err := rh.service.Get(c.Param("name"))
+	if err != nil {
+		writeRunbookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rb)
+}
+
+// CreateRunbook 注册运行手册（admin）
+func (rh *RunbookHandler) CreateRunbook(c *gin.Context) {
+	if !rh.available(c) {
+		return
+	}
+	var req runbookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	rb := req.toRunbook()
+	if user := middleware.GetCurrentUser(c); user != nil {
+		rb.CreatedBy = user.Username
+	}
+	created, err := rh.service.Create(rb)
+	if err != nil {
+		writeRunbookError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// UpdateRunbook 更新运行手册（admin）
+func (rh *RunbookHandler) UpdateRunbook(c *gin.Context) {
+	if !rh.available(c) {
+		return
+	}
+	var req runbookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	rb := req.toRunbook()
+	rb.Name = c.Param("name")
+	updated, err := rh.service.Update(rb)
+	if err != nil {
+		writeRunbookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeleteRunbook 删除运行手册（admin），执行历史保留
+func (rh *RunbookHandler) DeleteRunbook(c *gin.Context) {
+	if !rh.available(c) {
+		return
+	}
+	if err := rh.service.Delete(c.Param("name")); err != nil {
+		writeRunbookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+func (r runbookRequest) toRunbook() *runbooks.Runbook {
+	return &runbooks.Runbook{
+		Name:        r.Name,
+		Description: r.Description,
+		Manifest:    r.Manifest,
+		Parameters:  r.Parameters,
+		MinRole:     r.MinRole,
+		Namespaces:  r.Namespaces,
+	}
+}
+
+// RunRunbook 按参数渲染并创建 Job，记录执行历史
+func (rh *RunbookHandler) RunRunbook(c *gin.Context) {
+	if !rh.available(c) {
+		return
+	}
+	user := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未认证"})
+		return
+	}
+
+	var req runRunbookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	rb, err := rh.service.Get(c.Param("name"))
+	if err != nil {
+		writeRunbookError(c, err)
+		return
+	}
+	if err := runbooks.CanRun(rb, user.Role, req.Namespace); err != nil {
+		writeError(c, http.StatusForbidden, err)
+		return
+	}
+	scope, err := rh.h.getNamespaceAccessScope(c)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if !namespaceAllowed(scope, req.Namespace) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "无权访问该命名空间"})
+		return
+	}
+	// 执行会在目标命名空间创建 Job，需要与直接创建资源相同的 write 授权
+	if ok, reason := bodyNamespaceAllows(c, user.Role, "/api/v1/runbooks/:name/run", req.Namespace, rb.Name); !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": reason})
+		return
+	}
+
+	values, err := runbooks.ResolveValues(rb, req.Parameters)
+	if err != nil {
+		writeRunbookError(c, err)
+		return
+	}
+	job, err := runbooks.Render(rb, values, req.Namespace)
+	if err != nil {
+		writeRunbookError(c, err)
+		return
+	}
+
+	run := &runbooks.Run{
+		RunbookID:   rb.ID,
+		RunbookName: rb.Name,
+		UserID:      user.ID,
+		Username:    user.Username,
+		Cluster:     middleware.GetClusterName(c),
+		Namespace:   req.Namespace,
+		Parameters:  values,
+		Status:      runbooks.RunStatusRunning,
+	}
+
+	created, createErr := rh.h.getK8s(c).Clientset.BatchV1().Jobs(req.Namespace).Create(requestContext(c), job, metav1.CreateOptions{})
+	if createErr != nil {
+		run.Status = runbooks.RunStatusError
+		run.Message = createErr.Error()
+	} else {
+		run.JobName = created.Name
+	}
+	if err := rh.service.RecordRun(run); err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	middleware.SetAuditDetail(c, fmt.Sprintf("runbook=%s job=%s/%s", rb.Name, req.Namespace, run.JobName))
+
+	if createErr != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": createErr.Error(), "run": run})
+		return
+	}
+	c.JSON(http.StatusCreated, run)
+}
+
+// runAccessible 非 admin 只能查看自己发起的执行
+func runAccessible(c *gin.Context, run *runbooks.Run) bool {
+	user := middleware.GetCurrentUser(c)
+	return user != nil && (user.Role == "admin" || user.ID == run.UserID)
+}
+
+// runClient 执行所在集群的客户端（执行记录可能来自非当前集群）
+func (rh *RunbookHandler) runClient(c *gin.Context, run *runbooks.Run) (*k8s.Client, error) {
+	if rh.h.clusters != nil && run.Cluster != "" {
+		return rh.h.clusters.GetClient(run.Cluster)
+	}
+	return rh.h.getK8s(c), nil
+}
+
+// syncRun 根据 Job 最新状态刷新执行记录
+func (rh *RunbookHandler) syncRun(ctx context.Context, c *gin.Context, run *runbooks.Run) {
+	if run.Status != runbooks.RunStatusRunning || run.JobName == "" {
+		return
+	}
+	client, err := rh.runClient(c, run)
+	if err != nil {
+		return
+	}
+	job, err := client.Clientset.BatchV1().Jobs(run.Namespace).Get(ctx, run.JobName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		rh.service.MarkRunMissing(run)
+		return
+	}
+	if err == nil {
+		rh.service.SyncRunStatus(run, job)
+	}
+}
+
+// ListRunbookRuns 执行历史，可按 runbook 过滤；非 admin 仅返回自己的执行。
+// 运行中的状态可能滞后，以 GetRunbookRun 同步后的结果为准
+func (rh *RunbookHandler) ListRunbookRuns(c *gin.Context) {
+	if !rh.available(c) {
+		return
+	}
+	user := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未认证"})
+		return
+	}
+
+	params := runbooks.RunListParams{RunbookName: c.Query("runbook")}
+	if v := c.Query("limit"); v != "" {
+		params.Limit, _ = strconv.Atoi(v)
+	}
+	if user.Role != "admin" {
+		params.UserID = user.ID
+	}
+
+	items, err := rh.service.ListRuns(params)
+	if err != nil {
+		writeRunbookError(c, err)
+		return
+	}
+	// 列表直接返回已保存的状态，不逐条查询 Job；状态在查看执行详情时同步
+	c.JSON(http.StatusOK, gin.H{"items": items, "total": len(items)})
+}
+
+// GetRunbookRun 获取执行详情并同步 Job 状态
+func (rh *RunbookHandler) GetRunbookRun(c *gin.Context) {
+	if !rh.available(c) {
+		return
+	}
+	var id int64
+	if _, err := parsePathInt64(c, "id", &id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的执行ID"})
+		return
+	}
+	run, err := rh.service.GetRun(id)
+	if err != nil {
+		writeRunbookError(c, err)
+		return
+	}
+	if !runAccessible(c, run) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "无权查看该执行记录"})
+		return
+	}
+	rh.syncRun(requestContext(c), c, run)
+	c.JSON(http.StatusOK, run)
+}
+
+// GetRunbookRunLogs 输出执行 Job 最新 Pod 的日志，follow=true 时持续推送直到容器退出
+func (rh *RunbookHandler) GetRunbookRunLogs(c *gin.Context) {
+	if !rh.available(c) {
+		return
+	}
+	var id int64
+	if _, err := parsePathInt64(c, "id", &id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的执行ID"})
+		return
+	}
+	run, err := rh.service.GetRun(id)
+	if err != nil {
+		writeRunbookError(c, err)
+		return
+	}
+	if !runAccessible(c, run) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "无权查看该执行记录"})
+		return
+	}
+	if run.JobName == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "该执行未创建 Job"})
+		return
+	}
+
+	client, err := rh.runClient(c, run)
+	if err != nil {
+		writeError(c, http.StatusServiceUnavailable, err)
+		return
+	}
+	ctx := c.Request.Context()
+	pods, err := client.Clientset.CoreV1().Pods(run.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "job-name=" + run.JobName,
+	})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if len(pods.Items) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job 尚未创建 Pod"})
+		return
+	}
+	// 取最近创建的 Pod（失败重试时为最后一次尝试）
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[j].CreationTimestamp.Before(&pods.Items[i].CreationTimestamp)
+	})
+	pod := pods.Items[0]
+
+	follow := c.Query("follow") == "true"
+	opts := &corev1.PodLogOptions{Container: c.Query("container"), Follow: follow}
+	stream, err := client.Clientset.CoreV1().Pods(run.Namespace).GetLogs(pod.Name, opts).Stream(ctx)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	defer stream.Close()
+
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.Header("X-Log-Pod", pod.Name)
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	reader := bufio.NewReader(stream)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			if _, writeErr := c.Writer.Write(line); writeErr != nil {
+				return
+			}
+			if follow {
+				c.Writer.Flush()
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
+	"github.com/k8s-dashboard/backend/internal/k8s"
+)
+
+const (
+	searchDefaultLimit = 50
+	searchMaxLimit     = 200
+	// searchSyncWait 索引首次建立时等待同步的时长，超时后返回已同步类型的结果并在 pending 中列出其余类型
+	searchSyncWait = 3 * time.Second
+)
+
+// resourceLinks 各资源类型在前端的页面路径，没有详情路由的资源链接到列表页
+var resourceLinks = map[string]string{
+	"Pod":                   "/workloads/pods/%ns/%name",
+	"Deployment":            "/workloads/deployments/%ns/%name",
+	"StatefulSet":           "/workloads/statefulsets/%ns/%name",
+	"DaemonSet":             "/workloads/daemonsets/%ns/%name",
+	"Job":                   "/workloads/jobs/%ns/%name",
+	"CronJob":               "/workloads/cronjobs/%ns/%name",
+	"Service":               "/network/services/%ns/%name",
+	"Ingress":               "/network/ingresses/%ns/%name",
+	"ConfigMap":             "/config/configmaps/%ns/%name",
+	"Secret":                "/config/secrets/%ns/%name",
+	"PersistentVolumeClaim": "/config/persistentvolumeclaims",
+	"ServiceAccount":        "/rbac/serviceaccounts",
+	"Namespace":             "/namespaces/%name",
+	"Node":                  "/nodes/%name",
+	"PersistentVolume":      "/config/persistentvolumes",
+	"StorageClass":          "/config/storageclasses",
+}
+
+// Search 全局搜索：在集群元数据索引中按名称、标签、注解查找资源。
+// 参数 q 以空白分隔多个关键字（需全部命中），kinds 逗号分隔限定类型，namespace 限定命名空间，limit 默认 50、最大 200。
+// 索引使用集群凭据建立，结果按当前用户的命名空间授权过滤
+func (h *Handler) Search(c *gin.Context) {
+	terms := strings.Fields(c.Query("q"))
+	if len(terms) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q 不能为空"})
+		return
+	}
+
+	limit := searchDefaultLimit
+	if raw := c.Query("limit"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit 必须为正整数"})
+			return
+		}
+		limit = min(v, searchMaxLimit)
+	}
+
+	var kinds map[string]bool
+	if raw := c.Query("kinds"); raw != "" {
+		kinds = make(map[string]bool)
+		for _, name := range strings.Split(raw, ",") {
+			kind, ok := searchKindByName(strings.TrimSpace(name))
+			if !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "不支持搜索的资源类型: " + name})
+				return
+			}
+			kinds[kind] = true
+		}
+	}
+
+	scope, err := h.getNamespaceAccessScope(c)
+	if err != nil {
+		writeError(c, http.StatusUnauthorized, err)
+		return
+	}
+	namespace := c.Query("namespace")
+	if namespace != "" && !namespaceAllowed(scope, namespace) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "无权访问该命名空间"})
+		return
+	}
+
+	// 索引按集群共享，使用集群自身的客户端而非按用户身份包装的客户端
+	client := middleware.GetClusterClient(c)
+	if client == nil {
+		client = h.k8s
+	}
+	if client == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Kubernetes 客户端未配置"})
+		return
+	}
+	index, err := client.SearchIndex()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "创建搜索索引失败: " + err.Error()})
+		return
+	}
+	ctx, cancel := context.WithTimeout(requestContext(c), searchSyncWait)
+	index.WaitForSync(ctx)
+	cancel()
+
+	query := k8s.SearchQuery{Terms: terms, Kinds: kinds, Namespace: namespace, Limit: limit}
+	if !scope.unrestricted {
+		query.Allow = func(kind k8s.SearchKind, ns, name string) bool {
+			switch {
+			case kind.Namespaced:
+				return namespaceAllowed(scope, ns)
+			case kind.Kind == "Namespace":
+				return namespaceAllowed(scope, name)
+			}
+			return true
+		}
+	}
+
+	result := index.Search(query)
+	for i := range result.Hits {
+		result.Hits[i].Link = resourceLink(result.Hits[i].Kind, result.Hits[i].Namespace, result.Hits[i].Name)
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// searchKindByName 不区分大小写地解析 kinds 参数，同时接受资源复数名（如 pods）
+func searchKindByName(name string) (string, bool) {
+	for _, kind := range k8s.SearchKinds {
+		if strings.EqualFold(kind.Kind, name) || strings.EqualFold(kind.Resource.Resource, name) {
+			return kind.Kind, true
+		}
+	}
+	return "", false
+}
+
+// resourceLink 返回对象在前端的页面路径，未知类型返回空
+func resourceLink(kind, namespace, name string) string {
+	pattern, ok := resourceLinks[kind]
+	if !ok {
+		return ""
+	}
+	return strings.NewReplacer("%ns", namespace, "%name", name).Replace(pattern)
+}
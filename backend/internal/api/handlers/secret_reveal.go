@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
+	"github.com/k8s-dashboard/backend/internal/auth"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// secretRedactedValue 脱敏后 Secret 各键的占位值
+const secretRedactedValue = "REDACTED"
+
+// secretRevealApprovalTTL 查看明文的审批批准后的有效期，过期后需重新申请
+const secretRevealApprovalTTL = time.Hour
+
+// RevealSecret 返回 Secret 的明文数据，可通过 key 参数只查看单个键。
+// 需要 operator 角色（受限用户需命名空间 write 授权），每次查看都会记录审计日志；
+// 命中 reveal 审批规则时，首次请求提交审批并返回 202，批准后一小时内可查看一次
+func (h *Handler) RevealSecret(c *gin.Context) {
+	ctx := requestContext(c)
+	namespace := c.Param("ns")
+	name := c.Param("name")
+	key := c.Query("key")
+
+	scope, err := h.getNamespaceAccessScope(c)
+	if err != nil {
+		writeError(c, http.StatusUnauthorized, err)
+		return
+	}
+	if !namespaceAllowed(scope, namespace) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "无权访问该命名空间"})
+		return
+	}
+
+	secret, err := h.getK8s(c).Clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	data := secret.Data
+	if key != "" {
+		value, ok := secret.Data[key]
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Secret 中不存在键 " + key})
+			return
+		}
+		data = map[string][]byte{key: value}
+	}
+
+	if user := middleware.GetCurrentUser(c); h.auth != nil && user != nil {
+		req := &auth.CreateApprovalRequest{
+			Action:       "reveal",
+			Resource:     "secrets",
+			ResourceName: name,
+			Namespace:    namespace,
+			Reason:       c.GetHeader("X-Approval-Reason"),
+			Cluster:      middleware.GetClusterName(c),
+		}
+		needs, err := h.auth.NeedsApproval(user.Role, req.Action, req.Resource, namespace)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "检查审批规则失败: " + err.Error()})
+			return
+		}
+		if needs {
+			approval, err := h.auth.ConsumeApproval(user.ID, req, time.Now().Add(-secretRevealApprovalTTL), "已查看明文: "+strings.Join(secretKeys(data), ","))
+			if err != nil {
+				writeError(c, http.StatusInternalServerError, err)
+				return
+			}
+			if approval == nil {
+				h.requestSecretRevealApproval(c, user, req)
+				return
+			}
+		}
+	}
+
+	middleware.SetAuditDetail(c, "keys="+strings.Join(secretKeys(data), ","))
+	c.JSON(http.StatusOK, gin.H{
+		"name":      secret.Name,
+		"namespace": secret.Namespace,
+		"type":      secret.Type,
+		"data":      data,
+	})
+}
+
+// requestSecretRevealApproval 提交查看明文的审批请求；已有待处理的请求时直接返回该请求
+func (h *Handler) requestSecretRevealApproval(c *gin.Context, user *auth.User, req *auth.CreateApprovalRequest) {
+	approval, err := h.auth.FindPendingApproval(user.ID, req)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if approval == nil {
+		approval, err = h.auth.CreateApproval(user.ID, req)
+		if err != nil {
+			writeError(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	middleware.SetAuditDetail(c, "approvalRequired")
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":          "查看 Secret 明文需要审批，已提交审批请求",
+		"approvalRequired": true,
+		"approval":         approval,
+	})
+}
+
+func secretKeys(data map[string][]byte) []string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// restoreRedactedSecretData 将提交内容中仍为脱敏占位值的键恢复为当前值，
+// 避免编辑脱敏后的 Secret 时把 REDACTED 写回集群
+func restoreRedactedSecretData(secret, current *corev1.Secret) {
+	if current == nil {
+		return
+	}
+	for key, value := range secret.Data {
+		if !bytes.Equal(value, []byte(secretRedactedValue)) {
+			continue
+		}
+		if original, ok := current.Data[key]; ok {
+			secret.Data[key] = original
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ServicePortsRequest 修改 Service 端口的请求：ports 中的端口按名称（无名称时按 port/protocol）
+// 匹配已有端口并替换，未匹配的追加；remove 中的端口按名称或 "port/protocol" 删除
+type ServicePortsRequest struct {
+	Ports  []corev1.ServicePort `json:"ports"`
+	Remove []string             `json:"remove"`
+}
+
+// UpdateServicePorts 增加、修改或删除 Service 端口，无需手动编辑 YAML
+func (h *Handler) UpdateServicePorts(c *gin.Context) {
+	ctx := requestContext(c)
+	namespace := c.Param("ns")
+	name := c.Param("name")
+
+	var req ServicePortsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+	if len(req.Ports) == 0 && len(req.Remove) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ports 与 remove 不能同时为空"})
+		return
+	}
+
+	services := h.getK8s(c).Clientset.CoreV1().Services(namespace)
+	svc, err := services.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		writeError(c, http.StatusNotFound, err)
+		return
+	}
+	before := svc.DeepCopy()
+
+	ports, err := mergeServicePorts(svc.Spec.Ports, req.Ports, req.Remove)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+	svc.Spec.Ports = ports
+
+	updated, err := services.Update(ctx, svc, metav1.UpdateOptions{})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	recordAuditDiff(c, before, updated)
+	c.JSON(http.StatusOK, updated)
+}
+
+// mergeServicePorts 合并端口变更。替换已有端口时未指定的 nodePort 沿用原值，避免 NodePort 被重新分配
+func mergeServicePorts(existing, updates []corev1.ServicePort, remove []string) ([]corev1.ServicePort, error) {
+	ports := make([]corev1.ServicePort, 0, len(existing)+len(updates))
+	for _, port := range existing {
+		removed := false
+		for _, key := range remove {
+			if (port.Name != "" && port.Name == key) || servicePortKey(port) == strings.ToUpper(key) {
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			ports = append(ports, port)
+		}
+	}
+
+	for _, update := range updates {
+		if update.Port < 1 || update.Port > 65535 {
+			return nil, fmt.Errorf("端口 %d 超出范围 1-65535", update.Port)
+		}
+		if update.Protocol == "" {
+			update.Protocol = corev1.ProtocolTCP
+		}
+		index := -1
+		for i, port := range ports {
+			if (update.Name != "" && port.Name == update.Name) || (update.Name == "" && servicePortKey(port) == servicePortKey(update)) {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			ports = append(ports, update)
+			continue
+		}
+		if update.NodePort == 0 {
+			update.NodePort = ports[index].NodePort
+		}
+		ports[index] = update
+	}
+	return ports, nil
+}
+
+func servicePortKey(port corev1.ServicePort) string {
+	protocol := port.Protocol
+	if protocol == "" {
+		protocol = corev1.ProtocolTCP
+	}
+	return strconv.Itoa(int(port.Port)) + "/" + string(protocol)
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// defaultStorageClassAnnotation 标记默认 StorageClass 的注解
+	defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+	// betaDefaultStorageClassAnnotation 旧版本集群使用的 beta 注解，切换默认时一并清除
+	betaDefaultStorageClassAnnotation = "storageclass.beta.kubernetes.io/is-default-class"
+)
+
+func (h *Handler) CreateStorageClass(c *gin.Context) {
+	ctx := requestContext(c)
+	var sc storagev1.StorageClass
+	if err := c.ShouldBindJSON(&sc); err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+	if !validateObject(c, &sc, "", "") {
+		return
+	}
+	created, err := h.getK8s(c).Clientset.StorageV1().StorageClasses().Create(ctx, &sc, metav1.CreateOptions{})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *Handler) DeleteStorageClass(c *gin.Context) {
+	ctx := requestContext(c)
+	name := c.Param("name")
+	err := h.getK8s(c).Clientset.StorageV1().StorageClasses().Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// SetDefaultStorageClass 将指定 StorageClass 设为默认，并清除其他 StorageClass 的默认注解。
+// 先标记新的默认类再清除旧的，过程中集群不会出现没有默认类的窗口（多个默认类时 Kubernetes 取最新创建的）
+func (h *Handler) SetDefaultStorageClass(c *gin.Context) {
+	ctx := requestContext(c)
+	name := c.Param("name")
+	classes := h.getK8s(c).Clientset.StorageV1().StorageClasses()
+
+	list, err := classes.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	found := false
+	for i := range list.Items {
+		if list.Items[i].Name == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("StorageClass %s 不存在", name)})
+		return
+	}
+
+	setPatch := []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:"true"}}}`, defaultStorageClassAnnotation))
+	if _, err := classes.Patch(ctx, name, types.MergePatchType, setPatch, metav1.PatchOptions{}); err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	unsetPatch := []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:null,%q:null}}}`, defaultStorageClassAnnotation, betaDefaultStorageClassAnnotation))
+	unset := make([]string, 0)
+	failed := make([]string, 0)
+	for i := range list.Items {
+		sc := &list.Items[i]
+		if sc.Name == name || !isDefaultStorageClass(sc) {
+			continue
+		}
+		if _, err := classes.Patch(ctx, sc.Name, types.MergePatchType, unsetPatch, metav1.PatchOptions{}); err != nil {
+			failed = append(failed, sc.Name+": "+err.Error())
+			continue
+		}
+		unset = append(unset, sc.Name)
+	}
+
+	if len(failed) > 0 {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "已设为默认，但以下 StorageClass 的默认标记未能清除",
+			"default": name,
+			"unset":   unset,
+			"failed":  failed,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "default updated", "default": name, "unset": unset})
+}
+
+func isDefaultStorageClass(sc *storagev1.StorageClass) bool {
+	return sc.Annotations[defaultStorageClassAnnotation] == "true" || sc.Annotations[betaDefaultStorageClassAnnotation] == "true"
+}
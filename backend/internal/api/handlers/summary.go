@@ -0,0 +1,415 @@
+package handlers
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/metrics"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// listViewSummary 列表接口 ?view=summary：返回表格视图所需的精简字段，而不是完整对象
+const listViewSummary = "summary"
+
+// ObjectSummary 精简视图的公共字段，未单独定义精简结构的资源只返回这些字段
+type ObjectSummary struct {
+	Name              string      `json:"name"`
+	Namespace         string      `json:"namespace,omitempty"`
+	CreationTimestamp metav1.Time `json:"creationTimestamp"`
+	// Age 按服务端时间计算的存在时长，格式与 kubectl 一致（如 3d4h）
+	Age string `json:"age"`
+}
+
+// PodSummary Pod 精简视图
+type PodSummary struct {
+	ObjectSummary
+	// Status 与 kubectl get pods 的 STATUS 列一致，如 Running、CrashLoopBackOff、Init:0/1、Terminating
+	Status   string   `json:"status"`
+	Ready    string   `json:"ready"`
+	Restarts int32    `json:"restarts"`
+	Node     string   `json:"node,omitempty"`
+	PodIP    string   `json:"podIP,omitempty"`
+	Images   []string `json:"images"`
+}
+
+// WorkloadSummary Deployment、StatefulSet、DaemonSet 精简视图，DaemonSet 的 desired 为应调度的节点数
+type WorkloadSummary struct {
+	ObjectSummary
+	Desired   int32    `json:"desired"`
+	Ready     int32    `json:"ready"`
+	Updated   int32    `json:"updated"`
+	Available int32    `json:"available"`
+	Images    []string `json:"images"`
+}
+
+// JobSummary Job 精简视图
+type JobSummary struct {
+	ObjectSummary
+	Status      string   `json:"status"`
+	Completions string   `json:"completions"`
+	Duration    string   `json:"duration,omitempty"`
+	Images      []string `json:"images"`
+}
+
+// CronJobSummary CronJob 精简视图
+type CronJobSummary struct {
+	ObjectSummary
+	Schedule         string       `json:"schedule"`
+	Suspend          bool         `json:"suspend"`
+	Active           int          `json:"active"`
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	Images           []string     `json:"images"`
+}
+
+// ServiceSummary Service 精简视图，端口格式为 80/TCP 或 80:30080/TCP
+type ServiceSummary struct {
+	ObjectSummary
+	Type        corev1.ServiceType `json:"type"`
+	ClusterIP   string             `json:"clusterIP,omitempty"`
+	ExternalIPs []string           `json:"externalIPs,omitempty"`
+	Ports       []string           `json:"ports"`
+}
+
+// NodeSummary 节点精简视图
+type NodeSummary struct {
+	ObjectSummary
+	Status     string   `json:"status"`
+	Roles      []string `json:"roles"`
+	Version    string   `json:"version"`
+	InternalIP string   `json:"internalIP,omitempty"`
+}
+
+// PersistentVolumeClaimSummary PVC 精简视图
+type PersistentVolumeClaimSummary struct {
+	ObjectSummary
+	Status       corev1.PersistentVolumeClaimPhase   `json:"status"`
+	Volume       string                              `json:"volume,omitempty"`
+	Capacity     string                              `json:"capacity,omitempty"`
+	AccessModes  []corev1.PersistentVolumeAccessMode `json:"accessModes"`
+	StorageClass string                              `json:"storageClass,omitempty"`
+	Usage        *metrics.VolumeMetrics              `json:"usage,omitempty"`
+}
+
+// listView 按 ?view= 返回列表响应，summary 时把完整对象转换为精简结构，其它取值保持完整对象
+func listView(c *gin.Context, resp ListResponse) ListResponse {
+	if c.Query("view") == listViewSummary {
+		resp.Items = summarizeItems(resp.Items, time.Now())
+	}
+	return resp
+}
+
+// summarizeItems 将对象切片转换为精简视图；不是 Kubernetes 对象的切片原样返回
+func summarizeItems(items interface{}, now time.Time) interface{} {
+	switch list := items.(type) {
+	case []corev1.Pod:
+		return summarizeEach(list, now, podSummary)
+	case []appsv1.Deployment:
+		return summarizeEach(list, now, deploymentSummary)
+	case []appsv1.StatefulSet:
+		return summarizeEach(list, now, statefulSetSummary)
+	case []appsv1.DaemonSet:
+		return summarizeEach(list, now, daemonSetSummary)
+	case []batchv1.Job:
+		return summarizeEach(list, now, jobSummary)
+	case []batchv1.CronJob:
+		return summarizeEach(list, now, cronJobSummary)
+	case []corev1.Service:
+		return summarizeEach(list, now, serviceSummary)
+	case []corev1.Node:
+		return summarizeEach(list, now, nodeSummary)
+	case []PersistentVolumeClaimItem:
+		return summarizeEach(list, now, pvcSummary)
+	}
+
+	value := reflect.ValueOf(items)
+	if value.Kind() != reflect.Slice {
+		return items
+	}
+	summaries := make([]ObjectSummary, 0, value.Len())
+	for i := 0; i < value.Len(); i++ {
+		obj, err := meta.Accessor(value.Index(i).Addr().Interface())
+		if err != nil {
+			return items
+		}
+		summaries = append(summaries, objectSummary(obj, now))
+	}
+	return summaries
+}
+
+func summarizeEach[T any, S any](items []T, now time.Time, summarize func(*T, time.Time) S) []S {
+	summaries := make([]S, 0, len(items))
+	for i := range items {
+		summaries = append(summaries, summarize(&items[i], now))
+	}
+	return summaries
+}
+
+func objectSummary(obj metav1.Object, now time.Time) ObjectSummary {
+	created := obj.GetCreationTimestamp()
+	summary := ObjectSummary{Name: obj.GetName(), Namespace: obj.GetNamespace(), CreationTimestamp: created}
+	if !created.IsZero() {
+		summary.Age = duration.HumanDuration(now.Sub(created.Time))
+	}
+	return summary
+}
+
+func containerImages(spec *corev1.PodSpec) []string {
+	images := make([]string, 0, len(spec.Containers))
+	for _, container := range spec.Containers {
+		images = append(images, container.Image)
+	}
+	return images
+}
+
+func podSummary(pod *corev1.Pod, now time.Time) PodSummary {
+	summary := PodSummary{
+		ObjectSummary: objectSummary(pod, now),
+		Status:        podDisplayStatus(pod),
+		Node:          pod.Spec.NodeName,
+		PodIP:         pod.Status.PodIP,
+		Images:        containerImages(&pod.Spec),
+	}
+	ready := 0
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Ready {
+			ready++
+		}
+		summary.Restarts += status.RestartCount
+	}
+	summary.Ready = fmt.Sprintf("%d/%d", ready, len(pod.Spec.Containers))
+	return summary
+}
+
+// podDisplayStatus 按 kubectl 的规则计算 Pod 状态：优先显示初始化进度与容器等待/终止原因，删除中显示 Terminating
+func podDisplayStatus(pod *corev1.Pod) string {
+	reason := string(pod.Status.Phase)
+	if pod.Status.Reason != "" {
+		reason = pod.Status.Reason
+	}
+
+	initializing := false
+	for i, status := range pod.Status.InitContainerStatuses {
+		switch {
+		case status.State.Terminated != nil && status.State.Terminated.ExitCode == 0:
+			continue
+		case i < len(pod.Spec.InitContainers) && pod.Spec.InitContainers[i].RestartPolicy != nil &&
+			*pod.Spec.InitContainers[i].RestartPolicy == corev1.ContainerRestartPolicyAlways &&
+			status.Started != nil && *status.Started:
+			// 已启动的边车容器（restartPolicy: Always）视为初始化完成
+			continue
+		case status.State.Terminated != nil:
+			if status.State.Terminated.Reason != "" {
+				reason = "Init:" + status.State.Terminated.Reason
+			} else {
+				reason = fmt.Sprintf("Init:ExitCode:%d", status.State.Terminated.ExitCode)
+			}
+		case status.State.Waiting != nil && status.State.Waiting.Reason != "" && status.State.Waiting.Reason != "PodInitializing":
+			reason = "Init:" + status.State.Waiting.Reason
+		default:
+			reason = fmt.Sprintf("Init:%d/%d", i, len(pod.Spec.InitContainers))
+		}
+		initializing = true
+		break
+	}
+
+	if !initializing {
+		running := false
+		for i := len(pod.Status.ContainerStatuses) - 1; i >= 0; i-- {
+			status := pod.Status.ContainerStatuses[i]
+			switch {
+			case status.State.Waiting != nil && status.State.Waiting.Reason != "":
+				reason = status.State.Waiting.Reason
+			case status.State.Terminated != nil && status.State.Terminated.Reason != "":
+				reason = status.State.Terminated.Reason
+			case status.State.Terminated != nil:
+				reason = fmt.Sprintf("ExitCode:%d", status.State.Terminated.ExitCode)
+			case status.Ready && status.State.Running != nil:
+				running = true
+			}
+		}
+		if reason == "Completed" && running {
+			reason = string(corev1.PodRunning)
+		}
+	}
+
+	if pod.DeletionTimestamp != nil {
+		if pod.Status.Reason == "NodeLost" {
+			return string(corev1.PodUnknown)
+		}
+		return "Terminating"
+	}
+	return reason
+}
+
+func deploymentSummary(deploy *appsv1.Deployment, now time.Time) WorkloadSummary {
+	desired := int32(1)
+	if deploy.Spec.Replicas != nil {
+		desired = *deploy.Spec.Replicas
+	}
+	return WorkloadSummary{
+		ObjectSummary: objectSummary(deploy, now),
+		Desired:       desired,
+		Ready:         deploy.Status.ReadyReplicas,
+		Updated:       deploy.Status.UpdatedReplicas,
+		Available:     deploy.Status.AvailableReplicas,
+		Images:        containerImages(&deploy.Spec.Template.Spec),
+	}
+}
+
+func statefulSetSummary(sts *appsv1.StatefulSet, now time.Time) WorkloadSummary {
+	desired := int32(1)
+	if sts.Spec.Replicas != nil {
+		desired = *sts.Spec.Replicas
+	}
+	return WorkloadSummary{
+		ObjectSummary: objectSummary(sts, now),
+		Desired:       desired,
+		Ready:         sts.Status.ReadyReplicas,
+		Updated:       sts.Status.UpdatedReplicas,
+		Available:     sts.Status.AvailableReplicas,
+		Images:        containerImages(&sts.Spec.Template.Spec),
+	}
+}
+
+func daemonSetSummary(ds *appsv1.DaemonSet, now time.Time) WorkloadSummary {
+	return WorkloadSummary{
+		ObjectSummary: objectSummary(ds, now),
+		Desired:       ds.Status.DesiredNumberScheduled,
+		Ready:         ds.Status.NumberReady,
+		Updated:       ds.Status.UpdatedNumberScheduled,
+		Available:     ds.Status.NumberAvailable,
+		Images:        containerImages(&ds.Spec.Template.Spec),
+	}
+}
+
+func jobSummary(job *batchv1.Job, now time.Time) JobSummary {
+	completions := int32(1)
+	if job.Spec.Completions != nil {
+		completions = *job.Spec.Completions
+	}
+	summary := JobSummary{
+		ObjectSummary: objectSummary(job, now),
+		Status:        "Running",
+		Completions:   fmt.Sprintf("%d/%d", job.Status.Succeeded, completions),
+		Images:        containerImages(&job.Spec.Template.Spec),
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			summary.Status = "Complete"
+		case batchv1.JobFailed:
+			summary.Status = "Failed"
+		case batchv1.JobSuspended:
+			summary.Status = "Suspended"
+		}
+	}
+	if job.Status.StartTime != nil {
+		end := now
+		if job.Status.CompletionTime != nil {
+			end = job.Status.CompletionTime.Time
+		}
+		summary.Duration = duration.HumanDuration(end.Sub(job.Status.StartTime.Time))
+	}
+	return summary
+}
+
+func cronJobSummary(cronJob *batchv1.CronJob, now time.Time) CronJobSummary {
+	return CronJobSummary{
+		ObjectSummary:    objectSummary(cronJob, now),
+		Schedule:         cronJob.Spec.Schedule,
+		Suspend:          cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend,
+		Active:           len(cronJob.Status.Active),
+		LastScheduleTime: cronJob.Status.LastScheduleTime,
+		Images:           containerImages(&cronJob.Spec.JobTemplate.Spec.Template.Spec),
+	}
+}
+
+func serviceSummary(svc *corev1.Service, now time.Time) ServiceSummary {
+	summary := ServiceSummary{
+		ObjectSummary: objectSummary(svc, now),
+		Type:          svc.Spec.Type,
+		ClusterIP:     svc.Spec.ClusterIP,
+		ExternalIPs:   svc.Spec.ExternalIPs,
+		Ports:         make([]string, 0, len(svc.Spec.Ports)),
+	}
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			summary.ExternalIPs = append(summary.ExternalIPs, ingress.IP)
+		} else if ingress.Hostname != "" {
+			summary.ExternalIPs = append(summary.ExternalIPs, ingress.Hostname)
+		}
+	}
+	for _, port := range svc.Spec.Ports {
+		if port.NodePort != 0 {
+			summary.Ports = append(summary.Ports, fmt.Sprintf("%d:%d/%s", port.Port, port.NodePort, port.Protocol))
+		} else {
+			summary.Ports = append(summary.Ports, fmt.Sprintf("%d/%s", port.Port, port.Protocol))
+		}
+	}
+	return summary
+}
+
+func nodeSummary(node *corev1.Node, now time.Time) NodeSummary {
+	summary := NodeSummary{
+		ObjectSummary: objectSummary(node, now),
+		Status:        "NotReady",
+		Roles:         make([]string, 0),
+		Version:       node.Status.NodeInfo.KubeletVersion,
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			switch condition.Status {
+			case corev1.ConditionTrue:
+				summary.Status = "Ready"
+			case corev1.ConditionUnknown:
+				summary.Status = "Unknown"
+			}
+		}
+	}
+	if node.Spec.Unschedulable {
+		summary.Status += ",SchedulingDisabled"
+	}
+	for label := range node.Labels {
+		if role, ok := strings.CutPrefix(label, "node-role.kubernetes.io/"); ok && role != "" {
+			summary.Roles = append(summary.Roles, role)
+		}
+	}
+	sort.Strings(summary.Roles)
+	for _, address := range node.Status.Addresses {
+		if address.Type == corev1.NodeInternalIP {
+			summary.InternalIP = address.Address
+			break
+		}
+	}
+	return summary
+}
+
+func pvcSummary(item *PersistentVolumeClaimItem, now time.Time) PersistentVolumeClaimSummary {
+	pvc := &item.PersistentVolumeClaim
+	summary := PersistentVolumeClaimSummary{
+		ObjectSummary: objectSummary(pvc, now),
+		Status:        pvc.Status.Phase,
+		Volume:        pvc.Spec.VolumeName,
+		AccessModes:   pvc.Spec.AccessModes,
+		Usage:         item.Usage,
+	}
+	if pvc.Spec.StorageClassName != nil {
+		summary.StorageClass = *pvc.Spec.StorageClassName
+	}
+	if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+		summary.Capacity = capacity.String()
+	}
+	return summary
+}
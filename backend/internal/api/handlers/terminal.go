@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
+	"github.com/k8s-dashboard/backend/internal/audit"
+)
+
+// startTerminalRecording 为 exec 会话创建录制记录，录制未启用或失败时返回 nil
+func (h *Handler) startTerminalRecording(c *gin.Context, namespace, pod, container, command string) (*audit.TerminalRecorder, int64) {
+	if h.audit == nil {
+		return nil, 0
+	}
+	recorder := h.audit.NewTerminalRecorder()
+	if recorder == nil {
+		return nil, 0
+	}
+
+	session := &audit.TerminalSession{
+		User:      "anonymous",
+		Cluster:   middleware.GetClusterName(c),
+		Namespace: namespace,
+		Pod:       pod,
+		Container: container,
+		Command:   command,
+	}
+	if ticket := middleware.GetWSTicket(c); ticket != nil {
+		session.User = ticket.Username
+		if ticket.Cluster != "" {
+			session.Cluster = ticket.Cluster
+		}
+	} else if user := middleware.GetCurrentUser(c); user != nil {
+		session.User = user.Username
+	}
+	if session.Cluster == "" {
+		session.Cluster = "default"
+	}
+
+	if err := h.audit.StartTerminalSession(session); err != nil {
+		log.Printf("创建终端会话录制失败: %v", err)
+		return nil, 0
+	}
+	return recorder, session.ID
+}
+
+// finishTerminalRecording 保存录制内容
+func (h *Handler) finishTerminalRecording(recorder *audit.TerminalRecorder, sessionID int64) {
+	if recorder == nil {
+		return
+	}
+	events, bytes, truncated := recorder.Finish()
+	if err := h.audit.FinishTerminalSession(sessionID, events, bytes, truncated); err != nil {
+		log.Printf("保存终端会话录制失败: %v", err)
+	}
+}
+
+// ListTerminalSessions 查询终端会话录制列表
+func (h *Handler) ListTerminalSessions(c *gin.Context) {
+	if h.audit == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "审计日志功能未启用"})
+		return
+	}
+
+	params := audit.TerminalSessionListParams{
+		User:      c.Query("user"),
+		Namespace: c.Query("namespace"),
+		Pod:       c.Query("pod"),
+	}
+	params.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	params.PageSize, _ = strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+
+	items, total, err := h.audit.ListTerminalSessions(params)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items": items,
+		"total": total,
+	})
+}
+
+// GetTerminalSessionReplay 获取终端会话录制内容用于回放
+func (h *Handler) GetTerminalSessionReplay(c *gin.Context) {
+	if h.audit == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "审计日志功能未启用"})
+		return
+	}
+
+	var id int64
+	if _, err := parsePathInt64(c, "id", &id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的会话ID"})
+		return
+	}
+
+	session, err := h.audit.GetTerminalSession(id)
+	if err != nil {
+		if errors.Is(err, audit.ErrTerminalSessionNotFound) {
+			writeError(c, http.StatusNotFound, err)
+			return
+		}
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/metrics"
+)
+
+// maxTopK 资源排行最多返回的条数
+const maxTopK = 100
+
+// GetTopConsumers 返回 CPU 或内存占用最多的 k 个 Pod、命名空间或节点（resource=cpu|memory，scope=pod|namespace|node，k 默认 10），
+// 供“资源大户”组件使用，无需拉取全部 Pod 指标。受限用户的 Pod 与命名空间排行只统计可见命名空间
+func (h *Handler) GetTopConsumers(c *gin.Context) {
+	if h.getMetrics(c) == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "metrics client not configured"})
+		return
+	}
+
+	resource := c.DefaultQuery("resource", "cpu")
+	scope := c.DefaultQuery("scope", "pod")
+	if !metrics.ValidTopQuery(scope, resource) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "resource 必须为 cpu 或 memory，scope 必须为 pod、namespace 或 node"})
+		return
+	}
+	k, err := strconv.Atoi(c.DefaultQuery("k", "10"))
+	if err != nil || k < 1 || k > maxTopK {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "k 必须在 1-100 之间"})
+		return
+	}
+
+	var namespaces []string
+	if scope != "node" {
+		var ok bool
+		if namespaces, ok = h.metricsNamespaces(c); !ok {
+			return
+		}
+		if namespaces != nil && len(namespaces) == 0 {
+			c.JSON(http.StatusOK, gin.H{"resource": resource, "scope": scope, "items": []metrics.TopConsumer{}})
+			return
+		}
+	}
+
+	items, err := h.getMetrics(c).WithContext(requestContext(c)).GetTopConsumers(scope, resource, k, namespaces)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"resource": resource, "scope": scope, "items": items})
+}
@@ -0,0 +1,349 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// 拓扑边类型
+const (
+	TopologyEdgeOwns    = "owns"    // ownerReference：控制器 -> 被控制对象
+	TopologyEdgeRoutes  = "routes"  // Ingress -> Service
+	TopologyEdgeSelects = "selects" // Service -> Pod（标签选择器）
+	TopologyEdgeMounts  = "mounts"  // Pod -> PVC
+	TopologyEdgeUses    = "uses"    // Pod -> ConfigMap/Secret（环境变量、卷、镜像拉取凭据）
+)
+
+// TopologyNode 拓扑图中的一个对象，ID 为 Kind/Name
+type TopologyNode struct {
+	ID      string `json:"id"`
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	Status  string `json:"status,omitempty"`
+	Missing bool   `json:"missing,omitempty"` // 被引用但不存在的对象
+}
+
+// TopologyEdge 拓扑图中的一条有向边
+type TopologyEdge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Type   string `json:"type"`
+}
+
+// TopologyGraph 命名空间拓扑
+type TopologyGraph struct {
+	Namespace string         `json:"namespace"`
+	Nodes     []TopologyNode `json:"nodes"`
+	Edges     []TopologyEdge `json:"edges"`
+}
+
+// topologyBuilder 按加入顺序收集节点，并对边去重
+type topologyBuilder struct {
+	graph TopologyGraph
+	nodes map[string]int
+	edges map[TopologyEdge]bool
+}
+
+func newTopologyBuilder(namespace string) *topologyBuilder {
+	return &topologyBuilder{
+		graph: TopologyGraph{Namespace: namespace, Nodes: make([]TopologyNode, 0), Edges: make([]TopologyEdge, 0)},
+		nodes: make(map[string]int),
+		edges: make(map[TopologyEdge]bool),
+	}
+}
+
+func topologyID(kind, name string) string {
+	return kind + "/" + name
+}
+
+func (b *topologyBuilder) addNode(kind, name, status string) string {
+	id := topologyID(kind, name)
+	if _, exists := b.nodes[id]; !exists {
+		b.nodes[id] = len(b.graph.Nodes)
+		b.graph.Nodes = append(b.graph.Nodes, TopologyNode{ID: id, Kind: kind, Name: name, Status: status})
+	}
+	return id
+}
+
+func (b *topologyBuilder) hasNode(kind, name string) bool {
+	_, exists := b.nodes[topologyID(kind, name)]
+	return exists
+}
+
+// addReference 添加 source 到被引用对象的边，被引用对象未被列出时标记为 missing
+func (b *topologyBuilder) addReference(source, kind, name, edgeType string) {
+	if !b.hasNode(kind, name) {
+		id := b.addNode(kind, name, "")
+		b.graph.Nodes[b.nodes[id]].Missing = true
+	}
+	b.addEdge(source, topologyID(kind, name), edgeType)
+}
+
+func (b *topologyBuilder) addEdge(source, target, edgeType string) {
+	edge := TopologyEdge{Source: source, Target: target, Type: edgeType}
+	if !b.edges[edge] {
+		b.edges[edge] = true
+		b.graph.Edges = append(b.graph.Edges, edge)
+	}
+}
+
+// addOwners 为 ownerReferences 中已在图中的控制器添加 owns 边
+func (b *topologyBuilder) addOwners(child string, owners []metav1.OwnerReference) {
+	for _, owner := range owners {
+		if b.hasNode(owner.Kind, owner.Name) {
+			b.addEdge(topologyID(owner.Kind, owner.Name), child, TopologyEdgeOwns)
+		}
+	}
+}
+
+// GetNamespaceTopology 返回命名空间内对象的关系图：Ingress→Service→Pod→PVC/ConfigMap/Secret，
+// 以及 Deployment→ReplicaSet→Pod、CronJob→Job→Pod 等 ownerReference 关系。
+// 默认省略副本数为 0 的历史 ReplicaSet，all=true 时全部返回
+func (h *Handler) GetNamespaceTopology(c *gin.Context) {
+	namespace := c.Param("ns")
+	scope, err := h.getNamespaceAccessScope(c)
+	if err != nil {
+		writeError(c, http.StatusUnauthorized, err)
+		return
+	}
+	if !namespaceAllowed(scope, namespace) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "无权访问该命名空间"})
+		return
+	}
+
+	graph, err := buildNamespaceTopology(requestContext(c), h.getK8s(c).Clientset, namespace, c.Query("all") == "true")
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, graph)
+}
+
+func buildNamespaceTopology(ctx context.Context, clientset kubernetes.Interface, namespace string, includeInactive bool) (*TopologyGraph, error) {
+	opts := metav1.ListOptions{}
+	ingresses, err := clientset.NetworkingV1().Ingresses(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	services, err := clientset.CoreV1().Services(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	cronJobs, err := clientset.BatchV1().CronJobs(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := clientset.BatchV1().Jobs(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	deployments, err := clientset.AppsV1().Deployments(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	replicaSets, err := clientset.AppsV1().ReplicaSets(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	statefulSets, err := clientset.AppsV1().StatefulSets(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	daemonSets, err := clientset.AppsV1().DaemonSets(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	pvcs, err := clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	configMaps, err := clientset.CoreV1().ConfigMaps(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	secrets, err := clientset.CoreV1().Secrets(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	b := newTopologyBuilder(namespace)
+
+	// 先加入控制器，子对象加入时才能找到 owner
+	for _, cj := range cronJobs.Items {
+		status := "active"
+		if cj.Spec.Suspend != nil && *cj.Spec.Suspend {
+			status = "suspended"
+		}
+		b.addNode("CronJob", cj.Name, status)
+	}
+	for _, job := range jobs.Items {
+		status := "running"
+		if reason := finishedJobReason(&job); reason != "" {
+			status = "finished"
+		}
+		b.addOwners(b.addNode("Job", job.Name, status), job.OwnerReferences)
+	}
+	for _, dep := range deployments.Items {
+		b.addNode("Deployment", dep.Name, fmt.Sprintf("%d/%d", dep.Status.ReadyReplicas, replicasOrOne(dep.Spec.Replicas)))
+	}
+	for _, rs := range replicaSets.Items {
+		if !includeInactive && rs.Status.Replicas == 0 && rs.Spec.Replicas != nil && *rs.Spec.Replicas == 0 {
+			continue
+		}
+		b.addOwners(b.addNode("ReplicaSet", rs.Name, fmt.Sprintf("%d/%d", rs.Status.ReadyReplicas, replicasOrOne(rs.Spec.Replicas))), rs.OwnerReferences)
+	}
+	for _, sts := range statefulSets.Items {
+		b.addNode("StatefulSet", sts.Name, fmt.Sprintf("%d/%d", sts.Status.ReadyReplicas, replicasOrOne(sts.Spec.Replicas)))
+	}
+	for _, ds := range daemonSets.Items {
+		b.addNode("DaemonSet", ds.Name, fmt.Sprintf("%d/%d", ds.Status.NumberReady, ds.Status.DesiredNumberScheduled))
+	}
+
+	// 被引用对象先登记为存在，引用不到的才标记 missing
+	for _, pvc := range pvcs.Items {
+		b.addNode("PersistentVolumeClaim", pvc.Name, string(pvc.Status.Phase))
+	}
+	existingConfigMaps := make(map[string]bool, len(configMaps.Items))
+	for _, cm := range configMaps.Items {
+		existingConfigMaps[cm.Name] = true
+	}
+	existingSecrets := make(map[string]bool, len(secrets.Items))
+	for _, secret := range secrets.Items {
+		existingSecrets[secret.Name] = true
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		podID := b.addNode("Pod", pod.Name, string(pod.Status.Phase))
+		b.addOwners(podID, pod.OwnerReferences)
+
+		claims, cms, secretNames := podSpecDependencies(&pod.Spec)
+		for _, claim := range claims {
+			b.addReference(podID, "PersistentVolumeClaim", claim, TopologyEdgeMounts)
+		}
+		for _, name := range cms {
+			if existingConfigMaps[name] {
+				b.addNode("ConfigMap", name, "")
+			}
+			b.addReference(podID, "ConfigMap", name, TopologyEdgeUses)
+		}
+		for _, name := range secretNames {
+			if existingSecrets[name] {
+				b.addNode("Secret", name, "")
+			}
+			b.addReference(podID, "Secret", name, TopologyEdgeUses)
+		}
+	}
+
+	for _, svc := range services.Items {
+		svcID := b.addNode("Service", svc.Name, string(svc.Spec.Type))
+		if len(svc.Spec.Selector) == 0 {
+			continue
+		}
+		selector := labels.SelectorFromSet(svc.Spec.Selector)
+		for _, pod := range pods.Items {
+			if selector.Matches(labels.Set(pod.Labels)) {
+				b.addEdge(svcID, topologyID("Pod", pod.Name), TopologyEdgeSelects)
+			}
+		}
+	}
+
+	for _, ing := range ingresses.Items {
+		ingID := b.addNode("Ingress", ing.Name, "")
+		if backend := ing.Spec.DefaultBackend; backend != nil && backend.Service != nil {
+			b.addReference(ingID, "Service", backend.Service.Name, TopologyEdgeRoutes)
+		}
+		for _, rule := range ing.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			for _, path := range rule.HTTP.Paths {
+				if path.Backend.Service != nil {
+					b.addReference(ingID, "Service", path.Backend.Service.Name, TopologyEdgeRoutes)
+				}
+			}
+		}
+	}
+
+	return &b.graph, nil
+}
+
+func replicasOrOne(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// podSpecDependencies 收集 Pod 引用的 PVC、ConfigMap 与 Secret 名称（去重）
+func podSpecDependencies(spec *corev1.PodSpec) (claims, configMaps, secrets []string) {
+	seen := make(map[string]bool)
+	add := func(list *[]string, kind, name string) {
+		if name == "" || seen[kind+"/"+name] {
+			return
+		}
+		seen[kind+"/"+name] = true
+		*list = append(*list, name)
+	}
+
+	for _, volume := range spec.Volumes {
+		switch {
+		case volume.PersistentVolumeClaim != nil:
+			add(&claims, "pvc", volume.PersistentVolumeClaim.ClaimName)
+		case volume.ConfigMap != nil:
+			add(&configMaps, "cm", volume.ConfigMap.Name)
+		case volume.Secret != nil:
+			add(&secrets, "secret", volume.Secret.SecretName)
+		case volume.Projected != nil:
+			for _, source := range volume.Projected.Sources {
+				if source.ConfigMap != nil {
+					add(&configMaps, "cm", source.ConfigMap.Name)
+				}
+				if source.Secret != nil {
+					add(&secrets, "secret", source.Secret.Name)
+				}
+			}
+		}
+	}
+
+	containers := make([]corev1.Container, 0, len(spec.InitContainers)+len(spec.Containers))
+	containers = append(containers, spec.InitContainers...)
+	containers = append(containers, spec.Containers...)
+	for _, container := range containers {
+		for _, from := range container.EnvFrom {
+			if from.ConfigMapRef != nil {
+				add(&configMaps, "cm", from.ConfigMapRef.Name)
+			}
+			if from.SecretRef != nil {
+				add(&secrets, "secret", from.SecretRef.Name)
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if env.ValueFrom.ConfigMapKeyRef != nil {
+				add(&configMaps, "cm", env.ValueFrom.ConfigMapKeyRef.Name)
+			}
+			if env.ValueFrom.SecretKeyRef != nil {
+				add(&secrets, "secret", env.ValueFrom.SecretKeyRef.Name)
+			}
+		}
+	}
+
+	for _, ref := range spec.ImagePullSecrets {
+		add(&secrets, "secret", ref.Name)
+	}
+	return claims, configMaps, secrets
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/k8s-dashboard/backend/internal/api/middleware"
+	"github.com/k8s-dashboard/backend/internal/auth"
 )
 
 type createWSTicketRequest struct {
@@ -34,12 +35,13 @@ func (h *Handler) CreateWSTicket(c *gin.Context) {
 		req.Action = "exec"
 	}
 
-	if req.Action != "exec" && req.Action != "logs" && req.Action != "watch" {
+	if req.Action != "exec" && req.Action != "logs" && req.Action != "watch" && req.Action != "events" && req.Action != "notifications" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported ws action"})
 		return
 	}
 
-	if req.Namespace == "" || req.Name == "" {
+	// 事件流与通知流不针对单个资源；namespace 为空表示所有有权访问的命名空间
+	if req.Action != "events" && req.Action != "notifications" && (req.Namespace == "" || req.Name == "") {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace and name are required"})
 		return
 	}
@@ -51,7 +53,7 @@ func (h *Handler) CreateWSTicket(c *gin.Context) {
 	}
 
 	allowed := middleware.GetAllowedNamespaces(c)
-	if user.Role != "admin" && !user.AllNamespaces {
+	if user.Role != "admin" && !user.AllNamespaces && req.Namespace != "" {
 		ok := false
 		for _, ns := range allowed {
 			if ns == req.Namespace {
@@ -63,6 +65,11 @@ func (h *Handler) CreateWSTicket(c *gin.Context) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "无权访问该命名空间"})
 			return
 		}
+		// 只读授权的命名空间不允许进入终端
+		if req.Action == "exec" && !middleware.NamespacePermissionAllowed(c, req.Namespace, auth.NamespacePermWrite) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "命名空间授权不足", "requiredPermission": auth.NamespacePermWrite})
+			return
+		}
 	}
 
 	if req.Cluster == "" {
@@ -72,15 +79,21 @@ func (h *Handler) CreateWSTicket(c *gin.Context) {
 		}
 	}
 
+	sessionID := ""
+	if h.auth != nil {
+		sessionID = h.auth.SessionIDFromToken(strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer "))
+	}
+
 	ticket, err := middleware.IssueWSTicket(user, middleware.WSTicketRequest{
 		Action:    req.Action,
 		Namespace: req.Namespace,
 		Name:      req.Name,
 		Container: req.Container,
 		Cluster:   req.Cluster,
+		SessionID: sessionID,
 	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// wsSessionCheckInterval 超时检查间隔
+var wsSessionCheckInterval = 5 * time.Second
+
+// wsSessionLimits WebSocket 会话超时配置，0 表示不限制
+type wsSessionLimits struct {
+	Idle        time.Duration
+	MaxDuration time.Duration
+	Warning     time.Duration
+}
+
+// wsSessionLimits 返回启动配置中的 WebSocket 会话限制
+func (h *Handler) wsSessionLimits() wsSessionLimits {
+	return wsSessionLimits{
+		Idle:        h.opts.WSIdleTimeout,
+		MaxDuration: h.opts.WSMaxSessionDuration,
+		Warning:     h.opts.WSTimeoutWarning,
+	}
+}
+
+// wsSession 包装 WebSocket 连接：串行化写操作，并执行空闲/最长时长限制
+type wsSession struct {
+	conn   *websocket.Conn
+	limits wsSessionLimits
+	start  time.Time
+	cancel context.CancelFunc
+
+	writeMu sync.Mutex
+
+	mu         sync.Mutex
+	lastActive time.Time
+	idleWarned bool
+	maxWarned  bool
+}
+
+// newWSSession 创建会话并启动超时监控，返回的 ctx 在会话被终止时取消
+func newWSSession(conn *websocket.Conn, limits wsSessionLimits) (*wsSession, context.Context) {
+	ctx, cancel := context.WithCancel(context.Background())
+	now := time.Now()
+	s := &wsSession{
+		conn:       conn,
+		limits:     limits,
+		start:      now,
+		cancel:     cancel,
+		lastActive: now,
+	}
+	go s.watch(ctx)
+	return s, ctx
+}
+
+// WriteMessage 并发安全地写消息（gorilla/websocket 不支持并发写）
+func (s *wsSession) WriteMessage(messageType int, data []byte) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.conn.WriteMessage(messageType, data)
+}
+
+// Touch 记录用户活动，重置空闲计时
+func (s *wsSession) Touch() {
+	s.mu.Lock()
+	s.lastActive = time.Now()
+	s.idleWarned = false
+	s.mu.Unlock()
+}
+
+// Close 结束会话
+func (s *wsSession) Close() {
+	s.cancel()
+}
+
+func (s *wsSession) notify(format string, args ...interface{}) {
+	s.WriteMessage(websocket.TextMessage, []byte("\r\n"+fmt.Sprintf(format, args...)+"\r\n"))
+}
+
+func (s *wsSession) terminate(reason string) {
+	s.notify("%s", reason)
+	s.writeMu.Lock()
+	s.conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "session timeout"),
+		time.Now().Add(time.Second))
+	s.writeMu.Unlock()
+	s.cancel()
+	// 关闭底层连接，使读循环退出并释放 exec 流
+	s.conn.Close()
+}
+
+func (s *wsSession) watch(ctx context.Context) {
+	if s.limits.Idle <= 0 && s.limits.MaxDuration <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(wsSessionCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.mu.Lock()
+			idle := now.Sub(s.lastActive)
+			idleWarned, maxWarned := s.idleWarned, s.maxWarned
+			s.mu.Unlock()
+			elapsed := now.Sub(s.start)
+
+			if s.limits.MaxDuration > 0 {
+				if elapsed >= s.limits.MaxDuration {
+					s.terminate(fmt.Sprintf("会话已达到最长时长 %s，连接已断开", s.limits.MaxDuration))
+					return
+				}
+				if !maxWarned && s.limits.MaxDuration-elapsed <= s.limits.Warning {
+					s.notify("[警告] 会话将在 %s 后达到最长时长并断开", (s.limits.MaxDuration - elapsed).Round(time.Second))
+					s.mu.Lock()
+					s.maxWarned = true
+					s.mu.Unlock()
+				}
+			}
+
+			if s.limits.Idle > 0 {
+				if idle >= s.limits.Idle {
+					s.terminate(fmt.Sprintf("会话空闲超过 %s，连接已断开", s.limits.Idle))
+					return
+				}
+				if !idleWarned && s.limits.Idle-idle <= s.limits.Warning {
+					s.notify("[警告] 会话空闲，将在 %s 后断开，输入任意内容可保持连接", (s.limits.Idle - idle).Round(time.Second))
+					s.mu.Lock()
+					s.idleWarned = true
+					s.mu.Unlock()
+				}
+			}
+		}
+	}
+}
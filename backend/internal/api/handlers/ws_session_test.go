@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// startWSSession 启动一个使用给定限制的 WebSocket 服务端会话，返回客户端连接与服务端会话 ctx
+func startWSSession(t *testing.T, limits wsSessionLimits) (*websocket.Conn, <-chan context.Context) {
+	t.Helper()
+	prev := wsSessionCheckInterval
+	wsSessionCheckInterval = 10 * time.Millisecond
+	t.Cleanup(func() { wsSessionCheckInterval = prev })
+
+	ctxs := make(chan context.Context, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		session, ctx := newWSSession(ws, limits)
+		ctxs <- ctx
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				session.Close()
+				return
+			}
+			session.Touch()
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn, ctxs
+}
+
+// readUntilClose 读取消息直到连接关闭，返回收到的文本与关闭码
+func readUntilClose(t *testing.T, conn *websocket.Conn) (string, int) {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var text strings.Builder
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if closeErr, ok := err.(*websocket.CloseError); ok {
+				return text.String(), closeErr.Code
+			}
+			t.Fatalf("expected close frame, got %v (messages %q)", err, text.String())
+		}
+		text.Write(data)
+	}
+}
+
+func TestWSSessionIdleTimeout(t *testing.T) {
+	conn, ctxs := startWSSession(t, wsSessionLimits{Idle: 100 * time.Millisecond, Warning: 50 * time.Millisecond})
+
+	text, code := readUntilClose(t, conn)
+	if code != websocket.ClosePolicyViolation {
+		t.Fatalf("close code = %d, want %d", code, websocket.ClosePolicyViolation)
+	}
+	if !strings.Contains(text, "[警告] 会话空闲") || !strings.Contains(text, "会话空闲超过") {
+		t.Fatalf("expected idle warning and termination notice, got %q", text)
+	}
+	select {
+	case ctx := <-ctxs:
+		if ctx.Err() == nil {
+			t.Fatalf("expected session context to be cancelled")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("session was not started")
+	}
+}
+
+func TestWSSessionActivityResetsIdle(t *testing.T) {
+	conn, _ := startWSSession(t, wsSessionLimits{Idle: 200 * time.Millisecond})
+
+	deadline := time.Now().Add(400 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		if err := conn.WriteMessage(websocket.TextMessage, []byte("x")); err != nil {
+			t.Fatalf("session closed despite activity: %v", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	// 停止活动后仍会因空闲被断开
+	if _, code := readUntilClose(t, conn); code != websocket.ClosePolicyViolation {
+		t.Fatalf("close code = %d, want %d", code, websocket.ClosePolicyViolation)
+	}
+}
+
+func TestWSSessionMaxDuration(t *testing.T) {
+	conn, _ := startWSSession(t, wsSessionLimits{MaxDuration: 150 * time.Millisecond, Warning: 100 * time.Millisecond})
+
+	// 持续活动也无法超过最长时长
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		ticker := time.NewTicker(20 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				conn.WriteMessage(websocket.TextMessage, []byte("x"))
+			}
+		}
+	}()
+
+	start := time.Now()
+	text, code := readUntilClose(t, conn)
+	if code != websocket.ClosePolicyViolation {
+		t.Fatalf("close code = %d, want %d", code, websocket.ClosePolicyViolation)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Fatalf("session closed too early after %s", elapsed)
+	}
+	if !strings.Contains(text, "[警告] 会话将在") || !strings.Contains(text, "最长时长") {
+		t.Fatalf("expected max duration warning and termination notice, got %q", text)
+	}
+}
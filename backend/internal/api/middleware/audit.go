@@ -10,6 +10,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/k8s-dashboard/backend/internal/audit"
+	"github.com/k8s-dashboard/backend/internal/auth"
 	"sigs.k8s.io/yaml"
 )
 
@@ -21,6 +22,12 @@ var auditableMethods = map[string]bool{
 	"DELETE": true,
 }
 
+// ContextAuditDetailKey 处理器补充审计明细（例如传输的文件路径）的上下文键
+const ContextAuditDetailKey = "auditDetail"
+
+// ContextAuditDiffKey 处理器记录更新前后对象差异的上下文键
+const ContextAuditDiffKey = "auditDiff"
+
 var sensitiveKeyPattern = regexp.MustCompile(`(?i)(password|secret|token|key|credential|authorization|stringdata|data)`)
 
 // 资源路径模式
@@ -77,7 +84,8 @@ func AuditMiddleware(auditClient *audit.Client) gin.HandlerFunc {
 			return
 		}
 
-		if !shouldAudit(c.Request.Method, c.Request.URL.Path) {
+		path := CanonicalPath(c.Request.URL.Path)
+		if !shouldAudit(c.Request.Method, path) {
 			c.Next()
 			return
 		}
@@ -86,12 +94,12 @@ func AuditMiddleware(auditClient *audit.Client) gin.HandlerFunc {
 
 		var requestBody string
 		if c.Request.Body != nil && c.Request.Method != "GET" {
-			bodyBytes, _ := io.ReadAll(c.Request.Body)
-			c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-
-			if shouldStoreRequestBody(c.Request.URL.Path) {
+			if shouldStoreRequestBody(path) {
+				bodyBytes, _ := io.ReadAll(c.Request.Body)
+				c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
 				requestBody = sanitizeRequestBody(bodyBytes, c.ContentType())
 			} else {
+				// 不记录 payload 时无需缓冲请求体（如文件上传）
 				requestBody = "[FILTERED]"
 			}
 		}
@@ -99,14 +107,18 @@ func AuditMiddleware(auditClient *audit.Client) gin.HandlerFunc {
 		c.Next()
 
 		duration := time.Since(startTime).Milliseconds()
-		resource, namespace, resourceName := parseResourceInfo(c.Request.URL.Path)
-		user := resolveAuditUser(c)
+		resource, namespace, resourceName := parseResourceInfo(path)
+		// 认证与集群选择中间件在 c.Next() 中执行，此时上下文里已有当前用户与集群
+		user := GetCurrentUser(c)
 		cluster := resolveCluster(c)
-		message := generateActionMessage(c.Request.Method, c.Request.URL.Path, resource, resourceName, namespace)
+		message := generateActionMessage(c.Request.Method, path, resource, resourceName, namespace)
+		if detail := c.GetString(ContextAuditDetailKey); detail != "" {
+			message += ": " + detail
+		}
 
 		log := &audit.AuditLog{
 			Timestamp:    startTime,
-			User:         user,
+			User:         auditUsername(user),
 			Action:       c.Request.Method,
 			Resource:     resource,
 			ResourceName: resourceName,
@@ -118,6 +130,11 @@ func AuditMiddleware(auditClient *audit.Client) gin.HandlerFunc {
 			RequestBody:  requestBody,
 			Duration:     duration,
 			Message:      message,
+			Diff:         c.GetString(ContextAuditDiffKey),
+		}
+		if user != nil {
+			log.UserID = user.ID
+			log.Role = user.Role
 		}
 
 		go func(l *audit.AuditLog) {
@@ -132,39 +149,64 @@ func shouldAudit(method, path string) bool {
 	if !strings.HasPrefix(path, "/api/") {
 		return false
 	}
+	// 回放终端录制本身也需要留痕
+	if method == "GET" && strings.HasPrefix(path, "/api/v1/audit/terminal-sessions/") && strings.HasSuffix(path, "/replay") {
+		return true
+	}
 	if strings.HasPrefix(path, "/api/v1/audit") {
 		return false
 	}
+	// 自定义指标查询是只读请求，面板刷新频繁，不记录
+	if path == "/api/v1/metrics/query" {
+		return false
+	}
+	// Alertmanager Webhook 由 Alertmanager 周期性推送，已写入告警历史，不记录
+	if path == "/api/v1/integrations/alertmanager/webhook" {
+		return false
+	}
 	if auditableMethods[method] {
 		return true
 	}
+	if method == "GET" && (strings.HasSuffix(path, "/files") || strings.HasSuffix(path, "/users/export") ||
+		strings.HasSuffix(path, "/logs/download") || strings.HasPrefix(path, "/api/v1/packet-captures/") && strings.HasSuffix(path, "/download")) {
+		return true
+	}
 	return method == "GET" && strings.Contains(path, "/secrets/")
 }
 
 func shouldStoreRequestBody(path string) bool {
-	// Secret/YAML 相关请求默认不记录 payload，仅保留摘要。
-	return !strings.Contains(path, "/secrets") && !strings.Contains(path, "/yaml")
+	// Secret/YAML 相关请求默认不记录 payload，仅保留摘要；批量导入用户可能包含明文密码。
+	return !strings.Contains(path, "/secrets") && !strings.Contains(path, "/yaml") && !strings.HasSuffix(path, "/files") &&
+		!strings.HasSuffix(path, "/users/import")
 }
 
-func resolveAuditUser(c *gin.Context) string {
-	if user := GetCurrentUser(c); user != nil {
-		if user.Username != "" {
-			return user.Username
-		}
-		return "authenticated"
+// SetAuditDetail 为当前请求的审计日志追加明细
+func SetAuditDetail(c *gin.Context, detail string) {
+	c.Set(ContextAuditDetailKey, detail)
+}
+
+// SetAuditDiff 为当前请求的审计日志记录对象变更差异，diff 由 audit.Diff 生成
+func SetAuditDiff(c *gin.Context, diff string) {
+	c.Set(ContextAuditDiffKey, diff)
+}
+
+func auditUsername(user *auth.User) string {
+	if user == nil {
+		return "anonymous"
 	}
-	return "anonymous"
+	if user.Username != "" {
+		return user.Username
+	}
+	return "authenticated"
 }
 
+// resolveCluster 仅使用集群中间件解析出的集群；未经过集群中间件（如认证失败）时记为 default，
+// 不采信请求头中未经校验的 X-Cluster
 func resolveCluster(c *gin.Context) string {
 	if cluster := GetClusterName(c); cluster != "" {
 		return cluster
 	}
-	cluster := c.GetHeader("X-Cluster")
-	if cluster == "" {
-		cluster = "default"
-	}
-	return cluster
+	return "default"
 }
 
 func sanitizeRequestBody(body []byte, contentType string) string {
@@ -278,18 +320,66 @@ func generateActionMessage(method, path, resource, name, namespace string) strin
 
 // 从路径中识别特殊操作
 func detectSpecialAction(path string) string {
+	if path == "/api/v1/batch" {
+		return "批量操作"
+	}
+	if strings.HasPrefix(path, "/api/v1/nodes/") && strings.HasSuffix(path, "/rebalance") {
+		return "重新均衡"
+	}
 	if strings.Contains(path, "/restart") {
 		return "重启"
 	}
 	if strings.Contains(path, "/scale") {
 		return "扩缩容"
 	}
+	if strings.Contains(path, "/rerun") {
+		return "重新运行"
+	}
 	if strings.Contains(path, "/rollback") {
 		return "回滚"
 	}
+	if strings.HasSuffix(path, "/logs/download") {
+		return "下载日志"
+	}
+	if strings.HasSuffix(path, "/captures") {
+		return "抓包"
+	}
+	if strings.HasPrefix(path, "/api/v1/packet-captures/") && strings.HasSuffix(path, "/download") {
+		return "下载抓包"
+	}
 	if strings.Contains(path, "/logs") {
 		return "查看日志"
 	}
+	if strings.HasSuffix(path, "/files") {
+		return "传输文件"
+	}
+	if strings.HasSuffix(path, "/set-default") {
+		return "设为默认"
+	}
+	if strings.HasSuffix(path, "/expand") {
+		return "扩容"
+	}
+	if strings.HasSuffix(path, "/reveal") {
+		return "查看明文"
+	}
+	if strings.HasSuffix(path, "/cleanup") {
+		return "清理"
+	}
+	if strings.HasPrefix(path, "/api/v1/namespaces/") && strings.HasSuffix(path, "/ports") {
+		return "修改端口"
+	}
+	if strings.HasPrefix(path, "/api/v1/runbooks/") && strings.HasSuffix(path, "/run") {
+		return "执行运行手册"
+	}
+	if strings.HasSuffix(path, "/users/export") {
+		return "导出用户"
+	}
+	if strings.HasSuffix(path, "/users/import") {
+		return "导入用户"
+	}
+	if strings.HasSuffix(path, "/replay") {
+		return "回放终端会话"
+	}
 	if strings.Contains(path, "/exec") {
 		return "执行命令"
 	}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/audit"
+	"github.com/k8s-dashboard/backend/internal/auth"
+	dbutil "github.com/k8s-dashboard/backend/internal/db"
+)
+
+func TestAuditMiddlewareRecordsAuthenticatedIdentity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	conn, dialect, err := dbutil.Open(dbutil.Config{
+		SQLitePath:          filepath.Join(t.TempDir(), "audit.db"),
+		AllowSQLiteFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer conn.Close()
+
+	auditClient, err := audit.NewClient(conn, dialect)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	r := gin.New()
+	r.Use(AuditMiddleware(auditClient))
+	v1 := r.Group("/api/v1")
+	v1.Use(func(c *gin.Context) {
+		// 模拟认证与集群选择中间件：仅带 token 的请求视为已认证
+		if c.GetHeader("Authorization") == "" {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Set(ContextUserKey, &auth.User{ID: 42, Username: "alice", Role: "operator"})
+		c.Set(ContextClusterNameKey, "prod")
+		c.Next()
+	})
+	v1.DELETE("/namespaces/:ns/pods/:name", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/namespaces/default/pods/web-0", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/namespaces/default/pods/web-1", nil)
+	req.Header.Set("X-Cluster", "spoofed")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	var logs []audit.AuditLog
+	deadline := time.Now().Add(5 * time.Second)
+	for len(logs) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for audit logs, got %d", len(logs))
+		}
+		time.Sleep(10 * time.Millisecond)
+		result, err := auditClient.List(audit.ListParams{Page: 1, PageSize: 10})
+		if err != nil {
+			t.Fatalf("List failed: %v", err)
+		}
+		logs = result.Items
+	}
+
+	byName := make(map[string]audit.AuditLog)
+	for _, l := range logs {
+		byName[l.ResourceName] = l
+	}
+	authed := byName["web-0"]
+	if authed.User != "alice" || authed.UserID != 42 || authed.Role != "operator" || authed.Cluster != "prod" {
+		t.Fatalf("unexpected authenticated audit log: %+v", authed)
+	}
+	anon := byName["web-1"]
+	if anon.User != "anonymous" || anon.UserID != 0 || anon.Role != "" || anon.Cluster != "default" || anon.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("unexpected anonymous audit log: %+v", anon)
+	}
+	if !strings.Contains(authed.Message, "default/web-0") {
+		t.Fatalf("unexpected message: %q", authed.Message)
+	}
+}
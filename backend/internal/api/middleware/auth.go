@@ -13,6 +13,10 @@ import (
 const (
 	ContextUserKey              = "user"
 	ContextAllowedNamespacesKey = "allowedNamespaces"
+	// ContextNamespacePermissionsKey 受限用户各命名空间的授权级别
+	ContextNamespacePermissionsKey = "namespacePermissions"
+	// ContextAPITokenKey 通过 API 令牌认证时的令牌信息
+	ContextAPITokenKey = "apiToken"
 )
 
 // AuthMiddleware 认证中间件
@@ -40,15 +44,18 @@ func AuthMiddleware(authClient *auth.Client) gin.HandlerFunc {
 			return
 		}
 
-		// 验证 Token
-		user, err := authClient.ValidateToken(tokenString)
+		// 验证 Token（登录 JWT 或 API 令牌）
+		user, apiToken, err := authenticate(authClient, tokenString)
 		if err != nil {
 			status := http.StatusUnauthorized
 			message := "认证失败"
 
+			body := gin.H{}
 			switch err {
 			case auth.ErrTokenExpired:
-				message = "Token 已过期，请重新登录"
+				// 前端据此使用刷新令牌换取新的访问令牌
+				message = "Token 已过期，请刷新或重新登录"
+				body["code"] = "TOKEN_EXPIRED"
 			case auth.ErrInvalidToken:
 				message = "无效的 Token"
 			case auth.ErrUserDisabled:
@@ -56,17 +63,65 @@ func AuthMiddleware(authClient *auth.Client) gin.HandlerFunc {
 				status = http.StatusForbidden
 			}
 
-			c.JSON(status, gin.H{"error": message})
+			body["error"] = message
+			c.JSON(status, body)
 			c.Abort()
 			return
 		}
 
 		// 将用户信息存入上下文
 		c.Set(ContextUserKey, user)
+		if apiToken != nil {
+			c.Set(ContextAPITokenKey, apiToken)
+		}
 		c.Next()
 	}
 }
 
+// authenticate 按前缀区分 API 令牌与登录 JWT
+func authenticate(authClient *auth.Client, tokenString string) (*auth.User, *auth.APIToken, error) {
+	if strings.HasPrefix(tokenString, auth.APITokenPrefix) {
+		return authClient.ValidateAPIToken(tokenString)
+	}
+	user, err := authClient.ValidateToken(tokenString)
+	return user, nil, err
+}
+
+// GetAPIToken 返回当前请求使用的 API 令牌，登录会话认证时返回 nil
+func GetAPIToken(c *gin.Context) *auth.APIToken {
+	value, ok := c.Get(ContextAPITokenKey)
+	if !ok {
+		return nil
+	}
+	token, _ := value.(*auth.APIToken)
+	return token
+}
+
+// passwordChangeAllowedPaths 需要修改密码时仍可访问的接口
+var passwordChangeAllowedPaths = map[string]bool{
+	"/api/v1/auth/me":              true,
+	"/api/v1/auth/logout":          true,
+	"/api/v1/auth/password":        true,
+	"/api/v1/auth/password-policy": true,
+}
+
+// RequirePasswordChange 用户被要求修改密码（管理员重置、默认管理员首次登录或密码过期）时，
+// 仅放行查看身份、登出与修改密码接口，其余返回 403 与 code=PASSWORD_CHANGE_REQUIRED
+func RequirePasswordChange() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := GetCurrentUser(c)
+		if user == nil || !user.MustChangePassword || passwordChangeAllowedPaths[CanonicalPath(c.Request.URL.Path)] {
+			c.Next()
+			return
+		}
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": auth.ErrPasswordChangeRequired.Error(),
+			"code":  "PASSWORD_CHANGE_REQUIRED",
+		})
+		c.Abort()
+	}
+}
+
 // OptionalAuthMiddleware 可选认证中间件（不强制要求登录）
 func OptionalAuthMiddleware(authClient *auth.Client) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -88,9 +143,12 @@ func OptionalAuthMiddleware(authClient *auth.Client) gin.HandlerFunc {
 			return
 		}
 
-		user, err := authClient.ValidateToken(tokenString)
+		user, apiToken, err := authenticate(authClient, tokenString)
 		if err == nil {
 			c.Set(ContextUserKey, user)
+			if apiToken != nil {
+				c.Set(ContextAPITokenKey, apiToken)
+			}
 		}
 
 		c.Next()
@@ -157,7 +215,7 @@ func NamespaceAccessMiddleware(authClient *auth.Client) gin.HandlerFunc {
 			return
 		}
 
-		namespaces, err := authClient.GetUserNamespaces(user.ID)
+		namespaces, err := authClient.NamespaceGrants(user)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "读取命名空间权限失败"})
 			c.Abort()
@@ -165,12 +223,15 @@ func NamespaceAccessMiddleware(authClient *auth.Client) gin.HandlerFunc {
 		}
 
 		allowed := make([]string, 0, len(namespaces))
+		permissions := make(map[string]string, len(namespaces))
 		for _, ns := range namespaces {
 			if ns.Namespace != "" {
 				allowed = append(allowed, ns.Namespace)
+				permissions[ns.Namespace] = ns.Permissions
 			}
 		}
 		c.Set(ContextAllowedNamespacesKey, allowed)
+		c.Set(ContextNamespacePermissionsKey, permissions)
 
 		// 从路径参数获取命名空间
 		namespace := c.Param("ns")
@@ -192,6 +253,16 @@ func NamespaceAccessMiddleware(authClient *auth.Client) gin.HandlerFunc {
 			return
 		}
 
+		required := RequiredNamespacePermission(c.Request.Method, c.Request.URL.Path)
+		if !auth.NamespacePermissionAtLeast(permissions[namespace], required) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":              "命名空间授权不足",
+				"requiredPermission": required,
+			})
+			c.Abort()
+			return
+		}
+
 		c.Next()
 	}
 }
@@ -227,6 +298,24 @@ func GetAllowedNamespaces(c *gin.Context) []string {
 	return items
 }
 
+// NamespacePermissionAllowed 判断当前用户在命名空间上是否具备指定授权级别；
+// admin 与全命名空间用户不受限
+func NamespacePermissionAllowed(c *gin.Context, namespace, required string) bool {
+	user := GetCurrentUser(c)
+	if user == nil {
+		return false
+	}
+	if user.Role == "admin" || user.AllNamespaces {
+		return true
+	}
+	value, ok := c.Get(ContextNamespacePermissionsKey)
+	if !ok {
+		return false
+	}
+	permissions, _ := value.(map[string]string)
+	return auth.NamespacePermissionAtLeast(permissions[namespace], required)
+}
+
 func namespaceInList(namespace string, allowed []string) bool {
 	for _, ns := range allowed {
 		if ns == namespace {
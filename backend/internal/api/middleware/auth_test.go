@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/auth"
+	dbutil "github.com/k8s-dashboard/backend/internal/db"
+)
+
+func TestNamespaceAccessMiddlewareEnforcesPermissions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	conn, dialect, err := dbutil.Open(dbutil.Config{
+		SQLitePath:          filepath.Join(t.TempDir(), "auth.db"),
+		AllowSQLiteFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer conn.Close()
+
+	authClient, err := auth.NewClient(conn, dialect, "test-secret")
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	user, err := authClient.CreateUser(&auth.CreateUserRequest{
+		Username:   "dev",
+		Password:   "Passw0rd!",
+		Role:       "operator",
+		Namespaces: []string{"readonly", "team", "owned"},
+		NamespacePermissions: map[string]string{
+			"readonly": auth.NamespacePermRead,
+			"team":     auth.NamespacePermWrite,
+			"owned":    auth.NamespacePermAdmin,
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set(ContextUserKey, user)
+		c.Next()
+	})
+	r.Use(NamespaceAccessMiddleware(authClient))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/api/v1/namespaces/:ns/pods", ok)
+	r.DELETE("/api/v1/namespaces/:ns/pods/:name", ok)
+	r.DELETE("/api/v1/namespaces/:ns", ok)
+	r.GET("/api/v1/namespaces/:ns/pods/:name/files", ok)
+	r.GET("/api/v1/namespaces/:ns/secrets/:name/reveal", ok)
+	r.POST("/api/v1/apply", ok)
+
+	tests := []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{http.MethodGet, "/api/v1/namespaces/readonly/pods", http.StatusOK},
+		{http.MethodDelete, "/api/v1/namespaces/readonly/pods/web", http.StatusForbidden},
+		{http.MethodGet, "/api/v1/namespaces/readonly/pods/web/files", http.StatusForbidden},
+		{http.MethodGet, "/api/v1/namespaces/readonly/secrets/db/reveal", http.StatusForbidden},
+		{http.MethodGet, "/api/v1/namespaces/team/secrets/db/reveal", http.StatusOK},
+		{http.MethodPost, "/api/v1/apply?namespace=readonly", http.StatusForbidden},
+		{http.MethodDelete, "/api/v1/namespaces/team/pods/web", http.StatusOK},
+		{http.MethodDelete, "/api/v1/namespaces/team", http.StatusForbidden},
+		{http.MethodDelete, "/api/v1/namespaces/owned", http.StatusOK},
+		{http.MethodGet, "/api/v1/namespaces/other/pods", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if w.Code != tt.want {
+			t.Fatalf("%s %s = %d, want %d (%s)", tt.method, tt.path, w.Code, tt.want, w.Body.String())
+		}
+	}
+}
+
+func TestRequirePasswordChange(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set(ContextUserKey, &auth.User{ID: 1, Username: "admin", Role: "admin", MustChangePassword: true})
+		c.Next()
+	})
+	r.Use(RequirePasswordChange())
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/api/v1/auth/me", ok)
+	r.POST("/api/v1/auth/password", ok)
+	r.GET("/api/v1/namespaces", ok)
+
+	tests := []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{http.MethodGet, "/api/v1/auth/me", http.StatusOK},
+		{http.MethodPost, "/api/v1/auth/password", http.StatusOK},
+		{http.MethodGet, "/api/v1/namespaces", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if w.Code != tt.want {
+			t.Fatalf("%s %s = %d, want %d", tt.method, tt.path, w.Code, tt.want)
+		}
+	}
+}
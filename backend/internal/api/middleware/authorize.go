@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/auth"
 )
 
 // AuthorizeByRoute 按 method+path 进行最小角色校验：
@@ -34,7 +35,13 @@ func AuthorizeByRoute() gin.HandlerFunc {
 	}
 }
 
+// RequiredRole 返回访问指定接口所需的最低角色，供权限预检复用路由规则
+func RequiredRole(method, path string) string {
+	return requiredRole(method, path)
+}
+
 func requiredRole(method, path string) string {
+	path = CanonicalPath(path)
 	// 管理员 API
 	if strings.HasPrefix(path, "/api/v1/admin/") {
 		return "admin"
@@ -73,7 +80,29 @@ func requiredRole(method, path string) string {
 	// 用户自服务接口，viewer 即可
 	if strings.HasPrefix(path, "/api/v1/auth/password") ||
 		strings.HasPrefix(path, "/api/v1/auth/logout") ||
-		strings.HasPrefix(path, "/api/v1/auth/sessions") {
+		strings.HasPrefix(path, "/api/v1/auth/sessions") ||
+		strings.HasPrefix(path, "/api/v1/auth/tokens") ||
+		path == "/api/v1/auth/can-i" {
+		return "viewer"
+	}
+
+	// 自定义面板归属当前用户，viewer 即可管理自己的面板
+	if strings.HasPrefix(path, "/api/v1/panels") {
+		return "viewer"
+	}
+
+	// 终端会话录制包含完整的命令与输出，仅 admin 可回放
+	if strings.HasPrefix(path, "/api/v1/audit/terminal-sessions") {
+		return "admin"
+	}
+
+	// 审计导出用于合规交付，包含全部用户的操作记录，仅 admin；存储统计与异常告警同样仅 admin
+	if strings.HasPrefix(path, "/api/v1/audit/export") || path == "/api/v1/audit/storage" || path == "/api/v1/audit/anomalies" {
+		return "admin"
+	}
+
+	// 提交审批请求对所有登录用户开放，处理审批仍需 admin
+	if path == "/api/v1/approvals" && method == http.MethodPost {
 		return "viewer"
 	}
 
@@ -82,6 +111,21 @@ func requiredRole(method, path string) string {
 		return "admin"
 	}
 
+	// 容器文件传输等同于 exec，读写均需 operator
+	if strings.HasPrefix(path, "/api/v1/namespaces/") && strings.HasSuffix(path, "/files") {
+		return "operator"
+	}
+
+	// StorageClass 为集群级配置，变更影响所有命名空间的动态供给，仅 admin
+	if strings.HasPrefix(path, "/api/v1/storageclasses") && method != http.MethodGet {
+		return "admin"
+	}
+
+	// 查看 Secret 明文需 operator
+	if strings.HasPrefix(path, "/api/v1/namespaces/") && strings.HasSuffix(path, "/reveal") {
+		return "operator"
+	}
+
 	// 需要操作权限的接口
 	if method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch || method == http.MethodDelete {
 		return "operator"
@@ -89,3 +133,39 @@ func requiredRole(method, path string) string {
 
 	return "viewer"
 }
+
+// namespaceAdminResources 修改这些命名空间级资源需要命名空间 admin 授权
+var namespaceAdminResources = []string{"resourcequotas", "limitranges", "roles", "rolebindings", "serviceaccounts"}
+
+// RequiredNamespacePermission 返回受限用户访问命名空间内接口所需的授权级别：
+// 读取为 read，修改为 write，删除命名空间本身与管理配额/RBAC 为 admin
+func RequiredNamespacePermission(method, path string) string {
+	path = CanonicalPath(path)
+	// 容器文件传输等同于 exec、查看 Secret 明文，均需 write
+	if strings.HasPrefix(path, "/api/v1/namespaces/") && (strings.HasSuffix(path, "/files") || strings.HasSuffix(path, "/reveal")) {
+		return auth.NamespacePermWrite
+	}
+
+	if method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions {
+		return auth.NamespacePermRead
+	}
+
+	for _, prefix := range []string{"/api/v1/namespaces/", "/api/v1/namespace/"} {
+		if !strings.HasPrefix(path, prefix) {
+			continue
+		}
+		segments := strings.Split(strings.Trim(strings.TrimPrefix(path, prefix), "/"), "/")
+		if len(segments) == 1 && method == http.MethodDelete {
+			return auth.NamespacePermAdmin
+		}
+		if len(segments) > 1 {
+			for _, resource := range namespaceAdminResources {
+				if segments[1] == resource {
+					return auth.NamespacePermAdmin
+				}
+			}
+		}
+	}
+
+	return auth.NamespacePermWrite
+}
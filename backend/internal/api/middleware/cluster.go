@@ -5,19 +5,24 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/alertmanager"
 	"github.com/k8s-dashboard/backend/internal/clusters"
 	"github.com/k8s-dashboard/backend/internal/k8s"
+	"github.com/k8s-dashboard/backend/internal/metrics"
 )
 
 const (
 	ContextClusterNameKey   = "cluster"
 	ContextClusterClientKey = "clusterClient"
+	ContextMetricsClientKey = "clusterMetricsClient"
+	ContextAlertClientKey   = "clusterAlertClient"
 )
 
-// ClusterSelector 根据请求头 X-Cluster 解析目标集群，并注入请求上下文。
+// ClusterSelector 根据请求头 X-Cluster（或 ?cluster= 参数，供 WebSocket 使用）解析目标集群，并注入请求上下文，
+// 处理器通过 getK8s、观测服务通过 WithK8sClient 使用该集群的客户端。
 func ClusterSelector(manager *clusters.Manager) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if manager == nil || shouldSkipClusterResolution(c.Request.URL.Path) {
+		if manager == nil {
 			c.Next()
 			return
 		}
@@ -27,6 +32,18 @@ func ClusterSelector(manager *clusters.Manager) gin.HandlerFunc {
 			requested = strings.TrimSpace(c.Query("cluster"))
 		}
 
+		path := CanonicalPath(c.Request.URL.Path)
+		if shouldSkipClusterResolution(path) {
+			// 告警与静默接口不访问 K8s API，只按集群选择 Alertmanager，集群不可达时不影响告警查看
+			if strings.HasPrefix(path, "/api/v1/alerts") || strings.HasPrefix(path, "/api/v1/silences") {
+				if !bindEndpointClients(c, manager, requested) {
+					return
+				}
+			}
+			c.Next()
+			return
+		}
+
 		client, clusterName, err := manager.GetClientForRequest(requested)
 		if err != nil {
 			if clusterName == "" {
@@ -43,11 +60,52 @@ func ClusterSelector(manager *clusters.Manager) gin.HandlerFunc {
 
 		c.Set(ContextClusterNameKey, clusterName)
 		c.Set(ContextClusterClientKey, client)
+		if !bindEndpointClients(c, manager, clusterName) {
+			return
+		}
+		// 回显实际使用的集群（未指定时为默认集群），响应内容随集群变化，缓存需按该头区分；
+		// Vary 追加而非覆盖，保留压缩中间件写入的 Accept-Encoding
+		c.Header("X-Cluster", clusterName)
+		c.Writer.Header().Add("Vary", "X-Cluster")
 		c.Next()
 	}
 }
 
+// bindEndpointClients 注入集群专属的监控与告警客户端（未配置时不注入，处理器回落到全局客户端）
+func bindEndpointClients(c *gin.Context, manager *clusters.Manager, requested string) bool {
+	name, err := manager.ResolveClusterName(requested)
+	if err == nil {
+		var metricsClient *metrics.Client
+		var alertClient *alertmanager.Client
+		metricsClient, alertClient, err = manager.GetEndpointClients(name)
+		if err == nil {
+			c.Set(ContextClusterNameKey, name)
+			if metricsClient != nil {
+				c.Set(ContextMetricsClientKey, metricsClient)
+			}
+			if alertClient != nil {
+				c.Set(ContextAlertClientKey, alertClient)
+			}
+			return true
+		}
+	}
+	if name == "" {
+		name = requested
+	}
+	c.JSON(http.StatusServiceUnavailable, gin.H{
+		"code":    "CLUSTER_UNAVAILABLE",
+		"cluster": name,
+		"error":   err.Error(),
+	})
+	c.Abort()
+	return false
+}
+
 func shouldSkipClusterResolution(path string) bool {
+	// 权限预检需要在目标集群上执行
+	if path == "/api/v1/auth/can-i" {
+		return false
+	}
 	skips := []string{
 		"/api/v1/auth",
 		"/api/v1/clusters",
@@ -55,6 +113,7 @@ func shouldSkipClusterResolution(path string) bool {
 		"/api/v1/alerts",
 		"/api/v1/silences",
 		"/api/v1/approvals",
+		"/api/v1/panels",
 	}
 	for _, prefix := range skips {
 		if strings.HasPrefix(path, prefix) {
@@ -83,3 +142,23 @@ func GetClusterClient(c *gin.Context) *k8s.Client {
 	client, _ := value.(*k8s.Client)
 	return client
 }
+
+// GetMetricsClient 从上下文读取当前请求集群专属的 VictoriaMetrics 客户端，未配置时返回 nil。
+func GetMetricsClient(c *gin.Context) *metrics.Client {
+	value, ok := c.Get(ContextMetricsClientKey)
+	if !ok {
+		return nil
+	}
+	client, _ := value.(*metrics.Client)
+	return client
+}
+
+// GetAlertClient 从上下文读取当前请求集群专属的 Alertmanager 客户端，未配置时返回 nil。
+func GetAlertClient(c *gin.Context) *alertmanager.Client {
+	value, ok := c.Get(ContextAlertClientKey)
+	if !ok {
+		return nil
+	}
+	client, _ := value.(*alertmanager.Client)
+	return client
+}
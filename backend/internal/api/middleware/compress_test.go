@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGzip(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Gzip())
+	payload := strings.Repeat(`{"name":"nginx"},`, 200)
+	r.GET("/api/v2/pods", func(c *gin.Context) { c.String(http.StatusOK, payload) })
+	r.GET("/api/v2/download", func(c *gin.Context) { c.Data(http.StatusOK, "application/gzip", []byte("raw")) })
+	r.GET("/api/v2/cached", func(c *gin.Context) { c.Status(http.StatusNotModified) })
+
+	serve := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve("/api/v2/pods", "br, gzip")
+	if rec.Header().Get("Content-Encoding") != "gzip" || rec.Header().Get("Vary") != "Accept-Encoding" {
+		t.Fatalf("headers = %v", rec.Header())
+	}
+	reader, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %v", err)
+	}
+	body, _ := io.ReadAll(reader)
+	if string(body) != payload {
+		t.Errorf("decompressed body mismatch")
+	}
+
+	if rec := serve("/api/v2/pods", "gzip;q=0"); rec.Header().Get("Content-Encoding") != "" || rec.Body.String() != payload {
+		t.Errorf("gzip;q=0 should disable compression")
+	}
+	if rec := serve("/api/v2/pods", ""); rec.Header().Get("Content-Encoding") != "" {
+		t.Errorf("client without Accept-Encoding got compressed response")
+	}
+	if rec := serve("/api/v2/download", "gzip"); rec.Header().Get("Content-Encoding") != "" || rec.Body.String() != "raw" {
+		t.Errorf("binary download should not be compressed")
+	}
+	if rec := serve("/api/v2/cached", "gzip"); rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("304 should have empty body, got %d %q", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/ratelimit"
+)
+
+// RateLimitByIP 按客户端 IP 限流，覆盖登录等未认证接口；健康检查与静态文件不限流
+func RateLimitByIP(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		if !strings.HasPrefix(path, "/api/") && !strings.HasPrefix(path, "/ws/") {
+			c.Next()
+			return
+		}
+		if ok, retryAfter := limiter.AllowIP(c.ClientIP()); !ok {
+			abortTooManyRequests(c, retryAfter, "请求过于频繁，请稍后再试")
+			return
+		}
+		c.Next()
+	}
+}
+
+// RateLimitByUser 按认证用户限流，需放在认证中间件之后；同一用户的多个会话与 API 令牌共用额度
+func RateLimitByUser(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := GetCurrentUser(c)
+		if user == nil {
+			c.Next()
+			return
+		}
+		if ok, retryAfter := limiter.AllowUser(strconv.FormatInt(user.ID, 10)); !ok {
+			abortTooManyRequests(c, retryAfter, "请求过于频繁，请稍后再试")
+			return
+		}
+		c.Next()
+	}
+}
+
+// LimitExpensive 限制全局同时进行的高开销操作（全集群列表、日志流、导出等），名额在请求结束
+// （流式接口为连接关闭）时释放；名额已满时直接返回 429，不排队等待
+func LimitExpensive(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		release, ok, retryAfter := limiter.AcquireExpensive()
+		if !ok {
+			abortTooManyRequests(c, retryAfter, "服务端繁忙，同时进行的高开销操作过多，请稍后再试")
+			return
+		}
+		defer release()
+		c.Next()
+	}
+}
+
+// abortTooManyRequests 返回 429 与 Retry-After（秒，向上取整）
+func abortTooManyRequests(c *gin.Context, retryAfter time.Duration, message string) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":             message,
+		"code":              "RATE_LIMITED",
+		"retryAfterSeconds": seconds,
+	})
+	c.Abort()
+}
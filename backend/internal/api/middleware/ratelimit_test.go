@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/ratelimit"
+)
+
+func TestRateLimitByIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RateLimitByIP(ratelimit.NewLimiter(ratelimit.Config{IPRPS: 0.5, IPBurst: 1})))
+	r.GET("/api/v2/nodes", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+	if rec := serve("/api/v2/nodes"); rec.Code != http.StatusOK {
+		t.Fatalf("first request status = %d", rec.Code)
+	}
+	rec := serve("/api/v2/nodes")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("second request status = %d, want 429", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want 2", got)
+	}
+	if rec := serve("/healthz"); rec.Code != http.StatusOK {
+		t.Errorf("health check should not be limited, status = %d", rec.Code)
+	}
+}
+
+func TestLimitExpensive(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := ratelimit.NewLimiter(ratelimit.Config{MaxConcurrentExpensive: 1})
+	release, _, _ := limiter.AcquireExpensive()
+
+	r := gin.New()
+	r.GET("/api/v2/pods", LimitExpensive(limiter), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v2/pods", nil))
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("status = %d, Retry-After = %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+
+	release()
+	for i := 0; i < 2; i++ {
+		rec = httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v2/pods", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d status = %d, slot should be released after each request", i, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/alertmanager"
+	"github.com/k8s-dashboard/backend/internal/auth"
+	"github.com/k8s-dashboard/backend/internal/k8s"
+	"github.com/k8s-dashboard/backend/internal/metrics"
+)
+
+const ContextScopeKey = "requestScope"
+
+// Scope 单个请求使用的集群客户端，在中间件中一次性解析，处理器不再自行选择集群或回落全局客户端。
+// Metrics、Alerts 未配置时为 nil。
+type Scope struct {
+	Cluster string
+	K8s     *k8s.Client
+	Metrics *metrics.Client
+	Alerts  *alertmanager.Client
+}
+
+// ScopeDefaults 请求未指定集群或集群未单独配置时使用的全局客户端
+type ScopeDefaults struct {
+	K8s     *k8s.Client
+	Metrics *metrics.Client
+	Alerts  *alertmanager.Client
+	// UserClients 非 nil 时 K8s 客户端按当前用户身份构造，集群 RBAC 对每个用户生效
+	UserClients *k8s.UserClients
+}
+
+// Scope 基于全局客户端构造请求作用域（未经过 RequestScope 中间件的请求使用）
+func (d ScopeDefaults) Scope() *Scope {
+	return &Scope{K8s: d.K8s, Metrics: d.Metrics, Alerts: d.Alerts}
+}
+
+// RequestScope 组合 ClusterSelector 解析出的集群客户端与全局默认值，注入请求作用域。
+// 需放在认证与 ClusterSelector 之后；按用户身份构造客户端时，WebSocket 请求的用户由票据解析。
+func RequestScope(defaults ScopeDefaults, authClient *auth.Client) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scope := defaults.Scope()
+		scope.Cluster = GetClusterName(c)
+		if client := GetClusterClient(c); client != nil {
+			scope.K8s = client
+		}
+		if client := GetMetricsClient(c); client != nil {
+			scope.Metrics = client
+		}
+		if client := GetAlertClient(c); client != nil {
+			scope.Alerts = client
+		}
+
+		user := GetCurrentUser(c)
+		if user == nil && authClient != nil {
+			if ticket := GetWSTicket(c); ticket != nil {
+				if ticketUser, err := authClient.GetUserByID(ticket.UserID); err == nil {
+					user = ticketUser
+				}
+			}
+		}
+
+		if defaults.UserClients != nil && user != nil {
+			client, err := userK8sClient(defaults.UserClients, authClient, scope.K8s, user)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "构造用户 Kubernetes 客户端失败: " + err.Error()})
+				c.Abort()
+				return
+			}
+			scope.K8s = client
+		}
+
+		c.Set(ContextScopeKey, scope)
+		c.Next()
+	}
+}
+
+// userK8sClient 以当前用户身份包装集群客户端；token 模式下从数据库读取用户的 ServiceAccount Token
+func userK8sClient(clients *k8s.UserClients, authClient *auth.Client, base *k8s.Client, user *auth.User) (*k8s.Client, error) {
+	identity := k8s.UserIdentity{
+		ID:             user.ID,
+		Username:       user.Username,
+		Role:           user.Role,
+		ServiceAccount: user.ServiceAccount,
+		SANamespace:    user.SANamespace,
+	}
+	if clients.Mode() == k8s.UserAuthToken && authClient != nil {
+		token, err := authClient.GetServiceAccountToken(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		identity.SAToken = token
+	}
+	return clients.ForUser(base, identity)
+}
+
+// GetScope 从上下文读取请求作用域，未经过 RequestScope 中间件时返回 nil。
+func GetScope(c *gin.Context) *Scope {
+	value, ok := c.Get(ContextScopeKey)
+	if !ok {
+		return nil
+	}
+	scope, _ := value.(*Scope)
+	return scope
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// API 版本前缀。v2 与 v1 接口相同但不再提供旧的单数形式 /namespace/:ns 别名；
+// v1 保留兼容，响应带弃用标记
+const (
+	APIPrefixV1 = "/api/v1"
+	APIPrefixV2 = "/api/v2"
+)
+
+// CanonicalPath 把各版本的接口路径统一为 /api/v1 形式，
+// 使按路径判断的角色、命名空间授权、审批、审计与集群选择规则对所有版本一致
+func CanonicalPath(path string) string {
+	if rest, ok := strings.CutPrefix(path, APIPrefixV2); ok && (rest == "" || rest[0] == '/') {
+		return APIPrefixV1 + rest
+	}
+	return path
+}
+
+// SuccessorPath 返回旧版本接口在 successorPrefix 版本下的对应路径，单数形式的 /namespace/ 别名改为 /namespaces/
+func SuccessorPath(path, successorPrefix string) string {
+	rest := strings.TrimPrefix(CanonicalPath(path), APIPrefixV1)
+	if legacy, ok := strings.CutPrefix(rest, "/namespace/"); ok {
+		rest = "/namespaces/" + legacy
+	}
+	return successorPrefix + rest
+}
+
+// Deprecated 标记已弃用版本的接口：响应带 Deprecation 头与指向 successorPrefix 下对应接口的
+// Link: <...>; rel="successor-version"；sunset 非零时附带 Sunset 头告知下线时间（RFC 8594）
+func Deprecated(successorPrefix string, sunset time.Time) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Deprecation", "true")
+		if !sunset.IsZero() {
+			c.Header("Sunset", sunset.UTC().Format(http.TimeFormat))
+		}
+		c.Header("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, SuccessorPath(c.Request.URL.Path, successorPrefix)))
+		c.Next()
+	}
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCanonicalPath(t *testing.T) {
+	cases := map[string]string{
+		"/api/v2/namespaces/default/pods": "/api/v1/namespaces/default/pods",
+		"/api/v2":                         "/api/v1",
+		"/api/v1/nodes":                   "/api/v1/nodes",
+		"/api/v20/nodes":                  "/api/v20/nodes",
+		"/ws/logs":                        "/ws/logs",
+	}
+	for path, want := range cases {
+		if got := CanonicalPath(path); got != want {
+			t.Errorf("CanonicalPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+
+	// 按路径判断的规则对 v2 同样生效
+	if got := RequiredRole(http.MethodGet, "/api/v2/admin/users"); got != "admin" {
+		t.Errorf("v2 admin route requires %q, want admin", got)
+	}
+}
+
+func TestDeprecatedHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	sunset := time.Date(2027, 6, 30, 0, 0, 0, 0, time.UTC)
+	r.Use(Deprecated(APIPrefixV2, sunset))
+	r.GET("/api/v1/namespace/:ns", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/namespace/default", nil))
+	if got := rec.Header().Get("Deprecation"); got != "true" {
+		t.Errorf("Deprecation = %q", got)
+	}
+	if got := rec.Header().Get("Sunset"); got != "Wed, 30 Jun 2027 00:00:00 GMT" {
+		t.Errorf("Sunset = %q", got)
+	}
+	if got := rec.Header().Get("Link"); got != `</api/v2/namespaces/default>; rel="successor-version"` {
+		t.Errorf("Link = %q", got)
+	}
+}
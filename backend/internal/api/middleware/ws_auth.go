@@ -6,7 +6,6 @@ import (
 	"errors"
 	"net/http"
 	"net/url"
-	"os"
 	"strings"
 	"sync"
 	"time"
@@ -29,16 +28,20 @@ var (
 )
 
 type WSTicket struct {
-	Value      string
-	UserID     int64
-	Username   string
-	Action     string
-	Namespace  string
-	Name       string
-	Container  string
-	Cluster    string
-	ExpiresAt  time.Time
-	ConsumedAt *time.Time
+	Value     string
+	UserID    int64
+	Username  string
+	Action    string
+	Namespace string
+	Name      string
+	Container string
+	Cluster   string
+	SessionID string // 签发票据的登录会话，通知流据此推送会话过期提醒
+	// Role、ScopedNamespaces 为签发时的授权范围，API 令牌签发时为令牌收窄后的角色与命名空间
+	Role             string
+	ScopedNamespaces []auth.UserNamespace
+	ExpiresAt        time.Time
+	ConsumedAt       *time.Time
 }
 
 type WSTicketRequest struct {
@@ -47,6 +50,7 @@ type WSTicketRequest struct {
 	Name      string `json:"name"`
 	Container string `json:"container"`
 	Cluster   string `json:"cluster"`
+	SessionID string `json:"-"`
 }
 
 var wsTicketStore = struct {
@@ -80,7 +84,11 @@ func IssueWSTicket(user *auth.User, req WSTicketRequest) (*WSTicket, error) {
 		Name:      req.Name,
 		Container: req.Container,
 		Cluster:   req.Cluster,
+		SessionID: req.SessionID,
+		Role:      user.Role,
 		ExpiresAt: time.Now().Add(wsTicketTTL),
+		// 令牌限定的命名空间随票据保存，WS 链路按票据恢复授权范围
+		ScopedNamespaces: user.ScopedNamespaces,
 	}
 
 	wsTicketStore.mu.Lock()
@@ -122,18 +130,25 @@ func ConsumeWSTicket(value string) (*WSTicket, error) {
 	return ticket, nil
 }
 
+// WSAuthConfig WebSocket 认证配置
+type WSAuthConfig struct {
+	// AllowedOrigins 允许的 Origin（WS_ALLOWED_ORIGINS），为空时只允许与请求同源
+	AllowedOrigins []string
+	// AllowQueryToken 兼容开关（WS_ALLOW_QUERY_TOKEN）：允许 token=JWT 的旧链路（仅应急）
+	AllowQueryToken bool
+}
+
 // WSAuthMiddleware 统一校验 WS 票据、Origin，并把票据上下文注入请求。
-// 兼容开关：WS_ALLOW_QUERY_TOKEN=true 时允许 token=JWT 的旧链路（仅应急）。
-func WSAuthMiddleware(authClient *auth.Client) gin.HandlerFunc {
+func WSAuthMiddleware(authClient *auth.Client, cfg WSAuthConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if err := validateOrigin(c); err != nil {
+		if err := validateOrigin(c, cfg.AllowedOrigins); err != nil {
 			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 			c.Abort()
 			return
 		}
 
 		ticketValue := c.Query("ticket")
-		if ticketValue == "" && allowQueryTokenCompat() {
+		if ticketValue == "" && cfg.AllowQueryToken {
 			token := c.Query("token")
 			if token != "" && authClient != nil {
 				user, err := authClient.ValidateToken(token)
@@ -191,6 +206,10 @@ func actionFromWSPath(path string) string {
 		return "logs"
 	case strings.HasSuffix(path, "/watch"):
 		return "watch"
+	case strings.HasSuffix(path, "/events"):
+		return "events"
+	case strings.HasSuffix(path, "/notifications"):
+		return "notifications"
 	default:
 		return defaultWSAction
 	}
@@ -214,14 +233,13 @@ func ticketMatchesCluster(c *gin.Context, ticket *WSTicket) bool {
 	return ticket.Cluster == cluster
 }
 
-func validateOrigin(c *gin.Context) error {
+func validateOrigin(c *gin.Context, allowedOrigins []string) error {
 	origin := strings.TrimSpace(c.GetHeader("Origin"))
 	if origin == "" {
 		return errOriginDenied
 	}
 
-	allowedEnv := strings.TrimSpace(os.Getenv("WS_ALLOWED_ORIGINS"))
-	if allowedEnv == "" {
+	if len(allowedOrigins) == 0 {
 		u, err := url.Parse(origin)
 		if err != nil {
 			return errOriginDenied
@@ -229,7 +247,7 @@ func validateOrigin(c *gin.Context) error {
 		return sameHost(c.Request.Host, u.Host)
 	}
 
-	for _, item := range strings.Split(allowedEnv, ",") {
+	for _, item := range allowedOrigins {
 		allowed := strings.TrimSpace(item)
 		if allowed == "" {
 			continue
@@ -258,11 +276,6 @@ func stripDefaultPort(host string) string {
 	return host
 }
 
-func allowQueryTokenCompat() bool {
-	v := strings.TrimSpace(strings.ToLower(os.Getenv("WS_ALLOW_QUERY_TOKEN")))
-	return v == "1" || v == "true" || v == "yes" || v == "on"
-}
-
 func cleanupExpiredTicketsLocked(now time.Time) {
 	for key, ticket := range wsTicketStore.tickets {
 		if ticket == nil || now.After(ticket.ExpiresAt.Add(5*time.Minute)) {
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/k8s-dashboard/backend/internal/api/handlers"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
+	"github.com/k8s-dashboard/backend/internal/openapi"
+)
+
+// openAPIHandler 首次请求时根据路由表生成 version 的文档并缓存，路由注册完成后不再变化
+func openAPIHandler(r *gin.Engine, version apiVersion) gin.HandlerFunc {
+	var (
+		once sync.Once
+		body []byte
+		err  error
+	)
+	return func(c *gin.Context) {
+		once.Do(func() {
+			body, err = json.Marshal(buildOpenAPI(r.Routes(), version))
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "生成 OpenAPI 文档失败: " + err.Error()})
+			return
+		}
+		c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+	}
+}
+
+// buildOpenAPI 为 version 下的全部路由生成文档，handlers.OpenAPIRoutes 中登记了请求与响应结构体的接口带完整 schema；
+// 说明以 /api/v1 路径登记，其它版本按前缀替换
+func buildOpenAPI(routes gin.RoutesInfo, version apiVersion) *openapi.Document {
+	docs := handlers.OpenAPIRoutes()
+	if version.prefix != middleware.APIPrefixV1 {
+		versioned := make(map[string]openapi.Route, len(docs))
+		for key, route := range docs {
+			method, path, _ := strings.Cut(key, " ")
+			versioned[method+" "+version.prefix+strings.TrimPrefix(path, middleware.APIPrefixV1)] = route
+		}
+		docs = versioned
+	}
+	return openapi.Build(routes, openapi.Options{
+		Info: openapi.Info{
+			Title:       "K8s Dashboard API",
+			Description: "除登录、刷新令牌、登出与本文档外均需 Bearer 认证，可通过 X-Cluster 请求头选择集群",
+			Version:     strings.TrimPrefix(version.prefix, "/api/"),
+		},
+		Prefix:     version.prefix,
+		Routes:     docs,
+		Error:      handlers.ErrorResponse{},
+		Deprecated: version.deprecated,
+	})
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"log"
 	"net/http"
 	"time"
 
@@ -13,325 +14,607 @@ import (
 	"github.com/k8s-dashboard/backend/internal/audit"
 	"github.com/k8s-dashboard/backend/internal/auth"
 	"github.com/k8s-dashboard/backend/internal/clusters"
+	"github.com/k8s-dashboard/backend/internal/cost"
+	"github.com/k8s-dashboard/backend/internal/eventstore"
 	"github.com/k8s-dashboard/backend/internal/k8s"
 	"github.com/k8s-dashboard/backend/internal/metrics"
+	"github.com/k8s-dashboard/backend/internal/notifications"
+	"github.com/k8s-dashboard/backend/internal/notify"
 	"github.com/k8s-dashboard/backend/internal/observation"
+	"github.com/k8s-dashboard/backend/internal/panels"
+	"github.com/k8s-dashboard/backend/internal/promql"
+	"github.com/k8s-dashboard/backend/internal/ratelimit"
+	"github.com/k8s-dashboard/backend/internal/recommendations"
+	"github.com/k8s-dashboard/backend/internal/runbooks"
+	"github.com/k8s-dashboard/backend/internal/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
+// Options 路由与处理器的启动配置，由 config.Config 传入
+type Options struct {
+	// AlertWebhookToken Alertmanager Webhook 的 Bearer 令牌，为空时不启用接收接口
+	AlertWebhookToken string
+	// WSAuth WebSocket 的 Origin 校验与旧版 token 兼容开关
+	WSAuth middleware.WSAuthConfig
+	// Handler 处理器配置（WebSocket 会话限制、抓包镜像等）
+	Handler handlers.Options
+	// V1Sunset 已弃用的 v1 接口的下线日期，非零时响应附带 Sunset 头
+	V1Sunset time.Time
+	// TrustedProxies 可信反向代理的 IP 或 CIDR，为空时不信任 X-Forwarded-For，按连接地址识别客户端
+	TrustedProxies []string
+}
+
 // NewRouter 创建 HTTP 路由
-func NewRouter(k8sClient *k8s.Client, clusterManager *clusters.Manager, metricsClient *metrics.Client, alertClient *alertmanager.Client, alertService *alerts.Service, auditClient *audit.Client, authClient *auth.Client) *gin.Engine {
+func NewRouter(k8sClient *k8s.Client, clusterManager *clusters.Manager, metricsClient *metrics.Client, alertClient *alertmanager.Client, alertService *alerts.Service, auditClient *audit.Client, authClient *auth.Client, panelService *panels.Service, runbookService *runbooks.Service, eventRepo *eventstore.Repository, notifyHub *notify.Hub, userClients *k8s.UserClients, recommendationService *recommendations.Service, costService *cost.Service, channelService *notifications.Service, thresholdRepo *observation.ThresholdRepository, rateLimiter *ratelimit.Limiter, queryPolicy *promql.Policy, opts Options) *gin.Engine {
 	gin.SetMode(gin.ReleaseMode)
 
 	r := gin.New()
+	// 限流与审计按客户端 IP 区分，只接受可信代理转发的 X-Forwarded-For，防止伪造请求头绕过 IP 限流
+	if err := r.SetTrustedProxies(opts.TrustedProxies); err != nil {
+		log.Printf("Warning: 可信代理配置无效，改用连接地址识别客户端: %v", err)
+		r.SetTrustedProxies(nil)
+	}
 
 	// 中间件
 	r.Use(gin.Recovery())
+	r.Use(otelgin.Middleware(tracing.ServiceName, otelgin.WithFilter(func(req *http.Request) bool {
+		switch req.URL.Path {
+		case "/health", "/healthz", "/readyz":
+			return false
+		}
+		return true
+	})))
 	r.Use(middleware.Logger())
+	r.Use(middleware.RateLimitByIP(rateLimiter))
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Cluster"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Cluster", "If-None-Match"},
+		ExposeHeaders:    []string{"Content-Length", "X-Log-Match-Count", "X-Log-Scanned-Lines", "X-Log-Pod-Count", "X-Log-Truncated-Pods", "X-Cluster", "Deprecation", "Sunset", "Link", "Retry-After", "ETag"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
 
+	// 响应压缩
+	r.Use(middleware.Gzip())
+
 	// 审计日志中间件
 	r.Use(middleware.AuditMiddleware(auditClient))
 
-	// 健康检查
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "ok"})
-	})
+	// 健康检查：/healthz 存活探针，/readyz 就绪探针（含依赖状态），/health 保留兼容
+	healthHandler := handlers.NewHealthHandler(k8sClient, metricsClient, alertClient, authClient)
+	r.GET("/health", healthHandler.Healthz)
+	r.GET("/healthz", healthHandler.Healthz)
+	r.GET("/readyz", healthHandler.Readyz)
+
+	// 请求作用域的默认客户端，集群未单独配置时使用
+	scopeDefaults := middleware.ScopeDefaults{K8s: k8sClient, Metrics: metricsClient, Alerts: alertClient, UserClients: userClients}
 
 	// 创建处理器
-	h := handlers.NewHandler(k8sClient, clusterManager, metricsClient, alertClient, alertService, auditClient, authClient)
+	h := handlers.NewHandler(k8sClient, clusterManager, metricsClient, alertClient, alertService, auditClient, authClient, opts.Handler)
 	authHandler := handlers.NewAuthHandler(authClient)
 
 	// 创建观测服务和处理器
-	observationService := observation.NewService(k8sClient, metricsClient, alertClient)
+	observationService := observation.NewService(k8sClient, metricsClient, alertClient).WithThresholds(thresholdRepo)
 	observationHandler := handlers.NewObservationHandler(observationService)
+	panelHandler := handlers.NewPanelHandler(h, panelService)
+	runbookHandler := handlers.NewRunbookHandler(h, runbookService)
+	eventHistoryHandler := handlers.NewEventHistoryHandler(h, eventRepo)
+	notificationHandler := handlers.NewNotificationHandler(h, notifyHub)
+	recommendationHandler := handlers.NewRecommendationHandler(h, recommendationService)
+	costHandler := handlers.NewCostHandler(h, costService)
+	metricsQueryHandler := handlers.NewMetricsQueryHandler(h, queryPolicy)
+	alertWebhookHandler := handlers.NewAlertWebhookHandler(h, opts.AlertWebhookToken)
+	channelHandler := handlers.NewNotificationChannelHandler(channelService)
+	alertRouteHandler := handlers.NewAlertRouteHandler(h, channelService)
+
+	// ========== REST API（各版本接口相同，v1 已弃用）==========
+	hs := &apiHandlers{
+		h:              h,
+		auth:           authHandler,
+		observation:    observationHandler,
+		panel:          panelHandler,
+		runbook:        runbookHandler,
+		eventHistory:   eventHistoryHandler,
+		recommendation: recommendationHandler,
+		cost:           costHandler,
+		metricsQuery:   metricsQueryHandler,
+		alertWebhook:   alertWebhookHandler,
+		channel:        channelHandler,
+		alertRoute:     alertRouteHandler,
+	}
+	for _, version := range apiVersions {
+		registerAPI(r, version, hs, clusterManager, authClient, scopeDefaults, rateLimiter, opts.V1Sunset)
+	}
+
+	// WebSocket 路由
+	ws := r.Group("/ws")
+	ws.Use(middleware.ClusterSelector(clusterManager))
+	ws.Use(middleware.WSAuthMiddleware(authClient, opts.WSAuth))
+	ws.Use(middleware.RequestScope(scopeDefaults, authClient))
+	ws.Use(middleware.RateLimitByUser(rateLimiter))
+	{
+		ws.GET("/logs", middleware.LimitExpensive(rateLimiter), h.StreamPodLogs)
+		ws.GET("/exec", h.ExecPod)
+		ws.GET("/watch", h.WatchResources)
+		ws.GET("/events", h.StreamEvents)
+		ws.GET("/notifications", notificationHandler.StreamNotifications)
+	}
+
+	// 静态文件服务（前端）
+	r.Static("/assets", "./frontend/dist/assets")
+	r.StaticFile("/", "./frontend/dist/index.html")
+	r.NoRoute(func(c *gin.Context) {
+		c.File("./frontend/dist/index.html")
+	})
+
+	return r
+}
+
+// apiVersion 一个 API 版本的路由前缀与兼容选项
+type apiVersion struct {
+	prefix string
+	// deprecated 已弃用的版本，响应带 Deprecation、Link（及配置时的 Sunset）头
+	deprecated bool
+	// legacyRoutes 是否注册旧的单数形式 /namespace/:ns 别名
+	legacyRoutes bool
+}
+
+// apiVersions v1 为兼容旧客户端保留，新客户端使用 v2
+var apiVersions = []apiVersion{
+	{prefix: middleware.APIPrefixV1, deprecated: true, legacyRoutes: true},
+	{prefix: middleware.APIPrefixV2},
+}
+
+// apiHandlers 各版本共用的处理器
+type apiHandlers struct {
+	h              *handlers.Handler
+	auth           *handlers.AuthHandler
+	observation    *handlers.ObservationHandler
+	panel          *handlers.PanelHandler
+	runbook        *handlers.RunbookHandler
+	eventHistory   *handlers.EventHistoryHandler
+	recommendation *handlers.RecommendationHandler
+	cost           *handlers.CostHandler
+	metricsQuery   *handlers.MetricsQueryHandler
+	alertWebhook   *handlers.AlertWebhookHandler
+	channel        *handlers.NotificationChannelHandler
+	alertRoute     *handlers.AlertRouteHandler
+}
+
+// registerAPI 在 version.prefix 下注册公开、需认证与管理员接口
+func registerAPI(r *gin.Engine, version apiVersion, hs *apiHandlers, clusterManager *clusters.Manager, authClient *auth.Client, scopeDefaults middleware.ScopeDefaults, rateLimiter *ratelimit.Limiter, sunset time.Time) {
+	h, authHandler := hs.h, hs.auth
+	observationHandler, panelHandler, runbookHandler := hs.observation, hs.panel, hs.runbook
+	eventHistoryHandler, recommendationHandler, costHandler := hs.eventHistory, hs.recommendation, hs.cost
+	metricsQueryHandler := hs.metricsQuery
+
+	// expensive 高开销操作（全集群列表、日志、导出）的全局并发上限
+	expensive := middleware.LimitExpensive(rateLimiter)
+
+	base := r.Group(version.prefix)
+	if version.deprecated {
+		base.Use(middleware.Deprecated(middleware.APIPrefixV2, sunset))
+	}
 
 	// ========== 公开 API（不需要认证）==========
-	publicAPI := r.Group("/api/v1")
+	publicAPI := base.Group("")
 	{
 		// 登录登出
 		publicAPI.POST("/auth/login", authHandler.Login)
+		publicAPI.POST("/auth/refresh", authHandler.Refresh)
+		// 登出不经过认证中间件，访问令牌过期后仍可撤销会话
+		publicAPI.POST("/auth/logout", authHandler.Logout)
+		// OpenAPI 文档，供生成客户端
+		publicAPI.GET("/openapi.json", openAPIHandler(r, version))
+		// Alertmanager Webhook 使用独立的 Bearer 令牌（ALERTMANAGER_WEBHOOK_TOKEN）认证
+		publicAPI.POST("/integrations/alertmanager/webhook", hs.alertWebhook.ReceiveAlertmanagerWebhook)
 	}
 
 	// ========== 需要认证的 API ==========
-	v1 := r.Group("/api/v1")
-	v1.Use(middleware.AuthMiddleware(authClient))
-	v1.Use(middleware.NamespaceAccessMiddleware(authClient))
-	v1.Use(middleware.ClusterSelector(clusterManager))
-	v1.Use(middleware.AuthorizeByRoute())
+	authAPI := base.Group("")
+	authAPI.Use(middleware.AuthMiddleware(authClient))
+	authAPI.Use(middleware.RateLimitByUser(rateLimiter))
+	authAPI.Use(middleware.RequirePasswordChange())
+	authAPI.Use(middleware.NamespaceAccessMiddleware(authClient))
+	authAPI.Use(middleware.ClusterSelector(clusterManager))
+	authAPI.Use(middleware.RequestScope(scopeDefaults, authClient))
+	authAPI.Use(middleware.AuthorizeByRoute())
+	authAPI.Use(h.ApprovalGate())
 
 	{
 		// 当前用户
-		v1.GET("/auth/me", authHandler.GetCurrentUser)
-		v1.POST("/auth/logout", authHandler.Logout)
-		v1.POST("/auth/password", authHandler.ChangePassword)
-		v1.GET("/auth/sessions", authHandler.GetUserSessions)
-		v1.DELETE("/auth/sessions/:id", authHandler.RevokeSession)
-		v1.POST("/ws/tickets", h.CreateWSTicket)
+		authAPI.GET("/auth/me", authHandler.GetCurrentUser)
+		authAPI.POST("/auth/password", authHandler.ChangePassword)
+		authAPI.GET("/auth/password-policy", authHandler.GetPasswordPolicy)
+		authAPI.GET("/auth/sessions", authHandler.GetUserSessions)
+		authAPI.DELETE("/auth/sessions/:id", authHandler.RevokeSession)
+		authAPI.GET("/auth/tokens", authHandler.ListAPITokens)
+		authAPI.POST("/auth/tokens", authHandler.CreateAPIToken)
+		authAPI.DELETE("/auth/tokens/:id", authHandler.RevokeAPIToken)
+		authAPI.POST("/auth/can-i", h.CanI)
+		// 批量删除、重启与扩缩容
+		authAPI.POST("/batch", h.BatchOperations)
+		authAPI.POST("/ws/tickets", h.CreateWSTicket)
 
 		// 多集群（切换和查询对登录用户开放）
-		v1.GET("/clusters", h.ListClusters)
-		v1.GET("/clusters/:name", h.GetCluster)
-		v1.POST("/clusters/:name/switch", h.SwitchCluster)
+		authAPI.GET("/clusters", h.ListClusters)
+		authAPI.GET("/clusters/:name", h.GetCluster)
+		authAPI.POST("/clusters/:name/switch", h.SwitchCluster)
 
 		// 集群概览
-		v1.GET("/overview", h.GetOverview)
+		authAPI.GET("/overview", h.GetOverview)
+		authAPI.GET("/overview/issues", h.GetOverviewIssues)
+
+		// 全局搜索
+		authAPI.GET("/search", h.Search)
 
 		// 告警 (Alertmanager)
-		v1.GET("/alerts", h.ListAlerts)
-		v1.GET("/alerts/summary", h.GetAlertSummary)
-		v1.GET("/alerts/names", h.GetAlertNames)
-		v1.GET("/alerts/:fingerprint", h.GetAlertDetail)
-		v1.POST("/alerts/:fingerprint/acknowledge", h.AcknowledgeAlert)
-		v1.DELETE("/alerts/:fingerprint/acknowledge", h.UnacknowledgeAlert)
-		v1.GET("/alerts/:fingerprint/acknowledgement", h.GetAlertAcknowledgement)
-
-		// 静默规则
-		v1.GET("/silences", h.ListSilences)
-		v1.POST("/silences", h.CreateSilence)
-		v1.GET("/silences/:id", h.GetSilence)
-		v1.DELETE("/silences/:id", h.DeleteSilence)
+		authAPI.GET("/alerts", h.ListAlerts)
+		authAPI.GET("/alerts/summary", h.GetAlertSummary)
+		authAPI.GET("/alerts/names", h.GetAlertNames)
+		authAPI.GET("/alerts/history", h.GetAlertHistory)
+
+		// 静默规则（写入当前集群的 Alertmanager，本地记录创建者）
+		authAPI.GET("/alerts/silences", h.ListSilences)
+		authAPI.POST("/alerts/silences", h.CreateSilence)
+		authAPI.GET("/alerts/silences/:id", h.GetSilence)
+		authAPI.PUT("/alerts/silences/:id", h.UpdateSilence)
+		authAPI.DELETE("/alerts/silences/:id", h.DeleteSilence)
+
+		// 告警路由：按级别/命名空间/告警名称将告警推送到团队的通知渠道
+		authAPI.GET("/alerts/routes", hs.alertRoute.ListRoutes)
+		authAPI.POST("/alerts/routes", hs.alertRoute.CreateRoute)
+		authAPI.GET("/alerts/routes/channels", hs.alertRoute.ListRouteChannels)
+		authAPI.GET("/alerts/routes/:id", hs.alertRoute.GetRoute)
+		authAPI.PUT("/alerts/routes/:id", hs.alertRoute.UpdateRoute)
+		authAPI.DELETE("/alerts/routes/:id", hs.alertRoute.DeleteRoute)
+
+		authAPI.GET("/alerts/:fingerprint", h.GetAlertDetail)
+		authAPI.POST("/alerts/:fingerprint/ack", h.AcknowledgeAlert)
+		authAPI.DELETE("/alerts/:fingerprint/ack", h.UnacknowledgeAlert)
+		authAPI.GET("/alerts/:fingerprint/ack", h.GetAlertAcknowledgement)
+		// 旧的确认路径（已废弃，使用 /ack）
+		authAPI.POST("/alerts/:fingerprint/acknowledge", h.AcknowledgeAlert)
+		authAPI.DELETE("/alerts/:fingerprint/acknowledge", h.UnacknowledgeAlert)
+		authAPI.GET("/alerts/:fingerprint/acknowledgement", h.GetAlertAcknowledgement)
+
+		// Alertmanager 配置（只读）
+		authAPI.GET("/alertmanager/status", h.GetAlertmanagerStatus)
+		authAPI.GET("/alertmanager/receivers", h.ListAlertmanagerReceivers)
+		authAPI.GET("/alertmanager/routes", h.GetAlertmanagerRoutes)
+
+		// 旧的静默规则路径（已废弃，使用 /alerts/silences）
+		authAPI.GET("/silences", h.ListSilences)
+		authAPI.POST("/silences", h.CreateSilence)
+		authAPI.GET("/silences/:id", h.GetSilence)
+		authAPI.DELETE("/silences/:id", h.DeleteSilence)
 
 		// Namespaces
-		v1.GET("/namespaces", h.ListNamespaces)
-		v1.POST("/namespaces", h.CreateNamespace)
-		v1.GET("/namespaces/:ns", h.GetNamespace)
-		v1.DELETE("/namespaces/:ns", h.DeleteNamespace)
-		v1.GET("/namespace/:ns", func(c *gin.Context) {
-			c.Header("Deprecation", "true")
-			c.Header("Sunset", "vNext")
-			h.GetNamespace(c)
-		})
-		v1.DELETE("/namespace/:ns", func(c *gin.Context) {
-			c.Header("Deprecation", "true")
-			c.Header("Sunset", "vNext")
-			h.DeleteNamespace(c)
-		})
+		authAPI.GET("/namespaces", h.ListNamespaces)
+		authAPI.POST("/namespaces", h.CreateNamespace)
+		authAPI.GET("/namespaces/:ns", h.GetNamespace)
+		authAPI.DELETE("/namespaces/:ns", h.DeleteNamespace)
+		authAPI.GET("/namespaces/:ns/export", expensive, h.ExportNamespace)
+		authAPI.GET("/namespaces/:ns/topology", h.GetNamespaceTopology)
+		authAPI.POST("/namespaces/:ns/cleanup", h.CleanupNamespace)
+		if version.legacyRoutes {
+			// 旧的单数形式别名，仅 v1 提供
+			authAPI.GET("/namespace/:ns", h.GetNamespace)
+			authAPI.DELETE("/namespace/:ns", h.DeleteNamespace)
+			authAPI.POST("/namespace/:ns/cleanup", h.CleanupNamespace)
+		}
 
 		// Pods
-		v1.GET("/pods", h.ListAllPods)
-		v1.GET("/namespaces/:ns/pods", h.ListPods)
-		v1.GET("/namespaces/:ns/pods/:name", h.GetPod)
-		v1.DELETE("/namespaces/:ns/pods/:name", h.DeletePod)
-		v1.GET("/namespaces/:ns/pods/:name/yaml", h.GetPodYAML)
-		v1.GET("/namespaces/:ns/pods/:name/logs", h.GetPodLogs)
-		v1.GET("/namespaces/:ns/pods/:name/events", h.GetPodEvents)
+		authAPI.GET("/pods", expensive, h.ListAllPods)
+		authAPI.GET("/namespaces/:ns/pods", h.ListPods)
+		authAPI.GET("/namespaces/:ns/pods/:name", h.GetPod)
+		authAPI.DELETE("/namespaces/:ns/pods/:name", h.DeletePod)
+		authAPI.GET("/namespaces/:ns/pods/:name/yaml", h.GetPodYAML)
+		authAPI.GET("/namespaces/:ns/pods/:name/logs", expensive, h.GetPodLogs)
+		authAPI.GET("/namespaces/:ns/pods/:name/logs/download", expensive, h.DownloadPodLogs)
+		authAPI.GET("/namespaces/:ns/pods/:name/events", h.GetPodEvents)
+		authAPI.GET("/namespaces/:ns/pods/:name/files", h.DownloadPodFile)
+		authAPI.POST("/namespaces/:ns/pods/:name/captures", h.CreatePacketCapture)
+		authAPI.POST("/namespaces/:ns/pods/:name/files", h.UploadPodFile)
 
 		// Deployments
-		v1.GET("/deployments", h.ListAllDeployments)
-		v1.GET("/namespaces/:ns/deployments", h.ListDeployments)
-		v1.GET("/namespaces/:ns/deployments/:name", h.GetDeployment)
-		v1.POST("/namespaces/:ns/deployments", h.CreateDeployment)
-		v1.PUT("/namespaces/:ns/deployments/:name", h.UpdateDeployment)
-		v1.DELETE("/namespaces/:ns/deployments/:name", h.DeleteDeployment)
-		v1.GET("/namespaces/:ns/deployments/:name/yaml", h.GetDeploymentYAML)
-		v1.PUT("/namespaces/:ns/deployments/:name/yaml", h.UpdateDeploymentYAML)
-		v1.POST("/namespaces/:ns/deployments/:name/scale", h.ScaleDeployment)
-		v1.POST("/namespaces/:ns/deployments/:name/restart", h.RestartDeployment)
-		v1.POST("/namespaces/:ns/deployments/:name/rollback", h.RollbackDeployment)
-		v1.GET("/namespaces/:ns/deployments/:name/pods", h.GetDeploymentPods)
-		v1.GET("/namespaces/:ns/deployments/:name/events", h.GetDeploymentEvents)
-		v1.PUT("/namespaces/:ns/deployments/:name/strategy", h.UpdateDeploymentStrategy)
-		v1.GET("/namespaces/:ns/deployments/:name/revisions", h.GetDeploymentRevisions)
-		v1.POST("/namespaces/:ns/deployments/:name/pause", h.PauseDeployment)
-		v1.POST("/namespaces/:ns/deployments/:name/resume", h.ResumeDeployment)
-		v1.PUT("/namespaces/:ns/deployments/:name/image", h.UpdateDeploymentImage)
-		v1.PUT("/namespaces/:ns/deployments/:name/scheduling", h.UpdateDeploymentScheduling)
+		authAPI.GET("/deployments", expensive, h.ListAllDeployments)
+		authAPI.GET("/namespaces/:ns/deployments", h.ListDeployments)
+		authAPI.GET("/namespaces/:ns/deployments/:name", h.GetDeployment)
+		authAPI.POST("/namespaces/:ns/deployments", h.CreateDeployment)
+		authAPI.PUT("/namespaces/:ns/deployments/:name", h.UpdateDeployment)
+		authAPI.DELETE("/namespaces/:ns/deployments/:name", h.DeleteDeployment)
+		authAPI.GET("/namespaces/:ns/deployments/:name/logs", expensive, h.GetDeploymentLogs)
+		authAPI.GET("/namespaces/:ns/deployments/:name/yaml", h.GetDeploymentYAML)
+		authAPI.PUT("/namespaces/:ns/deployments/:name/yaml", h.UpdateDeploymentYAML)
+		authAPI.POST("/namespaces/:ns/deployments/:name/scale", h.ScaleDeployment)
+		authAPI.POST("/namespaces/:ns/deployments/:name/restart", h.RestartDeployment)
+		authAPI.POST("/namespaces/:ns/deployments/:name/rollback", h.RollbackDeployment)
+		authAPI.GET("/namespaces/:ns/deployments/:name/pods", h.GetDeploymentPods)
+		authAPI.GET("/namespaces/:ns/deployments/:name/events", h.GetDeploymentEvents)
+		authAPI.PUT("/namespaces/:ns/deployments/:name/strategy", h.UpdateDeploymentStrategy)
+		authAPI.GET("/namespaces/:ns/deployments/:name/revisions", h.GetDeploymentRevisions)
+		authAPI.POST("/namespaces/:ns/deployments/:name/pause", h.PauseDeployment)
+		authAPI.POST("/namespaces/:ns/deployments/:name/resume", h.ResumeDeployment)
+		authAPI.PUT("/namespaces/:ns/deployments/:name/image", h.UpdateDeploymentImage)
+		authAPI.PUT("/namespaces/:ns/deployments/:name/scheduling", h.UpdateDeploymentScheduling)
 
 		// StatefulSets
-		v1.GET("/statefulsets", h.ListAllStatefulSets)
-		v1.GET("/namespaces/:ns/statefulsets", h.ListStatefulSets)
-		v1.GET("/namespaces/:ns/statefulsets/:name", h.GetStatefulSet)
-		v1.DELETE("/namespaces/:ns/statefulsets/:name", h.DeleteStatefulSet)
-		v1.GET("/namespaces/:ns/statefulsets/:name/yaml", h.GetStatefulSetYAML)
-		v1.PUT("/namespaces/:ns/statefulsets/:name/yaml", h.UpdateStatefulSetYAML)
-		v1.POST("/namespaces/:ns/statefulsets/:name/scale", h.ScaleStatefulSet)
-		v1.POST("/namespaces/:ns/statefulsets/:name/restart", h.RestartStatefulSet)
-		v1.GET("/namespaces/:ns/statefulsets/:name/pods", h.GetStatefulSetPods)
-		v1.GET("/namespaces/:ns/statefulsets/:name/events", h.GetStatefulSetEvents)
-		v1.PUT("/namespaces/:ns/statefulsets/:name/strategy", h.UpdateStatefulSetStrategy)
-		v1.GET("/namespaces/:ns/statefulsets/:name/revisions", h.GetStatefulSetRevisions)
-		v1.POST("/namespaces/:ns/statefulsets/:name/rollback", h.RollbackStatefulSet)
+		authAPI.GET("/statefulsets", expensive, h.ListAllStatefulSets)
+		authAPI.GET("/namespaces/:ns/statefulsets", h.ListStatefulSets)
+		authAPI.GET("/namespaces/:ns/statefulsets/:name", h.GetStatefulSet)
+		authAPI.DELETE("/namespaces/:ns/statefulsets/:name", h.DeleteStatefulSet)
+		authAPI.GET("/namespaces/:ns/statefulsets/:name/yaml", h.GetStatefulSetYAML)
+		authAPI.PUT("/namespaces/:ns/statefulsets/:name/yaml", h.UpdateStatefulSetYAML)
+		authAPI.POST("/namespaces/:ns/statefulsets/:name/scale", h.ScaleStatefulSet)
+		authAPI.POST("/namespaces/:ns/statefulsets/:name/restart", h.RestartStatefulSet)
+		authAPI.GET("/namespaces/:ns/statefulsets/:name/pods", h.GetStatefulSetPods)
+		authAPI.GET("/namespaces/:ns/statefulsets/:name/events", h.GetStatefulSetEvents)
+		authAPI.PUT("/namespaces/:ns/statefulsets/:name/strategy", h.UpdateStatefulSetStrategy)
+		authAPI.GET("/namespaces/:ns/statefulsets/:name/revisions", h.GetStatefulSetRevisions)
+		authAPI.POST("/namespaces/:ns/statefulsets/:name/rollback", h.RollbackStatefulSet)
 
 		// DaemonSets
-		v1.GET("/daemonsets", h.ListAllDaemonSets)
-		v1.GET("/namespaces/:ns/daemonsets", h.ListDaemonSets)
-		v1.GET("/namespaces/:ns/daemonsets/:name", h.GetDaemonSet)
-		v1.DELETE("/namespaces/:ns/daemonsets/:name", h.DeleteDaemonSet)
-		v1.GET("/namespaces/:ns/daemonsets/:name/yaml", h.GetDaemonSetYAML)
-		v1.PUT("/namespaces/:ns/daemonsets/:name/yaml", h.UpdateDaemonSetYAML)
-		v1.POST("/namespaces/:ns/daemonsets/:name/restart", h.RestartDaemonSet)
-		v1.GET("/namespaces/:ns/daemonsets/:name/pods", h.GetDaemonSetPods)
-		v1.GET("/namespaces/:ns/daemonsets/:name/events", h.GetDaemonSetEvents)
-		v1.PUT("/namespaces/:ns/daemonsets/:name/strategy", h.UpdateDaemonSetStrategy)
+		authAPI.GET("/daemonsets", expensive, h.ListAllDaemonSets)
+		authAPI.GET("/namespaces/:ns/daemonsets", h.ListDaemonSets)
+		authAPI.GET("/namespaces/:ns/daemonsets/:name", h.GetDaemonSet)
+		authAPI.DELETE("/namespaces/:ns/daemonsets/:name", h.DeleteDaemonSet)
+		authAPI.GET("/namespaces/:ns/daemonsets/:name/yaml", h.GetDaemonSetYAML)
+		authAPI.PUT("/namespaces/:ns/daemonsets/:name/yaml", h.UpdateDaemonSetYAML)
+		authAPI.POST("/namespaces/:ns/daemonsets/:name/restart", h.RestartDaemonSet)
+		authAPI.GET("/namespaces/:ns/daemonsets/:name/pods", h.GetDaemonSetPods)
+		authAPI.GET("/namespaces/:ns/daemonsets/:name/events", h.GetDaemonSetEvents)
+		authAPI.PUT("/namespaces/:ns/daemonsets/:name/strategy", h.UpdateDaemonSetStrategy)
 
 		// Jobs
-		v1.GET("/jobs", h.ListAllJobs)
-		v1.GET("/namespaces/:ns/jobs", h.ListJobs)
-		v1.GET("/namespaces/:ns/jobs/:name", h.GetJob)
-		v1.DELETE("/namespaces/:ns/jobs/:name", h.DeleteJob)
+		authAPI.GET("/jobs", expensive, h.ListAllJobs)
+		authAPI.GET("/namespaces/:ns/jobs", h.ListJobs)
+		authAPI.GET("/namespaces/:ns/jobs/:name", h.GetJob)
+		authAPI.DELETE("/namespaces/:ns/jobs/:name", h.DeleteJob)
+		authAPI.POST("/namespaces/:ns/jobs/:name/rerun", h.RerunJob)
 
 		// CronJobs
-		v1.GET("/cronjobs", h.ListAllCronJobs)
-		v1.GET("/namespaces/:ns/cronjobs", h.ListCronJobs)
-		v1.GET("/namespaces/:ns/cronjobs/:name", h.GetCronJob)
-		v1.DELETE("/namespaces/:ns/cronjobs/:name", h.DeleteCronJob)
-		v1.POST("/namespaces/:ns/cronjobs/:name/trigger", h.TriggerCronJob)
+		authAPI.GET("/cronjobs", expensive, h.ListAllCronJobs)
+		authAPI.GET("/namespaces/:ns/cronjobs", h.ListCronJobs)
+		authAPI.GET("/namespaces/:ns/cronjobs/:name", h.GetCronJob)
+		authAPI.DELETE("/namespaces/:ns/cronjobs/:name", h.DeleteCronJob)
+		authAPI.POST("/namespaces/:ns/cronjobs/:name/trigger", h.TriggerCronJob)
+		authAPI.GET("/namespaces/:ns/cronjobs/:name/runs", h.ListCronJobRuns)
+		authAPI.POST("/namespaces/:ns/cronjobs/:name/suspend", h.SuspendCronJob)
+		authAPI.POST("/namespaces/:ns/cronjobs/:name/resume", h.ResumeCronJob)
 
 		// Services
-		v1.GET("/services", h.ListAllServices)
-		v1.GET("/namespaces/:ns/services", h.ListServices)
-		v1.GET("/namespaces/:ns/services/:name", h.GetService)
-		v1.POST("/namespaces/:ns/services", h.CreateService)
-		v1.PUT("/namespaces/:ns/services/:name", h.UpdateService)
-		v1.DELETE("/namespaces/:ns/services/:name", h.DeleteService)
-		v1.GET("/namespaces/:ns/services/:name/yaml", h.GetServiceYAML)
-		v1.PUT("/namespaces/:ns/services/:name/yaml", h.UpdateServiceYAML)
+		authAPI.GET("/services", expensive, h.ListAllServices)
+		authAPI.GET("/namespaces/:ns/services", h.ListServices)
+		authAPI.GET("/namespaces/:ns/services/:name", h.GetService)
+		authAPI.POST("/namespaces/:ns/services", h.CreateService)
+		authAPI.PUT("/namespaces/:ns/services/:name", h.UpdateService)
+		authAPI.DELETE("/namespaces/:ns/services/:name", h.DeleteService)
+		authAPI.POST("/namespaces/:ns/services/:name/ports", h.UpdateServicePorts)
+		authAPI.GET("/namespaces/:ns/services/:name/yaml", h.GetServiceYAML)
+		authAPI.PUT("/namespaces/:ns/services/:name/yaml", h.UpdateServiceYAML)
 
 		// Ingresses
-		v1.GET("/ingresses", h.ListAllIngresses)
-		v1.GET("/namespaces/:ns/ingresses", h.ListIngresses)
-		v1.GET("/namespaces/:ns/ingresses/:name", h.GetIngress)
-		v1.POST("/namespaces/:ns/ingresses", h.CreateIngress)
-		v1.PUT("/namespaces/:ns/ingresses/:name", h.UpdateIngress)
-		v1.DELETE("/namespaces/:ns/ingresses/:name", h.DeleteIngress)
-		v1.GET("/namespaces/:ns/ingresses/:name/yaml", h.GetIngressYAML)
-		v1.PUT("/namespaces/:ns/ingresses/:name/yaml", h.UpdateIngressYAML)
+		authAPI.GET("/ingresses", expensive, h.ListAllIngresses)
+		authAPI.GET("/namespaces/:ns/ingresses", h.ListIngresses)
+		authAPI.GET("/namespaces/:ns/ingresses/:name", h.GetIngress)
+		authAPI.POST("/namespaces/:ns/ingresses", h.CreateIngress)
+		authAPI.PUT("/namespaces/:ns/ingresses/:name", h.UpdateIngress)
+		authAPI.DELETE("/namespaces/:ns/ingresses/:name", h.DeleteIngress)
+		authAPI.GET("/namespaces/:ns/ingresses/:name/yaml", h.GetIngressYAML)
+		authAPI.PUT("/namespaces/:ns/ingresses/:name/yaml", h.UpdateIngressYAML)
 
 		// ConfigMaps
-		v1.GET("/configmaps", h.ListAllConfigMaps)
-		v1.GET("/namespaces/:ns/configmaps", h.ListConfigMaps)
-		v1.GET("/namespaces/:ns/configmaps/:name", h.GetConfigMap)
-		v1.POST("/namespaces/:ns/configmaps", h.CreateConfigMap)
-		v1.PUT("/namespaces/:ns/configmaps/:name", h.UpdateConfigMap)
-		v1.DELETE("/namespaces/:ns/configmaps/:name", h.DeleteConfigMap)
-		v1.GET("/namespaces/:ns/configmaps/:name/yaml", h.GetConfigMapYAML)
-		v1.PUT("/namespaces/:ns/configmaps/:name/yaml", h.UpdateConfigMapYAML)
+		authAPI.GET("/configmaps", expensive, h.ListAllConfigMaps)
+		authAPI.GET("/namespaces/:ns/configmaps", h.ListConfigMaps)
+		authAPI.GET("/namespaces/:ns/configmaps/:name", h.GetConfigMap)
+		authAPI.POST("/namespaces/:ns/configmaps", h.CreateConfigMap)
+		authAPI.PUT("/namespaces/:ns/configmaps/:name", h.UpdateConfigMap)
+		authAPI.DELETE("/namespaces/:ns/configmaps/:name", h.DeleteConfigMap)
+		authAPI.GET("/namespaces/:ns/configmaps/:name/usage", h.GetConfigMapUsage)
+		authAPI.GET("/namespaces/:ns/configmaps/:name/yaml", h.GetConfigMapYAML)
+		authAPI.PUT("/namespaces/:ns/configmaps/:name/yaml", h.UpdateConfigMapYAML)
 
 		// Secrets
-		v1.GET("/secrets", h.ListAllSecrets)
-		v1.GET("/namespaces/:ns/secrets", h.ListSecrets)
-		v1.GET("/namespaces/:ns/secrets/:name", h.GetSecret)
-		v1.POST("/namespaces/:ns/secrets", h.CreateSecret)
-		v1.PUT("/namespaces/:ns/secrets/:name", h.UpdateSecret)
-		v1.DELETE("/namespaces/:ns/secrets/:name", h.DeleteSecret)
-		v1.GET("/namespaces/:ns/secrets/:name/yaml", h.GetSecretYAML)
-		v1.PUT("/namespaces/:ns/secrets/:name/yaml", h.UpdateSecretYAML)
+		authAPI.GET("/secrets", expensive, h.ListAllSecrets)
+		authAPI.GET("/namespaces/:ns/secrets", h.ListSecrets)
+		authAPI.GET("/namespaces/:ns/secrets/:name", h.GetSecret)
+		authAPI.POST("/namespaces/:ns/secrets", h.CreateSecret)
+		authAPI.PUT("/namespaces/:ns/secrets/:name", h.UpdateSecret)
+		authAPI.DELETE("/namespaces/:ns/secrets/:name", h.DeleteSecret)
+		authAPI.GET("/namespaces/:ns/secrets/:name/reveal", h.RevealSecret)
+		authAPI.GET("/namespaces/:ns/secrets/:name/usage", h.GetSecretUsage)
+		authAPI.GET("/namespaces/:ns/secrets/:name/yaml", h.GetSecretYAML)
+		authAPI.PUT("/namespaces/:ns/secrets/:name/yaml", h.UpdateSecretYAML)
 
 		// PersistentVolumes
-		v1.GET("/persistentvolumes", h.ListPersistentVolumes)
-		v1.GET("/persistentvolumes/:name", h.GetPersistentVolume)
-		v1.DELETE("/persistentvolumes/:name", h.DeletePersistentVolume)
+		authAPI.GET("/persistentvolumes", h.ListPersistentVolumes)
+		authAPI.GET("/persistentvolumes/:name", h.GetPersistentVolume)
+		authAPI.DELETE("/persistentvolumes/:name", h.DeletePersistentVolume)
 
 		// PersistentVolumeClaims
-		v1.GET("/persistentvolumeclaims", h.ListAllPersistentVolumeClaims)
-		v1.GET("/namespaces/:ns/persistentvolumeclaims", h.ListPersistentVolumeClaims)
-		v1.GET("/namespaces/:ns/persistentvolumeclaims/:name", h.GetPersistentVolumeClaim)
-		v1.DELETE("/namespaces/:ns/persistentvolumeclaims/:name", h.DeletePersistentVolumeClaim)
+		authAPI.GET("/persistentvolumeclaims", expensive, h.ListAllPersistentVolumeClaims)
+		authAPI.GET("/namespaces/:ns/persistentvolumeclaims", h.ListPersistentVolumeClaims)
+		authAPI.GET("/namespaces/:ns/persistentvolumeclaims/:name", h.GetPersistentVolumeClaim)
+		authAPI.POST("/namespaces/:ns/persistentvolumeclaims", h.CreatePersistentVolumeClaim)
+		authAPI.POST("/namespaces/:ns/persistentvolumeclaims/:name/expand", h.ExpandPersistentVolumeClaim)
+		authAPI.DELETE("/namespaces/:ns/persistentvolumeclaims/:name", h.DeletePersistentVolumeClaim)
 
 		// StorageClasses
-		v1.GET("/storageclasses", h.ListStorageClasses)
-		v1.GET("/storageclasses/:name", h.GetStorageClass)
+		authAPI.GET("/storageclasses", h.ListStorageClasses)
+		authAPI.GET("/storageclasses/:name", h.GetStorageClass)
+		authAPI.POST("/storageclasses", h.CreateStorageClass)
+		authAPI.DELETE("/storageclasses/:name", h.DeleteStorageClass)
+		authAPI.POST("/storageclasses/:name/set-default", h.SetDefaultStorageClass)
 
 		// Nodes
-		v1.GET("/nodes", h.ListNodes)
-		v1.GET("/nodes/:name", h.GetNode)
-		v1.GET("/nodes/:name/yaml", h.GetNodeYAML)
-		v1.GET("/nodes/:name/metrics", h.GetNodeMetrics)
-		v1.GET("/nodes/:name/pods", h.GetNodePods)
-		v1.POST("/nodes/:name/cordon", h.CordonNode)
-		v1.POST("/nodes/:name/uncordon", h.UncordonNode)
-		v1.POST("/nodes/:name/drain", h.DrainNode)
+		authAPI.GET("/nodepools", h.ListNodePools)
+		authAPI.GET("/nodes", h.ListNodes)
+		authAPI.GET("/nodes/:name", h.GetNode)
+		authAPI.GET("/nodes/:name/yaml", h.GetNodeYAML)
+		authAPI.GET("/nodes/:name/metrics", h.GetNodeMetrics)
+		authAPI.GET("/nodes/:name/pods", h.GetNodePods)
+		authAPI.POST("/nodes/:name/cordon", h.CordonNode)
+		authAPI.POST("/nodes/:name/uncordon", h.UncordonNode)
+		authAPI.POST("/nodes/:name/drain", h.DrainNode)
+		authAPI.GET("/nodes/:name/rebalance-suggestions", h.GetNodeRebalanceSuggestions)
+		authAPI.POST("/nodes/:name/rebalance", h.RebalanceNode)
 
 		// Events
-		v1.GET("/events", h.ListAllEvents)
-		v1.GET("/namespaces/:ns/events", h.ListEvents)
+		authAPI.GET("/events", expensive, h.ListAllEvents)
+		authAPI.GET("/events/history", eventHistoryHandler.GetEventHistory)
+		authAPI.GET("/namespaces/:ns/events", h.ListEvents)
 
 		// RBAC
-		v1.GET("/namespaces/:ns/roles", h.ListRoles)
-		v1.GET("/clusterroles", h.ListClusterRoles)
-		v1.GET("/namespaces/:ns/rolebindings", h.ListRoleBindings)
-		v1.GET("/clusterrolebindings", h.ListClusterRoleBindings)
-		v1.GET("/serviceaccounts", h.ListAllServiceAccounts)
-		v1.GET("/namespaces/:ns/serviceaccounts", h.ListServiceAccounts)
+		authAPI.GET("/namespaces/:ns/roles", h.ListRoles)
+		authAPI.GET("/clusterroles", h.ListClusterRoles)
+		authAPI.GET("/namespaces/:ns/rolebindings", h.ListRoleBindings)
+		authAPI.GET("/clusterrolebindings", h.ListClusterRoleBindings)
+		authAPI.GET("/serviceaccounts", expensive, h.ListAllServiceAccounts)
+		authAPI.GET("/namespaces/:ns/serviceaccounts", h.ListServiceAccounts)
 
 		// Metrics (VictoriaMetrics)
-		v1.GET("/metrics/cluster", h.GetClusterMetrics)
-		v1.GET("/metrics/history/cpu", h.GetCPUHistory)
-		v1.GET("/metrics/history/memory", h.GetMemoryHistory)
-		v1.GET("/metrics/nodes/:name", h.GetNodeMetricsVM)
-		v1.GET("/metrics/pods", h.ListAllPodMetricsVM)
-		v1.GET("/metrics/pods/:ns/:name", h.GetPodMetricsVM)
+		authAPI.GET("/metrics/cluster", h.GetClusterMetrics)
+		authAPI.GET("/metrics/history/cpu", h.GetCPUHistory)
+		authAPI.GET("/metrics/history/memory", h.GetMemoryHistory)
+		authAPI.GET("/metrics/nodes/:name", h.GetNodeMetricsVM)
+		authAPI.GET("/metrics/nodes/:name/history", h.GetNodeHistory)
+		authAPI.GET("/metrics/pods", expensive, h.ListAllPodMetricsVM)
+		authAPI.GET("/metrics/pvc", h.GetPVCMetrics)
+		authAPI.GET("/metrics/gpu", h.GetGPUMetrics)
+		authAPI.POST("/metrics/query", expensive, metricsQueryHandler.Query)
+		authAPI.GET("/metrics/top", h.GetTopConsumers)
+		authAPI.GET("/metrics/network/pods", h.GetPodNetworkMetrics)
+		authAPI.GET("/metrics/network/namespaces", h.GetNamespaceNetworkMetrics)
+		authAPI.GET("/metrics/pods/:ns/:name", h.GetPodMetricsVM)
+		authAPI.GET("/metrics/pods/:ns/:name/containers", h.GetPodContainerMetrics)
+		authAPI.GET("/metrics/pods/:ns/:name/history", h.GetPodHistory)
+
+		// 告警/记录规则：评估状态来自 Prometheus /api/v1/rules，定义来自 PrometheusRule / VMRule
+		authAPI.GET("/rules", h.GetRuleGroups)
+		authAPI.GET("/rules/resources", h.ListRuleResources)
+		authAPI.GET("/rules/resources/:resource/:ns/:name", h.GetRuleResource)
+
+		// 容量规划：节点池可分配/已申请/实际用量与耗尽预测
+		authAPI.GET("/capacity", h.GetCapacity)
+
+		// 容器资源建议（基于历史用量分位数）
+		authAPI.GET("/recommendations/resources", recommendationHandler.GetResourceRecommendations)
+
+		// 费用估算（按 requests 与实际用量中的较大者计费）
+		authAPI.GET("/cost/namespaces", costHandler.GetNamespaceCosts)
+		authAPI.GET("/cost/workloads", costHandler.GetWorkloadCosts)
 
 		// 审计日志
-		v1.GET("/audit", h.ListAuditLogs)
-		v1.GET("/audit/stats", h.GetAuditStats)
+		authAPI.GET("/audit", h.ListAuditLogs)
+		authAPI.GET("/audit/stats", h.GetAuditStats)
+		authAPI.GET("/audit/storage", h.GetAuditStorage)
+		authAPI.GET("/audit/anomalies", h.ListAuditAnomalies)
+		authAPI.GET("/audit/export", h.ExportAuditLogs)
+		authAPI.GET("/audit/exports", h.ListAuditExports)
+		authAPI.GET("/audit/exports/:id", h.GetAuditExport)
+		authAPI.GET("/audit/exports/:id/download", h.DownloadAuditExport)
+		authAPI.GET("/audit/terminal-sessions", h.ListTerminalSessions)
+		authAPI.GET("/audit/terminal-sessions/:id/replay", h.GetTerminalSessionReplay)
+
+		// 抓包记录
+		authAPI.GET("/packet-captures", h.ListPacketCaptures)
+		authAPI.GET("/packet-captures/:id", h.GetPacketCapture)
+		authAPI.GET("/packet-captures/:id/download", h.DownloadPacketCapture)
 
 		// 集群观测
-		v1.GET("/observation/summary", observationHandler.GetObservationSummary)
-		v1.GET("/observation/pods/anomaly", observationHandler.GetPodAnomalies)
-		v1.GET("/observation/nodes/anomaly", observationHandler.GetNodeAnomalies)
-		v1.GET("/observation/resources/excess", observationHandler.GetResourceExcess)
-		v1.GET("/observation/trends/resource", observationHandler.GetResourceTrend)
-		v1.GET("/observation/trends/alerts", observationHandler.GetAlertTrend)
-		v1.GET("/observation/trends/restarts", observationHandler.GetRestartTrend)
+		authAPI.GET("/observation/summary", observationHandler.GetObservationSummary)
+		authAPI.GET("/observation/pods/anomaly", observationHandler.GetPodAnomalies)
+		authAPI.GET("/observation/nodes/anomaly", observationHandler.GetNodeAnomalies)
+		authAPI.GET("/observation/resources/excess", observationHandler.GetResourceExcess)
+		authAPI.GET("/observation/trends/resource", observationHandler.GetResourceTrend)
+		authAPI.GET("/observation/trends/alerts", observationHandler.GetAlertTrend)
+		authAPI.GET("/observation/trends/restarts", observationHandler.GetRestartTrend)
+
+		// 自定义监控面板
+		authAPI.GET("/panels", panelHandler.ListPanels)
+		authAPI.POST("/panels", panelHandler.CreatePanel)
+		authAPI.GET("/panels/:id", panelHandler.GetPanel)
+		authAPI.PUT("/panels/:id", panelHandler.UpdatePanel)
+		authAPI.DELETE("/panels/:id", panelHandler.DeletePanel)
+		authAPI.GET("/panels/:id/data", panelHandler.GetPanelData)
+
+		// 运行手册（参数化 Job 模板）
+		authAPI.GET("/runbooks", runbookHandler.ListRunbooks)
+		authAPI.GET("/runbooks/runs", runbookHandler.ListRunbookRuns)
+		authAPI.GET("/runbooks/runs/:id", runbookHandler.GetRunbookRun)
+		authAPI.GET("/runbooks/runs/:id/logs", runbookHandler.GetRunbookRunLogs)
+		authAPI.GET("/runbooks/:name", runbookHandler.GetRunbook)
+		authAPI.POST("/runbooks/:name/run", runbookHandler.RunRunbook)
 
 		// 审批管理
-		v1.GET("/approvals", authHandler.ListApprovals)
-		v1.GET("/approvals/pending/count", authHandler.GetPendingCount)
-		v1.GET("/approvals/:id", authHandler.GetApproval)
-		v1.POST("/approvals/:id/approve", authHandler.ApproveRequest)
-		v1.POST("/approvals/:id/reject", authHandler.RejectRequest)
+		authAPI.GET("/approvals", authHandler.ListApprovals)
+		authAPI.POST("/approvals", h.CreateApproval)
+		authAPI.GET("/approvals/pending/count", authHandler.GetPendingCount)
+		authAPI.GET("/approvals/:id", authHandler.GetApproval)
+		authAPI.POST("/approvals/:id/approve", h.ApproveRequest)
+		authAPI.POST("/approvals/:id/reject", authHandler.RejectRequest)
 	}
 
-	clusterAdmin := v1.Group("/clusters")
+	clusterAdmin := authAPI.Group("/clusters")
 	clusterAdmin.Use(middleware.RequireRole("admin"))
 	{
 		clusterAdmin.POST("", h.AddCluster)
 		clusterAdmin.POST("/test", h.TestCluster)
+		clusterAdmin.POST("/contexts", h.ListKubeconfigContexts)
+		clusterAdmin.PUT("/:name", h.UpdateCluster)
+		clusterAdmin.PUT("/:name/endpoints", h.UpdateClusterEndpoints)
 		clusterAdmin.DELETE("/:name", h.DeleteCluster)
 	}
 
+	ruleAdmin := authAPI.Group("/rules/resources")
+	ruleAdmin.Use(middleware.RequireRole("admin"))
+	{
+		ruleAdmin.PUT("/:resource/:ns/:name", h.UpdateRuleResource)
+	}
+
 	// ========== 管理员 API（需要 admin 角色）==========
-	adminAPI := r.Group("/api/v1/admin")
+	adminAPI := base.Group("/admin")
 	adminAPI.Use(middleware.AuthMiddleware(authClient))
+	adminAPI.Use(middleware.RateLimitByUser(rateLimiter))
 	adminAPI.Use(middleware.RequireRole("admin"))
 	{
 		// 用户管理
 		adminAPI.GET("/users", authHandler.ListUsers)
 		adminAPI.POST("/users", authHandler.CreateUser)
+		adminAPI.GET("/users/export", authHandler.ExportUsers)
+		adminAPI.POST("/users/import", authHandler.ImportUsers)
 		adminAPI.GET("/users/:id", authHandler.GetUser)
 		adminAPI.PUT("/users/:id", authHandler.UpdateUser)
 		adminAPI.DELETE("/users/:id", authHandler.DeleteUser)
 		adminAPI.POST("/users/:id/reset-password", authHandler.ResetPassword)
+		adminAPI.DELETE("/users/:id/sessions", authHandler.ForceLogoutUser)
+
+		// 会话管理（应急处置）
+		adminAPI.GET("/sessions", authHandler.ListAllSessions)
+		adminAPI.DELETE("/sessions/:id", authHandler.AdminRevokeSession)
 
 		// 审批规则
 		adminAPI.GET("/approval-rules", authHandler.ListApprovalRules)
 		adminAPI.PUT("/approval-rules/:id", authHandler.UpdateApprovalRule)
-	}
 
-	// WebSocket 路由
-	ws := r.Group("/ws")
-	ws.Use(middleware.ClusterSelector(clusterManager))
-	ws.Use(middleware.WSAuthMiddleware(authClient))
-	{
-		ws.GET("/logs", h.StreamPodLogs)
-		ws.GET("/exec", h.ExecPod)
-		ws.GET("/watch", h.WatchResources)
+		// 运行手册管理
+		adminAPI.POST("/runbooks", runbookHandler.CreateRunbook)
+		adminAPI.PUT("/runbooks/:name", runbookHandler.UpdateRunbook)
+		adminAPI.DELETE("/runbooks/:name", runbookHandler.DeleteRunbook)
+
+		// 通知渠道（审批、告警与审计异常通知）
+		adminAPI.GET("/notification-channels", hs.channel.ListChannels)
+		adminAPI.POST("/notification-channels", hs.channel.CreateChannel)
+		adminAPI.GET("/notification-channels/:id", hs.channel.GetChannel)
+		adminAPI.PUT("/notification-channels/:id", hs.channel.UpdateChannel)
+		adminAPI.DELETE("/notification-channels/:id", hs.channel.DeleteChannel)
+		adminAPI.POST("/notification-channels/:id/test", hs.channel.TestChannel)
+
+		// 异常检测阈值（按集群/命名空间覆盖默认阈值）
+		adminAPI.GET("/observation/thresholds", observationHandler.ListThresholdRules)
+		adminAPI.PUT("/observation/thresholds", observationHandler.SaveThresholdRule)
+		adminAPI.DELETE("/observation/thresholds/:id", observationHandler.DeleteThresholdRule)
 	}
-
-	// 静态文件服务（前端）
-	r.Static("/assets", "./frontend/dist/assets")
-	r.StaticFile("/", "./frontend/dist/index.html")
-	r.NoRoute(func(c *gin.Context) {
-		c.File("./frontend/dist/index.html")
-	})
-
-	return r
 }
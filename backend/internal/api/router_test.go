@@ -0,0 +1,347 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/k8s-dashboard/backend/internal/api/handlers"
+	"github.com/k8s-dashboard/backend/internal/api/middleware"
+	"github.com/k8s-dashboard/backend/internal/auth"
+	"github.com/k8s-dashboard/backend/internal/clusters"
+	dbutil "github.com/k8s-dashboard/backend/internal/db"
+	"github.com/k8s-dashboard/backend/internal/k8s"
+	"github.com/k8s-dashboard/backend/internal/openapi"
+	"github.com/k8s-dashboard/backend/internal/ratelimit"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// concretePath 将路由参数替换为示例值
+func concretePath(route string) string {
+	segments := strings.Split(route, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "sample"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func TestNamespacePermissionCoversAllNamespacedRoutes(t *testing.T) {
+	r := NewRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, Options{})
+
+	adminRoutes := map[string]bool{
+		"DELETE /api/v1/namespaces/:ns": true,
+		"DELETE /api/v1/namespace/:ns":  true,
+	}
+
+	checked := 0
+	for _, route := range r.Routes() {
+		if !strings.Contains(route.Path, "/:ns") {
+			continue
+		}
+		checked++
+		required := middleware.RequiredNamespacePermission(route.Method, concretePath(route.Path))
+		key := route.Method + " " + route.Path
+
+		switch {
+		case adminRoutes[key]:
+			if required != auth.NamespacePermAdmin {
+				t.Errorf("%s requires %q, want admin", key, required)
+			}
+		case strings.HasSuffix(route.Path, "/files"), strings.HasSuffix(route.Path, "/reveal"):
+			if required != auth.NamespacePermWrite {
+				t.Errorf("%s requires %q, want write", key, required)
+			}
+		case route.Method == http.MethodGet:
+			if required != auth.NamespacePermRead {
+				t.Errorf("%s requires %q, want read", key, required)
+			}
+		default:
+			// 只读授权必须拒绝所有修改类接口
+			if auth.NamespacePermissionAtLeast(auth.NamespacePermRead, required) {
+				t.Errorf("%s is writable with a read-only grant", key)
+			}
+		}
+	}
+	if checked == 0 {
+		t.Fatal("no namespaced routes found")
+	}
+
+	// 命名空间来自请求体的写接口按等效路由检查，只读授权同样必须被拒绝
+	bodyRoutes := map[string]bool{
+		"POST /api/v1/nodes/:name/rebalance": false,
+		"POST /api/v1/runbooks/:name/run":    false,
+	}
+	for _, route := range r.Routes() {
+		key := route.Method + " " + route.Path
+		method, path, ok := handlers.BodyNamespaceRoute(route.Method, route.Path, "team-a", "sample")
+		if !ok {
+			continue
+		}
+		if _, expected := bodyRoutes[key]; expected {
+			bodyRoutes[key] = true
+		}
+		if !strings.HasPrefix(path, "/api/v1/namespaces/team-a/") {
+			t.Errorf("%s maps to %s, want a route in the target namespace", key, path)
+		}
+		required := middleware.RequiredNamespacePermission(method, path)
+		if auth.NamespacePermissionAtLeast(auth.NamespacePermRead, required) {
+			t.Errorf("%s is writable with a read-only grant", key)
+		}
+		if middleware.RoleAtLeast("viewer", middleware.RequiredRole(method, path)) {
+			t.Errorf("%s is writable by viewers", key)
+		}
+	}
+	for key, seen := range bodyRoutes {
+		if !seen {
+			t.Errorf("body-namespace route %s is not registered or not mapped", key)
+		}
+	}
+}
+
+func TestApprovalGateCoversDestructiveRoutes(t *testing.T) {
+	r := NewRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, Options{})
+
+	want := map[string]handlers.ApprovalOperation{
+		"DELETE /api/v1/namespaces/:ns":                              {Action: "delete", Resource: "namespaces"},
+		"DELETE /api/v1/namespace/:ns":                               {Action: "delete", Resource: "namespaces"},
+		"DELETE /api/v1/namespaces/:ns/deployments/:name":            {Action: "delete", Resource: "deployments"},
+		"DELETE /api/v1/namespaces/:ns/secrets/:name":                {Action: "delete", Resource: "secrets"},
+		"DELETE /api/v1/persistentvolumes/:name":                     {Action: "delete", Resource: "persistentvolumes"},
+		"POST /api/v1/namespaces/:ns/deployments/:name/scale":        {Action: "scale", Resource: "deployments"},
+		"POST /api/v1/namespaces/:ns/statefulsets/:name/scale":       {Action: "scale", Resource: "statefulsets"},
+		"POST /api/v1/namespaces/:ns/daemonsets/:name/restart":       {Action: "restart", Resource: "daemonsets"},
+		"POST /api/v1/namespaces/:ns/statefulsets/:name/restart":     {Action: "restart", Resource: "statefulsets"},
+		"DELETE /api/v1/namespaces/:ns/persistentvolumeclaims/:name": {Action: "delete", Resource: "persistentvolumeclaims"},
+		"POST /api/v1/namespaces/:ns/cleanup":                        {Action: "cleanup", Resource: "namespaces"},
+		"POST /api/v1/namespace/:ns/cleanup":                         {Action: "cleanup", Resource: "namespaces"},
+	}
+
+	seen := map[string]bool{}
+	for _, route := range r.Routes() {
+		key := route.Method + " " + route.Path
+		op, ok := handlers.ApprovalOperationForRoute(route.Method, route.Path)
+		if ok && route.Method == http.MethodGet {
+			t.Errorf("%s should not require approval", key)
+		}
+		// 默认审批规则覆盖的资源，其删除路由都必须被拦截
+		if route.Method == http.MethodDelete && strings.HasPrefix(route.Path, "/api/v1/namespaces/:ns/") && strings.Count(route.Path, "/") == 6 && !ok {
+			t.Errorf("%s is not covered by the approval gate", key)
+		}
+		if expected, exists := want[key]; exists {
+			seen[key] = true
+			if !ok || op != expected {
+				t.Errorf("%s mapped to %+v (%v), want %+v", key, op, ok, expected)
+			}
+		}
+	}
+	for key := range want {
+		if !seen[key] {
+			t.Errorf("route %s not registered", key)
+		}
+	}
+}
+
+func TestOpenAPIDocumentCoversAllRoutes(t *testing.T) {
+	r := NewRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, Options{})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	var doc openapi.Document
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("decode document: %v", err)
+	}
+
+	registered := map[string]bool{}
+	for _, route := range r.Routes() {
+		if !strings.HasPrefix(route.Path, "/api/v1/") {
+			continue
+		}
+		registered[route.Method+" "+route.Path] = true
+		path := strings.NewReplacer(":ns", "{ns}", ":name", "{name}", ":id", "{id}", ":fingerprint", "{fingerprint}", ":resource", "{resource}").Replace(route.Path)
+		if doc.Paths[path][strings.ToLower(route.Method)] == nil {
+			t.Errorf("%s %s missing from document", route.Method, path)
+		}
+	}
+	// 文档说明中的路由必须真实存在，避免改路由后说明失效
+	for key := range handlers.OpenAPIRoutes() {
+		if !registered[key] {
+			t.Errorf("documented route %s is not registered", key)
+		}
+	}
+
+	// 所有 $ref 都能在 components 中找到
+	for ref := range collectRefs(rec.Body.Bytes()) {
+		name := strings.TrimPrefix(ref, "#/components/schemas/")
+		if doc.Components.Schemas[name] == nil {
+			t.Errorf("unresolved reference %s", ref)
+		}
+	}
+
+	login := doc.Paths["/api/v1/auth/login"]["post"]
+	if login == nil || login.Security == nil || len(*login.Security) != 0 {
+		t.Errorf("login should not require authentication")
+	}
+	if doc.Paths["/api/v1/namespaces/{ns}/deployments"]["post"].RequestBody == nil {
+		t.Errorf("create deployment should document its request body")
+	}
+}
+
+// collectRefs 提取 JSON 中所有 $ref 的值
+func collectRefs(data []byte) map[string]bool {
+	refs := map[string]bool{}
+	for _, part := range strings.Split(string(data), `"$ref":"`)[1:] {
+		refs[part[:strings.Index(part, `"`)]] = true
+	}
+	return refs
+}
+
+func TestAPIVersionsShareRoutes(t *testing.T) {
+	r := NewRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, Options{})
+
+	v1 := map[string]bool{}
+	v2 := map[string]bool{}
+	for _, route := range r.Routes() {
+		if rest, ok := strings.CutPrefix(route.Path, middleware.APIPrefixV1+"/"); ok {
+			v1[route.Method+" "+rest] = true
+		}
+		if rest, ok := strings.CutPrefix(route.Path, middleware.APIPrefixV2+"/"); ok {
+			v2[route.Method+" "+rest] = true
+		}
+	}
+	for key := range v1 {
+		legacy := strings.Contains(key, " namespace/")
+		if legacy && v2[key] {
+			t.Errorf("legacy route %s should not be registered under v2", key)
+		}
+		if !legacy && !v2[key] {
+			t.Errorf("route %s missing from v2", key)
+		}
+	}
+	for key := range v2 {
+		if !v1[key] {
+			t.Errorf("route %s missing from v1", key)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil))
+	if rec.Header().Get("Deprecation") != "true" || rec.Header().Get("Link") != `</api/v2/openapi.json>; rel="successor-version"` {
+		t.Errorf("v1 response headers = %v", rec.Header())
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v2/openapi.json", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Deprecation") != "" {
+		t.Errorf("v2 status = %d, headers = %v", rec.Code, rec.Header())
+	}
+	var doc openapi.Document
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("decode document: %v", err)
+	}
+	if op := doc.Paths["/api/v2/namespaces/{ns}/pods"]["get"]; op == nil || op.Deprecated || op.Responses["200"].Content == nil {
+		t.Errorf("v2 document should describe pod list: %+v", op)
+	}
+	if doc.Paths["/api/v2/namespace/{ns}"] != nil || doc.Paths["/api/v1/overview"] != nil {
+		t.Errorf("v2 document should only contain v2 routes")
+	}
+}
+
+func TestRateLimitIgnoresUntrustedForwardedFor(t *testing.T) {
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		wantLimited    bool
+	}{
+		// 未配置可信代理时伪造的 X-Forwarded-For 不能换出新的令牌桶
+		{name: "no trusted proxies", wantLimited: true},
+		{name: "trusted proxy forwards client ip", trustedProxies: []string{"10.0.0.0/8"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := ratelimit.NewLimiter(ratelimit.Config{IPRPS: 0.5, IPBurst: 1})
+			r := NewRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, limiter, nil, Options{TrustedProxies: tt.trustedProxies})
+
+			var code int
+			for _, forwardedFor := range []string{"203.0.113.1", "203.0.113.2"} {
+				req := httptest.NewRequest(http.MethodGet, "/api/v2/nodes", nil)
+				req.RemoteAddr = "10.0.0.1:1234"
+				req.Header.Set("X-Forwarded-For", forwardedFor)
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, req)
+				code = w.Code
+			}
+			if limited := code == http.StatusTooManyRequests; limited != tt.wantLimited {
+				t.Fatalf("second request status = %d, want limited %v", code, tt.wantLimited)
+			}
+		})
+	}
+}
+
+func TestVaryHeadersThroughAPIChain(t *testing.T) {
+	t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "missing"))
+	conn, dialect, err := dbutil.Open(dbutil.Config{
+		SQLitePath:          filepath.Join(t.TempDir(), "dashboard.db"),
+		AllowSQLiteFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	authClient, err := auth.NewClient(conn, dialect, "test-secret")
+	if err != nil {
+		t.Fatalf("auth.NewClient failed: %v", err)
+	}
+	if _, err := authClient.CreateUser(&auth.CreateUserRequest{Username: "root", Password: "Passw0rd!", Role: "admin"}); err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	_, tokens, err := authClient.Login("root", "Passw0rd!", "127.0.0.1", "test-agent")
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+
+	k8sAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"kind":"NamespaceList","apiVersion":"v1","items":[{"metadata":{"name":"default"}}]}`))
+	}))
+	t.Cleanup(k8sAPI.Close)
+	config := &rest.Config{Host: k8sAPI.URL, ContentConfig: rest.ContentConfig{ContentType: "application/json"}}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		t.Fatalf("NewForConfig failed: %v", err)
+	}
+	k8sClient := &k8s.Client{Clientset: clientset, Config: config}
+	manager, err := clusters.NewManager(conn, dialect, "", "test-secret", k8sClient)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+
+	r := NewRouter(k8sClient, manager, nil, nil, nil, nil, authClient, nil, nil, nil, nil, nil, nil, nil, nil, nil, ratelimit.NewLimiter(ratelimit.DefaultConfig()), nil, Options{})
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/namespaces", nil)
+	req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+	}
+
+	vary := strings.Join(w.Header().Values("Vary"), ",")
+	for _, want := range []string{"Accept-Encoding", "X-Cluster"} {
+		if !strings.Contains(vary, want) {
+			t.Errorf("Vary = %q, missing %s", vary, want)
+		}
+	}
+	if got := w.Header().Get("X-Cluster"); got != clusters.DefaultClusterName {
+		t.Errorf("X-Cluster = %q, want %q", got, clusters.DefaultClusterName)
+	}
+}
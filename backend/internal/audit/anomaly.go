@@ -0,0 +1,418 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	dbutil "github.com/k8s-dashboard/backend/internal/db"
+)
+
+// 异常严重级别
+const (
+	AnomalySeverityWarning  = "warning"
+	AnomalySeverityCritical = "critical"
+)
+
+// defaultAnomalyCooldown 不设置窗口的规则对同一用户重复告警的最小间隔
+const defaultAnomalyCooldown = time.Hour
+
+// AnomalyRule 审计异常规则。同一用户在 Window 内命中次数超过 Threshold 时产生告警，
+// Threshold 为 0 时每次命中都告警；同一规则对同一用户在冷却期（Window，未设置时 1 小时）内只告警一次
+type AnomalyRule struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Severity    string   `json:"severity"`            // warning, critical
+	Actions     []string `json:"actions,omitempty"`   // HTTP 方法，空表示任意
+	Resources   []string `json:"resources,omitempty"` // 资源类型，空表示任意
+	Threshold   int      `json:"threshold,omitempty"`
+	Window      string   `json:"window,omitempty"`   // 如 5m
+	OffHours    bool     `json:"offHours,omitempty"` // 仅统计工作时间之外的操作
+}
+
+// AnomalyConfig 审计异常检测配置
+type AnomalyConfig struct {
+	Enabled bool `json:"enabled"`
+	// BusinessHours 工作时间（周一至周五），格式 09:00-18:00
+	BusinessHours string `json:"businessHours"`
+	// Timezone 判断工作时间使用的时区（IANA 名称），为空时使用服务器本地时区
+	Timezone string `json:"timezone"`
+	// Notify 是否同时推送到审批通知渠道（Webhook、Slack、邮件）
+	Notify bool          `json:"notify"`
+	Rules  []AnomalyRule `json:"rules"`
+}
+
+// DefaultAnomalyRules 默认规则：5 分钟内删除超过 20 次、工作时间外读取 Secret
+func DefaultAnomalyRules() []AnomalyRule {
+	return []AnomalyRule{
+		{
+			Name:        "mass-delete",
+			Description: "短时间内大量删除资源",
+			Severity:    AnomalySeverityCritical,
+			Actions:     []string{"DELETE"},
+			Threshold:   20,
+			Window:      "5m",
+		},
+		{
+			Name:        "off-hours-secret-read",
+			Description: "工作时间外读取 Secret",
+			Severity:    AnomalySeverityWarning,
+			Actions:     []string{"GET"},
+			Resources:   []string{"secrets"},
+			OffHours:    true,
+		},
+	}
+}
+
+// DefaultAnomalyConfig 默认启用内置规则
+func DefaultAnomalyConfig() AnomalyConfig {
+	return AnomalyConfig{
+		Enabled:       true,
+		BusinessHours: "09:00-18:00",
+		Rules:         DefaultAnomalyRules(),
+	}
+}
+
+// Validate 校验规则与工作时间配置
+func (cfg AnomalyConfig) Validate() error {
+	_, err := NewAnomalyDetector(cfg)
+	return err
+}
+
+// AuditAnomaly 异常检测产生的告警
+type AuditAnomaly struct {
+	ID        int64     `json:"id"`
+	Rule      string    `json:"rule"`
+	Severity  string    `json:"severity"`
+	User      string    `json:"user"`
+	Cluster   string    `json:"cluster"`
+	Message   string    `json:"message"`
+	Count     int       `json:"count"`
+	FirstAt   time.Time `json:"firstAt"`
+	LastAt    time.Time `json:"lastAt"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// AnomalyHandler 接收新产生的异常告警，需自行处理耗时操作
+type AnomalyHandler func(anomaly *AuditAnomaly)
+
+type anomalyRule struct {
+	AnomalyRule
+	window    time.Duration
+	cooldown  time.Duration
+	actions   map[string]bool
+	resources map[string]bool
+}
+
+// AnomalyDetector 基于内存滑动窗口的审计异常检测，多副本部署时各副本独立统计
+type AnomalyDetector struct {
+	rules      []anomalyRule
+	loc        *time.Location
+	startMin   int // 工作时间起止，距零点的分钟数
+	endMin     int
+	mu         sync.Mutex
+	hits       map[string][]time.Time // rule/user -> 窗口内命中时间
+	lastFired  map[string]time.Time
+	lastPruned time.Time
+}
+
+// NewAnomalyDetector 按配置创建检测器
+func NewAnomalyDetector(cfg AnomalyConfig) (*AnomalyDetector, error) {
+	d := &AnomalyDetector{
+		loc:       time.Local,
+		hits:      make(map[string][]time.Time),
+		lastFired: make(map[string]time.Time),
+	}
+	if cfg.Timezone != "" {
+		loc, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("AUDIT_TIMEZONE 无效: %q", cfg.Timezone)
+		}
+		d.loc = loc
+	}
+	var err error
+	if d.startMin, d.endMin, err = parseBusinessHours(cfg.BusinessHours); err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	seen := make(map[string]bool)
+	for _, rule := range cfg.Rules {
+		compiled := anomalyRule{AnomalyRule: rule, cooldown: defaultAnomalyCooldown}
+		if rule.Name == "" || seen[rule.Name] {
+			errs = append(errs, fmt.Errorf("审计异常规则名称为空或重复: %q", rule.Name))
+		}
+		seen[rule.Name] = true
+		if rule.Severity != AnomalySeverityWarning && rule.Severity != AnomalySeverityCritical {
+			errs = append(errs, fmt.Errorf("审计异常规则 %s 的 severity 需为 warning 或 critical", rule.Name))
+		}
+		if rule.Threshold < 0 {
+			errs = append(errs, fmt.Errorf("审计异常规则 %s 的 threshold 不能为负数", rule.Name))
+		}
+		if rule.Window != "" {
+			window, err := time.ParseDuration(rule.Window)
+			if err != nil || window <= 0 {
+				errs = append(errs, fmt.Errorf("审计异常规则 %s 的 window 无效: %q", rule.Name, rule.Window))
+			}
+			compiled.window, compiled.cooldown = window, window
+		}
+		if rule.Threshold > 0 && compiled.window <= 0 {
+			errs = append(errs, fmt.Errorf("审计异常规则 %s 设置 threshold 时必须设置 window", rule.Name))
+		}
+		compiled.actions = toSet(rule.Actions, strings.ToUpper)
+		compiled.resources = toSet(rule.Resources, strings.ToLower)
+		d.rules = append(d.rules, compiled)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func parseBusinessHours(value string) (int, int, error) {
+	invalid := fmt.Errorf("AUDIT_BUSINESS_HOURS 需为 HH:MM-HH:MM 格式: %q", value)
+	from, to, ok := strings.Cut(value, "-")
+	if !ok {
+		return 0, 0, invalid
+	}
+	start, err1 := time.Parse("15:04", strings.TrimSpace(from))
+	end, err2 := time.Parse("15:04", strings.TrimSpace(to))
+	if err1 != nil || err2 != nil || !end.After(start) {
+		return 0, 0, invalid
+	}
+	return start.Hour()*60 + start.Minute(), end.Hour()*60 + end.Minute(), nil
+}
+
+func toSet(items []string, normalize func(string) string) map[string]bool {
+	if len(items) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		set[normalize(strings.TrimSpace(item))] = true
+	}
+	return set
+}
+
+// isOffHours 周末或工作时间之外
+func (d *AnomalyDetector) isOffHours(t time.Time) bool {
+	t = t.In(d.loc)
+	if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		return true
+	}
+	minute := t.Hour()*60 + t.Minute()
+	return minute < d.startMin || minute >= d.endMin
+}
+
+// Observe 统计一条审计日志，返回因此触发的异常
+func (d *AnomalyDetector) Observe(log *AuditLog) []*AuditAnomaly {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := log.Timestamp
+	d.prune(now)
+
+	var anomalies []*AuditAnomaly
+	for i := range d.rules {
+		rule := &d.rules[i]
+		if rule.actions != nil && !rule.actions[log.Action] {
+			continue
+		}
+		if rule.resources != nil && !rule.resources[log.Resource] {
+			continue
+		}
+		if rule.OffHours && !d.isOffHours(now) {
+			continue
+		}
+
+		key := rule.Name + "/" + log.User
+		hits := append(trimHits(d.hits[key], now.Add(-rule.window)), now)
+		d.hits[key] = hits
+		if len(hits) <= rule.Threshold {
+			continue
+		}
+		if last, ok := d.lastFired[key]; ok && now.Sub(last) < rule.cooldown {
+			continue
+		}
+		d.lastFired[key] = now
+
+		anomalies = append(anomalies, &AuditAnomaly{
+			Rule:      rule.Name,
+			Severity:  rule.Severity,
+			User:      log.User,
+			Cluster:   log.Cluster,
+			Message:   anomalyMessage(rule, log, len(hits)),
+			Count:     len(hits),
+			FirstAt:   hits[0],
+			LastAt:    now,
+			CreatedAt: time.Now(),
+		})
+		// 已告警的命中不再计入下一次
+		delete(d.hits, key)
+	}
+	return anomalies
+}
+
+func anomalyMessage(rule *anomalyRule, log *AuditLog, count int) string {
+	description := rule.Description
+	if description == "" {
+		description = rule.Name
+	}
+	if rule.Threshold > 0 {
+		return fmt.Sprintf("%s：%s 在 %s 内执行了 %d 次 %s %s", description, log.User, rule.window, count, log.Action, log.Resource)
+	}
+	target := log.Resource
+	if log.ResourceName != "" {
+		target += " " + log.ResourceName
+	}
+	if log.Namespace != "" {
+		target = log.Namespace + "/" + target
+	}
+	return fmt.Sprintf("%s：%s %s %s", description, log.User, log.Action, target)
+}
+
+// trimHits 丢弃 since 之前的命中，window 为 0 时全部丢弃
+func trimHits(hits []time.Time, since time.Time) []time.Time {
+	i := 0
+	for i < len(hits) && !hits[i].After(since) {
+		i++
+	}
+	return hits[i:]
+}
+
+// prune 定期清理不再活跃的用户，避免内存随用户数增长
+func (d *AnomalyDetector) prune(now time.Time) {
+	if now.Sub(d.lastPruned) < time.Minute {
+		return
+	}
+	d.lastPruned = now
+	var maxWindow, maxCooldown time.Duration
+	for _, rule := range d.rules {
+		maxWindow = max(maxWindow, rule.window)
+		maxCooldown = max(maxCooldown, rule.cooldown)
+	}
+	for key, hits := range d.hits {
+		if len(hits) == 0 || now.Sub(hits[len(hits)-1]) > maxWindow {
+			delete(d.hits, key)
+		}
+	}
+	for key, last := range d.lastFired {
+		if now.Sub(last) > maxCooldown {
+			delete(d.lastFired, key)
+		}
+	}
+}
+
+// SetAnomalyDetector 启用异常检测，每条审计日志写入后参与统计，
+// 产生的告警保存到 audit_anomalies 并交给 handler（可为 nil）
+func (c *Client) SetAnomalyDetector(detector *AnomalyDetector, handler AnomalyHandler) {
+	c.anomalyDetector = detector
+	c.anomalyHandler = handler
+}
+
+// detectAnomalies 统计审计日志并保存产生的告警
+func (c *Client) detectAnomalies(log *AuditLog) error {
+	if c.anomalyDetector == nil {
+		return nil
+	}
+	var errs []error
+	for _, anomaly := range c.anomalyDetector.Observe(log) {
+		if err := c.saveAnomaly(anomaly); err != nil {
+			errs = append(errs, fmt.Errorf("保存审计异常失败: %w", err))
+			continue
+		}
+		if c.anomalyHandler != nil {
+			c.anomalyHandler(anomaly)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// initAnomalySchema 初始化审计异常告警表
+func (c *Client) initAnomalySchema() error {
+	var schema string
+	if c.dialect == dbutil.DialectSQLite {
+		schema = `
+		CREATE TABLE IF NOT EXISTS audit_anomalies (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			rule TEXT NOT NULL,
+			severity TEXT NOT NULL,
+			"user" TEXT NOT NULL,
+			cluster TEXT,
+			message TEXT,
+			event_count INTEGER NOT NULL,
+			first_at DATETIME NOT NULL,
+			last_at DATETIME NOT NULL,
+			created_at DATETIME NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_audit_anomalies_created ON audit_anomalies(created_at DESC);
+		`
+	} else {
+		schema = `
+		CREATE TABLE IF NOT EXISTS audit_anomalies (
+			id BIGSERIAL PRIMARY KEY,
+			rule VARCHAR(100) NOT NULL,
+			severity VARCHAR(20) NOT NULL,
+			"user" VARCHAR(255) NOT NULL,
+			cluster VARCHAR(100),
+			message TEXT,
+			event_count INT NOT NULL,
+			first_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			last_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_audit_anomalies_created ON audit_anomalies(created_at DESC);
+		`
+	}
+	_, err := c.db.Exec(schema)
+	return err
+}
+
+func (c *Client) saveAnomaly(a *AuditAnomaly) error {
+	args := []interface{}{a.Rule, a.Severity, a.User, a.Cluster, a.Message, a.Count, a.FirstAt, a.LastAt, a.CreatedAt}
+	query := `
+		INSERT INTO audit_anomalies (rule, severity, "user", cluster, message, event_count, first_at, last_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	if c.dialect == dbutil.DialectSQLite {
+		result, err := c.db.Exec(query, args...)
+		if err != nil {
+			return err
+		}
+		a.ID, err = result.LastInsertId()
+		return err
+	}
+	return c.db.QueryRow(query+" RETURNING id", args...).Scan(&a.ID)
+}
+
+// ListAnomalies 按时间倒序列出审计异常告警，since 为零值时不限制
+func (c *Client) ListAnomalies(since time.Time, limit int) ([]AuditAnomaly, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	rows, err := c.db.Query(`
+		SELECT id, rule, severity, "user", COALESCE(cluster, ''), COALESCE(message, ''),
+		       event_count, first_at, last_at, created_at
+		FROM audit_anomalies
+		WHERE created_at >= $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	anomalies := []AuditAnomaly{}
+	for rows.Next() {
+		var a AuditAnomaly
+		if err := rows.Scan(&a.ID, &a.Rule, &a.Severity, &a.User, &a.Cluster, &a.Message,
+			&a.Count, &a.FirstAt, &a.LastAt, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		anomalies = append(anomalies, a)
+	}
+	return anomalies, rows.Err()
+}
@@ -0,0 +1,148 @@
+package audit
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	dbutil "github.com/k8s-dashboard/backend/internal/db"
+)
+
+func TestAnomalyDetectorMassDelete(t *testing.T) {
+	detector, err := NewAnomalyDetector(AnomalyConfig{
+		BusinessHours: "09:00-18:00",
+		Rules: []AnomalyRule{{
+			Name: "mass-delete", Severity: AnomalySeverityCritical,
+			Actions: []string{"delete"}, Threshold: 3, Window: "5m",
+		}},
+	})
+	if err != nil {
+		t.Fatalf("NewAnomalyDetector failed: %v", err)
+	}
+
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	observe := func(user, action string, offset time.Duration) []*AuditAnomaly {
+		return detector.Observe(&AuditLog{Timestamp: start.Add(offset), User: user, Action: action, Resource: "pods"})
+	}
+
+	for i := 0; i < 3; i++ {
+		if got := observe("alice", "DELETE", time.Duration(i)*time.Minute); len(got) != 0 {
+			t.Fatalf("unexpected anomaly at delete %d: %+v", i+1, got[0])
+		}
+	}
+	// 其他用户与其他操作不计入
+	if got := observe("bob", "DELETE", 3*time.Minute); len(got) != 0 {
+		t.Fatalf("unexpected anomaly for bob")
+	}
+	if got := observe("alice", "GET", 3*time.Minute); len(got) != 0 {
+		t.Fatalf("unexpected anomaly for GET")
+	}
+
+	got := observe("alice", "DELETE", 4*time.Minute)
+	if len(got) != 1 || got[0].Count != 4 || got[0].User != "alice" || !got[0].FirstAt.Equal(start) {
+		t.Fatalf("expected mass delete anomaly, got %+v", got)
+	}
+	// 冷却期内不重复告警
+	for i := 5; i < 9; i++ {
+		if got := observe("alice", "DELETE", time.Duration(i)*time.Minute); len(got) != 0 {
+			t.Fatalf("unexpected anomaly during cooldown")
+		}
+	}
+	// 窗口滑过后，较早的删除不再计入
+	if got := observe("alice", "DELETE", 20*time.Minute); len(got) != 0 {
+		t.Fatalf("unexpected anomaly after window expired")
+	}
+}
+
+func TestAnomalyDetectorOffHours(t *testing.T) {
+	cfg := DefaultAnomalyConfig()
+	cfg.Timezone = "Asia/Shanghai"
+	detector, err := NewAnomalyDetector(cfg)
+	if err != nil {
+		t.Fatalf("NewAnomalyDetector failed: %v", err)
+	}
+
+	read := func(user string, at time.Time) []*AuditAnomaly {
+		return detector.Observe(&AuditLog{Timestamp: at, User: user, Action: "GET", Resource: "secrets", Namespace: "prod", ResourceName: "db"})
+	}
+
+	// 北京时间周一 10:00
+	if got := read("alice", time.Date(2026, 3, 2, 2, 0, 0, 0, time.UTC)); len(got) != 0 {
+		t.Fatalf("unexpected anomaly during business hours")
+	}
+	// 北京时间周一 23:00
+	got := read("alice", time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC))
+	if len(got) != 1 || got[0].Rule != "off-hours-secret-read" || !strings.Contains(got[0].Message, "prod/secrets db") {
+		t.Fatalf("expected off-hours anomaly, got %+v", got)
+	}
+	// 周末全天视为工作时间外
+	if got := read("bob", time.Date(2026, 3, 7, 3, 0, 0, 0, time.UTC)); len(got) != 1 {
+		t.Fatalf("expected weekend anomaly, got %+v", got)
+	}
+}
+
+func TestAnomalyConfigValidate(t *testing.T) {
+	tests := []AnomalyConfig{
+		{BusinessHours: "18:00-09:00"},
+		{BusinessHours: "09:00-18:00", Timezone: "Mars/Olympus"},
+		{BusinessHours: "09:00-18:00", Rules: []AnomalyRule{{Name: "x", Severity: "info"}}},
+		{BusinessHours: "09:00-18:00", Rules: []AnomalyRule{{Name: "x", Severity: "warning", Threshold: 5}}},
+		{BusinessHours: "09:00-18:00", Rules: []AnomalyRule{{Name: "x", Severity: "warning"}, {Name: "x", Severity: "warning"}}},
+	}
+	for i, cfg := range tests {
+		if err := cfg.Validate(); err == nil {
+			t.Fatalf("case %d: expected validation error", i)
+		}
+	}
+	if err := DefaultAnomalyConfig().Validate(); err != nil {
+		t.Fatalf("default config should be valid: %v", err)
+	}
+}
+
+func TestSQLiteAuditAnomalies(t *testing.T) {
+	conn, dialect, err := dbutil.Open(dbutil.Config{
+		SQLitePath:          filepath.Join(t.TempDir(), "audit.db"),
+		AllowSQLiteFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer conn.Close()
+
+	client, err := NewClient(conn, dialect)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	detector, err := NewAnomalyDetector(AnomalyConfig{
+		BusinessHours: "09:00-18:00",
+		Rules:         []AnomalyRule{{Name: "mass-delete", Severity: AnomalySeverityCritical, Actions: []string{"DELETE"}, Threshold: 1, Window: "5m"}},
+	})
+	if err != nil {
+		t.Fatalf("NewAnomalyDetector failed: %v", err)
+	}
+	var handled []*AuditAnomaly
+	client.SetAnomalyDetector(detector, func(a *AuditAnomaly) { handled = append(handled, a) })
+
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		entry := &AuditLog{Timestamp: now.Add(time.Duration(i) * time.Second), User: "alice", Action: "DELETE", Resource: "pods", Cluster: "prod"}
+		if err := client.Log(entry); err != nil {
+			t.Fatalf("Log failed: %v", err)
+		}
+	}
+
+	if len(handled) != 1 || handled[0].ID == 0 {
+		t.Fatalf("expected one saved anomaly to be handled, got %+v", handled)
+	}
+	anomalies, err := client.ListAnomalies(time.Time{}, 10)
+	if err != nil {
+		t.Fatalf("ListAnomalies failed: %v", err)
+	}
+	if len(anomalies) != 1 || anomalies[0].Rule != "mass-delete" || anomalies[0].Count != 2 || anomalies[0].Cluster != "prod" {
+		t.Fatalf("unexpected anomalies: %+v", anomalies)
+	}
+	if anomalies, err := client.ListAnomalies(now.Add(time.Hour), 10); err != nil || len(anomalies) != 0 {
+		t.Fatalf("expected since filter to exclude anomaly, got %+v, %v", anomalies, err)
+	}
+}
@@ -0,0 +1,270 @@
+package audit
+
+import (
+	"compress/gzip"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	dbutil "github.com/k8s-dashboard/backend/internal/db"
+)
+
+// AuditArchive 归档记录，每次维护将超出保留期且未归档的审计日志写入一个 gzip 压缩的 NDJSON 文件
+type AuditArchive struct {
+	ID        int64     `json:"id"`
+	File      string    `json:"file"`
+	RangeFrom time.Time `json:"rangeFrom"` // 包含
+	RangeTo   time.Time `json:"rangeTo"`   // 不包含
+	Rows      int64     `json:"rows"`
+	Bytes     int64     `json:"bytes"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// StorageStats 审计数据存储统计
+type StorageStats struct {
+	Rows     int64      `json:"rows"`
+	OldestAt *time.Time `json:"oldestAt,omitempty"`
+	NewestAt *time.Time `json:"newestAt,omitempty"`
+	// TableBytes Postgres 为 audit_logs 全部分区（含索引）的大小，SQLite 为整个数据库文件大小
+	TableBytes int64 `json:"tableBytes"`
+	Partitions int   `json:"partitions,omitempty"`
+
+	RetentionDays int           `json:"retentionDays"`
+	ArchiveDir    string        `json:"archiveDir,omitempty"`
+	ArchiveFiles  int64         `json:"archiveFiles"`
+	ArchivedRows  int64         `json:"archivedRows"`
+	ArchiveBytes  int64         `json:"archiveBytes"`
+	LastArchive   *AuditArchive `json:"lastArchive,omitempty"`
+
+	LastMaintenanceAt    *time.Time `json:"lastMaintenanceAt,omitempty"`
+	LastMaintenanceError string     `json:"lastMaintenanceError,omitempty"`
+
+	// Forwarding 外部转发渠道状态，未配置转发时为空
+	Forwarding []SinkStatus `json:"forwarding,omitempty"`
+}
+
+// SetArchiveDir 设置归档目录，设置后清理过期审计日志前先归档，归档失败时不清理。
+// 目录可以是挂载的对象存储（如 S3 CSI、s3fs）
+func (c *Client) SetArchiveDir(dir string) error {
+	if dir == "" {
+		c.archiveDir = ""
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("创建审计归档目录失败: %w", err)
+	}
+	c.archiveDir = dir
+	return nil
+}
+
+// initArchiveSchema 初始化归档记录表
+func (c *Client) initArchiveSchema() error {
+	var schema string
+	if c.dialect == dbutil.DialectSQLite {
+		schema = `
+		CREATE TABLE IF NOT EXISTS audit_archives (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			file TEXT NOT NULL,
+			range_from DATETIME NOT NULL,
+			range_to DATETIME NOT NULL,
+			row_count INTEGER NOT NULL,
+			bytes INTEGER NOT NULL,
+			created_at DATETIME NOT NULL
+		);
+		`
+	} else {
+		schema = `
+		CREATE TABLE IF NOT EXISTS audit_archives (
+			id BIGSERIAL PRIMARY KEY,
+			file TEXT NOT NULL,
+			range_from TIMESTAMP WITH TIME ZONE NOT NULL,
+			range_to TIMESTAMP WITH TIME ZONE NOT NULL,
+			row_count BIGINT NOT NULL,
+			bytes BIGINT NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL
+		);
+		`
+	}
+	_, err := c.db.Exec(schema)
+	return err
+}
+
+// archiveBefore 将 [上次归档截止时间, cutoff) 内的审计日志写入归档文件并登记，
+// 没有新数据时不生成文件。Postgres 只删除整月过期的分区，未删除的行不会被重复归档
+func (c *Client) archiveBefore(cutoff time.Time) (*AuditArchive, error) {
+	// SQLite 的 MAX() 会丢失列的时间类型，用排序取最新一条
+	var from time.Time
+	err := c.db.QueryRow("SELECT range_to FROM audit_archives ORDER BY range_to DESC LIMIT 1").Scan(&from)
+	if err == sql.ErrNoRows {
+		// 首次归档从最早的一条开始
+		err = c.db.QueryRow("SELECT timestamp FROM audit_logs ORDER BY timestamp LIMIT 1").Scan(&from)
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !cutoff.After(from) {
+		return nil, nil
+	}
+
+	name := fmt.Sprintf("audit-%s-%s.ndjson.gz", from.UTC().Format("20060102T150405Z"), cutoff.UTC().Format("20060102T150405Z"))
+	path := filepath.Join(c.archiveDir, name)
+	tmp, err := os.CreateTemp(c.archiveDir, name+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	zw := gzip.NewWriter(tmp)
+	rows, err := c.writeArchive(zw, from, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	if rows == 0 {
+		return nil, nil
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	if err := tmp.Sync(); err != nil {
+		return nil, err
+	}
+	info, err := tmp.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return nil, err
+	}
+
+	archive := &AuditArchive{File: name, RangeFrom: from, RangeTo: cutoff, Rows: rows, Bytes: info.Size(), CreatedAt: time.Now()}
+	_, err = c.db.Exec(`
+		INSERT INTO audit_archives (file, range_from, range_to, row_count, bytes, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, archive.File, archive.RangeFrom, archive.RangeTo, archive.Rows, archive.Bytes, archive.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return archive, nil
+}
+
+// writeArchive 分批读取 [from, to) 内的审计日志并按 NDJSON 写入
+func (c *Client) writeArchive(w *gzip.Writer, from, to time.Time) (int64, error) {
+	query := `
+		SELECT ` + auditLogColumns + `
+		FROM audit_logs
+		WHERE timestamp >= $1 AND timestamp < $2 AND id > $3
+		ORDER BY id
+		LIMIT $4
+	`
+	encoder := json.NewEncoder(w)
+	var written, lastID int64
+	for {
+		logs, err := c.queryLogs(query, []interface{}{from, to, lastID, exportBatchSize})
+		if err != nil {
+			return written, err
+		}
+		for i := range logs {
+			if err := encoder.Encode(&logs[i]); err != nil {
+				return written, err
+			}
+		}
+		written += int64(len(logs))
+		if len(logs) < exportBatchSize {
+			return written, nil
+		}
+		lastID = logs[len(logs)-1].ID
+	}
+}
+
+// recordMaintenance 记录最近一次维护的结果，供存储统计展示
+func (c *Client) recordMaintenance(retention time.Duration, err error) {
+	c.maintenanceMu.Lock()
+	defer c.maintenanceMu.Unlock()
+	now := time.Now()
+	c.lastMaintenanceAt = &now
+	c.retention = retention
+	c.lastMaintenanceErr = ""
+	if err != nil {
+		c.lastMaintenanceErr = err.Error()
+	}
+}
+
+// GetStorageStats 返回审计表的行数、时间跨度、占用空间与归档情况
+func (c *Client) GetStorageStats() (*StorageStats, error) {
+	stats := &StorageStats{ArchiveDir: c.archiveDir}
+
+	if err := c.db.QueryRow("SELECT COUNT(*) FROM audit_logs").Scan(&stats.Rows); err != nil {
+		return nil, err
+	}
+	if stats.Rows > 0 {
+		var oldest, newest time.Time
+		if err := c.db.QueryRow("SELECT timestamp FROM audit_logs ORDER BY timestamp LIMIT 1").Scan(&oldest); err != nil {
+			return nil, err
+		}
+		if err := c.db.QueryRow("SELECT timestamp FROM audit_logs ORDER BY timestamp DESC LIMIT 1").Scan(&newest); err != nil {
+			return nil, err
+		}
+		stats.OldestAt, stats.NewestAt = &oldest, &newest
+	}
+
+	if c.dialect == dbutil.DialectSQLite {
+		var pageCount, pageSize int64
+		if err := c.db.QueryRow("PRAGMA page_count").Scan(&pageCount); err != nil {
+			return nil, err
+		}
+		if err := c.db.QueryRow("PRAGMA page_size").Scan(&pageSize); err != nil {
+			return nil, err
+		}
+		stats.TableBytes = pageCount * pageSize
+	} else {
+		partitions, err := dbutil.ListPartitions(c.db, "audit_logs")
+		if err != nil {
+			return nil, err
+		}
+		stats.Partitions = len(partitions)
+		for _, name := range partitions {
+			var size int64
+			if err := c.db.QueryRow("SELECT pg_total_relation_size($1::regclass)", name).Scan(&size); err != nil {
+				return nil, err
+			}
+			stats.TableBytes += size
+		}
+	}
+
+	if err := c.db.QueryRow(`
+		SELECT COUNT(*), COALESCE(SUM(row_count), 0), COALESCE(SUM(bytes), 0) FROM audit_archives
+	`).Scan(&stats.ArchiveFiles, &stats.ArchivedRows, &stats.ArchiveBytes); err != nil {
+		return nil, err
+	}
+	if stats.ArchiveFiles > 0 {
+		var a AuditArchive
+		err := c.db.QueryRow(`
+			SELECT id, file, range_from, range_to, row_count, bytes, created_at
+			FROM audit_archives ORDER BY range_to DESC LIMIT 1
+		`).Scan(&a.ID, &a.File, &a.RangeFrom, &a.RangeTo, &a.Rows, &a.Bytes, &a.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		stats.LastArchive = &a
+	}
+
+	c.maintenanceMu.Lock()
+	stats.RetentionDays = int(c.retention / (24 * time.Hour))
+	stats.LastMaintenanceAt = c.lastMaintenanceAt
+	stats.LastMaintenanceError = c.lastMaintenanceErr
+	c.maintenanceMu.Unlock()
+	if c.forwarder != nil {
+		stats.Forwarding = c.forwarder.Status()
+	}
+	return stats, nil
+}
@@ -0,0 +1,263 @@
+package audit
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	dbutil "github.com/k8s-dashboard/backend/internal/db"
+)
+
+// ErrPacketCaptureNotFound 抓包记录不存在
+var ErrPacketCaptureNotFound = errors.New("抓包记录不存在")
+
+// 抓包状态
+const (
+	CaptureStatusRunning   = "running"
+	CaptureStatusCompleted = "completed"
+	CaptureStatusFailed    = "failed"
+)
+
+// PacketCapture 抓包记录，pcap 内容单独存储，仅下载时读取
+type PacketCapture struct {
+	ID              int64      `json:"id"`
+	User            string     `json:"user"`
+	Cluster         string     `json:"cluster"`
+	Namespace       string     `json:"namespace"`
+	Pod             string     `json:"pod"`
+	Container       string     `json:"container"` // 执行抓包的临时容器
+	Interface       string     `json:"interface"`
+	Filter          string     `json:"filter"`
+	DurationSeconds int        `json:"durationSeconds"`
+	Status          string     `json:"status"`
+	Message         string     `json:"message,omitempty"`
+	Bytes           int64      `json:"bytes"`
+	Truncated       bool       `json:"truncated"`
+	StartedAt       time.Time  `json:"startedAt"`
+	EndedAt         *time.Time `json:"endedAt,omitempty"`
+}
+
+// PacketCaptureListParams 抓包记录查询参数
+type PacketCaptureListParams struct {
+	Page      int
+	PageSize  int
+	User      string
+	Namespace string
+	Pod       string
+}
+
+// initCaptureSchema 初始化抓包记录表
+func (c *Client) initCaptureSchema() error {
+	var schema string
+	if c.dialect == dbutil.DialectSQLite {
+		schema = `
+		CREATE TABLE IF NOT EXISTS packet_captures (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			"user" TEXT NOT NULL,
+			cluster TEXT DEFAULT 'default',
+			namespace TEXT NOT NULL,
+			pod TEXT NOT NULL,
+			container TEXT,
+			interface TEXT,
+			filter TEXT,
+			duration_seconds INTEGER DEFAULT 0,
+			status TEXT NOT NULL,
+			message TEXT,
+			bytes INTEGER DEFAULT 0,
+			truncated BOOLEAN DEFAULT 0,
+			data BLOB,
+			started_at DATETIME NOT NULL,
+			ended_at DATETIME
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_packet_captures_user ON packet_captures("user");
+		CREATE INDEX IF NOT EXISTS idx_packet_captures_pod ON packet_captures(namespace, pod);
+		CREATE INDEX IF NOT EXISTS idx_packet_captures_started_at ON packet_captures(started_at DESC);
+		`
+	} else {
+		schema = `
+		CREATE TABLE IF NOT EXISTS packet_captures (
+			id BIGSERIAL PRIMARY KEY,
+			"user" VARCHAR(255) NOT NULL,
+			cluster VARCHAR(100) DEFAULT 'default',
+			namespace VARCHAR(255) NOT NULL,
+			pod VARCHAR(255) NOT NULL,
+			container VARCHAR(255),
+			interface VARCHAR(64),
+			filter TEXT,
+			duration_seconds INTEGER DEFAULT 0,
+			status VARCHAR(20) NOT NULL,
+			message TEXT,
+			bytes BIGINT DEFAULT 0,
+			truncated BOOLEAN DEFAULT FALSE,
+			data BYTEA,
+			started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			ended_at TIMESTAMP WITH TIME ZONE
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_packet_captures_user ON packet_captures("user");
+		CREATE INDEX IF NOT EXISTS idx_packet_captures_pod ON packet_captures(namespace, pod);
+		CREATE INDEX IF NOT EXISTS idx_packet_captures_started_at ON packet_captures(started_at DESC);
+		`
+	}
+
+	if _, err := c.db.Exec(schema); err != nil {
+		return err
+	}
+
+	// 服务重启后后台抓包任务已丢失，遗留的 running 记录直接置为失败
+	_, err := c.db.Exec(`
+		UPDATE packet_captures SET status = $1, message = $2, ended_at = $3
+		WHERE status = $4
+	`, CaptureStatusFailed, "服务重启，抓包已中断", time.Now(), CaptureStatusRunning)
+	return err
+}
+
+// StartPacketCapture 创建抓包记录，状态为 running
+func (c *Client) StartPacketCapture(p *PacketCapture) error {
+	if p.StartedAt.IsZero() {
+		p.StartedAt = time.Now()
+	}
+	p.Status = CaptureStatusRunning
+
+	if c.dialect == dbutil.DialectSQLite {
+		result, err := c.db.Exec(`
+			INSERT INTO packet_captures ("user", cluster, namespace, pod, container, interface, filter, duration_seconds, status, started_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		`, p.User, p.Cluster, p.Namespace, p.Pod, p.Container, p.Interface, p.Filter, p.DurationSeconds, p.Status, p.StartedAt)
+		if err != nil {
+			return err
+		}
+		p.ID, err = result.LastInsertId()
+		return err
+	}
+
+	return c.db.QueryRow(`
+		INSERT INTO packet_captures ("user", cluster, namespace, pod, container, interface, filter, duration_seconds, status, started_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id
+	`, p.User, p.Cluster, p.Namespace, p.Pod, p.Container, p.Interface, p.Filter, p.DurationSeconds, p.Status, p.StartedAt).Scan(&p.ID)
+}
+
+// FinishPacketCapture 写入抓包结果，失败时 data 为空
+func (c *Client) FinishPacketCapture(id int64, status, message string, data []byte, truncated bool) error {
+	_, err := c.db.Exec(`
+		UPDATE packet_captures SET status = $1, message = $2, data = $3, bytes = $4, truncated = $5, ended_at = $6
+		WHERE id = $7
+	`, status, message, data, len(data), truncated, time.Now(), id)
+	return err
+}
+
+// HasRunningPacketCapture 判断 Pod 是否有进行中的抓包
+func (c *Client) HasRunningPacketCapture(cluster, namespace, pod string) (bool, error) {
+	var count int
+	err := c.db.QueryRow(`
+		SELECT COUNT(*) FROM packet_captures
+		WHERE COALESCE(cluster, 'default') = $1 AND namespace = $2 AND pod = $3 AND status = $4
+	`, cluster, namespace, pod, CaptureStatusRunning).Scan(&count)
+	return count > 0, err
+}
+
+const packetCaptureColumns = `id, "user", COALESCE(cluster, 'default'), namespace, pod, COALESCE(container, ''),
+	COALESCE(interface, ''), COALESCE(filter, ''), COALESCE(duration_seconds, 0), status, COALESCE(message, ''),
+	COALESCE(bytes, 0), COALESCE(truncated, FALSE), started_at, ended_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPacketCapture(row rowScanner, extra ...interface{}) (*PacketCapture, error) {
+	var p PacketCapture
+	var endedAt sql.NullTime
+	dest := []interface{}{&p.ID, &p.User, &p.Cluster, &p.Namespace, &p.Pod, &p.Container,
+		&p.Interface, &p.Filter, &p.DurationSeconds, &p.Status, &p.Message,
+		&p.Bytes, &p.Truncated, &p.StartedAt, &endedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	if endedAt.Valid {
+		p.EndedAt = &endedAt.Time
+	}
+	return &p, nil
+}
+
+// GetPacketCapture 获取抓包记录（不含 pcap 内容）
+func (c *Client) GetPacketCapture(id int64) (*PacketCapture, error) {
+	p, err := scanPacketCapture(c.db.QueryRow(`SELECT `+packetCaptureColumns+` FROM packet_captures WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrPacketCaptureNotFound
+	}
+	return p, err
+}
+
+// GetPacketCaptureData 获取抓包记录及 pcap 内容
+func (c *Client) GetPacketCaptureData(id int64) (*PacketCapture, []byte, error) {
+	var data []byte
+	p, err := scanPacketCapture(c.db.QueryRow(`SELECT `+packetCaptureColumns+`, data FROM packet_captures WHERE id = $1`, id), &data)
+	if err == sql.ErrNoRows {
+		return nil, nil, ErrPacketCaptureNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return p, data, nil
+}
+
+// ListPacketCaptures 查询抓包记录列表（不含 pcap 内容）
+func (c *Client) ListPacketCaptures(params PacketCaptureListParams) ([]PacketCapture, int64, error) {
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.PageSize < 1 {
+		params.PageSize = 20
+	}
+	if params.PageSize > 100 {
+		params.PageSize = 100
+	}
+
+	where := "WHERE 1=1"
+	args := []interface{}{}
+	argIndex := 1
+
+	if params.User != "" {
+		where += fmt.Sprintf(" AND \"user\" = $%d", argIndex)
+		args = append(args, params.User)
+		argIndex++
+	}
+	if params.Namespace != "" {
+		where += fmt.Sprintf(" AND namespace = $%d", argIndex)
+		args = append(args, params.Namespace)
+		argIndex++
+	}
+	if params.Pod != "" {
+		where += fmt.Sprintf(" AND pod = $%d", argIndex)
+		args = append(args, params.Pod)
+		argIndex++
+	}
+
+	var total int64
+	if err := c.db.QueryRow("SELECT COUNT(*) FROM packet_captures "+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM packet_captures %s ORDER BY started_at DESC LIMIT $%d OFFSET $%d`,
+		packetCaptureColumns, where, argIndex, argIndex+1)
+	args = append(args, params.PageSize, (params.Page-1)*params.PageSize)
+
+	rows, err := c.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := []PacketCapture{}
+	for rows.Next() {
+		p, err := scanPacketCapture(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, *p)
+	}
+	return items, total, rows.Err()
+}
@@ -2,7 +2,9 @@ package audit
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	dbutil "github.com/k8s-dashboard/backend/internal/db"
@@ -13,30 +15,41 @@ type AuditLog struct {
 	ID           int64     `json:"id"`
 	Timestamp    time.Time `json:"timestamp"`
 	User         string    `json:"user"`
-	Action       string    `json:"action"`       // GET, POST, PUT, DELETE
-	Resource     string    `json:"resource"`     // pods, deployments, nodes, etc.
-	ResourceName string    `json:"resourceName"` // 资源名称
-	Namespace    string    `json:"namespace"`    // 命名空间（如果适用）
-	Cluster      string    `json:"cluster"`      // 集群名称
-	StatusCode   int       `json:"statusCode"`   // HTTP 状态码
-	ClientIP     string    `json:"clientIP"`     // 客户端 IP
-	UserAgent    string    `json:"userAgent"`    // 用户代理
-	RequestBody  string    `json:"requestBody"`  // 请求体（敏感信息已过滤）
-	Duration     int64     `json:"duration"`     // 请求耗时（毫秒）
-	Message      string    `json:"message"`      // 额外信息
+	UserID       int64     `json:"userId,omitempty"` // 已认证用户的 ID
+	Role         string    `json:"role,omitempty"`   // 操作时的用户角色
+	Action       string    `json:"action"`           // GET, POST, PUT, DELETE
+	Resource     string    `json:"resource"`         // pods, deployments, nodes, etc.
+	ResourceName string    `json:"resourceName"`     // 资源名称
+	Namespace    string    `json:"namespace"`        // 命名空间（如果适用）
+	Cluster      string    `json:"cluster"`          // 集群名称
+	StatusCode   int       `json:"statusCode"`       // HTTP 状态码
+	ClientIP     string    `json:"clientIP"`         // 客户端 IP
+	UserAgent    string    `json:"userAgent"`        // 用户代理
+	RequestBody  string    `json:"requestBody"`      // 请求体（敏感信息已过滤）
+	Duration     int64     `json:"duration"`         // 请求耗时（毫秒）
+	Message      string    `json:"message"`          // 额外信息
+	Diff         string    `json:"diff,omitempty"`   // 更新操作前后对象的差异（JSON 数组），见 Diff
 }
 
+// auditLogColumns 查询审计日志的列，与 queryLogs 的扫描顺序一致
+const auditLogColumns = `id, timestamp, "user", action, resource, COALESCE(resource_name, ''),
+		       COALESCE(namespace, ''), COALESCE(cluster, 'default'),
+		       COALESCE(status_code, 0), COALESCE(client_ip, ''),
+		       COALESCE(user_agent, ''), COALESCE(request_body, ''),
+		       COALESCE(duration, 0), COALESCE(message, ''), COALESCE(diff, ''),
+		       COALESCE(user_id, 0), COALESCE(role, '')`
+
 // ListParams 查询参数
 type ListParams struct {
-	Page      int       `form:"page"`
-	PageSize  int       `form:"pageSize"`
-	StartTime time.Time `form:"startTime"`
-	EndTime   time.Time `form:"endTime"`
-	User      string    `form:"user"`
-	Action    string    `form:"action"`
-	Resource  string    `form:"resource"`
-	Namespace string    `form:"namespace"`
-	Cluster   string    `form:"cluster"`
+	Page      int       `form:"page" json:"-"`
+	PageSize  int       `form:"pageSize" json:"-"`
+	StartTime time.Time `form:"startTime" json:"startTime,omitempty"`
+	EndTime   time.Time `form:"endTime" json:"endTime,omitempty"`
+	User      string    `form:"user" json:"user,omitempty"`
+	Action    string    `form:"action" json:"action,omitempty"`
+	Resource  string    `form:"resource" json:"resource,omitempty"`
+	Namespace string    `form:"namespace" json:"namespace,omitempty"`
+	Cluster   string    `form:"cluster" json:"cluster,omitempty"`
 }
 
 // ListResponse 列表响应
@@ -49,8 +62,20 @@ type ListResponse struct {
 
 // Client 审计日志客户端
 type Client struct {
-	db      *sql.DB
-	dialect dbutil.Dialect
+	db         *sql.DB
+	dialect    dbutil.Dialect
+	terminal   TerminalRecordingConfig
+	archiveDir string
+	forwarder  *Forwarder
+
+	anomalyDetector *AnomalyDetector
+	anomalyHandler  AnomalyHandler
+
+	// 最近一次数据维护的结果
+	maintenanceMu      sync.Mutex
+	retention          time.Duration
+	lastMaintenanceAt  *time.Time
+	lastMaintenanceErr string
 }
 
 // NewClient 创建审计日志客户端
@@ -64,79 +89,89 @@ func NewClient(db *sql.DB, dialect dbutil.Dialect) (*Client, error) {
 	if err := client.initSchema(); err != nil {
 		return nil, fmt.Errorf("初始化表结构失败: %w", err)
 	}
+	for column, definition := range map[string]string{"diff": "TEXT", "user_id": "BIGINT", "role": "VARCHAR(50)"} {
+		if err := dbutil.EnsureColumn(db, dialect, "audit_logs", column, definition); err != nil {
+			return nil, fmt.Errorf("迁移审计日志表失败: %w", err)
+		}
+	}
+	if err := client.initTerminalSchema(); err != nil {
+		return nil, fmt.Errorf("初始化终端会话表失败: %w", err)
+	}
+	if err := client.initCaptureSchema(); err != nil {
+		return nil, fmt.Errorf("初始化抓包记录表失败: %w", err)
+	}
+	if err := client.initExportSchema(); err != nil {
+		return nil, fmt.Errorf("初始化审计导出表失败: %w", err)
+	}
+	if err := client.initArchiveSchema(); err != nil {
+		return nil, fmt.Errorf("初始化审计归档表失败: %w", err)
+	}
+	if err := client.initAnomalySchema(); err != nil {
+		return nil, fmt.Errorf("初始化审计异常表失败: %w", err)
+	}
 
 	return client, nil
 }
 
 // initSchema 初始化表结构
 func (c *Client) initSchema() error {
-	var schema string
-	if c.dialect == dbutil.DialectSQLite {
-		schema = `
-		CREATE TABLE IF NOT EXISTS audit_logs (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
-			"user" TEXT NOT NULL DEFAULT 'anonymous',
-			action TEXT NOT NULL,
-			resource TEXT NOT NULL,
-			resource_name TEXT,
-			namespace TEXT,
-			cluster TEXT DEFAULT 'default',
-			status_code INTEGER,
-			client_ip TEXT,
-			user_agent TEXT,
-			request_body TEXT,
-			duration INTEGER,
-			message TEXT
-		);
-
-		CREATE INDEX IF NOT EXISTS idx_audit_logs_timestamp ON audit_logs(timestamp DESC);
-		CREATE INDEX IF NOT EXISTS idx_audit_logs_user ON audit_logs("user");
-		CREATE INDEX IF NOT EXISTS idx_audit_logs_action ON audit_logs(action);
-		CREATE INDEX IF NOT EXISTS idx_audit_logs_resource ON audit_logs(resource);
-		CREATE INDEX IF NOT EXISTS idx_audit_logs_namespace ON audit_logs(namespace);
-		`
-	} else {
-		schema = `
-		CREATE TABLE IF NOT EXISTS audit_logs (
-			id BIGSERIAL PRIMARY KEY,
-			timestamp TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-			"user" VARCHAR(255) NOT NULL DEFAULT 'anonymous',
-			action VARCHAR(20) NOT NULL,
-			resource VARCHAR(100) NOT NULL,
-			resource_name VARCHAR(255),
-			namespace VARCHAR(255),
-			cluster VARCHAR(100) DEFAULT 'default',
-			status_code INT,
-			client_ip VARCHAR(50),
-			user_agent TEXT,
-			request_body TEXT,
-			duration BIGINT,
-			message TEXT
-		);
-
-		CREATE INDEX IF NOT EXISTS idx_audit_logs_timestamp ON audit_logs(timestamp DESC);
-		CREATE INDEX IF NOT EXISTS idx_audit_logs_user ON audit_logs("user");
-		CREATE INDEX IF NOT EXISTS idx_audit_logs_action ON audit_logs(action);
-		CREATE INDEX IF NOT EXISTS idx_audit_logs_resource ON audit_logs(resource);
-		CREATE INDEX IF NOT EXISTS idx_audit_logs_namespace ON audit_logs(namespace);
-		`
+	// Postgres 下 audit_logs 为按月分区表，见 partition.go
+	if c.dialect != dbutil.DialectSQLite {
+		return c.initPartitionedSchema()
 	}
 
+	schema := `
+	CREATE TABLE IF NOT EXISTS audit_logs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
+		"user" TEXT NOT NULL DEFAULT 'anonymous',
+		user_id INTEGER,
+		role TEXT,
+		action TEXT NOT NULL,
+		resource TEXT NOT NULL,
+		resource_name TEXT,
+		namespace TEXT,
+		cluster TEXT DEFAULT 'default',
+		status_code INTEGER,
+		client_ip TEXT,
+		user_agent TEXT,
+		request_body TEXT,
+		duration INTEGER,
+		message TEXT,
+		diff TEXT
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_audit_logs_timestamp ON audit_logs(timestamp DESC);
+	CREATE INDEX IF NOT EXISTS idx_audit_logs_user ON audit_logs("user");
+	CREATE INDEX IF NOT EXISTS idx_audit_logs_action ON audit_logs(action);
+	CREATE INDEX IF NOT EXISTS idx_audit_logs_resource ON audit_logs(resource);
+	CREATE INDEX IF NOT EXISTS idx_audit_logs_namespace ON audit_logs(namespace);
+	`
+
 	_, err := c.db.Exec(schema)
 	return err
 }
 
+// SetForwarder 设置审计日志外部转发器，每条审计日志写库的同时转发
+func (c *Client) SetForwarder(f *Forwarder) {
+	c.forwarder = f
+}
+
 // Log 记录审计日志
 func (c *Client) Log(log *AuditLog) error {
 	query := `
 		INSERT INTO audit_logs (
 			timestamp, "user", action, resource, resource_name,
 			namespace, cluster, status_code, client_ip, user_agent,
-			request_body, duration, message
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+			request_body, duration, message, diff, user_id, role
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 	`
 
+	// 外部转发与本地写库相互独立，写库失败时仍然转发
+	if c.forwarder != nil {
+		c.forwarder.Enqueue(*log)
+	}
+
 	_, err := c.db.Exec(query,
 		log.Timestamp,
 		log.User,
@@ -151,9 +186,13 @@ func (c *Client) Log(log *AuditLog) error {
 		log.RequestBody,
 		log.Duration,
 		log.Message,
+		log.Diff,
+		sql.NullInt64{Int64: log.UserID, Valid: log.UserID != 0},
+		log.Role,
 	)
 
-	return err
+	// 写库失败时异常检测仍然统计
+	return errors.Join(err, c.detectAnomalies(log))
 }
 
 // List 查询审计日志
@@ -169,7 +208,47 @@ func (c *Client) List(params ListParams) (*ListResponse, error) {
 		params.PageSize = 100
 	}
 
-	// 构建查询条件
+	where, args := params.where()
+	argIndex := len(args) + 1
+
+	// 查询总数
+	countQuery := "SELECT COUNT(*) FROM audit_logs " + where
+	var total int64
+	if err := c.db.QueryRow(countQuery, args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	// 查询数据
+	offset := (params.Page - 1) * params.PageSize
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM audit_logs %s
+		ORDER BY timestamp DESC
+		LIMIT $%d OFFSET $%d
+	`, auditLogColumns, where, argIndex, argIndex+1)
+
+	args = append(args, params.PageSize, offset)
+
+	logs, err := c.queryLogs(query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := int(total) / params.PageSize
+	if int(total)%params.PageSize > 0 {
+		pages++
+	}
+
+	return &ListResponse{
+		Items: logs,
+		Total: total,
+		Page:  params.Page,
+		Pages: pages,
+	}, nil
+}
+
+// where 按过滤条件构建 WHERE 子句，分页参数不参与
+func (params ListParams) where() (string, []interface{}) {
 	where := "WHERE 1=1"
 	args := []interface{}{}
 	argIndex := 1
@@ -207,62 +286,16 @@ func (c *Client) List(params ListParams) (*ListResponse, error) {
 	if params.Cluster != "" {
 		where += fmt.Sprintf(" AND cluster = $%d", argIndex)
 		args = append(args, params.Cluster)
-		argIndex++
 	}
+	return where, args
+}
 
-	// 查询总数
-	countQuery := "SELECT COUNT(*) FROM audit_logs " + where
+// Count 统计符合过滤条件的审计日志数量
+func (c *Client) Count(params ListParams) (int64, error) {
+	where, args := params.where()
 	var total int64
-	if err := c.db.QueryRow(countQuery, args...).Scan(&total); err != nil {
-		return nil, err
-	}
-
-	// 查询数据
-	offset := (params.Page - 1) * params.PageSize
-	query := fmt.Sprintf(`
-		SELECT id, timestamp, "user", action, resource, resource_name,
-		       COALESCE(namespace, ''), COALESCE(cluster, 'default'),
-		       COALESCE(status_code, 0), COALESCE(client_ip, ''),
-		       COALESCE(user_agent, ''), COALESCE(request_body, ''),
-		       COALESCE(duration, 0), COALESCE(message, '')
-		FROM audit_logs %s
-		ORDER BY timestamp DESC
-		LIMIT $%d OFFSET $%d
-	`, where, argIndex, argIndex+1)
-
-	args = append(args, params.PageSize, offset)
-
-	rows, err := c.db.Query(query, args...)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var logs []AuditLog
-	for rows.Next() {
-		var log AuditLog
-		err := rows.Scan(
-			&log.ID, &log.Timestamp, &log.User, &log.Action, &log.Resource,
-			&log.ResourceName, &log.Namespace, &log.Cluster, &log.StatusCode,
-			&log.ClientIP, &log.UserAgent, &log.RequestBody, &log.Duration, &log.Message,
-		)
-		if err != nil {
-			return nil, err
-		}
-		logs = append(logs, log)
-	}
-
-	pages := int(total) / params.PageSize
-	if int(total)%params.PageSize > 0 {
-		pages++
-	}
-
-	return &ListResponse{
-		Items: logs,
-		Total: total,
-		Page:  params.Page,
-		Pages: pages,
-	}, nil
+	err := c.db.QueryRow("SELECT COUNT(*) FROM audit_logs "+where, args...).Scan(&total)
+	return total, err
 }
 
 // GetStats 获取审计日志统计
@@ -0,0 +1,156 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxDiffChanges 单条审计记录保留的最多变更条数，超出部分合并为一条 truncated 记录
+	maxDiffChanges = 200
+	// maxDiffValueBytes 单个字段值序列化后的最大长度，超出时截断为字符串
+	maxDiffValueBytes = 1024
+)
+
+// diffIgnoredPaths 每次更新都会变化、不反映用户意图的字段
+var diffIgnoredPaths = map[string]bool{
+	"/metadata/resourceVersion": true,
+	"/metadata/generation":      true,
+	"/metadata/managedFields":   true,
+	"/status":                   true,
+}
+
+// DiffChange 单个字段的变更，Path 为 JSON Pointer（RFC 6901）
+type DiffChange struct {
+	Op     string      `json:"op"` // add, remove, replace, truncated
+	Path   string      `json:"path"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+// Diff 比较变更前后的对象，返回 JSON 数组形式的字段差异。
+// 任一对象为空或没有差异时返回空字符串
+func Diff(before, after interface{}) (string, error) {
+	beforeValue, err := toJSONValue(before)
+	if err != nil {
+		return "", err
+	}
+	afterValue, err := toJSONValue(after)
+	if err != nil {
+		return "", err
+	}
+	if beforeValue == nil || afterValue == nil {
+		return "", nil
+	}
+
+	var changes []DiffChange
+	diffValues("", beforeValue, afterValue, &changes)
+	if len(changes) == 0 {
+		return "", nil
+	}
+	if len(changes) > maxDiffChanges {
+		omitted := len(changes) - maxDiffChanges
+		changes = append(changes[:maxDiffChanges], DiffChange{Op: "truncated", Path: "", After: omitted})
+	}
+
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func toJSONValue(obj interface{}) (interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func diffValues(path string, before, after interface{}, changes *[]DiffChange) {
+	if diffIgnoredPaths[path] {
+		return
+	}
+
+	switch b := before.(type) {
+	case map[string]interface{}:
+		if a, ok := after.(map[string]interface{}); ok {
+			diffMaps(path, b, a, changes)
+			return
+		}
+	case []interface{}:
+		if a, ok := after.([]interface{}); ok {
+			diffSlices(path, b, a, changes)
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(before, after) {
+		*changes = append(*changes, DiffChange{Op: "replace", Path: path, Before: truncateDiffValue(before), After: truncateDiffValue(after)})
+	}
+}
+
+func diffMaps(path string, before, after map[string]interface{}, changes *[]DiffChange) {
+	keys := make([]string, 0, len(before)+len(after))
+	for key := range before {
+		keys = append(keys, key)
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		child := path + "/" + escapePointer(key)
+		if diffIgnoredPaths[child] {
+			continue
+		}
+		b, inBefore := before[key]
+		a, inAfter := after[key]
+		switch {
+		case !inAfter:
+			*changes = append(*changes, DiffChange{Op: "remove", Path: child, Before: truncateDiffValue(b)})
+		case !inBefore:
+			*changes = append(*changes, DiffChange{Op: "add", Path: child, After: truncateDiffValue(a)})
+		default:
+			diffValues(child, b, a, changes)
+		}
+	}
+}
+
+// diffSlices 按下标逐项比较；列表中间插入元素时会表现为后续各项的替换
+func diffSlices(path string, before, after []interface{}, changes *[]DiffChange) {
+	for i := 0; i < len(before) || i < len(after); i++ {
+		child := path + "/" + strconv.Itoa(i)
+		switch {
+		case i >= len(after):
+			*changes = append(*changes, DiffChange{Op: "remove", Path: child, Before: truncateDiffValue(before[i])})
+		case i >= len(before):
+			*changes = append(*changes, DiffChange{Op: "add", Path: child, After: truncateDiffValue(after[i])})
+		default:
+			diffValues(child, before[i], after[i], changes)
+		}
+	}
+}
+
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+func truncateDiffValue(value interface{}) interface{} {
+	data, err := json.Marshal(value)
+	if err != nil || len(data) <= maxDiffValueBytes {
+		return value
+	}
+	return string(data[:maxDiffValueBytes]) + "...[truncated]"
+}
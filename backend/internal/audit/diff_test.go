@@ -0,0 +1,95 @@
+package audit
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	before := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":            "web",
+			"resourceVersion": "1",
+			"labels":          map[string]interface{}{"app.kubernetes.io/name": "web", "tier": "frontend"},
+		},
+		"spec": map[string]interface{}{
+			"replicas":   1,
+			"containers": []interface{}{map[string]interface{}{"name": "web", "image": "nginx:1.25"}},
+		},
+		"status": map[string]interface{}{"readyReplicas": 1},
+	}
+	after := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":            "web",
+			"resourceVersion": "2",
+			"labels":          map[string]interface{}{"app.kubernetes.io/name": "web", "track": "stable"},
+		},
+		"spec": map[string]interface{}{
+			"replicas": 3,
+			"containers": []interface{}{
+				map[string]interface{}{"name": "web", "image": "nginx:1.27"},
+				map[string]interface{}{"name": "sidecar", "image": "envoy"},
+			},
+		},
+		"status": map[string]interface{}{"readyReplicas": 3},
+	}
+
+	diff, err := Diff(before, after)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	var changes []DiffChange
+	if err := json.Unmarshal([]byte(diff), &changes); err != nil {
+		t.Fatalf("invalid diff %q: %v", diff, err)
+	}
+
+	got := make([]string, 0, len(changes))
+	for _, change := range changes {
+		got = append(got, change.Op+" "+change.Path)
+	}
+	want := []string{
+		"remove /metadata/labels/tier",
+		"add /metadata/labels/track",
+		"replace /spec/containers/0/image",
+		"add /spec/containers/1",
+		"replace /spec/replicas",
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected changes:\n got %v\nwant %v", got, want)
+	}
+	if changes[4].Before != float64(1) || changes[4].After != float64(3) {
+		t.Fatalf("unexpected replicas change: %+v", changes[4])
+	}
+
+	if diff, err := Diff(before, before); err != nil || diff != "" {
+		t.Fatalf("expected no diff for identical objects, got %q, %v", diff, err)
+	}
+	var missing *struct{}
+	if diff, err := Diff(missing, after); err != nil || diff != "" {
+		t.Fatalf("expected no diff when before is nil, got %q, %v", diff, err)
+	}
+}
+
+func TestDiffTruncatesLargeChanges(t *testing.T) {
+	before := map[string]interface{}{}
+	after := map[string]interface{}{"data": strings.Repeat("x", maxDiffValueBytes*2)}
+	for i := 0; i < maxDiffChanges+10; i++ {
+		after[strings.Repeat("k", i+1)] = i
+	}
+
+	diff, err := Diff(before, after)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	var changes []DiffChange
+	if err := json.Unmarshal([]byte(diff), &changes); err != nil {
+		t.Fatalf("invalid diff: %v", err)
+	}
+	if len(changes) != maxDiffChanges+1 || changes[maxDiffChanges].Op != "truncated" {
+		t.Fatalf("expected truncated marker, got %d changes", len(changes))
+	}
+	if value, ok := changes[0].After.(string); !ok || !strings.HasSuffix(value, "...[truncated]") {
+		t.Fatalf("expected large value to be truncated, got %v", changes[0].After)
+	}
+}
@@ -0,0 +1,381 @@
+package audit
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	dbutil "github.com/k8s-dashboard/backend/internal/db"
+)
+
+// 导出格式
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+)
+
+// 导出任务状态
+const (
+	ExportStatusRunning   = "running"
+	ExportStatusCompleted = "completed"
+	ExportStatusFailed    = "failed"
+)
+
+const (
+	// MaxExportRows 单次导出的最大行数
+	MaxExportRows = 1000000
+	// StreamExportRows 超过该行数的导出转为后台生成，完成后下载
+	StreamExportRows = 50000
+	// exportBatchSize 分批读取，避免长时间占用数据库连接（SQLite 只有一个连接）
+	exportBatchSize = 1000
+	// exportRetention 后台导出文件保留时间
+	exportRetention = 7 * 24 * time.Hour
+)
+
+// ErrExportNotFound 导出任务不存在
+var ErrExportNotFound = errors.New("导出任务不存在")
+
+// exportColumns CSV 表头，与 AuditLog 的 JSON 字段一致
+var exportColumns = []string{
+	"id", "timestamp", "user", "action", "resource", "resourceName", "namespace", "cluster",
+	"statusCode", "clientIP", "userAgent", "requestBody", "duration", "message", "diff",
+	"userId", "role",
+}
+
+// AuditExport 后台导出任务，结果以 gzip 压缩后保存，仅下载时读取
+type AuditExport struct {
+	ID          int64      `json:"id"`
+	User        string     `json:"user"`
+	Format      string     `json:"format"`
+	Filters     ListParams `json:"filters"`
+	Limit       int        `json:"limit"`
+	Status      string     `json:"status"`
+	Message     string     `json:"message,omitempty"`
+	Rows        int64      `json:"rows"`
+	Bytes       int64      `json:"bytes"` // 压缩后大小
+	CreatedAt   time.Time  `json:"createdAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+// ValidExportFormat 判断导出格式是否支持
+func ValidExportFormat(format string) bool {
+	return format == ExportFormatCSV || format == ExportFormatNDJSON
+}
+
+// initExportSchema 初始化导出任务表
+func (c *Client) initExportSchema() error {
+	var schema string
+	if c.dialect == dbutil.DialectSQLite {
+		schema = `
+		CREATE TABLE IF NOT EXISTS audit_exports (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			"user" TEXT NOT NULL,
+			format TEXT NOT NULL,
+			filters TEXT,
+			row_limit INTEGER NOT NULL,
+			status TEXT NOT NULL,
+			message TEXT,
+			row_count INTEGER DEFAULT 0,
+			bytes INTEGER DEFAULT 0,
+			data BLOB,
+			created_at DATETIME NOT NULL,
+			completed_at DATETIME
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_audit_exports_created_at ON audit_exports(created_at DESC);
+		`
+	} else {
+		schema = `
+		CREATE TABLE IF NOT EXISTS audit_exports (
+			id BIGSERIAL PRIMARY KEY,
+			"user" VARCHAR(255) NOT NULL,
+			format VARCHAR(20) NOT NULL,
+			filters TEXT,
+			row_limit INTEGER NOT NULL,
+			status VARCHAR(20) NOT NULL,
+			message TEXT,
+			row_count BIGINT DEFAULT 0,
+			bytes BIGINT DEFAULT 0,
+			data BYTEA,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			completed_at TIMESTAMP WITH TIME ZONE
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_audit_exports_created_at ON audit_exports(created_at DESC);
+		`
+	}
+
+	if _, err := c.db.Exec(schema); err != nil {
+		return err
+	}
+
+	// 服务重启后后台导出任务已丢失，遗留的 running 记录直接置为失败
+	_, err := c.db.Exec(`
+		UPDATE audit_exports SET status = $1, message = $2, completed_at = $3
+		WHERE status = $4
+	`, ExportStatusFailed, "服务重启，导出已中断", time.Now(), ExportStatusRunning)
+	return err
+}
+
+// Export 按过滤条件将审计日志按写入顺序写入 w，返回写入的行数。limit 不大于 0 时使用 MaxExportRows
+func (c *Client) Export(ctx context.Context, params ListParams, format string, limit int, w io.Writer) (int64, error) {
+	if !ValidExportFormat(format) {
+		return 0, fmt.Errorf("不支持的导出格式: %s", format)
+	}
+	if limit <= 0 || limit > MaxExportRows {
+		limit = MaxExportRows
+	}
+
+	var csvWriter *csv.Writer
+	var encoder *json.Encoder
+	if format == ExportFormatCSV {
+		csvWriter = csv.NewWriter(w)
+		if err := csvWriter.Write(exportColumns); err != nil {
+			return 0, err
+		}
+	} else {
+		encoder = json.NewEncoder(w)
+	}
+
+	where, args := params.where()
+	argIndex := len(args) + 1
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM audit_logs %s AND id > $%d
+		ORDER BY id
+		LIMIT $%d
+	`, auditLogColumns, where, argIndex, argIndex+1)
+
+	var written int64
+	var lastID int64
+	for written < int64(limit) {
+		if err := ctx.Err(); err != nil {
+			return written, err
+		}
+		batch := exportBatchSize
+		if remaining := int64(limit) - written; remaining < int64(batch) {
+			batch = int(remaining)
+		}
+		logs, err := c.queryLogs(query, append(args, lastID, batch))
+		if err != nil {
+			return written, err
+		}
+
+		for i := range logs {
+			if csvWriter != nil {
+				err = csvWriter.Write(exportRecord(&logs[i]))
+			} else {
+				err = encoder.Encode(&logs[i])
+			}
+			if err != nil {
+				return written, err
+			}
+		}
+		if csvWriter != nil {
+			csvWriter.Flush()
+			if err := csvWriter.Error(); err != nil {
+				return written, err
+			}
+		}
+
+		written += int64(len(logs))
+		if len(logs) < batch {
+			break
+		}
+		lastID = logs[len(logs)-1].ID
+	}
+	return written, nil
+}
+
+// queryLogs 执行按 auditLogColumns 选列的查询，读取完成后立即释放连接
+func (c *Client) queryLogs(query string, args []interface{}) ([]AuditLog, error) {
+	rows, err := c.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logs []AuditLog
+	for rows.Next() {
+		var log AuditLog
+		err := rows.Scan(
+			&log.ID, &log.Timestamp, &log.User, &log.Action, &log.Resource,
+			&log.ResourceName, &log.Namespace, &log.Cluster, &log.StatusCode,
+			&log.ClientIP, &log.UserAgent, &log.RequestBody, &log.Duration, &log.Message, &log.Diff,
+			&log.UserID, &log.Role,
+		)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, log)
+	}
+	return logs, rows.Err()
+}
+
+func exportRecord(log *AuditLog) []string {
+	return []string{
+		strconv.FormatInt(log.ID, 10),
+		log.Timestamp.UTC().Format(time.RFC3339),
+		log.User,
+		log.Action,
+		log.Resource,
+		log.ResourceName,
+		log.Namespace,
+		log.Cluster,
+		strconv.Itoa(log.StatusCode),
+		log.ClientIP,
+		log.UserAgent,
+		log.RequestBody,
+		strconv.FormatInt(log.Duration, 10),
+		log.Message,
+		log.Diff,
+		strconv.FormatInt(log.UserID, 10),
+		log.Role,
+	}
+}
+
+// StartExport 创建后台导出任务并立即返回，导出在后台执行，结果通过 GetExportData 下载
+func (c *Client) StartExport(user string, params ListParams, format string, limit int) (*AuditExport, error) {
+	if !ValidExportFormat(format) {
+		return nil, fmt.Errorf("不支持的导出格式: %s", format)
+	}
+	if limit <= 0 || limit > MaxExportRows {
+		limit = MaxExportRows
+	}
+	params.Page, params.PageSize = 0, 0
+
+	// 顺带清理过期的导出文件
+	if _, err := c.db.Exec("DELETE FROM audit_exports WHERE created_at < $1", time.Now().Add(-exportRetention)); err != nil {
+		return nil, err
+	}
+
+	filters, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	e := &AuditExport{
+		User:      user,
+		Format:    format,
+		Filters:   params,
+		Limit:     limit,
+		Status:    ExportStatusRunning,
+		CreatedAt: time.Now(),
+	}
+
+	if c.dialect == dbutil.DialectSQLite {
+		result, err := c.db.Exec(`
+			INSERT INTO audit_exports ("user", format, filters, row_limit, status, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, e.User, e.Format, string(filters), e.Limit, e.Status, e.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if e.ID, err = result.LastInsertId(); err != nil {
+			return nil, err
+		}
+	} else {
+		err := c.db.QueryRow(`
+			INSERT INTO audit_exports ("user", format, filters, row_limit, status, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id
+		`, e.User, e.Format, string(filters), e.Limit, e.Status, e.CreatedAt).Scan(&e.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	go c.runExport(e.ID, params, format, limit)
+	return e, nil
+}
+
+// runExport 执行后台导出并写回结果
+func (c *Client) runExport(id int64, params ListParams, format string, limit int) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	rows, err := c.Export(context.Background(), params, format, limit, zw)
+	if err == nil {
+		err = zw.Close()
+	}
+
+	status, message, data := ExportStatusCompleted, "", buf.Bytes()
+	if err != nil {
+		status, message, data = ExportStatusFailed, err.Error(), nil
+	}
+	_, _ = c.db.Exec(`
+		UPDATE audit_exports SET status = $1, message = $2, row_count = $3, bytes = $4, data = $5, completed_at = $6
+		WHERE id = $7
+	`, status, message, rows, len(data), data, time.Now(), id)
+}
+
+const auditExportColumns = `id, "user", format, COALESCE(filters, ''), row_limit, status, COALESCE(message, ''),
+	COALESCE(row_count, 0), COALESCE(bytes, 0), created_at, completed_at`
+
+func scanAuditExport(row rowScanner, extra ...interface{}) (*AuditExport, error) {
+	var e AuditExport
+	var filters string
+	var completedAt sql.NullTime
+	dest := []interface{}{&e.ID, &e.User, &e.Format, &filters, &e.Limit, &e.Status, &e.Message,
+		&e.Rows, &e.Bytes, &e.CreatedAt, &completedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	if filters != "" {
+		_ = json.Unmarshal([]byte(filters), &e.Filters)
+	}
+	if completedAt.Valid {
+		e.CompletedAt = &completedAt.Time
+	}
+	return &e, nil
+}
+
+// GetExport 获取导出任务（不含导出内容）
+func (c *Client) GetExport(id int64) (*AuditExport, error) {
+	e, err := scanAuditExport(c.db.QueryRow(`SELECT `+auditExportColumns+` FROM audit_exports WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrExportNotFound
+	}
+	return e, err
+}
+
+// GetExportData 获取导出任务及 gzip 压缩的导出内容
+func (c *Client) GetExportData(id int64) (*AuditExport, []byte, error) {
+	var data []byte
+	e, err := scanAuditExport(c.db.QueryRow(`SELECT `+auditExportColumns+`, data FROM audit_exports WHERE id = $1`, id), &data)
+	if err == sql.ErrNoRows {
+		return nil, nil, ErrExportNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return e, data, nil
+}
+
+// ListExports 按创建时间倒序列出导出任务，limit 不大于 0 时返回最近 50 条
+func (c *Client) ListExports(limit int) ([]AuditExport, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	rows, err := c.db.Query(`SELECT `+auditExportColumns+` FROM audit_exports ORDER BY created_at DESC, id DESC LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []AuditExport{}
+	for rows.Next() {
+		e, err := scanAuditExport(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *e)
+	}
+	return items, rows.Err()
+}
@@ -0,0 +1,392 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/k8s-dashboard/backend/internal/webhook"
+)
+
+const (
+	// DefaultForwardBuffer 每个外部渠道默认缓冲的审计日志条数，渠道不可用时超出部分丢弃最旧的记录
+	DefaultForwardBuffer = 10000
+	// forwardBatchSize 单次推送的最大条数
+	forwardBatchSize = 100
+	// forwardMaxBackoff 推送失败后的最大重试间隔
+	forwardMaxBackoff = time.Minute
+)
+
+// Sink 审计日志外部转发渠道（SIEM、日志平台等）
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, logs []AuditLog) error
+}
+
+// SinkStatus 转发渠道状态
+type SinkStatus struct {
+	Name       string     `json:"name"`
+	Sent       int64      `json:"sent"`
+	Buffered   int        `json:"buffered"`
+	Dropped    int64      `json:"dropped"` // 缓冲区满时丢弃的条数
+	LastError  string     `json:"lastError,omitempty"`
+	LastSentAt *time.Time `json:"lastSentAt,omitempty"`
+}
+
+// Forwarder 将审计日志异步转发到多个渠道，每个渠道独立缓冲与重试，互不阻塞
+type Forwarder struct {
+	workers []*forwardWorker
+	wg      sync.WaitGroup
+	stop    chan struct{}
+}
+
+type queuedLog struct {
+	seq uint64
+	log AuditLog
+}
+
+type forwardWorker struct {
+	sink   Sink
+	size   int
+	notify chan struct{}
+
+	mu         sync.Mutex
+	queue      []queuedLog
+	nextSeq    uint64
+	sent       int64
+	dropped    int64
+	lastErr    string
+	lastSentAt *time.Time
+}
+
+// NewForwarder 创建转发器并启动后台推送，bufferSize 不大于 0 时使用 DefaultForwardBuffer
+func NewForwarder(bufferSize int, sinks ...Sink) *Forwarder {
+	if bufferSize <= 0 {
+		bufferSize = DefaultForwardBuffer
+	}
+	f := &Forwarder{stop: make(chan struct{})}
+	for _, sink := range sinks {
+		w := &forwardWorker{sink: sink, size: bufferSize, notify: make(chan struct{}, 1)}
+		f.workers = append(f.workers, w)
+		f.wg.Add(1)
+		go func() {
+			defer f.wg.Done()
+			w.run(f.stop)
+		}()
+	}
+	return f
+}
+
+// Enqueue 缓冲一条审计日志，不阻塞调用方
+func (f *Forwarder) Enqueue(entry AuditLog) {
+	for _, w := range f.workers {
+		w.enqueue(entry)
+	}
+}
+
+// Status 返回各渠道的推送状态
+func (f *Forwarder) Status() []SinkStatus {
+	statuses := make([]SinkStatus, 0, len(f.workers))
+	for _, w := range f.workers {
+		w.mu.Lock()
+		statuses = append(statuses, SinkStatus{
+			Name:       w.sink.Name(),
+			Sent:       w.sent,
+			Buffered:   len(w.queue),
+			Dropped:    w.dropped,
+			LastError:  w.lastErr,
+			LastSentAt: w.lastSentAt,
+		})
+		w.mu.Unlock()
+	}
+	return statuses
+}
+
+// Close 停止后台推送，并在 ctx 到期前尽量发送缓冲中的记录
+func (f *Forwarder) Close(ctx context.Context) {
+	close(f.stop)
+	f.wg.Wait()
+	for _, w := range f.workers {
+		for ctx.Err() == nil {
+			more, err := w.send(ctx)
+			if err != nil || !more {
+				break
+			}
+		}
+	}
+}
+
+func (w *forwardWorker) enqueue(entry AuditLog) {
+	w.mu.Lock()
+	if len(w.queue) >= w.size {
+		w.queue = w.queue[1:]
+		w.dropped++
+	}
+	w.nextSeq++
+	w.queue = append(w.queue, queuedLog{seq: w.nextSeq, log: entry})
+	w.mu.Unlock()
+
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (w *forwardWorker) run(stop <-chan struct{}) {
+	backoff := time.Second
+	for {
+		select {
+		case <-stop:
+			return
+		case <-w.notify:
+		}
+
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			more, err := w.send(ctx)
+			cancel()
+			if err != nil {
+				// 渠道不可用：保留缓冲，退避后重试
+				select {
+				case <-stop:
+					return
+				case <-time.After(backoff):
+				}
+				backoff = min(backoff*2, forwardMaxBackoff)
+				continue
+			}
+			backoff = time.Second
+			if !more {
+				break
+			}
+		}
+	}
+}
+
+// send 发送队首一批记录，成功后出队；返回队列中是否还有剩余
+func (w *forwardWorker) send(ctx context.Context) (bool, error) {
+	w.mu.Lock()
+	n := min(len(w.queue), forwardBatchSize)
+	if n == 0 {
+		w.mu.Unlock()
+		return false, nil
+	}
+	batch := make([]AuditLog, n)
+	for i := 0; i < n; i++ {
+		batch[i] = w.queue[i].log
+	}
+	lastSeq := w.queue[n-1].seq
+	w.mu.Unlock()
+
+	err := w.sink.Send(ctx, batch)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err != nil {
+		if w.lastErr == "" {
+			log.Printf("Warning: 审计日志转发到 %s 失败，缓冲后重试: %v", w.sink.Name(), err)
+		}
+		w.lastErr = err.Error()
+		return true, err
+	}
+	// 发送期间缓冲区满可能已丢弃部分队首记录，按序号移除已发送的部分
+	i := 0
+	for i < len(w.queue) && w.queue[i].seq <= lastSeq {
+		i++
+	}
+	w.queue = w.queue[i:]
+	now := time.Now()
+	w.sent += int64(n)
+	w.lastErr = ""
+	w.lastSentAt = &now
+	return len(w.queue) > 0, nil
+}
+
+// WebhookSink 以 NDJSON 批量推送到 HTTP 收集端（如 Splunk HEC 前置网关、Logstash http input），
+// 配置密钥时附带与用户事件 Webhook 相同的 HMAC 签名
+type WebhookSink struct {
+	url        string
+	secret     []byte
+	httpClient *http.Client
+}
+
+// NewWebhookSink 创建 HTTP 转发渠道
+func NewWebhookSink(url, secret string) *WebhookSink {
+	return &WebhookSink{url: strings.TrimSpace(url), secret: []byte(secret), httpClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (s *WebhookSink) Name() string { return "webhook" }
+
+func (s *WebhookSink) Send(ctx context.Context, logs []AuditLog) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for i := range logs {
+		if err := encoder.Encode(&logs[i]); err != nil {
+			return err
+		}
+	}
+	payload := buf.Bytes()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if len(s.secret) > 0 {
+		req.Header.Set(webhook.SignatureHeader, "sha256="+webhook.Sign(s.secret, payload))
+	}
+	return doForwardRequest(s.httpClient, req)
+}
+
+// KafkaRESTSink 通过 Kafka REST Proxy（Confluent REST v2 协议）写入 Kafka topic
+type KafkaRESTSink struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewKafkaRESTSink 创建 Kafka 转发渠道，baseURL 为 REST Proxy 地址
+func NewKafkaRESTSink(baseURL, topic string) *KafkaRESTSink {
+	return &KafkaRESTSink{
+		url:        strings.TrimRight(strings.TrimSpace(baseURL), "/") + "/topics/" + url.PathEscape(topic),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *KafkaRESTSink) Name() string { return "kafka" }
+
+func (s *KafkaRESTSink) Send(ctx context.Context, logs []AuditLog) error {
+	type record struct {
+		Key   string    `json:"key,omitempty"`
+		Value *AuditLog `json:"value"`
+	}
+	records := make([]record, len(logs))
+	for i := range logs {
+		// 以集群为 key，同一集群的记录落在同一分区内保持顺序
+		records[i] = record{Key: logs[i].Cluster, Value: &logs[i]}
+	}
+	payload, err := json.Marshal(map[string]interface{}{"records": records})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+	return doForwardRequest(s.httpClient, req)
+}
+
+func doForwardRequest(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned status %d", req.URL.Host, resp.StatusCode)
+	}
+	return nil
+}
+
+// SyslogSink 按 RFC 5424 发送 syslog，消息体为 JSON 格式的审计日志。
+// 地址格式为 udp://host:514、tcp://host:601 或 tls://host:6514，TCP/TLS 使用 RFC 6587 长度前缀分帧
+type SyslogSink struct {
+	network  string
+	addr     string
+	hostname string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// syslog facility=authpriv(10)、severity=info(6)
+const syslogPriority = 10*8 + 6
+
+// NewSyslogSink 解析地址并创建 syslog 渠道，连接在首次发送时建立
+func NewSyslogSink(address string) (*SyslogSink, error) {
+	u, err := url.Parse(strings.TrimSpace(address))
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "udp" && u.Scheme != "tcp" && u.Scheme != "tls" {
+		return nil, fmt.Errorf("syslog 地址需以 udp://、tcp:// 或 tls:// 开头")
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("syslog 地址缺少主机")
+	}
+	hostname, _ := os.Hostname()
+	if hostname == "" {
+		hostname = "-"
+	}
+	return &SyslogSink{network: u.Scheme, addr: u.Host, hostname: hostname}, nil
+}
+
+func (s *SyslogSink) Name() string { return "syslog" }
+
+func (s *SyslogSink) Send(ctx context.Context, logs []AuditLog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		conn, err := s.dial(ctx)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = s.conn.SetWriteDeadline(deadline)
+	}
+
+	for i := range logs {
+		msg, err := s.format(&logs[i])
+		if err != nil {
+			return err
+		}
+		if s.network != "udp" {
+			msg = append([]byte(fmt.Sprintf("%d ", len(msg))), msg...)
+		}
+		if _, err := s.conn.Write(msg); err != nil {
+			// 连接已断开，下次重新建立
+			s.conn.Close()
+			s.conn = nil
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SyslogSink) dial(ctx context.Context) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	if s.network == "tls" {
+		host, _, _ := net.SplitHostPort(s.addr)
+		return (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}}).DialContext(ctx, "tcp", s.addr)
+	}
+	return dialer.DialContext(ctx, s.network, s.addr)
+}
+
+// format 生成 RFC 5424 消息：<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID - MSG
+func (s *SyslogSink) format(entry *AuditLog) ([]byte, error) {
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+	header := fmt.Sprintf("<%d>1 %s %s k8s-dashboard - audit - ", syslogPriority,
+		entry.Timestamp.UTC().Format(time.RFC3339Nano), s.hostname)
+	return append([]byte(header), body...), nil
+}
@@ -0,0 +1,169 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/k8s-dashboard/backend/internal/webhook"
+)
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestForwarderRetriesWebhook(t *testing.T) {
+	var mu sync.Mutex
+	var lines []string
+	var signature string
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 第一次模拟收集端不可用
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		lines = append(lines, strings.Split(strings.TrimSpace(string(body)), "\n")...)
+		signature = r.Header.Get(webhook.SignatureHeader)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	f := NewForwarder(0, NewWebhookSink(server.URL, "secret"))
+	for _, user := range []string{"alice", "bob", "carol"} {
+		f.Enqueue(AuditLog{Timestamp: time.Now(), User: user, Action: "DELETE", Resource: "pods"})
+	}
+
+	waitFor(t, func() bool { return f.Status()[0].Sent == 3 })
+	status := f.Status()[0]
+	if status.Name != "webhook" || status.Buffered != 0 || status.LastError != "" || status.LastSentAt == nil {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	var first AuditLog
+	if len(lines) != 3 || json.Unmarshal([]byte(lines[0]), &first) != nil || first.User != "alice" {
+		t.Fatalf("unexpected payload lines: %v", lines)
+	}
+	if !strings.HasPrefix(signature, "sha256=") {
+		t.Fatalf("expected signed payload, got %q", signature)
+	}
+	f.Close(context.Background())
+}
+
+type failingSink struct{ attempts int32 }
+
+func (s *failingSink) Name() string { return "failing" }
+
+func (s *failingSink) Send(ctx context.Context, logs []AuditLog) error {
+	atomic.AddInt32(&s.attempts, 1)
+	return errors.New("collector down")
+}
+
+func TestForwarderBuffersWhenSinkDown(t *testing.T) {
+	sink := &failingSink{}
+	f := NewForwarder(2, sink)
+	defer f.Close(context.Background())
+
+	for i := 0; i < 3; i++ {
+		f.Enqueue(AuditLog{User: strconv.Itoa(i)})
+	}
+	waitFor(t, func() bool { return atomic.LoadInt32(&sink.attempts) > 0 })
+
+	status := f.Status()[0]
+	if status.Buffered != 2 || status.Dropped != 1 || status.Sent != 0 || status.LastError != "collector down" {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+}
+
+func TestSyslogSinkTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	defer ln.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		size, _ := r.ReadString(' ')
+		n, _ := strconv.Atoi(strings.TrimSpace(size))
+		buf := make([]byte, n)
+		io.ReadFull(r, buf)
+		received <- string(buf)
+	}()
+
+	sink, err := NewSyslogSink("tcp://" + ln.Addr().String())
+	if err != nil {
+		t.Fatalf("NewSyslogSink failed: %v", err)
+	}
+	entry := AuditLog{Timestamp: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), User: "alice", Action: "DELETE", Resource: "pods"}
+	if err := sink.Send(context.Background(), []AuditLog{entry}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	select {
+	case msg := <-received:
+		if !strings.HasPrefix(msg, "<86>1 2026-01-02T03:04:05Z ") || !strings.Contains(msg, " k8s-dashboard - audit - {") || !strings.Contains(msg, `"user":"alice"`) {
+			t.Fatalf("unexpected syslog message: %q", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for syslog message")
+	}
+
+	if _, err := NewSyslogSink("http://siem:514"); err == nil {
+		t.Fatal("expected unsupported scheme to be rejected")
+	}
+}
+
+func TestKafkaRESTSink(t *testing.T) {
+	var path, contentType string
+	var body struct {
+		Records []struct {
+			Key   string   `json:"key"`
+			Value AuditLog `json:"value"`
+		} `json:"records"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, contentType = r.URL.Path, r.Header.Get("Content-Type")
+		json.NewDecoder(r.Body).Decode(&body)
+	}))
+	defer server.Close()
+
+	sink := NewKafkaRESTSink(server.URL+"/", "k8s.audit")
+	if err := sink.Send(context.Background(), []AuditLog{{User: "alice", Cluster: "prod"}}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if path != "/topics/k8s.audit" || contentType != "application/vnd.kafka.json.v2+json" {
+		t.Fatalf("unexpected request: %s %s", path, contentType)
+	}
+	if len(body.Records) != 1 || body.Records[0].Key != "prod" || body.Records[0].Value.User != "alice" {
+		t.Fatalf("unexpected records: %+v", body.Records)
+	}
+}
@@ -0,0 +1,204 @@
+package audit
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	dbutil "github.com/k8s-dashboard/backend/internal/db"
+)
+
+// 预先创建的月分区数量（含当月）
+const partitionsAhead = 3
+
+// initPartitionedSchema 初始化 Postgres 按月分区的 audit_logs，
+// 若存在旧版非分区表则在同一事务中迁移数据
+func (c *Client) initPartitionedSchema() error {
+	exists, err := dbutil.TableExists(c.db, "audit_logs")
+	if err != nil {
+		return err
+	}
+	partitioned := false
+	if exists {
+		if partitioned, err = dbutil.IsPartitioned(c.db, "audit_logs"); err != nil {
+			return err
+		}
+	}
+	if exists && !partitioned {
+		return c.migrateToPartitioned()
+	}
+
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := createPartitionedAuditTable(tx); err != nil {
+		return err
+	}
+	if err := dbutil.EnsureMonthlyPartitions(tx, "audit_logs", time.Now(), partitionsAhead); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func createPartitionedAuditTable(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		CREATE SEQUENCE IF NOT EXISTS audit_logs_id_seq;
+
+		CREATE TABLE IF NOT EXISTS audit_logs (
+			id BIGINT NOT NULL DEFAULT nextval('audit_logs_id_seq'),
+			timestamp TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			"user" VARCHAR(255) NOT NULL DEFAULT 'anonymous',
+			user_id BIGINT,
+			role VARCHAR(50),
+			action VARCHAR(20) NOT NULL,
+			resource VARCHAR(100) NOT NULL,
+			resource_name VARCHAR(255),
+			namespace VARCHAR(255),
+			cluster VARCHAR(100) DEFAULT 'default',
+			status_code INT,
+			client_ip VARCHAR(50),
+			user_agent TEXT,
+			request_body TEXT,
+			duration BIGINT,
+			message TEXT,
+			diff TEXT,
+			PRIMARY KEY (id, timestamp)
+		) PARTITION BY RANGE (timestamp);
+
+		-- 兜底分区：分区维护滞后时避免写入失败
+		CREATE TABLE IF NOT EXISTS audit_logs_default PARTITION OF audit_logs DEFAULT;
+
+		CREATE INDEX IF NOT EXISTS idx_audit_logs_timestamp ON audit_logs(timestamp DESC);
+		CREATE INDEX IF NOT EXISTS idx_audit_logs_user ON audit_logs("user");
+		CREATE INDEX IF NOT EXISTS idx_audit_logs_action ON audit_logs(action);
+		CREATE INDEX IF NOT EXISTS idx_audit_logs_resource ON audit_logs(resource);
+		CREATE INDEX IF NOT EXISTS idx_audit_logs_namespace ON audit_logs(namespace);
+	`)
+	return err
+}
+
+// migrateToPartitioned 将旧版 audit_logs 迁移为分区表
+func (c *Client) migrateToPartitioned() error {
+	log.Printf("审计日志表迁移为按月分区表，数据量较大时可能需要较长时间...")
+	started := time.Now()
+
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// 旧表改名，释放主键、索引与序列名称供新表使用
+	if _, err := tx.Exec(`
+		ALTER TABLE audit_logs RENAME TO audit_logs_legacy;
+		ALTER TABLE audit_logs_legacy RENAME CONSTRAINT audit_logs_pkey TO audit_logs_legacy_pkey;
+		ALTER SEQUENCE IF EXISTS audit_logs_id_seq OWNED BY NONE;
+		DROP INDEX IF EXISTS idx_audit_logs_timestamp;
+		DROP INDEX IF EXISTS idx_audit_logs_user;
+		DROP INDEX IF EXISTS idx_audit_logs_action;
+		DROP INDEX IF EXISTS idx_audit_logs_resource;
+		DROP INDEX IF EXISTS idx_audit_logs_namespace;
+	`); err != nil {
+		return fmt.Errorf("重命名旧审计表失败: %w", err)
+	}
+
+	if err := createPartitionedAuditTable(tx); err != nil {
+		return err
+	}
+
+	// 为历史数据覆盖的每个月创建分区
+	var oldest sql.NullTime
+	if err := tx.QueryRow("SELECT MIN(timestamp) FROM audit_logs_legacy").Scan(&oldest); err != nil {
+		return err
+	}
+	from := time.Now()
+	if oldest.Valid && oldest.Time.Before(from) {
+		from = oldest.Time
+	}
+	months := monthsBetween(from, time.Now()) + partitionsAhead
+	if err := dbutil.EnsureMonthlyPartitions(tx, "audit_logs", from, months); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`
+		INSERT INTO audit_logs (
+			id, timestamp, "user", action, resource, resource_name,
+			namespace, cluster, status_code, client_ip, user_agent,
+			request_body, duration, message
+		)
+		SELECT id, COALESCE(timestamp, CURRENT_TIMESTAMP), "user", action, resource, resource_name,
+		       namespace, cluster, status_code, client_ip, user_agent,
+		       request_body, duration, message
+		FROM audit_logs_legacy;
+
+		SELECT setval('audit_logs_id_seq', COALESCE((SELECT MAX(id) FROM audit_logs), 0) + 1, false);
+
+		DROP TABLE audit_logs_legacy;
+	`); err != nil {
+		return fmt.Errorf("迁移审计数据失败: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	log.Printf("审计日志表分区迁移完成，耗时 %s", time.Since(started).Round(time.Second))
+	return nil
+}
+
+// monthsBetween 返回 from 到 to 之间跨越的月数（同月为 0）
+func monthsBetween(from, to time.Time) int {
+	from, to = dbutil.MonthStart(from), dbutil.MonthStart(to)
+	return (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+}
+
+// MaintainPartitions 维护审计数据：预建后续月份分区，并按保留期清理过期数据。
+// retention 为 0 时只预建分区、不清理。配置了归档目录时先归档再清理，归档失败则跳过清理。
+// Postgres 下整月过期的分区直接 DROP，SQLite 下按时间删除过期行。
+func (c *Client) MaintainPartitions(retention time.Duration) (err error) {
+	defer func() { c.recordMaintenance(retention, err) }()
+
+	if c.dialect != dbutil.DialectSQLite {
+		if err := dbutil.EnsureMonthlyPartitions(c.db, "audit_logs", time.Now(), partitionsAhead); err != nil {
+			return err
+		}
+	}
+	if retention <= 0 {
+		return nil
+	}
+
+	cutoff := time.Now().Add(-retention)
+	if c.archiveDir != "" {
+		archive, err := c.archiveBefore(cutoff)
+		if err != nil {
+			return fmt.Errorf("归档审计日志失败，本次不清理: %w", err)
+		}
+		if archive != nil {
+			log.Printf("已归档 %d 条审计日志到 %s", archive.Rows, archive.File)
+		}
+	}
+
+	if c.dialect == dbutil.DialectSQLite {
+		if _, err := c.db.Exec("DELETE FROM audit_logs WHERE timestamp < $1", cutoff); err != nil {
+			return err
+		}
+	} else {
+		dropped, err := dbutil.DropPartitionsBefore(c.db, "audit_logs", "timestamp", cutoff)
+		if err != nil {
+			return err
+		}
+		if len(dropped) > 0 {
+			log.Printf("已删除过期审计分区: %v", dropped)
+		}
+	}
+
+	// 终端录制、异常告警与审计日志使用同一保留期
+	if _, err := c.db.Exec("DELETE FROM terminal_sessions WHERE started_at < $1", cutoff); err != nil {
+		return err
+	}
+	_, err = c.db.Exec("DELETE FROM audit_anomalies WHERE created_at < $1", cutoff)
+	return err
+}
@@ -1,7 +1,15 @@
 package audit
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -37,6 +45,7 @@ func TestSQLiteAuditLifecycle(t *testing.T) {
 		RequestBody:  "",
 		Duration:     12,
 		Message:      "ok",
+		Diff:         `[{"op":"replace","path":"/spec/replicas","before":1,"after":3}]`,
 	}
 
 	if err := client.Log(entry); err != nil {
@@ -54,6 +63,9 @@ func TestSQLiteAuditLifecycle(t *testing.T) {
 	if result.Total < 1 || len(result.Items) < 1 {
 		t.Fatalf("expected at least one audit log, total=%d items=%d", result.Total, len(result.Items))
 	}
+	if result.Items[0].Diff != entry.Diff {
+		t.Fatalf("expected diff to round-trip, got %q", result.Items[0].Diff)
+	}
 
 	stats, err := client.GetStats(time.Hour)
 	if err != nil {
@@ -68,3 +80,374 @@ func TestSQLiteAuditLifecycle(t *testing.T) {
 		t.Fatalf("expected stats total >= 1, got %d", total)
 	}
 }
+
+func TestSQLiteTerminalSessionRecording(t *testing.T) {
+	conn, dialect, err := dbutil.Open(dbutil.Config{
+		SQLitePath:          filepath.Join(t.TempDir(), "terminal.db"),
+		AllowSQLiteFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer conn.Close()
+
+	client, err := NewClient(conn, dialect)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	if client.NewTerminalRecorder() != nil {
+		t.Fatalf("expected recording to be disabled by default")
+	}
+
+	if err := client.SetTerminalRecording(TerminalRecordingConfig{Enabled: true, MaxBytes: 1024}); err != nil {
+		t.Fatalf("SetTerminalRecording failed: %v", err)
+	}
+
+	session := &TerminalSession{
+		User:      "alice",
+		Cluster:   "prod",
+		Namespace: "default",
+		Pod:       "nginx-abc",
+		Container: "nginx",
+		Command:   "/bin/sh",
+	}
+	if err := client.StartTerminalSession(session); err != nil {
+		t.Fatalf("StartTerminalSession failed: %v", err)
+	}
+
+	recorder := client.NewTerminalRecorder()
+	for _, ch := range "mysql --password=hunter2\r" {
+		recorder.Input([]byte(string(ch)))
+	}
+	recorder.Output([]byte("export TOKEN=abc123\r\n"))
+	recorder.Input([]byte("ls"))
+
+	events, bytes, truncated := recorder.Finish()
+	if err := client.FinishTerminalSession(session.ID, events, bytes, truncated); err != nil {
+		t.Fatalf("FinishTerminalSession failed: %v", err)
+	}
+
+	got, err := client.GetTerminalSession(session.ID)
+	if err != nil {
+		t.Fatalf("GetTerminalSession failed: %v", err)
+	}
+	if got.EndedAt == nil || got.Truncated {
+		t.Fatalf("unexpected session state: %+v", got)
+	}
+	if len(got.Events) != 3 {
+		t.Fatalf("expected 3 events, got %d: %+v", len(got.Events), got.Events)
+	}
+	if got.Events[0].Type != "i" || got.Events[0].Data != "mysql --password=[REDACTED]\r" {
+		t.Fatalf("expected redacted input line, got %q", got.Events[0].Data)
+	}
+	if got.Events[1].Data != "export TOKEN=[REDACTED]\r\n" {
+		t.Fatalf("expected redacted output, got %q", got.Events[1].Data)
+	}
+
+	items, total, err := client.ListTerminalSessions(TerminalSessionListParams{User: "alice"})
+	if err != nil {
+		t.Fatalf("ListTerminalSessions failed: %v", err)
+	}
+	if total != 1 || len(items) != 1 || items[0].Events != nil {
+		t.Fatalf("unexpected list result: total=%d items=%+v", total, items)
+	}
+
+	if _, err := client.GetTerminalSession(session.ID + 100); err != ErrTerminalSessionNotFound {
+		t.Fatalf("expected ErrTerminalSessionNotFound, got %v", err)
+	}
+}
+
+func TestSQLitePacketCapture(t *testing.T) {
+	conn, dialect, err := dbutil.Open(dbutil.Config{
+		SQLitePath:          filepath.Join(t.TempDir(), "capture.db"),
+		AllowSQLiteFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer conn.Close()
+
+	client, err := NewClient(conn, dialect)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	capture := &PacketCapture{
+		User:            "alice",
+		Cluster:         "default",
+		Namespace:       "default",
+		Pod:             "web-0",
+		Container:       "capture-abcde",
+		Interface:       "any",
+		Filter:          "tcp port 80",
+		DurationSeconds: 30,
+	}
+	if err := client.StartPacketCapture(capture); err != nil {
+		t.Fatalf("StartPacketCapture failed: %v", err)
+	}
+	running, err := client.HasRunningPacketCapture("default", "default", "web-0")
+	if err != nil || !running {
+		t.Fatalf("expected running capture, got %v (%v)", running, err)
+	}
+	if _, _, err := client.GetPacketCaptureData(capture.ID + 1); err != ErrPacketCaptureNotFound {
+		t.Fatalf("expected ErrPacketCaptureNotFound, got %v", err)
+	}
+
+	pcap := []byte{0xd4, 0xc3, 0xb2, 0xa1, 0x00, 0x0a}
+	if err := client.FinishPacketCapture(capture.ID, CaptureStatusCompleted, "", pcap, true); err != nil {
+		t.Fatalf("FinishPacketCapture failed: %v", err)
+	}
+
+	stored, data, err := client.GetPacketCaptureData(capture.ID)
+	if err != nil {
+		t.Fatalf("GetPacketCaptureData failed: %v", err)
+	}
+	if stored.Status != CaptureStatusCompleted || stored.Bytes != int64(len(pcap)) || !stored.Truncated || stored.EndedAt == nil {
+		t.Fatalf("unexpected capture: %+v", stored)
+	}
+	if string(data) != string(pcap) {
+		t.Fatalf("unexpected pcap data: %v", data)
+	}
+
+	items, total, err := client.ListPacketCaptures(PacketCaptureListParams{User: "alice"})
+	if err != nil {
+		t.Fatalf("ListPacketCaptures failed: %v", err)
+	}
+	if total != 1 || len(items) != 1 || items[0].Filter != "tcp port 80" {
+		t.Fatalf("unexpected capture list: total=%d items=%+v", total, items)
+	}
+
+	// 重启后遗留的 running 记录置为失败
+	if err := client.StartPacketCapture(&PacketCapture{User: "bob", Namespace: "default", Pod: "web-1"}); err != nil {
+		t.Fatalf("StartPacketCapture failed: %v", err)
+	}
+	if _, err := NewClient(conn, dialect); err != nil {
+		t.Fatalf("reopen NewClient failed: %v", err)
+	}
+	if running, _ := client.HasRunningPacketCapture("default", "default", "web-1"); running {
+		t.Fatalf("expected stale running capture to be marked failed")
+	}
+}
+
+func TestSQLiteMaintainPartitionsRetention(t *testing.T) {
+	conn, dialect, err := dbutil.Open(dbutil.Config{
+		SQLitePath:          filepath.Join(t.TempDir(), "retention.db"),
+		AllowSQLiteFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer conn.Close()
+
+	client, err := NewClient(conn, dialect)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	for _, ts := range []time.Time{time.Now().AddDate(0, 0, -40), time.Now()} {
+		if err := client.Log(&AuditLog{Timestamp: ts, User: "bob", Action: "DELETE", Resource: "pods"}); err != nil {
+			t.Fatalf("Log failed: %v", err)
+		}
+	}
+
+	if err := client.MaintainPartitions(0); err != nil {
+		t.Fatalf("MaintainPartitions without retention failed: %v", err)
+	}
+	result, err := client.List(ListParams{User: "bob"})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if result.Total != 2 {
+		t.Fatalf("expected no rows purged without retention, total=%d", result.Total)
+	}
+
+	if err := client.MaintainPartitions(30 * 24 * time.Hour); err != nil {
+		t.Fatalf("MaintainPartitions failed: %v", err)
+	}
+	result, err = client.List(ListParams{User: "bob"})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if result.Total != 1 {
+		t.Fatalf("expected expired row to be purged, total=%d", result.Total)
+	}
+}
+
+func TestSQLiteAuditExport(t *testing.T) {
+	conn, dialect, err := dbutil.Open(dbutil.Config{
+		SQLitePath:          filepath.Join(t.TempDir(), "audit.db"),
+		AllowSQLiteFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer conn.Close()
+
+	client, err := NewClient(conn, dialect)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < exportBatchSize+5; i++ {
+		user := "alice"
+		if i%2 == 1 {
+			user = "bob"
+		}
+		if err := client.Log(&AuditLog{
+			Timestamp:  base.Add(time.Duration(i) * time.Millisecond),
+			User:       user,
+			Action:     "DELETE",
+			Resource:   "pods",
+			Namespace:  "default",
+			StatusCode: 200,
+			Message:    "删除, \"nginx\"",
+		}); err != nil {
+			t.Fatalf("Log failed: %v", err)
+		}
+	}
+
+	params := ListParams{User: "alice"}
+	if total, err := client.Count(params); err != nil || total != int64(exportBatchSize/2+3) {
+		t.Fatalf("unexpected count %d (%v)", total, err)
+	}
+
+	var buf bytes.Buffer
+	rows, err := client.Export(context.Background(), params, ExportFormatCSV, 0, &buf)
+	if err != nil {
+		t.Fatalf("Export csv failed: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv failed: %v", err)
+	}
+	if rows != int64(exportBatchSize/2+3) || len(records) != int(rows)+1 {
+		t.Fatalf("unexpected csv rows=%d records=%d", rows, len(records))
+	}
+	if records[0][0] != "id" || records[1][2] != "alice" || records[1][13] != "删除, \"nginx\"" {
+		t.Fatalf("unexpected csv content: %v %v", records[0], records[1])
+	}
+
+	buf.Reset()
+	rows, err = client.Export(context.Background(), ListParams{}, ExportFormatNDJSON, 3, &buf)
+	if err != nil || rows != 3 {
+		t.Fatalf("Export ndjson failed: rows=%d %v", rows, err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var first AuditLog
+	if len(lines) != 3 || json.Unmarshal([]byte(lines[0]), &first) != nil || first.User != "alice" {
+		t.Fatalf("unexpected ndjson output: %q", buf.String())
+	}
+
+	export, err := client.StartExport("admin", ListParams{User: "bob", Page: 3}, ExportFormatNDJSON, 0)
+	if err != nil {
+		t.Fatalf("StartExport failed: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for export.Status == ExportStatusRunning && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+		if export, err = client.GetExport(export.ID); err != nil {
+			t.Fatalf("GetExport failed: %v", err)
+		}
+	}
+	if export.Status != ExportStatusCompleted || export.Rows != int64(exportBatchSize/2+2) || export.Filters.User != "bob" {
+		t.Fatalf("unexpected export: %+v", export)
+	}
+
+	_, data, err := client.GetExportData(export.ID)
+	if err != nil {
+		t.Fatalf("GetExportData failed: %v", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("gzip reader failed: %v", err)
+	}
+	content, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("read export failed: %v", err)
+	}
+	if n := strings.Count(string(content), "\n"); n != int(export.Rows) {
+		t.Fatalf("expected %d ndjson lines, got %d", export.Rows, n)
+	}
+
+	list, err := client.ListExports(0)
+	if err != nil || len(list) != 1 || list[0].ID != export.ID {
+		t.Fatalf("unexpected exports: %+v (%v)", list, err)
+	}
+	if _, err := client.GetExport(export.ID + 1); err != ErrExportNotFound {
+		t.Fatalf("expected ErrExportNotFound, got %v", err)
+	}
+}
+
+func TestSQLiteAuditArchive(t *testing.T) {
+	dir := t.TempDir()
+	conn, dialect, err := dbutil.Open(dbutil.Config{
+		SQLitePath:          filepath.Join(dir, "archive.db"),
+		AllowSQLiteFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer conn.Close()
+
+	client, err := NewClient(conn, dialect)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	archiveDir := filepath.Join(dir, "archive")
+	if err := client.SetArchiveDir(archiveDir); err != nil {
+		t.Fatalf("SetArchiveDir failed: %v", err)
+	}
+
+	for _, days := range []int{-50, -40, 0} {
+		if err := client.Log(&AuditLog{Timestamp: time.Now().AddDate(0, 0, days), User: "carol", Action: "DELETE", Resource: "pods"}); err != nil {
+			t.Fatalf("Log failed: %v", err)
+		}
+	}
+
+	retention := 30 * 24 * time.Hour
+	if err := client.MaintainPartitions(retention); err != nil {
+		t.Fatalf("MaintainPartitions failed: %v", err)
+	}
+	if total, err := client.Count(ListParams{}); err != nil || total != 1 {
+		t.Fatalf("expected archived rows to be purged, total=%d (%v)", total, err)
+	}
+
+	stats, err := client.GetStorageStats()
+	if err != nil {
+		t.Fatalf("GetStorageStats failed: %v", err)
+	}
+	if stats.Rows != 1 || stats.TableBytes <= 0 || stats.RetentionDays != 30 || stats.LastMaintenanceAt == nil {
+		t.Fatalf("unexpected storage stats: %+v", stats)
+	}
+	if stats.ArchiveFiles != 1 || stats.ArchivedRows != 2 || stats.LastArchive == nil {
+		t.Fatalf("unexpected archive stats: %+v", stats)
+	}
+
+	f, err := os.Open(filepath.Join(archiveDir, stats.LastArchive.File))
+	if err != nil {
+		t.Fatalf("open archive failed: %v", err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("gzip reader failed: %v", err)
+	}
+	content, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("read archive failed: %v", err)
+	}
+	if n := strings.Count(string(content), "\n"); n != 2 || !strings.Contains(string(content), `"user":"carol"`) {
+		t.Fatalf("unexpected archive content: %q", content)
+	}
+
+	// 没有新的过期数据时不生成空文件
+	if err := client.MaintainPartitions(retention); err != nil {
+		t.Fatalf("MaintainPartitions failed: %v", err)
+	}
+	entries, err := os.ReadDir(archiveDir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected a single archive file, got %d (%v)", len(entries), err)
+	}
+}
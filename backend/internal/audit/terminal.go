@@ -0,0 +1,465 @@
+package audit
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	dbutil "github.com/k8s-dashboard/backend/internal/db"
+)
+
+// ErrTerminalSessionNotFound 终端会话记录不存在
+var ErrTerminalSessionNotFound = errors.New("终端会话记录不存在")
+
+// 终端录制默认配置
+const (
+	defaultTerminalMaxBytes = 10 * 1024 * 1024
+	maxTerminalOutputLine   = 4096
+	redactedPlaceholder     = "[REDACTED]"
+)
+
+// 默认脱敏规则：含捕获组时仅替换第一个捕获组
+var defaultRedactPatterns = []string{
+	`(?i)(?:password|passwd|pwd|secret|token|api[_-]?key)\s*[=:]\s*(\S+)`,
+	`(?i)--(?:password|token|secret)[= ](\S+)`,
+	`(?i)authorization:\s*bearer\s+(\S+)`,
+}
+
+// TerminalEvent 终端录制事件
+type TerminalEvent struct {
+	Time int64  `json:"t"`    // 相对会话开始的毫秒数
+	Type string `json:"type"` // i: 输入, o: 输出
+	Data string `json:"data"`
+}
+
+// TerminalSession 终端会话录制
+type TerminalSession struct {
+	ID        int64           `json:"id"`
+	User      string          `json:"user"`
+	Cluster   string          `json:"cluster"`
+	Namespace string          `json:"namespace"`
+	Pod       string          `json:"pod"`
+	Container string          `json:"container"`
+	Command   string          `json:"command"`
+	StartedAt time.Time       `json:"startedAt"`
+	EndedAt   *time.Time      `json:"endedAt,omitempty"`
+	Bytes     int64           `json:"bytes"`
+	Truncated bool            `json:"truncated"`
+	Events    []TerminalEvent `json:"events,omitempty"`
+}
+
+// TerminalSessionListParams 会话查询参数
+type TerminalSessionListParams struct {
+	Page      int
+	PageSize  int
+	User      string
+	Namespace string
+	Pod       string
+}
+
+// TerminalRecordingConfig 终端录制配置
+type TerminalRecordingConfig struct {
+	// Enabled 是否录制终端会话（TERMINAL_RECORDING，默认开启）
+	Enabled bool `json:"enabled"`
+	// MaxBytes 单个会话最大录制字节数（TERMINAL_RECORDING_MAX_BYTES）
+	MaxBytes int `json:"maxBytes"`
+	// RedactPatterns 在默认规则之外追加的脱敏正则（TERMINAL_RECORDING_REDACT_PATTERNS，JSON 数组）
+	RedactPatterns []string `json:"redactPatterns"`
+	// Redactor 编译后的脱敏规则，为空时由 SetTerminalRecording 根据 RedactPatterns 生成
+	Redactor *Redactor `json:"-"`
+}
+
+// DefaultTerminalRecordingConfig 返回默认终端录制配置
+func DefaultTerminalRecordingConfig() TerminalRecordingConfig {
+	return TerminalRecordingConfig{
+		Enabled:  true,
+		MaxBytes: defaultTerminalMaxBytes,
+	}
+}
+
+// Validate 校验录制上限与追加的脱敏规则
+func (c TerminalRecordingConfig) Validate() error {
+	if c.MaxBytes <= 0 {
+		return fmt.Errorf("TERMINAL_RECORDING_MAX_BYTES 必须大于 0: %d", c.MaxBytes)
+	}
+	if _, err := NewRedactor(c.RedactPatterns); err != nil {
+		return fmt.Errorf("TERMINAL_RECORDING_REDACT_PATTERNS %w", err)
+	}
+	return nil
+}
+
+// Redactor 录制内容脱敏
+type Redactor struct {
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor 编译脱敏规则
+func NewRedactor(patterns []string) (*Redactor, error) {
+	r := &Redactor{}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("无效的脱敏规则 %q: %w", p, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// Redact 对文本应用全部脱敏规则
+func (r *Redactor) Redact(s string) string {
+	if r == nil {
+		return s
+	}
+	for _, re := range r.patterns {
+		if re.NumSubexp() == 0 {
+			s = re.ReplaceAllString(s, redactedPlaceholder)
+			continue
+		}
+		matches := re.FindAllStringSubmatchIndex(s, -1)
+		if len(matches) == 0 {
+			continue
+		}
+		var b strings.Builder
+		last := 0
+		for _, m := range matches {
+			start, end := m[2], m[3]
+			if start < 0 {
+				continue
+			}
+			b.WriteString(s[last:start])
+			b.WriteString(redactedPlaceholder)
+			last = end
+		}
+		b.WriteString(s[last:])
+		s = b.String()
+	}
+	return s
+}
+
+// SetTerminalRecording 设置终端录制配置，RedactPatterns 追加在默认脱敏规则之后
+func (c *Client) SetTerminalRecording(cfg TerminalRecordingConfig) error {
+	if cfg.Redactor == nil {
+		redactor, err := NewRedactor(append(append([]string{}, defaultRedactPatterns...), cfg.RedactPatterns...))
+		if err != nil {
+			return err
+		}
+		cfg.Redactor = redactor
+	}
+	c.terminal = cfg
+	return nil
+}
+
+// NewTerminalRecorder 按当前配置创建录制器，未启用录制时返回 nil
+func (c *Client) NewTerminalRecorder() *TerminalRecorder {
+	if !c.terminal.Enabled {
+		return nil
+	}
+	return NewTerminalRecorder(c.terminal)
+}
+
+// TerminalRecorder 记录单个终端会话的输入输出，可被多个 goroutine 并发调用
+type TerminalRecorder struct {
+	mu        sync.Mutex
+	start     time.Time
+	maxBytes  int
+	redactor  *Redactor
+	events    []TerminalEvent
+	bytes     int
+	truncated bool
+
+	// 输入按行缓冲，保证整条命令能被脱敏规则匹配
+	line      []byte
+	lineStart time.Time
+
+	// 输出同样按行缓冲：TTY 回显的输入逐字符到达，逐块脱敏会漏掉被拆开的密钥
+	outLine      []byte
+	outLineStart time.Time
+}
+
+// NewTerminalRecorder 创建终端录制器
+func NewTerminalRecorder(cfg TerminalRecordingConfig) *TerminalRecorder {
+	maxBytes := cfg.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultTerminalMaxBytes
+	}
+	return &TerminalRecorder{
+		start:    time.Now(),
+		maxBytes: maxBytes,
+		redactor: cfg.Redactor,
+	}
+}
+
+// Input 记录用户输入
+func (r *TerminalRecorder) Input(p []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.line) == 0 {
+		r.lineStart = time.Now()
+	}
+	for _, b := range p {
+		r.line = append(r.line, b)
+		// 回车、换行或 Ctrl-C/Ctrl-D 时落一条输入事件
+		if b == '\r' || b == '\n' || b == 0x03 || b == 0x04 {
+			r.flushLineLocked()
+		}
+	}
+}
+
+// Output 记录容器输出
+func (r *TerminalRecorder) Output(p []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.outLine) == 0 {
+		r.outLineStart = time.Now()
+	}
+	for _, b := range p {
+		r.outLine = append(r.outLine, b)
+		// 换行时落一条输出事件；超长的行（如进度条）按上限切分，避免无限缓冲
+		if b == '\n' || len(r.outLine) >= maxTerminalOutputLine {
+			r.flushOutputLocked()
+			r.outLineStart = time.Now()
+		}
+	}
+}
+
+// Finish 结束录制并返回事件
+func (r *TerminalRecorder) Finish() ([]TerminalEvent, int, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.flushOutputLocked()
+	r.flushLineLocked()
+	return r.events, r.bytes, r.truncated
+}
+
+func (r *TerminalRecorder) flushOutputLocked() {
+	if len(r.outLine) == 0 {
+		return
+	}
+	r.appendLocked(r.outLineStart, "o", string(r.outLine))
+	r.outLine = r.outLine[:0]
+}
+
+func (r *TerminalRecorder) flushLineLocked() {
+	if len(r.line) == 0 {
+		return
+	}
+	r.appendLocked(r.lineStart, "i", string(r.line))
+	r.line = r.line[:0]
+}
+
+func (r *TerminalRecorder) appendLocked(at time.Time, typ, data string) {
+	if r.truncated || data == "" {
+		return
+	}
+	data = r.redactor.Redact(data)
+	if r.bytes+len(data) > r.maxBytes {
+		r.truncated = true
+		return
+	}
+	r.bytes += len(data)
+	r.events = append(r.events, TerminalEvent{
+		Time: at.Sub(r.start).Milliseconds(),
+		Type: typ,
+		Data: data,
+	})
+}
+
+// initTerminalSchema 初始化终端会话表
+func (c *Client) initTerminalSchema() error {
+	var schema string
+	if c.dialect == dbutil.DialectSQLite {
+		schema = `
+		CREATE TABLE IF NOT EXISTS terminal_sessions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			"user" TEXT NOT NULL,
+			cluster TEXT DEFAULT 'default',
+			namespace TEXT NOT NULL,
+			pod TEXT NOT NULL,
+			container TEXT,
+			command TEXT,
+			started_at DATETIME NOT NULL,
+			ended_at DATETIME,
+			bytes INTEGER DEFAULT 0,
+			truncated BOOLEAN DEFAULT 0,
+			events TEXT
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_terminal_sessions_user ON terminal_sessions("user");
+		CREATE INDEX IF NOT EXISTS idx_terminal_sessions_pod ON terminal_sessions(namespace, pod, container);
+		CREATE INDEX IF NOT EXISTS idx_terminal_sessions_started_at ON terminal_sessions(started_at DESC);
+		`
+	} else {
+		schema = `
+		CREATE TABLE IF NOT EXISTS terminal_sessions (
+			id BIGSERIAL PRIMARY KEY,
+			"user" VARCHAR(255) NOT NULL,
+			cluster VARCHAR(100) DEFAULT 'default',
+			namespace VARCHAR(255) NOT NULL,
+			pod VARCHAR(255) NOT NULL,
+			container VARCHAR(255),
+			command TEXT,
+			started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			ended_at TIMESTAMP WITH TIME ZONE,
+			bytes BIGINT DEFAULT 0,
+			truncated BOOLEAN DEFAULT FALSE,
+			events TEXT
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_terminal_sessions_user ON terminal_sessions("user");
+		CREATE INDEX IF NOT EXISTS idx_terminal_sessions_pod ON terminal_sessions(namespace, pod, container);
+		CREATE INDEX IF NOT EXISTS idx_terminal_sessions_started_at ON terminal_sessions(started_at DESC);
+		`
+	}
+
+	_, err := c.db.Exec(schema)
+	return err
+}
+
+// StartTerminalSession 创建终端会话记录
+func (c *Client) StartTerminalSession(s *TerminalSession) error {
+	if s.StartedAt.IsZero() {
+		s.StartedAt = time.Now()
+	}
+
+	if c.dialect == dbutil.DialectSQLite {
+		result, err := c.db.Exec(`
+			INSERT INTO terminal_sessions ("user", cluster, namespace, pod, container, command, started_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, s.User, s.Cluster, s.Namespace, s.Pod, s.Container, s.Command, s.StartedAt)
+		if err != nil {
+			return err
+		}
+		s.ID, err = result.LastInsertId()
+		return err
+	}
+
+	return c.db.QueryRow(`
+		INSERT INTO terminal_sessions ("user", cluster, namespace, pod, container, command, started_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`, s.User, s.Cluster, s.Namespace, s.Pod, s.Container, s.Command, s.StartedAt).Scan(&s.ID)
+}
+
+// FinishTerminalSession 写入会话录制内容
+func (c *Client) FinishTerminalSession(id int64, events []TerminalEvent, bytes int, truncated bool) error {
+	if events == nil {
+		events = []TerminalEvent{}
+	}
+	data, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.db.Exec(`
+		UPDATE terminal_sessions SET ended_at = $1, bytes = $2, truncated = $3, events = $4
+		WHERE id = $5
+	`, time.Now(), bytes, truncated, string(data), id)
+	return err
+}
+
+// GetTerminalSession 获取会话及完整录制内容
+func (c *Client) GetTerminalSession(id int64) (*TerminalSession, error) {
+	var s TerminalSession
+	var endedAt sql.NullTime
+	var events string
+	err := c.db.QueryRow(`
+		SELECT id, "user", COALESCE(cluster, 'default'), namespace, pod, COALESCE(container, ''),
+		       COALESCE(command, ''), started_at, ended_at, COALESCE(bytes, 0), COALESCE(truncated, FALSE),
+		       COALESCE(events, '')
+		FROM terminal_sessions WHERE id = $1
+	`, id).Scan(&s.ID, &s.User, &s.Cluster, &s.Namespace, &s.Pod, &s.Container,
+		&s.Command, &s.StartedAt, &endedAt, &s.Bytes, &s.Truncated, &events)
+	if err == sql.ErrNoRows {
+		return nil, ErrTerminalSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if endedAt.Valid {
+		s.EndedAt = &endedAt.Time
+	}
+
+	s.Events = []TerminalEvent{}
+	if events != "" {
+		if err := json.Unmarshal([]byte(events), &s.Events); err != nil {
+			return nil, fmt.Errorf("解析录制内容失败: %w", err)
+		}
+	}
+	return &s, nil
+}
+
+// ListTerminalSessions 查询会话列表（不含录制内容）
+func (c *Client) ListTerminalSessions(params TerminalSessionListParams) ([]TerminalSession, int64, error) {
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.PageSize < 1 {
+		params.PageSize = 20
+	}
+	if params.PageSize > 100 {
+		params.PageSize = 100
+	}
+
+	where := "WHERE 1=1"
+	args := []interface{}{}
+	argIndex := 1
+
+	if params.User != "" {
+		where += fmt.Sprintf(" AND \"user\" = $%d", argIndex)
+		args = append(args, params.User)
+		argIndex++
+	}
+	if params.Namespace != "" {
+		where += fmt.Sprintf(" AND namespace = $%d", argIndex)
+		args = append(args, params.Namespace)
+		argIndex++
+	}
+	if params.Pod != "" {
+		where += fmt.Sprintf(" AND pod = $%d", argIndex)
+		args = append(args, params.Pod)
+		argIndex++
+	}
+
+	var total int64
+	if err := c.db.QueryRow("SELECT COUNT(*) FROM terminal_sessions "+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, "user", COALESCE(cluster, 'default'), namespace, pod, COALESCE(container, ''),
+		       COALESCE(command, ''), started_at, ended_at, COALESCE(bytes, 0), COALESCE(truncated, FALSE)
+		FROM terminal_sessions %s
+		ORDER BY started_at DESC
+		LIMIT $%d OFFSET $%d
+	`, where, argIndex, argIndex+1)
+	args = append(args, params.PageSize, (params.Page-1)*params.PageSize)
+
+	rows, err := c.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := []TerminalSession{}
+	for rows.Next() {
+		var s TerminalSession
+		var endedAt sql.NullTime
+		if err := rows.Scan(&s.ID, &s.User, &s.Cluster, &s.Namespace, &s.Pod, &s.Container,
+			&s.Command, &s.StartedAt, &endedAt, &s.Bytes, &s.Truncated); err != nil {
+			return nil, 0, err
+		}
+		if endedAt.Valid {
+			s.EndedAt = &endedAt.Time
+		}
+		items = append(items, s)
+	}
+	return items, total, rows.Err()
+}
@@ -0,0 +1,57 @@
+package audit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTerminalRecorderRedactsSplitEcho(t *testing.T) {
+	redactor, err := NewRedactor(defaultRedactPatterns)
+	if err != nil {
+		t.Fatalf("NewRedactor failed: %v", err)
+	}
+	recorder := NewTerminalRecorder(TerminalRecordingConfig{Enabled: true, Redactor: redactor})
+
+	// 输入逐字符到达，TTY 按相同的粒度回显
+	recorder.Output([]byte("$ "))
+	for _, ch := range "export TOKEN=s3cr3t\r" {
+		recorder.Input([]byte(string(ch)))
+		recorder.Output([]byte(string(ch)))
+	}
+	recorder.Output([]byte("\n"))
+	// 密钥跨输出块拆分
+	recorder.Output([]byte("pass"))
+	recorder.Output([]byte("word: hun"))
+	recorder.Output([]byte("ter2\r\n$ "))
+
+	events, _, truncated := recorder.Finish()
+	if truncated {
+		t.Fatalf("unexpected truncation")
+	}
+	var output strings.Builder
+	for _, event := range events {
+		if strings.Contains(event.Data, "s3cr3t") || strings.Contains(event.Data, "hunter2") {
+			t.Fatalf("secret leaked in %s event %q", event.Type, event.Data)
+		}
+		if event.Type == "o" {
+			output.WriteString(event.Data)
+		}
+	}
+	want := "$ export TOKEN=[REDACTED]\r\npassword: [REDACTED]\r\n$ "
+	if output.String() != want {
+		t.Fatalf("output = %q, want %q", output.String(), want)
+	}
+}
+
+func TestTerminalRecorderSplitsLongOutputLines(t *testing.T) {
+	recorder := NewTerminalRecorder(TerminalRecordingConfig{Enabled: true})
+	recorder.Output([]byte(strings.Repeat("x", maxTerminalOutputLine+10)))
+
+	events, bytes, _ := recorder.Finish()
+	if len(events) != 2 || len(events[0].Data) != maxTerminalOutputLine || len(events[1].Data) != 10 {
+		t.Fatalf("unexpected events: %d", len(events))
+	}
+	if bytes != maxTerminalOutputLine+10 {
+		t.Fatalf("bytes = %d", bytes)
+	}
+}
@@ -0,0 +1,281 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// APITokenPrefix API 令牌前缀，认证中间件据此区分 API 令牌与登录 JWT
+	APITokenPrefix = "kdp_"
+	// DefaultAPITokenTTLDays 未指定有效期时的默认天数
+	DefaultAPITokenTTLDays = 90
+	// MaxAPITokenTTLDays 有效期上限
+	MaxAPITokenTTLDays = 365
+	// maxAPITokensPerUser 单个用户可持有的令牌数量上限
+	maxAPITokensPerUser = 20
+	// apiTokenTouchInterval 最近使用时间的最小更新间隔，避免每个请求都写库
+	apiTokenTouchInterval = time.Minute
+)
+
+var (
+	ErrAPITokenNotFound = errors.New("API 令牌不存在")
+	ErrAPITokenLimit    = fmt.Errorf("每个用户最多持有 %d 个 API 令牌", maxAPITokensPerUser)
+)
+
+// APIToken 个人 API 令牌，明文仅在创建时返回一次，数据库只保存 SHA-256 摘要
+type APIToken struct {
+	ID         int64      `json:"id"`
+	UserID     int64      `json:"userId"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`               // 明文前若干位，便于用户识别
+	Role       string     `json:"role"`                 // 令牌角色，不高于所属用户的角色
+	Namespaces []string   `json:"namespaces,omitempty"` // 为空表示沿用用户的命名空间授权
+	ExpiresAt  time.Time  `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// CreateAPITokenRequest 创建 API 令牌请求
+type CreateAPITokenRequest struct {
+	Name          string   `json:"name"`
+	Role          string   `json:"role"`          // 为空时使用用户当前角色
+	Namespaces    []string `json:"namespaces"`    // 限定可访问的命名空间，须为用户已授权命名空间的子集
+	ExpiresInDays int      `json:"expiresInDays"` // 默认 90，最长 365
+}
+
+var apiTokenRoleLevel = map[string]int{
+	"viewer":   0,
+	"operator": 1,
+	"admin":    2,
+}
+
+func hashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateAPIToken 为用户签发 API 令牌，返回令牌信息与明文（仅此一次）
+func (c *Client) CreateAPIToken(user *User, req *CreateAPITokenRequest) (*APIToken, string, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > 100 {
+		return nil, "", fmt.Errorf("令牌名称不能为空且不超过 100 个字符")
+	}
+
+	if req.Role == "" {
+		req.Role = user.Role
+	}
+	level, ok := apiTokenRoleLevel[req.Role]
+	if !ok {
+		return nil, "", fmt.Errorf("无效的角色: %s", req.Role)
+	}
+	if level > apiTokenRoleLevel[user.Role] {
+		return nil, "", fmt.Errorf("令牌角色不能高于当前用户角色 %s", user.Role)
+	}
+
+	namespaces := make([]string, 0, len(req.Namespaces))
+	for _, ns := range req.Namespaces {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	if len(namespaces) > 0 {
+		// admin 接口不区分命名空间，限定命名空间的令牌没有意义
+		if req.Role == "admin" {
+			return nil, "", fmt.Errorf("admin 令牌不支持命名空间限制")
+		}
+		if user.Role != "admin" && !user.AllNamespaces {
+			for _, ns := range namespaces {
+				allowed, err := c.CanAccessNamespace(user.ID, ns)
+				if err != nil {
+					return nil, "", err
+				}
+				if !allowed {
+					return nil, "", fmt.Errorf("无权访问命名空间 %s", ns)
+				}
+			}
+		}
+	}
+
+	if req.ExpiresInDays == 0 {
+		req.ExpiresInDays = DefaultAPITokenTTLDays
+	}
+	if req.ExpiresInDays < 1 || req.ExpiresInDays > MaxAPITokenTTLDays {
+		return nil, "", fmt.Errorf("有效期必须在 1-%d 天之间", MaxAPITokenTTLDays)
+	}
+
+	var count int
+	if err := c.db.QueryRow("SELECT COUNT(*) FROM api_tokens WHERE user_id = $1", user.ID).Scan(&count); err != nil {
+		return nil, "", err
+	}
+	if count >= maxAPITokensPerUser {
+		return nil, "", ErrAPITokenLimit
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, "", err
+	}
+	plaintext := APITokenPrefix + hex.EncodeToString(b)
+
+	now := time.Now()
+	token := &APIToken{
+		UserID:     user.ID,
+		Name:       req.Name,
+		Prefix:     plaintext[:len(APITokenPrefix)+8],
+		Role:       req.Role,
+		Namespaces: namespaces,
+		ExpiresAt:  now.AddDate(0, 0, req.ExpiresInDays),
+		CreatedAt:  now,
+	}
+	err := c.db.QueryRow(`
+		INSERT INTO api_tokens (user_id, name, token_hash, prefix, role, namespaces, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`, token.UserID, token.Name, hashAPIToken(plaintext), token.Prefix, token.Role,
+		strings.Join(namespaces, ","), token.ExpiresAt, token.CreatedAt).Scan(&token.ID)
+	if err != nil {
+		return nil, "", fmt.Errorf("创建 API 令牌失败: %w", err)
+	}
+	return token, plaintext, nil
+}
+
+const apiTokenColumns = "id, user_id, name, prefix, role, namespaces, expires_at, last_used_at, created_at"
+
+func scanAPIToken(row interface{ Scan(...any) error }) (*APIToken, error) {
+	var token APIToken
+	var namespaces string
+	var lastUsedAt sql.NullTime
+	if err := row.Scan(&token.ID, &token.UserID, &token.Name, &token.Prefix, &token.Role,
+		&namespaces, &token.ExpiresAt, &lastUsedAt, &token.CreatedAt); err != nil {
+		return nil, err
+	}
+	if namespaces != "" {
+		token.Namespaces = strings.Split(namespaces, ",")
+	}
+	if lastUsedAt.Valid {
+		token.LastUsedAt = &lastUsedAt.Time
+	}
+	return &token, nil
+}
+
+// ListAPITokens 列出用户的 API 令牌
+func (c *Client) ListAPITokens(userID int64) ([]APIToken, error) {
+	rows, err := c.db.Query("SELECT "+apiTokenColumns+" FROM api_tokens WHERE user_id = $1 ORDER BY created_at DESC", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []APIToken{}
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *token)
+	}
+	return tokens, rows.Err()
+}
+
+// RevokeAPIToken 撤销用户自己的 API 令牌
+func (c *Client) RevokeAPIToken(userID, tokenID int64) error {
+	result, err := c.db.Exec("DELETE FROM api_tokens WHERE id = $1 AND user_id = $2", tokenID, userID)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrAPITokenNotFound
+	}
+	return nil
+}
+
+// ValidateAPIToken 校验 API 令牌，返回按令牌角色与命名空间收窄后的用户。
+// 返回的用户 Role 为令牌角色；限定了命名空间时 ScopedNamespaces 为令牌可访问的授权列表。
+func (c *Client) ValidateAPIToken(plaintext string) (*User, *APIToken, error) {
+	row := c.db.QueryRow("SELECT "+apiTokenColumns+" FROM api_tokens WHERE token_hash = $1", hashAPIToken(plaintext))
+	token, err := scanAPIToken(row)
+	if err == sql.ErrNoRows {
+		return nil, nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	if now.After(token.ExpiresAt) {
+		return nil, nil, ErrTokenExpired
+	}
+
+	user, err := c.GetUserByID(token.UserID)
+	if err == ErrUserNotFound {
+		return nil, nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if !user.Enabled {
+		return nil, nil, ErrUserDisabled
+	}
+
+	// 用户角色被下调后，令牌角色随之收窄
+	if apiTokenRoleLevel[token.Role] < apiTokenRoleLevel[user.Role] {
+		user.Role = token.Role
+	}
+	if len(token.Namespaces) > 0 {
+		scoped, err := c.scopeTokenNamespaces(user, token.Namespaces)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(scoped) == 0 {
+			// 用户已失去令牌内所有命名空间的授权
+			return nil, nil, ErrInvalidToken
+		}
+		user.AllNamespaces = false
+		user.ScopedNamespaces = scoped
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) > apiTokenTouchInterval {
+		c.db.Exec("UPDATE api_tokens SET last_used_at = $1 WHERE id = $2", now, token.ID)
+	}
+	return user, token, nil
+}
+
+// scopeTokenNamespaces 计算令牌命名空间与用户当前授权的交集；全命名空间用户按 admin 级别授权
+func (c *Client) scopeTokenNamespaces(user *User, namespaces []string) ([]UserNamespace, error) {
+	scoped := make([]UserNamespace, 0, len(namespaces))
+	if user.Role == "admin" || user.AllNamespaces {
+		for _, ns := range namespaces {
+			scoped = append(scoped, UserNamespace{UserID: user.ID, Namespace: ns, Permissions: NamespacePermAdmin})
+		}
+		return scoped, nil
+	}
+
+	granted, err := c.GetUserNamespaces(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, ns := range namespaces {
+		for _, g := range granted {
+			if g.Namespace == ns {
+				scoped = append(scoped, g)
+				break
+			}
+		}
+	}
+	return scoped, nil
+}
+
+// NamespaceGrants 返回用户当前请求可用的命名空间授权：API 令牌限定了命名空间时使用令牌范围
+func (c *Client) NamespaceGrants(user *User) ([]UserNamespace, error) {
+	if user.ScopedNamespaces != nil {
+		return user.ScopedNamespaces, nil
+	}
+	return c.GetUserNamespaces(user.ID)
+}
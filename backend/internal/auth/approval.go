@@ -17,6 +17,36 @@ type CreateApprovalRequest struct {
 	Namespace    string      `json:"namespace"`
 	Reason       string      `json:"reason"`
 	RequestData  interface{} `json:"requestData"`
+
+	// Preview 由服务端根据集群当前状态计算，不接受客户端传入
+	Preview *ApprovalPreview `json:"-"`
+	// Cluster 由服务端按请求解析出的集群填写，批准后在该集群上执行
+	Cluster string `json:"-"`
+}
+
+// 审批执行结果
+const (
+	ApprovalExecutionSucceeded = "succeeded"
+	ApprovalExecutionFailed    = "failed"
+)
+
+// ApprovalPreview 审批变更预览，供审批人直观了解操作影响
+type ApprovalPreview struct {
+	Kind              string        `json:"kind"` // Deployment, StatefulSet, ...
+	Summary           string        `json:"summary"`
+	CurrentReplicas   *int32        `json:"currentReplicas,omitempty"`
+	RequestedReplicas *int32        `json:"requestedReplicas,omitempty"`
+	ImageChanges      []ImageChange `json:"imageChanges,omitempty"`
+	AffectedPods      int           `json:"affectedPods"`
+	Warnings          []string      `json:"warnings,omitempty"`
+	ComputedAt        time.Time     `json:"computedAt"`
+}
+
+// ImageChange 容器镜像变更
+type ImageChange struct {
+	Container string `json:"container"`
+	Current   string `json:"current"`
+	Requested string `json:"requested"`
 }
 
 // ListApprovalParams 审批列表查询参数
@@ -86,6 +116,30 @@ func (c *Client) NeedsApproval(userRole, action, resource, namespace string) (bo
 	return true, nil
 }
 
+// RequiredApprovals 返回操作最匹配的启用规则要求的批准人数，未匹配规则时为 1
+func (c *Client) RequiredApprovals(action, resource, namespace string) (int, error) {
+	var required int
+	err := c.db.QueryRow(`
+		SELECT required_approvals FROM approval_rules
+		WHERE (action = $1 OR action = '*')
+		  AND (resource = $2 OR resource = '*')
+		  AND (namespace = $3 OR namespace = '' OR namespace IS NULL)
+		  AND enabled = true
+		ORDER BY
+			CASE WHEN action = $1 THEN 0 ELSE 1 END,
+			CASE WHEN resource = $2 THEN 0 ELSE 1 END,
+			CASE WHEN namespace = $3 THEN 0 ELSE 1 END
+		LIMIT 1
+	`, action, resource, namespace).Scan(&required)
+	if err == sql.ErrNoRows || (err == nil && required < 1) {
+		return 1, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return required, nil
+}
+
 // CreateApproval 创建审批请求
 func (c *Client) CreateApproval(userID int64, req *CreateApprovalRequest) (*ApprovalRequest, error) {
 	// 序列化请求数据
@@ -97,13 +151,25 @@ func (c *Client) CreateApproval(userID int64, req *CreateApprovalRequest) (*Appr
 		}
 		requestDataJSON = string(data)
 	}
+	var previewJSON sql.NullString
+	if req.Preview != nil {
+		data, err := json.Marshal(req.Preview)
+		if err != nil {
+			return nil, err
+		}
+		previewJSON = sql.NullString{String: string(data), Valid: true}
+	}
+	required, err := c.RequiredApprovals(req.Action, req.Resource, req.Namespace)
+	if err != nil {
+		return nil, err
+	}
 
 	var approvalID int64
 	if c.dialect == dbutil.DialectSQLite {
 		result, err := c.db.Exec(`
-			INSERT INTO approval_requests (user_id, action, resource, resource_name, namespace, reason, request_data, status)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, 'pending')
-		`, userID, req.Action, req.Resource, req.ResourceName, req.Namespace, req.Reason, requestDataJSON)
+			INSERT INTO approval_requests (user_id, action, resource, resource_name, namespace, reason, request_data, preview, cluster, required_approvals, status)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, 'pending')
+		`, userID, req.Action, req.Resource, req.ResourceName, req.Namespace, req.Reason, requestDataJSON, previewJSON, req.Cluster, required)
 		if err != nil {
 			return nil, err
 		}
@@ -114,16 +180,20 @@ func (c *Client) CreateApproval(userID int64, req *CreateApprovalRequest) (*Appr
 		approvalID = lastID
 	} else {
 		err := c.db.QueryRow(`
-			INSERT INTO approval_requests (user_id, action, resource, resource_name, namespace, reason, request_data, status)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, 'pending')
+			INSERT INTO approval_requests (user_id, action, resource, resource_name, namespace, reason, request_data, preview, cluster, required_approvals, status)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, 'pending')
 			RETURNING id
-		`, userID, req.Action, req.Resource, req.ResourceName, req.Namespace, req.Reason, requestDataJSON).Scan(&approvalID)
+		`, userID, req.Action, req.Resource, req.ResourceName, req.Namespace, req.Reason, requestDataJSON, previewJSON, req.Cluster, required).Scan(&approvalID)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	return c.GetApprovalByID(approvalID)
+	approval, err := c.GetApprovalByID(approvalID)
+	if err == nil && c.approvalHandler != nil {
+		c.approvalHandler(EventApprovalCreated, approval)
+	}
+	return approval, err
 }
 
 // GetApprovalByID 根据 ID 获取审批请求
@@ -135,19 +205,24 @@ func (c *Client) GetApprovalByID(id int64) (*ApprovalRequest, error) {
 	var requestData sql.NullString
 	var namespace sql.NullString
 	var reason sql.NullString
+	var preview sql.NullString
+	var executedAt sql.NullTime
 
 	err := c.db.QueryRow(`
 		SELECT ar.id, ar.user_id, u.username, ar.action, ar.resource, ar.resource_name,
 		       ar.namespace, ar.reason, ar.status, ar.approver_id, ar.approved_at,
-		       ar.comment, ar.request_data, ar.created_at, ar.updated_at
+		       ar.comment, ar.request_data, ar.preview, COALESCE(ar.cluster, ''),
+		       COALESCE(ar.execution_status, ''), COALESCE(ar.execution_result, ''), ar.executed_at,
+		       ar.required_approvals, ar.created_at, ar.updated_at
 		FROM approval_requests ar
 		JOIN users u ON ar.user_id = u.id
 		WHERE ar.id = $1
 	`, id).Scan(
 		&approval.ID, &approval.UserID, &approval.Username, &approval.Action,
 		&approval.Resource, &approval.ResourceName, &namespace, &reason,
-		&approval.Status, &approverID, &approvedAt, &comment, &requestData,
-		&approval.CreatedAt, &approval.UpdatedAt,
+		&approval.Status, &approverID, &approvedAt, &comment, &requestData, &preview,
+		&approval.Cluster, &approval.ExecutionStatus, &approval.ExecutionResult, &executedAt,
+		&approval.RequiredApprovals, &approval.CreatedAt, &approval.UpdatedAt,
 	)
 
 	if err == sql.ErrNoRows {
@@ -179,30 +254,182 @@ func (c *Client) GetApprovalByID(id int64) (*ApprovalRequest, error) {
 	if requestData.Valid {
 		approval.RequestData = requestData.String
 	}
+	approval.Preview = decodeApprovalPreview(preview)
+	if executedAt.Valid {
+		approval.ExecutedAt = &executedAt.Time
+	}
+	if approval.Votes, err = c.listApprovalVotes(approval.ID); err != nil {
+		return nil, err
+	}
 
 	return &approval, nil
 }
 
-// ApproveRequest 批准审批请求
-func (c *Client) ApproveRequest(approvalID, approverID int64, comment string) error {
+// listApprovalVotes 按批准时间返回审批请求的投票记录
+func (c *Client) listApprovalVotes(approvalID int64) ([]ApprovalVote, error) {
+	rows, err := c.db.Query(`
+		SELECT v.approver_id, u.username, COALESCE(v.comment, ''), v.created_at
+		FROM approval_votes v
+		JOIN users u ON v.approver_id = u.id
+		WHERE v.approval_id = $1
+		ORDER BY v.created_at, v.id
+	`, approvalID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var votes []ApprovalVote
+	for rows.Next() {
+		var v ApprovalVote
+		if err := rows.Scan(&v.ApproverID, &v.ApproverName, &v.Comment, &v.CreatedAt); err != nil {
+			return nil, err
+		}
+		votes = append(votes, v)
+	}
+	return votes, rows.Err()
+}
+
+// decodeApprovalPreview 解析存储的预览，旧数据或解析失败时返回 nil
+func decodeApprovalPreview(raw sql.NullString) *ApprovalPreview {
+	if !raw.Valid || raw.String == "" {
+		return nil
+	}
+	var preview ApprovalPreview
+	if err := json.Unmarshal([]byte(raw.String), &preview); err != nil {
+		return nil
+	}
+	return &preview
+}
+
+// ApproveRequest 记录审批人的批准。规则要求多人审批时，每位 admin 只计一票，
+// 达到所需人数后请求才变为 approved；返回值表示本次批准后请求是否已通过
+func (c *Client) ApproveRequest(approvalID, approverID int64, comment string) (bool, error) {
+	var requesterID int64
+	var status string
+	var required int
+	err := c.db.QueryRow(`
+		SELECT user_id, status, required_approvals FROM approval_requests WHERE id = $1
+	`, approvalID).Scan(&requesterID, &status, &required)
+	if err == sql.ErrNoRows || (err == nil && status != "pending") {
+		return false, fmt.Errorf("审批请求不存在或已处理")
+	}
+	if err != nil {
+		return false, err
+	}
+	if required > 1 && approverID == requesterID {
+		return false, fmt.Errorf("多人审批的请求不能由申请人自己批准")
+	}
+
+	var voted int
+	if err := c.db.QueryRow(`
+		SELECT COUNT(*) FROM approval_votes WHERE approval_id = $1 AND approver_id = $2
+	`, approvalID, approverID).Scan(&voted); err != nil {
+		return false, err
+	}
+	if voted > 0 {
+		return false, fmt.Errorf("您已批准过该请求，需要其他审批人批准")
+	}
+
+	now := time.Now()
+	if _, err := c.db.Exec(`
+		INSERT INTO approval_votes (approval_id, approver_id, comment, created_at)
+		VALUES ($1, $2, $3, $4)
+	`, approvalID, approverID, comment, now); err != nil {
+		return false, err
+	}
+
+	var votes int
+	if err := c.db.QueryRow("SELECT COUNT(*) FROM approval_votes WHERE approval_id = $1", approvalID).Scan(&votes); err != nil {
+		return false, err
+	}
+	if votes < required {
+		if _, err := c.db.Exec("UPDATE approval_requests SET updated_at = $1 WHERE id = $2", now, approvalID); err != nil {
+			return false, err
+		}
+		c.emitApproval(EventApprovalVoted, approvalID)
+		return false, nil
+	}
+
+	// 以 pending 为条件更新，并发批准时只有一个请求完成审批
 	result, err := c.db.Exec(`
 		UPDATE approval_requests
 		SET status = 'approved', approver_id = $1, approved_at = $2, comment = $3, updated_at = $4
 		WHERE id = $5 AND status = 'pending'
-	`, approverID, time.Now(), comment, time.Now(), approvalID)
+	`, approverID, now, comment, now, approvalID)
 
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
-		return fmt.Errorf("审批请求不存在或已处理")
+		return false, fmt.Errorf("审批请求不存在或已处理")
 	}
 
+	c.emitApproval(EventApprovalApproved, approvalID)
+	return true, nil
+}
+
+// RecordApprovalExecution 记录已批准请求的执行结果，每个请求只记录一次
+func (c *Client) RecordApprovalExecution(approvalID int64, status, result string) error {
+	now := time.Now()
+	res, err := c.db.Exec(`
+		UPDATE approval_requests
+		SET execution_status = $1, execution_result = $2, executed_at = $3, updated_at = $4
+		WHERE id = $5 AND status = 'approved' AND executed_at IS NULL
+	`, status, result, now, now, approvalID)
+	if err != nil {
+		return err
+	}
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected == 0 {
+		return fmt.Errorf("审批请求未批准或已执行")
+	}
 	return nil
 }
 
+// FindPendingApproval 查找申请人对同一目标尚未处理的审批请求，避免重复提交；不存在时返回 nil
+func (c *Client) FindPendingApproval(userID int64, req *CreateApprovalRequest) (*ApprovalRequest, error) {
+	id, err := c.findApprovalID(userID, req, "pending", time.Time{})
+	if err != nil || id == 0 {
+		return nil, err
+	}
+	return c.GetApprovalByID(id)
+}
+
+// ConsumeApproval 查找申请人在 since 之后获批且尚未使用的审批请求，并记录为已执行。
+// 用于批准后由申请人自行完成的一次性操作（如查看 Secret 明文），没有可用审批时返回 nil
+func (c *Client) ConsumeApproval(userID int64, req *CreateApprovalRequest, since time.Time, result string) (*ApprovalRequest, error) {
+	id, err := c.findApprovalID(userID, req, "approved", since)
+	if err != nil || id == 0 {
+		return nil, err
+	}
+	if err := c.RecordApprovalExecution(id, ApprovalExecutionSucceeded, result); err != nil {
+		return nil, err
+	}
+	return c.GetApprovalByID(id)
+}
+
+func (c *Client) findApprovalID(userID int64, req *CreateApprovalRequest, status string, since time.Time) (int64, error) {
+	query := `
+		SELECT id FROM approval_requests
+		WHERE user_id = $1 AND action = $2 AND resource = $3 AND resource_name = $4
+		  AND namespace = $5 AND COALESCE(cluster, '') = $6 AND status = $7`
+	args := []interface{}{userID, req.Action, req.Resource, req.ResourceName, req.Namespace, req.Cluster, status}
+	if status == "approved" {
+		query += " AND executed_at IS NULL AND approved_at >= $8"
+		args = append(args, since)
+	}
+	query += " ORDER BY created_at DESC LIMIT 1"
+
+	var id int64
+	err := c.db.QueryRow(query, args...).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return id, err
+}
+
 // RejectRequest 拒绝审批请求
 func (c *Client) RejectRequest(approvalID, approverID int64, comment string) error {
 	result, err := c.db.Exec(`
@@ -220,6 +447,7 @@ func (c *Client) RejectRequest(approvalID, approverID int64, comment string) err
 		return fmt.Errorf("审批请求不存在或已处理")
 	}
 
+	c.emitApproval(EventApprovalRejected, approvalID)
 	return nil
 }
 
@@ -278,8 +506,9 @@ func (c *Client) ListApprovals(params ListApprovalParams) (*ListApprovalResponse
 	query := fmt.Sprintf(`
 		SELECT ar.id, ar.user_id, u.username, ar.action, ar.resource, ar.resource_name,
 		       ar.namespace, ar.reason, ar.status, ar.approver_id,
-		       COALESCE(au.username, ''), ar.approved_at, ar.comment, ar.request_data,
-		       ar.created_at, ar.updated_at
+		       COALESCE(au.username, ''), ar.approved_at, ar.comment, ar.request_data, ar.preview,
+		       COALESCE(ar.cluster, ''), COALESCE(ar.execution_status, ''), COALESCE(ar.execution_result, ''),
+		       ar.executed_at, ar.required_approvals, ar.created_at, ar.updated_at
 		FROM approval_requests ar
 		JOIN users u ON ar.user_id = u.id
 		LEFT JOIN users au ON ar.approver_id = au.id
@@ -306,11 +535,14 @@ func (c *Client) ListApprovals(params ListApprovalParams) (*ListApprovalResponse
 		var requestData sql.NullString
 		var namespace sql.NullString
 		var reason sql.NullString
+		var preview sql.NullString
+		var executedAt sql.NullTime
 
 		err := rows.Scan(
 			&a.ID, &a.UserID, &a.Username, &a.Action, &a.Resource, &a.ResourceName,
 			&namespace, &reason, &a.Status, &approverID, &approverName, &approvedAt,
-			&comment, &requestData, &a.CreatedAt, &a.UpdatedAt,
+			&comment, &requestData, &preview, &a.Cluster, &a.ExecutionStatus, &a.ExecutionResult,
+			&executedAt, &a.RequiredApprovals, &a.CreatedAt, &a.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -335,9 +567,27 @@ func (c *Client) ListApprovals(params ListApprovalParams) (*ListApprovalResponse
 		if requestData.Valid {
 			a.RequestData = requestData.String
 		}
+		a.Preview = decodeApprovalPreview(preview)
+		if executedAt.Valid {
+			a.ExecutedAt = &executedAt.Time
+		}
 
 		approvals = append(approvals, a)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	// 需在关闭结果集后查询投票，SQLite 只有一个连接
+	for i := range approvals {
+		if approvals[i].RequiredApprovals <= 1 {
+			continue
+		}
+		if approvals[i].Votes, err = c.listApprovalVotes(approvals[i].ID); err != nil {
+			return nil, err
+		}
+	}
 
 	pages := int(total) / params.PageSize
 	if int(total)%params.PageSize > 0 {
@@ -352,6 +602,36 @@ func (c *Client) ListApprovals(params ListApprovalParams) (*ListApprovalResponse
 	}, nil
 }
 
+// ApprovalNotificationEmails 返回审批邮件通知的收件人：新请求通知已启用的 admin（不含申请人），
+// 处理结果通知申请人。查询失败时返回 nil
+func (c *Client) ApprovalNotificationEmails(eventType string, approval *ApprovalRequest) []string {
+	var rows *sql.Rows
+	var err error
+	if eventType == EventApprovalCreated {
+		rows, err = c.db.Query(`
+			SELECT email FROM users
+			WHERE role = 'admin' AND enabled = $1 AND id <> $2 AND COALESCE(email, '') <> ''
+		`, true, approval.UserID)
+	} else {
+		rows, err = c.db.Query(`
+			SELECT email FROM users WHERE id = $1 AND COALESCE(email, '') <> ''
+		`, approval.UserID)
+	}
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	var emails []string
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err == nil {
+			emails = append(emails, email)
+		}
+	}
+	return emails
+}
+
 // GetPendingApprovalCount 获取待审批数量
 func (c *Client) GetPendingApprovalCount() (int64, error) {
 	var count int64
@@ -362,7 +642,7 @@ func (c *Client) GetPendingApprovalCount() (int64, error) {
 // ListApprovalRules 获取审批规则列表
 func (c *Client) ListApprovalRules() ([]ApprovalRule, error) {
 	rows, err := c.db.Query(`
-		SELECT id, action, resource, COALESCE(namespace, ''), min_role, enabled
+		SELECT id, action, resource, COALESCE(namespace, ''), min_role, enabled, required_approvals
 		FROM approval_rules
 		ORDER BY resource, action
 	`)
@@ -374,7 +654,7 @@ func (c *Client) ListApprovalRules() ([]ApprovalRule, error) {
 	var rules []ApprovalRule
 	for rows.Next() {
 		var r ApprovalRule
-		if err := rows.Scan(&r.ID, &r.Action, &r.Resource, &r.Namespace, &r.MinRole, &r.Enabled); err != nil {
+		if err := rows.Scan(&r.ID, &r.Action, &r.Resource, &r.Namespace, &r.MinRole, &r.Enabled, &r.RequiredApprovals); err != nil {
 			return nil, err
 		}
 		rules = append(rules, r)
@@ -383,22 +663,31 @@ func (c *Client) ListApprovalRules() ([]ApprovalRule, error) {
 	return rules, nil
 }
 
-// UpdateApprovalRule 更新审批规则
-func (c *Client) UpdateApprovalRule(id int64, minRole string, enabled bool) error {
+// UpdateApprovalRule 更新审批规则，requiredApprovals 不大于 0 时保持原值
+func (c *Client) UpdateApprovalRule(id int64, minRole string, enabled bool, requiredApprovals int) error {
+	if requiredApprovals <= 0 {
+		_, err := c.db.Exec(`
+			UPDATE approval_rules SET min_role = $1, enabled = $2 WHERE id = $3
+		`, minRole, enabled, id)
+		return err
+	}
 	_, err := c.db.Exec(`
-		UPDATE approval_rules SET min_role = $1, enabled = $2 WHERE id = $3
-	`, minRole, enabled, id)
+		UPDATE approval_rules SET min_role = $1, enabled = $2, required_approvals = $3 WHERE id = $4
+	`, minRole, enabled, requiredApprovals, id)
 	return err
 }
 
 // CreateApprovalRule 创建审批规则
-func (c *Client) CreateApprovalRule(action, resource, namespace, minRole string, enabled bool) error {
+func (c *Client) CreateApprovalRule(action, resource, namespace, minRole string, enabled bool, requiredApprovals int) error {
+	if requiredApprovals < 1 {
+		requiredApprovals = 1
+	}
 	_, err := c.db.Exec(`
-		INSERT INTO approval_rules (action, resource, namespace, min_role, enabled)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO approval_rules (action, resource, namespace, min_role, enabled, required_approvals)
+		VALUES ($1, $2, $3, $4, $5, $6)
 		ON CONFLICT (action, resource, namespace) DO UPDATE
-		SET min_role = $4, enabled = $5
-	`, action, resource, namespace, minRole, enabled)
+		SET min_role = $4, enabled = $5, required_approvals = $6
+	`, action, resource, namespace, minRole, enabled, requiredApprovals)
 	return err
 }
 
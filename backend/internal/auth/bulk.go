@@ -0,0 +1,430 @@
+package auth
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	dbutil "github.com/k8s-dashboard/backend/internal/db"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 导入导出 CSV 列
+var userCSVHeader = []string{"username", "displayName", "email", "role", "allNamespaces", "namespaces", "enabled", "password"}
+
+var usernamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._@-]{0,99}$`)
+
+// UserRecord 用户导入/导出记录
+type UserRecord struct {
+	Username      string   `json:"username"`
+	DisplayName   string   `json:"displayName"`
+	Email         string   `json:"email"`
+	Role          string   `json:"role"`
+	AllNamespaces bool     `json:"allNamespaces"`
+	Namespaces    []string `json:"namespaces"`
+	Enabled       *bool    `json:"enabled,omitempty"`
+	Password      string   `json:"password,omitempty"` // 仅导入：新用户必填，已有用户填写则重置密码
+}
+
+// ImportItemResult 单条导入结果
+type ImportItemResult struct {
+	Row      int    `json:"row"` // 从 1 开始
+	Username string `json:"username"`
+	Action   string `json:"action"` // create, update
+	Error    string `json:"error,omitempty"`
+}
+
+// ImportResult 批量导入报告
+type ImportResult struct {
+	DryRun  bool               `json:"dryRun"`
+	Applied bool               `json:"applied"`
+	Created int                `json:"created"`
+	Updated int                `json:"updated"`
+	Failed  int                `json:"failed"`
+	Items   []ImportItemResult `json:"items"`
+}
+
+// ExportUsers 导出全部用户（含命名空间授权，不含密码和 Token）
+func (c *Client) ExportUsers() ([]UserRecord, error) {
+	rows, err := c.db.Query(`
+		SELECT id, username, COALESCE(display_name, ''), COALESCE(email, ''),
+		       role, all_namespaces, enabled
+		FROM users ORDER BY id ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type exported struct {
+		id     int64
+		record UserRecord
+	}
+	var users []exported
+	for rows.Next() {
+		var u exported
+		var enabled bool
+		if err := rows.Scan(&u.id, &u.record.Username, &u.record.DisplayName, &u.record.Email,
+			&u.record.Role, &u.record.AllNamespaces, &enabled); err != nil {
+			return nil, err
+		}
+		u.record.Enabled = &enabled
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	records := make([]UserRecord, 0, len(users))
+	for _, u := range users {
+		namespaces, err := c.GetUserNamespaces(u.id)
+		if err != nil {
+			return nil, err
+		}
+		u.record.Namespaces = []string{}
+		for _, ns := range namespaces {
+			u.record.Namespaces = append(u.record.Namespaces, ns.Namespace)
+		}
+		records = append(records, u.record)
+	}
+	return records, nil
+}
+
+// ImportUsers 批量创建/更新用户。先校验全部记录，存在任何错误时不做修改；
+// 全部记录在同一事务中写入，任一行失败时整体回滚。dryRun 为 true 时只返回校验报告。
+func (c *Client) ImportUsers(records []UserRecord, dryRun bool) (*ImportResult, error) {
+	result := &ImportResult{DryRun: dryRun, Items: make([]ImportItemResult, 0, len(records))}
+	existing := make([]int64, len(records))
+	seen := make(map[string]int)
+
+	for i := range records {
+		rec := &records[i]
+		normalizeUserRecord(rec)
+		item := ImportItemResult{Row: i + 1, Username: rec.Username, Action: "create"}
+
+		var id int64
+		err := c.db.QueryRow("SELECT id FROM users WHERE username = $1", rec.Username).Scan(&id)
+		switch {
+		case err == nil:
+			item.Action = "update"
+			existing[i] = id
+		case err != sql.ErrNoRows:
+			return nil, err
+		}
+
+		if msg := c.validateUserRecord(rec, item.Action == "create"); msg != "" {
+			item.Error = msg
+		} else if prev, dup := seen[strings.ToLower(rec.Username)]; dup {
+			item.Error = fmt.Sprintf("用户名与第 %d 行重复", prev)
+		}
+		seen[strings.ToLower(rec.Username)] = i + 1
+
+		if item.Error != "" {
+			result.Failed++
+		} else if item.Action == "create" {
+			result.Created++
+		} else {
+			result.Updated++
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	if dryRun || result.Failed > 0 {
+		return result, nil
+	}
+
+	plans := make([]importPlan, len(records))
+	for i := range records {
+		plan, err := c.planUserRecord(&records[i], existing[i])
+		if err != nil {
+			return result, fmt.Errorf("第 %d 行（%s）导入失败: %w", i+1, records[i].Username, err)
+		}
+		plans[i] = plan
+	}
+	if err := c.applyImportPlans(plans); err != nil {
+		return result, err
+	}
+	result.Applied = true
+	for _, plan := range plans {
+		c.emitImportEvents(plan)
+	}
+	return result, nil
+}
+
+func normalizeUserRecord(rec *UserRecord) {
+	rec.Username = strings.TrimSpace(rec.Username)
+	rec.DisplayName = strings.TrimSpace(rec.DisplayName)
+	rec.Email = strings.TrimSpace(rec.Email)
+	rec.Role = strings.ToLower(strings.TrimSpace(rec.Role))
+	if rec.Role == "" {
+		rec.Role = "viewer"
+	}
+	namespaces := make([]string, 0, len(rec.Namespaces))
+	for _, ns := range rec.Namespaces {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	rec.Namespaces = namespaces
+}
+
+// validateUserRecord 校验单条记录，返回错误描述
+func (c *Client) validateUserRecord(rec *UserRecord, isNew bool) string {
+	if !usernamePattern.MatchString(rec.Username) {
+		return "用户名不合法"
+	}
+	if rec.Role != "admin" && rec.Role != "operator" && rec.Role != "viewer" {
+		return fmt.Sprintf("无效的角色: %s", rec.Role)
+	}
+	if rec.Email != "" && !strings.Contains(rec.Email, "@") {
+		return "邮箱格式不正确"
+	}
+	if isNew && rec.Password == "" {
+		return "新用户必须提供密码"
+	}
+	if rec.Password != "" {
+		if err := c.passwordPolicy.Validate(rec.Password); err != nil {
+			return err.Error()
+		}
+	}
+	if rec.Username == "admin" && (rec.Role != "admin" || (rec.Enabled != nil && !*rec.Enabled)) {
+		return "不能降级或禁用系统管理员账户"
+	}
+	return ""
+}
+
+// importPlan 单条记录的写入内容。现有用户与授权、密码哈希在事务外准备，
+// SQLite 单连接下事务内再查询会阻塞
+type importPlan struct {
+	rec         *UserRecord
+	previous    *User // 为 nil 时新建用户
+	userID      int64
+	hash        string // 为空时不修改密码
+	permissions map[string]string
+	enabled     bool
+}
+
+func (c *Client) planUserRecord(rec *UserRecord, existingID int64) (importPlan, error) {
+	plan := importPlan{rec: rec, userID: existingID, enabled: rec.Enabled == nil || *rec.Enabled}
+
+	var existingPermissions map[string]string
+	if existingID != 0 {
+		previous, err := c.GetUserByID(existingID)
+		if err != nil {
+			return plan, err
+		}
+		plan.previous = previous
+		if rec.Enabled == nil {
+			plan.enabled = previous.Enabled
+		}
+		namespaces, err := c.GetUserNamespaces(existingID)
+		if err != nil {
+			return plan, err
+		}
+		existingPermissions = make(map[string]string, len(namespaces))
+		for _, ns := range namespaces {
+			existingPermissions[ns.Namespace] = ns.Permissions
+		}
+	}
+	permissions, err := resolveNamespacePermissions(rec.Namespaces, nil, existingPermissions)
+	if err != nil {
+		return plan, err
+	}
+	plan.permissions = permissions
+
+	if rec.Password != "" {
+		hashed, err := bcrypt.GenerateFromPassword([]byte(rec.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return plan, err
+		}
+		plan.hash = string(hashed)
+	}
+	return plan, nil
+}
+
+// applyImportPlans 在同一事务中写入全部记录，任一行失败时回滚
+func (c *Client) applyImportPlans(plans []importPlan) error {
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	for i := range plans {
+		plan := &plans[i]
+		if err := c.applyImportPlan(tx, plan, now); err != nil {
+			return fmt.Errorf("第 %d 行（%s）导入失败，全部修改已回滚: %w", i+1, plan.rec.Username, err)
+		}
+	}
+	return tx.Commit()
+}
+
+func (c *Client) applyImportPlan(tx *sql.Tx, plan *importPlan, now time.Time) error {
+	rec := plan.rec
+	if plan.previous == nil {
+		if c.dialect == dbutil.DialectSQLite {
+			result, err := tx.Exec(`
+				INSERT INTO users (username, password, display_name, email, role, all_namespaces, enabled, password_changed_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			`, rec.Username, plan.hash, rec.DisplayName, rec.Email, rec.Role, rec.AllNamespaces, plan.enabled, now)
+			if err != nil {
+				return fmt.Errorf("创建用户失败: %w", err)
+			}
+			if plan.userID, err = result.LastInsertId(); err != nil {
+				return fmt.Errorf("读取用户 ID 失败: %w", err)
+			}
+		} else {
+			err := tx.QueryRow(`
+				INSERT INTO users (username, password, display_name, email, role, all_namespaces, enabled, password_changed_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+				RETURNING id
+			`, rec.Username, plan.hash, rec.DisplayName, rec.Email, rec.Role, rec.AllNamespaces, plan.enabled, now).Scan(&plan.userID)
+			if err != nil {
+				return fmt.Errorf("创建用户失败: %w", err)
+			}
+		}
+	} else {
+		// 不修改 ServiceAccount 绑定
+		if _, err := tx.Exec(`
+			UPDATE users SET display_name = $1, email = $2, role = $3, all_namespaces = $4, enabled = $5, updated_at = $6
+			WHERE id = $7
+		`, rec.DisplayName, rec.Email, rec.Role, rec.AllNamespaces, plan.enabled, now, plan.userID); err != nil {
+			return fmt.Errorf("更新用户失败: %w", err)
+		}
+		if plan.hash != "" {
+			// 与 ResetPassword 一致：用户下次登录后需先修改密码
+			if _, err := tx.Exec(`
+				UPDATE users SET password = $1, must_change_password = $2, password_changed_at = $3
+				WHERE id = $4
+			`, plan.hash, true, now, plan.userID); err != nil {
+				return fmt.Errorf("重置密码失败: %w", err)
+			}
+		}
+		if !rec.AllNamespaces {
+			if _, err := tx.Exec("DELETE FROM user_namespaces WHERE user_id = $1", plan.userID); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !rec.AllNamespaces {
+		for _, ns := range rec.Namespaces {
+			if _, err := tx.Exec(`
+				INSERT INTO user_namespaces (user_id, namespace, permissions)
+				VALUES ($1, $2, $3)
+			`, plan.userID, ns, plan.permissions[ns]); err != nil {
+				return fmt.Errorf("添加命名空间权限失败: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// emitImportEvents 事务提交后发出与 CreateUser/UpdateUser 相同的生命周期事件
+func (c *Client) emitImportEvents(plan importPlan) {
+	if plan.previous == nil {
+		c.emit(UserEvent{Type: EventUserCreated, UserID: plan.userID, Username: plan.rec.Username, Role: plan.rec.Role})
+		if !plan.enabled {
+			c.emit(UserEvent{Type: EventUserDisabled, UserID: plan.userID, Username: plan.rec.Username, Role: plan.rec.Role})
+		}
+		return
+	}
+	current := *plan.previous
+	current.Role = plan.rec.Role
+	current.Enabled = plan.enabled
+	c.emitUpdateEvents(plan.previous, &current)
+}
+
+// WriteUsersCSV 以 CSV 格式写出用户（password 列留空）
+func WriteUsersCSV(w io.Writer, records []UserRecord) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(userCSVHeader); err != nil {
+		return err
+	}
+	for _, rec := range records {
+		enabled := rec.Enabled == nil || *rec.Enabled
+		if err := cw.Write([]string{
+			rec.Username,
+			rec.DisplayName,
+			rec.Email,
+			rec.Role,
+			strconv.FormatBool(rec.AllNamespaces),
+			strings.Join(rec.Namespaces, ";"),
+			strconv.FormatBool(enabled),
+			"",
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ParseUsersCSV 解析用户 CSV，首行为表头，列顺序不限；namespaces 以分号分隔
+func ParseUsersCSV(r io.Reader) ([]UserRecord, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("解析 CSV 失败: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("CSV 为空")
+	}
+
+	index := make(map[string]int)
+	for i, name := range rows[0] {
+		index[strings.TrimSpace(name)] = i
+	}
+	if _, ok := index["username"]; !ok {
+		return nil, fmt.Errorf("CSV 缺少 username 列")
+	}
+	get := func(row []string, col string) string {
+		if i, ok := index[col]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+	parseBool := func(row int, col, value string) (bool, error) {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return false, fmt.Errorf("第 %d 行 %s 列不是合法的布尔值: %q", row, col, value)
+		}
+		return b, nil
+	}
+
+	records := make([]UserRecord, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		rec := UserRecord{
+			Username:    get(row, "username"),
+			DisplayName: get(row, "displayName"),
+			Email:       get(row, "email"),
+			Role:        get(row, "role"),
+			Password:    get(row, "password"),
+			Namespaces:  []string{},
+		}
+		if v := get(row, "allNamespaces"); v != "" {
+			if rec.AllNamespaces, err = parseBool(i+1, "allNamespaces", v); err != nil {
+				return nil, err
+			}
+		}
+		if v := get(row, "enabled"); v != "" {
+			enabled, err := parseBool(i+1, "enabled", v)
+			if err != nil {
+				return nil, err
+			}
+			rec.Enabled = &enabled
+		}
+		if v := get(row, "namespaces"); v != "" {
+			rec.Namespaces = strings.Split(v, ";")
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"crypto/rand"
 	"database/sql"
 	"encoding/hex"
@@ -22,6 +23,8 @@ var (
 	ErrInvalidToken        = errors.New("无效的 Token")
 	ErrPermissionDenied    = errors.New("权限不足")
 	ErrNamespaceNotAllowed = errors.New("无权访问该命名空间")
+	ErrUserLocked          = errors.New("登录失败次数过多，账户已临时锁定")
+	ErrSessionNotFound     = errors.New("会话不存在")
 )
 
 // User 用户信息
@@ -39,8 +42,14 @@ type User struct {
 	Enabled        bool       `json:"enabled"`
 	LastLoginAt    *time.Time `json:"lastLoginAt,omitempty"`
 	LastLoginIP    string     `json:"lastLoginIP,omitempty"`
-	CreatedAt      time.Time  `json:"createdAt"`
-	UpdatedAt      time.Time  `json:"updatedAt"`
+	// MustChangePassword 为 true 时除修改密码外的接口均被拒绝（管理员重置、默认密码或密码过期）
+	MustChangePassword bool       `json:"mustChangePassword"`
+	PasswordChangedAt  *time.Time `json:"passwordChangedAt,omitempty"`
+	CreatedAt          time.Time  `json:"createdAt"`
+	UpdatedAt          time.Time  `json:"updatedAt"`
+
+	// ScopedNamespaces 通过限定命名空间的 API 令牌认证时的授权范围，nil 表示使用用户自身授权
+	ScopedNamespaces []UserNamespace `json:"-"`
 }
 
 // UserNamespace 用户可访问的命名空间
@@ -55,7 +64,8 @@ type UserNamespace struct {
 type Session struct {
 	ID        string    `json:"id"`
 	UserID    int64     `json:"userId"`
-	Token     string    `json:"token"`
+	Username  string    `json:"username,omitempty"`
+	Token     string    `json:"-"`
 	IP        string    `json:"ip"`
 	UserAgent string    `json:"userAgent"`
 	ExpiresAt time.Time `json:"expiresAt"`
@@ -64,22 +74,34 @@ type Session struct {
 
 // ApprovalRequest 审批请求
 type ApprovalRequest struct {
-	ID           int64      `json:"id"`
-	UserID       int64      `json:"userId"`
-	Username     string     `json:"username"`
-	Action       string     `json:"action"`   // delete, scale, restart
-	Resource     string     `json:"resource"` // pods, deployments, etc.
-	ResourceName string     `json:"resourceName"`
-	Namespace    string     `json:"namespace"`
-	Reason       string     `json:"reason"`
-	Status       string     `json:"status"` // pending, approved, rejected
-	ApproverID   *int64     `json:"approverId,omitempty"`
-	ApproverName string     `json:"approverName,omitempty"`
-	ApprovedAt   *time.Time `json:"approvedAt,omitempty"`
-	Comment      string     `json:"comment,omitempty"`
-	RequestData  string     `json:"requestData,omitempty"` // JSON 原始请求数据
-	CreatedAt    time.Time  `json:"createdAt"`
-	UpdatedAt    time.Time  `json:"updatedAt"`
+	ID           int64            `json:"id"`
+	UserID       int64            `json:"userId"`
+	Username     string           `json:"username"`
+	Action       string           `json:"action"`   // delete, scale, restart
+	Resource     string           `json:"resource"` // pods, deployments, etc.
+	ResourceName string           `json:"resourceName"`
+	Namespace    string           `json:"namespace"`
+	Reason       string           `json:"reason"`
+	Status       string           `json:"status"` // pending, approved, rejected
+	ApproverID   *int64           `json:"approverId,omitempty"`
+	ApproverName string           `json:"approverName,omitempty"`
+	ApprovedAt   *time.Time       `json:"approvedAt,omitempty"`
+	Comment      string           `json:"comment,omitempty"`
+	RequestData  string           `json:"requestData,omitempty"` // JSON 原始请求数据
+	Preview      *ApprovalPreview `json:"preview,omitempty"`     // 提交时计算的变更预览
+	Cluster      string           `json:"cluster,omitempty"`     // 操作目标集群，空表示默认集群
+
+	// 批准后自动执行的结果，不支持自动执行的操作为空
+	ExecutionStatus string     `json:"executionStatus,omitempty"` // succeeded, failed
+	ExecutionResult string     `json:"executionResult,omitempty"`
+	ExecutedAt      *time.Time `json:"executedAt,omitempty"`
+
+	// 多人审批：需要的批准人数与已批准的审批人
+	RequiredApprovals int            `json:"requiredApprovals"`
+	Votes             []ApprovalVote `json:"votes,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
 }
 
 // ApprovalRule 审批规则
@@ -90,6 +112,17 @@ type ApprovalRule struct {
 	Namespace string `json:"namespace"` // 空表示所有命名空间
 	MinRole   string `json:"minRole"`   // 需要的最低角色: admin, operator
 	Enabled   bool   `json:"enabled"`
+
+	// RequiredApprovals 需要不同 admin 批准的人数，2 即双人复核
+	RequiredApprovals int `json:"requiredApprovals"`
+}
+
+// ApprovalVote 单个审批人的批准记录
+type ApprovalVote struct {
+	ApproverID   int64     `json:"approverId"`
+	ApproverName string    `json:"approverName"`
+	Comment      string    `json:"comment,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
 }
 
 // JWTClaims JWT 声明
@@ -103,17 +136,27 @@ type JWTClaims struct {
 
 // Client 认证客户端
 type Client struct {
-	db        *sql.DB
-	dialect   dbutil.Dialect
-	jwtSecret []byte
+	db              *sql.DB
+	dialect         dbutil.Dialect
+	jwtSecret       []byte
+	guard           *loginGuard
+	eventHandler    UserEventHandler
+	approvalHandler ApprovalEventHandler
+	passwordPolicy  PasswordPolicy
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
 }
 
 // NewClient 创建认证客户端
 func NewClient(db *sql.DB, dialect dbutil.Dialect, jwtSecret string) (*Client, error) {
 	client := &Client{
-		db:        db,
-		dialect:   dialect,
-		jwtSecret: []byte(jwtSecret),
+		db:              db,
+		dialect:         dialect,
+		jwtSecret:       []byte(jwtSecret),
+		guard:           newLoginGuard(),
+		passwordPolicy:  DefaultPasswordPolicy(),
+		accessTokenTTL:  DefaultAccessTokenTTL,
+		refreshTokenTTL: DefaultRefreshTokenTTL,
 	}
 
 	// 初始化表结构
@@ -130,6 +173,17 @@ func NewClient(db *sql.DB, dialect dbutil.Dialect, jwtSecret string) (*Client, e
 	return client, nil
 }
 
+// Ping 检查数据库连接是否可用，供就绪探针使用
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.db.ExecContext(ctx, "SELECT 1")
+	return err
+}
+
+// Dialect 返回数据库方言
+func (c *Client) Dialect() dbutil.Dialect {
+	return c.dialect
+}
+
 // initSchema 初始化表结构
 func (c *Client) initSchema() error {
 	var schema string
@@ -188,6 +242,7 @@ func (c *Client) initSchema() error {
 			approved_at DATETIME,
 			comment TEXT,
 			request_data TEXT,
+			preview TEXT,
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 		);
@@ -200,9 +255,34 @@ func (c *Client) initSchema() error {
 			namespace TEXT,
 			min_role TEXT NOT NULL DEFAULT 'admin',
 			enabled INTEGER DEFAULT 1,
+			required_approvals INTEGER NOT NULL DEFAULT 1,
 			UNIQUE(action, resource, namespace)
 		);
 
+		-- 审批投票表（多人审批时每位审批人一条）
+		CREATE TABLE IF NOT EXISTS approval_votes (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			approval_id INTEGER NOT NULL REFERENCES approval_requests(id) ON DELETE CASCADE,
+			approver_id INTEGER NOT NULL REFERENCES users(id),
+			comment TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(approval_id, approver_id)
+		);
+
+		-- API 令牌表（仅保存摘要）
+		CREATE TABLE IF NOT EXISTS api_tokens (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			name TEXT NOT NULL,
+			token_hash TEXT UNIQUE NOT NULL,
+			prefix TEXT NOT NULL,
+			role TEXT NOT NULL,
+			namespaces TEXT NOT NULL DEFAULT '',
+			expires_at DATETIME NOT NULL,
+			last_used_at DATETIME,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+
 		-- 索引
 		CREATE INDEX IF NOT EXISTS idx_users_username ON users(username);
 		CREATE INDEX IF NOT EXISTS idx_users_role ON users(role);
@@ -211,6 +291,7 @@ func (c *Client) initSchema() error {
 		CREATE INDEX IF NOT EXISTS idx_sessions_expires_at ON sessions(expires_at);
 		CREATE INDEX IF NOT EXISTS idx_approval_requests_status ON approval_requests(status);
 		CREATE INDEX IF NOT EXISTS idx_approval_requests_user_id ON approval_requests(user_id);
+		CREATE INDEX IF NOT EXISTS idx_api_tokens_user_id ON api_tokens(user_id);
 		`
 	} else {
 		schema = `
@@ -267,6 +348,7 @@ func (c *Client) initSchema() error {
 			approved_at TIMESTAMP WITH TIME ZONE,
 			comment TEXT,
 			request_data TEXT,
+			preview TEXT,
 			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 		);
@@ -279,9 +361,34 @@ func (c *Client) initSchema() error {
 			namespace VARCHAR(200),
 			min_role VARCHAR(50) NOT NULL DEFAULT 'admin',
 			enabled BOOLEAN DEFAULT TRUE,
+			required_approvals INTEGER NOT NULL DEFAULT 1,
 			UNIQUE(action, resource, namespace)
 		);
 
+		-- 审批投票表（多人审批时每位审批人一条）
+		CREATE TABLE IF NOT EXISTS approval_votes (
+			id BIGSERIAL PRIMARY KEY,
+			approval_id BIGINT NOT NULL REFERENCES approval_requests(id) ON DELETE CASCADE,
+			approver_id BIGINT NOT NULL REFERENCES users(id),
+			comment TEXT,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(approval_id, approver_id)
+		);
+
+		-- API 令牌表（仅保存摘要）
+		CREATE TABLE IF NOT EXISTS api_tokens (
+			id BIGSERIAL PRIMARY KEY,
+			user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			name VARCHAR(100) NOT NULL,
+			token_hash VARCHAR(64) UNIQUE NOT NULL,
+			prefix VARCHAR(20) NOT NULL,
+			role VARCHAR(50) NOT NULL,
+			namespaces TEXT NOT NULL DEFAULT '',
+			expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			last_used_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		);
+
 		-- 索引
 		CREATE INDEX IF NOT EXISTS idx_users_username ON users(username);
 		CREATE INDEX IF NOT EXISTS idx_users_role ON users(role);
@@ -290,10 +397,52 @@ func (c *Client) initSchema() error {
 		CREATE INDEX IF NOT EXISTS idx_sessions_expires_at ON sessions(expires_at);
 		CREATE INDEX IF NOT EXISTS idx_approval_requests_status ON approval_requests(status);
 		CREATE INDEX IF NOT EXISTS idx_approval_requests_user_id ON approval_requests(user_id);
+		CREATE INDEX IF NOT EXISTS idx_api_tokens_user_id ON api_tokens(user_id);
 		`
 	}
 
-	_, err := c.db.Exec(schema)
+	if _, err := c.db.Exec(schema); err != nil {
+		return err
+	}
+	return c.migrateSchema()
+}
+
+// migrateSchema 为旧版本数据库补充新增列
+func (c *Client) migrateSchema() error {
+	timestampType := "TIMESTAMP WITH TIME ZONE"
+	if c.dialect == dbutil.DialectSQLite {
+		timestampType = "DATETIME"
+	}
+	for _, col := range []string{"preview", "cluster", "execution_status", "execution_result"} {
+		if err := dbutil.EnsureColumn(c.db, c.dialect, "approval_requests", col, "TEXT"); err != nil {
+			return err
+		}
+	}
+	if err := dbutil.EnsureColumn(c.db, c.dialect, "approval_requests", "executed_at", timestampType); err != nil {
+		return err
+	}
+	// 规则要求的批准人数，创建请求时写入请求本身，规则修改不影响已提交的请求
+	for _, table := range []string{"approval_rules", "approval_requests"} {
+		if err := dbutil.EnsureColumn(c.db, c.dialect, table, "required_approvals", "INTEGER NOT NULL DEFAULT 1"); err != nil {
+			return err
+		}
+	}
+	if err := dbutil.EnsureColumn(c.db, c.dialect, "users", "password_changed_at", timestampType); err != nil {
+		return err
+	}
+	if err := dbutil.EnsureColumn(c.db, c.dialect, "users", "must_change_password", "BOOLEAN NOT NULL DEFAULT FALSE"); err != nil {
+		return err
+	}
+	// 刷新令牌只保存摘要，previous_refresh_hash 用于识别已轮换令牌被重放
+	for _, col := range []string{"refresh_token_hash", "previous_refresh_hash"} {
+		if err := dbutil.EnsureColumn(c.db, c.dialect, "sessions", col, "TEXT"); err != nil {
+			return err
+		}
+	}
+	if err := dbutil.EnsureColumn(c.db, c.dialect, "sessions", "refreshed_at", timestampType); err != nil {
+		return err
+	}
+	_, err := c.db.Exec("CREATE INDEX IF NOT EXISTS idx_sessions_refresh_token_hash ON sessions(refresh_token_hash)")
 	return err
 }
 
@@ -309,13 +458,26 @@ func (c *Client) ensureAdminUser() error {
 		// 创建默认管理员，密码为 admin123
 		hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("admin123"), bcrypt.DefaultCost)
 		_, err = c.db.Exec(`
-			INSERT INTO users (username, password, display_name, role, all_namespaces, enabled)
-			VALUES ('admin', $1, '系统管理员', 'admin', true, true)
+			INSERT INTO users (username, password, display_name, role, all_namespaces, enabled, must_change_password)
+			VALUES ('admin', $1, '系统管理员', 'admin', true, true, true)
 		`, string(hashedPassword))
 		if err != nil {
 			return err
 		}
-		log.Println("默认管理员账户已创建: admin / admin123")
+		log.Println("默认管理员账户已创建: admin / admin123（首次登录需修改密码）")
+	} else {
+		// 旧版本创建的默认管理员仍在使用默认密码时，同样要求修改
+		var hashedPassword string
+		var changedAt sql.NullTime
+		err := c.db.QueryRow("SELECT password, password_changed_at FROM users WHERE username = 'admin'").Scan(&hashedPassword, &changedAt)
+		if err != nil {
+			return err
+		}
+		if !changedAt.Valid && bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte("admin123")) == nil {
+			if _, err := c.db.Exec("UPDATE users SET must_change_password = $1 WHERE username = 'admin'", true); err != nil {
+				return err
+			}
+		}
 	}
 
 	// 插入默认审批规则
@@ -329,7 +491,9 @@ func (c *Client) ensureAdminUser() error {
 			('delete', 'configmaps', '', 'operator', false),
 			('delete', 'secrets', '', 'admin', true),
 			('delete', 'persistentvolumeclaims', '', 'admin', true),
-			('delete', 'namespaces', '', 'admin', true)
+			('delete', 'namespaces', '', 'admin', true),
+			('reveal', 'secrets', '', 'admin', false),
+			('cleanup', 'namespaces', '', 'admin', true)
 		ON CONFLICT DO NOTHING
 	`)
 
@@ -343,74 +507,67 @@ func generateSessionID() string {
 	return hex.EncodeToString(b)
 }
 
-// Login 用户登录
-func (c *Client) Login(username, password, ip, userAgent string) (*User, string, error) {
+// Login 用户登录，返回访问令牌与刷新令牌
+func (c *Client) Login(username, password, ip, userAgent string) (*User, *TokenPair, error) {
 	var user User
 	var hashedPassword string
 	var lastLoginAt sql.NullTime
 	var lastLoginIP sql.NullString
+	var passwordChangedAt sql.NullTime
 
 	err := c.db.QueryRow(`
 		SELECT id, username, password, COALESCE(display_name, ''), COALESCE(email, ''),
 		       role, COALESCE(service_account, ''), COALESCE(sa_namespace, ''), COALESCE(sa_token, ''),
-		       all_namespaces, enabled, last_login_at, last_login_ip, created_at, updated_at
+		       all_namespaces, enabled, must_change_password, password_changed_at,
+		       last_login_at, last_login_ip, created_at, updated_at
 		FROM users WHERE username = $1
 	`, username).Scan(
 		&user.ID, &user.Username, &hashedPassword, &user.DisplayName, &user.Email,
 		&user.Role, &user.ServiceAccount, &user.SANamespace, &user.SAToken,
-		&user.AllNamespaces, &user.Enabled, &lastLoginAt, &lastLoginIP, &user.CreatedAt, &user.UpdatedAt,
+		&user.AllNamespaces, &user.Enabled, &user.MustChangePassword, &passwordChangedAt,
+		&lastLoginAt, &lastLoginIP, &user.CreatedAt, &user.UpdatedAt,
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, "", ErrUserNotFound
+		return nil, nil, ErrUserNotFound
 	}
 	if err != nil {
-		return nil, "", err
+		return nil, nil, err
 	}
 
 	if !user.Enabled {
-		return nil, "", ErrUserDisabled
+		return nil, nil, ErrUserDisabled
+	}
+
+	now := time.Now()
+	if c.guard.locked(user.Username, now) {
+		return nil, nil, ErrUserLocked
 	}
 
 	// 验证密码
 	if err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password)); err != nil {
-		return nil, "", ErrInvalidPassword
+		if c.guard.fail(user.Username, now) {
+			c.emit(UserEvent{
+				Type:     EventUserLockedOut,
+				UserID:   user.ID,
+				Username: user.Username,
+				Role:     user.Role,
+				IP:       ip,
+			})
+			return nil, nil, ErrUserLocked
+		}
+		return nil, nil, ErrInvalidPassword
 	}
+	c.guard.reset(user.Username)
 
 	// 更新最后登录时间
 	c.db.Exec("UPDATE users SET last_login_at = $1, last_login_ip = $2 WHERE id = $3",
 		time.Now(), ip, user.ID)
 
 	// 创建会话
-	sessionID := generateSessionID()
-	expiresAt := time.Now().Add(24 * time.Hour)
-
-	// 生成 JWT
-	claims := JWTClaims{
-		UserID:    user.ID,
-		Username:  user.Username,
-		Role:      user.Role,
-		SessionID: sessionID,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expiresAt),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			Subject:   fmt.Sprintf("%d", user.ID),
-		},
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(c.jwtSecret)
+	tokens, err := c.createSession(&user, ip, userAgent)
 	if err != nil {
-		return nil, "", err
-	}
-
-	// 保存会话
-	_, err = c.db.Exec(`
-		INSERT INTO sessions (id, user_id, token, ip, user_agent, expires_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
-	`, sessionID, user.ID, tokenString, ip, userAgent, expiresAt)
-	if err != nil {
-		return nil, "", err
+		return nil, nil, err
 	}
 
 	if lastLoginAt.Valid {
@@ -419,8 +576,9 @@ func (c *Client) Login(username, password, ip, userAgent string) (*User, string,
 	if lastLoginIP.Valid {
 		user.LastLoginIP = lastLoginIP.String
 	}
+	c.applyPasswordState(&user, passwordChangedAt)
 
-	return &user, tokenString, nil
+	return &user, tokens, nil
 }
 
 // ValidateToken 验证 JWT Token
@@ -429,6 +587,9 @@ func (c *Client) ValidateToken(tokenString string) (*User, error) {
 		return c.jwtSecret, nil
 	})
 
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return nil, ErrTokenExpired
+	}
 	if err != nil {
 		return nil, ErrInvalidToken
 	}
@@ -456,11 +617,36 @@ func (c *Client) ValidateToken(tokenString string) (*User, error) {
 	return c.GetUserByID(claims.UserID)
 }
 
-// Logout 用户登出
-func (c *Client) Logout(tokenString string) error {
+// SessionIDFromToken 解析 Token 中的会话 ID，不校验会话状态，解析失败返回空串
+func (c *Client) SessionIDFromToken(tokenString string) string {
 	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
 		return c.jwtSecret, nil
 	})
+	if err != nil {
+		return ""
+	}
+	claims, ok := token.Claims.(*JWTClaims)
+	if !ok || !token.Valid {
+		return ""
+	}
+	return claims.SessionID
+}
+
+// SessionExpiry 返回会话过期时间，会话不存在（已登出或被撤销）时返回 ErrInvalidToken
+func (c *Client) SessionExpiry(sessionID string) (time.Time, error) {
+	var expiresAt time.Time
+	err := c.db.QueryRow("SELECT expires_at FROM sessions WHERE id = $1", sessionID).Scan(&expiresAt)
+	if err == sql.ErrNoRows {
+		return time.Time{}, ErrInvalidToken
+	}
+	return expiresAt, err
+}
+
+// Logout 用户登出，删除会话的同时使其刷新令牌失效；访问令牌已过期时同样生效
+func (c *Client) Logout(tokenString string) error {
+	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return c.jwtSecret, nil
+	}, jwt.WithoutClaimsValidation())
 	if err != nil {
 		return nil
 	}
@@ -480,16 +666,19 @@ func (c *Client) GetUserByID(id int64) (*User, error) {
 	var user User
 	var lastLoginAt sql.NullTime
 	var lastLoginIP sql.NullString
+	var passwordChangedAt sql.NullTime
 
 	err := c.db.QueryRow(`
 		SELECT id, username, COALESCE(display_name, ''), COALESCE(email, ''),
 		       role, COALESCE(service_account, ''), COALESCE(sa_namespace, ''),
-		       all_namespaces, enabled, last_login_at, last_login_ip, created_at, updated_at
+		       all_namespaces, enabled, must_change_password, password_changed_at,
+		       last_login_at, last_login_ip, created_at, updated_at
 		FROM users WHERE id = $1
 	`, id).Scan(
 		&user.ID, &user.Username, &user.DisplayName, &user.Email,
 		&user.Role, &user.ServiceAccount, &user.SANamespace,
-		&user.AllNamespaces, &user.Enabled, &lastLoginAt, &lastLoginIP, &user.CreatedAt, &user.UpdatedAt,
+		&user.AllNamespaces, &user.Enabled, &user.MustChangePassword, &passwordChangedAt,
+		&lastLoginAt, &lastLoginIP, &user.CreatedAt, &user.UpdatedAt,
 	)
 
 	if err == sql.ErrNoRows {
@@ -505,6 +694,7 @@ func (c *Client) GetUserByID(id int64) (*User, error) {
 	if lastLoginIP.Valid {
 		user.LastLoginIP = lastLoginIP.String
 	}
+	c.applyPasswordState(&user, passwordChangedAt)
 
 	return &user, nil
 }
@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// 用户生命周期事件类型
+const (
+	EventUserCreated         = "user.created"
+	EventUserRoleChanged     = "user.role_changed"
+	EventUserDisabled        = "user.disabled"
+	EventUserEnabled         = "user.enabled"
+	EventUserDeleted         = "user.deleted"
+	EventUserLockedOut       = "user.locked_out"
+	EventUserSessionsRevoked = "user.sessions_revoked" // 管理员强制下线
+)
+
+// UserEvent 用户生命周期事件，供身份治理系统对账
+type UserEvent struct {
+	Type         string    `json:"type"`
+	UserID       int64     `json:"userId"`
+	Username     string    `json:"username"`
+	Role         string    `json:"role,omitempty"`
+	PreviousRole string    `json:"previousRole,omitempty"`
+	IP           string    `json:"ip,omitempty"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+// UserEventHandler 用户事件回调
+type UserEventHandler func(UserEvent)
+
+// 审批事件类型
+const (
+	EventApprovalCreated  = "approval.created"
+	EventApprovalVoted    = "approval.voted" // 多人审批中记录了一票批准，尚未达到所需人数
+	EventApprovalApproved = "approval.approved"
+	EventApprovalRejected = "approval.rejected"
+)
+
+// ApprovalEventHandler 审批状态变更回调，approval 为变更后的审批请求
+type ApprovalEventHandler func(eventType string, approval *ApprovalRequest)
+
+// 登录失败锁定策略
+const (
+	MaxFailedLogins = 5
+	LockoutDuration = 15 * time.Minute
+)
+
+// loginGuard 记录连续登录失败次数，达到阈值后临时锁定账户
+type loginGuard struct {
+	mu       sync.Mutex
+	failures map[string]*loginFailure
+}
+
+type loginFailure struct {
+	count       int
+	lockedUntil time.Time
+}
+
+func newLoginGuard() *loginGuard {
+	return &loginGuard{failures: make(map[string]*loginFailure)}
+}
+
+// locked 判断账户当前是否处于锁定期
+func (g *loginGuard) locked(username string, now time.Time) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	f, ok := g.failures[username]
+	return ok && now.Before(f.lockedUntil)
+}
+
+// fail 记录一次失败，返回本次是否触发锁定
+func (g *loginGuard) fail(username string, now time.Time) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	f, ok := g.failures[username]
+	if !ok || (!f.lockedUntil.IsZero() && !now.Before(f.lockedUntil)) {
+		f = &loginFailure{}
+		g.failures[username] = f
+	}
+	f.count++
+	if f.count >= MaxFailedLogins {
+		f.lockedUntil = now.Add(LockoutDuration)
+		f.count = 0
+		return true
+	}
+	return false
+}
+
+// reset 登录成功后清除失败记录
+func (g *loginGuard) reset(username string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.failures, username)
+}
+
+// SetEventHandler 设置用户生命周期事件回调
+func (c *Client) SetEventHandler(handler UserEventHandler) {
+	c.eventHandler = handler
+}
+
+func (c *Client) emit(event UserEvent) {
+	if c.eventHandler == nil {
+		return
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	c.eventHandler(event)
+}
+
+// SetApprovalHandler 设置审批状态变更回调（用于实时推送待审批数量与审批结果）
+func (c *Client) SetApprovalHandler(handler ApprovalEventHandler) {
+	c.approvalHandler = handler
+}
+
+func (c *Client) emitApproval(eventType string, approvalID int64) {
+	if c.approvalHandler == nil {
+		return
+	}
+	approval, err := c.GetApprovalByID(approvalID)
+	if err != nil {
+		return
+	}
+	c.approvalHandler(eventType, approval)
+}
@@ -0,0 +1,52 @@
+package auth
+
+import "fmt"
+
+// 命名空间授权级别：read 只读；write 可修改命名空间内资源；
+// admin 额外可删除命名空间并管理配额、限制范围与 RBAC
+const (
+	NamespacePermRead  = "read"
+	NamespacePermWrite = "write"
+	NamespacePermAdmin = "admin"
+)
+
+var namespacePermLevels = map[string]int{
+	NamespacePermRead:  1,
+	NamespacePermWrite: 2,
+	NamespacePermAdmin: 3,
+}
+
+// ValidNamespacePermission 检查授权级别是否合法
+func ValidNamespacePermission(permission string) bool {
+	_, ok := namespacePermLevels[permission]
+	return ok
+}
+
+// NamespacePermissionAtLeast 判断已授予的级别是否满足要求，未知级别视为无权限
+func NamespacePermissionAtLeast(granted, required string) bool {
+	g, ok := namespacePermLevels[granted]
+	if !ok {
+		return false
+	}
+	return g >= namespacePermLevels[required]
+}
+
+// resolveNamespacePermissions 计算每个命名空间的授权级别：优先使用请求中显式指定的级别，
+// 其次保留已有授权，默认 write（与引入分级前的行为一致）
+func resolveNamespacePermissions(namespaces []string, requested, existing map[string]string) (map[string]string, error) {
+	resolved := make(map[string]string, len(namespaces))
+	for _, ns := range namespaces {
+		permission := requested[ns]
+		if permission == "" {
+			permission = existing[ns]
+		}
+		if permission == "" {
+			permission = NamespacePermWrite
+		}
+		if !ValidNamespacePermission(permission) {
+			return nil, fmt.Errorf("命名空间 %s 的授权级别无效: %s", ns, permission)
+		}
+		resolved[ns] = permission
+	}
+	return resolved, nil
+}
@@ -0,0 +1,44 @@
+package auth
+
+import "testing"
+
+func TestNamespacePermissionAtLeast(t *testing.T) {
+	tests := []struct {
+		granted, required string
+		want              bool
+	}{
+		{NamespacePermRead, NamespacePermRead, true},
+		{NamespacePermRead, NamespacePermWrite, false},
+		{NamespacePermWrite, NamespacePermRead, true},
+		{NamespacePermWrite, NamespacePermAdmin, false},
+		{NamespacePermAdmin, NamespacePermWrite, true},
+		{"", NamespacePermRead, false},
+		{"owner", NamespacePermRead, false},
+	}
+	for _, tt := range tests {
+		if got := NamespacePermissionAtLeast(tt.granted, tt.required); got != tt.want {
+			t.Fatalf("NamespacePermissionAtLeast(%q, %q) = %v, want %v", tt.granted, tt.required, got, tt.want)
+		}
+	}
+}
+
+func TestResolveNamespacePermissions(t *testing.T) {
+	resolved, err := resolveNamespacePermissions(
+		[]string{"dev", "prod", "test"},
+		map[string]string{"prod": NamespacePermRead},
+		map[string]string{"dev": NamespacePermAdmin, "prod": NamespacePermWrite},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"dev": NamespacePermAdmin, "prod": NamespacePermRead, "test": NamespacePermWrite}
+	for ns, permission := range want {
+		if resolved[ns] != permission {
+			t.Fatalf("%s = %q, want %q", ns, resolved[ns], permission)
+		}
+	}
+
+	if _, err := resolveNamespacePermissions([]string{"dev"}, map[string]string{"dev": "owner"}, nil); err == nil {
+		t.Fatal("expected invalid permission to be rejected")
+	}
+}
@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrPasswordChangeRequired 用户需先修改密码（管理员重置、默认管理员首次登录或密码过期）
+var ErrPasswordChangeRequired = errors.New("需要修改密码后才能继续使用")
+
+// ErrPasswordReused 新密码不能与旧密码相同
+var ErrPasswordReused = errors.New("新密码不能与旧密码相同")
+
+// PasswordPolicy 密码复杂度与有效期策略
+type PasswordPolicy struct {
+	MinLength     int  `json:"minLength"`
+	RequireUpper  bool `json:"requireUpper"`
+	RequireLower  bool `json:"requireLower"`
+	RequireDigit  bool `json:"requireDigit"`
+	RequireSymbol bool `json:"requireSymbol"`
+	// MaxAgeDays 密码最长使用天数，超过后需修改，0 表示不过期
+	MaxAgeDays int `json:"maxAgeDays"`
+}
+
+// DefaultPasswordPolicy 默认策略：至少 8 位，不强制字符类别，不过期
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{MinLength: 8}
+}
+
+// Validate 按策略校验密码，返回包含全部未满足要求的错误
+func (p PasswordPolicy) Validate(password string) error {
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+
+	var unmet []string
+	if len([]rune(password)) < p.MinLength {
+		unmet = append(unmet, fmt.Sprintf("长度至少 %d 位", p.MinLength))
+	}
+	if p.RequireUpper && !upper {
+		unmet = append(unmet, "包含大写字母")
+	}
+	if p.RequireLower && !lower {
+		unmet = append(unmet, "包含小写字母")
+	}
+	if p.RequireDigit && !digit {
+		unmet = append(unmet, "包含数字")
+	}
+	if p.RequireSymbol && !symbol {
+		unmet = append(unmet, "包含特殊字符")
+	}
+	if len(unmet) > 0 {
+		return fmt.Errorf("密码不符合要求: %s", strings.Join(unmet, "，"))
+	}
+	return nil
+}
+
+// SetPasswordPolicy 设置密码策略，未设置时使用 DefaultPasswordPolicy
+func (c *Client) SetPasswordPolicy(policy PasswordPolicy) {
+	c.passwordPolicy = policy
+}
+
+// PasswordPolicy 返回当前密码策略
+func (c *Client) PasswordPolicy() PasswordPolicy {
+	return c.passwordPolicy
+}
+
+// applyPasswordState 填充密码修改时间，并在密码超过最长使用天数时标记为需要修改。
+// 旧数据没有修改时间时以创建时间为准。
+func (c *Client) applyPasswordState(user *User, changedAt sql.NullTime) {
+	if changedAt.Valid {
+		user.PasswordChangedAt = &changedAt.Time
+	}
+	if c.passwordPolicy.MaxAgeDays <= 0 || user.MustChangePassword {
+		return
+	}
+	since := user.CreatedAt
+	if user.PasswordChangedAt != nil {
+		since = *user.PasswordChangedAt
+	}
+	if time.Since(since) > time.Duration(c.passwordPolicy.MaxAgeDays)*24*time.Hour {
+		user.MustChangePassword = true
+	}
+}
@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	strict := PasswordPolicy{MinLength: 10, RequireUpper: true, RequireLower: true, RequireDigit: true, RequireSymbol: true}
+
+	tests := []struct {
+		name     string
+		policy   PasswordPolicy
+		password string
+		unmet    []string
+	}{
+		{name: "default accepts long password", policy: DefaultPasswordPolicy(), password: "longenough"},
+		{name: "default rejects short password", policy: DefaultPasswordPolicy(), password: "short", unmet: []string{"长度至少 8 位"}},
+		{name: "strict accepts complex password", policy: strict, password: "Str0ng!Pass"},
+		{name: "strict lists all unmet rules", policy: strict, password: "weak", unmet: []string{"长度至少 10 位", "大写字母", "数字", "特殊字符"}},
+		{name: "length counts runes", policy: PasswordPolicy{MinLength: 4}, password: "密码密码"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate(tt.password)
+			if len(tt.unmet) == 0 {
+				if err != nil {
+					t.Fatalf("expected password to pass, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected password to be rejected")
+			}
+			for _, want := range tt.unmet {
+				if !strings.Contains(err.Error(), want) {
+					t.Fatalf("expected error to mention %q, got %v", want, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package auth
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// RefreshTokenPrefix 刷新令牌前缀
+	RefreshTokenPrefix = "kdr_"
+	// DefaultAccessTokenTTL 访问令牌（JWT）默认有效期
+	DefaultAccessTokenTTL = time.Hour
+	// DefaultRefreshTokenTTL 刷新令牌默认有效期，每次刷新后顺延
+	DefaultRefreshTokenTTL = 7 * 24 * time.Hour
+	// refreshReuseGrace 轮换后短时间内再次提交旧刷新令牌视为并发刷新（如多个标签页），
+	// 仅拒绝而不撤销会话；超过该窗口视为令牌泄露
+	refreshReuseGrace = 10 * time.Second
+)
+
+// ErrRefreshTokenReused 已轮换的刷新令牌被再次使用，会话已被撤销
+var ErrRefreshTokenReused = errors.New("刷新令牌已失效，会话已撤销，请重新登录")
+
+// TokenPair 登录或刷新后签发的令牌
+type TokenPair struct {
+	AccessToken      string    `json:"token"`
+	RefreshToken     string    `json:"refreshToken"`
+	ExpiresAt        time.Time `json:"expiresAt"`        // 访问令牌过期时间
+	RefreshExpiresAt time.Time `json:"refreshExpiresAt"` // 刷新令牌（会话）过期时间
+}
+
+// SetTokenLifetimes 设置访问令牌与刷新令牌有效期，非正值保持默认
+func (c *Client) SetTokenLifetimes(access, refresh time.Duration) {
+	if access > 0 {
+		c.accessTokenTTL = access
+	}
+	if refresh > 0 {
+		c.refreshTokenTTL = refresh
+	}
+}
+
+func newRefreshToken() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return RefreshTokenPrefix + hex.EncodeToString(b)
+}
+
+// signAccessToken 为会话签发访问令牌
+func (c *Client) signAccessToken(user *User, sessionID string, now time.Time) (string, time.Time, error) {
+	expiresAt := now.Add(c.accessTokenTTL)
+	claims := JWTClaims{
+		UserID:    user.ID,
+		Username:  user.Username,
+		Role:      user.Role,
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Subject:   fmt.Sprintf("%d", user.ID),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(c.jwtSecret)
+	return token, expiresAt, err
+}
+
+// createSession 创建会话并签发访问令牌与刷新令牌
+func (c *Client) createSession(user *User, ip, userAgent string) (*TokenPair, error) {
+	now := time.Now()
+	sessionID := generateSessionID()
+	accessToken, accessExpiresAt, err := c.signAccessToken(user, sessionID, now)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken := newRefreshToken()
+	refreshExpiresAt := now.Add(c.refreshTokenTTL)
+
+	_, err = c.db.Exec(`
+		INSERT INTO sessions (id, user_id, token, refresh_token_hash, ip, user_agent, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, sessionID, user.ID, accessToken, hashAPIToken(refreshToken), ip, userAgent, refreshExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		ExpiresAt:        accessExpiresAt,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+// RefreshSession 用刷新令牌换取新的访问令牌，同时轮换刷新令牌并顺延会话有效期。
+// 已轮换的旧令牌在宽限期外被再次使用时撤销整个会话
+func (c *Client) RefreshSession(refreshToken, ip, userAgent string) (*User, *TokenPair, error) {
+	hash := hashAPIToken(refreshToken)
+
+	var sessionID string
+	var userID int64
+	var expiresAt time.Time
+	err := c.db.QueryRow(`
+		SELECT id, user_id, expires_at FROM sessions WHERE refresh_token_hash = $1
+	`, hash).Scan(&sessionID, &userID, &expiresAt)
+	if err == sql.ErrNoRows {
+		return nil, nil, c.checkRefreshReuse(hash)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	if !now.Before(expiresAt) {
+		_, _ = c.db.Exec("DELETE FROM sessions WHERE id = $1", sessionID)
+		return nil, nil, ErrTokenExpired
+	}
+
+	user, err := c.GetUserByID(userID)
+	if err == ErrUserNotFound {
+		return nil, nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if !user.Enabled {
+		_, _ = c.db.Exec("DELETE FROM sessions WHERE id = $1", sessionID)
+		return nil, nil, ErrUserDisabled
+	}
+
+	accessToken, accessExpiresAt, err := c.signAccessToken(user, sessionID, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	newToken := newRefreshToken()
+	refreshExpiresAt := now.Add(c.refreshTokenTTL)
+
+	// 以旧摘要为条件更新，保证并发刷新时只有一个请求轮换成功
+	result, err := c.db.Exec(`
+		UPDATE sessions
+		SET token = $1, refresh_token_hash = $2, previous_refresh_hash = $3, refreshed_at = $4,
+		    ip = $5, user_agent = $6, expires_at = $7
+		WHERE id = $8 AND refresh_token_hash = $3
+	`, accessToken, hashAPIToken(newToken), hash, now, ip, userAgent, refreshExpiresAt, sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return nil, nil, ErrInvalidToken
+	}
+
+	return user, &TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     newToken,
+		ExpiresAt:        accessExpiresAt,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+// checkRefreshReuse 处理未匹配当前刷新令牌的请求：命中已轮换的旧令牌且超出宽限期时撤销会话
+func (c *Client) checkRefreshReuse(hash string) error {
+	var sessionID string
+	var refreshedAt sql.NullTime
+	err := c.db.QueryRow(`
+		SELECT id, refreshed_at FROM sessions WHERE previous_refresh_hash = $1
+	`, hash).Scan(&sessionID, &refreshedAt)
+	if err == sql.ErrNoRows {
+		return ErrInvalidToken
+	}
+	if err != nil {
+		return err
+	}
+	if refreshedAt.Valid && time.Since(refreshedAt.Time) <= refreshReuseGrace {
+		return ErrInvalidToken
+	}
+	if _, err := c.db.Exec("DELETE FROM sessions WHERE id = $1", sessionID); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+// RevokeRefreshToken 撤销刷新令牌所属的会话
+func (c *Client) RevokeRefreshToken(refreshToken string) error {
+	_, err := c.db.Exec("DELETE FROM sessions WHERE refresh_token_hash = $1", hashAPIToken(refreshToken))
+	return err
+}
@@ -1,8 +1,11 @@
 package auth
 
 import (
+	"bytes"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	dbutil "github.com/k8s-dashboard/backend/internal/db"
 )
@@ -63,7 +66,7 @@ func TestSQLiteUserLifecycle(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Login failed: %v", err)
 	}
-	if token == "" {
+	if token.AccessToken == "" || token.RefreshToken == "" {
 		t.Fatalf("expected token not empty")
 	}
 
@@ -75,3 +78,837 @@ func TestSQLiteUserLifecycle(t *testing.T) {
 		t.Fatalf("expected at least one session")
 	}
 }
+
+func TestSQLiteUserLifecycleEvents(t *testing.T) {
+	conn, dialect, err := dbutil.Open(dbutil.Config{
+		SQLitePath:          filepath.Join(t.TempDir(), "auth-events.db"),
+		AllowSQLiteFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer conn.Close()
+
+	client, err := NewClient(conn, dialect, "test-secret")
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	var events []UserEvent
+	client.SetEventHandler(func(e UserEvent) {
+		events = append(events, e)
+	})
+
+	created, err := client.CreateUser(&CreateUserRequest{
+		Username: "bob",
+		Password: "Passw0rd!",
+		Role:     "viewer",
+	})
+	if err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+
+	if _, err := client.UpdateUser(created.ID, &UpdateUserRequest{
+		Role:          "operator",
+		AllNamespaces: true,
+		Enabled:       true,
+	}); err != nil {
+		t.Fatalf("UpdateUser failed: %v", err)
+	}
+
+	for i := 0; i < MaxFailedLogins; i++ {
+		_, _, err = client.Login("bob", "wrong", "127.0.0.1", "test-agent")
+	}
+	if err != ErrUserLocked {
+		t.Fatalf("expected ErrUserLocked after %d failures, got %v", MaxFailedLogins, err)
+	}
+	if _, _, err := client.Login("bob", "Passw0rd!", "127.0.0.1", "test-agent"); err != ErrUserLocked {
+		t.Fatalf("expected locked account to reject correct password, got %v", err)
+	}
+
+	want := []string{EventUserCreated, EventUserRoleChanged, EventUserLockedOut}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %d: %+v", len(want), len(events), events)
+	}
+	for i, typ := range want {
+		if events[i].Type != typ {
+			t.Fatalf("event %d: expected %q, got %q", i, typ, events[i].Type)
+		}
+	}
+	if events[1].PreviousRole != "viewer" || events[1].Role != "operator" {
+		t.Fatalf("unexpected role change event: %+v", events[1])
+	}
+}
+
+func TestSQLiteNamespacePermissions(t *testing.T) {
+	conn, dialect, err := dbutil.Open(dbutil.Config{
+		SQLitePath:          filepath.Join(t.TempDir(), "auth.db"),
+		AllowSQLiteFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer conn.Close()
+
+	client, err := NewClient(conn, dialect, "test-secret")
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	user, err := client.CreateUser(&CreateUserRequest{
+		Username:             "carol",
+		Password:             "Passw0rd!",
+		Role:                 "operator",
+		Namespaces:           []string{"dev", "prod"},
+		NamespacePermissions: map[string]string{"prod": NamespacePermRead},
+	})
+	if err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+
+	permissions := func() map[string]string {
+		t.Helper()
+		namespaces, err := client.GetUserNamespaces(user.ID)
+		if err != nil {
+			t.Fatalf("GetUserNamespaces failed: %v", err)
+		}
+		result := map[string]string{}
+		for _, ns := range namespaces {
+			result[ns.Namespace] = ns.Permissions
+		}
+		return result
+	}
+	if got := permissions(); got["dev"] != NamespacePermWrite || got["prod"] != NamespacePermRead {
+		t.Fatalf("unexpected permissions after create: %v", got)
+	}
+
+	// 未显式指定级别时保留已有授权
+	if _, err := client.UpdateUser(user.ID, &UpdateUserRequest{
+		Role:                 "operator",
+		Namespaces:           []string{"dev", "prod", "test"},
+		NamespacePermissions: map[string]string{"dev": NamespacePermAdmin},
+		Enabled:              true,
+	}); err != nil {
+		t.Fatalf("UpdateUser failed: %v", err)
+	}
+	if got := permissions(); got["dev"] != NamespacePermAdmin || got["prod"] != NamespacePermRead || got["test"] != NamespacePermWrite {
+		t.Fatalf("unexpected permissions after update: %v", got)
+	}
+
+	if _, err := client.UpdateUser(user.ID, &UpdateUserRequest{
+		Role:                 "operator",
+		Namespaces:           []string{"dev"},
+		NamespacePermissions: map[string]string{"dev": "owner"},
+		Enabled:              true,
+	}); err == nil {
+		t.Fatal("expected invalid permission to be rejected")
+	}
+}
+
+func TestSQLiteBulkUserImportExport(t *testing.T) {
+	conn, dialect, err := dbutil.Open(dbutil.Config{
+		SQLitePath:          filepath.Join(t.TempDir(), "auth-bulk.db"),
+		AllowSQLiteFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer conn.Close()
+
+	client, err := NewClient(conn, dialect, "test-secret")
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	csvInput := `username,displayName,email,role,allNamespaces,namespaces,enabled,password
+carol,Carol,carol@example.com,operator,false,default;prod,true,Passw0rd!
+dave,Dave,dave@example.com,superuser,false,,true,Passw0rd!
+carol,Carol 2,,viewer,false,,true,Passw0rd!
+`
+	records, err := ParseUsersCSV(strings.NewReader(csvInput))
+	if err != nil {
+		t.Fatalf("ParseUsersCSV failed: %v", err)
+	}
+
+	result, err := client.ImportUsers(records, false)
+	if err != nil {
+		t.Fatalf("ImportUsers failed: %v", err)
+	}
+	if result.Applied || result.Failed != 2 {
+		t.Fatalf("expected invalid file to be rejected with 2 failures, got %+v", result)
+	}
+	if _, _, err := client.Login("carol", "Passw0rd!", "127.0.0.1", "test"); err == nil {
+		t.Fatalf("expected no user to be created when validation fails")
+	}
+
+	records[1].Role = "viewer"
+	records = records[:2]
+	dry, err := client.ImportUsers(records, true)
+	if err != nil {
+		t.Fatalf("dry-run ImportUsers failed: %v", err)
+	}
+	if dry.Applied || dry.Created != 2 || dry.Failed != 0 {
+		t.Fatalf("unexpected dry-run report: %+v", dry)
+	}
+
+	applied, err := client.ImportUsers(records, false)
+	if err != nil {
+		t.Fatalf("ImportUsers failed: %v", err)
+	}
+	if !applied.Applied || applied.Created != 2 {
+		t.Fatalf("unexpected import report: %+v", applied)
+	}
+	if _, _, err := client.Login("carol", "Passw0rd!", "127.0.0.1", "test"); err != nil {
+		t.Fatalf("expected imported user to log in: %v", err)
+	}
+
+	// 再次导入同名用户为更新，未提供密码时保留原密码
+	disabled := false
+	update, err := client.ImportUsers([]UserRecord{{Username: "dave", Role: "operator", Enabled: &disabled}}, false)
+	if err != nil {
+		t.Fatalf("update ImportUsers failed: %v", err)
+	}
+	if update.Updated != 1 {
+		t.Fatalf("expected update, got %+v", update)
+	}
+
+	// 写入阶段任一行失败时整体回滚，之前的行也不生效
+	if _, err := conn.Exec(`CREATE TRIGGER reject_erin BEFORE INSERT ON users WHEN NEW.username = 'erin'
+		BEGIN SELECT RAISE(ABORT, 'rejected'); END`); err != nil {
+		t.Fatalf("create trigger failed: %v", err)
+	}
+	failed, err := client.ImportUsers([]UserRecord{
+		{Username: "carol", Role: "admin", Namespaces: []string{"default", "prod"}},
+		{Username: "frank", Role: "viewer", Password: "Passw0rd!"},
+		{Username: "erin", Role: "viewer", Password: "Passw0rd!"},
+	}, false)
+	if err == nil || !strings.Contains(err.Error(), "第 3 行") || failed.Applied {
+		t.Fatalf("expected row 3 to fail the import, got %+v, %v", failed, err)
+	}
+
+	exported, err := client.ExportUsers()
+	if err != nil {
+		t.Fatalf("ExportUsers failed: %v", err)
+	}
+	byName := make(map[string]UserRecord)
+	for _, rec := range exported {
+		byName[rec.Username] = rec
+	}
+	if carol := byName["carol"]; carol.Role != "operator" || len(carol.Namespaces) != 2 {
+		t.Fatalf("unexpected exported carol: %+v", carol)
+	}
+	if _, ok := byName["frank"]; ok {
+		t.Fatalf("expected frank to be rolled back")
+	}
+	if dave := byName["dave"]; dave.Role != "operator" || dave.Enabled == nil || *dave.Enabled {
+		t.Fatalf("unexpected exported dave: %+v", dave)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteUsersCSV(&buf, exported); err != nil {
+		t.Fatalf("WriteUsersCSV failed: %v", err)
+	}
+	roundTrip, err := ParseUsersCSV(&buf)
+	if err != nil {
+		t.Fatalf("ParseUsersCSV round trip failed: %v", err)
+	}
+	if len(roundTrip) != len(exported) {
+		t.Fatalf("expected %d records after round trip, got %d", len(exported), len(roundTrip))
+	}
+}
+
+func TestSQLiteApprovalPreview(t *testing.T) {
+	conn, dialect, err := dbutil.Open(dbutil.Config{
+		SQLitePath:          filepath.Join(t.TempDir(), "auth-approval.db"),
+		AllowSQLiteFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer conn.Close()
+
+	// 模拟旧版本没有 preview 列的审批表
+	if _, err := conn.Exec(`
+		CREATE TABLE approval_requests (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			action TEXT NOT NULL,
+			resource TEXT NOT NULL,
+			resource_name TEXT NOT NULL,
+			namespace TEXT,
+			reason TEXT,
+			status TEXT DEFAULT 'pending',
+			approver_id INTEGER,
+			approved_at DATETIME,
+			comment TEXT,
+			request_data TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`); err != nil {
+		t.Fatalf("create legacy table failed: %v", err)
+	}
+
+	client, err := NewClient(conn, dialect, "test-secret")
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	var adminID int64
+	if err := conn.QueryRow("SELECT id FROM users WHERE username = 'admin'").Scan(&adminID); err != nil {
+		t.Fatalf("query admin failed: %v", err)
+	}
+
+	current, requested := int32(3), int32(1)
+	created, err := client.CreateApproval(adminID, &CreateApprovalRequest{
+		Action:       "scale",
+		Resource:     "deployments",
+		ResourceName: "web",
+		Namespace:    "default",
+		RequestData:  map[string]int32{"replicas": requested},
+		Preview: &ApprovalPreview{
+			Kind:              "Deployment",
+			CurrentReplicas:   &current,
+			RequestedReplicas: &requested,
+			AffectedPods:      2,
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateApproval failed: %v", err)
+	}
+	if created.Preview == nil || created.Preview.AffectedPods != 2 || *created.Preview.RequestedReplicas != 1 {
+		t.Fatalf("unexpected preview: %+v", created.Preview)
+	}
+
+	list, err := client.ListApprovals(ListApprovalParams{Status: "pending"})
+	if err != nil {
+		t.Fatalf("ListApprovals failed: %v", err)
+	}
+	if len(list.Items) != 1 || list.Items[0].Preview == nil || *list.Items[0].Preview.CurrentReplicas != 3 {
+		t.Fatalf("unexpected approvals: %+v", list.Items)
+	}
+}
+
+func TestSQLiteApprovalExecution(t *testing.T) {
+	conn, dialect, err := dbutil.Open(dbutil.Config{
+		SQLitePath:          filepath.Join(t.TempDir(), "auth.db"),
+		AllowSQLiteFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer conn.Close()
+
+	client, err := NewClient(conn, dialect, "test-secret")
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	requester, err := client.CreateUser(&CreateUserRequest{Username: "ivan", Password: "Passw0rd!", Role: "operator"})
+	if err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	var adminID int64
+	if err := conn.QueryRow("SELECT id FROM users WHERE username = 'admin'").Scan(&adminID); err != nil {
+		t.Fatalf("query admin failed: %v", err)
+	}
+
+	if needs, err := client.NeedsApproval("operator", "delete", "deployments", "default"); err != nil || !needs {
+		t.Fatalf("expected operator deleting deployments to need approval, got %v %v", needs, err)
+	}
+	if needs, err := client.NeedsApproval("operator", "cleanup", "namespaces", "default"); err != nil || !needs {
+		t.Fatalf("expected operator namespace cleanup to need approval, got %v %v", needs, err)
+	}
+
+	approval, err := client.CreateApproval(requester.ID, &CreateApprovalRequest{
+		Action:       "delete",
+		Resource:     "deployments",
+		ResourceName: "web",
+		Namespace:    "default",
+		Cluster:      "prod",
+	})
+	if err != nil {
+		t.Fatalf("CreateApproval failed: %v", err)
+	}
+	if approval.Cluster != "prod" {
+		t.Fatalf("expected cluster to be stored, got %q", approval.Cluster)
+	}
+
+	if err := client.RecordApprovalExecution(approval.ID, ApprovalExecutionSucceeded, "ok"); err == nil {
+		t.Fatal("expected pending approval execution to be rejected")
+	}
+	if approved, err := client.ApproveRequest(approval.ID, adminID, "lgtm"); err != nil || !approved {
+		t.Fatalf("ApproveRequest failed: %v %v", approved, err)
+	}
+	if err := client.RecordApprovalExecution(approval.ID, ApprovalExecutionFailed, "deployments web 已不存在"); err != nil {
+		t.Fatalf("RecordApprovalExecution failed: %v", err)
+	}
+	if err := client.RecordApprovalExecution(approval.ID, ApprovalExecutionSucceeded, "again"); err == nil {
+		t.Fatal("expected approval to execute only once")
+	}
+
+	executed, err := client.GetApprovalByID(approval.ID)
+	if err != nil {
+		t.Fatalf("GetApprovalByID failed: %v", err)
+	}
+	if executed.ExecutionStatus != ApprovalExecutionFailed || executed.ExecutedAt == nil || executed.ExecutionResult == "" {
+		t.Fatalf("unexpected execution state: %+v", executed)
+	}
+	list, err := client.ListApprovals(ListApprovalParams{Status: "approved"})
+	if err != nil || len(list.Items) != 1 || list.Items[0].ExecutionStatus != ApprovalExecutionFailed {
+		t.Fatalf("unexpected approvals: %+v (%v)", list, err)
+	}
+}
+
+func TestSQLiteConsumeApproval(t *testing.T) {
+	conn, dialect, err := dbutil.Open(dbutil.Config{
+		SQLitePath:          filepath.Join(t.TempDir(), "auth.db"),
+		AllowSQLiteFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer conn.Close()
+
+	client, err := NewClient(conn, dialect, "test-secret")
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	requester, err := client.CreateUser(&CreateUserRequest{Username: "judy", Password: "Passw0rd!", Role: "operator"})
+	if err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	var adminID int64
+	if err := conn.QueryRow("SELECT id FROM users WHERE username = 'admin'").Scan(&adminID); err != nil {
+		t.Fatalf("query admin failed: %v", err)
+	}
+
+	if needs, err := client.NeedsApproval("operator", "reveal", "secrets", "default"); err != nil || needs {
+		t.Fatalf("expected reveal approval rule to be disabled by default, got %v %v", needs, err)
+	}
+
+	req := &CreateApprovalRequest{Action: "reveal", Resource: "secrets", ResourceName: "db", Namespace: "default", Cluster: "prod"}
+	since := time.Now().Add(-time.Hour)
+	if approval, err := client.ConsumeApproval(requester.ID, req, since, "keys=password"); err != nil || approval != nil {
+		t.Fatalf("expected no approval to consume, got %+v (%v)", approval, err)
+	}
+
+	created, err := client.CreateApproval(requester.ID, req)
+	if err != nil {
+		t.Fatalf("CreateApproval failed: %v", err)
+	}
+	pending, err := client.FindPendingApproval(requester.ID, req)
+	if err != nil || pending == nil || pending.ID != created.ID {
+		t.Fatalf("expected pending approval %d, got %+v (%v)", created.ID, pending, err)
+	}
+	other := *req
+	other.Cluster = "staging"
+	if pending, err := client.FindPendingApproval(requester.ID, &other); err != nil || pending != nil {
+		t.Fatalf("expected approvals to be scoped by cluster, got %+v (%v)", pending, err)
+	}
+	if approval, err := client.ConsumeApproval(requester.ID, req, since, "keys=password"); err != nil || approval != nil {
+		t.Fatalf("expected pending approval not to be consumable, got %+v (%v)", approval, err)
+	}
+
+	if approved, err := client.ApproveRequest(created.ID, adminID, "ok"); err != nil || !approved {
+		t.Fatalf("ApproveRequest failed: %v %v", approved, err)
+	}
+	if approval, err := client.ConsumeApproval(requester.ID, req, time.Now().Add(time.Minute), "keys=password"); err != nil || approval != nil {
+		t.Fatalf("expected approval older than since to be ignored, got %+v (%v)", approval, err)
+	}
+	consumed, err := client.ConsumeApproval(requester.ID, req, since, "keys=password")
+	if err != nil || consumed == nil {
+		t.Fatalf("ConsumeApproval failed: %+v (%v)", consumed, err)
+	}
+	if consumed.ExecutionStatus != ApprovalExecutionSucceeded || consumed.ExecutionResult != "keys=password" {
+		t.Fatalf("unexpected execution state: %+v", consumed)
+	}
+	if approval, err := client.ConsumeApproval(requester.ID, req, since, "keys=password"); err != nil || approval != nil {
+		t.Fatalf("expected approval to be consumed only once, got %+v (%v)", approval, err)
+	}
+}
+
+func TestSQLiteDualControlApproval(t *testing.T) {
+	conn, dialect, err := dbutil.Open(dbutil.Config{
+		SQLitePath:          filepath.Join(t.TempDir(), "auth.db"),
+		AllowSQLiteFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer conn.Close()
+
+	client, err := NewClient(conn, dialect, "test-secret")
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	requester, err := client.CreateUser(&CreateUserRequest{Username: "judy", Password: "Passw0rd!", Role: "operator"})
+	if err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	second, err := client.CreateUser(&CreateUserRequest{Username: "ken", Password: "Passw0rd!", Role: "admin"})
+	if err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	var adminID int64
+	if err := conn.QueryRow("SELECT id FROM users WHERE username = 'admin'").Scan(&adminID); err != nil {
+		t.Fatalf("query admin failed: %v", err)
+	}
+
+	var ruleID int64
+	if err := conn.QueryRow("SELECT id FROM approval_rules WHERE action = 'delete' AND resource = 'namespaces'").Scan(&ruleID); err != nil {
+		t.Fatalf("query rule failed: %v", err)
+	}
+	if err := client.UpdateApprovalRule(ruleID, "admin", true, 2); err != nil {
+		t.Fatalf("UpdateApprovalRule failed: %v", err)
+	}
+	if required, err := client.RequiredApprovals("delete", "namespaces", ""); err != nil || required != 2 {
+		t.Fatalf("expected 2 required approvals, got %d %v", required, err)
+	}
+	if required, err := client.RequiredApprovals("delete", "pods", "default"); err != nil || required != 1 {
+		t.Fatalf("expected unmatched action to require 1 approval, got %d %v", required, err)
+	}
+
+	var events []string
+	client.SetApprovalHandler(func(eventType string, approval *ApprovalRequest) {
+		events = append(events, eventType)
+	})
+
+	approval, err := client.CreateApproval(requester.ID, &CreateApprovalRequest{
+		Action:       "delete",
+		Resource:     "namespaces",
+		ResourceName: "staging",
+	})
+	if err != nil {
+		t.Fatalf("CreateApproval failed: %v", err)
+	}
+	if approval.RequiredApprovals != 2 {
+		t.Fatalf("expected request to snapshot 2 required approvals, got %d", approval.RequiredApprovals)
+	}
+
+	// 规则修改不影响已提交的请求
+	if err := client.UpdateApprovalRule(ruleID, "admin", true, 1); err != nil {
+		t.Fatalf("UpdateApprovalRule failed: %v", err)
+	}
+
+	if _, err := client.ApproveRequest(approval.ID, requester.ID, ""); err == nil {
+		t.Fatal("expected requester to be unable to approve own dual-control request")
+	}
+	approved, err := client.ApproveRequest(approval.ID, adminID, "first")
+	if err != nil || approved {
+		t.Fatalf("expected first vote to keep request pending, got %v %v", approved, err)
+	}
+	if _, err := client.ApproveRequest(approval.ID, adminID, "again"); err == nil {
+		t.Fatal("expected same admin to be unable to vote twice")
+	}
+
+	pending, err := client.ListApprovals(ListApprovalParams{Status: "pending"})
+	if err != nil || len(pending.Items) != 1 || len(pending.Items[0].Votes) != 1 || pending.Items[0].Votes[0].ApproverName != "admin" {
+		t.Fatalf("unexpected pending approvals: %+v (%v)", pending, err)
+	}
+
+	approved, err = client.ApproveRequest(approval.ID, second.ID, "second")
+	if err != nil || !approved {
+		t.Fatalf("expected second vote to approve request, got %v %v", approved, err)
+	}
+	final, err := client.GetApprovalByID(approval.ID)
+	if err != nil {
+		t.Fatalf("GetApprovalByID failed: %v", err)
+	}
+	if final.Status != "approved" || len(final.Votes) != 2 || final.ApproverName != "ken" {
+		t.Fatalf("unexpected final approval: %+v", final)
+	}
+	if _, err := client.ApproveRequest(approval.ID, requester.ID, ""); err == nil {
+		t.Fatal("expected approved request to reject further votes")
+	}
+
+	want := []string{EventApprovalCreated, EventApprovalVoted, EventApprovalApproved}
+	if strings.Join(events, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected approval events: %v", events)
+	}
+}
+
+func TestSQLiteAPITokens(t *testing.T) {
+	conn, dialect, err := dbutil.Open(dbutil.Config{
+		SQLitePath:          filepath.Join(t.TempDir(), "auth.db"),
+		AllowSQLiteFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer conn.Close()
+
+	client, err := NewClient(conn, dialect, "test-secret")
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	user, err := client.CreateUser(&CreateUserRequest{
+		Username:             "ci",
+		Password:             "Passw0rd!",
+		Role:                 "operator",
+		Namespaces:           []string{"dev", "prod"},
+		NamespacePermissions: map[string]string{"prod": NamespacePermRead},
+	})
+	if err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+
+	if _, _, err := client.CreateAPIToken(user, &CreateAPITokenRequest{Name: "escalate", Role: "admin"}); err == nil {
+		t.Fatal("expected token role above user role to be rejected")
+	}
+	if _, _, err := client.CreateAPIToken(user, &CreateAPITokenRequest{Name: "foreign", Namespaces: []string{"kube-system"}}); err == nil {
+		t.Fatal("expected namespace outside user grants to be rejected")
+	}
+
+	token, plaintext, err := client.CreateAPIToken(user, &CreateAPITokenRequest{
+		Name:       "deploy-bot",
+		Role:       "viewer",
+		Namespaces: []string{"prod"},
+	})
+	if err != nil {
+		t.Fatalf("CreateAPIToken failed: %v", err)
+	}
+	if !strings.HasPrefix(plaintext, APITokenPrefix) || !strings.HasPrefix(plaintext, token.Prefix) {
+		t.Fatalf("unexpected token format: %q (prefix %q)", plaintext, token.Prefix)
+	}
+
+	var stored string
+	if err := conn.QueryRow("SELECT token_hash FROM api_tokens WHERE id = $1", token.ID).Scan(&stored); err != nil {
+		t.Fatal(err)
+	}
+	if stored == plaintext || strings.Contains(stored, plaintext) {
+		t.Fatal("token must be stored hashed")
+	}
+
+	scoped, validated, err := client.ValidateAPIToken(plaintext)
+	if err != nil {
+		t.Fatalf("ValidateAPIToken failed: %v", err)
+	}
+	if validated.ID != token.ID || scoped.Role != "viewer" {
+		t.Fatalf("unexpected validation result: user=%+v token=%+v", scoped, validated)
+	}
+	grants, err := client.NamespaceGrants(scoped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(grants) != 1 || grants[0].Namespace != "prod" || grants[0].Permissions != NamespacePermRead {
+		t.Fatalf("unexpected token namespace grants: %+v", grants)
+	}
+
+	if _, _, err := client.ValidateAPIToken(plaintext + "x"); err != ErrInvalidToken {
+		t.Fatalf("expected ErrInvalidToken for unknown token, got %v", err)
+	}
+
+	if _, err := conn.Exec("UPDATE api_tokens SET expires_at = $1 WHERE id = $2", time.Now().Add(-time.Hour), token.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := client.ValidateAPIToken(plaintext); err != ErrTokenExpired {
+		t.Fatalf("expected ErrTokenExpired, got %v", err)
+	}
+
+	tokens, err := client.ListAPITokens(user.ID)
+	if err != nil || len(tokens) != 1 {
+		t.Fatalf("ListAPITokens = %v, %v", tokens, err)
+	}
+	if err := client.RevokeAPIToken(user.ID+1, token.ID); err != ErrAPITokenNotFound {
+		t.Fatalf("expected other users to be unable to revoke, got %v", err)
+	}
+	if err := client.RevokeAPIToken(user.ID, token.ID); err != nil {
+		t.Fatalf("RevokeAPIToken failed: %v", err)
+	}
+	if _, _, err := client.ValidateAPIToken(plaintext); err != ErrInvalidToken {
+		t.Fatalf("expected revoked token to be invalid, got %v", err)
+	}
+}
+
+func TestSQLitePasswordRotation(t *testing.T) {
+	conn, dialect, err := dbutil.Open(dbutil.Config{
+		SQLitePath:          filepath.Join(t.TempDir(), "auth.db"),
+		AllowSQLiteFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer conn.Close()
+
+	client, err := NewClient(conn, dialect, "test-secret")
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	admin, _, err := client.Login("admin", "admin123", "127.0.0.1", "test")
+	if err != nil {
+		t.Fatalf("seeded admin login failed: %v", err)
+	}
+	if !admin.MustChangePassword {
+		t.Fatal("expected seeded admin to be required to change password")
+	}
+
+	user, err := client.CreateUser(&CreateUserRequest{Username: "erin", Password: "Passw0rd!", Role: "viewer"})
+	if err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	if user.MustChangePassword || user.PasswordChangedAt == nil {
+		t.Fatalf("unexpected password state for new user: %+v", user)
+	}
+
+	if err := client.ResetPassword(user.ID, "short"); err == nil {
+		t.Fatal("expected reset to enforce password policy")
+	}
+	if err := client.ResetPassword(user.ID, "Temp0rary!"); err != nil {
+		t.Fatalf("ResetPassword failed: %v", err)
+	}
+	if reset, _ := client.GetUserByID(user.ID); !reset.MustChangePassword {
+		t.Fatal("expected admin reset to require password change")
+	}
+
+	if err := client.UpdatePassword(user.ID, "Temp0rary!", "Temp0rary!"); err != ErrPasswordReused {
+		t.Fatalf("expected ErrPasswordReused, got %v", err)
+	}
+	if err := client.UpdatePassword(user.ID, "Temp0rary!", "N3w-Passw0rd"); err != nil {
+		t.Fatalf("UpdatePassword failed: %v", err)
+	}
+	if changed, _ := client.GetUserByID(user.ID); changed.MustChangePassword {
+		t.Fatal("expected password change to clear the flag")
+	}
+
+	client.SetPasswordPolicy(PasswordPolicy{MinLength: 8, MaxAgeDays: 30})
+	if _, err := conn.Exec("UPDATE users SET password_changed_at = $1 WHERE id = $2", time.Now().AddDate(0, 0, -31), user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if expired, _ := client.GetUserByID(user.ID); !expired.MustChangePassword {
+		t.Fatal("expected password older than max age to require change")
+	}
+}
+
+func TestSQLiteRefreshSession(t *testing.T) {
+	conn, dialect, err := dbutil.Open(dbutil.Config{
+		SQLitePath:          filepath.Join(t.TempDir(), "auth.db"),
+		AllowSQLiteFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer conn.Close()
+
+	client, err := NewClient(conn, dialect, "test-secret")
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	client.SetTokenLifetimes(time.Minute, time.Hour)
+
+	if _, err := client.CreateUser(&CreateUserRequest{Username: "frank", Password: "Passw0rd!", Role: "viewer"}); err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	_, tokens, err := client.Login("frank", "Passw0rd!", "127.0.0.1", "test")
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if time.Until(tokens.ExpiresAt) > time.Minute || time.Until(tokens.RefreshExpiresAt) < 59*time.Minute {
+		t.Fatalf("unexpected token lifetimes: %+v", tokens)
+	}
+
+	user, refreshed, err := client.RefreshSession(tokens.RefreshToken, "127.0.0.2", "test")
+	if err != nil {
+		t.Fatalf("RefreshSession failed: %v", err)
+	}
+	if user.Username != "frank" || refreshed.RefreshToken == tokens.RefreshToken {
+		t.Fatalf("expected rotated refresh token, got %+v", refreshed)
+	}
+	if _, err := client.ValidateToken(refreshed.AccessToken); err != nil {
+		t.Fatalf("refreshed access token invalid: %v", err)
+	}
+
+	// 宽限期内重放旧令牌只被拒绝，会话保持有效
+	if _, _, err := client.RefreshSession(tokens.RefreshToken, "127.0.0.1", "test"); err != ErrInvalidToken {
+		t.Fatalf("expected ErrInvalidToken within grace period, got %v", err)
+	}
+	// 超出宽限期后重放视为泄露，撤销整个会话
+	if _, err := conn.Exec("UPDATE sessions SET refreshed_at = $1", time.Now().Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := client.RefreshSession(tokens.RefreshToken, "127.0.0.1", "test"); err != ErrRefreshTokenReused {
+		t.Fatalf("expected ErrRefreshTokenReused, got %v", err)
+	}
+	if _, _, err := client.RefreshSession(refreshed.RefreshToken, "127.0.0.2", "test"); err != ErrInvalidToken {
+		t.Fatalf("expected session revoked after reuse, got %v", err)
+	}
+
+	// 登出后刷新令牌失效
+	_, tokens, err = client.Login("frank", "Passw0rd!", "127.0.0.1", "test")
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if err := client.Logout(tokens.AccessToken); err != nil {
+		t.Fatalf("Logout failed: %v", err)
+	}
+	if _, _, err := client.RefreshSession(tokens.RefreshToken, "127.0.0.1", "test"); err != ErrInvalidToken {
+		t.Fatalf("expected refresh after logout to fail, got %v", err)
+	}
+}
+
+func TestSQLiteAdminSessionManagement(t *testing.T) {
+	conn, dialect, err := dbutil.Open(dbutil.Config{
+		SQLitePath:          filepath.Join(t.TempDir(), "auth.db"),
+		AllowSQLiteFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer conn.Close()
+
+	client, err := NewClient(conn, dialect, "test-secret")
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	var events []UserEvent
+	client.SetEventHandler(func(e UserEvent) { events = append(events, e) })
+
+	for _, name := range []string{"gina", "hank"} {
+		if _, err := client.CreateUser(&CreateUserRequest{Username: name, Password: "Passw0rd!", Role: "viewer"}); err != nil {
+			t.Fatalf("CreateUser failed: %v", err)
+		}
+	}
+	gina, _, _ := client.Login("gina", "Passw0rd!", "10.0.0.1", "test")
+	client.Login("gina", "Passw0rd!", "10.0.0.2", "test")
+	hank, hankTokens, _ := client.Login("hank", "Passw0rd!", "10.0.0.3", "test")
+
+	all, err := client.ListActiveSessions(0)
+	if err != nil || len(all) != 3 {
+		t.Fatalf("expected 3 active sessions, got %d (%v)", len(all), err)
+	}
+	ginaSessions, _ := client.ListActiveSessions(gina.ID)
+	if len(ginaSessions) != 2 || ginaSessions[0].Username != "gina" {
+		t.Fatalf("unexpected sessions for gina: %+v", ginaSessions)
+	}
+
+	// 普通用户不能撤销他人的会话
+	if err := client.RevokeUserSession(gina.ID, firstSessionID(t, client, hank.ID)); err != ErrSessionNotFound {
+		t.Fatalf("expected ErrSessionNotFound, got %v", err)
+	}
+	if err := client.RevokeSession(firstSessionID(t, client, hank.ID)); err != nil {
+		t.Fatalf("RevokeSession failed: %v", err)
+	}
+	if _, err := client.ValidateToken(hankTokens.AccessToken); err != ErrInvalidToken {
+		t.Fatalf("expected revoked session to be rejected, got %v", err)
+	}
+
+	revoked, err := client.ForceLogout(gina.ID, "10.0.0.9")
+	if err != nil || revoked != 2 {
+		t.Fatalf("expected 2 sessions revoked, got %d (%v)", revoked, err)
+	}
+	if last := events[len(events)-1]; last.Type != EventUserSessionsRevoked || last.Username != "gina" {
+		t.Fatalf("unexpected event: %+v", last)
+	}
+	if _, err := client.ForceLogout(9999, ""); err != ErrUserNotFound {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func firstSessionID(t *testing.T, client *Client, userID int64) string {
+	t.Helper()
+	sessions, err := client.ListActiveSessions(userID)
+	if err != nil || len(sessions) == 0 {
+		t.Fatalf("expected active session for user %d: %v", userID, err)
+	}
+	return sessions[0].ID
+}
@@ -21,6 +21,8 @@ type CreateUserRequest struct {
 	SAToken        string   `json:"saToken"`
 	AllNamespaces  bool     `json:"allNamespaces"`
 	Namespaces     []string `json:"namespaces"`
+	// NamespacePermissions 命名空间授权级别（read/write/admin），未指定的命名空间默认为 write
+	NamespacePermissions map[string]string `json:"namespacePermissions,omitempty"`
 }
 
 // UpdateUserRequest 更新用户请求
@@ -34,6 +36,8 @@ type UpdateUserRequest struct {
 	AllNamespaces  bool     `json:"allNamespaces"`
 	Namespaces     []string `json:"namespaces"`
 	Enabled        bool     `json:"enabled"`
+	// NamespacePermissions 命名空间授权级别，未指定时保留已有级别，新增的命名空间默认为 write
+	NamespacePermissions map[string]string `json:"namespacePermissions,omitempty"`
 }
 
 // ListUsersParams 用户列表查询参数
@@ -65,6 +69,15 @@ func (c *Client) CreateUser(req *CreateUserRequest) (*User, error) {
 		req.Role = "viewer"
 	}
 
+	if err := c.passwordPolicy.Validate(req.Password); err != nil {
+		return nil, err
+	}
+
+	permissions, err := resolveNamespacePermissions(req.Namespaces, req.NamespacePermissions, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	// 加密密码
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -83,10 +96,10 @@ func (c *Client) CreateUser(req *CreateUserRequest) (*User, error) {
 	if c.dialect == dbutil.DialectSQLite {
 		result, execErr := tx.Exec(`
 			INSERT INTO users (username, password, display_name, email, role,
-			                   service_account, sa_namespace, sa_token, all_namespaces, enabled)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, true)
+			                   service_account, sa_namespace, sa_token, all_namespaces, enabled, password_changed_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, true, $10)
 		`, req.Username, string(hashedPassword), req.DisplayName, req.Email, req.Role,
-			req.ServiceAccount, req.SANamespace, req.SAToken, req.AllNamespaces)
+			req.ServiceAccount, req.SANamespace, req.SAToken, req.AllNamespaces, time.Now())
 		if execErr != nil {
 			return nil, fmt.Errorf("创建用户失败: %w", execErr)
 		}
@@ -98,11 +111,11 @@ func (c *Client) CreateUser(req *CreateUserRequest) (*User, error) {
 	} else {
 		err = tx.QueryRow(`
 			INSERT INTO users (username, password, display_name, email, role,
-			                   service_account, sa_namespace, sa_token, all_namespaces, enabled)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, true)
+			                   service_account, sa_namespace, sa_token, all_namespaces, enabled, password_changed_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, true, $10)
 			RETURNING id
 		`, req.Username, string(hashedPassword), req.DisplayName, req.Email, req.Role,
-			req.ServiceAccount, req.SANamespace, req.SAToken, req.AllNamespaces).Scan(&userID)
+			req.ServiceAccount, req.SANamespace, req.SAToken, req.AllNamespaces, time.Now()).Scan(&userID)
 		if err != nil {
 			return nil, fmt.Errorf("创建用户失败: %w", err)
 		}
@@ -113,8 +126,8 @@ func (c *Client) CreateUser(req *CreateUserRequest) (*User, error) {
 		for _, ns := range req.Namespaces {
 			_, err = tx.Exec(`
 				INSERT INTO user_namespaces (user_id, namespace, permissions)
-				VALUES ($1, $2, 'write')
-			`, userID, ns)
+				VALUES ($1, $2, $3)
+			`, userID, ns, permissions[ns])
 			if err != nil {
 				return nil, fmt.Errorf("添加命名空间权限失败: %w", err)
 			}
@@ -125,11 +138,35 @@ func (c *Client) CreateUser(req *CreateUserRequest) (*User, error) {
 		return nil, err
 	}
 
-	return c.GetUserByID(userID)
+	user, err := c.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	c.emit(UserEvent{Type: EventUserCreated, UserID: user.ID, Username: user.Username, Role: user.Role})
+	return user, nil
 }
 
 // UpdateUser 更新用户
 func (c *Client) UpdateUser(userID int64, req *UpdateUserRequest) (*User, error) {
+	previous, err := c.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 在事务外读取现有授权，SQLite 单连接下事务内再查询会阻塞
+	existing, err := c.GetUserNamespaces(userID)
+	if err != nil {
+		return nil, err
+	}
+	existingPermissions := make(map[string]string, len(existing))
+	for _, ns := range existing {
+		existingPermissions[ns.Namespace] = ns.Permissions
+	}
+	permissions, err := resolveNamespacePermissions(req.Namespaces, req.NamespacePermissions, existingPermissions)
+	if err != nil {
+		return nil, err
+	}
+
 	tx, err := c.db.Begin()
 	if err != nil {
 		return nil, err
@@ -162,8 +199,8 @@ func (c *Client) UpdateUser(userID int64, req *UpdateUserRequest) (*User, error)
 		for _, ns := range req.Namespaces {
 			_, err = tx.Exec(`
 				INSERT INTO user_namespaces (user_id, namespace, permissions)
-				VALUES ($1, $2, 'write')
-			`, userID, ns)
+				VALUES ($1, $2, $3)
+			`, userID, ns, permissions[ns])
 			if err != nil {
 				return nil, err
 			}
@@ -174,7 +211,35 @@ func (c *Client) UpdateUser(userID int64, req *UpdateUserRequest) (*User, error)
 		return nil, err
 	}
 
-	return c.GetUserByID(userID)
+	user, err := c.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	c.emitUpdateEvents(previous, user)
+	return user, nil
+}
+
+// emitUpdateEvents 对比更新前后的用户，发出角色变更和启用/禁用事件
+func (c *Client) emitUpdateEvents(previous, current *User) {
+	if previous == nil || current == nil {
+		return
+	}
+	if previous.Role != current.Role {
+		c.emit(UserEvent{
+			Type:         EventUserRoleChanged,
+			UserID:       current.ID,
+			Username:     current.Username,
+			Role:         current.Role,
+			PreviousRole: previous.Role,
+		})
+	}
+	if previous.Enabled != current.Enabled {
+		eventType := EventUserEnabled
+		if !current.Enabled {
+			eventType = EventUserDisabled
+		}
+		c.emit(UserEvent{Type: eventType, UserID: current.ID, Username: current.Username, Role: current.Role})
+	}
 }
 
 // UpdatePassword 更新密码
@@ -189,6 +254,12 @@ func (c *Client) UpdatePassword(userID int64, oldPassword, newPassword string) e
 	if err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(oldPassword)); err != nil {
 		return ErrInvalidPassword
 	}
+	if oldPassword == newPassword {
+		return ErrPasswordReused
+	}
+	if err := c.passwordPolicy.Validate(newPassword); err != nil {
+		return err
+	}
 
 	// 加密新密码
 	newHashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
@@ -196,20 +267,29 @@ func (c *Client) UpdatePassword(userID int64, oldPassword, newPassword string) e
 		return err
 	}
 
-	_, err = c.db.Exec("UPDATE users SET password = $1, updated_at = $2 WHERE id = $3",
-		string(newHashedPassword), time.Now(), userID)
+	now := time.Now()
+	_, err = c.db.Exec(`
+		UPDATE users SET password = $1, must_change_password = $2, password_changed_at = $3, updated_at = $4
+		WHERE id = $5
+	`, string(newHashedPassword), false, now, now, userID)
 	return err
 }
 
-// ResetPassword 重置密码（管理员操作）
+// ResetPassword 重置密码（管理员操作），用户下次登录后需先修改密码
 func (c *Client) ResetPassword(userID int64, newPassword string) error {
+	if err := c.passwordPolicy.Validate(newPassword); err != nil {
+		return err
+	}
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
 	if err != nil {
 		return err
 	}
 
-	_, err = c.db.Exec("UPDATE users SET password = $1, updated_at = $2 WHERE id = $3",
-		string(hashedPassword), time.Now(), userID)
+	now := time.Now()
+	_, err = c.db.Exec(`
+		UPDATE users SET password = $1, must_change_password = $2, password_changed_at = $3, updated_at = $4
+		WHERE id = $5
+	`, string(hashedPassword), true, now, now, userID)
 	return err
 }
 
@@ -225,8 +305,21 @@ func (c *Client) DeleteUser(userID int64) error {
 		return fmt.Errorf("不能删除系统管理员账户")
 	}
 
-	_, err = c.db.Exec("DELETE FROM users WHERE id = $1", userID)
-	return err
+	if _, err = c.db.Exec("DELETE FROM users WHERE id = $1", userID); err != nil {
+		return err
+	}
+	c.emit(UserEvent{Type: EventUserDeleted, UserID: userID, Username: username})
+	return nil
+}
+
+// GetServiceAccountToken 获取用户绑定的 ServiceAccount Token，未配置时返回空字符串
+func (c *Client) GetServiceAccountToken(userID int64) (string, error) {
+	var token string
+	err := c.db.QueryRow("SELECT COALESCE(sa_token, '') FROM users WHERE id = $1", userID).Scan(&token)
+	if err == sql.ErrNoRows {
+		return "", ErrUserNotFound
+	}
+	return token, err
 }
 
 // ListUsers 获取用户列表
@@ -278,7 +371,8 @@ func (c *Client) ListUsers(params ListUsersParams) (*ListUsersResponse, error) {
 	query := fmt.Sprintf(`
 		SELECT id, username, COALESCE(display_name, ''), COALESCE(email, ''),
 		       role, COALESCE(service_account, ''), COALESCE(sa_namespace, ''),
-		       all_namespaces, enabled, last_login_at, last_login_ip, created_at, updated_at
+		       all_namespaces, enabled, must_change_password, password_changed_at,
+		       last_login_at, last_login_ip, created_at, updated_at
 		FROM users %s
 		ORDER BY created_at DESC
 		LIMIT $%d OFFSET $%d
@@ -297,11 +391,13 @@ func (c *Client) ListUsers(params ListUsersParams) (*ListUsersResponse, error) {
 		var user User
 		var lastLoginAt sql.NullTime
 		var lastLoginIP sql.NullString
+		var passwordChangedAt sql.NullTime
 
 		err := rows.Scan(
 			&user.ID, &user.Username, &user.DisplayName, &user.Email,
 			&user.Role, &user.ServiceAccount, &user.SANamespace,
-			&user.AllNamespaces, &user.Enabled, &lastLoginAt, &lastLoginIP,
+			&user.AllNamespaces, &user.Enabled, &user.MustChangePassword, &passwordChangedAt,
+			&lastLoginAt, &lastLoginIP,
 			&user.CreatedAt, &user.UpdatedAt,
 		)
 		if err != nil {
@@ -314,6 +410,7 @@ func (c *Client) ListUsers(params ListUsersParams) (*ListUsersResponse, error) {
 		if lastLoginIP.Valid {
 			user.LastLoginIP = lastLoginIP.String
 		}
+		c.applyPasswordState(&user, passwordChangedAt)
 
 		users = append(users, user)
 	}
@@ -333,32 +430,60 @@ func (c *Client) ListUsers(params ListUsersParams) (*ListUsersResponse, error) {
 
 // GetUserSessions 获取用户会话列表
 func (c *Client) GetUserSessions(userID int64) ([]Session, error) {
-	rows, err := c.db.Query(`
-		SELECT id, user_id, ip, user_agent, expires_at, created_at
-		FROM sessions WHERE user_id = $1 AND expires_at > $2
-		ORDER BY created_at DESC
-	`, userID, time.Now())
+	return c.ListActiveSessions(userID)
+}
+
+// ListActiveSessions 列出未过期的会话（附带用户名），userID 为 0 时返回所有用户的会话
+func (c *Client) ListActiveSessions(userID int64) ([]Session, error) {
+	query := `
+		SELECT s.id, s.user_id, u.username, COALESCE(s.ip, ''), COALESCE(s.user_agent, ''), s.expires_at, s.created_at
+		FROM sessions s JOIN users u ON u.id = s.user_id
+		WHERE s.expires_at > $1`
+	args := []interface{}{time.Now()}
+	if userID > 0 {
+		query += " AND s.user_id = $2"
+		args = append(args, userID)
+	}
+	rows, err := c.db.Query(query+" ORDER BY s.created_at DESC", args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var sessions []Session
+	sessions := []Session{}
 	for rows.Next() {
 		var s Session
-		if err := rows.Scan(&s.ID, &s.UserID, &s.IP, &s.UserAgent, &s.ExpiresAt, &s.CreatedAt); err != nil {
+		if err := rows.Scan(&s.ID, &s.UserID, &s.Username, &s.IP, &s.UserAgent, &s.ExpiresAt, &s.CreatedAt); err != nil {
 			return nil, err
 		}
 		sessions = append(sessions, s)
 	}
 
-	return sessions, nil
+	return sessions, rows.Err()
 }
 
-// RevokeSession 撤销会话
+// RevokeSession 撤销任意会话（管理员），会话不存在时返回 ErrSessionNotFound
 func (c *Client) RevokeSession(sessionID string) error {
-	_, err := c.db.Exec("DELETE FROM sessions WHERE id = $1", sessionID)
-	return err
+	result, err := c.db.Exec("DELETE FROM sessions WHERE id = $1", sessionID)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// RevokeUserSession 撤销用户自己的会话，不能撤销其他用户的会话
+func (c *Client) RevokeUserSession(userID int64, sessionID string) error {
+	result, err := c.db.Exec("DELETE FROM sessions WHERE id = $1 AND user_id = $2", sessionID, userID)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
 }
 
 // RevokeAllSessions 撤销用户所有会话
@@ -367,6 +492,27 @@ func (c *Client) RevokeAllSessions(userID int64) error {
 	return err
 }
 
+// ForceLogout 强制用户下线：撤销其全部会话（含刷新令牌），返回撤销的会话数。
+// 用于账户疑似泄露时的应急处置，API 令牌需另行撤销
+func (c *Client) ForceLogout(userID int64, ip string) (int64, error) {
+	var username, role string
+	err := c.db.QueryRow("SELECT username, role FROM users WHERE id = $1", userID).Scan(&username, &role)
+	if err == sql.ErrNoRows {
+		return 0, ErrUserNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := c.db.Exec("DELETE FROM sessions WHERE user_id = $1", userID)
+	if err != nil {
+		return 0, err
+	}
+	revoked, _ := result.RowsAffected()
+	c.emit(UserEvent{Type: EventUserSessionsRevoked, UserID: userID, Username: username, Role: role, IP: ip})
+	return revoked, nil
+}
+
 // CleanExpiredSessions 清理过期会话
 func (c *Client) CleanExpiredSessions() error {
 	_, err := c.db.Exec("DELETE FROM sessions WHERE expires_at < $1", time.Now())
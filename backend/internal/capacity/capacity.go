@@ -0,0 +1,194 @@
+// Package capacity 汇总节点池的可分配、已申请与实际用量，并根据历史用量线性外推容量耗尽时间
+package capacity
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/k8s-dashboard/backend/internal/k8s"
+	"github.com/k8s-dashboard/backend/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// 预测的资源类型，与 metrics.GetCapacityTrends 的键一致
+const (
+	ResourceCPU    = "cpu"
+	ResourceMemory = "memory"
+	ResourcePods   = "pods"
+)
+
+// minForecastSpan 拟合所需的最短历史跨度，过短的数据外推没有意义
+const minForecastSpan = 24 * time.Hour
+
+// Usage 一种资源的可分配量、已申请量（requests 之和）与实际用量。
+// CPU 单位为核，内存为字节，Pod 为个数（Requested 与 Used 均为已调度的 Pod 数）
+type Usage struct {
+	Allocatable float64 `json:"allocatable"`
+	Requested   float64 `json:"requested"`
+	Used        float64 `json:"used"`
+}
+
+// Pool 一个节点池的容量，Extended 为扩展资源（如 nvidia.com/gpu）与大页内存，没有实际用量数据
+type Pool struct {
+	Name     string           `json:"name"`
+	Nodes    int              `json:"nodes"`
+	CPU      Usage            `json:"cpu"`
+	Memory   Usage            `json:"memory"`
+	Pods     Usage            `json:"pods"`
+	Extended map[string]Usage `json:"extended,omitempty"`
+}
+
+// Forecast 单个资源的耗尽预测。GrowthPerDay 为线性拟合的日增长量，
+// 不增长或历史数据不足时 DaysUntilExhaustion 与 ExhaustionDate 为空
+type Forecast struct {
+	Resource            string     `json:"resource"`
+	Current             float64    `json:"current"`
+	Capacity            float64    `json:"capacity"`
+	GrowthPerDay        float64    `json:"growthPerDay"`
+	DaysUntilExhaustion *float64   `json:"daysUntilExhaustion"`
+	ExhaustionDate      *time.Time `json:"exhaustionDate"`
+	Samples             int        `json:"samples"`
+}
+
+// BuildPools 按节点池汇总容量，poolLabel 含义见 k8s.NodePool。pods 中已结束的 Pod 不计入；
+// podUsage 为 Pod 的实际用量（可为空）
+func BuildPools(nodes []corev1.Node, pods []corev1.Pod, podUsage []metrics.PodMetrics, poolLabel string) []Pool {
+	poolOfNode := make(map[string]string, len(nodes))
+	pools := make(map[string]*Pool)
+	for i := range nodes {
+		node := &nodes[i]
+		name := k8s.NodePool(node, poolLabel)
+		poolOfNode[node.Name] = name
+		pool, ok := pools[name]
+		if !ok {
+			pool = &Pool{Name: name}
+			pools[name] = pool
+		}
+		pool.Nodes++
+		pool.CPU.Allocatable += node.Status.Allocatable.Cpu().AsApproximateFloat64()
+		pool.Memory.Allocatable += node.Status.Allocatable.Memory().AsApproximateFloat64()
+		pool.Pods.Allocatable += node.Status.Allocatable.Pods().AsApproximateFloat64()
+		for name, value := range k8s.ExtendedResources(node.Status.Allocatable) {
+			pool.addExtended(name, Usage{Allocatable: value})
+		}
+	}
+
+	usageByPod := make(map[string]metrics.PodMetrics, len(podUsage))
+	for _, usage := range podUsage {
+		usageByPod[usage.Namespace+"/"+usage.Name] = usage
+	}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		pool, ok := pools[poolOfNode[pod.Spec.NodeName]]
+		if !ok {
+			continue
+		}
+		cpu, memory := podRequests(pod)
+		pool.CPU.Requested += cpu
+		pool.Memory.Requested += memory
+		pool.Pods.Requested++
+		pool.Pods.Used++
+		for name, value := range k8s.PodExtendedRequests(pod) {
+			pool.addExtended(name, Usage{Requested: value})
+		}
+		if usage, ok := usageByPod[pod.Namespace+"/"+pod.Name]; ok {
+			pool.CPU.Used += usage.CPUUsage
+			pool.Memory.Used += usage.MemoryUsage
+		}
+	}
+
+	result := make([]Pool, 0, len(pools))
+	for _, pool := range pools {
+		result = append(result, *pool)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// podRequests 计算 Pod 的有效 requests：普通容器之和与任一 init 容器取较大者（与调度器一致），再加上 overhead
+func podRequests(pod *corev1.Pod) (float64, float64) {
+	var cpu, memory float64
+	for _, container := range pod.Spec.Containers {
+		cpu += container.Resources.Requests.Cpu().AsApproximateFloat64()
+		memory += container.Resources.Requests.Memory().AsApproximateFloat64()
+	}
+	for _, container := range pod.Spec.InitContainers {
+		cpu = math.Max(cpu, container.Resources.Requests.Cpu().AsApproximateFloat64())
+		memory = math.Max(memory, container.Resources.Requests.Memory().AsApproximateFloat64())
+	}
+	cpu += pod.Spec.Overhead.Cpu().AsApproximateFloat64()
+	memory += pod.Spec.Overhead.Memory().AsApproximateFloat64()
+	return cpu, memory
+}
+
+// Total 汇总所有节点池
+func Total(pools []Pool) Pool {
+	total := Pool{Name: "total"}
+	for _, pool := range pools {
+		total.Nodes += pool.Nodes
+		total.CPU = addUsage(total.CPU, pool.CPU)
+		total.Memory = addUsage(total.Memory, pool.Memory)
+		total.Pods = addUsage(total.Pods, pool.Pods)
+		for name, usage := range pool.Extended {
+			total.addExtended(name, usage)
+		}
+	}
+	return total
+}
+
+func (p *Pool) addExtended(name string, usage Usage) {
+	if p.Extended == nil {
+		p.Extended = make(map[string]Usage)
+	}
+	p.Extended[name] = addUsage(p.Extended[name], usage)
+}
+
+func addUsage(a, b Usage) Usage {
+	return Usage{Allocatable: a.Allocatable + b.Allocatable, Requested: a.Requested + b.Requested, Used: a.Used + b.Used}
+}
+
+// LinearForecast 对历史序列做最小二乘线性拟合，估算用量增长到 capacity 的剩余天数
+func LinearForecast(resource string, series []metrics.TimeSeriesData, capacity float64, now time.Time) Forecast {
+	forecast := Forecast{Resource: resource, Capacity: capacity, Samples: len(series)}
+	if len(series) == 0 {
+		return forecast
+	}
+	forecast.Current = series[len(series)-1].Value
+	if len(series) < 2 || time.Duration(series[len(series)-1].Timestamp-series[0].Timestamp)*time.Second < minForecastSpan {
+		return forecast
+	}
+
+	// 以天为横轴，减去起点避免时间戳过大损失精度
+	origin := series[0].Timestamp
+	n := float64(len(series))
+	var sumX, sumY, sumXY, sumXX float64
+	for _, point := range series {
+		x := float64(point.Timestamp-origin) / 86400
+		sumX += x
+		sumY += point.Value
+		sumXY += x * point.Value
+		sumXX += x * x
+	}
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return forecast
+	}
+	slope := (n*sumXY - sumX*sumY) / denominator
+	intercept := (sumY - slope*sumX) / n
+	forecast.GrowthPerDay = slope
+	if slope <= 0 || capacity <= 0 {
+		return forecast
+	}
+
+	// 从拟合线上的当前值外推，减少最后一个采样点抖动的影响
+	nowX := float64(now.Unix()-origin) / 86400
+	days := math.Round(math.Max((capacity-(intercept+slope*nowX))/slope, 0)*10) / 10
+	date := now.Add(time.Duration(days * float64(24*time.Hour)))
+	forecast.DaysUntilExhaustion = &days
+	forecast.ExhaustionDate = &date
+	return forecast
+}
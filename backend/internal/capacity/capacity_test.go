@@ -0,0 +1,141 @@
+package capacity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/k8s-dashboard/backend/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testNode(name, pool string) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"karpenter.sh/nodepool": pool}},
+		Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("4"),
+			corev1.ResourceMemory: resource.MustParse("8Gi"),
+			corev1.ResourcePods:   resource.MustParse("110"),
+		}},
+	}
+}
+
+func testPod(name, node string, phase corev1.PodPhase, cpu string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: corev1.PodSpec{
+			NodeName: node,
+			Containers: []corev1.Container{{Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+			}}},
+			InitContainers: []corev1.Container{{Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			}}},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestBuildPools(t *testing.T) {
+	nodes := []corev1.Node{testNode("a-1", "general"), testNode("a-2", "general"), testNode("g-1", "gpu")}
+	nodes[2].Status.Allocatable["nvidia.com/gpu"] = resource.MustParse("4")
+	pods := []corev1.Pod{
+		testPod("web", "a-1", corev1.PodRunning, "500m"),
+		testPod("api", "a-2", corev1.PodRunning, "2"),
+		testPod("done", "a-1", corev1.PodSucceeded, "2"),
+		testPod("pending", "", corev1.PodPending, "2"),
+		testPod("train", "g-1", corev1.PodRunning, "1"),
+	}
+	pods[4].Spec.Containers[0].Resources.Requests["nvidia.com/gpu"] = resource.MustParse("2")
+	usage := []metrics.PodMetrics{{Namespace: "default", Name: "web", CPUUsage: 0.25, MemoryUsage: 1024}}
+
+	pools := BuildPools(nodes, pods, usage, "")
+	if len(pools) != 2 || pools[0].Name != "general" || pools[1].Name != "gpu" {
+		t.Fatalf("unexpected pools %+v", pools)
+	}
+	general := pools[0]
+	if general.Nodes != 2 || general.CPU.Allocatable != 8 || general.Pods.Allocatable != 220 {
+		t.Fatalf("unexpected allocatable %+v", general)
+	}
+	// web 的 init 容器 1 核大于普通容器 500m，按 1 核计
+	if general.CPU.Requested != 3 || general.Pods.Requested != 2 {
+		t.Fatalf("unexpected requested %+v", general.CPU)
+	}
+	if general.CPU.Used != 0.25 || general.Memory.Used != 1024 {
+		t.Fatalf("unexpected used %+v", general)
+	}
+	if gpu := pools[1].Extended["nvidia.com/gpu"]; gpu.Allocatable != 4 || gpu.Requested != 2 {
+		t.Fatalf("unexpected gpu usage %+v", pools[1].Extended)
+	}
+	if general.Extended != nil {
+		t.Fatalf("general pool should have no extended resources: %+v", general.Extended)
+	}
+	if total := Total(pools); total.Nodes != 3 || total.CPU.Allocatable != 12 || total.Extended["nvidia.com/gpu"].Requested != 2 {
+		t.Fatalf("unexpected total %+v", total)
+	}
+}
+
+func TestLinearForecast(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var series []metrics.TimeSeriesData
+	for day := 0; day <= 10; day++ {
+		series = append(series, metrics.TimeSeriesData{Timestamp: start.Add(time.Duration(day) * 24 * time.Hour).Unix(), Value: 10 + float64(day)})
+	}
+	now := start.Add(10 * 24 * time.Hour)
+
+	forecast := LinearForecast(ResourceCPU, series, 30, now)
+	if forecast.GrowthPerDay != 1 || forecast.Current != 20 {
+		t.Fatalf("unexpected fit %+v", forecast)
+	}
+	if forecast.DaysUntilExhaustion == nil || *forecast.DaysUntilExhaustion != 10 {
+		t.Fatalf("expected 10 days until exhaustion, got %+v", forecast.DaysUntilExhaustion)
+	}
+	if !forecast.ExhaustionDate.Equal(now.Add(10 * 24 * time.Hour)) {
+		t.Fatalf("unexpected exhaustion date %v", forecast.ExhaustionDate)
+	}
+
+	flat := LinearForecast(ResourceMemory, []metrics.TimeSeriesData{{Timestamp: start.Unix(), Value: 5}, {Timestamp: now.Unix(), Value: 5}}, 30, now)
+	if flat.DaysUntilExhaustion != nil || flat.GrowthPerDay != 0 {
+		t.Fatalf("flat usage should not be forecast to exhaust: %+v", flat)
+	}
+
+	short := LinearForecast(ResourcePods, series[:1], 30, now)
+	if short.DaysUntilExhaustion != nil || short.Current != 10 {
+		t.Fatalf("single sample should not be forecast: %+v", short)
+	}
+}
+
+func TestSummarizeNodePools(t *testing.T) {
+	ready := corev1.NodeCondition{Type: corev1.NodeReady, Status: corev1.ConditionTrue}
+	notReady := corev1.NodeCondition{Type: corev1.NodeReady, Status: corev1.ConditionUnknown}
+	nodes := []corev1.Node{testNode("a-1", "general"), testNode("a-2", "general"), testNode("b-1", "general")}
+	nodes[0].Status.Conditions = []corev1.NodeCondition{ready}
+	nodes[1].Status.Conditions = []corev1.NodeCondition{notReady}
+	nodes[1].Spec.Unschedulable = true
+	nodes[2].Status.Conditions = []corev1.NodeCondition{ready}
+	nodes[0].Labels["tier"] = "app"
+	nodes[1].Labels["tier"] = "app"
+	nodes[0].Labels[corev1.LabelInstanceTypeStable] = "m5.large"
+	pods := []corev1.Pod{testPod("web", "a-1", corev1.PodRunning, "1")}
+	usage := map[string]corev1.ResourceList{"a-1": {corev1.ResourceCPU: resource.MustParse("2")}}
+
+	pools := SummarizeNodePools(nodes, pods, usage, "tier")
+	if len(pools) != 2 || pools[0].Name != "app" || pools[1].Name != "default" {
+		t.Fatalf("unexpected pools %+v", pools)
+	}
+	app := pools[0]
+	if app.Nodes != 2 || app.Ready != 1 || app.NotReady != 1 || app.Cordoned != 1 || app.RunningPods != 1 {
+		t.Fatalf("unexpected status counts %+v", app)
+	}
+	if len(app.InstanceTypes) != 1 || app.InstanceTypes[0] != "m5.large" {
+		t.Fatalf("unexpected instance types %v", app.InstanceTypes)
+	}
+	if app.Allocatable.CPU != 8 || app.Usage == nil || app.Usage.CPU != 2 || app.CPUPercent != 25 {
+		t.Fatalf("unexpected usage %+v %+v", app.Allocatable, app.Usage)
+	}
+
+	if withoutMetrics := SummarizeNodePools(nodes, pods, nil, ""); withoutMetrics[0].Usage != nil || withoutMetrics[0].Nodes != 3 {
+		t.Fatalf("unexpected summary without metrics %+v", withoutMetrics)
+	}
+}
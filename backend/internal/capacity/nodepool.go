@@ -0,0 +1,125 @@
+package capacity
+
+import (
+	"sort"
+
+	"github.com/k8s-dashboard/backend/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NodePoolSummary 节点池概览。CPU 单位为核，内存为字节；Usage 来自 metrics-server，
+// 未部署时为空。UsagePercent 以 allocatable 为分母
+type NodePoolSummary struct {
+	Name          string             `json:"name"`
+	Nodes         int                `json:"nodes"`
+	Ready         int                `json:"ready"`
+	NotReady      int                `json:"notReady"`
+	Cordoned      int                `json:"cordoned"`
+	RunningPods   int                `json:"runningPods"`
+	InstanceTypes []string           `json:"instanceTypes"`
+	NodeNames     []string           `json:"nodeNames"`
+	Capacity      NodePoolResources  `json:"capacity"`
+	Allocatable   NodePoolResources  `json:"allocatable"`
+	Usage         *NodePoolResources `json:"usage,omitempty"`
+	CPUPercent    float64            `json:"cpuPercent"`
+	MemoryPercent float64            `json:"memoryPercent"`
+	PodPercent    float64            `json:"podPercent"`
+}
+
+// NodePoolResources 节点池资源合计，Extended 为扩展资源（如 nvidia.com/gpu）与大页内存
+type NodePoolResources struct {
+	CPU      float64            `json:"cpu"`
+	Memory   float64            `json:"memory"`
+	Pods     float64            `json:"pods,omitempty"`
+	Extended map[string]float64 `json:"extended,omitempty"`
+}
+
+// SummarizeNodePools 按节点池汇总节点状态、容量与用量。poolLabel 含义见 k8s.NodePool；
+// nodeUsage 为节点名到 metrics-server 用量的映射，为 nil 表示没有用量数据
+func SummarizeNodePools(nodes []corev1.Node, pods []corev1.Pod, nodeUsage map[string]corev1.ResourceList, poolLabel string) []NodePoolSummary {
+	poolOfNode := make(map[string]string, len(nodes))
+	pools := make(map[string]*NodePoolSummary)
+	instanceTypes := make(map[string]map[string]bool)
+	for i := range nodes {
+		node := &nodes[i]
+		name := k8s.NodePool(node, poolLabel)
+		poolOfNode[node.Name] = name
+		pool, ok := pools[name]
+		if !ok {
+			pool = &NodePoolSummary{Name: name, InstanceTypes: []string{}}
+			if nodeUsage != nil {
+				pool.Usage = &NodePoolResources{}
+			}
+			pools[name] = pool
+			instanceTypes[name] = make(map[string]bool)
+		}
+
+		pool.Nodes++
+		pool.NodeNames = append(pool.NodeNames, node.Name)
+		if nodeReady(node) {
+			pool.Ready++
+		} else {
+			pool.NotReady++
+		}
+		if node.Spec.Unschedulable {
+			pool.Cordoned++
+		}
+		if instanceType := node.Labels[corev1.LabelInstanceTypeStable]; instanceType != "" && !instanceTypes[name][instanceType] {
+			instanceTypes[name][instanceType] = true
+			pool.InstanceTypes = append(pool.InstanceTypes, instanceType)
+		}
+		pool.Capacity = addResources(pool.Capacity, node.Status.Capacity)
+		pool.Allocatable = addResources(pool.Allocatable, node.Status.Allocatable)
+		if usage, ok := nodeUsage[node.Name]; ok && pool.Usage != nil {
+			*pool.Usage = addResources(*pool.Usage, usage)
+		}
+	}
+
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		if pool, ok := pools[poolOfNode[pod.Spec.NodeName]]; ok {
+			pool.RunningPods++
+		}
+	}
+
+	result := make([]NodePoolSummary, 0, len(pools))
+	for _, pool := range pools {
+		sort.Strings(pool.NodeNames)
+		sort.Strings(pool.InstanceTypes)
+		if pool.Usage != nil {
+			pool.CPUPercent = percent(pool.Usage.CPU, pool.Allocatable.CPU)
+			pool.MemoryPercent = percent(pool.Usage.Memory, pool.Allocatable.Memory)
+		}
+		pool.PodPercent = percent(float64(pool.RunningPods), pool.Allocatable.Pods)
+		result = append(result, *pool)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+func nodeReady(node *corev1.Node) bool {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func addResources(total NodePoolResources, list corev1.ResourceList) NodePoolResources {
+	total.CPU += list.Cpu().AsApproximateFloat64()
+	total.Memory += list.Memory().AsApproximateFloat64()
+	total.Pods += list.Pods().AsApproximateFloat64()
+	total.Extended = k8s.AddResources(total.Extended, k8s.ExtendedResources(list))
+	return total
+}
+
+func percent(used, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return used / total * 100
+}
@@ -0,0 +1,175 @@
+package clusters
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/k8s-dashboard/backend/internal/k8s"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ClusterSourceToken 使用 API Server 地址 + ServiceAccount Token 注册的集群
+const ClusterSourceToken = "token"
+
+// Credentials 集群接入凭据：完整 kubeconfig，或 server + caData + bearerToken（通常来自 ServiceAccount）
+type Credentials struct {
+	Kubeconfig  string `json:"kubeconfig"`
+	Context     string `json:"context"` // kubeconfig 中使用的 context，为空时使用 current-context
+	Server      string `json:"server"`
+	CAData      string `json:"caData"` // PEM 或 base64 编码的 PEM（与 kubeconfig 的 certificate-authority-data 相同）
+	BearerToken string `json:"bearerToken"`
+}
+
+// tokenCredentials Token 方式注册时加密保存的内容
+type tokenCredentials struct {
+	Server      string `json:"server"`
+	CAData      []byte `json:"caData,omitempty"`
+	BearerToken string `json:"bearerToken"`
+}
+
+// build 校验凭据并创建客户端，返回来源类型与需加密保存的内容
+func (c Credentials) build() (source string, secret []byte, client *k8s.Client, err error) {
+	kubeconfig := strings.TrimSpace(c.Kubeconfig)
+	usesToken := strings.TrimSpace(c.Server) != "" || strings.TrimSpace(c.BearerToken) != ""
+
+	switch {
+	case kubeconfig != "" && usesToken:
+		return "", nil, nil, errors.New("provide either kubeconfig or server/bearerToken, not both")
+	case kubeconfig != "":
+		content := []byte(kubeconfig)
+		if c.Context != "" {
+			if content, err = selectKubeconfigContext(content, c.Context); err != nil {
+				return "", nil, nil, err
+			}
+		}
+		client, err := k8s.NewClientWithKubeconfigBytes(content)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		return ClusterSourceKubeconfig, content, client, nil
+	case usesToken:
+		creds, err := c.tokenCredentials()
+		if err != nil {
+			return "", nil, nil, err
+		}
+		client, err := k8s.NewClientWithConfig(creds.restConfig())
+		if err != nil {
+			return "", nil, nil, err
+		}
+		secret, err := json.Marshal(creds)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		return ClusterSourceToken, secret, client, nil
+	default:
+		return "", nil, nil, errors.New("kubeconfig or server/bearerToken is required")
+	}
+}
+
+func (c Credentials) tokenCredentials() (*tokenCredentials, error) {
+	server := strings.TrimRight(strings.TrimSpace(c.Server), "/")
+	token := strings.TrimSpace(c.BearerToken)
+	if server == "" || token == "" {
+		return nil, errors.New("server and bearerToken are required")
+	}
+	u, err := url.Parse(server)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("server must be an https URL: %q", server)
+	}
+
+	creds := &tokenCredentials{Server: server, BearerToken: token}
+	if ca := strings.TrimSpace(c.CAData); ca != "" {
+		if strings.HasPrefix(ca, "-----BEGIN") {
+			creds.CAData = []byte(ca)
+		} else {
+			decoded, err := base64.StdEncoding.DecodeString(ca)
+			if err != nil {
+				return nil, errors.New("caData must be PEM or base64-encoded PEM")
+			}
+			creds.CAData = decoded
+		}
+	}
+	return creds, nil
+}
+
+// restConfig 未提供 CA 时使用系统根证书校验 API Server
+func (t *tokenCredentials) restConfig() *rest.Config {
+	return &rest.Config{
+		Host:            t.Server,
+		BearerToken:     t.BearerToken,
+		TLSClientConfig: rest.TLSClientConfig{CAData: t.CAData},
+	}
+}
+
+func clientFromTokenSecret(secret []byte) (*k8s.Client, error) {
+	var creds tokenCredentials
+	if err := json.Unmarshal(secret, &creds); err != nil {
+		return nil, fmt.Errorf("decode token credentials failed: %w", err)
+	}
+	if creds.Server == "" || creds.BearerToken == "" {
+		return nil, errors.New("empty token credentials")
+	}
+	return k8s.NewClientWithConfig(creds.restConfig())
+}
+
+// KubeconfigContext kubeconfig 中的一个 context
+type KubeconfigContext struct {
+	Name      string `json:"name"`
+	Cluster   string `json:"cluster"`
+	User      string `json:"user"`
+	Namespace string `json:"namespace,omitempty"`
+	Server    string `json:"server,omitempty"`
+	Current   bool   `json:"current"`
+}
+
+// ListKubeconfigContexts 列出 kubeconfig 中的 context，供导入时选择
+func ListKubeconfigContexts(kubeconfig string) ([]KubeconfigContext, error) {
+	content := strings.TrimSpace(kubeconfig)
+	if content == "" {
+		return nil, errors.New("kubeconfig is required")
+	}
+	cfg, err := clientcmd.Load([]byte(content))
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]KubeconfigContext, 0, len(cfg.Contexts))
+	for name, ctx := range cfg.Contexts {
+		item := KubeconfigContext{
+			Name:      name,
+			Cluster:   ctx.Cluster,
+			User:      ctx.AuthInfo,
+			Namespace: ctx.Namespace,
+			Current:   name == cfg.CurrentContext,
+		}
+		if cluster := cfg.Clusters[ctx.Cluster]; cluster != nil {
+			item.Server = cluster.Server
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	return items, nil
+}
+
+// selectKubeconfigContext 切换到指定 context 并只保留其引用的集群与用户，避免保存无关凭据
+func selectKubeconfigContext(kubeconfig []byte, context string) ([]byte, error) {
+	cfg, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := cfg.Contexts[context]; !ok {
+		return nil, fmt.Errorf("context %q not found in kubeconfig", context)
+	}
+	cfg.CurrentContext = context
+	if err := clientcmdapi.MinifyConfig(cfg); err != nil {
+		return nil, err
+	}
+	return clientcmd.Write(*cfg)
+}
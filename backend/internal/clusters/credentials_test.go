@@ -0,0 +1,88 @@
+package clusters
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const multiContextKubeconfig = `apiVersion: v1
+kind: Config
+current-context: prod
+clusters:
+- name: prod-cluster
+  cluster:
+    server: https://prod.example.com:6443
+- name: staging-cluster
+  cluster:
+    server: https://staging.example.com:6443
+users:
+- name: prod-admin
+  user:
+    token: prod-token
+- name: staging-admin
+  user:
+    token: staging-token
+contexts:
+- name: prod
+  context:
+    cluster: prod-cluster
+    user: prod-admin
+- name: staging
+  context:
+    cluster: staging-cluster
+    user: staging-admin
+    namespace: apps
+`
+
+func TestListKubeconfigContexts(t *testing.T) {
+	items, err := ListKubeconfigContexts(multiContextKubeconfig)
+	if err != nil {
+		t.Fatalf("list contexts failed: %v", err)
+	}
+	if len(items) != 2 || items[0].Name != "prod" || items[1].Name != "staging" {
+		t.Fatalf("unexpected contexts: %+v", items)
+	}
+	if !items[0].Current || items[1].Current {
+		t.Fatalf("expected prod to be the current context: %+v", items)
+	}
+	if items[1].Server != "https://staging.example.com:6443" || items[1].Namespace != "apps" {
+		t.Fatalf("unexpected staging context: %+v", items[1])
+	}
+
+	if _, err := ListKubeconfigContexts(" "); err == nil {
+		t.Fatalf("expected empty kubeconfig to be rejected")
+	}
+}
+
+func TestAddWithKubeconfigContext(t *testing.T) {
+	mgr := newTestManager(t)
+	ctx := context.Background()
+
+	if _, err := mgr.Add(ctx, "staging", Credentials{Kubeconfig: multiContextKubeconfig, Context: "missing"}, Endpoints{}); err == nil {
+		t.Fatalf("expected unknown context to be rejected")
+	}
+	if _, err := mgr.Add(ctx, "staging", Credentials{Kubeconfig: multiContextKubeconfig, Context: "staging"}, Endpoints{}); err != nil {
+		t.Fatalf("add cluster with context failed: %v", err)
+	}
+
+	rec, err := mgr.repo.Get("staging")
+	if err != nil {
+		t.Fatalf("get record failed: %v", err)
+	}
+	stored, err := mgr.crypto.Decrypt(rec.KubeconfigEncrypted)
+	if err != nil {
+		t.Fatalf("decrypt kubeconfig failed: %v", err)
+	}
+	if strings.Contains(string(stored), "prod-token") {
+		t.Fatalf("expected unrelated credentials to be dropped from stored kubeconfig")
+	}
+
+	client, err := mgr.GetClient("staging")
+	if err != nil {
+		t.Fatalf("get client failed: %v", err)
+	}
+	if client.Config.Host != "https://staging.example.com:6443" {
+		t.Fatalf("expected staging server, got %q", client.Config.Host)
+	}
+}
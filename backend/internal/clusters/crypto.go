@@ -9,49 +9,53 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"os"
 	"strings"
 )
 
-const (
-	encryptionKeyEnv = "CLUSTER_ENCRYPTION_KEY"
-)
-
 // Crypto 负责 kubeconfig 的加解密。
 type Crypto struct {
 	key []byte
 }
 
-// NewCryptoFromEnv 创建加密器。
-// 优先使用 CLUSTER_ENCRYPTION_KEY（Base64 编码的 32 字节），
+// NewCrypto 创建加密器。
+// 优先使用 encryptionKey（CLUSTER_ENCRYPTION_KEY，Base64 编码的 32 字节），
 // 未配置时退化为 SHA-256(JWT_SECRET)。
-func NewCryptoFromEnv(jwtSecret string) (*Crypto, error) {
-	key, err := loadEncryptionKey(jwtSecret)
+func NewCrypto(encryptionKey, jwtSecret string) (*Crypto, error) {
+	key, err := loadEncryptionKey(encryptionKey, jwtSecret)
 	if err != nil {
 		return nil, err
 	}
 	return &Crypto{key: key}, nil
 }
 
-func loadEncryptionKey(jwtSecret string) ([]byte, error) {
-	keyB64 := strings.TrimSpace(os.Getenv(encryptionKeyEnv))
-	if keyB64 != "" {
-		key, err := base64.StdEncoding.DecodeString(keyB64)
-		if err != nil {
-			return nil, fmt.Errorf("decode %s failed: %w", encryptionKeyEnv, err)
-		}
-		if len(key) != 32 {
-			return nil, fmt.Errorf("%s must decode to 32 bytes, got %d", encryptionKeyEnv, len(key))
-		}
-		return key, nil
+// ValidateEncryptionKey 校验 CLUSTER_ENCRYPTION_KEY 为 Base64 编码的 32 字节
+func ValidateEncryptionKey(keyB64 string) error {
+	_, err := decodeEncryptionKey(keyB64)
+	return err
+}
+
+func decodeEncryptionKey(keyB64 string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(keyB64))
+	if err != nil {
+		return nil, fmt.Errorf("decode CLUSTER_ENCRYPTION_KEY failed: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("CLUSTER_ENCRYPTION_KEY must decode to 32 bytes, got %d", len(key))
+	}
+	return key, nil
+}
+
+func loadEncryptionKey(keyB64, jwtSecret string) ([]byte, error) {
+	if strings.TrimSpace(keyB64) != "" {
+		return decodeEncryptionKey(keyB64)
 	}
 
 	if jwtSecret == "" {
-		return nil, fmt.Errorf("%s is not set and JWT secret is empty", encryptionKeyEnv)
+		return nil, fmt.Errorf("CLUSTER_ENCRYPTION_KEY is not set and JWT secret is empty")
 	}
 
 	sum := sha256.Sum256([]byte(jwtSecret))
-	log.Printf("WARNING: %s is not set, deriving cluster encryption key from JWT_SECRET", encryptionKeyEnv)
+	log.Printf("WARNING: CLUSTER_ENCRYPTION_KEY is not set, deriving cluster encryption key from JWT_SECRET")
 	return sum[:], nil
 }
 
@@ -10,9 +10,7 @@ func TestCryptoEncryptDecrypt(t *testing.T) {
 	for i := range key {
 		key[i] = byte(i + 1)
 	}
-	t.Setenv(encryptionKeyEnv, base64.StdEncoding.EncodeToString(key))
-
-	c, err := NewCryptoFromEnv("jwt-secret")
+	c, err := NewCrypto(base64.StdEncoding.EncodeToString(key), "jwt-secret")
 	if err != nil {
 		t.Fatalf("new crypto failed: %v", err)
 	}
@@ -43,8 +41,7 @@ func TestCryptoDecryptWithWrongKeyFails(t *testing.T) {
 		keyB[i] = byte(i + 60)
 	}
 
-	t.Setenv(encryptionKeyEnv, base64.StdEncoding.EncodeToString(keyA))
-	a, err := NewCryptoFromEnv("jwt-secret")
+	a, err := NewCrypto(base64.StdEncoding.EncodeToString(keyA), "jwt-secret")
 	if err != nil {
 		t.Fatalf("new crypto A failed: %v", err)
 	}
@@ -53,8 +50,7 @@ func TestCryptoDecryptWithWrongKeyFails(t *testing.T) {
 		t.Fatalf("encrypt failed: %v", err)
 	}
 
-	t.Setenv(encryptionKeyEnv, base64.StdEncoding.EncodeToString(keyB))
-	b, err := NewCryptoFromEnv("jwt-secret")
+	b, err := NewCrypto(base64.StdEncoding.EncodeToString(keyB), "jwt-secret")
 	if err != nil {
 		t.Fatalf("new crypto B failed: %v", err)
 	}
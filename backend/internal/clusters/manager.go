@@ -5,20 +5,24 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/k8s-dashboard/backend/internal/alertmanager"
 	dbutil "github.com/k8s-dashboard/backend/internal/db"
 	"github.com/k8s-dashboard/backend/internal/k8s"
+	"github.com/k8s-dashboard/backend/internal/metrics"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // Info 是提供给 API/前端的集群视图。
 type Info struct {
 	Name        string `json:"name"`
+	DisplayName string `json:"displayName,omitempty"`
 	Endpoint    string `json:"endpoint"`
 	Version     string `json:"version"`
 	Status      string `json:"status"` // connected | disconnected | error
@@ -29,6 +33,36 @@ type Info struct {
 	Enabled     bool   `json:"enabled"`
 	LastError   string `json:"lastError,omitempty"`
 	Source      string `json:"source"` // kubeconfig | incluster
+	// 集群独立的监控与告警地址，为空表示使用全局配置
+	VictoriaMetricsURL string `json:"victoriaMetricsUrl,omitempty"`
+	AlertmanagerURL    string `json:"alertmanagerUrl,omitempty"`
+}
+
+// Endpoints 集群的监控与告警服务地址
+type Endpoints struct {
+	VictoriaMetricsURL string `json:"victoriaMetricsUrl"`
+	AlertmanagerURL    string `json:"alertmanagerUrl"`
+}
+
+// Validate 校验地址格式，空值合法（表示使用全局配置）
+func (e Endpoints) Validate() error {
+	for key, raw := range map[string]string{"victoriaMetricsUrl": e.VictoriaMetricsURL, "alertmanagerUrl": e.AlertmanagerURL} {
+		if raw == "" {
+			continue
+		}
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%s is invalid: %q", key, raw)
+		}
+	}
+	return nil
+}
+
+func (e Endpoints) normalize() Endpoints {
+	return Endpoints{
+		VictoriaMetricsURL: strings.TrimRight(strings.TrimSpace(e.VictoriaMetricsURL), "/"),
+		AlertmanagerURL:    strings.TrimRight(strings.TrimSpace(e.AlertmanagerURL), "/"),
+	}
 }
 
 // Manager 负责多集群管理、客户端缓存和连通性检查。
@@ -39,14 +73,20 @@ type Manager struct {
 
 	mu    sync.RWMutex
 	cache map[string]*k8s.Client
+	// 按地址缓存的监控/告警客户端，多个集群可共用同一地址
+	metricsCache    map[string]*metrics.Client
+	alertCache      map[string]*alertmanager.Client
+	severityMapping *alertmanager.SeverityMapping
+	queryRewriter   metrics.QueryRewriter
+	metricsConfig   metrics.Config
 }
 
-func NewManager(db *sql.DB, dialect dbutil.Dialect, jwtSecret string, defaultClient *k8s.Client) (*Manager, error) {
+func NewManager(db *sql.DB, dialect dbutil.Dialect, encryptionKey, jwtSecret string, defaultClient *k8s.Client) (*Manager, error) {
 	repo, err := NewRepository(db, dialect)
 	if err != nil {
 		return nil, fmt.Errorf("init cluster repository failed: %w", err)
 	}
-	crypto, err := NewCryptoFromEnv(jwtSecret)
+	crypto, err := NewCrypto(encryptionKey, jwtSecret)
 	if err != nil {
 		return nil, fmt.Errorf("init cluster crypto failed: %w", err)
 	}
@@ -56,6 +96,9 @@ func NewManager(db *sql.DB, dialect dbutil.Dialect, jwtSecret string, defaultCli
 		crypto:        crypto,
 		defaultClient: defaultClient,
 		cache:         make(map[string]*k8s.Client),
+		metricsCache:  make(map[string]*metrics.Client),
+		alertCache:    make(map[string]*alertmanager.Client),
+		metricsConfig: metrics.DefaultConfig(),
 	}
 
 	if err := m.bootstrapDefaultCluster(); err != nil {
@@ -252,6 +295,16 @@ func (m *Manager) createClient(rec Record) (*k8s.Client, error) {
 			return nil, fmt.Errorf("init kubernetes client failed: %w", err)
 		}
 		return client, nil
+	case ClusterSourceToken:
+		plain, err := m.crypto.Decrypt(rec.KubeconfigEncrypted)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt token credentials failed: %w", err)
+		}
+		client, err := clientFromTokenSecret(plain)
+		if err != nil {
+			return nil, fmt.Errorf("init kubernetes client failed: %w", err)
+		}
+		return client, nil
 	default:
 		return nil, fmt.Errorf("unknown cluster source: %s", rec.Source)
 	}
@@ -294,6 +347,7 @@ func infoFromRecord(rec Record) Info {
 	}
 	return Info{
 		Name:        rec.Name,
+		DisplayName: rec.DisplayName,
 		Status:      "disconnected",
 		LastChecked: lastChecked,
 		NodeCount:   0,
@@ -302,6 +356,9 @@ func infoFromRecord(rec Record) Info {
 		Enabled:     rec.Enabled,
 		LastError:   rec.LastError,
 		Source:      rec.Source,
+
+		VictoriaMetricsURL: rec.VictoriaMetricsURL,
+		AlertmanagerURL:    rec.AlertmanagerURL,
 	}
 }
 
@@ -358,14 +415,9 @@ func (m *Manager) Get(ctx context.Context, name string) (*Info, error) {
 	return &item, nil
 }
 
-// TestKubeconfig 测试 kubeconfig 连通性，不会持久化。
-func (m *Manager) TestKubeconfig(ctx context.Context, kubeconfig string) (*Info, error) {
-	content := strings.TrimSpace(kubeconfig)
-	if content == "" {
-		return nil, errors.New("kubeconfig is required")
-	}
-
-	client, err := k8s.NewClientWithKubeconfigBytes([]byte(content))
+// TestCredentials 测试 kubeconfig 或 Token 凭据的连通性，不会持久化。
+func (m *Manager) TestCredentials(ctx context.Context, creds Credentials) (*Info, error) {
+	source, _, client, err := creds.build()
 	if err != nil {
 		return nil, err
 	}
@@ -404,12 +456,12 @@ func (m *Manager) TestKubeconfig(ctx context.Context, kubeconfig string) (*Info,
 		PodCount:    podCount,
 		IsDefault:   false,
 		Enabled:     true,
-		Source:      ClusterSourceKubeconfig,
+		Source:      source,
 	}, nil
 }
 
-// Add 新增集群。
-func (m *Manager) Add(ctx context.Context, name, kubeconfig string) (*Info, error) {
+// Add 新增集群，凭据为 kubeconfig 或 server/caData/bearerToken；endpoints 为空时该集群使用全局监控与告警地址。
+func (m *Manager) Add(ctx context.Context, name string, creds Credentials, endpoints Endpoints) (*Info, error) {
 	clusterName := strings.TrimSpace(name)
 	if clusterName == "" {
 		return nil, errors.New("cluster name is required")
@@ -418,9 +470,9 @@ func (m *Manager) Add(ctx context.Context, name, kubeconfig string) (*Info, erro
 		return nil, fmt.Errorf("%q is reserved", DefaultClusterName)
 	}
 
-	content := strings.TrimSpace(kubeconfig)
-	if content == "" {
-		return nil, errors.New("kubeconfig is required")
+	endpoints = endpoints.normalize()
+	if err := endpoints.Validate(); err != nil {
+		return nil, err
 	}
 
 	if _, err := m.repo.Get(clusterName); err == nil {
@@ -429,12 +481,12 @@ func (m *Manager) Add(ctx context.Context, name, kubeconfig string) (*Info, erro
 		return nil, err
 	}
 
-	client, err := k8s.NewClientWithKubeconfigBytes([]byte(content))
+	source, secret, client, err := creds.build()
 	if err != nil {
 		return nil, err
 	}
 
-	encrypted, err := m.crypto.Encrypt([]byte(content))
+	encrypted, err := m.crypto.Encrypt(secret)
 	if err != nil {
 		return nil, err
 	}
@@ -442,9 +494,11 @@ func (m *Manager) Add(ctx context.Context, name, kubeconfig string) (*Info, erro
 	if err := m.repo.Create(Record{
 		Name:                clusterName,
 		KubeconfigEncrypted: encrypted,
-		Source:              ClusterSourceKubeconfig,
+		Source:              source,
 		IsDefault:           false,
 		Enabled:             true,
+		VictoriaMetricsURL:  endpoints.VictoriaMetricsURL,
+		AlertmanagerURL:     endpoints.AlertmanagerURL,
 	}); err != nil {
 		return nil, err
 	}
@@ -456,6 +510,73 @@ func (m *Manager) Add(ctx context.Context, name, kubeconfig string) (*Info, erro
 	return m.Get(ctx, clusterName)
 }
 
+// Update 集群更新内容，nil 字段保持不变
+type Update struct {
+	DisplayName *string
+	Credentials *Credentials
+	Enabled     *bool
+}
+
+// Update 修改展示名称、轮换凭据或启用/禁用集群。新凭据需连通后才保存，
+// 保存后清除客户端缓存，后续请求使用新凭据；默认集群不可禁用。
+func (m *Manager) Update(ctx context.Context, name string, update Update) (*Info, error) {
+	clusterName := strings.TrimSpace(name)
+	if clusterName == "" {
+		return nil, errors.New("cluster name is required")
+	}
+	rec, err := m.repo.Get(clusterName)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("cluster %q not found", clusterName)
+		}
+		return nil, err
+	}
+
+	if update.DisplayName != nil {
+		rec.DisplayName = strings.TrimSpace(*update.DisplayName)
+	}
+	if update.Enabled != nil {
+		if !*update.Enabled && rec.IsDefault {
+			return nil, errors.New("default cluster cannot be disabled")
+		}
+		rec.Enabled = *update.Enabled
+	}
+	if update.Credentials != nil {
+		source, secret, client, err := update.Credentials.build()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := client.Clientset.Discovery().ServerVersion(); err != nil {
+			return nil, fmt.Errorf("cluster is unreachable with new credentials: %w", err)
+		}
+		encrypted, err := m.crypto.Encrypt(secret)
+		if err != nil {
+			return nil, err
+		}
+		rec.KubeconfigEncrypted = encrypted
+		rec.Source = source
+	}
+
+	if err := m.repo.Update(*rec); err != nil {
+		return nil, err
+	}
+
+	m.evictClient(clusterName)
+
+	return m.Get(ctx, clusterName)
+}
+
+// evictClient 移除缓存的集群客户端并停止其后台 informer；in-cluster 客户端为全局共享，不关闭
+func (m *Manager) evictClient(name string) {
+	m.mu.Lock()
+	client := m.cache[name]
+	delete(m.cache, name)
+	m.mu.Unlock()
+	if client != nil && client != m.defaultClient {
+		client.Close()
+	}
+}
+
 // Delete 删除集群（默认集群不可删）。
 func (m *Manager) Delete(name string) error {
 	clusterName := strings.TrimSpace(name)
@@ -468,9 +589,7 @@ func (m *Manager) Delete(name string) error {
 	if err := m.repo.Delete(clusterName); err != nil {
 		return err
 	}
-	m.mu.Lock()
-	delete(m.cache, clusterName)
-	m.mu.Unlock()
+	m.evictClient(clusterName)
 	return nil
 }
 
@@ -486,3 +605,83 @@ func (m *Manager) Switch(ctx context.Context, name string) (*Info, error) {
 	}
 	return m.Get(ctx, clusterName)
 }
+
+// UpdateEndpoints 设置集群的监控与告警地址，空值表示回落到全局配置。
+func (m *Manager) UpdateEndpoints(ctx context.Context, name string, endpoints Endpoints) (*Info, error) {
+	clusterName := strings.TrimSpace(name)
+	if clusterName == "" {
+		return nil, errors.New("cluster name is required")
+	}
+	endpoints = endpoints.normalize()
+	if err := endpoints.Validate(); err != nil {
+		return nil, err
+	}
+	if err := m.repo.UpdateEndpoints(clusterName, endpoints.VictoriaMetricsURL, endpoints.AlertmanagerURL); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("cluster %q not found", clusterName)
+		}
+		return nil, err
+	}
+	return m.Get(ctx, clusterName)
+}
+
+// SetAlertSeverityMapping 设置集群专属 Alertmanager 客户端使用的严重级别映射，与全局客户端保持一致。
+func (m *Manager) SetAlertSeverityMapping(mapping *alertmanager.SeverityMapping) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.severityMapping = mapping
+	for _, client := range m.alertCache {
+		client.SetSeverityMapping(mapping)
+	}
+}
+
+// SetMetricsQueryRewriter 设置集群专属 VictoriaMetrics 客户端使用的内置查询映射，与全局客户端保持一致。
+func (m *Manager) SetMetricsQueryRewriter(rewriter metrics.QueryRewriter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queryRewriter = rewriter
+	for _, client := range m.metricsCache {
+		client.SetQueryRewriter(rewriter)
+	}
+}
+
+// SetMetricsConfig 设置之后创建的集群专属 VictoriaMetrics 客户端的超时、重试、熔断与缓存配置。
+func (m *Manager) SetMetricsConfig(cfg metrics.Config) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.metricsConfig = cfg
+}
+
+// GetEndpointClients 返回集群专属的监控与告警客户端，未单独配置的返回 nil（调用方使用全局客户端）。
+func (m *Manager) GetEndpointClients(name string) (*metrics.Client, *alertmanager.Client, error) {
+	rec, err := m.repo.Get(name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, fmt.Errorf("cluster %q not found", name)
+		}
+		return nil, nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var metricsClient *metrics.Client
+	if rec.VictoriaMetricsURL != "" {
+		metricsClient = m.metricsCache[rec.VictoriaMetricsURL]
+		if metricsClient == nil {
+			metricsClient = metrics.NewClientWithConfig(rec.VictoriaMetricsURL, m.metricsConfig)
+			metricsClient.SetQueryRewriter(m.queryRewriter)
+			m.metricsCache[rec.VictoriaMetricsURL] = metricsClient
+		}
+	}
+	var alertClient *alertmanager.Client
+	if rec.AlertmanagerURL != "" {
+		alertClient = m.alertCache[rec.AlertmanagerURL]
+		if alertClient == nil {
+			alertClient = alertmanager.NewClient(rec.AlertmanagerURL)
+			alertClient.SetSeverityMapping(m.severityMapping)
+			m.alertCache[rec.AlertmanagerURL] = alertClient
+		}
+	}
+	return metricsClient, alertClient, nil
+}
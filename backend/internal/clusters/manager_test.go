@@ -1,8 +1,17 @@
 package clusters
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/base64"
+	"encoding/pem"
+	"math/big"
 	"testing"
+	"time"
 
 	dbutil "github.com/k8s-dashboard/backend/internal/db"
 )
@@ -14,7 +23,6 @@ func newTestManager(t *testing.T) *Manager {
 	for i := range key {
 		key[i] = byte(i + 1)
 	}
-	t.Setenv(encryptionKeyEnv, base64.StdEncoding.EncodeToString(key))
 
 	database, dialect, err := dbutil.Open(dbutil.Config{
 		SQLitePath:          ":memory:",
@@ -27,7 +35,7 @@ func newTestManager(t *testing.T) *Manager {
 		_ = database.Close()
 	})
 
-	mgr, err := NewManager(database, dialect, "jwt-secret", nil)
+	mgr, err := NewManager(database, dialect, base64.StdEncoding.EncodeToString(key), "jwt-secret", nil)
 	if err != nil {
 		t.Fatalf("new manager failed: %v", err)
 	}
@@ -53,3 +61,138 @@ func TestManagerResolveMissingCluster(t *testing.T) {
 		t.Fatalf("expected missing cluster to return error")
 	}
 }
+
+func TestManagerClusterEndpointClients(t *testing.T) {
+	mgr := newTestManager(t)
+
+	metricsClient, alertClient, err := mgr.GetEndpointClients(DefaultClusterName)
+	if err != nil {
+		t.Fatalf("get endpoint clients failed: %v", err)
+	}
+	if metricsClient != nil || alertClient != nil {
+		t.Fatalf("expected no cluster-specific clients before configuration")
+	}
+
+	if _, err := mgr.UpdateEndpoints(context.Background(), DefaultClusterName, Endpoints{VictoriaMetricsURL: "vm:8428"}); err == nil {
+		t.Fatalf("expected url without scheme to be rejected")
+	}
+	info, err := mgr.UpdateEndpoints(context.Background(), DefaultClusterName, Endpoints{VictoriaMetricsURL: "http://vm:8428/"})
+	if err != nil {
+		t.Fatalf("update endpoints failed: %v", err)
+	}
+	if info.VictoriaMetricsURL != "http://vm:8428" || info.AlertmanagerURL != "" {
+		t.Fatalf("unexpected endpoints: %+v", info)
+	}
+
+	metricsClient, alertClient, err = mgr.GetEndpointClients(DefaultClusterName)
+	if err != nil {
+		t.Fatalf("get endpoint clients failed: %v", err)
+	}
+	if metricsClient == nil || alertClient != nil {
+		t.Fatalf("expected only a cluster-specific metrics client")
+	}
+	again, _, _ := mgr.GetEndpointClients(DefaultClusterName)
+	if again != metricsClient {
+		t.Fatalf("expected metrics client to be cached")
+	}
+
+	if _, err := mgr.UpdateEndpoints(context.Background(), "missing-cluster", Endpoints{}); err == nil {
+		t.Fatalf("expected missing cluster to return error")
+	}
+}
+
+func TestManagerUpdateCluster(t *testing.T) {
+	mgr := newTestManager(t)
+	if err := mgr.repo.Create(Record{Name: "dev-cluster", Source: ClusterSourceKubeconfig, Enabled: true}); err != nil {
+		t.Fatalf("create cluster failed: %v", err)
+	}
+
+	disabled := false
+	if _, err := mgr.Update(context.Background(), DefaultClusterName, Update{Enabled: &disabled}); err == nil {
+		t.Fatalf("expected disabling default cluster to fail")
+	}
+
+	displayName := " 开发集群 "
+	info, err := mgr.Update(context.Background(), "dev-cluster", Update{DisplayName: &displayName, Enabled: &disabled})
+	if err != nil {
+		t.Fatalf("update cluster failed: %v", err)
+	}
+	if info.DisplayName != "开发集群" || info.Enabled {
+		t.Fatalf("unexpected cluster info: %+v", info)
+	}
+	if _, err := mgr.GetClient("dev-cluster"); err == nil {
+		t.Fatalf("expected disabled cluster client to be rejected")
+	}
+
+	if _, err := mgr.Update(context.Background(), "dev-cluster", Update{Credentials: &Credentials{Kubeconfig: " "}}); err == nil {
+		t.Fatalf("expected empty credentials to be rejected")
+	}
+	if _, err := mgr.Update(context.Background(), "missing-cluster", Update{DisplayName: &displayName}); err == nil {
+		t.Fatalf("expected missing cluster to return error")
+	}
+}
+
+func TestManagerAddWithServiceAccountToken(t *testing.T) {
+	mgr := newTestManager(t)
+	ctx := context.Background()
+
+	invalid := []Credentials{
+		{},
+		{Server: "https://10.0.0.1:6443"},
+		{Server: "http://10.0.0.1:6443", BearerToken: "token"},
+		{Server: "https://10.0.0.1:6443", BearerToken: "token", CAData: "not-base64!"},
+		{Server: "https://10.0.0.1:6443", BearerToken: "token", Kubeconfig: "apiVersion: v1"},
+	}
+	for _, creds := range invalid {
+		if _, err := mgr.Add(ctx, "sa-cluster", creds, Endpoints{}); err == nil {
+			t.Fatalf("expected credentials %+v to be rejected", creds)
+		}
+	}
+
+	caPEM := testCertificatePEM(t)
+	info, err := mgr.Add(ctx, "sa-cluster", Credentials{
+		Server:      "https://10.0.0.1:6443/",
+		CAData:      base64.StdEncoding.EncodeToString(caPEM),
+		BearerToken: "sa-token",
+	}, Endpoints{})
+	if err != nil {
+		t.Fatalf("add token cluster failed: %v", err)
+	}
+	if info.Source != ClusterSourceToken {
+		t.Fatalf("expected token source, got %q", info.Source)
+	}
+
+	// 清除缓存，验证从加密存储重建客户端
+	mgr.mu.Lock()
+	delete(mgr.cache, "sa-cluster")
+	mgr.mu.Unlock()
+
+	client, err := mgr.GetClient("sa-cluster")
+	if err != nil {
+		t.Fatalf("get token cluster client failed: %v", err)
+	}
+	if client.Config.Host != "https://10.0.0.1:6443" || client.Config.BearerToken != "sa-token" || string(client.Config.CAData) != string(caPEM) {
+		t.Fatalf("unexpected rest config: host=%q token=%q", client.Config.Host, client.Config.BearerToken)
+	}
+}
+
+func testCertificatePEM(t *testing.T) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key failed: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate failed: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
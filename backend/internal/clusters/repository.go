@@ -18,14 +18,18 @@ const (
 type Record struct {
 	ID                  int64
 	Name                string
-	KubeconfigEncrypted string
+	DisplayName         string // 展示名称，为空时展示 Name
+	KubeconfigEncrypted string // 加密的 kubeconfig；token 来源时为加密的 Token 凭据
 	Source              string
 	IsDefault           bool
 	Enabled             bool
 	LastCheckedAt       *time.Time
 	LastError           string
-	CreatedAt           time.Time
-	UpdatedAt           time.Time
+	// 集群独立的监控与告警地址，为空时使用全局配置
+	VictoriaMetricsURL string
+	AlertmanagerURL    string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
 }
 
 // Repository 负责集群记录持久化。
@@ -79,8 +83,44 @@ func (r *Repository) ensureSchema() error {
 		CREATE INDEX IF NOT EXISTS idx_clusters_is_default ON clusters(is_default);
 		`
 	}
-	_, err := r.db.Exec(schema)
-	return err
+	if _, err := r.db.Exec(schema); err != nil {
+		return err
+	}
+	for _, column := range []string{"victoria_metrics_url", "alertmanager_url", "display_name"} {
+		if err := dbutil.EnsureColumn(r.db, r.dialect, "clusters", column, "TEXT NOT NULL DEFAULT ''"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const recordColumns = `id, name, display_name, kubeconfig_encrypted, source, is_default, enabled, last_checked_at, last_error,
+		victoria_metrics_url, alertmanager_url, created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRecord(row rowScanner) (*Record, error) {
+	var rec Record
+	if err := row.Scan(
+		&rec.ID,
+		&rec.Name,
+		&rec.DisplayName,
+		&rec.KubeconfigEncrypted,
+		&rec.Source,
+		&rec.IsDefault,
+		&rec.Enabled,
+		&rec.LastCheckedAt,
+		&rec.LastError,
+		&rec.VictoriaMetricsURL,
+		&rec.AlertmanagerURL,
+		&rec.CreatedAt,
+		&rec.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &rec, nil
 }
 
 func (r *Repository) Count() (int64, error) {
@@ -95,8 +135,9 @@ func (r *Repository) Create(rec Record) error {
 	}
 	query := `
 		INSERT INTO clusters (
-			name, kubeconfig_encrypted, source, is_default, enabled, last_checked_at, last_error, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, CURRENT_TIMESTAMP)
+			name, kubeconfig_encrypted, source, is_default, enabled, last_checked_at, last_error,
+			victoria_metrics_url, alertmanager_url, display_name, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, CURRENT_TIMESTAMP)
 	`
 	_, err := r.db.Exec(
 		query,
@@ -107,13 +148,16 @@ func (r *Repository) Create(rec Record) error {
 		rec.Enabled,
 		rec.LastCheckedAt,
 		rec.LastError,
+		rec.VictoriaMetricsURL,
+		rec.AlertmanagerURL,
+		rec.DisplayName,
 	)
 	return err
 }
 
 func (r *Repository) List() ([]Record, error) {
 	rows, err := r.db.Query(`
-		SELECT id, name, kubeconfig_encrypted, source, is_default, enabled, last_checked_at, last_error, created_at, updated_at
+		SELECT ` + recordColumns + `
 		FROM clusters
 		ORDER BY is_default DESC, name ASC
 	`)
@@ -124,77 +168,38 @@ func (r *Repository) List() ([]Record, error) {
 
 	var records []Record
 	for rows.Next() {
-		var rec Record
-		if err := rows.Scan(
-			&rec.ID,
-			&rec.Name,
-			&rec.KubeconfigEncrypted,
-			&rec.Source,
-			&rec.IsDefault,
-			&rec.Enabled,
-			&rec.LastCheckedAt,
-			&rec.LastError,
-			&rec.CreatedAt,
-			&rec.UpdatedAt,
-		); err != nil {
+		rec, err := scanRecord(rows)
+		if err != nil {
 			return nil, err
 		}
-		records = append(records, rec)
+		records = append(records, *rec)
 	}
 	return records, rows.Err()
 }
 
 func (r *Repository) Get(name string) (*Record, error) {
-	var rec Record
-	err := r.db.QueryRow(`
-		SELECT id, name, kubeconfig_encrypted, source, is_default, enabled, last_checked_at, last_error, created_at, updated_at
+	return scanRecord(r.db.QueryRow(`
+		SELECT `+recordColumns+`
 		FROM clusters
 		WHERE name = $1
-	`, name).Scan(
-		&rec.ID,
-		&rec.Name,
-		&rec.KubeconfigEncrypted,
-		&rec.Source,
-		&rec.IsDefault,
-		&rec.Enabled,
-		&rec.LastCheckedAt,
-		&rec.LastError,
-		&rec.CreatedAt,
-		&rec.UpdatedAt,
-	)
-	if err != nil {
-		return nil, err
-	}
-	return &rec, nil
+	`, name))
 }
 
 func (r *Repository) GetDefault() (*Record, error) {
-	var rec Record
-	err := r.db.QueryRow(`
-		SELECT id, name, kubeconfig_encrypted, source, is_default, enabled, last_checked_at, last_error, created_at, updated_at
+	rec, err := scanRecord(r.db.QueryRow(`
+		SELECT `+recordColumns+`
 		FROM clusters
 		WHERE is_default = $1
 		ORDER BY id ASC
 		LIMIT 1
-	`, true).Scan(
-		&rec.ID,
-		&rec.Name,
-		&rec.KubeconfigEncrypted,
-		&rec.Source,
-		&rec.IsDefault,
-		&rec.Enabled,
-		&rec.LastCheckedAt,
-		&rec.LastError,
-		&rec.CreatedAt,
-		&rec.UpdatedAt,
-	)
+	`, true))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, err
 	}
-	return &rec, nil
+	return rec, nil
 }
 
 func (r *Repository) EnsureDefault(name string) error {
@@ -235,6 +240,52 @@ func (r *Repository) UpdateHealth(name string, checkedAt time.Time, lastError st
 	return err
 }
 
+// UpdateEndpoints 更新集群的 VictoriaMetrics 与 Alertmanager 地址
+func (r *Repository) UpdateEndpoints(name, victoriaMetricsURL, alertmanagerURL string) error {
+	result, err := r.db.Exec(`
+		UPDATE clusters
+		SET victoria_metrics_url = $2,
+		    alertmanager_url = $3,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE name = $1
+	`, name, victoriaMetricsURL, alertmanagerURL)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Update 保存集群的展示名称、凭据与启用状态
+func (r *Repository) Update(rec Record) error {
+	result, err := r.db.Exec(`
+		UPDATE clusters
+		SET display_name = $2,
+		    kubeconfig_encrypted = $3,
+		    source = $4,
+		    enabled = $5,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE name = $1
+	`, rec.Name, rec.DisplayName, rec.KubeconfigEncrypted, rec.Source, rec.Enabled)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
 func (r *Repository) ValidateDelete(name string) error {
 	rec, err := r.Get(name)
 	if err != nil {
@@ -0,0 +1,676 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/k8s-dashboard/backend/internal/alertmanager"
+	"github.com/k8s-dashboard/backend/internal/audit"
+	"github.com/k8s-dashboard/backend/internal/auth"
+	"github.com/k8s-dashboard/backend/internal/clusters"
+	"github.com/k8s-dashboard/backend/internal/cost"
+	"github.com/k8s-dashboard/backend/internal/db"
+	"github.com/k8s-dashboard/backend/internal/metrics"
+	"github.com/k8s-dashboard/backend/internal/promql"
+	"github.com/k8s-dashboard/backend/internal/ratelimit"
+	"github.com/k8s-dashboard/backend/internal/recommendations"
+	"sigs.k8s.io/yaml"
+)
+
+// 运行环境
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+)
+
+// 开发环境默认值，生产环境禁止使用
+const (
+	DefaultJWTSecret          = "k8s-dashboard-secret-key-change-in-production"
+	DevVictoriaMetricsURL     = "http://192.168.1.90:31007"
+	DevAlertmanagerURL        = "http://192.168.1.90:32607"
+	minProductionSecretLength = 32
+)
+
+// 部署清单中的占位密钥，与默认值同样视为不安全
+var insecureJWTSecrets = []string{
+	DefaultJWTSecret,
+	"your-jwt-secret-key-change-in-production",
+	"your-jwt-secret-key",
+	"your-jwt-secret",
+}
+
+// Config 服务启动配置。加载顺序：默认值 < 配置文件（YAML） < 环境变量
+type Config struct {
+	// Environment 运行环境（APP_ENV），production 下拒绝不安全的默认值
+	Environment string `json:"environment"`
+	Port        string `json:"port"`
+
+	VictoriaMetricsURL string `json:"victoriaMetricsUrl"`
+	// VictoriaMetricsQueryPath 查询接口路径前缀：auto（默认）自动探测，或显式指定如 /select/0/prometheus、/
+	VictoriaMetricsQueryPath string `json:"victoriaMetricsQueryPath"`
+	AlertmanagerURL          string `json:"alertmanagerUrl"`
+
+	JWTSecret           string `json:"jwtSecret"`
+	MultiClusterEnabled bool   `json:"multiClusterEnabled"`
+
+	// K8sUserAuth 访问 Kubernetes 的身份：dashboard（默认，共用 Dashboard 凭据）、
+	// token（用户绑定的 ServiceAccount Token）或 impersonate（模拟用户）
+	K8sUserAuth string `json:"k8sUserAuth"`
+
+	Database db.Config `json:"database"`
+
+	PasswordPolicy auth.PasswordPolicy `json:"passwordPolicy"`
+	Session        SessionConfig       `json:"session"`
+
+	UserWebhook    WebhookConfig        `json:"userWebhook"`
+	ApprovalNotify ApprovalNotifyConfig `json:"approvalNotify"`
+	EventHistory   EventHistoryConfig   `json:"eventHistory"`
+	AlertWebhook   AlertWebhookConfig   `json:"alertWebhook"`
+
+	// 数据保留天数，0 表示永久保留
+	AuditRetentionDays int `json:"auditRetentionDays"`
+	AlertRetentionDays int `json:"alertRetentionDays"`
+	// AuditArchiveDir 清理前将过期审计日志归档为 gzip 文件的目录，为空时直接删除
+	AuditArchiveDir string `json:"auditArchiveDir"`
+
+	AuditForward AuditForwardConfig  `json:"auditForward"`
+	AuditAnomaly audit.AnomalyConfig `json:"auditAnomaly"`
+
+	// Recommendations 容器资源建议（基于 VictoriaMetrics 历史用量）
+	Recommendations recommendations.Config `json:"recommendations"`
+	// Cost 命名空间与工作负载费用估算的计价配置
+	Cost cost.Config `json:"cost"`
+	// RateLimit 按用户与 IP 的请求限流及高开销操作并发上限
+	RateLimit ratelimit.Config `json:"rateLimit"`
+	// TrustedProxies 可信反向代理的 IP 或 CIDR，仅来自这些地址的 X-Forwarded-For 用于识别客户端 IP；
+	// 为空时始终使用连接的对端地址
+	TrustedProxies []string `json:"trustedProxies"`
+	// MetricsQuery 自定义 PromQL 查询（/metrics/query）的指标允许/拒绝列表与时间范围限制
+	MetricsQuery promql.Config `json:"metricsQuery"`
+	// MetricsMapping 内置查询的指标名与标签名映射，适配非标准的 exporter 命名
+	MetricsMapping promql.Mapping `json:"metricsMapping"`
+	// Metrics VictoriaMetrics 查询的超时、重试、熔断与结果缓存
+	Metrics metrics.Config `json:"metrics"`
+
+	// TerminalRecording 终端会话录制与脱敏规则
+	TerminalRecording audit.TerminalRecordingConfig `json:"terminalRecording"`
+	// WebSocket 终端、日志、事件与通知 WebSocket 的 Origin 校验与会话限制
+	WebSocket WebSocketConfig `json:"webSocket"`
+	// PacketCaptureImage 抓包临时容器镜像（需包含 tcpdump），为空时使用 nicolaka/netshoot
+	PacketCaptureImage string `json:"packetCaptureImage"`
+	// APIV1Sunset /api/v1 计划下线日期（YYYY-MM-DD），设置后 v1 响应附带 Sunset 头
+	APIV1Sunset string `json:"apiV1Sunset"`
+	// NodePoolLabel 节点池分组标签，为空时自动识别 karpenter/GKE/EKS/AKS/ACK 节点池标签
+	NodePoolLabel string `json:"nodePoolLabel"`
+	// AlertSeverityMapping 告警严重级别映射（兼容 P1/P2、sev1 等自定义级别），为空时使用默认映射
+	AlertSeverityMapping *alertmanager.SeverityMapping `json:"alertSeverityMapping"`
+	// AlertSeverityMappingFile 严重级别映射的 JSON 文件，未设置 AlertSeverityMapping 时读取
+	AlertSeverityMappingFile string `json:"alertSeverityMappingFile"`
+	// ClusterEncryptionKey 加密集群 kubeconfig 的密钥（Base64 编码的 32 字节），为空时由 JWT_SECRET 派生
+	ClusterEncryptionKey string `json:"clusterEncryptionKey"`
+}
+
+// WebSocketConfig WebSocket 配置，时长为 Go duration 格式（如 15m、4h），0 表示不限制
+type WebSocketConfig struct {
+	AllowedOrigins     []string `json:"allowedOrigins"`  // 为空时只允许与请求同源
+	AllowQueryToken    bool     `json:"allowQueryToken"` // 允许 token=JWT 的旧链路（仅应急）
+	IdleTimeout        string   `json:"idleTimeout"`
+	MaxSessionDuration string   `json:"maxSessionDuration"`
+	TimeoutWarning     string   `json:"timeoutWarning"` // 会话到期前提醒的提前量
+}
+
+// SessionLimits 解析会话的空闲超时、最长时长与到期提醒提前量
+func (w WebSocketConfig) SessionLimits() (idle, maxDuration, warning time.Duration, err error) {
+	durations := []struct {
+		key string
+		raw string
+		dst *time.Duration
+	}{
+		{"WS_IDLE_TIMEOUT", w.IdleTimeout, &idle},
+		{"WS_MAX_SESSION_DURATION", w.MaxSessionDuration, &maxDuration},
+		{"WS_TIMEOUT_WARNING", w.TimeoutWarning, &warning},
+	}
+	for _, d := range durations {
+		if d.raw == "" {
+			continue
+		}
+		v, parseErr := time.ParseDuration(d.raw)
+		if parseErr != nil || v < 0 {
+			return 0, 0, 0, fmt.Errorf("%s 无效: %q", d.key, d.raw)
+		}
+		*d.dst = v
+	}
+	return idle, maxDuration, warning, nil
+}
+
+// AuditForwardConfig 审计日志外部转发（SIEM），未配置的渠道不启用
+type AuditForwardConfig struct {
+	WebhookURL    string `json:"webhookUrl"`
+	WebhookSecret string `json:"webhookSecret"`
+	SyslogAddr    string `json:"syslogAddr"`   // udp://host:514、tcp://host:601 或 tls://host:6514
+	KafkaRESTURL  string `json:"kafkaRestUrl"` // Kafka REST Proxy 地址
+	KafkaTopic    string `json:"kafkaTopic"`
+	BufferSize    int    `json:"bufferSize"` // 每个渠道在不可用时缓冲的条数
+}
+
+// WebhookConfig 用户生命周期事件 Webhook 配置
+type WebhookConfig struct {
+	URL    string `json:"url"`
+	Secret string `json:"secret"`
+}
+
+// ApprovalNotifyConfig 审批通知渠道，未配置的渠道不启用
+type ApprovalNotifyConfig struct {
+	// DashboardURL Dashboard 对外地址，用于在通知中附带审批链接
+	DashboardURL    string     `json:"dashboardUrl"`
+	Template        string     `json:"template"` // text/template 正文模板，为空使用默认模板
+	WebhookURL      string     `json:"webhookUrl"`
+	WebhookSecret   string     `json:"webhookSecret"`
+	SlackWebhookURL string     `json:"slackWebhookUrl"`
+	SMTP            SMTPConfig `json:"smtp"`
+}
+
+// SMTPConfig 邮件通知配置，Host 为空时不发送邮件
+type SMTPConfig struct {
+	Host     string   `json:"host"`
+	Port     int      `json:"port"`
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	From     string   `json:"from"`
+	To       []string `json:"to"` // 固定收件人，另外按事件通知 admin 或申请人的邮箱
+}
+
+// AlertWebhookConfig Alertmanager Webhook 接收配置，Token 为空时不启用接收接口
+type AlertWebhookConfig struct {
+	Token string `json:"token"` // Alertmanager http_config.authorization 中配置的 Bearer 令牌
+	// Notify 是否同时将告警推送到审批通知渠道（Webhook、Slack、邮件），在线用户始终会收到实时推送
+	Notify bool `json:"notify"`
+	// PollSeconds 定期拉取默认集群 Alertmanager 告警写入告警历史的间隔，用于无法配置 Webhook 的场景，0 表示不拉取
+	PollSeconds int `json:"pollSeconds"`
+}
+
+// SessionConfig 登录会话令牌有效期
+type SessionConfig struct {
+	AccessTokenMinutes int `json:"accessTokenMinutes"` // 访问令牌（JWT）有效期
+	RefreshTokenHours  int `json:"refreshTokenHours"`  // 刷新令牌有效期，每次刷新后顺延
+}
+
+// AccessTokenTTL 访问令牌有效期
+func (s SessionConfig) AccessTokenTTL() time.Duration {
+	return time.Duration(s.AccessTokenMinutes) * time.Minute
+}
+
+// RefreshTokenTTL 刷新令牌有效期
+func (s SessionConfig) RefreshTokenTTL() time.Duration {
+	return time.Duration(s.RefreshTokenHours) * time.Hour
+}
+
+// EventHistoryConfig 事件历史采集配置
+type EventHistoryConfig struct {
+	Enabled       bool     `json:"enabled"`
+	Clusters      []string `json:"clusters"`
+	RetentionDays int      `json:"retentionDays"`
+}
+
+// Default 返回开发环境默认配置
+func Default() *Config {
+	return &Config{
+		Environment:              EnvDevelopment,
+		Port:                     "8080",
+		VictoriaMetricsURL:       DevVictoriaMetricsURL,
+		VictoriaMetricsQueryPath: metrics.QueryPathAuto,
+		AlertmanagerURL:          DevAlertmanagerURL,
+		JWTSecret:                DefaultJWTSecret,
+		MultiClusterEnabled:      true,
+		K8sUserAuth:              "dashboard",
+		Database: db.Config{
+			PostgresPort:        5432,
+			PostgresSSLMode:     "disable",
+			SQLitePath:          "./data/k8s-dashboard.db",
+			AllowSQLiteFallback: true,
+		},
+		PasswordPolicy: auth.DefaultPasswordPolicy(),
+		Session: SessionConfig{
+			AccessTokenMinutes: int(auth.DefaultAccessTokenTTL / time.Minute),
+			RefreshTokenHours:  int(auth.DefaultRefreshTokenTTL / time.Hour),
+		},
+		ApprovalNotify: ApprovalNotifyConfig{
+			SMTP: SMTPConfig{Port: 587},
+		},
+		EventHistory: EventHistoryConfig{
+			Enabled:       true,
+			Clusters:      []string{"default"},
+			RetentionDays: 14,
+		},
+		AlertRetentionDays: 90,
+		AuditForward: AuditForwardConfig{
+			BufferSize: audit.DefaultForwardBuffer,
+		},
+		AuditAnomaly:      audit.DefaultAnomalyConfig(),
+		Recommendations:   recommendations.DefaultConfig(),
+		Cost:              cost.DefaultConfig(),
+		RateLimit:         ratelimit.DefaultConfig(),
+		MetricsQuery:      promql.DefaultConfig(),
+		Metrics:           metrics.DefaultConfig(),
+		TerminalRecording: audit.DefaultTerminalRecordingConfig(),
+		WebSocket: WebSocketConfig{
+			IdleTimeout:        "15m",
+			MaxSessionDuration: "4h",
+			TimeoutWarning:     "1m",
+		},
+	}
+}
+
+// Load 解析命令行参数（-config 指定配置文件，也可用 CONFIG_FILE），依次合并配置文件与环境变量并校验
+func Load(args []string) (*Config, error) {
+	fs := flag.NewFlagSet("k8s-dashboard", flag.ContinueOnError)
+	path := fs.String("config", os.Getenv("CONFIG_FILE"), "YAML 配置文件路径")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	cfg := Default()
+	if *path != "" {
+		data, err := os.ReadFile(*path)
+		if err != nil {
+			return nil, fmt.Errorf("读取配置文件失败: %w", err)
+		}
+		if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+			return nil, fmt.Errorf("解析配置文件 %s 失败: %w", *path, err)
+		}
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+	if err := cfg.loadAlertSeverityMappingFile(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// applyEnv 用已设置的环境变量覆盖配置，未设置的保持原值
+func (c *Config) applyEnv() error {
+	var errs []error
+	envString("APP_ENV", &c.Environment)
+	envString("PORT", &c.Port)
+	envString("VICTORIA_METRICS_URL", &c.VictoriaMetricsURL)
+	envString("VICTORIA_METRICS_QUERY_PATH", &c.VictoriaMetricsQueryPath)
+	envString("ALERTMANAGER_URL", &c.AlertmanagerURL)
+	envString("JWT_SECRET", &c.JWTSecret)
+	errs = append(errs, envBool("MULTI_CLUSTER_ENABLED", &c.MultiClusterEnabled))
+	envString("K8S_USER_AUTH", &c.K8sUserAuth)
+
+	envString("POSTGRES_DSN", &c.Database.PostgresDSN)
+	envString("POSTGRES_HOST", &c.Database.PostgresHost)
+	errs = append(errs, envInt("POSTGRES_PORT", &c.Database.PostgresPort))
+	envString("POSTGRES_USER", &c.Database.PostgresUser)
+	envString("POSTGRES_PASSWORD", &c.Database.PostgresPass)
+	envString("POSTGRES_DB", &c.Database.PostgresDB)
+	envString("POSTGRES_SSLMODE", &c.Database.PostgresSSLMode)
+	envString("SQLITE_PATH", &c.Database.SQLitePath)
+	errs = append(errs, envBool("ALLOW_SQLITE_FALLBACK", &c.Database.AllowSQLiteFallback))
+
+	errs = append(errs, envInt("PASSWORD_MIN_LENGTH", &c.PasswordPolicy.MinLength))
+	errs = append(errs, envBool("PASSWORD_REQUIRE_UPPER", &c.PasswordPolicy.RequireUpper))
+	errs = append(errs, envBool("PASSWORD_REQUIRE_LOWER", &c.PasswordPolicy.RequireLower))
+	errs = append(errs, envBool("PASSWORD_REQUIRE_DIGIT", &c.PasswordPolicy.RequireDigit))
+	errs = append(errs, envBool("PASSWORD_REQUIRE_SYMBOL", &c.PasswordPolicy.RequireSymbol))
+	errs = append(errs, envInt("PASSWORD_MAX_AGE_DAYS", &c.PasswordPolicy.MaxAgeDays))
+
+	errs = append(errs, envInt("ACCESS_TOKEN_TTL_MINUTES", &c.Session.AccessTokenMinutes))
+	errs = append(errs, envInt("REFRESH_TOKEN_TTL_HOURS", &c.Session.RefreshTokenHours))
+
+	envString("USER_WEBHOOK_URL", &c.UserWebhook.URL)
+	envString("USER_WEBHOOK_SECRET", &c.UserWebhook.Secret)
+
+	envString("DASHBOARD_URL", &c.ApprovalNotify.DashboardURL)
+	envString("APPROVAL_NOTIFY_TEMPLATE", &c.ApprovalNotify.Template)
+	envString("APPROVAL_WEBHOOK_URL", &c.ApprovalNotify.WebhookURL)
+	envString("APPROVAL_WEBHOOK_SECRET", &c.ApprovalNotify.WebhookSecret)
+	envString("APPROVAL_SLACK_WEBHOOK_URL", &c.ApprovalNotify.SlackWebhookURL)
+	envString("SMTP_HOST", &c.ApprovalNotify.SMTP.Host)
+	errs = append(errs, envInt("SMTP_PORT", &c.ApprovalNotify.SMTP.Port))
+	envString("SMTP_USERNAME", &c.ApprovalNotify.SMTP.Username)
+	envString("SMTP_PASSWORD", &c.ApprovalNotify.SMTP.Password)
+	envString("SMTP_FROM", &c.ApprovalNotify.SMTP.From)
+	envList("APPROVAL_EMAIL_TO", &c.ApprovalNotify.SMTP.To)
+
+	errs = append(errs, envBool("EVENT_HISTORY_ENABLED", &c.EventHistory.Enabled))
+	envList("EVENT_HISTORY_CLUSTERS", &c.EventHistory.Clusters)
+	errs = append(errs, envInt("EVENT_RETENTION_DAYS", &c.EventHistory.RetentionDays))
+	errs = append(errs, envInt("AUDIT_RETENTION_DAYS", &c.AuditRetentionDays))
+	envString("AUDIT_ARCHIVE_DIR", &c.AuditArchiveDir)
+	envString("AUDIT_WEBHOOK_URL", &c.AuditForward.WebhookURL)
+	envString("AUDIT_WEBHOOK_SECRET", &c.AuditForward.WebhookSecret)
+	envString("AUDIT_SYSLOG_ADDR", &c.AuditForward.SyslogAddr)
+	envString("AUDIT_KAFKA_REST_URL", &c.AuditForward.KafkaRESTURL)
+	envString("AUDIT_KAFKA_TOPIC", &c.AuditForward.KafkaTopic)
+	errs = append(errs, envInt("AUDIT_FORWARD_BUFFER", &c.AuditForward.BufferSize))
+	errs = append(errs, envBool("AUDIT_ANOMALY_ENABLED", &c.AuditAnomaly.Enabled))
+	errs = append(errs, envBool("AUDIT_ANOMALY_NOTIFY", &c.AuditAnomaly.Notify))
+	errs = append(errs, envJSON("AUDIT_ANOMALY_RULES", &c.AuditAnomaly.Rules))
+	envString("AUDIT_BUSINESS_HOURS", &c.AuditAnomaly.BusinessHours)
+	envString("AUDIT_TIMEZONE", &c.AuditAnomaly.Timezone)
+	errs = append(errs, envInt("ALERT_RETENTION_DAYS", &c.AlertRetentionDays))
+	envString("ALERTMANAGER_WEBHOOK_TOKEN", &c.AlertWebhook.Token)
+	errs = append(errs, envBool("ALERTMANAGER_WEBHOOK_NOTIFY", &c.AlertWebhook.Notify))
+	errs = append(errs, envInt("ALERTMANAGER_POLL_SECONDS", &c.AlertWebhook.PollSeconds))
+	envString("RECOMMENDATION_WINDOW", &c.Recommendations.Window)
+	errs = append(errs, envInt("RECOMMENDATION_PERCENTILE", &c.Recommendations.Percentile))
+	errs = append(errs, envInt("RECOMMENDATION_HEADROOM_PERCENT", &c.Recommendations.HeadroomPercent))
+	errs = append(errs, envInt("RECOMMENDATION_TOLERANCE_PERCENT", &c.Recommendations.TolerancePercent))
+	envString("COST_CURRENCY", &c.Cost.Currency)
+	errs = append(errs, envFloat("COST_CPU_HOUR_PRICE", &c.Cost.CPUHourPrice))
+	errs = append(errs, envFloat("COST_MEMORY_GB_HOUR_PRICE", &c.Cost.MemoryGBHourPrice))
+	errs = append(errs, envJSON("COST_NODE_PRICES", &c.Cost.NodePrices))
+	envString("COST_WINDOW", &c.Cost.Window)
+	errs = append(errs, envFloat("RATE_LIMIT_USER_RPS", &c.RateLimit.UserRPS))
+	errs = append(errs, envInt("RATE_LIMIT_USER_BURST", &c.RateLimit.UserBurst))
+	errs = append(errs, envFloat("RATE_LIMIT_IP_RPS", &c.RateLimit.IPRPS))
+	errs = append(errs, envInt("RATE_LIMIT_IP_BURST", &c.RateLimit.IPBurst))
+	errs = append(errs, envInt("RATE_LIMIT_MAX_CONCURRENT_EXPENSIVE", &c.RateLimit.MaxConcurrentExpensive))
+	envList("TRUSTED_PROXIES", &c.TrustedProxies)
+	envList("METRICS_QUERY_ALLOW", &c.MetricsQuery.Allow)
+	envList("METRICS_QUERY_DENY", &c.MetricsQuery.Deny)
+	envString("METRICS_QUERY_MAX_RANGE", &c.MetricsQuery.MaxRange)
+	envString("METRICS_QUERY_MAX_LOOKBACK", &c.MetricsQuery.MaxLookback)
+	envString("METRICS_QUERY_MIN_STEP", &c.MetricsQuery.MinStep)
+	errs = append(errs, envInt("METRICS_QUERY_MAX_POINTS", &c.MetricsQuery.MaxPoints))
+	errs = append(errs, envJSON("METRICS_MAPPING", &c.MetricsMapping))
+	envString("METRICS_TIMEOUT", &c.Metrics.Timeout)
+	errs = append(errs, envInt("METRICS_RETRIES", &c.Metrics.Retries))
+	envString("METRICS_RETRY_BACKOFF", &c.Metrics.RetryBackoff)
+	errs = append(errs, envInt("METRICS_BREAKER_THRESHOLD", &c.Metrics.BreakerThreshold))
+	envString("METRICS_BREAKER_COOLDOWN", &c.Metrics.BreakerCooldown)
+	envString("METRICS_CACHE_TTL", &c.Metrics.CacheTTL)
+	errs = append(errs, envBool("TERMINAL_RECORDING", &c.TerminalRecording.Enabled))
+	errs = append(errs, envInt("TERMINAL_RECORDING_MAX_BYTES", &c.TerminalRecording.MaxBytes))
+	errs = append(errs, envJSON("TERMINAL_RECORDING_REDACT_PATTERNS", &c.TerminalRecording.RedactPatterns))
+	envList("WS_ALLOWED_ORIGINS", &c.WebSocket.AllowedOrigins)
+	errs = append(errs, envBool("WS_ALLOW_QUERY_TOKEN", &c.WebSocket.AllowQueryToken))
+	envString("WS_IDLE_TIMEOUT", &c.WebSocket.IdleTimeout)
+	envString("WS_MAX_SESSION_DURATION", &c.WebSocket.MaxSessionDuration)
+	envString("WS_TIMEOUT_WARNING", &c.WebSocket.TimeoutWarning)
+	envString("PACKET_CAPTURE_IMAGE", &c.PacketCaptureImage)
+	envString("NODE_POOL_LABEL", &c.NodePoolLabel)
+	envString("API_V1_SUNSET", &c.APIV1Sunset)
+	errs = append(errs, envJSON("ALERT_SEVERITY_MAPPING", &c.AlertSeverityMapping))
+	envString("ALERT_SEVERITY_MAPPING_FILE", &c.AlertSeverityMappingFile)
+	envString("CLUSTER_ENCRYPTION_KEY", &c.ClusterEncryptionKey)
+	return errors.Join(errs...)
+}
+
+// loadAlertSeverityMappingFile 未直接配置严重级别映射时从 AlertSeverityMappingFile 读取
+func (c *Config) loadAlertSeverityMappingFile() error {
+	if c.AlertSeverityMapping != nil || c.AlertSeverityMappingFile == "" {
+		return nil
+	}
+	data, err := os.ReadFile(c.AlertSeverityMappingFile)
+	if err != nil {
+		return fmt.Errorf("读取严重级别映射文件失败: %w", err)
+	}
+	mapping, err := alertmanager.ParseSeverityMapping(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", c.AlertSeverityMappingFile, err)
+	}
+	c.AlertSeverityMapping = mapping
+	return nil
+}
+
+// APIV1SunsetDate v1 接口的下线日期，未配置时返回零值
+func (c *Config) APIV1SunsetDate() time.Time {
+	sunset, _ := time.Parse(time.DateOnly, c.APIV1Sunset)
+	return sunset
+}
+
+// IsProduction 是否为生产环境
+func (c *Config) IsProduction() bool {
+	return c.Environment == EnvProduction
+}
+
+// Validate 校验配置，生产环境额外拒绝默认密钥、开发用地址与未加密的数据库连接
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.Environment != EnvDevelopment && c.Environment != EnvProduction {
+		errs = append(errs, fmt.Errorf("APP_ENV 必须为 %s 或 %s", EnvDevelopment, EnvProduction))
+	}
+	if port, err := strconv.Atoi(c.Port); err != nil || port < 1 || port > 65535 {
+		errs = append(errs, fmt.Errorf("PORT 无效: %q", c.Port))
+	}
+	if c.JWTSecret == "" {
+		errs = append(errs, errors.New("JWT_SECRET 不能为空"))
+	}
+	for key, raw := range map[string]string{"VICTORIA_METRICS_URL": c.VictoriaMetricsURL, "ALERTMANAGER_URL": c.AlertmanagerURL} {
+		if u, err := url.Parse(raw); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("%s 无效: %q", key, raw))
+		}
+	}
+	if err := metrics.ValidateQueryPath(c.VictoriaMetricsQueryPath); err != nil {
+		errs = append(errs, fmt.Errorf("VICTORIA_METRICS_QUERY_PATH %w", err))
+	}
+	switch c.K8sUserAuth {
+	case "dashboard", "token", "impersonate":
+	default:
+		errs = append(errs, fmt.Errorf("K8S_USER_AUTH 必须为 dashboard、token 或 impersonate: %q", c.K8sUserAuth))
+	}
+	if c.Database.PostgresPort < 1 || c.Database.PostgresPort > 65535 {
+		errs = append(errs, fmt.Errorf("POSTGRES_PORT 无效: %d", c.Database.PostgresPort))
+	}
+	if c.PasswordPolicy.MinLength < 6 || c.PasswordPolicy.MinLength > 128 {
+		errs = append(errs, fmt.Errorf("PASSWORD_MIN_LENGTH 必须在 6-128 之间: %d", c.PasswordPolicy.MinLength))
+	}
+	if c.PasswordPolicy.MaxAgeDays < 0 {
+		errs = append(errs, errors.New("PASSWORD_MAX_AGE_DAYS 不能为负数"))
+	}
+	if c.Session.AccessTokenMinutes < 1 {
+		errs = append(errs, fmt.Errorf("ACCESS_TOKEN_TTL_MINUTES 必须大于 0: %d", c.Session.AccessTokenMinutes))
+	}
+	if c.Session.RefreshTokenHours < 1 {
+		errs = append(errs, fmt.Errorf("REFRESH_TOKEN_TTL_HOURS 必须大于 0: %d", c.Session.RefreshTokenHours))
+	} else if c.Session.AccessTokenMinutes > c.Session.RefreshTokenHours*60 {
+		errs = append(errs, errors.New("ACCESS_TOKEN_TTL_MINUTES 不能超过刷新令牌有效期"))
+	}
+	if p := c.AlertWebhook.PollSeconds; p != 0 && (p < 10 || p > 3600) {
+		errs = append(errs, fmt.Errorf("ALERTMANAGER_POLL_SECONDS 必须为 0 或 10-3600 之间: %d", p))
+	}
+	notifyURLs := map[string]string{
+		"DASHBOARD_URL":              c.ApprovalNotify.DashboardURL,
+		"APPROVAL_WEBHOOK_URL":       c.ApprovalNotify.WebhookURL,
+		"APPROVAL_SLACK_WEBHOOK_URL": c.ApprovalNotify.SlackWebhookURL,
+		"AUDIT_WEBHOOK_URL":          c.AuditForward.WebhookURL,
+		"AUDIT_KAFKA_REST_URL":       c.AuditForward.KafkaRESTURL,
+	}
+	for key, raw := range notifyURLs {
+		if raw == "" {
+			continue
+		}
+		if u, err := url.Parse(raw); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("%s 无效: %q", key, raw))
+		}
+	}
+	if smtp := c.ApprovalNotify.SMTP; smtp.Host != "" {
+		if smtp.Port < 1 || smtp.Port > 65535 {
+			errs = append(errs, fmt.Errorf("SMTP_PORT 无效: %d", smtp.Port))
+		}
+		if smtp.From == "" {
+			errs = append(errs, errors.New("启用 SMTP_HOST 时必须设置 SMTP_FROM"))
+		}
+	}
+	if fwd := c.AuditForward; fwd.SyslogAddr != "" {
+		if u, err := url.Parse(fwd.SyslogAddr); err != nil || (u.Scheme != "udp" && u.Scheme != "tcp" && u.Scheme != "tls") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("AUDIT_SYSLOG_ADDR 需为 udp://、tcp:// 或 tls:// 地址: %q", fwd.SyslogAddr))
+		}
+	}
+	if c.AuditForward.KafkaRESTURL != "" && c.AuditForward.KafkaTopic == "" {
+		errs = append(errs, errors.New("启用 AUDIT_KAFKA_REST_URL 时必须设置 AUDIT_KAFKA_TOPIC"))
+	}
+	if c.AuditForward.BufferSize < 1 {
+		errs = append(errs, fmt.Errorf("AUDIT_FORWARD_BUFFER 必须大于 0: %d", c.AuditForward.BufferSize))
+	}
+	if c.AuditAnomaly.Enabled {
+		if err := c.AuditAnomaly.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := c.Recommendations.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.Cost.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.RateLimit.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	for _, proxy := range c.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			errs = append(errs, fmt.Errorf("TRUSTED_PROXIES 需为 IP 或 CIDR: %q", proxy))
+		}
+	}
+	if err := c.MetricsQuery.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.MetricsMapping.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.Metrics.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if c.APIV1Sunset != "" {
+		if _, err := time.Parse(time.DateOnly, c.APIV1Sunset); err != nil {
+			errs = append(errs, fmt.Errorf("API_V1_SUNSET 格式应为 YYYY-MM-DD: %q", c.APIV1Sunset))
+		}
+	}
+	if err := c.TerminalRecording.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if _, _, _, err := c.WebSocket.SessionLimits(); err != nil {
+		errs = append(errs, err)
+	}
+	if c.AlertSeverityMapping != nil {
+		if err := c.AlertSeverityMapping.Normalize(); err != nil {
+			errs = append(errs, fmt.Errorf("ALERT_SEVERITY_MAPPING %w", err))
+		}
+	}
+	if c.ClusterEncryptionKey != "" {
+		if err := clusters.ValidateEncryptionKey(c.ClusterEncryptionKey); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if c.AuditRetentionDays < 0 || c.AlertRetentionDays < 0 || c.EventHistory.RetentionDays < 0 {
+		errs = append(errs, errors.New("保留天数不能为负数"))
+	}
+	if c.EventHistory.Enabled && len(c.EventHistory.Clusters) == 0 {
+		errs = append(errs, errors.New("EVENT_HISTORY_CLUSTERS 不能为空"))
+	}
+
+	if c.IsProduction() {
+		for _, insecure := range insecureJWTSecrets {
+			if c.JWTSecret == insecure {
+				errs = append(errs, errors.New("生产环境必须设置 JWT_SECRET，不能使用默认值或示例值"))
+				break
+			}
+		}
+		if len(c.JWTSecret) < minProductionSecretLength {
+			errs = append(errs, fmt.Errorf("生产环境 JWT_SECRET 长度不能少于 %d", minProductionSecretLength))
+		}
+		if c.VictoriaMetricsURL == DevVictoriaMetricsURL {
+			errs = append(errs, errors.New("生产环境必须显式设置 VICTORIA_METRICS_URL"))
+		}
+		if c.AlertmanagerURL == DevAlertmanagerURL {
+			errs = append(errs, errors.New("生产环境必须显式设置 ALERTMANAGER_URL"))
+		}
+		usesPostgres := c.Database.PostgresDSN != "" || c.Database.PostgresHost != ""
+		if usesPostgres && c.Database.PostgresDSN == "" && c.Database.PostgresSSLMode == "disable" {
+			errs = append(errs, errors.New("生产环境 PostgreSQL 不能使用 POSTGRES_SSLMODE=disable"))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func envString(key string, dst *string) {
+	if v, ok := os.LookupEnv(key); ok {
+		if v = strings.TrimSpace(v); v != "" {
+			*dst = v
+		}
+	}
+}
+
+func envBool(key string, dst *bool) error {
+	v := strings.TrimSpace(strings.ToLower(os.Getenv(key)))
+	switch v {
+	case "":
+	case "1", "true", "yes", "on":
+		*dst = true
+	case "0", "false", "no", "off":
+		*dst = false
+	default:
+		return fmt.Errorf("%s 无效: %q", key, v)
+	}
+	return nil
+}
+
+func envInt(key string, dst *int) error {
+	v := strings.TrimSpace(os.Getenv(key))
+	if v == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("%s 无效: %q", key, v)
+	}
+	*dst = n
+	return nil
+}
+
+func envFloat(key string, dst *float64) error {
+	v := strings.TrimSpace(os.Getenv(key))
+	if v == "" {
+		return nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return fmt.Errorf("%s 无效: %q", key, v)
+	}
+	*dst = f
+	return nil
+}
+
+// envJSON 解析 JSON 格式的环境变量，整体替换原值（不与配置文件中的值合并）
+func envJSON[T any](key string, dst *T) error {
+	v := strings.TrimSpace(os.Getenv(key))
+	if v == "" {
+		return nil
+	}
+	var parsed T
+	if err := json.Unmarshal([]byte(v), &parsed); err != nil {
+		return fmt.Errorf("%s 不是有效的 JSON: %w", key, err)
+	}
+	*dst = parsed
+	return nil
+}
+
+func envList(key string, dst *[]string) {
+	var items []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			items = append(items, v)
+		}
+	}
+	if len(items) > 0 {
+		*dst = items
+	}
+}
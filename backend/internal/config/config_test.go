@@ -0,0 +1,492 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadDefaultsInDevelopment(t *testing.T) {
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if cfg.Environment != EnvDevelopment || cfg.Port != "8080" || cfg.JWTSecret != DefaultJWTSecret {
+		t.Fatalf("unexpected defaults: %+v", cfg)
+	}
+}
+
+func TestLoadFileThenEnvOverride(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "port: \"9090\"\nalertmanagerUrl: http://am:9093\ndatabase:\n  sqlitePath: /tmp/file.db\neventHistory:\n  clusters: [a, b]\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PORT", "7070")
+
+	cfg, err := Load([]string{"-config", path})
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if cfg.Port != "7070" {
+		t.Fatalf("expected env to override file port, got %q", cfg.Port)
+	}
+	if cfg.AlertmanagerURL != "http://am:9093" || cfg.Database.SQLitePath != "/tmp/file.db" {
+		t.Fatalf("file values not applied: %+v", cfg)
+	}
+	if strings.Join(cfg.EventHistory.Clusters, ",") != "a,b" {
+		t.Fatalf("unexpected clusters: %v", cfg.EventHistory.Clusters)
+	}
+}
+
+func TestLoadRejectsUnknownFileKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("jwtSecrte: typo\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load([]string{"-config", path}); err == nil {
+		t.Fatal("expected unknown key to be rejected")
+	}
+}
+
+func TestProductionRejectsInsecureDefaults(t *testing.T) {
+	t.Setenv("APP_ENV", EnvProduction)
+	t.Setenv("POSTGRES_HOST", "db")
+
+	_, err := Load(nil)
+	if err == nil {
+		t.Fatal("expected production defaults to be rejected")
+	}
+	for _, want := range []string{"JWT_SECRET", "VICTORIA_METRICS_URL", "ALERTMANAGER_URL", "POSTGRES_SSLMODE"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected error to mention %s, got %v", want, err)
+		}
+	}
+
+	t.Setenv("JWT_SECRET", strings.Repeat("s", 32))
+	t.Setenv("VICTORIA_METRICS_URL", "http://vm:8428")
+	t.Setenv("ALERTMANAGER_URL", "http://am:9093")
+	t.Setenv("POSTGRES_SSLMODE", "require")
+	if _, err := Load(nil); err != nil {
+		t.Fatalf("expected explicit production config to pass, got %v", err)
+	}
+}
+
+func TestLoadRejectsInvalidEnvValues(t *testing.T) {
+	t.Setenv("AUDIT_RETENTION_DAYS", "abc")
+	if _, err := Load(nil); err == nil {
+		t.Fatal("expected invalid integer to be rejected")
+	}
+}
+
+func TestLoadVictoriaMetricsQueryPath(t *testing.T) {
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if cfg.VictoriaMetricsQueryPath != "auto" {
+		t.Fatalf("unexpected default query path: %q", cfg.VictoriaMetricsQueryPath)
+	}
+
+	t.Setenv("VICTORIA_METRICS_QUERY_PATH", "/select/0/prometheus")
+	if cfg, err = Load(nil); err != nil || cfg.VictoriaMetricsQueryPath != "/select/0/prometheus" {
+		t.Fatalf("Load() = %+v, %v", cfg, err)
+	}
+
+	t.Setenv("VICTORIA_METRICS_QUERY_PATH", "select/0")
+	if _, err := Load(nil); err == nil || !strings.Contains(err.Error(), "VICTORIA_METRICS_QUERY_PATH") {
+		t.Fatalf("expected relative query path to be rejected, got %v", err)
+	}
+}
+
+func TestLoadRejectsUnknownK8sUserAuth(t *testing.T) {
+	t.Setenv("K8S_USER_AUTH", "impersonate")
+	cfg, err := Load(nil)
+	if err != nil || cfg.K8sUserAuth != "impersonate" {
+		t.Fatalf("expected impersonate mode, got %v %v", cfg, err)
+	}
+
+	t.Setenv("K8S_USER_AUTH", "oidc")
+	if _, err := Load(nil); err == nil {
+		t.Fatal("expected unknown K8S_USER_AUTH to be rejected")
+	}
+}
+
+func TestLoadPasswordPolicyFromEnv(t *testing.T) {
+	t.Setenv("PASSWORD_MIN_LENGTH", "12")
+	t.Setenv("PASSWORD_REQUIRE_SYMBOL", "true")
+	t.Setenv("PASSWORD_MAX_AGE_DAYS", "90")
+
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if p := cfg.PasswordPolicy; p.MinLength != 12 || !p.RequireSymbol || p.MaxAgeDays != 90 {
+		t.Fatalf("unexpected password policy: %+v", p)
+	}
+
+	t.Setenv("PASSWORD_MIN_LENGTH", "3")
+	if _, err := Load(nil); err == nil {
+		t.Fatal("expected too short minimum length to be rejected")
+	}
+}
+
+func TestLoadSessionLifetimesFromEnv(t *testing.T) {
+	t.Setenv("ACCESS_TOKEN_TTL_MINUTES", "15")
+	t.Setenv("REFRESH_TOKEN_TTL_HOURS", "24")
+
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if cfg.Session.AccessTokenTTL() != 15*time.Minute || cfg.Session.RefreshTokenTTL() != 24*time.Hour {
+		t.Fatalf("unexpected session lifetimes: %+v", cfg.Session)
+	}
+
+	t.Setenv("ACCESS_TOKEN_TTL_MINUTES", "1500")
+	if _, err := Load(nil); err == nil {
+		t.Fatal("expected access token outliving refresh token to be rejected")
+	}
+}
+
+func TestLoadApprovalNotifyFromEnv(t *testing.T) {
+	t.Setenv("APPROVAL_SLACK_WEBHOOK_URL", "https://hooks.slack.com/services/T000/B000/XXX")
+	t.Setenv("SMTP_HOST", "smtp.example.com")
+	t.Setenv("SMTP_FROM", "dashboard@example.com")
+	t.Setenv("APPROVAL_EMAIL_TO", "ops@example.com,sre@example.com")
+
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	n := cfg.ApprovalNotify
+	if n.SlackWebhookURL == "" || n.SMTP.Port != 587 || len(n.SMTP.To) != 2 {
+		t.Fatalf("unexpected approval notify config: %+v", n)
+	}
+
+	t.Setenv("SMTP_FROM", "")
+	t.Setenv("APPROVAL_WEBHOOK_URL", "ftp://example.com/hook")
+	_, err = Load(nil)
+	if err == nil || !strings.Contains(err.Error(), "APPROVAL_WEBHOOK_URL") {
+		t.Fatalf("expected invalid webhook URL to be rejected, got %v", err)
+	}
+}
+
+func TestLoadAuditAnomalyFromEnv(t *testing.T) {
+	t.Setenv("AUDIT_ANOMALY_RULES", `[{"name":"bulk-scale","severity":"warning","actions":["PUT"],"threshold":10,"window":"10m"}]`)
+	t.Setenv("AUDIT_BUSINESS_HOURS", "08:30-20:00")
+	t.Setenv("AUDIT_TIMEZONE", "Asia/Shanghai")
+
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	a := cfg.AuditAnomaly
+	if !a.Enabled || len(a.Rules) != 1 || a.Rules[0].Name != "bulk-scale" || a.BusinessHours != "08:30-20:00" {
+		t.Fatalf("unexpected audit anomaly config: %+v", a)
+	}
+
+	t.Setenv("AUDIT_ANOMALY_RULES", `[{"name":"bulk-scale","severity":"warning","threshold":10}]`)
+	if _, err := Load(nil); err == nil || !strings.Contains(err.Error(), "window") {
+		t.Fatalf("expected threshold without window to be rejected, got %v", err)
+	}
+	t.Setenv("AUDIT_ANOMALY_RULES", `{not json`)
+	if _, err := Load(nil); err == nil || !strings.Contains(err.Error(), "AUDIT_ANOMALY_RULES") {
+		t.Fatalf("expected invalid JSON to be rejected, got %v", err)
+	}
+}
+
+func TestLoadAlertWebhookFromEnv(t *testing.T) {
+	t.Setenv("ALERTMANAGER_WEBHOOK_TOKEN", "am-token")
+	t.Setenv("ALERTMANAGER_WEBHOOK_NOTIFY", "true")
+
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if cfg.AlertWebhook.Token != "am-token" || !cfg.AlertWebhook.Notify || cfg.AlertWebhook.PollSeconds != 0 {
+		t.Fatalf("unexpected alert webhook config: %+v", cfg.AlertWebhook)
+	}
+
+	t.Setenv("ALERTMANAGER_POLL_SECONDS", "60")
+	if cfg, err := Load(nil); err != nil || cfg.AlertWebhook.PollSeconds != 60 {
+		t.Fatalf("expected poll interval 60, got %+v, %v", cfg, err)
+	}
+	t.Setenv("ALERTMANAGER_POLL_SECONDS", "5")
+	if _, err := Load(nil); err == nil || !strings.Contains(err.Error(), "ALERTMANAGER_POLL_SECONDS") {
+		t.Fatalf("expected too short poll interval to be rejected, got %v", err)
+	}
+	t.Setenv("ALERTMANAGER_POLL_SECONDS", "0")
+
+	t.Setenv("ALERTMANAGER_WEBHOOK_NOTIFY", "maybe")
+	if _, err := Load(nil); err == nil || !strings.Contains(err.Error(), "ALERTMANAGER_WEBHOOK_NOTIFY") {
+		t.Fatalf("expected invalid bool to be rejected, got %v", err)
+	}
+}
+
+func TestLoadRecommendationsFromEnv(t *testing.T) {
+	t.Setenv("RECOMMENDATION_WINDOW", "14d")
+	t.Setenv("RECOMMENDATION_PERCENTILE", "99")
+
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	r := cfg.Recommendations
+	if r.Window != "14d" || r.Percentile != 99 || r.HeadroomPercent != 15 {
+		t.Fatalf("unexpected recommendations config: %+v", r)
+	}
+
+	t.Setenv("RECOMMENDATION_WINDOW", "5m")
+	if _, err := Load(nil); err == nil || !strings.Contains(err.Error(), "RECOMMENDATION_WINDOW") {
+		t.Fatalf("expected minute window to be rejected, got %v", err)
+	}
+}
+
+func TestLoadCostFromEnv(t *testing.T) {
+	t.Setenv("COST_CPU_HOUR_PRICE", "0.25")
+	t.Setenv("COST_NODE_PRICES", `{"ecs.g7.xlarge":1.2}`)
+
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	c := cfg.Cost
+	if c.CPUHourPrice != 0.25 || c.NodePrices["ecs.g7.xlarge"] != 1.2 || c.Window != "7d" {
+		t.Fatalf("unexpected cost config: %+v", c)
+	}
+
+	t.Setenv("COST_MEMORY_GB_HOUR_PRICE", "-1")
+	if _, err := Load(nil); err == nil || !strings.Contains(err.Error(), "COST_MEMORY_GB_HOUR_PRICE") {
+		t.Fatalf("expected negative price to be rejected, got %v", err)
+	}
+}
+
+func TestLoadRateLimitFromEnv(t *testing.T) {
+	t.Setenv("RATE_LIMIT_USER_RPS", "5.5")
+	t.Setenv("RATE_LIMIT_MAX_CONCURRENT_EXPENSIVE", "0")
+
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	c := cfg.RateLimit
+	if c.UserRPS != 5.5 || c.UserBurst != 40 || c.IPRPS != 50 || c.MaxConcurrentExpensive != 0 {
+		t.Fatalf("unexpected rate limit config: %+v", c)
+	}
+
+	t.Setenv("RATE_LIMIT_IP_BURST", "0")
+	if _, err := Load(nil); err == nil || !strings.Contains(err.Error(), "RATE_LIMIT_IP_BURST") {
+		t.Fatalf("expected zero burst to be rejected, got %v", err)
+	}
+}
+
+func TestLoadTrustedProxiesFromEnv(t *testing.T) {
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, 192.168.1.10")
+
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if strings.Join(cfg.TrustedProxies, ",") != "10.0.0.0/8,192.168.1.10" {
+		t.Fatalf("unexpected trusted proxies: %v", cfg.TrustedProxies)
+	}
+
+	t.Setenv("TRUSTED_PROXIES", "ingress.local")
+	if _, err := Load(nil); err == nil || !strings.Contains(err.Error(), "TRUSTED_PROXIES") {
+		t.Fatalf("expected hostname to be rejected, got %v", err)
+	}
+}
+
+func TestLoadMetricsQueryFromEnv(t *testing.T) {
+	t.Setenv("METRICS_QUERY_ALLOW", "container_*, kube_*")
+	t.Setenv("METRICS_QUERY_MAX_RANGE", "24h")
+
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	c := cfg.MetricsQuery
+	if len(c.Allow) != 2 || c.Allow[1] != "kube_*" || c.MaxRange != "24h" || c.MaxLookback != "30d" || c.MaxPoints != 11000 {
+		t.Fatalf("unexpected metrics query config: %+v", c)
+	}
+
+	t.Setenv("METRICS_QUERY_MIN_STEP", "fast")
+	if _, err := Load(nil); err == nil || !strings.Contains(err.Error(), "METRICS_QUERY_MIN_STEP") {
+		t.Fatalf("expected invalid step to be rejected, got %v", err)
+	}
+}
+
+func TestLoadMetricsMappingFromEnv(t *testing.T) {
+	t.Setenv("METRICS_MAPPING", `{"metrics":{"kube_node_status_allocatable{resource=\"cpu\"}":"machine_cpu_cores"},"labels":{"pod":"pod_name"}}`)
+
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	m := cfg.MetricsMapping
+	if m.Metrics[`kube_node_status_allocatable{resource="cpu"}`] != "machine_cpu_cores" || m.Labels["pod"] != "pod_name" {
+		t.Fatalf("unexpected metrics mapping: %+v", m)
+	}
+
+	t.Setenv("METRICS_MAPPING", `{"metrics":{"sum(up)":"up"}}`)
+	if _, err := Load(nil); err == nil || !strings.Contains(err.Error(), "METRICS_MAPPING") {
+		t.Fatalf("expected invalid mapping to be rejected, got %v", err)
+	}
+}
+
+func TestLoadMetricsClientFromEnv(t *testing.T) {
+	t.Setenv("METRICS_RETRIES", "0")
+	t.Setenv("METRICS_CACHE_TTL", "0s")
+
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	c := cfg.Metrics
+	if c.Retries != 0 || c.CacheTTL != "0s" || c.Timeout != "10s" || c.BreakerThreshold != 5 {
+		t.Fatalf("unexpected metrics client config: %+v", c)
+	}
+
+	t.Setenv("METRICS_BREAKER_COOLDOWN", "0s")
+	if _, err := Load(nil); err == nil || !strings.Contains(err.Error(), "METRICS_BREAKER_COOLDOWN") {
+		t.Fatalf("expected zero cooldown to be rejected, got %v", err)
+	}
+}
+
+func TestLoadTerminalRecordingFromEnv(t *testing.T) {
+	t.Setenv("TERMINAL_RECORDING", "false")
+	t.Setenv("TERMINAL_RECORDING_REDACT_PATTERNS", `["(?i)pin=(\\d+)"]`)
+
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	c := cfg.TerminalRecording
+	if c.Enabled || c.MaxBytes != 10*1024*1024 || len(c.RedactPatterns) != 1 {
+		t.Fatalf("unexpected terminal recording config: %+v", c)
+	}
+
+	t.Setenv("TERMINAL_RECORDING_REDACT_PATTERNS", `["("]`)
+	if _, err := Load(nil); err == nil || !strings.Contains(err.Error(), "TERMINAL_RECORDING_REDACT_PATTERNS") {
+		t.Fatalf("expected invalid pattern to be rejected, got %v", err)
+	}
+}
+
+func TestLoadWebSocketFromEnv(t *testing.T) {
+	t.Setenv("WS_ALLOWED_ORIGINS", "https://a.example.com, https://b.example.com")
+	t.Setenv("WS_IDLE_TIMEOUT", "0")
+	t.Setenv("PACKET_CAPTURE_IMAGE", "registry.local/netshoot:v1")
+
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if strings.Join(cfg.WebSocket.AllowedOrigins, ",") != "https://a.example.com,https://b.example.com" || cfg.WebSocket.AllowQueryToken {
+		t.Fatalf("unexpected websocket config: %+v", cfg.WebSocket)
+	}
+	idle, maxDuration, warning, err := cfg.WebSocket.SessionLimits()
+	if err != nil || idle != 0 || maxDuration != 4*time.Hour || warning != time.Minute {
+		t.Fatalf("unexpected session limits: %v %v %v %v", idle, maxDuration, warning, err)
+	}
+	if cfg.PacketCaptureImage != "registry.local/netshoot:v1" {
+		t.Fatalf("unexpected capture image: %q", cfg.PacketCaptureImage)
+	}
+
+	t.Setenv("WS_MAX_SESSION_DURATION", "forever")
+	if _, err := Load(nil); err == nil || !strings.Contains(err.Error(), "WS_MAX_SESSION_DURATION") {
+		t.Fatalf("expected invalid duration to be rejected, got %v", err)
+	}
+}
+
+func TestLoadAlertSeverityMapping(t *testing.T) {
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if cfg.AlertSeverityMapping != nil {
+		t.Fatalf("expected default mapping to be nil, got %+v", cfg.AlertSeverityMapping)
+	}
+
+	path := filepath.Join(t.TempDir(), "severity.json")
+	if err := os.WriteFile(path, []byte(`{"label":"priority","map":{"P1":"Critical"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("ALERT_SEVERITY_MAPPING_FILE", path)
+	cfg, err = Load(nil)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if m := cfg.AlertSeverityMapping; m == nil || m.Label != "priority" || m.Map["p1"] != "critical" {
+		t.Fatalf("unexpected mapping from file: %+v", m)
+	}
+
+	// 环境变量中的映射优先于文件
+	t.Setenv("ALERT_SEVERITY_MAPPING", `{"map":{"sev1":"critical"}}`)
+	cfg, err = Load(nil)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if m := cfg.AlertSeverityMapping; m.Label != "severity" || m.Map["sev1"] != "critical" || m.Default != "warning" {
+		t.Fatalf("unexpected mapping from env: %+v", m)
+	}
+
+	t.Setenv("ALERT_SEVERITY_MAPPING", `{"map":{"sev1":"urgent"}}`)
+	if _, err := Load(nil); err == nil || !strings.Contains(err.Error(), "ALERT_SEVERITY_MAPPING") {
+		t.Fatalf("expected invalid mapping to be rejected, got %v", err)
+	}
+}
+
+func TestLoadClusterEncryptionKeyFromEnv(t *testing.T) {
+	t.Setenv("CLUSTER_ENCRYPTION_KEY", "c2hvcnQ=")
+	if _, err := Load(nil); err == nil || !strings.Contains(err.Error(), "CLUSTER_ENCRYPTION_KEY") {
+		t.Fatalf("expected short key to be rejected, got %v", err)
+	}
+
+	t.Setenv("CLUSTER_ENCRYPTION_KEY", "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=")
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if cfg.ClusterEncryptionKey == "" {
+		t.Fatal("expected encryption key to be loaded")
+	}
+}
+
+func TestLoadNodePoolLabelFromEnv(t *testing.T) {
+	t.Setenv("NODE_POOL_LABEL", " node.kubernetes.io/instance-type ")
+
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if cfg.NodePoolLabel != "node.kubernetes.io/instance-type" {
+		t.Fatalf("unexpected node pool label: %q", cfg.NodePoolLabel)
+	}
+}
+
+func TestLoadAPIV1SunsetFromEnv(t *testing.T) {
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if !cfg.APIV1SunsetDate().IsZero() {
+		t.Fatalf("expected no sunset by default, got %v", cfg.APIV1SunsetDate())
+	}
+
+	t.Setenv("API_V1_SUNSET", "2027-06-30")
+	cfg, err = Load(nil)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if got := cfg.APIV1SunsetDate(); !got.Equal(time.Date(2027, 6, 30, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected sunset: %v", got)
+	}
+
+	t.Setenv("API_V1_SUNSET", "30/06/2027")
+	if _, err := Load(nil); err == nil || !strings.Contains(err.Error(), "API_V1_SUNSET") {
+		t.Fatalf("expected invalid date to be rejected, got %v", err)
+	}
+}
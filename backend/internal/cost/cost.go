@@ -0,0 +1,255 @@
+// Package cost 基于 VictoriaMetrics 中的容器用量估算命名空间与工作负载的费用，用于内部分摊（chargeback）。
+// 计费量取 requests 与实际用量中的较大者：预留但未使用的资源同样计入费用
+package cost
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/k8s-dashboard/backend/internal/k8s"
+	"github.com/k8s-dashboard/backend/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// 报表聚合维度
+const (
+	GroupByNamespace = "namespace"
+	GroupByWorkload  = "workload"
+)
+
+const bytesPerGiB = 1024 * 1024 * 1024
+
+// Config 计价配置
+type Config struct {
+	Currency string `json:"currency"`
+	// CPUHourPrice 每核每小时价格
+	CPUHourPrice float64 `json:"cpuHourPrice"`
+	// MemoryGBHourPrice 每 GiB 内存每小时价格
+	MemoryGBHourPrice float64 `json:"memoryGbHourPrice"`
+	// NodePrices 节点实例类型（node.kubernetes.io/instance-type 标签）到每小时价格的映射。
+	// 命中的节点按该价格等比例换算 CPU 与内存单价，未命中的节点使用上面的默认单价
+	NodePrices map[string]float64 `json:"nodePrices"`
+	// Window 默认统计窗口（PromQL 时长，如 24h、7d、30d）
+	Window string `json:"window"`
+}
+
+// DefaultConfig 默认单价参考公有云按需价格，实际使用时应按账单配置
+func DefaultConfig() Config {
+	return Config{
+		Currency:          "USD",
+		CPUHourPrice:      0.031611,
+		MemoryGBHourPrice: 0.004237,
+		Window:            "7d",
+	}
+}
+
+// Validate 校验计价配置
+func (cfg Config) Validate() error {
+	var errs []error
+	if cfg.CPUHourPrice < 0 || cfg.MemoryGBHourPrice < 0 {
+		errs = append(errs, errors.New("COST_CPU_HOUR_PRICE 与 COST_MEMORY_GB_HOUR_PRICE 不能为负数"))
+	}
+	for instanceType, price := range cfg.NodePrices {
+		if instanceType == "" || price < 0 {
+			errs = append(errs, fmt.Errorf("COST_NODE_PRICES 无效: %q=%g", instanceType, price))
+		}
+	}
+	if err := metrics.ValidateWindow(cfg.Window); err != nil {
+		errs = append(errs, fmt.Errorf("COST_WINDOW %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// Item 一个命名空间或工作负载的费用。*CoreHours / *GiBHours 为计费量，Usage* 为实际用量
+type Item struct {
+	Namespace           string  `json:"namespace"`
+	WorkloadKind        string  `json:"workloadKind,omitempty"`
+	Workload            string  `json:"workload,omitempty"`
+	Pods                int     `json:"pods"`
+	CPUCoreHours        float64 `json:"cpuCoreHours"`
+	MemoryGiBHours      float64 `json:"memoryGiBHours"`
+	UsageCPUCoreHours   float64 `json:"usageCpuCoreHours"`
+	UsageMemoryGiBHours float64 `json:"usageMemoryGiBHours"`
+	CPUCost             float64 `json:"cpuCost"`
+	MemoryCost          float64 `json:"memoryCost"`
+	TotalCost           float64 `json:"totalCost"`
+	// Efficiency 实际用量费用占计费费用的比例，越低说明 requests 预留越多
+	Efficiency float64 `json:"efficiency"`
+
+	usageCost float64
+	pods      map[string]bool
+}
+
+// Report 费用报表，按总费用从高到低排序
+type Report struct {
+	Window            string  `json:"window"`
+	Currency          string  `json:"currency"`
+	CPUHourPrice      float64 `json:"cpuHourPrice"`
+	MemoryGBHourPrice float64 `json:"memoryGbHourPrice"`
+	Items             []Item  `json:"items"`
+	Total             int     `json:"total"`
+	TotalCost         float64 `json:"totalCost"`
+}
+
+// Service 费用估算服务
+type Service struct {
+	cfg Config
+}
+
+// NewService 创建费用估算服务
+func NewService(cfg Config) (*Service, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &Service{cfg: cfg}, nil
+}
+
+// Config 返回计价配置
+func (s *Service) Config() Config {
+	return s.cfg
+}
+
+// Estimate 查询 window 内的容器累计用量并按 groupBy 聚合费用。pods 提供 requests 与所在节点，
+// nodes 仅在配置了 NodePrices 时用于换算单价；window 为空时使用默认窗口
+func (s *Service) Estimate(client *metrics.Client, pods []corev1.Pod, nodes []corev1.Node, namespaces []string, window, groupBy string) (*Report, error) {
+	if window == "" {
+		window = s.cfg.Window
+	}
+	if err := metrics.ValidateWindow(window); err != nil {
+		return nil, err
+	}
+	totals, err := client.GetContainerUsageTotals(window, namespaces)
+	if err != nil {
+		return nil, err
+	}
+	report := Build(s.cfg, totals, pods, nodes, groupBy)
+	report.Window = window
+	return report, nil
+}
+
+// Build 将容器累计用量与当前 Pod 规格关联并聚合费用。窗口内已删除的 Pod 没有 requests 信息，
+// 只按实际用量计费，按工作负载聚合时以 Pod 名单独列出
+func Build(cfg Config, totals []metrics.ContainerUsageTotal, pods []corev1.Pod, nodes []corev1.Node, groupBy string) *Report {
+	podIndex := make(map[string]*corev1.Pod, len(pods))
+	for i := range pods {
+		podIndex[pods[i].Namespace+"/"+pods[i].Name] = &pods[i]
+	}
+	nodePrices := make(map[string][2]float64)
+	for i := range nodes {
+		if cpuPrice, memPrice, ok := cfg.nodeUnitPrices(&nodes[i]); ok {
+			nodePrices[nodes[i].Name] = [2]float64{cpuPrice, memPrice}
+		}
+	}
+
+	grouped := make(map[string]*Item)
+	for _, total := range totals {
+		cpuPrice, memPrice := cfg.CPUHourPrice, cfg.MemoryGBHourPrice
+		kind, workload := "Pod", total.Pod
+		var requestCores, requestGiB float64
+		if pod, ok := podIndex[total.Namespace+"/"+total.Pod]; ok {
+			kind, workload = k8s.PodWorkload(pod)
+			requestCores, requestGiB = containerRequests(pod, total.Container)
+			if prices, ok := nodePrices[pod.Spec.NodeName]; ok {
+				cpuPrice, memPrice = prices[0], prices[1]
+			}
+		}
+
+		key := total.Namespace
+		item := &Item{Namespace: total.Namespace}
+		if groupBy == GroupByWorkload {
+			key = strings.Join([]string{total.Namespace, kind, workload}, "/")
+			item.WorkloadKind, item.Workload = kind, workload
+		}
+		if existing, ok := grouped[key]; ok {
+			item = existing
+		} else {
+			item.pods = make(map[string]bool)
+			grouped[key] = item
+		}
+
+		cpuHours := math.Max(requestCores*total.Hours, total.CPUCoreHours)
+		memHours := math.Max(requestGiB*total.Hours, total.MemoryGiBHours)
+		item.pods[total.Pod] = true
+		item.CPUCoreHours += cpuHours
+		item.MemoryGiBHours += memHours
+		item.UsageCPUCoreHours += total.CPUCoreHours
+		item.UsageMemoryGiBHours += total.MemoryGiBHours
+		item.CPUCost += cpuHours * cpuPrice
+		item.MemoryCost += memHours * memPrice
+		item.usageCost += total.CPUCoreHours*cpuPrice + total.MemoryGiBHours*memPrice
+	}
+
+	report := &Report{
+		Currency:          cfg.Currency,
+		CPUHourPrice:      cfg.CPUHourPrice,
+		MemoryGBHourPrice: cfg.MemoryGBHourPrice,
+		Items:             make([]Item, 0, len(grouped)),
+	}
+	for _, item := range grouped {
+		item.Pods = len(item.pods)
+		item.TotalCost = item.CPUCost + item.MemoryCost
+		if item.TotalCost > 0 {
+			item.Efficiency = round(item.usageCost / item.TotalCost)
+		}
+		report.TotalCost += item.TotalCost
+		item.CPUCoreHours = round(item.CPUCoreHours)
+		item.MemoryGiBHours = round(item.MemoryGiBHours)
+		item.UsageCPUCoreHours = round(item.UsageCPUCoreHours)
+		item.UsageMemoryGiBHours = round(item.UsageMemoryGiBHours)
+		item.CPUCost = round(item.CPUCost)
+		item.MemoryCost = round(item.MemoryCost)
+		item.TotalCost = round(item.TotalCost)
+		report.Items = append(report.Items, *item)
+	}
+	report.Total = len(report.Items)
+	report.TotalCost = round(report.TotalCost)
+
+	sort.Slice(report.Items, func(i, j int) bool {
+		a, b := report.Items[i], report.Items[j]
+		if a.TotalCost != b.TotalCost {
+			return a.TotalCost > b.TotalCost
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Workload < b.Workload
+	})
+	return report
+}
+
+// nodeUnitPrices 按节点实例价格等比例缩放默认单价，使节点满载时的费用等于实例价格
+func (cfg Config) nodeUnitPrices(node *corev1.Node) (float64, float64, bool) {
+	price, ok := cfg.NodePrices[node.Labels[corev1.LabelInstanceTypeStable]]
+	if !ok {
+		return 0, 0, false
+	}
+	cores := float64(node.Status.Capacity.Cpu().MilliValue()) / 1000
+	gib := float64(node.Status.Capacity.Memory().Value()) / bytesPerGiB
+	base := cfg.CPUHourPrice*cores + cfg.MemoryGBHourPrice*gib
+	if base <= 0 {
+		return 0, 0, false
+	}
+	scale := price / base
+	return cfg.CPUHourPrice * scale, cfg.MemoryGBHourPrice * scale, true
+}
+
+// containerRequests 返回容器的 CPU（核）与内存（GiB）requests
+func containerRequests(pod *corev1.Pod, name string) (float64, float64) {
+	for _, container := range pod.Spec.Containers {
+		if container.Name != name {
+			continue
+		}
+		cpu := container.Resources.Requests[corev1.ResourceCPU]
+		memory := container.Resources.Requests[corev1.ResourceMemory]
+		return float64(cpu.MilliValue()) / 1000, float64(memory.Value()) / bytesPerGiB
+	}
+	return 0, 0
+}
+
+// round 保留 4 位小数
+func round(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
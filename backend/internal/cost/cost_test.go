@@ -0,0 +1,97 @@
+package cost
+
+import (
+	"testing"
+
+	"github.com/k8s-dashboard/backend/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testConfig() Config {
+	return Config{Currency: "USD", CPUHourPrice: 1, MemoryGBHourPrice: 0.5, Window: "24h"}
+}
+
+func testPod(name, namespace, node, cpu, memory string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: corev1.PodSpec{
+			NodeName: node,
+			Containers: []corev1.Container{{
+				Name: "app",
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(cpu),
+					corev1.ResourceMemory: resource.MustParse(memory),
+				}},
+			}},
+		},
+	}
+}
+
+func TestBuildChargesGreaterOfRequestsAndUsage(t *testing.T) {
+	pods := []corev1.Pod{testPod("web", "shop", "node-1", "500m", "2Gi")}
+	totals := []metrics.ContainerUsageTotal{
+		// 10 小时：CPU 用量 8 核·时高于 requests 的 5，内存用量 4 GiB·时低于 requests 的 20
+		{Namespace: "shop", Pod: "web", Container: "app", Hours: 10, CPUCoreHours: 8, MemoryGiBHours: 4},
+	}
+
+	report := Build(testConfig(), totals, pods, nil, GroupByNamespace)
+	if report.Total != 1 {
+		t.Fatalf("expected 1 item, got %+v", report.Items)
+	}
+	item := report.Items[0]
+	if item.CPUCoreHours != 8 || item.MemoryGiBHours != 20 {
+		t.Fatalf("unexpected billed usage: %+v", item)
+	}
+	if item.CPUCost != 8 || item.MemoryCost != 10 || item.TotalCost != 18 || report.TotalCost != 18 {
+		t.Fatalf("unexpected cost: %+v", item)
+	}
+	// 实际用量费用 8 + 2 = 10
+	if item.Efficiency != 0.5556 {
+		t.Fatalf("unexpected efficiency %v", item.Efficiency)
+	}
+}
+
+func TestBuildGroupsByWorkloadAndKeepsDeletedPods(t *testing.T) {
+	controller := true
+	pod := testPod("api-5c8d-x", "shop", "node-1", "1", "1Gi")
+	pod.Labels = map[string]string{"pod-template-hash": "5c8d"}
+	pod.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "api-5c8d", Controller: &controller}}
+	totals := []metrics.ContainerUsageTotal{
+		{Namespace: "shop", Pod: "api-5c8d-x", Container: "app", Hours: 1},
+		{Namespace: "shop", Pod: "api-old-y", Container: "app", Hours: 1, CPUCoreHours: 3},
+	}
+
+	report := Build(testConfig(), totals, []corev1.Pod{pod}, nil, GroupByWorkload)
+	if report.Total != 2 {
+		t.Fatalf("expected 2 items, got %+v", report.Items)
+	}
+	// 按费用排序：已删除 Pod 的 3 核·时在前
+	if report.Items[0].WorkloadKind != "Pod" || report.Items[0].Workload != "api-old-y" || report.Items[0].TotalCost != 3 {
+		t.Fatalf("unexpected first item %+v", report.Items[0])
+	}
+	if report.Items[1].WorkloadKind != "Deployment" || report.Items[1].Workload != "api" || report.Items[1].TotalCost != 1.5 {
+		t.Fatalf("unexpected second item %+v", report.Items[1])
+	}
+}
+
+func TestBuildUsesNodeInstancePrice(t *testing.T) {
+	cfg := testConfig()
+	cfg.NodePrices = map[string]float64{"m5.large": 4}
+	node := corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{corev1.LabelInstanceTypeStable: "m5.large"}},
+		Status: corev1.NodeStatus{Capacity: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("2"),
+			corev1.ResourceMemory: resource.MustParse("4Gi"),
+		}},
+	}
+	pods := []corev1.Pod{testPod("web", "shop", "node-1", "2", "4Gi")}
+	totals := []metrics.ContainerUsageTotal{{Namespace: "shop", Pod: "web", Container: "app", Hours: 1}}
+
+	// 占满整个节点一小时，费用等于实例价格
+	report := Build(cfg, totals, pods, []corev1.Node{node}, GroupByNamespace)
+	if report.TotalCost != 4 {
+		t.Fatalf("expected node price 4, got %+v", report.Items)
+	}
+}
@@ -6,8 +6,6 @@ import (
 	"log"
 	"os"
 	"path/filepath"
-	"strconv"
-	"strings"
 	"time"
 
 	_ "github.com/lib/pq"
@@ -24,43 +22,16 @@ const (
 
 // Config 数据库配置
 type Config struct {
-	PostgresDSN     string
-	PostgresHost    string
-	PostgresPort    int
-	PostgresUser    string
-	PostgresPass    string
-	PostgresDB      string
-	PostgresSSLMode string
-
-	SQLitePath          string
-	AllowSQLiteFallback bool
-}
-
-// LoadConfigFromEnv 从环境变量加载配置
-func LoadConfigFromEnv() Config {
-	port := 5432
-	if portStr := strings.TrimSpace(os.Getenv("POSTGRES_PORT")); portStr != "" {
-		if p, err := strconv.Atoi(portStr); err == nil && p > 0 {
-			port = p
-		}
-	}
-
-	return Config{
-		PostgresDSN:         strings.TrimSpace(os.Getenv("POSTGRES_DSN")),
-		PostgresHost:        strings.TrimSpace(os.Getenv("POSTGRES_HOST")),
-		PostgresPort:        port,
-		PostgresUser:        strings.TrimSpace(os.Getenv("POSTGRES_USER")),
-		PostgresPass:        strings.TrimSpace(os.Getenv("POSTGRES_PASSWORD")),
-		PostgresDB:          strings.TrimSpace(os.Getenv("POSTGRES_DB")),
-		PostgresSSLMode:     defaultString(strings.TrimSpace(os.Getenv("POSTGRES_SSLMODE")), "disable"),
-		SQLitePath:          defaultString(strings.TrimSpace(os.Getenv("SQLITE_PATH")), "./data/k8s-dashboard.db"),
-		AllowSQLiteFallback: parseBoolEnv("ALLOW_SQLITE_FALLBACK", true),
-	}
-}
-
-// OpenFromEnv 根据环境变量打开数据库连接并返回最终方言
-func OpenFromEnv() (*sql.DB, Dialect, error) {
-	return Open(LoadConfigFromEnv())
+	PostgresDSN     string `json:"postgresDsn"`
+	PostgresHost    string `json:"postgresHost"`
+	PostgresPort    int    `json:"postgresPort"`
+	PostgresUser    string `json:"postgresUser"`
+	PostgresPass    string `json:"postgresPassword"`
+	PostgresDB      string `json:"postgresDb"`
+	PostgresSSLMode string `json:"postgresSslMode"`
+
+	SQLitePath          string `json:"sqlitePath"`
+	AllowSQLiteFallback bool   `json:"allowSqliteFallback"`
 }
 
 // Open 按优先级选择数据库:
@@ -166,25 +137,3 @@ func openSQLite(path string) (*sql.DB, error) {
 	}
 	return db, nil
 }
-
-func parseBoolEnv(key string, def bool) bool {
-	v := strings.TrimSpace(strings.ToLower(os.Getenv(key)))
-	if v == "" {
-		return def
-	}
-	switch v {
-	case "1", "true", "yes", "on":
-		return true
-	case "0", "false", "no", "off":
-		return false
-	default:
-		return def
-	}
-}
-
-func defaultString(v, def string) string {
-	if v == "" {
-		return def
-	}
-	return v
-}
@@ -0,0 +1,130 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Execer 可执行语句的连接或事务
+type Execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// MonthStart 返回 t 所在月份的第一天（UTC）
+func MonthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// PartitionName 月分区表名，如 audit_logs_y2026m01
+func PartitionName(table string, month time.Time) string {
+	month = MonthStart(month)
+	return fmt.Sprintf("%s_y%04dm%02d", table, month.Year(), int(month.Month()))
+}
+
+// parsePartitionMonth 从月分区表名解析月份，非月分区返回 false
+func parsePartitionMonth(table, name string) (time.Time, bool) {
+	suffix := strings.TrimPrefix(name, table+"_")
+	if suffix == name {
+		return time.Time{}, false
+	}
+	var year, month int
+	if n, err := fmt.Sscanf(suffix, "y%4dm%2d", &year, &month); err != nil || n != 2 {
+		return time.Time{}, false
+	}
+	if month < 1 || month > 12 || PartitionName(table, time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)) != name {
+		return time.Time{}, false
+	}
+	return time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC), true
+}
+
+// IsPartitioned 判断 Postgres 表是否为原生分区表
+func IsPartitioned(conn *sql.DB, table string) (bool, error) {
+	var count int
+	err := conn.QueryRow(`
+		SELECT COUNT(*) FROM pg_partitioned_table pt
+		JOIN pg_class c ON c.oid = pt.partrelid
+		WHERE c.relname = $1 AND pg_table_is_visible(c.oid)
+	`, table).Scan(&count)
+	return count > 0, err
+}
+
+// TableExists 判断 Postgres 表是否存在
+func TableExists(conn *sql.DB, table string) (bool, error) {
+	var name sql.NullString
+	if err := conn.QueryRow("SELECT to_regclass($1)::text", table).Scan(&name); err != nil {
+		return false, err
+	}
+	return name.Valid, nil
+}
+
+// EnsureMonthlyPartitions 为分区表创建从 from 所在月起共 months 个月的分区（已存在则跳过）
+func EnsureMonthlyPartitions(conn Execer, table string, from time.Time, months int) error {
+	start := MonthStart(from)
+	for i := 0; i < months; i++ {
+		lower := start.AddDate(0, i, 0)
+		upper := lower.AddDate(0, 1, 0)
+		stmt := fmt.Sprintf(
+			`CREATE TABLE IF NOT EXISTS %s PARTITION OF %s FOR VALUES FROM ('%s') TO ('%s')`,
+			PartitionName(table, lower), table,
+			lower.Format("2006-01-02")+" 00:00:00+00", upper.Format("2006-01-02")+" 00:00:00+00",
+		)
+		if _, err := conn.Exec(stmt); err != nil {
+			return fmt.Errorf("创建分区 %s 失败: %w", PartitionName(table, lower), err)
+		}
+	}
+	return nil
+}
+
+// ListPartitions 列出分区表的全部子分区
+func ListPartitions(conn *sql.DB, table string) ([]string, error) {
+	rows, err := conn.Query(`
+		SELECT c.relname FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		JOIN pg_class p ON p.oid = i.inhparent
+		WHERE p.relname = $1 AND pg_table_is_visible(p.oid)
+		ORDER BY c.relname
+	`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// DropPartitionsBefore 按保留策略清理分区：整月早于 cutoff 的月分区直接 DROP，
+// 其他分区（默认分区、迁移前的历史表）按 column 删除过期行。返回被删除的分区名。
+func DropPartitionsBefore(conn *sql.DB, table, column string, cutoff time.Time) ([]string, error) {
+	partitions, err := ListPartitions(conn, table)
+	if err != nil {
+		return nil, err
+	}
+
+	var dropped []string
+	for _, name := range partitions {
+		if month, ok := parsePartitionMonth(table, name); ok {
+			if !month.AddDate(0, 1, 0).After(cutoff) {
+				if _, err := conn.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", name)); err != nil {
+					return dropped, fmt.Errorf("删除分区 %s 失败: %w", name, err)
+				}
+				dropped = append(dropped, name)
+			}
+			continue
+		}
+		if _, err := conn.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s < $1", name, column), cutoff); err != nil {
+			return dropped, fmt.Errorf("清理分区 %s 失败: %w", name, err)
+		}
+	}
+	return dropped, nil
+}
@@ -0,0 +1,29 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPartitionNameRoundTrip(t *testing.T) {
+	month := time.Date(2026, time.February, 17, 13, 0, 0, 0, time.UTC)
+
+	name := PartitionName("audit_logs", month)
+	if name != "audit_logs_y2026m02" {
+		t.Fatalf("unexpected partition name: %s", name)
+	}
+
+	parsed, ok := parsePartitionMonth("audit_logs", name)
+	if !ok {
+		t.Fatalf("expected %s to parse as monthly partition", name)
+	}
+	if !parsed.Equal(MonthStart(month)) {
+		t.Fatalf("expected %v, got %v", MonthStart(month), parsed)
+	}
+
+	for _, other := range []string{"audit_logs_default", "audit_logs_legacy", "audit_logs_y2026m13", "other_y2026m02"} {
+		if _, ok := parsePartitionMonth("audit_logs", other); ok {
+			t.Fatalf("expected %s not to parse as monthly partition", other)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// EnsureColumn 为已存在的表补充新增列（旧版本数据库升级用），列已存在时不做修改。
+// definition 为列类型及约束，如 "TEXT" 或"INTEGER NOT NULL DEFAULT 1"。
+func EnsureColumn(conn *sql.DB, dialect Dialect, table, column, definition string) error {
+	if dialect != DialectSQLite {
+		_, err := conn.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s", table, column, definition))
+		return err
+	}
+
+	exists, err := sqliteColumnExists(conn, table, column)
+	if err != nil || exists {
+		return err
+	}
+	_, err = conn.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err
+}
+
+func sqliteColumnExists(conn *sql.DB, table, column string) (bool, error) {
+	rows, err := conn.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid       int
+			name      string
+			colType   string
+			notNull   int
+			dfltValue sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
@@ -0,0 +1,120 @@
+package eventstore
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/k8s-dashboard/backend/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+const (
+	collectorMinBackoff = 5 * time.Second
+	collectorMaxBackoff = 2 * time.Minute
+)
+
+// ClientResolver 按集群名称获取 Kubernetes 客户端
+type ClientResolver func(cluster string) (*k8s.Client, error)
+
+// Collector 监听各集群的 Event 并写入事件历史，watch 中断后自动重连
+type Collector struct {
+	repo     *Repository
+	resolve  ClientResolver
+	clusters []string
+}
+
+// NewCollector 创建事件采集器
+func NewCollector(repo *Repository, resolve ClientResolver, clusters []string) *Collector {
+	return &Collector{repo: repo, resolve: resolve, clusters: clusters}
+}
+
+// Run 为每个集群启动采集协程，ctx 取消后退出
+func (c *Collector) Run(ctx context.Context) {
+	for _, cluster := range c.clusters {
+		go c.runCluster(ctx, cluster)
+	}
+}
+
+func (c *Collector) runCluster(ctx context.Context, cluster string) {
+	backoff := collectorMinBackoff
+	for {
+		start := time.Now()
+		err := c.collect(ctx, cluster)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Printf("Warning: 集群 %s 事件采集中断: %v", cluster, err)
+		}
+		// 稳定运行一段时间后重置退避
+		if time.Since(start) > collectorMaxBackoff {
+			backoff = collectorMinBackoff
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > collectorMaxBackoff {
+			backoff = collectorMaxBackoff
+		}
+	}
+}
+
+// collect 先全量写入现存事件，再从列表版本开始 watch；resourceVersion 过期时返回由外层重新全量同步
+func (c *Collector) collect(ctx context.Context, cluster string) error {
+	client, err := c.resolve(cluster)
+	if err != nil {
+		return err
+	}
+	events := client.Clientset.CoreV1().Events("")
+
+	list, err := events.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range list.Items {
+		c.save(cluster, &list.Items[i])
+	}
+
+	resourceVersion := list.ResourceVersion
+	for {
+		w, err := events.Watch(ctx, metav1.ListOptions{
+			ResourceVersion:     resourceVersion,
+			AllowWatchBookmarks: true,
+		})
+		if err != nil {
+			return err
+		}
+
+		for ev := range w.ResultChan() {
+			switch ev.Type {
+			case watch.Error:
+				w.Stop()
+				return apierrors.FromObject(ev.Object)
+			case watch.Bookmark:
+				if event, ok := ev.Object.(*corev1.Event); ok {
+					resourceVersion = event.ResourceVersion
+				}
+			case watch.Added, watch.Modified:
+				if event, ok := ev.Object.(*corev1.Event); ok {
+					resourceVersion = event.ResourceVersion
+					c.save(cluster, event)
+				}
+			}
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+func (c *Collector) save(cluster string, event *corev1.Event) {
+	if err := c.repo.Upsert(RecordFromEvent(cluster, event)); err != nil {
+		log.Printf("Warning: 保存事件 %s/%s 失败: %v", event.Namespace, event.Name, err)
+	}
+}
@@ -0,0 +1,301 @@
+package eventstore
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	dbutil "github.com/k8s-dashboard/backend/internal/db"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Record 持久化的集群事件，同一事件（cluster + uid）重复发生时更新计数与最后时间
+type Record struct {
+	ID                int64     `json:"id"`
+	Cluster           string    `json:"cluster"`
+	UID               string    `json:"uid"`
+	Namespace         string    `json:"namespace"`
+	Name              string    `json:"name"`
+	Type              string    `json:"type"`
+	Reason            string    `json:"reason"`
+	Message           string    `json:"message"`
+	InvolvedKind      string    `json:"involvedKind"`
+	InvolvedName      string    `json:"involvedName"`
+	InvolvedNamespace string    `json:"involvedNamespace"`
+	Source            string    `json:"source"`
+	Count             int32     `json:"count"`
+	FirstTimestamp    time.Time `json:"firstTimestamp"`
+	LastTimestamp     time.Time `json:"lastTimestamp"`
+}
+
+// QueryParams 历史事件查询参数
+type QueryParams struct {
+	Cluster    string
+	Namespace  string
+	Namespaces []string // 非空时仅返回这些命名空间的事件（权限范围）
+	Type       string
+	Reason     string
+	Kind       string
+	Name       string // 涉及对象名称
+	Search     string // 在 message、reason、对象名称中模糊匹配
+	Since      time.Time
+	Until      time.Time
+	Page       int
+	PageSize   int
+}
+
+// Repository 事件历史数据仓库
+type Repository struct {
+	db      *sql.DB
+	dialect dbutil.Dialect
+}
+
+// NewRepository 创建事件历史数据仓库
+func NewRepository(db *sql.DB, dialect dbutil.Dialect) (*Repository, error) {
+	repo := &Repository{
+		db:      db,
+		dialect: dialect,
+	}
+
+	if err := repo.initSchema(); err != nil {
+		return nil, fmt.Errorf("初始化表结构失败: %w", err)
+	}
+
+	return repo, nil
+}
+
+// initSchema 初始化表结构
+func (r *Repository) initSchema() error {
+	var schema string
+	if r.dialect == dbutil.DialectSQLite {
+		schema = `
+		CREATE TABLE IF NOT EXISTS k8s_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			cluster TEXT NOT NULL DEFAULT 'default',
+			uid TEXT NOT NULL,
+			namespace TEXT,
+			name TEXT,
+			type TEXT,
+			reason TEXT,
+			message TEXT,
+			involved_kind TEXT,
+			involved_name TEXT,
+			involved_namespace TEXT,
+			source TEXT,
+			count INTEGER DEFAULT 1,
+			first_timestamp DATETIME NOT NULL,
+			last_timestamp DATETIME NOT NULL,
+			UNIQUE (cluster, uid)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_k8s_events_last_timestamp ON k8s_events(last_timestamp DESC);
+		CREATE INDEX IF NOT EXISTS idx_k8s_events_namespace ON k8s_events(cluster, namespace);
+		CREATE INDEX IF NOT EXISTS idx_k8s_events_involved ON k8s_events(involved_kind, involved_name);
+		`
+	} else {
+		schema = `
+		CREATE TABLE IF NOT EXISTS k8s_events (
+			id BIGSERIAL PRIMARY KEY,
+			cluster VARCHAR(100) NOT NULL DEFAULT 'default',
+			uid VARCHAR(64) NOT NULL,
+			namespace VARCHAR(255),
+			name VARCHAR(255),
+			type VARCHAR(20),
+			reason VARCHAR(255),
+			message TEXT,
+			involved_kind VARCHAR(100),
+			involved_name VARCHAR(255),
+			involved_namespace VARCHAR(255),
+			source VARCHAR(255),
+			count INTEGER DEFAULT 1,
+			first_timestamp TIMESTAMP WITH TIME ZONE NOT NULL,
+			last_timestamp TIMESTAMP WITH TIME ZONE NOT NULL,
+			UNIQUE (cluster, uid)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_k8s_events_last_timestamp ON k8s_events(last_timestamp DESC);
+		CREATE INDEX IF NOT EXISTS idx_k8s_events_namespace ON k8s_events(cluster, namespace);
+		CREATE INDEX IF NOT EXISTS idx_k8s_events_involved ON k8s_events(involved_kind, involved_name);
+		`
+	}
+
+	_, err := r.db.Exec(schema)
+	return err
+}
+
+// RecordFromEvent 将 Kubernetes Event 转换为持久化记录
+func RecordFromEvent(cluster string, event *corev1.Event) Record {
+	last := event.LastTimestamp.Time
+	if last.IsZero() {
+		last = event.EventTime.Time
+	}
+	if last.IsZero() {
+		last = event.CreationTimestamp.Time
+	}
+	first := event.FirstTimestamp.Time
+	if first.IsZero() {
+		first = last
+	}
+	count := event.Count
+	if count == 0 {
+		count = 1
+	}
+	source := event.Source.Component
+	if source == "" {
+		source = event.ReportingController
+	}
+	if event.Source.Host != "" {
+		source += "@" + event.Source.Host
+	}
+
+	return Record{
+		Cluster:           cluster,
+		UID:               string(event.UID),
+		Namespace:         event.Namespace,
+		Name:              event.Name,
+		Type:              event.Type,
+		Reason:            event.Reason,
+		Message:           event.Message,
+		InvolvedKind:      event.InvolvedObject.Kind,
+		InvolvedName:      event.InvolvedObject.Name,
+		InvolvedNamespace: event.InvolvedObject.Namespace,
+		Source:            source,
+		Count:             count,
+		FirstTimestamp:    first,
+		LastTimestamp:     last,
+	}
+}
+
+// Upsert 写入事件，已存在时更新计数、消息与最后发生时间
+func (r *Repository) Upsert(rec Record) error {
+	_, err := r.db.Exec(`
+		INSERT INTO k8s_events (
+			cluster, uid, namespace, name, type, reason, message,
+			involved_kind, involved_name, involved_namespace, source,
+			count, first_timestamp, last_timestamp
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		ON CONFLICT (cluster, uid) DO UPDATE SET
+			type = excluded.type,
+			reason = excluded.reason,
+			message = excluded.message,
+			count = excluded.count,
+			last_timestamp = excluded.last_timestamp
+	`, rec.Cluster, rec.UID, rec.Namespace, rec.Name, rec.Type, rec.Reason, rec.Message,
+		rec.InvolvedKind, rec.InvolvedName, rec.InvolvedNamespace, rec.Source,
+		rec.Count, rec.FirstTimestamp, rec.LastTimestamp)
+	return err
+}
+
+// Query 按条件分页查询历史事件，按最后发生时间倒序
+func (r *Repository) Query(params QueryParams) ([]Record, int64, error) {
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.PageSize < 1 {
+		params.PageSize = 50
+	}
+	if params.PageSize > 500 {
+		params.PageSize = 500
+	}
+
+	where := "WHERE 1=1"
+	args := []interface{}{}
+	argIndex := 1
+	addCond := func(cond string, value interface{}) {
+		where += fmt.Sprintf(" AND "+cond, argIndex)
+		args = append(args, value)
+		argIndex++
+	}
+
+	if params.Cluster != "" {
+		addCond("cluster = $%d", params.Cluster)
+	}
+	if params.Namespace != "" {
+		addCond("namespace = $%d", params.Namespace)
+	}
+	if len(params.Namespaces) > 0 {
+		placeholders := make([]string, 0, len(params.Namespaces))
+		for _, ns := range params.Namespaces {
+			placeholders = append(placeholders, fmt.Sprintf("$%d", argIndex))
+			args = append(args, ns)
+			argIndex++
+		}
+		where += " AND namespace IN (" + strings.Join(placeholders, ", ") + ")"
+	}
+	if params.Type != "" {
+		addCond("type = $%d", params.Type)
+	}
+	if params.Reason != "" {
+		addCond("reason = $%d", params.Reason)
+	}
+	if params.Kind != "" {
+		addCond("involved_kind = $%d", params.Kind)
+	}
+	if params.Name != "" {
+		addCond("involved_name = $%d", params.Name)
+	}
+	if !params.Since.IsZero() {
+		addCond("last_timestamp >= $%d", params.Since)
+	}
+	if !params.Until.IsZero() {
+		addCond("first_timestamp <= $%d", params.Until)
+	}
+	if params.Search != "" {
+		// SQLite 的 LIKE 默认忽略 ASCII 大小写，Postgres 需使用 ILIKE
+		like := "LIKE"
+		if r.dialect != dbutil.DialectSQLite {
+			like = "ILIKE"
+		}
+		where += fmt.Sprintf(` AND (message %[1]s $%[2]d ESCAPE '\' OR reason %[1]s $%[2]d ESCAPE '\' OR involved_name %[1]s $%[2]d ESCAPE '\')`, like, argIndex)
+		args = append(args, "%"+escapeLike(params.Search)+"%")
+		argIndex++
+	}
+
+	var total int64
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM k8s_events "+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, cluster, uid, COALESCE(namespace, ''), COALESCE(name, ''), COALESCE(type, ''),
+		       COALESCE(reason, ''), COALESCE(message, ''), COALESCE(involved_kind, ''),
+		       COALESCE(involved_name, ''), COALESCE(involved_namespace, ''), COALESCE(source, ''),
+		       COALESCE(count, 1), first_timestamp, last_timestamp
+		FROM k8s_events %s
+		ORDER BY last_timestamp DESC, id DESC
+		LIMIT $%d OFFSET $%d
+	`, where, argIndex, argIndex+1)
+	args = append(args, params.PageSize, (params.Page-1)*params.PageSize)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := []Record{}
+	for rows.Next() {
+		var rec Record
+		if err := rows.Scan(&rec.ID, &rec.Cluster, &rec.UID, &rec.Namespace, &rec.Name, &rec.Type,
+			&rec.Reason, &rec.Message, &rec.InvolvedKind, &rec.InvolvedName, &rec.InvolvedNamespace,
+			&rec.Source, &rec.Count, &rec.FirstTimestamp, &rec.LastTimestamp); err != nil {
+			return nil, 0, err
+		}
+		items = append(items, rec)
+	}
+	return items, total, rows.Err()
+}
+
+// PurgeBefore 删除最后发生时间早于 before 的事件
+func (r *Repository) PurgeBefore(before time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM k8s_events WHERE last_timestamp < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
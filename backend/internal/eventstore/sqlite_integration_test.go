@@ -0,0 +1,103 @@
+package eventstore
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	dbutil "github.com/k8s-dashboard/backend/internal/db"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestSQLiteEventHistory(t *testing.T) {
+	conn, dialect, err := dbutil.Open(dbutil.Config{
+		SQLitePath:          filepath.Join(t.TempDir(), "events.db"),
+		AllowSQLiteFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer conn.Close()
+
+	repo, err := NewRepository(conn, dialect)
+	if err != nil {
+		t.Fatalf("NewRepository failed: %v", err)
+	}
+
+	now := time.Now().Truncate(time.Second)
+	backoff := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "web-0.1", Namespace: "prod", UID: types.UID("uid-1")},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web-0", Namespace: "prod"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "BackOff",
+		Message:        "Back-off restarting failed container",
+		Source:         corev1.EventSource{Component: "kubelet", Host: "node-1"},
+		Count:          1,
+		FirstTimestamp: metav1.NewTime(now.Add(-3 * time.Hour)),
+		LastTimestamp:  metav1.NewTime(now.Add(-3 * time.Hour)),
+	}
+	if err := repo.Upsert(RecordFromEvent("default", backoff)); err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+
+	// 同一事件再次发生：更新计数与最后时间，而不是新增记录
+	backoff.Count = 5
+	backoff.LastTimestamp = metav1.NewTime(now.Add(-time.Hour))
+	if err := repo.Upsert(RecordFromEvent("default", backoff)); err != nil {
+		t.Fatalf("Upsert repeat failed: %v", err)
+	}
+
+	scheduled := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "api-1.1", Namespace: "dev", UID: types.UID("uid-2")},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "api-1", Namespace: "dev"},
+		Type:           corev1.EventTypeNormal,
+		Reason:         "Scheduled",
+		Message:        "Successfully assigned dev/api-1 to node-2",
+		EventTime:      metav1.NewMicroTime(now.Add(-10 * time.Minute)),
+	}
+	if err := repo.Upsert(RecordFromEvent("default", scheduled)); err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+
+	items, total, err := repo.Query(QueryParams{Cluster: "default"})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if total != 2 || len(items) != 2 || items[0].Reason != "Scheduled" {
+		t.Fatalf("unexpected query result: total=%d items=%+v", total, items)
+	}
+	if items[0].Count != 1 || !items[0].FirstTimestamp.Equal(items[0].LastTimestamp) {
+		t.Fatalf("expected eventTime fallback for first/last timestamp: %+v", items[0])
+	}
+	if items[1].Count != 5 || items[1].Source != "kubelet@node-1" {
+		t.Fatalf("expected upserted count and source, got %+v", items[1])
+	}
+
+	items, _, err = repo.Query(QueryParams{Search: "back-OFF"})
+	if err != nil || len(items) != 1 || items[0].UID != "uid-1" {
+		t.Fatalf("expected case-insensitive search match, got %+v (%v)", items, err)
+	}
+	if items, _, _ = repo.Query(QueryParams{Search: "100%"}); len(items) != 0 {
+		t.Fatalf("expected escaped wildcard to match nothing, got %+v", items)
+	}
+
+	items, _, err = repo.Query(QueryParams{Namespaces: []string{"dev"}, Type: corev1.EventTypeWarning})
+	if err != nil || len(items) != 0 {
+		t.Fatalf("expected namespace scope to hide prod events, got %+v (%v)", items, err)
+	}
+
+	items, _, err = repo.Query(QueryParams{Since: now.Add(-2 * time.Hour), Until: now.Add(-30 * time.Minute)})
+	if err != nil || len(items) != 1 || items[0].UID != "uid-1" {
+		t.Fatalf("expected time range to match overlapping event, got %+v (%v)", items, err)
+	}
+
+	purged, err := repo.PurgeBefore(now.Add(-30 * time.Minute))
+	if err != nil || purged != 1 {
+		t.Fatalf("expected 1 purged event, got %d (%v)", purged, err)
+	}
+	if _, total, _ = repo.Query(QueryParams{}); total != 1 {
+		t.Fatalf("expected 1 remaining event, got %d", total)
+	}
+}
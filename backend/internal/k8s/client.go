@@ -3,7 +3,9 @@ package k8s
 import (
 	"os"
 	"path/filepath"
+	"sync"
 
+	"github.com/k8s-dashboard/backend/internal/tracing"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -21,6 +23,40 @@ type Client struct {
 	MetricsClient *versioned.Clientset
 	// REST 配置
 	Config *rest.Config
+
+	searchMu sync.Mutex
+	search   *SearchIndex
+}
+
+// SearchIndex 返回该集群的全局搜索索引，首次调用时创建并在后台开始同步
+func (c *Client) SearchIndex() (*SearchIndex, error) {
+	c.searchMu.Lock()
+	defer c.searchMu.Unlock()
+	if c.search == nil {
+		index, err := NewSearchIndex(c.Config)
+		if err != nil {
+			return nil, err
+		}
+		c.search = index
+	}
+	return c.search, nil
+}
+
+// CachedSearchIndex 返回已创建的搜索索引，尚未创建时返回 nil（不会触发创建）
+func (c *Client) CachedSearchIndex() *SearchIndex {
+	c.searchMu.Lock()
+	defer c.searchMu.Unlock()
+	return c.search
+}
+
+// Close 停止客户端持有的后台 informer；之后再次调用 SearchIndex 会重新建立索引
+func (c *Client) Close() {
+	c.searchMu.Lock()
+	defer c.searchMu.Unlock()
+	if c.search != nil {
+		c.search.Stop()
+		c.search = nil
+	}
 }
 
 // NewClient 创建新的 Kubernetes 客户端
@@ -35,20 +71,24 @@ func NewClient() (*Client, error) {
 
 // NewClientWithConfig 使用指定的 REST 配置创建客户端。
 func NewClientWithConfig(config *rest.Config) (*Client, error) {
+	// API 调用走带追踪的 Transport；Config 保持原样供 exec/port-forward 等升级连接使用
+	traced := rest.CopyConfig(config)
+	traced.Wrap(tracing.WrapTransport)
+
 	// 创建标准客户端
-	clientset, err := kubernetes.NewForConfig(config)
+	clientset, err := kubernetes.NewForConfig(traced)
 	if err != nil {
 		return nil, err
 	}
 
 	// 创建动态客户端
-	dynamicClient, err := dynamic.NewForConfig(config)
+	dynamicClient, err := dynamic.NewForConfig(traced)
 	if err != nil {
 		return nil, err
 	}
 
 	// 创建 Metrics 客户端
-	metricsClient, err := versioned.NewForConfig(config)
+	metricsClient, err := versioned.NewForConfig(traced)
 	if err != nil {
 		// Metrics 客户端创建失败不是致命错误
 		metricsClient = nil
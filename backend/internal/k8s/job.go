@@ -0,0 +1,36 @@
+package k8s
+
+import (
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Job 执行状态
+const (
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+)
+
+// JobStatus 根据 Job 的 Complete/Failed 条件推导执行状态与失败原因，未结束时 finishedAt 为 nil
+func JobStatus(job *batchv1.Job) (status, message string, finishedAt *time.Time) {
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		finished := cond.LastTransitionTime.Time
+		switch cond.Type {
+		case batchv1.JobComplete:
+			return JobStatusSucceeded, "", &finished
+		case batchv1.JobFailed:
+			msg := cond.Reason
+			if cond.Message != "" {
+				msg = cond.Reason + ": " + cond.Message
+			}
+			return JobStatusFailed, msg, &finished
+		}
+	}
+	return JobStatusRunning, "", nil
+}
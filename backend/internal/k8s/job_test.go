@@ -0,0 +1,72 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestJobStatus(t *testing.T) {
+	finished := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	cond := func(typ batchv1.JobConditionType, status corev1.ConditionStatus, reason, message string) batchv1.JobCondition {
+		return batchv1.JobCondition{Type: typ, Status: status, Reason: reason, Message: message, LastTransitionTime: metav1.NewTime(finished)}
+	}
+
+	tests := []struct {
+		name        string
+		conditions  []batchv1.JobCondition
+		wantStatus  string
+		wantMessage string
+		wantDone    bool
+	}{
+		{name: "no conditions", wantStatus: JobStatusRunning},
+		{
+			name:       "suspended is still running",
+			conditions: []batchv1.JobCondition{cond(batchv1.JobSuspended, corev1.ConditionTrue, "Suspended", "")},
+			wantStatus: JobStatusRunning,
+		},
+		{
+			name:       "complete",
+			conditions: []batchv1.JobCondition{cond(batchv1.JobComplete, corev1.ConditionTrue, "", "")},
+			wantStatus: JobStatusSucceeded,
+			wantDone:   true,
+		},
+		{
+			name:        "failed with message",
+			conditions:  []batchv1.JobCondition{cond(batchv1.JobFailed, corev1.ConditionTrue, "BackoffLimitExceeded", "Job has reached the specified backoff limit")},
+			wantStatus:  JobStatusFailed,
+			wantMessage: "BackoffLimitExceeded: Job has reached the specified backoff limit",
+			wantDone:    true,
+		},
+		{
+			name:        "failed without message",
+			conditions:  []batchv1.JobCondition{cond(batchv1.JobFailed, corev1.ConditionTrue, "DeadlineExceeded", "")},
+			wantStatus:  JobStatusFailed,
+			wantMessage: "DeadlineExceeded",
+			wantDone:    true,
+		},
+		{
+			name:       "false condition ignored",
+			conditions: []batchv1.JobCondition{cond(batchv1.JobFailed, corev1.ConditionFalse, "BackoffLimitExceeded", "")},
+			wantStatus: JobStatusRunning,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &batchv1.Job{Status: batchv1.JobStatus{Conditions: tt.conditions}}
+			status, message, finishedAt := JobStatus(job)
+			if status != tt.wantStatus || message != tt.wantMessage {
+				t.Fatalf("JobStatus = (%q, %q), want (%q, %q)", status, message, tt.wantStatus, tt.wantMessage)
+			}
+			if tt.wantDone != (finishedAt != nil) {
+				t.Fatalf("finishedAt = %v, want done=%v", finishedAt, tt.wantDone)
+			}
+			if finishedAt != nil && !finishedAt.Equal(finished) {
+				t.Fatalf("finishedAt = %v, want %v", finishedAt, finished)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package k8s
+
+import corev1 "k8s.io/api/core/v1"
+
+// DefaultNodePool 无法识别节点池时使用的名称
+const DefaultNodePool = "default"
+
+// nodePoolLabels 各云厂商与 Karpenter 标记节点池的标签，按顺序取第一个存在的
+var nodePoolLabels = []string{
+	"karpenter.sh/nodepool",
+	"cloud.google.com/gke-nodepool",
+	"eks.amazonaws.com/nodegroup",
+	"kubernetes.azure.com/agentpool",
+	"alibabacloud.com/nodepool-id",
+	"node.kubernetes.io/instance-type",
+}
+
+// NodePool 返回节点所属的节点池。label 非空时只按该标签分组，节点缺少该标签时归入 default；
+// label 为空时依次识别常见节点池标签，最后退回实例类型
+func NodePool(node *corev1.Node, label string) string {
+	if label != "" {
+		if value := node.Labels[label]; value != "" {
+			return value
+		}
+		return DefaultNodePool
+	}
+	for _, label := range nodePoolLabels {
+		if value := node.Labels[label]; value != "" {
+			return value
+		}
+	}
+	return DefaultNodePool
+}
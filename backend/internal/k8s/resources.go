@@ -0,0 +1,65 @@
+package k8s
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// IsExtendedResource 判断是否为扩展资源（如 nvidia.com/gpu）或大页内存（hugepages-*），
+// 规则与 kubelet 一致：带域名前缀且不属于 kubernetes.io 域
+func IsExtendedResource(name corev1.ResourceName) bool {
+	s := string(name)
+	if strings.HasPrefix(s, corev1.ResourceHugePagesPrefix) {
+		return true
+	}
+	return strings.Contains(s, "/") && !strings.Contains(s, "kubernetes.io/") && !strings.HasPrefix(s, corev1.DefaultResourceRequestsPrefix)
+}
+
+// ExtendedResources 取出资源列表中的扩展资源，数值为个数（大页为字节），没有时返回 nil
+func ExtendedResources(list corev1.ResourceList) map[string]float64 {
+	var result map[string]float64
+	for name, quantity := range list {
+		if !IsExtendedResource(name) {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]float64)
+		}
+		result[string(name)] = quantity.AsApproximateFloat64()
+	}
+	return result
+}
+
+// PodExtendedRequests 计算 Pod 申请的扩展资源：普通容器之和与任一 init 容器取较大者，没有时返回 nil
+func PodExtendedRequests(pod *corev1.Pod) map[string]float64 {
+	var result map[string]float64
+	for _, container := range pod.Spec.Containers {
+		for name, value := range ExtendedResources(container.Resources.Requests) {
+			if result == nil {
+				result = make(map[string]float64)
+			}
+			result[name] += value
+		}
+	}
+	for _, container := range pod.Spec.InitContainers {
+		for name, value := range ExtendedResources(container.Resources.Requests) {
+			if result == nil {
+				result = make(map[string]float64)
+			}
+			result[name] = max(result[name], value)
+		}
+	}
+	return result
+}
+
+// AddResources 将 src 累加到 dst，dst 为 nil 且 src 非空时新建
+func AddResources(dst, src map[string]float64) map[string]float64 {
+	for name, value := range src {
+		if dst == nil {
+			dst = make(map[string]float64)
+		}
+		dst[name] += value
+	}
+	return dst
+}
@@ -0,0 +1,45 @@
+package k8s
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestIsExtendedResource(t *testing.T) {
+	tests := map[corev1.ResourceName]bool{
+		"nvidia.com/gpu":                true,
+		"hugepages-2Mi":                 true,
+		"example.com/foo":               true,
+		corev1.ResourceCPU:              false,
+		corev1.ResourceEphemeralStorage: false,
+		"kubernetes.io/batch-cpu":       false,
+		"requests.nvidia.com/gpu":       false,
+	}
+	for name, want := range tests {
+		if got := IsExtendedResource(name); got != want {
+			t.Errorf("IsExtendedResource(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestPodExtendedRequests(t *testing.T) {
+	gpu := func(n string) corev1.ResourceRequirements {
+		return corev1.ResourceRequirements{Requests: corev1.ResourceList{
+			"nvidia.com/gpu":   resource.MustParse(n),
+			corev1.ResourceCPU: resource.MustParse("1"),
+		}}
+	}
+	pod := &corev1.Pod{Spec: corev1.PodSpec{
+		Containers:     []corev1.Container{{Resources: gpu("1")}, {Resources: gpu("2")}},
+		InitContainers: []corev1.Container{{Resources: gpu("1")}},
+	}}
+	got := PodExtendedRequests(pod)
+	if len(got) != 1 || got["nvidia.com/gpu"] != 3 {
+		t.Fatalf("unexpected extended requests %v", got)
+	}
+	if PodExtendedRequests(&corev1.Pod{}) != nil {
+		t.Fatal("pod without extended resources should return nil")
+	}
+}
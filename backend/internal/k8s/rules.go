@@ -0,0 +1,220 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/k8s-dashboard/backend/internal/promql"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// RuleKind 承载告警/记录规则的 CRD：Prometheus Operator 的 PrometheusRule 与 VictoriaMetrics Operator 的 VMRule，
+// 两者的 spec.groups 结构相同
+type RuleKind struct {
+	Kind     string
+	Resource schema.GroupVersionResource
+}
+
+// RuleKinds 支持的规则 CRD，键为路径中使用的资源名
+var RuleKinds = map[string]RuleKind{
+	"prometheusrules": {Kind: "PrometheusRule", Resource: schema.GroupVersionResource{Group: "monitoring.coreos.com", Version: "v1", Resource: "prometheusrules"}},
+	"vmrules":         {Kind: "VMRule", Resource: schema.GroupVersionResource{Group: "operator.victoriametrics.com", Version: "v1beta1", Resource: "vmrules"}},
+}
+
+// RuleResource 一个规则 CR。Groups 保留 CR 中的原始结构（含各 Operator 的扩展字段），便于原样编辑
+type RuleResource struct {
+	Kind            string            `json:"kind"`
+	Resource        string            `json:"resource"`
+	Namespace       string            `json:"namespace"`
+	Name            string            `json:"name"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	ResourceVersion string            `json:"resourceVersion"`
+	AlertCount      int               `json:"alertCount"`
+	RecordCount     int               `json:"recordCount"`
+	Groups          []interface{}     `json:"groups"`
+}
+
+// RuleGroupSpec 规则组定义中需要校验的字段
+type RuleGroupSpec struct {
+	Name     string     `json:"name"`
+	Interval string     `json:"interval,omitempty"`
+	Rules    []RuleSpec `json:"rules"`
+}
+
+// RuleSpec 规则定义中需要校验的字段，alert 与 record 二选一
+type RuleSpec struct {
+	Alert       string            `json:"alert,omitempty"`
+	Record      string            `json:"record,omitempty"`
+	Expr        string            `json:"expr"`
+	For         string            `json:"for,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// RuleValidationError 规则定义校验失败，Field 指出出错的位置，如 groups[0].rules[2].expr
+type RuleValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *RuleValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidateRuleGroups 校验规则组：组名非空且不重复，每条规则只能是告警或记录规则之一，
+// 表达式语法正确，for 与 interval 为合法时长。返回解析后的规则组
+func ValidateRuleGroups(raw []interface{}) ([]RuleGroupSpec, error) {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, &RuleValidationError{Field: "groups", Message: err.Error()}
+	}
+	var groups []RuleGroupSpec
+	if err := json.Unmarshal(data, &groups); err != nil {
+		return nil, &RuleValidationError{Field: "groups", Message: fmt.Sprintf("格式错误: %v", err)}
+	}
+
+	names := make(map[string]bool, len(groups))
+	for i, group := range groups {
+		field := fmt.Sprintf("groups[%d]", i)
+		if strings.TrimSpace(group.Name) == "" {
+			return nil, &RuleValidationError{Field: field + ".name", Message: "规则组名称不能为空"}
+		}
+		if names[group.Name] {
+			return nil, &RuleValidationError{Field: field + ".name", Message: fmt.Sprintf("规则组名称重复: %s", group.Name)}
+		}
+		names[group.Name] = true
+		if group.Interval != "" {
+			if _, err := promql.ParseDuration(group.Interval); err != nil {
+				return nil, &RuleValidationError{Field: field + ".interval", Message: err.Error()}
+			}
+		}
+		if len(group.Rules) == 0 {
+			return nil, &RuleValidationError{Field: field + ".rules", Message: "规则组至少需要一条规则"}
+		}
+		for j, rule := range group.Rules {
+			ruleField := fmt.Sprintf("%s.rules[%d]", field, j)
+			if (rule.Alert == "") == (rule.Record == "") {
+				return nil, &RuleValidationError{Field: ruleField, Message: "alert 与 record 必须且只能设置一个"}
+			}
+			if err := promql.Validate(rule.Expr); err != nil {
+				return nil, &RuleValidationError{Field: ruleField + ".expr", Message: err.Error()}
+			}
+			if rule.For != "" {
+				if rule.Record != "" {
+					return nil, &RuleValidationError{Field: ruleField + ".for", Message: "记录规则不支持 for"}
+				}
+				if _, err := promql.ParseDuration(rule.For); err != nil {
+					return nil, &RuleValidationError{Field: ruleField + ".for", Message: err.Error()}
+				}
+			}
+		}
+	}
+	return groups, nil
+}
+
+// ListRuleResources 列出规则 CR，namespace 为空时列出全部命名空间；集群未安装的 CRD 跳过
+func ListRuleResources(ctx context.Context, client dynamic.Interface, namespace string) ([]RuleResource, error) {
+	items := []RuleResource{}
+	for resource, kind := range RuleKinds {
+		list, err := client.Resource(kind.Resource).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("列出 %s 失败: %w", kind.Kind, err)
+		}
+		for i := range list.Items {
+			items = append(items, ruleResourceFromObject(resource, kind, &list.Items[i]))
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Namespace != items[j].Namespace {
+			return items[i].Namespace < items[j].Namespace
+		}
+		if items[i].Name != items[j].Name {
+			return items[i].Name < items[j].Name
+		}
+		return items[i].Kind < items[j].Kind
+	})
+	return items, nil
+}
+
+// GetRuleResource 获取规则 CR
+func GetRuleResource(ctx context.Context, client dynamic.Interface, resource, namespace, name string) (*RuleResource, error) {
+	kind, ok := RuleKinds[resource]
+	if !ok {
+		return nil, fmt.Errorf("不支持的规则资源: %s", resource)
+	}
+	obj, err := client.Resource(kind.Resource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	item := ruleResourceFromObject(resource, kind, obj)
+	return &item, nil
+}
+
+// UpdateRuleGroups 校验并替换规则 CR 的 spec.groups，其余字段保持不变。
+// resourceVersion 非空时用于乐观锁，CR 已被他人修改时返回 Conflict
+func UpdateRuleGroups(ctx context.Context, client dynamic.Interface, resource, namespace, name, resourceVersion string, groups []interface{}) (*RuleResource, error) {
+	kind, ok := RuleKinds[resource]
+	if !ok {
+		return nil, fmt.Errorf("不支持的规则资源: %s", resource)
+	}
+	if _, err := ValidateRuleGroups(groups); err != nil {
+		return nil, err
+	}
+
+	ri := client.Resource(kind.Resource).Namespace(namespace)
+	obj, err := ri.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if resourceVersion != "" {
+		obj.SetResourceVersion(resourceVersion)
+	}
+	if err := unstructured.SetNestedSlice(obj.Object, groups, "spec", "groups"); err != nil {
+		return nil, err
+	}
+	updated, err := ri.Update(ctx, obj, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	item := ruleResourceFromObject(resource, kind, updated)
+	return &item, nil
+}
+
+func ruleResourceFromObject(resource string, kind RuleKind, obj *unstructured.Unstructured) RuleResource {
+	groups, _, _ := unstructured.NestedSlice(obj.Object, "spec", "groups")
+	if groups == nil {
+		groups = []interface{}{}
+	}
+	item := RuleResource{
+		Kind:            kind.Kind,
+		Resource:        resource,
+		Namespace:       obj.GetNamespace(),
+		Name:            obj.GetName(),
+		Labels:          obj.GetLabels(),
+		ResourceVersion: obj.GetResourceVersion(),
+		Groups:          groups,
+	}
+	for _, g := range groups {
+		group, _ := g.(map[string]interface{})
+		rules, _ := group["rules"].([]interface{})
+		for _, r := range rules {
+			rule, _ := r.(map[string]interface{})
+			if _, ok := rule["alert"]; ok {
+				item.AlertCount++
+			} else {
+				item.RecordCount++
+			}
+		}
+	}
+	return item
+}
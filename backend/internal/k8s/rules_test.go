@@ -0,0 +1,132 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func newRuleObject(apiVersion, kind, namespace, name string, groups []interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"namespace": namespace, "name": name},
+		"spec":       map[string]interface{}{"groups": groups},
+	}}
+}
+
+func TestValidateRuleGroups(t *testing.T) {
+	valid := []interface{}{
+		map[string]interface{}{
+			"name":     "node",
+			"interval": "1m",
+			"rules": []interface{}{
+				map[string]interface{}{"alert": "NodeDown", "expr": `up{job="node"} == 0`, "for": "5m", "labels": map[string]interface{}{"severity": "critical"}},
+				map[string]interface{}{"record": "node:cpu:rate5m", "expr": `sum by (instance) (rate(node_cpu_seconds_total[5m]))`},
+			},
+		},
+	}
+	groups, err := ValidateRuleGroups(valid)
+	if err != nil {
+		t.Fatalf("ValidateRuleGroups returned error: %v", err)
+	}
+	if len(groups) != 1 || groups[0].Rules[0].Labels["severity"] != "critical" {
+		t.Fatalf("groups = %+v", groups)
+	}
+
+	tests := []struct {
+		name   string
+		groups []interface{}
+		field  string
+	}{
+		{"empty group name", []interface{}{map[string]interface{}{"rules": []interface{}{map[string]interface{}{"alert": "A", "expr": "up"}}}}, "groups[0].name"},
+		{"duplicate group", []interface{}{
+			map[string]interface{}{"name": "g", "rules": []interface{}{map[string]interface{}{"alert": "A", "expr": "up"}}},
+			map[string]interface{}{"name": "g", "rules": []interface{}{map[string]interface{}{"alert": "B", "expr": "up"}}},
+		}, "groups[1].name"},
+		{"no rules", []interface{}{map[string]interface{}{"name": "g"}}, "groups[0].rules"},
+		{"alert and record", []interface{}{map[string]interface{}{"name": "g", "rules": []interface{}{map[string]interface{}{"alert": "A", "record": "a", "expr": "up"}}}}, "groups[0].rules[0]"},
+		{"bad expr", []interface{}{map[string]interface{}{"name": "g", "rules": []interface{}{map[string]interface{}{"alert": "A", "expr": "sum(rate(x[5m])"}}}}, "groups[0].rules[0].expr"},
+		{"bad for", []interface{}{map[string]interface{}{"name": "g", "rules": []interface{}{map[string]interface{}{"alert": "A", "expr": "up", "for": "5 minutes"}}}}, "groups[0].rules[0].for"},
+		{"record with for", []interface{}{map[string]interface{}{"name": "g", "rules": []interface{}{map[string]interface{}{"record": "a", "expr": "up", "for": "5m"}}}}, "groups[0].rules[0].for"},
+		{"bad interval", []interface{}{map[string]interface{}{"name": "g", "interval": "often", "rules": []interface{}{map[string]interface{}{"alert": "A", "expr": "up"}}}}, "groups[0].interval"},
+	}
+	for _, tt := range tests {
+		_, err := ValidateRuleGroups(tt.groups)
+		var validationErr *RuleValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != tt.field {
+			t.Errorf("%s: expected error on %s, got %v", tt.name, tt.field, err)
+		}
+	}
+}
+
+func TestRuleResources(t *testing.T) {
+	ctx := context.Background()
+	groups := []interface{}{
+		map[string]interface{}{
+			"name": "app",
+			"rules": []interface{}{
+				map[string]interface{}{"alert": "HighErrorRate", "expr": `rate(http_errors_total[5m]) > 1`, "keep_firing_for": "10m"},
+				map[string]interface{}{"record": "app:requests:rate5m", "expr": `rate(http_requests_total[5m])`},
+			},
+		},
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		RuleKinds["prometheusrules"].Resource: "PrometheusRuleList",
+		RuleKinds["vmrules"].Resource:         "VMRuleList",
+	},
+		newRuleObject("monitoring.coreos.com/v1", "PrometheusRule", "payments", "app-rules", groups),
+		newRuleObject("operator.victoriametrics.com/v1beta1", "VMRule", "monitoring", "node-rules", []interface{}{}),
+	)
+
+	items, err := ListRuleResources(ctx, client, "")
+	if err != nil {
+		t.Fatalf("ListRuleResources returned error: %v", err)
+	}
+	if len(items) != 2 || items[0].Namespace != "monitoring" || items[1].Kind != "PrometheusRule" {
+		t.Fatalf("items = %+v", items)
+	}
+	if items[1].AlertCount != 1 || items[1].RecordCount != 1 {
+		t.Fatalf("expected rule counts, got %+v", items[1])
+	}
+	if scoped, _ := ListRuleResources(ctx, client, "payments"); len(scoped) != 1 {
+		t.Fatalf("expected namespace filter, got %+v", scoped)
+	}
+
+	if _, err := GetRuleResource(ctx, client, "configmaps", "payments", "app-rules"); err == nil {
+		t.Fatal("expected unsupported resource to be rejected")
+	}
+
+	updatedGroups := []interface{}{
+		map[string]interface{}{
+			"name": "app",
+			"rules": []interface{}{
+				map[string]interface{}{"alert": "HighErrorRate", "expr": `rate(http_errors_total[5m]) > 5`, "keep_firing_for": "10m"},
+			},
+		},
+	}
+	updated, err := UpdateRuleGroups(ctx, client, "prometheusrules", "payments", "app-rules", "", updatedGroups)
+	if err != nil {
+		t.Fatalf("UpdateRuleGroups returned error: %v", err)
+	}
+	if updated.AlertCount != 1 || updated.RecordCount != 0 {
+		t.Fatalf("updated = %+v", updated)
+	}
+	// Operator 的扩展字段原样保留
+	got, _ := GetRuleResource(ctx, client, "prometheusrules", "payments", "app-rules")
+	rule := got.Groups[0].(map[string]interface{})["rules"].([]interface{})[0].(map[string]interface{})
+	if rule["keep_firing_for"] != "10m" || rule["expr"] != `rate(http_errors_total[5m]) > 5` {
+		t.Fatalf("unexpected rule after update: %+v", rule)
+	}
+
+	var validationErr *RuleValidationError
+	invalid := []interface{}{map[string]interface{}{"name": "app", "rules": []interface{}{map[string]interface{}{"alert": "A", "expr": "up{"}}}}
+	if _, err := UpdateRuleGroups(ctx, client, "prometheusrules", "payments", "app-rules", "", invalid); !errors.As(err, &validationErr) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
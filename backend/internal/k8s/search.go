@@ -0,0 +1,285 @@
+package k8s
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// searchResync 搜索索引的全量重新同步周期，增量变更由 watch 实时更新
+	searchResync = 30 * time.Minute
+	// lastAppliedAnnotation kubectl apply 写入的完整清单，体积大且会让任意关键字都命中，不纳入索引
+	lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+// SearchKind 搜索索引覆盖的资源类型
+type SearchKind struct {
+	Kind       string
+	Resource   schema.GroupVersionResource
+	Namespaced bool
+}
+
+// SearchKinds 全局搜索覆盖的资源类型，Kind 同时作为 kinds 过滤参数的取值
+var SearchKinds = []SearchKind{
+	{Kind: "Pod", Resource: schema.GroupVersionResource{Version: "v1", Resource: "pods"}, Namespaced: true},
+	{Kind: "Deployment", Resource: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, Namespaced: true},
+	{Kind: "StatefulSet", Resource: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}, Namespaced: true},
+	{Kind: "DaemonSet", Resource: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}, Namespaced: true},
+	{Kind: "Job", Resource: schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}, Namespaced: true},
+	{Kind: "CronJob", Resource: schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"}, Namespaced: true},
+	{Kind: "Service", Resource: schema.GroupVersionResource{Version: "v1", Resource: "services"}, Namespaced: true},
+	{Kind: "Ingress", Resource: schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}, Namespaced: true},
+	{Kind: "ConfigMap", Resource: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, Namespaced: true},
+	{Kind: "Secret", Resource: schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, Namespaced: true},
+	{Kind: "PersistentVolumeClaim", Resource: schema.GroupVersionResource{Version: "v1", Resource: "persistentvolumeclaims"}, Namespaced: true},
+	{Kind: "ServiceAccount", Resource: schema.GroupVersionResource{Version: "v1", Resource: "serviceaccounts"}, Namespaced: true},
+	{Kind: "Namespace", Resource: schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}},
+	{Kind: "Node", Resource: schema.GroupVersionResource{Version: "v1", Resource: "nodes"}},
+	{Kind: "PersistentVolume", Resource: schema.GroupVersionResource{Version: "v1", Resource: "persistentvolumes"}},
+	{Kind: "StorageClass", Resource: schema.GroupVersionResource{Group: "storage.k8s.io", Version: "v1", Resource: "storageclasses"}},
+}
+
+// 命中位置，按相关度从高到低
+const (
+	SearchMatchName       = "name"
+	SearchMatchLabel      = "label"
+	SearchMatchAnnotation = "annotation"
+)
+
+// SearchHit 一条搜索结果
+type SearchHit struct {
+	Kind        string            `json:"kind"`
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace,omitempty"`
+	MatchedOn   string            `json:"matchedOn"`       // name, label, annotation
+	Match       string            `json:"match,omitempty"` // 命中的标签或注解（key=value）
+	Labels      map[string]string `json:"labels,omitempty"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	Link        string            `json:"link,omitempty"` // 前端页面路径，由 API 层填充
+	score       int
+	kindOrdinal int
+}
+
+// SearchQuery 搜索条件。Terms 全部命中才算匹配；Allow 非 nil 时用于过滤调用方无权查看的对象
+type SearchQuery struct {
+	Terms     []string
+	Kinds     map[string]bool
+	Namespace string
+	Limit     int
+	Allow     func(kind SearchKind, namespace, name string) bool
+}
+
+// SearchResult 搜索结果；Pending 为索引尚未完成首次同步（或无权 list）的资源类型
+type SearchResult struct {
+	Hits      []SearchHit `json:"items"`
+	Total     int         `json:"total"`
+	Truncated bool        `json:"truncated"`
+	Pending   []string    `json:"pending,omitempty"`
+}
+
+// SearchIndex 基于元数据 informer 的全集群资源索引，只缓存 name/labels/annotations 等元数据，
+// 不缓存 Secret 内容与对象 spec
+type SearchIndex struct {
+	factory   metadatainformer.SharedInformerFactory
+	informers []cache.SharedIndexInformer
+	stopCh    chan struct{}
+	stopOnce  sync.Once
+}
+
+// NewSearchIndex 创建并启动搜索索引，首次同步在后台进行
+func NewSearchIndex(config *rest.Config) (*SearchIndex, error) {
+	client, err := metadata.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	factory := metadatainformer.NewSharedInformerFactoryWithOptions(client, searchResync,
+		metadatainformer.WithTransform(trimSearchMetadata))
+
+	index := &SearchIndex{factory: factory, stopCh: make(chan struct{})}
+	for _, kind := range SearchKinds {
+		index.informers = append(index.informers, factory.ForResource(kind.Resource).Informer())
+	}
+	factory.Start(index.stopCh)
+	return index, nil
+}
+
+// trimSearchMetadata 丢弃索引不需要的字段以降低内存占用
+func trimSearchMetadata(obj interface{}) (interface{}, error) {
+	if meta, ok := obj.(*metav1.PartialObjectMetadata); ok {
+		meta.ManagedFields = nil
+		if _, exists := meta.Annotations[lastAppliedAnnotation]; exists {
+			annotations := make(map[string]string, len(meta.Annotations)-1)
+			for key, value := range meta.Annotations {
+				if key != lastAppliedAnnotation {
+					annotations[key] = value
+				}
+			}
+			meta.Annotations = annotations
+		}
+	}
+	return obj, nil
+}
+
+// WaitForSync 等待所有资源类型完成首次同步，ctx 结束时返回 false
+func (s *SearchIndex) WaitForSync(ctx context.Context) bool {
+	synced := make([]cache.InformerSynced, 0, len(s.informers))
+	for _, informer := range s.informers {
+		synced = append(synced, informer.HasSynced)
+	}
+	return cache.WaitForCacheSync(ctx.Done(), synced...)
+}
+
+// Stop 停止所有 informer
+func (s *SearchIndex) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+		s.factory.Shutdown()
+	})
+}
+
+// Count 返回索引中某类资源的对象数，该类型未完成首次同步或不在索引范围内时 ok 为 false
+func (s *SearchIndex) Count(kind string) (int, bool) {
+	for i, searchKind := range SearchKinds {
+		if searchKind.Kind != kind {
+			continue
+		}
+		if !s.informers[i].HasSynced() {
+			return 0, false
+		}
+		return len(s.informers[i].GetStore().ListKeys()), true
+	}
+	return 0, false
+}
+
+// Search 在索引中查找名称、标签或注解包含全部关键字的对象，按相关度、类型、命名空间、名称排序
+func (s *SearchIndex) Search(query SearchQuery) SearchResult {
+	result := SearchResult{Hits: make([]SearchHit, 0)}
+	for i, kind := range SearchKinds {
+		if len(query.Kinds) > 0 && !query.Kinds[kind.Kind] {
+			continue
+		}
+		if query.Namespace != "" && !kind.Namespaced && kind.Kind != "Namespace" {
+			continue
+		}
+		informer := s.informers[i]
+		if !informer.HasSynced() {
+			result.Pending = append(result.Pending, kind.Kind)
+			continue
+		}
+		for _, obj := range informer.GetStore().List() {
+			meta, ok := obj.(*metav1.PartialObjectMetadata)
+			if !ok {
+				continue
+			}
+			if query.Namespace != "" {
+				if kind.Namespaced && meta.Namespace != query.Namespace {
+					continue
+				}
+				if kind.Kind == "Namespace" && meta.Name != query.Namespace {
+					continue
+				}
+			}
+			hit, matched := MatchSearchTerms(meta, query.Terms)
+			if !matched {
+				continue
+			}
+			if query.Allow != nil && !query.Allow(kind, meta.Namespace, meta.Name) {
+				continue
+			}
+			hit.Kind = kind.Kind
+			hit.kindOrdinal = i
+			result.Hits = append(result.Hits, hit)
+		}
+	}
+
+	sort.Slice(result.Hits, func(i, j int) bool {
+		a, b := result.Hits[i], result.Hits[j]
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		if a.kindOrdinal != b.kindOrdinal {
+			return a.kindOrdinal < b.kindOrdinal
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	result.Total = len(result.Hits)
+	if query.Limit > 0 && len(result.Hits) > query.Limit {
+		result.Hits = result.Hits[:query.Limit]
+		result.Truncated = true
+	}
+	return result
+}
+
+// MatchSearchTerms 判断对象元数据是否命中全部关键字（不区分大小写）。
+// 每个关键字依次匹配名称、标签（key=value）、注解，命中位置取所有关键字中相关度最低的一处
+func MatchSearchTerms(meta *metav1.PartialObjectMetadata, terms []string) (SearchHit, bool) {
+	hit := SearchHit{
+		Name:      meta.Name,
+		Namespace: meta.Namespace,
+		Labels:    meta.Labels,
+		CreatedAt: meta.CreationTimestamp.Time,
+	}
+	if len(terms) == 0 {
+		return hit, false
+	}
+
+	name := strings.ToLower(meta.Name)
+	score := 0
+	for i, term := range terms {
+		term = strings.ToLower(term)
+		termScore, matchedOn, match := 0, "", ""
+		switch {
+		case name == term:
+			termScore, matchedOn = 100, SearchMatchName
+		case strings.HasPrefix(name, term):
+			termScore, matchedOn = 80, SearchMatchName
+		case strings.Contains(name, term):
+			termScore, matchedOn = 60, SearchMatchName
+		default:
+			if pair, ok := matchKeyValues(meta.Labels, term); ok {
+				termScore, matchedOn, match = 40, SearchMatchLabel, pair
+			} else if pair, ok := matchKeyValues(meta.Annotations, term); ok {
+				termScore, matchedOn, match = 20, SearchMatchAnnotation, pair
+			} else {
+				return hit, false
+			}
+		}
+		if i == 0 || termScore < score {
+			score, hit.MatchedOn, hit.Match = termScore, matchedOn, match
+		}
+	}
+	hit.score = score
+	return hit, true
+}
+
+// matchKeyValues 在 key=value 形式中查找关键字，按 key 排序保证结果稳定
+func matchKeyValues(values map[string]string, term string) (string, bool) {
+	if len(values) == 0 {
+		return "", false
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		pair := key + "=" + values[key]
+		if strings.Contains(strings.ToLower(pair), term) {
+			return pair, true
+		}
+	}
+	return "", false
+}
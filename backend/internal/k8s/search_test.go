@@ -0,0 +1,82 @@
+package k8s
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMatchSearchTerms(t *testing.T) {
+	meta := &metav1.PartialObjectMetadata{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "payment-api",
+			Namespace:   "shop",
+			Labels:      map[string]string{"app": "payment", "tier": "backend"},
+			Annotations: map[string]string{"owner": "team-billing"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		terms     []string
+		wantMatch bool
+		wantOn    string
+		wantPair  string
+	}{
+		{name: "exact name", terms: []string{"payment-api"}, wantMatch: true, wantOn: SearchMatchName},
+		{name: "case insensitive substring", terms: []string{"API"}, wantMatch: true, wantOn: SearchMatchName},
+		{name: "label pair", terms: []string{"tier=backend"}, wantMatch: true, wantOn: SearchMatchLabel, wantPair: "tier=backend"},
+		{name: "annotation value", terms: []string{"billing"}, wantMatch: true, wantOn: SearchMatchAnnotation, wantPair: "owner=team-billing"},
+		{name: "all terms required", terms: []string{"payment", "frontend"}, wantMatch: false},
+		{name: "weakest term decides match location", terms: []string{"payment", "billing"}, wantMatch: true, wantOn: SearchMatchAnnotation, wantPair: "owner=team-billing"},
+		{name: "no terms", terms: nil, wantMatch: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hit, ok := MatchSearchTerms(meta, tt.terms)
+			if ok != tt.wantMatch {
+				t.Fatalf("match = %v, want %v", ok, tt.wantMatch)
+			}
+			if !ok {
+				return
+			}
+			if hit.MatchedOn != tt.wantOn || hit.Match != tt.wantPair {
+				t.Fatalf("matched on %q (%q), want %q (%q)", hit.MatchedOn, hit.Match, tt.wantOn, tt.wantPair)
+			}
+			if hit.Name != "payment-api" || hit.Namespace != "shop" {
+				t.Fatalf("unexpected hit %+v", hit)
+			}
+		})
+	}
+}
+
+func TestMatchSearchTermsRanksNameAboveLabels(t *testing.T) {
+	exact, _ := MatchSearchTerms(&metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "redis"}}, []string{"redis"})
+	prefix, _ := MatchSearchTerms(&metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "redis-master"}}, []string{"redis"})
+	label, _ := MatchSearchTerms(&metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{
+		Name:   "cache-0",
+		Labels: map[string]string{"app": "redis"},
+	}}, []string{"redis"})
+
+	if !(exact.score > prefix.score && prefix.score > label.score) {
+		t.Fatalf("unexpected ranking: exact=%d prefix=%d label=%d", exact.score, prefix.score, label.score)
+	}
+}
+
+func TestTrimSearchMetadataDropsLastAppliedConfiguration(t *testing.T) {
+	meta := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{
+		Name:          "web",
+		Annotations:   map[string]string{lastAppliedAnnotation: "{...}", "team": "web"},
+		ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+	}}
+	if _, err := trimSearchMetadata(meta); err != nil {
+		t.Fatalf("trimSearchMetadata failed: %v", err)
+	}
+	if _, exists := meta.Annotations[lastAppliedAnnotation]; exists || meta.Annotations["team"] != "web" {
+		t.Fatalf("unexpected annotations %v", meta.Annotations)
+	}
+	if meta.ManagedFields != nil {
+		t.Fatalf("managed fields should be dropped")
+	}
+}
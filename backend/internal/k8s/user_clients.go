@@ -0,0 +1,123 @@
+package k8s
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"k8s.io/client-go/rest"
+)
+
+// 用户凭据模式：决定每个请求以什么身份访问 Kubernetes API
+const (
+	// UserAuthDashboard 所有请求使用 Dashboard 自身凭据（默认），仅由平台角色控制权限
+	UserAuthDashboard = "dashboard"
+	// UserAuthToken 使用用户绑定的 ServiceAccount Token，未配置 Token 时回落为模拟
+	UserAuthToken = "token"
+	// UserAuthImpersonate 使用 Dashboard 凭据并附带 Impersonate-User/Group 头
+	UserAuthImpersonate = "impersonate"
+)
+
+const (
+	// ImpersonateUserPrefix 模拟普通平台用户时的用户名前缀，避免与集群内已有用户重名
+	ImpersonateUserPrefix = "k8s-dashboard:"
+	// ImpersonateRoleGroupPrefix 模拟时附带的平台角色组前缀，可在 RoleBinding 中按组授权
+	ImpersonateRoleGroupPrefix = "k8s-dashboard:role:"
+	// maxUserClients 缓存的用户客户端上限，超出后整体清空重建
+	maxUserClients = 1000
+)
+
+// UserIdentity 构造用户客户端所需的身份信息
+type UserIdentity struct {
+	ID             int64
+	Username       string
+	Role           string
+	ServiceAccount string
+	SANamespace    string
+	SAToken        string
+}
+
+// UserClients 按用户构造并缓存访问 Kubernetes 的客户端，使集群 RBAC 对每个平台用户生效
+type UserClients struct {
+	mode string
+
+	mu      sync.Mutex
+	clients map[string]*Client
+}
+
+// NewUserClients 创建用户客户端工厂；mode 为 dashboard 或空时返回 nil，表示不区分用户
+func NewUserClients(mode string) (*UserClients, error) {
+	switch mode {
+	case "", UserAuthDashboard:
+		return nil, nil
+	case UserAuthToken, UserAuthImpersonate:
+		return &UserClients{mode: mode, clients: make(map[string]*Client)}, nil
+	default:
+		return nil, fmt.Errorf("不支持的用户凭据模式: %s", mode)
+	}
+}
+
+// Mode 返回当前用户凭据模式
+func (u *UserClients) Mode() string {
+	if u == nil {
+		return UserAuthDashboard
+	}
+	return u.mode
+}
+
+// ForUser 基于集群客户端 base 返回以 user 身份访问的客户端。
+// base 变化（集群凭据轮换）或用户 Token、角色变化时会生成新的缓存项。
+func (u *UserClients) ForUser(base *Client, user UserIdentity) (*Client, error) {
+	if u == nil || base == nil {
+		return base, nil
+	}
+
+	config := userConfig(base.Config, user, u.mode)
+	key := userClientKey(base, user, config)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if client, ok := u.clients[key]; ok {
+		return client, nil
+	}
+	client, err := NewClientWithConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	if len(u.clients) >= maxUserClients {
+		u.clients = make(map[string]*Client)
+	}
+	u.clients[key] = client
+	return client, nil
+}
+
+// userConfig 构造用户身份的 REST 配置：token 模式替换为用户的 Bearer Token，
+// 否则保留 Dashboard 凭据并模拟用户（ServiceAccount 用户模拟为对应的 SA）
+func userConfig(base *rest.Config, user UserIdentity, mode string) *rest.Config {
+	if mode == UserAuthToken && user.SAToken != "" {
+		config := rest.AnonymousClientConfig(base)
+		config.BearerToken = user.SAToken
+		return config
+	}
+
+	config := rest.CopyConfig(base)
+	if user.ServiceAccount != "" && user.SANamespace != "" {
+		// 未指定组时 API Server 会自动补充 system:serviceaccounts 相关组
+		config.Impersonate = rest.ImpersonationConfig{
+			UserName: "system:serviceaccount:" + user.SANamespace + ":" + user.ServiceAccount,
+		}
+		return config
+	}
+	config.Impersonate = rest.ImpersonationConfig{
+		UserName: ImpersonateUserPrefix + user.Username,
+		Groups:   []string{ImpersonateRoleGroupPrefix + user.Role},
+	}
+	return config
+}
+
+func userClientKey(base *Client, user UserIdentity, config *rest.Config) string {
+	sum := sha256.Sum256([]byte(config.BearerToken))
+	return fmt.Sprintf("%p|%d|%s|%s|%s", base, user.ID, config.Impersonate.UserName,
+		config.Impersonate.Groups, hex.EncodeToString(sum[:8]))
+}
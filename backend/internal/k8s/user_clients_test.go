@@ -0,0 +1,81 @@
+package k8s
+
+import (
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestUserConfig(t *testing.T) {
+	base := &rest.Config{
+		Host:            "https://k8s.example.com",
+		BearerToken:     "dashboard-token",
+		TLSClientConfig: rest.TLSClientConfig{CAData: []byte("ca")},
+	}
+
+	tests := []struct {
+		name      string
+		mode      string
+		user      UserIdentity
+		wantToken string
+		wantUser  string
+		wantGroup string
+	}{
+		{
+			name:      "token mode uses user token",
+			mode:      UserAuthToken,
+			user:      UserIdentity{Username: "dev", Role: "operator", SAToken: "user-token"},
+			wantToken: "user-token",
+		},
+		{
+			name:      "token mode without token falls back to impersonation",
+			mode:      UserAuthToken,
+			user:      UserIdentity{Username: "dev", Role: "viewer"},
+			wantToken: "dashboard-token",
+			wantUser:  "k8s-dashboard:dev",
+			wantGroup: "k8s-dashboard:role:viewer",
+		},
+		{
+			name:      "impersonate service account",
+			mode:      UserAuthImpersonate,
+			user:      UserIdentity{Username: "dev", Role: "operator", ServiceAccount: "deployer", SANamespace: "apps", SAToken: "ignored"},
+			wantToken: "dashboard-token",
+			wantUser:  "system:serviceaccount:apps:deployer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := userConfig(base, tt.user, tt.mode)
+			if config.BearerToken != tt.wantToken {
+				t.Fatalf("token = %q, want %q", config.BearerToken, tt.wantToken)
+			}
+			if config.Impersonate.UserName != tt.wantUser {
+				t.Fatalf("impersonate user = %q, want %q", config.Impersonate.UserName, tt.wantUser)
+			}
+			gotGroup := ""
+			if len(config.Impersonate.Groups) > 0 {
+				gotGroup = config.Impersonate.Groups[0]
+			}
+			if gotGroup != tt.wantGroup {
+				t.Fatalf("impersonate group = %q, want %q", gotGroup, tt.wantGroup)
+			}
+			if string(config.CAData) != "ca" {
+				t.Fatal("expected TLS config to be preserved")
+			}
+		})
+	}
+
+	if base.BearerToken != "dashboard-token" || base.Impersonate.UserName != "" {
+		t.Fatal("base config must not be modified")
+	}
+}
+
+func TestNewUserClientsRejectsUnknownMode(t *testing.T) {
+	if clients, err := NewUserClients(UserAuthDashboard); err != nil || clients != nil {
+		t.Fatalf("dashboard mode should disable user clients, got %v %v", clients, err)
+	}
+	if _, err := NewUserClients("oidc"); err == nil {
+		t.Fatal("expected unknown mode to be rejected")
+	}
+}
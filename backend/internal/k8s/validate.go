@@ -0,0 +1,160 @@
+package k8s
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+)
+
+// unknownFieldPattern 从严格解码的错误中提取未知字段名
+var unknownFieldPattern = regexp.MustCompile(`unknown field "([^"]+)"`)
+
+// DecodeManifest 严格解析 YAML/JSON 清单到 into：空内容、语法错误、未知字段（多为拼写错误）
+// 以及与目标类型不符的 apiVersion/kind 均以字段错误返回；apiVersion/kind 省略时不检查
+func DecodeManifest(data []byte, into runtime.Object) field.ErrorList {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return field.ErrorList{field.Required(field.NewPath("yaml"), "清单内容为空")}
+	}
+	if err := yaml.UnmarshalStrict(data, into); err != nil {
+		if match := unknownFieldPattern.FindStringSubmatch(err.Error()); match != nil {
+			return field.ErrorList{field.Forbidden(field.NewPath(match[1]), "未知字段，请检查拼写与缩进层级")}
+		}
+		return field.ErrorList{field.Invalid(field.NewPath("yaml"), "", err.Error())}
+	}
+
+	gvks, _, err := scheme.Scheme.ObjectKinds(into)
+	if err != nil || len(gvks) == 0 {
+		return nil
+	}
+	expected := gvks[0]
+	actual := into.GetObjectKind().GroupVersionKind()
+	var errs field.ErrorList
+	if actual.Kind != "" && actual.Kind != expected.Kind {
+		errs = append(errs, field.Invalid(field.NewPath("kind"), actual.Kind, fmt.Sprintf("应为 %s", expected.Kind)))
+	}
+	if apiVersion := actual.GroupVersion().String(); actual.Version != "" && apiVersion != expected.GroupVersion().String() {
+		errs = append(errs, field.Invalid(field.NewPath("apiVersion"), apiVersion, fmt.Sprintf("应为 %s", expected.GroupVersion().String())))
+	}
+	return errs
+}
+
+// ValidateObject 在提交给 API Server 前做基础校验：名称必填且格式合法、与路径中的命名空间和名称一致、
+// 工作负载的 selector 非空且匹配 Pod 模板标签等。namespace 为路径中的命名空间（集群级资源传空），
+// name 为路径中的名称（创建时传空）；请求体中缺省的命名空间与名称按路径补全
+func ValidateObject(obj runtime.Object, namespace, name string) field.ErrorList {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return field.ErrorList{field.InternalError(field.NewPath("metadata"), err)}
+	}
+	metaPath := field.NewPath("metadata")
+	var errs field.ErrorList
+
+	if namespace != "" {
+		switch accessor.GetNamespace() {
+		case "":
+			accessor.SetNamespace(namespace)
+		case namespace:
+		default:
+			errs = append(errs, field.Invalid(metaPath.Child("namespace"), accessor.GetNamespace(), fmt.Sprintf("与路径中的命名空间 %s 不一致", namespace)))
+		}
+	}
+	if name != "" {
+		switch accessor.GetName() {
+		case "":
+			accessor.SetName(name)
+		case name:
+		default:
+			errs = append(errs, field.Invalid(metaPath.Child("name"), accessor.GetName(), fmt.Sprintf("与路径中的名称 %s 不一致", name)))
+		}
+	}
+
+	switch {
+	case accessor.GetName() != "":
+		for _, msg := range nameValidator(obj)(accessor.GetName()) {
+			errs = append(errs, field.Invalid(metaPath.Child("name"), accessor.GetName(), msg))
+		}
+	case accessor.GetGenerateName() == "":
+		errs = append(errs, field.Required(metaPath.Child("name"), "名称不能为空"))
+	}
+
+	specPath := field.NewPath("spec")
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		errs = append(errs, validateWorkload(o.Spec.Selector, &o.Spec.Template)...)
+	case *appsv1.StatefulSet:
+		errs = append(errs, validateWorkload(o.Spec.Selector, &o.Spec.Template)...)
+	case *appsv1.DaemonSet:
+		errs = append(errs, validateWorkload(o.Spec.Selector, &o.Spec.Template)...)
+	case *networkingv1.Ingress:
+		if len(o.Spec.Rules) == 0 && o.Spec.DefaultBackend == nil {
+			errs = append(errs, field.Required(specPath.Child("rules"), "至少需要一条规则或 defaultBackend"))
+		}
+	case *corev1.PersistentVolumeClaim:
+		if len(o.Spec.AccessModes) == 0 {
+			errs = append(errs, field.Required(specPath.Child("accessModes"), "至少需要一种访问模式"))
+		}
+		if _, ok := o.Spec.Resources.Requests[corev1.ResourceStorage]; !ok {
+			errs = append(errs, field.Required(specPath.Child("resources", "requests", "storage"), "需要指定容量"))
+		}
+	case *corev1.ConfigMap:
+		errs = append(errs, validateDataKeys(field.NewPath("data"), slices.Sorted(maps.Keys(o.Data)))...)
+		errs = append(errs, validateDataKeys(field.NewPath("binaryData"), slices.Sorted(maps.Keys(o.BinaryData)))...)
+	case *corev1.Secret:
+		errs = append(errs, validateDataKeys(field.NewPath("data"), slices.Sorted(maps.Keys(o.Data)))...)
+		errs = append(errs, validateDataKeys(field.NewPath("stringData"), slices.Sorted(maps.Keys(o.StringData)))...)
+	}
+	return errs
+}
+
+// nameValidator Service 名称需符合 DNS-1035 label，Namespace 为 DNS-1123 label，其余资源为 DNS-1123 子域名
+func nameValidator(obj runtime.Object) func(string) []string {
+	switch obj.(type) {
+	case *corev1.Service:
+		return validation.IsDNS1035Label
+	case *corev1.Namespace:
+		return validation.IsDNS1123Label
+	default:
+		return validation.IsDNS1123Subdomain
+	}
+}
+
+func validateWorkload(selector *metav1.LabelSelector, template *corev1.PodTemplateSpec) field.ErrorList {
+	selectorPath := field.NewPath("spec", "selector")
+	var errs field.ErrorList
+	if selector == nil || (len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0) {
+		errs = append(errs, field.Required(selectorPath, "selector 不能为空"))
+	} else if parsed, err := metav1.LabelSelectorAsSelector(selector); err != nil {
+		errs = append(errs, field.Invalid(selectorPath, selector, err.Error()))
+	} else if !parsed.Matches(labels.Set(template.Labels)) {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "template", "metadata", "labels"), template.Labels, "与 spec.selector 不匹配"))
+	}
+	if len(template.Spec.Containers) == 0 {
+		errs = append(errs, field.Required(field.NewPath("spec", "template", "spec", "containers"), "至少需要一个容器"))
+	}
+	return errs
+}
+
+// validateDataKeys 校验 ConfigMap/Secret 的键名，keys 需已排序以保证错误顺序稳定
+func validateDataKeys(path *field.Path, keys []string) field.ErrorList {
+	var errs field.ErrorList
+	for _, key := range keys {
+		for _, msg := range validation.IsConfigMapKey(key) {
+			errs = append(errs, field.Invalid(path.Key(key), key, msg))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,85 @@
+package k8s
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func errorFields(errs field.ErrorList) []string {
+	fields := make([]string, 0, len(errs))
+	for _, err := range errs {
+		fields = append(fields, err.Field)
+	}
+	return fields
+}
+
+func TestValidateObjectFillsAndChecksPathValues(t *testing.T) {
+	svc := &corev1.Service{}
+	if errs := ValidateObject(svc, "shop", "web"); len(errs) != 0 {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+	if svc.Namespace != "shop" || svc.Name != "web" {
+		t.Fatalf("path values not filled: %s/%s", svc.Namespace, svc.Name)
+	}
+
+	mismatched := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "api"}}
+	fields := errorFields(ValidateObject(mismatched, "shop", "web"))
+	if len(fields) != 2 || fields[0] != "metadata.namespace" || fields[1] != "metadata.name" {
+		t.Fatalf("unexpected errors %v", fields)
+	}
+
+	if fields := errorFields(ValidateObject(&corev1.ConfigMap{}, "shop", "")); len(fields) != 1 || fields[0] != "metadata.name" {
+		t.Fatalf("expected missing name, got %v", fields)
+	}
+	// Service 名称不能以数字开头（DNS-1035）
+	if fields := errorFields(ValidateObject(&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "1web"}}, "shop", "")); len(fields) != 1 {
+		t.Fatalf("expected invalid service name, got %v", fields)
+	}
+}
+
+func TestValidateObjectWorkloadSelector(t *testing.T) {
+	dep := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web"}}
+	fields := errorFields(ValidateObject(dep, "shop", ""))
+	if len(fields) != 2 || fields[0] != "spec.selector" || fields[1] != "spec.template.spec.containers" {
+		t.Fatalf("unexpected errors %v", fields)
+	}
+
+	dep.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+	dep.Spec.Template.Labels = map[string]string{"app": "api"}
+	dep.Spec.Template.Spec.Containers = []corev1.Container{{Name: "app"}}
+	if fields := errorFields(ValidateObject(dep, "shop", "")); len(fields) != 1 || fields[0] != "spec.template.metadata.labels" {
+		t.Fatalf("expected selector mismatch, got %v", fields)
+	}
+
+	dep.Spec.Template.Labels["app"] = "web"
+	if errs := ValidateObject(dep, "shop", ""); len(errs) != 0 {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+}
+
+func TestDecodeManifest(t *testing.T) {
+	var dep appsv1.Deployment
+	if errs := DecodeManifest([]byte("apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\nspec:\n  replicas: 2\n"), &dep); len(errs) != 0 {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+	if dep.Name != "web" || *dep.Spec.Replicas != 2 {
+		t.Fatalf("unexpected object %+v", dep)
+	}
+
+	tests := map[string]string{
+		"":                                "yaml",
+		"metadata: [":                     "yaml",
+		"spec:\n  replcas: 2\n":           "replcas",
+		"apiVersion: v1\nkind: Service\n": "kind",
+	}
+	for manifest, wantField := range tests {
+		errs := DecodeManifest([]byte(manifest), &appsv1.Deployment{})
+		if len(errs) == 0 || errs[0].Field != wantField {
+			t.Errorf("DecodeManifest(%q) = %v, want error on %s", manifest, errs, wantField)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+)
+
+// capacityTrendQueries 集群级用量趋势，仅依赖 cAdvisor 指标：CPU（核）、工作集内存（字节）、运行中的 Pod 数
+var capacityTrendQueries = map[string]string{
+	"cpu":    `sum(rate(container_cpu_usage_seconds_total{container!="",container!="POD"}[5m]))`,
+	"memory": `sum(container_memory_working_set_bytes{container!="",container!="POD"})`,
+	"pods":   `count(count by (namespace, pod) (container_memory_working_set_bytes{container!="",container!="POD"}))`,
+}
+
+// GetCapacityTrends 获取 [start, end] 内 CPU、内存与 Pod 数的集群用量序列，键为 cpu、memory、pods
+func (c *Client) GetCapacityTrends(start, end time.Time, step time.Duration) (map[string][]TimeSeriesData, error) {
+	trends := make(map[string][]TimeSeriesData, len(capacityTrendQueries))
+	for resource, query := range capacityTrendQueries {
+		resp, err := c.QueryRange(query, start, end, fmt.Sprintf("%ds", int(step.Seconds())))
+		if err != nil {
+			return nil, fmt.Errorf("查询 %s 用量趋势失败: %w", resource, err)
+		}
+		trends[resource] = extractTimeSeries(resp)
+	}
+	return trends, nil
+}
@@ -1,30 +1,179 @@
 package metrics
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
+	"math"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/k8s-dashboard/backend/internal/tracing"
+	corev1 "k8s.io/api/core/v1"
 )
 
+// QueryPathAuto 自动探测查询路径
+const QueryPathAuto = "auto"
+
+// vmselectPath vmselect 集群模式（租户 0）的查询路径
+const vmselectPath = "/select/0/prometheus"
+
+// queryPathState 查询路径及其探测结果，由 WithContext 等创建的副本共享
+type queryPathState struct {
+	mu       sync.Mutex
+	path     string
+	resolved bool
+}
+
 // Client VictoriaMetrics 客户端
 type Client struct {
 	baseURL    string
-	queryPath  string // vmselect 集群模式的查询路径
+	queryPath  *queryPathState // 查询路径：单机 VictoriaMetrics/Prometheus/Thanos 为空，vmselect 集群模式为 /select/0/prometheus
 	httpClient *http.Client
+	ctx        context.Context // 请求上下文，用于关联调用方的 trace
+	rewriter   QueryRewriter   // 内置查询的指标与标签映射，为 nil 时不改写
+
+	retries      int
+	retryBackoff time.Duration
+	breaker      *breaker     // 由 WithContext 等创建的副本共享
+	cache        *resultCache // 由 WithContext 等创建的副本共享
+}
+
+// QueryRewriter 改写内置查询以适配不同的指标命名，并将结果中的标签改回内置查询使用的名称（见 promql.Rewriter）
+type QueryRewriter interface {
+	Rewrite(query string) (string, error)
+	RestoreLabels(labels map[string]string)
 }
 
-// NewClient 创建 VictoriaMetrics 客户端
-// baseURL: VictoriaMetrics 地址，支持 vmauth 代理
+// NewClient 使用默认配置创建 VictoriaMetrics 客户端，默认在首次查询时自动探测查询路径
+// baseURL: VictoriaMetrics 地址，支持 vmauth 代理，也可以是 Prometheus、Thanos Query 地址
 func NewClient(baseURL string) *Client {
+	return NewClientWithConfig(baseURL, DefaultConfig())
+}
+
+// NewClientWithConfig 按超时、重试、熔断与缓存配置创建客户端，cfg 需已通过 Validate
+func NewClientWithConfig(baseURL string, cfg Config) *Client {
+	timeout, _ := time.ParseDuration(cfg.Timeout)
+	backoff, _ := time.ParseDuration(cfg.RetryBackoff)
+	cooldown, _ := time.ParseDuration(cfg.BreakerCooldown)
+	cacheTTL, _ := time.ParseDuration(cfg.CacheTTL)
+	httpClient := newHTTPClient(timeout)
+	httpClient.Transport = tracing.WrapTransport(httpClient.Transport)
 	return &Client{
-		baseURL:   baseURL,
-		queryPath: "/select/0/prometheus", // vmselect 集群模式路径
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		baseURL:      baseURL,
+		queryPath:    &queryPathState{},
+		httpClient:   httpClient,
+		retries:      cfg.Retries,
+		retryBackoff: backoff,
+		breaker:      &breaker{threshold: cfg.BreakerThreshold, cooldown: cooldown},
+		cache:        &resultCache{ttl: cacheTTL, entries: make(map[string]cachedResult)},
+	}
+}
+
+// WithContext 返回绑定请求上下文的客户端副本，查询 span 将挂在调用方 trace 下
+func (c *Client) WithContext(ctx context.Context) *Client {
+	if c == nil {
+		return nil
+	}
+	clone := *c
+	clone.ctx = ctx
+	return &clone
+}
+
+// ValidateQueryPath 校验查询路径配置：auto 或以 / 开头的路径
+func ValidateQueryPath(path string) error {
+	if path != QueryPathAuto && !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("查询路径必须为 auto 或以 / 开头: %q", path)
+	}
+	return nil
+}
+
+// SetQueryPath 设置查询路径：auto 在首次查询时依次探测根路径与 vmselect 集群路径，
+// 其它值直接作为 /api/v1/query 之前的路径前缀（/ 表示根路径）
+func (c *Client) SetQueryPath(path string) {
+	if path == QueryPathAuto {
+		c.queryPath = &queryPathState{}
+		return
+	}
+	c.queryPath = &queryPathState{path: strings.TrimRight(path, "/"), resolved: true}
+}
+
+// resolveQueryPath 返回查询路径，未确定时探测：先尝试根路径（Prometheus、Thanos、单机 VictoriaMetrics 或 vmauth），
+// 再尝试 vmselect 集群路径。探测失败不缓存，下次查询时重试
+func (c *Client) resolveQueryPath() (string, error) {
+	state := c.queryPath
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.resolved {
+		return state.path, nil
+	}
+
+	var errs []error
+	for _, candidate := range []string{"", vmselectPath} {
+		if err := c.probe(candidate); err != nil {
+			errs = append(errs, fmt.Errorf("%s%s: %w", c.baseURL, candidate, err))
+			continue
+		}
+		state.path, state.resolved = candidate, true
+		return candidate, nil
+	}
+	return "", fmt.Errorf("探测查询路径失败: %w", errors.Join(errs...))
+}
+
+// probe 检查 path 下的查询接口是否可用
+func (c *Client) probe(path string) error {
+	body, err := c.fetch(fmt.Sprintf("%s%s/api/v1/query?query=vector(1)", c.baseURL, path))
+	if err != nil {
+		return err
+	}
+	var result QueryResponse
+	if err := json.Unmarshal(body, &result); err != nil || result.Status != "success" {
+		return errors.New("响应不是 Prometheus 查询 API 格式")
+	}
+	return nil
+}
+
+// SetQueryRewriter 设置内置查询的改写规则，nil 时不改写
+func (c *Client) SetQueryRewriter(rewriter QueryRewriter) {
+	c.rewriter = rewriter
+}
+
+// Raw 返回不改写查询的客户端副本，用于执行用户编写的 PromQL（自定义查询、面板）
+func (c *Client) Raw() *Client {
+	if c == nil {
+		return nil
+	}
+	clone := *c
+	clone.rewriter = nil
+	return &clone
+}
+
+// rewrite 按映射改写请求参数中的查询
+func (c *Client) rewrite(params url.Values) error {
+	if c.rewriter == nil {
+		return nil
+	}
+	query, err := c.rewriter.Rewrite(params.Get("query"))
+	if err != nil {
+		return fmt.Errorf("改写查询失败: %w", err)
+	}
+	params.Set("query", query)
+	return nil
+}
+
+// restoreLabels 将结果中被映射的标签改回原名
+func (c *Client) restoreLabels(result *QueryResponse) {
+	if c.rewriter == nil {
+		return
+	}
+	for _, res := range result.Data.Result {
+		if res.Metric != nil {
+			c.rewriter.RestoreLabels(res.Metric)
+		}
 	}
 }
 
@@ -50,16 +199,28 @@ type QueryResult struct {
 func (c *Client) Query(query string) (*QueryResponse, error) {
 	params := url.Values{}
 	params.Set("query", query)
+	return c.instantQuery(params)
+}
+
+// QueryAt 在指定时间点执行即时查询
+func (c *Client) QueryAt(query string, ts time.Time) (*QueryResponse, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("time", fmt.Sprintf("%d", ts.Unix()))
+	return c.instantQuery(params)
+}
 
-	resp, err := c.httpClient.Get(fmt.Sprintf("%s%s/api/v1/query?%s", c.baseURL, c.queryPath, params.Encode()))
+func (c *Client) instantQuery(params url.Values) (*QueryResponse, error) {
+	if err := c.rewrite(params); err != nil {
+		return nil, err
+	}
+	queryPath, err := c.resolveQueryPath()
 	if err != nil {
-		return nil, fmt.Errorf("查询失败: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
+	body, err := c.fetch(fmt.Sprintf("%s%s/api/v1/query?%s", c.baseURL, queryPath, params.Encode()))
 	if err != nil {
-		return nil, fmt.Errorf("读取响应失败: %w", err)
+		return nil, fmt.Errorf("查询失败: %w", err)
 	}
 
 	var result QueryResponse
@@ -71,6 +232,7 @@ func (c *Client) Query(query string) (*QueryResponse, error) {
 		return nil, fmt.Errorf("查询错误: %s", result.Error)
 	}
 
+	c.restoreLabels(&result)
 	return &result, nil
 }
 
@@ -81,16 +243,17 @@ func (c *Client) QueryRange(query string, start, end time.Time, step string) (*Q
 	params.Set("start", fmt.Sprintf("%d", start.Unix()))
 	params.Set("end", fmt.Sprintf("%d", end.Unix()))
 	params.Set("step", step)
-
-	resp, err := c.httpClient.Get(fmt.Sprintf("%s%s/api/v1/query_range?%s", c.baseURL, c.queryPath, params.Encode()))
+	if err := c.rewrite(params); err != nil {
+		return nil, err
+	}
+	queryPath, err := c.resolveQueryPath()
 	if err != nil {
-		return nil, fmt.Errorf("范围查询失败: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := c.fetch(fmt.Sprintf("%s%s/api/v1/query_range?%s", c.baseURL, queryPath, params.Encode()))
 	if err != nil {
-		return nil, fmt.Errorf("读取响应失败: %w", err)
+		return nil, fmt.Errorf("范围查询失败: %w", err)
 	}
 
 	var result QueryResponse
@@ -102,6 +265,7 @@ func (c *Client) QueryRange(query string, start, end time.Time, step string) (*Q
 		return nil, fmt.Errorf("查询错误: %s", result.Error)
 	}
 
+	c.restoreLabels(&result)
 	return &result, nil
 }
 
@@ -118,8 +282,22 @@ type ResourceMetric struct {
 	Used  float64 `json:"used"`
 	Total float64 `json:"total"`
 	Unit  string  `json:"unit"`
+	// TotalSource 总量的数据来源，见 Source* 常量；为空表示未取到总量
+	TotalSource string `json:"totalSource,omitempty"`
+	// UsedStatus、TotalStatus 使用量与总量的数据状态，见 Status* 常量；不是 ok 时对应的值为 0，不代表真实用量
+	UsedStatus  string `json:"usedStatus"`
+	TotalStatus string `json:"totalStatus"`
+	// Errors 查询失败的原因
+	Errors []string `json:"errors,omitempty"`
 }
 
+// 容量数据来源
+const (
+	SourceKubeStateMetrics = "kube-state-metrics"
+	SourceNodeExporter     = "node-exporter"
+	SourceKubernetesAPI    = "kubernetes-api"
+)
+
 // NodeMetrics 节点指标
 type NodeMetrics struct {
 	Name        string  `json:"name"`
@@ -127,6 +305,11 @@ type NodeMetrics struct {
 	MemoryUsage float64 `json:"memoryUsage"` // 百分比
 	CPUCores    float64 `json:"cpuCores"`
 	MemoryBytes float64 `json:"memoryBytes"`
+
+	// DiskUsage、InodeUsage 为各文件系统中使用率最高者的百分比，明细见 Filesystems
+	DiskUsage   float64          `json:"diskUsage"`
+	InodeUsage  float64          `json:"inodeUsage"`
+	Filesystems []NodeFilesystem `json:"filesystems"`
 }
 
 // PodMetrics Pod 指标
@@ -135,121 +318,154 @@ type PodMetrics struct {
 	Name        string  `json:"name"`
 	CPUUsage    float64 `json:"cpuUsage"`    // cores
 	MemoryUsage float64 `json:"memoryUsage"` // bytes
+	// CPUStatus、MemoryStatus 单个 Pod 查询时的数据状态，见 Status* 常量；不是 ok 时对应的值为 0，不代表真实用量
+	CPUStatus    string `json:"cpuStatus,omitempty"`
+	MemoryStatus string `json:"memoryStatus,omitempty"`
+	// Errors 查询失败的原因
+	Errors []string `json:"errors,omitempty"`
 }
 
-// GetClusterMetrics 获取集群指标概览
+// GetClusterMetrics 获取集群指标概览。单项查询失败或没有数据时记录在对应字段的状态中，
+// 所有查询均失败（数据源不可用）时返回错误
 func (c *Client) GetClusterMetrics() (*ClusterMetrics, error) {
-	metrics := &ClusterMetrics{}
+	metrics := &ClusterMetrics{
+		CPU:        ResourceMetric{Unit: "cores"},
+		Memory:     ResourceMetric{Unit: "GB"},
+		NodeMemory: ResourceMetric{Unit: "GB"},
+		Pods:       ResourceMetric{Unit: "pods"},
+	}
+	const gb = 1024 * 1024 * 1024
 
 	// CPU 使用量 (cores)
-	cpuUsedResp, err := c.Query(`sum(rate(container_cpu_usage_seconds_total{container!="",container!="POD"}[5m]))`)
-	if err == nil && len(cpuUsedResp.Data.Result) > 0 {
-		if val, ok := cpuUsedResp.Data.Result[0].Value[1].(string); ok {
-			fmt.Sscanf(val, "%f", &metrics.CPU.Used)
-		}
-	}
+	value, err := c.queryScalar(`sum(rate(container_cpu_usage_seconds_total{container!="",container!="POD"}[5m]))`)
+	metrics.CPU.setUsed(value, err)
 
 	// CPU 总量 (cores) - 使用 kube_node_status_allocatable (可分配 CPU)
 	// 这样计算的使用率会更准确，因为 capacity 包含了系统保留的 CPU
-	cpuTotalResp, err := c.Query(`sum(kube_node_status_allocatable{resource="cpu"})`)
-	if err == nil && len(cpuTotalResp.Data.Result) > 0 {
-		if val, ok := cpuTotalResp.Data.Result[0].Value[1].(string); ok {
-			fmt.Sscanf(val, "%f", &metrics.CPU.Total)
-		}
-	}
-	metrics.CPU.Unit = "cores"
+	value, err = c.queryScalar(`sum(kube_node_status_allocatable{resource="cpu"})`)
+	metrics.CPU.setTotal(value, err)
 
 	// 内存使用量 (GB)
-	memUsedResp, err := c.Query(`sum(container_memory_working_set_bytes{container!="",container!="POD"})`)
-	if err == nil && len(memUsedResp.Data.Result) > 0 {
-		if val, ok := memUsedResp.Data.Result[0].Value[1].(string); ok {
-			var bytes float64
-			fmt.Sscanf(val, "%f", &bytes)
-			metrics.Memory.Used = bytes / 1024 / 1024 / 1024
-		}
-	}
+	value, err = c.queryScalar(`sum(container_memory_working_set_bytes{container!="",container!="POD"})`)
+	metrics.Memory.setUsed(value/gb, err)
 
 	// 内存总量 (GB) - 使用 kube_node_status_allocatable (可分配内存)
 	// 这样计算的使用率会更准确，因为 capacity 包含了系统保留的内存
-	memTotalResp, err := c.Query(`sum(kube_node_status_allocatable{resource="memory"})`)
-	if err == nil && len(memTotalResp.Data.Result) > 0 {
-		if val, ok := memTotalResp.Data.Result[0].Value[1].(string); ok {
-			var bytes float64
-			fmt.Sscanf(val, "%f", &bytes)
-			metrics.Memory.Total = bytes / 1024 / 1024 / 1024
-		}
-	}
-	metrics.Memory.Unit = "GB"
+	value, err = c.queryScalar(`sum(kube_node_status_allocatable{resource="memory"})`)
+	metrics.Memory.setTotal(value/gb, err)
 
 	// 节点内存使用量 (GB) - OS 视角
 	// 使用 node_memory 指标，计算实际使用的内存（不包括可回收的 cache）
-	nodeMemUsedResp, err := c.Query(`sum(node_memory_MemTotal_bytes) - sum(node_memory_MemAvailable_bytes)`)
-	if err == nil && len(nodeMemUsedResp.Data.Result) > 0 {
-		if val, ok := nodeMemUsedResp.Data.Result[0].Value[1].(string); ok {
-			var bytes float64
-			fmt.Sscanf(val, "%f", &bytes)
-			metrics.NodeMemory.Used = bytes / 1024 / 1024 / 1024
-		}
-	}
+	value, err = c.queryScalar(`sum(node_memory_MemTotal_bytes) - sum(node_memory_MemAvailable_bytes)`)
+	metrics.NodeMemory.setUsed(value/gb, err)
 
 	// 节点内存总量 (GB) - 与容器内存使用相同的总量
-	nodeMemTotalResp, err := c.Query(`sum(node_memory_MemTotal_bytes)`)
-	if err == nil && len(nodeMemTotalResp.Data.Result) > 0 {
-		if val, ok := nodeMemTotalResp.Data.Result[0].Value[1].(string); ok {
-			var bytes float64
-			fmt.Sscanf(val, "%f", &bytes)
-			metrics.NodeMemory.Total = bytes / 1024 / 1024 / 1024
-		}
-	}
-	metrics.NodeMemory.Unit = "GB"
+	value, err = c.queryScalar(`sum(node_memory_MemTotal_bytes)`)
+	metrics.NodeMemory.setTotal(value/gb, err)
 
 	// Pod 数量 - 使用 kube_pod_status_phase
-	podUsedResp, err := c.Query(`count(kube_pod_status_phase{phase="Running"})`)
-	if err == nil && len(podUsedResp.Data.Result) > 0 {
-		if val, ok := podUsedResp.Data.Result[0].Value[1].(string); ok {
-			fmt.Sscanf(val, "%f", &metrics.Pods.Used)
-		}
-	}
+	value, err = c.queryScalar(`count(kube_pod_status_phase{phase="Running"})`)
+	metrics.Pods.setUsed(value, err)
 
 	// Pod 容量 - 使用 kube_node_status_allocatable (可分配 Pod 容量)
 	// 这样计算的使用率会更准确，因为 capacity 可能包含了系统保留的 Pod 容量
-	podCapacityResp, err := c.Query(`sum(kube_node_status_allocatable{resource="pods"})`)
-	if err == nil && len(podCapacityResp.Data.Result) > 0 {
-		if val, ok := podCapacityResp.Data.Result[0].Value[1].(string); ok {
-			fmt.Sscanf(val, "%f", &metrics.Pods.Total)
+	value, err = c.queryScalar(`sum(kube_node_status_allocatable{resource="pods"})`)
+	metrics.Pods.setTotal(value, err)
+
+	available := false
+	for _, m := range []*ResourceMetric{&metrics.CPU, &metrics.Memory, &metrics.NodeMemory, &metrics.Pods} {
+		if m.UsedStatus != StatusUnavailable || m.TotalStatus != StatusUnavailable {
+			available = true
 		}
 	}
-	metrics.Pods.Unit = "pods"
+	if !available {
+		return nil, fmt.Errorf("查询集群指标失败: %s", metrics.CPU.Errors[0])
+	}
+
+	if metrics.CPU.TotalStatus == StatusOK {
+		metrics.CPU.TotalSource = SourceKubeStateMetrics
+	}
+	if metrics.Memory.TotalStatus == StatusOK {
+		metrics.Memory.TotalSource = SourceKubeStateMetrics
+	}
+	if metrics.NodeMemory.TotalStatus == StatusOK {
+		metrics.NodeMemory.TotalSource = SourceNodeExporter
+	}
+	if metrics.Pods.TotalStatus == StatusOK {
+		metrics.Pods.TotalSource = SourceKubeStateMetrics
+	}
 
 	return metrics, nil
 }
 
+// NeedsCapacityFallback 判断是否缺少 kube-state-metrics 容量序列
+func (m *ClusterMetrics) NeedsCapacityFallback() bool {
+	return needsTotal(m.CPU) || needsTotal(m.Memory) || needsTotal(m.Pods)
+}
+
+// needsTotal 总量未取到（没有数据、查询失败或为 0）
+func needsTotal(m ResourceMetric) bool {
+	return m.TotalStatus != StatusOK || m.Total == 0
+}
+
+// ApplyNodeCapacity 在缺少 kube-state-metrics 时，用节点 allocatable 补齐容量总量，
+// runningPods < 0 表示不覆盖 Pod 使用量
+func (m *ClusterMetrics) ApplyNodeCapacity(nodes []corev1.Node, runningPods int) {
+	var cpu, memory, pods float64
+	for _, node := range nodes {
+		cpu += node.Status.Allocatable.Cpu().AsApproximateFloat64()
+		memory += node.Status.Allocatable.Memory().AsApproximateFloat64()
+		pods += node.Status.Allocatable.Pods().AsApproximateFloat64()
+	}
+
+	if needsTotal(m.CPU) {
+		m.CPU.Total, m.CPU.TotalStatus = cpu, StatusOK
+		m.CPU.TotalSource = SourceKubernetesAPI
+	}
+	if needsTotal(m.Memory) {
+		m.Memory.Total, m.Memory.TotalStatus = memory/1024/1024/1024, StatusOK
+		m.Memory.TotalSource = SourceKubernetesAPI
+	}
+	if needsTotal(m.Pods) {
+		m.Pods.Total, m.Pods.TotalStatus = pods, StatusOK
+		m.Pods.TotalSource = SourceKubernetesAPI
+		if runningPods >= 0 && m.Pods.UsedStatus != StatusOK {
+			m.Pods.Used, m.Pods.UsedStatus = float64(runningPods), StatusOK
+		}
+	}
+}
+
 // GetNodeMetrics 获取节点指标
 func (c *Client) GetNodeMetrics(nodeName string) (*NodeMetrics, error) {
 	metrics := &NodeMetrics{Name: nodeName}
 
 	// CPU 使用率
 	cpuQuery := fmt.Sprintf(`100 - (avg by(instance) (rate(node_cpu_seconds_total{mode="idle",instance=~"%s.*"}[5m])) * 100)`, nodeName)
-	cpuResp, err := c.Query(cpuQuery)
-	if err == nil && len(cpuResp.Data.Result) > 0 {
-		if val, ok := cpuResp.Data.Result[0].Value[1].(string); ok {
-			fmt.Sscanf(val, "%f", &metrics.CPUUsage)
-		}
+	if value, err := c.queryScalar(cpuQuery); err == nil {
+		metrics.CPUUsage = value
 	}
 
 	// 内存使用率
 	memQuery := fmt.Sprintf(`(1 - (node_memory_MemAvailable_bytes{instance=~"%s.*"} / node_memory_MemTotal_bytes{instance=~"%s.*"})) * 100`, nodeName, nodeName)
-	memResp, err := c.Query(memQuery)
-	if err == nil && len(memResp.Data.Result) > 0 {
-		if val, ok := memResp.Data.Result[0].Value[1].(string); ok {
-			fmt.Sscanf(val, "%f", &metrics.MemoryUsage)
+	if value, err := c.queryScalar(memQuery); err == nil {
+		metrics.MemoryUsage = value
+	}
+
+	// 磁盘空间与 inode 用量
+	filesystems, err := c.GetNodeFilesystems(nodeName)
+	if err == nil {
+		metrics.Filesystems = filesystems
+		for _, fs := range filesystems {
+			metrics.DiskUsage = math.Max(metrics.DiskUsage, fs.UsagePercent)
+			metrics.InodeUsage = math.Max(metrics.InodeUsage, fs.InodesUsagePercent)
 		}
 	}
 
 	return metrics, nil
 }
 
-// GetPodMetrics 获取 Pod 指标
+// GetPodMetrics 获取 Pod 指标。单项查询失败或没有数据时记录在对应字段的状态中，
+// 两项查询均失败（数据源不可用）时返回错误
 func (c *Client) GetPodMetrics(namespace, podName string) (*PodMetrics, error) {
 	metrics := &PodMetrics{
 		Namespace: namespace,
@@ -258,32 +474,30 @@ func (c *Client) GetPodMetrics(namespace, podName string) (*PodMetrics, error) {
 
 	// CPU 使用量
 	cpuQuery := fmt.Sprintf(`sum(rate(container_cpu_usage_seconds_total{namespace="%s",pod="%s",container!="",container!="POD"}[5m]))`, namespace, podName)
-	cpuResp, err := c.Query(cpuQuery)
-	if err == nil && len(cpuResp.Data.Result) > 0 {
-		if val, ok := cpuResp.Data.Result[0].Value[1].(string); ok {
-			fmt.Sscanf(val, "%f", &metrics.CPUUsage)
-		}
-	}
+	value, err := c.queryScalar(cpuQuery)
+	metrics.CPUUsage, metrics.CPUStatus = value, metrics.status(err)
 
 	// 内存使用量
 	memQuery := fmt.Sprintf(`sum(container_memory_working_set_bytes{namespace="%s",pod="%s",container!="",container!="POD"})`, namespace, podName)
-	memResp, err := c.Query(memQuery)
-	if err == nil && len(memResp.Data.Result) > 0 {
-		if val, ok := memResp.Data.Result[0].Value[1].(string); ok {
-			fmt.Sscanf(val, "%f", &metrics.MemoryUsage)
-		}
-	}
+	value, err = c.queryScalar(memQuery)
+	metrics.MemoryUsage, metrics.MemoryStatus = value, metrics.status(err)
 
+	if metrics.CPUStatus == StatusUnavailable && metrics.MemoryStatus == StatusUnavailable {
+		return nil, fmt.Errorf("查询 Pod 指标失败: %s", metrics.Errors[0])
+	}
 	return metrics, nil
 }
 
-// GetAllPodMetrics 批量获取所有 Pod 的指标
-func (c *Client) GetAllPodMetrics() ([]PodMetrics, error) {
+// GetAllPodMetrics 批量获取 Pod 指标，namespaces 非空时仅查询这些命名空间
+func (c *Client) GetAllPodMetrics(namespaces []string) ([]PodMetrics, error) {
 	var result []PodMetrics
 	podMetricsMap := make(map[string]*PodMetrics)
 
 	// 批量查询所有 Pod 的 CPU 使用量
-	cpuQuery := `sum by (namespace, pod) (rate(container_cpu_usage_seconds_total{container!="",container!="POD"}[5m]))`
+	cpuQuery, err := ScopeQuery(`sum by (namespace, pod) (rate(container_cpu_usage_seconds_total{container!="",container!="POD"}[5m]))`, namespaces)
+	if err != nil {
+		return nil, err
+	}
 	cpuResp, err := c.Query(cpuQuery)
 	if err != nil {
 		return nil, fmt.Errorf("查询 CPU 指标失败: %w", err)
@@ -304,15 +518,16 @@ func (c *Client) GetAllPodMetrics() ([]PodMetrics, error) {
 			}
 		}
 
-		if len(res.Value) >= 2 {
-			if val, ok := res.Value[1].(string); ok {
-				fmt.Sscanf(val, "%f", &podMetricsMap[key].CPUUsage)
-			}
+		if value, err := res.SampleValue(); err == nil {
+			podMetricsMap[key].CPUUsage = value
 		}
 	}
 
 	// 批量查询所有 Pod 的内存使用量
-	memQuery := `sum by (namespace, pod) (container_memory_working_set_bytes{container!="",container!="POD"})`
+	memQuery, err := ScopeQuery(`sum by (namespace, pod) (container_memory_working_set_bytes{container!="",container!="POD"})`, namespaces)
+	if err != nil {
+		return nil, err
+	}
 	memResp, err := c.Query(memQuery)
 	if err != nil {
 		return nil, fmt.Errorf("查询内存指标失败: %w", err)
@@ -333,10 +548,8 @@ func (c *Client) GetAllPodMetrics() ([]PodMetrics, error) {
 			}
 		}
 
-		if len(res.Value) >= 2 {
-			if val, ok := res.Value[1].(string); ok {
-				fmt.Sscanf(val, "%f", &podMetricsMap[key].MemoryUsage)
-			}
+		if value, err := res.SampleValue(); err == nil {
+			podMetricsMap[key].MemoryUsage = value
 		}
 	}
 
@@ -354,12 +567,15 @@ type TimeSeriesData struct {
 	Value     float64 `json:"value"`
 }
 
-// GetCPUHistory 获取 CPU 历史数据
-func (c *Client) GetCPUHistory(duration string, step string) ([]TimeSeriesData, error) {
+// GetCPUHistory 获取 CPU 历史数据，namespaces 非空时仅统计这些命名空间
+func (c *Client) GetCPUHistory(duration string, step string, namespaces []string) ([]TimeSeriesData, error) {
 	end := time.Now()
 	start := end.Add(-parseDuration(duration))
 
-	query := `sum(rate(container_cpu_usage_seconds_total{container!="",container!="POD"}[5m]))`
+	query, err := ScopeQuery(`sum(rate(container_cpu_usage_seconds_total{container!="",container!="POD"}[5m]))`, namespaces)
+	if err != nil {
+		return nil, err
+	}
 	resp, err := c.QueryRange(query, start, end, step)
 	if err != nil {
 		return nil, err
@@ -368,12 +584,15 @@ func (c *Client) GetCPUHistory(duration string, step string) ([]TimeSeriesData,
 	return extractTimeSeries(resp), nil
 }
 
-// GetMemoryHistory 获取内存历史数据
-func (c *Client) GetMemoryHistory(duration string, step string) ([]TimeSeriesData, error) {
+// GetMemoryHistory 获取内存历史数据，namespaces 非空时仅统计这些命名空间
+func (c *Client) GetMemoryHistory(duration string, step string, namespaces []string) ([]TimeSeriesData, error) {
 	end := time.Now()
 	start := end.Add(-parseDuration(duration))
 
-	query := `sum(container_memory_working_set_bytes{container!="",container!="POD"}) / 1024 / 1024 / 1024`
+	query, err := ScopeQuery(`sum(container_memory_working_set_bytes{container!="",container!="POD"}) / 1024 / 1024 / 1024`, namespaces)
+	if err != nil {
+		return nil, err
+	}
 	resp, err := c.QueryRange(query, start, end, step)
 	if err != nil {
 		return nil, err
@@ -382,6 +601,33 @@ func (c *Client) GetMemoryHistory(duration string, step string) ([]TimeSeriesDat
 	return extractTimeSeries(resp), nil
 }
 
+// PodHistory 单个 Pod 的用量历史，CPU 单位为 cores，内存单位为 bytes（working set）
+type PodHistory struct {
+	CPU    []TimeSeriesData `json:"cpu"`
+	Memory []TimeSeriesData `json:"memory"`
+}
+
+// GetPodHistory 获取单个 Pod 的 CPU 与内存历史数据，duration/step 与集群级历史接口一致
+func (c *Client) GetPodHistory(namespace, podName, duration, step string) (*PodHistory, error) {
+	end := time.Now()
+	start := end.Add(-parseDuration(duration))
+	selector := fmt.Sprintf(`namespace=%q,pod=%q,container!="",container!="POD"`, namespace, podName)
+
+	cpuResp, err := c.QueryRange(fmt.Sprintf(`sum(rate(container_cpu_usage_seconds_total{%s}[5m]))`, selector), start, end, step)
+	if err != nil {
+		return nil, fmt.Errorf("查询 CPU 历史失败: %w", err)
+	}
+	memResp, err := c.QueryRange(fmt.Sprintf(`sum(container_memory_working_set_bytes{%s})`, selector), start, end, step)
+	if err != nil {
+		return nil, fmt.Errorf("查询内存历史失败: %w", err)
+	}
+
+	return &PodHistory{
+		CPU:    extractTimeSeries(cpuResp),
+		Memory: extractTimeSeries(memResp),
+	}, nil
+}
+
 // 解析时间范围
 func parseDuration(d string) time.Duration {
 	switch d {
@@ -406,17 +652,19 @@ func extractTimeSeries(resp *QueryResponse) []TimeSeriesData {
 	}
 
 	for _, v := range resp.Data.Result[0].Values {
-		if len(v) >= 2 {
-			ts, _ := v[0].(float64)
-			var val float64
-			if strVal, ok := v[1].(string); ok {
-				fmt.Sscanf(strVal, "%f", &val)
-			}
-			result = append(result, TimeSeriesData{
-				Timestamp: int64(ts),
-				Value:     val,
-			})
+		if len(v) < 2 {
+			continue
+		}
+		ts, _ := v[0].(float64)
+		// 无法解析或不是有限数的点（如除以 0 得到的 NaN）跳过，图表显示为断点而不是 0
+		val, err := ParseSampleValue(v[1])
+		if err != nil {
+			continue
 		}
+		result = append(result, TimeSeriesData{
+			Timestamp: int64(ts),
+			Value:     val,
+		})
 	}
 
 	return result
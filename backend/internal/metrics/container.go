@@ -0,0 +1,124 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ContainerMetrics 单个容器的用量、requests/limits 与 CPU 限流情况，requests/limits 为 0 表示未设置
+type ContainerMetrics struct {
+	Name          string  `json:"name"`
+	CPUUsage      float64 `json:"cpuUsage"`    // cores
+	MemoryUsage   float64 `json:"memoryUsage"` // bytes（working set）
+	CPURequest    float64 `json:"cpuRequest"`
+	CPULimit      float64 `json:"cpuLimit"`
+	MemoryRequest float64 `json:"memoryRequest"`
+	MemoryLimit   float64 `json:"memoryLimit"`
+	// CPUThrottledPercent 近 5 分钟被 CFS 限流的调度周期占比，未设置 CPU limit 的容器通常为 0
+	CPUThrottledPercent float64 `json:"cpuThrottledPercent"`
+	// CPUThrottledSeconds 近 5 分钟平均每秒被限流的时长
+	CPUThrottledSeconds float64 `json:"cpuThrottledSeconds"`
+}
+
+// PodContainerMetrics Pod 内各容器的指标
+type PodContainerMetrics struct {
+	Namespace  string             `json:"namespace"`
+	Pod        string             `json:"pod"`
+	Containers []ContainerMetrics `json:"containers"`
+}
+
+// containerQueries 按容器聚合的 cAdvisor 指标，%[1]s 为标签选择器
+var containerQueries = map[string]string{
+	"cpu":              `sum by (container) (rate(container_cpu_usage_seconds_total{%[1]s}[5m]))`,
+	"memory":           `sum by (container) (container_memory_working_set_bytes{%[1]s})`,
+	"throttled_ratio":  `sum by (container) (rate(container_cpu_cfs_throttled_periods_total{%[1]s}[5m])) / sum by (container) (rate(container_cpu_cfs_periods_total{%[1]s}[5m]))`,
+	"throttled_second": `sum by (container) (rate(container_cpu_cfs_throttled_seconds_total{%[1]s}[5m]))`,
+}
+
+// GetContainerMetrics 获取 Pod 内各容器的 CPU、内存用量与 CPU 限流，用于定位 Pod 中占用资源最多的容器。
+// requests/limits 不在指标中查询，由调用方通过 ApplyPodSpec 从 Pod 定义补充
+func (c *Client) GetContainerMetrics(namespace, podName string) (*PodContainerMetrics, error) {
+	selector := fmt.Sprintf(`namespace=%q,pod=%q,container!="",container!="POD"`, namespace, podName)
+	containers := make(map[string]*ContainerMetrics)
+	for field, query := range containerQueries {
+		resp, err := c.Query(fmt.Sprintf(query, selector))
+		if err != nil {
+			return nil, fmt.Errorf("查询容器指标失败: %w", err)
+		}
+		for _, res := range resp.Data.Result {
+			name := res.Metric["container"]
+			if name == "" {
+				continue
+			}
+			// 未限流的容器周期数为 0 时比值为 NaN，SampleValue 会返回错误
+			value, err := res.SampleValue()
+			if err != nil {
+				continue
+			}
+
+			container, exists := containers[name]
+			if !exists {
+				container = &ContainerMetrics{Name: name}
+				containers[name] = container
+			}
+			switch field {
+			case "cpu":
+				container.CPUUsage = value
+			case "memory":
+				container.MemoryUsage = value
+			case "throttled_ratio":
+				container.CPUThrottledPercent = value * 100
+			case "throttled_second":
+				container.CPUThrottledSeconds = value
+			}
+		}
+	}
+
+	result := &PodContainerMetrics{Namespace: namespace, Pod: podName, Containers: make([]ContainerMetrics, 0, len(containers))}
+	for _, container := range containers {
+		result.Containers = append(result.Containers, *container)
+	}
+	sort.Slice(result.Containers, func(i, j int) bool {
+		return result.Containers[i].Name < result.Containers[j].Name
+	})
+	return result, nil
+}
+
+// ApplyPodSpec 按 Pod 定义补充各容器的 requests/limits，并按定义顺序排列（边车 init 容器在前）；
+// 尚无指标数据的容器以 0 用量列出，已不在定义中的容器排在最后
+func (m *PodContainerMetrics) ApplyPodSpec(pod *corev1.Pod) {
+	specs := make([]corev1.Container, 0, len(pod.Spec.InitContainers)+len(pod.Spec.Containers))
+	for _, container := range pod.Spec.InitContainers {
+		// 只有 restartPolicy: Always 的边车容器会持续运行
+		if container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			specs = append(specs, container)
+		}
+	}
+	specs = append(specs, pod.Spec.Containers...)
+
+	existing := make(map[string]ContainerMetrics, len(m.Containers))
+	for _, container := range m.Containers {
+		existing[container.Name] = container
+	}
+	ordered := make([]ContainerMetrics, 0, len(specs)+len(m.Containers))
+	for _, spec := range specs {
+		container, ok := existing[spec.Name]
+		if !ok {
+			container = ContainerMetrics{Name: spec.Name}
+		}
+		delete(existing, spec.Name)
+		container.CPURequest = spec.Resources.Requests.Cpu().AsApproximateFloat64()
+		container.CPULimit = spec.Resources.Limits.Cpu().AsApproximateFloat64()
+		container.MemoryRequest = spec.Resources.Requests.Memory().AsApproximateFloat64()
+		container.MemoryLimit = spec.Resources.Limits.Memory().AsApproximateFloat64()
+		ordered = append(ordered, container)
+	}
+	for _, container := range m.Containers {
+		if _, ok := existing[container.Name]; ok {
+			ordered = append(ordered, container)
+		}
+	}
+	m.Containers = ordered
+}
@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+)
+
+// GPUMetrics 单块 GPU 的实时指标，来自 NVIDIA dcgm-exporter。
+// GPU 被 Pod 占用时 Namespace/Pod/Container 为占用者（需开启 dcgm-exporter 的 Kubernetes 映射）
+type GPUMetrics struct {
+	Node               string  `json:"node"`
+	GPU                string  `json:"gpu"` // 设备序号
+	UUID               string  `json:"uuid"`
+	Model              string  `json:"model"`
+	Namespace          string  `json:"namespace,omitempty"`
+	Pod                string  `json:"pod,omitempty"`
+	Container          string  `json:"container,omitempty"`
+	Utilization        float64 `json:"utilization"` // 百分比
+	MemoryUsedBytes    float64 `json:"memoryUsedBytes"`
+	MemoryTotalBytes   float64 `json:"memoryTotalBytes"`
+	MemoryUsagePercent float64 `json:"memoryUsagePercent"`
+	TemperatureCelsius float64 `json:"temperatureCelsius"`
+	PowerWatts         float64 `json:"powerWatts"`
+}
+
+// gpuQueries DCGM 指标，显存（FB）单位为 MiB
+var gpuQueries = map[string]string{
+	"util":    `DCGM_FI_DEV_GPU_UTIL`,
+	"fb_used": `DCGM_FI_DEV_FB_USED`,
+	"fb_free": `DCGM_FI_DEV_FB_FREE`,
+	"temp":    `DCGM_FI_DEV_GPU_TEMP`,
+	"power":   `DCGM_FI_DEV_POWER_USAGE`,
+}
+
+// gpuNodeLabels dcgm-exporter 所在节点的标签，不同部署方式不同，依次取第一个存在的
+var gpuNodeLabels = []string{"Hostname", "kubernetes_node", "node", "instance"}
+
+// GetGPUMetrics 获取所有 GPU 的利用率、显存、温度与功耗。未部署 dcgm-exporter 时返回空列表
+func (c *Client) GetGPUMetrics() ([]GPUMetrics, error) {
+	gpus := make(map[string]*GPUMetrics)
+	for field, query := range gpuQueries {
+		resp, err := c.Query(query)
+		if err != nil {
+			return nil, fmt.Errorf("查询 GPU 指标失败: %w", err)
+		}
+		for _, res := range resp.Data.Result {
+			value, err := res.SampleValue()
+			if err != nil {
+				continue
+			}
+
+			node := firstLabel(res.Metric, gpuNodeLabels...)
+			key := res.Metric["UUID"]
+			if key == "" {
+				key = node + "/" + res.Metric["gpu"]
+			}
+			gpu, exists := gpus[key]
+			if !exists {
+				// 经 Prometheus Operator 采集时与采集目标冲突的标签会被改名为 exported_*
+				gpu = &GPUMetrics{
+					Node:      node,
+					GPU:       res.Metric["gpu"],
+					UUID:      res.Metric["UUID"],
+					Model:     res.Metric["modelName"],
+					Namespace: firstLabel(res.Metric, "exported_namespace", "namespace"),
+					Pod:       firstLabel(res.Metric, "exported_pod", "pod"),
+					Container: firstLabel(res.Metric, "exported_container", "container"),
+				}
+				gpus[key] = gpu
+			}
+			switch field {
+			case "util":
+				gpu.Utilization = value
+			case "fb_used":
+				gpu.MemoryUsedBytes = value * 1024 * 1024
+			case "fb_free":
+				gpu.MemoryTotalBytes += value * 1024 * 1024
+			case "temp":
+				gpu.TemperatureCelsius = value
+			case "power":
+				gpu.PowerWatts = value
+			}
+		}
+	}
+
+	result := make([]GPUMetrics, 0, len(gpus))
+	for _, gpu := range gpus {
+		gpu.MemoryTotalBytes += gpu.MemoryUsedBytes
+		if gpu.MemoryTotalBytes > 0 {
+			gpu.MemoryUsagePercent = gpu.MemoryUsedBytes / gpu.MemoryTotalBytes * 100
+		}
+		result = append(result, *gpu)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Node != result[j].Node {
+			return result[i].Node < result[j].Node
+		}
+		return result[i].GPU < result[j].GPU
+	})
+	return result, nil
+}
+
+func firstLabel(labels map[string]string, keys ...string) string {
+	for _, key := range keys {
+		if value := labels[key]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,113 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+)
+
+// PodNetworkMetrics Pod 网络流量速率（近 5 分钟平均），来自 cAdvisor 的 container_network_*_bytes_total 指标。
+// 使用 hostNetwork 的 Pod 统计的是所在节点网卡的流量
+type PodNetworkMetrics struct {
+	Namespace              string  `json:"namespace"`
+	Pod                    string  `json:"pod"`
+	ReceiveBytesPerSecond  float64 `json:"receiveBytesPerSecond"`
+	TransmitBytesPerSecond float64 `json:"transmitBytesPerSecond"`
+}
+
+// NamespaceNetworkMetrics 命名空间内所有 Pod 的网络流量速率之和
+type NamespaceNetworkMetrics struct {
+	Namespace              string  `json:"namespace"`
+	Pods                   int     `json:"pods"`
+	ReceiveBytesPerSecond  float64 `json:"receiveBytesPerSecond"`
+	TransmitBytesPerSecond float64 `json:"transmitBytesPerSecond"`
+}
+
+// podNetworkQueries 按 Pod 聚合的网络速率，排除回环网卡
+var podNetworkQueries = map[string]string{
+	"receive":  `sum by (namespace, pod) (rate(container_network_receive_bytes_total{pod!="",interface!="lo"}[5m]))`,
+	"transmit": `sum by (namespace, pod) (rate(container_network_transmit_bytes_total{pod!="",interface!="lo"}[5m]))`,
+}
+
+// GetPodNetworkMetrics 批量获取 Pod 的网络收发速率，按收发合计降序排列；namespaces 非空时仅查询这些命名空间
+func (c *Client) GetPodNetworkMetrics(namespaces []string) ([]PodNetworkMetrics, error) {
+	pods := make(map[string]*PodNetworkMetrics)
+	for field, query := range podNetworkQueries {
+		scoped, err := ScopeQuery(query, namespaces)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.Query(scoped)
+		if err != nil {
+			return nil, fmt.Errorf("查询网络流量指标失败: %w", err)
+		}
+		for _, res := range resp.Data.Result {
+			ns, pod := res.Metric["namespace"], res.Metric["pod"]
+			if ns == "" || pod == "" {
+				continue
+			}
+			value, err := res.SampleValue()
+			if err != nil {
+				continue
+			}
+
+			key := ns + "/" + pod
+			item, exists := pods[key]
+			if !exists {
+				item = &PodNetworkMetrics{Namespace: ns, Pod: pod}
+				pods[key] = item
+			}
+			switch field {
+			case "receive":
+				item.ReceiveBytesPerSecond = value
+			case "transmit":
+				item.TransmitBytesPerSecond = value
+			}
+		}
+	}
+
+	result := make([]PodNetworkMetrics, 0, len(pods))
+	for _, item := range pods {
+		result = append(result, *item)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		ti := result[i].ReceiveBytesPerSecond + result[i].TransmitBytesPerSecond
+		tj := result[j].ReceiveBytesPerSecond + result[j].TransmitBytesPerSecond
+		if ti != tj {
+			return ti > tj
+		}
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].Pod < result[j].Pod
+	})
+	return result, nil
+}
+
+// AggregateNamespaceNetwork 按命名空间汇总 Pod 网络速率，按收发合计降序排列
+func AggregateNamespaceNetwork(pods []PodNetworkMetrics) []NamespaceNetworkMetrics {
+	namespaces := make(map[string]*NamespaceNetworkMetrics)
+	for _, pod := range pods {
+		item, exists := namespaces[pod.Namespace]
+		if !exists {
+			item = &NamespaceNetworkMetrics{Namespace: pod.Namespace}
+			namespaces[pod.Namespace] = item
+		}
+		item.Pods++
+		item.ReceiveBytesPerSecond += pod.ReceiveBytesPerSecond
+		item.TransmitBytesPerSecond += pod.TransmitBytesPerSecond
+	}
+
+	result := make([]NamespaceNetworkMetrics, 0, len(namespaces))
+	for _, item := range namespaces {
+		result = append(result, *item)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		ti := result[i].ReceiveBytesPerSecond + result[i].TransmitBytesPerSecond
+		tj := result[j].ReceiveBytesPerSecond + result[j].TransmitBytesPerSecond
+		if ti != tj {
+			return ti > tj
+		}
+		return result[i].Namespace < result[j].Namespace
+	})
+	return result
+}
@@ -0,0 +1,162 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// NodeFilesystem 节点文件系统的空间与 inode 用量，来自 node_exporter 的 node_filesystem_* 指标
+type NodeFilesystem struct {
+	Mountpoint         string  `json:"mountpoint"`
+	Device             string  `json:"device"`
+	FSType             string  `json:"fstype"`
+	UsedBytes          float64 `json:"usedBytes"`
+	TotalBytes         float64 `json:"totalBytes"`
+	AvailableBytes     float64 `json:"availableBytes"`
+	UsagePercent       float64 `json:"usagePercent"`
+	InodesUsed         float64 `json:"inodesUsed"`
+	Inodes             float64 `json:"inodes"`
+	InodesUsagePercent float64 `json:"inodesUsagePercent"`
+}
+
+// nodeFilesystemFilter 排除内存文件系统与容器运行时的 overlay 挂载
+const nodeFilesystemFilter = `fstype!="",fstype!~"tmpfs|ramfs|overlay|squashfs|nsfs|fuse\\..*"`
+
+// nodeNetworkFilter 只统计物理网卡，排除回环与 CNI 创建的虚拟网卡
+const nodeNetworkFilter = `device!~"lo|veth.*|cali.*|cni.*|flannel.*|docker.*|cilium.*|lxc.*|tunl.*|vxlan.*|kube-.*"`
+
+// nodeSelector 与 GetNodeMetrics 一致，按 instance 前缀匹配节点的 node_exporter
+func nodeSelector(nodeName string) string {
+	return fmt.Sprintf(`instance=~"%s.*"`, nodeName)
+}
+
+// nodeFilesystemQueries 节点文件系统指标，%[1]s 为标签选择器
+var nodeFilesystemQueries = map[string]string{
+	"size":       `max by (mountpoint, device, fstype) (node_filesystem_size_bytes{%[1]s})`,
+	"avail":      `max by (mountpoint, device, fstype) (node_filesystem_avail_bytes{%[1]s})`,
+	"files":      `max by (mountpoint, device, fstype) (node_filesystem_files{%[1]s})`,
+	"files_free": `max by (mountpoint, device, fstype) (node_filesystem_files_free{%[1]s})`,
+}
+
+// GetNodeFilesystems 获取节点各文件系统的空间与 inode 用量，按挂载点排序
+func (c *Client) GetNodeFilesystems(nodeName string) ([]NodeFilesystem, error) {
+	selector := nodeSelector(nodeName) + "," + nodeFilesystemFilter
+	filesystems := make(map[string]*NodeFilesystem)
+	freeInodes := make(map[string]float64)
+	for field, query := range nodeFilesystemQueries {
+		resp, err := c.Query(fmt.Sprintf(query, selector))
+		if err != nil {
+			return nil, fmt.Errorf("查询节点文件系统指标失败: %w", err)
+		}
+		for _, res := range resp.Data.Result {
+			mountpoint := res.Metric["mountpoint"]
+			if mountpoint == "" {
+				continue
+			}
+			value, err := res.SampleValue()
+			if err != nil {
+				continue
+			}
+
+			key := mountpoint + "|" + res.Metric["device"]
+			fs, exists := filesystems[key]
+			if !exists {
+				fs = &NodeFilesystem{Mountpoint: mountpoint, Device: res.Metric["device"], FSType: res.Metric["fstype"]}
+				filesystems[key] = fs
+			}
+			switch field {
+			case "size":
+				fs.TotalBytes = value
+			case "avail":
+				fs.AvailableBytes = value
+			case "files":
+				fs.Inodes = value
+			case "files_free":
+				freeInodes[key] = value
+			}
+		}
+	}
+
+	result := make([]NodeFilesystem, 0, len(filesystems))
+	for key, fs := range filesystems {
+		if fs.TotalBytes > 0 {
+			fs.UsedBytes = fs.TotalBytes - fs.AvailableBytes
+			fs.UsagePercent = fs.UsedBytes / fs.TotalBytes * 100
+		}
+		if fs.Inodes > 0 {
+			fs.InodesUsed = fs.Inodes - freeInodes[key]
+			fs.InodesUsagePercent = fs.InodesUsed / fs.Inodes * 100
+		}
+		result = append(result, *fs)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Mountpoint != result[j].Mountpoint {
+			return result[i].Mountpoint < result[j].Mountpoint
+		}
+		return result[i].Device < result[j].Device
+	})
+	return result, nil
+}
+
+// NodeHistory 节点用量历史：CPU、内存、磁盘空间与磁盘 IO 繁忙度为百分比，网络为 bytes/s，负载为原始 load average
+type NodeHistory struct {
+	CPU             []TimeSeriesData `json:"cpu"`
+	Memory          []TimeSeriesData `json:"memory"`
+	Disk            []TimeSeriesData `json:"disk"`   // 使用率最高的文件系统
+	DiskIO          []TimeSeriesData `json:"diskIO"` // 最繁忙磁盘的 IO 时间占比
+	NetworkReceive  []TimeSeriesData `json:"networkReceive"`
+	NetworkTransmit []TimeSeriesData `json:"networkTransmit"`
+	Load1           []TimeSeriesData `json:"load1"`
+	Load5           []TimeSeriesData `json:"load5"`
+	Load15          []TimeSeriesData `json:"load15"`
+}
+
+// nodeHistoryQueries 节点历史曲线的查询，%[1]s 为节点选择器，%[2]s 为文件系统过滤，%[3]s 为网卡过滤
+var nodeHistoryQueries = map[string]string{
+	"cpu":     `100 - (avg(rate(node_cpu_seconds_total{mode="idle",%[1]s}[5m])) * 100)`,
+	"memory":  `(1 - (sum(node_memory_MemAvailable_bytes{%[1]s}) / sum(node_memory_MemTotal_bytes{%[1]s}))) * 100`,
+	"disk":    `max((1 - node_filesystem_avail_bytes{%[1]s,%[2]s} / node_filesystem_size_bytes{%[1]s,%[2]s}) * 100)`,
+	"disk_io": `max(rate(node_disk_io_time_seconds_total{%[1]s}[5m])) * 100`,
+	"rx":      `sum(rate(node_network_receive_bytes_total{%[1]s,%[3]s}[5m]))`,
+	"tx":      `sum(rate(node_network_transmit_bytes_total{%[1]s,%[3]s}[5m]))`,
+	"load1":   `max(node_load1{%[1]s})`,
+	"load5":   `max(node_load5{%[1]s})`,
+	"load15":  `max(node_load15{%[1]s})`,
+}
+
+// GetNodeHistory 获取节点 CPU、内存、磁盘、网络与负载的历史数据，duration/step 与集群级历史接口一致
+func (c *Client) GetNodeHistory(nodeName, duration, step string) (*NodeHistory, error) {
+	end := time.Now()
+	start := end.Add(-parseDuration(duration))
+
+	history := &NodeHistory{}
+	for field, query := range nodeHistoryQueries {
+		resp, err := c.QueryRange(fmt.Sprintf(query, nodeSelector(nodeName), nodeFilesystemFilter, nodeNetworkFilter), start, end, step)
+		if err != nil {
+			return nil, fmt.Errorf("查询节点历史失败: %w", err)
+		}
+		series := extractTimeSeries(resp)
+		switch field {
+		case "cpu":
+			history.CPU = series
+		case "memory":
+			history.Memory = series
+		case "disk":
+			history.Disk = series
+		case "disk_io":
+			history.DiskIO = series
+		case "rx":
+			history.NetworkReceive = series
+		case "tx":
+			history.NetworkTransmit = series
+		case "load1":
+			history.Load1 = series
+		case "load5":
+			history.Load5 = series
+		case "load15":
+			history.Load15 = series
+		}
+	}
+	return history, nil
+}
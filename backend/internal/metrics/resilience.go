@@ -0,0 +1,262 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen 数据源连续请求失败后熔断，冷却期内的查询直接失败而不再等待超时
+var ErrCircuitOpen = errors.New("监控数据源连续请求失败，暂停查询")
+
+// maxCacheEntries 结果缓存的最大条数
+const maxCacheEntries = 1000
+
+// maxResponseBytes 单次查询响应的最大字节数
+const maxResponseBytes = 64 << 20
+
+// Config 查询客户端的超时、重试、熔断与结果缓存
+type Config struct {
+	// Timeout 单次请求超时（Go 时长）
+	Timeout string `json:"timeout"`
+	// Retries 网络错误、5xx 或 429 时的重试次数，0 表示不重试
+	Retries int `json:"retries"`
+	// RetryBackoff 首次重试前的等待时间，之后每次翻倍
+	RetryBackoff string `json:"retryBackoff"`
+	// BreakerThreshold 连续失败多少次（重试之后）后熔断，0 表示不熔断
+	BreakerThreshold int `json:"breakerThreshold"`
+	// BreakerCooldown 熔断持续时间，到期后放行一个请求试探数据源是否恢复
+	BreakerCooldown string `json:"breakerCooldown"`
+	// CacheTTL 相同查询结果的缓存时长，0 表示不缓存
+	CacheTTL string `json:"cacheTtl"`
+}
+
+// DefaultConfig 单次请求 10 秒超时、最多重试 2 次，连续失败 5 次后熔断 30 秒，结果缓存 10 秒
+func DefaultConfig() Config {
+	return Config{
+		Timeout:          "10s",
+		Retries:          2,
+		RetryBackoff:     "200ms",
+		BreakerThreshold: 5,
+		BreakerCooldown:  "30s",
+		CacheTTL:         "10s",
+	}
+}
+
+// Validate 校验客户端配置
+func (cfg Config) Validate() error {
+	var errs []error
+	if d, err := time.ParseDuration(cfg.Timeout); err != nil || d <= 0 {
+		errs = append(errs, fmt.Errorf("METRICS_TIMEOUT 无效: %q", cfg.Timeout))
+	}
+	if cfg.Retries < 0 || cfg.Retries > 10 {
+		errs = append(errs, fmt.Errorf("METRICS_RETRIES 必须在 0-10 之间: %d", cfg.Retries))
+	}
+	if d, err := time.ParseDuration(cfg.RetryBackoff); err != nil || d < 0 {
+		errs = append(errs, fmt.Errorf("METRICS_RETRY_BACKOFF 无效: %q", cfg.RetryBackoff))
+	}
+	if cfg.BreakerThreshold < 0 {
+		errs = append(errs, fmt.Errorf("METRICS_BREAKER_THRESHOLD 不能为负数: %d", cfg.BreakerThreshold))
+	}
+	if d, err := time.ParseDuration(cfg.BreakerCooldown); err != nil || d <= 0 {
+		errs = append(errs, fmt.Errorf("METRICS_BREAKER_COOLDOWN 无效: %q", cfg.BreakerCooldown))
+	}
+	if d, err := time.ParseDuration(cfg.CacheTTL); err != nil || d < 0 {
+		errs = append(errs, fmt.Errorf("METRICS_CACHE_TTL 无效: %q", cfg.CacheTTL))
+	}
+	return errors.Join(errs...)
+}
+
+// newHTTPClient 创建带连接池的 HTTP 客户端，默认 Transport 每个主机只保留 2 个空闲连接，
+// 概览等页面并发查询时会频繁重建连接
+func newHTTPClient(timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = 100
+	transport.MaxIdleConnsPerHost = 32
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// breaker 熔断器：连续失败达到阈值后打开，冷却期内拒绝请求；冷却结束后放行一个试探请求，成功则恢复
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// allow 判断是否放行请求，放行后必须以返回的 probe 调用 done；probe 为 true 表示该请求是冷却结束后的试探请求
+func (b *breaker) allow() (probe bool, err error) {
+	if b.threshold <= 0 {
+		return false, nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return false, nil
+	}
+	if wait := time.Until(b.openUntil); wait > 0 {
+		return false, fmt.Errorf("%w，%s 后重试", ErrCircuitOpen, wait.Round(time.Second))
+	}
+	if b.probing {
+		return false, fmt.Errorf("%w，正在检测数据源是否恢复", ErrCircuitOpen)
+	}
+	b.probing = true
+	return true, nil
+}
+
+// done 记录请求结果；只有试探请求结束时才清除试探标记，熔断前已发出的请求不会放行第二个试探请求。
+// 调用方取消的请求不计入失败
+func (b *breaker) done(probe bool, err error) {
+	if b.threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if probe {
+		b.probing = false
+	}
+	switch {
+	case err == nil:
+		b.failures = 0
+	case errors.Is(err, context.Canceled):
+	default:
+		b.failures++
+		if b.failures >= b.threshold {
+			b.openUntil = time.Now().Add(b.cooldown)
+		}
+	}
+}
+
+// resultCache 按请求 URL 缓存成功的查询响应体，每次读取时重新解析，调用方修改结果不影响缓存
+type resultCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cachedResult
+}
+
+type cachedResult struct {
+	body    []byte
+	expires time.Time
+}
+
+func (rc *resultCache) get(key string) ([]byte, bool) {
+	if rc.ttl <= 0 {
+		return nil, false
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	entry, ok := rc.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.body, true
+}
+
+func (rc *resultCache) put(key string, body []byte) {
+	if rc.ttl <= 0 {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	now := time.Now()
+	if len(rc.entries) >= maxCacheEntries {
+		for k, v := range rc.entries {
+			if now.After(v.expires) {
+				delete(rc.entries, k)
+			}
+		}
+		if len(rc.entries) >= maxCacheEntries {
+			return
+		}
+	}
+	rc.entries[key] = cachedResult{body: body, expires: now.Add(rc.ttl)}
+}
+
+// fetch 发送 GET 请求并返回响应体：命中缓存直接返回，熔断打开时立即失败；
+// 网络错误、5xx 与 429 按指数退避重试，其它状态码（如查询语法错误的 4xx）原样返回响应体
+func (c *Client) fetch(rawURL string) ([]byte, error) {
+	if body, ok := c.cache.get(rawURL); ok {
+		return body, nil
+	}
+	probe, err := c.breaker.allow()
+	if err != nil {
+		return nil, err
+	}
+
+	body, status, err := c.fetchWithRetry(rawURL)
+	c.breaker.done(probe, err)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusOK {
+		c.cache.put(rawURL, body)
+	}
+	return body, nil
+}
+
+func (c *Client) fetchWithRetry(rawURL string) ([]byte, int, error) {
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	backoff := c.retryBackoff
+	for attempt := 0; ; attempt++ {
+		body, status, err := c.do(ctx, rawURL)
+		retryable := err != nil || status >= http.StatusInternalServerError || status == http.StatusTooManyRequests
+		if !retryable {
+			return body, status, nil
+		}
+		if err == nil {
+			err = fmt.Errorf("HTTP %d: %s", status, responseError(body))
+		}
+		if attempt >= c.retries || ctx.Err() != nil {
+			return nil, status, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, status, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (c *Client) do(ctx context.Context, rawURL string) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, 0, fmt.Errorf("读取响应失败: %w", err)
+	}
+	return body, resp.StatusCode, nil
+}
+
+// responseError 提取错误响应中的错误信息，非 JSON 响应截取前 200 个字符
+func responseError(body []byte) string {
+	var result QueryResponse
+	if err := json.Unmarshal(body, &result); err == nil && result.Error != "" {
+		return result.Error
+	}
+	msg := strings.TrimSpace(string(body))
+	if len(msg) > 200 {
+		msg = msg[:200] + "..."
+	}
+	return msg
+}
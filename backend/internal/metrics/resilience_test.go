@@ -0,0 +1,216 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newStatusServer 依次返回 statuses 中的状态码（用尽后重复最后一个），记录请求次数
+func newStatusServer(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(hits.Add(1))
+		status := statuses[min(n, len(statuses))-1]
+		w.WriteHeader(status)
+		w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]},"n":` + strconv.Itoa(n) + `}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+func testConfig() Config {
+	return Config{
+		Timeout:          "1s",
+		Retries:          2,
+		RetryBackoff:     "1ms",
+		BreakerThreshold: 0,
+		BreakerCooldown:  "1s",
+		CacheTTL:         "0s",
+	}
+}
+
+func TestBreakerOpensAndRecoversAfterProbe(t *testing.T) {
+	b := &breaker{threshold: 2, cooldown: 50 * time.Millisecond}
+	failure := errors.New("connection refused")
+	for i := 0; i < 2; i++ {
+		probe, err := b.allow()
+		if err != nil || probe {
+			t.Fatalf("request %d before threshold: probe %v err %v", i, probe, err)
+		}
+		b.done(probe, failure)
+	}
+	if _, err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("breaker should be open during cooldown, got %v", err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	probe, err := b.allow()
+	if err != nil || !probe {
+		t.Fatalf("first request after cooldown should be a probe: probe %v err %v", probe, err)
+	}
+	if _, err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("only one probe may be in flight, got %v", err)
+	}
+	// 熔断前发出、之后才结束的请求不能清除试探标记
+	b.done(false, context.Canceled)
+	if _, err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("non-probe completion released a second probe, got %v", err)
+	}
+
+	b.done(probe, nil)
+	if probe, err := b.allow(); err != nil || probe {
+		t.Fatalf("breaker should close after a successful probe: probe %v err %v", probe, err)
+	}
+}
+
+func TestBreakerFailedProbeReopens(t *testing.T) {
+	b := &breaker{threshold: 1, cooldown: 30 * time.Millisecond}
+	b.done(false, errors.New("timeout"))
+	time.Sleep(40 * time.Millisecond)
+
+	probe, err := b.allow()
+	if err != nil || !probe {
+		t.Fatalf("expected probe, got probe %v err %v", probe, err)
+	}
+	b.done(probe, errors.New("timeout"))
+	if _, err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("failed probe should reopen the breaker, got %v", err)
+	}
+}
+
+func TestFetchRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		wantHits int32
+		wantErr  bool
+	}{
+		{name: "5xx then success", statuses: []int{503, 502, 200}, wantHits: 3},
+		{name: "429 then success", statuses: []int{429, 200}, wantHits: 2},
+		{name: "retries exhausted", statuses: []int{500}, wantHits: 3, wantErr: true},
+		{name: "4xx is not retried", statuses: []int{400}, wantHits: 1},
+		{name: "404 is not retried", statuses: []int{404}, wantHits: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, hits := newStatusServer(t, tt.statuses...)
+			c := NewClientWithConfig(server.URL, testConfig())
+			_, err := c.fetch(server.URL + "/api/v1/query")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("fetch error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := hits.Load(); got != tt.wantHits {
+				t.Fatalf("server hits = %d, want %d", got, tt.wantHits)
+			}
+		})
+	}
+}
+
+func TestFetchOpensBreakerAfterRepeatedFailures(t *testing.T) {
+	server, hits := newStatusServer(t, 500)
+	cfg := testConfig()
+	cfg.Retries = 0
+	cfg.BreakerThreshold = 2
+	c := NewClientWithConfig(server.URL, cfg)
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.fetch(server.URL); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("request %d error = %v, want upstream error", i, err)
+		}
+	}
+	if _, err := c.fetch(server.URL); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("error = %v, want ErrCircuitOpen", err)
+	}
+	if got := hits.Load(); got != 2 {
+		t.Fatalf("server hits = %d, open breaker should not reach the server", got)
+	}
+}
+
+func TestFetchCanceledDoesNotCountAsFailure(t *testing.T) {
+	server, hits := newStatusServer(t, 200)
+	cfg := testConfig()
+	cfg.BreakerThreshold = 1
+	c := NewClientWithConfig(server.URL, cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.WithContext(ctx).fetch(server.URL); !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want context.Canceled", err)
+	}
+	if _, err := c.fetch(server.URL); err != nil {
+		t.Fatalf("canceled request tripped the breaker: %v", err)
+	}
+	if got := hits.Load(); got != 1 {
+		t.Fatalf("server hits = %d, want 1", got)
+	}
+}
+
+func TestFetchCachesSuccessfulResponses(t *testing.T) {
+	server, hits := newStatusServer(t, 200)
+	cfg := testConfig()
+	cfg.CacheTTL = "50ms"
+	c := NewClientWithConfig(server.URL, cfg)
+
+	first, err := c.fetch(server.URL)
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	second, err := c.fetch(server.URL)
+	if err != nil || string(second) != string(first) || hits.Load() != 1 {
+		t.Fatalf("second fetch should be served from cache: hits %d err %v", hits.Load(), err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, err := c.fetch(server.URL); err != nil || hits.Load() != 2 {
+		t.Fatalf("expired entry should be refetched: hits %d err %v", hits.Load(), err)
+	}
+}
+
+func TestFetchDoesNotCacheErrorResponses(t *testing.T) {
+	server, hits := newStatusServer(t, 400)
+	cfg := testConfig()
+	cfg.CacheTTL = "1m"
+	c := NewClientWithConfig(server.URL, cfg)
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.fetch(server.URL); err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+	}
+	if got := hits.Load(); got != 2 {
+		t.Fatalf("server hits = %d, 4xx responses must not be cached", got)
+	}
+}
+
+func TestResultCacheEviction(t *testing.T) {
+	rc := &resultCache{ttl: time.Minute, entries: make(map[string]cachedResult)}
+	expired := time.Now().Add(-time.Second)
+	for i := 0; i < maxCacheEntries; i++ {
+		rc.entries[strconv.Itoa(i)] = cachedResult{body: []byte("old"), expires: expired}
+	}
+
+	// 满员时先清理过期条目再写入
+	rc.put("fresh", []byte("new"))
+	if body, ok := rc.get("fresh"); !ok || string(body) != "new" {
+		t.Fatalf("fresh entry not cached after evicting expired entries")
+	}
+	if len(rc.entries) != 1 {
+		t.Fatalf("entries = %d, expired entries should be evicted", len(rc.entries))
+	}
+
+	// 满员且均未过期时不再写入
+	for i := 0; len(rc.entries) < maxCacheEntries; i++ {
+		rc.put(strconv.Itoa(i), []byte("live"))
+	}
+	rc.put("overflow", []byte("x"))
+	if _, ok := rc.get("overflow"); ok || len(rc.entries) != maxCacheEntries {
+		t.Fatalf("cache grew past maxCacheEntries: %d entries", len(rc.entries))
+	}
+}
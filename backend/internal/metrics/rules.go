@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// 规则类型过滤（/api/v1/rules 的 type 参数）
+const (
+	RuleTypeAlert  = "alert"
+	RuleTypeRecord = "record"
+)
+
+// RuleGroup 规则组及最近一次评估信息（Prometheus /api/v1/rules 格式，vmselect 需配置 -vmalert.proxyURL）
+type RuleGroup struct {
+	Name           string    `json:"name"`
+	File           string    `json:"file"`
+	Interval       float64   `json:"interval"` // 秒
+	LastEvaluation time.Time `json:"lastEvaluation"`
+	EvaluationTime float64   `json:"evaluationTime"` // 秒
+	Rules          []Rule    `json:"rules"`
+}
+
+// Rule 告警规则或记录规则
+type Rule struct {
+	Name           string            `json:"name"`
+	Query          string            `json:"query"`
+	Type           string            `json:"type"`   // alerting, recording
+	Health         string            `json:"health"` // ok, err, unknown
+	LastError      string            `json:"lastError,omitempty"`
+	LastEvaluation time.Time         `json:"lastEvaluation"`
+	EvaluationTime float64           `json:"evaluationTime"`
+	Labels         map[string]string `json:"labels,omitempty"`
+	// 以下字段仅告警规则有
+	Duration    float64           `json:"duration,omitempty"` // for，秒
+	Annotations map[string]string `json:"annotations,omitempty"`
+	State       string            `json:"state,omitempty"` // firing, pending, inactive
+	Alerts      []RuleAlert       `json:"alerts,omitempty"`
+}
+
+// RuleAlert 告警规则当前产生的告警实例
+type RuleAlert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	State       string            `json:"state"`
+	ActiveAt    *time.Time        `json:"activeAt,omitempty"`
+	Value       string            `json:"value"`
+}
+
+type rulesResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		Groups []RuleGroup `json:"groups"`
+	} `json:"data"`
+	Error string `json:"error,omitempty"`
+}
+
+// GetRules 获取规则组，ruleType 为 alert 或 record 时只返回对应类型，为空返回全部
+func (c *Client) GetRules(ruleType string) ([]RuleGroup, error) {
+	queryPath, err := c.resolveQueryPath()
+	if err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	if ruleType != "" {
+		params.Set("type", ruleType)
+	}
+	body, err := c.fetch(fmt.Sprintf("%s%s/api/v1/rules?%s", c.baseURL, queryPath, params.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("获取规则失败: %w", err)
+	}
+
+	var result rulesResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("解析规则失败（VictoriaMetrics 需在 vmselect 上配置 -vmalert.proxyURL）: %w", err)
+	}
+	if result.Status != "success" {
+		return nil, fmt.Errorf("获取规则失败: %s", result.Error)
+	}
+	if result.Data.Groups == nil {
+		result.Data.Groups = []RuleGroup{}
+	}
+	return result.Data.Groups, nil
+}
@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/k8s-dashboard/backend/internal/promql"
+)
+
+// NamespaceMatcher 生成匹配指定命名空间的 PromQL 标签匹配器
+func NamespaceMatcher(namespaces []string) string {
+	quoted := make([]string, 0, len(namespaces))
+	for _, ns := range namespaces {
+		// PromQL 字符串中反斜杠需要再转义一次
+		quoted = append(quoted, strings.ReplaceAll(regexp.QuoteMeta(ns), `\`, `\\`))
+	}
+	return `namespace=~"` + strings.Join(quoted, "|") + `"`
+}
+
+// ScopeQuery 按 promql.Scope 为查询中的每个序列选择器（含不带花括号的裸指标名）追加命名空间匹配，
+// 使结果只包含指定命名空间的序列；已有的 namespace 匹配器保留，与追加的匹配器取交集。
+// namespaces 为空时原样返回（不限制），调用方需自行处理“无任何可见命名空间”的情况；查询无法解析时返回错误
+func ScopeQuery(query string, namespaces []string) (string, error) {
+	if len(namespaces) == 0 {
+		return query, nil
+	}
+	return promql.Scope(query, NamespaceMatcher(namespaces))
+}
@@ -0,0 +1,149 @@
+package metrics
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestNamespaceMatcher(t *testing.T) {
+	tests := []struct {
+		namespaces []string
+		want       string
+	}{
+		{[]string{"default"}, `namespace=~"default"`},
+		{[]string{"shop", "payments"}, `namespace=~"shop|payments"`},
+		{[]string{"team.a"}, `namespace=~"team\\.a"`},
+		{[]string{"a+b", "c*"}, `namespace=~"a\\+b|c\\*"`},
+		{[]string{}, `namespace=~""`},
+	}
+	for _, tt := range tests {
+		if got := NamespaceMatcher(tt.namespaces); got != tt.want {
+			t.Errorf("NamespaceMatcher(%q) = %s, want %s", tt.namespaces, got, tt.want)
+		}
+	}
+}
+
+func TestNamespaceMatcherMatchesOnlyListedNamespaces(t *testing.T) {
+	// 取出 PromQL 字符串内容并还原一层转义，得到 VictoriaMetrics 实际使用的正则（完整锚定）
+	matcher := NamespaceMatcher([]string{"team.a", "b"})
+	pattern := regexp.MustCompile(`^namespace=~"(.*)"$`).FindStringSubmatch(matcher)[1]
+	re := regexp.MustCompile(`^(?:` + strings.ReplaceAll(pattern, `\\`, `\`) + `)$`)
+	for ns, want := range map[string]bool{"team.a": true, "b": true, "teamxa": false, "team.ab": false, "bb": false} {
+		if got := re.MatchString(ns); got != want {
+			t.Errorf("namespace %q matched = %v, want %v", ns, got, want)
+		}
+	}
+}
+
+func TestScopeQuery(t *testing.T) {
+	const m = `namespace=~"shop|payments"`
+	namespaces := []string{"shop", "payments"}
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "selector without namespace",
+			query: `kube_pod_info{pod="web"}`,
+			want:  `kube_pod_info{pod="web",` + m + `}`,
+		},
+		{
+			name:  "empty selector",
+			query: `up{}`,
+			want:  `up{` + m + `}`,
+		},
+		{
+			name:  "existing namespace equality is intersected",
+			query: `kube_pod_info{namespace="shop",pod="web"}`,
+			want:  `kube_pod_info{namespace="shop",pod="web",` + m + `}`,
+		},
+		{
+			name:  "aggregate over binary expression",
+			query: `sum by (namespace) (rate(container_cpu_usage_seconds_total{container!=""}[5m])) / sum by (namespace) (kube_pod_container_resource_requests{resource="cpu"})`,
+			want: `sum by (namespace) (rate(container_cpu_usage_seconds_total{container!="",` + m + `}[5m])) / ` +
+				`sum by (namespace) (kube_pod_container_resource_requests{resource="cpu",` + m + `})`,
+		},
+		{
+			name:  "subquery",
+			query: `max_over_time(rate(up{job="a"}[1m])[10m:1m])`,
+			want:  `max_over_time(rate(up{job="a",` + m + `}[1m])[10m:1m])`,
+		},
+		{
+			name:  "closing brace in label value",
+			query: `http_requests_total{path="/a}b",code="200"}`,
+			want:  `http_requests_total{path="/a}b",code="200",` + m + `}`,
+		},
+		{
+			name:  "bare metric name is scoped",
+			query: `sum(up)`,
+			want:  `sum(up{` + m + `})`,
+		},
+		{
+			name:  "bare metric in binary expression",
+			query: `node_memory_MemTotal_bytes - node_memory_MemAvailable_bytes{}`,
+			want:  `node_memory_MemTotal_bytes{` + m + `} - node_memory_MemAvailable_bytes{` + m + `}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ScopeQuery(tt.query, namespaces)
+			if err != nil {
+				t.Fatalf("ScopeQuery(%s) failed: %v", tt.query, err)
+			}
+			if got != tt.want {
+				t.Fatalf("ScopeQuery(%s)\n got  %s\n want %s", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScopeQueryRejectsUnparsableQuery(t *testing.T) {
+	if got, err := ScopeQuery(`up{job="a"`, []string{"shop"}); err == nil {
+		t.Fatalf("ScopeQuery of unclosed selector = %s, want error", got)
+	}
+}
+
+func TestScopeQueryWithoutNamespaces(t *testing.T) {
+	query := `sum(rate(up{job="a"}[5m]))`
+	if got, err := ScopeQuery(query, nil); err != nil || got != query {
+		t.Fatalf("ScopeQuery without namespaces = (%s, %v), want unchanged", got, err)
+	}
+}
+
+// 内置查询都必须能被解析并限制命名空间，否则受限用户的请求会直接失败
+func TestBuiltinQueriesCanBeScoped(t *testing.T) {
+	queries := []string{
+		`sum by (namespace, pod) (rate(container_cpu_usage_seconds_total{container!="",container!="POD"}[5m]))`,
+		`sum(container_memory_working_set_bytes{container!="",container!="POD"}) / 1024 / 1024 / 1024`,
+	}
+	for _, byResource := range topQueries {
+		for _, q := range byResource {
+			queries = append(queries, q)
+		}
+	}
+	for _, q := range volumeStatsQueries {
+		queries = append(queries, q)
+	}
+	for _, q := range podNetworkQueries {
+		queries = append(queries, q)
+	}
+	for _, pattern := range usageQuantileQueries {
+		queries = append(queries, fmt.Sprintf(pattern, 0.95, "7d"))
+	}
+	for _, pattern := range usageTotalQueries {
+		queries = append(queries, fmt.Sprintf(pattern, "30d"))
+	}
+	for _, q := range queries {
+		scoped, err := ScopeQuery(q, []string{"shop"})
+		if err != nil {
+			t.Errorf("ScopeQuery(%s) failed: %v", q, err)
+			continue
+		}
+		if !strings.Contains(scoped, `namespace=~"shop"`) {
+			t.Errorf("ScopeQuery(%s) = %s, namespace matcher missing", q, scoped)
+		}
+	}
+}
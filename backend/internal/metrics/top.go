@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"fmt"
+	"net"
+	"sort"
+)
+
+// TopConsumer 资源占用排行中的一项，按 scope 只填写 namespace/pod 或 node；
+// CPU 单位为 cores，内存单位为 bytes（working set，节点为已用内存）
+type TopConsumer struct {
+	Namespace string  `json:"namespace,omitempty"`
+	Pod       string  `json:"pod,omitempty"`
+	Node      string  `json:"node,omitempty"`
+	Value     float64 `json:"value"`
+}
+
+// topQueries 各维度的用量表达式，按 scope、resource 索引。Pod 与命名空间来自 cAdvisor，节点来自 node_exporter（含系统进程）
+var topQueries = map[string]map[string]string{
+	"pod": {
+		"cpu":    `sum by (namespace, pod) (rate(container_cpu_usage_seconds_total{container!="",container!="POD"}[5m]))`,
+		"memory": `sum by (namespace, pod) (container_memory_working_set_bytes{container!="",container!="POD"})`,
+	},
+	"namespace": {
+		"cpu":    `sum by (namespace) (rate(container_cpu_usage_seconds_total{container!="",container!="POD"}[5m]))`,
+		"memory": `sum by (namespace) (container_memory_working_set_bytes{container!="",container!="POD"})`,
+	},
+	"node": {
+		"cpu":    `sum by (instance) (rate(node_cpu_seconds_total{mode!="idle",mode!="iowait",mode!="steal"}[5m]))`,
+		"memory": `sum by (instance) (node_memory_MemTotal_bytes{} - node_memory_MemAvailable_bytes{})`,
+	},
+}
+
+// ValidTopQuery 判断 scope（pod、namespace、node）与 resource（cpu、memory）是否受支持
+func ValidTopQuery(scope, resource string) bool {
+	_, ok := topQueries[scope][resource]
+	return ok
+}
+
+// GetTopConsumers 用 topk() 查询占用 resource 最多的 k 个 Pod、命名空间或节点，按用量降序排列。
+// namespaces 非空时只统计这些命名空间（对节点维度无效）
+func (c *Client) GetTopConsumers(scope, resource string, k int, namespaces []string) ([]TopConsumer, error) {
+	expr, ok := topQueries[scope][resource]
+	if !ok {
+		return nil, fmt.Errorf("不支持的排行维度: %s/%s", scope, resource)
+	}
+	if scope != "node" {
+		scoped, err := ScopeQuery(expr, namespaces)
+		if err != nil {
+			return nil, err
+		}
+		expr = scoped
+	}
+	resp, err := c.Query(fmt.Sprintf("topk(%d, %s)", k, expr))
+	if err != nil {
+		return nil, fmt.Errorf("查询资源排行失败: %w", err)
+	}
+
+	result := make([]TopConsumer, 0, len(resp.Data.Result))
+	for _, res := range resp.Data.Result {
+		value, err := res.SampleValue()
+		if err != nil {
+			continue
+		}
+		item := TopConsumer{Value: value}
+		switch scope {
+		case "pod":
+			item.Namespace, item.Pod = res.Metric["namespace"], res.Metric["pod"]
+		case "namespace":
+			item.Namespace = res.Metric["namespace"]
+		case "node":
+			// instance 为 node_exporter 的采集地址，去掉端口后通常即节点名或节点 IP
+			item.Node = res.Metric["instance"]
+			if host, _, err := net.SplitHostPort(item.Node); err == nil {
+				item.Node = host
+			}
+		}
+		result = append(result, item)
+	}
+	// 即时查询的 topk 结果不保证顺序
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Value > result[j].Value
+	})
+	return result, nil
+}
@@ -0,0 +1,160 @@
+package metrics
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var windowPattern = regexp.MustCompile(`^[1-9][0-9]*[hdw]$`)
+
+// ValidateWindow 校验用量统计的时间窗口（PromQL 时长），只接受小时、天、周，
+// 窗口会直接拼入查询，必须先校验
+func ValidateWindow(window string) error {
+	if !windowPattern.MatchString(window) {
+		return fmt.Errorf("时间窗口格式无效（示例: 24h、7d、2w）: %q", window)
+	}
+	return nil
+}
+
+// ParseWindow 将时间窗口转换为 time.Duration
+func ParseWindow(window string) (time.Duration, error) {
+	if err := ValidateWindow(window); err != nil {
+		return 0, err
+	}
+	n, _ := strconv.Atoi(window[:len(window)-1])
+	unit := time.Hour
+	switch window[len(window)-1] {
+	case 'd':
+		unit = 24 * time.Hour
+	case 'w':
+		unit = 7 * 24 * time.Hour
+	}
+	return time.Duration(n) * unit, nil
+}
+
+// ContainerUsage 容器在时间窗口内的资源用量分位数
+type ContainerUsage struct {
+	Namespace   string  `json:"namespace"`
+	Pod         string  `json:"pod"`
+	Container   string  `json:"container"`
+	CPUCores    float64 `json:"cpuCores"`    // CPU 用量分位数（核）
+	MemoryBytes float64 `json:"memoryBytes"` // 工作集内存分位数（字节）
+}
+
+// usageQuantileQueries 容器用量分位数查询，%[1]g 为分位数（0-1），%[2]s 为时间窗口（PromQL 时长，如 7d）。
+// CPU 先按 5 分钟速率计算再在窗口内取分位数；同名容器重启产生的多条序列取最大值
+var usageQuantileQueries = map[string]string{
+	"cpu":    `max by (namespace, pod, container) (quantile_over_time(%[1]g, rate(container_cpu_usage_seconds_total{container!="",container!="POD"}[5m])[%[2]s:5m]))`,
+	"memory": `max by (namespace, pod, container) (quantile_over_time(%[1]g, container_memory_working_set_bytes{container!="",container!="POD"}[%[2]s]))`,
+}
+
+// GetContainerUsageQuantile 批量获取容器在 window 内 CPU 与内存用量的 quantile 分位数，
+// namespaces 非空时仅查询这些命名空间
+func (c *Client) GetContainerUsageQuantile(quantile float64, window string, namespaces []string) ([]ContainerUsage, error) {
+	usages := make(map[string]*ContainerUsage)
+	for field, pattern := range usageQuantileQueries {
+		query, err := ScopeQuery(fmt.Sprintf(pattern, quantile, window), namespaces)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.Query(query)
+		if err != nil {
+			return nil, fmt.Errorf("查询容器用量分位数失败: %w", err)
+		}
+		for _, res := range resp.Data.Result {
+			ns, pod, container := res.Metric["namespace"], res.Metric["pod"], res.Metric["container"]
+			if ns == "" || pod == "" || container == "" {
+				continue
+			}
+			value, err := res.SampleValue()
+			if err != nil {
+				continue
+			}
+
+			key := ns + "/" + pod + "/" + container
+			usage, exists := usages[key]
+			if !exists {
+				usage = &ContainerUsage{Namespace: ns, Pod: pod, Container: container}
+				usages[key] = usage
+			}
+			switch field {
+			case "cpu":
+				usage.CPUCores = value
+			case "memory":
+				usage.MemoryBytes = value
+			}
+		}
+	}
+
+	result := make([]ContainerUsage, 0, len(usages))
+	for _, usage := range usages {
+		result = append(result, *usage)
+	}
+	return result, nil
+}
+
+// ContainerUsageTotal 容器在时间窗口内的累计用量，按 5 分钟采样点近似
+type ContainerUsageTotal struct {
+	Namespace      string  `json:"namespace"`
+	Pod            string  `json:"pod"`
+	Container      string  `json:"container"`
+	Hours          float64 `json:"hours"`          // 窗口内运行时长（小时）
+	CPUCoreHours   float64 `json:"cpuCoreHours"`   // CPU 用量（核·小时）
+	MemoryGiBHours float64 `json:"memoryGiBHours"` // 工作集内存（GiB·小时）
+}
+
+// usageTotalQueries 容器累计用量查询，%s 为时间窗口。子查询步长 5m，每个采样点计 5/60 小时
+var usageTotalQueries = map[string]string{
+	"hours":  `max by (namespace, pod, container) (count_over_time(container_memory_working_set_bytes{container!="",container!="POD"}[%s:5m])) * 5 / 60`,
+	"cpu":    `max by (namespace, pod, container) (sum_over_time(rate(container_cpu_usage_seconds_total{container!="",container!="POD"}[5m])[%s:5m])) * 5 / 60`,
+	"memory": `max by (namespace, pod, container) (sum_over_time(container_memory_working_set_bytes{container!="",container!="POD"}[%s:5m])) * 5 / 60 / 1073741824`,
+}
+
+// GetContainerUsageTotals 批量获取容器在 window 内的运行时长与累计 CPU、内存用量，
+// namespaces 非空时仅查询这些命名空间。已删除的 Pod 只要在窗口内有数据也会返回
+func (c *Client) GetContainerUsageTotals(window string, namespaces []string) ([]ContainerUsageTotal, error) {
+	totals := make(map[string]*ContainerUsageTotal)
+	for field, pattern := range usageTotalQueries {
+		query, err := ScopeQuery(fmt.Sprintf(pattern, window), namespaces)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.Query(query)
+		if err != nil {
+			return nil, fmt.Errorf("查询容器累计用量失败: %w", err)
+		}
+		for _, res := range resp.Data.Result {
+			ns, pod, container := res.Metric["namespace"], res.Metric["pod"], res.Metric["container"]
+			if ns == "" || pod == "" || container == "" {
+				continue
+			}
+			value, err := res.SampleValue()
+			if err != nil {
+				continue
+			}
+
+			key := ns + "/" + pod + "/" + container
+			total, exists := totals[key]
+			if !exists {
+				total = &ContainerUsageTotal{Namespace: ns, Pod: pod, Container: container}
+				totals[key] = total
+			}
+			switch field {
+			case "hours":
+				total.Hours = value
+			case "cpu":
+				total.CPUCoreHours = value
+			case "memory":
+				total.MemoryGiBHours = value
+			}
+		}
+	}
+
+	result := make([]ContainerUsageTotal, 0, len(totals))
+	for _, total := range totals {
+		result = append(result, *total)
+	}
+	return result, nil
+}
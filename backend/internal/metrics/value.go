@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// 指标数据状态，用于区分“没有数据”与“值为 0”
+const (
+	StatusOK          = "ok"          // 查询成功并取到值
+	StatusNoData      = "no_data"     // 查询成功但没有序列（如未部署对应的 exporter）
+	StatusUnavailable = "unavailable" // 查询失败或值无法解析
+)
+
+// ErrNoData 查询成功但没有返回任何序列
+var ErrNoData = errors.New("没有数据")
+
+// ParseSampleValue 解析样本值。Prometheus 以字符串表示样本值，NaN、±Inf 视为无效
+func ParseSampleValue(raw interface{}) (float64, error) {
+	s, ok := raw.(string)
+	if !ok {
+		return 0, fmt.Errorf("样本值类型无效: %T", raw)
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("样本值无效: %q", s)
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("样本值不是有限数: %s", s)
+	}
+	return value, nil
+}
+
+// SampleValue 返回即时查询结果的样本值
+func (r QueryResult) SampleValue() (float64, error) {
+	if len(r.Value) < 2 {
+		return 0, errors.New("样本缺少值")
+	}
+	return ParseSampleValue(r.Value[1])
+}
+
+// queryScalar 执行只返回一条序列的即时查询（如 sum(...)），没有序列时返回 ErrNoData
+func (c *Client) queryScalar(query string) (float64, error) {
+	resp, err := c.Query(query)
+	if err != nil {
+		return 0, err
+	}
+	if len(resp.Data.Result) == 0 {
+		return 0, ErrNoData
+	}
+	return resp.Data.Result[0].SampleValue()
+}
+
+// setUsed 记录使用量的查询结果与数据状态
+func (m *ResourceMetric) setUsed(value float64, err error) {
+	m.Used, m.UsedStatus = value, m.status(err)
+}
+
+// setTotal 记录总量的查询结果与数据状态
+func (m *ResourceMetric) setTotal(value float64, err error) {
+	m.Total, m.TotalStatus = value, m.status(err)
+}
+
+func (m *ResourceMetric) status(err error) string {
+	status := dataStatus(err)
+	if status == StatusUnavailable {
+		m.Errors = append(m.Errors, err.Error())
+	}
+	return status
+}
+
+func (m *PodMetrics) status(err error) string {
+	status := dataStatus(err)
+	if status == StatusUnavailable {
+		m.Errors = append(m.Errors, err.Error())
+	}
+	return status
+}
+
+// dataStatus 将查询错误转换为数据状态
+func dataStatus(err error) string {
+	switch {
+	case err == nil:
+		return StatusOK
+	case errors.Is(err, ErrNoData):
+		return StatusNoData
+	default:
+		return StatusUnavailable
+	}
+}
@@ -0,0 +1,128 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSampleValue(t *testing.T) {
+	tests := []struct {
+		raw     interface{}
+		want    float64
+		wantErr bool
+	}{
+		{raw: "1.5", want: 1.5},
+		{raw: "0", want: 0},
+		{raw: "-2", want: -2},
+		{raw: "1e3", want: 1000},
+		{raw: "NaN", wantErr: true},
+		{raw: "+Inf", wantErr: true},
+		{raw: "-Inf", wantErr: true},
+		{raw: "abc", wantErr: true},
+		{raw: "", wantErr: true},
+		{raw: 1.5, wantErr: true}, // 样本值应为字符串
+		{raw: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSampleValue(tt.raw)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseSampleValue(%#v) = (%v, %v), want %v wantErr %v", tt.raw, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestDataStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, StatusOK},
+		{ErrNoData, StatusNoData},
+		{errors.Join(errors.New("wrapped"), ErrNoData), StatusNoData},
+		{errors.New("查询失败: connection refused"), StatusUnavailable},
+	}
+	for _, tt := range tests {
+		if got := dataStatus(tt.err); got != tt.want {
+			t.Errorf("dataStatus(%v) = %s, want %s", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestGetPodMetricsStatus(t *testing.T) {
+	const (
+		emptyVector = `{"status":"success","data":{"resultType":"vector","result":[]}}`
+		queryError  = `{"status":"error","errorType":"bad_data","error":"parse error"}`
+	)
+	sample := func(v string) string {
+		return `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"` + v + `"]}]}}`
+	}
+	tests := []struct {
+		name        string
+		cpu, memory string
+		want        PodMetrics
+		wantErrs    int
+		wantErr     bool
+	}{
+		{
+			name: "values", cpu: sample("0.25"), memory: sample("1048576"),
+			want: PodMetrics{CPUUsage: 0.25, MemoryUsage: 1048576, CPUStatus: StatusOK, MemoryStatus: StatusOK},
+		},
+		{
+			name: "zero is a real value", cpu: sample("0"), memory: sample("0"),
+			want: PodMetrics{CPUStatus: StatusOK, MemoryStatus: StatusOK},
+		},
+		{
+			name: "empty vector", cpu: emptyVector, memory: sample("512"),
+			want: PodMetrics{MemoryUsage: 512, CPUStatus: StatusNoData, MemoryStatus: StatusOK},
+		},
+		{
+			name: "NaN", cpu: sample("NaN"), memory: sample("512"),
+			want: PodMetrics{MemoryUsage: 512, CPUStatus: StatusUnavailable, MemoryStatus: StatusOK}, wantErrs: 1,
+		},
+		{
+			name: "Inf", cpu: sample("1"), memory: sample("+Inf"),
+			want: PodMetrics{CPUUsage: 1, CPUStatus: StatusOK, MemoryStatus: StatusUnavailable}, wantErrs: 1,
+		},
+		{
+			name: "query error", cpu: queryError, memory: emptyVector,
+			want: PodMetrics{CPUStatus: StatusUnavailable, MemoryStatus: StatusNoData}, wantErrs: 1,
+		},
+		{name: "all queries fail", cpu: queryError, memory: queryError, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.Contains(r.URL.Query().Get("query"), "container_cpu_usage_seconds_total") {
+					w.Write([]byte(tt.cpu))
+					return
+				}
+				w.Write([]byte(tt.memory))
+			}))
+			defer server.Close()
+			c := NewClientWithConfig(server.URL, testConfig())
+			c.SetQueryPath("/")
+
+			got, err := c.GetPodMetrics("shop", "web-0")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetPodMetrics failed: %v", err)
+			}
+			if len(got.Errors) != tt.wantErrs {
+				t.Fatalf("errors = %v, want %d", got.Errors, tt.wantErrs)
+			}
+			tt.want.Namespace, tt.want.Name, tt.want.Errors = "shop", "web-0", got.Errors
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Fatalf("GetPodMetrics = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
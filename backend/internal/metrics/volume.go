@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+)
+
+// VolumeMetrics PVC 卷用量，来自 kubelet 的 kubelet_volume_stats_* 指标。
+// 只有被 Pod 挂载中的文件系统卷才有数据
+type VolumeMetrics struct {
+	Namespace          string  `json:"namespace"`
+	PVC                string  `json:"pvc"`
+	UsedBytes          float64 `json:"usedBytes"`
+	CapacityBytes      float64 `json:"capacityBytes"`
+	AvailableBytes     float64 `json:"availableBytes"`
+	UsagePercent       float64 `json:"usagePercent"`
+	InodesUsed         float64 `json:"inodesUsed"`
+	Inodes             float64 `json:"inodes"`
+	InodesUsagePercent float64 `json:"inodesUsagePercent"`
+}
+
+// volumeStatsQueries kubelet 卷指标；同一 PVC 被多个节点挂载（RWX）时取最大值
+var volumeStatsQueries = map[string]string{
+	"used":        `max by (namespace, persistentvolumeclaim) (kubelet_volume_stats_used_bytes{})`,
+	"capacity":    `max by (namespace, persistentvolumeclaim) (kubelet_volume_stats_capacity_bytes{})`,
+	"available":   `max by (namespace, persistentvolumeclaim) (kubelet_volume_stats_available_bytes{})`,
+	"inodes_used": `max by (namespace, persistentvolumeclaim) (kubelet_volume_stats_inodes_used{})`,
+	"inodes":      `max by (namespace, persistentvolumeclaim) (kubelet_volume_stats_inodes{})`,
+}
+
+// GetVolumeMetrics 批量获取 PVC 卷用量，namespaces 非空时仅查询这些命名空间
+func (c *Client) GetVolumeMetrics(namespaces []string) ([]VolumeMetrics, error) {
+	volumes := make(map[string]*VolumeMetrics)
+	for field, query := range volumeStatsQueries {
+		scoped, err := ScopeQuery(query, namespaces)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.Query(scoped)
+		if err != nil {
+			return nil, fmt.Errorf("查询卷用量指标失败: %w", err)
+		}
+		for _, res := range resp.Data.Result {
+			ns, pvc := res.Metric["namespace"], res.Metric["persistentvolumeclaim"]
+			if ns == "" || pvc == "" {
+				continue
+			}
+			value, err := res.SampleValue()
+			if err != nil {
+				continue
+			}
+
+			key := ns + "/" + pvc
+			volume, exists := volumes[key]
+			if !exists {
+				volume = &VolumeMetrics{Namespace: ns, PVC: pvc}
+				volumes[key] = volume
+			}
+			switch field {
+			case "used":
+				volume.UsedBytes = value
+			case "capacity":
+				volume.CapacityBytes = value
+			case "available":
+				volume.AvailableBytes = value
+			case "inodes_used":
+				volume.InodesUsed = value
+			case "inodes":
+				volume.Inodes = value
+			}
+		}
+	}
+
+	result := make([]VolumeMetrics, 0, len(volumes))
+	for _, volume := range volumes {
+		if volume.CapacityBytes > 0 {
+			volume.UsagePercent = volume.UsedBytes / volume.CapacityBytes * 100
+		}
+		if volume.Inodes > 0 {
+			volume.InodesUsagePercent = volume.InodesUsed / volume.Inodes * 100
+		}
+		result = append(result, *volume)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].PVC < result[j].PVC
+	})
+	return result, nil
+}
@@ -0,0 +1,297 @@
+package notifications
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	dbutil "github.com/k8s-dashboard/backend/internal/db"
+)
+
+// ErrChannelNotFound 通知渠道不存在
+var ErrChannelNotFound = errors.New("通知渠道不存在")
+
+// 渠道类型
+const (
+	TypeSlack    = "slack"
+	TypeDingTalk = "dingtalk"
+	TypeWeCom    = "wecom"
+	TypeEmail    = "email"
+	TypeWebhook  = "webhook"
+)
+
+// ChannelConfig 渠道连接配置，按类型使用其中的字段
+type ChannelConfig struct {
+	// URL Slack / 钉钉 / 企业微信机器人或通用 Webhook 地址
+	URL string `json:"url,omitempty"`
+	// Secret 通用 Webhook 的 HMAC 签名密钥，或钉钉机器人的加签密钥
+	Secret string `json:"secret,omitempty"`
+
+	// 邮件渠道
+	SMTPHost     string   `json:"smtpHost,omitempty"`
+	SMTPPort     int      `json:"smtpPort,omitempty"`
+	SMTPUsername string   `json:"smtpUsername,omitempty"`
+	SMTPPassword string   `json:"smtpPassword,omitempty"`
+	From         string   `json:"from,omitempty"`
+	To           []string `json:"to,omitempty"`
+}
+
+// Channel 通知渠道
+type Channel struct {
+	ID      int64         `json:"id"`
+	Name    string        `json:"name"`
+	Type    string        `json:"type"` // slack, dingtalk, wecom, email, webhook
+	Enabled bool          `json:"enabled"`
+	Config  ChannelConfig `json:"config"`
+	// Events 订阅的事件，支持 alert.* 形式的前缀匹配，空表示全部事件
+	Events []string `json:"events"`
+	// Template text/template 正文模板，为空时使用通知自带的正文
+	Template string `json:"template,omitempty"`
+
+	CreatedBy string    `json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	// 最近一次推送结果
+	LastSentAt *time.Time `json:"lastSentAt,omitempty"`
+	LastError  string     `json:"lastError,omitempty"`
+}
+
+// Repository 通知渠道数据仓库
+type Repository struct {
+	db      *sql.DB
+	dialect dbutil.Dialect
+}
+
+// NewRepository 创建通知渠道数据仓库
+func NewRepository(db *sql.DB, dialect dbutil.Dialect) (*Repository, error) {
+	repo := &Repository{
+		db:      db,
+		dialect: dialect,
+	}
+
+	if err := repo.initSchema(); err != nil {
+		return nil, fmt.Errorf("初始化表结构失败: %w", err)
+	}
+
+	return repo, nil
+}
+
+// initSchema 初始化表结构
+func (r *Repository) initSchema() error {
+	var schema string
+	if r.dialect == dbutil.DialectSQLite {
+		schema = `
+		CREATE TABLE IF NOT EXISTS notification_channels (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT UNIQUE NOT NULL,
+			type TEXT NOT NULL,
+			enabled INTEGER NOT NULL DEFAULT 1,
+			config TEXT NOT NULL,
+			events TEXT,
+			template TEXT,
+			created_by TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			last_sent_at DATETIME,
+			last_error TEXT
+		);
+		`
+	} else {
+		schema = `
+		CREATE TABLE IF NOT EXISTS notification_channels (
+			id BIGSERIAL PRIMARY KEY,
+			name VARCHAR(63) UNIQUE NOT NULL,
+			type VARCHAR(20) NOT NULL,
+			enabled BOOLEAN NOT NULL DEFAULT TRUE,
+			config TEXT NOT NULL,
+			events TEXT,
+			template TEXT,
+			created_by VARCHAR(255),
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			last_sent_at TIMESTAMP WITH TIME ZONE,
+			last_error TEXT
+		);
+		`
+	}
+
+	if _, err := r.db.Exec(schema); err != nil {
+		return err
+	}
+	return r.initRouteSchema()
+}
+
+// encodeChannel 序列化渠道配置与订阅事件
+func encodeChannel(ch *Channel) (string, string, error) {
+	config, err := json.Marshal(ch.Config)
+	if err != nil {
+		return "", "", fmt.Errorf("序列化渠道配置失败: %w", err)
+	}
+	events, err := json.Marshal(ch.Events)
+	if err != nil {
+		return "", "", fmt.Errorf("序列化订阅事件失败: %w", err)
+	}
+	return string(config), string(events), nil
+}
+
+// Create 创建通知渠道
+func (r *Repository) Create(ch *Channel) error {
+	config, events, err := encodeChannel(ch)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	ch.CreatedAt = now
+	ch.UpdatedAt = now
+
+	if r.dialect == dbutil.DialectSQLite {
+		result, err := r.db.Exec(`
+			INSERT INTO notification_channels (name, type, enabled, config, events, template, created_by, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`, ch.Name, ch.Type, ch.Enabled, config, events, ch.Template, ch.CreatedBy, now, now)
+		if err != nil {
+			return err
+		}
+		ch.ID, err = result.LastInsertId()
+		return err
+	}
+
+	return r.db.QueryRow(`
+		INSERT INTO notification_channels (name, type, enabled, config, events, template, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`, ch.Name, ch.Type, ch.Enabled, config, events, ch.Template, ch.CreatedBy, now, now).Scan(&ch.ID)
+}
+
+// Update 按 ID 更新通知渠道
+func (r *Repository) Update(ch *Channel) error {
+	config, events, err := encodeChannel(ch)
+	if err != nil {
+		return err
+	}
+
+	ch.UpdatedAt = time.Now()
+	result, err := r.db.Exec(`
+		UPDATE notification_channels SET
+			name = $1, type = $2, enabled = $3, config = $4, events = $5, template = $6, updated_at = $7
+		WHERE id = $8
+	`, ch.Name, ch.Type, ch.Enabled, config, events, ch.Template, ch.UpdatedAt, ch.ID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrChannelNotFound
+	}
+	return nil
+}
+
+// Delete 删除通知渠道
+func (r *Repository) Delete(id int64) error {
+	result, err := r.db.Exec("DELETE FROM notification_channels WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrChannelNotFound
+	}
+	return nil
+}
+
+const channelColumns = `id, name, type, enabled, config, COALESCE(events, ''), COALESCE(template, ''),
+	COALESCE(created_by, ''), created_at, updated_at, last_sent_at, COALESCE(last_error, '')`
+
+// Get 按 ID 获取通知渠道
+func (r *Repository) Get(id int64) (*Channel, error) {
+	ch, err := scanChannel(r.db.QueryRow("SELECT "+channelColumns+" FROM notification_channels WHERE id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, ErrChannelNotFound
+	}
+	return ch, err
+}
+
+// GetByName 按名称获取通知渠道
+func (r *Repository) GetByName(name string) (*Channel, error) {
+	ch, err := scanChannel(r.db.QueryRow("SELECT "+channelColumns+" FROM notification_channels WHERE name = $1", name))
+	if err == sql.ErrNoRows {
+		return nil, ErrChannelNotFound
+	}
+	return ch, err
+}
+
+// List 列出通知渠道，enabledOnly 为 true 时只返回已启用的渠道
+func (r *Repository) List(enabledOnly bool) ([]Channel, error) {
+	query := "SELECT " + channelColumns + " FROM notification_channels"
+	var args []interface{}
+	if enabledOnly {
+		query += " WHERE enabled = $1"
+		args = append(args, true)
+	}
+	query += " ORDER BY name ASC"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []Channel{}
+	for rows.Next() {
+		ch, err := scanChannel(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *ch)
+	}
+	return items, rows.Err()
+}
+
+// RecordDelivery 记录最近一次推送结果，sendErr 为 nil 表示成功
+func (r *Repository) RecordDelivery(id int64, sendErr error) error {
+	var lastError string
+	if sendErr != nil {
+		lastError = sendErr.Error()
+	}
+	_, err := r.db.Exec(`UPDATE notification_channels SET last_sent_at = $1, last_error = $2 WHERE id = $3`, time.Now(), lastError, id)
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanChannel(row rowScanner) (*Channel, error) {
+	ch := &Channel{}
+	var config, events string
+	var lastSentAt sql.NullTime
+	err := row.Scan(&ch.ID, &ch.Name, &ch.Type, &ch.Enabled, &config, &events, &ch.Template,
+		&ch.CreatedBy, &ch.CreatedAt, &ch.UpdatedAt, &lastSentAt, &ch.LastError)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(config), &ch.Config); err != nil {
+		return nil, fmt.Errorf("解析渠道配置失败: %w", err)
+	}
+	if events != "" {
+		if err := json.Unmarshal([]byte(events), &ch.Events); err != nil {
+			return nil, fmt.Errorf("解析订阅事件失败: %w", err)
+		}
+	}
+	if ch.Events == nil {
+		ch.Events = []string{}
+	}
+	if lastSentAt.Valid {
+		ch.LastSentAt = &lastSentAt.Time
+	}
+	return ch, nil
+}
@@ -0,0 +1,252 @@
+package notifications
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	dbutil "github.com/k8s-dashboard/backend/internal/db"
+)
+
+// ErrRouteNotFound 告警路由不存在
+var ErrRouteNotFound = errors.New("告警路由不存在")
+
+// RouteMatch 告警路由的匹配条件，各条件之间为“与”，同一条件内为“或”，空表示不限
+type RouteMatch struct {
+	Severities []string `json:"severities"` // critical, warning, info
+	Namespaces []string `json:"namespaces"`
+	AlertNames []string `json:"alertNames"` // 支持 * 通配，如 Kube*
+	Clusters   []string `json:"clusters"`
+}
+
+// QuietHours 免打扰时段，时段内命中路由的告警不推送
+type QuietHours struct {
+	Start string `json:"start"` // HH:MM
+	End   string `json:"end"`   // HH:MM，早于 Start 表示跨午夜，如 22:00-08:00
+	// Timezone IANA 时区，如 Asia/Shanghai，为空时使用服务器本地时区
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// Route 告警路由规则：将匹配的告警推送到指定通知渠道，用于按团队/命名空间分发告警
+type Route struct {
+	ID          int64       `json:"id"`
+	Name        string      `json:"name"`
+	Enabled     bool        `json:"enabled"`
+	Match       RouteMatch  `json:"match"`
+	ChannelID   int64       `json:"channelId"`
+	ChannelName string      `json:"channelName,omitempty"`
+	QuietHours  *QuietHours `json:"quietHours,omitempty"`
+
+	CreatedBy string    `json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// initRouteSchema 初始化告警路由表
+func (r *Repository) initRouteSchema() error {
+	var schema string
+	if r.dialect == dbutil.DialectSQLite {
+		schema = `
+		CREATE TABLE IF NOT EXISTS alert_routes (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT UNIQUE NOT NULL,
+			enabled INTEGER NOT NULL DEFAULT 1,
+			matchers TEXT NOT NULL,
+			channel_id INTEGER NOT NULL,
+			quiet_hours TEXT,
+			created_by TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_alert_routes_channel ON alert_routes(channel_id);
+		`
+	} else {
+		schema = `
+		CREATE TABLE IF NOT EXISTS alert_routes (
+			id BIGSERIAL PRIMARY KEY,
+			name VARCHAR(63) UNIQUE NOT NULL,
+			enabled BOOLEAN NOT NULL DEFAULT TRUE,
+			matchers TEXT NOT NULL,
+			channel_id BIGINT NOT NULL,
+			quiet_hours TEXT,
+			created_by VARCHAR(255),
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_alert_routes_channel ON alert_routes(channel_id);
+		`
+	}
+
+	_, err := r.db.Exec(schema)
+	return err
+}
+
+// encodeRoute 序列化匹配条件与免打扰时段
+func encodeRoute(rt *Route) (string, *string, error) {
+	matchers, err := json.Marshal(rt.Match)
+	if err != nil {
+		return "", nil, fmt.Errorf("序列化匹配条件失败: %w", err)
+	}
+	if rt.QuietHours == nil {
+		return string(matchers), nil, nil
+	}
+	quiet, err := json.Marshal(rt.QuietHours)
+	if err != nil {
+		return "", nil, fmt.Errorf("序列化免打扰时段失败: %w", err)
+	}
+	quietHours := string(quiet)
+	return string(matchers), &quietHours, nil
+}
+
+// CreateRoute 创建告警路由
+func (r *Repository) CreateRoute(rt *Route) error {
+	matchers, quietHours, err := encodeRoute(rt)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	rt.CreatedAt = now
+	rt.UpdatedAt = now
+
+	if r.dialect == dbutil.DialectSQLite {
+		result, err := r.db.Exec(`
+			INSERT INTO alert_routes (name, enabled, matchers, channel_id, quiet_hours, created_by, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`, rt.Name, rt.Enabled, matchers, rt.ChannelID, quietHours, rt.CreatedBy, now, now)
+		if err != nil {
+			return err
+		}
+		rt.ID, err = result.LastInsertId()
+		return err
+	}
+
+	return r.db.QueryRow(`
+		INSERT INTO alert_routes (name, enabled, matchers, channel_id, quiet_hours, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`, rt.Name, rt.Enabled, matchers, rt.ChannelID, quietHours, rt.CreatedBy, now, now).Scan(&rt.ID)
+}
+
+// UpdateRoute 按 ID 更新告警路由
+func (r *Repository) UpdateRoute(rt *Route) error {
+	matchers, quietHours, err := encodeRoute(rt)
+	if err != nil {
+		return err
+	}
+
+	rt.UpdatedAt = time.Now()
+	result, err := r.db.Exec(`
+		UPDATE alert_routes SET
+			name = $1, enabled = $2, matchers = $3, channel_id = $4, quiet_hours = $5, updated_at = $6
+		WHERE id = $7
+	`, rt.Name, rt.Enabled, matchers, rt.ChannelID, quietHours, rt.UpdatedAt, rt.ID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrRouteNotFound
+	}
+	return nil
+}
+
+// DeleteRoute 删除告警路由
+func (r *Repository) DeleteRoute(id int64) error {
+	result, err := r.db.Exec("DELETE FROM alert_routes WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrRouteNotFound
+	}
+	return nil
+}
+
+const routeColumns = `r.id, r.name, r.enabled, r.matchers, r.channel_id, COALESCE(c.name, ''), COALESCE(r.quiet_hours, ''),
+	COALESCE(r.created_by, ''), r.created_at, r.updated_at`
+
+const routeFrom = " FROM alert_routes r LEFT JOIN notification_channels c ON c.id = r.channel_id"
+
+// GetRoute 按 ID 获取告警路由
+func (r *Repository) GetRoute(id int64) (*Route, error) {
+	rt, err := scanRoute(r.db.QueryRow("SELECT "+routeColumns+routeFrom+" WHERE r.id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, ErrRouteNotFound
+	}
+	return rt, err
+}
+
+// GetRouteByName 按名称获取告警路由
+func (r *Repository) GetRouteByName(name string) (*Route, error) {
+	rt, err := scanRoute(r.db.QueryRow("SELECT "+routeColumns+routeFrom+" WHERE r.name = $1", name))
+	if err == sql.ErrNoRows {
+		return nil, ErrRouteNotFound
+	}
+	return rt, err
+}
+
+// ListRoutes 列出告警路由，enabledOnly 为 true 时只返回已启用的路由
+func (r *Repository) ListRoutes(enabledOnly bool) ([]Route, error) {
+	query := "SELECT " + routeColumns + routeFrom
+	var args []interface{}
+	if enabledOnly {
+		query += " WHERE r.enabled = $1"
+		args = append(args, true)
+	}
+	query += " ORDER BY r.name ASC"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []Route{}
+	for rows.Next() {
+		rt, err := scanRoute(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *rt)
+	}
+	return items, rows.Err()
+}
+
+// CountRoutesForChannel 统计引用渠道的告警路由数量
+func (r *Repository) CountRoutesForChannel(channelID int64) (int, error) {
+	var count int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM alert_routes WHERE channel_id = $1", channelID).Scan(&count)
+	return count, err
+}
+
+func scanRoute(row rowScanner) (*Route, error) {
+	rt := &Route{}
+	var matchers, quietHours string
+	err := row.Scan(&rt.ID, &rt.Name, &rt.Enabled, &matchers, &rt.ChannelID, &rt.ChannelName, &quietHours,
+		&rt.CreatedBy, &rt.CreatedAt, &rt.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(matchers), &rt.Match); err != nil {
+		return nil, fmt.Errorf("解析匹配条件失败: %w", err)
+	}
+	if quietHours != "" {
+		rt.QuietHours = &QuietHours{}
+		if err := json.Unmarshal([]byte(quietHours), rt.QuietHours); err != nil {
+			return nil, fmt.Errorf("解析免打扰时段失败: %w", err)
+		}
+	}
+	return rt, nil
+}
@@ -0,0 +1,343 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/k8s-dashboard/backend/internal/alertmanager"
+	"github.com/k8s-dashboard/backend/internal/alerts"
+	"github.com/k8s-dashboard/backend/internal/notify"
+)
+
+const quietHoursLayout = "15:04"
+
+// RouteInput 创建或更新告警路由的参数，enabled 为空时创建默认启用、更新保持不变
+type RouteInput struct {
+	Name       string      `json:"name"`
+	Enabled    *bool       `json:"enabled,omitempty"`
+	Match      RouteMatch  `json:"match"`
+	ChannelID  int64       `json:"channelId"`
+	QuietHours *QuietHours `json:"quietHours,omitempty"`
+}
+
+// ChannelOption 供创建告警路由时选择的渠道，不含连接配置
+type ChannelOption struct {
+	ID      int64  `json:"id"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Enabled bool   `json:"enabled"`
+}
+
+// ValidateRoute 校验并规范化告警路由
+func ValidateRoute(rt *Route) error {
+	rt.Name = strings.TrimSpace(rt.Name)
+	if rt.Name == "" || utf8.RuneCountInString(rt.Name) > maxChannelName {
+		return &ValidationError{Field: "name", Message: fmt.Sprintf("名称不能为空且不超过 %d 个字符", maxChannelName)}
+	}
+	if rt.ChannelID <= 0 {
+		return &ValidationError{Field: "channelId", Message: "必须指定通知渠道"}
+	}
+
+	m := &rt.Match
+	severities, err := normalizeValues("match.severities", m.Severities, func(v string) (string, bool) {
+		v = strings.ToLower(v)
+		switch v {
+		case alertmanager.SeverityCritical, alertmanager.SeverityWarning, alertmanager.SeverityInfo:
+			return v, true
+		}
+		return v, false
+	})
+	if err != nil {
+		return err
+	}
+	m.Severities = severities
+
+	if m.Namespaces, err = normalizeValues("match.namespaces", m.Namespaces, nil); err != nil {
+		return err
+	}
+	if m.Clusters, err = normalizeValues("match.clusters", m.Clusters, nil); err != nil {
+		return err
+	}
+	if m.AlertNames, err = normalizeValues("match.alertNames", m.AlertNames, func(v string) (string, bool) {
+		_, err := path.Match(v, "")
+		return v, err == nil
+	}); err != nil {
+		return err
+	}
+
+	if q := rt.QuietHours; q != nil {
+		q.Start = strings.TrimSpace(q.Start)
+		q.End = strings.TrimSpace(q.End)
+		q.Timezone = strings.TrimSpace(q.Timezone)
+		start, err := time.Parse(quietHoursLayout, q.Start)
+		if err != nil {
+			return &ValidationError{Field: "quietHours.start", Message: "格式应为 HH:MM"}
+		}
+		end, err := time.Parse(quietHoursLayout, q.End)
+		if err != nil {
+			return &ValidationError{Field: "quietHours.end", Message: "格式应为 HH:MM"}
+		}
+		if start.Equal(end) {
+			return &ValidationError{Field: "quietHours.end", Message: "结束时间不能与开始时间相同"}
+		}
+		if q.Timezone != "" {
+			if _, err := time.LoadLocation(q.Timezone); err != nil {
+				return &ValidationError{Field: "quietHours.timezone", Message: "未知时区"}
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeValues 去除空白与空值，valid 非空时逐项校验
+func normalizeValues(field string, values []string, valid func(string) (string, bool)) ([]string, error) {
+	result := make([]string, 0, len(values))
+	for i, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if valid != nil {
+			var ok bool
+			if v, ok = valid(v); !ok {
+				return nil, &ValidationError{Field: fmt.Sprintf("%s[%d]", field, i), Message: fmt.Sprintf("无效的值: %s", v)}
+			}
+		}
+		result = append(result, v)
+	}
+	return result, nil
+}
+
+// Matches 告警是否命中路由的匹配条件
+func (rt *Route) Matches(entry *alerts.HistoryEntry) bool {
+	m := rt.Match
+	if len(m.Severities) > 0 && !containsString(m.Severities, entry.Severity) {
+		return false
+	}
+	if len(m.Namespaces) > 0 && !containsString(m.Namespaces, entry.Namespace) {
+		return false
+	}
+	if len(m.Clusters) > 0 && !containsString(m.Clusters, entry.Cluster) {
+		return false
+	}
+	if len(m.AlertNames) > 0 {
+		matched := false
+		for _, pattern := range m.AlertNames {
+			if ok, _ := path.Match(pattern, entry.AlertName); ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// Active 给定时间是否处于免打扰时段
+func (q *QuietHours) Active(t time.Time) bool {
+	if q == nil {
+		return false
+	}
+	start, err := time.Parse(quietHoursLayout, q.Start)
+	if err != nil {
+		return false
+	}
+	end, err := time.Parse(quietHoursLayout, q.End)
+	if err != nil {
+		return false
+	}
+	if q.Timezone != "" {
+		if loc, err := time.LoadLocation(q.Timezone); err == nil {
+			t = t.In(loc)
+		}
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	from := start.Hour()*60 + start.Minute()
+	to := end.Hour()*60 + end.Minute()
+	if from < to {
+		return minute >= from && minute < to
+	}
+	// 跨午夜
+	return minute >= from || minute < to
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
+
+// ListRoutes 列出告警路由
+func (s *Service) ListRoutes() ([]Route, error) {
+	return s.repo.ListRoutes(false)
+}
+
+// GetRoute 获取告警路由
+func (s *Service) GetRoute(id int64) (*Route, error) {
+	return s.repo.GetRoute(id)
+}
+
+// ChannelOptions 列出可供告警路由选择的渠道
+func (s *Service) ChannelOptions() ([]ChannelOption, error) {
+	channels, err := s.repo.List(false)
+	if err != nil {
+		return nil, err
+	}
+	options := make([]ChannelOption, 0, len(channels))
+	for _, ch := range channels {
+		options = append(options, ChannelOption{ID: ch.ID, Name: ch.Name, Type: ch.Type, Enabled: ch.Enabled})
+	}
+	return options, nil
+}
+
+// CreateRoute 创建告警路由
+func (s *Service) CreateRoute(in RouteInput, createdBy string) (*Route, error) {
+	rt := &Route{
+		Name:       in.Name,
+		Enabled:    in.Enabled == nil || *in.Enabled,
+		Match:      in.Match,
+		ChannelID:  in.ChannelID,
+		QuietHours: in.QuietHours,
+		CreatedBy:  createdBy,
+	}
+	if err := s.validateRoute(rt); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetRouteByName(rt.Name); err == nil {
+		return nil, &ValidationError{Field: "name", Message: "路由名称已存在"}
+	}
+	if err := s.repo.CreateRoute(rt); err != nil {
+		return nil, err
+	}
+	return s.repo.GetRoute(rt.ID)
+}
+
+// UpdateRoute 更新告警路由
+func (s *Service) UpdateRoute(id int64, in RouteInput) (*Route, error) {
+	existing, err := s.repo.GetRoute(id)
+	if err != nil {
+		return nil, err
+	}
+
+	rt := *existing
+	rt.Name = in.Name
+	rt.Match = in.Match
+	rt.ChannelID = in.ChannelID
+	rt.QuietHours = in.QuietHours
+	if in.Enabled != nil {
+		rt.Enabled = *in.Enabled
+	}
+	if err := s.validateRoute(&rt); err != nil {
+		return nil, err
+	}
+	if other, err := s.repo.GetRouteByName(rt.Name); err == nil && other.ID != id {
+		return nil, &ValidationError{Field: "name", Message: "路由名称已存在"}
+	}
+	if err := s.repo.UpdateRoute(&rt); err != nil {
+		return nil, err
+	}
+	return s.repo.GetRoute(id)
+}
+
+// DeleteRoute 删除告警路由
+func (s *Service) DeleteRoute(id int64) error {
+	return s.repo.DeleteRoute(id)
+}
+
+// validateRoute 校验路由并确认目标渠道存在
+func (s *Service) validateRoute(rt *Route) error {
+	if err := ValidateRoute(rt); err != nil {
+		return err
+	}
+	if _, err := s.repo.Get(rt.ChannelID); err != nil {
+		if errors.Is(err, ErrChannelNotFound) {
+			return &ValidationError{Field: "channelId", Message: "通知渠道不存在"}
+		}
+		return err
+	}
+	return nil
+}
+
+// AlertRouter 按告警路由将告警触发与恢复推送到各团队的通知渠道
+type AlertRouter struct {
+	service      *Service
+	dashboardURL string
+	now          func() time.Time
+}
+
+// NewAlertRouter 创建告警路由器，dashboardURL 用于生成告警页面链接
+func NewAlertRouter(service *Service, dashboardURL string) *AlertRouter {
+	return &AlertRouter{service: service, dashboardURL: dashboardURL, now: time.Now}
+}
+
+// HandleAlerts 作为 alerts.HistoryHandler 使用，异步推送
+func (r *AlertRouter) HandleAlerts(entries []*alerts.HistoryEntry) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if err := r.Route(ctx, entries); err != nil {
+			log.Printf("告警路由推送失败 [%d 条]: %v", len(entries), err)
+		}
+	}()
+}
+
+// Route 按已启用的路由匹配告警：处于免打扰时段的路由跳过，多条路由指向同一渠道时合并推送，
+// 同一告警只推送一次；渠道停用时不推送
+func (r *AlertRouter) Route(ctx context.Context, entries []*alerts.HistoryEntry) error {
+	routes, err := r.service.repo.ListRoutes(true)
+	if err != nil {
+		return err
+	}
+
+	now := r.now()
+	var order []int64
+	matched := make(map[int64][]*alerts.HistoryEntry)
+	seen := make(map[int64]map[*alerts.HistoryEntry]bool)
+	for i := range routes {
+		rt := &routes[i]
+		if rt.QuietHours.Active(now) {
+			continue
+		}
+		for _, entry := range entries {
+			if !rt.Matches(entry) || seen[rt.ChannelID][entry] {
+				continue
+			}
+			if seen[rt.ChannelID] == nil {
+				seen[rt.ChannelID] = make(map[*alerts.HistoryEntry]bool)
+				order = append(order, rt.ChannelID)
+			}
+			seen[rt.ChannelID][entry] = true
+			matched[rt.ChannelID] = append(matched[rt.ChannelID], entry)
+		}
+	}
+
+	var errs []error
+	for _, channelID := range order {
+		ch, err := r.service.repo.Get(channelID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("channel %d: %w", channelID, err))
+			continue
+		}
+		if !ch.Enabled {
+			continue
+		}
+		for _, msg := range notify.AlertMessages(r.dashboardURL, matched[channelID]) {
+			if err := r.service.deliver(ctx, ch, msg); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", ch.Name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,62 @@
+package notifications
+
+import (
+	"testing"
+	"time"
+
+	"github.com/k8s-dashboard/backend/internal/alerts"
+)
+
+func TestQuietHoursActive(t *testing.T) {
+	at := func(hour, minute int) time.Time { return time.Date(2024, 5, 1, hour, minute, 0, 0, time.UTC) }
+
+	overnight := &QuietHours{Start: "22:00", End: "08:00", Timezone: "UTC"}
+	daytime := &QuietHours{Start: "12:00", End: "13:30", Timezone: "UTC"}
+	shanghai := &QuietHours{Start: "22:00", End: "08:00", Timezone: "Asia/Shanghai"}
+
+	tests := []struct {
+		name  string
+		quiet *QuietHours
+		now   time.Time
+		want  bool
+	}{
+		{"nil", nil, at(23, 0), false},
+		{"overnight before midnight", overnight, at(23, 0), true},
+		{"overnight after midnight", overnight, at(7, 59), true},
+		{"overnight end exclusive", overnight, at(8, 0), false},
+		{"overnight daytime", overnight, at(12, 0), false},
+		{"daytime inside", daytime, at(13, 0), true},
+		{"daytime outside", daytime, at(13, 30), false},
+		// 15:00 UTC 为上海 23:00
+		{"timezone", shanghai, at(15, 0), true},
+		{"timezone outside", shanghai, at(1, 0), false},
+	}
+	for _, tt := range tests {
+		if got := tt.quiet.Active(tt.now); got != tt.want {
+			t.Errorf("%s: Active(%s) = %v, want %v", tt.name, tt.now.Format(time.Kitchen), got, tt.want)
+		}
+	}
+}
+
+func TestRouteMatches(t *testing.T) {
+	entry := &alerts.HistoryEntry{Cluster: "prod", AlertName: "KubePodCrashLooping", Namespace: "payments", Severity: "critical"}
+
+	tests := []struct {
+		name  string
+		match RouteMatch
+		want  bool
+	}{
+		{"empty matches all", RouteMatch{}, true},
+		{"severity and namespace", RouteMatch{Severities: []string{"critical"}, Namespaces: []string{"orders", "payments"}}, true},
+		{"severity mismatch", RouteMatch{Severities: []string{"warning"}, Namespaces: []string{"payments"}}, false},
+		{"alertname glob", RouteMatch{AlertNames: []string{"Node*", "KubePod*"}}, true},
+		{"alertname mismatch", RouteMatch{AlertNames: []string{"Node*"}}, false},
+		{"cluster mismatch", RouteMatch{Clusters: []string{"staging"}}, false},
+	}
+	for _, tt := range tests {
+		rt := &Route{Match: tt.match}
+		if got := rt.Matches(entry); got != tt.want {
+			t.Errorf("%s: Matches = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/k8s-dashboard/backend/internal/notify"
+)
+
+// robotResponse 钉钉与企业微信机器人的响应，HTTP 200 时仍需检查 errcode
+type robotResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// DingTalkSink 钉钉自定义机器人，配置加签密钥时按时间戳签名
+type DingTalkSink struct {
+	url        string
+	secret     string
+	httpClient *http.Client
+}
+
+// NewDingTalkSink 创建钉钉机器人渠道
+func NewDingTalkSink(webhookURL, secret string) *DingTalkSink {
+	return &DingTalkSink{url: strings.TrimSpace(webhookURL), secret: secret, httpClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (s *DingTalkSink) Name() string { return TypeDingTalk }
+
+func (s *DingTalkSink) Send(ctx context.Context, msg *notify.ApprovalMessage) error {
+	target := s.url
+	if s.secret != "" {
+		timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+		sep := "?"
+		if strings.Contains(target, "?") {
+			sep = "&"
+		}
+		target += sep + "timestamp=" + timestamp + "&sign=" + url.QueryEscape(dingTalkSign(timestamp, s.secret))
+	}
+	return postRobot(ctx, s.httpClient, target, map[string]interface{}{
+		"msgtype": "text",
+		"text":    map[string]string{"content": messageContent(msg)},
+	})
+}
+
+// dingTalkSign 钉钉加签：HmacSHA256(timestamp + "\n" + secret) 后 Base64
+func dingTalkSign(timestamp, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// WeComSink 企业微信群机器人
+type WeComSink struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewWeComSink 创建企业微信机器人渠道
+func NewWeComSink(webhookURL string) *WeComSink {
+	return &WeComSink{url: strings.TrimSpace(webhookURL), httpClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (s *WeComSink) Name() string { return TypeWeCom }
+
+func (s *WeComSink) Send(ctx context.Context, msg *notify.ApprovalMessage) error {
+	return postRobot(ctx, s.httpClient, s.url, map[string]interface{}{
+		"msgtype": "text",
+		"text":    map[string]string{"content": messageContent(msg)},
+	})
+}
+
+// messageContent 机器人消息只有正文，正文未以标题开头时补充标题
+func messageContent(msg *notify.ApprovalMessage) string {
+	if msg.Title == "" || strings.HasPrefix(msg.Text, msg.Title) {
+		return msg.Text
+	}
+	return msg.Title + "\n" + msg.Text
+}
+
+func postRobot(ctx context.Context, client *http.Client, target string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("robot webhook returned status %d", resp.StatusCode)
+	}
+	var result robotResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("解析机器人响应失败: %w", err)
+	}
+	if result.ErrCode != 0 {
+		return fmt.Errorf("robot webhook error %d: %s", result.ErrCode, result.ErrMsg)
+	}
+	return nil
+}
+
+// newSink 按渠道类型创建推送实现
+func newSink(ch *Channel) (notify.Sink, error) {
+	cfg := ch.Config
+	switch ch.Type {
+	case TypeSlack:
+		return notify.NewSlackSink(cfg.URL), nil
+	case TypeDingTalk:
+		return NewDingTalkSink(cfg.URL, cfg.Secret), nil
+	case TypeWeCom:
+		return NewWeComSink(cfg.URL), nil
+	case TypeWebhook:
+		return notify.NewWebhookSink(cfg.URL, cfg.Secret), nil
+	case TypeEmail:
+		return notify.NewEmailSink(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.From, cfg.To), nil
+	default:
+		return nil, fmt.Errorf("不支持的渠道类型: %s", ch.Type)
+	}
+}
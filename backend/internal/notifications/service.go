@@ -0,0 +1,349 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/k8s-dashboard/backend/internal/notify"
+)
+
+// RedactedValue 接口返回时替代密钥的占位值，更新时原样提交表示保留原密钥
+const RedactedValue = "******"
+
+// EventTest 测试通知事件，不受渠道订阅限制
+const EventTest = "notification.test"
+
+// deliveryAttempts 单个渠道的最大推送次数
+const deliveryAttempts = 3
+
+const maxChannelName = 63
+
+// ErrDeliveryFailed 推送失败（测试渠道时返回）
+var ErrDeliveryFailed = errors.New("通知推送失败")
+
+var eventPattern = regexp.MustCompile(`^(\*|[a-z_]+(\.[a-z_]+)*(\.\*)?)$`)
+
+// ValidationError 渠道配置校验失败
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ChannelInput 创建或更新渠道的参数，enabled 为空时创建默认启用、更新保持不变
+type ChannelInput struct {
+	Name     string        `json:"name"`
+	Type     string        `json:"type"`
+	Enabled  *bool         `json:"enabled,omitempty"`
+	Config   ChannelConfig `json:"config"`
+	Events   []string      `json:"events"`
+	Template string        `json:"template,omitempty"`
+}
+
+// Service 通知渠道服务：管理数据库中配置的渠道，并将审批、告警与审计异常通知推送到订阅的渠道
+type Service struct {
+	repo       *Repository
+	newSink    func(*Channel) (notify.Sink, error)
+	retryDelay time.Duration
+}
+
+// NewService 创建通知渠道服务
+func NewService(repo *Repository) *Service {
+	return &Service{repo: repo, newSink: newSink, retryDelay: 2 * time.Second}
+}
+
+// Validate 校验并规范化渠道定义
+func Validate(ch *Channel) error {
+	ch.Name = strings.TrimSpace(ch.Name)
+	ch.Type = strings.ToLower(strings.TrimSpace(ch.Type))
+
+	if ch.Name == "" || utf8.RuneCountInString(ch.Name) > maxChannelName {
+		return &ValidationError{Field: "name", Message: fmt.Sprintf("名称不能为空且不超过 %d 个字符", maxChannelName)}
+	}
+
+	cfg := &ch.Config
+	switch ch.Type {
+	case TypeSlack, TypeDingTalk, TypeWeCom, TypeWebhook:
+		cfg.URL = strings.TrimSpace(cfg.URL)
+		if u, err := url.Parse(cfg.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return &ValidationError{Field: "config.url", Message: "必须是 http(s) 地址"}
+		}
+	case TypeEmail:
+		cfg.SMTPHost = strings.TrimSpace(cfg.SMTPHost)
+		if cfg.SMTPHost == "" {
+			return &ValidationError{Field: "config.smtpHost", Message: "SMTP 服务器不能为空"}
+		}
+		if cfg.SMTPPort == 0 {
+			cfg.SMTPPort = 587
+		}
+		if cfg.SMTPPort < 1 || cfg.SMTPPort > 65535 {
+			return &ValidationError{Field: "config.smtpPort", Message: "端口无效"}
+		}
+		if strings.TrimSpace(cfg.From) == "" {
+			return &ValidationError{Field: "config.from", Message: "发件人不能为空"}
+		}
+		to := cfg.To[:0]
+		for _, addr := range cfg.To {
+			if addr = strings.TrimSpace(addr); addr != "" {
+				to = append(to, addr)
+			}
+		}
+		if len(to) == 0 {
+			return &ValidationError{Field: "config.to", Message: "至少需要一个收件人"}
+		}
+		cfg.To = to
+	default:
+		return &ValidationError{Field: "type", Message: "仅支持 slack, dingtalk, wecom, email, webhook"}
+	}
+
+	events := make([]string, 0, len(ch.Events))
+	for i, event := range ch.Events {
+		event = strings.TrimSpace(event)
+		if !eventPattern.MatchString(event) {
+			return &ValidationError{Field: fmt.Sprintf("events[%d]", i), Message: "事件格式应为 alert.firing 或 alert.* 形式"}
+		}
+		events = append(events, event)
+	}
+	ch.Events = events
+
+	if strings.TrimSpace(ch.Template) == "" {
+		ch.Template = ""
+	} else if _, err := template.New(ch.Name).Parse(ch.Template); err != nil {
+		return &ValidationError{Field: "template", Message: fmt.Sprintf("模板无效: %v", err)}
+	}
+	return nil
+}
+
+// Subscribed 渠道是否订阅了事件，订阅列表为空表示全部事件
+func (ch *Channel) Subscribed(event string) bool {
+	if event == EventTest || len(ch.Events) == 0 {
+		return true
+	}
+	for _, pattern := range ch.Events {
+		if pattern == "*" || pattern == event {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(event, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redacted 返回隐藏密钥后的副本，用于接口响应
+func (ch Channel) Redacted() Channel {
+	if ch.Config.Secret != "" {
+		ch.Config.Secret = RedactedValue
+	}
+	if ch.Config.SMTPPassword != "" {
+		ch.Config.SMTPPassword = RedactedValue
+	}
+	return ch
+}
+
+// List 列出通知渠道（密钥已隐藏）
+func (s *Service) List() ([]Channel, error) {
+	items, err := s.repo.List(false)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i] = items[i].Redacted()
+	}
+	return items, nil
+}
+
+// Get 获取通知渠道（密钥已隐藏）
+func (s *Service) Get(id int64) (*Channel, error) {
+	ch, err := s.repo.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	redacted := ch.Redacted()
+	return &redacted, nil
+}
+
+// Create 创建通知渠道
+func (s *Service) Create(in ChannelInput, createdBy string) (*Channel, error) {
+	ch := &Channel{
+		Name:      in.Name,
+		Type:      in.Type,
+		Enabled:   in.Enabled == nil || *in.Enabled,
+		Config:    in.Config,
+		Events:    in.Events,
+		Template:  in.Template,
+		CreatedBy: createdBy,
+	}
+	if err := Validate(ch); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetByName(ch.Name); err == nil {
+		return nil, &ValidationError{Field: "name", Message: "渠道名称已存在"}
+	}
+	if err := s.repo.Create(ch); err != nil {
+		return nil, err
+	}
+	redacted := ch.Redacted()
+	return &redacted, nil
+}
+
+// Update 更新通知渠道，密钥为 RedactedValue 时保留原值
+func (s *Service) Update(id int64, in ChannelInput) (*Channel, error) {
+	existing, err := s.repo.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := *existing
+	ch.Name = in.Name
+	ch.Type = in.Type
+	ch.Config = in.Config
+	ch.Events = in.Events
+	ch.Template = in.Template
+	if in.Enabled != nil {
+		ch.Enabled = *in.Enabled
+	}
+	if ch.Config.Secret == RedactedValue {
+		ch.Config.Secret = existing.Config.Secret
+	}
+	if ch.Config.SMTPPassword == RedactedValue {
+		ch.Config.SMTPPassword = existing.Config.SMTPPassword
+	}
+	if err := Validate(&ch); err != nil {
+		return nil, err
+	}
+	if other, err := s.repo.GetByName(ch.Name); err == nil && other.ID != id {
+		return nil, &ValidationError{Field: "name", Message: "渠道名称已存在"}
+	}
+	if err := s.repo.Update(&ch); err != nil {
+		return nil, err
+	}
+	return s.Get(id)
+}
+
+// Delete 删除通知渠道，仍被告警路由引用时拒绝删除
+func (s *Service) Delete(id int64) error {
+	count, err := s.repo.CountRoutesForChannel(id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return &ValidationError{Field: "id", Message: fmt.Sprintf("渠道仍被 %d 条告警路由引用", count)}
+	}
+	return s.repo.Delete(id)
+}
+
+// Test 向渠道发送一条测试通知（渠道停用时同样发送），失败时返回 ErrDeliveryFailed
+func (s *Service) Test(ctx context.Context, id int64) error {
+	ch, err := s.repo.Get(id)
+	if err != nil {
+		return err
+	}
+	msg := &notify.ApprovalMessage{
+		Event: EventTest,
+		Title: "测试通知",
+		Text:  fmt.Sprintf("测试通知\n这是一条来自 K8s Dashboard 的测试消息（渠道 %s）", ch.Name),
+	}
+	if err := s.deliver(ctx, ch, msg); err != nil {
+		return fmt.Errorf("%w: %v", ErrDeliveryFailed, err)
+	}
+	return nil
+}
+
+// Publish 推送到订阅该事件的全部已启用渠道，单个渠道失败时重试，不影响其他渠道
+func (s *Service) Publish(ctx context.Context, msg *notify.ApprovalMessage) error {
+	channels, err := s.repo.List(true)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for i := range channels {
+		ch := &channels[i]
+		if !ch.Subscribed(msg.Event) {
+			continue
+		}
+		if err := s.deliver(ctx, ch, msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ch.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Name 与 Send 使服务可作为 notify.Sink 接入审批、告警与审计异常通知
+func (s *Service) Name() string { return "channels" }
+
+// Send 推送到订阅的渠道。各渠道已单独重试并记录结果，失败只记录日志而不向上返回，
+// 避免调用方整体重试时向已成功的渠道重复推送
+func (s *Service) Send(ctx context.Context, msg *notify.ApprovalMessage) error {
+	if err := s.Publish(ctx, msg); err != nil {
+		log.Printf("通知渠道推送失败 [%s]: %v", msg.Event, err)
+	}
+	return nil
+}
+
+// deliver 渲染并推送到单个渠道，失败时按退避重试，并记录最近一次推送结果
+func (s *Service) deliver(ctx context.Context, ch *Channel, msg *notify.ApprovalMessage) error {
+	err := s.send(ctx, ch, msg)
+	if recordErr := s.repo.RecordDelivery(ch.ID, err); recordErr != nil {
+		log.Printf("记录通知渠道推送结果失败 [%s]: %v", ch.Name, recordErr)
+	}
+	return err
+}
+
+func (s *Service) send(ctx context.Context, ch *Channel, msg *notify.ApprovalMessage) error {
+	sink, err := s.newSink(ch)
+	if err != nil {
+		return err
+	}
+	rendered, err := render(ch, msg)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 0; attempt < deliveryAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(attempt) * s.retryDelay):
+			}
+		}
+		if err = sink.Send(ctx, rendered); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// render 按渠道模板渲染正文，模板可用字段：.Event .Title .Text .Link .Approval .Anomaly .Alerts。
+// 邮件收件人只使用渠道配置，不沿用调用方按事件解析出的收件人
+func render(ch *Channel, msg *notify.ApprovalMessage) (*notify.ApprovalMessage, error) {
+	rendered := *msg
+	rendered.Recipients = nil
+	if ch.Template == "" {
+		return &rendered, nil
+	}
+
+	tmpl, err := template.New(ch.Name).Parse(ch.Template)
+	if err != nil {
+		return nil, fmt.Errorf("解析渠道模板失败: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, msg); err != nil {
+		return nil, fmt.Errorf("渲染渠道模板失败: %w", err)
+	}
+	rendered.Text = buf.String()
+	return &rendered, nil
+}
@@ -0,0 +1,300 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/k8s-dashboard/backend/internal/alerts"
+	dbutil "github.com/k8s-dashboard/backend/internal/db"
+	"github.com/k8s-dashboard/backend/internal/notify"
+)
+
+func newTestService(t *testing.T) *Service {
+	t.Helper()
+	conn, dialect, err := dbutil.Open(dbutil.Config{
+		SQLitePath:          filepath.Join(t.TempDir(), "notifications.db"),
+		AllowSQLiteFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	repo, err := NewRepository(conn, dialect)
+	if err != nil {
+		t.Fatalf("NewRepository failed: %v", err)
+	}
+	service := NewService(repo)
+	service.retryDelay = 0
+	return service
+}
+
+// robotServer 记录收到的机器人消息，前 failures 次请求返回 errcode
+type robotServer struct {
+	mu       sync.Mutex
+	bodies   []map[string]interface{}
+	queries  []string
+	failures int
+}
+
+func (s *robotServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var body map[string]interface{}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	s.bodies = append(s.bodies, body)
+	s.queries = append(s.queries, r.URL.RawQuery)
+	if len(s.bodies) <= s.failures {
+		_ = json.NewEncoder(w).Encode(robotResponse{ErrCode: 310000, ErrMsg: "sign not match"})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(robotResponse{ErrMsg: "ok"})
+}
+
+func (s *robotServer) content(i int) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	text, _ := s.bodies[i]["text"].(map[string]interface{})
+	content, _ := text["content"].(string)
+	return content
+}
+
+func TestSQLiteChannelLifecycle(t *testing.T) {
+	service := newTestService(t)
+
+	var validationErr *ValidationError
+	if _, err := service.Create(ChannelInput{Name: "ops", Type: "sms"}, "admin"); !errors.As(err, &validationErr) || validationErr.Field != "type" {
+		t.Fatalf("expected invalid type to be rejected, got %v", err)
+	}
+	if _, err := service.Create(ChannelInput{Name: "ops", Type: TypeEmail, Config: ChannelConfig{SMTPHost: "smtp.example.com", From: "a@example.com"}}, "admin"); !errors.As(err, &validationErr) || validationErr.Field != "config.to" {
+		t.Fatalf("expected email without recipients to be rejected, got %v", err)
+	}
+	if _, err := service.Create(ChannelInput{Name: "ops", Type: TypeSlack, Config: ChannelConfig{URL: "https://hooks.example.com"}, Events: []string{"alert.**"}}, "admin"); !errors.As(err, &validationErr) || validationErr.Field != "events[0]" {
+		t.Fatalf("expected invalid event pattern to be rejected, got %v", err)
+	}
+
+	created, err := service.Create(ChannelInput{
+		Name:   "值班群",
+		Type:   TypeDingTalk,
+		Config: ChannelConfig{URL: "https://oapi.dingtalk.com/robot/send?access_token=x", Secret: "SEC123"},
+		Events: []string{"alert.*"},
+	}, "admin")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if !created.Enabled || created.Config.Secret != RedactedValue || created.CreatedBy != "admin" {
+		t.Fatalf("created = %+v", created)
+	}
+	if _, err := service.Create(ChannelInput{Name: "值班群", Type: TypeWeCom, Config: ChannelConfig{URL: "https://qyapi.weixin.qq.com/x"}}, "admin"); !errors.As(err, &validationErr) || validationErr.Field != "name" {
+		t.Fatalf("expected duplicate name to be rejected, got %v", err)
+	}
+
+	// 提交占位值时保留原密钥
+	disabled := false
+	updated, err := service.Update(created.ID, ChannelInput{
+		Name:    "值班群",
+		Type:    TypeDingTalk,
+		Enabled: &disabled,
+		Config:  ChannelConfig{URL: "https://oapi.dingtalk.com/robot/send?access_token=y", Secret: RedactedValue},
+		Events:  []string{"alert.*", "approval.created"},
+	})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if updated.Enabled || len(updated.Events) != 2 {
+		t.Fatalf("updated = %+v", updated)
+	}
+	stored, _ := service.repo.Get(created.ID)
+	if stored.Config.Secret != "SEC123" || !strings.HasSuffix(stored.Config.URL, "token=y") {
+		t.Fatalf("expected secret to be kept, got %+v", stored.Config)
+	}
+
+	items, err := service.List()
+	if err != nil || len(items) != 1 || items[0].Config.Secret != RedactedValue {
+		t.Fatalf("List = %+v, %v", items, err)
+	}
+
+	if err := service.Delete(created.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := service.Get(created.ID); !errors.Is(err, ErrChannelNotFound) {
+		t.Fatalf("expected channel to be deleted, got %v", err)
+	}
+}
+
+func TestSQLiteChannelDelivery(t *testing.T) {
+	service := newTestService(t)
+
+	dingtalk := &robotServer{failures: 1}
+	dingtalkServer := httptest.NewServer(dingtalk)
+	defer dingtalkServer.Close()
+	wecom := &robotServer{failures: 100}
+	wecomServer := httptest.NewServer(wecom)
+	defer wecomServer.Close()
+
+	alertsOnly, err := service.Create(ChannelInput{
+		Name:     "dingtalk",
+		Type:     TypeDingTalk,
+		Config:   ChannelConfig{URL: dingtalkServer.URL, Secret: "SEC123"},
+		Events:   []string{"alert.*"},
+		Template: "{{.Title}} -> {{len .Alerts}}",
+	}, "admin")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	broken, err := service.Create(ChannelInput{Name: "wecom", Type: TypeWeCom, Config: ChannelConfig{URL: wecomServer.URL}}, "admin")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	// 审批事件只推送到订阅全部事件的渠道
+	if err := service.Publish(context.Background(), &notify.ApprovalMessage{Event: "approval.created", Title: "新的审批", Text: "新的审批\nalice"}); err == nil || !strings.Contains(err.Error(), "wecom") {
+		t.Fatalf("expected wecom failure to be reported, got %v", err)
+	}
+	if len(dingtalk.bodies) != 0 || len(wecom.bodies) != deliveryAttempts {
+		t.Fatalf("dingtalk = %d, wecom = %d requests", len(dingtalk.bodies), len(wecom.bodies))
+	}
+	if ch, _ := service.repo.Get(broken.ID); ch.LastSentAt == nil || !strings.Contains(ch.LastError, "310000") {
+		t.Fatalf("expected failure to be recorded, got %+v", ch)
+	}
+
+	// 钉钉首次失败后重试成功，按渠道模板渲染并加签
+	msg := &notify.ApprovalMessage{Event: "alert.firing", Title: "2 条告警触发", Text: "ignored", Alerts: nil}
+	if err := service.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send should not return channel errors, got %v", err)
+	}
+	if len(dingtalk.bodies) != 2 || dingtalk.content(1) != "2 条告警触发 -> 0" {
+		t.Fatalf("unexpected dingtalk content: %d requests, %q", len(dingtalk.bodies), dingtalk.content(len(dingtalk.bodies)-1))
+	}
+	if q := dingtalk.queries[1]; !strings.Contains(q, "timestamp=") || !strings.Contains(q, "sign=") {
+		t.Fatalf("expected signed request, got %q", q)
+	}
+	if ch, _ := service.repo.Get(alertsOnly.ID); ch.LastError != "" || ch.LastSentAt == nil {
+		t.Fatalf("expected success to be recorded, got %+v", ch)
+	}
+
+	// 测试通知不受订阅限制，失败时返回 ErrDeliveryFailed
+	if err := service.Test(context.Background(), alertsOnly.ID); err != nil {
+		t.Fatalf("Test failed: %v", err)
+	}
+	if err := service.Test(context.Background(), broken.ID); !errors.Is(err, ErrDeliveryFailed) {
+		t.Fatalf("expected ErrDeliveryFailed, got %v", err)
+	}
+}
+
+func TestSQLiteAlertRouting(t *testing.T) {
+	service := newTestService(t)
+
+	payments := &robotServer{}
+	paymentsServer := httptest.NewServer(payments)
+	defer paymentsServer.Close()
+	platform := &robotServer{}
+	platformServer := httptest.NewServer(platform)
+	defer platformServer.Close()
+
+	paymentsChannel, err := service.Create(ChannelInput{Name: "payments", Type: TypeWeCom, Config: ChannelConfig{URL: paymentsServer.URL}, Events: []string{"approval.*"}}, "admin")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	platformChannel, err := service.Create(ChannelInput{Name: "platform", Type: TypeWeCom, Config: ChannelConfig{URL: platformServer.URL}}, "admin")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	var validationErr *ValidationError
+	if _, err := service.CreateRoute(RouteInput{Name: "bad", ChannelID: paymentsChannel.ID, Match: RouteMatch{Severities: []string{"fatal"}}}, "alice"); !errors.As(err, &validationErr) || validationErr.Field != "match.severities[0]" {
+		t.Fatalf("expected invalid severity to be rejected, got %v", err)
+	}
+	if _, err := service.CreateRoute(RouteInput{Name: "bad", ChannelID: 999}, "alice"); !errors.As(err, &validationErr) || validationErr.Field != "channelId" {
+		t.Fatalf("expected unknown channel to be rejected, got %v", err)
+	}
+	if _, err := service.CreateRoute(RouteInput{Name: "bad", ChannelID: paymentsChannel.ID, QuietHours: &QuietHours{Start: "22:00", End: "8am"}}, "alice"); !errors.As(err, &validationErr) || validationErr.Field != "quietHours.end" {
+		t.Fatalf("expected invalid quiet hours to be rejected, got %v", err)
+	}
+
+	paymentsRoute, err := service.CreateRoute(RouteInput{
+		Name:      "payments-critical",
+		ChannelID: paymentsChannel.ID,
+		Match:     RouteMatch{Severities: []string{"Critical"}, Namespaces: []string{"payments"}},
+	}, "alice")
+	if err != nil {
+		t.Fatalf("CreateRoute failed: %v", err)
+	}
+	if paymentsRoute.ChannelName != "payments" || paymentsRoute.Match.Severities[0] != "critical" || !paymentsRoute.Enabled {
+		t.Fatalf("route = %+v", paymentsRoute)
+	}
+	// 同一渠道的两条路由命中同一告警时只推送一次
+	if _, err := service.CreateRoute(RouteInput{Name: "payments-kube", ChannelID: paymentsChannel.ID, Match: RouteMatch{AlertNames: []string{"Kube*"}}}, "alice"); err != nil {
+		t.Fatalf("CreateRoute failed: %v", err)
+	}
+	quietRoute, err := service.CreateRoute(RouteInput{
+		Name:       "platform-night",
+		ChannelID:  platformChannel.ID,
+		QuietHours: &QuietHours{Start: "22:00", End: "08:00", Timezone: "UTC"},
+	}, "bob")
+	if err != nil {
+		t.Fatalf("CreateRoute failed: %v", err)
+	}
+
+	router := NewAlertRouter(service, "https://dashboard.example.com/")
+	router.now = func() time.Time { return time.Date(2024, 5, 1, 23, 30, 0, 0, time.UTC) }
+	entries := []*alerts.HistoryEntry{
+		{Fingerprint: "fp-1", AlertName: "KubePodCrashLooping", Namespace: "payments", Severity: "critical", Status: "firing"},
+		{Fingerprint: "fp-2", AlertName: "HighLatency", Namespace: "payments", Severity: "warning", Status: "firing"},
+		{Fingerprint: "fp-3", AlertName: "DiskFull", Namespace: "orders", Severity: "critical", Status: "firing"},
+	}
+	if err := router.Route(context.Background(), entries); err != nil {
+		t.Fatalf("Route failed: %v", err)
+	}
+	if len(payments.bodies) != 1 || !strings.HasPrefix(payments.content(0), "[严重] 告警触发：KubePodCrashLooping") || !strings.Contains(payments.content(0), "https://dashboard.example.com/alerts") {
+		t.Fatalf("unexpected payments content: %d requests", len(payments.bodies))
+	}
+	if len(platform.bodies) != 0 {
+		t.Fatalf("expected platform route to be muted during quiet hours, got %d requests", len(platform.bodies))
+	}
+
+	router.now = func() time.Time { return time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC) }
+	if err := router.Route(context.Background(), entries); err != nil {
+		t.Fatalf("Route failed: %v", err)
+	}
+	if len(platform.bodies) != 1 || !strings.HasPrefix(platform.content(0), "3 条告警触发") {
+		t.Fatalf("unexpected platform content: %d requests", len(platform.bodies))
+	}
+
+	// 停用的路由不再推送
+	disabled := false
+	if _, err := service.UpdateRoute(quietRoute.ID, RouteInput{Name: "platform-night", ChannelID: platformChannel.ID, Enabled: &disabled}); err != nil {
+		t.Fatalf("UpdateRoute failed: %v", err)
+	}
+	if err := router.Route(context.Background(), entries); err != nil {
+		t.Fatalf("Route failed: %v", err)
+	}
+	if len(platform.bodies) != 1 {
+		t.Fatalf("expected disabled route to be skipped, got %d requests", len(platform.bodies))
+	}
+
+	// 被路由引用的渠道不能删除
+	if err := service.Delete(paymentsChannel.ID); !errors.As(err, &validationErr) {
+		t.Fatalf("expected referenced channel deletion to be rejected, got %v", err)
+	}
+	routes, err := service.ListRoutes()
+	if err != nil || len(routes) != 3 {
+		t.Fatalf("ListRoutes = %+v, %v", routes, err)
+	}
+	for _, rt := range routes {
+		if err := service.DeleteRoute(rt.ID); err != nil {
+			t.Fatalf("DeleteRoute failed: %v", err)
+		}
+	}
+	if err := service.Delete(paymentsChannel.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+}
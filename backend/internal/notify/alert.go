@@ -0,0 +1,168 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/k8s-dashboard/backend/internal/alertmanager"
+	"github.com/k8s-dashboard/backend/internal/alerts"
+)
+
+// 告警通知事件
+const (
+	EventAlertFiring   = "alert.firing"
+	EventAlertResolved = "alert.resolved"
+)
+
+// alertSummaryLimit 合并通知中逐条列出的告警数量上限
+const alertSummaryLimit = 5
+
+// AlertNotifier 将 Alertmanager Webhook 推送的告警转发到与审批通知相同的外部渠道
+type AlertNotifier struct {
+	sinks        []Sink
+	dashboardURL string
+}
+
+// NewAlertNotifier 创建告警通知器，dashboardURL 用于生成告警页面链接
+func NewAlertNotifier(dashboardURL string, sinks ...Sink) *AlertNotifier {
+	return &AlertNotifier{sinks: sinks, dashboardURL: strings.TrimRight(dashboardURL, "/")}
+}
+
+// HandleAlerts 作为 alerts.HistoryHandler 使用，异步推送
+func (n *AlertNotifier) HandleAlerts(entries []*alerts.HistoryEntry) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if err := n.Notify(ctx, entries); err != nil {
+			log.Printf("告警通知推送失败 [%d 条]: %v", len(entries), err)
+		}
+	}()
+}
+
+// Notify 按触发/恢复分组渲染告警并推送到各渠道
+func (n *AlertNotifier) Notify(ctx context.Context, entries []*alerts.HistoryEntry) error {
+	var errs []error
+	for _, msg := range AlertMessages(n.dashboardURL, entries) {
+		if err := deliver(ctx, n.sinks, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// AlertMessages 按触发/恢复分组渲染告警通知，dashboardURL 非空时附带告警页面链接
+func AlertMessages(dashboardURL string, entries []*alerts.HistoryEntry) []*ApprovalMessage {
+	dashboardURL = strings.TrimRight(dashboardURL, "/")
+	var messages []*ApprovalMessage
+	for _, group := range groupAlerts(entries) {
+		msg := &ApprovalMessage{
+			Event:  group.event,
+			Title:  alertTitle(group.event, group.entries),
+			Alerts: group.entries,
+		}
+		// Slack 等渠道只展示正文，正文首行附带标题
+		msg.Text = msg.Title + "\n" + alertText(group.entries)
+		if dashboardURL != "" {
+			msg.Link = dashboardURL + "/alerts"
+			msg.Text += "\n查看：" + msg.Link
+		}
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+type alertGroup struct {
+	event   string
+	entries []*alerts.HistoryEntry
+}
+
+// groupAlerts 按事件（触发、恢复）分组，保持触发在前
+func groupAlerts(entries []*alerts.HistoryEntry) []alertGroup {
+	var firing, resolved []*alerts.HistoryEntry
+	for _, entry := range entries {
+		if entry.Status == alertmanager.WebhookStatusResolved {
+			resolved = append(resolved, entry)
+		} else {
+			firing = append(firing, entry)
+		}
+	}
+	var groups []alertGroup
+	if len(firing) > 0 {
+		groups = append(groups, alertGroup{event: EventAlertFiring, entries: firing})
+	}
+	if len(resolved) > 0 {
+		groups = append(groups, alertGroup{event: EventAlertResolved, entries: resolved})
+	}
+	return groups
+}
+
+func alertTitle(event string, entries []*alerts.HistoryEntry) string {
+	verb := "触发"
+	if event == EventAlertResolved {
+		verb = "已恢复"
+	}
+	if len(entries) > 1 {
+		return fmt.Sprintf("%d 条告警%s", len(entries), verb)
+	}
+	entry := entries[0]
+	title := fmt.Sprintf("告警%s：%s", verb, entry.AlertName)
+	if event == EventAlertFiring && entry.Severity == alertmanager.SeverityCritical {
+		title = "[严重] " + title
+	}
+	return title
+}
+
+// alertText 逐条列出告警名称、级别、位置与摘要，超过上限时省略
+func alertText(entries []*alerts.HistoryEntry) string {
+	lines := make([]string, 0, len(entries))
+	for i, entry := range entries {
+		if i == alertSummaryLimit {
+			lines = append(lines, fmt.Sprintf("……另有 %d 条", len(entries)-alertSummaryLimit))
+			break
+		}
+		line := fmt.Sprintf("[%s] %s", entry.Severity, entry.AlertName)
+		var scope []string
+		if entry.Cluster != "" {
+			scope = append(scope, "集群 "+entry.Cluster)
+		}
+		if entry.Namespace != "" {
+			scope = append(scope, "命名空间 "+entry.Namespace)
+		}
+		if len(scope) > 0 {
+			line += "（" + strings.Join(scope, "，") + "）"
+		}
+		if summary := alertSummary(entry); summary != "" {
+			line += "：" + summary
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func alertSummary(entry *alerts.HistoryEntry) string {
+	if summary := entry.Annotations["summary"]; summary != "" {
+		return summary
+	}
+	return entry.Annotations["description"]
+}
+
+// HandleAlerts 作为 alerts.HistoryHandler 使用：将告警触发与恢复实时推送给所有在线用户
+func (h *Hub) HandleAlerts(entries []*alerts.HistoryEntry) {
+	for _, group := range groupAlerts(entries) {
+		n := &Notification{
+			Kind:      group.event,
+			Title:     alertTitle(group.event, group.entries),
+			Message:   alertText(group.entries),
+			Cluster:   group.entries[0].Cluster,
+			CreatedAt: time.Now(),
+		}
+		if len(group.entries) == 1 {
+			n.Fingerprint = group.entries[0].Fingerprint
+		}
+		h.publish(Message{Type: MessageNotification, Notification: n}, func(*subscriber) bool { return true })
+	}
+}
@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/k8s-dashboard/backend/internal/audit"
+)
+
+// EventAuditAnomaly 审计异常告警事件
+const EventAuditAnomaly = "audit.anomaly"
+
+// AnomalyNotifier 将审计异常告警推送到与审批通知相同的外部渠道
+type AnomalyNotifier struct {
+	sinks        []Sink
+	dashboardURL string
+}
+
+// NewAnomalyNotifier 创建审计异常通知器，dashboardURL 用于生成告警列表链接
+func NewAnomalyNotifier(dashboardURL string, sinks ...Sink) *AnomalyNotifier {
+	return &AnomalyNotifier{sinks: sinks, dashboardURL: strings.TrimRight(dashboardURL, "/")}
+}
+
+// HandleAnomaly 作为 audit.AnomalyHandler 使用，异步推送
+func (n *AnomalyNotifier) HandleAnomaly(anomaly *audit.AuditAnomaly) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if err := n.Notify(ctx, anomaly); err != nil {
+			log.Printf("审计异常通知推送失败 [%s #%d]: %v", anomaly.Rule, anomaly.ID, err)
+		}
+	}()
+}
+
+// Notify 渲染告警并推送到各渠道
+func (n *AnomalyNotifier) Notify(ctx context.Context, anomaly *audit.AuditAnomaly) error {
+	msg := &ApprovalMessage{
+		Event:   EventAuditAnomaly,
+		Title:   anomalyTitle(anomaly),
+		Text:    anomaly.Message,
+		Anomaly: anomaly,
+	}
+	if anomaly.Cluster != "" {
+		msg.Text += fmt.Sprintf("（集群 %s）", anomaly.Cluster)
+	}
+	if n.dashboardURL != "" {
+		msg.Link = n.dashboardURL + "/audit?tab=anomalies"
+		msg.Text += "\n查看：" + msg.Link
+	}
+	return deliver(ctx, n.sinks, msg)
+}
+
+func anomalyTitle(anomaly *audit.AuditAnomaly) string {
+	if anomaly.Severity == audit.AnomalySeverityCritical {
+		return fmt.Sprintf("[严重] 审计异常：%s", anomaly.Rule)
+	}
+	return fmt.Sprintf("审计异常：%s", anomaly.Rule)
+}
@@ -0,0 +1,220 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/k8s-dashboard/backend/internal/audit"
+	"github.com/k8s-dashboard/backend/internal/auth"
+)
+
+// 推送消息类型
+const (
+	MessagePendingApprovals = "pending_approvals"
+	MessageNotification     = "notification"
+	MessageSessionExpiring  = "session_expiring"
+	MessageSessionExpired   = "session_expired"
+)
+
+// pendingResyncInterval 定期重算待审批数量，覆盖其他副本产生的变更
+const pendingResyncInterval = 30 * time.Second
+
+// subscriberBuffer 订阅者缓冲，写满时丢弃消息，避免慢连接阻塞广播
+const subscriberBuffer = 16
+
+// Notification 推送给用户的通知
+type Notification struct {
+	Kind        string    `json:"kind"` // approval.created, approval.approved, approval.rejected, audit.anomaly, alert.firing, alert.resolved
+	Title       string    `json:"title"`
+	Message     string    `json:"message,omitempty"`
+	ApprovalID  int64     `json:"approvalId,omitempty"`
+	Cluster     string    `json:"cluster,omitempty"`     // 告警通知所属集群
+	Fingerprint string    `json:"fingerprint,omitempty"` // 单条告警通知的告警指纹
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// Message 推送消息
+type Message struct {
+	Type         string        `json:"type"`
+	Count        *int64        `json:"count,omitempty"`
+	Notification *Notification `json:"notification,omitempty"`
+	ExpiresAt    *time.Time    `json:"expiresAt,omitempty"`
+}
+
+type subscriber struct {
+	userID int64
+	admin  bool
+	ch     chan Message
+}
+
+// Hub 按用户分发实时消息。待审批数量仅推送给 admin，审批结果推送给申请人
+type Hub struct {
+	countPending func() (int64, error)
+
+	mu           sync.Mutex
+	subscribers  map[*subscriber]struct{}
+	pendingCount int64
+	pendingKnown bool
+}
+
+// NewHub 创建消息中心，countPending 用于查询当前待审批数量
+func NewHub(countPending func() (int64, error)) *Hub {
+	return &Hub{
+		countPending: countPending,
+		subscribers:  make(map[*subscriber]struct{}),
+	}
+}
+
+// Run 定期重算待审批数量，变化时广播给 admin，直到 ctx 结束
+func (h *Hub) Run(ctx context.Context) {
+	ticker := time.NewTicker(pendingResyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if h.hasAdminSubscribers() {
+				h.refreshPending()
+			}
+		}
+	}
+}
+
+// Subscribe 注册订阅，返回消息通道与取消函数。admin 订阅时会立即收到当前待审批数量
+func (h *Hub) Subscribe(userID int64, admin bool) (<-chan Message, func()) {
+	sub := &subscriber{userID: userID, admin: admin, ch: make(chan Message, subscriberBuffer)}
+
+	h.mu.Lock()
+	h.subscribers[sub] = struct{}{}
+	h.mu.Unlock()
+
+	if admin {
+		if count, err := h.currentPending(); err == nil {
+			sub.ch <- pendingMessage(count)
+		}
+	}
+
+	return sub.ch, func() {
+		h.mu.Lock()
+		delete(h.subscribers, sub)
+		h.mu.Unlock()
+	}
+}
+
+// approvalTarget 审批操作的简短描述，如 default/delete deployments web
+func approvalTarget(approval *auth.ApprovalRequest) string {
+	target := fmt.Sprintf("%s %s", approval.Action, approval.Resource)
+	if approval.ResourceName != "" {
+		target += " " + approval.ResourceName
+	}
+	if approval.Namespace != "" {
+		target = approval.Namespace + "/" + target
+	}
+	return target
+}
+
+// HandleApproval 作为 auth.ApprovalEventHandler 使用：刷新待审批数量，并通知相关用户
+func (h *Hub) HandleApproval(eventType string, approval *auth.ApprovalRequest) {
+	h.refreshPending()
+
+	n := &Notification{Kind: eventType, ApprovalID: approval.ID, CreatedAt: time.Now(), Message: approvalTarget(approval)}
+	switch eventType {
+	case auth.EventApprovalCreated:
+		n.Title = fmt.Sprintf("%s 提交了新的审批请求", approval.Username)
+		h.publish(Message{Type: MessageNotification, Notification: n}, func(s *subscriber) bool {
+			return s.admin && s.userID != approval.UserID
+		})
+	case auth.EventApprovalVoted:
+		n.Title = fmt.Sprintf("审批已获 %d/%d 人批准", len(approval.Votes), approval.RequiredApprovals)
+		h.publish(Message{Type: MessageNotification, Notification: n}, func(s *subscriber) bool {
+			return s.userID == approval.UserID
+		})
+	case auth.EventApprovalApproved, auth.EventApprovalRejected:
+		n.Title = "审批已通过"
+		if eventType == auth.EventApprovalRejected {
+			n.Title = "审批被拒绝"
+		}
+		if approval.Comment != "" {
+			n.Message += "：" + approval.Comment
+		}
+		h.publish(Message{Type: MessageNotification, Notification: n}, func(s *subscriber) bool {
+			return s.userID == approval.UserID
+		})
+	}
+}
+
+// HandleAnomaly 作为 audit.AnomalyHandler 使用：将审计异常告警推送给在线的 admin
+func (h *Hub) HandleAnomaly(anomaly *audit.AuditAnomaly) {
+	n := &Notification{Kind: EventAuditAnomaly, Title: anomalyTitle(anomaly), Message: anomaly.Message, CreatedAt: time.Now()}
+	h.publish(Message{Type: MessageNotification, Notification: n}, func(s *subscriber) bool { return s.admin })
+}
+
+// refreshPending 重新查询待审批数量，变化时广播给 admin
+func (h *Hub) refreshPending() {
+	if h.countPending == nil {
+		return
+	}
+	count, err := h.countPending()
+	if err != nil {
+		log.Printf("Warning: 查询待审批数量失败: %v", err)
+		return
+	}
+
+	h.mu.Lock()
+	changed := !h.pendingKnown || h.pendingCount != count
+	h.pendingCount, h.pendingKnown = count, true
+	h.mu.Unlock()
+
+	if changed {
+		h.publish(pendingMessage(count), func(s *subscriber) bool { return s.admin })
+	}
+}
+
+// currentPending 查询最新待审批数量并更新缓存（无 admin 在线时缓存不会定期刷新）
+func (h *Hub) currentPending() (int64, error) {
+	if h.countPending == nil {
+		return 0, fmt.Errorf("pending count unavailable")
+	}
+	count, err := h.countPending()
+	if err != nil {
+		return 0, err
+	}
+	h.mu.Lock()
+	h.pendingCount, h.pendingKnown = count, true
+	h.mu.Unlock()
+	return count, nil
+}
+
+func (h *Hub) hasAdminSubscribers() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subscribers {
+		if sub.admin {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *Hub) publish(msg Message, match func(*subscriber) bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subscribers {
+		if !match(sub) {
+			continue
+		}
+		select {
+		case sub.ch <- msg:
+		default:
+			// 客户端消费过慢时丢弃，下次数量变化或重连会重新同步
+		}
+	}
+}
+
+func pendingMessage(count int64) Message {
+	return Message{Type: MessagePendingApprovals, Count: &count}
+}
@@ -0,0 +1,110 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/k8s-dashboard/backend/internal/alerts"
+	"github.com/k8s-dashboard/backend/internal/audit"
+	"github.com/k8s-dashboard/backend/internal/auth"
+)
+
+func receive(t *testing.T, ch <-chan Message) Message {
+	t.Helper()
+	select {
+	case msg := <-ch:
+		return msg
+	default:
+		t.Fatal("expected a message")
+		return Message{}
+	}
+}
+
+func expectEmpty(t *testing.T, ch <-chan Message) {
+	t.Helper()
+	select {
+	case msg := <-ch:
+		t.Fatalf("unexpected message: %+v", msg)
+	default:
+	}
+}
+
+func TestHubApprovalNotifications(t *testing.T) {
+	pending := int64(0)
+	hub := NewHub(func() (int64, error) { return pending, nil })
+
+	admin, cancelAdmin := hub.Subscribe(1, true)
+	defer cancelAdmin()
+	requester, cancelRequester := hub.Subscribe(2, false)
+	defer cancelRequester()
+
+	if msg := receive(t, admin); msg.Type != MessagePendingApprovals || *msg.Count != 0 {
+		t.Fatalf("expected initial pending count, got %+v", msg)
+	}
+	expectEmpty(t, requester)
+
+	approval := &auth.ApprovalRequest{ID: 7, UserID: 2, Username: "dev", Action: "delete", Resource: "pods", Namespace: "default", ResourceName: "web"}
+	pending = 1
+	hub.HandleApproval(auth.EventApprovalCreated, approval)
+
+	if msg := receive(t, admin); msg.Type != MessagePendingApprovals || *msg.Count != 1 {
+		t.Fatalf("expected pending count 1, got %+v", msg)
+	}
+	if msg := receive(t, admin); msg.Type != MessageNotification || msg.Notification.ApprovalID != 7 {
+		t.Fatalf("expected approval notification for admin, got %+v", msg)
+	}
+	expectEmpty(t, requester)
+
+	pending = 0
+	hub.HandleApproval(auth.EventApprovalRejected, approval)
+
+	if msg := receive(t, admin); msg.Type != MessagePendingApprovals || *msg.Count != 0 {
+		t.Fatalf("expected pending count 0, got %+v", msg)
+	}
+	expectEmpty(t, admin)
+	if msg := receive(t, requester); msg.Notification == nil || msg.Notification.Kind != auth.EventApprovalRejected {
+		t.Fatalf("expected rejection notification for requester, got %+v", msg)
+	}
+}
+
+func TestHubAnomalyNotifications(t *testing.T) {
+	hub := NewHub(nil)
+	admin, cancelAdmin := hub.Subscribe(1, true)
+	defer cancelAdmin()
+	viewer, cancelViewer := hub.Subscribe(2, false)
+	defer cancelViewer()
+
+	hub.HandleAnomaly(&audit.AuditAnomaly{Rule: "mass-delete", Severity: audit.AnomalySeverityCritical, Message: "alice deleted 25 pods"})
+
+	msg := receive(t, admin)
+	if msg.Notification == nil || msg.Notification.Kind != EventAuditAnomaly || !strings.Contains(msg.Notification.Title, "mass-delete") {
+		t.Fatalf("expected anomaly notification for admin, got %+v", msg)
+	}
+	expectEmpty(t, viewer)
+}
+
+func TestHubAlertNotifications(t *testing.T) {
+	hub := NewHub(nil)
+	admin, cancelAdmin := hub.Subscribe(1, true)
+	defer cancelAdmin()
+	viewer, cancelViewer := hub.Subscribe(2, false)
+	defer cancelViewer()
+
+	hub.HandleAlerts([]*alerts.HistoryEntry{
+		{Cluster: "prod", Fingerprint: "fp-1", AlertName: "NodeDown", Severity: "critical", Status: "firing"},
+		{Cluster: "prod", Fingerprint: "fp-2", AlertName: "HighCPU", Severity: "warning", Status: "resolved"},
+	})
+
+	// 告警推送给所有在线用户，触发与恢复分别推送
+	for _, ch := range []<-chan Message{admin, viewer} {
+		firing := receive(t, ch)
+		if n := firing.Notification; n == nil || n.Kind != EventAlertFiring || n.Title != "[严重] 告警触发：NodeDown" || n.Fingerprint != "fp-1" || n.Cluster != "prod" {
+			t.Fatalf("unexpected firing notification: %+v", firing.Notification)
+		}
+		resolved := receive(t, ch)
+		if n := resolved.Notification; n == nil || n.Kind != EventAlertResolved || !strings.Contains(n.Message, "HighCPU") {
+			t.Fatalf("unexpected resolved notification: %+v", resolved.Notification)
+		}
+		expectEmpty(t, ch)
+	}
+}
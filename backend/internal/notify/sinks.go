@@ -0,0 +1,287 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/k8s-dashboard/backend/internal/alerts"
+	"github.com/k8s-dashboard/backend/internal/audit"
+	"github.com/k8s-dashboard/backend/internal/auth"
+	"github.com/k8s-dashboard/backend/internal/webhook"
+)
+
+// DefaultApprovalTemplate 审批通知默认正文模板（text/template），
+// 可用字段：.Event .Title .Target .Link .Approval（auth.ApprovalRequest）
+const DefaultApprovalTemplate = `{{.Title}}
+操作：{{.Target}}{{if .Approval.Cluster}}（集群 {{.Approval.Cluster}}）{{end}}
+申请人：{{.Approval.Username}}{{if .Approval.Reason}}
+理由：{{.Approval.Reason}}{{end}}{{if .Approval.Preview}}
+影响：{{.Approval.Preview.Summary}}{{end}}{{if .Approval.ApproverName}}
+审批人：{{.Approval.ApproverName}}{{end}}{{if .Approval.Comment}}
+意见：{{.Approval.Comment}}{{end}}{{if .Link}}
+查看：{{.Link}}{{end}}`
+
+// sinkAttempts 单个渠道的最大推送次数
+const sinkAttempts = 3
+
+// ApprovalMessage 渲染后的通知，审批通知携带 Approval，审计异常告警携带 Anomaly，Alertmanager 告警携带 Alerts
+type ApprovalMessage struct {
+	Event    string                 `json:"event"`
+	Title    string                 `json:"title"`
+	Text     string                 `json:"text"`
+	Link     string                 `json:"link,omitempty"`
+	Approval *auth.ApprovalRequest  `json:"approval,omitempty"`
+	Anomaly  *audit.AuditAnomaly    `json:"anomaly,omitempty"`
+	Alerts   []*alerts.HistoryEntry `json:"alerts,omitempty"`
+
+	// Recipients 邮件收件人（审批创建时为 admin，处理后为申请人）
+	Recipients []string `json:"-"`
+}
+
+// Sink 审批通知的外部渠道
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, msg *ApprovalMessage) error
+}
+
+// RecipientResolver 按事件返回邮件收件人
+type RecipientResolver func(eventType string, approval *auth.ApprovalRequest) []string
+
+// ApprovalNotifier 将审批创建与处理结果推送到 Webhook、Slack 与邮件，审批人无需轮询待审批数量
+type ApprovalNotifier struct {
+	sinks        []Sink
+	tmpl         *template.Template
+	dashboardURL string
+	recipients   RecipientResolver
+}
+
+// NewApprovalNotifier 创建审批通知器，tmpl 为空时使用 DefaultApprovalTemplate，
+// dashboardURL 用于生成审批详情链接，recipients 可为 nil
+func NewApprovalNotifier(tmpl, dashboardURL string, recipients RecipientResolver, sinks ...Sink) (*ApprovalNotifier, error) {
+	if strings.TrimSpace(tmpl) == "" {
+		tmpl = DefaultApprovalTemplate
+	}
+	parsed, err := template.New("approval").Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("解析审批通知模板失败: %w", err)
+	}
+	return &ApprovalNotifier{
+		sinks:        sinks,
+		tmpl:         parsed,
+		dashboardURL: strings.TrimRight(dashboardURL, "/"),
+		recipients:   recipients,
+	}, nil
+}
+
+// HandleApproval 作为 auth.ApprovalEventHandler 使用，异步推送，不阻塞审批请求
+func (n *ApprovalNotifier) HandleApproval(eventType string, approval *auth.ApprovalRequest) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if err := n.Notify(ctx, eventType, approval); err != nil {
+			log.Printf("审批通知推送失败 [%s #%d]: %v", eventType, approval.ID, err)
+		}
+	}()
+}
+
+// Notify 渲染通知并推送到各渠道
+func (n *ApprovalNotifier) Notify(ctx context.Context, eventType string, approval *auth.ApprovalRequest) error {
+	msg, err := n.Render(eventType, approval)
+	if err != nil {
+		return err
+	}
+	if n.recipients != nil {
+		msg.Recipients = n.recipients(eventType, approval)
+	}
+
+	return deliver(ctx, n.sinks, msg)
+}
+
+// deliver 依次推送到各渠道，单个渠道失败时重试，不影响其他渠道
+func deliver(ctx context.Context, sinks []Sink, msg *ApprovalMessage) error {
+	var errs []error
+	for _, sink := range sinks {
+		var err error
+		for attempt := 0; attempt < sinkAttempts; attempt++ {
+			if attempt > 0 {
+				select {
+				case <-ctx.Done():
+					return errors.Join(append(errs, fmt.Errorf("%s: %w", sink.Name(), ctx.Err()))...)
+				case <-time.After(time.Duration(attempt) * 2 * time.Second):
+				}
+			}
+			if err = sink.Send(ctx, msg); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sink.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Render 按模板渲染审批通知
+func (n *ApprovalNotifier) Render(eventType string, approval *auth.ApprovalRequest) (*ApprovalMessage, error) {
+	msg := &ApprovalMessage{Event: eventType, Approval: approval}
+	switch eventType {
+	case auth.EventApprovalCreated:
+		msg.Title = fmt.Sprintf("%s 提交了新的审批请求", approval.Username)
+	case auth.EventApprovalVoted:
+		msg.Title = fmt.Sprintf("审批已获 %d/%d 人批准", len(approval.Votes), approval.RequiredApprovals)
+	case auth.EventApprovalApproved:
+		msg.Title = "审批已通过"
+	case auth.EventApprovalRejected:
+		msg.Title = "审批被拒绝"
+	default:
+		msg.Title = eventType
+	}
+	if n.dashboardURL != "" {
+		msg.Link = fmt.Sprintf("%s/approvals?id=%d", n.dashboardURL, approval.ID)
+	}
+
+	var buf bytes.Buffer
+	err := n.tmpl.Execute(&buf, struct {
+		Event, Title, Target, Link string
+		Approval                   *auth.ApprovalRequest
+	}{eventType, msg.Title, approvalTarget(approval), msg.Link, approval})
+	if err != nil {
+		return nil, fmt.Errorf("渲染审批通知失败: %w", err)
+	}
+	msg.Text = buf.String()
+	return msg, nil
+}
+
+// WebhookSink 通用 Webhook，推送完整的 JSON 通知，配置密钥时附带 HMAC 签名
+type WebhookSink struct {
+	client *webhook.Client
+}
+
+// NewWebhookSink 创建通用 Webhook 渠道
+func NewWebhookSink(url, secret string) *WebhookSink {
+	return &WebhookSink{client: webhook.NewClient(url, secret)}
+}
+
+func (s *WebhookSink) Name() string { return "webhook" }
+
+func (s *WebhookSink) Send(ctx context.Context, msg *ApprovalMessage) error {
+	return s.client.Send(ctx, msg.Event, msg)
+}
+
+// SlackSink Slack 兼容的 Incoming Webhook（Mattermost、Rocket.Chat 等同样适用），推送渲染后的文本
+type SlackSink struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewSlackSink 创建 Slack 兼容渠道
+func NewSlackSink(url string) *SlackSink {
+	return &SlackSink{url: strings.TrimSpace(url), httpClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (s *SlackSink) Name() string { return "slack" }
+
+func (s *SlackSink) Send(ctx context.Context, msg *ApprovalMessage) error {
+	payload, err := json.Marshal(map[string]string{"text": msg.Text})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("slack webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// EmailSink SMTP 邮件渠道，收件人为固定列表与按事件解析出的用户邮箱之和
+type EmailSink struct {
+	addr string
+	auth smtp.Auth
+	from string
+	to   []string
+
+	// sendMail 便于测试替换
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailSink 创建邮件渠道，username 为空时不进行 SMTP 认证
+func NewEmailSink(host string, port int, username, password, from string, to []string) *EmailSink {
+	var smtpAuth smtp.Auth
+	if username != "" {
+		smtpAuth = smtp.PlainAuth("", username, password, host)
+	}
+	return &EmailSink{
+		addr:     net.JoinHostPort(host, strconv.Itoa(port)),
+		auth:     smtpAuth,
+		from:     from,
+		to:       to,
+		sendMail: smtp.SendMail,
+	}
+}
+
+func (s *EmailSink) Name() string { return "email" }
+
+func (s *EmailSink) Send(ctx context.Context, msg *ApprovalMessage) error {
+	recipients := mergeRecipients(s.to, msg.Recipients)
+	if len(recipients) == 0 {
+		return nil
+	}
+	return s.sendMail(s.addr, s.auth, s.from, recipients, buildEmail(s.from, recipients, msg.Title, msg.Text))
+}
+
+// mergeRecipients 合并收件人并去重
+func mergeRecipients(lists ...[]string) []string {
+	seen := make(map[string]bool)
+	var merged []string
+	for _, list := range lists {
+		for _, addr := range list {
+			addr = strings.TrimSpace(addr)
+			if addr == "" || seen[strings.ToLower(addr)] {
+				continue
+			}
+			seen[strings.ToLower(addr)] = true
+			merged = append(merged, addr)
+		}
+	}
+	return merged
+}
+
+// buildEmail 构造纯文本邮件，主题按 RFC 2047 编码以支持中文
+func buildEmail(from string, to []string, subject, body string) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	buf.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	buf.WriteString("\r\n")
+	return buf.Bytes()
+}
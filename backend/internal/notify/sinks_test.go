@@ -0,0 +1,150 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/k8s-dashboard/backend/internal/alerts"
+	"github.com/k8s-dashboard/backend/internal/auth"
+	"github.com/k8s-dashboard/backend/internal/webhook"
+)
+
+func sampleApproval() *auth.ApprovalRequest {
+	return &auth.ApprovalRequest{
+		ID:           7,
+		UserID:       2,
+		Username:     "alice",
+		Action:       "delete",
+		Resource:     "deployments",
+		ResourceName: "web",
+		Namespace:    "prod",
+		Reason:       "下线旧版本",
+	}
+}
+
+func TestApprovalNotifierSinks(t *testing.T) {
+	var hookBody ApprovalMessage
+	var hookSignature, hookType string
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hookSignature = r.Header.Get(webhook.SignatureHeader)
+		event := webhook.Event{Data: &hookBody}
+		json.NewDecoder(r.Body).Decode(&event)
+		hookType = event.Type
+	}))
+	defer hook.Close()
+
+	var slackBody map[string]string
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&slackBody)
+	}))
+	defer slack.Close()
+
+	var mailTo []string
+	var mailBody string
+	email := NewEmailSink("smtp.example.com", 587, "", "", "dashboard@example.com", []string{"ops@example.com"})
+	email.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		mailTo, mailBody = to, string(msg)
+		return nil
+	}
+
+	recipients := func(eventType string, approval *auth.ApprovalRequest) []string {
+		return []string{"admin@example.com", "OPS@example.com"}
+	}
+	notifier, err := NewApprovalNotifier("", "https://dashboard.example.com/", recipients,
+		NewWebhookSink(hook.URL, "secret"), NewSlackSink(slack.URL), email)
+	if err != nil {
+		t.Fatalf("NewApprovalNotifier failed: %v", err)
+	}
+
+	if err := notifier.Notify(context.Background(), auth.EventApprovalCreated, sampleApproval()); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+
+	if hookType != auth.EventApprovalCreated || hookBody.Event != auth.EventApprovalCreated || hookBody.Approval == nil || hookBody.Approval.ID != 7 {
+		t.Fatalf("unexpected webhook payload: %+v", hookBody)
+	}
+	if hookBody.Link != "https://dashboard.example.com/approvals?id=7" || !strings.HasPrefix(hookSignature, "sha256=") {
+		t.Fatalf("unexpected webhook link/signature: %q %q", hookBody.Link, hookSignature)
+	}
+	if text := slackBody["text"]; !strings.Contains(text, "alice 提交了新的审批请求") || !strings.Contains(text, "prod/delete deployments web") {
+		t.Fatalf("unexpected slack text: %q", text)
+	}
+	if len(mailTo) != 2 || mailTo[0] != "ops@example.com" || mailTo[1] != "admin@example.com" {
+		t.Fatalf("expected deduplicated recipients, got %v", mailTo)
+	}
+	if !strings.Contains(mailBody, "Subject: =?utf-8?q?") || !strings.Contains(mailBody, "理由：下线旧版本") {
+		t.Fatalf("unexpected email:\n%s", mailBody)
+	}
+}
+
+func TestApprovalNotifierTemplateAndFailures(t *testing.T) {
+	if _, err := NewApprovalNotifier("{{.Missing", "", nil); err == nil {
+		t.Fatal("expected invalid template to be rejected")
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	notifier, err := NewApprovalNotifier("[{{.Event}}] {{.Approval.Username}} {{.Approval.Comment}}", "", nil, NewSlackSink(failing.URL))
+	if err != nil {
+		t.Fatalf("NewApprovalNotifier failed: %v", err)
+	}
+
+	approval := sampleApproval()
+	approval.Comment = "窗口期外"
+	msg, err := notifier.Render(auth.EventApprovalRejected, approval)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if msg.Title != "审批被拒绝" || msg.Text != "[approval.rejected] alice 窗口期外" || msg.Link != "" {
+		t.Fatalf("unexpected message: %+v", msg)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := notifier.Notify(ctx, auth.EventApprovalRejected, approval); err == nil || !strings.Contains(err.Error(), "slack") {
+		t.Fatalf("expected slack failure to be reported, got %v", err)
+	}
+}
+
+func TestAlertNotifierSinks(t *testing.T) {
+	var slackBody map[string]string
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&slackBody)
+	}))
+	defer slack.Close()
+
+	entries := make([]*alerts.HistoryEntry, 0, 7)
+	for i := 0; i < 7; i++ {
+		entries = append(entries, &alerts.HistoryEntry{
+			Cluster:     "prod",
+			Fingerprint: "fp-" + strconv.Itoa(i),
+			AlertName:   "PodCrashLooping",
+			Namespace:   "web",
+			Severity:    "warning",
+			Status:      "firing",
+			Annotations: map[string]string{"summary": "pod restarting"},
+		})
+	}
+
+	notifier := NewAlertNotifier("https://dashboard.example.com/", NewSlackSink(slack.URL))
+	if err := notifier.Notify(context.Background(), entries); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+
+	text := slackBody["text"]
+	if !strings.HasPrefix(text, "7 条告警触发\n") || !strings.Contains(text, "[warning] PodCrashLooping（集群 prod，命名空间 web）：pod restarting") {
+		t.Fatalf("unexpected slack text: %q", text)
+	}
+	if !strings.Contains(text, "另有 2 条") || !strings.HasSuffix(text, "查看：https://dashboard.example.com/alerts") {
+		t.Fatalf("expected truncated list with link, got %q", text)
+	}
+}
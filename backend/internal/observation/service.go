@@ -3,20 +3,24 @@ package observation
 import (
 	"context"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/k8s-dashboard/backend/internal/alertmanager"
 	"github.com/k8s-dashboard/backend/internal/k8s"
 	"github.com/k8s-dashboard/backend/internal/metrics"
+	"github.com/k8s-dashboard/backend/internal/promql"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // Service 集群观测服务
 type Service struct {
-	k8s     *k8s.Client
-	metrics *metrics.Client
-	alerts  *alertmanager.Client
+	k8s        *k8s.Client
+	metrics    *metrics.Client
+	alerts     *alertmanager.Client
+	thresholds *ThresholdRepository
+	cluster    string
 }
 
 // NewService 创建观测服务
@@ -38,12 +42,58 @@ func (s *Service) WithK8sClient(client *k8s.Client) *Service {
 	return &clone
 }
 
-// GetSummary 获取异常状态汇总
-func (s *Service) GetSummary(ctx context.Context) (*ObservationSummary, error) {
+// WithThresholds 使用持久化的异常阈值规则，未设置时使用默认阈值
+func (s *Service) WithThresholds(repo *ThresholdRepository) *Service {
+	s.thresholds = repo
+	return s
+}
+
+// WithCluster 返回按指定集群解析异常阈值的服务副本。
+func (s *Service) WithCluster(name string) *Service {
+	clone := *s
+	clone.cluster = name
+	return &clone
+}
+
+// Thresholds 返回异常阈值规则数据仓库，未启用时为 nil
+func (s *Service) Thresholds() *ThresholdRepository {
+	return s.thresholds
+}
+
+// thresholdSet 读取阈值规则，读取失败时回落到默认阈值
+func (s *Service) thresholdSet() ThresholdSet {
+	if s.thresholds == nil {
+		return nil
+	}
+	set, err := s.thresholds.List()
+	if err != nil {
+		log.Printf("读取异常阈值规则失败，使用默认阈值: %v", err)
+		return nil
+	}
+	return set
+}
+
+// WithEndpointClients 返回使用指定监控与告警客户端的服务副本，nil 参数保持原客户端。
+func (s *Service) WithEndpointClients(metricsClient *metrics.Client, alertClient *alertmanager.Client) *Service {
+	if metricsClient == nil && alertClient == nil {
+		return s
+	}
+	clone := *s
+	if metricsClient != nil {
+		clone.metrics = metricsClient
+	}
+	if alertClient != nil {
+		clone.alerts = alertClient
+	}
+	return &clone
+}
+
+// GetSummary 获取异常状态汇总，namespaces 非空时 Pod 异常与资源超限只统计这些命名空间
+func (s *Service) GetSummary(ctx context.Context, namespaces []string) (*ObservationSummary, error) {
 	summary := &ObservationSummary{}
 
 	// 获取 Pod 异常数量
-	podAnomalies, err := s.GetPodAnomalies(ctx, "")
+	podAnomalies, err := s.GetPodAnomalies(ctx, "", namespaces)
 	if err == nil {
 		summary.PodAnomalyCount = len(podAnomalies)
 	}
@@ -55,14 +105,14 @@ func (s *Service) GetSummary(ctx context.Context) (*ObservationSummary, error) {
 	}
 
 	// 获取资源超限数量
-	resourceExcess, err := s.GetResourceExcess(ctx, "")
+	resourceExcess, err := s.GetResourceExcess(ctx, "", namespaces)
 	if err == nil {
 		summary.ResourceExcessCount = len(resourceExcess)
 	}
 
 	// 获取活跃告警数量
 	if s.alerts != nil {
-		alertSummary, err := s.alerts.GetAlertSummary()
+		alertSummary, err := s.alerts.WithContext(ctx).GetAlertSummary()
 		if err == nil {
 			summary.ActiveAlertCount = alertSummary.Critical + alertSummary.Warning
 		}
@@ -71,10 +121,15 @@ func (s *Service) GetSummary(ctx context.Context) (*ObservationSummary, error) {
 	return summary, nil
 }
 
-// GetPodAnomalies 获取异常 Pod 列表
-func (s *Service) GetPodAnomalies(ctx context.Context, namespace string) ([]PodAnomaly, error) {
+// GetPodAnomalies 获取异常 Pod 列表，namespaces 非空时只返回这些命名空间中的 Pod
+func (s *Service) GetPodAnomalies(ctx context.Context, namespace string, namespaces []string) ([]PodAnomaly, error) {
 	var anomalies []PodAnomaly
 
+	allowed := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		allowed[ns] = true
+	}
+
 	// 获取所有 Pod
 	var pods *corev1.PodList
 	var err error
@@ -88,10 +143,15 @@ func (s *Service) GetPodAnomalies(ctx context.Context, namespace string) ([]PodA
 	}
 
 	now := time.Now()
+	thresholds := s.thresholdSet()
 
 	for _, pod := range pods.Items {
+		if len(allowed) > 0 && !allowed[pod.Namespace] {
+			continue
+		}
 		// 检查 Pod 是否处于异常状态
-		anomaly := s.checkPodAnomaly(&pod, now)
+		th, ruleID := thresholds.For(s.cluster, pod.Namespace)
+		anomaly := s.checkPodAnomaly(&pod, now, th, ruleID)
 		if anomaly != nil {
 			anomalies = append(anomalies, *anomaly)
 		}
@@ -100,15 +160,19 @@ func (s *Service) GetPodAnomalies(ctx context.Context, namespace string) ([]PodA
 	return anomalies, nil
 }
 
-// checkPodAnomaly 检查单个 Pod 是否异常
-func (s *Service) checkPodAnomaly(pod *corev1.Pod, now time.Time) *PodAnomaly {
+// checkPodAnomaly 按阈值检查单个 Pod 是否异常，ruleID 为阈值来源的规则（0 表示默认阈值）
+func (s *Service) checkPodAnomaly(pod *corev1.Pod, now time.Time, th AnomalyThresholds, ruleID int64) *PodAnomaly {
+	triggered := func(name string, value int) *TriggeredThreshold {
+		return &TriggeredThreshold{Name: name, Value: value, RuleID: ruleID}
+	}
+
 	// 检查 Pod 状态
 	phase := pod.Status.Phase
 
-	// Pending 状态超过 5 分钟
+	// Pending 状态超过阈值时长
 	if phase == corev1.PodPending {
 		duration := now.Sub(pod.CreationTimestamp.Time)
-		if duration > 5*time.Minute {
+		if duration > time.Duration(th.PendingMinutes)*time.Minute {
 			return &PodAnomaly{
 				Name:      pod.Name,
 				Namespace: pod.Namespace,
@@ -116,6 +180,7 @@ func (s *Service) checkPodAnomaly(pod *corev1.Pod, now time.Time) *PodAnomaly {
 				Message:   getPodConditionMessage(pod),
 				Duration:  formatDuration(duration),
 				NodeName:  pod.Spec.NodeName,
+				Threshold: triggered(ThresholdPendingMinutes, th.PendingMinutes),
 			}
 		}
 	}
@@ -152,8 +217,8 @@ func (s *Service) checkPodAnomaly(pod *corev1.Pod, now time.Time) *PodAnomaly {
 		// OOMKilled（最近终止状态）
 		if cs.LastTerminationState.Terminated != nil &&
 			cs.LastTerminationState.Terminated.Reason == "OOMKilled" {
-			// 只报告最近 1 小时内的 OOMKilled
-			if now.Sub(cs.LastTerminationState.Terminated.FinishedAt.Time) < time.Hour {
+			// 只报告阈值时间窗口内的 OOMKilled
+			if now.Sub(cs.LastTerminationState.Terminated.FinishedAt.Time) < time.Duration(th.OOMWindowMinutes)*time.Minute {
 				return &PodAnomaly{
 					Name:         pod.Name,
 					Namespace:    pod.Namespace,
@@ -162,12 +227,13 @@ func (s *Service) checkPodAnomaly(pod *corev1.Pod, now time.Time) *PodAnomaly {
 					RestartCount: int(cs.RestartCount),
 					Duration:     formatDuration(now.Sub(cs.LastTerminationState.Terminated.FinishedAt.Time)),
 					NodeName:     pod.Spec.NodeName,
+					Threshold:    triggered(ThresholdOOMWindowMinutes, th.OOMWindowMinutes),
 				}
 			}
 		}
 
-		// 高重启次数（超过 5 次）
-		if cs.RestartCount > 5 && cs.State.Running != nil {
+		// 高重启次数（超过阈值）
+		if int(cs.RestartCount) > th.RestartCount && cs.State.Running != nil {
 			return &PodAnomaly{
 				Name:         pod.Name,
 				Namespace:    pod.Namespace,
@@ -176,6 +242,7 @@ func (s *Service) checkPodAnomaly(pod *corev1.Pod, now time.Time) *PodAnomaly {
 				RestartCount: int(cs.RestartCount),
 				Duration:     formatDuration(now.Sub(pod.CreationTimestamp.Time)),
 				NodeName:     pod.Spec.NodeName,
+				Threshold:    triggered(ThresholdRestartCount, th.RestartCount),
 			}
 		}
 	}
@@ -256,16 +323,29 @@ func (s *Service) GetNodeAnomalies(ctx context.Context) ([]NodeAnomaly, error) {
 	return anomalies, nil
 }
 
-// GetResourceExcess 获取资源超限列表
-func (s *Service) GetResourceExcess(ctx context.Context, namespace string) ([]ResourceExcess, error) {
+// GetResourceExcess 获取资源超限列表，namespaces 非空时查询仅覆盖这些命名空间
+func (s *Service) GetResourceExcess(ctx context.Context, namespace string, namespaces []string) ([]ResourceExcess, error) {
 	var excess []ResourceExcess
 
 	if s.metrics == nil {
 		return excess, nil
 	}
 
+	if namespace != "" {
+		namespaces = []string{namespace}
+	}
+
+	cpuQuery, err := metrics.ScopeQuery(QueryHighCPUPods, namespaces)
+	if err != nil {
+		return nil, err
+	}
+	memQuery, err := metrics.ScopeQuery(QueryHighMemoryPods, namespaces)
+	if err != nil {
+		return nil, err
+	}
+
 	// 查询 CPU 超限的 Pod
-	cpuResp, err := s.metrics.Query(QueryHighCPUPods)
+	cpuResp, err := s.metrics.WithContext(ctx).Query(cpuQuery)
 	if err == nil {
 		for _, result := range cpuResp.Data.Result {
 			ns := result.Metric["namespace"]
@@ -273,11 +353,9 @@ func (s *Service) GetResourceExcess(ctx context.Context, namespace string) ([]Re
 			if namespace != "" && ns != namespace {
 				continue
 			}
-			var usage float64
-			if len(result.Value) >= 2 {
-				if val, ok := result.Value[1].(string); ok {
-					fmt.Sscanf(val, "%f", &usage)
-				}
+			usage, err := result.SampleValue()
+			if err != nil {
+				continue
 			}
 			excess = append(excess, ResourceExcess{
 				Type:         string(ResourceTypeCPU),
@@ -290,7 +368,7 @@ func (s *Service) GetResourceExcess(ctx context.Context, namespace string) ([]Re
 	}
 
 	// 查询内存超限的 Pod
-	memResp, err := s.metrics.Query(QueryHighMemoryPods)
+	memResp, err := s.metrics.WithContext(ctx).Query(memQuery)
 	if err == nil {
 		for _, result := range memResp.Data.Result {
 			ns := result.Metric["namespace"]
@@ -298,11 +376,9 @@ func (s *Service) GetResourceExcess(ctx context.Context, namespace string) ([]Re
 			if namespace != "" && ns != namespace {
 				continue
 			}
-			var usage float64
-			if len(result.Value) >= 2 {
-				if val, ok := result.Value[1].(string); ok {
-					fmt.Sscanf(val, "%f", &usage)
-				}
+			usage, err := result.SampleValue()
+			if err != nil {
+				continue
 			}
 			excess = append(excess, ResourceExcess{
 				Type:         string(ResourceTypeMemory),
@@ -343,7 +419,7 @@ func (s *Service) GetResourceTrend(ctx context.Context, resourceType ResourceTyp
 	step := timeRange.Step()
 
 	// 当前周期数据
-	resp, err := s.metrics.QueryRange(query, start, end, step)
+	resp, err := s.metrics.WithContext(ctx).QueryRange(query, start, end, step)
 	if err != nil {
 		return nil, err
 	}
@@ -352,7 +428,7 @@ func (s *Service) GetResourceTrend(ctx context.Context, resourceType ResourceTyp
 	// 计算周环比（上周同期）
 	prevStart := start.Add(-7 * 24 * time.Hour)
 	prevEnd := end.Add(-7 * 24 * time.Hour)
-	prevResp, err := s.metrics.QueryRange(query, prevStart, prevEnd, step)
+	prevResp, err := s.metrics.WithContext(ctx).QueryRange(query, prevStart, prevEnd, step)
 	if err == nil {
 		trend.Previous = extractTimeSeriesPoints(prevResp)
 	}
@@ -375,7 +451,7 @@ func (s *Service) GetAlertTrend(ctx context.Context, timeRange TimeRange) (*Aler
 
 	if s.alerts != nil {
 		// 获取当前告警摘要作为基础数据
-		summary, err := s.alerts.GetAlertSummary()
+		summary, err := s.alerts.WithContext(ctx).GetAlertSummary()
 		if err == nil {
 			// 简化处理：使用当前数据作为趋势点
 			today := time.Now().Format("2006-01-02")
@@ -389,12 +465,21 @@ func (s *Service) GetAlertTrend(ctx context.Context, timeRange TimeRange) (*Aler
 	return trend, nil
 }
 
-// GetRestartTrend 获取 Pod 重启趋势
-func (s *Service) GetRestartTrend(ctx context.Context, timeRange TimeRange) (*RestartTrend, error) {
+// GetRestartTrend 获取 Pod 重启趋势，namespaces 非空时只统计这些命名空间
+func (s *Service) GetRestartTrend(ctx context.Context, timeRange TimeRange, namespaces []string) (*RestartTrend, error) {
 	if s.metrics == nil {
 		return nil, fmt.Errorf("metrics client not configured")
 	}
 
+	query := QueryPodRestarts
+	if len(namespaces) > 0 {
+		scoped, err := promql.Scope(query, metrics.NamespaceMatcher(namespaces))
+		if err != nil {
+			return nil, err
+		}
+		query = scoped
+	}
+
 	trend := &RestartTrend{}
 	end := time.Now()
 	duration := timeRange.Duration()
@@ -405,7 +490,7 @@ func (s *Service) GetRestartTrend(ctx context.Context, timeRange TimeRange) (*Re
 	step := timeRange.Step()
 
 	// 查询重启次数趋势
-	resp, err := s.metrics.QueryRange(QueryPodRestarts, start, end, step)
+	resp, err := s.metrics.WithContext(ctx).QueryRange(query, start, end, step)
 	if err != nil {
 		return nil, err
 	}
@@ -422,7 +507,7 @@ func (s *Service) GetRestartTrend(ctx context.Context, timeRange TimeRange) (*Re
 	// 计算周环比
 	prevStart := start.Add(-7 * 24 * time.Hour)
 	prevEnd := end.Add(-7 * 24 * time.Hour)
-	prevResp, err := s.metrics.QueryRange(QueryPodRestarts, prevStart, prevEnd, step)
+	prevResp, err := s.metrics.WithContext(ctx).QueryRange(query, prevStart, prevEnd, step)
 	if err == nil {
 		prevPoints := extractTimeSeriesPoints(prevResp)
 		for _, p := range prevPoints {
@@ -491,17 +576,18 @@ func extractTimeSeriesPoints(resp *metrics.QueryResponse) []TimeSeriesPoint {
 	}
 
 	for _, v := range resp.Data.Result[0].Values {
-		if len(v) >= 2 {
-			ts, _ := v[0].(float64)
-			var val float64
-			if strVal, ok := v[1].(string); ok {
-				fmt.Sscanf(strVal, "%f", &val)
-			}
-			points = append(points, TimeSeriesPoint{
-				Timestamp: int64(ts),
-				Value:     val,
-			})
+		if len(v) < 2 {
+			continue
 		}
+		ts, _ := v[0].(float64)
+		val, err := metrics.ParseSampleValue(v[1])
+		if err != nil {
+			continue
+		}
+		points = append(points, TimeSeriesPoint{
+			Timestamp: int64(ts),
+			Value:     val,
+		})
 	}
 
 	return points
@@ -0,0 +1,266 @@
+package observation
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	dbutil "github.com/k8s-dashboard/backend/internal/db"
+)
+
+// 默认异常判定阈值，未配置阈值规则时使用
+const (
+	DefaultPendingMinutes   = 5
+	DefaultRestartCount     = 5
+	DefaultOOMWindowMinutes = 60
+)
+
+// 阈值取值上限
+const (
+	maxPendingMinutes   = 7 * 24 * 60
+	maxRestartCount     = 10000
+	maxOOMWindowMinutes = 7 * 24 * 60
+)
+
+// 阈值名称，用于标注异常由哪个阈值触发
+const (
+	ThresholdPendingMinutes   = "pendingMinutes"
+	ThresholdRestartCount     = "restartCount"
+	ThresholdOOMWindowMinutes = "oomWindowMinutes"
+)
+
+// ErrThresholdRuleNotFound 阈值规则不存在
+var ErrThresholdRuleNotFound = errors.New("阈值规则不存在")
+
+// AnomalyThresholds 判定 Pod 异常的阈值
+type AnomalyThresholds struct {
+	PendingMinutes   int `json:"pendingMinutes"`   // Pending 超过该时长（分钟）视为异常
+	RestartCount     int `json:"restartCount"`     // 运行中的容器重启次数超过该值视为异常
+	OOMWindowMinutes int `json:"oomWindowMinutes"` // 该时间窗口（分钟）内发生过 OOMKilled 视为异常
+}
+
+// DefaultAnomalyThresholds 返回默认阈值
+func DefaultAnomalyThresholds() AnomalyThresholds {
+	return AnomalyThresholds{
+		PendingMinutes:   DefaultPendingMinutes,
+		RestartCount:     DefaultRestartCount,
+		OOMWindowMinutes: DefaultOOMWindowMinutes,
+	}
+}
+
+// ThresholdRule 持久化的阈值规则，Cluster、Namespace 为空表示匹配全部集群或命名空间
+type ThresholdRule struct {
+	ID        int64  `json:"id"`
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+	AnomalyThresholds
+	UpdatedBy string    `json:"updatedBy"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TriggeredThreshold 触发异常的阈值，RuleID 为 0 表示使用默认阈值
+type TriggeredThreshold struct {
+	Name   string `json:"name"`
+	Value  int    `json:"value"`
+	RuleID int64  `json:"ruleId,omitempty"`
+}
+
+// ThresholdValidationError 阈值规则校验失败
+type ThresholdValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ThresholdValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidateThresholdRule 校验并规范化阈值规则
+func ValidateThresholdRule(rule *ThresholdRule) error {
+	rule.Cluster = strings.TrimSpace(rule.Cluster)
+	rule.Namespace = strings.TrimSpace(rule.Namespace)
+	checks := []struct {
+		field string
+		value int
+		max   int
+	}{
+		{ThresholdPendingMinutes, rule.PendingMinutes, maxPendingMinutes},
+		{ThresholdRestartCount, rule.RestartCount, maxRestartCount},
+		{ThresholdOOMWindowMinutes, rule.OOMWindowMinutes, maxOOMWindowMinutes},
+	}
+	for _, check := range checks {
+		if check.value < 1 || check.value > check.max {
+			return &ThresholdValidationError{Field: check.field, Message: fmt.Sprintf("取值范围为 1-%d", check.max)}
+		}
+	}
+	return nil
+}
+
+// ThresholdSet 一组阈值规则，按集群与命名空间解析生效的阈值
+type ThresholdSet []ThresholdRule
+
+// For 返回集群与命名空间生效的阈值及对应的规则 ID（0 表示默认阈值）。
+// 优先级：集群+命名空间 > 全部集群+命名空间 > 集群 > 全部集群
+func (s ThresholdSet) For(cluster, namespace string) (AnomalyThresholds, int64) {
+	best, bestRank := -1, 0
+	for i, rule := range s {
+		if (rule.Cluster != "" && rule.Cluster != cluster) || (rule.Namespace != "" && rule.Namespace != namespace) {
+			continue
+		}
+		rank := 1
+		if rule.Namespace != "" {
+			rank += 2
+		}
+		if rule.Cluster != "" {
+			rank++
+		}
+		if rank > bestRank {
+			best, bestRank = i, rank
+		}
+	}
+	if best < 0 {
+		return DefaultAnomalyThresholds(), 0
+	}
+	return s[best].AnomalyThresholds, s[best].ID
+}
+
+// ThresholdRepository 异常阈值规则数据仓库
+type ThresholdRepository struct {
+	db      *sql.DB
+	dialect dbutil.Dialect
+}
+
+// NewThresholdRepository 创建异常阈值规则数据仓库
+func NewThresholdRepository(db *sql.DB, dialect dbutil.Dialect) (*ThresholdRepository, error) {
+	repo := &ThresholdRepository{
+		db:      db,
+		dialect: dialect,
+	}
+
+	if err := repo.initSchema(); err != nil {
+		return nil, fmt.Errorf("初始化表结构失败: %w", err)
+	}
+
+	return repo, nil
+}
+
+// initSchema 初始化表结构
+func (r *ThresholdRepository) initSchema() error {
+	var schema string
+	if r.dialect == dbutil.DialectSQLite {
+		schema = `
+		CREATE TABLE IF NOT EXISTS anomaly_thresholds (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			cluster TEXT NOT NULL DEFAULT '',
+			namespace TEXT NOT NULL DEFAULT '',
+			pending_minutes INTEGER NOT NULL,
+			restart_count INTEGER NOT NULL,
+			oom_window_minutes INTEGER NOT NULL,
+			updated_by TEXT NOT NULL DEFAULT '',
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(cluster, namespace)
+		);
+		`
+	} else {
+		schema = `
+		CREATE TABLE IF NOT EXISTS anomaly_thresholds (
+			id BIGSERIAL PRIMARY KEY,
+			cluster VARCHAR(100) NOT NULL DEFAULT '',
+			namespace VARCHAR(253) NOT NULL DEFAULT '',
+			pending_minutes INTEGER NOT NULL,
+			restart_count INTEGER NOT NULL,
+			oom_window_minutes INTEGER NOT NULL,
+			updated_by VARCHAR(100) NOT NULL DEFAULT '',
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(cluster, namespace)
+		);
+		`
+	}
+
+	_, err := r.db.Exec(schema)
+	return err
+}
+
+// List 列出全部阈值规则，按集群、命名空间排序
+func (r *ThresholdRepository) List() (ThresholdSet, error) {
+	rows, err := r.db.Query(`
+		SELECT id, cluster, namespace, pending_minutes, restart_count, oom_window_minutes, updated_by, updated_at
+		FROM anomaly_thresholds
+		ORDER BY cluster ASC, namespace ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := ThresholdSet{}
+	for rows.Next() {
+		rule, err := scanThresholdRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *rule)
+	}
+	return items, rows.Err()
+}
+
+// Save 校验并保存阈值规则，同一集群与命名空间已有规则时覆盖
+func (r *ThresholdRepository) Save(rule *ThresholdRule) (*ThresholdRule, error) {
+	if err := ValidateThresholdRule(rule); err != nil {
+		return nil, err
+	}
+
+	_, err := r.db.Exec(`
+		INSERT INTO anomaly_thresholds (cluster, namespace, pending_minutes, restart_count, oom_window_minutes, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (cluster, namespace) DO UPDATE SET
+			pending_minutes = excluded.pending_minutes,
+			restart_count = excluded.restart_count,
+			oom_window_minutes = excluded.oom_window_minutes,
+			updated_by = excluded.updated_by,
+			updated_at = excluded.updated_at
+	`, rule.Cluster, rule.Namespace, rule.PendingMinutes, rule.RestartCount, rule.OOMWindowMinutes, rule.UpdatedBy, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	row := r.db.QueryRow(`
+		SELECT id, cluster, namespace, pending_minutes, restart_count, oom_window_minutes, updated_by, updated_at
+		FROM anomaly_thresholds WHERE cluster = $1 AND namespace = $2
+	`, rule.Cluster, rule.Namespace)
+	return scanThresholdRule(row)
+}
+
+// Delete 删除阈值规则，删除后对应范围回落到更宽泛的规则或默认阈值
+func (r *ThresholdRepository) Delete(id int64) (*ThresholdRule, error) {
+	row := r.db.QueryRow(`
+		SELECT id, cluster, namespace, pending_minutes, restart_count, oom_window_minutes, updated_by, updated_at
+		FROM anomaly_thresholds WHERE id = $1
+	`, id)
+	rule, err := scanThresholdRule(row)
+	if err == sql.ErrNoRows {
+		return nil, ErrThresholdRuleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.db.Exec("DELETE FROM anomaly_thresholds WHERE id = $1", id); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+type thresholdScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanThresholdRule(row thresholdScanner) (*ThresholdRule, error) {
+	var rule ThresholdRule
+	if err := row.Scan(&rule.ID, &rule.Cluster, &rule.Namespace, &rule.PendingMinutes, &rule.RestartCount, &rule.OOMWindowMinutes, &rule.UpdatedBy, &rule.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
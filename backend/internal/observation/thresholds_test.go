@@ -0,0 +1,132 @@
+package observation
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	dbutil "github.com/k8s-dashboard/backend/internal/db"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestThresholdSetFor(t *testing.T) {
+	set := ThresholdSet{
+		{ID: 1, AnomalyThresholds: AnomalyThresholds{PendingMinutes: 10, RestartCount: 10, OOMWindowMinutes: 60}},
+		{ID: 2, Cluster: "prod", AnomalyThresholds: AnomalyThresholds{PendingMinutes: 3, RestartCount: 3, OOMWindowMinutes: 30}},
+		{ID: 3, Namespace: "batch", AnomalyThresholds: AnomalyThresholds{PendingMinutes: 60, RestartCount: 20, OOMWindowMinutes: 120}},
+		{ID: 4, Cluster: "prod", Namespace: "payments", AnomalyThresholds: AnomalyThresholds{PendingMinutes: 1, RestartCount: 1, OOMWindowMinutes: 1440}},
+	}
+	tests := []struct {
+		cluster, namespace string
+		ruleID             int64
+	}{
+		{"staging", "default", 1},
+		{"prod", "default", 2},
+		{"prod", "batch", 3},
+		{"prod", "payments", 4},
+		{"staging", "payments", 1},
+	}
+	for _, tt := range tests {
+		if _, id := set.For(tt.cluster, tt.namespace); id != tt.ruleID {
+			t.Errorf("For(%s, %s) rule = %d, want %d", tt.cluster, tt.namespace, id, tt.ruleID)
+		}
+	}
+
+	th, id := ThresholdSet(nil).For("prod", "default")
+	if id != 0 || th != DefaultAnomalyThresholds() {
+		t.Fatalf("expected defaults, got %+v (rule %d)", th, id)
+	}
+}
+
+func TestCheckPodAnomalyThresholds(t *testing.T) {
+	now := time.Now()
+	s := &Service{}
+	pending := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "payments", CreationTimestamp: metav1.NewTime(now.Add(-10 * time.Minute))},
+		Status:     corev1.PodStatus{Phase: corev1.PodPending},
+	}
+
+	if got := s.checkPodAnomaly(pending, now, AnomalyThresholds{PendingMinutes: 15, RestartCount: 5, OOMWindowMinutes: 60}, 0); got != nil {
+		t.Fatalf("expected pod within pending threshold to be healthy, got %+v", got)
+	}
+	got := s.checkPodAnomaly(pending, now, AnomalyThresholds{PendingMinutes: 5, RestartCount: 5, OOMWindowMinutes: 60}, 7)
+	if got == nil || got.Threshold == nil || got.Threshold.Name != ThresholdPendingMinutes || got.Threshold.Value != 5 || got.Threshold.RuleID != 7 {
+		t.Fatalf("expected pending anomaly with threshold, got %+v", got)
+	}
+
+	restarting := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "payments", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{{
+			RestartCount: 4,
+			State:        corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+		}}},
+	}
+	if got := s.checkPodAnomaly(restarting, now, DefaultAnomalyThresholds(), 0); got != nil {
+		t.Fatalf("expected 4 restarts to be below default threshold, got %+v", got)
+	}
+	got = s.checkPodAnomaly(restarting, now, AnomalyThresholds{PendingMinutes: 5, RestartCount: 3, OOMWindowMinutes: 60}, 0)
+	if got == nil || got.Reason != "HighRestartCount" || got.Threshold.Name != ThresholdRestartCount || got.Threshold.Value != 3 {
+		t.Fatalf("expected restart anomaly, got %+v", got)
+	}
+}
+
+func TestSQLiteThresholdRules(t *testing.T) {
+	conn, dialect, err := dbutil.Open(dbutil.Config{
+		SQLitePath:          filepath.Join(t.TempDir(), "thresholds.db"),
+		AllowSQLiteFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer conn.Close()
+
+	repo, err := NewThresholdRepository(conn, dialect)
+	if err != nil {
+		t.Fatalf("NewThresholdRepository failed: %v", err)
+	}
+
+	saved, err := repo.Save(&ThresholdRule{Cluster: " prod ", Namespace: "payments", AnomalyThresholds: AnomalyThresholds{PendingMinutes: 2, RestartCount: 3, OOMWindowMinutes: 30}, UpdatedBy: "admin"})
+	if err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if saved.ID <= 0 || saved.Cluster != "prod" || saved.UpdatedBy != "admin" {
+		t.Fatalf("unexpected saved rule: %+v", saved)
+	}
+
+	// 同一范围覆盖原规则
+	updated, err := repo.Save(&ThresholdRule{Cluster: "prod", Namespace: "payments", AnomalyThresholds: AnomalyThresholds{PendingMinutes: 4, RestartCount: 3, OOMWindowMinutes: 30}})
+	if err != nil {
+		t.Fatalf("Save (overwrite) failed: %v", err)
+	}
+	if updated.ID != saved.ID || updated.PendingMinutes != 4 {
+		t.Fatalf("expected rule %d to be overwritten, got %+v", saved.ID, updated)
+	}
+
+	var validationErr *ThresholdValidationError
+	if _, err := repo.Save(&ThresholdRule{AnomalyThresholds: AnomalyThresholds{PendingMinutes: 5, OOMWindowMinutes: 60}}); !errors.As(err, &validationErr) || validationErr.Field != ThresholdRestartCount {
+		t.Fatalf("expected restartCount validation error, got %v", err)
+	}
+
+	if _, err := repo.Save(&ThresholdRule{AnomalyThresholds: DefaultAnomalyThresholds()}); err != nil {
+		t.Fatalf("Save global rule failed: %v", err)
+	}
+	set, err := repo.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(set) != 2 || set[0].Cluster != "" {
+		t.Fatalf("unexpected rules: %+v", set)
+	}
+	if th, id := set.For("prod", "payments"); id != saved.ID || th.PendingMinutes != 4 {
+		t.Fatalf("expected payments rule, got %+v (rule %d)", th, id)
+	}
+
+	if _, err := repo.Delete(saved.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := repo.Delete(saved.ID); !errors.Is(err, ErrThresholdRuleNotFound) {
+		t.Fatalf("expected ErrThresholdRuleNotFound, got %v", err)
+	}
+}
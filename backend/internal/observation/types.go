@@ -19,6 +19,8 @@ type PodAnomaly struct {
 	RestartCount int    `json:"restartCount"`
 	Duration     string `json:"duration"`
 	NodeName     string `json:"nodeName,omitempty"`
+	// Threshold 触发异常的阈值（Pending 时长、重启次数、OOM 时间窗口），状态类异常为空
+	Threshold *TriggeredThreshold `json:"threshold,omitempty"`
 }
 
 // NodeAnomaly 节点异常
@@ -0,0 +1,267 @@
+// Package openapi 根据 gin 路由表与处理器的请求/响应结构体生成 OpenAPI 3 文档
+package openapi
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Version 生成文档遵循的 OpenAPI 规范版本
+const Version = "3.0.3"
+
+// Document OpenAPI 文档根对象
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Paths      map[string]PathItem   `json:"paths"`
+	Components Components            `json:"components"`
+	Security   []SecurityRequirement `json:"security,omitempty"`
+	Tags       []Tag                 `json:"tags,omitempty"`
+}
+
+// Info 文档基本信息
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// PathItem 同一路径下各 HTTP 方法（小写）对应的操作
+type PathItem map[string]*Operation
+
+// Operation 单个接口
+type Operation struct {
+	Tags        []string               `json:"tags,omitempty"`
+	Summary     string                 `json:"summary,omitempty"`
+	OperationID string                 `json:"operationId"`
+	Parameters  []Parameter            `json:"parameters,omitempty"`
+	RequestBody *RequestBody           `json:"requestBody,omitempty"`
+	Responses   map[string]*Response   `json:"responses"`
+	Deprecated  bool                   `json:"deprecated,omitempty"`
+	Security    *[]SecurityRequirement `json:"security,omitempty"`
+}
+
+// Parameter 路径或查询参数
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody 请求体
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response 响应
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType 某种内容类型的 schema
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components 可复用的 schema 与认证方式
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme 认证方式
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// SecurityRequirement 认证要求，键为 SecurityScheme 名称
+type SecurityRequirement map[string][]string
+
+// Tag 接口分组
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// Route 单个路由的补充说明。Request/Response 为请求体与成功响应的类型（传零值，列表用 List 包装），
+// 为空时只生成路径参数与通用错误响应
+type Route struct {
+	Summary  string
+	Query    []string
+	Request  interface{}
+	Response interface{}
+	// Status 成功响应的状态码，默认 200
+	Status int
+	// Public 不需要认证的接口
+	Public     bool
+	Deprecated bool
+}
+
+// Options 生成选项
+type Options struct {
+	Info Info
+	// Prefix 只收录该前缀下的路由，如 /api/v2
+	Prefix string
+	// Routes 以 "METHOD /path"（gin 路由格式）为键的补充说明
+	Routes map[string]Route
+	// Error 所有接口共用的错误响应类型
+	Error interface{}
+	// Deprecated 整个版本已弃用，所有接口标记为 deprecated
+	Deprecated bool
+}
+
+// bearerAuth 文档中认证方式的名称
+const bearerAuth = "bearerAuth"
+
+// Build 根据 gin 路由表生成 OpenAPI 文档，路由按路径与方法排序以保证输出稳定
+func Build(routes gin.RoutesInfo, opts Options) *Document {
+	reg := newRegistry()
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    opts.Info,
+		Paths:   make(map[string]PathItem),
+		Components: Components{
+			SecuritySchemes: map[string]SecurityScheme{
+				bearerAuth: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+		Security: []SecurityRequirement{{bearerAuth: {}}},
+	}
+
+	sorted := make(gin.RoutesInfo, 0, len(routes))
+	for _, route := range routes {
+		if strings.HasPrefix(route.Path, opts.Prefix) {
+			sorted = append(sorted, route)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Path != sorted[j].Path {
+			return sorted[i].Path < sorted[j].Path
+		}
+		return sorted[i].Method < sorted[j].Method
+	})
+
+	errorSchema := reg.schemaOf(opts.Error)
+	operationIDs := make(map[string]int)
+	tags := make(map[string]bool)
+	for _, route := range sorted {
+		info := opts.Routes[route.Method+" "+route.Path]
+		path, params := convertPath(route.Path)
+		tag := routeTag(strings.TrimPrefix(route.Path, opts.Prefix))
+		tags[tag] = true
+
+		op := &Operation{
+			Tags:        []string{tag},
+			Summary:     info.Summary,
+			OperationID: operationID(route, operationIDs),
+			Parameters:  params,
+			Responses:   make(map[string]*Response),
+			Deprecated:  info.Deprecated || opts.Deprecated,
+		}
+		for _, name := range info.Query {
+			op.Parameters = append(op.Parameters, Parameter{Name: name, In: "query", Schema: &Schema{Type: "string"}})
+		}
+		if info.Public {
+			// 空数组覆盖文档级的认证要求
+			op.Security = &[]SecurityRequirement{}
+		}
+		if body := reg.schemaOf(info.Request); body != nil {
+			op.RequestBody = &RequestBody{Required: true, Content: jsonContent(body)}
+		}
+
+		status := info.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		success := &Response{Description: http.StatusText(status)}
+		if resp := reg.schemaOf(info.Response); resp != nil {
+			success.Content = jsonContent(resp)
+		}
+		op.Responses[strconv.Itoa(status)] = success
+		if errorSchema != nil {
+			op.Responses["default"] = &Response{Description: "错误响应", Content: jsonContent(errorSchema)}
+		}
+
+		item := doc.Paths[path]
+		if item == nil {
+			item = make(PathItem)
+			doc.Paths[path] = item
+		}
+		item[strings.ToLower(route.Method)] = op
+	}
+
+	doc.Components.Schemas = reg.schemas
+	for name := range tags {
+		doc.Tags = append(doc.Tags, Tag{Name: name})
+	}
+	sort.Slice(doc.Tags, func(i, j int) bool { return doc.Tags[i].Name < doc.Tags[j].Name })
+	return doc
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+// convertPath 把 gin 的 :param 与 *param 转换为 OpenAPI 的 {param}，并生成路径参数
+func convertPath(ginPath string) (string, []Parameter) {
+	segments := strings.Split(ginPath, "/")
+	var params []Parameter
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			name := segment[1:]
+			segments[i] = "{" + name + "}"
+			params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// routeTag 以资源名分组：/namespaces/:ns/pods/... 归入 pods，其余按第一段路径
+func routeTag(p string) string {
+	var segments []string
+	for _, segment := range strings.Split(strings.Trim(p, "/"), "/") {
+		if segment != "" && !strings.HasPrefix(segment, ":") && !strings.HasPrefix(segment, "*") {
+			segments = append(segments, segment)
+		}
+	}
+	switch {
+	case len(segments) == 0:
+		return "default"
+	case segments[0] == "namespaces" && len(segments) > 1:
+		return segments[1]
+	}
+	return segments[0]
+}
+
+// operationID 取处理函数名（如 (*Handler).GetPod-fm 取 GetPod），匿名函数或重名时追加序号
+func operationID(route gin.RouteInfo, seen map[string]int) string {
+	name := route.Handler
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.TrimSuffix(name, "-fm")
+	if name == "" || !unicode.IsUpper(rune(name[0])) {
+		var b strings.Builder
+		b.WriteString(strings.ToLower(route.Method))
+		for _, word := range strings.FieldsFunc(route.Path, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+		name = b.String()
+	}
+	seen[name]++
+	if n := seen[name]; n > 1 {
+		return name + strconv.Itoa(n)
+	}
+	return name
+}
@@ -0,0 +1,120 @@
+package openapi
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type node struct {
+	Name     string  `json:"name"`
+	Children []*node `json:"children,omitempty"`
+	Parent   *node   `json:"-"`
+}
+
+type detail struct {
+	*corev1.Pod
+	CreatedAt time.Time `json:"createdAt"`
+	Count     *int      `json:"count"`
+	internal  string
+}
+
+func TestSchemaFollowsJSONEncoding(t *testing.T) {
+	reg := newRegistry()
+	ref := reg.schemaOf(detail{})
+	if ref.Ref != "#/components/schemas/openapi.detail" {
+		t.Fatalf("ref = %q", ref.Ref)
+	}
+	s := reg.schemas["openapi.detail"]
+	for _, name := range []string{"metadata", "spec", "status", "kind", "createdAt", "count"} {
+		if s.Properties[name] == nil {
+			t.Errorf("missing property %s (embedded fields should be flattened)", name)
+		}
+	}
+	if s.Properties["internal"] != nil || s.Properties["Pod"] != nil {
+		t.Errorf("unexpected properties %v", s.Properties)
+	}
+	if p := s.Properties["createdAt"]; p.Type != "string" || p.Format != "date-time" {
+		t.Errorf("createdAt = %+v", p)
+	}
+	if !s.Properties["count"].Nullable {
+		t.Errorf("pointer field should be nullable")
+	}
+
+	meta := reg.schemas["meta.v1.ObjectMeta"]
+	if meta == nil || meta.Properties["creationTimestamp"].Format != "date-time" {
+		t.Fatalf("metav1.Time should be a date-time string: %+v", meta)
+	}
+	if reg.schemaOf(metav1.Duration{}).Type != "string" {
+		t.Errorf("metav1.Duration should be a string")
+	}
+	requests := reg.schemas["core.v1.ResourceRequirements"].Properties["requests"]
+	if requests.AdditionalProperties == nil || requests.AdditionalProperties.Type != "string" {
+		t.Errorf("resource.Quantity should be a string: %+v", requests)
+	}
+	port := reg.schemas["core.v1.HTTPGetAction"].Properties["port"]
+	if len(port.AnyOf) != 2 {
+		t.Errorf("IntOrString should be integer or string: %+v", port)
+	}
+}
+
+func TestSchemaHandlesRecursiveTypes(t *testing.T) {
+	reg := newRegistry()
+	reg.schemaOf(node{})
+	s := reg.schemas["openapi.node"]
+	if s == nil {
+		t.Fatal("node not registered")
+	}
+	if got := s.Properties["children"].Items.Ref; got != "#/components/schemas/openapi.node" {
+		t.Errorf("children items = %q", got)
+	}
+	if s.Properties["Parent"] != nil {
+		t.Errorf(`json:"-" field should be skipped`)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	routes := gin.RoutesInfo{
+		{Method: http.MethodGet, Path: "/api/v1/namespaces/:ns/pods", Handler: "handlers.(*Handler).ListPods-fm"},
+		{Method: http.MethodPost, Path: "/api/v1/auth/login", Handler: "handlers.(*AuthHandler).Login-fm"},
+		{Method: http.MethodGet, Path: "/api/v1/namespace/:ns", Handler: "api.NewRouter.func1"},
+		{Method: http.MethodGet, Path: "/healthz", Handler: "handlers.(*HealthHandler).Healthz-fm"},
+	}
+	doc := Build(routes, Options{
+		Prefix: "/api/v1",
+		Routes: map[string]Route{
+			"GET /api/v1/namespaces/:ns/pods": {Response: List(corev1.Pod{})},
+			"POST /api/v1/auth/login":         {Public: true, Request: node{}, Status: http.StatusCreated},
+		},
+	})
+
+	if len(doc.Paths) != 3 {
+		t.Fatalf("paths = %v, want only /api/v1 routes", doc.Paths)
+	}
+	list := doc.Paths["/api/v1/namespaces/{ns}/pods"]["get"]
+	if list == nil || list.OperationID != "ListPods" || list.Tags[0] != "pods" {
+		t.Fatalf("list operation = %+v", list)
+	}
+	if len(list.Parameters) != 1 || list.Parameters[0].Name != "ns" || list.Parameters[0].In != "path" {
+		t.Errorf("parameters = %+v", list.Parameters)
+	}
+	items := list.Responses["200"].Content["application/json"].Schema.Properties["items"]
+	if items.Items.Ref != "#/components/schemas/core.v1.Pod" {
+		t.Errorf("items = %+v", items)
+	}
+
+	login := doc.Paths["/api/v1/auth/login"]["post"]
+	if login.Security == nil || len(*login.Security) != 0 {
+		t.Errorf("public route should clear security")
+	}
+	if login.RequestBody == nil || login.Responses["201"] == nil {
+		t.Errorf("login = %+v", login)
+	}
+	if id := doc.Paths["/api/v1/namespace/{ns}"]["get"].OperationID; id != "getApiV1NamespaceNs" {
+		t.Errorf("anonymous handler operationId = %q", id)
+	}
+}
@@ -0,0 +1,213 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Schema OpenAPI 3.0 Schema 对象，只包含生成器用到的字段
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+}
+
+// openAPISchemaType Kubernetes 中自定义序列化的类型（metav1.Time、resource.Quantity、intstr.IntOrString 等）
+// 通过该方法声明自身的 schema 类型
+type openAPISchemaType interface {
+	OpenAPISchemaType() []string
+	OpenAPISchemaFormat() string
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	schemaType    = reflect.TypeOf((*openAPISchemaType)(nil)).Elem()
+	// versionSegment 匹配 v1、v1beta1 等 API 版本目录
+	versionSegment = regexp.MustCompile(`^v\d+((alpha|beta)\d+)?$`)
+)
+
+// list 列表响应的占位类型，生成 {items, total, continue} 结构
+type list struct {
+	item reflect.Type
+}
+
+// List 返回列表响应的类型描述，item 为列表元素的零值，对应 handlers.ListResponse
+func List(item interface{}) interface{} {
+	return list{item: reflect.TypeOf(item)}
+}
+
+// registry 把 Go 类型转换为 schema，具名结构体登记到 components 中并以 $ref 引用，
+// 先登记后展开字段，因此自引用的类型不会无限递归
+type registry struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newRegistry() *registry {
+	return &registry{schemas: make(map[string]*Schema), names: make(map[reflect.Type]string)}
+}
+
+// schemaOf 返回值 v 对应的 schema，v 为 nil 时返回 nil
+func (r *registry) schemaOf(v interface{}) *Schema {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case list:
+		return &Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"items":    {Type: "array", Items: r.schema(v.item)},
+				"total":    {Type: "integer"},
+				"continue": {Type: "string"},
+			},
+		}
+	case *Schema:
+		return v
+	}
+	return r.schema(reflect.TypeOf(v))
+}
+
+func (r *registry) schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if s := customSchema(t); s != nil {
+		return s
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// []byte 按 base64 字符串序列化
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: r.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.structSchema(t)
+		}
+		return r.ref(t)
+	}
+	// interface{} 等无法静态确定的类型
+	return &Schema{}
+}
+
+// customSchema 处理自定义 JSON 序列化的类型：时间、声明了 OpenAPI 类型的 Kubernetes 类型，
+// 以及其它实现 json.Marshaler 的类型（结构未知，返回任意值）
+func customSchema(t reflect.Type) *Schema {
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+	ptr := reflect.PointerTo(t)
+	if t.Implements(schemaType) || ptr.Implements(schemaType) {
+		declared := reflect.New(t).Interface().(openAPISchemaType)
+		types := declared.OpenAPISchemaType()
+		if len(types) == 1 {
+			format := declared.OpenAPISchemaFormat()
+			if format == "int-or-string" {
+				return &Schema{AnyOf: []*Schema{{Type: "integer"}, {Type: "string"}}}
+			}
+			return &Schema{Type: types[0], Format: format}
+		}
+		return &Schema{}
+	}
+	if t.Kind() == reflect.Struct && (t.Implements(marshalerType) || ptr.Implements(marshalerType)) {
+		return &Schema{}
+	}
+	return nil
+}
+
+// ref 登记具名结构体并返回引用
+func (r *registry) ref(t reflect.Type) *Schema {
+	name, ok := r.names[t]
+	if !ok {
+		name = r.uniqueName(t)
+		r.names[t] = name
+		r.schemas[name] = &Schema{}
+		*r.schemas[name] = *r.structSchema(t)
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// uniqueName 以包名限定类型名，避免不同包的同名类型冲突；Kubernetes API 类型带上组与版本（如 core.v1.Pod）
+func (r *registry) uniqueName(t reflect.Type) string {
+	pkg := strings.TrimPrefix(t.PkgPath(), "k8s.io/api/")
+	prefix := path.Base(pkg)
+	if versionSegment.MatchString(prefix) {
+		prefix = path.Base(path.Dir(pkg)) + "." + prefix
+	}
+	base := prefix + "." + t.Name()
+	name := base
+	for i := 2; r.schemas[name] != nil; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	return name
+}
+
+// structSchema 按 encoding/json 的规则展开结构体字段：只导出字段、遵循 json 标签、内嵌结构体平铺
+func (r *registry) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for key, prop := range r.structSchema(embedded).Properties {
+					if _, ok := s.Properties[key]; !ok {
+						s.Properties[key] = prop
+					}
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		prop := r.schema(f.Type)
+		if opts == "string" {
+			prop = &Schema{Type: "string"}
+		}
+		if f.Type.Kind() == reflect.Pointer && prop.Ref == "" {
+			prop.Nullable = true
+		}
+		s.Properties[name] = prop
+	}
+	return s
+}
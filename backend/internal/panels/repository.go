@@ -0,0 +1,228 @@
+package panels
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	dbutil "github.com/k8s-dashboard/backend/internal/db"
+)
+
+// ErrPanelNotFound 面板不存在或不属于当前用户
+var ErrPanelNotFound = errors.New("面板不存在")
+
+// Threshold 面板阈值线
+type Threshold struct {
+	Value float64 `json:"value"`
+	Color string  `json:"color"` // green, yellow, red 等
+	Label string  `json:"label,omitempty"`
+}
+
+// Panel 用户自定义监控面板
+type Panel struct {
+	ID         int64       `json:"id"`
+	UserID     int64       `json:"userId"`
+	Name       string      `json:"name"`
+	Query      string      `json:"query"` // PromQL
+	Range      string      `json:"range"` // 1h, 6h, 24h, 7d
+	Step       string      `json:"step"`  // 30s, 1m, 5m ...
+	Unit       string      `json:"unit"`
+	Thresholds []Threshold `json:"thresholds"`
+	CreatedAt  time.Time   `json:"createdAt"`
+	UpdatedAt  time.Time   `json:"updatedAt"`
+}
+
+// Repository 面板数据仓库
+type Repository struct {
+	db      *sql.DB
+	dialect dbutil.Dialect
+}
+
+// NewRepository 创建面板数据仓库
+func NewRepository(db *sql.DB, dialect dbutil.Dialect) (*Repository, error) {
+	repo := &Repository{
+		db:      db,
+		dialect: dialect,
+	}
+
+	if err := repo.initSchema(); err != nil {
+		return nil, fmt.Errorf("初始化表结构失败: %w", err)
+	}
+
+	return repo, nil
+}
+
+// initSchema 初始化表结构
+func (r *Repository) initSchema() error {
+	var schema string
+	if r.dialect == dbutil.DialectSQLite {
+		schema = `
+		CREATE TABLE IF NOT EXISTS user_panels (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			name TEXT NOT NULL,
+			query TEXT NOT NULL,
+			time_range TEXT NOT NULL DEFAULT '1h',
+			step TEXT NOT NULL DEFAULT '1m',
+			unit TEXT,
+			thresholds TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(user_id, name)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_user_panels_user_id ON user_panels(user_id);
+		`
+	} else {
+		schema = `
+		CREATE TABLE IF NOT EXISTS user_panels (
+			id BIGSERIAL PRIMARY KEY,
+			user_id BIGINT NOT NULL,
+			name VARCHAR(200) NOT NULL,
+			query TEXT NOT NULL,
+			time_range VARCHAR(20) NOT NULL DEFAULT '1h',
+			step VARCHAR(20) NOT NULL DEFAULT '1m',
+			unit VARCHAR(50),
+			thresholds TEXT,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(user_id, name)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_user_panels_user_id ON user_panels(user_id);
+		`
+	}
+
+	_, err := r.db.Exec(schema)
+	return err
+}
+
+// Create 创建面板
+func (r *Repository) Create(p *Panel) error {
+	thresholds, err := json.Marshal(p.Thresholds)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	p.CreatedAt = now
+	p.UpdatedAt = now
+
+	if r.dialect == dbutil.DialectSQLite {
+		result, err := r.db.Exec(`
+			INSERT INTO user_panels (user_id, name, query, time_range, step, unit, thresholds, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`, p.UserID, p.Name, p.Query, p.Range, p.Step, p.Unit, string(thresholds), now, now)
+		if err != nil {
+			return err
+		}
+		p.ID, err = result.LastInsertId()
+		return err
+	}
+
+	return r.db.QueryRow(`
+		INSERT INTO user_panels (user_id, name, query, time_range, step, unit, thresholds, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`, p.UserID, p.Name, p.Query, p.Range, p.Step, p.Unit, string(thresholds), now, now).Scan(&p.ID)
+}
+
+// Update 更新面板（仅限所有者）
+func (r *Repository) Update(p *Panel) error {
+	thresholds, err := json.Marshal(p.Thresholds)
+	if err != nil {
+		return err
+	}
+
+	p.UpdatedAt = time.Now()
+	result, err := r.db.Exec(`
+		UPDATE user_panels SET
+			name = $1, query = $2, time_range = $3, step = $4, unit = $5, thresholds = $6, updated_at = $7
+		WHERE id = $8 AND user_id = $9
+	`, p.Name, p.Query, p.Range, p.Step, p.Unit, string(thresholds), p.UpdatedAt, p.ID, p.UserID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrPanelNotFound
+	}
+	return nil
+}
+
+// Delete 删除面板（仅限所有者）
+func (r *Repository) Delete(userID, id int64) error {
+	result, err := r.db.Exec("DELETE FROM user_panels WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrPanelNotFound
+	}
+	return nil
+}
+
+// Get 获取面板（仅限所有者）
+func (r *Repository) Get(userID, id int64) (*Panel, error) {
+	row := r.db.QueryRow(`
+		SELECT id, user_id, name, query, time_range, step, COALESCE(unit, ''), COALESCE(thresholds, ''), created_at, updated_at
+		FROM user_panels WHERE id = $1 AND user_id = $2
+	`, id, userID)
+
+	p, err := scanPanel(row)
+	if err == sql.ErrNoRows {
+		return nil, ErrPanelNotFound
+	}
+	return p, err
+}
+
+// List 列出用户的全部面板
+func (r *Repository) List(userID int64) ([]Panel, error) {
+	rows, err := r.db.Query(`
+		SELECT id, user_id, name, query, time_range, step, COALESCE(unit, ''), COALESCE(thresholds, ''), created_at, updated_at
+		FROM user_panels WHERE user_id = $1
+		ORDER BY id ASC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []Panel{}
+	for rows.Next() {
+		p, err := scanPanel(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *p)
+	}
+	return items, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPanel(row rowScanner) (*Panel, error) {
+	var p Panel
+	var thresholds string
+	if err := row.Scan(&p.ID, &p.UserID, &p.Name, &p.Query, &p.Range, &p.Step, &p.Unit, &thresholds, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	p.Thresholds = []Threshold{}
+	if thresholds != "" {
+		if err := json.Unmarshal([]byte(thresholds), &p.Thresholds); err != nil {
+			return nil, fmt.Errorf("解析阈值失败: %w", err)
+		}
+	}
+	return &p, nil
+}
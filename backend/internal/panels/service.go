@@ -0,0 +1,280 @@
+package panels
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/k8s-dashboard/backend/internal/metrics"
+	"github.com/k8s-dashboard/backend/internal/promql"
+)
+
+// 面板约束
+const (
+	maxPanelsPerUser = 50
+	maxQueryLength   = 2000
+	queryCacheTTL    = 30 * time.Second
+)
+
+// 允许的查询范围及对应时长
+var panelRanges = map[string]time.Duration{
+	"15m": 15 * time.Minute,
+	"1h":  time.Hour,
+	"6h":  6 * time.Hour,
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+}
+
+// ErrMetricsUnavailable 指标客户端未配置
+var ErrMetricsUnavailable = errors.New("metrics client not configured")
+
+// ValidationError 面板参数校验失败
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// PanelData 面板查询结果
+type PanelData struct {
+	Panel     *Panel                `json:"panel"`
+	Series    []metrics.QueryResult `json:"series"`
+	Cached    bool                  `json:"cached"`
+	QueriedAt time.Time             `json:"queriedAt"`
+	Start     int64                 `json:"start"`
+	End       int64                 `json:"end"`
+}
+
+type cacheEntry struct {
+	series    []metrics.QueryResult
+	queriedAt time.Time
+}
+
+// Service 面板服务
+type Service struct {
+	repo    *Repository
+	metrics *metrics.Client
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+}
+
+// NewService 创建面板服务
+func NewService(repo *Repository, metricsClient *metrics.Client) *Service {
+	return &Service{
+		repo:    repo,
+		metrics: metricsClient,
+		cache:   make(map[string]cacheEntry),
+	}
+}
+
+// Validate 校验并规范化面板定义
+func Validate(p *Panel) error {
+	p.Name = strings.TrimSpace(p.Name)
+	p.Query = strings.TrimSpace(p.Query)
+	p.Unit = strings.TrimSpace(p.Unit)
+
+	if p.Name == "" {
+		return &ValidationError{Field: "name", Message: "面板名称不能为空"}
+	}
+	if len(p.Name) > 200 {
+		return &ValidationError{Field: "name", Message: "面板名称过长"}
+	}
+	if p.Query == "" {
+		return &ValidationError{Field: "query", Message: "PromQL 不能为空"}
+	}
+	if len(p.Query) > maxQueryLength {
+		return &ValidationError{Field: "query", Message: fmt.Sprintf("PromQL 长度不能超过 %d", maxQueryLength)}
+	}
+	if err := checkBalanced(p.Query); err != nil {
+		return &ValidationError{Field: "query", Message: err.Error()}
+	}
+
+	if p.Range == "" {
+		p.Range = "1h"
+	}
+	rangeDuration, ok := panelRanges[p.Range]
+	if !ok {
+		return &ValidationError{Field: "range", Message: "仅支持 15m, 1h, 6h, 24h, 7d"}
+	}
+
+	if p.Step == "" {
+		p.Step = "1m"
+	}
+	step, err := time.ParseDuration(p.Step)
+	if err != nil || step < 15*time.Second {
+		return &ValidationError{Field: "step", Message: "step 必须是不小于 15s 的时长"}
+	}
+	// 限制单条序列最多约 11000 个点（与 Prometheus 默认上限一致）
+	if rangeDuration/step > 11000 {
+		return &ValidationError{Field: "step", Message: "step 过小，数据点数量超出上限"}
+	}
+
+	if p.Thresholds == nil {
+		p.Thresholds = []Threshold{}
+	}
+	return nil
+}
+
+// checkBalanced 对 PromQL 做括号配对的基本语法检查
+func checkBalanced(query string) error {
+	pairs := map[rune]rune{')': '(', ']': '[', '}': '{'}
+	var stack []rune
+	var quote rune
+	for _, ch := range query {
+		if quote != 0 {
+			if ch == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch ch {
+		case '"', '\'', '`':
+			quote = ch
+		case '(', '[', '{':
+			stack = append(stack, ch)
+		case ')', ']', '}':
+			if len(stack) == 0 || stack[len(stack)-1] != pairs[ch] {
+				return fmt.Errorf("括号不匹配: %q", ch)
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if quote != 0 {
+		return errors.New("引号未闭合")
+	}
+	if len(stack) > 0 {
+		return errors.New("括号未闭合")
+	}
+	return nil
+}
+
+// List 列出用户面板
+func (s *Service) List(userID int64) ([]Panel, error) {
+	return s.repo.List(userID)
+}
+
+// Get 获取用户面板
+func (s *Service) Get(userID, id int64) (*Panel, error) {
+	return s.repo.Get(userID, id)
+}
+
+// Create 创建面板
+func (s *Service) Create(userID int64, p *Panel) (*Panel, error) {
+	if err := Validate(p); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.List(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxPanelsPerUser {
+		return nil, &ValidationError{Field: "panels", Message: fmt.Sprintf("每个用户最多创建 %d 个面板", maxPanelsPerUser)}
+	}
+
+	p.UserID = userID
+	if err := s.repo.Create(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Update 更新面板
+func (s *Service) Update(userID, id int64, p *Panel) (*Panel, error) {
+	if err := Validate(p); err != nil {
+		return nil, err
+	}
+
+	p.ID = id
+	p.UserID = userID
+	if err := s.repo.Update(p); err != nil {
+		return nil, err
+	}
+	return s.repo.Get(userID, id)
+}
+
+// Delete 删除面板
+func (s *Service) Delete(userID, id int64) error {
+	return s.repo.Delete(userID, id)
+}
+
+// Query 执行面板查询，相同查询在 TTL 内复用缓存结果。namespaces 为 nil 时不限制，
+// 否则查询中的每个序列选择器都会追加命名空间匹配；空切片表示无可见命名空间，直接返回空结果
+func (s *Service) Query(userID, id int64, namespaces []string) (*PanelData, error) {
+	if s.metrics == nil {
+		return nil, ErrMetricsUnavailable
+	}
+
+	panel, err := s.repo.Get(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 对齐到 step，使缓存窗口内的请求得到一致的时间范围
+	step, _ := time.ParseDuration(panel.Step)
+	end := time.Now().Truncate(step)
+	start := end.Add(-panelRanges[panel.Range])
+
+	query := panel.Query
+	if namespaces != nil {
+		if len(namespaces) == 0 {
+			return &PanelData{
+				Panel:     panel,
+				Series:    []metrics.QueryResult{},
+				QueriedAt: time.Now(),
+				Start:     start.Unix(),
+				End:       end.Unix(),
+			}, nil
+		}
+		if query, err = promql.Scope(query, metrics.NamespaceMatcher(namespaces)); err != nil {
+			return nil, &ValidationError{Field: "query", Message: err.Error()}
+		}
+	}
+
+	// 缓存键使用限定后的查询，不同可见范围的用户不会共享结果
+	key := query + "|" + panel.Range + "|" + panel.Step
+	now := time.Now()
+
+	s.mu.Lock()
+	entry, ok := s.cache[key]
+	s.mu.Unlock()
+	if ok && now.Sub(entry.queriedAt) < queryCacheTTL {
+		return &PanelData{
+			Panel:     panel,
+			Series:    entry.series,
+			Cached:    true,
+			QueriedAt: entry.queriedAt,
+			Start:     start.Unix(),
+			End:       end.Unix(),
+		}, nil
+	}
+
+	resp, err := s.metrics.Raw().QueryRange(query, start, end, panel.Step)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.cache[key] = cacheEntry{series: resp.Data.Result, queriedAt: now}
+	// 顺带清理过期缓存，避免无限增长
+	for k, v := range s.cache {
+		if now.Sub(v.queriedAt) >= queryCacheTTL {
+			delete(s.cache, k)
+		}
+	}
+	s.mu.Unlock()
+
+	return &PanelData{
+		Panel:     panel,
+		Series:    resp.Data.Result,
+		QueriedAt: now,
+		Start:     start.Unix(),
+		End:       end.Unix(),
+	}, nil
+}
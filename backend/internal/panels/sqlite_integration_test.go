@@ -0,0 +1,165 @@
+package panels
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	dbutil "github.com/k8s-dashboard/backend/internal/db"
+	"github.com/k8s-dashboard/backend/internal/metrics"
+)
+
+func TestSQLitePanelLifecycle(t *testing.T) {
+	conn, dialect, err := dbutil.Open(dbutil.Config{
+		SQLitePath:          filepath.Join(t.TempDir(), "panels.db"),
+		AllowSQLiteFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer conn.Close()
+
+	repo, err := NewRepository(conn, dialect)
+	if err != nil {
+		t.Fatalf("NewRepository failed: %v", err)
+	}
+	service := NewService(repo, nil)
+
+	created, err := service.Create(1, &Panel{
+		Name:  "API latency",
+		Query: `histogram_quantile(0.99, sum by (le) (rate(http_request_duration_seconds_bucket{job="api"}[5m])))`,
+		Range: "6h",
+		Step:  "1m",
+		Unit:  "s",
+		Thresholds: []Threshold{
+			{Value: 0.5, Color: "yellow"},
+			{Value: 1, Color: "red"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if created.ID <= 0 {
+		t.Fatalf("expected panel id > 0")
+	}
+
+	if _, err := service.Get(2, created.ID); err != ErrPanelNotFound {
+		t.Fatalf("expected other user to get ErrPanelNotFound, got %v", err)
+	}
+
+	got, err := service.Get(1, created.ID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(got.Thresholds) != 2 || got.Thresholds[1].Color != "red" {
+		t.Fatalf("unexpected thresholds: %+v", got.Thresholds)
+	}
+
+	updated, err := service.Update(1, created.ID, &Panel{
+		Name:  "API latency p99",
+		Query: got.Query,
+		Range: "24h",
+		Step:  "5m",
+	})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if updated.Name != "API latency p99" || updated.Range != "24h" {
+		t.Fatalf("unexpected updated panel: %+v", updated)
+	}
+
+	if _, err := service.Query(1, created.ID, nil); err != ErrMetricsUnavailable {
+		t.Fatalf("expected ErrMetricsUnavailable without metrics client, got %v", err)
+	}
+
+	if err := service.Delete(2, created.ID); err != ErrPanelNotFound {
+		t.Fatalf("expected other user delete to fail, got %v", err)
+	}
+	if err := service.Delete(1, created.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	items, err := service.List(1)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(items) != 0 {
+		t.Fatalf("expected no panels after delete, got %d", len(items))
+	}
+}
+
+func TestSQLitePanelQueryScopesNamespaces(t *testing.T) {
+	var mu sync.Mutex
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/query_range") {
+			mu.Lock()
+			queries = append(queries, r.URL.Query().Get("query"))
+			mu.Unlock()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[]}}`))
+	}))
+	defer server.Close()
+
+	conn, dialect, err := dbutil.Open(dbutil.Config{
+		SQLitePath:          filepath.Join(t.TempDir(), "panels.db"),
+		AllowSQLiteFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer conn.Close()
+
+	repo, err := NewRepository(conn, dialect)
+	if err != nil {
+		t.Fatalf("NewRepository failed: %v", err)
+	}
+	service := NewService(repo, metrics.NewClient(server.URL))
+
+	created, err := service.Create(1, &Panel{Name: "restarts", Query: `sum(rate(kube_pod_container_status_restarts_total[5m]))`})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	if _, err := service.Query(1, created.ID, []string{"shop"}); err != nil {
+		t.Fatalf("scoped Query failed: %v", err)
+	}
+	if _, err := service.Query(1, created.ID, nil); err != nil {
+		t.Fatalf("unrestricted Query failed: %v", err)
+	}
+	want := []string{
+		`sum(rate(kube_pod_container_status_restarts_total{namespace=~"shop"}[5m]))`,
+		created.Query,
+	}
+	if len(queries) != len(want) || queries[0] != want[0] || queries[1] != want[1] {
+		t.Fatalf("queries = %q, want %q", queries, want)
+	}
+
+	data, err := service.Query(1, created.ID, []string{})
+	if err != nil {
+		t.Fatalf("Query without visible namespaces failed: %v", err)
+	}
+	if len(data.Series) != 0 || len(queries) != len(want) {
+		t.Fatalf("expected empty result without querying, got %d series after %d queries", len(data.Series), len(queries))
+	}
+}
+
+func TestValidateRejectsInvalidPanels(t *testing.T) {
+	cases := []Panel{
+		{Name: "", Query: "up"},
+		{Name: "broken", Query: `sum(rate(x[5m])`},
+		{Name: "quote", Query: `up{job="api}`},
+		{Name: "range", Query: "up", Range: "90d"},
+		{Name: "step", Query: "up", Range: "7d", Step: "1s"},
+	}
+	for _, p := range cases {
+		panel := p
+		if err := Validate(&panel); err == nil {
+			t.Fatalf("expected validation error for %+v", p)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package promql
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	labelNamePattern  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	metricNamePattern = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+)
+
+// Mapping 内置查询的指标与标签映射，使仪表盘在指标命名不同的环境中（只有 cAdvisor、
+// kube-state-metrics v2 改名、OpenCost 等）无需修改代码即可工作。只作用于内置查询，不影响用户自定义查询
+type Mapping struct {
+	// Metrics 序列选择器映射。键为内置查询中的指标名，可带标签匹配以只替换特定序列
+	// （如 kube_node_status_allocatable{resource="cpu"}）；值为替换后的选择器（如 machine_cpu_cores），
+	// 原选择器中未被键使用的标签匹配会保留
+	Metrics map[string]string `json:"metrics"`
+	// Labels 标签名映射（如 pod: pod_name），作用于标签匹配与 by、without、on、ignoring 等标签列表，
+	// 查询结果中的标签会改回原名。label_replace 等函数参数中的标签名不会改写
+	Labels map[string]string `json:"labels"`
+}
+
+// Empty 是否未配置任何映射
+func (m Mapping) Empty() bool {
+	return len(m.Metrics) == 0 && len(m.Labels) == 0
+}
+
+// Validate 校验映射
+func (m Mapping) Validate() error {
+	_, err := NewRewriter(m)
+	return err
+}
+
+// rewriteRule 一条选择器映射，from 的标签匹配全部命中时替换为 to
+type rewriteRule struct {
+	from *selector
+	to   *selector
+}
+
+// Rewriter 按 Mapping 改写查询
+type Rewriter struct {
+	rules   []rewriteRule
+	labels  map[string]string
+	reverse map[string]string
+}
+
+// NewRewriter 解析映射，键或值不是合法的选择器或标签名时返回错误
+func NewRewriter(m Mapping) (*Rewriter, error) {
+	r := &Rewriter{labels: map[string]string{}, reverse: map[string]string{}}
+	var errs []error
+	for from, to := range m.Metrics {
+		fromSel, err := parseSelector(from)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("METRICS_MAPPING 指标 %q: %w", from, err))
+			continue
+		}
+		toSel, err := parseSelector(to)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("METRICS_MAPPING 指标 %q 的替换 %q: %w", from, to, err))
+			continue
+		}
+		r.rules = append(r.rules, rewriteRule{from: fromSel, to: toSel})
+	}
+	for from, to := range m.Labels {
+		if !labelNamePattern.MatchString(from) || !labelNamePattern.MatchString(to) || from == "__name__" || to == "__name__" {
+			errs = append(errs, fmt.Errorf("METRICS_MAPPING 标签映射无效: %q -> %q", from, to))
+			continue
+		}
+		if prev, ok := r.reverse[to]; ok {
+			errs = append(errs, fmt.Errorf("METRICS_MAPPING 标签 %q 与 %q 映射到同一个标签 %q", prev, from, to))
+			continue
+		}
+		r.labels[from] = to
+		r.reverse[to] = from
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	// 标签匹配多的规则更具体，优先匹配
+	sort.Slice(r.rules, func(i, j int) bool {
+		if len(r.rules[i].from.matchers) != len(r.rules[j].from.matchers) {
+			return len(r.rules[i].from.matchers) > len(r.rules[j].from.matchers)
+		}
+		return r.rules[i].from.name < r.rules[j].from.name
+	})
+	return r, nil
+}
+
+// parseSelector 解析单个带指标名的序列选择器
+func parseSelector(s string) (*selector, error) {
+	s = strings.TrimSpace(s)
+	a, err := analyze(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(a.selectors) != 1 || a.selectors[0].start != 0 || a.selectors[0].end != len(s) || a.selectors[0].name == "" {
+		return nil, errors.New("必须是单个带指标名的序列选择器")
+	}
+	return &a.selectors[0], nil
+}
+
+// Rewrite 改写查询中的序列选择器与标签名
+func (r *Rewriter) Rewrite(query string) (string, error) {
+	if len(r.rules) == 0 && len(r.labels) == 0 {
+		return query, nil
+	}
+	a, err := analyze(query)
+	if err != nil {
+		return "", err
+	}
+
+	type edit struct {
+		start, end int
+		text       string
+	}
+	var edits []edit
+	for _, sel := range a.selectors {
+		if text, ok := r.rewriteSelector(sel); ok {
+			edits = append(edits, edit{sel.start, sel.end, text})
+		}
+	}
+	for _, span := range a.labels {
+		if to, ok := r.labels[query[span[0]:span[1]]]; ok {
+			edits = append(edits, edit{span[0], span[1], to})
+		}
+	}
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+
+	var b strings.Builder
+	last := 0
+	for _, e := range edits {
+		b.WriteString(query[last:e.start])
+		b.WriteString(e.text)
+		last = e.end
+	}
+	b.WriteString(query[last:])
+	return b.String(), nil
+}
+
+// rewriteSelector 返回改写后的选择器，不需要改写时返回 false
+func (r *Rewriter) rewriteSelector(sel selector) (string, bool) {
+	name := sel.name
+	var matchers []matcher
+	changed := false
+
+	rest := sel.matchers
+	for _, rule := range r.rules {
+		if remaining, ok := matchRule(sel, rule.from); ok {
+			name = rule.to.name
+			matchers = append(matchers, rule.to.matchers...)
+			rest = remaining
+			changed = true
+			break
+		}
+	}
+	for _, m := range rest {
+		if to, ok := r.labels[m.label]; ok {
+			m.label = to
+			changed = true
+		}
+		matchers = append(matchers, m)
+	}
+	if !changed {
+		return "", false
+	}
+
+	var b strings.Builder
+	parts := make([]string, 0, len(matchers)+1)
+	if metricNamePattern.MatchString(name) {
+		b.WriteString(name)
+	} else if name != "" {
+		parts = append(parts, strconv.Quote(name))
+	}
+	for _, m := range matchers {
+		label := m.label
+		if !labelNamePattern.MatchString(label) {
+			label = strconv.Quote(label)
+		}
+		parts = append(parts, label+m.op+m.raw)
+	}
+	if len(parts) > 0 || name == "" {
+		b.WriteString("{" + strings.Join(parts, ",") + "}")
+	}
+	return b.String(), true
+}
+
+// matchRule 判断选择器是否命中规则（指标名相同且包含规则的全部标签匹配），返回未被规则使用的标签匹配
+func matchRule(sel selector, from *selector) ([]matcher, bool) {
+	if sel.name != from.name {
+		return nil, false
+	}
+	used := make([]bool, len(sel.matchers))
+	for _, want := range from.matchers {
+		found := false
+		for i, m := range sel.matchers {
+			if !used[i] && m.label == want.label && m.op == want.op && m.value == want.value {
+				used[i], found = true, true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	var rest []matcher
+	for i, m := range sel.matchers {
+		if !used[i] {
+			rest = append(rest, m)
+		}
+	}
+	return rest, true
+}
+
+// RestoreLabels 将查询结果中被映射的标签改回内置查询使用的原名
+func (r *Rewriter) RestoreLabels(labels map[string]string) {
+	for to, from := range r.reverse {
+		if value, ok := labels[to]; ok {
+			delete(labels, to)
+			labels[from] = value
+		}
+	}
+}
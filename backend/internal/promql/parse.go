@@ -0,0 +1,475 @@
+package promql
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// analysis 对查询做词法级分析得到的信息。这里不构建完整语法树，只识别准入检查需要的部分：
+// 指标名、区间选择器与子查询的时长、offset 与 @ 修饰符，以及每个序列选择器的位置（用于注入命名空间匹配）
+type analysis struct {
+	metrics []string
+	// nameRegex 使用了非等值的 __name__ 匹配（如 {__name__=~"node_.*"}），无法确定涉及哪些指标
+	nameRegex bool
+	// ranges 区间选择器与子查询的时长，steps 子查询的步长
+	ranges []time.Duration
+	steps  []time.Duration
+	// offsets offset 修饰符的时长（取绝对值），timestamps @ 修饰符指定的 Unix 时间
+	offsets    []time.Duration
+	timestamps []float64
+	selectors  []selector
+	// labels by (...) 等标签列表中标签名的位置 [start, end)
+	labels [][2]int
+}
+
+// selector 一个序列选择器：bare 为不带花括号的指标名，pos 为指标名末尾；否则 pos 为右花括号的位置。
+// start、end 为整个选择器（含指标名）在查询中的范围，name 为花括号前或花括号内带引号的指标名
+type selector struct {
+	pos      int
+	bare     bool
+	empty    bool
+	start    int
+	end      int
+	name     string
+	matchers []matcher
+}
+
+// matcher 选择器中的一个标签匹配，raw 为值在查询中的原始写法（含引号）
+type matcher struct {
+	label string
+	op    string
+	value string
+	raw   string
+}
+
+// labelListKeywords 后面可跟括号标签列表的关键字，列表中的标识符是标签名而不是指标名
+var labelListKeywords = map[string]bool{
+	"by": true, "without": true, "on": true, "ignoring": true, "group_left": true, "group_right": true,
+}
+
+// keywords 不是指标名的其它关键字（含 MetricsQL 扩展）
+var keywords = map[string]bool{
+	"and": true, "or": true, "unless": true, "bool": true, "atan2": true, "inf": true, "nan": true,
+	"default": true, "if": true, "ifnot": true, "limit": true, "keep_metric_names": true,
+}
+
+var durationPattern = regexp.MustCompile(`^(?:([0-9]+)y)?(?:([0-9]+)w)?(?:([0-9]+)d)?(?:([0-9]+)h)?(?:([0-9]+)m)?(?:([0-9]+)s)?(?:([0-9]+)ms)?$`)
+
+var durationUnits = []time.Duration{
+	365 * 24 * time.Hour, 7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second, time.Millisecond,
+}
+
+// parseDuration 解析 PromQL 时长（如 5m、1h30m、7d），纯数字按秒处理
+func parseDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, errors.New("时长不能为空")
+	}
+	if seconds, err := strconv.ParseFloat(s, 64); err == nil {
+		if seconds < 0 {
+			return 0, fmt.Errorf("时长不能为负数: %q", s)
+		}
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+	match := durationPattern.FindStringSubmatch(s)
+	if match == nil {
+		return 0, fmt.Errorf("无法识别的时长: %q", s)
+	}
+	var total time.Duration
+	for i, unit := range durationUnits {
+		if match[i+1] == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(match[i+1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("无法识别的时长: %q", s)
+		}
+		total += time.Duration(n) * unit
+	}
+	return total, nil
+}
+
+func isSpace(ch byte) bool {
+	return ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r'
+}
+
+func isIdentStart(ch byte) bool {
+	return ch == '_' || ch == ':' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
+
+func isIdentChar(ch byte) bool {
+	return isIdentStart(ch) || (ch >= '0' && ch <= '9')
+}
+
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+// skipSpace 跳过空白与 # 注释
+func skipSpace(query string, i int) int {
+	for i < len(query) {
+		switch {
+		case isSpace(query[i]):
+			i++
+		case query[i] == '#':
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// scanIdent 返回从 i 开始的标识符的结束位置
+func scanIdent(query string, i int) int {
+	for i < len(query) && isIdentChar(query[i]) {
+		i++
+	}
+	return i
+}
+
+// scanString 解析从 i 开始的字符串字面量，返回其值与结束位置（右引号之后）
+func scanString(query string, i int) (string, int, error) {
+	quote := query[i]
+	for j := i + 1; j < len(query); j++ {
+		switch query[j] {
+		case '\\':
+			if quote != '`' {
+				j++
+			}
+		case quote:
+			raw := query[i : j+1]
+			if quote == '\'' {
+				// strconv.Unquote 不支持单引号字符串，转换为双引号形式
+				raw = `"` + strings.ReplaceAll(strings.ReplaceAll(raw[1:len(raw)-1], `\'`, `'`), `"`, `\"`) + `"`
+			}
+			value, err := strconv.Unquote(raw)
+			if err != nil {
+				return "", 0, fmt.Errorf("字符串无效: %s", query[i:j+1])
+			}
+			return value, j + 1, nil
+		}
+	}
+	return "", 0, errors.New("引号未闭合")
+}
+
+// scanNumber 返回从 i 开始的数字或时长字面量的结束位置（含 1e-3 形式的指数）
+func scanNumber(query string, i int) int {
+	for i < len(query) {
+		ch := query[i]
+		switch {
+		case isIdentChar(ch) && ch != ':', ch == '.':
+			i++
+		case (ch == '+' || ch == '-') && (query[i-1] == 'e' || query[i-1] == 'E') && !strings.HasPrefix(strings.ToLower(query[:i]), "0x"):
+			i++
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// analyze 分析查询，语法明显错误（括号或引号不配对、无法识别的时长等）时返回错误
+func analyze(query string) (*analysis, error) {
+	a := &analysis{}
+	depth := 0
+	for i := skipSpace(query, 0); i < len(query); i = skipSpace(query, i) {
+		ch := query[i]
+		switch {
+		case ch == '"' || ch == '\'' || ch == '`':
+			_, end, err := scanString(query, i)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+
+		case ch == '{':
+			end, err := a.scanMatchers(query, i, i, "")
+			if err != nil {
+				return nil, err
+			}
+			i = end
+
+		case ch == '[':
+			end, err := a.scanRange(query, i)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+
+		case ch == '(':
+			depth++
+			i++
+
+		case ch == ')':
+			depth--
+			if depth < 0 {
+				return nil, errors.New("括号不匹配: ')'")
+			}
+			i++
+
+		case ch == '}' || ch == ']':
+			return nil, fmt.Errorf("括号不匹配: %q", ch)
+
+		case ch == '@':
+			end, err := a.scanAt(query, i+1)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+
+		case isDigit(ch) || (ch == '.' && i+1 < len(query) && isDigit(query[i+1])):
+			i = scanNumber(query, i)
+
+		case isIdentStart(ch):
+			end := scanIdent(query, i)
+			next, err := a.scanIdentifier(query, i, end)
+			if err != nil {
+				return nil, err
+			}
+			i = next
+
+		default:
+			i++
+		}
+	}
+	if depth != 0 {
+		return nil, errors.New("括号未闭合")
+	}
+	return a, nil
+}
+
+// scanIdentifier 处理 query[start:end] 处的标识符：关键字、函数名或指标名，返回继续扫描的位置
+func (a *analysis) scanIdentifier(query string, start, end int) (int, error) {
+	ident := query[start:end]
+	lower := strings.ToLower(ident)
+	next := skipSpace(query, end)
+
+	if labelListKeywords[lower] {
+		if next < len(query) && query[next] == '(' {
+			return a.scanLabelList(query, next)
+		}
+		return end, nil
+	}
+	if lower == "offset" {
+		return a.scanOffset(query, next)
+	}
+	if keywords[lower] {
+		return end, nil
+	}
+	// 函数与聚合操作符，聚合操作符后可以先写 by/without 子句：sum by (pod) (...)
+	if next < len(query) && query[next] == '(' {
+		return end, nil
+	}
+	if clause := strings.ToLower(query[next:scanIdent(query, next)]); clause == "by" || clause == "without" {
+		return end, nil
+	}
+
+	a.metrics = append(a.metrics, ident)
+	if next < len(query) && query[next] == '{' {
+		return a.scanMatchers(query, next, start, ident)
+	}
+	a.selectors = append(a.selectors, selector{pos: end, bare: true, start: start, end: end, name: ident})
+	return end, nil
+}
+
+// scanLabelList 解析 by (...) 等标签列表，记录其中标签名的位置
+func (a *analysis) scanLabelList(query string, i int) (int, error) {
+	for j := i + 1; j < len(query); j++ {
+		switch ch := query[j]; {
+		case ch == '"' || ch == '\'' || ch == '`':
+			_, end, err := scanString(query, j)
+			if err != nil {
+				return 0, err
+			}
+			j = end - 1
+		case isIdentStart(ch):
+			end := scanIdent(query, j)
+			a.labels = append(a.labels, [2]int{j, end})
+			j = end - 1
+		case ch == ')':
+			return j + 1, nil
+		case ch == '(' || ch == '[' || ch == '{':
+			return 0, errors.New("标签列表中不能包含括号")
+		}
+	}
+	return 0, errors.New("括号未闭合")
+}
+
+// scanMatchers 解析 {label op "value", ...}，返回右花括号之后的位置。
+// start 为左花括号的位置，nameStart、name 为花括号前的指标名（没有时 nameStart 与 start 相同）
+func (a *analysis) scanMatchers(query string, start, nameStart int, name string) (int, error) {
+	sel := selector{start: nameStart, name: name, empty: true}
+	i := skipSpace(query, start+1)
+	for {
+		if i >= len(query) {
+			return 0, errors.New("括号未闭合")
+		}
+		if query[i] == '}' {
+			sel.pos, sel.end = i, i+1
+			a.selectors = append(a.selectors, sel)
+			return i + 1, nil
+		}
+		sel.empty = false
+
+		// 标签名，Prometheus 3 起也可以是带引号的指标名（{"metric.name"}）或标签名
+		var label string
+		quoted := false
+		switch {
+		case query[i] == '"' || query[i] == '\'' || query[i] == '`':
+			value, end, err := scanString(query, i)
+			if err != nil {
+				return 0, err
+			}
+			label, i, quoted = value, end, true
+		case isIdentStart(query[i]):
+			end := scanIdent(query, i)
+			label, i = query[i:end], end
+		default:
+			return 0, fmt.Errorf("无效的标签匹配: %q", query[start:min(len(query), i+1)])
+		}
+		i = skipSpace(query, i)
+
+		if quoted && i < len(query) && (query[i] == ',' || query[i] == '}') {
+			a.metrics = append(a.metrics, label)
+			sel.name = label
+		} else {
+			op := ""
+			for _, candidate := range []string{"=~", "!~", "!=", "="} {
+				if strings.HasPrefix(query[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return 0, fmt.Errorf("标签 %s 缺少匹配操作符", label)
+			}
+			i = skipSpace(query, i+len(op))
+			if i >= len(query) || (query[i] != '"' && query[i] != '\'' && query[i] != '`') {
+				return 0, fmt.Errorf("标签 %s 的值必须是字符串", label)
+			}
+			value, end, err := scanString(query, i)
+			if err != nil {
+				return 0, err
+			}
+			sel.matchers = append(sel.matchers, matcher{label: label, op: op, value: value, raw: query[i:end]})
+			i = end
+			if label == "__name__" {
+				if op == "=" {
+					a.metrics = append(a.metrics, value)
+				} else {
+					a.nameRegex = true
+				}
+			}
+		}
+
+		i = skipSpace(query, i)
+		if i < len(query) && query[i] == ',' {
+			i = skipSpace(query, i+1)
+		}
+	}
+}
+
+// scanRange 解析区间选择器 [5m] 或子查询 [1h:1m]，返回右方括号之后的位置
+func (a *analysis) scanRange(query string, start int) (int, error) {
+	end := strings.IndexByte(query[start:], ']')
+	if end < 0 {
+		return 0, errors.New("括号未闭合")
+	}
+	end += start
+	content := strings.TrimSpace(query[start+1 : end])
+	rangePart, stepPart, subquery := strings.Cut(content, ":")
+	rangeDuration, err := parseDuration(strings.TrimSpace(rangePart))
+	if err != nil {
+		return 0, err
+	}
+	a.ranges = append(a.ranges, rangeDuration)
+	if subquery {
+		if stepPart = strings.TrimSpace(stepPart); stepPart != "" {
+			step, err := parseDuration(stepPart)
+			if err != nil {
+				return 0, err
+			}
+			a.steps = append(a.steps, step)
+		}
+	}
+	return end + 1, nil
+}
+
+// scanOffset 解析 offset 修饰符的时长（允许负数）
+func (a *analysis) scanOffset(query string, i int) (int, error) {
+	if i < len(query) && (query[i] == '-' || query[i] == '+') {
+		i = skipSpace(query, i+1)
+	}
+	end := scanNumber(query, i)
+	offset, err := parseDuration(query[i:end])
+	if err != nil {
+		return 0, fmt.Errorf("offset %w", err)
+	}
+	a.offsets = append(a.offsets, offset)
+	return end, nil
+}
+
+// scanAt 解析 @ 修饰符，start()/end() 交给主循环按函数处理
+func (a *analysis) scanAt(query string, i int) (int, error) {
+	i = skipSpace(query, i)
+	if i < len(query) && isIdentStart(query[i]) {
+		return i, nil
+	}
+	end := scanNumber(query, i)
+	ts, err := strconv.ParseFloat(query[i:end], 64)
+	if err != nil {
+		return 0, fmt.Errorf("@ 修饰符的时间无效: %q", query[i:end])
+	}
+	a.timestamps = append(a.timestamps, ts)
+	return end, nil
+}
+
+// Scope 为查询中的每个序列选择器追加命名空间匹配，使结果只包含指定命名空间的序列；
+// 不带花括号的指标名同样会被限制
+func Scope(query, matcher string) (string, error) {
+	a, err := analyze(query)
+	if err != nil {
+		return "", err
+	}
+	selectors := append([]selector(nil), a.selectors...)
+	sort.Slice(selectors, func(i, j int) bool { return selectors[i].pos < selectors[j].pos })
+
+	var b strings.Builder
+	last := 0
+	for _, sel := range selectors {
+		b.WriteString(query[last:sel.pos])
+		switch {
+		case sel.bare:
+			b.WriteString("{" + matcher + "}")
+		case sel.empty || strings.HasSuffix(strings.TrimSpace(query[:sel.pos]), ","):
+			b.WriteString(matcher)
+		default:
+			b.WriteString("," + matcher)
+		}
+		last = sel.pos
+	}
+	b.WriteString(query[last:])
+	return b.String(), nil
+}
+
+// Validate 检查查询的基本语法（括号与引号配对、时长格式等），不做指标与时间范围限制，
+// 用于校验告警规则、记录规则中的表达式
+func Validate(query string) error {
+	if strings.TrimSpace(query) == "" {
+		return errors.New("表达式不能为空")
+	}
+	_, err := analyze(query)
+	return err
+}
+
+// ParseDuration 解析 PromQL 时长（如 5m、1h30m），用于校验规则的 for 与 interval
+func ParseDuration(s string) (time.Duration, error) {
+	return parseDuration(s)
+}
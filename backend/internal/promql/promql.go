@@ -0,0 +1,199 @@
+// Package promql 基于词法分析的 PromQL 处理：用户自定义查询的准入检查（指标名允许/拒绝列表、时间范围与步长限制）、
+// 为受限用户注入命名空间匹配，以及按配置改写内置查询的指标名与标签名
+package promql
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"time"
+)
+
+// maxQueryLength 单条查询的最大长度
+const maxQueryLength = 4000
+
+// ErrMetricDenied 查询引用了未被允许的指标
+var ErrMetricDenied = errors.New("不允许查询该指标")
+
+// Config 自定义查询的限制
+type Config struct {
+	// Allow 允许查询的指标名模式（支持 * 通配），为空表示允许所有未被拒绝的指标
+	Allow []string `json:"allow"`
+	// Deny 拒绝查询的指标名模式，优先于 Allow
+	Deny []string `json:"deny"`
+	// MaxRange 范围查询的最大跨度，同时限制查询中区间选择器与子查询的时长（如 24h、7d、2w）
+	MaxRange string `json:"maxRange"`
+	// MaxLookback 查询时间（含 offset、@ 修饰符）最早可以回溯到多久以前
+	MaxLookback string `json:"maxLookback"`
+	// MinStep 范围查询与子查询的最小步长（Go 时长，如 15s）
+	MinStep string `json:"minStep"`
+	// MaxPoints 范围查询每条序列的最大数据点数
+	MaxPoints int `json:"maxPoints"`
+}
+
+// DefaultConfig 默认不限制指标，范围查询最长 7 天、最多回溯 30 天，步长不小于 15s，每条序列最多 11000 个点（与 Prometheus 一致）
+func DefaultConfig() Config {
+	return Config{
+		MaxRange:    "7d",
+		MaxLookback: "30d",
+		MinStep:     "15s",
+		MaxPoints:   11000,
+	}
+}
+
+// Validate 校验查询限制
+func (cfg Config) Validate() error {
+	var errs []error
+	if _, err := parseLimit(cfg.MaxRange); err != nil {
+		errs = append(errs, fmt.Errorf("METRICS_QUERY_MAX_RANGE %w", err))
+	}
+	if _, err := parseLimit(cfg.MaxLookback); err != nil {
+		errs = append(errs, fmt.Errorf("METRICS_QUERY_MAX_LOOKBACK %w", err))
+	}
+	if step, err := time.ParseDuration(cfg.MinStep); err != nil || step <= 0 {
+		errs = append(errs, fmt.Errorf("METRICS_QUERY_MIN_STEP 无效: %q", cfg.MinStep))
+	}
+	if cfg.MaxPoints < 1 {
+		errs = append(errs, fmt.Errorf("METRICS_QUERY_MAX_POINTS 必须大于 0: %d", cfg.MaxPoints))
+	}
+	for _, pattern := range append(append([]string(nil), cfg.Allow...), cfg.Deny...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			errs = append(errs, fmt.Errorf("指标名模式无效: %q", pattern))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Policy 按配置检查自定义查询
+type Policy struct {
+	cfg         Config
+	maxRange    time.Duration
+	maxLookback time.Duration
+	minStep     time.Duration
+}
+
+// NewPolicy 创建查询准入策略
+func NewPolicy(cfg Config) (*Policy, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	maxRange, _ := parseLimit(cfg.MaxRange)
+	maxLookback, _ := parseLimit(cfg.MaxLookback)
+	minStep, _ := time.ParseDuration(cfg.MinStep)
+	return &Policy{cfg: cfg, maxRange: maxRange, maxLookback: maxLookback, minStep: minStep}, nil
+}
+
+// CheckQuery 检查查询语句：引用的指标需通过允许/拒绝列表（不满足时返回 ErrMetricDenied），
+// 区间选择器、子查询、offset 与 @ 修饰符不能超出时间限制
+func (p *Policy) CheckQuery(query string, now time.Time) error {
+	if query == "" {
+		return errors.New("query 不能为空")
+	}
+	if len(query) > maxQueryLength {
+		return fmt.Errorf("query 长度不能超过 %d", maxQueryLength)
+	}
+	a, err := analyze(query)
+	if err != nil {
+		return err
+	}
+
+	if a.nameRegex && (len(p.cfg.Allow) > 0 || len(p.cfg.Deny) > 0) {
+		return fmt.Errorf("%w: 启用指标允许/拒绝列表时不支持按 __name__ 正则或不等匹配", ErrMetricDenied)
+	}
+	for _, name := range a.metrics {
+		if !p.MetricAllowed(name) {
+			return fmt.Errorf("%w: %s", ErrMetricDenied, name)
+		}
+	}
+
+	for _, d := range a.ranges {
+		if d > p.maxRange {
+			return fmt.Errorf("区间选择器或子查询的时长不能超过 %s", p.cfg.MaxRange)
+		}
+	}
+	for _, step := range a.steps {
+		if step < p.minStep {
+			return fmt.Errorf("子查询步长不能小于 %s", p.cfg.MinStep)
+		}
+	}
+	for _, offset := range a.offsets {
+		if offset > p.maxLookback {
+			return fmt.Errorf("offset 不能超过 %s", p.cfg.MaxLookback)
+		}
+	}
+	for _, ts := range a.timestamps {
+		if err := p.checkTime(time.Unix(0, int64(ts*float64(time.Second))), now); err != nil {
+			return fmt.Errorf("@ 修饰符%w", err)
+		}
+	}
+	return nil
+}
+
+// MetricAllowed 指标名是否允许查询
+func (p *Policy) MetricAllowed(name string) bool {
+	for _, pattern := range p.cfg.Deny {
+		if ok, _ := path.Match(pattern, name); ok {
+			return false
+		}
+	}
+	if len(p.cfg.Allow) == 0 {
+		return true
+	}
+	for _, pattern := range p.cfg.Allow {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckInstant 检查即时查询的时间点
+func (p *Policy) CheckInstant(ts, now time.Time) error {
+	return p.checkTime(ts, now)
+}
+
+// CheckRange 检查范围查询的时间范围与步长
+func (p *Policy) CheckRange(start, end time.Time, step time.Duration, now time.Time) error {
+	if !end.After(start) {
+		return errors.New("end 必须晚于 start")
+	}
+	if end.Sub(start) > p.maxRange {
+		return fmt.Errorf("查询范围不能超过 %s", p.cfg.MaxRange)
+	}
+	if err := p.checkTime(start, now); err != nil {
+		return fmt.Errorf("start %w", err)
+	}
+	if err := p.checkTime(end, now); err != nil {
+		return fmt.Errorf("end %w", err)
+	}
+	if step < p.minStep {
+		return fmt.Errorf("step 不能小于 %s", p.cfg.MinStep)
+	}
+	if int(end.Sub(start)/step) > p.cfg.MaxPoints {
+		return fmt.Errorf("step 过小，数据点数量超出上限 %d", p.cfg.MaxPoints)
+	}
+	return nil
+}
+
+// checkTime 查询时间不能早于最大回溯时间，也不能晚于当前时间太多（允许 5 分钟时钟偏差）
+func (p *Policy) checkTime(ts, now time.Time) error {
+	if ts.Before(now.Add(-p.maxLookback)) {
+		return fmt.Errorf("不能早于 %s 以前", p.cfg.MaxLookback)
+	}
+	if ts.After(now.Add(5 * time.Minute)) {
+		return errors.New("不能晚于当前时间")
+	}
+	return nil
+}
+
+// parseLimit 解析时长上限（PromQL 时长，如 24h、7d、2w），必须大于 0
+func parseLimit(s string) (time.Duration, error) {
+	d, err := parseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("必须大于 0: %q", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,220 @@
+package promql
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeMetrics(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{`up`, []string{"up"}},
+		{`sum by (namespace, pod) (rate(container_cpu_usage_seconds_total{container!=""}[5m]))`, []string{"container_cpu_usage_seconds_total"}},
+		{`sum(rate(http_requests_total[5m])) without (instance) / on (job) group_left (team) job:info`, []string{"http_requests_total", "job:info"}},
+		{`histogram_quantile(0.99, sum by (le) (rate(api_duration_seconds_bucket{job="api"}[5m])))`, []string{"api_duration_seconds_bucket"}},
+		{`{__name__="node_load1", instance="a"}`, []string{"node_load1"}},
+		{`{"metric.with.dots"}`, []string{"metric.with.dots"}},
+		{`label_replace(up, "dst", "$1", "src", "(.*)") > bool 1e-3 and on() vector(1)`, []string{"up"}},
+		{`SUM BY (pod) (kube_pod_info) # comment mentions other_metric`, []string{"kube_pod_info"}},
+	}
+	for _, tt := range tests {
+		a, err := analyze(tt.query)
+		if err != nil {
+			t.Fatalf("analyze(%q) error: %v", tt.query, err)
+		}
+		if !reflect.DeepEqual(a.metrics, tt.want) {
+			t.Errorf("analyze(%q) metrics = %v, want %v", tt.query, a.metrics, tt.want)
+		}
+	}
+}
+
+func TestAnalyzeRejectsMalformed(t *testing.T) {
+	for _, query := range []string{
+		`sum(rate(x[5m])`,
+		`up{job="api}`,
+		`up{job}`,
+		`rate(x[5q])`,
+		`up offset abc`,
+		`x)`,
+	} {
+		if _, err := analyze(query); err == nil {
+			t.Errorf("analyze(%q) expected error", query)
+		}
+	}
+}
+
+func TestCheckQuery(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cfg := DefaultConfig()
+	cfg.Allow = []string{"container_*", "kube_*", "up"}
+	cfg.Deny = []string{"kube_secret_*"}
+	p, err := NewPolicy(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	allowed := []string{
+		`sum by (pod) (rate(container_cpu_usage_seconds_total[5m]))`,
+		`up offset 1d`,
+		`max_over_time(kube_pod_status_phase[1d:5m])`,
+		`vector(1)`,
+	}
+	for _, query := range allowed {
+		if err := p.CheckQuery(query, now); err != nil {
+			t.Errorf("CheckQuery(%q) unexpected error: %v", query, err)
+		}
+	}
+
+	denied := []string{
+		`node_load1`,
+		`kube_secret_info`,
+		`{__name__=~"container_.*"}`,
+	}
+	for _, query := range denied {
+		if err := p.CheckQuery(query, now); !errors.Is(err, ErrMetricDenied) {
+			t.Errorf("CheckQuery(%q) = %v, want ErrMetricDenied", query, err)
+		}
+	}
+
+	invalid := []string{
+		`rate(up[30d])`,
+		`max_over_time(up[1h:1s])`,
+		`up offset 90d`,
+		`up @ 1000`,
+		``,
+	}
+	for _, query := range invalid {
+		err := p.CheckQuery(query, now)
+		if err == nil || errors.Is(err, ErrMetricDenied) {
+			t.Errorf("CheckQuery(%q) = %v, want validation error", query, err)
+		}
+	}
+}
+
+func TestCheckRange(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	p, err := NewPolicy(DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.CheckRange(now.Add(-time.Hour), now, time.Minute, now); err != nil {
+		t.Fatalf("CheckRange() unexpected error: %v", err)
+	}
+	tests := []struct {
+		name       string
+		start, end time.Time
+		step       time.Duration
+	}{
+		{"inverted", now, now.Add(-time.Hour), time.Minute},
+		{"too long", now.Add(-8 * 24 * time.Hour), now, time.Hour},
+		{"too old", now.Add(-40 * 24 * time.Hour), now.Add(-39 * 24 * time.Hour), time.Hour},
+		{"small step", now.Add(-time.Hour), now, time.Second},
+		{"too many points", now.Add(-7 * 24 * time.Hour), now, 20 * time.Second},
+	}
+	for _, tt := range tests {
+		if err := p.CheckRange(tt.start, tt.end, tt.step, now); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestScope(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{`up`, `up{namespace=~"a|b"}`},
+		{`sum(rate(x{job="api"}[5m])) by (pod)`, `sum(rate(x{job="api",namespace=~"a|b"}[5m])) by (pod)`},
+		{`x{} / y{job="a",}`, `x{namespace=~"a|b"} / y{job="a",namespace=~"a|b"}`},
+		// 标签值中的花括号不影响注入位置
+		{`x{job="{}"}`, `x{job="{}",namespace=~"a|b"}`},
+		{`{__name__="x"} offset 5m`, `{__name__="x",namespace=~"a|b"} offset 5m`},
+	}
+	for _, tt := range tests {
+		got, err := Scope(tt.query, `namespace=~"a|b"`)
+		if err != nil {
+			t.Fatalf("Scope(%q) error: %v", tt.query, err)
+		}
+		if got != tt.want {
+			t.Errorf("Scope(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	if err := DefaultConfig().Validate(); err != nil {
+		t.Fatalf("default config invalid: %v", err)
+	}
+	cfg := DefaultConfig()
+	cfg.MaxRange = "7x"
+	cfg.MinStep = "0s"
+	cfg.MaxPoints = 0
+	cfg.Allow = []string{"["}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected validation errors")
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	r, err := NewRewriter(Mapping{
+		Metrics: map[string]string{
+			`kube_node_status_allocatable{resource="cpu"}`: `machine_cpu_cores`,
+			`kube_node_status_allocatable`:                 `kube_node_status_allocatable_total`,
+			`kube_pod_status_phase{phase="Running"}`:       `kube_pod_status_running{job="ksm"}`,
+		},
+		Labels: map[string]string{"pod": "pod_name", "container": "container_name"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{`sum(kube_node_status_allocatable{resource="cpu"})`, `sum(machine_cpu_cores)`},
+		{`sum(kube_node_status_allocatable{resource="memory"})`, `sum(kube_node_status_allocatable_total{resource="memory"})`},
+		{`sum(kube_pod_status_phase{namespace="a", phase="Running"})`, `sum(kube_pod_status_running{job="ksm",namespace="a"})`},
+		{`sum by (namespace, pod) (rate(container_cpu_usage_seconds_total{container!="",container!="POD"}[5m]))`,
+			`sum by (namespace, pod_name) (rate(container_cpu_usage_seconds_total{container_name!="",container_name!="POD"}[5m]))`},
+		// 标签值与字符串参数不受影响
+		{`up{job="pod"} or label_replace(x, "pod", "$1", "instance", "(.*)")`, `up{job="pod"} or label_replace(x, "pod", "$1", "instance", "(.*)")`},
+		{`kube_node_status_allocatable[5m]`, `kube_node_status_allocatable_total[5m]`},
+	}
+	for _, tt := range tests {
+		got, err := r.Rewrite(tt.query)
+		if err != nil {
+			t.Fatalf("Rewrite(%q) error: %v", tt.query, err)
+		}
+		if got != tt.want {
+			t.Errorf("Rewrite(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+
+	labels := map[string]string{"pod_name": "web-0", "namespace": "a"}
+	r.RestoreLabels(labels)
+	if !reflect.DeepEqual(labels, map[string]string{"pod": "web-0", "namespace": "a"}) {
+		t.Errorf("RestoreLabels() = %v", labels)
+	}
+}
+
+func TestMappingValidate(t *testing.T) {
+	invalid := []Mapping{
+		{Metrics: map[string]string{`sum(x)`: `y`}},
+		{Metrics: map[string]string{`x`: `y{`}},
+		{Metrics: map[string]string{`{job="a"}`: `y`}},
+		{Labels: map[string]string{"pod": "bad-label"}},
+		{Labels: map[string]string{"pod": "name", "container": "name"}},
+	}
+	for _, m := range invalid {
+		if err := m.Validate(); err == nil {
+			t.Errorf("Validate(%v) expected error", m)
+		}
+	}
+	if err := (Mapping{}).Validate(); err != nil {
+		t.Errorf("empty mapping invalid: %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+// Package ratelimit 按用户与客户端 IP 的令牌桶限流，以及高开销操作的全局并发上限
+package ratelimit
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Config 限流参数，速率为 0 表示不限制对应维度
+type Config struct {
+	// UserRPS、UserBurst 每个认证用户的每秒请求数与突发容量
+	UserRPS   float64 `json:"userRps"`
+	UserBurst int     `json:"userBurst"`
+	// IPRPS、IPBurst 每个客户端 IP 的每秒请求数与突发容量（含登录等未认证请求）
+	IPRPS   float64 `json:"ipRps"`
+	IPBurst int     `json:"ipBurst"`
+	// MaxConcurrentExpensive 全局同时进行的高开销操作（全集群列表、日志流等）上限，0 表示不限制
+	MaxConcurrentExpensive int `json:"maxConcurrentExpensive"`
+}
+
+// DefaultConfig 默认每用户 20 req/s、每 IP 50 req/s（多个用户可能共用出口 IP），高开销操作最多并发 32 个
+func DefaultConfig() Config {
+	return Config{
+		UserRPS:                20,
+		UserBurst:              40,
+		IPRPS:                  50,
+		IPBurst:                100,
+		MaxConcurrentExpensive: 32,
+	}
+}
+
+// Validate 校验限流参数
+func (cfg Config) Validate() error {
+	var errs []error
+	if cfg.UserRPS < 0 || cfg.IPRPS < 0 {
+		errs = append(errs, errors.New("RATE_LIMIT_USER_RPS 与 RATE_LIMIT_IP_RPS 不能为负数"))
+	}
+	if (cfg.UserRPS > 0 && cfg.UserBurst < 1) || (cfg.IPRPS > 0 && cfg.IPBurst < 1) {
+		errs = append(errs, errors.New("启用限流时 RATE_LIMIT_USER_BURST 与 RATE_LIMIT_IP_BURST 必须大于 0"))
+	}
+	if cfg.MaxConcurrentExpensive < 0 {
+		errs = append(errs, errors.New("RATE_LIMIT_MAX_CONCURRENT_EXPENSIVE 不能为负数"))
+	}
+	return errors.Join(errs...)
+}
+
+// idleTTL 超过该时长没有请求的令牌桶被清理（此时桶早已回满，重建不影响限流结果）
+const idleTTL = 10 * time.Minute
+
+// concurrencyRetryAfter 并发已满时建议客户端重试的等待时间
+const concurrencyRetryAfter = time.Second
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// buckets 按键维护的令牌桶
+type buckets struct {
+	limit     rate.Limit
+	burst     int
+	mu        sync.Mutex
+	entries   map[string]*bucket
+	lastSweep time.Time
+}
+
+func newBuckets(rps float64, burst int) *buckets {
+	if rps <= 0 {
+		return nil
+	}
+	return &buckets{limit: rate.Limit(rps), burst: burst, entries: make(map[string]*bucket)}
+}
+
+// allow 消耗 key 的一个令牌，令牌不足时返回需要等待的时长
+func (b *buckets) allow(key string, now time.Time) (bool, time.Duration) {
+	if b == nil {
+		return true, 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if now.Sub(b.lastSweep) > idleTTL {
+		for k, entry := range b.entries {
+			if now.Sub(entry.lastSeen) > idleTTL {
+				delete(b.entries, k)
+			}
+		}
+		b.lastSweep = now
+	}
+
+	entry, ok := b.entries[key]
+	if !ok {
+		entry = &bucket{limiter: rate.NewLimiter(b.limit, b.burst)}
+		b.entries[key] = entry
+	}
+	entry.lastSeen = now
+
+	reservation := entry.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		// 被拒绝的请求不占用令牌
+		reservation.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// Limiter 请求限流器，零值不可用，使用 NewLimiter 创建；nil 表示不限流
+type Limiter struct {
+	users     *buckets
+	ips       *buckets
+	expensive chan struct{}
+	now       func() time.Time
+}
+
+// NewLimiter 创建限流器
+func NewLimiter(cfg Config) *Limiter {
+	l := &Limiter{
+		users: newBuckets(cfg.UserRPS, cfg.UserBurst),
+		ips:   newBuckets(cfg.IPRPS, cfg.IPBurst),
+		now:   time.Now,
+	}
+	if cfg.MaxConcurrentExpensive > 0 {
+		l.expensive = make(chan struct{}, cfg.MaxConcurrentExpensive)
+	}
+	return l
+}
+
+// AllowUser 按用户限流，拒绝时返回建议的重试等待时间
+func (l *Limiter) AllowUser(user string) (bool, time.Duration) {
+	if l == nil {
+		return true, 0
+	}
+	return l.users.allow(user, l.now())
+}
+
+// AllowIP 按客户端 IP 限流，拒绝时返回建议的重试等待时间
+func (l *Limiter) AllowIP(ip string) (bool, time.Duration) {
+	if l == nil {
+		return true, 0
+	}
+	return l.ips.allow(ip, l.now())
+}
+
+// AcquireExpensive 占用一个高开销操作名额，不等待；成功时操作结束后必须调用 release，
+// 名额已满时返回建议的重试等待时间
+func (l *Limiter) AcquireExpensive() (release func(), ok bool, retryAfter time.Duration) {
+	if l == nil || l.expensive == nil {
+		return func() {}, true, 0
+	}
+	select {
+	case l.expensive <- struct{}{}:
+		var once sync.Once
+		return func() { once.Do(func() { <-l.expensive }) }, true, 0
+	default:
+		return nil, false, concurrencyRetryAfter
+	}
+}
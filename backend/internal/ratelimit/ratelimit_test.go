@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAllowUserTokenBucket(t *testing.T) {
+	l := NewLimiter(Config{UserRPS: 2, UserBurst: 2})
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.AllowUser("1"); !ok {
+			t.Fatalf("request %d within burst was rejected", i)
+		}
+	}
+	ok, retryAfter := l.AllowUser("1")
+	if ok || retryAfter != 500*time.Millisecond {
+		t.Fatalf("AllowUser() = %v, %v, want rejected with 500ms", ok, retryAfter)
+	}
+	// 其他用户的额度独立
+	if ok, _ := l.AllowUser("2"); !ok {
+		t.Fatal("other user should not be limited")
+	}
+	// 被拒绝的请求不消耗令牌
+	now = now.Add(500 * time.Millisecond)
+	if ok, _ := l.AllowUser("1"); !ok {
+		t.Fatal("token should be refilled after retryAfter")
+	}
+	// IP 维度未启用
+	for i := 0; i < 100; i++ {
+		if ok, _ := l.AllowIP("10.0.0.1"); !ok {
+			t.Fatal("IP limit disabled but request rejected")
+		}
+	}
+}
+
+func TestBucketsSweepIdleEntries(t *testing.T) {
+	b := newBuckets(1, 1)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	b.allow("a", now)
+	b.allow("b", now.Add(idleTTL))
+	b.allow("b", now.Add(idleTTL+time.Minute))
+	if _, ok := b.entries["a"]; ok {
+		t.Fatal("idle bucket should be swept")
+	}
+	if _, ok := b.entries["b"]; !ok {
+		t.Fatal("active bucket should be kept")
+	}
+}
+
+func TestAcquireExpensive(t *testing.T) {
+	l := NewLimiter(Config{MaxConcurrentExpensive: 1})
+	release, ok, _ := l.AcquireExpensive()
+	if !ok {
+		t.Fatal("first acquire should succeed")
+	}
+	if _, ok, retryAfter := l.AcquireExpensive(); ok || retryAfter <= 0 {
+		t.Fatalf("second acquire = %v, %v, want rejected", ok, retryAfter)
+	}
+	release()
+	release()
+	if _, ok, _ := l.AcquireExpensive(); !ok {
+		t.Fatal("acquire should succeed after release")
+	}
+
+	var nilLimiter *Limiter
+	if _, ok, _ := nilLimiter.AcquireExpensive(); !ok {
+		t.Fatal("nil limiter should not limit")
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	if err := DefaultConfig().Validate(); err != nil {
+		t.Fatalf("default config invalid: %v", err)
+	}
+	if err := (Config{}).Validate(); err != nil {
+		t.Fatalf("disabled config invalid: %v", err)
+	}
+	if err := (Config{UserRPS: 1}).Validate(); err == nil {
+		t.Fatal("expected zero burst to be rejected")
+	}
+	if err := (Config{IPRPS: -1}).Validate(); err == nil {
+		t.Fatal("expected negative rate to be rejected")
+	}
+}
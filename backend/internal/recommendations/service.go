@@ -0,0 +1,257 @@
+// Package recommendations 根据历史用量为容器给出 requests/limits 建议，作用类似轻量的 VPA 推荐器：
+// 只给出建议，不修改任何工作负载
+package recommendations
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/k8s-dashboard/backend/internal/k8s"
+	"github.com/k8s-dashboard/backend/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// 建议结论
+const (
+	StatusOK               = "ok"
+	StatusOverProvisioned  = "over-provisioned"
+	StatusUnderProvisioned = "under-provisioned"
+	StatusMissingRequests  = "missing-requests"
+)
+
+// Config 推荐参数
+type Config struct {
+	// Window 统计用量的时间窗口（PromQL 时长，如 24h、7d、2w）
+	Window string `json:"window"`
+	// Percentile 用量分位数（1-100），默认 95
+	Percentile int `json:"percentile"`
+	// HeadroomPercent 在分位数用量基础上预留的余量百分比
+	HeadroomPercent int `json:"headroomPercent"`
+	// TolerancePercent 当前 request 与建议值的偏差在此范围内视为合理
+	TolerancePercent int `json:"tolerancePercent"`
+	// MinCPUMillicores、MinMemoryBytes 建议 request 的下限，避免给空闲容器过小的值
+	MinCPUMillicores int64 `json:"minCpuMillicores"`
+	MinMemoryBytes   int64 `json:"minMemoryBytes"`
+}
+
+// DefaultConfig 默认取 7 天 P95 用量并预留 15% 余量
+func DefaultConfig() Config {
+	return Config{
+		Window:           "7d",
+		Percentile:       95,
+		HeadroomPercent:  15,
+		TolerancePercent: 30,
+		MinCPUMillicores: 10,
+		MinMemoryBytes:   32 * 1024 * 1024,
+	}
+}
+
+// Validate 校验推荐参数
+func (cfg Config) Validate() error {
+	var errs []error
+	if err := metrics.ValidateWindow(cfg.Window); err != nil {
+		errs = append(errs, fmt.Errorf("RECOMMENDATION_WINDOW %w", err))
+	}
+	if cfg.Percentile <= 0 || cfg.Percentile > 100 {
+		errs = append(errs, fmt.Errorf("RECOMMENDATION_PERCENTILE 必须在 1-100 之间: %d", cfg.Percentile))
+	}
+	if cfg.HeadroomPercent < 0 || cfg.TolerancePercent < 0 {
+		errs = append(errs, errors.New("RECOMMENDATION_HEADROOM_PERCENT 与 RECOMMENDATION_TOLERANCE_PERCENT 不能为负数"))
+	}
+	if cfg.MinCPUMillicores < 0 || cfg.MinMemoryBytes < 0 {
+		errs = append(errs, errors.New("建议值下限不能为负数"))
+	}
+	return errors.Join(errs...)
+}
+
+// Resources 一组 CPU（毫核）与内存（字节）数值，0 表示未设置
+type Resources struct {
+	CPURequestMillicores int64 `json:"cpuRequestMillicores"`
+	CPULimitMillicores   int64 `json:"cpuLimitMillicores"`
+	MemoryRequestBytes   int64 `json:"memoryRequestBytes"`
+	MemoryLimitBytes     int64 `json:"memoryLimitBytes"`
+}
+
+// Usage 窗口内的用量分位数，取工作负载所有 Pod 中的最大值
+type Usage struct {
+	CPUMillicores float64 `json:"cpuMillicores"`
+	MemoryBytes   float64 `json:"memoryBytes"`
+}
+
+// ContainerRecommendation 单个工作负载中一个容器的建议
+type ContainerRecommendation struct {
+	Namespace    string    `json:"namespace"`
+	WorkloadKind string    `json:"workloadKind"`
+	Workload     string    `json:"workload"`
+	Container    string    `json:"container"`
+	Pods         int       `json:"pods"` // 参与统计且有用量数据的 Pod 数
+	Current      Resources `json:"current"`
+	Usage        Usage     `json:"usage"`
+	Recommended  Resources `json:"recommended"`
+	CPUStatus    string    `json:"cpuStatus"`
+	MemoryStatus string    `json:"memoryStatus"`
+}
+
+// Report 推荐结果
+type Report struct {
+	Window     string                    `json:"window"`
+	Percentile int                       `json:"percentile"`
+	Items      []ContainerRecommendation `json:"items"`
+	Total      int                       `json:"total"`
+}
+
+// Service 资源建议服务
+type Service struct {
+	cfg Config
+}
+
+// NewService 创建资源建议服务
+func NewService(cfg Config) (*Service, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &Service{cfg: cfg}, nil
+}
+
+// Config 返回推荐参数
+func (s *Service) Config() Config {
+	return s.cfg
+}
+
+// Recommend 查询 pods 所在命名空间的容器用量分位数并给出建议。window 为空时使用配置的默认窗口，
+// namespaces 用于限定指标查询范围（为空表示不限制）
+func (s *Service) Recommend(client *metrics.Client, pods []corev1.Pod, namespaces []string, window string) (*Report, error) {
+	if window == "" {
+		window = s.cfg.Window
+	}
+	if err := metrics.ValidateWindow(window); err != nil {
+		return nil, err
+	}
+	usages, err := client.GetContainerUsageQuantile(float64(s.cfg.Percentile)/100, window, namespaces)
+	if err != nil {
+		return nil, err
+	}
+	items := Build(s.cfg, pods, usages)
+	return &Report{Window: window, Percentile: s.cfg.Percentile, Items: items, Total: len(items)}, nil
+}
+
+// Build 将容器用量与 Pod 规格按工作负载、容器聚合并计算建议。没有用量数据的容器（如刚创建）不出现在结果中
+func Build(cfg Config, pods []corev1.Pod, usages []metrics.ContainerUsage) []ContainerRecommendation {
+	usageByContainer := make(map[string]metrics.ContainerUsage, len(usages))
+	for _, usage := range usages {
+		usageByContainer[usage.Namespace+"/"+usage.Pod+"/"+usage.Container] = usage
+	}
+
+	grouped := make(map[string]*ContainerRecommendation)
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		kind, workload := k8s.PodWorkload(pod)
+		for _, container := range pod.Spec.Containers {
+			usage, ok := usageByContainer[pod.Namespace+"/"+pod.Name+"/"+container.Name]
+			if !ok {
+				continue
+			}
+			key := strings.Join([]string{pod.Namespace, kind, workload, container.Name}, "/")
+			rec, exists := grouped[key]
+			if !exists {
+				rec = &ContainerRecommendation{
+					Namespace:    pod.Namespace,
+					WorkloadKind: kind,
+					Workload:     workload,
+					Container:    container.Name,
+					Current:      currentResources(container.Resources),
+				}
+				grouped[key] = rec
+			}
+			rec.Pods++
+			rec.Usage.CPUMillicores = math.Max(rec.Usage.CPUMillicores, usage.CPUCores*1000)
+			rec.Usage.MemoryBytes = math.Max(rec.Usage.MemoryBytes, usage.MemoryBytes)
+		}
+	}
+
+	result := make([]ContainerRecommendation, 0, len(grouped))
+	for _, rec := range grouped {
+		headroom := 1 + float64(cfg.HeadroomPercent)/100
+		rec.Recommended.CPURequestMillicores = max(int64(math.Ceil(rec.Usage.CPUMillicores*headroom)), cfg.MinCPUMillicores)
+		rec.Recommended.MemoryRequestBytes = roundUpMiB(max(int64(math.Ceil(rec.Usage.MemoryBytes*headroom)), cfg.MinMemoryBytes))
+		// limit 保持当前 limit/request 比例，与 VPA 的做法一致；未设置 limit 的仍不建议设置
+		rec.Recommended.CPULimitMillicores = scaleLimit(rec.Current.CPULimitMillicores, rec.Current.CPURequestMillicores, rec.Recommended.CPURequestMillicores)
+		rec.Recommended.MemoryLimitBytes = scaleLimit(rec.Current.MemoryLimitBytes, rec.Current.MemoryRequestBytes, rec.Recommended.MemoryRequestBytes)
+		if rec.Recommended.MemoryLimitBytes > 0 {
+			rec.Recommended.MemoryLimitBytes = roundUpMiB(rec.Recommended.MemoryLimitBytes)
+		}
+		rec.CPUStatus = compare(rec.Current.CPURequestMillicores, rec.Recommended.CPURequestMillicores, cfg.TolerancePercent)
+		rec.MemoryStatus = compare(rec.Current.MemoryRequestBytes, rec.Recommended.MemoryRequestBytes, cfg.TolerancePercent)
+		result = append(result, *rec)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Workload != b.Workload {
+			return a.Workload < b.Workload
+		}
+		if a.WorkloadKind != b.WorkloadKind {
+			return a.WorkloadKind < b.WorkloadKind
+		}
+		return a.Container < b.Container
+	})
+	return result
+}
+
+func currentResources(req corev1.ResourceRequirements) Resources {
+	var r Resources
+	if q, ok := req.Requests[corev1.ResourceCPU]; ok {
+		r.CPURequestMillicores = q.MilliValue()
+	}
+	if q, ok := req.Limits[corev1.ResourceCPU]; ok {
+		r.CPULimitMillicores = q.MilliValue()
+	}
+	if q, ok := req.Requests[corev1.ResourceMemory]; ok {
+		r.MemoryRequestBytes = q.Value()
+	}
+	if q, ok := req.Limits[corev1.ResourceMemory]; ok {
+		r.MemoryLimitBytes = q.Value()
+	}
+	return r
+}
+
+// scaleLimit 按当前 limit/request 比例换算建议 limit；未设置 limit 返回 0，只设置 limit 时 request 默认等于 limit
+func scaleLimit(limit, request, recommended int64) int64 {
+	if limit <= 0 {
+		return 0
+	}
+	if request <= 0 {
+		return recommended
+	}
+	return int64(math.Ceil(float64(recommended) * float64(limit) / float64(request)))
+}
+
+// compare 比较当前 request 与建议值
+func compare(current, recommended int64, tolerancePercent int) string {
+	if current <= 0 {
+		return StatusMissingRequests
+	}
+	tolerance := float64(tolerancePercent) / 100
+	switch {
+	case float64(current) > float64(recommended)*(1+tolerance):
+		return StatusOverProvisioned
+	case float64(current) < float64(recommended)*(1-tolerance):
+		return StatusUnderProvisioned
+	}
+	return StatusOK
+}
+
+// roundUpMiB 向上取整到 MiB，便于直接写入清单
+func roundUpMiB(bytes int64) int64 {
+	const mib = 1024 * 1024
+	return (bytes + mib - 1) / mib * mib
+}
@@ -0,0 +1,88 @@
+package recommendations
+
+import (
+	"testing"
+
+	"github.com/k8s-dashboard/backend/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testPod(name, rsName, hash string, requests, limits corev1.ResourceList) corev1.Pod {
+	controller := true
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "shop",
+			Labels:          map[string]string{"pod-template-hash": hash},
+			OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: rsName, Controller: &controller}},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:      "api",
+			Resources: corev1.ResourceRequirements{Requests: requests, Limits: limits},
+		}}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func TestBuildAggregatesPodsIntoDeployment(t *testing.T) {
+	requests := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("1"),
+		corev1.ResourceMemory: resource.MustParse("1Gi"),
+	}
+	limits := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("2"),
+		corev1.ResourceMemory: resource.MustParse("1Gi"),
+	}
+	pods := []corev1.Pod{
+		testPod("api-7d9f-a", "api-7d9f", "7d9f", requests, limits),
+		testPod("api-7d9f-b", "api-7d9f", "7d9f", requests, limits),
+	}
+	usages := []metrics.ContainerUsage{
+		{Namespace: "shop", Pod: "api-7d9f-a", Container: "api", CPUCores: 0.1, MemoryBytes: 200 * 1024 * 1024},
+		{Namespace: "shop", Pod: "api-7d9f-b", Container: "api", CPUCores: 0.2, MemoryBytes: 100 * 1024 * 1024},
+	}
+
+	items := Build(DefaultConfig(), pods, usages)
+	if len(items) != 1 {
+		t.Fatalf("expected 1 recommendation, got %d: %+v", len(items), items)
+	}
+	rec := items[0]
+	if rec.WorkloadKind != "Deployment" || rec.Workload != "api" || rec.Pods != 2 {
+		t.Fatalf("unexpected workload grouping: %+v", rec)
+	}
+	// 取两个 Pod 中较高的用量：200m * 1.15
+	if rec.Recommended.CPURequestMillicores != 230 || rec.Recommended.CPULimitMillicores != 460 {
+		t.Fatalf("unexpected cpu recommendation: %+v", rec.Recommended)
+	}
+	if rec.Recommended.MemoryRequestBytes != 230*1024*1024 || rec.Recommended.MemoryLimitBytes != 230*1024*1024 {
+		t.Fatalf("unexpected memory recommendation: %+v", rec.Recommended)
+	}
+	if rec.CPUStatus != StatusOverProvisioned || rec.MemoryStatus != StatusOverProvisioned {
+		t.Fatalf("unexpected status cpu=%s memory=%s", rec.CPUStatus, rec.MemoryStatus)
+	}
+}
+
+func TestBuildFlagsMissingRequestsAndSkipsContainersWithoutUsage(t *testing.T) {
+	pods := []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "debug", Namespace: "shop"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "shell"}, {Name: "sidecar"}}},
+		},
+	}
+	usages := []metrics.ContainerUsage{{Namespace: "shop", Pod: "debug", Container: "shell", CPUCores: 0.0001, MemoryBytes: 1024}}
+
+	items := Build(DefaultConfig(), pods, usages)
+	if len(items) != 1 || items[0].Container != "shell" || items[0].WorkloadKind != "Pod" {
+		t.Fatalf("unexpected recommendations: %+v", items)
+	}
+	rec := items[0]
+	if rec.CPUStatus != StatusMissingRequests || rec.MemoryStatus != StatusMissingRequests {
+		t.Fatalf("expected missing requests, got cpu=%s memory=%s", rec.CPUStatus, rec.MemoryStatus)
+	}
+	// 低于下限时使用配置的最小值，未设置 limit 时不建议 limit
+	if rec.Recommended.CPURequestMillicores != 10 || rec.Recommended.MemoryRequestBytes != 32*1024*1024 || rec.Recommended.CPULimitMillicores != 0 {
+		t.Fatalf("unexpected recommendation: %+v", rec.Recommended)
+	}
+}
@@ -0,0 +1,436 @@
+package runbooks
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	dbutil "github.com/k8s-dashboard/backend/internal/db"
+	"github.com/k8s-dashboard/backend/internal/k8s"
+)
+
+// ErrRunbookNotFound 运行手册不存在
+var ErrRunbookNotFound = errors.New("运行手册不存在")
+
+// ErrRunNotFound 执行记录不存在
+var ErrRunNotFound = errors.New("执行记录不存在")
+
+// 执行状态
+const (
+	RunStatusRunning   = k8s.JobStatusRunning
+	RunStatusSucceeded = k8s.JobStatusSucceeded
+	RunStatusFailed    = k8s.JobStatusFailed
+	RunStatusError     = "error" // Job 创建失败
+)
+
+// Parameter 模板参数定义
+type Parameter struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required"`
+	Default     string `json:"default,omitempty"`
+	Pattern     string `json:"pattern,omitempty"` // 取值需完整匹配的正则
+}
+
+// Runbook 参数化的 Job 模板
+type Runbook struct {
+	ID          int64       `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Manifest    string      `json:"manifest"` // Job YAML，使用 {{ .参数名 }} 引用参数
+	Parameters  []Parameter `json:"parameters"`
+	MinRole     string      `json:"minRole"`    // 执行所需最低角色: operator, admin
+	Namespaces  []string    `json:"namespaces"` // 允许执行的命名空间，空表示不限
+	CreatedBy   string      `json:"createdBy"`
+	CreatedAt   time.Time   `json:"createdAt"`
+	UpdatedAt   time.Time   `json:"updatedAt"`
+}
+
+// Run 运行手册执行记录
+type Run struct {
+	ID          int64             `json:"id"`
+	RunbookID   int64             `json:"runbookId"`
+	RunbookName string            `json:"runbookName"`
+	UserID      int64             `json:"userId"`
+	Username    string            `json:"username"`
+	Cluster     string            `json:"cluster"`
+	Namespace   string            `json:"namespace"`
+	JobName     string            `json:"jobName"`
+	Parameters  map[string]string `json:"parameters"`
+	Status      string            `json:"status"`
+	Message     string            `json:"message,omitempty"`
+	StartedAt   time.Time         `json:"startedAt"`
+	FinishedAt  *time.Time        `json:"finishedAt,omitempty"`
+}
+
+// RunListParams 执行记录查询参数
+type RunListParams struct {
+	RunbookName string
+	UserID      int64 // 0 表示不限
+	Limit       int
+}
+
+// Repository 运行手册数据仓库
+type Repository struct {
+	db      *sql.DB
+	dialect dbutil.Dialect
+}
+
+// NewRepository 创建运行手册数据仓库
+func NewRepository(db *sql.DB, dialect dbutil.Dialect) (*Repository, error) {
+	repo := &Repository{
+		db:      db,
+		dialect: dialect,
+	}
+
+	if err := repo.initSchema(); err != nil {
+		return nil, fmt.Errorf("初始化表结构失败: %w", err)
+	}
+
+	return repo, nil
+}
+
+// initSchema 初始化表结构
+func (r *Repository) initSchema() error {
+	var schema string
+	if r.dialect == dbutil.DialectSQLite {
+		schema = `
+		CREATE TABLE IF NOT EXISTS runbooks (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT UNIQUE NOT NULL,
+			description TEXT,
+			manifest TEXT NOT NULL,
+			parameters TEXT,
+			min_role TEXT NOT NULL DEFAULT 'operator',
+			namespaces TEXT,
+			created_by TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS runbook_runs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			runbook_id INTEGER NOT NULL,
+			runbook_name TEXT NOT NULL,
+			user_id INTEGER NOT NULL,
+			username TEXT NOT NULL,
+			cluster TEXT,
+			namespace TEXT NOT NULL,
+			job_name TEXT,
+			parameters TEXT,
+			status TEXT NOT NULL,
+			message TEXT,
+			started_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			finished_at DATETIME
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_runbook_runs_runbook ON runbook_runs(runbook_name);
+		CREATE INDEX IF NOT EXISTS idx_runbook_runs_user ON runbook_runs(user_id);
+		`
+	} else {
+		schema = `
+		CREATE TABLE IF NOT EXISTS runbooks (
+			id BIGSERIAL PRIMARY KEY,
+			name VARCHAR(63) UNIQUE NOT NULL,
+			description TEXT,
+			manifest TEXT NOT NULL,
+			parameters TEXT,
+			min_role VARCHAR(20) NOT NULL DEFAULT 'operator',
+			namespaces TEXT,
+			created_by VARCHAR(100),
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS runbook_runs (
+			id BIGSERIAL PRIMARY KEY,
+			runbook_id BIGINT NOT NULL,
+			runbook_name VARCHAR(63) NOT NULL,
+			user_id BIGINT NOT NULL,
+			username VARCHAR(100) NOT NULL,
+			cluster VARCHAR(100),
+			namespace VARCHAR(255) NOT NULL,
+			job_name VARCHAR(255),
+			parameters TEXT,
+			status VARCHAR(20) NOT NULL,
+			message TEXT,
+			started_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			finished_at TIMESTAMP WITH TIME ZONE
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_runbook_runs_runbook ON runbook_runs(runbook_name);
+		CREATE INDEX IF NOT EXISTS idx_runbook_runs_user ON runbook_runs(user_id);
+		`
+	}
+
+	_, err := r.db.Exec(schema)
+	return err
+}
+
+// Create 创建运行手册
+func (r *Repository) Create(rb *Runbook) error {
+	params, namespaces, err := encodeRunbook(rb)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	rb.CreatedAt = now
+	rb.UpdatedAt = now
+
+	if r.dialect == dbutil.DialectSQLite {
+		result, err := r.db.Exec(`
+			INSERT INTO runbooks (name, description, manifest, parameters, min_role, namespaces, created_by, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`, rb.Name, rb.Description, rb.Manifest, params, rb.MinRole, namespaces, rb.CreatedBy, now, now)
+		if err != nil {
+			return err
+		}
+		rb.ID, err = result.LastInsertId()
+		return err
+	}
+
+	return r.db.QueryRow(`
+		INSERT INTO runbooks (name, description, manifest, parameters, min_role, namespaces, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`, rb.Name, rb.Description, rb.Manifest, params, rb.MinRole, namespaces, rb.CreatedBy, now, now).Scan(&rb.ID)
+}
+
+// Update 按名称更新运行手册
+func (r *Repository) Update(rb *Runbook) error {
+	params, namespaces, err := encodeRunbook(rb)
+	if err != nil {
+		return err
+	}
+
+	rb.UpdatedAt = time.Now()
+	result, err := r.db.Exec(`
+		UPDATE runbooks SET
+			description = $1, manifest = $2, parameters = $3, min_role = $4, namespaces = $5, updated_at = $6
+		WHERE name = $7
+	`, rb.Description, rb.Manifest, params, rb.MinRole, namespaces, rb.UpdatedAt, rb.Name)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrRunbookNotFound
+	}
+	return nil
+}
+
+// Delete 删除运行手册（保留执行记录）
+func (r *Repository) Delete(name string) error {
+	result, err := r.db.Exec("DELETE FROM runbooks WHERE name = $1", name)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrRunbookNotFound
+	}
+	return nil
+}
+
+// Get 按名称获取运行手册
+func (r *Repository) Get(name string) (*Runbook, error) {
+	row := r.db.QueryRow(`
+		SELECT id, name, COALESCE(description, ''), manifest, COALESCE(parameters, ''), min_role,
+		       COALESCE(namespaces, ''), COALESCE(created_by, ''), created_at, updated_at
+		FROM runbooks WHERE name = $1
+	`, name)
+
+	rb, err := scanRunbook(row)
+	if err == sql.ErrNoRows {
+		return nil, ErrRunbookNotFound
+	}
+	return rb, err
+}
+
+// List 列出全部运行手册
+func (r *Repository) List() ([]Runbook, error) {
+	rows, err := r.db.Query(`
+		SELECT id, name, COALESCE(description, ''), manifest, COALESCE(parameters, ''), min_role,
+		       COALESCE(namespaces, ''), COALESCE(created_by, ''), created_at, updated_at
+		FROM runbooks ORDER BY name ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []Runbook{}
+	for rows.Next() {
+		rb, err := scanRunbook(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *rb)
+	}
+	return items, rows.Err()
+}
+
+// CreateRun 记录一次执行
+func (r *Repository) CreateRun(run *Run) error {
+	params, err := json.Marshal(run.Parameters)
+	if err != nil {
+		return err
+	}
+	if run.StartedAt.IsZero() {
+		run.StartedAt = time.Now()
+	}
+
+	if r.dialect == dbutil.DialectSQLite {
+		result, err := r.db.Exec(`
+			INSERT INTO runbook_runs (runbook_id, runbook_name, user_id, username, cluster, namespace, job_name, parameters, status, message, started_at, finished_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		`, run.RunbookID, run.RunbookName, run.UserID, run.Username, run.Cluster, run.Namespace, run.JobName,
+			string(params), run.Status, run.Message, run.StartedAt, run.FinishedAt)
+		if err != nil {
+			return err
+		}
+		run.ID, err = result.LastInsertId()
+		return err
+	}
+
+	return r.db.QueryRow(`
+		INSERT INTO runbook_runs (runbook_id, runbook_name, user_id, username, cluster, namespace, job_name, parameters, status, message, started_at, finished_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id
+	`, run.RunbookID, run.RunbookName, run.UserID, run.Username, run.Cluster, run.Namespace, run.JobName,
+		string(params), run.Status, run.Message, run.StartedAt, run.FinishedAt).Scan(&run.ID)
+}
+
+// UpdateRunStatus 更新执行状态
+func (r *Repository) UpdateRunStatus(id int64, status, message string, finishedAt *time.Time) error {
+	_, err := r.db.Exec(`
+		UPDATE runbook_runs SET status = $1, message = $2, finished_at = $3 WHERE id = $4
+	`, status, message, finishedAt, id)
+	return err
+}
+
+// GetRun 获取执行记录
+func (r *Repository) GetRun(id int64) (*Run, error) {
+	row := r.db.QueryRow(`
+		SELECT id, runbook_id, runbook_name, user_id, username, COALESCE(cluster, ''), namespace,
+		       COALESCE(job_name, ''), COALESCE(parameters, ''), status, COALESCE(message, ''), started_at, finished_at
+		FROM runbook_runs WHERE id = $1
+	`, id)
+
+	run, err := scanRun(row)
+	if err == sql.ErrNoRows {
+		return nil, ErrRunNotFound
+	}
+	return run, err
+}
+
+// ListRuns 查询执行历史（按时间倒序）
+func (r *Repository) ListRuns(params RunListParams) ([]Run, error) {
+	if params.Limit <= 0 || params.Limit > 200 {
+		params.Limit = 50
+	}
+
+	where := "WHERE 1=1"
+	args := []interface{}{}
+	argIndex := 1
+	if params.RunbookName != "" {
+		where += fmt.Sprintf(" AND runbook_name = $%d", argIndex)
+		args = append(args, params.RunbookName)
+		argIndex++
+	}
+	if params.UserID > 0 {
+		where += fmt.Sprintf(" AND user_id = $%d", argIndex)
+		args = append(args, params.UserID)
+		argIndex++
+	}
+	args = append(args, params.Limit)
+
+	rows, err := r.db.Query(fmt.Sprintf(`
+		SELECT id, runbook_id, runbook_name, user_id, username, COALESCE(cluster, ''), namespace,
+		       COALESCE(job_name, ''), COALESCE(parameters, ''), status, COALESCE(message, ''), started_at, finished_at
+		FROM runbook_runs %s
+		ORDER BY id DESC
+		LIMIT $%d
+	`, where, argIndex), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []Run{}
+	for rows.Next() {
+		run, err := scanRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *run)
+	}
+	return items, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func encodeRunbook(rb *Runbook) (string, string, error) {
+	params, err := json.Marshal(rb.Parameters)
+	if err != nil {
+		return "", "", err
+	}
+	namespaces, err := json.Marshal(rb.Namespaces)
+	if err != nil {
+		return "", "", err
+	}
+	return string(params), string(namespaces), nil
+}
+
+func scanRunbook(row rowScanner) (*Runbook, error) {
+	var rb Runbook
+	var params, namespaces string
+	if err := row.Scan(&rb.ID, &rb.Name, &rb.Description, &rb.Manifest, &params, &rb.MinRole,
+		&namespaces, &rb.CreatedBy, &rb.CreatedAt, &rb.UpdatedAt); err != nil {
+		return nil, err
+	}
+	rb.Parameters = []Parameter{}
+	if params != "" && params != "null" {
+		if err := json.Unmarshal([]byte(params), &rb.Parameters); err != nil {
+			return nil, fmt.Errorf("解析参数定义失败: %w", err)
+		}
+	}
+	rb.Namespaces = []string{}
+	if namespaces != "" && namespaces != "null" {
+		if err := json.Unmarshal([]byte(namespaces), &rb.Namespaces); err != nil {
+			return nil, fmt.Errorf("解析命名空间失败: %w", err)
+		}
+	}
+	return &rb, nil
+}
+
+func scanRun(row rowScanner) (*Run, error) {
+	var run Run
+	var params string
+	var finishedAt sql.NullTime
+	if err := row.Scan(&run.ID, &run.RunbookID, &run.RunbookName, &run.UserID, &run.Username, &run.Cluster,
+		&run.Namespace, &run.JobName, &params, &run.Status, &run.Message, &run.StartedAt, &finishedAt); err != nil {
+		return nil, err
+	}
+	run.Parameters = map[string]string{}
+	if params != "" && params != "null" {
+		if err := json.Unmarshal([]byte(params), &run.Parameters); err != nil {
+			return nil, fmt.Errorf("解析执行参数失败: %w", err)
+		}
+	}
+	if finishedAt.Valid {
+		run.FinishedAt = &finishedAt.Time
+	}
+	return &run, nil
+}
@@ -0,0 +1,321 @@
+package runbooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	"github.com/k8s-dashboard/backend/internal/k8s"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// LabelRunbook Job 上标记运行手册来源的标签
+const LabelRunbook = "dashboard.k8s.io/runbook"
+
+const (
+	maxManifestLength   = 64 * 1024
+	maxParameterValue   = 1024
+	maxParametersPerRun = 50
+)
+
+var (
+	runbookNamePattern   = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,50}[a-z0-9])?$`)
+	parameterNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// ValidationError 运行手册或执行参数校验失败
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// Service 运行手册服务
+type Service struct {
+	repo *Repository
+}
+
+// NewService 创建运行手册服务
+func NewService(repo *Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// templateFuncs 模板可用函数：quote 输出 YAML 安全的双引号字符串
+var templateFuncs = template.FuncMap{
+	"quote": func(v string) string {
+		data, _ := json.Marshal(v)
+		return string(data)
+	},
+}
+
+// Validate 校验并规范化运行手册定义，会用参数默认值（或占位值）试渲染模板
+func Validate(rb *Runbook) error {
+	rb.Name = strings.TrimSpace(rb.Name)
+	rb.Description = strings.TrimSpace(rb.Description)
+	rb.MinRole = strings.ToLower(strings.TrimSpace(rb.MinRole))
+
+	if !runbookNamePattern.MatchString(rb.Name) {
+		return &ValidationError{Field: "name", Message: "名称只能包含小写字母、数字和中划线，且不超过 52 个字符"}
+	}
+	if rb.MinRole == "" {
+		rb.MinRole = "operator"
+	}
+	// 执行会创建 Job，至少需要 operator
+	if rb.MinRole != "operator" && rb.MinRole != "admin" {
+		return &ValidationError{Field: "minRole", Message: "仅支持 operator, admin"}
+	}
+	if strings.TrimSpace(rb.Manifest) == "" {
+		return &ValidationError{Field: "manifest", Message: "Job 模板不能为空"}
+	}
+	if len(rb.Manifest) > maxManifestLength {
+		return &ValidationError{Field: "manifest", Message: "Job 模板过大"}
+	}
+	if rb.Parameters == nil {
+		rb.Parameters = []Parameter{}
+	}
+	if len(rb.Parameters) > maxParametersPerRun {
+		return &ValidationError{Field: "parameters", Message: fmt.Sprintf("参数数量不能超过 %d", maxParametersPerRun)}
+	}
+
+	seen := make(map[string]bool)
+	sample := make(map[string]string, len(rb.Parameters))
+	for i := range rb.Parameters {
+		p := &rb.Parameters[i]
+		p.Name = strings.TrimSpace(p.Name)
+		if !parameterNamePattern.MatchString(p.Name) {
+			return &ValidationError{Field: "parameters", Message: fmt.Sprintf("参数名 %q 不合法", p.Name)}
+		}
+		if seen[p.Name] {
+			return &ValidationError{Field: "parameters", Message: fmt.Sprintf("参数 %s 重复", p.Name)}
+		}
+		seen[p.Name] = true
+		if p.Pattern != "" {
+			re, err := regexp.Compile("^(?:" + p.Pattern + ")$")
+			if err != nil {
+				return &ValidationError{Field: "parameters", Message: fmt.Sprintf("参数 %s 的 pattern 不合法: %v", p.Name, err)}
+			}
+			if p.Default != "" && !re.MatchString(p.Default) {
+				return &ValidationError{Field: "parameters", Message: fmt.Sprintf("参数 %s 的默认值不匹配 pattern", p.Name)}
+			}
+		}
+		sample[p.Name] = p.Default
+		if sample[p.Name] == "" {
+			sample[p.Name] = "placeholder"
+		}
+	}
+
+	cleaned := make([]string, 0, len(rb.Namespaces))
+	for _, ns := range rb.Namespaces {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			cleaned = append(cleaned, ns)
+		}
+	}
+	rb.Namespaces = cleaned
+
+	if _, err := renderJob(rb, sample); err != nil {
+		return &ValidationError{Field: "manifest", Message: err.Error()}
+	}
+	return nil
+}
+
+// ResolveValues 合并默认值并校验执行参数
+func ResolveValues(rb *Runbook, values map[string]string) (map[string]string, error) {
+	defined := make(map[string]Parameter, len(rb.Parameters))
+	for _, p := range rb.Parameters {
+		defined[p.Name] = p
+	}
+	for name := range values {
+		if _, ok := defined[name]; !ok {
+			return nil, &ValidationError{Field: name, Message: "未定义的参数"}
+		}
+	}
+
+	resolved := make(map[string]string, len(rb.Parameters))
+	for _, p := range rb.Parameters {
+		v, ok := values[p.Name]
+		if !ok || v == "" {
+			v = p.Default
+		}
+		if v == "" {
+			if p.Required {
+				return nil, &ValidationError{Field: p.Name, Message: "参数必填"}
+			}
+			resolved[p.Name] = ""
+			continue
+		}
+		if len(v) > maxParameterValue {
+			return nil, &ValidationError{Field: p.Name, Message: "参数值过长"}
+		}
+		// 禁止换行等控制字符，避免参数值改变 YAML 结构
+		if strings.IndexFunc(v, unicode.IsControl) >= 0 {
+			return nil, &ValidationError{Field: p.Name, Message: "参数值不能包含换行或控制字符"}
+		}
+		if p.Pattern != "" && !regexp.MustCompile("^(?:"+p.Pattern+")$").MatchString(v) {
+			return nil, &ValidationError{Field: p.Name, Message: fmt.Sprintf("参数值不匹配 %s", p.Pattern)}
+		}
+		resolved[p.Name] = v
+	}
+	return resolved, nil
+}
+
+// Render 按参数渲染 Job，并设置目标命名空间与来源标签
+func Render(rb *Runbook, values map[string]string, namespace string) (*batchv1.Job, error) {
+	job, err := renderJob(rb, values)
+	if err != nil {
+		return nil, &ValidationError{Field: "manifest", Message: err.Error()}
+	}
+
+	job.Namespace = namespace
+	// 每次执行都生成新的 Job 名称，避免与历史执行冲突
+	if job.Name != "" && job.GenerateName == "" {
+		job.GenerateName = job.Name + "-"
+	}
+	job.Name = ""
+	if job.GenerateName == "" {
+		job.GenerateName = rb.Name + "-"
+	}
+	if job.Labels == nil {
+		job.Labels = map[string]string{}
+	}
+	job.Labels[LabelRunbook] = rb.Name
+	return job, nil
+}
+
+func renderJob(rb *Runbook, values map[string]string) (*batchv1.Job, error) {
+	tmpl, err := template.New(rb.Name).Option("missingkey=error").Funcs(templateFuncs).Parse(rb.Manifest)
+	if err != nil {
+		return nil, fmt.Errorf("模板语法错误: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, values); err != nil {
+		return nil, fmt.Errorf("模板渲染失败: %v", err)
+	}
+
+	var job batchv1.Job
+	if err := yaml.UnmarshalStrict(buf.Bytes(), &job); err != nil {
+		return nil, fmt.Errorf("解析 Job 失败: %v", err)
+	}
+	if job.Kind != "" && job.Kind != "Job" {
+		return nil, fmt.Errorf("模板只能定义 Job，当前为 %s", job.Kind)
+	}
+	if len(job.Spec.Template.Spec.Containers) == 0 {
+		return nil, fmt.Errorf("Job 至少需要一个容器")
+	}
+	if job.Spec.Template.Spec.RestartPolicy == "" {
+		job.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyNever
+	}
+	return &job, nil
+}
+
+var roleLevel = map[string]int{
+	"viewer":   1,
+	"operator": 2,
+	"admin":    3,
+}
+
+// CanRun 判断角色能否在指定命名空间执行运行手册
+func CanRun(rb *Runbook, role, namespace string) error {
+	if roleLevel[role] < roleLevel[rb.MinRole] {
+		return fmt.Errorf("执行该运行手册需要 %s 权限", rb.MinRole)
+	}
+	if len(rb.Namespaces) == 0 {
+		return nil
+	}
+	for _, ns := range rb.Namespaces {
+		if ns == namespace {
+			return nil
+		}
+	}
+	return fmt.Errorf("该运行手册不允许在命名空间 %s 执行", namespace)
+}
+
+// List 列出运行手册
+func (s *Service) List() ([]Runbook, error) {
+	return s.repo.List()
+}
+
+// Get 获取运行手册
+func (s *Service) Get(name string) (*Runbook, error) {
+	return s.repo.Get(name)
+}
+
+// Create 创建运行手册
+func (s *Service) Create(rb *Runbook) (*Runbook, error) {
+	if err := Validate(rb); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.Get(rb.Name); err == nil {
+		return nil, &ValidationError{Field: "name", Message: "运行手册已存在"}
+	}
+	if err := s.repo.Create(rb); err != nil {
+		return nil, err
+	}
+	return rb, nil
+}
+
+// Update 更新运行手册
+func (s *Service) Update(rb *Runbook) (*Runbook, error) {
+	if err := Validate(rb); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(rb); err != nil {
+		return nil, err
+	}
+	return s.repo.Get(rb.Name)
+}
+
+// Delete 删除运行手册
+func (s *Service) Delete(name string) error {
+	return s.repo.Delete(name)
+}
+
+// RecordRun 记录一次执行
+func (s *Service) RecordRun(run *Run) error {
+	return s.repo.CreateRun(run)
+}
+
+// GetRun 获取执行记录
+func (s *Service) GetRun(id int64) (*Run, error) {
+	return s.repo.GetRun(id)
+}
+
+// ListRuns 查询执行历史
+func (s *Service) ListRuns(params RunListParams) ([]Run, error) {
+	return s.repo.ListRuns(params)
+}
+
+// SyncRunStatus 根据 Job 最新状态更新执行记录
+func (s *Service) SyncRunStatus(run *Run, job *batchv1.Job) error {
+	status, message, finishedAt := k8s.JobStatus(job)
+	if status == run.Status && message == run.Message {
+		return nil
+	}
+	if err := s.repo.UpdateRunStatus(run.ID, status, message, finishedAt); err != nil {
+		return err
+	}
+	run.Status, run.Message, run.FinishedAt = status, message, finishedAt
+	return nil
+}
+
+// MarkRunMissing Job 已被删除（如 TTL 清理）时结束执行记录
+func (s *Service) MarkRunMissing(run *Run) error {
+	if run.Status != RunStatusRunning {
+		return nil
+	}
+	now := time.Now()
+	if err := s.repo.UpdateRunStatus(run.ID, RunStatusFailed, "Job 已不存在", &now); err != nil {
+		return err
+	}
+	run.Status, run.Message, run.FinishedAt = RunStatusFailed, "Job 已不存在", &now
+	return nil
+}
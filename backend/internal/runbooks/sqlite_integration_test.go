@@ -0,0 +1,161 @@
+package runbooks
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	dbutil "github.com/k8s-dashboard/backend/internal/db"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const cacheFlushManifest = `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: cache-flush
+spec:
+  backoffLimit: 0
+  template:
+    spec:
+      containers:
+      - name: flush
+        image: redis:7
+        command: ["redis-cli", "-h", {{ quote .host }}, "FLUSHDB"]
+`
+
+func TestSQLiteRunbookLifecycle(t *testing.T) {
+	conn, dialect, err := dbutil.Open(dbutil.Config{
+		SQLitePath:          filepath.Join(t.TempDir(), "runbooks.db"),
+		AllowSQLiteFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer conn.Close()
+
+	repo, err := NewRepository(conn, dialect)
+	if err != nil {
+		t.Fatalf("NewRepository failed: %v", err)
+	}
+	service := NewService(repo)
+
+	var validationErr *ValidationError
+	if _, err := service.Create(&Runbook{Name: "bad", Manifest: "kind: Deployment\nspec: {}"}); !errors.As(err, &validationErr) {
+		t.Fatalf("expected validation error for non-Job manifest, got %v", err)
+	}
+
+	created, err := service.Create(&Runbook{
+		Name:       "cache-flush",
+		Manifest:   cacheFlushManifest,
+		Parameters: []Parameter{{Name: "host", Required: true, Pattern: `[a-z0-9.-]+`}},
+		Namespaces: []string{"default"},
+	})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if created.MinRole != "operator" {
+		t.Fatalf("expected default minRole operator, got %s", created.MinRole)
+	}
+
+	rb, err := service.Get("cache-flush")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(rb.Parameters) != 1 || rb.Parameters[0].Name != "host" || len(rb.Namespaces) != 1 {
+		t.Fatalf("unexpected runbook: %+v", rb)
+	}
+
+	if err := CanRun(rb, "viewer", "default"); err == nil {
+		t.Fatalf("expected viewer to be denied")
+	}
+	if err := CanRun(rb, "operator", "prod"); err == nil {
+		t.Fatalf("expected namespace outside allow-list to be denied")
+	}
+	if err := CanRun(rb, "operator", "default"); err != nil {
+		t.Fatalf("expected operator to run in default: %v", err)
+	}
+
+	if _, err := ResolveValues(rb, map[string]string{}); err == nil {
+		t.Fatalf("expected missing required parameter to fail")
+	}
+	if _, err := ResolveValues(rb, map[string]string{"host": "redis\nspec: {}"}); err == nil {
+		t.Fatalf("expected newline in parameter to fail")
+	}
+	if _, err := ResolveValues(rb, map[string]string{"host": "redis", "extra": "x"}); err == nil {
+		t.Fatalf("expected undefined parameter to fail")
+	}
+
+	values, err := ResolveValues(rb, map[string]string{"host": "redis.cache"})
+	if err != nil {
+		t.Fatalf("ResolveValues failed: %v", err)
+	}
+	job, err := Render(rb, values, "default")
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if job.Namespace != "default" || job.Name != "" || job.GenerateName != "cache-flush-" {
+		t.Fatalf("unexpected job metadata: %+v", job.ObjectMeta)
+	}
+	if job.Labels[LabelRunbook] != "cache-flush" {
+		t.Fatalf("expected runbook label, got %v", job.Labels)
+	}
+	if cmd := job.Spec.Template.Spec.Containers[0].Command; len(cmd) != 4 || cmd[2] != "redis.cache" {
+		t.Fatalf("unexpected rendered command: %v", cmd)
+	}
+	if job.Spec.Template.Spec.RestartPolicy != corev1.RestartPolicyNever {
+		t.Fatalf("expected default restartPolicy Never, got %s", job.Spec.Template.Spec.RestartPolicy)
+	}
+
+	run := &Run{
+		RunbookID:   rb.ID,
+		RunbookName: rb.Name,
+		UserID:      7,
+		Username:    "bob",
+		Cluster:     "default",
+		Namespace:   "default",
+		JobName:     "cache-flush-abcde",
+		Parameters:  values,
+		Status:      RunStatusRunning,
+	}
+	if err := service.RecordRun(run); err != nil {
+		t.Fatalf("RecordRun failed: %v", err)
+	}
+
+	finished := metav1.NewTime(time.Now())
+	if err := service.SyncRunStatus(run, &batchv1.Job{Status: batchv1.JobStatus{
+		Conditions: []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue, LastTransitionTime: finished}},
+	}}); err != nil {
+		t.Fatalf("SyncRunStatus failed: %v", err)
+	}
+
+	stored, err := service.GetRun(run.ID)
+	if err != nil {
+		t.Fatalf("GetRun failed: %v", err)
+	}
+	if stored.Status != RunStatusSucceeded || stored.FinishedAt == nil || stored.Parameters["host"] != "redis.cache" {
+		t.Fatalf("unexpected stored run: %+v", stored)
+	}
+
+	runs, err := service.ListRuns(RunListParams{UserID: 8})
+	if err != nil {
+		t.Fatalf("ListRuns failed: %v", err)
+	}
+	if len(runs) != 0 {
+		t.Fatalf("expected no runs for other user, got %d", len(runs))
+	}
+
+	if err := service.Delete("cache-flush"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := service.Get("cache-flush"); !errors.Is(err, ErrRunbookNotFound) {
+		t.Fatalf("expected ErrRunbookNotFound, got %v", err)
+	}
+	runs, err = service.ListRuns(RunListParams{RunbookName: "cache-flush"})
+	if err != nil || len(runs) != 1 {
+		t.Fatalf("expected run history to be kept, got %d (%v)", len(runs), err)
+	}
+}
@@ -0,0 +1,104 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SignatureHeader 携带请求体 HMAC-SHA256 签名的请求头
+const SignatureHeader = "X-Dashboard-Signature"
+
+// Event 推送给外部系统的事件
+type Event struct {
+	Type      string      `json:"type"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+// Client Webhook 推送客户端
+type Client struct {
+	url        string
+	secret     []byte
+	httpClient *http.Client
+}
+
+// NewClient 创建 Webhook 客户端，secret 为空时不签名
+func NewClient(url, secret string) *Client {
+	return &Client{
+		url:    strings.TrimSpace(url),
+		secret: []byte(secret),
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Send 同步推送事件，非 2xx 响应视为失败
+func (c *Client) Send(ctx context.Context, eventType string, data interface{}) error {
+	payload, err := json.Marshal(Event{
+		Type:      eventType,
+		Timestamp: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Dashboard-Event", eventType)
+	if len(c.secret) > 0 {
+		req.Header.Set(SignatureHeader, "sha256="+Sign(c.secret, payload))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Dispatch 异步推送事件，失败时重试并仅记录日志，不阻塞调用方
+func (c *Client) Dispatch(eventType string, data interface{}) {
+	go func() {
+		var err error
+		for attempt := 0; attempt < 3; attempt++ {
+			if attempt > 0 {
+				time.Sleep(time.Duration(attempt) * 2 * time.Second)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			err = c.Send(ctx, eventType, data)
+			cancel()
+			if err == nil {
+				return
+			}
+		}
+		log.Printf("webhook 推送失败 [%s]: %v", eventType, err)
+	}()
+}
+
+// Sign 计算 payload 的 HMAC-SHA256 十六进制签名
+func Sign(secret, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -1,4 +1,4 @@
-import api, { post, get, del } from './client';
+import api, { post, get, put, del, createWebSocket } from './client';
 import type { User } from '../store/auth';
 
 // 登录请求
@@ -10,6 +10,9 @@ interface LoginRequest {
 // 登录响应
 interface LoginResponse {
   token: string;
+  refreshToken: string;
+  expiresAt: string;
+  refreshExpiresAt: string;
   user: User;
   namespaces: string[];
 }
@@ -23,7 +26,8 @@ interface ChangePasswordRequest {
 // 会话信息
 export interface Session {
   id: string;
-  userID: number;
+  userId: number;
+  username?: string;
   ip: string;
   userAgent: string;
   createdAt: string;
@@ -46,6 +50,9 @@ interface ListUsersResponse {
   pageSize: number;
 }
 
+// 命名空间授权级别：read 只读，write 可修改资源，admin 可删除命名空间并管理配额/RBAC
+export type NamespacePermission = 'read' | 'write' | 'admin';
+
 // 创建用户请求
 export interface CreateUserRequest {
   username: string;
@@ -55,6 +62,7 @@ export interface CreateUserRequest {
   role: string;
   allNamespaces: boolean;
   namespaces: string[];
+  namespacePermissions?: Record<string, NamespacePermission>;
 }
 
 // 更新用户请求
@@ -65,6 +73,7 @@ export interface UpdateUserRequest {
   enabled?: boolean;
   allNamespaces?: boolean;
   namespaces?: string[];
+  namespacePermissions?: Record<string, NamespacePermission>;
 }
 
 // 审批请求
@@ -81,10 +90,86 @@ export interface ApprovalRequest {
   reviewerID?: number;
   reviewerName?: string;
   reviewComment?: string;
+  requestData?: string;
+  preview?: ApprovalPreview;
+  cluster?: string;
+  // 批准后自动执行的结果
+  executionStatus?: 'succeeded' | 'failed';
+  executionResult?: string;
+  executedAt?: string;
+  // 多人审批：需要的批准人数与已批准的审批人
+  requiredApprovals: number;
+  votes?: ApprovalVote[];
   createdAt: string;
   reviewedAt?: string;
 }
 
+export interface ApprovalVote {
+  approverId: number;
+  approverName: string;
+  comment?: string;
+  createdAt: string;
+}
+
+// 审批变更预览（提交时由服务端计算）
+export interface ApprovalPreview {
+  kind: string;
+  summary: string;
+  currentReplicas?: number;
+  requestedReplicas?: number;
+  imageChanges?: Array<{ container: string; current: string; requested: string }>;
+  affectedPods: number;
+  warnings?: string[];
+  computedAt: string;
+}
+
+// 密码策略
+export interface PasswordPolicy {
+  minLength: number;
+  requireUpper: boolean;
+  requireLower: boolean;
+  requireDigit: boolean;
+  requireSymbol: boolean;
+  maxAgeDays: number;
+}
+
+// 个人 API 令牌
+export interface APIToken {
+  id: number;
+  userId: number;
+  name: string;
+  prefix: string;
+  role: string;
+  namespaces?: string[];
+  expiresAt: string;
+  lastUsedAt?: string;
+  createdAt: string;
+}
+
+export interface CreateAPITokenRequest {
+  name: string;
+  role?: string;
+  namespaces?: string[];
+  expiresInDays?: number;
+}
+
+// 权限预检
+export interface AccessCheck {
+  verb: string;
+  group?: string;
+  resource: string;
+  subresource?: string;
+  namespace?: string;
+  name?: string;
+}
+
+export interface AccessCheckResult extends AccessCheck {
+  allowed: boolean;
+  dashboardAllowed: boolean;
+  clusterAllowed: boolean;
+  reason?: string;
+}
+
 // 审批规则
 export interface ApprovalRule {
   id: number;
@@ -92,6 +177,7 @@ export interface ApprovalRule {
   resource: string;
   minRole: string;
   enabled: boolean;
+  requiredApprovals: number;
   createdAt: string;
   updatedAt: string;
 }
@@ -105,9 +191,9 @@ export const authApi = {
     return response.data;
   },
 
-  // 登出
+  // 登出（同时撤销刷新令牌，访问令牌过期时也能注销会话）
   logout: async (): Promise<void> => {
-    await post('/auth/logout');
+    await post('/auth/logout', { refreshToken: localStorage.getItem('refreshToken') || undefined });
   },
 
   // 获取当前用户
@@ -120,6 +206,11 @@ export const authApi = {
     await post('/auth/password', data);
   },
 
+  // 获取密码策略
+  getPasswordPolicy: async (): Promise<PasswordPolicy> => {
+    return get('/auth/password-policy');
+  },
+
   // 获取用户会话
   getSessions: async (): Promise<{ items: Session[] }> => {
     return get('/auth/sessions');
@@ -129,6 +220,26 @@ export const authApi = {
   revokeSession: async (sessionId: string): Promise<void> => {
     await del(`/auth/sessions/${sessionId}`);
   },
+
+  // 列出个人 API 令牌
+  listTokens: async (): Promise<{ items: APIToken[] }> => {
+    return get('/auth/tokens');
+  },
+
+  // 签发 API 令牌，明文 token 仅返回一次
+  createToken: async (data: CreateAPITokenRequest): Promise<{ token: string; item: APIToken }> => {
+    return post('/auth/tokens', data);
+  },
+
+  // 撤销 API 令牌
+  revokeToken: async (id: number): Promise<void> => {
+    await del(`/auth/tokens/${id}`);
+  },
+
+  // 批量预检当前集群上的操作权限
+  canI: async (checks: AccessCheck[]): Promise<{ results: AccessCheckResult[] }> => {
+    return post('/auth/can-i', { checks });
+  },
 };
 
 // ========== 用户管理 API (管理员) ==========
@@ -164,8 +275,47 @@ export const userApi = {
   resetPassword: async (id: number, newPassword: string): Promise<void> => {
     await post(`/admin/users/${id}/reset-password`, { newPassword });
   },
+
+  // 导出（CSV 时返回文件内容）
+  exportUsers: async (format: 'json' | 'csv' = 'json'): Promise<Blob> => {
+    const response = await api.get('/admin/users/export', { params: { format }, responseType: 'blob' });
+    return response.data;
+  },
+
+  // 批量导入，dryRun 时只返回校验报告
+  importUsers: async (file: File, dryRun = false): Promise<UserImportResult> => {
+    const form = new FormData();
+    form.append('file', file);
+    const response = await api.post<UserImportResult>('/admin/users/import', form, { params: { dryRun } });
+    return response.data;
+  },
+
+  // 所有用户的活跃会话，可按用户过滤
+  listSessions: async (userId?: number): Promise<{ items: Session[]; total: number }> => {
+    return get('/admin/sessions', userId ? { userId } : undefined);
+  },
+
+  // 撤销任意会话
+  revokeSession: async (sessionId: string): Promise<void> => {
+    await del(`/admin/sessions/${sessionId}`);
+  },
+
+  // 强制用户下线
+  forceLogout: async (id: number): Promise<{ revoked: number }> => {
+    return del(`/admin/users/${id}/sessions`);
+  },
 };
 
+// 批量导入报告
+export interface UserImportResult {
+  dryRun: boolean;
+  applied: boolean;
+  created: number;
+  updated: number;
+  failed: number;
+  items: Array<{ row: number; username: string; action: 'create' | 'update'; error?: string }>;
+}
+
 // ========== 审批 API ==========
 
 export const approvalApi = {
@@ -183,14 +333,26 @@ export const approvalApi = {
     return get(`/approvals/${id}`);
   },
 
+  // 提交审批，requestData: scale 为 { replicas }，update 为 { images: { 容器名: 镜像 } }
+  create: async (data: {
+    action: string;
+    resource: string;
+    resourceName: string;
+    namespace?: string;
+    reason?: string;
+    requestData?: Record<string, unknown>;
+  }): Promise<ApprovalRequest> => {
+    return post('/approvals', data);
+  },
+
   // 待审批数量
   getPendingCount: async (): Promise<{ count: number }> => {
     return get('/approvals/pending/count');
   },
 
   // 批准
-  approve: async (id: number, comment?: string): Promise<void> => {
-    await post(`/approvals/${id}/approve`, { comment });
+  approve: async (id: number, comment?: string): Promise<{ message: string; approval: ApprovalRequest }> => {
+    return post(`/approvals/${id}/approve`, { comment });
   },
 
   // 拒绝
@@ -206,8 +368,90 @@ export const approvalApi = {
   // 更新规则
   updateRule: async (
     id: number,
-    data: { minRole?: string; enabled?: boolean }
+    data: { minRole?: string; enabled?: boolean; requiredApprovals?: number }
   ): Promise<void> => {
     await api.put(`/admin/approval-rules/${id}`, data);
   },
 };
+
+// 通知渠道（admin）
+export interface NotificationChannelConfig {
+  url?: string;
+  secret?: string;
+  smtpHost?: string;
+  smtpPort?: number;
+  smtpUsername?: string;
+  smtpPassword?: string;
+  from?: string;
+  to?: string[];
+}
+
+export interface NotificationChannel {
+  id: number;
+  name: string;
+  type: 'slack' | 'dingtalk' | 'wecom' | 'email' | 'webhook';
+  enabled: boolean;
+  config: NotificationChannelConfig;
+  events: string[];
+  template?: string;
+  createdBy: string;
+  createdAt: string;
+  updatedAt: string;
+  lastSentAt?: string;
+  lastError?: string;
+}
+
+export type NotificationChannelInput = Pick<NotificationChannel, 'name' | 'type' | 'config' | 'events' | 'template'> & {
+  enabled?: boolean;
+};
+
+export const notificationChannelApi = {
+  list: async (): Promise<{ items: NotificationChannel[]; total: number }> => {
+    return get('/admin/notification-channels');
+  },
+
+  create: async (data: NotificationChannelInput): Promise<NotificationChannel> => {
+    return post('/admin/notification-channels', data);
+  },
+
+  // 密钥保持 '******' 时服务端保留原值
+  update: async (id: number, data: NotificationChannelInput): Promise<NotificationChannel> => {
+    return put(`/admin/notification-channels/${id}`, data);
+  },
+
+  delete: async (id: number): Promise<void> => {
+    await del(`/admin/notification-channels/${id}`);
+  },
+
+  test: async (id: number): Promise<{ message: string }> => {
+    return post(`/admin/notification-channels/${id}/test`);
+  },
+};
+
+// 实时通知推送消息（/ws/notifications）
+export interface NotificationStreamMessage {
+  type: 'pending_approvals' | 'notification' | 'session_expiring' | 'session_expired';
+  count?: number;
+  notification?: {
+    kind: 'approval.created' | 'approval.approved' | 'approval.rejected' | 'audit.anomaly' | 'alert.firing' | 'alert.resolved';
+    title: string;
+    message?: string;
+    approvalId?: number;
+    cluster?: string;
+    fingerprint?: string;
+    createdAt: string;
+  };
+  expiresAt?: string;
+}
+
+export const notificationApi = {
+  // 实时通知流：待审批数量（admin）、审批结果与会话过期提醒，替代轮询待审批数量
+  stream: async () => {
+    const cluster = localStorage.getItem('currentCluster') || 'default';
+    const response = await api.post<{ ticket: string }>('/ws/tickets', {
+      action: 'notifications',
+      cluster,
+    });
+    return createWebSocket('/ws/notifications', { ticket: response.data.ticket });
+  },
+};
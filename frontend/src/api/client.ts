@@ -1,10 +1,11 @@
 import axios, { type AxiosInstance, type AxiosError, type InternalAxiosRequestConfig } from 'axios';
 import type { ApiError } from '../types';
 import { useAppStore } from '../store';
+import { useAuthStore } from '../store/auth';
 
 // 创建 axios 实例
 const api: AxiosInstance = axios.create({
-  baseURL: '/api/v1',
+  baseURL: '/api/v2',
   timeout: 30000,
   headers: {
     'Content-Type': 'application/json',
@@ -33,6 +34,28 @@ api.interceptors.request.use(
   }
 );
 
+// 刷新中的请求，多个请求同时遇到访问令牌过期时共用一次刷新
+let refreshing: Promise<string> | null = null;
+
+// 使用刷新令牌换取新的访问令牌（刷新令牌同时轮换）
+function refreshAccessToken(): Promise<string> {
+  if (!refreshing) {
+    const refreshToken = localStorage.getItem('refreshToken');
+    refreshing = (refreshToken
+      ? axios
+          .post<{ token: string; refreshToken: string }>('/api/v2/auth/refresh', { refreshToken })
+          .then(({ data }) => {
+            useAuthStore.getState().setToken(data.token, data.refreshToken);
+            return data.token;
+          })
+      : Promise.reject(new Error('no refresh token'))
+    ).finally(() => {
+      refreshing = null;
+    });
+  }
+  return refreshing;
+}
+
 // 响应拦截器
 api.interceptors.response.use(
   (response) => {
@@ -41,7 +64,21 @@ api.interceptors.response.use(
     }
     return response;
   },
-  (error: AxiosError<ApiError>) => {
+  async (error: AxiosError<ApiError>) => {
+    // 访问令牌过期：刷新后重试原请求，每个请求只重试一次
+    const original = error.config as (InternalAxiosRequestConfig & { _retried?: boolean }) | undefined;
+    const errorCode = (error.response?.data as { code?: string } | undefined)?.code;
+    if (error.response?.status === 401 && errorCode === 'TOKEN_EXPIRED' && original && !original._retried) {
+      original._retried = true;
+      try {
+        const token = await refreshAccessToken();
+        original.headers.Authorization = `Bearer ${token}`;
+        return api(original);
+      } catch {
+        // 刷新失败，按未授权处理
+      }
+    }
+
     if (error.response) {
       const { status, data } = error.response;
       const clusterError = data as unknown as {
@@ -60,10 +97,17 @@ api.interceptors.response.use(
       switch (status) {
         case 401:
           // 未授权，跳转登录
-          localStorage.removeItem('token');
+          useAuthStore.getState().clearAuth();
           window.location.href = '/login';
           break;
         case 403:
+          // 需要修改密码（管理员重置、默认密码或密码过期）
+          if (clusterError?.code === 'PASSWORD_CHANGE_REQUIRED') {
+            if (window.location.pathname !== '/settings') {
+              window.location.href = '/settings';
+            }
+            break;
+          }
           // 权限不足
           console.error('Permission denied:', data?.message);
           break;
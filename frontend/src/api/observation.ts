@@ -1,4 +1,4 @@
-import { get } from './client';
+import { get, put, del } from './client';
 
 // 类型定义
 
@@ -19,6 +19,30 @@ export interface PodAnomaly {
   restartCount: number;
   duration: string;
   nodeName?: string;
+  // 触发异常的阈值，ruleId 为空表示默认阈值
+  threshold?: TriggeredThreshold;
+}
+
+// 异常检测阈值
+export interface AnomalyThresholds {
+  pendingMinutes: number;
+  restartCount: number;
+  oomWindowMinutes: number;
+}
+
+// 异常检测阈值规则，cluster/namespace 为空表示全部
+export interface ThresholdRule extends AnomalyThresholds {
+  id: number;
+  cluster: string;
+  namespace: string;
+  updatedBy: string;
+  updatedAt: string;
+}
+
+export interface TriggeredThreshold {
+  name: keyof AnomalyThresholds;
+  value: number;
+  ruleId?: number;
 }
 
 // 节点异常
@@ -102,8 +126,8 @@ export type ResourceType = 'cpu' | 'memory';
 // API 模块
 export const observationApi = {
   // 获取异常状态汇总
-  getSummary: () =>
-    get<ObservationSummary>('/observation/summary'),
+  getSummary: (params?: { namespace?: string }) =>
+    get<ObservationSummary>('/observation/summary', params),
 
   // 获取异常 Pod 列表
   getPodAnomalies: (params?: { namespace?: string }) =>
@@ -126,8 +150,18 @@ export const observationApi = {
     get<AlertTrend>('/observation/trends/alerts', range ? { range } : undefined),
 
   // 获取 Pod 重启趋势
-  getRestartTrend: (range?: TimeRange) =>
-    get<RestartTrend>('/observation/trends/restarts', range ? { range } : undefined),
+  getRestartTrend: (range?: TimeRange, namespace?: string) =>
+    get<RestartTrend>('/observation/trends/restarts', { ...(range ? { range } : {}), ...(namespace ? { namespace } : {}) }),
+
+  // 异常检测阈值规则（admin）
+  getThresholdRules: () =>
+    get<ListResponse<ThresholdRule> & { defaults: AnomalyThresholds }>('/admin/observation/thresholds'),
+
+  saveThresholdRule: (data: { cluster?: string; namespace?: string } & AnomalyThresholds) =>
+    put<ThresholdRule>('/admin/observation/thresholds', data),
+
+  deleteThresholdRule: (id: number) =>
+    del<void>(`/admin/observation/thresholds/${id}`),
 };
 
 export default observationApi;
@@ -4,6 +4,7 @@ export const queryKeys = {
   overview: ['overview'] as const,
   alertSummary: ['alertSummary'] as const,
   alerts: ['alerts'] as const,
+  search: (cluster: string, q: string) => ['search', cluster, q] as const,
   events: (namespace: string) => ['events', namespace] as const,
   pods: (namespace: string) => ['pods', namespace] as const,
   podsMetrics: ['pods-metrics'] as const,
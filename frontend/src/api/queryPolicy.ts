@@ -5,6 +5,7 @@ const CLUSTER_SCOPED_ROOT_KEYS = [
   'overview',
   'alertSummary',
   'alerts',
+  'search',
   'events',
   'clusters',
   'namespaces',
@@ -1,4 +1,4 @@
-import { get, post, put, del, putYaml } from './client';
+import api, { get, post, put, del, putYaml, createWebSocket } from './client';
 import type {
   Pod,
   Deployment,
@@ -9,12 +9,14 @@ import type {
   ReplicaSet,
   Service,
   ServiceInput,
+  ServicePort,
   Ingress,
   IngressInput,
   ConfigMap,
   ConfigMapInput,
   Secret,
   SecretInput,
+  SecretReveal,
   PersistentVolume,
   PersistentVolumeClaim,
   StorageClass,
@@ -32,17 +34,69 @@ import type {
 import type {
   ListResponse,
   ClusterOverview,
+  OverviewIssuesResponse,
+  CronJobRun,
   NodeMetrics,
   PodMetrics,
+  PodContainerMetrics,
+  PodHistory,
+  NodeHistory,
+  PodNetworkMetrics,
+  NamespaceNetworkMetrics,
+  MetricsQueryRequest,
+  TopConsumersResponse,
+  MetricsQueryResponse,
   ListParams,
+  LogSearchParams,
+  EventStreamFilter,
+  EventRecord,
+  NodeRebalanceSuggestions,
+  RebalanceResult,
+  EventHistoryParams,
+  Runbook,
+  RunbookRun,
   ScaleRequest,
   RollbackRequest,
   AuditLog,
+  AuditLogParams,
+  AuditExport,
+  AuditStorageStats,
+  AuditAnomaly,
+  TerminalSession,
+  PacketCapture,
+  PacketCaptureRequest,
   Alert,
   AlertSummary,
+  AlertmanagerStatus,
+  AlertmanagerRoute,
+  AlertmanagerReceiver,
   AlertAcknowledgement,
   Silence,
+  SilenceInput,
+  AlertRoute,
+  AlertRouteInput,
+  AlertRouteChannel,
   ClusterInfo,
+  ClusterEndpoints,
+  ClusterCredentials,
+  KubeconfigContext,
+  ConfigUsage,
+  ConfigMapRolloutResponse,
+  VolumeMetrics,
+  GPUMetrics,
+  ResourceRecommendationReport,
+  CostReport,
+  RuleGroupsResponse,
+  RuleResource,
+  CapacityReport,
+  NodePoolSummary,
+  NamespaceCleanupKind,
+  NamespaceCleanupResult,
+  SearchParams,
+  SearchResponse,
+  NamespaceTopology,
+  BatchOperation,
+  BatchResponse,
 } from '../types/api';
 
 // 构建查询参数
@@ -57,9 +111,23 @@ function buildParams(params?: ListParams): Record<string, unknown> {
   return result;
 }
 
+// ============ 批量操作 ============
+export const batchApi = {
+  // 多选删除 Pod/工作负载、滚动重启与扩缩容，逐项返回结果；命中审批规则的项转为审批请求
+  execute: (operations: BatchOperation[]) =>
+    post<BatchResponse>('/batch', { operations }),
+};
+
 // ============ 集群概览 ============
 export const overviewApi = {
   getOverview: () => get<ClusterOverview>('/overview'),
+  getIssues: () => get<OverviewIssuesResponse>('/overview/issues'),
+};
+
+// ============ 全局搜索 ============
+export const searchApi = {
+  // 按名称、标签、注解搜索资源，q 中以空格分隔的关键字需全部命中
+  search: (params: SearchParams) => get<SearchResponse>('/search', { ...params }),
 };
 
 // ============ Namespace ============
@@ -70,6 +138,20 @@ export const namespaceApi = {
   create: (data: Namespace) => post<Namespace>('/namespaces', data),
   update: (name: string, data: Namespace) => put<Namespace>(`/namespaces/${name}`, data),
   delete: (name: string) => del<void>(`/namespaces/${name}`),
+  // 导出命名空间清单（工作负载、Service、ConfigMap、Ingress）为 zip
+  export: async (name: string, format: 'yaml' | 'json' = 'yaml'): Promise<Blob> => {
+    const response = await api.get(`/namespaces/${name}/export`, {
+      params: { format },
+      responseType: 'blob',
+    });
+    return response.data;
+  },
+  // 清理已结束的资源；dryRun 只返回将被删除的资源，命中审批规则时返回 202
+  cleanup: (name: string, kinds: NamespaceCleanupKind[], dryRun = false) =>
+    post<NamespaceCleanupResult>(`/namespaces/${name}/cleanup`, { kinds, dryRun }),
+  // 对象关系图；all=true 时包含副本数为 0 的历史 ReplicaSet
+  topology: (name: string, all = false) =>
+    get<NamespaceTopology>(`/namespaces/${name}/topology`, all ? { all: true } : undefined),
 };
 
 // ============ Pod ============
@@ -90,8 +172,106 @@ export const podApi = {
     get<ListResponse<Event>>(`/namespaces/${namespace}/pods/${name}/events`),
   getLogs: (namespace: string, name: string, container: string, tailLines: number = 500) =>
     get<string>(`/namespaces/${namespace}/pods/${name}/logs`, { container, tailLines }),
+  searchLogs: (namespace: string, name: string, params: LogSearchParams) =>
+    get<string>(`/namespaces/${namespace}/pods/${name}/logs`, { ...params }),
+  // 下载日志：默认单容器 gzip，format=zip 时打包全部容器
+  downloadLogs: async (
+    namespace: string,
+    name: string,
+    params: LogSearchParams & { format?: 'gzip' | 'zip' } = {}
+  ): Promise<Blob> => {
+    const response = await api.get(`/namespaces/${namespace}/pods/${name}/logs/download`, {
+      params,
+      responseType: 'blob',
+    });
+    return response.data;
+  },
+  // 抓包为异步任务，返回的记录状态为 running，可通过 packetCaptureApi 轮询
+  capture: (namespace: string, name: string, data: PacketCaptureRequest) =>
+    post<PacketCapture>(`/namespaces/${namespace}/pods/${name}/captures`, data),
   listAllMetrics: () =>
     get<ListResponse<PodMetrics>>('/metrics/pods'),
+  containerMetrics: (namespace: string, name: string) =>
+    get<PodContainerMetrics>(`/metrics/pods/${namespace}/${name}/containers`),
+  history: (namespace: string, name: string, params?: { duration?: string; step?: string }) =>
+    get<PodHistory>(`/metrics/pods/${namespace}/${name}/history`, params),
+};
+
+// ============ PVC 卷用量 ============
+export const volumeMetricsApi = {
+  list: (namespace?: string) =>
+    get<ListResponse<VolumeMetrics>>('/metrics/pvc', namespace ? { namespace } : undefined),
+};
+
+// ============ 网络流量 ============
+export const networkMetricsApi = {
+  // 按收发合计降序，limit 只返回前 N 个
+  pods: (params?: { namespace?: string; limit?: number }) =>
+    get<ListResponse<PodNetworkMetrics>>('/metrics/network/pods', params),
+  namespaces: (namespace?: string) =>
+    get<ListResponse<NamespaceNetworkMetrics>>('/metrics/network/namespaces', namespace ? { namespace } : undefined),
+};
+
+// ============ 资源占用排行 ============
+export const topConsumersApi = {
+  get: (params: { resource?: 'cpu' | 'memory'; scope?: 'pod' | 'namespace' | 'node'; k?: number } = {}) =>
+    get<TopConsumersResponse>('/metrics/top', params),
+};
+
+// ============ 自定义 PromQL 查询 ============
+export const metricsQueryApi = {
+  query: (data: MetricsQueryRequest) =>
+    post<MetricsQueryResponse>('/metrics/query', data),
+};
+
+// ============ GPU 指标 ============
+export const gpuMetricsApi = {
+  list: (namespace?: string) =>
+    get<ListResponse<GPUMetrics> & { available: boolean }>('/metrics/gpu', namespace ? { namespace } : undefined),
+};
+
+// ============ 容量规划 ============
+export const capacityApi = {
+  get: (lookback?: string) =>
+    get<CapacityReport>('/capacity', lookback ? { lookback } : undefined),
+};
+
+// ============ 资源建议 ============
+export const recommendationApi = {
+  resources: (params?: { namespace?: string; window?: string }) =>
+    get<ResourceRecommendationReport>('/recommendations/resources', params),
+};
+
+// ============ 告警/记录规则 ============
+export const ruleApi = {
+  groups: (params?: { type?: 'alert' | 'record'; health?: 'ok' | 'err' | 'unknown' }) =>
+    get<RuleGroupsResponse>('/rules', params),
+  resources: (namespace?: string) =>
+    get<ListResponse<RuleResource>>('/rules/resources', namespace ? { namespace } : undefined),
+  getResource: (resource: string, namespace: string, name: string) =>
+    get<RuleResource>(`/rules/resources/${resource}/${namespace}/${name}`),
+  // 仅 admin；resourceVersion 不一致时返回 409
+  updateResource: (resource: string, namespace: string, name: string, data: { groups: Record<string, unknown>[]; resourceVersion?: string }) =>
+    put<RuleResource>(`/rules/resources/${resource}/${namespace}/${name}`, data),
+};
+
+// ============ 费用估算 ============
+export const costApi = {
+  namespaces: (params?: { window?: string }) =>
+    get<CostReport>('/cost/namespaces', params),
+  workloads: (params?: { namespace?: string; window?: string }) =>
+    get<CostReport>('/cost/workloads', params),
+};
+
+// ============ Packet Capture ============
+export const packetCaptureApi = {
+  list: (params?: { page?: number; pageSize?: number; user?: string; namespace?: string; pod?: string }) =>
+    get<{ items: PacketCapture[]; total: number }>('/packet-captures', params as Record<string, unknown>),
+  get: (id: number) => get<PacketCapture>(`/packet-captures/${id}`),
+  download: async (id: number): Promise<Blob> => {
+    const response = await api.get(`/packet-captures/${id}/download`, { responseType: 'blob' });
+    return response.data;
+  },
 };
 
 // ============ Deployment ============
@@ -122,6 +302,9 @@ export const deploymentApi = {
     get<{ items: Array<{ name: string; revision: string; replicas: number; ready: number; created: string; image: string }> }>(`/namespaces/${namespace}/deployments/${name}/revisions`),
   getPods: (namespace: string, name: string) =>
     get<ListResponse<Pod>>(`/namespaces/${namespace}/deployments/${name}/pods`),
+  // 聚合所有 Pod 日志，每行以 [pod] 开头，按时间排序
+  getLogs: (namespace: string, name: string, params: LogSearchParams = {}) =>
+    get<string>(`/namespaces/${namespace}/deployments/${name}/logs`, { ...params }),
   getEvents: (namespace: string, name: string) =>
     get<ListResponse<Event>>(`/namespaces/${namespace}/deployments/${name}/events`),
   updateStrategy: (namespace: string, name: string, strategy: { type: string; maxUnavailable?: string; maxSurge?: string }) =>
@@ -214,6 +397,8 @@ export const jobApi = {
     get<string>(`/namespaces/${namespace}/jobs/${name}/yaml`),
   getPods: (namespace: string, name: string) =>
     get<ListResponse<Pod>>(`/namespaces/${namespace}/jobs/${name}/pods`),
+  rerun: (namespace: string, name: string) =>
+    post<Job>(`/namespaces/${namespace}/jobs/${name}/rerun`),
 };
 
 // ============ CronJob ============
@@ -230,10 +415,15 @@ export const cronJobApi = {
     put<CronJob>(`/namespaces/${namespace}/cronjobs/${name}`, data),
   delete: (namespace: string, name: string) =>
     del<void>(`/namespaces/${namespace}/cronjobs/${name}`),
-  trigger: (namespace: string, name: string) =>
-    post<Job>(`/namespaces/${namespace}/cronjobs/${name}/trigger`),
+  // 已有未结束的手动 Job 时返回 409，force 为 true 时仍然创建
+  trigger: (namespace: string, name: string, force = false) =>
+    post<Job>(`/namespaces/${namespace}/cronjobs/${name}/trigger${force ? '?force=true' : ''}`),
+  runs: (namespace: string, name: string, limit?: number) =>
+    get<ListResponse<CronJobRun>>(`/namespaces/${namespace}/cronjobs/${name}/runs`, { limit }),
   suspend: (namespace: string, name: string, suspend: boolean) =>
     post<void>(`/namespaces/${namespace}/cronjobs/${name}/suspend`, { suspend }),
+  resume: (namespace: string, name: string) =>
+    post<void>(`/namespaces/${namespace}/cronjobs/${name}/resume`),
   getYaml: (namespace: string, name: string) =>
     get<string>(`/namespaces/${namespace}/cronjobs/${name}/yaml`),
 };
@@ -272,6 +462,9 @@ export const serviceApi = {
     putYaml<Service>(`/namespaces/${namespace}/services/${name}/yaml`, yaml),
   getEndpoints: (namespace: string, name: string) =>
     get<Pod[]>(`/namespaces/${namespace}/services/${name}/endpoints`),
+  // 按名称（无名称时按 port/protocol）新增或替换端口，remove 按名称或 "80/TCP" 删除
+  updatePorts: (namespace: string, name: string, ports: ServicePort[], remove: string[] = []) =>
+    post<Service>(`/namespaces/${namespace}/services/${name}/ports`, { ports, remove }),
 };
 
 // ============ Ingress ============
@@ -304,14 +497,19 @@ export const configMapApi = {
     get<ConfigMap>(`/namespaces/${namespace}/configmaps/${name}`),
   create: (namespace: string, data: ConfigMapInput) =>
     post<ConfigMap>(`/namespaces/${namespace}/configmaps`, data),
-  update: (namespace: string, name: string, data: ConfigMap) =>
-    put<ConfigMap>(`/namespaces/${namespace}/configmaps/${name}`, data),
-  delete: (namespace: string, name: string) =>
-    del<void>(`/namespaces/${namespace}/configmaps/${name}`),
+  // rollout 为 true 时更新后滚动重启引用该 ConfigMap 的 Deployment
+  update: (namespace: string, name: string, data: ConfigMap, rollout = false) =>
+    put<ConfigMap | ConfigMapRolloutResponse>(`/namespaces/${namespace}/configmaps/${name}${rollout ? '?rollout=true' : ''}`, data),
+  // 仍被工作负载引用时返回 409，force 为 true 时仍然删除
+  delete: (namespace: string, name: string, force = false) =>
+    del<void>(`/namespaces/${namespace}/configmaps/${name}${force ? '?force=true' : ''}`),
+  // 引用该 ConfigMap 的工作负载
+  usage: (namespace: string, name: string) =>
+    get<ConfigUsage>(`/namespaces/${namespace}/configmaps/${name}/usage`),
   getYaml: (namespace: string, name: string) =>
     get<string>(`/namespaces/${namespace}/configmaps/${name}/yaml`),
-  updateYaml: (namespace: string, name: string, yaml: string) =>
-    putYaml<ConfigMap>(`/namespaces/${namespace}/configmaps/${name}/yaml`, yaml),
+  updateYaml: (namespace: string, name: string, yaml: string, rollout = false) =>
+    putYaml<ConfigMap | ConfigMapRolloutResponse>(`/namespaces/${namespace}/configmaps/${name}/yaml${rollout ? '?rollout=true' : ''}`, yaml),
 };
 
 // ============ Secret ============
@@ -326,8 +524,15 @@ export const secretApi = {
     post<Secret>(`/namespaces/${namespace}/secrets`, data),
   update: (namespace: string, name: string, data: Secret) =>
     put<Secret>(`/namespaces/${namespace}/secrets/${name}`, data),
-  delete: (namespace: string, name: string) =>
-    del<void>(`/namespaces/${namespace}/secrets/${name}`),
+  // 仍被工作负载引用时返回 409，force 为 true 时仍然删除
+  delete: (namespace: string, name: string, force = false) =>
+    del<void>(`/namespaces/${namespace}/secrets/${name}${force ? '?force=true' : ''}`),
+  // 引用该 Secret 的工作负载
+  usage: (namespace: string, name: string) =>
+    get<ConfigUsage>(`/namespaces/${namespace}/secrets/${name}/usage`),
+  // 查看明文（operator，记录审计），key 为空时返回全部键
+  reveal: (namespace: string, name: string, key?: string) =>
+    get<SecretReveal>(`/namespaces/${namespace}/secrets/${name}/reveal`, key ? { key } : undefined),
   getYaml: (namespace: string, name: string) =>
     get<string>(`/namespaces/${namespace}/secrets/${name}/yaml`),
   updateYaml: (namespace: string, name: string, yaml: string) =>
@@ -364,6 +569,9 @@ export const pvcApi = {
     put<PersistentVolumeClaim>(`/namespaces/${namespace}/persistentvolumeclaims/${name}`, data),
   delete: (namespace: string, name: string) =>
     del<void>(`/namespaces/${namespace}/persistentvolumeclaims/${name}`),
+  // 扩容到 storage（如 20Gi），需 StorageClass 开启 allowVolumeExpansion
+  expand: (namespace: string, name: string, storage: string) =>
+    post<PersistentVolumeClaim>(`/namespaces/${namespace}/persistentvolumeclaims/${name}/expand`, { storage }),
   getYaml: (namespace: string, name: string) =>
     get<string>(`/namespaces/${namespace}/persistentvolumeclaims/${name}/yaml`),
 };
@@ -378,18 +586,26 @@ export const storageClassApi = {
     post<StorageClass>('/storageclasses', data),
   delete: (name: string) =>
     del<void>(`/storageclasses/${name}`),
+  // 设为默认 StorageClass，同时清除其他类的默认标记（admin）
+  setDefault: (name: string) =>
+    post<{ message: string; default: string; unset: string[] }>(`/storageclasses/${name}/set-default`),
   getYaml: (name: string) =>
     get<string>(`/storageclasses/${name}/yaml`),
 };
 
 // ============ Node ============
 export const nodeApi = {
+  // 按节点池分组，label 为空时使用服务端 NODE_POOL_LABEL
+  listPools: (label?: string) =>
+    get<ListResponse<NodePoolSummary> & { label: string }>('/nodepools', label ? { label } : undefined),
   list: (params?: ListParams) =>
     get<ListResponse<Node>>('/nodes', buildParams(params)),
   get: (name: string) =>
     get<Node>(`/nodes/${name}`),
   getMetrics: (name: string) =>
     get<NodeMetrics>(`/nodes/${name}/metrics`),
+  history: (name: string, params?: { duration?: string; step?: string }) =>
+    get<NodeHistory>(`/metrics/nodes/${name}/history`, params),
   getYaml: (name: string) =>
     get<string>(`/nodes/${name}/yaml`),
   cordon: (name: string) =>
@@ -406,6 +622,11 @@ export const nodeApi = {
     get<ListResponse<Pod>>(`/nodes/${name}/pods`),
   getEvents: (name: string) =>
     get<ListResponse<Event>>(`/nodes/${name}/events`),
+  getRebalanceSuggestions: (name: string) =>
+    get<NodeRebalanceSuggestions>(`/nodes/${name}/rebalance-suggestions`),
+  // 滚动重启选中的 Deployment，使 Pod 有机会调度到该节点
+  rebalance: (name: string, deployments: Array<{ namespace: string; name: string }>) =>
+    post<{ node: string; restarted: number; results: RebalanceResult[] }>(`/nodes/${name}/rebalance`, { deployments }),
 };
 
 // ============ ResourceQuota ============
@@ -442,6 +663,24 @@ export const eventApi = {
     get<ListResponse<Event>>(`/namespaces/${namespace}/events`, buildParams(params)),
   listAll: (params?: ListParams) =>
     get<ListResponse<Event>>('/events', buildParams(params)),
+  // 历史事件（持久化存储，不受 1 小时过期限制）
+  history: (params: EventHistoryParams = {}) =>
+    get<{ items: EventRecord[]; total: number }>('/events/history', { ...params }),
+  // 实时事件流：先申请一次性票据，再建立 WebSocket，消息格式见 EventStreamMessage
+  stream: async (filter: EventStreamFilter = {}) => {
+    const cluster = localStorage.getItem('currentCluster') || 'default';
+    const response = await api.post<{ ticket: string }>('/ws/tickets', {
+      action: 'events',
+      namespace: filter.namespace,
+      cluster,
+    });
+    return createWebSocket('/ws/events', {
+      ticket: response.data.ticket,
+      type: filter.type,
+      reason: filter.reason,
+      kind: filter.kind,
+    });
+  },
 };
 
 // ============ RBAC ============
@@ -526,12 +765,46 @@ export const auditApi = {
     get<{ items: AuditLog[]; total: number; page: number; pages: number }>('/audit', params as Record<string, unknown>),
   getStats: (duration?: string) =>
     get<{ total: number; byAction: Record<string, number>; byResource: Record<string, number>; byUser: Record<string, number> }>('/audit/stats', duration ? { duration } : {}),
+  listTerminalSessions: (params?: { page?: number; pageSize?: number; user?: string; namespace?: string; pod?: string }) =>
+    get<{ items: TerminalSession[]; total: number }>('/audit/terminal-sessions', params as Record<string, unknown>),
+  getTerminalSessionReplay: (id: number) =>
+    get<TerminalSession>(`/audit/terminal-sessions/${id}/replay`),
+  // 导出（admin）：数据量较小时直接返回文件，超过阈值或 async=true 时返回后台导出任务
+  export: async (
+    params: Omit<AuditLogParams, 'page' | 'pageSize'> & { format?: 'csv' | 'ndjson'; limit?: number; async?: boolean }
+  ): Promise<{ blob?: Blob; export?: AuditExport }> => {
+    const response = await api.get('/audit/export', { params, responseType: 'blob' });
+    if (response.status === 202) {
+      const body = JSON.parse(await (response.data as Blob).text()) as { export: AuditExport };
+      return { export: body.export };
+    }
+    return { blob: response.data };
+  },
+  getStorage: () => get<AuditStorageStats>('/audit/storage'),
+  listAnomalies: (params?: { since?: string; limit?: number }) =>
+    get<{ items: AuditAnomaly[]; total: number }>('/audit/anomalies', params),
+  listExports: () => get<{ items: AuditExport[] }>('/audit/exports'),
+  getExport: (id: number) => get<AuditExport>(`/audit/exports/${id}`),
+  downloadExport: async (id: number): Promise<Blob> => {
+    const response = await api.get(`/audit/exports/${id}/download`, { responseType: 'blob' });
+    return response.data;
+  },
 };
 
 // ============ 告警 ============
 export const alertApi = {
-  list: (params?: { severity?: string; namespace?: string; alertname?: string; state?: string }) =>
-    get<ListResponse<Alert>>('/alerts', params as Record<string, unknown>),
+  // 指定 page 或 pageSize 时分页返回，total 为过滤后的总数
+  list: (params?: {
+    severity?: string;
+    namespace?: string;
+    alertname?: string;
+    state?: string;
+    search?: string;
+    sort?: 'severity' | 'startsAt' | 'alertname';
+    page?: number;
+    pageSize?: number;
+  }) =>
+    get<ListResponse<Alert> & { page?: number; pageSize?: number }>('/alerts', params as Record<string, unknown>),
   get: (fingerprint: string) =>
     get<Alert>(`/alerts/${fingerprint}`),
   getSummary: () =>
@@ -539,40 +812,89 @@ export const alertApi = {
   getNames: () =>
     get<{ items: string[] }>('/alerts/names'),
   acknowledge: (fingerprint: string, data: { comment: string; expiresAt?: string }) =>
-    post<void>(`/alerts/${fingerprint}/acknowledge`, data),
+    post<AlertAcknowledgement>(`/alerts/${fingerprint}/ack`, data),
   unacknowledge: (fingerprint: string) =>
-    del<void>(`/alerts/${fingerprint}/acknowledge`),
+    del<void>(`/alerts/${fingerprint}/ack`),
   getAcknowledgement: (fingerprint: string) =>
-    get<AlertAcknowledgement>(`/alerts/${fingerprint}/acknowledgement`),
+    get<AlertAcknowledgement>(`/alerts/${fingerprint}/ack`),
+};
+
+// ============ Alertmanager 配置 ============
+
+export const alertmanagerApi = {
+  getStatus: () =>
+    get<AlertmanagerStatus>('/alertmanager/status'),
+  listReceivers: () =>
+    get<ListResponse<AlertmanagerReceiver> & { issues: string[] }>('/alertmanager/receivers'),
+  getRoutes: () =>
+    get<{ route: AlertmanagerRoute | null; issues: string[] }>('/alertmanager/routes'),
 };
 
 // ============ 静默规则 ============
 export const silenceApi = {
   list: (params?: { state?: string }) =>
-    get<ListResponse<Silence>>('/silences', params as Record<string, unknown>),
+    get<ListResponse<Silence>>('/alerts/silences', params as Record<string, unknown>),
   get: (id: number) =>
-    get<Silence>(`/silences/${id}`),
-  create: (data: {
-    matchers: Array<{ name: string; value: string; isRegex: boolean; isEqual: boolean }>;
-    startsAt: string;
-    endsAt: string;
-    comment: string;
-  }) =>
-    post<Silence>('/silences', data),
+    get<Silence>(`/alerts/silences/${id}`),
+  create: (data: SilenceInput) =>
+    post<Silence>('/alerts/silences', data),
+  update: (id: number, data: SilenceInput) =>
+    put<Silence>(`/alerts/silences/${id}`, data),
   delete: (id: number) =>
-    del<void>(`/silences/${id}`),
+    del<void>(`/alerts/silences/${id}`),
+};
+
+// ============ 告警路由 ============
+export const alertRouteApi = {
+  list: () =>
+    get<ListResponse<AlertRoute>>('/alerts/routes'),
+  channels: () =>
+    get<ListResponse<AlertRouteChannel>>('/alerts/routes/channels'),
+  create: (data: AlertRouteInput) =>
+    post<AlertRoute>('/alerts/routes', data),
+  update: (id: number, data: AlertRouteInput) =>
+    put<AlertRoute>(`/alerts/routes/${id}`, data),
+  delete: (id: number) =>
+    del<void>(`/alerts/routes/${id}`),
 };
 
 // ============ 多集群 ============
 export const clusterApi = {
   list: () => get<ClusterInfo[]>('/clusters'),
   get: (name: string) => get<ClusterInfo>(`/clusters/${name}`),
-  add: (data: { name: string; kubeconfig: string }) =>
+  add: (data: { name: string } & ClusterCredentials & Partial<ClusterEndpoints>) =>
     post<ClusterInfo>('/clusters', data),
+  // 修改展示名称、轮换凭据或启用/禁用，未提供的字段保持不变
+  update: (name: string, data: { displayName?: string; enabled?: boolean } & ClusterCredentials) =>
+    put<ClusterInfo>(`/clusters/${name}`, data),
+  updateEndpoints: (name: string, data: ClusterEndpoints) =>
+    put<ClusterInfo>(`/clusters/${name}/endpoints`, data),
   delete: (name: string) =>
     del<void>(`/clusters/${name}`),
   switch: (name: string) =>
     post<ClusterInfo>(`/clusters/${name}/switch`),
-  test: (kubeconfig: string) =>
-    post<{ success: boolean; message: string; cluster?: ClusterInfo }>('/clusters/test', { kubeconfig }),
+  test: (credentials: string | ClusterCredentials) =>
+    post<{ success: boolean; message: string; cluster?: ClusterInfo }>(
+      '/clusters/test',
+      typeof credentials === 'string' ? { kubeconfig: credentials } : credentials
+    ),
+  // 列出 kubeconfig 中的 context，供导入时选择
+  listContexts: (kubeconfig: string) =>
+    post<ListResponse<KubeconfigContext>>('/clusters/contexts', { kubeconfig }),
+};
+
+// ============ 运行手册 ============
+export const runbookApi = {
+  list: () => get<ListResponse<Runbook>>('/runbooks'),
+  get: (name: string) => get<Runbook>(`/runbooks/${name}`),
+  create: (data: Partial<Runbook>) => post<Runbook>('/admin/runbooks', data),
+  update: (name: string, data: Partial<Runbook>) => put<Runbook>(`/admin/runbooks/${name}`, data),
+  delete: (name: string) => del<void>(`/admin/runbooks/${name}`),
+  run: (name: string, namespace: string, parameters: Record<string, string>) =>
+    post<RunbookRun>(`/runbooks/${name}/run`, { namespace, parameters }),
+  listRuns: (params?: { runbook?: string; limit?: number }) =>
+    get<ListResponse<RunbookRun>>('/runbooks/runs', params as Record<string, unknown>),
+  getRun: (id: number) => get<RunbookRun>(`/runbooks/runs/${id}`),
+  getRunLogs: (id: number, container?: string) =>
+    get<string>(`/runbooks/runs/${id}/logs`, { container }),
 };
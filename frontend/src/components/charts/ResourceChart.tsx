@@ -11,6 +11,7 @@ interface ResourceChartProps {
   size?: 'sm' | 'md';
   onClick?: () => void;
   isSelected?: boolean;
+  unavailable?: boolean;  // 用量未取到（指标缺失或查询失败），不显示为 0%
 }
 
 export default function ResourceChart({
@@ -23,6 +24,7 @@ export default function ResourceChart({
   size = 'md',
   onClick,
   isSelected,
+  unavailable = false,
 }: ResourceChartProps) {
   const sizeConfig = size === 'sm'
     ? {
@@ -39,12 +41,12 @@ export default function ResourceChart({
         valueClassName: 'text-4xl',
         unitClassName: 'text-lg',
       };
-  const percentage = total > 0 ? Math.min((used / total) * 100, 100) : 0;
+  const percentage = !unavailable && total > 0 ? Math.min((used / total) * 100, 100) : 0;
   const center = sizeConfig.diameter / 2;
   const circumference = 2 * Math.PI * sizeConfig.radius;
   const strokeDashoffset = circumference - (percentage / 100) * circumference;
 
-  const chartColor = color || getUsageColor(percentage);
+  const chartColor = unavailable ? 'var(--color-text-muted)' : color || getUsageColor(percentage);
   const level = getUsageLevel(percentage);
   const formatted = formatResourceUsage(used, total, unit);
 
@@ -108,7 +110,7 @@ export default function ResourceChart({
         {/* 中心内容 */}
         <div className="absolute inset-0 flex flex-col items-center justify-center">
           <span className={`${sizeConfig.valueClassName} font-semibold`} style={{ color: chartColor }}>
-            {percentage.toFixed(1)}%
+            {unavailable ? '--' : `${percentage.toFixed(1)}%`}
           </span>
           {title && (
             <span className="text-xs mt-1 text-[var(--color-text-muted)]">
@@ -118,7 +120,13 @@ export default function ResourceChart({
         </div>
       </div>
 
-      {showLegend && (
+      {showLegend && unavailable && (
+        <div className="mt-4 text-center text-sm text-[var(--color-text-muted)]">
+          指标不可用
+        </div>
+      )}
+
+      {showLegend && !unavailable && (
         <div className="mt-4 text-center">
           <div className={`${sizeConfig.unitClassName} font-medium text-[var(--color-text-primary)]`}>
             {formatted.usedStr}{' '}
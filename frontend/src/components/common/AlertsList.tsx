@@ -92,7 +92,7 @@ export default function AlertsList({
   let filteredAlerts = data?.items ?? [];
   if (severityFilter) {
     filteredAlerts = filteredAlerts.filter((alert) => {
-      const severity = alert.labels?.severity || 'info';
+      const severity = alert.severity || alert.labels?.severity || 'info';
       // 处理没有 severity 或 severity 为 none 的情况
       if (severityFilter === 'warning' && (!severity || severity === 'none')) {
         return true;
@@ -124,7 +124,7 @@ export default function AlertsList({
         </div>
       )}
       {alerts.map((alert) => {
-        const severity = alert.labels?.severity || 'info';
+        const severity = alert.severity || alert.labels?.severity || 'info';
         const config = getSeverityConfig(severity);
         const Icon = config.icon;
         const alertName = alert.labels?.alertname || '未知告警';
@@ -0,0 +1,67 @@
+import { Link } from 'react-router-dom';
+import type { ResourceRef } from '../../types';
+
+interface RelatedResourcesProps {
+  controlledBy?: ResourceRef[];
+  controls?: ResourceRef[];
+  className?: string;
+}
+
+function ResourceChip({ item }: { item: ResourceRef }) {
+  const label = (
+    <>
+      <span className="text-[var(--color-text-muted)]">{item.kind}</span>
+      <span className="ml-1 font-medium">{item.name}</span>
+    </>
+  );
+  if (!item.link) {
+    return <span className="badge badge-default text-xs">{label}</span>;
+  }
+  return (
+    <Link to={item.link} className="badge badge-default text-xs hover:text-[var(--color-primary)]">
+      {label}
+    </Link>
+  );
+}
+
+// 关联对象：控制器链（由近及远）与直接受控的子对象，数据来自详情接口的 controlledBy/controls
+export default function RelatedResources({ controlledBy = [], controls = [], className }: RelatedResourcesProps) {
+  if (controlledBy.length === 0 && controls.length === 0) {
+    return null;
+  }
+
+  return (
+    <div
+      className={className ?? 'p-6 rounded-xl'}
+      style={{
+        background: 'var(--color-bg-secondary)',
+        border: '1px solid var(--color-border)',
+      }}
+    >
+      <h3 className="text-lg font-semibold mb-4 text-[var(--color-text-primary)]">关联资源</h3>
+      <dl className="space-y-3">
+        {controlledBy.length > 0 && (
+          <div className="flex flex-wrap items-center gap-2">
+            <dt className="w-16 text-sm text-[var(--color-text-muted)]">控制者</dt>
+            {controlledBy.map((item, index) => (
+              <dd key={`${item.kind}/${item.name}`} className="flex items-center gap-2">
+                {index > 0 && <span className="text-[var(--color-text-muted)]">←</span>}
+                <ResourceChip item={item} />
+              </dd>
+            ))}
+          </div>
+        )}
+        {controls.length > 0 && (
+          <div className="flex flex-wrap items-center gap-2">
+            <dt className="w-16 text-sm text-[var(--color-text-muted)]">控制</dt>
+            {controls.map((item) => (
+              <dd key={`${item.kind}/${item.name}`}>
+                <ResourceChip item={item} />
+              </dd>
+            ))}
+          </div>
+        )}
+      </dl>
+    </div>
+  );
+}
@@ -0,0 +1,116 @@
+import { useEffect } from 'react';
+import { useQueryClient } from '@tanstack/react-query';
+import { notificationApi, type NotificationStreamMessage } from '../api/auth';
+import { useNotificationStore } from '../store';
+
+const MAX_RECONNECT_DELAY = 30000;
+
+type NotificationKind = NonNullable<NotificationStreamMessage['notification']>['kind'];
+
+function notificationType(kind: NotificationKind): 'success' | 'error' | 'warning' | 'info' {
+  switch (kind) {
+    case 'alert.firing':
+      return 'error';
+    case 'alert.resolved':
+      return 'success';
+    case 'approval.rejected':
+    case 'audit.anomaly':
+      return 'warning';
+    default:
+      return 'info';
+  }
+}
+
+// 订阅服务端实时通知：写入待审批数量缓存、弹出审批与告警通知及会话过期提醒，断线后指数退避重连
+export function useNotificationStream(enabled: boolean) {
+  const queryClient = useQueryClient();
+  const addNotification = useNotificationStore((state) => state.addNotification);
+
+  useEffect(() => {
+    if (!enabled) {
+      return;
+    }
+
+    let ws: WebSocket | null = null;
+    let timer: ReturnType<typeof setTimeout> | undefined;
+    let attempt = 0;
+    let stopped = false;
+
+    const handleMessage = (msg: NotificationStreamMessage) => {
+      switch (msg.type) {
+        case 'pending_approvals':
+          queryClient.setQueryData(['approvals', 'pending-count'], { count: msg.count ?? 0 });
+          break;
+        case 'notification':
+          if (msg.notification) {
+            addNotification({
+              type: notificationType(msg.notification.kind),
+              title: msg.notification.title,
+              message: msg.notification.message,
+            });
+          }
+          // Alertmanager 推送的告警刷新告警列表，其余为审批相关通知
+          if (msg.notification?.kind.startsWith('alert.')) {
+            void queryClient.invalidateQueries({ queryKey: ['alerts'] });
+          } else {
+            void queryClient.invalidateQueries({ queryKey: ['approvals'] });
+          }
+          break;
+        case 'session_expiring':
+          addNotification({
+            type: 'warning',
+            title: '登录即将过期',
+            message: msg.expiresAt
+              ? `会话将于 ${new Date(msg.expiresAt).toLocaleTimeString()} 过期，请保存工作并重新登录`
+              : '请保存工作并重新登录',
+          });
+          break;
+        case 'session_expired':
+          // 会话已失效，后续请求会由全局拦截器处理登出，不再重连
+          stopped = true;
+          break;
+      }
+    };
+
+    const scheduleReconnect = () => {
+      if (stopped) {
+        return;
+      }
+      const delay = Math.min(1000 * 2 ** attempt, MAX_RECONNECT_DELAY);
+      attempt += 1;
+      timer = setTimeout(connect, delay);
+    };
+
+    const connect = async () => {
+      try {
+        ws = await notificationApi.stream();
+      } catch {
+        scheduleReconnect();
+        return;
+      }
+      if (stopped) {
+        ws.close();
+        return;
+      }
+      ws.onopen = () => {
+        attempt = 0;
+      };
+      ws.onmessage = (event) => {
+        try {
+          handleMessage(JSON.parse(event.data) as NotificationStreamMessage);
+        } catch {
+          // 忽略无法解析的消息
+        }
+      };
+      ws.onclose = scheduleReconnect;
+    };
+
+    void connect();
+
+    return () => {
+      stopped = true;
+      clearTimeout(timer);
+      ws?.close();
+    };
+  }, [enabled, queryClient, addNotification]);
+}
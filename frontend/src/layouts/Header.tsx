@@ -1,13 +1,15 @@
-import { Fragment, useMemo } from 'react';
+import { Fragment, useEffect, useMemo, useRef, useState } from 'react';
 import { Link, useNavigate } from 'react-router-dom';
-import { useQueryClient } from '@tanstack/react-query';
+import { useQuery, useQueryClient } from '@tanstack/react-query';
 import { Menu, Transition } from '@headlessui/react';
 import clsx from 'clsx';
 import { useAppStore } from '../store';
 import { useAuthStore, useRoleDisplay } from '../store/auth';
 import { authApi } from '../api/auth';
-import { clusterApi } from '../api';
+import { clusterApi, searchApi } from '../api';
+import { queryKeys } from '../api/queryKeys';
 import { invalidateClusterScopedQueries } from '../api/queryPolicy';
+import type { SearchHit } from '../types/api';
 import {
   BellIcon,
   ClockIcon,
@@ -21,6 +23,12 @@ import {
   UserIcon,
 } from '@heroicons/react/24/outline';
 
+const matchedOnLabels: Record<SearchHit['matchedOn'], string> = {
+  name: '名称',
+  label: '标签',
+  annotation: '注解',
+};
+
 function formatClusterStatus(status?: string) {
   if (status === 'connected') return '已连接';
   if (status === 'error') return '异常';
@@ -45,6 +53,42 @@ export default function Header() {
   const { user, clearAuth } = useAuthStore();
   const roleDisplay = useRoleDisplay();
 
+  const [searchInput, setSearchInput] = useState('');
+  const [searchTerm, setSearchTerm] = useState('');
+  const [searchOpen, setSearchOpen] = useState(false);
+  const searchInputRef = useRef<HTMLInputElement>(null);
+
+  // 输入停顿 300ms 后再发起搜索
+  useEffect(() => {
+    const timer = setTimeout(() => setSearchTerm(searchInput.trim()), 300);
+    return () => clearTimeout(timer);
+  }, [searchInput]);
+
+  useEffect(() => {
+    const handleKeyDown = (event: KeyboardEvent) => {
+      if ((event.metaKey || event.ctrlKey) && event.key.toLowerCase() === 'k') {
+        event.preventDefault();
+        searchInputRef.current?.focus();
+      }
+    };
+    window.addEventListener('keydown', handleKeyDown);
+    return () => window.removeEventListener('keydown', handleKeyDown);
+  }, []);
+
+  const { data: searchResult, isFetching: searching } = useQuery({
+    queryKey: queryKeys.search(currentCluster ?? '', searchTerm),
+    queryFn: () => searchApi.search({ q: searchTerm, limit: 20 }),
+    enabled: searchTerm.length >= 2,
+    staleTime: 10_000,
+  });
+
+  const handleSelectHit = (hit: SearchHit) => {
+    if (!hit.link) return;
+    setSearchOpen(false);
+    setSearchInput('');
+    navigate(hit.link);
+  };
+
   const currentClusterStatus = useMemo(
     () => clusters.find((cluster) => cluster.name === currentCluster)?.status,
     [clusters, currentCluster]
@@ -128,7 +172,23 @@ export default function Header() {
           <div className="relative">
             <MagnifyingGlassIcon className="absolute left-3 top-1/2 h-5 w-5 -translate-y-1/2 text-[var(--color-text-muted)]" />
             <input
+              ref={searchInputRef}
               type="text"
+              value={searchInput}
+              onChange={(e) => {
+                setSearchInput(e.target.value);
+                setSearchOpen(true);
+              }}
+              onFocus={() => setSearchOpen(true)}
+              onBlur={() => setTimeout(() => setSearchOpen(false), 150)}
+              onKeyDown={(e) => {
+                if (e.key === 'Escape') {
+                  setSearchOpen(false);
+                  e.currentTarget.blur();
+                } else if (e.key === 'Enter' && searchResult?.items[0]) {
+                  handleSelectHit(searchResult.items[0]);
+                }
+              }}
               placeholder="搜索资源..."
               className="h-11 w-72 rounded-lg border border-[var(--color-border)] bg-[var(--color-bg-tertiary)] py-2 pl-10 pr-12 text-sm text-[var(--color-text-primary)] transition-all duration-150 ease-out placeholder:text-[var(--color-text-muted)] focus-visible:border-[var(--color-primary)] focus-visible:outline-none focus-visible:ring-2 focus-visible:ring-focus-ring lg:w-80"
               aria-label="搜索资源"
@@ -136,6 +196,44 @@ export default function Header() {
             <kbd className="absolute right-3 top-1/2 -translate-y-1/2 rounded bg-[var(--color-bg-elevated)] px-2 py-0.5 text-xs text-[var(--color-text-muted)]">
               ⌘K
             </kbd>
+            {searchOpen && searchTerm.length >= 2 && (
+              <div className="absolute left-0 z-50 mt-2 max-h-96 w-[28rem] overflow-y-auto rounded-lg border border-[var(--color-border)] bg-[var(--color-bg-elevated)] py-1 shadow-lg">
+                {searching && !searchResult ? (
+                  <div className="px-4 py-3 text-sm text-[var(--color-text-muted)]">搜索中...</div>
+                ) : !searchResult || searchResult.items.length === 0 ? (
+                  <div className="px-4 py-3 text-sm text-[var(--color-text-muted)]">没有匹配的资源</div>
+                ) : (
+                  searchResult.items.map((hit) => (
+                    <button
+                      key={`${hit.kind}/${hit.namespace ?? ''}/${hit.name}`}
+                      type="button"
+                      onMouseDown={(e) => e.preventDefault()}
+                      onClick={() => handleSelectHit(hit)}
+                      className={clsx(menuItemClass, 'justify-between text-left hover:bg-primary-light')}
+                    >
+                      <span className="min-w-0 flex-1">
+                        <span className="block truncate font-medium">{hit.name}</span>
+                        <span className="block truncate text-xs text-[var(--color-text-muted)]">
+                          {hit.kind}
+                          {hit.namespace ? ` · ${hit.namespace}` : ''}
+                          {hit.matchedOn !== 'name' && ` · ${matchedOnLabels[hit.matchedOn]} ${hit.match ?? ''}`}
+                        </span>
+                      </span>
+                    </button>
+                  ))
+                )}
+                {searchResult?.truncated && (
+                  <div className="border-t border-divider-strong px-4 py-2 text-xs text-[var(--color-text-muted)]">
+                    共 {searchResult.total} 条结果，仅显示前 {searchResult.items.length} 条，请输入更多关键字
+                  </div>
+                )}
+                {searchResult?.pending && searchResult.pending.length > 0 && (
+                  <div className="border-t border-divider-strong px-4 py-2 text-xs text-[var(--color-warning)]">
+                    索引同步中，暂未包含：{searchResult.pending.join('、')}
+                  </div>
+                )}
+              </div>
+            )}
           </div>
         </div>
 
@@ -7,6 +7,7 @@ import { useAppStore } from '../store';
 import { useAuthStore } from '../store/auth';
 import { clusterApi, namespaceApi } from '../api';
 import { usePollingInterval } from '../utils/polling';
+import { useNotificationStream } from '../hooks/useNotificationStream';
 import { queryKeys } from '../api/queryKeys';
 import { createVisibilityRefetchInterval, invalidateClusterScopedQueries } from '../api/queryPolicy';
 import clsx from 'clsx';
@@ -28,6 +29,9 @@ export default function MainLayout() {
   const standardRefetchInterval = createVisibilityRefetchInterval(pollingInterval);
   const slowRefetchInterval = createVisibilityRefetchInterval(slowPollingInterval);
 
+  // 待审批数量、审批通知与会话过期提醒由服务端推送
+  useNotificationStream(!!user);
+
   // 获取命名空间列表
   const { data: namespacesData } = useQuery({
     queryKey: queryKeys.namespaces,
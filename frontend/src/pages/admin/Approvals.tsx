@@ -134,6 +134,16 @@ function ApprovalDetailModal({
                 {new Date(approval.createdAt).toLocaleString()}
               </span>
             </div>
+            {approval.requiredApprovals > 1 && (
+              <div className="flex justify-between">
+                <span className="text-text-muted">多人审批</span>
+                <span className="text-white">
+                  {approval.votes?.length ?? 0}/{approval.requiredApprovals} 人已批准
+                  {approval.votes && approval.votes.length > 0 &&
+                    `（${approval.votes.map((vote) => vote.approverName).join('、')}）`}
+                </span>
+              </div>
+            )}
           </div>
 
           {/* 申请原因 */}
@@ -146,6 +156,40 @@ function ApprovalDetailModal({
             </div>
           )}
 
+          {/* 变更预览 */}
+          {approval.preview && (
+            <div>
+              <label className="block text-text-muted text-sm mb-1">变更预览</label>
+              <div className="bg-surface-tertiary rounded-lg p-3 text-sm space-y-2">
+                <div className="text-white">{approval.preview.summary}</div>
+                {approval.preview.requestedReplicas !== undefined && (
+                  <div className="flex justify-between">
+                    <span className="text-text-muted">副本数</span>
+                    <span className="text-white font-mono">
+                      {approval.preview.currentReplicas ?? '-'} → {approval.preview.requestedReplicas}
+                    </span>
+                  </div>
+                )}
+                {approval.preview.imageChanges?.map((change) => (
+                  <div key={change.container} className="font-mono text-xs">
+                    <div className="text-text-secondary">{change.container}</div>
+                    <div className="text-red-400">- {change.current}</div>
+                    <div className="text-green-400">+ {change.requested}</div>
+                  </div>
+                ))}
+                <div className="flex justify-between">
+                  <span className="text-text-muted">受影响 Pod</span>
+                  <span className="text-white">{approval.preview.affectedPods}</span>
+                </div>
+                {approval.preview.warnings?.map((warning) => (
+                  <div key={warning} className="text-yellow-400">
+                    {warning}
+                  </div>
+                ))}
+              </div>
+            </div>
+          )}
+
           {/* 审批结果（如果已处理） */}
           {approval.status !== 'pending' && (
             <div className="border-t border-border pt-4 space-y-3">
@@ -244,9 +288,9 @@ export default function Approvals() {
   const approveMutation = useMutation({
     mutationFn: ({ id, comment }: { id: number; comment: string }) =>
       approvalApi.approve(id, comment),
-    onSuccess: () => {
+    onSuccess: (res) => {
       queryClient.invalidateQueries({ queryKey: ['approvals'] });
-      addNotification({ type: 'success', title: '已批准' });
+      addNotification({ type: 'success', title: '已批准', message: res.message });
       setSelectedApproval(null);
     },
     onError: (err: Error) => {
@@ -12,6 +12,7 @@ import {
   ExclamationCircleIcon,
   InformationCircleIcon,
   FunnelIcon,
+  MagnifyingGlassIcon,
   XMarkIcon,
   ArrowPathIcon,
   ChevronRightIcon,
@@ -63,6 +64,7 @@ export default function AlertsActive() {
     severity: searchParams.get('severity') || '',
     namespace: searchParams.get('namespace') || '',
     alertname: searchParams.get('alertname') || '',
+    search: searchParams.get('search') || '',
   };
 
   // 获取活跃告警
@@ -106,7 +108,7 @@ export default function AlertsActive() {
   };
 
   // 是否有活跃的过滤器
-  const hasActiveFilters = filters.severity || filters.namespace || filters.alertname;
+  const hasActiveFilters = filters.severity || filters.namespace || filters.alertname || filters.search;
 
   const alerts = data?.items || [];
   const namespaces = namespacesData?.items?.map(ns => ns.metadata.name) || [];
@@ -138,6 +140,18 @@ export default function AlertsActive() {
             <span className="text-sm">过滤</span>
           </div>
 
+          {/* 关键字搜索 */}
+          <div className="relative">
+            <MagnifyingGlassIcon className="w-4 h-4 text-text-muted absolute left-3 top-1/2 -translate-y-1/2" />
+            <input
+              type="text"
+              value={filters.search}
+              onChange={(e) => updateFilter('search', e.target.value)}
+              placeholder="搜索告警名称、标签或描述..."
+              className="input input-sm bg-surface-secondary border-border-hover text-text-secondary pl-9"
+            />
+          </div>
+
           {/* 严重级别过滤 */}
           <select
             value={filters.severity}
@@ -235,7 +249,7 @@ export default function AlertsActive() {
 
 // 告警卡片组件
 function AlertCard({ alert, onClick }: { alert: Alert; onClick: () => void }) {
-  const severity = alert.labels.severity || 'info';
+  const severity = alert.severity || alert.labels.severity || 'info';
   const config = getSeverityConfig(severity);
   const Icon = config.icon;
 
@@ -285,6 +299,11 @@ function AlertCard({ alert, onClick }: { alert: Alert; onClick: () => void }) {
             {alert.status.silencedBy.length > 0 && (
               <span className="text-yellow-500">已静默</span>
             )}
+            {alert.acknowledgement && (
+              <span className="text-yellow-400" title={alert.acknowledgement.comment || undefined}>
+                {alert.acknowledgement.acknowledgedBy} 处理中
+              </span>
+            )}
           </div>
         </div>
 
@@ -301,7 +320,7 @@ function AlertDetailDrawer({ alert, onClose }: { alert: Alert; onClose: () => vo
   const [showSilenceModal, setShowSilenceModal] = useState(false);
   const queryClient = useQueryClient();
 
-  const severity = alert.labels.severity || 'info';
+  const severity = alert.severity || alert.labels.severity || 'info';
   const config = getSeverityConfig(severity);
   const Icon = config.icon;
 
@@ -320,6 +339,7 @@ function AlertDetailDrawer({ alert, onClose }: { alert: Alert; onClose: () => vo
       alertApi.acknowledge(alert.fingerprint, data),
     onSuccess: () => {
       queryClient.invalidateQueries({ queryKey: ['alert-acknowledgement', alert.fingerprint] });
+      queryClient.invalidateQueries({ queryKey: ['alerts'] });
       setShowAckModal(false);
     },
   });
@@ -329,6 +349,7 @@ function AlertDetailDrawer({ alert, onClose }: { alert: Alert; onClose: () => vo
     mutationFn: () => alertApi.unacknowledge(alert.fingerprint),
     onSuccess: () => {
       queryClient.invalidateQueries({ queryKey: ['alert-acknowledgement', alert.fingerprint] });
+      queryClient.invalidateQueries({ queryKey: ['alerts'] });
     },
   });
 
@@ -534,6 +534,12 @@ export default function AuditLogs() {
                                 <span className="text-text-muted">用户:</span>
                                 <span className="text-text-secondary font-medium">{log.user}</span>
                               </div>
+                              {log.role && (
+                                <div className="flex justify-between">
+                                  <span className="text-text-muted">角色:</span>
+                                  <span className="text-text-secondary">{log.role}{log.userId ? ` (ID ${log.userId})` : ''}</span>
+                                </div>
+                              )}
                               <div className="flex justify-between">
                                 <span className="text-text-muted">操作类型:</span>
                                 <span className={clsx('badge', actionColors[log.action] || 'badge-default')}>
@@ -614,6 +620,19 @@ export default function AuditLogs() {
                               </pre>
                             </div>
                           )}
+
+                          {/* 变更差异 */}
+                          {log.diff && (
+                            <div className="lg:col-span-2 space-y-2">
+                              <h4 className="text-text-secondary font-medium flex items-center gap-2">
+                                <DocumentTextIcon className="w-4 h-4" />
+                                变更差异
+                              </h4>
+                              <pre className="bg-surface-tertiary rounded-lg p-3 text-xs text-text-secondary font-mono overflow-x-auto max-h-64">
+                                {formatJSON(log.diff)}
+                              </pre>
+                            </div>
+                          )}
                         </div>
                       </td>
                     </tr>
@@ -26,7 +26,7 @@ export default function Login() {
   const loginMutation = useMutation({
     mutationFn: authApi.login,
     onSuccess: (data) => {
-      setAuth(data.user, data.token, data.namespaces);
+      setAuth(data.user, data.token, data.namespaces, data.refreshToken);
       navigate(from, { replace: true });
     },
     onError: (err: Error & { response?: { data?: { error?: string } } }) => {
@@ -24,6 +24,8 @@ export default function ConfigMapDetail() {
   const [searchParams, setSearchParams] = useSearchParams();
   const [showYamlEditor, setShowYamlEditor] = useState(false);
   const [showDeleteConfirm, setShowDeleteConfirm] = useState(false);
+  // 保存后滚动重启引用该 ConfigMap 的 Deployment
+  const [rolloutOnSave, setRolloutOnSave] = useState(false);
   const queryClient = useQueryClient();
 
   // 从 URL 参数读取当前标签，默认为 'overview'
@@ -48,9 +50,17 @@ export default function ConfigMapDetail() {
     enabled: !!namespace && !!name,
   });
 
+  // 打开删除确认框时查询引用该 ConfigMap 的工作负载
+  const { data: usage } = useQuery({
+    queryKey: ['configmap-usage', namespace, name],
+    queryFn: () => configMapApi.usage(namespace!, name!),
+    enabled: !!namespace && !!name && showDeleteConfirm,
+  });
+
   // 删除 ConfigMap
   const deleteMutation = useMutation({
-    mutationFn: () => configMapApi.delete(namespace!, name!),
+    // 确认框中已展示引用情况，确认后强制删除
+    mutationFn: () => configMapApi.delete(namespace!, name!, true),
     onSuccess: () => {
       queryClient.invalidateQueries({ queryKey: ['configmaps'] });
       window.history.back();
@@ -59,8 +69,15 @@ export default function ConfigMapDetail() {
 
   // 更新 YAML
   const updateYamlMutation = useMutation({
-    mutationFn: (yaml: string) => configMapApi.updateYaml(namespace!, name!, yaml),
-    onSuccess: () => {
+    mutationFn: (yaml: string) => configMapApi.updateYaml(namespace!, name!, yaml, rolloutOnSave),
+    onSuccess: (result) => {
+      if ('configMap' in result) {
+        if (result.rolloutError) {
+          alert(`配置已更新，但未能重启工作负载: ${result.rolloutError}`);
+        } else if (result.rollout?.failed.length) {
+          alert(`配置已更新，以下 Deployment 重启失败:\n${result.rollout.failed.join('\n')}`);
+        }
+      }
       queryClient.invalidateQueries({ queryKey: ['configmap', namespace, name] });
       queryClient.invalidateQueries({ queryKey: ['configmap-yaml', namespace, name] });
       setShowYamlEditor(false);
@@ -112,6 +129,15 @@ export default function ConfigMapDetail() {
           </div>
         </div>
         <div className="flex items-center gap-2">
+          <label className="flex items-center gap-2 text-sm text-text-secondary" title="保存后通过 restartedAt 注解滚动重启引用该 ConfigMap 的 Deployment">
+            <input
+              type="checkbox"
+              checked={rolloutOnSave}
+              onChange={(e) => setRolloutOnSave(e.target.checked)}
+            />
+            保存后重启 Deployment
+          </label>
+
           {/* 编辑 YAML 按钮 */}
           <button
             onClick={() => setShowYamlEditor(true)}
@@ -212,6 +238,23 @@ export default function ConfigMapDetail() {
                 </dl>
               </div>
 
+              {usage?.inUse && (
+                <div className="text-sm text-yellow-400 bg-yellow-500/5 rounded-lg p-3 border border-yellow-500/20">
+                  <div className="flex items-center gap-2 font-medium mb-2">
+                    <ExclamationTriangleIcon className="w-5 h-5 flex-shrink-0" />
+                    仍被 {usage.workloads?.length} 个工作负载引用，删除后它们将无法启动
+                  </div>
+                  <ul className="space-y-1 text-xs">
+                    {usage.workloads?.map((w) => (
+                      <li key={`${w.kind}/${w.name}`}>
+                        {w.kind}/{w.name}
+                        <span className="text-text-muted"> — {w.references.join('，')}</span>
+                      </li>
+                    ))}
+                  </ul>
+                </div>
+              )}
+
               <div className="flex items-start gap-2 text-sm text-red-400 bg-red-500/5 rounded-lg p-3 border border-red-500/20">
                 <InformationCircleIcon className="w-5 h-5 flex-shrink-0 mt-0.5" />
                 <p>此操作不可撤销，请谨慎操作！</p>
@@ -51,9 +51,17 @@ export default function SecretDetail() {
     enabled: !!namespace && !!name,
   });
 
+  // 打开删除确认框时查询引用该 Secret 的工作负载
+  const { data: usage } = useQuery({
+    queryKey: ['secret-usage', namespace, name],
+    queryFn: () => secretApi.usage(namespace!, name!),
+    enabled: !!namespace && !!name && showDeleteConfirm,
+  });
+
   // 删除 Secret
   const deleteMutation = useMutation({
-    mutationFn: () => secretApi.delete(namespace!, name!),
+    // 确认框中已展示引用情况，确认后强制删除
+    mutationFn: () => secretApi.delete(namespace!, name!, true),
     onSuccess: () => {
       queryClient.invalidateQueries({ queryKey: ['secrets'] });
       window.history.back();
@@ -248,6 +256,23 @@ export default function SecretDetail() {
                 </dl>
               </div>
 
+              {usage?.inUse && (
+                <div className="text-sm text-yellow-400 bg-yellow-500/5 rounded-lg p-3 border border-yellow-500/20">
+                  <div className="flex items-center gap-2 font-medium mb-2">
+                    <ExclamationTriangleIcon className="w-5 h-5 flex-shrink-0" />
+                    仍被 {usage.workloads?.length} 个工作负载引用，删除后它们将无法启动
+                  </div>
+                  <ul className="space-y-1 text-xs">
+                    {usage.workloads?.map((w) => (
+                      <li key={`${w.kind}/${w.name}`}>
+                        {w.kind}/{w.name}
+                        <span className="text-text-muted"> — {w.references.join('，')}</span>
+                      </li>
+                    ))}
+                  </ul>
+                </div>
+              )}
+
               <div className="flex items-start gap-2 text-sm text-red-400 bg-red-500/5 rounded-lg p-3 border border-red-500/20">
                 <InformationCircleIcon className="w-5 h-5 flex-shrink-0 mt-0.5" />
                 <p>此操作不可撤销，删除 Secret 可能影响依赖它的应用！</p>
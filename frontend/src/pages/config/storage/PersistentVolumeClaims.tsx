@@ -5,6 +5,7 @@ import { usePollingInterval } from '../../../utils/polling';
 import { formatDistanceToNow } from 'date-fns';
 import { zhCN } from 'date-fns/locale';
 import clsx from 'clsx';
+import { formatBytes } from '../../../utils/format';
 
 export default function PersistentVolumeClaims() {
   const { currentNamespace } = useAppStore();
@@ -100,6 +101,7 @@ export default function PersistentVolumeClaims() {
                 <th>状态</th>
                 <th>Volume</th>
                 <th>容量</th>
+                <th>已用</th>
                 <th>访问模式</th>
                 <th>StorageClass</th>
                 <th>创建时间</th>
@@ -127,6 +129,24 @@ export default function PersistentVolumeClaims() {
                   <td className="text-text-muted">
                     {pvc.status?.capacity?.storage || pvc.spec.resources?.requests?.storage || '-'}
                   </td>
+                  <td className="text-text-muted">
+                    {pvc.usage ? (
+                      <span
+                        className={clsx(
+                          pvc.usage.usagePercent >= 90 || pvc.usage.inodesUsagePercent >= 90
+                            ? 'text-red-400'
+                            : pvc.usage.usagePercent >= 80
+                              ? 'text-yellow-400'
+                              : undefined
+                        )}
+                        title={`inode 使用率 ${pvc.usage.inodesUsagePercent.toFixed(1)}%`}
+                      >
+                        {formatBytes(pvc.usage.usedBytes)} ({pvc.usage.usagePercent.toFixed(1)}%)
+                      </span>
+                    ) : (
+                      '-'
+                    )}
+                  </td>
                   <td className="text-text-muted">
                     {formatAccessModes(pvc.spec.accessModes)}
                   </td>
@@ -85,6 +85,17 @@ export default function Dashboard() {
 
   return (
     <div className="space-y-6">
+      {overview.warnings && overview.warnings.length > 0 && (
+        <div className="card rounded-xl border border-yellow-500/30 bg-yellow-500/10 p-4 text-sm text-yellow-400">
+          <div className="font-medium">部分数据获取失败，以下统计可能不完整</div>
+          {overview.warnings.map((warning) => (
+            <div key={warning} className="mt-1 text-xs">
+              {warning}
+            </div>
+          ))}
+        </div>
+      )}
+
       <ExecutiveSummary
         overview={overview}
         alertSummary={alertSummary}
@@ -10,8 +10,13 @@ import {
 import StatsCard from '../../../components/common/StatsCard';
 import ResourceChart from '../../../components/charts/ResourceChart';
 import { formatNumber } from '../../../utils/format';
+import type { UsageMetric } from '../../../types';
 import type { OpsDetailGridProps, QuickActionLink } from '../types';
 
+// 用量来自指标查询但未取到值（没有数据或查询失败）
+const isUsageUnavailable = (metric: UsageMetric) =>
+  metric.usedStatus !== undefined && metric.usedStatus !== 'ok';
+
 const quickLinks: QuickActionLink[] = [
   {
     name: 'Pods',
@@ -77,6 +82,7 @@ export default function OpsDetailGrid({ overview, alertSummary }: OpsDetailGridP
                   used={overview.resources.cpu.used}
                   total={overview.resources.cpu.total}
                   unit={overview.resources.cpu.unit}
+                  unavailable={isUsageUnavailable(overview.resources.cpu)}
                   size="sm"
                 />
               </div>
@@ -92,6 +98,7 @@ export default function OpsDetailGrid({ overview, alertSummary }: OpsDetailGridP
                   used={overview.resources.memory.used}
                   total={overview.resources.memory.total}
                   unit={overview.resources.memory.unit}
+                  unavailable={isUsageUnavailable(overview.resources.memory)}
                   size="sm"
                 />
               </div>
@@ -107,6 +114,7 @@ export default function OpsDetailGrid({ overview, alertSummary }: OpsDetailGridP
                   used={overview.resources.nodeMemory.used}
                   total={overview.resources.nodeMemory.total}
                   unit={overview.resources.nodeMemory.unit}
+                  unavailable={isUsageUnavailable(overview.resources.nodeMemory)}
                   size="sm"
                 />
               </div>
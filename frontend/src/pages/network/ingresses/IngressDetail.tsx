@@ -5,7 +5,7 @@ import { ingressApi } from '../../../api';
 import { formatDistanceToNow } from 'date-fns';
 import { zhCN } from 'date-fns/locale';
 import clsx from 'clsx';
-import type { Ingress } from '../../../types';
+import type { Ingress, IngressTLSCertificate } from '../../../types';
 import YamlEditorModal from '../../../components/common/YamlEditorModal';
 import {
   ArrowLeftIcon,
@@ -308,21 +308,62 @@ function OverviewTab({ ingress }: { ingress: Ingress }) {
         <div className="card p-6 lg:col-span-2">
           <h3 className="text-lg font-semibold text-white mb-4">TLS 配置</h3>
           <div className="space-y-3">
-            {ingress.spec.tls.map((tls, idx) => (
-              <div key={idx} className="bg-[color-mix(in_srgb,var(--color-bg-secondary)_50%,transparent)] rounded-lg p-4">
-                <div className="flex items-center justify-between mb-2">
-                  <span className="text-sm font-medium text-white">Secret: {tls.secretName}</span>
-                  <span className="badge badge-success text-xs">HTTPS</span>
-                </div>
-                <div className="flex flex-wrap gap-2">
-                  {tls.hosts?.map((host) => (
-                    <span key={host} className="badge badge-default text-xs">
-                      {host}
+            {ingress.spec.tls.map((tls, idx) => {
+              const cert = ingress.tlsCertificates?.[idx];
+              return (
+                <div key={idx} className="bg-[color-mix(in_srgb,var(--color-bg-secondary)_50%,transparent)] rounded-lg p-4">
+                  <div className="flex items-center justify-between mb-2">
+                    <span className="text-sm font-medium text-white">Secret: {tls.secretName || '（默认证书）'}</span>
+                    <span
+                      className={clsx(
+                        'badge text-xs',
+                        cert?.status === 'expired' || cert?.status === 'error'
+                          ? 'badge-error'
+                          : cert?.status === 'expiring'
+                            ? 'badge-warning'
+                            : 'badge-success'
+                      )}
+                    >
+                      {certificateStatusLabel(cert)}
                     </span>
-                  ))}
+                  </div>
+                  <div className="flex flex-wrap gap-2">
+                    {tls.hosts?.map((host) => (
+                      <span
+                        key={host}
+                        className={clsx('badge text-xs', cert?.unmatchedHosts?.includes(host) ? 'badge-error' : 'badge-default')}
+                        title={cert?.unmatchedHosts?.includes(host) ? '证书未覆盖该主机名' : undefined}
+                      >
+                        {host}
+                      </span>
+                    ))}
+                  </div>
+                  {cert?.notAfter && (
+                    <dl className="mt-3 grid grid-cols-1 md:grid-cols-2 gap-2 text-xs">
+                      <div>
+                        <dt className="text-text-muted">主题</dt>
+                        <dd className="text-text-secondary break-all">{cert.subject}</dd>
+                      </div>
+                      <div>
+                        <dt className="text-text-muted">签发者</dt>
+                        <dd className="text-text-secondary break-all">{cert.issuer}</dd>
+                      </div>
+                      <div>
+                        <dt className="text-text-muted">SAN</dt>
+                        <dd className="text-text-secondary break-all">{cert.dnsNames?.join(', ') || '-'}</dd>
+                      </div>
+                      <div>
+                        <dt className="text-text-muted">过期时间</dt>
+                        <dd className="text-text-secondary">
+                          {new Date(cert.notAfter).toLocaleString('zh-CN')}（剩余 {cert.daysRemaining} 天）
+                        </dd>
+                      </div>
+                    </dl>
+                  )}
+                  {cert?.error && <p className="mt-2 text-xs text-red-400">{cert.error}</p>}
                 </div>
-              </div>
-            ))}
+              );
+            })}
           </div>
         </div>
       )}
@@ -330,6 +371,21 @@ function OverviewTab({ ingress }: { ingress: Ingress }) {
   );
 }
 
+function certificateStatusLabel(cert?: IngressTLSCertificate) {
+  switch (cert?.status) {
+    case 'expired':
+      return '证书已过期';
+    case 'expiring':
+      return `${cert.daysRemaining} 天后过期`;
+    case 'error':
+      return '证书异常';
+    case 'default':
+      return '默认证书';
+    default:
+      return 'HTTPS';
+  }
+}
+
 function YamlTab({ yaml }: { yaml: string }) {
   return (
     <div className="space-y-4">
@@ -18,6 +18,7 @@ function isCurrentTheme(theme: 'dark' | 'light', current: 'dark' | 'light'): boo
 export default function Settings() {
   const queryClient = useQueryClient();
   const user = useAuthStore((state) => state.user);
+  const updateUser = useAuthStore((state) => state.updateUser);
   const { theme, setTheme, refreshInterval, setRefreshInterval } = useAppStore();
   const [oldPassword, setOldPassword] = useState('');
   const [newPassword, setNewPassword] = useState('');
@@ -31,9 +32,16 @@ export default function Settings() {
 
   const sessions = useMemo<Session[]>(() => sessionsData?.items ?? [], [sessionsData]);
 
+  const { data: passwordPolicy } = useQuery({
+    queryKey: ['auth-password-policy'],
+    queryFn: () => authApi.getPasswordPolicy(),
+  });
+  const minPasswordLength = passwordPolicy?.minLength ?? 8;
+
   const changePasswordMutation = useMutation({
     mutationFn: (payload: { oldPassword: string; newPassword: string }) => authApi.changePassword(payload),
     onSuccess: () => {
+      updateUser({ mustChangePassword: false });
       setMessage('密码修改成功');
       setOldPassword('');
       setNewPassword('');
@@ -63,8 +71,8 @@ export default function Settings() {
       setMessage('旧密码不能为空');
       return;
     }
-    if (newPassword.length < 6) {
-      setMessage('新密码长度至少 6 位');
+    if (newPassword.length < minPasswordLength) {
+      setMessage(`新密码长度至少 ${minPasswordLength} 位`);
       return;
     }
     if (newPassword !== confirmPassword) {
@@ -89,6 +97,19 @@ export default function Settings() {
         </p>
       </div>
 
+      {user?.mustChangePassword && (
+        <div
+          className="rounded-lg px-4 py-3 text-sm"
+          style={{
+            background: 'var(--color-bg-secondary)',
+            border: '1px solid var(--color-warning)',
+            color: 'var(--color-text-primary)',
+          }}
+        >
+          密码已被重置、仍为默认密码或已过期，请先修改密码后再继续使用
+        </div>
+      )}
+
       {message && (
         <div
           className="rounded-lg px-4 py-3 text-sm"
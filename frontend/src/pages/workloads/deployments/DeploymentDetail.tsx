@@ -12,6 +12,7 @@ import ActionDropdown from '../../../components/common/ActionDropdown';
 import EditImageModal from '../../../components/workloads/EditImageModal';
 import SchedulingEditor from '../../../components/workloads/SchedulingEditor';
 import YamlEditorModal from '../../../components/common/YamlEditorModal';
+import RelatedResources from '../../../components/common/RelatedResources';
 import { usePollingInterval } from '../../../utils/polling';
 import {
   ArrowLeftIcon,
@@ -555,6 +556,12 @@ function OverviewTab({ deployment, namespace, name }: { deployment: Deployment;
         </dl>
       </div>
 
+      <RelatedResources
+        className="card p-6 lg:col-span-2"
+        controlledBy={deployment.controlledBy}
+        controls={deployment.controls}
+      />
+
       {/* 更新策略编辑器 */}
       <div className="lg:col-span-2">
         <UpdateStrategyEditor
@@ -6,6 +6,7 @@ import { formatDistanceToNow } from 'date-fns';
 import { zhCN } from 'date-fns/locale';
 import clsx from 'clsx';
 import type { Pod, Event, PodPhase } from '../../../types';
+import RelatedResources from '../../../components/common/RelatedResources';
 import {
   ArrowLeftIcon,
   TrashIcon,
@@ -343,6 +344,12 @@ function OverviewTab({ pod }: { pod: Pod }) {
         </div>
       </div>
 
+      <RelatedResources
+        className="p-6 lg:col-span-2 rounded-xl"
+        controlledBy={pod.controlledBy}
+        controls={pod.controls}
+      />
+
       {/* 条件状态 */}
       <div
         className="p-6 lg:col-span-2 rounded-xl"
@@ -12,6 +12,8 @@ export interface User {
   enabled: boolean;
   createdAt: string;
   lastLoginAt?: string;
+  mustChangePassword?: boolean;
+  passwordChangedAt?: string;
 }
 
 // 认证状态
@@ -25,7 +27,8 @@ interface AuthState {
   allowedNamespaces: string[];
 
   // 操作
-  setAuth: (user: User, token: string, namespaces?: string[]) => void;
+  setAuth: (user: User, token: string, namespaces?: string[], refreshToken?: string) => void;
+  setToken: (token: string, refreshToken: string) => void;
   clearAuth: () => void;
   updateUser: (user: Partial<User>) => void;
   setAllowedNamespaces: (namespaces: string[]) => void;
@@ -43,9 +46,12 @@ export const useAuthStore = create<AuthState>()(
       isAuthenticated: false,
       allowedNamespaces: [],
 
-      setAuth: (user, token, namespaces = []) => {
+      setAuth: (user, token, namespaces = [], refreshToken) => {
         // 同时保存到 localStorage（供 API client 使用）
         localStorage.setItem('token', token);
+        if (refreshToken) {
+          localStorage.setItem('refreshToken', refreshToken);
+        }
         set({
           user,
           token,
@@ -54,8 +60,16 @@ export const useAuthStore = create<AuthState>()(
         });
       },
 
+      // 刷新令牌轮换后更新访问令牌
+      setToken: (token, refreshToken) => {
+        localStorage.setItem('token', token);
+        localStorage.setItem('refreshToken', refreshToken);
+        set({ token });
+      },
+
       clearAuth: () => {
         localStorage.removeItem('token');
+        localStorage.removeItem('refreshToken');
         set({
           user: null,
           token: null,
@@ -1,3 +1,5 @@
+import type { ConfigMap, Event } from './kubernetes';
+
 // API 响应和请求类型
 
 // 通用列表响应
@@ -18,6 +20,72 @@ export interface ClusterOverview {
   namespaces: number;
   events: EventSummary;
   resources: ResourceUsage;
+  warnings?: string[];  // 获取失败的数据块，对应字段为 0
+}
+
+// 概览“当前问题”：仅包含数量非零的类别，link 为前端路由
+export interface OverviewIssueItem {
+  namespace?: string;
+  name: string;
+  message?: string;
+  link: string;
+}
+
+export interface OverviewIssue {
+  type: 'crashLoopBackOff' | 'imagePull' | 'pending' | 'nodeNotReady' | 'criticalAlert';
+  title: string;
+  severity: 'critical' | 'warning';
+  count: number;
+  link: string;
+  items: OverviewIssueItem[];
+}
+
+export interface OverviewIssuesResponse {
+  healthy: boolean;
+  issues: OverviewIssue[];
+  errors?: string[];
+  generatedAt: string;
+}
+
+// ConfigMap/Secret 被工作负载引用的情况
+export interface ConfigReference {
+  kind: string;
+  name: string;
+  references: string[];
+}
+
+export interface ConfigUsage {
+  kind: 'ConfigMap' | 'Secret';
+  name: string;
+  namespace: string;
+  inUse: boolean;
+  workloads: ConfigReference[] | null;
+}
+
+// 更新 ConfigMap 并滚动重启引用它的 Deployment（rollout=true）的响应
+export interface ConfigMapRolloutResponse {
+  configMap: ConfigMap;
+  rollout?: {
+    restarted: string[];
+    failed: string[];
+  };
+  rolloutError?: string;
+}
+
+// CronJob 执行记录（定时与手动触发的 Job）
+export interface CronJobRun {
+  name: string;
+  createdAt: string;
+  trigger: 'scheduled' | 'manual';
+  triggeredBy?: string;
+  status: 'pending' | 'running' | 'succeeded' | 'failed';
+  message?: string;
+  startTime?: string;
+  completionTime?: string;
+  durationSeconds: number;
+  active: number;
+  succeeded: number;
+  failed: number;
 }
 
 export interface ResourceCount {
@@ -37,12 +105,20 @@ export interface ResourceUsage {
   memory: UsageMetric;      // 容器内存（K8s 视角）
   nodeMemory: UsageMetric;  // 节点内存（OS 视角）
   pods: UsageMetric;
+  extended?: Record<string, UsageMetric>;  // GPU、大页内存等扩展资源，used 为已申请量
 }
 
+// 指标数据状态：no_data 为查询成功但没有序列，unavailable 为查询失败；两者对应的值为 0 但不代表真实用量
+export type MetricStatus = 'ok' | 'no_data' | 'unavailable';
+
 export interface UsageMetric {
   used: number;
   total: number;
   unit: string;
+  totalSource?: 'kube-state-metrics' | 'node-exporter' | 'kubernetes-api';  // 总量数据来源
+  usedStatus?: MetricStatus;   // 为空表示直接来自 Kubernetes API
+  totalStatus?: MetricStatus;  // 仅 /metrics/cluster 返回
+  errors?: string[];           // 查询失败的原因
 }
 
 // 节点指标
@@ -74,9 +150,311 @@ export interface PodMetrics {
   namespace: string;
   cpuUsage: number;    // CPU 使用量 (cores)
   memoryUsage: number; // 内存使用量 (bytes)
+  cpuStatus?: MetricStatus;    // 仅单个 Pod 指标返回，不是 ok 时用量为 0
+  memoryStatus?: MetricStatus;
+  errors?: string[];
   containers?: ContainerMetrics[];
 }
 
+// Pod 内各容器的用量、requests/limits 与 CPU 限流（requests/limits 为 0 表示未设置）
+export interface ContainerUsage {
+  name: string;
+  cpuUsage: number;
+  memoryUsage: number;
+  cpuRequest: number;
+  cpuLimit: number;
+  memoryRequest: number;
+  memoryLimit: number;
+  cpuThrottledPercent: number; // 近 5 分钟被限流的 CFS 周期占比
+  cpuThrottledSeconds: number;
+}
+
+export interface PodContainerMetrics {
+  namespace: string;
+  pod: string;
+  containers: ContainerUsage[];
+}
+
+// 时序数据点，timestamp 为 Unix 秒
+export interface TimeSeriesPoint {
+  timestamp: number;
+  value: number;
+}
+
+// Pod 用量历史，cpu 单位为 cores，memory 单位为 bytes
+export interface PodHistory {
+  cpu: TimeSeriesPoint[] | null;
+  memory: TimeSeriesPoint[] | null;
+}
+
+// 节点用量历史：cpu/memory/disk/diskIO 为百分比，网络为 bytes/s
+export interface NodeHistory {
+  cpu: TimeSeriesPoint[] | null;
+  memory: TimeSeriesPoint[] | null;
+  disk: TimeSeriesPoint[] | null;
+  diskIO: TimeSeriesPoint[] | null;
+  networkReceive: TimeSeriesPoint[] | null;
+  networkTransmit: TimeSeriesPoint[] | null;
+  load1: TimeSeriesPoint[] | null;
+  load5: TimeSeriesPoint[] | null;
+  load15: TimeSeriesPoint[] | null;
+}
+
+// 网络收发速率（bytes/s，近 5 分钟平均）
+export interface PodNetworkMetrics {
+  namespace: string;
+  pod: string;
+  receiveBytesPerSecond: number;
+  transmitBytesPerSecond: number;
+}
+
+export interface NamespaceNetworkMetrics {
+  namespace: string;
+  pods: number;
+  receiveBytesPerSecond: number;
+  transmitBytesPerSecond: number;
+}
+
+// 资源占用排行，cpu 单位为 cores，memory 单位为 bytes
+export interface TopConsumer {
+  namespace?: string;
+  pod?: string;
+  node?: string;
+  value: number;
+}
+
+export interface TopConsumersResponse {
+  resource: 'cpu' | 'memory';
+  scope: 'pod' | 'namespace' | 'node';
+  items: TopConsumer[];
+}
+
+// 自定义 PromQL 查询，时间为 Unix 秒
+export interface MetricsQueryRequest {
+  query: string;
+  type?: 'instant' | 'range';
+  time?: number;
+  start?: number;
+  end?: number;
+  step?: string;
+}
+
+export interface MetricsQueryResult {
+  metric: Record<string, string>;
+  value?: [number, string];
+  values?: [number, string][];
+}
+
+export interface MetricsQueryResponse {
+  resultType: 'vector' | 'matrix' | 'scalar' | 'string';
+  result: MetricsQueryResult[];
+  time?: number;
+  start?: number;
+  end?: number;
+  step?: string;
+}
+
+// PVC 卷用量（kubelet_volume_stats_*），仅挂载中的卷有数据
+export interface VolumeMetrics {
+  namespace: string;
+  pvc: string;
+  usedBytes: number;
+  capacityBytes: number;
+  availableBytes: number;
+  usagePercent: number;
+  inodesUsed: number;
+  inodes: number;
+  inodesUsagePercent: number;
+}
+
+// GPU 指标（dcgm-exporter），namespace/pod/container 为占用者
+export interface GPUMetrics {
+  node: string;
+  gpu: string;
+  uuid: string;
+  model: string;
+  namespace?: string;
+  pod?: string;
+  container?: string;
+  utilization: number;
+  memoryUsedBytes: number;
+  memoryTotalBytes: number;
+  memoryUsagePercent: number;
+  temperatureCelsius: number;
+  powerWatts: number;
+}
+
+export type RecommendationStatus = 'ok' | 'over-provisioned' | 'under-provisioned' | 'missing-requests';
+
+// CPU 单位为毫核，内存单位为字节，0 表示未设置
+export interface RecommendedResources {
+  cpuRequestMillicores: number;
+  cpuLimitMillicores: number;
+  memoryRequestBytes: number;
+  memoryLimitBytes: number;
+}
+
+export interface ContainerRecommendation {
+  namespace: string;
+  workloadKind: string;
+  workload: string;
+  container: string;
+  pods: number;
+  current: RecommendedResources;
+  usage: { cpuMillicores: number; memoryBytes: number };
+  recommended: RecommendedResources;
+  cpuStatus: RecommendationStatus;
+  memoryStatus: RecommendationStatus;
+}
+
+export interface ResourceRecommendationReport {
+  window: string;
+  percentile: number;
+  items: ContainerRecommendation[];
+  total: number;
+}
+
+export interface CapacityUsage {
+  allocatable: number;
+  requested: number;
+  used: number;
+}
+
+// CPU 单位为核，内存为字节
+export interface CapacityPool {
+  name: string;
+  nodes: number;
+  cpu: CapacityUsage;
+  memory: CapacityUsage;
+  pods: CapacityUsage;
+  extended?: Record<string, CapacityUsage>;  // 扩展资源，无实际用量
+}
+
+export interface CapacityForecast {
+  resource: 'cpu' | 'memory' | 'pods';
+  current: number;
+  capacity: number;
+  growthPerDay: number;
+  daysUntilExhaustion: number | null;
+  exhaustionDate: string | null;
+  samples: number;
+}
+
+export interface CapacityReport {
+  pools: CapacityPool[];
+  total: CapacityPool;
+  forecast: CapacityForecast[];
+  lookback: string;
+  warnings?: string[];
+}
+
+export interface NodePoolResources {
+  cpu: number;
+  memory: number;
+  pods?: number;
+  extended?: Record<string, number>;
+}
+
+export interface NodePoolSummary {
+  name: string;
+  nodes: number;
+  ready: number;
+  notReady: number;
+  cordoned: number;
+  runningPods: number;
+  instanceTypes: string[];
+  nodeNames: string[];
+  capacity: NodePoolResources;
+  allocatable: NodePoolResources;
+  usage?: NodePoolResources;
+  cpuPercent: number;
+  memoryPercent: number;
+  podPercent: number;
+}
+
+export interface CostItem {
+  namespace: string;
+  workloadKind?: string;
+  workload?: string;
+  pods: number;
+  cpuCoreHours: number;
+  memoryGiBHours: number;
+  usageCpuCoreHours: number;
+  usageMemoryGiBHours: number;
+  cpuCost: number;
+  memoryCost: number;
+  totalCost: number;
+  efficiency: number;
+}
+
+export interface CostReport {
+  window: string;
+  currency: string;
+  cpuHourPrice: number;
+  memoryGbHourPrice: number;
+  items: CostItem[];
+  total: number;
+  totalCost: number;
+}
+
+// 告警/记录规则（Prometheus /api/v1/rules）
+export interface RuleAlert {
+  labels: Record<string, string>;
+  annotations: Record<string, string>;
+  state: string;
+  activeAt?: string;
+  value: string;
+}
+
+export interface Rule {
+  name: string;
+  query: string;
+  type: 'alerting' | 'recording';
+  health: 'ok' | 'err' | 'unknown';
+  lastError?: string;
+  lastEvaluation: string;
+  evaluationTime: number;
+  labels?: Record<string, string>;
+  duration?: number;
+  annotations?: Record<string, string>;
+  state?: 'firing' | 'pending' | 'inactive';
+  alerts?: RuleAlert[];
+}
+
+export interface RuleGroup {
+  name: string;
+  file: string;
+  interval: number;
+  lastEvaluation: string;
+  evaluationTime: number;
+  rules: Rule[];
+}
+
+export interface RuleGroupsResponse {
+  groups: RuleGroup[];
+  total: number;
+  summary: {
+    alerting: number;
+    recording: number;
+    unhealthy: number;
+    firing: number;
+    pending: number;
+  };
+}
+
+// PrometheusRule / VMRule，groups 为 CR 中的原始规则组定义
+export interface RuleResource {
+  kind: 'PrometheusRule' | 'VMRule';
+  resource: 'prometheusrules' | 'vmrules';
+  namespace: string;
+  name: string;
+  labels?: Record<string, string>;
+  resourceVersion: string;
+  alertCount: number;
+  recordCount: number;
+  groups: Record<string, unknown>[];
+}
+
 export interface ContainerMetrics {
   name: string;
   cpu: string;
@@ -128,6 +506,8 @@ export interface AuditLog {
   id: number;
   timestamp: string;
   user: string;
+  userId?: number;      // 已认证用户 ID
+  role?: string;        // 操作时的角色
   action: string;       // GET, POST, PUT, DELETE
   resource: string;     // pods, deployments, nodes, etc.
   resourceName: string;
@@ -139,6 +519,48 @@ export interface AuditLog {
   requestBody: string;
   duration: number;     // 毫秒
   message: string;
+  diff?: string;        // 更新前后差异，JSON 数组 [{op, path, before, after}]
+}
+
+// 终端会话录制
+export interface TerminalSession {
+  id: number;
+  user: string;
+  cluster: string;
+  namespace: string;
+  pod: string;
+  container: string;
+  command: string;
+  startedAt: string;
+  endedAt?: string;
+  bytes: number;
+  truncated: boolean;
+  events?: Array<{ t: number; type: 'i' | 'o'; data: string }>;
+}
+
+// Pod 抓包
+export interface PacketCaptureRequest {
+  interface?: string;
+  filter?: string;
+  durationSeconds?: number;
+}
+
+export interface PacketCapture {
+  id: number;
+  user: string;
+  cluster: string;
+  namespace: string;
+  pod: string;
+  container: string;
+  interface: string;
+  filter: string;
+  durationSeconds: number;
+  status: 'running' | 'completed' | 'failed';
+  message?: string;
+  bytes: number;
+  truncated: boolean;
+  startedAt: string;
+  endedAt?: string;
 }
 
 // 审计日志查询参数
@@ -155,6 +577,62 @@ export interface AuditLogParams {
 }
 
 // 审计日志列表响应
+// 审计日志后台导出任务
+export interface AuditExport {
+  id: number;
+  user: string;
+  format: 'csv' | 'ndjson';
+  filters: Omit<AuditLogParams, 'page' | 'pageSize'>;
+  limit: number;
+  status: 'running' | 'completed' | 'failed';
+  message?: string;
+  rows: number;
+  bytes: number;
+  createdAt: string;
+  completedAt?: string;
+}
+
+// 审计数据存储统计
+export interface AuditStorageStats {
+  rows: number;
+  oldestAt?: string;
+  newestAt?: string;
+  tableBytes: number;
+  partitions?: number;
+  retentionDays: number;
+  archiveDir?: string;
+  archiveFiles: number;
+  archivedRows: number;
+  archiveBytes: number;
+  lastArchive?: { id: number; file: string; rangeFrom: string; rangeTo: string; rows: number; bytes: number; createdAt: string };
+  lastMaintenanceAt?: string;
+  lastMaintenanceError?: string;
+  forwarding?: AuditForwardStatus[];
+}
+
+// 审计异常告警
+export interface AuditAnomaly {
+  id: number;
+  rule: string;
+  severity: 'warning' | 'critical';
+  user: string;
+  cluster: string;
+  message: string;
+  count: number;
+  firstAt: string;
+  lastAt: string;
+  createdAt: string;
+}
+
+export interface AuditForwardStatus {
+  name: string;
+  sent: number;
+  buffered: number;
+  dropped: number;
+  lastError?: string;
+  lastSentAt?: string;
+}
+
 export interface AuditLogListResponse {
   items: AuditLog[];
   total: number;
@@ -181,6 +659,9 @@ export interface Alert {
   status: AlertStatus;
   receivers: AlertReceiver[];
   updatedAt: string;
+  severity?: 'critical' | 'warning' | 'info'; // 后端按映射规则归一化后的级别
+  acknowledged?: boolean;
+  acknowledgement?: AlertAcknowledgement; // 当前处理人、确认时间与备注
 }
 
 export interface AlertStatus {
@@ -202,6 +683,36 @@ export interface AlertSummary {
   info: number;
 }
 
+// Alertmanager 运行状态
+export interface AlertmanagerStatus {
+  cluster: { name: string; status: string; peers: Array<{ name: string; address: string }> };
+  versionInfo: Record<string, string>;
+  config: { original: string };
+  uptime: string;
+}
+
+// Alertmanager 路由树节点
+export interface AlertmanagerRoute {
+  receiver?: string;
+  group_by?: string[];
+  matchers?: string[];
+  match?: Record<string, string>;
+  match_re?: Record<string, string>;
+  continue?: boolean;
+  group_wait?: string;
+  group_interval?: string;
+  repeat_interval?: string;
+  routes?: AlertmanagerRoute[];
+}
+
+// Alertmanager 接收器概要
+export interface AlertmanagerReceiver {
+  name: string;
+  integrations: string[];
+  used: boolean;
+  issues: string[];
+}
+
 // 告警过滤参数
 export interface AlertFilter {
   severity?: string;
@@ -214,6 +725,7 @@ export interface AlertFilter {
 export interface AlertAcknowledgement {
   id: number;
   alertFingerprint: string;
+  cluster?: string;
   acknowledgedBy: string;
   acknowledgedAt: string;
   comment: string;
@@ -224,6 +736,7 @@ export interface AlertAcknowledgement {
 export interface Silence {
   id: number;
   silenceId: string;
+  cluster?: string;
   matchers: SilenceMatcher[];
   startsAt: string;
   endsAt: string;
@@ -241,9 +754,60 @@ export interface SilenceMatcher {
   isEqual: boolean; // true = =, false = !=
 }
 
+// 创建或更新静默规则的参数，startsAt 缺省为当前时间
+export interface SilenceInput {
+  matchers: SilenceMatcher[];
+  startsAt?: string;
+  endsAt: string;
+  comment: string;
+}
+
+// 告警路由：匹配的告警推送到指定通知渠道，条件为空表示不限
+export interface AlertRouteMatch {
+  severities: string[];
+  namespaces: string[];
+  alertNames: string[]; // 支持 * 通配
+  clusters: string[];
+}
+
+export interface AlertRouteQuietHours {
+  start: string; // HH:MM
+  end: string; // 早于 start 表示跨午夜
+  timezone?: string;
+}
+
+export interface AlertRoute {
+  id: number;
+  name: string;
+  enabled: boolean;
+  match: AlertRouteMatch;
+  channelId: number;
+  channelName?: string;
+  quietHours?: AlertRouteQuietHours;
+  createdBy: string;
+  createdAt: string;
+  updatedAt: string;
+}
+
+export interface AlertRouteInput {
+  name: string;
+  enabled?: boolean;
+  match: Partial<AlertRouteMatch>;
+  channelId: number;
+  quietHours?: AlertRouteQuietHours;
+}
+
+export interface AlertRouteChannel {
+  id: number;
+  name: string;
+  type: string;
+  enabled: boolean;
+}
+
 // 集群信息
 export interface ClusterInfo {
   name: string;
+  displayName?: string;
   endpoint: string;
   version: string;
   status: 'connected' | 'disconnected' | 'error';
@@ -254,13 +818,49 @@ export interface ClusterInfo {
   enabled: boolean;
   lastError?: string;
   source: 'kubeconfig' | 'incluster' | string;
+  // 集群独立的监控与告警地址，未设置时使用全局配置
+  victoriaMetricsUrl?: string;
+  alertmanagerUrl?: string;
+}
+
+// 集群凭据二选一：kubeconfig，或 API Server 地址 + CA + ServiceAccount Token
+export interface ClusterCredentials {
+  kubeconfig?: string;
+  // kubeconfig 含多个 context 时指定使用的 context，默认 current-context
+  context?: string;
+  server?: string;
+  caData?: string;
+  bearerToken?: string;
+}
+
+export interface KubeconfigContext {
+  name: string;
+  cluster: string;
+  user: string;
+  namespace?: string;
+  server?: string;
+  current: boolean;
+}
+
+export interface ClusterEndpoints {
+  victoriaMetricsUrl: string;
+  alertmanagerUrl: string;
 }
 
 // 错误响应
+// 统一错误响应，code 为大写下划线形式的错误类别（如 NOT_FOUND、CONFLICT），reason 为 Kubernetes StatusReason
 export interface ApiError {
-  code: number;
+  error: string;
+  code: string;
+  reason?: string;
   message: string;
-  details?: string;
+  details?: {
+    group?: string;
+    kind?: string;
+    name?: string;
+    causes?: { type?: string; field?: string; message: string }[];
+    retryAfterSeconds?: number;
+  };
 }
 
 // WebSocket 消息类型
@@ -295,6 +895,18 @@ export interface ListParams {
   search?: string;
 }
 
+// 日志查询参数（时间为 RFC3339，grep 为正则）
+export interface LogSearchParams {
+  container?: string;
+  tailLines?: number;
+  sinceTime?: string;
+  untilTime?: string;
+  previous?: boolean;
+  timestamps?: boolean;
+  grep?: string;
+  invert?: boolean;
+}
+
 // 统计数据类型
 export interface TimeSeriesData {
   timestamp: string;
@@ -305,3 +917,210 @@ export interface ResourceHistory {
   cpu: TimeSeriesData[];
   memory: TimeSeriesData[];
 }
+
+// 运行手册（参数化 Job 模板）
+// 节点重新均衡建议
+export interface RebalanceSuggestion {
+  namespace: string;
+  name: string;
+  replicas: number;
+  distribution: Record<string, number>;
+  skew: number;
+  cpuRequest?: string;
+  memoryRequest?: string;
+  fits: boolean;
+  notes?: string[];
+}
+
+export interface NodeRebalanceSuggestions {
+  node: string;
+  schedulable: boolean;
+  hints: string[];
+  freeCpu: string;
+  freeMemory: string;
+  suggestions: RebalanceSuggestion[];
+}
+
+export interface RebalanceResult {
+  namespace: string;
+  name: string;
+  status: 'restarted' | 'skipped' | 'failed';
+  message?: string;
+}
+
+// 持久化的历史事件
+export interface EventRecord {
+  id: number;
+  cluster: string;
+  uid: string;
+  namespace: string;
+  name: string;
+  type: string;
+  reason: string;
+  message: string;
+  involvedKind: string;
+  involvedName: string;
+  involvedNamespace: string;
+  source: string;
+  count: number;
+  firstTimestamp: string;
+  lastTimestamp: string;
+}
+
+export interface EventHistoryParams {
+  namespace?: string;
+  type?: string;
+  reason?: string;
+  kind?: string;
+  name?: string;
+  q?: string;
+  startTime?: string;
+  endTime?: string;
+  page?: number;
+  pageSize?: number;
+}
+
+// 实时事件流过滤条件，type/reason/kind 支持逗号分隔多个值
+export interface EventStreamFilter {
+  namespace?: string;
+  type?: string;
+  reason?: string;
+  kind?: string;
+}
+
+export interface EventStreamMessage {
+  type: 'event' | 'error';
+  action?: 'ADDED' | 'MODIFIED';
+  event?: Event;
+  message?: string;
+}
+
+export interface RunbookParameter {
+  name: string;
+  description?: string;
+  required: boolean;
+  default?: string;
+  pattern?: string;
+}
+
+export interface Runbook {
+  id: number;
+  name: string;
+  description: string;
+  manifest: string;
+  parameters: RunbookParameter[];
+  minRole: 'operator' | 'admin';
+  namespaces: string[];
+  createdBy: string;
+  createdAt: string;
+  updatedAt: string;
+  canRun?: boolean;
+}
+
+export interface RunbookRun {
+  id: number;
+  runbookId: number;
+  runbookName: string;
+  userId: number;
+  username: string;
+  cluster: string;
+  namespace: string;
+  jobName: string;
+  parameters: Record<string, string>;
+  status: 'running' | 'succeeded' | 'failed' | 'error';
+  message?: string;
+  startedAt: string;
+  finishedAt?: string;
+}
+
+// 命名空间清理
+export type NamespaceCleanupKind = 'completed-jobs' | 'finished-pods' | 'orphaned-replicasets';
+
+export interface NamespaceCleanupItem {
+  kind: 'Job' | 'Pod' | 'ReplicaSet';
+  name: string;
+  reason: string;
+  error?: string;
+}
+
+export interface NamespaceCleanupResult {
+  namespace: string;
+  dryRun: boolean;
+  items: NamespaceCleanupItem[];
+  deleted: number;
+  failed: number;
+}
+
+// 批量操作
+export type BatchAction = 'delete' | 'restart' | 'scale';
+
+export interface BatchOperation {
+  action: BatchAction;
+  kind: 'Pod' | 'Deployment' | 'StatefulSet' | 'DaemonSet' | 'Job' | 'CronJob';
+  namespace: string;
+  name: string;
+  replicas?: number;
+}
+
+export interface BatchResult extends BatchOperation {
+  status: 'succeeded' | 'failed' | 'denied' | 'pendingApproval';
+  message?: string;
+  approvalId?: number;
+}
+
+export interface BatchResponse {
+  results: BatchResult[];
+  succeeded: number;
+  failed: number;
+  denied: number;
+  pendingApproval: number;
+}
+
+// 全局搜索
+export interface SearchParams {
+  q: string;
+  kinds?: string;
+  namespace?: string;
+  limit?: number;
+}
+
+export interface SearchHit {
+  kind: string;
+  name: string;
+  namespace?: string;
+  matchedOn: 'name' | 'label' | 'annotation';
+  match?: string;
+  labels?: Record<string, string>;
+  createdAt: string;
+  link?: string;
+}
+
+export interface SearchResponse {
+  items: SearchHit[];
+  total: number;
+  truncated: boolean;
+  pending?: string[];
+}
+
+// 命名空间拓扑
+export type TopologyEdgeType = 'owns' | 'routes' | 'selects' | 'mounts' | 'uses';
+
+export interface TopologyNode {
+  id: string;
+  kind: string;
+  name: string;
+  status?: string;
+  missing?: boolean;
+}
+
+export interface TopologyEdge {
+  source: string;
+  target: string;
+  type: TopologyEdgeType;
+}
+
+export interface NamespaceTopology {
+  namespace: string;
+  nodes: TopologyNode[];
+  edges: TopologyEdge[];
+}
@@ -1,3 +1,5 @@
+import type { VolumeMetrics } from './api';
+
 // Kubernetes 资源基础类型
 
 // 通用元数据
@@ -20,8 +22,21 @@ export interface OwnerReference {
   controller?: boolean;
 }
 
+// 详情接口附带的关联对象，link 为前端页面路径
+export interface ResourceRef {
+  kind: string;
+  name: string;
+  namespace?: string;
+  link?: string;
+}
+
+export interface ResourceRelations {
+  controlledBy: ResourceRef[];
+  controls: ResourceRef[];
+}
+
 // Pod 相关类型
-export interface Pod {
+export interface Pod extends Partial<ResourceRelations> {
   metadata: ObjectMeta;
   spec: PodSpec;
   status: PodStatus;
@@ -197,7 +212,7 @@ export interface ContainerStateTerminated {
 }
 
 // Deployment 相关类型
-export interface Deployment {
+export interface Deployment extends Partial<ResourceRelations> {
   metadata: ObjectMeta;
   spec: DeploymentSpec;
   status: DeploymentStatus;
@@ -259,7 +274,7 @@ export interface DeploymentCondition {
 }
 
 // StatefulSet 相关类型
-export interface StatefulSet {
+export interface StatefulSet extends Partial<ResourceRelations> {
   metadata: ObjectMeta;
   spec: StatefulSetSpec;
   status: StatefulSetStatus;
@@ -305,7 +320,7 @@ export interface StatefulSetCondition {
 }
 
 // DaemonSet 相关类型
-export interface DaemonSet {
+export interface DaemonSet extends Partial<ResourceRelations> {
   metadata: ObjectMeta;
   spec: DaemonSetSpec;
   status: DaemonSetStatus;
@@ -349,7 +364,7 @@ export interface DaemonSetCondition {
 }
 
 // Job 相关类型
-export interface Job {
+export interface Job extends Partial<ResourceRelations> {
   metadata: ObjectMeta;
   spec: JobSpec;
   status: JobStatus;
@@ -383,7 +398,7 @@ export interface JobCondition {
 }
 
 // CronJob 相关类型
-export interface CronJob {
+export interface CronJob extends Partial<ResourceRelations> {
   metadata: ObjectMeta;
   spec: CronJobSpec;
   status: CronJobStatus;
@@ -492,6 +507,22 @@ export interface Ingress {
   metadata: ObjectMeta;
   spec: IngressSpec;
   status?: IngressStatus;
+  // 仅详情接口返回：spec.tls 各项（顺序一致）引用的证书信息
+  tlsCertificates?: IngressTLSCertificate[];
+}
+
+export interface IngressTLSCertificate {
+  secretName: string;
+  hosts?: string[];
+  subject?: string;
+  issuer?: string;
+  dnsNames?: string[];
+  notBefore?: string;
+  notAfter?: string;
+  daysRemaining: number;
+  status: 'valid' | 'expiring' | 'expired' | 'default' | 'error';
+  unmatchedHosts?: string[];
+  error?: string;
 }
 
 export interface IngressSpec {
@@ -567,6 +598,16 @@ export interface Secret {
   immutable?: boolean;
 }
 
+// 查看 Secret 明文的响应；命中审批规则时返回 202 与 approvalRequired
+export interface SecretReveal {
+  name?: string;
+  namespace?: string;
+  type?: string;
+  data?: Record<string, string>;
+  approvalRequired?: boolean;
+  message?: string;
+}
+
 // 创建资源时可提交的 metadata（不包含服务端只读字段）
 export interface CreateObjectMeta {
   name: string;
@@ -628,6 +669,25 @@ export interface PersistentVolumeClaim {
   metadata: ObjectMeta;
   spec: PersistentVolumeClaimSpec;
   status?: PersistentVolumeClaimStatus;
+  // 以下字段仅详情接口返回
+  capacity?: string;
+  requested?: string;
+  accessModes?: string[];
+  boundVolume?: BoundVolumeInfo;
+  expandable?: boolean;
+  resizePending?: boolean;
+  // 列表与详情接口返回的卷用量，VictoriaMetrics 不可用或卷未挂载时为空
+  usage?: VolumeMetrics;
+}
+
+// PVC 绑定的 PV 摘要
+export interface BoundVolumeInfo {
+  name: string;
+  capacity?: string;
+  accessModes?: string[];
+  reclaimPolicy?: string;
+  volumeMode?: string;
+  phase?: string;
 }
 
 export interface PersistentVolumeClaimSpec {